@@ -0,0 +1,349 @@
+// Package gtapi is a Go client for the Gas Town HTTP API served by
+// `gt dashboard` under /api/.
+//
+// It lets external tools query and drive a town without shelling out to gt
+// or hand-rolling HTTP calls:
+//
+//	c, err := gtapi.NewFromTown("/home/me/gt")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	ready, err := c.Ready(ctx)
+//	...
+//	_, err = c.SendMail(ctx, gtapi.MailSendRequest{To: "mayor/", Subject: "hi"})
+//
+// Mutating calls need the dashboard's anti-CSRF token. The client reads it
+// from the dashboard page on first use and again if the dashboard restarts,
+// so callers don't have to manage it.
+package gtapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/endpoints"
+)
+
+// DefaultTimeout is the default HTTP client's request timeout. Commands run
+// by the API can take up to a minute.
+const DefaultTimeout = 90 * time.Second
+
+// Error is a failed API call: a non-2xx response or an action reporting
+// success=false.
+type Error struct {
+	StatusCode int
+	Message    string
+	Output     string // command output, when the API included it
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("gt api: %s (HTTP %d)", e.Message, e.StatusCode)
+}
+
+// IsForbidden reports whether err is the API refusing a request: a blocked
+// command, an unconfirmed dangerous one, or a bad token.
+func IsForbidden(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden
+}
+
+// Client calls a town's HTTP API. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	http    *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient uses hc for requests instead of a default client.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithToken sets the anti-CSRF token instead of reading it from the
+// dashboard page.
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// New returns a client for the dashboard at baseURL, e.g.
+// "http://127.0.0.1:8080".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Timeout: DefaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// NewFromTown returns a client for the dashboard the town at townRoot has
+// registered, failing if none is running.
+func NewFromTown(townRoot string, opts ...Option) (*Client, error) {
+	reg, err := endpoints.Load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := reg.Get(endpoints.HTTP)
+	if !ok || !e.Live() {
+		return nil, fmt.Errorf("no dashboard running for town %s (start one with 'gt dashboard')", townRoot)
+	}
+	return New("http://"+e.Address(), opts...), nil
+}
+
+// BaseURL returns the dashboard URL the client talks to.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// runRequest is the body of /api/run.
+type runRequest struct {
+	Command   string `json:"command"`
+	Confirmed bool   `json:"confirmed,omitempty"`
+}
+
+// Run executes a gt command (without the "gt" prefix) through the API's
+// command allowlist. Commands marked Confirm in Commands need confirmed.
+// A command that ran but failed returns its result and an *Error.
+func (c *Client) Run(ctx context.Context, command string, confirmed bool) (*CommandResult, error) {
+	req := runRequest{Command: command, Confirmed: confirmed}
+	var res CommandResult
+	if err := c.post(ctx, "/run", req, &res); err != nil {
+		return nil, err
+	}
+	if !res.Success {
+		return &res, &Error{StatusCode: http.StatusOK, Message: res.Error, Output: res.Output}
+	}
+	return &res, nil
+}
+
+// Commands lists the commands Run accepts.
+func (c *Client) Commands(ctx context.Context) ([]CommandInfo, error) {
+	var res struct {
+		Commands []CommandInfo `json:"commands"`
+	}
+	if err := c.get(ctx, "/commands", nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Commands, nil
+}
+
+// Options lists the rigs, agents, convoys and other names currently valid
+// as command arguments.
+func (c *Client) Options(ctx context.Context) (*Options, error) {
+	return get[Options](ctx, c, "/options", nil)
+}
+
+// MailInbox returns the overseer's inbox.
+func (c *Client) MailInbox(ctx context.Context) (*MailInbox, error) {
+	return get[MailInbox](ctx, c, "/mail/inbox", nil)
+}
+
+// MailThreads returns the overseer's inbox grouped into threads.
+func (c *Client) MailThreads(ctx context.Context) (*MailThreads, error) {
+	return get[MailThreads](ctx, c, "/mail/threads", nil)
+}
+
+// ReadMail returns a message, marking it read.
+func (c *Client) ReadMail(ctx context.Context, id string) (*MailMessage, error) {
+	return get[MailMessage](ctx, c, "/mail/read", url.Values{"id": {id}})
+}
+
+// SendMail sends a message.
+func (c *Client) SendMail(ctx context.Context, req MailSendRequest) (*ActionResult, error) {
+	return c.action(ctx, "/mail/send", req)
+}
+
+// Issue returns an issue. id may be a bead ID or "external:prefix:id".
+func (c *Client) Issue(ctx context.Context, id string) (*Issue, error) {
+	return get[Issue](ctx, c, "/issues/show", url.Values{"id": {id}})
+}
+
+// CreateIssue creates an issue; the result's ID is the new bead.
+func (c *Client) CreateIssue(ctx context.Context, req IssueCreateRequest) (*ActionResult, error) {
+	return c.action(ctx, "/issues/create", req)
+}
+
+// CloseIssue closes an issue.
+func (c *Client) CloseIssue(ctx context.Context, id string) (*ActionResult, error) {
+	return c.action(ctx, "/issues/close", map[string]string{"id": id})
+}
+
+// UpdateIssue changes an issue's status, priority or assignee.
+func (c *Client) UpdateIssue(ctx context.Context, req IssueUpdateRequest) (*ActionResult, error) {
+	return c.action(ctx, "/issues/update", req)
+}
+
+// PullRequest returns a GitHub pull request by repo ("owner/name") and
+// number.
+func (c *Client) PullRequest(ctx context.Context, repo string, number int) (*PullRequest, error) {
+	q := url.Values{"repo": {repo}, "number": {strconv.Itoa(number)}}
+	return get[PullRequest](ctx, c, "/pr/show", q)
+}
+
+// Crew returns every crew worker's status.
+func (c *Client) Crew(ctx context.Context) (*Crew, error) {
+	return get[Crew](ctx, c, "/crew", nil)
+}
+
+// Ready returns the town's ready work.
+func (c *Client) Ready(ctx context.Context) (*Ready, error) {
+	return get[Ready](ctx, c, "/ready", nil)
+}
+
+// SessionPreview returns the last lines of an agent session's pane.
+func (c *Client) SessionPreview(ctx context.Context, session string) (*SessionPreview, error) {
+	return get[SessionPreview](ctx, c, "/session/preview", url.Values{"session": {session}})
+}
+
+// Metrics returns the town's recorded metrics history for the last days
+// days (0 for the server default), bucketed by step (0 for the server
+// default).
+func (c *Client) Metrics(ctx context.Context, days int, step time.Duration) (*Metrics, error) {
+	query := url.Values{}
+	if days > 0 {
+		query.Set("days", strconv.Itoa(days))
+	}
+	if step > 0 {
+		query.Set("step", step.String())
+	}
+	return get[Metrics](ctx, c, "/metrics", query)
+}
+
+// action posts to an action endpoint and turns success=false into an error.
+func (c *Client) action(ctx context.Context, path string, req interface{}) (*ActionResult, error) {
+	var res ActionResult
+	if err := c.post(ctx, path, req, &res); err != nil {
+		return nil, err
+	}
+	if !res.Success {
+		return &res, &Error{StatusCode: http.StatusOK, Message: res.Error, Output: res.Output}
+	}
+	return &res, nil
+}
+
+// get fetches path and decodes the response into a new T.
+func get[T any](ctx context.Context, c *Client, path string, query url.Values) (*T, error) {
+	var res T
+	if err := c.get(ctx, path, query, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	u := c.baseURL + "/api" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, out)
+}
+
+// post sends body as JSON with the anti-CSRF token. A rejected token is
+// refreshed once, in case the dashboard restarted with a new one.
+func (c *Client) post(ctx context.Context, path string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	for attempt := 0; ; attempt++ {
+		token, err := c.csrfToken(ctx, attempt > 0)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api"+path, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Dashboard-Token", token)
+		err = c.do(req, out)
+		var apiErr *Error
+		if attempt == 0 && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden &&
+			strings.Contains(apiErr.Message, "token") {
+			continue
+		}
+		return err
+	}
+}
+
+func (c *Client) do(req *http.Request, out interface{}) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("gt api: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("gt api: reading response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+		var res struct {
+			Error  string `json:"error"`
+			Output string `json:"output"`
+		}
+		if json.Unmarshal(body, &res) == nil && res.Error != "" {
+			apiErr.Message, apiErr.Output = res.Error, res.Output
+		}
+		return apiErr
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("gt api: decoding %s response: %w", req.URL.Path, err)
+	}
+	return nil
+}
+
+// tokenMeta finds the token the dashboard page embeds for its own scripts.
+var tokenMeta = regexp.MustCompile(`<meta name="dashboard-token" content="([^"]*)">`)
+
+// csrfToken returns the cached token, reading it from the dashboard page
+// when there is none yet or refresh is set.
+func (c *Client) csrfToken(ctx context.Context, refresh bool) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && !refresh {
+		return c.token, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("gt api: fetching dashboard token: %w", err)
+	}
+	defer resp.Body.Close()
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("gt api: fetching dashboard token: %w", err)
+	}
+	m := tokenMeta.FindSubmatch(page)
+	if m == nil {
+		return "", fmt.Errorf("gt api: dashboard page at %s has no token", c.baseURL)
+	}
+	c.token = string(m[1])
+	return c.token, nil
+}
@@ -0,0 +1,144 @@
+package gtapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/web"
+)
+
+// emptyFetcher serves an empty dashboard page.
+type emptyFetcher struct{}
+
+func (emptyFetcher) FetchConvoys() ([]web.ConvoyRow, error)         { return nil, nil }
+func (emptyFetcher) FetchMergeQueue() ([]web.MergeQueueRow, error)  { return nil, nil }
+func (emptyFetcher) FetchWorkers() ([]web.WorkerRow, error)         { return nil, nil }
+func (emptyFetcher) FetchMail() ([]web.MailRow, error)              { return nil, nil }
+func (emptyFetcher) FetchRigs() ([]web.RigRow, error)               { return nil, nil }
+func (emptyFetcher) FetchDogs() ([]web.DogRow, error)               { return nil, nil }
+func (emptyFetcher) FetchEscalations() ([]web.EscalationRow, error) { return nil, nil }
+func (emptyFetcher) FetchHealth() (*web.HealthRow, error)           { return nil, nil }
+func (emptyFetcher) FetchQueues() ([]web.QueueRow, error)           { return nil, nil }
+func (emptyFetcher) FetchSessions() ([]web.SessionRow, error)       { return nil, nil }
+func (emptyFetcher) FetchHooks() ([]web.HookRow, error)             { return nil, nil }
+func (emptyFetcher) FetchMayor() (*web.MayorStatus, error)          { return nil, nil }
+func (emptyFetcher) FetchIssues() ([]web.IssueRow, error)           { return nil, nil }
+func (emptyFetcher) FetchActivity() ([]web.ActivityRow, error)      { return nil, nil }
+
+// TestClientAgainstDashboard exercises the client against the real
+// dashboard handler, using requests the API answers without running gt.
+func TestClientAgainstDashboard(t *testing.T) {
+	mux, err := web.NewDashboardMux(emptyFetcher{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	ctx := context.Background()
+
+	c := New(srv.URL + "/")
+	cmds, err := c.Commands(ctx)
+	if err != nil {
+		t.Fatalf("Commands() error: %v", err)
+	}
+	if len(cmds) == 0 || cmds[0].Name == "" {
+		t.Errorf("Commands() = %+v, want the allowlist", cmds)
+	}
+
+	// A blocked command gets past the token check: the client fetched it.
+	_, err = c.Run(ctx, "nuke everything", true)
+	if !IsForbidden(err) || !strings.Contains(err.Error(), "blocked") {
+		t.Errorf("Run(blocked) error = %v, want forbidden 'Command blocked'", err)
+	}
+
+	// A stale token (dashboard restarted) is refreshed once.
+	stale := New(srv.URL, WithToken("stale"))
+	if _, err := stale.Run(ctx, "nuke everything", true); !strings.Contains(err.Error(), "blocked") {
+		t.Errorf("Run() with stale token error = %v, want 'Command blocked'", err)
+	}
+
+	var apiErr *Error
+	_, err = c.CloseIssue(ctx, "not an id!")
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("CloseIssue(invalid) error = %v, want HTTP 400", err)
+	}
+	if _, err := c.Issue(ctx, ""); !errors.As(err, &apiErr) || !strings.Contains(apiErr.Message, "Missing issue ID") {
+		t.Errorf("Issue(\"\") error = %v", err)
+	}
+}
+
+func TestActionFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Dashboard-Token") != "tok" {
+			t.Errorf("token header = %q", r.Header.Get("X-Dashboard-Token"))
+		}
+		_, _ = w.Write([]byte(`{"success":false,"error":"Failed to close issue","output":"no such issue"}`))
+	}))
+	defer srv.Close()
+
+	res, err := New(srv.URL, WithToken("tok")).CloseIssue(context.Background(), "gt-abc")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Output != "no such issue" {
+		t.Fatalf("CloseIssue() error = %v, want *Error with output", err)
+	}
+	if res == nil || res.Success {
+		t.Errorf("CloseIssue() result = %+v, want the failed result", res)
+	}
+}
+
+// TestTypesMatchAPI keeps the client's types in step with the API's.
+func TestTypesMatchAPI(t *testing.T) {
+	pairs := []struct{ client, api interface{} }{
+		{CommandResult{}, web.CommandResponse{}},
+		{CommandInfo{}, web.CommandInfo{}},
+		{OptionItem{}, web.OptionItem{}},
+		{Options{}, web.OptionsResponse{}},
+		{MailMessage{}, web.MailMessage{}},
+		{MailInbox{}, web.MailInboxResponse{}},
+		{MailThread{}, web.MailThread{}},
+		{MailThreads{}, web.MailThreadsResponse{}},
+		{MailSendRequest{}, web.MailSendRequest{}},
+		{Issue{}, web.IssueShowResponse{}},
+		{IssueCreateRequest{}, web.IssueCreateRequest{}},
+		{IssueUpdateRequest{}, web.IssueUpdateRequest{}},
+		{PullRequest{}, web.PRShowResponse{}},
+		{CrewMember{}, web.CrewMember{}},
+		{Crew{}, web.CrewResponse{}},
+		{ReadyItem{}, web.ReadyItem{}},
+		{Ready{}, web.ReadyResponse{}},
+		{SessionPreview{}, web.SessionPreviewResponse{}},
+		{Metrics{}, web.MetricsResponse{}},
+	}
+	for _, p := range pairs {
+		got, want := jsonFields(reflect.TypeOf(p.client)), jsonFields(reflect.TypeOf(p.api))
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%T fields %v, want %v (from %T)", p.client, got, want, p.api)
+		}
+	}
+}
+
+// jsonFields lists a struct's JSON field names with their kinds, recursing
+// into nested structs.
+func jsonFields(typ reflect.Type) []string {
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		ft := f.Type
+		for ft.Kind() == reflect.Slice || ft.Kind() == reflect.Map || ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		fields = append(fields, name+":"+f.Type.Kind().String())
+		if ft.Kind() == reflect.Struct {
+			for _, sub := range jsonFields(ft) {
+				fields = append(fields, name+"."+sub)
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,229 @@
+package gtapi
+
+import "time"
+
+// The types below mirror the JSON of the dashboard API (internal/web). They
+// are copied rather than shared so the API can stay internal; a test keeps
+// their JSON fields in sync.
+
+// CommandResult is the result of Client.Run.
+type CommandResult struct {
+	Success    bool   `json:"success"`
+	Output     string `json:"output,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	Command    string `json:"command"`
+}
+
+// CommandInfo describes a gt command the API allows Client.Run to execute.
+type CommandInfo struct {
+	Name     string `json:"name"`
+	Desc     string `json:"desc"`
+	Category string `json:"category"`
+	Safe     bool   `json:"safe"`    // read-only
+	Confirm  bool   `json:"confirm"` // Run needs confirmed=true
+	Args     string `json:"args,omitempty"`
+	ArgType  string `json:"argType,omitempty"`
+}
+
+// OptionItem is an agent with its status.
+type OptionItem struct {
+	Name    string `json:"name"`
+	Status  string `json:"status,omitempty"`
+	Running bool   `json:"running,omitempty"`
+}
+
+// Options lists the names currently valid as command arguments.
+type Options struct {
+	Rigs        []string     `json:"rigs,omitempty"`
+	Polecats    []string     `json:"polecats,omitempty"`
+	Convoys     []string     `json:"convoys,omitempty"`
+	Agents      []OptionItem `json:"agents,omitempty"`
+	Hooks       []string     `json:"hooks,omitempty"`
+	Messages    []string     `json:"messages,omitempty"`
+	Crew        []string     `json:"crew,omitempty"`
+	Escalations []string     `json:"escalations,omitempty"`
+}
+
+// MailMessage is a mail message.
+type MailMessage struct {
+	ID        string `json:"id"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Subject   string `json:"subject"`
+	Body      string `json:"body,omitempty"`
+	Timestamp string `json:"timestamp"`
+	Read      bool   `json:"read"`
+	Priority  string `json:"priority,omitempty"`
+	ThreadID  string `json:"thread_id,omitempty"`
+	ReplyTo   string `json:"reply_to,omitempty"`
+}
+
+// MailInbox is the overseer's inbox.
+type MailInbox struct {
+	Messages    []MailMessage `json:"messages"`
+	UnreadCount int           `json:"unread_count"`
+	Total       int           `json:"total"`
+}
+
+// MailThread is a conversation.
+type MailThread struct {
+	ThreadID    string        `json:"thread_id"`
+	Subject     string        `json:"subject"`
+	LastMessage MailMessage   `json:"last_message"`
+	Messages    []MailMessage `json:"messages"`
+	Count       int           `json:"count"`
+	UnreadCount int           `json:"unread_count"`
+}
+
+// MailThreads is the inbox grouped into threads.
+type MailThreads struct {
+	Threads     []MailThread `json:"threads"`
+	UnreadCount int          `json:"unread_count"`
+	Total       int          `json:"total"`
+}
+
+// MailSendRequest is a message to send.
+type MailSendRequest struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	ReplyTo string `json:"reply_to,omitempty"`
+}
+
+// Issue is an issue (bead) as shown by bd show.
+type Issue struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Type        string   `json:"type,omitempty"`
+	Status      string   `json:"status,omitempty"`
+	Priority    string   `json:"priority,omitempty"`
+	Owner       string   `json:"owner,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Created     string   `json:"created,omitempty"`
+	Updated     string   `json:"updated,omitempty"`
+	DependsOn   []string `json:"depends_on,omitempty"`
+	Blocks      []string `json:"blocks,omitempty"`
+	RawOutput   string   `json:"raw_output"`
+}
+
+// IssueCreateRequest is a new issue.
+type IssueCreateRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Priority    int    `json:"priority,omitempty"` // 1-4, default 2
+}
+
+// IssueUpdateRequest changes an issue. Zero fields are left unchanged.
+type IssueUpdateRequest struct {
+	ID       string `json:"id"`
+	Status   string `json:"status,omitempty"`   // "open" or "in_progress"
+	Priority int    `json:"priority,omitempty"` // 1-4
+	Assignee string `json:"assignee,omitempty"`
+}
+
+// ActionResult is the result of an action endpoint (send, create, close,
+// update). Calls returning one fail with an *Error when Success is false.
+type ActionResult struct {
+	Success bool   `json:"success"`
+	ID      string `json:"id,omitempty"` // created issue
+	Message string `json:"message,omitempty"`
+	Output  string `json:"output,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// PullRequest is a GitHub pull request as shown by gh pr view.
+type PullRequest struct {
+	Number       int      `json:"number"`
+	Title        string   `json:"title"`
+	State        string   `json:"state"`
+	Author       string   `json:"author"`
+	URL          string   `json:"url"`
+	Body         string   `json:"body"`
+	CreatedAt    string   `json:"created_at"`
+	UpdatedAt    string   `json:"updated_at"`
+	Additions    int      `json:"additions"`
+	Deletions    int      `json:"deletions"`
+	ChangedFiles int      `json:"changed_files"`
+	Mergeable    string   `json:"mergeable"`
+	BaseRef      string   `json:"base_ref"`
+	HeadRef      string   `json:"head_ref"`
+	Labels       []string `json:"labels,omitempty"`
+	Checks       []string `json:"checks,omitempty"`
+	RawOutput    string   `json:"raw_output,omitempty"`
+}
+
+// CrewMember is a crew worker's status.
+type CrewMember struct {
+	Name       string `json:"name"`
+	Rig        string `json:"rig"`
+	State      string `json:"state"` // spinning, finished, ready, questions
+	Hook       string `json:"hook,omitempty"`
+	HookTitle  string `json:"hook_title,omitempty"`
+	Session    string `json:"session"` // attached, detached, none
+	LastActive string `json:"last_active"`
+}
+
+// Crew is every crew worker in the town.
+type Crew struct {
+	Crew  []CrewMember            `json:"crew"`
+	ByRig map[string][]CrewMember `json:"by_rig"`
+	Total int                     `json:"total"`
+}
+
+// ReadyItem is a work item with no open blockers.
+type ReadyItem struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Priority int    `json:"priority"`
+	Source   string `json:"source"` // "town" or rig name
+	Type     string `json:"type"`
+}
+
+// ReadySummary counts ready work by priority.
+type ReadySummary struct {
+	Total   int `json:"total"`
+	P1Count int `json:"p1_count"`
+	P2Count int `json:"p2_count"`
+	P3Count int `json:"p3_count"`
+}
+
+// Ready is the town's ready work.
+type Ready struct {
+	Items    []ReadyItem            `json:"items"`
+	BySource map[string][]ReadyItem `json:"by_source"`
+	Summary  ReadySummary           `json:"summary"`
+}
+
+// SessionPreview is the last lines of an agent session's pane.
+type SessionPreview struct {
+	Session   string `json:"session"`
+	Content   string `json:"content"`
+	Timestamp string `json:"timestamp"`
+}
+
+// MetricSummary describes one metric over the requested window.
+type MetricSummary struct {
+	Name  string  `json:"name"`
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Mean  float64 `json:"mean"`
+	Last  float64 `json:"last"`
+	Total float64 `json:"total"`
+}
+
+// MetricPoint is one bucket of a metric series.
+type MetricPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Metrics is the town's recorded metrics history.
+type Metrics struct {
+	Start     string                   `json:"start"`
+	End       string                   `json:"end"`
+	Step      string                   `json:"step"`
+	Summaries []MetricSummary          `json:"summaries"`
+	Series    map[string][]MetricPoint `json:"series"`
+}
@@ -0,0 +1,224 @@
+// Package announce tracks announcements made to live agent sessions and
+// which agents have acknowledged them.
+//
+// An announcement ("merge freeze starts now") is delivered to every live
+// session in a rig or the town. Agents acknowledge with gt ack, or by
+// saying "acknowledged <id>" in their output; whoever hasn't after a
+// while is a laggard.
+//
+// State lives in <town>/.runtime/announcements/<id>/: announcement.json,
+// plus one file per acknowledging agent under acks/, so concurrent acks
+// never rewrite a shared file.
+package announce
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNotRecipient is returned by Acknowledge for an agent the announcement
+// was not delivered to.
+var ErrNotRecipient = errors.New("agent is not a recipient of this announcement")
+
+// Ways an acknowledgement can arrive.
+const (
+	ViaCommand = "command" // gt ack
+	ViaOutput  = "output"  // "acknowledged <id>" seen in the agent's pane
+)
+
+// Announcement is one delivered announcement.
+type Announcement struct {
+	ID      string `json:"id"`
+	From    string `json:"from"`
+	Message string `json:"message"`
+	// Scope is "town" or a rig name.
+	Scope     string    `json:"scope"`
+	CreatedAt time.Time `json:"created_at"`
+	// Sessions maps each recipient agent (e.g. gastown/Toast) to the tmux
+	// session it was delivered to.
+	Sessions map[string]string `json:"sessions"`
+}
+
+// Ack is one agent's acknowledgement.
+type Ack struct {
+	Agent string    `json:"agent"`
+	At    time.Time `json:"at"`
+	Via   string    `json:"via"`
+}
+
+// Dir returns the directory holding all announcements.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "announcements")
+}
+
+func announcementDir(townRoot, id string) string {
+	return filepath.Join(Dir(townRoot), id)
+}
+
+func ackPath(townRoot, id, agent string) string {
+	return filepath.Join(announcementDir(townRoot, id), "acks", strings.ReplaceAll(agent, "/", "_")+".json")
+}
+
+// NewID returns a fresh announcement ID, e.g. "ann-3f9a2c".
+func NewID() string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b) // crypto/rand.Read only fails on broken system
+	return "ann-" + hex.EncodeToString(b)
+}
+
+// Recipients returns the recipient agents, sorted.
+func (a *Announcement) Recipients() []string {
+	agents := make([]string, 0, len(a.Sessions))
+	for agent := range a.Sessions {
+		agents = append(agents, agent)
+	}
+	sort.Strings(agents)
+	return agents
+}
+
+// Laggards returns the recipients that have not acknowledged, sorted.
+func (a *Announcement) Laggards(acks map[string]Ack) []string {
+	var laggards []string
+	for _, agent := range a.Recipients() {
+		if _, ok := acks[agent]; !ok {
+			laggards = append(laggards, agent)
+		}
+	}
+	return laggards
+}
+
+// AckPattern matches an agent saying it acknowledged the announcement,
+// e.g. "Acknowledged ann-3f9a2c". The delivered text must not match it.
+func AckPattern(id string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)\backnowledged:?\s+` + regexp.QuoteMeta(id) + `\b`)
+}
+
+// Save writes the announcement.
+func Save(townRoot string, a *Announcement) error {
+	dir := announcementDir(townRoot, a.ID)
+	if err := os.MkdirAll(filepath.Join(dir, "acks"), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "announcement.json"), data, 0600)
+}
+
+// Get returns the announcement with the given ID.
+func Get(townRoot, id string) (*Announcement, error) {
+	data, err := os.ReadFile(filepath.Join(announcementDir(townRoot, id), "announcement.json")) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("announcement %s not found", id)
+		}
+		return nil, err
+	}
+	var a Announcement
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("parsing announcement %s: %w", id, err)
+	}
+	return &a, nil
+}
+
+// List returns all announcements, newest first.
+func List(townRoot string) ([]*Announcement, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var list []*Announcement
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		a, err := Get(townRoot, entry.Name())
+		if err != nil {
+			continue
+		}
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	return list, nil
+}
+
+// Acks returns the acknowledgements for an announcement, by agent.
+func Acks(townRoot, id string) (map[string]Ack, error) {
+	entries, err := os.ReadDir(filepath.Join(announcementDir(townRoot, id), "acks"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]Ack{}, nil
+		}
+		return nil, err
+	}
+	acks := make(map[string]Ack, len(entries))
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(announcementDir(townRoot, id), "acks", entry.Name())) //nolint:gosec // G304: path is constructed from trusted townRoot
+		if err != nil {
+			continue
+		}
+		var ack Ack
+		if err := json.Unmarshal(data, &ack); err != nil {
+			continue
+		}
+		acks[ack.Agent] = ack
+	}
+	return acks, nil
+}
+
+// Acknowledge records agent's acknowledgement. It returns false if the
+// agent had already acknowledged.
+func Acknowledge(townRoot, id, agent, via string) (bool, error) {
+	a, err := Get(townRoot, id)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := a.Sessions[agent]; !ok {
+		return false, ErrNotRecipient
+	}
+	path := ackPath(townRoot, id, agent)
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	}
+	data, err := json.MarshalIndent(Ack{Agent: agent, At: time.Now().UTC(), Via: via}, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, data, 0600)
+}
+
+// Pending returns the announcements agent has not acknowledged, oldest first.
+func Pending(townRoot, agent string) ([]*Announcement, error) {
+	list, err := List(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var pending []*Announcement
+	for i := len(list) - 1; i >= 0; i-- {
+		a := list[i]
+		if _, ok := a.Sessions[agent]; !ok {
+			continue
+		}
+		if _, err := os.Stat(ackPath(townRoot, a.ID, agent)); err == nil {
+			continue
+		}
+		pending = append(pending, a)
+	}
+	return pending, nil
+}
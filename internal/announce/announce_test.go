@@ -0,0 +1,64 @@
+package announce
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAcknowledge(t *testing.T) {
+	townRoot := t.TempDir()
+	a := &Announcement{
+		ID:        NewID(),
+		From:      "mayor",
+		Message:   "merge freeze starts now",
+		Scope:     "town",
+		CreatedAt: time.Now().UTC(),
+		Sessions:  map[string]string{"gastown/Toast": "gt-Toast", "gastown/crew/max": "gt-crew-max"},
+	}
+	if err := Save(townRoot, a); err != nil {
+		t.Fatal(err)
+	}
+
+	if pending, _ := Pending(townRoot, "gastown/Toast"); len(pending) != 1 {
+		t.Fatalf("pending = %d, want 1", len(pending))
+	}
+	ok, err := Acknowledge(townRoot, a.ID, "gastown/Toast", ViaCommand)
+	if err != nil || !ok {
+		t.Fatalf("Acknowledge = %v, %v", ok, err)
+	}
+	if ok, _ := Acknowledge(townRoot, a.ID, "gastown/Toast", ViaOutput); ok {
+		t.Error("second Acknowledge should report already acknowledged")
+	}
+	if _, err := Acknowledge(townRoot, a.ID, "gastown/nobody", ViaCommand); !errors.Is(err, ErrNotRecipient) {
+		t.Errorf("non-recipient ack err = %v, want ErrNotRecipient", err)
+	}
+	if pending, _ := Pending(townRoot, "gastown/Toast"); len(pending) != 0 {
+		t.Errorf("pending after ack = %d, want 0", len(pending))
+	}
+
+	acks, err := Acks(townRoot, a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acks["gastown/Toast"].Via != ViaCommand {
+		t.Errorf("ack via = %q, want %q", acks["gastown/Toast"].Via, ViaCommand)
+	}
+	if laggards := a.Laggards(acks); len(laggards) != 1 || laggards[0] != "gastown/crew/max" {
+		t.Errorf("laggards = %v, want [gastown/crew/max]", laggards)
+	}
+}
+
+func TestAckPattern(t *testing.T) {
+	re := AckPattern("ann-3f9a2c")
+	for _, s := range []string{"Acknowledged ann-3f9a2c", "acknowledged: ann-3f9a2c, pausing merges"} {
+		if !re.MatchString(s) {
+			t.Errorf("AckPattern should match %q", s)
+		}
+	}
+	for _, s := range []string{"Acknowledge with: gt ack ann-3f9a2c", "Acknowledged ann-3f9a2cd"} {
+		if re.MatchString(s) {
+			t.Errorf("AckPattern should not match %q", s)
+		}
+	}
+}
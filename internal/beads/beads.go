@@ -174,6 +174,7 @@ type UpdateOptions struct {
 	Priority     *int
 	Description  *string
 	Assignee     *string
+	Type         *string  // "task", "bug", "feature", "chore", "epic"
 	AddLabels    []string // Labels to add
 	RemoveLabels []string // Labels to remove
 	SetLabels    []string // Labels to set (replaces all existing)
@@ -837,6 +838,9 @@ func (b *Beads) Update(id string, opts UpdateOptions) error {
 	if opts.Assignee != nil {
 		args = append(args, "--assignee="+*opts.Assignee)
 	}
+	if opts.Type != nil {
+		args = append(args, "--type="+*opts.Type)
+	}
 	// Label operations: set-labels replaces all, otherwise use add/remove
 	if len(opts.SetLabels) > 0 {
 		for _, label := range opts.SetLabels {
@@ -0,0 +1,165 @@
+package beads
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultClaimTTL is how long a dispatch claim lasts unless renewed. Running
+// polecat sessions renew their claim each witness patrol, so a claim outlives
+// its holder by at most this long.
+const DefaultClaimTTL = 30 * time.Minute
+
+// IssueClaim is a dispatch lease on an issue: while it is active, only its
+// holder may assign the issue. It is stored in the issue description as
+// "claimed_by" and "claim_expires" lines.
+type IssueClaim struct {
+	Holder  string
+	Expires time.Time
+}
+
+// Active reports whether the claim is still in force at now.
+func (c *IssueClaim) Active(now time.Time) bool {
+	return c != nil && c.Holder != "" && now.Before(c.Expires)
+}
+
+// ClaimOptions controls how ClaimIssue treats an existing claim.
+type ClaimOptions struct {
+	TTL       time.Duration // Lease length (0 = DefaultClaimTTL)
+	Force     bool          // Take over an active claim held by anyone
+	Supersede []string      // Holders whose active claim may be taken over
+}
+
+// ClaimHeldError is returned when an issue is claimed by someone else.
+type ClaimHeldError struct {
+	IssueID string
+	Claim   IssueClaim
+}
+
+func (e *ClaimHeldError) Error() string {
+	return fmt.Sprintf("issue %s is claimed by %s until %s", e.IssueID, e.Claim.Holder, e.Claim.Expires.Format(time.RFC3339))
+}
+
+// ParseIssueClaim extracts the dispatch claim from an issue description.
+// Returns nil if there is none.
+func ParseIssueClaim(description string) *IssueClaim {
+	claim := &IssueClaim{}
+	for _, line := range strings.Split(description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "claimed_by":
+			claim.Holder = value
+		case "claim_expires":
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				claim.Expires = t
+			}
+		}
+	}
+	if claim.Holder == "" {
+		return nil
+	}
+	return claim
+}
+
+// SetIssueClaim returns description with its claim lines replaced by claim,
+// or removed if claim is nil. Other content is preserved.
+func SetIssueClaim(description string, claim *IssueClaim) string {
+	var kept []string
+	for _, line := range strings.Split(description, "\n") {
+		key, _, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok {
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "claimed_by", "claim_expires":
+				continue
+			}
+		}
+		kept = append(kept, line)
+	}
+	desc := strings.TrimRight(strings.Join(kept, "\n"), "\n")
+	if claim == nil {
+		return desc
+	}
+	lines := "claimed_by: " + claim.Holder + "\nclaim_expires: " + claim.Expires.UTC().Format(time.RFC3339)
+	if desc == "" {
+		return lines
+	}
+	return desc + "\n" + lines
+}
+
+// claimBlocks reports whether cur prevents holder from claiming at now.
+func claimBlocks(cur *IssueClaim, holder string, opts ClaimOptions, now time.Time) bool {
+	if !cur.Active(now) || cur.Holder == holder || opts.Force {
+		return false
+	}
+	for _, s := range opts.Supersede {
+		if s != "" && cur.Holder == s {
+			return false
+		}
+	}
+	return true
+}
+
+// ClaimIssue takes or renews the dispatch claim on an issue for holder. It
+// succeeds when the issue is unclaimed, the claim has expired, or it is held
+// by holder (or a holder opts allows taking over); otherwise it returns a
+// *ClaimHeldError. The check and the write happen under the bead's lock, so
+// of two concurrent dispatchers only one gets the claim.
+func (b *Beads) ClaimIssue(id, holder string, opts ClaimOptions) error {
+	if holder == "" {
+		return fmt.Errorf("claiming %s: empty holder", id)
+	}
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = DefaultClaimTTL
+	}
+
+	unlock, err := b.lockBead(id)
+	if err != nil {
+		return fmt.Errorf("acquiring bead lock: %w", err)
+	}
+	defer unlock()
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return fmt.Errorf("fetching %s: %w", id, err)
+	}
+	now := time.Now()
+	if cur := ParseIssueClaim(issue.Description); claimBlocks(cur, holder, opts, now) {
+		return &ClaimHeldError{IssueID: id, Claim: *cur}
+	}
+
+	desc := SetIssueClaim(issue.Description, &IssueClaim{Holder: holder, Expires: now.Add(ttl)})
+	if err := b.Update(id, UpdateOptions{Description: &desc}); err != nil {
+		return fmt.Errorf("recording claim on %s: %w", id, err)
+	}
+	return nil
+}
+
+// ReleaseIssueClaim drops holder's claim on an issue. A claim held by anyone
+// else is left alone.
+func (b *Beads) ReleaseIssueClaim(id, holder string) error {
+	unlock, err := b.lockBead(id)
+	if err != nil {
+		return fmt.Errorf("acquiring bead lock: %w", err)
+	}
+	defer unlock()
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return fmt.Errorf("fetching %s: %w", id, err)
+	}
+	cur := ParseIssueClaim(issue.Description)
+	if cur == nil || cur.Holder != holder {
+		return nil
+	}
+	desc := SetIssueClaim(issue.Description, nil)
+	if err := b.Update(id, UpdateOptions{Description: &desc}); err != nil {
+		return fmt.Errorf("releasing claim on %s: %w", id, err)
+	}
+	return nil
+}
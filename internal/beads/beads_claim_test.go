@@ -0,0 +1,58 @@
+package beads
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIssueClaimRoundTrip(t *testing.T) {
+	expires := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	desc := "Fix the thing\n\nattached_molecule: gt-wisp-1"
+
+	desc = SetIssueClaim(desc, &IssueClaim{Holder: "mayor (sling pid 42)", Expires: expires})
+	got := ParseIssueClaim(desc)
+	if got == nil || got.Holder != "mayor (sling pid 42)" || !got.Expires.Equal(expires) {
+		t.Fatalf("ParseIssueClaim = %+v, want holder and expiry back", got)
+	}
+
+	// Renewing replaces the lines instead of stacking them.
+	desc = SetIssueClaim(desc, &IssueClaim{Holder: "gastown/polecats/Toast", Expires: expires.Add(time.Hour)})
+	if got := ParseIssueClaim(desc); got.Holder != "gastown/polecats/Toast" {
+		t.Errorf("holder after renewal = %q", got.Holder)
+	}
+
+	desc = SetIssueClaim(desc, nil)
+	if got := ParseIssueClaim(desc); got != nil {
+		t.Errorf("claim after release = %+v, want nil", got)
+	}
+	if want := "Fix the thing\n\nattached_molecule: gt-wisp-1"; desc != want {
+		t.Errorf("description after release = %q, want %q", desc, want)
+	}
+}
+
+func TestClaimBlocks(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	held := &IssueClaim{Holder: "mayor", Expires: now.Add(time.Minute)}
+	expired := &IssueClaim{Holder: "mayor", Expires: now.Add(-time.Minute)}
+
+	tests := []struct {
+		name   string
+		cur    *IssueClaim
+		holder string
+		opts   ClaimOptions
+		want   bool
+	}{
+		{"unclaimed", nil, "overseer", ClaimOptions{}, false},
+		{"expired", expired, "overseer", ClaimOptions{}, false},
+		{"renewal by holder", held, "mayor", ClaimOptions{}, false},
+		{"held by another", held, "overseer", ClaimOptions{}, true},
+		{"forced", held, "overseer", ClaimOptions{Force: true}, false},
+		{"superseded", held, "gastown/polecats/Toast", ClaimOptions{Supersede: []string{"mayor"}}, false},
+		{"other superseded", held, "overseer", ClaimOptions{Supersede: []string{"deacon", ""}}, true},
+	}
+	for _, tt := range tests {
+		if got := claimBlocks(tt.cur, tt.holder, tt.opts, now); got != tt.want {
+			t.Errorf("%s: claimBlocks = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
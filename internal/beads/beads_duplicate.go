@@ -0,0 +1,79 @@
+package beads
+
+import (
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/triage"
+)
+
+// workItemTypes are the issue types that represent dispatchable work.
+var workItemTypes = map[string]bool{
+	"":        true,
+	"task":    true,
+	"bug":     true,
+	"feature": true,
+	"chore":   true,
+}
+
+// IsWorkItem reports whether an issue is ordinary work (a task, bug,
+// feature, or chore) rather than one of Gas Town's own bookkeeping beads:
+// agents, merge requests, molecules, convoys, and other gt:-labelled beads.
+func IsWorkItem(issue *Issue) bool {
+	if issue.Ephemeral || !workItemTypes[issue.Type] {
+		return false
+	}
+	for _, l := range issue.Labels {
+		if strings.HasPrefix(l, "gt:") {
+			return false
+		}
+	}
+	return true
+}
+
+// Duplicate is an existing issue that probably describes the same work.
+type Duplicate struct {
+	Issue *Issue
+	Score float64 // similarity, 0-1
+}
+
+// ListActive returns the open, in-progress and hooked issues in this
+// database, leaving out closed ones, which are most of a long-lived rig.
+func (b *Beads) ListActive() ([]*Issue, error) {
+	var issues []*Issue
+	for _, status := range []string{"open", "in_progress", StatusHooked} {
+		batch, err := b.List(ListOptions{Status: status, Priority: -1})
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, batch...)
+	}
+	return issues, nil
+}
+
+// FindDuplicates returns open, in-progress and hooked work items in this
+// database whose title and description are similar enough to be probable
+// duplicates of the given ones, best match first. excludeID, if set, is
+// skipped so an issue is never reported as a duplicate of itself.
+func (b *Beads) FindDuplicates(excludeID, title, description string) ([]Duplicate, error) {
+	issues, err := b.ListActive()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*Issue)
+	var corpus []triage.Issue
+	for _, issue := range issues {
+		if issue.ID == excludeID || !IsWorkItem(issue) {
+			continue
+		}
+		byID[issue.ID] = issue
+		corpus = append(corpus, triage.Issue{ID: issue.ID, Title: issue.Title, Description: issue.Description})
+	}
+
+	candidate := triage.Issue{ID: excludeID, Title: title, Description: description}
+	var dups []Duplicate
+	for _, m := range triage.FindSimilar(candidate, corpus, triage.DuplicateThreshold, 0) {
+		dups = append(dups, Duplicate{Issue: byID[m.ID], Score: m.Score})
+	}
+	return dups, nil
+}
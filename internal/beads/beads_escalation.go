@@ -27,6 +27,18 @@ type EscalationFields struct {
 	ReescalationCount  int    // Number of times this has been re-escalated
 	LastReescalatedAt  string // When last re-escalated (empty if never)
 	LastReescalatedBy  string // Who last re-escalated (empty if never)
+
+	// Context is structured context attached by the escalator (session
+	// name, captured output, patrol stats). Stored as one JSON line so
+	// multi-line values can't be mistaken for fields.
+	Context []EscalationAttachment
+}
+
+// EscalationAttachment is one named piece of escalation context. Value may
+// span multiple lines (e.g., a captured output tail).
+type EscalationAttachment struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 
@@ -96,6 +108,11 @@ func FormatEscalationDescription(title string, fields *EscalationFields) string
 	} else {
 		lines = append(lines, "last_reescalated_by: null")
 	}
+	if len(fields.Context) > 0 {
+		if data, err := json.Marshal(fields.Context); err == nil {
+			lines = append(lines, "context: "+string(data))
+		}
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -152,6 +169,11 @@ func ParseEscalationFields(description string) *EscalationFields {
 			fields.LastReescalatedAt = value
 		case "last_reescalated_by":
 			fields.LastReescalatedBy = value
+		case "context":
+			var attachments []EscalationAttachment
+			if err := json.Unmarshal([]byte(value), &attachments); err == nil {
+				fields.Context = attachments
+			}
 		}
 	}
 
@@ -1,6 +1,7 @@
 package beads
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -257,6 +258,10 @@ func TestEscalationFieldsRoundTrip(t *testing.T) {
 		ReescalationCount: 1,
 		LastReescalatedAt: "2024-06-15T11:30:00Z",
 		LastReescalatedBy: "deacon",
+		Context: []EscalationAttachment{
+			{Name: "session", Value: "gt-gastown-Toast"},
+			{Name: "output", Value: "severity: fake\nreason: not a field\n"},
+		},
 	}
 
 	formatted := FormatEscalationDescription("Escalation: Agent stuck", original)
@@ -298,6 +303,9 @@ func TestEscalationFieldsRoundTrip(t *testing.T) {
 	if parsed.LastReescalatedBy != original.LastReescalatedBy {
 		t.Errorf("LastReescalatedBy: got %q, want %q", parsed.LastReescalatedBy, original.LastReescalatedBy)
 	}
+	if !reflect.DeepEqual(parsed.Context, original.Context) {
+		t.Errorf("Context: got %+v, want %+v", parsed.Context, original.Context)
+	}
 }
 
 func TestBumpSeverity(t *testing.T) {
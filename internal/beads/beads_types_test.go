@@ -483,3 +483,23 @@ func TestBeads_getTownRoot(t *testing.T) {
 		t.Errorf("expected townRoot to be cached as %q, got %q", tmpDir, b.townRoot)
 	}
 }
+
+func TestIsWorkItem(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue *Issue
+		want  bool
+	}{
+		{"untyped", &Issue{}, true},
+		{"bug", &Issue{Type: "bug"}, true},
+		{"agent", &Issue{Type: "agent"}, false},
+		{"merge request", &Issue{Type: "task", Labels: []string{"gt:merge-request"}}, false},
+		{"wisp", &Issue{Type: "task", Ephemeral: true}, false},
+		{"plain label", &Issue{Type: "feature", Labels: []string{"ui"}}, true},
+	}
+	for _, tt := range tests {
+		if got := IsWorkItem(tt.issue); got != tt.want {
+			t.Errorf("%s: IsWorkItem = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
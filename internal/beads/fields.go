@@ -22,6 +22,7 @@ type AttachmentFields struct {
 	ConvoyID         string // Convoy bead ID tracking this issue (e.g., "hq-cv-abc")
 	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local", or "" (default = mr)
 	ConvoyOwned      bool   // If true, convoy has gt:owned label (caller-managed lifecycle)
+	AgentVersion     string // Agent CLI version the work was dispatched to (e.g., "2.0.14")
 }
 
 // ParseAttachmentFields extracts attachment fields from an issue's description.
@@ -84,6 +85,9 @@ func ParseAttachmentFields(issue *Issue) *AttachmentFields {
 		case "convoy_owned", "convoy-owned", "convoyowned":
 			fields.ConvoyOwned = strings.ToLower(value) == "true"
 			hasFields = true
+		case "agent_version", "agent-version", "agentversion":
+			fields.AgentVersion = value
+			hasFields = true
 		}
 	}
 
@@ -132,6 +136,9 @@ func FormatAttachmentFields(fields *AttachmentFields) string {
 	if fields.ConvoyOwned {
 		lines = append(lines, "convoy_owned: true")
 	}
+	if fields.AgentVersion != "" {
+		lines = append(lines, "agent_version: "+fields.AgentVersion)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -171,6 +178,9 @@ func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
 		"convoy_owned":      true,
 		"convoy-owned":      true,
 		"convoyowned":       true,
+		"agent_version":     true,
+		"agent-version":     true,
+		"agentversion":      true,
 	}
 
 	// Collect non-attachment lines from existing description
@@ -227,6 +227,19 @@ func TestConvoyFieldsRoundTrip(t *testing.T) {
 	}
 }
 
+func TestAgentVersionFieldRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "Fix the widget\n\ndispatched_by: mayor/\nagent_version: 1.0.0"}
+	fields := ParseAttachmentFields(issue)
+	if fields == nil || fields.AgentVersion != "1.0.0" {
+		t.Fatalf("ParseAttachmentFields = %+v, want agent_version 1.0.0", fields)
+	}
+	fields.AgentVersion = "2.0.14"
+	desc := SetAttachmentFields(issue, fields)
+	if strings.Count(desc, "agent_version:") != 1 || !strings.Contains(desc, "agent_version: 2.0.14") {
+		t.Errorf("SetAttachmentFields did not replace agent_version, got:\n%s", desc)
+	}
+}
+
 func TestConvoyOwnedFalseNotFormatted(t *testing.T) {
 	fields := &AttachmentFields{
 		ConvoyID:    "hq-cv-xyz",
@@ -0,0 +1,210 @@
+// Package budget enforces per-rig spend limits (config.BudgetConfig).
+//
+// Spend comes from the cost ledger (gt costs); this package decides what
+// it means: which period a limit covers, how much a rig has been extended
+// with gt budget extend, and whether the rig's agents and the mayor have
+// already been told about an exceeded limit this period.
+//
+// State lives in <town>/.runtime/budget.json.
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Period is the span a limit covers.
+type Period string
+
+const (
+	Day  Period = "day"
+	Week Period = "week"
+)
+
+// ParsePeriod parses "day" or "week".
+func ParsePeriod(s string) (Period, error) {
+	switch Period(s) {
+	case Day, Week:
+		return Period(s), nil
+	}
+	return "", fmt.Errorf("invalid period %q (want day or week)", s)
+}
+
+// Label returns "daily" or "weekly".
+func (p Period) Label() string {
+	if p == Week {
+		return "weekly"
+	}
+	return "daily"
+}
+
+// Start returns when the period containing now began: local midnight for
+// a day, Monday's midnight for a week.
+func (p Period) Start(now time.Time) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if p == Week {
+		offset := (int(midnight.Weekday()) + 6) % 7 // days since Monday
+		return midnight.AddDate(0, 0, -offset)
+	}
+	return midnight
+}
+
+// Limit returns cfg's limit for the period, or 0 for none.
+func Limit(cfg *config.BudgetConfig, p Period) float64 {
+	if cfg == nil {
+		return 0
+	}
+	if p == Week {
+		return cfg.WeeklyUSD
+	}
+	return cfg.DailyUSD
+}
+
+// Extension raises a rig's limit for one period.
+type Extension struct {
+	Rig         string    `json:"rig"`
+	Period      Period    `json:"period"`
+	PeriodStart time.Time `json:"period_start"`
+	AmountUSD   float64   `json:"amount_usd"`
+	By          string    `json:"by,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	At          time.Time `json:"at"`
+}
+
+// State is the persisted budget state.
+type State struct {
+	Extensions []Extension `json:"extensions,omitempty"`
+
+	// Notified maps "rig/period" to the start of the period in which the
+	// rig's agents and the mayor were last told the limit was exceeded.
+	Notified map[string]time.Time `json:"notified,omitempty"`
+}
+
+// StatePath returns the path of the budget state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "budget.json")
+}
+
+// Load reads the budget state. A missing file is an empty state.
+func Load(townRoot string) (*State, error) {
+	state := &State{}
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing budget state: %w", err)
+	}
+	return state, nil
+}
+
+// Save writes the budget state, dropping extensions from past periods.
+func Save(townRoot string, state *State, now time.Time) error {
+	kept := state.Extensions[:0]
+	for _, e := range state.Extensions {
+		if !e.PeriodStart.Before(e.Period.Start(now)) {
+			kept = append(kept, e)
+		}
+	}
+	state.Extensions = kept
+	return util.EnsureDirAndWriteJSON(StatePath(townRoot), state)
+}
+
+// Extend raises rig's limit for the current period by amount. The rig
+// is notified again if it goes over the raised limit.
+func (s *State) Extend(rig string, p Period, amount float64, by, reason string, now time.Time) {
+	delete(s.Notified, rig+"/"+string(p))
+	s.Extensions = append(s.Extensions, Extension{
+		Rig: rig, Period: p, PeriodStart: p.Start(now), AmountUSD: amount,
+		By: by, Reason: reason, At: now,
+	})
+}
+
+// Extended returns how much rig's limit has been raised this period.
+func (s *State) Extended(rig string, p Period, now time.Time) float64 {
+	start := p.Start(now)
+	var total float64
+	for _, e := range s.Extensions {
+		if e.Rig == rig && e.Period == p && e.PeriodStart.Equal(start) {
+			total += e.AmountUSD
+		}
+	}
+	return total
+}
+
+// MarkNotified records that rig was told about exceeding its limit this
+// period. It returns false if it already had been.
+func (s *State) MarkNotified(rig string, p Period, now time.Time) bool {
+	key := rig + "/" + string(p)
+	start := p.Start(now)
+	if last, ok := s.Notified[key]; ok && last.Equal(start) {
+		return false
+	}
+	if s.Notified == nil {
+		s.Notified = make(map[string]time.Time)
+	}
+	s.Notified[key] = start
+	return true
+}
+
+// Status is a rig's spend against one limit.
+type Status struct {
+	Rig         string  `json:"rig"`
+	Period      Period  `json:"period"`
+	SpentUSD    float64 `json:"spent_usd"`
+	LimitUSD    float64 `json:"limit_usd"`
+	ExtendedUSD float64 `json:"extended_usd,omitempty"`
+}
+
+// Allowed returns the limit including extensions.
+func (s Status) Allowed() float64 {
+	return s.LimitUSD + s.ExtendedUSD
+}
+
+// Exceeded reports whether spend has reached the allowed amount.
+func (s Status) Exceeded() bool {
+	return s.LimitUSD > 0 && s.SpentUSD >= s.Allowed()
+}
+
+// String describes the status, e.g. "$52.10 of $50.00 today".
+func (s Status) String() string {
+	when := "today"
+	if s.Period == Week {
+		when = "this week"
+	}
+	desc := fmt.Sprintf("$%.2f of $%.2f %s", s.SpentUSD, s.Allowed(), when)
+	if s.ExtendedUSD > 0 {
+		desc += fmt.Sprintf(" (incl. $%.2f extension)", s.ExtendedUSD)
+	}
+	return desc
+}
+
+// Evaluate returns rig's status against each configured limit. spend
+// returns the rig's spend since a time.
+func Evaluate(cfg *config.BudgetConfig, state *State, rig string, now time.Time, spend func(since time.Time) (float64, error)) ([]Status, error) {
+	var statuses []Status
+	for _, p := range []Period{Day, Week} {
+		limit := Limit(cfg, p)
+		if limit <= 0 {
+			continue
+		}
+		spent, err := spend(p.Start(now))
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, Status{
+			Rig: rig, Period: p, SpentUSD: spent, LimitUSD: limit,
+			ExtendedUSD: state.Extended(rig, p, now),
+		})
+	}
+	return statuses, nil
+}
@@ -0,0 +1,92 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestPeriodStart(t *testing.T) {
+	// 2026-10-16 is a Friday.
+	now := time.Date(2026, 10, 16, 15, 30, 0, 0, time.UTC)
+	if got, want := Day.Start(now), time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Day.Start = %v, want %v", got, want)
+	}
+	if got, want := Week.Start(now), time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Week.Start = %v, want %v", got, want)
+	}
+	sunday := time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)
+	if got, want := Week.Start(sunday), time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Week.Start(Sunday) = %v, want %v", got, want)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+	cfg := &config.BudgetConfig{DailyUSD: 50, WeeklyUSD: 200}
+	state := &State{}
+	spend := func(since time.Time) (float64, error) {
+		if since.Equal(Day.Start(now)) {
+			return 55, nil
+		}
+		return 120, nil
+	}
+
+	statuses, err := Evaluate(cfg, state, "gastown", now, spend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || !statuses[0].Exceeded() || statuses[1].Exceeded() {
+		t.Fatalf("statuses = %+v, want day exceeded and week under", statuses)
+	}
+
+	state.Extend("gastown", Day, 10, "human", "", now)
+	statuses, _ = Evaluate(cfg, state, "gastown", now, spend)
+	if statuses[0].Exceeded() || statuses[0].Allowed() != 60 {
+		t.Errorf("after extension: %+v, want $60 allowed and not exceeded", statuses[0])
+	}
+	if got := state.Extended("gastown", Day, now.AddDate(0, 0, 1)); got != 0 {
+		t.Errorf("extension carried into the next day: %v", got)
+	}
+
+	if statuses, _ := Evaluate(nil, state, "gastown", now, spend); len(statuses) != 0 {
+		t.Errorf("no budget = %+v, want none", statuses)
+	}
+}
+
+func TestMarkNotified(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+	state := &State{}
+	if !state.MarkNotified("gastown", Day, now) {
+		t.Fatal("first MarkNotified = false")
+	}
+	if state.MarkNotified("gastown", Day, now.Add(time.Hour)) {
+		t.Error("second MarkNotified in the same day = true")
+	}
+	if !state.MarkNotified("gastown", Day, now.AddDate(0, 0, 1)) {
+		t.Error("MarkNotified on the next day = false")
+	}
+	state.Extend("gastown", Day, 5, "human", "", now.AddDate(0, 0, 1))
+	if !state.MarkNotified("gastown", Day, now.AddDate(0, 0, 1)) {
+		t.Error("MarkNotified after an extension = false")
+	}
+}
+
+func TestSaveDropsPastExtensions(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now()
+	state := &State{}
+	state.Extend("gastown", Day, 5, "human", "", now.AddDate(0, 0, -2))
+	state.Extend("gastown", Day, 7, "human", "", now)
+	if err := Save(town, state, now); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Extensions) != 1 || loaded.Extensions[0].AmountUSD != 7 {
+		t.Errorf("extensions = %+v, want only today's", loaded.Extensions)
+	}
+}
@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/announce"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/takeover"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	announceTo    string
+	announceText  string
+	announceForce bool
+	announceJSON  bool
+)
+
+var announceCmd = &cobra.Command{
+	Use:     "announce",
+	GroupID: GroupComm,
+	Short:   "Announce something to all live agents and track acknowledgements",
+	Long: `Deliver an announcement to every live agent session in a rig or the
+whole town, and track which agents acknowledge it.
+
+Use it for coordination that everyone must see, like "merge freeze starts
+now". Each agent is asked to acknowledge with 'gt ack'. An agent that says
+"acknowledged <id>" in its output also counts; 'gt announce status' checks
+the panes of agents that haven't acked yet.
+
+Agents with DND enabled or under human takeover are skipped unless --force
+is given.
+
+Examples:
+  gt announce -m "Merge freeze starts now - do not push to main"
+  gt announce --to gastown -m "Refinery is down, hold your MRs"
+  gt announce status
+  gt announce status ann-3f9a2c`,
+	Args: cobra.NoArgs,
+	RunE: runAnnounce,
+}
+
+var announceStatusCmd = &cobra.Command{
+	Use:   "status [id]",
+	Short: "Show who has acknowledged an announcement",
+	Long: `Without an ID, list recent announcements with their acknowledgement
+counts. With an ID, list who acknowledged and who is lagging.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAnnounceStatus,
+}
+
+var ackCmd = &cobra.Command{
+	Use:     "ack [id]",
+	GroupID: GroupComm,
+	Short:   "Acknowledge an announcement",
+	Long: `Acknowledge an announcement made with 'gt announce'.
+
+Without an ID, acknowledges every announcement you haven't acknowledged yet.
+
+Examples:
+  gt ack
+  gt ack ann-3f9a2c`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAck,
+}
+
+func init() {
+	announceCmd.Flags().StringVar(&announceTo, "to", "town", "Who to announce to: town, or a rig name")
+	announceCmd.Flags().StringVarP(&announceText, "message", "m", "", "Announcement text (required)")
+	announceCmd.Flags().BoolVarP(&announceForce, "force", "f", false, "Also deliver to agents with DND enabled or under takeover")
+	announceStatusCmd.Flags().BoolVar(&announceJSON, "json", false, "Output as JSON")
+	announceCmd.AddCommand(announceStatusCmd)
+	rootCmd.AddCommand(announceCmd)
+	rootCmd.AddCommand(ackCmd)
+}
+
+// announceRecent is how many announcements 'gt announce status' lists.
+const announceRecent = 10
+
+// announceAckScanLines is how much of a laggard's pane is searched for an
+// acknowledgement.
+const announceAckScanLines = 200
+
+// formatAnnouncement is the text delivered to each agent. It must not
+// match announce.AckPattern, or every agent would appear to have acked.
+func formatAnnouncement(a *announce.Announcement) string {
+	return fmt.Sprintf("ANNOUNCEMENT %s: %s (Acknowledge with: gt ack %s)", a.ID, a.Message, a.ID)
+}
+
+// agentAddress converts a mail address (e.g. "mayor/") to the agent name
+// formatAgentName uses (e.g. "mayor").
+func agentAddress(mailAddress string) string {
+	return strings.TrimSuffix(mailAddress, "/")
+}
+
+func runAnnounce(cmd *cobra.Command, args []string) error {
+	if announceText == "" {
+		return fmt.Errorf("announcement text required: use -m")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	agents, err := getAgentSessions(true)
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+	from := agentAddress(detectSender())
+	a := &announce.Announcement{
+		ID:        announce.NewID(),
+		From:      from,
+		Message:   announceText,
+		Scope:     announceTo,
+		CreatedAt: time.Now().UTC(),
+		Sessions:  make(map[string]string),
+	}
+	text := fmt.Sprintf("[from %s] %s", from, formatAnnouncement(a))
+
+	t := tmux.NewTmux()
+	throttle := newNudgeThrottle(townRoot)
+	var skipped, failed int
+	for _, agent := range agents {
+		if announceTo != "town" && agent.Rig != announceTo {
+			continue
+		}
+		name := formatAgentName(agent)
+		if name == from {
+			continue
+		}
+		if !announceForce {
+			if shouldSend, level, _ := shouldNudgeTarget(townRoot, name, false); !shouldSend {
+				skipped++
+				fmt.Printf("  %s %s %s\n", style.Dim.Render("○"), name, style.Dim.Render("(DND: "+level+")"))
+				continue
+			}
+			if takeover.IsTakenOver(townRoot, agent.Name) {
+				skipped++
+				fmt.Printf("  %s %s %s\n", style.Dim.Render("○"), name, style.Dim.Render("(taken over)"))
+				continue
+			}
+		}
+		if err := t.NudgeSession(agent.Name, text); err != nil {
+			failed++
+			fmt.Printf("  %s %s %s\n", style.ErrorPrefix, name, style.Dim.Render(err.Error()))
+			continue
+		}
+		a.Sessions[name] = agent.Name
+		fmt.Printf("  %s %s\n", style.SuccessPrefix, name)
+		time.Sleep(throttle.delay(t, agent.Name))
+	}
+
+	if len(a.Sessions) == 0 {
+		if failed == 0 && skipped == 0 {
+			fmt.Printf("No live agents to announce to (scope: %s).\n", announceTo)
+			return nil
+		}
+		return fmt.Errorf("announcement was not delivered to any agent")
+	}
+	if err := announce.Save(townRoot, a); err != nil {
+		return fmt.Errorf("recording announcement: %w", err)
+	}
+	_ = events.LogFeed(events.TypeAnnouncement, from, events.AnnouncementPayload(a.ID, a.Scope, a.Message, len(a.Sessions)))
+
+	fmt.Printf("\n%s Announced %s to %d agent(s)", style.SuccessPrefix, style.Bold.Render(a.ID), len(a.Sessions))
+	if skipped > 0 {
+		fmt.Printf(", %d skipped", skipped)
+	}
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+	fmt.Printf("  Track acknowledgements: %s\n", style.Dim.Render("gt announce status "+a.ID))
+	return nil
+}
+
+func runAnnounceStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if len(args) == 0 {
+		return listAnnouncements(townRoot)
+	}
+
+	a, err := announce.Get(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	detectOutputAcks(townRoot, a)
+	acks, err := announce.Acks(townRoot, a.ID)
+	if err != nil {
+		return fmt.Errorf("reading acknowledgements: %w", err)
+	}
+	laggards := a.Laggards(acks)
+
+	if announceJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			*announce.Announcement
+			Acks     map[string]announce.Ack `json:"acks"`
+			Laggards []string                `json:"laggards"`
+		}{a, acks, laggards})
+	}
+
+	fmt.Printf("%s %s from %s, %s\n", style.Bold.Render(a.ID), a.Scope, a.From, formatAge(a.CreatedAt))
+	fmt.Printf("  %s\n\n", a.Message)
+	for _, agent := range a.Recipients() {
+		if ack, ok := acks[agent]; ok {
+			fmt.Printf("  %s %s %s\n", style.SuccessPrefix, agent,
+				style.Dim.Render(fmt.Sprintf("(%s after %s)", ack.Via, ack.At.Sub(a.CreatedAt).Round(time.Second))))
+		}
+	}
+	for _, agent := range laggards {
+		fmt.Printf("  %s %s %s\n", style.WarningPrefix, agent, style.Dim.Render("(no ack)"))
+	}
+	fmt.Printf("\n%d/%d acknowledged", len(a.Sessions)-len(laggards), len(a.Sessions))
+	if len(laggards) > 0 {
+		fmt.Printf(", %d lagging", len(laggards))
+	}
+	fmt.Println()
+	return nil
+}
+
+func listAnnouncements(townRoot string) error {
+	list, err := announce.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing announcements: %w", err)
+	}
+	if len(list) == 0 {
+		fmt.Println("No announcements.")
+		return nil
+	}
+	if len(list) > announceRecent {
+		list = list[:announceRecent]
+	}
+	for _, a := range list {
+		acks, _ := announce.Acks(townRoot, a.ID)
+		acked := len(a.Sessions) - len(a.Laggards(acks))
+		mark := style.SuccessPrefix
+		if acked < len(a.Sessions) {
+			mark = style.WarningPrefix
+		}
+		fmt.Printf("%s %s  %d/%d acked  %s  %s\n", mark, style.Bold.Render(a.ID), acked, len(a.Sessions),
+			style.Dim.Render(formatAge(a.CreatedAt)), a.Message)
+	}
+	return nil
+}
+
+// detectOutputAcks records an acknowledgement for each laggard whose pane
+// shows "acknowledged <id>".
+func detectOutputAcks(townRoot string, a *announce.Announcement) {
+	acks, err := announce.Acks(townRoot, a.ID)
+	if err != nil {
+		return
+	}
+	pattern := announce.AckPattern(a.ID)
+	t := tmux.NewTmux()
+	for _, agent := range a.Laggards(acks) {
+		pane, err := t.CapturePane(a.Sessions[agent], announceAckScanLines)
+		if err != nil || !pattern.MatchString(pane) {
+			continue
+		}
+		if ok, _ := announce.Acknowledge(townRoot, a.ID, agent, announce.ViaOutput); ok {
+			_ = events.LogFeed(events.TypeAnnouncementAck, agent, events.AnnouncementAckPayload(a.ID, announce.ViaOutput))
+		}
+	}
+}
+
+func runAck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	agent := agentAddress(detectSender())
+
+	var ids []string
+	if len(args) == 1 {
+		ids = args
+	} else {
+		pending, err := announce.Pending(townRoot, agent)
+		if err != nil {
+			return fmt.Errorf("listing announcements: %w", err)
+		}
+		for _, a := range pending {
+			ids = append(ids, a.ID)
+		}
+		if len(ids) == 0 {
+			fmt.Println("No announcements waiting for your acknowledgement.")
+			return nil
+		}
+	}
+
+	for _, id := range ids {
+		ok, err := announce.Acknowledge(townRoot, id, agent, announce.ViaCommand)
+		if errors.Is(err, announce.ErrNotRecipient) {
+			return fmt.Errorf("%s was not announced to %s", id, agent)
+		}
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Printf("%s %s already acknowledged\n", style.Dim.Render("○"), id)
+			continue
+		}
+		_ = events.LogFeed(events.TypeAnnouncementAck, agent, events.AnnouncementAckPayload(id, announce.ViaCommand))
+		fmt.Printf("%s Acknowledged %s\n", style.SuccessPrefix, id)
+	}
+	return nil
+}
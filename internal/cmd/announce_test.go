@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/announce"
+)
+
+func TestFormatAnnouncementIsNotAnAck(t *testing.T) {
+	a := &announce.Announcement{ID: "ann-3f9a2c", Message: "merge freeze starts now"}
+	text := formatAnnouncement(a)
+	if announce.AckPattern(a.ID).MatchString(text) {
+		t.Errorf("delivered text %q would count as an acknowledgement", text)
+	}
+}
+
+func TestAgentAddress(t *testing.T) {
+	for in, want := range map[string]string{"mayor/": "mayor", "gastown/crew/max": "gastown/crew/max", "gastown/Toast": "gastown/Toast"} {
+		if got := agentAddress(in); got != want {
+			t.Errorf("agentAddress(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/townspec"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var applyDryRun bool
+
+var applyCmd = &cobra.Command{
+	Use:     "apply <dir>",
+	GroupID: GroupConfig,
+	Short:   "Reconcile the town to a declarative YAML spec",
+	Long: `Reconcile the town to the desired state described in a directory of YAML
+files, so a town can be kept in version control and reproduced from it.
+
+The spec declares rigs (created with 'gt rig add' when missing), their crew
+(created with 'gt crew add'), polecat worktree pool sizes, rig settings,
+town settings (including the scheduler) and daemon patrols. Settings are
+merged key by key into the existing config files.
+
+Apply only adds and updates. Rigs, crew and settings the spec doesn't
+mention are left alone; rigs and crew are listed as unmanaged.
+
+The spec directory is remembered in town settings (spec_dir), so
+'gt config diff' and the daemon's config_drift patrol can report drift
+from it later.
+
+Example spec (town/town.yaml):
+  settings:
+    scheduler:
+      max_polecats: 6
+  daemon:
+    patrols:
+      witness: {enabled: true, interval: 5m}
+  rigs:
+    gastown:
+      git_url: https://github.com/example/gastown.git
+      prefix: gt
+      crew: [max, joe]
+      pool_size: 4
+      settings:
+        merge_queue: {run_tests: true}
+
+Examples:
+  gt apply town/ --dry-run   # Show the plan
+  gt apply town/             # Apply it`,
+	Args: cobra.ExactArgs(1),
+	RunE: runApply,
+}
+
+func init() {
+	applyCmd.Flags().BoolVarP(&applyDryRun, "dry-run", "n", false, "Show the plan without changing anything")
+	rootCmd.AddCommand(applyCmd)
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	spec, err := townspec.Load(args[0])
+	if err != nil {
+		return fmt.Errorf("loading spec: %w", err)
+	}
+	plan, err := townspec.Diff(townRoot, spec)
+	if err != nil {
+		return err
+	}
+
+	printApplyPlan(plan)
+	if applyDryRun {
+		return nil
+	}
+	if plan.Empty() {
+		return recordSpecDir(townRoot, args[0])
+	}
+
+	fmt.Println()
+	for _, c := range plan.Changes {
+		if err := applyChange(townRoot, c); err != nil {
+			return fmt.Errorf("%s: %w", applyChangeName(c), err)
+		}
+		fmt.Printf("%s %s\n", style.Success.Render("✓"), applyChangeName(c))
+	}
+	fmt.Printf("\n%s Town matches %s\n", style.Bold.Render("✓"), args[0])
+	return recordSpecDir(townRoot, args[0])
+}
+
+// recordSpecDir remembers the applied spec in town settings, for gt config
+// diff and the daemon's config_drift patrol.
+func recordSpecDir(townRoot, dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	path := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.SpecDir == abs {
+		return nil
+	}
+	settings.SpecDir = abs
+	if err := config.SaveTownSettings(path, settings); err != nil {
+		return fmt.Errorf("recording spec dir: %w", err)
+	}
+	return nil
+}
+
+func printApplyPlan(plan *townspec.Plan) {
+	for _, w := range plan.Warnings {
+		fmt.Printf("%s %s\n", style.Warning.Render("⚠"), w)
+	}
+	if plan.Empty() {
+		fmt.Println("No changes: the town matches the spec.")
+	} else {
+		fmt.Printf("%s\n", style.Bold.Render(fmt.Sprintf("Plan: %d change(s)", len(plan.Changes))))
+		for _, c := range plan.Changes {
+			fmt.Printf("  %s\n", c)
+		}
+	}
+	if len(plan.Unmanaged) > 0 {
+		fmt.Printf("%s\n", style.Dim.Render("Not in spec (left alone):"))
+		for _, u := range plan.Unmanaged {
+			fmt.Printf("  %s\n", style.Dim.Render(u))
+		}
+	}
+}
+
+// applyChangeName is a short label for progress and error messages.
+func applyChangeName(c *townspec.Change) string {
+	switch c.Kind {
+	case townspec.AddRig:
+		return "rig " + c.Rig
+	case townspec.AddCrew:
+		return fmt.Sprintf("crew %s/crew/%s", c.Rig, c.Crew)
+	}
+	return c.File
+}
+
+// applyChange performs one planned change. Rigs and crew are created by
+// running gt itself, so they get everything 'gt rig add' and 'gt crew add'
+// set up.
+func applyChange(townRoot string, c *townspec.Change) error {
+	var gtArgs []string
+	switch c.Kind {
+	case townspec.UpdateFile:
+		return c.Write()
+	case townspec.AddRig:
+		gtArgs = []string{"rig", "add", c.Rig, c.Spec.GitURL}
+		if c.Spec.Prefix != "" {
+			gtArgs = append(gtArgs, "--prefix", c.Spec.Prefix)
+		}
+		if c.Spec.Branch != "" {
+			gtArgs = append(gtArgs, "--branch", c.Spec.Branch)
+		}
+		if c.Spec.PushURL != "" {
+			gtArgs = append(gtArgs, "--push-url", c.Spec.PushURL)
+		}
+	case townspec.AddCrew:
+		gtArgs = []string{"crew", "add", c.Crew, "--rig", c.Rig}
+	default:
+		return fmt.Errorf("unknown change kind %q", c.Kind)
+	}
+
+	gtPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	gtCmd := exec.Command(gtPath, gtArgs...) //nolint:gosec // G204: args come from the town spec
+	gtCmd.Dir = townRoot
+	gtCmd.Stdout = os.Stdout
+	gtCmd.Stderr = os.Stderr
+	return gtCmd.Run()
+}
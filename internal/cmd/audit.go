@@ -475,6 +475,75 @@ func formatFeedSummary(e events.Event) string {
 			return fmt.Sprintf("Sent mail to %s", to)
 		}
 		return "Sent mail"
+	case events.TypeTakeover:
+		target, _ := e.Payload["target"].(string)
+		return fmt.Sprintf("Took over %s", target)
+	case events.TypeHandback:
+		target, _ := e.Payload["target"].(string)
+		if d, ok := e.Payload["duration"].(string); ok {
+			return fmt.Sprintf("Handed back %s after %s", target, d)
+		}
+		return fmt.Sprintf("Handed back %s", target)
+	case events.TypeMaintenanceStart:
+		if reason, ok := e.Payload["reason"].(string); ok {
+			return fmt.Sprintf("Started maintenance window: %s", reason)
+		}
+		return "Started maintenance window"
+	case events.TypeMaintenanceEnd:
+		return "Ended maintenance window"
+	case events.TypePanic:
+		if reason, ok := e.Payload["reason"].(string); ok {
+			return fmt.Sprintf("PANIC: froze the town: %s", reason)
+		}
+		return "PANIC: froze the town"
+	case events.TypeAgentSignal:
+		session, _ := e.Payload["session"].(string)
+		kind, _ := e.Payload["kind"].(string)
+		detail, _ := e.Payload["detail"].(string)
+		return fmt.Sprintf("%s: %s: %s", session, strings.ReplaceAll(kind, "_", " "), detail)
+	case events.TypeBudgetExceeded:
+		rig, _ := e.Payload["rig"].(string)
+		period, _ := e.Payload["period"].(string)
+		return fmt.Sprintf("%s went over its %s budget", rig, period)
+	case events.TypeBudgetExtended:
+		rig, _ := e.Payload["rig"].(string)
+		amount, _ := e.Payload["amount"].(float64)
+		return fmt.Sprintf("Extended %s budget by $%.2f", rig, amount)
+	case events.TypePatrolDryRun:
+		patrol, _ := e.Payload["patrol"].(string)
+		action, _ := e.Payload["action"].(string)
+		target, _ := e.Payload["target"].(string)
+		return fmt.Sprintf("[dry run] %s would %s: %s", patrol, action, target)
+	case events.TypeIssueHandoff:
+		bead, _ := e.Payload["bead"].(string)
+		from, _ := e.Payload["from"].(string)
+		to, _ := e.Payload["to"].(string)
+		return fmt.Sprintf("Handed off %s from %s to %s", bead, from, to)
+	case events.TypeAnnouncement:
+		id, _ := e.Payload["id"].(string)
+		scope, _ := e.Payload["scope"].(string)
+		message, _ := e.Payload["message"].(string)
+		return fmt.Sprintf("Announced %s to %s: %s", id, scope, message)
+	case events.TypeAnnouncementAck:
+		id, _ := e.Payload["id"].(string)
+		return fmt.Sprintf("Acknowledged %s", id)
+	case events.TypeOutboxQueued:
+		command, _ := e.Payload["command"].(string)
+		return fmt.Sprintf("Queued %q while beads was unreachable", command)
+	case events.TypeOutboxReplayed:
+		command, _ := e.Payload["command"].(string)
+		return fmt.Sprintf("Replayed queued %q", command)
+	case events.TypeOutboxFailed:
+		command, _ := e.Payload["command"].(string)
+		return fmt.Sprintf("Gave up replaying queued %q", command)
+	case events.TypePolecatCleanup:
+		rig, _ := e.Payload["rig"].(string)
+		polecat, _ := e.Payload["polecat"].(string)
+		actions, _ := e.Payload["actions"].([]interface{})
+		if failed, _ := e.Payload["failed"].([]interface{}); len(failed) > 0 {
+			return fmt.Sprintf("Cleaned up %s/%s (%d actions, %d failed)", rig, polecat, len(actions), len(failed))
+		}
+		return fmt.Sprintf("Cleaned up %s/%s (%d actions)", rig, polecat, len(actions))
 	default:
 		return e.Type
 	}
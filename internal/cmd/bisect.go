@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// bisectLabel marks bisection tasks so a failing branch gets one at a time.
+const bisectLabel = "bisect"
+
+var (
+	bisectStartRig        string
+	bisectStartTest       string
+	bisectStartNoDispatch bool
+
+	bisectHelperGood string
+	bisectHelperBad  string
+	bisectHelperTest string
+	bisectHelperPost string
+)
+
+var bisectCmd = &cobra.Command{
+	Use:     "bisect",
+	GroupID: GroupWork,
+	Short:   "Find the merge that broke an integration branch",
+	RunE:    requireSubcommand,
+	Long: `Bisect test failures on integration branches.
+
+When tests fail while landing an integration branch, 'gt mq integration
+land' files a bisection task and dispatches a polecat to it automatically.
+The polecat runs 'gt bisect helper' to find the first failing merge and
+posts the result to the epic.
+
+The refinery squash-merges each MR into the integration branch, so every
+first-parent commit on it is one MR: the offending commit is the
+offending MR.`,
+}
+
+var bisectStartCmd = &cobra.Command{
+	Use:   "start <epic-id>",
+	Short: "File and dispatch a bisection task for an epic's integration branch",
+	Long: `Create a bisection task for an epic's integration branch and sling it to
+the epic's rig.
+
+The task tells the polecat which range to bisect (the integration branch
+against its base branch) and which test command to run. If an open
+bisection task for the branch already exists, it is reused.
+
+Examples:
+  gt bisect start gt-epic-abc
+  gt bisect start gt-epic-abc --test "go test ./internal/parser/..."
+  gt bisect start gt-epic-abc --no-dispatch   # File the task only`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBisectStart,
+}
+
+var bisectHelperCmd = &cobra.Command{
+	Use:   "helper",
+	Short: "Run git bisect over MR merges and report the offending one",
+	Long: `Bisect the first-parent history between a good and a bad ref in the
+current worktree, running the test command at each step.
+
+Bisecting first-parent commits steps over whole MRs, so the result is the
+MR (squash commit) that introduced the failure. Issue IDs in the commit
+subject, e.g. "fix: retry backoff (gp-xyz)", are reported with it.
+
+The test command defaults to the rig's merge_queue.test_command. Exit codes
+follow git bisect run: 0 is good, 125 skips the commit, anything else is bad.
+
+Examples:
+  gt bisect helper --good origin/main
+  gt bisect helper --good origin/main --bad origin/integration/auth --post gt-epic-abc
+  gt bisect helper --good v1.2.0 --test "make test-unit"`,
+	Args: cobra.NoArgs,
+	RunE: runBisectHelper,
+}
+
+func init() {
+	bisectStartCmd.Flags().StringVar(&bisectStartRig, "rig", "", "Rig of the epic (default: from the epic's prefix)")
+	bisectStartCmd.Flags().StringVar(&bisectStartTest, "test", "", "Test command to bisect with (default: rig's merge_queue.test_command)")
+	bisectStartCmd.Flags().BoolVar(&bisectStartNoDispatch, "no-dispatch", false, "Create the task without slinging it")
+
+	bisectHelperCmd.Flags().StringVar(&bisectHelperGood, "good", "", "Known-good ref (required)")
+	bisectHelperCmd.Flags().StringVar(&bisectHelperBad, "bad", "HEAD", "Known-bad ref")
+	bisectHelperCmd.Flags().StringVar(&bisectHelperTest, "test", "", "Test command (default: rig's merge_queue.test_command)")
+	bisectHelperCmd.Flags().StringVar(&bisectHelperPost, "post", "", "Issue (usually the epic) to post the result to as a comment")
+	_ = bisectHelperCmd.MarkFlagRequired("good")
+
+	bisectCmd.AddCommand(bisectStartCmd)
+	bisectCmd.AddCommand(bisectHelperCmd)
+	rootCmd.AddCommand(bisectCmd)
+}
+
+func runBisectStart(cmd *cobra.Command, args []string) error {
+	epicID := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName := bisectStartRig
+	if rigName == "" {
+		if rigName = resolveRigForBead(townRoot, epicID); rigName == "" {
+			return fmt.Errorf("cannot resolve rig for %s\nSpecify explicitly: gt bisect start %s --rig <rig>", epicID, epicID)
+		}
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	g, err := getRigGit(r.Path)
+	if err != nil {
+		return fmt.Errorf("initializing git: %w", err)
+	}
+	epic, err := beads.New(r.Path).Show(epicID)
+	if err != nil {
+		return fmt.Errorf("fetching epic %s: %w", epicID, err)
+	}
+	branchName := resolveEpicBranch(epic, r.Path, g)
+	baseBranch := beads.GetBaseBranchField(epic.Description)
+	if baseBranch == "" {
+		baseBranch = r.DefaultBranch()
+	}
+
+	testCmd := bisectStartTest
+	if testCmd == "" {
+		testCmd = getTestCommand(r.Path)
+	}
+	_, err = startBisection(townRoot, r, epicID, branchName, baseBranch, testCmd, !bisectStartNoDispatch)
+	return err
+}
+
+// startBisection files a bisection task for an integration branch whose
+// tests fail, and optionally slings it to the rig. An open bisection task
+// for the same branch is reused rather than duplicated. Returns the task ID.
+func startBisection(townRoot string, r *rig.Rig, epicID, branchName, baseBranch, testCmd string, dispatch bool) (string, error) {
+	bd := beads.New(r.Path)
+	title := fmt.Sprintf("Bisect test failure on %s", branchName)
+
+	existing, err := bd.List(beads.ListOptions{Status: "open", Label: bisectLabel, Priority: -1})
+	if err != nil {
+		return "", fmt.Errorf("listing bisection tasks: %w", err)
+	}
+	for _, issue := range existing {
+		if issue.Title == title {
+			fmt.Printf("  %s Bisection already filed: %s\n", style.Dim.Render("→"), issue.ID)
+			return issue.ID, nil
+		}
+	}
+
+	issue, err := bd.Create(beads.CreateOptions{
+		Title:       title,
+		Type:        "bug",
+		Priority:    1,
+		Description: bisectTaskDescription(epicID, branchName, baseBranch, testCmd),
+		Actor:       detectSender(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating bisection task: %w", err)
+	}
+	if err := bd.Update(issue.ID, beads.UpdateOptions{AddLabels: []string{bisectLabel}}); err != nil {
+		fmt.Printf("  %s Could not label %s: %v\n", style.WarningPrefix, issue.ID, err)
+	}
+	fmt.Printf("  %s Filed bisection task %s\n", style.SuccessPrefix, issue.ID)
+
+	if !dispatch {
+		return issue.ID, nil
+	}
+	result, err := executeSling(SlingParams{
+		BeadID:        issue.ID,
+		RigName:       r.Name,
+		FormulaName:   resolveFormula("", false),
+		BaseBranch:    branchName,
+		NoMerge:       true, // Bisection reports a result; it lands no code
+		CallerContext: "bisect",
+		TownRoot:      townRoot,
+	})
+	if err != nil {
+		return issue.ID, fmt.Errorf("dispatching %s: %w", issue.ID, err)
+	}
+	wakeRigAgents(r.Name)
+	fmt.Printf("  %s Dispatched %s to %s\n", style.SuccessPrefix, issue.ID, result.PolecatName)
+	return issue.ID, nil
+}
+
+// bisectTaskDescription writes the instructions for a bisection polecat.
+func bisectTaskDescription(epicID, branchName, baseBranch, testCmd string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Tests fail on integration branch %s (epic %s). ", branchName, epicID)
+	sb.WriteString("Find the MR that introduced the failure. Do not fix it; report it.\n\n")
+	fmt.Fprintf(&sb, "- Good: origin/%s\n", baseBranch)
+	fmt.Fprintf(&sb, "- Bad: origin/%s (your worktree starts here)\n", branchName)
+	if testCmd != "" {
+		fmt.Fprintf(&sb, "- Test command: %s\n", testCmd)
+	}
+	sb.WriteString("\n## Steps\n\n")
+	sb.WriteString("1. Reproduce the failure at HEAD with the test command. If it passes, the failure ")
+	sb.WriteString("is flaky or environmental: say so on the epic and stop.\n")
+	sb.WriteString("2. Narrow the test command to the failing tests if you can; bisection is faster.\n")
+	fmt.Fprintf(&sb, "3. Run:\n\n    gt bisect helper --good origin/%s --bad HEAD --post %s", baseBranch, epicID)
+	if testCmd != "" {
+		fmt.Fprintf(&sb, " --test %q", testCmd)
+	}
+	sb.WriteString("\n\n4. Check the reported commit makes sense (read its diff), add anything useful ")
+	fmt.Fprintf(&sb, "to the epic with `bd comment %s ...`, then run `gt done`.\n", epicID)
+	return sb.String()
+}
+
+func runBisectHelper(cmd *cobra.Command, args []string) error {
+	testCmd := bisectHelperTest
+	if testCmd == "" {
+		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+			if _, r, err := findCurrentRig(townRoot); err == nil {
+				testCmd = getTestCommand(r.Path)
+			}
+		}
+	}
+	if testCmd == "" {
+		return fmt.Errorf("no test command: pass --test or set merge_queue.test_command in the rig settings")
+	}
+
+	fmt.Printf("Bisecting %s..%s (first-parent) with: %s\n", bisectHelperGood, bisectHelperBad, testCmd)
+	if out, err := exec.Command("git", "bisect", "start", "--first-parent", bisectHelperBad, bisectHelperGood).CombinedOutput(); err != nil {
+		return fmt.Errorf("git bisect start: %v\n%s", err, out)
+	}
+	defer func() { _ = exec.Command("git", "bisect", "reset").Run() }()
+
+	var buf bytes.Buffer
+	run := exec.Command("git", "bisect", "run", "sh", "-c", testCmd) //nolint:gosec // G204: test command is operator-supplied
+	run.Stdout = io.MultiWriter(os.Stdout, &buf)
+	run.Stderr = io.MultiWriter(os.Stderr, &buf)
+	runErr := run.Run()
+
+	sha := parseBisectResult(buf.String())
+	if sha == "" {
+		if runErr != nil {
+			return fmt.Errorf("git bisect run: %w", runErr)
+		}
+		return fmt.Errorf("git bisect did not identify a first bad commit")
+	}
+
+	subject := sha
+	if out, err := exec.Command("git", "log", "-1", "--format=%s", sha).Output(); err == nil {
+		subject = strings.TrimSpace(string(out))
+	}
+	report := bisectReport(sha, subject, bisectHelperGood, bisectHelperBad, testCmd)
+	fmt.Printf("\n%s\n", style.Bold.Render(report))
+
+	if bisectHelperPost != "" {
+		if _, err := beads.New(resolveBeadDir(bisectHelperPost)).Run("comment", bisectHelperPost, report); err != nil {
+			return fmt.Errorf("posting result to %s: %w", bisectHelperPost, err)
+		}
+		fmt.Printf("%s Posted result to %s\n", style.SuccessPrefix, bisectHelperPost)
+	}
+	return nil
+}
+
+// bisectFirstBad matches git bisect's verdict line.
+var bisectFirstBad = regexp.MustCompile(`(?m)^([0-9a-f]{7,64}) is the first bad commit`)
+
+// parseBisectResult returns the first bad commit from git bisect run
+// output, or "" if bisect did not reach a verdict.
+func parseBisectResult(output string) string {
+	if m := bisectFirstBad.FindStringSubmatch(output); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// bisectSubjectIDs matches parenthesized tokens in a commit subject, where
+// polecat commits carry their issue ID: "fix: retry backoff (gp-xyz)".
+var bisectSubjectIDs = regexp.MustCompile(`\(([^()\s]+)\)`)
+
+// bisectIssueIDs returns the issue IDs referenced in a commit subject.
+func bisectIssueIDs(subject string) []string {
+	var ids []string
+	for _, m := range bisectSubjectIDs.FindAllStringSubmatch(subject, -1) {
+		if looksLikeBeadID(m[1]) {
+			ids = append(ids, m[1])
+		}
+	}
+	return ids
+}
+
+// bisectReport formats a bisection result for the terminal and the epic.
+func bisectReport(sha, subject, good, bad, testCmd string) string {
+	short := sha
+	if len(short) > 12 {
+		short = short[:12]
+	}
+	report := fmt.Sprintf("%s %q", short, subject)
+	if ids := bisectIssueIDs(subject); len(ids) > 0 {
+		report += " (issue " + strings.Join(ids, ", ") + ")"
+	}
+	return fmt.Sprintf("bisect: first failing merge is %s; range %s..%s; test: %s", report, good, bad, testCmd)
+}
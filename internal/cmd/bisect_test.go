@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBisectResult(t *testing.T) {
+	output := `running  'sh' '-c' 'go test ./...'
+ok  	example.com/pkg	0.01s
+Bisecting: 0 revisions left to test after this (roughly 0 steps)
+3f2a9c1d4e5b6a7980f1e2d3c4b5a69788796a5b is the first bad commit
+commit 3f2a9c1d4e5b6a7980f1e2d3c4b5a69788796a5b
+`
+	if got := parseBisectResult(output); got != "3f2a9c1d4e5b6a7980f1e2d3c4b5a69788796a5b" {
+		t.Errorf("parseBisectResult = %q", got)
+	}
+	if got := parseBisectResult("bisect run failed:\nexit code 128"); got != "" {
+		t.Errorf("parseBisectResult without verdict = %q, want empty", got)
+	}
+}
+
+func TestBisectIssueIDs(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    []string
+	}{
+		{"fix: retry backoff (gp-xyz)", []string{"gp-xyz"}},
+		{"feat(parser): handle tabs (gt-abc) (gt-def)", []string{"gt-abc", "gt-def"}},
+		{"chore: bump deps", nil},
+	}
+	for _, tt := range tests {
+		if got := bisectIssueIDs(tt.subject); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("bisectIssueIDs(%q) = %v, want %v", tt.subject, got, tt.want)
+		}
+	}
+}
+
+func TestBisectTaskDescription(t *testing.T) {
+	got := bisectTaskDescription("gt-epic", "integration/auth", "main", "make test")
+	for _, want := range []string{
+		"origin/main",
+		"origin/integration/auth",
+		`gt bisect helper --good origin/main --bad HEAD --post gt-epic --test "make test"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("description missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	bootstrapName         string
+	bootstrapAgent        string
+	bootstrapNoSupervisor bool
+	bootstrapCheckOnly    bool
+)
+
+var bootstrapCmd = &cobra.Command{
+	Use:     "bootstrap [path]",
+	GroupID: GroupWorkspace,
+	Short:   "Set up Gas Town on a brand-new machine in one step",
+	Long: `Bootstrap a complete Gas Town installation on a fresh machine.
+
+Runs preflight checks for every external binary Gas Town depends on
+(git, tmux, dolt, bd, and the agent CLI), then performs a full install:
+
+  1. Create the HQ directory structure and mayor identity (gt install)
+  2. Initialize the Dolt data dir and town database, start the server
+  3. Initialize git for the HQ
+  4. Install the daemon as a user service (systemd on Linux, launchd on macOS)
+
+Preflight failures abort before anything is written, so a failed bootstrap
+can simply be re-run once the missing tools are installed.
+
+Examples:
+  gt bootstrap ~/gt                      # Full setup at ~/gt
+  gt bootstrap ~/gt --agent codex        # Verify codex instead of claude
+  gt bootstrap --check                   # Only run preflight checks
+  gt bootstrap ~/gt --no-supervisor      # Skip systemd/launchd service`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBootstrap,
+}
+
+func init() {
+	bootstrapCmd.Flags().StringVarP(&bootstrapName, "name", "n", "", "Town name (defaults to directory name)")
+	bootstrapCmd.Flags().StringVar(&bootstrapAgent, "agent", string(config.DefaultAgentPreset()), "Agent preset whose CLI must be installed")
+	bootstrapCmd.Flags().BoolVar(&bootstrapNoSupervisor, "no-supervisor", false, "Do not install the daemon as a user service")
+	bootstrapCmd.Flags().BoolVar(&bootstrapCheckOnly, "check", false, "Run preflight checks only")
+	rootCmd.AddCommand(bootstrapCmd)
+}
+
+// bootstrapCheck is the result of one preflight check.
+type bootstrapCheck struct {
+	Name   string
+	OK     bool
+	Detail string
+	Hint   string
+}
+
+// bootstrapPreflight verifies the binaries Gas Town needs. lookPath is
+// injectable for tests.
+func bootstrapPreflight(agent string, lookPath func(string) (string, error)) []bootstrapCheck {
+	var checks []bootstrapCheck
+
+	binary := func(name, bin, hint string) {
+		c := bootstrapCheck{Name: name, Hint: hint}
+		if path, err := lookPath(bin); err == nil {
+			c.OK = true
+			c.Detail = path
+		} else {
+			c.Detail = bin + " not found in PATH"
+		}
+		checks = append(checks, c)
+	}
+
+	binary("git", "git", "install git from your package manager")
+	binary("tmux", "tmux", "install tmux (brew install tmux / apt install tmux)")
+
+	doltCheck := bootstrapCheck{Name: "dolt", Hint: "see " + deps.DoltInstallURL}
+	switch status, version, detail := deps.CheckDolt(); status {
+	case deps.DoltOK:
+		doltCheck.OK = true
+		doltCheck.Detail = version
+	case deps.DoltTooOld:
+		doltCheck.Detail = fmt.Sprintf("%s is older than required %s", version, deps.MinDoltVersion)
+	case deps.DoltNotFound:
+		doltCheck.Detail = "dolt not found in PATH"
+	default:
+		doltCheck.Detail = "could not determine dolt version " + detail
+	}
+	checks = append(checks, doltCheck)
+
+	beadsCheck := bootstrapCheck{Name: "bd", Hint: "go install " + deps.BeadsInstallPath}
+	switch status, version := deps.CheckBeads(); status {
+	case deps.BeadsOK:
+		beadsCheck.OK = true
+		beadsCheck.Detail = version
+	case deps.BeadsTooOld:
+		beadsCheck.Detail = fmt.Sprintf("%s is older than required %s", version, deps.MinBeadsVersion)
+	case deps.BeadsNotFound:
+		// Install will auto-install bd, so a missing bd is not fatal.
+		beadsCheck.OK = true
+		beadsCheck.Detail = "not found (will be installed)"
+	default:
+		beadsCheck.Detail = "could not determine bd version"
+	}
+	checks = append(checks, beadsCheck)
+
+	preset := config.GetAgentPresetByName(agent)
+	if preset == nil {
+		checks = append(checks, bootstrapCheck{
+			Name:   "agent",
+			Detail: fmt.Sprintf("unknown agent preset %q", agent),
+			Hint:   "see 'gt config agent list'",
+		})
+	} else {
+		binary("agent ("+agent+")", preset.Command, "install the "+agent+" CLI")
+	}
+
+	return checks
+}
+
+func runBootstrap(cmd *cobra.Command, args []string) error {
+	fmt.Printf("%s Preflight checks\n\n", style.Bold.Render("🔍"))
+	checks := bootstrapPreflight(bootstrapAgent, exec.LookPath)
+	failed := 0
+	for _, c := range checks {
+		if c.OK {
+			fmt.Printf("   %s %-14s %s\n", style.Success.Render("✓"), c.Name, style.Dim.Render(c.Detail))
+			continue
+		}
+		failed++
+		fmt.Printf("   %s %-14s %s\n", style.Error.Render("✗"), c.Name, c.Detail)
+		if c.Hint != "" {
+			fmt.Printf("     %s\n", style.Dim.Render(c.Hint))
+		}
+	}
+	fmt.Println()
+
+	if failed > 0 {
+		return fmt.Errorf("%d preflight check(s) failed; fix them and re-run 'gt bootstrap'", failed)
+	}
+	if bootstrapCheckOnly {
+		fmt.Printf("%s All preflight checks passed\n", style.SuccessPrefix)
+		return nil
+	}
+
+	// Delegate to install with the full feature set enabled.
+	installName = bootstrapName
+	installGit = true
+	installSupervisor = !bootstrapNoSupervisor
+	return runInstall(cmd, args)
+}
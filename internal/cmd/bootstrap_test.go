@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBootstrapPreflightMissingBinaries(t *testing.T) {
+	lookPath := func(bin string) (string, error) {
+		if bin == "git" {
+			return "/usr/bin/git", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	checks := bootstrapPreflight("claude", lookPath)
+	byName := make(map[string]bootstrapCheck)
+	for _, c := range checks {
+		byName[c.Name] = c
+	}
+
+	if c := byName["git"]; !c.OK || c.Detail != "/usr/bin/git" {
+		t.Errorf("git check = %+v, want OK with path", c)
+	}
+	if c := byName["tmux"]; c.OK || c.Hint == "" {
+		t.Errorf("tmux check = %+v, want failure with hint", c)
+	}
+	if c := byName["agent (claude)"]; c.OK {
+		t.Errorf("agent check = %+v, want failure", c)
+	}
+}
+
+func TestBootstrapPreflightUnknownAgent(t *testing.T) {
+	lookPath := func(bin string) (string, error) { return "/bin/" + bin, nil }
+
+	checks := bootstrapPreflight("no-such-agent", lookPath)
+	last := checks[len(checks)-1]
+	if last.Name != "agent" || last.OK {
+		t.Errorf("last check = %+v, want failed agent check", last)
+	}
+}
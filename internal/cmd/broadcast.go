@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/quota"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -106,6 +109,7 @@ func runBroadcast(cmd *cobra.Command, args []string) error {
 	townRoot, _ := workspace.FindFromCwd()
 	var succeeded, failed, skipped int
 	var failures []string
+	throttle := newNudgeThrottle(townRoot)
 
 	fmt.Printf("Broadcasting to %d agent(s)...\n\n", len(targets))
 
@@ -130,9 +134,15 @@ func runBroadcast(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  %s %s %s\n", style.SuccessPrefix, AgentTypeIcons[agent.Type], agentName)
 		}
 
-		// Small delay between nudges to avoid overwhelming tmux
+		// Small delay between nudges to avoid overwhelming tmux. Nudges to
+		// agents whose provider is rate-limited are staggered further, so
+		// they don't all resume and hit the limit at once.
 		if i < len(targets)-1 {
-			time.Sleep(100 * time.Millisecond)
+			delay := throttle.delay(t, agent.Name)
+			if delay > time.Second {
+				fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("provider rate-limited, waiting %s", delay)))
+			}
+			time.Sleep(delay)
 		}
 	}
 
@@ -175,3 +185,39 @@ func formatAgentName(agent *AgentSession) string {
 	}
 	return agent.Name
 }
+
+// nudgeThrottle spaces out nudges to agents whose provider is under
+// rate-limit pressure.
+type nudgeThrottle struct {
+	cfg       *capacity.ThrottleConfig
+	pressure  map[string]capacity.ProviderPressure
+	providers *quota.ProviderResolver
+}
+
+func newNudgeThrottle(townRoot string) *nudgeThrottle {
+	nt := &nudgeThrottle{}
+	if townRoot == "" {
+		return nt
+	}
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && settings.Scheduler != nil {
+		nt.cfg = settings.Scheduler.Throttle
+	}
+	if ps, err := capacity.LoadPressure(townRoot); err == nil {
+		nt.pressure = ps.Providers
+	}
+	nt.providers = &quota.ProviderResolver{TownRoot: townRoot}
+	return nt
+}
+
+// delay returns how long to wait after nudging sess.
+func (nt *nudgeThrottle) delay(t *tmux.Tmux, sess string) time.Duration {
+	const base = 100 * time.Millisecond
+	if len(nt.pressure) == 0 {
+		return base
+	}
+	provider := nt.providers.SessionProvider(t, sess)
+	if nt.cfg.Level(nt.pressure[provider], time.Now()) == capacity.ThrottleNone {
+		return base
+	}
+	return nt.cfg.GetStagger()
+}
@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/takeover"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	budgetJSON         bool
+	budgetExtendPeriod string
+	budgetExtendReason string
+)
+
+var budgetCmd = &cobra.Command{
+	Use:     "budget",
+	GroupID: GroupDiag,
+	Short:   "Show and extend per-rig spend budgets",
+	Long: `Show each rig's spend against its budget.
+
+Budgets are set per rig in <rig>/settings/config.json:
+
+  "budget": {"daily_usd": 50, "weekly_usd": 200}
+
+Spend comes from the cost ledger (gt costs). When a rig reaches a limit:
+  - new polecat spawns for the rig are blocked (gt sling, scheduler dispatch)
+  - the rig's polecats are nudged to wrap up their current work
+  - the mayor is notified by mail
+
+The daemon checks budgets every heartbeat. Use 'gt budget extend' to raise
+a limit for the rest of the day or week.
+
+Examples:
+  gt budget
+  gt budget extend gastown 25
+  gt budget extend gastown 100 --period week --reason "release crunch"`,
+	Args: cobra.NoArgs,
+	RunE: runBudget,
+}
+
+var budgetExtendCmd = &cobra.Command{
+	Use:   "extend <rig> <usd>",
+	Short: "Raise a rig's budget for the rest of the period",
+	Long: `Raise a rig's spend limit by <usd> until the end of the current day
+(or week, with --period week). Blocked spawns resume as soon as spend is
+under the new limit.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runBudgetExtend,
+}
+
+var budgetCheckCmd = &cobra.Command{
+	Use:    "check",
+	Short:  "Enforce rig budgets (called by the daemon)",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runBudgetCheck,
+}
+
+func init() {
+	budgetCmd.Flags().BoolVar(&budgetJSON, "json", false, "Output as JSON")
+	budgetExtendCmd.Flags().StringVar(&budgetExtendPeriod, "period", string(budget.Day), "Period to extend: day or week")
+	budgetExtendCmd.Flags().StringVarP(&budgetExtendReason, "reason", "r", "", "Why the budget is being extended")
+	budgetCmd.AddCommand(budgetExtendCmd)
+	budgetCmd.AddCommand(budgetCheckCmd)
+	rootCmd.AddCommand(budgetCmd)
+}
+
+// budgetWrapUpMessage is nudged to a rig's polecats when its budget runs out.
+const budgetWrapUpMessage = "Rig %s has reached its spend budget (%s). Wrap up: commit and push " +
+	"what you have, run gt done if the work is complete, and do not start anything new."
+
+func runBudget(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := budget.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	statuses, err := townBudgetStatuses(townRoot, state, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if budgetJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+	if len(statuses) == 0 {
+		fmt.Println("No rig has a budget. Set one in <rig>/settings/config.json:")
+		fmt.Println(style.Dim.Render(`  "budget": {"daily_usd": 50, "weekly_usd": 200}`))
+		return nil
+	}
+	for _, s := range statuses {
+		mark := style.SuccessPrefix
+		if s.Exceeded() {
+			mark = style.ErrorPrefix
+		}
+		fmt.Printf("%s %-16s %s\n", mark, s.Rig, s)
+	}
+	return nil
+}
+
+func runBudgetExtend(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName := args[0]
+	amount, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || amount <= 0 {
+		return fmt.Errorf("invalid amount %q (want a positive number of USD)", args[1])
+	}
+	period, err := budget.ParsePeriod(budgetExtendPeriod)
+	if err != nil {
+		return err
+	}
+	limit := budget.Limit(loadRigBudget(townRoot, rigName), period)
+	if limit <= 0 {
+		return fmt.Errorf("rig %s has no %s budget to extend", rigName, period.Label())
+	}
+
+	now := time.Now()
+	state, err := budget.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	by := detectActor()
+	state.Extend(rigName, period, amount, by, budgetExtendReason, now)
+	if err := budget.Save(townRoot, state, now); err != nil {
+		return fmt.Errorf("saving budget state: %w", err)
+	}
+	_ = events.LogAudit(events.TypeBudgetExtended, by,
+		events.BudgetPayload(rigName, string(period), amount, limit, budgetExtendReason))
+
+	fmt.Printf("%s Extended %s's %s budget by $%.2f (now $%.2f)\n",
+		style.SuccessPrefix, rigName, period.Label(), amount, limit+state.Extended(rigName, period, now))
+	return nil
+}
+
+// runBudgetCheck tells the polecats and mayor of each rig that has newly
+// gone over budget. Spawn blocking needs no action here: it is checked at
+// spawn time.
+func runBudgetCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	now := time.Now()
+	state, err := budget.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	statuses, err := townBudgetStatuses(townRoot, state, now)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, s := range statuses {
+		if !s.Exceeded() || !state.MarkNotified(s.Rig, s.Period, now) {
+			continue
+		}
+		changed = true
+		nudged := wrapUpRigPolecats(townRoot, s)
+		if err := notifyMayorOfBudget(townRoot, s, nudged); err != nil {
+			style.PrintWarning("notifying mayor of %s budget: %v", s.Rig, err)
+		}
+		_ = events.LogFeed(events.TypeBudgetExceeded, "daemon",
+			events.BudgetPayload(s.Rig, string(s.Period), s.SpentUSD, s.Allowed(), ""))
+		fmt.Printf("%s %s is over budget: %s (%d polecat(s) nudged to wrap up)\n", style.WarningPrefix, s.Rig, s, nudged)
+	}
+	if changed {
+		return budget.Save(townRoot, state, now)
+	}
+	return nil
+}
+
+// checkRigBudget returns an error if the rig has reached a spend limit.
+// Called before spawning a polecat.
+func checkRigBudget(townRoot, rigName string) error {
+	cfg := loadRigBudget(townRoot, rigName)
+	if cfg == nil {
+		return nil
+	}
+	state, err := budget.Load(townRoot)
+	if err != nil {
+		return nil // Don't block spawns on unreadable budget state
+	}
+	statuses, err := budget.Evaluate(cfg, state, rigName, time.Now(), func(since time.Time) (float64, error) {
+		return rigSpendSince(townRoot, rigName, since)
+	})
+	if err != nil {
+		return nil
+	}
+	for _, s := range statuses {
+		if s.Exceeded() {
+			return fmt.Errorf("rig %s is over budget: %s\n  Raise it with: gt budget extend %s <usd>", rigName, s, rigName)
+		}
+	}
+	return nil
+}
+
+// loadRigBudget returns the rig's budget config, or nil if it has none.
+func loadRigBudget(townRoot, rigName string) *config.BudgetConfig {
+	settings, err := config.LoadRigSettings(filepath.Join(townRoot, rigName, "settings", "config.json"))
+	if err != nil {
+		return nil
+	}
+	return settings.Budget
+}
+
+// townBudgetStatuses evaluates every rig that has a budget, sorted by rig.
+func townBudgetStatuses(townRoot string, state *budget.State, now time.Time) ([]budget.Status, error) {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return nil, fmt.Errorf("loading rigs: %w", err)
+	}
+	var rigNames []string
+	for name := range rigsConfig.Rigs {
+		rigNames = append(rigNames, name)
+	}
+	sort.Strings(rigNames)
+
+	var statuses []budget.Status
+	for _, rigName := range rigNames {
+		cfg := loadRigBudget(townRoot, rigName)
+		if cfg == nil {
+			continue
+		}
+		rs, err := budget.Evaluate(cfg, state, rigName, now, func(since time.Time) (float64, error) {
+			return rigSpendSince(townRoot, rigName, since)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rigName, err)
+		}
+		statuses = append(statuses, rs...)
+	}
+	return statuses, nil
+}
+
+// rigSpendSince totals a rig's spend since the given time: undigested
+// entries from the cost log, plus daily digests for days already digested.
+func rigSpendSince(townRoot, rigName string, since time.Time) (float64, error) {
+	var total float64
+	digested := make(map[string]bool)
+	today := time.Now().Format("2006-01-02")
+	if since.Format("2006-01-02") < today {
+		days := int(time.Since(since).Hours()/24) + 1
+		digests, err := queryCostDigests(townRoot, days)
+		if err != nil {
+			return 0, err
+		}
+		for _, d := range digests {
+			if d.Date < since.Format("2006-01-02") {
+				continue
+			}
+			digested[d.Date] = true
+			total += d.ByRig[rigName]
+		}
+	}
+
+	f, err := os.Open(getCostsLogPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return total, nil
+		}
+		return 0, fmt.Errorf("reading costs log: %w", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry CostLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Rig != rigName || entry.EndedAt.Before(since) || digested[entry.EndedAt.Format("2006-01-02")] {
+			continue
+		}
+		total += entry.CostUSD
+	}
+	return total, scanner.Err()
+}
+
+// wrapUpRigPolecats nudges the rig's running polecats to wrap up, skipping
+// sessions a human has taken over. Returns how many were nudged.
+func wrapUpRigPolecats(townRoot string, s budget.Status) int {
+	t := tmux.NewTmux()
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return 0
+	}
+	msg := "[from daemon] " + fmt.Sprintf(budgetWrapUpMessage, s.Rig, s)
+	nudged := 0
+	for _, name := range sessions {
+		id, err := session.ParseSessionName(name)
+		if err != nil || id.Role != session.RolePolecat || id.Rig != s.Rig {
+			continue
+		}
+		if takeover.IsTakenOver(townRoot, name) {
+			continue
+		}
+		if err := t.NudgeSession(name, msg); err == nil {
+			nudged++
+		}
+	}
+	return nudged
+}
+
+func notifyMayorOfBudget(townRoot string, s budget.Status, nudged int) error {
+	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
+	defer router.WaitPendingNotifications()
+	return router.Send(&mail.Message{
+		From:     "daemon",
+		To:       "mayor/",
+		Subject:  fmt.Sprintf("Budget exceeded: %s (%s)", s.Rig, s.Period),
+		Priority: mail.PriorityHigh,
+		Body: fmt.Sprintf(`Rig %s has reached its spend budget: %s.
+
+New polecat spawns for the rig are blocked until the period ends.
+%d running polecat(s) were nudged to wrap up.
+
+To let work continue: gt budget extend %s <usd>`, s.Rig, s, nudged, s.Rig),
+		Timestamp: time.Now(),
+	})
+}
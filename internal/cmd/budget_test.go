@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+)
+
+// writeBudgetTown creates a town whose gastown rig has a $10 daily budget,
+// and a cost log with the given gastown spend today.
+func writeBudgetTown(t *testing.T, spent float64) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	townRoot := t.TempDir()
+
+	settings := &config.RigSettings{Type: "rig-settings", Version: 1, Budget: &config.BudgetConfig{DailyUSD: 10}}
+	if err := config.SaveRigSettings(filepath.Join(townRoot, "gastown", "settings", "config.json"), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for _, e := range []CostLogEntry{
+		{SessionID: "gt-Toast", Role: "polecat", Rig: "gastown", CostUSD: spent, EndedAt: time.Now()},
+		{SessionID: "gt-Nux", Role: "polecat", Rig: "other", CostUSD: 99, EndedAt: time.Now()},
+		{SessionID: "gt-Old", Role: "polecat", Rig: "gastown", CostUSD: 99, EndedAt: time.Now().AddDate(0, 0, -1)},
+	} {
+		data, _ := json.Marshal(e)
+		lines = append(lines, string(data))
+	}
+	logPath := getCostsLogPath()
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+func TestRigSpendSince(t *testing.T) {
+	townRoot := writeBudgetTown(t, 4.5)
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	got, err := rigSpendSince(townRoot, "gastown", midnight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 4.5 {
+		t.Errorf("rigSpendSince = %v, want 4.5 (today's gastown entries only)", got)
+	}
+}
+
+func TestCheckRigBudget(t *testing.T) {
+	if err := checkRigBudget(writeBudgetTown(t, 4.5), "gastown"); err != nil {
+		t.Errorf("under budget: %v", err)
+	}
+	townRoot := writeBudgetTown(t, 12)
+	err := checkRigBudget(townRoot, "gastown")
+	if err == nil || !strings.Contains(err.Error(), "gt budget extend gastown") {
+		t.Errorf("over budget err = %v, want a gt budget extend hint", err)
+	}
+	if err := checkRigBudget(townRoot, "other"); err != nil {
+		t.Errorf("rig without budget: %v", err)
+	}
+
+	kept, held := holdOverBudget(townRoot, []capacity.PendingBead{
+		{ID: "ctx-1", TargetRig: "gastown"},
+		{ID: "ctx-2", TargetRig: "other"},
+	})
+	if len(kept) != 1 || kept[0].TargetRig != "other" || held["gastown"] == nil {
+		t.Errorf("holdOverBudget kept %+v held %v, want only other kept", kept, held)
+	}
+}
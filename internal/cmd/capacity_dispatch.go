@@ -8,11 +8,14 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/failure"
+	"github.com/steveyegge/gastown/internal/quota"
 	"github.com/steveyegge/gastown/internal/scheduler/capacity"
 	"github.com/steveyegge/gastown/internal/style"
 )
@@ -85,6 +88,20 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 	}
 	spawnDelay := schedulerCfg.GetSpawnDelay()
 
+	// Quota-aware throttling: hold or slow dispatch to providers the daemon
+	// has seen hitting rate limits, and space out spawns while any are.
+	throttleCfg := schedulerCfg.Throttle
+	var pressure map[string]capacity.ProviderPressure
+	if ps, err := capacity.LoadPressure(townRoot); err == nil {
+		pressure = ps.Providers
+	}
+	if stagger := throttleCfg.GetStagger(); spawnDelay < stagger && providersThrottled(throttleCfg, pressure) {
+		spawnDelay = stagger
+	}
+	providers := &quota.ProviderResolver{TownRoot: townRoot}
+	var throttled capacity.ThrottleReport
+	var overBudget map[string]error
+
 	townBeads := beads.NewWithBeadsDir(townRoot, filepath.Join(townRoot, ".beads"))
 
 	// Clean up invalid/stale contexts before querying for ready beads.
@@ -107,7 +124,15 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 			return cap, nil
 		},
 		QueryPending: func() ([]capacity.PendingBead, error) {
-			return getReadySlingContexts(townRoot)
+			pending, err := getReadySlingContexts(townRoot)
+			if err != nil {
+				return nil, err
+			}
+			pending, throttled = capacity.ThrottlePending(throttleCfg, pending, func(b capacity.PendingBead) string {
+				return beadProvider(providers, b)
+			}, pressure, time.Now())
+			pending, overBudget = holdOverBudget(townRoot, pending)
+			return pending, nil
 		},
 		Execute: func(b capacity.PendingBead) error {
 			result, err := dispatchSingleBead(b, townRoot, actor)
@@ -153,7 +178,7 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 				_ = events.LogFeed(events.TypeSchedulerDispatchFailed, actor,
 					events.SchedulerDispatchFailedPayload(b.WorkBeadID, b.TargetRig, err.Error()))
 			}
-			recordDispatchFailure(townBeads, b, err)
+			recordDispatchFailure(townRoot, townBeads, b, err)
 		},
 		BatchSize:  batchSize,
 		SpawnDelay: spawnDelay,
@@ -165,6 +190,8 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 			return 0, fmt.Errorf("planning dispatch: %w", planErr)
 		}
 		printDryRunPlan(plan, maxPolecats, batchSize)
+		printThrottled(throttled, spawnDelay)
+		printOverBudget(overBudget)
 		return 0, nil
 	}
 
@@ -191,6 +218,11 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 		}
 	}
 
+	if !isDaemonDispatch() {
+		printThrottled(throttled, spawnDelay)
+		printOverBudget(overBudget)
+	}
+
 	if report.Dispatched > 0 || report.Failed > 0 {
 		fmt.Printf("\n%s Dispatched %d, failed %d (reason: %s)\n",
 			style.Bold.Render("✓"), report.Dispatched, report.Failed, report.Reason)
@@ -199,6 +231,39 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 	return report.Dispatched, nil
 }
 
+// holdOverBudget removes beads targeting rigs that are over their spend
+// budget, so they wait in the queue instead of failing at spawn. Returns the
+// kept beads and the budget error for each held rig.
+func holdOverBudget(townRoot string, pending []capacity.PendingBead) ([]capacity.PendingBead, map[string]error) {
+	held := make(map[string]error)
+	checked := make(map[string]bool)
+	var kept []capacity.PendingBead
+	for _, b := range pending {
+		if !checked[b.TargetRig] {
+			checked[b.TargetRig] = true
+			if err := checkRigBudget(townRoot, b.TargetRig); err != nil {
+				held[b.TargetRig] = err
+			}
+		}
+		if held[b.TargetRig] == nil {
+			kept = append(kept, b)
+		}
+	}
+	return kept, held
+}
+
+// printOverBudget lists rigs whose dispatches are held by their budget.
+func printOverBudget(held map[string]error) {
+	rigNames := make([]string, 0, len(held))
+	for rigName := range held {
+		rigNames = append(rigNames, rigName)
+	}
+	sort.Strings(rigNames)
+	for _, rigName := range rigNames {
+		fmt.Printf("%s Holding dispatches to %s: over budget (gt budget)\n", style.Dim.Render("⏸"), rigName)
+	}
+}
+
 // printDryRunPlan displays a dry-run dispatch plan.
 func printDryRunPlan(plan capacity.DispatchPlan, maxPolecats, batchSize int) {
 	if plan.Reason == "none" {
@@ -229,6 +294,47 @@ func printDryRunPlan(plan capacity.DispatchPlan, maxPolecats, batchSize int) {
 	}
 }
 
+// beadProvider returns the agent provider a scheduled bead would run on.
+func beadProvider(r *quota.ProviderResolver, b capacity.PendingBead) string {
+	agent := ""
+	if b.Context != nil {
+		agent = b.Context.Agent
+	}
+	return r.Provider(agent, "polecat", b.TargetRig)
+}
+
+// providersThrottled reports whether any provider is currently throttled.
+func providersThrottled(cfg *capacity.ThrottleConfig, pressure map[string]capacity.ProviderPressure) bool {
+	now := time.Now()
+	for _, p := range pressure {
+		if cfg.Level(p, now) != capacity.ThrottleNone {
+			return true
+		}
+	}
+	return false
+}
+
+// printThrottled reports beads held back by provider throttling.
+func printThrottled(r capacity.ThrottleReport, spawnDelay time.Duration) {
+	if len(r.Levels) == 0 {
+		return
+	}
+	names := make([]string, 0, len(r.Levels))
+	for name := range r.Levels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%s Provider %s under rate-limit pressure: %s\n", style.Warning.Render("⚠"), name, r.Levels[name])
+	}
+	if r.Held > 0 {
+		fmt.Printf("  Held %d bead(s) until pressure clears (see: gt quota pressure)\n", r.Held)
+	}
+	if r.Stagger {
+		fmt.Printf("  Spawns staggered %s apart\n", spawnDelay)
+	}
+}
+
 // cleanupStaleContexts closes invalid and stale sling context beads.
 // Called explicitly before the dispatch cycle to separate cleanup from querying.
 func cleanupStaleContexts(townRoot string) {
@@ -447,20 +553,42 @@ func isDaemonDispatch() bool {
 	return os.Getenv("GT_DAEMON") == "1"
 }
 
-// recordDispatchFailure increments the dispatch failure counter on the sling context bead.
-func recordDispatchFailure(townBeads *beads.Beads, b capacity.PendingBead, dispatchErr error) {
+// recordDispatchFailure classifies a dispatch failure (see internal/failure),
+// records it on the sling context bead, and circuit-breaks the context when
+// retrying can't help: after maxDispatchFailures failures, or at once when
+// the environment needs fixing (agent binary missing, login required).
+// Rate-limit failures don't count toward the limit; they clear on their own.
+func recordDispatchFailure(townRoot string, townBeads *beads.Beads, b capacity.PendingBead, dispatchErr error) {
 	if b.Context == nil {
 		return
 	}
 
-	b.Context.DispatchFailures++
+	rec := failure.ClassifyError(dispatchErr)
+	rec.Agent = b.TargetRig + "/polecats"
+	rec.Issue = b.WorkBeadID
+	_ = failure.Append(townRoot, rec)
+
+	if rec.Recovery != failure.RecoverWait {
+		b.Context.DispatchFailures++
+	}
 	b.Context.LastFailure = dispatchErr.Error()
+	b.Context.FailureClass = string(rec.Class)
 
 	if err := townBeads.UpdateSlingContextFields(b.ID, b.Context); err != nil {
 		fmt.Printf("  %s Failed to record dispatch failure for %s: %v\n",
 			style.Warning.Render("⚠"), b.ID, err)
 	}
 
+	if rec.Recovery == failure.RecoverFixEnvironment {
+		if err := townBeads.CloseSlingContext(b.ID, "fix-environment"); err != nil {
+			fmt.Printf("  %s Failed to close context %s: %v\n",
+				style.Warning.Render("⚠"), b.ID, err)
+		}
+		fmt.Printf("  %s Context %s (work: %s) can't dispatch until the environment is fixed: %s\n",
+			style.Warning.Render("⚠"), b.ID, b.WorkBeadID, rec.Reason())
+		return
+	}
+
 	if b.Context.DispatchFailures >= maxDispatchFailures {
 		if err := townBeads.CloseSlingContext(b.ID, "circuit-broken"); err != nil {
 			fmt.Printf("  %s Failed to close circuit-broken context %s: %v\n",
@@ -11,7 +11,7 @@ import (
 )
 
 // DefaultAgentEmailDomain is the default domain for agent git emails.
-const DefaultAgentEmailDomain = "gastown.local"
+const DefaultAgentEmailDomain = config.DefaultAgentEmailDomain
 
 var commitCmd = &cobra.Command{
 	Use:   "commit [flags] [-- git-commit-args...]",
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/townspec"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var configDiffExitCode bool
+
+var configDiffCmd = &cobra.Command{
+	Use:   "diff [spec-dir]",
+	Short: "Show drift between the declared town spec and live state",
+	Long: `Compare the town with its declarative spec (see 'gt apply') and list every
+difference: declared rigs and crew that are missing, rigs and crew added by
+hand, and settings or patrol intervals changed since the spec was applied.
+
+Without an argument, the spec last applied with 'gt apply' is used.
+
+The daemon's config_drift patrol runs the same comparison periodically and
+mails the mayor when the drift changes.
+
+Examples:
+  gt config diff
+  gt config diff town/
+  gt config diff --exit-code   # Exit 1 when the town has drifted (for CI)`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigDiff,
+}
+
+func init() {
+	configDiffCmd.Flags().BoolVar(&configDiffExitCode, "exit-code", false, "Exit with status 1 when there is drift")
+	configCmd.AddCommand(configDiffCmd)
+}
+
+func runConfigDiff(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	dir, err := configSpecDir(townRoot, args)
+	if err != nil {
+		return err
+	}
+	spec, err := townspec.Load(dir)
+	if err != nil {
+		return fmt.Errorf("loading spec: %w", err)
+	}
+	plan, err := townspec.Diff(townRoot, spec)
+	if err != nil {
+		return err
+	}
+
+	if !plan.Drifted() {
+		fmt.Printf("%s Town matches %s\n", style.Success.Render("✓"), dir)
+		return nil
+	}
+	fmt.Printf("%s\n", style.Bold.Render("Drift from "+dir+":"))
+	for _, line := range plan.Drift() {
+		fmt.Printf("  %s\n", line)
+	}
+	if !plan.Empty() {
+		fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("Reconcile with: gt apply %s", dir)))
+	}
+	if configDiffExitCode {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// configSpecDir returns the spec directory from args, or the one recorded
+// by the last gt apply.
+func configSpecDir(townRoot string, args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return "", fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.SpecDir == "" {
+		return "", fmt.Errorf("no town spec has been applied; pass the spec directory or run 'gt apply <dir>' first")
+	}
+	return settings.SpecDir, nil
+}
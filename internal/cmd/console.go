@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+const (
+	consoleHistoryFile   = "console_history"
+	consoleHistoryMax    = 1000
+	consoleWatchInterval = 2 * time.Second
+)
+
+// consoleWatches are shorthand watch expressions and the gt commands they
+// run. Anything else after "watch" is run as a gt command line.
+var consoleWatches = map[string][]string{
+	"sessions":    {"session", "list"},
+	"polecats":    {"polecat", "list", "--all"},
+	"convoys":     {"convoy", "list"},
+	"escalations": {"escalate", "list"},
+	"status":      {"status"},
+}
+
+// consoleBuiltins are console commands that are not gt commands.
+var consoleBuiltins = []string{"help", "watch", "history", "clear", "exit", "quit"}
+
+var consoleCmd = &cobra.Command{
+	Use:     "console",
+	GroupID: GroupDiag,
+	Short:   "Interactive gt shell with history, completion, and watches",
+	Long: `Start an interactive shell for running gt commands.
+
+Type gt commands without the "gt" prefix. The console adds:
+  - Command history (up/down arrows), saved across sessions
+  - Tab completion of commands, subcommands, flags, and live resources
+    (rigs, polecats, crew, and other agent addresses)
+  - Watch expressions, which re-run a command until Ctrl-C
+
+Builtins:
+  watch [-n SECONDS] <expr>   Re-run a command every few seconds (default 2)
+  history                     Show command history
+  clear                       Clear the screen
+  help                        Show this help
+  exit, quit, Ctrl-D          Leave the console
+
+Watch shorthands: sessions, polecats, convoys, escalations, status.
+Any other watch expression is run as a gt command line.
+
+Examples:
+  gt console
+  gt> status
+  gt> watch sessions
+  gt> watch -n 10 mq list gastown
+  gt> nudge gastown/Toast "check your mail"`,
+	Args: cobra.NoArgs,
+	RunE: runConsole,
+}
+
+func init() {
+	rootCmd.AddCommand(consoleCmd)
+}
+
+func runConsole(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("gt console needs an interactive terminal")
+	}
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating gt binary: %w", err)
+	}
+
+	historyPath := filepath.Join(townRoot, constants.DirRuntime, consoleHistoryFile)
+	history := loadConsoleHistory(historyPath)
+	resources := &consoleResources{townRoot: townRoot}
+	completer := &consoleCompleter{root: rootCmd, builtins: consoleBuiltins, resources: resources.list}
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, consolePrompt())
+	t.History = history
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		newLine, newPos, candidates, ok := completer.complete(line, pos)
+		if len(candidates) > 0 {
+			fmt.Fprintln(t, strings.Join(candidates, "  "))
+		}
+		return newLine, newPos, ok
+	}
+
+	fmt.Printf("Gas Town console (%s). Tab completes, \"help\" for builtins, Ctrl-D to leave.\n", townRoot)
+	for {
+		line, err := readConsoleLine(fd, t)
+		if err == io.EOF {
+			fmt.Println()
+			break
+		}
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		words, err := splitConsoleLine(line)
+		if err != nil {
+			fmt.Printf("%s %v\n", style.ErrorPrefix, err)
+			continue
+		}
+		if words[0] == "gt" { // forgive habit
+			words = words[1:]
+			if len(words) == 0 {
+				continue
+			}
+		}
+
+		switch words[0] {
+		case "exit", "quit":
+			return saveConsoleHistory(historyPath, history)
+		case "help":
+			fmt.Print(cmd.Long + "\n\n")
+		case "history":
+			for i := history.Len() - 1; i >= 0; i-- {
+				fmt.Printf("%5d  %s\n", history.Len()-i, history.At(i))
+			}
+		case "clear":
+			fmt.Print("\x1b[H\x1b[2J")
+		case "watch":
+			gtArgs, interval, err := parseConsoleWatch(words[1:])
+			if err != nil {
+				fmt.Printf("%s %v\n", style.ErrorPrefix, err)
+				continue
+			}
+			runConsoleWatch(gtPath, gtArgs, interval)
+		default:
+			if err := runConsoleCommand(gtPath, words); err != nil {
+				fmt.Printf("%s %v\n", style.ErrorPrefix, err)
+			}
+		}
+		if err := saveConsoleHistory(historyPath, history); err != nil {
+			fmt.Printf("%s saving history: %v\n", style.WarningPrefix, err)
+		}
+	}
+	return saveConsoleHistory(historyPath, history)
+}
+
+func consolePrompt() string {
+	return style.Bold.Render("gt>") + " "
+}
+
+// readConsoleLine reads one line with the terminal in raw mode, restoring
+// it before returning so commands run with a normal terminal.
+func readConsoleLine(fd int, t *term.Terminal) (string, error) {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", fmt.Errorf("setting terminal mode: %w", err)
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+	if w, h, err := term.GetSize(fd); err == nil {
+		_ = t.SetSize(w, h)
+	}
+	return t.ReadLine()
+}
+
+// runConsoleCommand runs gt with args attached to the terminal. Ctrl-C
+// interrupts the command, not the console.
+func runConsoleCommand(gtPath string, args []string) error {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	defer signal.Stop(sigs)
+
+	c := exec.Command(gtPath, args...) //nolint:gosec // G204: the user typed this command
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil // the command already reported its own failure
+	}
+	return err
+}
+
+// parseConsoleWatch parses "[-n SECONDS] <expr>" into gt args and an interval.
+func parseConsoleWatch(words []string) ([]string, time.Duration, error) {
+	interval := consoleWatchInterval
+	if len(words) >= 2 && (words[0] == "-n" || words[0] == "--interval") {
+		secs, err := strconv.ParseFloat(words[1], 64)
+		if err != nil || secs < 0.5 {
+			return nil, 0, fmt.Errorf("invalid interval %q: want seconds, at least 0.5", words[1])
+		}
+		interval = time.Duration(secs * float64(time.Second))
+		words = words[2:]
+	}
+	if len(words) == 0 {
+		return nil, 0, fmt.Errorf("usage: watch [-n SECONDS] <expr>  (shorthands: %s)", strings.Join(consoleWatchNames(), ", "))
+	}
+	if len(words) == 1 {
+		if gtArgs, ok := consoleWatches[words[0]]; ok {
+			return gtArgs, interval, nil
+		}
+	}
+	if words[0] == "watch" {
+		return nil, 0, errors.New("cannot watch a watch")
+	}
+	return words, interval, nil
+}
+
+// consoleWatchNames returns the watch shorthands, sorted.
+func consoleWatchNames() []string {
+	names := make([]string, 0, len(consoleWatches))
+	for name := range consoleWatches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runConsoleWatch re-runs gt with args every interval until Ctrl-C.
+func runConsoleWatch(gtPath string, args []string, interval time.Duration) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	defer signal.Stop(sigs)
+
+	header := fmt.Sprintf("Every %s: gt %s", interval, strings.Join(args, " "))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		out, _ := exec.Command(gtPath, args...).CombinedOutput() //nolint:gosec // G204: the user typed this command
+		fmt.Print("\x1b[H\x1b[2J")
+		fmt.Printf("%s    %s\n\n", style.Bold.Render(header), style.Dim.Render(time.Now().Format("15:04:05")+"  (Ctrl-C to stop)"))
+		_, _ = os.Stdout.Write(out)
+
+		select {
+		case <-sigs:
+			fmt.Println()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// consoleHistory is a term.History that can be saved to and loaded from a
+// file. Entries are oldest first; At(0) is the newest.
+type consoleHistory struct {
+	entries []string
+	max     int
+}
+
+func (h *consoleHistory) Add(entry string) {
+	if entry == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > h.max {
+		h.entries = h.entries[len(h.entries)-h.max:]
+	}
+}
+
+func (h *consoleHistory) Len() int { return len(h.entries) }
+
+func (h *consoleHistory) At(idx int) string { return h.entries[len(h.entries)-1-idx] }
+
+// loadConsoleHistory reads history from path, one entry per line. A missing
+// or unreadable file starts an empty history.
+func loadConsoleHistory(path string) *consoleHistory {
+	h := &consoleHistory{max: consoleHistoryMax}
+	f, err := os.Open(path)
+	if err != nil {
+		return h
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		h.Add(scanner.Text())
+	}
+	return h
+}
+
+func saveConsoleHistory(path string, h *consoleHistory) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data := strings.Join(h.entries, "\n")
+	if data != "" {
+		data += "\n"
+	}
+	return os.WriteFile(path, []byte(data), 0600)
+}
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// consoleResourceTTL is how long completion candidates for live resources
+// (rigs, agents) are cached before being re-read.
+const consoleResourceTTL = 30 * time.Second
+
+// splitConsoleLine splits a console line into words. Single and double
+// quotes group words and a backslash escapes the next character, as in a
+// shell; nothing else (variables, globs, pipes) is interpreted.
+func splitConsoleLine(line string) ([]string, error) {
+	var (
+		words   []string
+		cur     strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
+
+// consoleCompleter completes console input: builtins and gt commands, then
+// subcommands and flags from the cobra tree, then live resources.
+type consoleCompleter struct {
+	root      *cobra.Command
+	builtins  []string
+	resources func() []string
+}
+
+// complete completes the word ending at pos. It returns the new line and
+// cursor position, and the candidates when the word is ambiguous (so the
+// caller can list them). ok is false when there is nothing to complete.
+func (c *consoleCompleter) complete(line string, pos int) (newLine string, newPos int, candidates []string, ok bool) {
+	before := line[:pos]
+	start := strings.LastIndexAny(before, " \t") + 1
+	word := before[start:]
+	words := strings.Fields(before[:start])
+
+	matches := filterPrefix(c.candidates(words, word), word)
+	switch len(matches) {
+	case 0:
+		return line, pos, nil, false
+	case 1:
+		completion := matches[0]
+		if !strings.HasSuffix(completion, "/") && !strings.HasSuffix(completion, "=") {
+			completion += " "
+		}
+		return line[:start] + completion + line[pos:], start + len(completion), nil, true
+	}
+	prefix := commonPrefix(matches)
+	if len(prefix) > len(word) {
+		return line[:start] + prefix + line[pos:], start + len(prefix), nil, true
+	}
+	return line, pos, matches, true
+}
+
+// candidates returns every completion for the word following words.
+func (c *consoleCompleter) candidates(words []string, word string) []string {
+	if len(words) == 0 {
+		out := append([]string(nil), c.builtins...)
+		for _, sub := range c.root.Commands() {
+			if sub.IsAvailableCommand() {
+				out = append(out, sub.Name())
+			}
+		}
+		return out
+	}
+
+	args := words
+	if words[0] == "watch" {
+		args = words[1:]
+		if len(args) == 0 {
+			out := append([]string(nil), consoleWatchNames()...)
+			return append(out, c.candidates(nil, word)...)
+		}
+	}
+	cmd := c.root
+	for _, w := range args {
+		if strings.HasPrefix(w, "-") {
+			continue
+		}
+		next := findSubcommand(cmd, w)
+		if next == nil {
+			break
+		}
+		cmd = next
+	}
+
+	if strings.HasPrefix(word, "-") {
+		var flags []string
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if !f.Hidden {
+				flags = append(flags, "--"+f.Name)
+			}
+		})
+		return flags
+	}
+
+	var out []string
+	for _, sub := range cmd.Commands() {
+		if sub.IsAvailableCommand() {
+			out = append(out, sub.Name())
+		}
+	}
+	if c.resources != nil {
+		out = append(out, c.resources()...)
+	}
+	return out
+}
+
+// findSubcommand returns cmd's subcommand named name (or aliased), or nil.
+func findSubcommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, sub := range cmd.Commands() {
+		if sub.Name() == name || sub.HasAlias(name) {
+			return sub
+		}
+	}
+	return nil
+}
+
+// filterPrefix returns the sorted, deduplicated candidates that start with prefix.
+func filterPrefix(candidates []string, prefix string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) && !seen[c] {
+			seen[c] = true
+			out = append(out, c)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// commonPrefix returns the longest prefix shared by all of ss.
+func commonPrefix(ss []string) string {
+	if len(ss) == 0 {
+		return ""
+	}
+	prefix := ss[0]
+	for _, s := range ss[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// consoleResources lists completable live resources in a town: rigs, agent
+// addresses (rig/polecat, rig/crew/name, rig/witness, rig/refinery), and
+// town roles. Results are cached for consoleResourceTTL.
+type consoleResources struct {
+	townRoot string
+
+	mu      sync.Mutex
+	cached  []string
+	fetched time.Time
+}
+
+func (r *consoleResources) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cached != nil && time.Since(r.fetched) < consoleResourceTTL {
+		return r.cached
+	}
+
+	out := []string{"mayor", "deacon"}
+	for _, rigName := range discoverRigs(r.townRoot) {
+		out = append(out, rigName, rigName+"/witness", rigName+"/refinery")
+		for _, name := range listSubdirs(filepath.Join(r.townRoot, rigName, "polecats")) {
+			out = append(out, rigName+"/"+name)
+		}
+		for _, name := range listSubdirs(filepath.Join(r.townRoot, rigName, "crew")) {
+			out = append(out, rigName+"/crew/"+name)
+		}
+	}
+	r.cached, r.fetched = out, time.Now()
+	return out
+}
+
+// listSubdirs returns the names of dir's visible subdirectories.
+func listSubdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSplitConsoleLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{"status", []string{"status"}, false},
+		{"  mq   list gastown ", []string{"mq", "list", "gastown"}, false},
+		{`nudge gastown/Toast "check your mail"`, []string{"nudge", "gastown/Toast", "check your mail"}, false},
+		{`mail send -s 'it''s' x`, []string{"mail", "send", "-s", "its", "x"}, false},
+		{`echo a\ b`, []string{"echo", "a b"}, false},
+		{`say "a \"quoted\" word"`, []string{"say", `a "quoted" word`}, false},
+		{`empty ""`, []string{"empty", ""}, false},
+		{`nudge "unterminated`, nil, true},
+		{`trailing \`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := splitConsoleLine(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitConsoleLine(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitConsoleLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func testConsoleCompleter() *consoleCompleter {
+	root := &cobra.Command{Use: "gt"}
+	mq := &cobra.Command{Use: "mq", Run: func(*cobra.Command, []string) {}}
+	mqList := &cobra.Command{Use: "list <rig>", Run: func(*cobra.Command, []string) {}}
+	mqList.Flags().Bool("ready", false, "")
+	mqList.Flags().Bool("json", false, "")
+	mq.AddCommand(mqList, &cobra.Command{Use: "reject", Run: func(*cobra.Command, []string) {}})
+	root.AddCommand(mq,
+		&cobra.Command{Use: "mail", Run: func(*cobra.Command, []string) {}},
+		&cobra.Command{Use: "status", Run: func(*cobra.Command, []string) {}},
+		&cobra.Command{Use: "secret", Hidden: true, Run: func(*cobra.Command, []string) {}},
+	)
+	return &consoleCompleter{
+		root:     root,
+		builtins: []string{"watch", "exit"},
+		resources: func() []string {
+			return []string{"gastown", "gastown/Toast", "gastown/Nux", "beads"}
+		},
+	}
+}
+
+func TestConsoleCompleterComplete(t *testing.T) {
+	c := testConsoleCompleter()
+	tests := []struct {
+		line           string
+		wantLine       string
+		wantCandidates []string
+		wantOK         bool
+	}{
+		{"st", "status ", nil, true},
+		{"m", "m", []string{"mail", "mq"}, true},
+		{"se", "se", nil, false}, // hidden commands are not offered
+		{"mq l", "mq list ", nil, true},
+		{"mq list gas", "mq list gastown", nil, true},
+		{"mq list gastown/T", "mq list gastown/Toast ", nil, true},
+		{"mq list --r", "mq list --ready ", nil, true},
+		{"mq list --", "mq list --", []string{"--json", "--ready"}, true},
+		{"watch ses", "watch sessions ", nil, true},
+		{"watch x", "watch x", nil, false},
+		{"watch stat", "watch status ", nil, true},
+		{"watch mq re", "watch mq reject ", nil, true},
+		{"nudge gastown/N", "nudge gastown/Nux ", nil, true},
+	}
+	for _, tt := range tests {
+		line, pos, candidates, ok := c.complete(tt.line, len(tt.line))
+		if ok != tt.wantOK || line != tt.wantLine || pos != len(tt.wantLine) || !reflect.DeepEqual(candidates, tt.wantCandidates) {
+			t.Errorf("complete(%q) = (%q, %d, %v, %v), want (%q, %d, %v, %v)",
+				tt.line, line, pos, candidates, ok, tt.wantLine, len(tt.wantLine), tt.wantCandidates, tt.wantOK)
+		}
+	}
+}
+
+func TestConsoleCompleterMidLine(t *testing.T) {
+	c := testConsoleCompleter()
+	line, pos, _, ok := c.complete("mq li gastown", 5)
+	if !ok || line != "mq list  gastown" || pos != 8 {
+		t.Errorf("complete mid-line = (%q, %d, %v)", line, pos, ok)
+	}
+}
+
+func TestParseConsoleWatch(t *testing.T) {
+	args, interval, err := parseConsoleWatch([]string{"sessions"})
+	if err != nil || !reflect.DeepEqual(args, []string{"session", "list"}) || interval != consoleWatchInterval {
+		t.Errorf("watch sessions = %v, %v, %v", args, interval, err)
+	}
+
+	args, interval, err = parseConsoleWatch([]string{"-n", "10", "mq", "list", "gastown"})
+	if err != nil || !reflect.DeepEqual(args, []string{"mq", "list", "gastown"}) || interval != 10*time.Second {
+		t.Errorf("watch -n 10 mq list gastown = %v, %v, %v", args, interval, err)
+	}
+
+	for _, words := range [][]string{nil, {"-n", "0.1", "status"}, {"-n", "x", "status"}, {"watch", "status"}} {
+		if _, _, err := parseConsoleWatch(words); err == nil {
+			t.Errorf("parseConsoleWatch(%q) should fail", words)
+		}
+	}
+}
+
+func TestConsoleHistory(t *testing.T) {
+	h := &consoleHistory{max: 3}
+	for _, e := range []string{"a", "b", "b", "", "c", "d"} {
+		h.Add(e)
+	}
+	if h.Len() != 3 || h.At(0) != "d" || h.At(2) != "b" {
+		t.Fatalf("history = %v", h.entries)
+	}
+
+	path := filepath.Join(t.TempDir(), ".runtime", consoleHistoryFile)
+	if err := saveConsoleHistory(path, h); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded := loadConsoleHistory(path)
+	if !reflect.DeepEqual(loaded.entries, h.entries) {
+		t.Errorf("loaded %v, want %v", loaded.entries, h.entries)
+	}
+
+	if missing := loadConsoleHistory(filepath.Join(t.TempDir(), "none")); missing.Len() != 0 {
+		t.Errorf("missing history file should load empty, got %v", missing.entries)
+	}
+}
@@ -564,6 +564,37 @@ func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
 
 // queryDigestBeads queries costs.digest events from the past N days and extracts session entries.
 func queryDigestBeads(days int) ([]CostEntry, error) {
+	digests, err := queryCostDigests("", days)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []CostEntry
+	for _, digest := range digests {
+		digestDate, _ := time.Parse("2006-01-02", digest.Date)
+
+		// If the digest has per-session data (old format), use it directly.
+		// Otherwise, synthesize entries from the aggregate ByRole data.
+		if len(digest.Sessions) > 0 {
+			entries = append(entries, digest.Sessions...)
+		} else {
+			for role, cost := range digest.ByRole {
+				entries = append(entries, CostEntry{
+					SessionID: fmt.Sprintf("digest-%s-%s", digest.Date, role),
+					Role:      role,
+					CostUSD:   cost,
+					EndedAt:   digestDate,
+				})
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// queryCostDigests returns the costs.digest events from the past N days.
+// bd runs in dir ("" for the current directory).
+func queryCostDigests(dir string, days int) ([]CostDigest, error) {
 	// Get list of event IDs
 	listArgs := []string{
 		"list",
@@ -574,6 +605,7 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	}
 
 	listCmd := exec.Command("bd", listArgs...)
+	listCmd.Dir = dir
 	listOutput, err := listCmd.Output()
 	if err != nil {
 		return nil, nil
@@ -595,6 +627,7 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	}
 
 	showCmd := exec.Command("bd", showArgs...)
+	showCmd.Dir = dir
 	showOutput, err := showCmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("showing events: %w", err)
@@ -609,7 +642,7 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	now := time.Now()
 	cutoff := now.AddDate(0, 0, -days)
 
-	var entries []CostEntry
+	var digests []CostDigest
 	for _, event := range events {
 		// Filter for costs.digest events only
 		if event.EventKind != "costs.digest" {
@@ -632,24 +665,10 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 		if digestDate.Before(cutoff) {
 			continue
 		}
-
-		// If the digest has per-session data (old format), use it directly.
-		// Otherwise, synthesize entries from the aggregate ByRole data.
-		if len(digest.Sessions) > 0 {
-			entries = append(entries, digest.Sessions...)
-		} else {
-			for role, cost := range digest.ByRole {
-				entries = append(entries, CostEntry{
-					SessionID: fmt.Sprintf("digest-%s-%s", digest.Date, role),
-					Role:      role,
-					CostUSD:   cost,
-					EndedAt:   digestDate,
-				})
-			}
-		}
+		digests = append(digests, digest)
 	}
 
-	return entries, nil
+	return digests, nil
 }
 
 // parseSessionName extracts role, rig, and worker from a session name.
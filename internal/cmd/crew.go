@@ -24,6 +24,8 @@ var (
 	crewDebug         bool
 	crewReset         bool
 	crewResume        string
+	crewPrompt        string
+	crewPromptClear   bool
 )
 
 var crewCmd = &cobra.Command{
@@ -55,7 +57,11 @@ Commands:
   gt crew at <name>        Attach to session
   gt crew remove <name>    Remove workspace
   gt crew refresh <name>   Context cycle with handoff mail
-  gt crew restart <name>   Kill and restart session fresh`,
+  gt crew restart <name>   Kill and restart session fresh
+  gt crew prompt <name>    Show or set the custom startup prompt
+
+Crew sessions are never reaped by the witness: stalled or idle crew are
+left for their human to manage, unlike polecats.`,
 }
 
 var crewAddCmd = &cobra.Command{
@@ -242,6 +248,21 @@ Examples:
 	RunE: runCrewRename,
 }
 
+var crewPromptCmd = &cobra.Command{
+	Use:   "prompt <name> [text...]",
+	Short: "Show or set a crew worker's custom startup prompt",
+	Long: `Show or set extra instructions sent to a crew worker each time its
+session starts fresh. The prompt follows the standard startup beacon and
+is not sent when resuming a previous session.
+
+Examples:
+  gt crew prompt dave                                 # Show current prompt
+  gt crew prompt dave "Own the docs; review PRs first"
+  gt crew prompt dave --clear`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCrewPrompt,
+}
+
 var crewPristineCmd = &cobra.Command{
 	Use:   "pristine [<name>]",
 	Short: "Sync crew workspaces with remote",
@@ -355,6 +376,7 @@ func init() {
 	// Add flags
 	crewAddCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to create crew workspace in")
 	crewAddCmd.Flags().BoolVar(&crewBranch, "branch", false, "Create a feature branch (crew/<name>)")
+	crewAddCmd.Flags().StringVar(&crewPrompt, "prompt", "", "Custom startup prompt sent on each fresh start")
 
 	crewListCmd.Flags().StringVar(&crewRig, "rig", "", "Filter by rig name")
 	crewListCmd.Flags().BoolVar(&crewListAll, "all", false, "List crew workspaces in all rigs")
@@ -380,6 +402,9 @@ func init() {
 
 	crewRenameCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to use")
 
+	crewPromptCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to use")
+	crewPromptCmd.Flags().BoolVar(&crewPromptClear, "clear", false, "Remove the custom startup prompt")
+
 	crewPristineCmd.Flags().StringVar(&crewRig, "rig", "", "Filter by rig name")
 	crewPristineCmd.Flags().BoolVar(&crewJSON, "json", false, "Output as JSON")
 
@@ -406,6 +431,7 @@ func init() {
 	crewCmd.AddCommand(crewRefreshCmd)
 	crewCmd.AddCommand(crewStatusCmd)
 	crewCmd.AddCommand(crewRenameCmd)
+	crewCmd.AddCommand(crewPromptCmd)
 	crewCmd.AddCommand(crewPristineCmd)
 	crewCmd.AddCommand(crewRestartCmd)
 
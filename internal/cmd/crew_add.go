@@ -109,6 +109,13 @@ func runCrewAdd(cmd *cobra.Command, args []string) error {
 			style.Bold.Render("✓"), rigName, name)
 		fmt.Printf("  Path: %s\n", worker.ClonePath)
 		fmt.Printf("  Branch: %s\n", worker.Branch)
+		if crewPrompt != "" {
+			if err := crewMgr.SetStartupPrompt(name, crewPrompt); err != nil {
+				style.PrintWarning("could not save startup prompt for %s: %v", name, err)
+			} else {
+				fmt.Printf("  Startup prompt: set\n")
+			}
+		}
 
 		// Create agent bead for the crew worker
 		prefix := beads.GetPrefixForRig(townRoot, rigName)
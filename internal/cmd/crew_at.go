@@ -9,6 +9,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/instructions"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -131,6 +132,10 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 		// Non-fatal but log warning - missing settings can cause agents to start without hooks
 		style.PrintWarning("could not ensure settings for %s: %v", name, err)
 	}
+	agent := instructions.Agent{TownRoot: townRoot, Rig: r.Name, Role: "crew", Name: name, WorkDir: worker.ClonePath}
+	if _, err := instructions.Generate(agent, instructions.FileFor(runtimeConfig)); err != nil {
+		style.PrintWarning("could not generate instructions for %s: %v", name, err)
+	}
 
 	// Check if session exists
 	t := tmux.NewTmux()
@@ -202,7 +207,15 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 		// Apply rig-based theming (non-fatal: theming failure doesn't affect operation)
 		// Note: ConfigureGasTownSession includes cycle bindings
 		theme := getThemeForRig(r.Name)
-		_ = t.ConfigureGasTownSession(sessionID, theme, r.Name, name, "crew")
+		_ = session.ConfigureSession(t, session.SessionConfig{
+			SessionID: sessionID,
+			WorkDir:   worker.ClonePath,
+			Role:      "crew",
+			RigPath:   r.Path,
+			RigName:   r.Name,
+			AgentName: name,
+			Theme:     &theme,
+		})
 
 		// Wait for shell to be ready after session creation
 		if err := t.WaitForShellReady(sessionID, constants.ShellReadyTimeout); err != nil {
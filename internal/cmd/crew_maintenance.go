@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/crew"
@@ -130,3 +131,48 @@ func runCrewPristine(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runCrewPrompt(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if rig, crewName, ok := parseRigSlashName(name); ok {
+		if crewRig == "" {
+			crewRig = rig
+		}
+		name = crewName
+	}
+
+	crewMgr, r, err := getCrewManager(crewRig)
+	if err != nil {
+		return err
+	}
+
+	text := strings.Join(args[1:], " ")
+	if text == "" && !crewPromptClear {
+		worker, err := crewMgr.Get(name)
+		if err != nil {
+			return fmt.Errorf("crew workspace '%s' not found in rig '%s'", name, r.Name)
+		}
+		if worker.StartupPrompt == "" {
+			fmt.Printf("No custom startup prompt for %s/%s\n", r.Name, name)
+			return nil
+		}
+		fmt.Println(worker.StartupPrompt)
+		return nil
+	}
+	if text != "" && crewPromptClear {
+		return fmt.Errorf("cannot use --clear with prompt text")
+	}
+
+	if err := crewMgr.SetStartupPrompt(name, text); err != nil {
+		if errors.Is(err, crew.ErrCrewNotFound) {
+			return fmt.Errorf("crew workspace '%s' not found in rig '%s'", name, r.Name)
+		}
+		return fmt.Errorf("saving startup prompt: %w", err)
+	}
+	if crewPromptClear {
+		fmt.Printf("%s Cleared startup prompt for %s/%s\n", style.Bold.Render("✓"), r.Name, name)
+	} else {
+		fmt.Printf("%s Set startup prompt for %s/%s (applies on next fresh start)\n", style.Bold.Render("✓"), r.Name, name)
+	}
+	return nil
+}
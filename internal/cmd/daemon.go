@@ -6,10 +6,12 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/templates"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -26,6 +28,8 @@ The daemon is a simple Go process that:
 - Pokes agents periodically (heartbeat)
 - Processes lifecycle requests (cycle, restart, shutdown)
 - Restarts sessions when agents request cycling
+- Runs maintenance patrols on their own tickers ('gt daemon run-patrol'
+  runs one now)
 
 The daemon is a "dumb scheduler" - all intelligence is in agents.`,
 }
@@ -35,7 +39,16 @@ var daemonStartCmd = &cobra.Command{
 	Short: "Start the daemon",
 	Long: `Start the Gas Town daemon in the background.
 
-The daemon will run until stopped with 'gt daemon stop'.`,
+The daemon will run until stopped with 'gt daemon stop'.
+
+--dry-run puts mutating patrols in dry-run: they log what they would do
+(to the daemon log and as patrol_dry_run events in gt feed) without doing
+it. Patrols: zombie_recovery, idle_reaper, wisp_reaper, upstream_sync, or
+all. The same list can be set as "dry_run" in mayor/daemon.json.
+
+Examples:
+  gt daemon start
+  gt daemon start --dry-run zombie_recovery,idle_reaper`,
 	RunE: runDaemonStart,
 }
 
@@ -92,20 +105,44 @@ normally in the background.`,
 	RunE:   runDaemonRun,
 }
 
-var daemonEnableSupervisorCmd = &cobra.Command{
-	Use:   "enable-supervisor",
-	Short: "Configure launchd/systemd for daemon auto-restart",
-	Long: `Configure external supervision for the Gas Town daemon.
+var daemonInstallCmd = &cobra.Command{
+	Use:     "install",
+	Aliases: []string{"enable-supervisor"},
+	Short:   "Install the daemon as a supervised user service",
+	Long: `Install the Gas Town daemon as a supervised user service.
 
-This command creates and enables a supervisor service (launchd on macOS,
-systemd on Linux) that will automatically restart the daemon if it crashes
-or terminates. The daemon will also start automatically on login/boot.
+Generates and loads a service definition (launchd plist on macOS, systemd
+user unit on Linux) that:
+  - Restarts the daemon if it exits with a failure
+  - Starts the daemon automatically on login/boot
+  - Appends stdout/stderr to <town>/daemon/daemon.log
+  - Sets GT_TOWN_ROOT and captures the current PATH so tmux, dolt, bd and
+    agent CLIs resolve the same way they do in your shell
+
+Re-running install regenerates the service (e.g. after moving the gt binary).
 
 Examples:
-  gt daemon enable-supervisor    # Configure launchd/systemd`,
-	RunE: runDaemonEnableSupervisor,
+  gt daemon install            # Generate and load the service
+  gt daemon install --print    # Show the generated file without installing
+  gt daemon uninstall          # Remove it again`,
+	RunE: runDaemonInstall,
+}
+
+var daemonUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the daemon's supervised user service",
+	Long: `Stop the supervised daemon and remove its launchd plist or systemd unit.
+
+Safe to run when no service is installed. The daemon can still be run
+manually afterwards with 'gt daemon start'.`,
+	RunE: runDaemonUninstall,
 }
 
+var (
+	daemonInstallPrint bool
+	daemonDryRun       []string
+)
+
 var (
 	daemonLogLines  int
 	daemonLogFollow bool
@@ -117,10 +154,14 @@ func init() {
 	daemonCmd.AddCommand(daemonStatusCmd)
 	daemonCmd.AddCommand(daemonLogsCmd)
 	daemonCmd.AddCommand(daemonRunCmd)
-	daemonCmd.AddCommand(daemonEnableSupervisorCmd)
+	daemonCmd.AddCommand(daemonInstallCmd)
+	daemonCmd.AddCommand(daemonUninstallCmd)
 
 	daemonLogsCmd.Flags().IntVarP(&daemonLogLines, "lines", "n", 50, "Number of lines to show")
 	daemonLogsCmd.Flags().BoolVarP(&daemonLogFollow, "follow", "f", false, "Follow log output")
+	daemonStartCmd.Flags().StringSliceVar(&daemonDryRun, "dry-run", nil, "Patrols to run in dry-run (zombie_recovery, idle_reaper, wisp_reaper, upstream_sync, all)")
+	daemonRunCmd.Flags().StringSliceVar(&daemonDryRun, "dry-run", nil, "Patrols to run in dry-run")
+	daemonInstallCmd.Flags().BoolVar(&daemonInstallPrint, "print", false, "Print the generated service file instead of installing it")
 
 	rootCmd.AddCommand(daemonCmd)
 }
@@ -147,7 +188,14 @@ func runDaemonStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding executable: %w", err)
 	}
 
-	daemonCmd := exec.Command(gtPath, "daemon", "run")
+	if err := daemon.ValidateDryRun(daemonDryRun); err != nil {
+		return err
+	}
+	runArgs := []string{"daemon", "run"}
+	if len(daemonDryRun) > 0 {
+		runArgs = append(runArgs, "--dry-run", strings.Join(daemonDryRun, ","))
+	}
+	daemonCmd := exec.Command(gtPath, runArgs...)
 	daemonCmd.Dir = townRoot
 
 	// Detach from terminal
@@ -251,9 +299,26 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("\nStart with: %s\n", style.Dim.Render("gt daemon start"))
 	}
 
+	printResourceHolders(townRoot)
 	return nil
 }
 
+// printResourceHolders lists who holds the town's shared resources.
+func printResourceHolders(townRoot string) {
+	printed := false
+	for _, name := range []string{lock.ResourceDolt, lock.ResourceGitRemote, lock.ResourceTmuxServer} {
+		r := lock.NewResource(townRoot, name)
+		for _, h := range r.Holders() {
+			if !printed {
+				fmt.Printf("\n%s\n", style.Bold.Render("Shared resources in use:"))
+				printed = true
+			}
+			fmt.Printf("  %s [%d/%d]: %s (pid %d, since %s)\n",
+				name, h.Slot+1, r.Limit, h.Holder, h.PID, h.Since.Local().Format("15:04:05"))
+		}
+	}
+}
+
 // getBinaryModTime returns the modification time of the current executable
 func getBinaryModTime() (time.Time, error) {
 	exePath, err := os.Executable()
@@ -300,7 +365,11 @@ func runDaemonRun(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if err := daemon.ValidateDryRun(daemonDryRun); err != nil {
+		return err
+	}
 	config := daemon.DefaultConfig(townRoot)
+	config.DryRun = daemonDryRun
 	d, err := daemon.New(config)
 	if err != nil {
 		return fmt.Errorf("creating daemon: %w", err)
@@ -309,12 +378,38 @@ func runDaemonRun(cmd *cobra.Command, args []string) error {
 	return d.Run()
 }
 
-func runDaemonEnableSupervisor(cmd *cobra.Command, args []string) error {
+func runDaemonInstall(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if daemonInstallPrint {
+		data, err := templates.NewSupervisorData(townRoot)
+		if err != nil {
+			return err
+		}
+		content, err := templates.RenderSupervisor(runtime.GOOS, data)
+		if err != nil {
+			return err
+		}
+		unitPath, err := templates.SupervisorUnitPath(runtime.GOOS)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("# %s\n%s", unitPath, content)
+		return nil
+	}
+
+	// A manually started daemon would hold the lock and make the supervised
+	// one exit immediately; stop it so the service takes over cleanly.
+	if running, pid, _ := daemon.IsRunning(townRoot); running {
+		fmt.Printf("Stopping manually started daemon (PID %d)...\n", pid)
+		if err := daemon.StopDaemon(townRoot); err != nil {
+			return fmt.Errorf("stopping running daemon: %w", err)
+		}
+	}
+
 	msg, err := templates.ProvisionSupervisor(townRoot)
 	if err != nil {
 		return fmt.Errorf("configuring supervisor: %w", err)
@@ -324,12 +419,16 @@ func runDaemonEnableSupervisor(cmd *cobra.Command, args []string) error {
 	fmt.Println("\nThe daemon will now:")
 	fmt.Println("  - Auto-restart if it crashes")
 	fmt.Println("  - Start automatically on login/boot")
-	fmt.Println("\nTo stop the supervised daemon:")
-	if runtime.GOOS == "darwin" {
-		fmt.Println("  launchctl unload ~/Library/LaunchAgents/com.gastown.daemon.plist")
-	} else {
-		fmt.Println("  systemctl --user stop gastown-daemon.service")
-		fmt.Println("  systemctl --user disable gastown-daemon.service")
+	fmt.Printf("  - Log to %s\n", filepath.Join(townRoot, "daemon", "daemon.log"))
+	fmt.Printf("\nTo remove the service: %s\n", style.Dim.Render("gt daemon uninstall"))
+	return nil
+}
+
+func runDaemonUninstall(cmd *cobra.Command, args []string) error {
+	msg, err := templates.UninstallSupervisor()
+	if err != nil {
+		return fmt.Errorf("removing supervisor: %w", err)
 	}
+	fmt.Printf("%s %s\n", style.Bold.Render("✓"), msg)
 	return nil
 }
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	daemonRunPatrolWait    bool
+	daemonRunPatrolTimeout time.Duration
+)
+
+// runPatrolPickupWarning is how long a request may wait for the daemon
+// before the user is told it may not be picked up.
+const runPatrolPickupWarning = 15 * time.Second
+
+var daemonRunPatrolCmd = &cobra.Command{
+	Use:   "run-patrol <name>",
+	Short: "Run a daemon patrol now",
+	Long: `Ask the running daemon to run one patrol immediately, instead of waiting
+for its next tick or restarting the daemon to try a patrol change.
+
+The daemon runs the patrol in its main loop within a couple of seconds, so
+it never overlaps a scheduled run of any patrol. The usual gates apply: a
+patrol not enabled in mayor/daemon.json is skipped, and so is one that
+quiet hours pause.
+
+With --wait, the patrol's daemon log lines are streamed here as it runs and
+the command fails if the patrol was skipped.
+
+Patrols: ` + strings.Join(daemon.OnDemandPatrols, ", ") + `
+
+Examples:
+  gt daemon run-patrol wisp_reaper --wait
+  gt daemon run-patrol heartbeat`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDaemonRunPatrol,
+}
+
+func init() {
+	daemonRunPatrolCmd.Flags().BoolVar(&daemonRunPatrolWait, "wait", false, "Stream the patrol's log and wait for it to finish")
+	daemonRunPatrolCmd.Flags().DurationVar(&daemonRunPatrolTimeout, "timeout", 30*time.Minute, "With --wait, give up after this long")
+	daemonCmd.AddCommand(daemonRunPatrolCmd)
+}
+
+func runDaemonRunPatrol(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	req, err := daemon.RequestPatrol(townRoot, args[0], detectActor())
+	if err != nil {
+		return err
+	}
+	logPath := daemon.PatrolRequestLogPath(townRoot, req.ID)
+	if !daemonRunPatrolWait {
+		fmt.Printf("%s Requested %s patrol %s\n", style.SuccessPrefix, req.Patrol, style.Dim.Render("("+req.ID+")"))
+		fmt.Printf("  Log: %s\n", logPath)
+		return nil
+	}
+
+	fmt.Printf("%s Running %s patrol...\n", style.Bold.Render("→"), req.Patrol)
+	res, err := waitForPatrolRequest(townRoot, req.ID, os.Stdout, daemonRunPatrolTimeout)
+	if err != nil {
+		return err
+	}
+	took := res.FinishedAt.Sub(res.StartedAt).Round(time.Millisecond)
+	if res.Status == daemon.PatrolSkipped {
+		return fmt.Errorf("%s patrol skipped: %s", res.Patrol, res.Reason)
+	}
+	fmt.Printf("%s %s patrol finished in %v\n", style.SuccessPrefix, res.Patrol, took)
+	return nil
+}
+
+// waitForPatrolRequest copies the request's log to w as it grows until the
+// daemon writes the result.
+func waitForPatrolRequest(townRoot, id string, w io.Writer, timeout time.Duration) (*daemon.PatrolResult, error) {
+	logPath := daemon.PatrolRequestLogPath(townRoot, id)
+	var offset int64
+	drain := func() {
+		f, err := os.Open(logPath)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return
+		}
+		n, _ := io.Copy(w, f)
+		offset += n
+	}
+
+	start := time.Now()
+	warned := false
+	for {
+		res, err := daemon.ReadPatrolResult(townRoot, id)
+		drain()
+		if err != nil {
+			return nil, err
+		}
+		if res != nil {
+			return res, nil
+		}
+		waited := time.Since(start)
+		if waited > timeout {
+			return nil, fmt.Errorf("timed out after %v waiting for patrol (log: %s)", timeout, logPath)
+		}
+		if !warned && waited > runPatrolPickupWarning && daemon.PatrolRequestPending(townRoot, id) {
+			warned = true
+			fmt.Fprintf(os.Stderr, "%s The daemon has not picked up the request yet; it may predate run-patrol (restart it with 'gt daemon stop && gt daemon start')\n", style.WarningPrefix)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
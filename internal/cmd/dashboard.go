@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/endpoints"
 	"github.com/steveyegge/gastown/internal/web"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -34,7 +35,7 @@ The dashboard shows real-time convoy status with:
 - Auto-refresh every 30 seconds via htmx
 
 Example:
-  gt dashboard              # Start on default port 8080
+  gt dashboard              # Start on port 8080, or the next one free of other towns
   gt dashboard --port 3000  # Start on port 3000
   gt dashboard --open       # Start and open browser`,
 	RunE: runDashboard,
@@ -79,6 +80,27 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Pick a port no other town on this machine has claimed, unless one was
+	// given explicitly, and record it in the town's endpoint registry.
+	if wsErr == nil {
+		if !cmd.Flags().Changed("port") {
+			port, err := endpoints.FreePort(townRoot, dashboardPort)
+			if err != nil {
+				return fmt.Errorf("allocating dashboard port: %w", err)
+			}
+			dashboardPort = port
+		}
+		if err := endpoints.Register(townRoot, endpoints.Endpoint{
+			Name: endpoints.HTTP,
+			Kind: endpoints.KindTCP,
+			Host: "127.0.0.1",
+			Port: dashboardPort,
+			PID:  os.Getpid(),
+		}); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: registering dashboard endpoint: %v\n", err)
+		}
+	}
+
 	// Build the URL
 	url := fmt.Sprintf("http://localhost:%d", dashboardPort)
 
@@ -126,7 +148,11 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		WriteTimeout:      60 * time.Second,
 		IdleTimeout:       120 * time.Second,
 	}
-	return server.ListenAndServe()
+	err = server.ListenAndServe()
+	if wsErr == nil {
+		_ = endpoints.Unregister(townRoot, endpoints.HTTP)
+	}
+	return err
 }
 
 // openBrowser opens the specified URL in the default browser.
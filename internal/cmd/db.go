@@ -0,0 +1,443 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/eventarchive"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	dbJSON           bool
+	dbEventsIssue    string
+	dbEventsSince    string
+	dbEventsUntil    string
+	dbEventsLimit    int
+	dbEventsArchived bool
+)
+
+var dbCmd = &cobra.Command{
+	Use:     "db",
+	GroupID: GroupServices,
+	Short:   "Database maintenance (gc, stats, integrity, retention)",
+	RunE:    requireSubcommand,
+	Long: `Maintain the beads databases on the Dolt server.
+
+Long-lived databases accumulate garbage chunks and, after deletions,
+orphaned rows. These commands keep them healthy:
+
+  gc         Reclaim disk space with dolt gc
+  stats      Show on-disk size and per-table row counts
+  integrity  Find rows that reference deleted issues
+  retention  Show what the wisp reaper would delete (dry run)
+  events     Show event history, including archived events
+
+Each command takes database names, or runs on all databases.
+
+To run maintenance on a schedule, enable the db_maintenance patrol in
+mayor/daemon.json:
+  "db_maintenance": {"enabled": true, "interval": "24h"}
+It checks integrity (escalating orphans), runs gc, and logs sizes.`,
+}
+
+var dbGCCmd = &cobra.Command{
+	Use:   "gc [db...]",
+	Short: "Reclaim disk space with dolt gc",
+	Long: `Run dolt gc on each database (default: all databases), reporting the
+space reclaimed.
+
+Examples:
+  gt db gc
+  gt db gc gastown hq`,
+	RunE: runDBGC,
+}
+
+var dbStatsCmd = &cobra.Command{
+	Use:   "stats [db...]",
+	Short: "Show database size and row counts",
+	Long: `Show each database's on-disk size and exact row count per table
+(default: all databases).
+
+Examples:
+  gt db stats
+  gt db stats gastown --json`,
+	RunE: runDBStats,
+}
+
+var dbIntegrityCmd = &cobra.Command{
+	Use:   "integrity [db...]",
+	Short: "Find orphaned rows",
+	Long: `Check each database (default: all databases) for rows that reference
+issues that no longer exist: labels, comments, events and dependencies of
+deleted issues, dependencies on deleted issues, and agent hooks pointing at
+deleted issues.
+
+References to other rigs' issues are not checked. Exits non-zero when
+orphans are found.
+
+Examples:
+  gt db integrity
+  gt db integrity gastown --json`,
+	RunE: runDBIntegrity,
+}
+
+var dbRetentionCmd = &cobra.Command{
+	Use:   "retention [db...]",
+	Short: "Show what the wisp reaper would delete (dry run)",
+	Long: `Report, without changing anything, what the next wisp_reaper patrol cycle
+would close and delete in each database (default: the patrol's databases,
+or all databases):
+
+  stale wisps    open wisps older than max_age          (closed)
+  wisps          closed wisps older than delete_age     (deleted)
+  events         events of closed issues older than     (deleted)
+                 event_retention
+  mail           acked mail older than mail_retention   (deleted)
+
+Retention is configured per data type in mayor/daemon.json:
+  "wisp_reaper": {"enabled": true, "delete_age": "168h",
+                  "event_retention": "2160h", "mail_retention": "720h"}
+Set a retention to "off" to keep that data forever, or "dry_run": true to
+have the patrol only log this report. Events are never purged while the
+jsonl_git_backup patrol archives them (see gt db events).
+
+Examples:
+  gt db retention
+  gt db retention hq --json`,
+	RunE: runDBRetention,
+}
+
+var dbEventsCmd = &cobra.Command{
+	Use:   "events <db>",
+	Short: "Show event history, including archived events",
+	Long: `Show a database's events, merging the live events table with the
+archive in the JSONL backup repo.
+
+With "event_archive_age" set on the jsonl_git_backup patrol, events older
+than that age move to compressed monthly files under
+<db>/events-archive/ in the backup repo and leave the live table, which
+keeps patrol queries fast. This command reads both, so history reports are
+unaffected:
+  "jsonl_git_backup": {"enabled": true, "event_archive_age": "720h"}
+
+Examples:
+  gt db events hq --issue hq-abc12
+  gt db events gastown --since 90d --until 30d
+  gt db events gastown --archived --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDBEvents,
+}
+
+func init() {
+	dbEventsCmd.Flags().StringVar(&dbEventsIssue, "issue", "", "Only events of this issue")
+	dbEventsCmd.Flags().StringVar(&dbEventsSince, "since", "", "Only events newer than this (e.g., 7d, 24h)")
+	dbEventsCmd.Flags().StringVar(&dbEventsUntil, "until", "", "Only events older than this (e.g., 30d)")
+	dbEventsCmd.Flags().IntVarP(&dbEventsLimit, "limit", "n", 0, "Show only the newest N events (0 = all)")
+	dbEventsCmd.Flags().BoolVar(&dbEventsArchived, "archived", false, "Only read the archive, not the live table")
+	dbEventsCmd.Flags().BoolVar(&dbJSON, "json", false, "Output as JSON")
+	dbStatsCmd.Flags().BoolVar(&dbJSON, "json", false, "Output as JSON")
+	dbIntegrityCmd.Flags().BoolVar(&dbJSON, "json", false, "Output as JSON")
+	dbRetentionCmd.Flags().BoolVar(&dbJSON, "json", false, "Output as JSON")
+
+	dbCmd.AddCommand(dbGCCmd)
+	dbCmd.AddCommand(dbStatsCmd)
+	dbCmd.AddCommand(dbIntegrityCmd)
+	dbCmd.AddCommand(dbRetentionCmd)
+	dbCmd.AddCommand(dbEventsCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+// dbTargets returns the town root and the databases named in args, or all
+// databases.
+func dbTargets(args []string) (string, []string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	databases, err := snapshotDatabases(townRoot, args)
+	if err != nil {
+		return "", nil, err
+	}
+	return townRoot, databases, nil
+}
+
+func runDBGC(cmd *cobra.Command, args []string) error {
+	townRoot, databases, err := dbTargets(args)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	var reclaimed int64
+	for _, db := range databases {
+		result, err := doltserver.GC(townRoot, db)
+		if err != nil {
+			failed++
+			fmt.Printf("%s %s: %v\n", style.ErrorPrefix, db, err)
+			continue
+		}
+		reclaimed += result.Reclaimed()
+		fmt.Printf("%s %s: %s → %s %s\n", style.SuccessPrefix, db,
+			doltserver.FormatBytes(result.Before), doltserver.FormatBytes(result.After),
+			style.Dim.Render(fmt.Sprintf("(%v)", result.Duration.Round(time.Millisecond))))
+	}
+	fmt.Printf("\nReclaimed %s across %d database(s)\n", doltserver.FormatBytes(reclaimed), len(databases)-failed)
+	if failed > 0 {
+		return fmt.Errorf("gc failed on %d of %d database(s)", failed, len(databases))
+	}
+	return nil
+}
+
+func runDBStats(cmd *cobra.Command, args []string) error {
+	townRoot, databases, err := dbTargets(args)
+	if err != nil {
+		return err
+	}
+
+	var all []*doltserver.DatabaseStats
+	for _, db := range databases {
+		stats, err := doltserver.GetDatabaseStats(townRoot, db)
+		if err != nil {
+			return fmt.Errorf("%s: %w", db, err)
+		}
+		all = append(all, stats)
+	}
+
+	if dbJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(all)
+	}
+	for i, stats := range all {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render(stats.Name),
+			style.Dim.Render(fmt.Sprintf("(%s, %d rows)", doltserver.FormatBytes(stats.SizeBytes), stats.TotalRows())))
+		for _, t := range stats.Tables {
+			fmt.Printf("  %-24s %10d\n", t.Name, t.Rows)
+		}
+	}
+	return nil
+}
+
+func runDBIntegrity(cmd *cobra.Command, args []string) error {
+	townRoot, databases, err := dbTargets(args)
+	if err != nil {
+		return err
+	}
+
+	all := make(map[string][]doltserver.IntegrityProblem)
+	var orphaned int
+	for _, db := range databases {
+		problems, err := doltserver.CheckIntegrity(townRoot, db)
+		if err != nil {
+			return fmt.Errorf("%s: %w", db, err)
+		}
+		all[db] = problems
+		if len(problems) > 0 {
+			orphaned++
+		}
+	}
+
+	if dbJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(all); err != nil {
+			return err
+		}
+	} else {
+		for _, db := range databases {
+			problems := all[db]
+			if len(problems) == 0 {
+				fmt.Printf("%s %s: no orphaned rows\n", style.SuccessPrefix, db)
+				continue
+			}
+			fmt.Printf("%s %s:\n", style.ErrorPrefix, db)
+			for _, p := range problems {
+				fmt.Printf("  %6d %s\n", p.Count, p.Check)
+			}
+		}
+	}
+	if orphaned > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runDBRetention(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	patrolConfig := daemon.LoadPatrolConfig(townRoot)
+	databases := args
+	if len(databases) == 0 && patrolConfig != nil && patrolConfig.Patrols != nil && patrolConfig.Patrols.WispReaper != nil {
+		databases = patrolConfig.Patrols.WispReaper.Databases
+	}
+	if len(databases) == 0 {
+		if databases, err = snapshotDatabases(townRoot, nil); err != nil {
+			return err
+		}
+	}
+
+	policy := daemon.ReaperRetention(patrolConfig)
+	maxAge := daemon.WispReaperMaxAge(patrolConfig)
+	config := doltserver.DefaultConfig(townRoot)
+	host := config.Host
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	plan, err := daemon.PlanReap(host, config.Port, databases, maxAge, policy, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	retention := func(d time.Duration) string {
+		if d <= 0 {
+			return "off"
+		}
+		return d.String()
+	}
+	if dbJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{
+			"retention": map[string]string{
+				"max_age": maxAge.String(),
+				"wisps":   retention(policy.Wisps),
+				"events":  retention(policy.Events),
+				"mail":    retention(policy.Mail),
+			},
+			"databases": plan,
+		})
+	}
+	fmt.Printf("Retention: wisps %s, events %s, mail %s %s\n",
+		retention(policy.Wisps), retention(policy.Events), retention(policy.Mail),
+		style.Dim.Render("(stale wisps close after "+maxAge.String()+")"))
+	if !daemon.IsPatrolEnabled(patrolConfig, "wisp_reaper") {
+		style.PrintWarning("wisp_reaper patrol is not enabled; nothing is reaped on a schedule")
+	}
+	fmt.Println()
+	fmt.Printf("  %-16s %12s %12s %12s %12s\n", "DATABASE", "STALE WISPS", "WISPS", "EVENTS", "MAIL")
+	var total daemon.ReapCounts
+	for _, c := range plan {
+		fmt.Printf("  %-16s %12d %12d %12d %12d\n", c.Database, c.StaleWisps, c.Wisps, c.Events, c.Mail)
+		total.StaleWisps += c.StaleWisps
+		total.Wisps += c.Wisps
+		total.Events += c.Events
+		total.Mail += c.Mail
+	}
+	fmt.Printf("\n%d row(s) would be closed or deleted %s\n", total.Total(), style.Dim.Render("(dry run; nothing changed)"))
+	return nil
+}
+
+func runDBEvents(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	db := args[0]
+
+	filter := eventarchive.Filter{IssueID: dbEventsIssue}
+	for _, f := range []struct {
+		flag, value string
+		t           *time.Time
+	}{{"--since", dbEventsSince, &filter.Since}, {"--until", dbEventsUntil, &filter.Until}} {
+		if f.value == "" {
+			continue
+		}
+		d, err := parseDuration(f.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s duration: %w", f.flag, err)
+		}
+		*f.t = time.Now().UTC().Add(-d)
+	}
+
+	var backupConfig *daemon.JsonlGitBackupConfig
+	if patrolConfig := daemon.LoadPatrolConfig(townRoot); patrolConfig != nil && patrolConfig.Patrols != nil {
+		backupConfig = patrolConfig.Patrols.JsonlGitBackup
+	}
+	repo, err := daemon.JsonlGitBackupRepo(backupConfig)
+	if err != nil {
+		return err
+	}
+	archived, err := eventarchive.Read(eventarchive.Dir(repo, db), filter)
+	if err != nil {
+		return fmt.Errorf("reading event archive: %w", err)
+	}
+
+	var live []eventarchive.Event
+	if !dbEventsArchived {
+		query := fmt.Sprintf("USE `%s`; SELECT * FROM events", db)
+		if filter.IssueID != "" {
+			query += fmt.Sprintf(" WHERE issue_id = '%s'", doltserver.EscapeSQL(filter.IssueID))
+		}
+		out, err := doltserver.QueryJSON(townRoot, query)
+		if err != nil {
+			return fmt.Errorf("reading live events: %w", err)
+		}
+		rows, err := eventarchive.ParseRows(out)
+		if err != nil {
+			return err
+		}
+		for _, e := range rows {
+			if filter.Match(e) {
+				live = append(live, e)
+			}
+		}
+	}
+
+	events := eventarchive.Merge(archived, live)
+	if dbEventsLimit > 0 && len(events) > dbEventsLimit {
+		events = events[len(events)-dbEventsLimit:]
+	}
+
+	if dbJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(events)
+	}
+	for _, e := range events {
+		detail := ""
+		switch {
+		case e.NewValue != nil && e.OldValue != nil:
+			detail = *e.OldValue + " → " + *e.NewValue
+		case e.NewValue != nil:
+			detail = *e.NewValue
+		case e.Comment != nil:
+			detail = *e.Comment
+		}
+		if i := strings.IndexByte(detail, '\n'); i >= 0 {
+			detail = detail[:i] + " …"
+		}
+		fmt.Printf("%s  %-12s %-16s %-20s %s\n", style.Dim.Render(e.Time().Format("2006-01-02 15:04")),
+			e.IssueID, e.EventType, e.Actor, detail)
+	}
+	fmt.Printf("\n%d event(s) %s\n", len(events),
+		style.Dim.Render(fmt.Sprintf("(%d archived, %d live)", len(events)-countLive(events, live), countLive(events, live))))
+	return nil
+}
+
+// countLive returns how many of events came from the live table.
+func countLive(events, live []eventarchive.Event) int {
+	ids := make(map[int64]bool, len(live))
+	for _, e := range live {
+		ids[e.ID] = true
+	}
+	n := 0
+	for _, e := range events {
+		if ids[e.ID] {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	gitpkg "github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/summarize"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	diffSummaryJSON     bool
+	diffSummaryMaxFiles int
+	diffSummaryNoBodies bool
+	diffSummarySummary  bool
+)
+
+var diffSummaryCmd = &cobra.Command{
+	Use:     "diff-summary <issue-id>",
+	GroupID: GroupWork,
+	Short:   "Summarize the code changes made for an issue",
+	Long: `Summarize the code changes made for an issue, ready to paste into a
+review prompt or digest.
+
+The changes are found through the issue's merge request (open ones first,
+then the most recent) or, without one, a polecat branch named after the
+issue. Merged MRs are summarized from their merge commit; everything else
+from the branch against its target.
+
+The digest lists commit messages, files changed with line counts, test
+files touched, and size totals, as Markdown (or JSON with --json).
+
+With --summarize, the patch itself is condensed into a prose summary for
+the reviewer by the town's summarizer model (settings/config.json
+"summarizer"), or truncated when none is configured.
+
+Examples:
+  gt diff-summary gt-abc12
+  gt diff-summary gt-abc12 --max-files 20 --no-bodies
+  gt diff-summary gt-abc12 --json
+  gt diff-summary gt-abc12 --summarize`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDiffSummary,
+}
+
+func init() {
+	diffSummaryCmd.Flags().BoolVar(&diffSummaryJSON, "json", false, "Output as JSON")
+	diffSummaryCmd.Flags().IntVar(&diffSummaryMaxFiles, "max-files", 50, "List at most this many files (0 = all)")
+	diffSummaryCmd.Flags().BoolVar(&diffSummaryNoBodies, "no-bodies", false, "Show commit subjects only")
+	diffSummaryCmd.Flags().BoolVar(&diffSummarySummary, "summarize", false, "Add a summary of the patch for reviewers")
+	rootCmd.AddCommand(diffSummaryCmd)
+}
+
+// diffDigest is the summary of an issue's changes.
+type diffDigest struct {
+	Issue  string `json:"issue"`
+	Title  string `json:"title,omitempty"`
+	MR     string `json:"mr,omitempty"`
+	Merged bool   `json:"merged,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	Base   string `json:"base"`
+	Head   string `json:"head"`
+
+	Commits   []gitpkg.CommitInfo `json:"commits"`
+	Files     []gitpkg.FileChange `json:"files"`
+	TestFiles []string            `json:"test_files,omitempty"`
+
+	Insertions int `json:"insertions"`
+	Deletions  int `json:"deletions"`
+
+	// Summary condenses the patch (--summarize only).
+	Summary string `json:"summary,omitempty"`
+}
+
+// diffSummaryChars caps the --summarize summary.
+const diffSummaryChars = 2000
+
+// diffSource is where an issue's changes live: Log and Diff are the revision
+// arguments for git log and git diff.
+type diffSource struct {
+	MR     string
+	Merged bool
+	Branch string
+	Base   string
+	Head   string
+	Log    string
+	Diff   string
+}
+
+func runDiffSummary(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	issueID := args[0]
+	issue, err := beads.New(resolveBeadDir(issueID)).Show(issueID)
+	if err != nil {
+		return fmt.Errorf("issue %s: %w", issueID, err)
+	}
+
+	rigName := beads.GetRigNameForPrefix(townRoot, beads.ExtractPrefix(issueID))
+	if rigName == "" {
+		return fmt.Errorf("cannot determine the rig for %s (no route for its prefix)", issueID)
+	}
+	mgr, r, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	repo, err := mgr.RepoBase()
+	if err != nil {
+		return err
+	}
+	_ = repo.Fetch("origin") // non-fatal: may be offline
+
+	src, err := findDiffSource(repo, r, issueID)
+	if err != nil {
+		return err
+	}
+	digest, err := buildDiffDigest(repo, src)
+	if err != nil {
+		return err
+	}
+	digest.Issue, digest.Title = issue.ID, issue.Title
+	if diffSummarySummary {
+		patch, err := repo.Diff(src.Diff)
+		if err != nil {
+			return fmt.Errorf("diffing %s: %w", src.Diff, err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), summarize.DefaultTimeout)
+		digest.Summary = summarize.Text(ctx, summarize.New(townRoot), summarize.KindDiff, patch, diffSummaryChars)
+		cancel()
+	}
+
+	if diffSummaryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(digest)
+	}
+	fmt.Print(renderDiffDigest(digest, diffSummaryMaxFiles, !diffSummaryNoBodies))
+	return nil
+}
+
+// findDiffSource locates an issue's changes: its MR if it has one, else a
+// polecat branch named after it.
+func findDiffSource(repo *gitpkg.Git, r *rig.Rig, issueID string) (*diffSource, error) {
+	src := &diffSource{}
+	target := r.DefaultBranch()
+
+	if mr, fields := findIssueMR(r, issueID); mr != nil {
+		src.MR, src.Branch = mr.ID, fields.Branch
+		if fields.Target != "" {
+			target = fields.Target
+		}
+		if fields.MergeCommit != "" {
+			if _, err := repo.Rev(fields.MergeCommit); err == nil {
+				// Compare against the first parent so squash and merge
+				// commits both cover exactly what landed.
+				src.Merged = true
+				src.Base, src.Head = fields.MergeCommit+"^1", fields.MergeCommit
+				src.Log = src.Base + ".." + src.Head
+				src.Diff = src.Log
+				return src, nil
+			}
+		}
+	}
+	if src.Branch == "" {
+		src.Branch = findIssueBranch(repo, issueID)
+	}
+	if src.Branch == "" {
+		return nil, fmt.Errorf("no merge request or branch found for %s", issueID)
+	}
+
+	head := src.Branch
+	if exists, _ := repo.BranchExists(head); !exists {
+		head = "origin/" + src.Branch
+		if _, err := repo.Rev(head); err != nil {
+			return nil, fmt.Errorf("branch %s of %s no longer exists (merged and deleted?)", src.Branch, issueID)
+		}
+	}
+	src.Base, src.Head = baseRefFor(repo, target), head
+	src.Log = src.Base + ".." + src.Head
+	src.Diff = src.Base + "..." + src.Head
+	return src, nil
+}
+
+// findIssueMR returns the issue's open MR, or its most recent one.
+func findIssueMR(r *rig.Rig, issueID string) (*beads.Issue, *beads.MRFields) {
+	mrs, err := beads.New(r.BeadsPath()).List(beads.ListOptions{
+		Label:    "gt:merge-request",
+		Status:   "all",
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, nil
+	}
+	var best *beads.Issue
+	var bestFields *beads.MRFields
+	for _, mr := range mrs {
+		fields := beads.ParseMRFields(mr)
+		if fields == nil || fields.SourceIssue != issueID {
+			continue
+		}
+		if best == nil || betterMR(mr, best) {
+			best, bestFields = mr, fields
+		}
+	}
+	return best, bestFields
+}
+
+// betterMR reports whether a should be preferred over b: open before
+// closed, then newest.
+func betterMR(a, b *beads.Issue) bool {
+	aOpen, bOpen := a.Status != "closed", b.Status != "closed"
+	if aOpen != bOpen {
+		return aOpen
+	}
+	return a.CreatedAt > b.CreatedAt
+}
+
+// findIssueBranch returns a polecat branch (local, then on origin) whose
+// name carries the issue ID. The last in sort order wins, which for the
+// default polecat/<name>/<issue>@<timestamp> scheme is usually the newest.
+func findIssueBranch(repo *gitpkg.Git, issueID string) string {
+	var candidates []string
+	local, _ := repo.ListBranches(constants.BranchPolecatPrefix + "*")
+	remote, _ := repo.ListRemoteBranches("origin", constants.BranchPolecatPrefix)
+	for _, b := range append(local, remote...) {
+		if parseBranchName(strings.TrimSpace(b)).Issue == issueID {
+			candidates = append(candidates, strings.TrimSpace(b))
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.Strings(candidates)
+	return candidates[len(candidates)-1]
+}
+
+func buildDiffDigest(repo *gitpkg.Git, src *diffSource) (*diffDigest, error) {
+	commits, err := repo.Commits(src.Log)
+	if err != nil {
+		return nil, fmt.Errorf("listing commits %s: %w", src.Log, err)
+	}
+	files, err := repo.DiffNumstat(src.Diff)
+	if err != nil {
+		return nil, fmt.Errorf("diffing %s: %w", src.Diff, err)
+	}
+	d := &diffDigest{
+		MR:      src.MR,
+		Merged:  src.Merged,
+		Branch:  src.Branch,
+		Base:    src.Base,
+		Head:    src.Head,
+		Commits: commits,
+		Files:   files,
+	}
+	for _, f := range files {
+		d.Insertions += f.Added
+		d.Deletions += f.Deleted
+		if isTestPath(f.Path) {
+			d.TestFiles = append(d.TestFiles, f.Path)
+		}
+	}
+	return d, nil
+}
+
+// isTestPath reports whether a file path looks like a test file in the
+// common Go, JS/TS, Python, Ruby and Java layouts.
+func isTestPath(p string) bool {
+	base := path.Base(p)
+	ext := path.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	switch {
+	case strings.HasSuffix(stem, "_test"), strings.HasSuffix(stem, "_spec"),
+		strings.HasSuffix(stem, ".test"), strings.HasSuffix(stem, ".spec"),
+		strings.HasPrefix(stem, "test_"),
+		ext == ".java" && strings.HasSuffix(stem, "Test"):
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		switch dir {
+		case "test", "tests", "__tests__", "spec", "testdata":
+			return true
+		}
+	}
+	return false
+}
+
+// renderDiffDigest formats a digest as Markdown, listing at most maxFiles
+// files (0 = all).
+func renderDiffDigest(d *diffDigest, maxFiles int, bodies bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s", d.Issue)
+	if d.Title != "" {
+		fmt.Fprintf(&b, ": %s", d.Title)
+	}
+	b.WriteString("\n\n")
+
+	var source []string
+	if d.MR != "" {
+		state := "open"
+		if d.Merged {
+			state = "merged"
+		}
+		source = append(source, fmt.Sprintf("MR %s (%s)", d.MR, state))
+	}
+	if d.Branch != "" {
+		source = append(source, "branch "+d.Branch)
+	}
+	fmt.Fprintf(&b, "Source: %s, compared to %s\n", strings.Join(source, ", "), d.Base)
+	fmt.Fprintf(&b, "Size: %d commit(s), %d file(s), +%d/-%d lines, %d test file(s)\n",
+		len(d.Commits), len(d.Files), d.Insertions, d.Deletions, len(d.TestFiles))
+
+	if d.Summary != "" {
+		b.WriteString("\n### Summary\n\n")
+		b.WriteString(strings.TrimSpace(d.Summary) + "\n")
+	}
+
+	if len(d.Commits) > 0 {
+		b.WriteString("\n### Commits\n\n")
+		for _, c := range d.Commits {
+			fmt.Fprintf(&b, "- %s %s\n", shortSHA(c.SHA), c.Subject)
+			if bodies && c.Body != "" {
+				for _, line := range strings.Split(c.Body, "\n") {
+					fmt.Fprintf(&b, "  %s\n", line)
+				}
+			}
+		}
+	}
+
+	if len(d.Files) > 0 {
+		b.WriteString("\n### Files changed\n\n")
+		files := append([]gitpkg.FileChange(nil), d.Files...)
+		// Largest changes first: they are what a reviewer should read.
+		sort.SliceStable(files, func(i, j int) bool {
+			return files[i].Added+files[i].Deleted > files[j].Added+files[j].Deleted
+		})
+		shown := files
+		if maxFiles > 0 && len(shown) > maxFiles {
+			shown = shown[:maxFiles]
+		}
+		for _, f := range shown {
+			if f.Binary {
+				fmt.Fprintf(&b, "- %s (binary)\n", f.Path)
+			} else {
+				fmt.Fprintf(&b, "- %s (+%d/-%d)\n", f.Path, f.Added, f.Deleted)
+			}
+		}
+		if rest := len(files) - len(shown); rest > 0 {
+			fmt.Fprintf(&b, "- ... and %d more\n", rest)
+		}
+	}
+
+	if len(d.TestFiles) > 0 {
+		b.WriteString("\n### Tests touched\n\n")
+		for _, f := range d.TestFiles {
+			fmt.Fprintf(&b, "- %s\n", f)
+		}
+	} else if len(d.Files) > 0 {
+		b.WriteString("\nNo test files touched.\n")
+	}
+	return b.String()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
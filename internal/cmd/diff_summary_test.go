@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	gitpkg "github.com/steveyegge/gastown/internal/git"
+)
+
+func TestIsTestPath(t *testing.T) {
+	tests := map[string]bool{
+		"internal/cmd/explain_test.go":  true,
+		"web/src/app.test.tsx":          true,
+		"web/src/__tests__/app.tsx":     true,
+		"pkg/test_parser.py":            true,
+		"spec/models/user_spec.rb":      true,
+		"src/main/FooTest.java":         true,
+		"internal/git/testdata/a.patch": true,
+		"internal/cmd/explain.go":       false,
+		"docs/testing.md":               false,
+		"src/contest.py":                false,
+	}
+	for p, want := range tests {
+		if got := isTestPath(p); got != want {
+			t.Errorf("isTestPath(%q) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestBetterMR(t *testing.T) {
+	open := &beads.Issue{ID: "gt-mr1", Status: "open", CreatedAt: "2026-01-01T00:00:00Z"}
+	closedNew := &beads.Issue{ID: "gt-mr2", Status: "closed", CreatedAt: "2026-02-01T00:00:00Z"}
+	closedOld := &beads.Issue{ID: "gt-mr3", Status: "closed", CreatedAt: "2025-12-01T00:00:00Z"}
+	if !betterMR(open, closedNew) {
+		t.Error("open MR should beat a newer closed one")
+	}
+	if !betterMR(closedNew, closedOld) {
+		t.Error("newer closed MR should beat an older one")
+	}
+}
+
+func TestRenderDiffDigest(t *testing.T) {
+	d := &diffDigest{
+		Issue:  "gt-abc12",
+		Title:  "Add widget",
+		MR:     "gt-mr1",
+		Branch: "polecat/Toast/gt-abc12@k2",
+		Base:   "origin/main",
+		Commits: []gitpkg.CommitInfo{
+			{SHA: "0123456789abcdef", Subject: "feat: add widget", Body: "Explains why."},
+		},
+		Files: []gitpkg.FileChange{
+			{Path: "small.go", Added: 1},
+			{Path: "widget.go", Added: 40, Deleted: 2},
+			{Path: "widget_test.go", Added: 20},
+			{Path: "logo.png", Binary: true},
+		},
+		TestFiles:  []string{"widget_test.go"},
+		Insertions: 61,
+		Deletions:  2,
+		Summary:    "Adds a widget.",
+	}
+	out := renderDiffDigest(d, 2, true)
+	for _, want := range []string{
+		"## gt-abc12: Add widget",
+		"Source: MR gt-mr1 (open), branch polecat/Toast/gt-abc12@k2, compared to origin/main",
+		"Size: 1 commit(s), 4 file(s), +61/-2 lines, 1 test file(s)",
+		"- 01234567 feat: add widget\n  Explains why.",
+		"- widget.go (+40/-2)\n- widget_test.go (+20/-0)\n- ... and 2 more",
+		"### Tests touched\n\n- widget_test.go",
+		"### Summary\n\nAdds a widget.\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("digest missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(renderDiffDigest(d, 0, false), "Explains why.") {
+		t.Error("commit bodies shown with bodies=false")
+	}
+}
@@ -17,6 +17,8 @@ var (
 	doctorRestartSessions bool
 	doctorNoStart         bool
 	doctorSlow            string
+	doctorSessions        []string
+	doctorJSON            bool
 )
 
 var doctorCmd = &cobra.Command{
@@ -79,6 +81,12 @@ Rig checks (with --rig flag):
   - polecat-clones-valid     Verify polecat directories are valid clones
   - beads-config-valid       Verify beads configuration (fixable)
 
+Preflight checks (with --rig flag; also run by gt sling before spawning):
+  - preflight-agent          Verify the agent binary is installed at the pinned agent_version
+  - preflight-remote         Verify the rig's git remote is reachable
+  - preflight-test-command   Verify the merge queue test gate command exists
+  - preflight-secrets        Verify preflight.secrets are set
+
 Routing checks (fixable):
   - routes-config            Check beads routing configuration
   - prefix-mismatch          Detect rigs.json vs routes.jsonl prefix mismatches (fixable)
@@ -105,7 +113,17 @@ Patrol checks:
 Use --fix to attempt automatic fixes for issues that support it.
 Use --no-start with --fix to suppress starting the daemon and agents.
 Use --rig to check a specific rig instead of the entire workspace.
-Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).`,
+Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).
+
+Session diagnosis (with --sessions):
+Instead of the checks, gather everything about specific problem sessions
+into one report: tmux health, the pane's process tree, the last 100 lines
+of output, nudges from the last 24h, the hooked issue, branch state, and
+probable causes (dead pane, startup command visible but not running,
+blank window, ignored nudges, finished work still hooked).
+
+  gt doctor --sessions gastown/Toast
+  gt doctor --sessions gastown/crew/max,gt-Furiosa --json`,
 	RunE: runDoctor,
 }
 
@@ -116,6 +134,8 @@ func init() {
 	doctorCmd.Flags().BoolVar(&doctorRestartSessions, "restart-sessions", false, "Restart patrol sessions when fixing stale settings (use with --fix)")
 	doctorCmd.Flags().BoolVar(&doctorNoStart, "no-start", false, "Suppress starting daemon/agents during --fix")
 	doctorCmd.Flags().StringVar(&doctorSlow, "slow", "", "Highlight slow checks (optional threshold, default 1s)")
+	doctorCmd.Flags().StringSliceVar(&doctorSessions, "sessions", nil, "Diagnose specific sessions (rig/polecat, rig/crew/name, or tmux session name)")
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Output session reports as JSON (with --sessions)")
 	// Allow --slow without a value (uses default 1s)
 	doctorCmd.Flags().Lookup("slow").NoOptDefVal = "1s"
 	rootCmd.AddCommand(doctorCmd)
@@ -128,6 +148,10 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if len(doctorSessions) > 0 {
+		return runDoctorSessions(cmd.OutOrStdout(), townRoot, doctorSessions, doctorJSON)
+	}
+
 	// Create check context
 	ctx := &doctor.CheckContext{
 		TownRoot:        townRoot,
@@ -150,10 +174,13 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	// 1. gt binary freshness
 	// 2. bd binary exists
 	// 3. dolt binary exists
-	// 4. Dolt server is reachable (everything downstream depends on this)
+	// 4. tmux is new enough
+	// 5. Dolt server is reachable (everything downstream depends on this)
 	d.Register(doctor.NewStaleBinaryCheck())
 	d.Register(doctor.NewBeadsBinaryCheck())
 	d.Register(doctor.NewDoltBinaryCheck())
+	d.Register(doctor.NewTmuxVersionCheck())
+	d.Register(doctor.NewWSLCheck())
 	d.Register(doctor.NewDoltServerReachableCheck())
 
 	d.Register(doctor.NewTownGitCheck())
@@ -194,6 +221,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewThemeCheck())
 	d.Register(doctor.NewCrashReportCheck())
 	d.Register(doctor.NewEnvVarsCheck())
+	d.Register(doctor.NewGitIdentityCheck())
 
 	// Patrol system checks
 	d.Register(doctor.NewPatrolMoleculesExistCheck())
@@ -252,6 +280,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	// Rig-specific checks (only when --rig is specified)
 	if doctorRig != "" {
 		d.RegisterAll(doctor.RigChecks()...)
+		d.RegisterAll(doctor.PreflightChecks()...)
 	}
 
 	// Parse slow threshold (0 = disabled)
@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/platform"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+const (
+	// sessionDiagLines is how much pane output a session report captures.
+	sessionDiagLines = 100
+	// sessionDiagNudgeWindow is how far back a report looks for nudges.
+	sessionDiagNudgeWindow = 24 * time.Hour
+	// sessionDiagTreeDepth bounds the pane process tree walk.
+	sessionDiagTreeDepth = 6
+)
+
+// runDoctorSessions diagnoses each named session and prints one report per
+// session: tmux health, pane process tree, recent output, nudges, hooked
+// work, branch state, and probable causes.
+func runDoctorSessions(w io.Writer, townRoot string, targets []string, asJSON bool) error {
+	var reports []*doctor.SessionReport
+	for _, target := range targets {
+		r, err := gatherSessionReport(townRoot, target)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, r)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reports)
+	}
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		printSessionReport(w, r)
+	}
+	return nil
+}
+
+// resolveSessionTarget accepts an address (gastown/Toast, gastown/crew/max,
+// mayor) or a raw tmux session name (gt-Toast).
+func resolveSessionTarget(target string) (*session.AgentIdentity, error) {
+	if strings.Contains(target, "/") || target == "mayor" || target == "deacon" {
+		return session.ParseAddress(target)
+	}
+	return session.ParseSessionName(target)
+}
+
+func gatherSessionReport(townRoot, target string) (*doctor.SessionReport, error) {
+	id, err := resolveSessionTarget(target)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %q: %w", target, err)
+	}
+	r := &doctor.SessionReport{
+		Target:     target,
+		Session:    id.SessionName(),
+		Role:       string(id.Role),
+		ExitStatus: -1,
+	}
+
+	t := tmux.NewTmux()
+	r.Exists, _ = t.HasSession(r.Session)
+	if r.Exists {
+		r.PaneDead, r.ExitStatus, _ = t.PaneDeadStatus(r.Session)
+		r.PaneCommand, _ = t.GetPaneCommand(r.Session)
+		r.StartCommand, _ = t.GetPaneStartCommand(r.Session)
+		if pid, err := t.GetPanePID(r.Session); err == nil {
+			r.PanePID, _ = strconv.Atoi(pid)
+			r.ProcessTree = paneProcessTree(r.PanePID)
+		}
+		r.AgentRunning = !r.PaneDead && t.IsAgentAlive(r.Session)
+		r.LastActivity, _ = t.GetSessionActivity(r.Session)
+		r.Output, _ = t.CapturePane(r.Session, sessionDiagLines)
+	}
+
+	r.Nudges = recentNudges(townRoot, nudgeTargets(target, id), time.Now().Add(-sessionDiagNudgeWindow))
+	gatherSessionWork(r, id)
+
+	r.Causes = doctor.DiagnoseSession(r)
+	return r, nil
+}
+
+// paneProcessTree walks the pane's process tree, depth first.
+func paneProcessTree(pid int) []doctor.Process {
+	var tree []doctor.Process
+	var walk func(pid, depth int)
+	walk = func(pid, depth int) {
+		command := "?"
+		if args, err := platform.Cmdline(pid); err == nil && len(args) > 0 {
+			command = strings.Join(args, " ")
+		}
+		tree = append(tree, doctor.Process{PID: pid, Depth: depth, Command: command})
+		if depth >= sessionDiagTreeDepth {
+			return
+		}
+		children, err := platform.Children(pid)
+		if err != nil {
+			return
+		}
+		for _, c := range children {
+			walk(c, depth+1)
+		}
+	}
+	if pid > 0 {
+		walk(pid, 0)
+	}
+	return tree
+}
+
+// nudgeTargets returns the forms a nudge event's target may take for id.
+func nudgeTargets(target string, id *session.AgentIdentity) map[string]bool {
+	targets := map[string]bool{target: true, id.SessionName(): true, id.Address(): true}
+	if id.Role == session.RolePolecat {
+		targets[id.Rig+"/"+id.Name] = true
+	}
+	return targets
+}
+
+// recentNudges reads nudges to any of targets since the given time from the
+// town event log, oldest first.
+func recentNudges(townRoot string, targets map[string]bool, since time.Time) []doctor.Nudge {
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var nudges []doctor.Nudge
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Type != events.TypeNudge {
+			continue
+		}
+		target, _ := e.Payload["target"].(string)
+		if !targets[target] {
+			continue
+		}
+		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
+		if ts.Before(since) {
+			continue
+		}
+		reason, _ := e.Payload["reason"].(string)
+		nudges = append(nudges, doctor.Nudge{Time: ts, Sender: e.Actor, Message: reason})
+	}
+	return nudges
+}
+
+// gatherSessionWork fills in hooked issue and branch state for polecats and
+// crew. Other roles have neither.
+func gatherSessionWork(r *doctor.SessionReport, id *session.AgentIdentity) {
+	if id.Role != session.RolePolecat && id.Role != session.RoleCrew {
+		return
+	}
+	var rg *rig.Rig
+	var agentBeadID string
+	if id.Role == session.RolePolecat {
+		mgr, pr, err := getPolecatManager(id.Rig)
+		if err != nil {
+			r.HookError = err.Error()
+			return
+		}
+		rg = pr
+		agentBeadID = polecatBeadIDForRig(rg, id.Rig, id.Name)
+		if p, err := mgr.Get(id.Name); err == nil {
+			r.WorkDir, r.Branch = p.ClonePath, p.Branch
+		}
+	} else {
+		_, cr, err := getRig(id.Rig)
+		if err != nil {
+			r.HookError = err.Error()
+			return
+		}
+		rg = cr
+		agentBeadID = beads.CrewBeadIDWithPrefix(rigPrefix(rg), id.Rig, id.Name)
+		r.WorkDir = filepath.Join(rg.Path, "crew", id.Name)
+		r.Branch, _ = git.NewGit(r.WorkDir).CurrentBranch()
+	}
+
+	bd := beads.New(rg.Path)
+	if issue, fields, err := bd.GetAgentBead(agentBeadID); err != nil {
+		r.HookError = err.Error()
+	} else {
+		if issue != nil {
+			r.HookBead = issue.HookBead
+		}
+		if r.HookBead == "" && fields != nil {
+			r.HookBead = fields.HookBead
+		}
+	}
+	if r.HookBead != "" {
+		if hooked, err := bd.Show(r.HookBead); err != nil {
+			r.HookError = err.Error()
+		} else {
+			r.HookTitle, r.HookStatus = hooked.Title, hooked.Status
+		}
+	}
+
+	if r.WorkDir != "" {
+		if state, err := getGitState(r.WorkDir); err != nil {
+			r.GitError = err.Error()
+		} else {
+			r.UnpushedCommits = state.UnpushedCommits
+			r.UncommittedFiles = len(state.UncommittedFiles)
+		}
+	}
+}
+
+func printSessionReport(w io.Writer, r *doctor.SessionReport) {
+	fmt.Fprintf(w, "%s %s\n", style.Bold.Render("Session:"), r.Session)
+	fmt.Fprintf(w, "  target: %s (%s)\n", r.Target, r.Role)
+
+	fmt.Fprintf(w, "\n%s\n", style.Bold.Render("tmux"))
+	switch {
+	case !r.Exists:
+		fmt.Fprintln(w, "  session does not exist")
+	case r.PaneDead:
+		fmt.Fprintf(w, "  pane dead (exit status %d)\n", r.ExitStatus)
+	default:
+		fmt.Fprintf(w, "  pane command: %s  agent running: %v\n", r.PaneCommand, r.AgentRunning)
+	}
+	if r.StartCommand != "" {
+		fmt.Fprintf(w, "  start command: %s\n", r.StartCommand)
+	}
+	if !r.LastActivity.IsZero() {
+		fmt.Fprintf(w, "  last activity: %s\n", formatAge(r.LastActivity))
+	}
+
+	if len(r.ProcessTree) > 0 {
+		fmt.Fprintf(w, "\n%s\n", style.Bold.Render("Pane process tree"))
+		for _, p := range r.ProcessTree {
+			fmt.Fprintf(w, "  %s%d %s\n", strings.Repeat("  ", p.Depth), p.PID, p.Command)
+		}
+	}
+
+	if r.Exists {
+		fmt.Fprintf(w, "\n%s\n", style.Bold.Render(fmt.Sprintf("Last %d lines", sessionDiagLines)))
+		if strings.TrimSpace(r.Output) == "" {
+			fmt.Fprintln(w, style.Dim.Render("  (blank)"))
+		} else {
+			for _, line := range strings.Split(strings.TrimRight(r.Output, "\n"), "\n") {
+				fmt.Fprintf(w, "  │ %s\n", line)
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "\n%s\n", style.Bold.Render("Recent nudges"))
+	if len(r.Nudges) == 0 {
+		fmt.Fprintln(w, style.Dim.Render("  (none in the last 24h)"))
+	}
+	for _, n := range r.Nudges {
+		fmt.Fprintf(w, "  %s  %s: %s\n", n.Time.Local().Format("15:04:05"), n.Sender, n.Message)
+	}
+
+	if r.Role == string(session.RolePolecat) || r.Role == string(session.RoleCrew) {
+		fmt.Fprintf(w, "\n%s\n", style.Bold.Render("Work"))
+		switch {
+		case r.HookError != "":
+			fmt.Fprintf(w, "  hook: unknown (%s)\n", r.HookError)
+		case r.HookBead == "":
+			fmt.Fprintln(w, "  hook: (empty)")
+		default:
+			fmt.Fprintf(w, "  hook: %s [%s] %s\n", r.HookBead, r.HookStatus, r.HookTitle)
+		}
+		if r.Branch != "" {
+			fmt.Fprintf(w, "  branch: %s\n", r.Branch)
+		}
+		if r.GitError != "" {
+			fmt.Fprintf(w, "  git: %s\n", r.GitError)
+		} else if r.WorkDir != "" {
+			fmt.Fprintf(w, "  git: %d unpushed commit(s), %d uncommitted file(s)\n", r.UnpushedCommits, r.UncommittedFiles)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%s\n", style.Bold.Render("Probable causes"))
+	if len(r.Causes) == 0 {
+		fmt.Fprintf(w, "  %s nothing looks wrong\n", style.SuccessPrefix)
+	}
+	for _, c := range r.Causes {
+		fmt.Fprintf(w, "  %s %s\n", style.WarningPrefix, c.Summary)
+		if c.Detail != "" {
+			fmt.Fprintf(w, "      %s\n", c.Detail)
+		}
+		if c.Fix != "" {
+			fmt.Fprintf(w, "      → %s\n", c.Fix)
+		}
+	}
+}
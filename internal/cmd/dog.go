@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/dog"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/plugin"
@@ -763,12 +764,14 @@ func showPackStatus(mgr *dog.Manager) error {
 			Total     int    `json:"total"`
 			Idle      int    `json:"idle"`
 			Working   int    `json:"working"`
+			MaxSize   int    `json:"max_size"`
 			KennelDir string `json:"kennel_dir"`
 		}
 
 		townRoot, _ := workspace.FindFromCwd()
 		status := PackStatus{
 			Total:     len(dogs),
+			MaxSize:   daemon.DogPoolMaxSize(daemon.LoadPatrolConfig(townRoot)),
 			KennelDir: filepath.Join(townRoot, "deacon", "dogs"),
 		}
 		for _, d := range dogs {
@@ -804,7 +807,8 @@ func showPackStatus(mgr *dog.Manager) error {
 		}
 	}
 
-	fmt.Printf("  Total:   %d\n", len(dogs))
+	townRoot, _ := workspace.FindFromCwd()
+	fmt.Printf("  Total:   %d/%d\n", len(dogs), daemon.DogPoolMaxSize(daemon.LoadPatrolConfig(townRoot)))
 	fmt.Printf("  Idle:    %d\n", idleCount)
 	fmt.Printf("  Working: %d\n", workingCount)
 
@@ -984,8 +988,8 @@ func runDogDispatch(cmd *cobra.Command, args []string) error {
 
 		if targetDog == nil {
 			if dogDispatchCreate {
-				// Create a new dog (reuse generateDogName from sling_dog.go)
-				newName := generateDogName(mgr)
+				// Create a new dog with the next free pool name
+				newName := mgr.NextName()
 				if dogDispatchDryRun {
 					targetDog = &dog.Dog{Name: newName, State: dog.StateIdle}
 					dogCreated = true
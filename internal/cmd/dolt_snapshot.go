@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltSnapshotJSON   bool
+	doltSnapshotRemote string
+	doltSnapshotYes    bool
+)
+
+var doltSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Point-in-time database snapshots (backup branches)",
+	Long: `Manage point-in-time snapshots of Dolt databases.
+
+A snapshot is a backup/<YYYYMMDD-HHMMSS> branch of a database, keeping its
+data and schema exactly as committed. The daemon takes one per dolt_backup
+tick when the patrol runs in snapshot mode, pushes it to a Dolt remote
+(DoltHub or file://), and keeps the newest snapshot_retention of them.
+
+Configure in mayor/daemon.json:
+  "dolt_backup": {"enabled": true, "mode": "snapshot",
+                  "snapshot_remote": "backup", "snapshot_retention": 96}
+
+"mode": "both" also keeps running dolt backup sync. Snapshots can replace
+or complement the JSONL export (jsonl_git_backup).`,
+	RunE: requireSubcommand,
+}
+
+var doltSnapshotListCmd = &cobra.Command{
+	Use:   "list [db...]",
+	Short: "List snapshots, newest first",
+	Long: `List the snapshot branches of each database (default: all databases).
+
+Examples:
+  gt dolt snapshot list
+  gt dolt snapshot list gastown --json`,
+	RunE: runDoltSnapshotList,
+}
+
+var doltSnapshotCreateCmd = &cobra.Command{
+	Use:   "create [db...]",
+	Short: "Take a snapshot now",
+	Long: `Commit pending changes and branch a snapshot of each database (default:
+all databases), pushing it to the snapshot remote. A database unchanged since
+its newest snapshot is skipped.
+
+The remote defaults to patrols.dolt_backup.snapshot_remote in
+mayor/daemon.json.
+
+Examples:
+  gt dolt snapshot create
+  gt dolt snapshot create gastown --remote backup`,
+	RunE: runDoltSnapshotCreate,
+}
+
+var doltSnapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <db> <snapshot>",
+	Short: "Reset a database to a snapshot",
+	Long: `Reset a database's main branch to a snapshot, restoring its data and
+schema as of that point in time.
+
+The current state is snapshotted first, so a restore can be undone by
+restoring that snapshot. Stop agents writing to the database first.
+
+Examples:
+  gt dolt snapshot restore gastown backup/20260101-120000`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDoltSnapshotRestore,
+}
+
+func init() {
+	doltSnapshotListCmd.Flags().BoolVar(&doltSnapshotJSON, "json", false, "Output as JSON")
+	doltSnapshotCreateCmd.Flags().StringVar(&doltSnapshotRemote, "remote", "", "Dolt remote to push snapshots to (default: snapshot_remote)")
+	doltSnapshotRestoreCmd.Flags().BoolVarP(&doltSnapshotYes, "yes", "y", false, "Skip confirmation")
+
+	doltSnapshotCmd.AddCommand(doltSnapshotListCmd)
+	doltSnapshotCmd.AddCommand(doltSnapshotCreateCmd)
+	doltSnapshotCmd.AddCommand(doltSnapshotRestoreCmd)
+	doltCmd.AddCommand(doltSnapshotCmd)
+}
+
+// snapshotDatabases returns the databases named in args, or all databases.
+func snapshotDatabases(townRoot string, args []string) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+	databases, err := doltserver.ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	return databases, nil
+}
+
+func runDoltSnapshotList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	databases, err := snapshotDatabases(townRoot, args)
+	if err != nil {
+		return err
+	}
+
+	all := make(map[string][]doltserver.Snapshot)
+	for _, db := range databases {
+		snapshots, err := doltserver.ListSnapshots(townRoot, db)
+		if err != nil {
+			return fmt.Errorf("%s: %w", db, err)
+		}
+		all[db] = snapshots
+	}
+
+	if doltSnapshotJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(all)
+	}
+	for _, db := range databases {
+		snapshots := all[db]
+		fmt.Printf("%s %s\n", style.Bold.Render(db), style.Dim.Render(fmt.Sprintf("(%d snapshots)", len(snapshots))))
+		for _, s := range snapshots {
+			hash := s.Hash
+			if len(hash) > 8 {
+				hash = hash[:8]
+			}
+			fmt.Printf("  %s  %s  %s\n", s.Branch, style.Dim.Render(hash), style.Dim.Render(formatAge(s.Time)))
+		}
+	}
+	return nil
+}
+
+func runDoltSnapshotCreate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	databases, err := snapshotDatabases(townRoot, args)
+	if err != nil {
+		return err
+	}
+	remote := doltSnapshotRemote
+	if remote == "" {
+		if cfg := daemon.LoadPatrolConfig(townRoot); cfg != nil && cfg.Patrols != nil && cfg.Patrols.DoltBackup != nil {
+			remote = cfg.Patrols.DoltBackup.SnapshotRemote
+		}
+	}
+
+	now := time.Now()
+	var failed int
+	for _, db := range databases {
+		branch, created, err := doltserver.CreateSnapshot(townRoot, db, remote, now)
+		switch {
+		case err != nil:
+			failed++
+			fmt.Printf("%s %s: %v\n", style.ErrorPrefix, db, err)
+		case created:
+			fmt.Printf("%s %s: %s\n", style.SuccessPrefix, db, branch)
+		default:
+			fmt.Printf("  %s: %s\n", db, style.Dim.Render("unchanged since "+branch))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d snapshot(s) failed", failed, len(databases))
+	}
+	return nil
+}
+
+func runDoltSnapshotRestore(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	db, branch := args[0], args[1]
+
+	if !doltSnapshotYes && !promptYesNo(fmt.Sprintf("Reset %s to %s?", db, branch)) {
+		fmt.Println("Aborted.")
+		return nil
+	}
+	safety, err := doltserver.RestoreSnapshot(townRoot, db, branch)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s reset to %s\n", style.SuccessPrefix, db, branch)
+	fmt.Printf("  %s\n", style.Dim.Render("Previous state kept as "+safety))
+	return nil
+}
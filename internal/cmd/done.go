@@ -40,6 +40,15 @@ This is a convenience command for polecats that:
 3. Notifies the Witness with the exit outcome
 4. Exits the Claude session (polecats don't stay alive after completion)
 
+If the rig sets push_policy in settings/config.json, the branch's commits
+are checked first (file size, forbidden files like .env, conventional
+commit messages). Violations are listed and nothing else happens, so you
+can rewrite the branch and run gt done again.
+
+If the beads database is unreachable, gt done queues itself in the town's
+outbox (see gt outbox) and returns; the daemon finishes it, ending the
+session, once the database is back.
+
 Exit statuses:
   COMPLETED      - Work done, MR submitted (default)
   ESCALATED      - Hit blocker, needs human intervention
@@ -276,6 +285,18 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	// Push policy: check before anything changes state (and before the
+	// deferred session kill is armed), so a rejected agent keeps its session
+	// and can rewrite its branch and re-run gt done.
+	if rigName != "" {
+		g.SetPushPolicy(rigPushPolicy(filepath.Join(townRoot, rigName)))
+	}
+	if exitType == ExitCompleted && cwdAvailable {
+		if err := g.CheckPushPolicy(g.PushPolicy(), "origin", branch); err != nil {
+			return err
+		}
+	}
+
 	// Auto-detect cleanup status if not explicitly provided
 	// This prevents premature polecat cleanup by ensuring witness knows git state
 	if doneCleanupStatus == "" {
@@ -293,6 +314,10 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 					doneCleanupStatus = "uncommitted"
 				case workStatus.StashCount > 0:
 					doneCleanupStatus = "stash"
+				case workStatus.UnpushedSubmoduleCommits > 0:
+					// Submodule commits live only in this worktree's
+					// submodule repos until the refinery pushes them.
+					doneCleanupStatus = "unpushed"
 				default:
 					// CheckUncommittedWork.UnpushedCommits doesn't work for branches
 					// without upstream tracking (common for polecats). Use the more
@@ -311,6 +336,22 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	// Beads unreachable (Dolt restarting, network blip): everything from
+	// here on records state in beads, so queue the rest of gt done in the
+	// outbox instead of half-completing. The session stays up; the replay
+	// finishes the work and ends it. Work that would be lost is caught now,
+	// while the agent can still commit it.
+	if reachErr := beadsUnreachable(townRoot); reachErr != nil {
+		if exitType == ExitCompleted && doneCleanupStatus == "uncommitted" {
+			return fmt.Errorf("cannot complete: uncommitted changes would be lost\nCommit your changes first, or use --status DEFERRED to exit without completing")
+		}
+		if err := queueForReplay(townRoot, cwd, doneReplayArgs(exitType), reachErr); err != nil {
+			return err
+		}
+		fmt.Printf("  Your work is saved. Stop here: the session ends when the queued gt done runs.\n")
+		return nil
+	}
+
 	// Parse branch info
 	info := parseBranchName(branch)
 
@@ -1021,6 +1062,24 @@ notifyWitness:
 //
 // Follows the existing idle:N / backoff-until:TIMESTAMP label pattern.
 // Non-fatal: if this fails, gt done continues without the safety net.
+// rigPushPolicy returns the rig's push policy (config.PushPolicyConfig), or
+// nil if it has none.
+func rigPushPolicy(rigPath string) *git.PushPolicy {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.PushPolicy == nil {
+		return nil
+	}
+	forbidden := settings.PushPolicy.ForbiddenPaths
+	if forbidden == nil {
+		forbidden = git.DefaultForbiddenPaths
+	}
+	return &git.PushPolicy{
+		MaxFileSize:         settings.PushPolicy.MaxFileSizeKB * 1024,
+		ForbiddenPaths:      forbidden,
+		ConventionalCommits: settings.PushPolicy.ConventionalCommits,
+	}
+}
+
 func setDoneIntentLabel(bd *beads.Beads, agentBeadID, exitType string) {
 	if agentBeadID == "" {
 		return
@@ -1454,3 +1513,19 @@ func selfKillSession(townRoot string, roleInfo RoleInfo) error {
 
 	return nil
 }
+
+// doneReplayArgs returns the gt arguments that replay this gt done from
+// the outbox, with the cleanup status already observed.
+func doneReplayArgs(exitType string) []string {
+	args := []string{"done", "--status", exitType}
+	if doneCleanupStatus != "" {
+		args = append(args, "--cleanup-status", doneCleanupStatus)
+	}
+	if doneIssue != "" {
+		args = append(args, "--issue", doneIssue)
+	}
+	if donePriority >= 0 {
+		args = append(args, "--priority", strconv.Itoa(donePriority))
+	}
+	return args
+}
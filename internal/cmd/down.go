@@ -93,7 +93,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 
 	t := tmux.NewTmux()
 	if !t.IsAvailable() {
-		return fmt.Errorf("tmux not available (is tmux installed and on PATH?)")
+		return fmt.Errorf("%s not available (is %s installed and on PATH?)", t.Name(), t.Name())
 	}
 
 	// Phase 0: Acquire shutdown lock (skip for dry-run)
@@ -159,7 +159,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 			}
 			continue
 		}
-		wasRunning, err := stopSession(t, sessionName)
+		wasRunning, err := stopSession(context.Background(), t, sessionName)
 		if err != nil {
 			printDownStatus(fmt.Sprintf("Refinery (%s)", rigName), false, err.Error())
 			allOK = false
@@ -179,7 +179,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 			}
 			continue
 		}
-		wasRunning, err := stopSession(t, sessionName)
+		wasRunning, err := stopSession(context.Background(), t, sessionName)
 		if err != nil {
 			printDownStatus(fmt.Sprintf("Witness (%s)", rigName), false, err.Error())
 			allOK = false
@@ -411,7 +411,12 @@ func printDownStatus(name string, ok bool, detail string) {
 
 // stopSession gracefully stops a tmux session.
 // Returns (wasRunning, error) - wasRunning is true if session existed and was stopped.
-func stopSession(t *tmux.Tmux, sessionName string) (bool, error) {
+// If ctx ends first, stopSession gives up and returns ctx.Err(), leaving the
+// session as it is.
+func stopSession(ctx context.Context, t *tmux.Tmux, sessionName string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
 	running, err := t.HasSession(sessionName)
 	if err != nil {
 		return false, err
@@ -423,15 +428,34 @@ func stopSession(t *tmux.Tmux, sessionName string) (bool, error) {
 	// Try graceful shutdown first (Ctrl-C, best-effort interrupt)
 	if !downForce {
 		_ = t.SendKeysRaw(sessionName, "C-c")
-		if session.WaitForSessionExit(t, sessionName, constants.GracefulShutdownTimeout) {
+		if waitForSessionExit(ctx, t, sessionName, constants.GracefulShutdownTimeout) {
 			return true, nil // Process exited gracefully
 		}
+		if err := ctx.Err(); err != nil {
+			return true, err
+		}
 	}
 
 	// Kill the session (with explicit process termination to prevent orphans)
 	return true, t.KillSessionWithProcesses(sessionName)
 }
 
+// waitForSessionExit is session.WaitForSessionExit, cut short when ctx ends.
+func waitForSessionExit(ctx context.Context, t *tmux.Tmux, sessionName string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if running, err := t.HasSession(sessionName); err != nil || !running {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(constants.PollInterval):
+		}
+	}
+	return false
+}
+
 // acquireShutdownLock prevents concurrent shutdowns.
 // Returns the lock (caller must defer Unlock()) or error if lock held.
 func acquireShutdownLock(townRoot string) (*flock.Flock, error) {
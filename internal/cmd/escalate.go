@@ -17,6 +17,11 @@ var (
 	escalateDryRun      bool
 	escalateCloseReason string
 	escalateStdin       bool // Read reason from stdin
+
+	escalateContext      []string // name=value attachments
+	escalateContextFiles []string // name=path attachments
+	escalateCapture      string   // session whose output tail to attach
+	escalateCaptureLines int
 )
 
 var escalateCmd = &cobra.Command{
@@ -49,10 +54,19 @@ CONFIGURATION:
   - stale_threshold: When unacked escalations are re-escalated (default: 4h)
   - max_reescalations: How many times to bump severity (default: 2)
 
+CONTEXT:
+  Attach structured context instead of packing it into the description.
+  It is stored with the escalation, shown by 'gt escalate show', and
+  included in the mail to each recipient:
+  --context name=value        a named value (repeatable)
+  --context-file name=path    a file's contents, last 200 lines (repeatable)
+  --capture-session <session> the session name and its output tail
+
 Examples:
   gt escalate "Build failing" --severity critical --reason "CI blocked"
   gt escalate "Need API credentials" --severity high --source "plugin:rebuild-gt"
   gt escalate "Code review requested" --reason "PR #123 ready"
+  gt escalate "Toast stuck" --capture-session gt-gastown-Toast --context polecat=gastown/Toast
   gt escalate list                          # Show open escalations
   gt escalate ack hq-abc123                 # Acknowledge
   gt escalate close hq-abc123 --reason "Fixed in commit abc"
@@ -145,6 +159,10 @@ func init() {
 	escalateCmd.Flags().BoolVar(&escalateJSON, "json", false, "Output as JSON")
 	escalateCmd.Flags().BoolVarP(&escalateDryRun, "dry-run", "n", false, "Show what would be done without executing")
 	escalateCmd.Flags().BoolVar(&escalateStdin, "stdin", false, "Read reason from stdin (avoids shell quoting issues)")
+	escalateCmd.Flags().StringArrayVar(&escalateContext, "context", nil, "Attach context as name=value (repeatable)")
+	escalateCmd.Flags().StringArrayVar(&escalateContextFiles, "context-file", nil, "Attach a file's contents as name=path (repeatable)")
+	escalateCmd.Flags().StringVar(&escalateCapture, "capture-session", "", "Attach a tmux session's name and output tail")
+	escalateCmd.Flags().IntVar(&escalateCaptureLines, "capture-lines", 40, "Lines of output to capture with --capture-session")
 
 	// List subcommand flags
 	escalateListCmd.Flags().BoolVar(&escalateListJSON, "json", false, "Output as JSON")
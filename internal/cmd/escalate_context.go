@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+const (
+	// maxEscalationContextLines caps multi-line context values (file
+	// contents, captured output) to their last lines.
+	maxEscalationContextLines = 200
+
+	// maxEscalationContextBytes caps a single context value.
+	maxEscalationContextBytes = 16 * 1024
+)
+
+// buildEscalationContext assembles escalation attachments from the
+// --context name=value pairs, --context-file name=path files, and, if
+// session is set, that session's captured output tail.
+func buildEscalationContext(pairs, files []string, session string, lines int, capture func(session string, lines int) (string, error)) ([]beads.EscalationAttachment, error) {
+	var out []beads.EscalationAttachment
+	for _, p := range pairs {
+		name, value, err := parseContextPair(p, "--context")
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, beads.EscalationAttachment{Name: name, Value: value})
+	}
+	for _, f := range files {
+		name, path, err := parseContextPair(f, "--context-file")
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is supplied by the caller
+		if err != nil {
+			return nil, fmt.Errorf("--context-file %s: %w", name, err)
+		}
+		out = append(out, beads.EscalationAttachment{Name: name, Value: tailContext(string(data), maxEscalationContextLines)})
+	}
+	if session != "" {
+		out = append(out, beads.EscalationAttachment{Name: "session", Value: session})
+		captured, err := capture(session, lines)
+		if err != nil {
+			return nil, fmt.Errorf("capturing %s: %w", session, err)
+		}
+		out = append(out, beads.EscalationAttachment{Name: "output", Value: tailContext(captured, lines)})
+	}
+	return out, nil
+}
+
+// parseContextPair splits a name=value flag value.
+func parseContextPair(s, flag string) (name, value string, err error) {
+	name, value, ok := strings.Cut(s, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("%s %q: expected name=value", flag, s)
+	}
+	return name, value, nil
+}
+
+// tailContext trims trailing blank lines and keeps the last maxLines lines,
+// within maxEscalationContextBytes.
+func tailContext(s string, maxLines int) string {
+	lines := strings.Split(strings.TrimRight(s, " \t\n"), "\n")
+	if maxLines > 0 && len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+	out := strings.Join(lines, "\n")
+	if len(out) > maxEscalationContextBytes {
+		out = out[len(out)-maxEscalationContextBytes:]
+	}
+	return out
+}
+
+// renderEscalationContext formats attachments for mail and gt escalate show:
+// single-line values as "name: value", multi-line values as indented blocks.
+func renderEscalationContext(attachments []beads.EscalationAttachment) []string {
+	if len(attachments) == 0 {
+		return nil
+	}
+	lines := []string{"Context:"}
+	var blocks []beads.EscalationAttachment
+	for _, a := range attachments {
+		if strings.Contains(a.Value, "\n") {
+			blocks = append(blocks, a)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", a.Name, a.Value))
+	}
+	for _, a := range blocks {
+		lines = append(lines, "", fmt.Sprintf("%s:", a.Name))
+		for _, l := range strings.Split(a.Value, "\n") {
+			lines = append(lines, "  | "+l)
+		}
+	}
+	return lines
+}
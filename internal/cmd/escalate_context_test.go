@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestBuildEscalationContext(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "build.log")
+	if err := os.WriteFile(logPath, []byte("line1\nline2\n\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	capture := func(session string, lines int) (string, error) {
+		return fmt.Sprintf("a\nb\nc\n%s/%d\n\n", session, lines), nil
+	}
+
+	got, err := buildEscalationContext(
+		[]string{"polecat=gastown/Toast", "query=a=b"},
+		[]string{"log=" + logPath},
+		"gt-gastown-Toast", 2, capture)
+	if err != nil {
+		t.Fatalf("buildEscalationContext: %v", err)
+	}
+	want := []beads.EscalationAttachment{
+		{Name: "polecat", Value: "gastown/Toast"},
+		{Name: "query", Value: "a=b"},
+		{Name: "log", Value: "line1\nline2"},
+		{Name: "session", Value: "gt-gastown-Toast"},
+		{Name: "output", Value: "c\ngt-gastown-Toast/2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildEscalationContext =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if _, err := buildEscalationContext([]string{"novalue"}, nil, "", 0, capture); err == nil {
+		t.Error("expected an error for a --context without '='")
+	}
+}
+
+func TestRenderEscalationContext(t *testing.T) {
+	if lines := renderEscalationContext(nil); lines != nil {
+		t.Errorf("renderEscalationContext(nil) = %v, want nil", lines)
+	}
+	got := strings.Join(renderEscalationContext([]beads.EscalationAttachment{
+		{Name: "output", Value: "x\ny"},
+		{Name: "session", Value: "gt-gastown-Toast"},
+	}), "\n")
+	want := "Context:\n  session: gt-gastown-Toast\n\noutput:\n  | x\n  | y"
+	if got != want {
+		t.Errorf("renderEscalationContext =\n%s\nwant\n%s", got, want)
+	}
+}
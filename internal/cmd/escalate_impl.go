@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -55,6 +56,12 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("loading escalation config: %w", err)
 	}
 
+	attachments, err := buildEscalationContext(escalateContext, escalateContextFiles,
+		escalateCapture, escalateCaptureLines, tmux.NewTmux().CapturePane)
+	if err != nil {
+		return err
+	}
+
 	// Detect agent identity
 	agentID := detectSender()
 	if agentID == "" {
@@ -74,6 +81,9 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		if escalateSource != "" {
 			fmt.Printf("  Source: %s\n", escalateSource)
 		}
+		for _, a := range attachments {
+			fmt.Printf("  Context %s: %d line(s)\n", a.Name, strings.Count(a.Value, "\n")+1)
+		}
 		fmt.Printf("  Actions: %s\n", strings.Join(actions, ", "))
 		fmt.Printf("  Mail targets: %s\n", strings.Join(targets, ", "))
 		return nil
@@ -88,6 +98,7 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		EscalatedBy: agentID,
 		EscalatedAt: time.Now().Format(time.RFC3339),
 		RelatedBead: escalateRelatedBead,
+		Context:     attachments,
 	}
 
 	issue, err := bd.CreateEscalationBead(description, fields)
@@ -107,7 +118,7 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 			From:    agentID,
 			To:      target,
 			Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
-			Body:    formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
+			Body:    formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead, attachments),
 			Type:    mail.TypeTask,
 		}
 
@@ -138,6 +149,13 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	if escalateSource != "" {
 		payload["source"] = escalateSource
 	}
+	if len(attachments) > 0 {
+		names := make([]string, 0, len(attachments))
+		for _, a := range attachments {
+			names = append(names, a.Name)
+		}
+		payload["context"] = names
+	}
 	_ = events.LogFeed(events.TypeEscalationSent, agentID, payload)
 
 	// Output
@@ -238,6 +256,10 @@ func runEscalateAck(cmd *cobra.Command, args []string) error {
 		ackedBy = "unknown"
 	}
 
+	if reachErr := beadsUnreachable(townRoot); reachErr != nil {
+		return queueForReplay(townRoot, townRoot, []string{"escalate", "ack", escalationID}, reachErr)
+	}
+
 	bd := beads.New(beads.ResolveBeadsDir(townRoot))
 	if err := bd.AckEscalation(escalationID, ackedBy); err != nil {
 		return fmt.Errorf("acknowledging escalation: %w", err)
@@ -267,6 +289,10 @@ func runEscalateClose(cmd *cobra.Command, args []string) error {
 		closedBy = "unknown"
 	}
 
+	if reachErr := beadsUnreachable(townRoot); reachErr != nil {
+		return queueForReplay(townRoot, townRoot, []string{"escalate", "close", escalationID, "--reason", escalateCloseReason}, reachErr)
+	}
+
 	bd := beads.New(beads.ResolveBeadsDir(townRoot))
 	if err := bd.CloseEscalation(escalationID, closedBy, escalateCloseReason); err != nil {
 		return fmt.Errorf("closing escalation: %w", err)
@@ -505,6 +531,7 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 			"closedBy":    fields.ClosedBy,
 			"closedReason": fields.ClosedReason,
 			"relatedBead": fields.RelatedBead,
+			"context":     fields.Context,
 		}
 		out, _ := json.MarshalIndent(data, "", "  ")
 		fmt.Println(string(out))
@@ -531,6 +558,12 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 	if fields.RelatedBead != "" {
 		fmt.Printf("  Related: %s\n", fields.RelatedBead)
 	}
+	if lines := renderEscalationContext(fields.Context); len(lines) > 0 {
+		fmt.Println()
+		for _, line := range lines {
+			fmt.Printf("  %s\n", line)
+		}
+	}
 
 	return nil
 }
@@ -555,7 +588,7 @@ func extractMailTargetsFromActions(actions []string) []string {
 
 // executeExternalActions processes external notification actions (email:, sms:, slack).
 // For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
+func executeExternalActions(actions []string, cfg *config.EscalationConfig, beadID, severity, description string) {
 	for _, action := range actions {
 		switch {
 		case strings.HasPrefix(action, "email:"):
@@ -582,6 +615,16 @@ func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _
 				fmt.Printf("  💬 Would post to Slack (not yet implemented)\n")
 			}
 
+		case action == "popup":
+			// Best effort: the operator may not be attached.
+			summary := fmt.Sprintf("%s %s escalation %s\n\n%s\n\nSee: gt escalate show %s",
+				severityEmoji(severity), strings.ToUpper(severity), beadID, description, beadID)
+			if err := tmux.NewTmux().ShowPopup(tmux.Popup{Title: "Escalation"}, summary); err != nil {
+				fmt.Printf("  %s Popup not shown: %v\n", style.Dim.Render("○"), err)
+			} else {
+				fmt.Printf("  🪟 Shown in attached tmux client\n")
+			}
+
 		case action == "log":
 			// Log action always succeeds - writes to escalation log file
 			// TODO: Implement actual log file writing
@@ -590,7 +633,7 @@ func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _
 	}
 }
 
-func formatEscalationMailBody(beadID, severity, reason, from, related string) string {
+func formatEscalationMailBody(beadID, severity, reason, from, related string, attachments []beads.EscalationAttachment) string {
 	var lines []string
 	lines = append(lines, fmt.Sprintf("Escalation ID: %s", beadID))
 	lines = append(lines, fmt.Sprintf("Severity: %s", severity))
@@ -604,6 +647,10 @@ func formatEscalationMailBody(beadID, severity, reason, from, related string) st
 		lines = append(lines, "")
 		lines = append(lines, fmt.Sprintf("Related: %s", related))
 	}
+	if context := renderEscalationContext(attachments); len(context) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, context...)
+	}
 	lines = append(lines, "")
 	lines = append(lines, "---")
 	lines = append(lines, "To acknowledge: gt escalate ack "+beadID)
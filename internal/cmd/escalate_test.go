@@ -240,7 +240,7 @@ func TestFormatEscalationMailBody(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := formatEscalationMailBody(tt.beadID, tt.severity, tt.reason, tt.from, tt.related)
+			got := formatEscalationMailBody(tt.beadID, tt.severity, tt.reason, tt.from, tt.related, nil)
 			for _, s := range tt.wantIn {
 				if !strings.Contains(got, s) {
 					t.Errorf("missing %q in output:\n%s", s, got)
@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// explainStallAfter is how long a working polecat can go without session
+// output before explain calls it possibly stalled.
+const explainStallAfter = 15 * time.Minute
+
+// explainEventLimit is how many recent events explain shows.
+const explainEventLimit = 5
+
+var explainJSON bool
+
+var explainCmd = &cobra.Command{
+	Use:     "explain <issue-id | rig/polecat>",
+	GroupID: GroupDiag,
+	Short:   "Explain why an issue or polecat is in its current state",
+	Long: `Explain why an issue or polecat is in its current state.
+
+Gathers the state that decides what happens next - assignment, session
+health, scheduler queue position and capacity, blocking dependencies and
+recent events - and prints it as a plain-language explanation, e.g.:
+
+  gt-abc12 is queued: town at capacity (3/3 polecats busy), next in line
+
+A target containing a slash is a polecat address; anything else is tried
+as an issue ID first, then as a polecat name in the current rig.
+
+Examples:
+  gt explain gt-abc12             # Why is this issue not being worked?
+  gt explain gastown/furiosa      # What is this polecat doing?
+  gt explain gt-abc12 --json      # Facts and reasons as JSON`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExplain,
+}
+
+func init() {
+	explainCmd.Flags().BoolVar(&explainJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(explainCmd)
+}
+
+// explainFacts is the state explain reasons about. Gathering it touches
+// beads, tmux and the scheduler; explaining it is pure.
+type explainFacts struct {
+	Now time.Time
+
+	// Issue is the issue being explained, or the polecat's hooked issue.
+	Issue *beads.Issue
+
+	// Polecat and its session, when the subject is (or is assigned to) a polecat.
+	PolecatAddr string
+	Polecat     *polecat.Polecat
+	Session     *polecat.SessionInfo
+
+	// Scheduler state for the issue.
+	Scheduled        bool
+	QueuePosition    int // 1-based among dispatchable beads; 0 = not dispatchable yet
+	QueueLength      int
+	DispatchFailures int
+	SchedulerPaused  bool
+	PausedBy         string
+	MaxPolecats      int // <= 0: direct dispatch, no capacity limit
+	ActivePolecats   int
+
+	Events []events.Event
+}
+
+// explanation is what explain prints.
+type explanation struct {
+	Subject string         `json:"subject"`
+	State   string         `json:"state"`
+	Reasons []string       `json:"reasons"`
+	Events  []events.Event `json:"events,omitempty"`
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	target := args[0]
+	facts := explainFacts{Now: time.Now()}
+	var ex explanation
+
+	if !strings.Contains(target, "/") {
+		if issue, err := beads.New(resolveBeadDir(target)).Show(target); err == nil {
+			facts.Issue = issue
+			gatherIssueFacts(townRoot, &facts)
+			facts.Events = recentEvents(townRoot, issue.ID, explainEventLimit)
+			ex = explainIssue(facts)
+		}
+	}
+	if ex.Subject == "" {
+		rigName, polecatName, err := parseAddress(target)
+		if err != nil {
+			return fmt.Errorf("%q is neither an issue nor a polecat address (rig/polecat)", target)
+		}
+		if err := gatherPolecatFacts(rigName, polecatName, &facts); err != nil {
+			return err
+		}
+		facts.Events = recentEvents(townRoot, facts.PolecatAddr, explainEventLimit)
+		ex = explainPolecat(facts)
+	}
+
+	if explainJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(ex)
+	}
+	printExplanation(ex)
+	return nil
+}
+
+// gatherIssueFacts fills in the assignee's session and the scheduler state
+// for facts.Issue. Missing state is left zero rather than failing.
+func gatherIssueFacts(townRoot string, facts *explainFacts) {
+	issue := facts.Issue
+	if issue.Assignee != "" {
+		if id, err := session.ParseAddress(issue.Assignee); err == nil && id.Role == session.RolePolecat {
+			_ = gatherPolecatFacts(id.Rig, id.Name, facts)
+		}
+		return
+	}
+
+	contexts, err := listAllSlingContexts(townRoot)
+	if err != nil {
+		return
+	}
+	for _, ctx := range contexts {
+		if fields := beads.ParseSlingContextFields(ctx.Description); fields != nil && fields.WorkBeadID == issue.ID {
+			facts.Scheduled = true
+			facts.DispatchFailures = max(facts.DispatchFailures, fields.DispatchFailures)
+		}
+	}
+	if !facts.Scheduled {
+		return
+	}
+
+	if pending, err := getReadySlingContexts(townRoot); err == nil {
+		facts.QueueLength = len(pending)
+		for i, b := range pending {
+			if b.WorkBeadID == issue.ID {
+				facts.QueuePosition = i + 1
+				break
+			}
+		}
+	}
+	if state, err := capacity.LoadState(townRoot); err == nil {
+		facts.SchedulerPaused = state.Paused
+		facts.PausedBy = state.PausedBy
+	}
+	schedulerCfg := capacity.DefaultSchedulerConfig()
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && settings.Scheduler != nil {
+		schedulerCfg = settings.Scheduler
+	}
+	facts.MaxPolecats = schedulerCfg.GetMaxPolecats()
+	facts.ActivePolecats = countActivePolecats()
+}
+
+// gatherPolecatFacts loads a polecat, its session and its hooked issue.
+func gatherPolecatFacts(rigName, polecatName string, facts *explainFacts) error {
+	mgr, r, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	p, err := mgr.Get(polecatName)
+	if err != nil {
+		return fmt.Errorf("polecat '%s' not found in rig '%s'", polecatName, rigName)
+	}
+	facts.PolecatAddr = rigName + "/" + polecatName
+	facts.Polecat = p
+
+	sessInfo, err := polecat.NewSessionManager(tmux.NewTmux(), r).Status(polecatName)
+	if err != nil {
+		sessInfo = &polecat.SessionInfo{Polecat: polecatName}
+	}
+	facts.Session = sessInfo
+
+	if p.Issue != "" && facts.Issue == nil {
+		if issue, err := beads.New(resolveBeadDir(p.Issue)).Show(p.Issue); err == nil {
+			facts.Issue = issue
+		}
+	}
+	return nil
+}
+
+// explainIssue turns an issue's facts into a causal explanation.
+func explainIssue(f explainFacts) explanation {
+	issue := f.Issue
+	ex := explanation{Subject: issue.ID, State: issue.Status, Events: f.Events}
+	add := func(format string, a ...interface{}) {
+		ex.Reasons = append(ex.Reasons, fmt.Sprintf(format, a...))
+	}
+
+	switch issue.Status {
+	case "closed", "tombstone":
+		if issue.ClosedAt != "" {
+			add("closed at %s; nothing will pick it up again unless it is reopened", issue.ClosedAt)
+		} else {
+			add("closed; nothing will pick it up again unless it is reopened")
+		}
+		return ex
+	case "deferred":
+		add("deferred; the scheduler and gt sling skip it until its status changes")
+		return ex
+	}
+
+	if blockers := openBlockers(issue); len(blockers) > 0 {
+		add("blocked by %s; it can't be dispatched until they close", strings.Join(blockers, ", "))
+	}
+
+	if issue.Assignee != "" {
+		if f.Polecat != nil {
+			ex.Reasons = append(ex.Reasons, polecatReasons(f)...)
+		} else {
+			add("assigned to %s", issue.Assignee)
+		}
+		return ex
+	}
+
+	if !f.Scheduled {
+		if len(ex.Reasons) == 0 {
+			add("open and unassigned: nothing has slung it yet (gt sling %s <rig>)", issue.ID)
+		}
+		return ex
+	}
+
+	switch {
+	case f.DispatchFailures >= maxDispatchFailures:
+		add("scheduled, but dispatch failed %d times so the scheduler gave up on it (gt scheduler clear --bead %s, then sling again)",
+			f.DispatchFailures, issue.ID)
+	case f.SchedulerPaused:
+		add("queued, but the scheduler is paused (by %s); gt scheduler resume", f.PausedBy)
+	case f.QueuePosition == 0:
+		if len(ex.Reasons) == 0 {
+			add("scheduled, but not ready to dispatch yet (bd ready doesn't list it)")
+		}
+	case f.MaxPolecats > 0 && f.ActivePolecats >= f.MaxPolecats:
+		add("queued because town at capacity (%d/%d polecats busy), %s",
+			f.ActivePolecats, f.MaxPolecats, queuePlace(f.QueuePosition, f.QueueLength))
+	default:
+		add("queued with free capacity, %s; dispatches on the next scheduler run", queuePlace(f.QueuePosition, f.QueueLength))
+	}
+	if f.DispatchFailures > 0 && f.DispatchFailures < maxDispatchFailures {
+		add("%d earlier dispatch attempt(s) failed; the scheduler gives up after %d", f.DispatchFailures, maxDispatchFailures)
+	}
+	return ex
+}
+
+// explainPolecat turns a polecat's facts into a causal explanation.
+func explainPolecat(f explainFacts) explanation {
+	return explanation{
+		Subject: f.PolecatAddr,
+		State:   string(f.Polecat.State),
+		Reasons: polecatReasons(f),
+		Events:  f.Events,
+	}
+}
+
+// polecatReasons explains a polecat's state from its session health.
+func polecatReasons(f explainFacts) []string {
+	p, sess := f.Polecat, f.Session
+	running := sess != nil && sess.Running
+	work := "its work"
+	if p.Issue != "" {
+		work = p.Issue
+	}
+
+	var reasons []string
+	switch p.State {
+	case polecat.StateWorking:
+		switch {
+		case !running:
+			reasons = append(reasons, fmt.Sprintf("%s is assigned %s but its session is not running; the witness restarts dead sessions on its next patrol", f.PolecatAddr, work))
+		case !sess.LastActivity.IsZero() && f.Now.Sub(sess.LastActivity) > explainStallAfter:
+			reasons = append(reasons, fmt.Sprintf("%s is working on %s but has produced no output for %s; it may be stalled (gt polecat status %s)",
+				f.PolecatAddr, work, f.Now.Sub(sess.LastActivity).Round(time.Minute), f.PolecatAddr))
+		default:
+			reasons = append(reasons, fmt.Sprintf("%s is working on %s; its session is alive", f.PolecatAddr, work))
+		}
+	case polecat.StateDone:
+		if running {
+			reasons = append(reasons, fmt.Sprintf("%s finished %s but its session is still running; cleanup may have failed (gt witness cleanup --dry-run)", f.PolecatAddr, work))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("%s finished %s and is waiting for the witness to clean it up", f.PolecatAddr, work))
+		}
+	case polecat.StateIdle:
+		reasons = append(reasons, fmt.Sprintf("%s is idle: its last work is finished and the sandbox is kept for reuse by the next gt sling", f.PolecatAddr))
+	case polecat.StateStandby:
+		if running {
+			reasons = append(reasons, fmt.Sprintf("%s is on warm standby: its session is waiting at an idle prompt for the next gt sling", f.PolecatAddr))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("%s is marked standby but its session is not running; gt polecat standby maintain returns it to idle", f.PolecatAddr))
+		}
+	case polecat.StateStuck:
+		reasons = append(reasons, fmt.Sprintf("%s asked for help with %s and is waiting for a response", f.PolecatAddr, work))
+	case polecat.StateZombie:
+		reasons = append(reasons, fmt.Sprintf("%s has a session but no worktree; it was incompletely nuked", f.PolecatAddr))
+	default:
+		reasons = append(reasons, fmt.Sprintf("%s is in state %q", f.PolecatAddr, p.State))
+	}
+
+	if issue := f.Issue; issue != nil && p.Issue == issue.ID {
+		if blockers := openBlockers(issue); len(blockers) > 0 {
+			reasons = append(reasons, fmt.Sprintf("%s is blocked by %s", issue.ID, strings.Join(blockers, ", ")))
+		}
+	}
+	return reasons
+}
+
+// openBlockers lists an issue's unclosed blocking dependencies.
+func openBlockers(issue *beads.Issue) []string {
+	var out []string
+	for _, dep := range issue.Dependencies {
+		if dep.DependencyType != "" && dep.DependencyType != "blocks" {
+			continue
+		}
+		if dep.Status != "closed" && dep.Status != "tombstone" {
+			out = append(out, fmt.Sprintf("%s (%s)", dep.ID, dep.Status))
+		}
+	}
+	return out
+}
+
+func queuePlace(pos, length int) string {
+	if pos == 1 {
+		return "next in line"
+	}
+	return fmt.Sprintf("position %d of %d", pos, length)
+}
+
+// recentEvents returns the last limit events in the town event log that
+// mention needle.
+func recentEvents(townRoot, needle string, limit int) []events.Event {
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var out []events.Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if !strings.Contains(string(line), needle) {
+			continue
+		}
+		var e events.Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			continue
+		}
+		out = append(out, e)
+		if len(out) > limit {
+			out = out[1:]
+		}
+	}
+	return out
+}
+
+func printExplanation(ex explanation) {
+	fmt.Printf("%s %s\n\n", style.Bold.Render(ex.Subject), style.Dim.Render("("+ex.State+")"))
+	for _, r := range ex.Reasons {
+		fmt.Printf("  • %s\n", r)
+	}
+	if len(ex.Events) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Recent events"))
+		for _, e := range ex.Events {
+			fmt.Printf("  %s  %-22s %s\n", style.Dim.Render(e.Timestamp), e.Type, e.Actor)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/polecat"
+)
+
+func TestExplainIssue(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	open := func() *beads.Issue { return &beads.Issue{ID: "gt-abc", Status: "open"} }
+
+	tests := []struct {
+		name  string
+		facts explainFacts
+		want  string
+	}{
+		{"closed", explainFacts{Issue: &beads.Issue{ID: "gt-abc", Status: "closed"}}, "closed"},
+		{"unslung", explainFacts{Issue: open()}, "nothing has slung it"},
+		{"at capacity", explainFacts{Issue: open(), Scheduled: true, QueuePosition: 1, QueueLength: 2, MaxPolecats: 3, ActivePolecats: 3},
+			"queued because town at capacity (3/3 polecats busy), next in line"},
+		{"free capacity", explainFacts{Issue: open(), Scheduled: true, QueuePosition: 2, QueueLength: 4, MaxPolecats: 5, ActivePolecats: 1},
+			"position 2 of 4"},
+		{"paused", explainFacts{Issue: open(), Scheduled: true, QueuePosition: 1, SchedulerPaused: true, PausedBy: "mayor"},
+			"scheduler is paused (by mayor)"},
+		{"circuit broken", explainFacts{Issue: open(), Scheduled: true, DispatchFailures: maxDispatchFailures}, "gave up"},
+		{"blocked", explainFacts{Issue: &beads.Issue{ID: "gt-abc", Status: "open", Dependencies: []beads.IssueDep{
+			{ID: "gt-dep", Status: "in_progress", DependencyType: "blocks"},
+			{ID: "gt-done", Status: "closed", DependencyType: "blocks"},
+			{ID: "gt-epic", Status: "open", DependencyType: "parent-child"},
+		}}}, "blocked by gt-dep (in_progress);"},
+		{"dead session", explainFacts{Now: now,
+			Issue:       &beads.Issue{ID: "gt-abc", Status: "hooked", Assignee: "gastown/polecats/toast"},
+			PolecatAddr: "gastown/toast",
+			Polecat:     &polecat.Polecat{Name: "toast", State: polecat.StateWorking, Issue: "gt-abc"},
+			Session:     &polecat.SessionInfo{Running: false},
+		}, "session is not running"},
+		{"stalled", explainFacts{Now: now,
+			Issue:       &beads.Issue{ID: "gt-abc", Status: "hooked", Assignee: "gastown/polecats/toast"},
+			PolecatAddr: "gastown/toast",
+			Polecat:     &polecat.Polecat{Name: "toast", State: polecat.StateWorking, Issue: "gt-abc"},
+			Session:     &polecat.SessionInfo{Running: true, LastActivity: now.Add(-40 * time.Minute)},
+		}, "no output for 40m0s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ex := explainIssue(tt.facts)
+			got := strings.Join(ex.Reasons, "\n")
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("reasons = %q, want substring %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecentEvents(t *testing.T) {
+	town := t.TempDir()
+	log := `{"ts":"1","type":"sling","actor":"mayor","payload":{"bead":"gt-abc"}}
+{"ts":"2","type":"nudge","actor":"mayor","payload":{"target":"gt-other"}}
+{"ts":"3","type":"hook","actor":"gastown/toast","payload":{"bead":"gt-abc"}}
+{"ts":"4","type":"done","actor":"gastown/toast","payload":{"bead":"gt-abc"}}
+`
+	if err := os.WriteFile(filepath.Join(town, ".events.jsonl"), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got := recentEvents(town, "gt-abc", 2)
+	if len(got) != 2 || got[0].Timestamp != "3" || got[1].Timestamp != "4" {
+		t.Errorf("recentEvents() = %+v, want the last two gt-abc events", got)
+	}
+}
@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/export"
+	"github.com/steveyegge/gastown/internal/report"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	exportFormat string
+	exportSince  string
+	exportOutput string
+)
+
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	GroupID: GroupDiag,
+	Short:   "Export town data for analysis",
+	RunE:    requireSubcommand,
+}
+
+var exportMetricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Export issue, merge and cost records as CSV or Parquet",
+	Long: `Export raw records for analysis in spreadsheets or notebooks.
+
+Writes one file per table into the output directory:
+
+  issues.<ext>   Issue lifecycle from beads: created, closed, status,
+                 assignee and cycle time (created to closed, in hours).
+                 Issues closed before --since are left out.
+  merges.<ext>   Merge queue outcomes from the events log (merged,
+                 merge_failed, merge_skipped) with MR, worker and branch.
+  costs.<ext>    Session cost records from the cost ledger and daily
+                 cost digests.
+
+Parquet files have one typed column per field (timestamps are UTC
+milliseconds); CSV times are RFC 3339.
+
+Examples:
+  gt export metrics                                  # CSV, last 30 days
+  gt export metrics --since 2026-01-01 -o ./data
+  gt export metrics --format parquet -o ./data`,
+	Args: cobra.NoArgs,
+	RunE: runExportMetrics,
+}
+
+func init() {
+	exportMetricsCmd.Flags().StringVar(&exportFormat, "format", "csv", "Output format: csv or parquet")
+	exportMetricsCmd.Flags().StringVar(&exportSince, "since", "", "Export records from a date (YYYY-MM-DD); default 30 days ago")
+	exportMetricsCmd.Flags().StringVarP(&exportOutput, "output", "o", ".", "Directory to write the files to")
+	exportCmd.AddCommand(exportMetricsCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExportMetrics(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	write := export.WriteCSV
+	switch exportFormat {
+	case "csv":
+	case "parquet":
+		write = export.WriteParquet
+	default:
+		return fmt.Errorf("invalid --format %q: must be csv or parquet", exportFormat)
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -30)
+	if exportSince != "" {
+		since, err = time.ParseInLocation("2006-01-02", exportSince, now.Location())
+		if err != nil {
+			return fmt.Errorf("invalid --since %q: want YYYY-MM-DD", exportSince)
+		}
+	}
+	period := report.Period{Start: since, End: now}
+
+	rigs := discoverRigs(townRoot)
+	evts, err := readReportEvents(townRoot, period)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s reading events: %v\n", style.WarningPrefix, err)
+	}
+	tables := []*export.Table{
+		issueTable(exportIssues(townRoot, rigs), since),
+		mergeTable(evts, rigs),
+		costTable(exportCosts(period)),
+	}
+
+	if err := os.MkdirAll(exportOutput, 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	for _, t := range tables {
+		path := filepath.Join(exportOutput, t.Name+"."+exportFormat)
+		f, err := os.Create(path) //nolint:gosec // G304: path is the user's output directory
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", path, err)
+		}
+		err = write(f, t)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+		fmt.Printf("%s Wrote %d %s to %s\n", style.SuccessPrefix, len(t.Rows), t.Name, path)
+	}
+	return nil
+}
+
+// rigIssue is a work item and the rig ("" for town HQ) it lives in.
+type rigIssue struct {
+	Rig   string
+	Issue *beads.Issue
+}
+
+// exportIssues lists work items of every status from every rig and HQ.
+func exportIssues(townRoot string, rigs []string) []rigIssue {
+	var out []rigIssue
+	for _, rigName := range append([]string{""}, rigs...) { // "" is the town-level (HQ) database
+		issues, err := beads.New(filepath.Join(townRoot, rigName)).List(beads.ListOptions{Status: "all", Priority: -1})
+		if err != nil {
+			continue
+		}
+		for _, issue := range issues {
+			if beads.IsWorkItem(issue) {
+				out = append(out, rigIssue{Rig: rigName, Issue: issue})
+			}
+		}
+	}
+	return out
+}
+
+// issueTable builds the issue lifecycle table, leaving out issues closed
+// before since.
+func issueTable(issues []rigIssue, since time.Time) *export.Table {
+	t := export.NewTable("issues",
+		export.Column{Name: "rig", Type: export.String},
+		export.Column{Name: "id", Type: export.String},
+		export.Column{Name: "title", Type: export.String},
+		export.Column{Name: "type", Type: export.String},
+		export.Column{Name: "priority", Type: export.Int64},
+		export.Column{Name: "status", Type: export.String},
+		export.Column{Name: "assignee", Type: export.String},
+		export.Column{Name: "created_at", Type: export.Time},
+		export.Column{Name: "closed_at", Type: export.Time},
+		export.Column{Name: "cycle_hours", Type: export.Float64},
+	)
+	for _, ri := range issues {
+		issue := ri.Issue
+		rig := ri.Rig
+		if rig == "" {
+			rig = report.TownRig
+		}
+		var created, closed, cycle any
+		createdAt, createdErr := time.Parse(time.RFC3339, issue.CreatedAt)
+		if createdErr == nil {
+			created = createdAt
+		}
+		if closedAt, err := time.Parse(time.RFC3339, issue.ClosedAt); err == nil {
+			if closedAt.Before(since) {
+				continue
+			}
+			closed = closedAt
+			if createdErr == nil {
+				cycle = closedAt.Sub(createdAt).Hours()
+			}
+		}
+		t.Append(rig, issue.ID, issue.Title, issue.Type, int64(issue.Priority), issue.Status,
+			issue.Assignee, created, closed, cycle)
+	}
+	return t
+}
+
+// mergeTable builds the merge outcome table from the events log.
+func mergeTable(evts []events.Event, rigs []string) *export.Table {
+	t := export.NewTable("merges",
+		export.Column{Name: "time", Type: export.Time},
+		export.Column{Name: "rig", Type: export.String},
+		export.Column{Name: "outcome", Type: export.String},
+		export.Column{Name: "mr", Type: export.String},
+		export.Column{Name: "worker", Type: export.String},
+		export.Column{Name: "branch", Type: export.String},
+		export.Column{Name: "reason", Type: export.String},
+	)
+	for _, e := range evts {
+		switch e.Type {
+		case events.TypeMerged, events.TypeMergeFailed, events.TypeMergeSkipped:
+		default:
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			continue
+		}
+		payload := func(key string) any {
+			if s, ok := e.Payload[key].(string); ok && s != "" {
+				return s
+			}
+			return nil
+		}
+		t.Append(ts, report.EventRig(e, rigs), e.Type,
+			payload("mr"), payload("worker"), payload("branch"), payload("reason"))
+	}
+	return t
+}
+
+// exportCosts collects cost records for the period: daily digests for
+// days already digested, and the raw ledger for the rest.
+func exportCosts(period report.Period) []CostEntry {
+	entries, err := queryDigestBeads(period.Days())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s reading cost digests: %v\n", style.WarningPrefix, err)
+	}
+	ledger, err := readCostLedger(getCostsLogPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s reading cost ledger: %v\n", style.WarningPrefix, err)
+	}
+
+	var out []CostEntry
+	for _, e := range append(entries, ledger...) {
+		if period.Contains(e.EndedAt) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// readCostLedger reads every entry in the cost ledger. A missing ledger
+// has no entries.
+func readCostLedger(path string) ([]CostEntry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is the fixed cost ledger location
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []CostEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e CostLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		out = append(out, CostEntry{
+			SessionID: e.SessionID,
+			Role:      e.Role,
+			Rig:       e.Rig,
+			Worker:    e.Worker,
+			CostUSD:   e.CostUSD,
+			EndedAt:   e.EndedAt,
+			WorkItem:  e.WorkItem,
+		})
+	}
+	return out, scanner.Err()
+}
+
+// costTable builds the cost record table.
+func costTable(entries []CostEntry) *export.Table {
+	t := export.NewTable("costs",
+		export.Column{Name: "ended_at", Type: export.Time},
+		export.Column{Name: "rig", Type: export.String},
+		export.Column{Name: "role", Type: export.String},
+		export.Column{Name: "worker", Type: export.String},
+		export.Column{Name: "session_id", Type: export.String},
+		export.Column{Name: "work_item", Type: export.String},
+		export.Column{Name: "cost_usd", Type: export.Float64},
+	)
+	optional := func(s string) any {
+		if s == "" {
+			return nil
+		}
+		return s
+	}
+	for _, e := range entries {
+		t.Append(e.EndedAt, optional(e.Rig), optional(e.Role), optional(e.Worker),
+			e.SessionID, optional(e.WorkItem), e.CostUSD)
+	}
+	return t
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestIssueTable(t *testing.T) {
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	issues := []rigIssue{
+		{Rig: "gastown", Issue: &beads.Issue{ID: "gt-a", Status: "closed", Priority: 1,
+			CreatedAt: "2026-03-02T00:00:00Z", ClosedAt: "2026-03-02T06:00:00Z"}},
+		{Rig: "gastown", Issue: &beads.Issue{ID: "gt-old", Status: "closed",
+			CreatedAt: "2026-01-01T00:00:00Z", ClosedAt: "2026-02-01T00:00:00Z"}},
+		{Rig: "", Issue: &beads.Issue{ID: "hq-b", Status: "open", CreatedAt: "2026-01-01T00:00:00Z"}},
+	}
+	tbl := issueTable(issues, since)
+	if err := tbl.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(tbl.Rows) != 2 {
+		t.Fatalf("got %d rows, want 2 (issue closed before --since dropped)", len(tbl.Rows))
+	}
+	if cycle := tbl.Rows[0][9]; cycle != 6.0 {
+		t.Errorf("gt-a cycle_hours = %v, want 6", cycle)
+	}
+	if rig, closed := tbl.Rows[1][0], tbl.Rows[1][8]; rig != "town" || closed != nil {
+		t.Errorf("hq-b rig = %v, closed_at = %v; want town, nil", rig, closed)
+	}
+}
+
+func TestMergeTable(t *testing.T) {
+	evts := []events.Event{
+		{Timestamp: "2026-03-02T10:00:00Z", Type: events.TypeMerged, Actor: "gastown/refinery",
+			Payload: events.MergePayload("gt-mr1", "toast", "polecat/toast", "")},
+		{Timestamp: "2026-03-02T11:00:00Z", Type: events.TypeSling, Actor: "mayor"},
+		{Timestamp: "2026-03-02T12:00:00Z", Type: events.TypeMergeFailed, Actor: "gastown/refinery",
+			Payload: events.MergePayload("gt-mr2", "nux", "polecat/nux", "conflict")},
+	}
+	tbl := mergeTable(evts, []string{"gastown"})
+	if err := tbl.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(tbl.Rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(tbl.Rows))
+	}
+	if row := tbl.Rows[1]; row[1] != "gastown" || row[2] != events.TypeMergeFailed || row[6] != "conflict" {
+		t.Errorf("merge_failed row = %v", row)
+	}
+	if reason := tbl.Rows[0][6]; reason != nil {
+		t.Errorf("merged row reason = %v, want nil", reason)
+	}
+}
+
+func TestReadCostLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "costs.jsonl")
+	ledger := `{"session_id":"s1","role":"polecat","rig":"gastown","worker":"toast","cost_usd":1.25,"ended_at":"2026-03-02T10:00:00Z"}
+garbage
+`
+	if err := os.WriteFile(path, []byte(ledger), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := readCostLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].CostUSD != 1.25 || entries[0].Worker != "toast" {
+		t.Errorf("readCostLedger() = %+v", entries)
+	}
+	if err := costTable(entries).Validate(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/failure"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	failuresSince  time.Duration
+	failuresRig    string
+	failuresClass  string
+	failuresRecent int
+	failuresJSON   bool
+)
+
+var failuresCmd = &cobra.Command{
+	Use:     "failures",
+	GroupID: GroupDiag,
+	Short:   "Report why agent sessions died",
+	Long: `Report classified session failures.
+
+When an agent session dies, its last output and exit status are classified:
+
+  binary_not_found  agent command missing     → fix environment (held)
+  auth_required     login needed / key bad    → fix environment (held)
+  environment       rig preflight failed      → fix environment (held)
+  rate_limited      provider rate limit       → wait, then retry
+  crashed           stack trace / fatal error → retry
+  killed            stopped by a signal       → retry
+  clean_exit        exited normally           → retry
+  unknown           anything else             → retry
+
+A session failing the same way ` + fmt.Sprint(failure.EscalateAfter) + ` times within an hour is escalated
+(held for a human) instead of retried. Failures to start a polecat at dispatch
+are classified the same way and recorded on the sling context.
+
+Examples:
+  gt failures                      # Last 24h, by class and rig
+  gt failures --since 168h --rig gastown
+  gt failures --class auth_required -n 20
+  gt failures --json`,
+	Args: cobra.NoArgs,
+	RunE: runFailures,
+}
+
+func init() {
+	failuresCmd.Flags().DurationVar(&failuresSince, "since", 24*time.Hour, "Report failures from this long ago")
+	failuresCmd.Flags().StringVar(&failuresRig, "rig", "", "Only failures in this rig")
+	failuresCmd.Flags().StringVar(&failuresClass, "class", "", "Only failures of this class")
+	failuresCmd.Flags().IntVarP(&failuresRecent, "recent", "n", 10, "Recent failures to list (0 = none)")
+	failuresCmd.Flags().BoolVar(&failuresJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(failuresCmd)
+}
+
+func runFailures(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	records, err := failure.Read(townRoot, time.Now().Add(-failuresSince))
+	if err != nil {
+		return fmt.Errorf("reading failures: %w", err)
+	}
+	records = filterFailures(records, failuresRig, failure.Class(failuresClass))
+	summary := failure.Summarize(records, failureRig)
+
+	if failuresJSON {
+		out := struct {
+			failure.Summary
+			Recent []failure.Record `json:"recent"`
+		}{summary, recentFailures(records, failuresRecent)}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if summary.Total == 0 {
+		fmt.Printf("%s No session failures in the last %s\n", style.SuccessPrefix, failuresSince)
+		return nil
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("%d session failure(s) in the last %s", summary.Total, failuresSince)))
+	for _, c := range failure.Classes {
+		if n := summary.ByClass[c]; n > 0 {
+			fmt.Printf("  %-18s %4d  %s\n", c, n, style.Dim.Render(string(c.Recovery())))
+		}
+	}
+
+	rigs := make([]string, 0, len(summary.ByRig))
+	for rig := range summary.ByRig {
+		rigs = append(rigs, rig)
+	}
+	sort.Strings(rigs)
+	fmt.Printf("\n%s\n", style.Bold.Render("By rig"))
+	for _, rig := range rigs {
+		var parts []string
+		for _, c := range failure.Classes {
+			if n := summary.ByRig[rig][c]; n > 0 {
+				parts = append(parts, fmt.Sprintf("%s %d", c, n))
+			}
+		}
+		name := rig
+		if name == "" {
+			name = "(town)"
+		}
+		fmt.Printf("  %-18s %s\n", name, strings.Join(parts, ", "))
+	}
+
+	if recent := recentFailures(records, failuresRecent); len(recent) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Recent"))
+		for i := len(recent) - 1; i >= 0; i-- {
+			r := recent[i]
+			who := r.Session
+			if who == "" {
+				who = r.Agent
+			}
+			line := fmt.Sprintf("  %s  %-24s %s", r.Time.Local().Format("01-02 15:04"), who, r.Reason())
+			if r.Issue != "" {
+				line += "  " + style.Dim.Render(r.Issue)
+			}
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
+
+// failureRig returns the rig a failure belongs to: the first element of
+// its agent address, else the rig in its session name.
+func failureRig(r failure.Record) string {
+	if rig, _, ok := strings.Cut(r.Agent, "/"); ok {
+		return rig
+	}
+	if id, err := session.ParseSessionName(r.Session); err == nil {
+		return id.Rig
+	}
+	return ""
+}
+
+func filterFailures(records []failure.Record, rig string, class failure.Class) []failure.Record {
+	if rig == "" && class == "" {
+		return records
+	}
+	var out []failure.Record
+	for _, r := range records {
+		if rig != "" && failureRig(r) != rig {
+			continue
+		}
+		if class != "" && r.Class != class {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// recentFailures returns the last n records (oldest first).
+func recentFailures(records []failure.Record, n int) []failure.Record {
+	if n <= 0 {
+		return nil
+	}
+	if len(records) > n {
+		return records[len(records)-n:]
+	}
+	return records
+}
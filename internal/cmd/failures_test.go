@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/failure"
+)
+
+func TestFailureRig(t *testing.T) {
+	tests := []struct {
+		r    failure.Record
+		want string
+	}{
+		{failure.Record{Agent: "gastown/Toast", Session: "gt-Toast"}, "gastown"},
+		{failure.Record{Agent: "beads/polecats"}, "beads"},
+		{failure.Record{}, ""},
+	}
+	for _, tt := range tests {
+		if got := failureRig(tt.r); got != tt.want {
+			t.Errorf("failureRig(%+v) = %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestFilterFailures(t *testing.T) {
+	records := []failure.Record{
+		{Agent: "gastown/Toast", Classification: failure.Classification{Class: failure.ClassCrashed}},
+		{Agent: "gastown/Nux", Classification: failure.Classification{Class: failure.ClassAuthRequired}},
+		{Agent: "beads/Ace", Classification: failure.Classification{Class: failure.ClassCrashed}},
+	}
+	if got := filterFailures(records, "gastown", ""); len(got) != 2 {
+		t.Errorf("rig filter: %d records, want 2", len(got))
+	}
+	if got := filterFailures(records, "", failure.ClassCrashed); len(got) != 2 {
+		t.Errorf("class filter: %d records, want 2", len(got))
+	}
+	if got := filterFailures(records, "gastown", failure.ClassCrashed); len(got) != 1 || got[0].Agent != "gastown/Toast" {
+		t.Errorf("both filters: %+v", got)
+	}
+	if got := recentFailures(records, 2); len(got) != 2 || got[1].Agent != "beads/Ace" {
+		t.Errorf("recentFailures = %+v", got)
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/federation"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	federationStatusJSON bool
+	federationTimeout    time.Duration
+)
+
+var federationCmd = &cobra.Command{
+	Use:     "federation",
+	GroupID: GroupDiag,
+	Short:   "Fleet-level view across multiple towns",
+	Long: `Aggregate read-only status from several Gas Towns into one view.
+
+The town you run this from acts as the "capital". Member towns are listed in
+mayor/federation.json and must be running their dashboard (gt dashboard),
+which serves a compact summary at /api/federation/summary.
+
+Examples:
+  gt federation add buildbox http://buildbox:8080
+  gt federation list
+  gt federation status
+  gt federation status --json
+  gt federation remove buildbox`,
+	RunE: requireSubcommand,
+}
+
+var federationStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show utilization, escalations, and backups across member towns",
+	Args:  cobra.NoArgs,
+	RunE:  runFederationStatus,
+}
+
+var federationAddCmd = &cobra.Command{
+	Use:   "add <name> <url>",
+	Short: "Add or update a member town",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runFederationAdd,
+}
+
+var federationRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a member town",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runFederationRemove,
+}
+
+var federationListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List member towns",
+	Args:  cobra.NoArgs,
+	RunE:  runFederationList,
+}
+
+func init() {
+	federationStatusCmd.Flags().BoolVar(&federationStatusJSON, "json", false, "Output as JSON")
+	federationStatusCmd.Flags().DurationVar(&federationTimeout, "timeout", federation.DefaultFetchTimeout, "Per-town fetch timeout")
+
+	federationCmd.AddCommand(federationStatusCmd)
+	federationCmd.AddCommand(federationAddCmd)
+	federationCmd.AddCommand(federationRemoveCmd)
+	federationCmd.AddCommand(federationListCmd)
+	rootCmd.AddCommand(federationCmd)
+}
+
+func runFederationStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := federation.LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Members) == 0 {
+		return fmt.Errorf("no member towns configured (use 'gt federation add <name> <url>')")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*federationTimeout)
+	defer cancel()
+	fleet := federation.Collect(ctx, nil, cfg.Members)
+
+	if federationStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(fleet)
+	}
+
+	tbl := style.NewTable(
+		style.Column{Name: "TOWN", Width: 16},
+		style.Column{Name: "RIGS", Width: 5, Align: style.AlignRight},
+		style.Column{Name: "AGENTS", Width: 7, Align: style.AlignRight},
+		style.Column{Name: "BUSY", Width: 5, Align: style.AlignRight},
+		style.Column{Name: "UTIL", Width: 5, Align: style.AlignRight},
+		style.Column{Name: "ESC", Width: 4, Align: style.AlignRight},
+		style.Column{Name: "LAST BACKUP", Width: 14},
+		style.Column{Name: "NOTE", Width: 30},
+	)
+	for _, ms := range fleet.Members {
+		if ms.Summary == nil {
+			tbl.AddRow(ms.Member.Name, "-", "-", "-", "-", "-", "-", style.Error.Render("unreachable: "+ms.Error))
+			continue
+		}
+		tbl.AddRow(federationRow(ms.Member.Name, *ms.Summary)...)
+	}
+	tbl.AddRow(federationRow(style.Bold.Render("TOTAL"), fleet.Totals)...)
+
+	fmt.Printf("Federation (%d towns):\n\n", len(fleet.Members))
+	fmt.Print(tbl.Render())
+	if fleet.Unreachable > 0 {
+		fmt.Printf("\n%s %d town(s) unreachable\n", style.WarningPrefix, fleet.Unreachable)
+	}
+	return nil
+}
+
+// federationRow formats one summary as a table row.
+func federationRow(name string, s federation.TownSummary) []string {
+	backup := "never"
+	if !s.LastBackup.IsZero() {
+		backup = formatRelativeTime(s.LastBackup.Format(time.RFC3339))
+	}
+	esc := fmt.Sprintf("%d", s.OpenEscalations)
+	if s.OpenEscalations > 0 {
+		esc = style.Warning.Render(esc)
+	}
+	return []string{
+		name,
+		fmt.Sprintf("%d", s.Rigs),
+		fmt.Sprintf("%d", s.AgentsRunning),
+		fmt.Sprintf("%d", s.AgentsWorking),
+		fmt.Sprintf("%.0f%%", s.Utilization()*100),
+		esc,
+		backup,
+		"",
+	}
+}
+
+func runFederationAdd(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := federation.LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := cfg.AddMember(federation.Member{Name: args[0], URL: args[1]}); err != nil {
+		return err
+	}
+	if err := federation.SaveConfig(townRoot, cfg); err != nil {
+		return fmt.Errorf("saving federation config: %w", err)
+	}
+	fmt.Printf("%s Added %s (%s)\n", style.SuccessPrefix, args[0], args[1])
+	return nil
+}
+
+func runFederationRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := federation.LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if !cfg.RemoveMember(args[0]) {
+		return fmt.Errorf("member %q not found", args[0])
+	}
+	if err := federation.SaveConfig(townRoot, cfg); err != nil {
+		return fmt.Errorf("saving federation config: %w", err)
+	}
+	fmt.Printf("%s Removed %s\n", style.SuccessPrefix, args[0])
+	return nil
+}
+
+func runFederationList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := federation.LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Members) == 0 {
+		fmt.Println("No member towns configured")
+		return nil
+	}
+	for _, m := range cfg.Members {
+		fmt.Printf("  %s  %s\n", style.Bold.Render(m.Name), style.Dim.Render(m.URL))
+	}
+	return nil
+}
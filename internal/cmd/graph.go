@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	graphFormat    string
+	graphOutput    string
+	graphAll       bool
+	graphNoMRs     bool
+	graphDirection string
+)
+
+var graphCmd = &cobra.Command{
+	Use:     "graph <epic|rig>",
+	GroupID: GroupWork,
+	Short:   "Export the issue dependency graph (DOT, Mermaid, JSON)",
+	Long: `Export an issue dependency graph for visualization.
+
+Given an epic, the graph covers the epic and all its descendants. Given a
+rig, it covers the rig's open work items (add --all for closed ones too).
+
+The graph includes:
+  - Blocking dependencies (blocks, conditional-blocks, waits-for) as solid edges
+  - Parent-child hierarchy as dotted edges
+  - Open merge requests, linked to the issue they implement
+  - The epic's integration branch, linked to the MRs that target it
+
+Nodes are colored by status. The critical path — the longest chain of
+unfinished blocking dependencies — is highlighted, since it bounds how
+soon the work can finish no matter how many polecats are slung at it.
+
+Formats:
+  dot       Graphviz (render with: dot -Tsvg graph.dot -o graph.svg)
+  mermaid   Mermaid flowchart (paste into markdown)
+  json      Nodes, edges, and critical path for tooling
+
+Examples:
+  gt graph gt-epic-abc
+  gt graph gt-epic-abc --format mermaid
+  gt graph gastown --format dot -o gastown.dot
+  gt graph gastown --all --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGraph,
+}
+
+func init() {
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "f", "dot", "Output format: dot, mermaid, or json")
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "", "Write the graph to a file instead of stdout")
+	graphCmd.Flags().BoolVar(&graphAll, "all", false, "Include closed issues (rig graphs only)")
+	graphCmd.Flags().BoolVar(&graphNoMRs, "no-mrs", false, "Omit merge requests and integration branches")
+	graphCmd.Flags().StringVar(&graphDirection, "direction", "LR", "Layout direction: LR (left to right) or TB (top to bottom)")
+	rootCmd.AddCommand(graphCmd)
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	switch graphFormat {
+	case "dot", "mermaid", "json":
+	default:
+		return fmt.Errorf("invalid --format %q: must be dot, mermaid, or json", graphFormat)
+	}
+	if graphDirection != "LR" && graphDirection != "TB" {
+		return fmt.Errorf("invalid --direction %q: must be LR or TB", graphDirection)
+	}
+	if _, err := workspace.FindFromCwdOrError(); err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	target := args[0]
+	var (
+		g   *depGraph
+		err error
+	)
+	if rigName, ok := IsRigName(target); ok {
+		g, err = collectRigGraph(rigName)
+	} else {
+		g, err = collectEpicGraph(target)
+	}
+	if err != nil {
+		return err
+	}
+
+	if cycle := detectCycles(g.dag); cycle != nil {
+		fmt.Fprintf(os.Stderr, "%s Dependency cycle: %v (no critical path)\n", style.WarningPrefix, cycle)
+	} else {
+		g.CriticalPath = criticalPath(g.dag)
+	}
+
+	var out string
+	switch graphFormat {
+	case "mermaid":
+		out = g.Mermaid(graphDirection)
+	case "json":
+		data, err := json.MarshalIndent(g.export(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding graph: %w", err)
+		}
+		out = string(data) + "\n"
+	default:
+		out = g.DOT(graphDirection)
+	}
+
+	if graphOutput == "" {
+		fmt.Print(out)
+		return nil
+	}
+	if err := os.WriteFile(graphOutput, []byte(out), 0644); err != nil { //nolint:gosec // G306: graph is not secret
+		return fmt.Errorf("writing graph: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "%s Wrote %d nodes to %s\n", style.SuccessPrefix, len(g.dag.Nodes)+len(g.MRs)+len(g.Branches), graphOutput)
+	return nil
+}
+
+// collectEpicGraph builds the graph for an epic and its descendants.
+func collectEpicGraph(epicID string) (*depGraph, error) {
+	beadList, deps, err := collectEpicBeads(epicID)
+	if err != nil {
+		return nil, err
+	}
+	g := &depGraph{Root: epicID, dag: buildConvoyDAG(beadList, deps)}
+	if graphNoMRs {
+		return g, nil
+	}
+
+	rigName := rigFromBeadID(epicID)
+	if rigName == "" {
+		return g, nil
+	}
+	branch := ""
+	if _, r, err := getRig(rigName); err == nil {
+		if epic, err := beads.New(r.BeadsPath()).Show(epicID); err == nil {
+			branch = resolveEpicBranch(epic, r.Path, nil)
+		}
+	}
+	g.addMRs(graphMRs(rigName), epicID, branch)
+	return g, nil
+}
+
+// collectRigGraph builds the graph for a rig's work items.
+func collectRigGraph(rigName string) (*depGraph, error) {
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return nil, err
+	}
+	status := "open"
+	if graphAll {
+		status = "all"
+	}
+	issues, err := beads.New(r.BeadsPath()).List(beads.ListOptions{Status: status, Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing issues in %s: %w", rigName, err)
+	}
+
+	var beadList []BeadInfo
+	var deps []DepInfo
+	for _, issue := range issues {
+		if issue.Type != "epic" && !beads.IsWorkItem(issue) {
+			continue
+		}
+		beadList = append(beadList, BeadInfo{ID: issue.ID, Title: issue.Title, Type: issue.Type, Status: issue.Status, Rig: rigName})
+		if issue.DependencyCount == 0 {
+			continue // spare a bd call per issue with nothing to fetch
+		}
+		issueDeps, err := bdDepList(issue.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", style.WarningPrefix, err)
+			continue
+		}
+		for _, d := range issueDeps {
+			deps = append(deps, DepInfo{IssueID: d.IssueID, DependsOnID: d.DependsOnID, Type: d.Type})
+		}
+	}
+
+	g := &depGraph{Root: rigName, dag: buildConvoyDAG(beadList, deps)}
+	if !graphNoMRs {
+		g.addMRs(graphMRs(rigName), "", "")
+	}
+	return g, nil
+}
+
+// graphMRs returns the rig's open merge requests, or nil if the queue
+// cannot be read.
+func graphMRs(rigName string) []*refinery.MergeRequest {
+	mgr, _, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return nil
+	}
+	items, err := mgr.Queue()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Reading merge queue: %v\n", style.WarningPrefix, err)
+		return nil
+	}
+	mrs := make([]*refinery.MergeRequest, 0, len(items))
+	for _, item := range items {
+		mrs = append(mrs, item.MR)
+	}
+	sort.Slice(mrs, func(i, j int) bool { return mrs[i].ID < mrs[j].ID })
+	return mrs
+}
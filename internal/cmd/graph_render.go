@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+// Graph edge kinds.
+const (
+	graphEdgeBlocks      = "blocks"      // From must finish before To can start
+	graphEdgeParent      = "parent"      // From is the parent of To
+	graphEdgeMR          = "mr"          // To is a merge request implementing From
+	graphEdgeTargets     = "targets"     // MR From merges into branch To
+	graphEdgeIntegration = "integration" // Branch To is epic From's integration branch
+)
+
+// graphStatusColors are node fill colors by issue status.
+var graphStatusColors = map[string]string{
+	"open":        "#ffffff",
+	"in_progress": "#fff2a8",
+	"hooked":      "#fff2a8",
+	"pinned":      "#fff2a8",
+	"blocked":     "#f4a6a6",
+	"deferred":    "#e0e0e0",
+	"closed":      "#c8e6c9",
+}
+
+const (
+	graphMRColor       = "#d6e4f5"
+	graphBranchColor   = "#e8dcf0"
+	graphCriticalColor = "#c62828"
+	graphTitleMax      = 40
+)
+
+// depGraph is an issue dependency graph with the merge requests and
+// integration branch attached to it, ready to render.
+type depGraph struct {
+	Root         string // epic ID or rig name
+	dag          *ConvoyDAG
+	MRs          []*refinery.MergeRequest
+	Branches     []graphBranch
+	CriticalPath []string // issue IDs, first to last
+}
+
+// graphBranch is an integration branch node.
+type graphBranch struct {
+	Name string
+	Epic string
+}
+
+// graphNode and graphEdge are the renderer-neutral form of the graph.
+type graphNode struct {
+	ID       string `json:"id"`
+	Label    string `json:"label"`
+	Title    string `json:"title,omitempty"`
+	Kind     string `json:"kind"` // "issue", "mr", or "branch"
+	Type     string `json:"type,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Rig      string `json:"rig,omitempty"`
+	Critical bool   `json:"critical,omitempty"`
+}
+
+type graphEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Kind     string `json:"kind"`
+	Critical bool   `json:"critical,omitempty"`
+}
+
+// graphExport is the JSON form of a graph.
+type graphExport struct {
+	Root         string      `json:"root"`
+	Nodes        []graphNode `json:"nodes"`
+	Edges        []graphEdge `json:"edges"`
+	CriticalPath []string    `json:"critical_path"`
+}
+
+// addMRs attaches merge requests for issues in the graph. If branch is
+// set, it is added as epicID's integration branch and MRs targeting it are
+// attached even if their issue is outside the graph.
+func (g *depGraph) addMRs(mrs []*refinery.MergeRequest, epicID, branch string) {
+	if branch != "" {
+		g.Branches = append(g.Branches, graphBranch{Name: branch, Epic: epicID})
+	}
+	for _, mr := range mrs {
+		_, inGraph := g.dag.Nodes[mr.IssueID]
+		if inGraph || (branch != "" && mr.TargetBranch == branch) {
+			g.MRs = append(g.MRs, mr)
+		}
+	}
+}
+
+// criticalPath returns the longest chain of unfinished issues linked by
+// blocking edges, or nil if no issue blocks another. The DAG must be
+// acyclic. Ties go to the lexically smallest IDs so output is stable.
+func criticalPath(dag *ConvoyDAG) []string {
+	open := func(id string) bool {
+		n := dag.Nodes[id]
+		return n != nil && n.Status != "closed" && n.Status != "tombstone"
+	}
+
+	longest := make(map[string][]string) // path starting at id
+	var walk func(id string) []string
+	walk = func(id string) []string {
+		if p, ok := longest[id]; ok {
+			return p
+		}
+		next := append([]string(nil), dag.Nodes[id].Blocks...)
+		sort.Strings(next)
+		var best []string
+		for _, n := range next {
+			if !open(n) {
+				continue
+			}
+			if p := walk(n); len(p) > len(best) {
+				best = p
+			}
+		}
+		p := append([]string{id}, best...)
+		longest[id] = p
+		return p
+	}
+
+	ids := make([]string, 0, len(dag.Nodes))
+	for id := range dag.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var best []string
+	for _, id := range ids {
+		if !open(id) {
+			continue
+		}
+		if p := walk(id); len(p) > len(best) {
+			best = p
+		}
+	}
+	if len(best) < 2 {
+		return nil
+	}
+	return best
+}
+
+// export flattens the graph into sorted nodes and edges.
+func (g *depGraph) export() graphExport {
+	critical := make(map[string]int, len(g.CriticalPath)) // id -> position
+	for i, id := range g.CriticalPath {
+		critical[id] = i
+	}
+	criticalEdge := func(from, to string) bool {
+		i, ok1 := critical[from]
+		j, ok2 := critical[to]
+		return ok1 && ok2 && j == i+1
+	}
+
+	ids := make([]string, 0, len(g.dag.Nodes))
+	for id := range g.dag.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	out := graphExport{Root: g.Root, CriticalPath: g.CriticalPath}
+	if out.CriticalPath == nil {
+		out.CriticalPath = []string{}
+	}
+	for _, id := range ids {
+		n := g.dag.Nodes[id]
+		_, isCritical := critical[id]
+		out.Nodes = append(out.Nodes, graphNode{
+			ID: id, Label: id, Title: n.Title, Kind: "issue",
+			Type: n.Type, Status: n.Status, Rig: n.Rig, Critical: isCritical,
+		})
+	}
+	for _, id := range ids {
+		n := g.dag.Nodes[id]
+		for _, to := range sortedCopy(n.Blocks) {
+			out.Edges = append(out.Edges, graphEdge{From: id, To: to, Kind: graphEdgeBlocks, Critical: criticalEdge(id, to)})
+		}
+		for _, to := range sortedCopy(n.Children) {
+			out.Edges = append(out.Edges, graphEdge{From: id, To: to, Kind: graphEdgeParent})
+		}
+	}
+
+	branchID := func(name string) string { return "branch:" + name }
+	branches := make(map[string]bool)
+	for _, b := range g.Branches {
+		branches[b.Name] = true
+		out.Nodes = append(out.Nodes, graphNode{ID: branchID(b.Name), Label: b.Name, Kind: "branch"})
+		if _, ok := g.dag.Nodes[b.Epic]; ok {
+			out.Edges = append(out.Edges, graphEdge{From: b.Epic, To: branchID(b.Name), Kind: graphEdgeIntegration})
+		}
+	}
+	for _, mr := range g.MRs {
+		out.Nodes = append(out.Nodes, graphNode{ID: mr.ID, Label: mr.ID, Title: mr.Branch, Kind: "mr", Status: string(mr.Status)})
+		if _, ok := g.dag.Nodes[mr.IssueID]; ok {
+			out.Edges = append(out.Edges, graphEdge{From: mr.IssueID, To: mr.ID, Kind: graphEdgeMR})
+		}
+		if branches[mr.TargetBranch] {
+			out.Edges = append(out.Edges, graphEdge{From: mr.ID, To: branchID(mr.TargetBranch), Kind: graphEdgeTargets})
+		}
+	}
+	return out
+}
+
+// DOT renders the graph in Graphviz format.
+func (g *depGraph) DOT(direction string) string {
+	ex := g.export()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %s {\n", dotQuote(g.Root))
+	fmt.Fprintf(&sb, "  rankdir=%s;\n", direction)
+	sb.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\", fontsize=10];\n")
+	sb.WriteString("  edge [fontname=\"Helvetica\", fontsize=9];\n\n")
+
+	for _, n := range ex.Nodes {
+		attrs := []string{"label=" + dotQuote(nodeLabel(n, "\n"))}
+		switch n.Kind {
+		case "mr":
+			attrs = append(attrs, "shape=note", "fillcolor=\""+graphMRColor+"\"")
+		case "branch":
+			attrs = append(attrs, "shape=cds", "fillcolor=\""+graphBranchColor+"\"")
+		default:
+			attrs = append(attrs, "fillcolor=\""+statusColor(n.Status)+"\"")
+			if n.Type == "epic" {
+				attrs = append(attrs, "shape=box3d")
+			}
+		}
+		if n.Critical {
+			attrs = append(attrs, "color=\""+graphCriticalColor+"\"", "penwidth=2.5")
+		}
+		fmt.Fprintf(&sb, "  %s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+	}
+	sb.WriteString("\n")
+
+	for _, e := range ex.Edges {
+		var attrs []string
+		switch e.Kind {
+		case graphEdgeParent:
+			attrs = append(attrs, "style=dotted", "arrowhead=none")
+		case graphEdgeMR, graphEdgeTargets, graphEdgeIntegration:
+			attrs = append(attrs, "style=dashed")
+		}
+		if e.Critical {
+			attrs = append(attrs, "color=\""+graphCriticalColor+"\"", "penwidth=2.5")
+		}
+		line := fmt.Sprintf("  %s -> %s", dotQuote(e.From), dotQuote(e.To))
+		if len(attrs) > 0 {
+			line += " [" + strings.Join(attrs, ", ") + "]"
+		}
+		sb.WriteString(line + ";\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g *depGraph) Mermaid(direction string) string {
+	ex := g.export()
+	ids := make(map[string]string, len(ex.Nodes)) // node ID -> mermaid-safe ID
+	for i, n := range ex.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "flowchart %s\n", direction)
+	for _, n := range ex.Nodes {
+		label := mermaidQuote(nodeLabel(n, "<br/>"))
+		switch {
+		case n.Kind == "mr":
+			fmt.Fprintf(&sb, "  %s>%s]\n", ids[n.ID], label)
+		case n.Kind == "branch":
+			fmt.Fprintf(&sb, "  %s{{%s}}\n", ids[n.ID], label)
+		case n.Type == "epic":
+			fmt.Fprintf(&sb, "  %s[[%s]]\n", ids[n.ID], label)
+		default:
+			fmt.Fprintf(&sb, "  %s[%s]\n", ids[n.ID], label)
+		}
+	}
+	for _, e := range ex.Edges {
+		arrow := "-->"
+		switch {
+		case e.Critical:
+			arrow = "==>"
+		case e.Kind == graphEdgeParent:
+			arrow = "-.-"
+		case e.Kind != graphEdgeBlocks:
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&sb, "  %s %s %s\n", ids[e.From], arrow, ids[e.To])
+	}
+	for _, n := range ex.Nodes {
+		fill := statusColor(n.Status)
+		switch n.Kind {
+		case "mr":
+			fill = graphMRColor
+		case "branch":
+			fill = graphBranchColor
+		}
+		style := "fill:" + fill
+		if n.Critical {
+			style += ",stroke:" + graphCriticalColor + ",stroke-width:3px"
+		}
+		fmt.Fprintf(&sb, "  style %s %s\n", ids[n.ID], style)
+	}
+	return sb.String()
+}
+
+// nodeLabel is the display label: ID, truncated title, and status.
+func nodeLabel(n graphNode, sep string) string {
+	parts := []string{n.Label}
+	if n.Title != "" && n.Kind != "branch" {
+		parts = append(parts, truncateGraphTitle(n.Title))
+	}
+	if n.Status != "" {
+		parts = append(parts, "("+n.Status+")")
+	}
+	return strings.Join(parts, sep)
+}
+
+func truncateGraphTitle(s string) string {
+	r := []rune(s)
+	if len(r) <= graphTitleMax {
+		return s
+	}
+	return string(r[:graphTitleMax-1]) + "…"
+}
+
+func statusColor(status string) string {
+	if c, ok := graphStatusColors[status]; ok {
+		return c
+	}
+	return graphStatusColors["open"]
+}
+
+// dotQuote quotes s as a DOT string. Newlines become DOT's \n escape.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// mermaidQuote quotes s as a Mermaid node label.
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+func sortedCopy(ss []string) []string {
+	out := append([]string(nil), ss...)
+	sort.Strings(out)
+	return out
+}
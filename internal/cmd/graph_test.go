@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+// testGraph is an epic with a chain a -> b -> c, a side task d blocked by
+// a, and a closed task x that blocks c.
+func testGraph() *depGraph {
+	dag := buildConvoyDAG(
+		[]BeadInfo{
+			{ID: "gt-epic", Title: "Epic", Type: "epic", Status: "open"},
+			{ID: "gt-a", Title: "First", Type: "task", Status: "closed"},
+			{ID: "gt-b", Title: `Second "quoted"`, Type: "task", Status: "in_progress"},
+			{ID: "gt-c", Title: "Third", Type: "task", Status: "open"},
+			{ID: "gt-d", Title: "Side", Type: "task", Status: "open"},
+			{ID: "gt-e", Title: "Fourth", Type: "task", Status: "open"},
+		},
+		[]DepInfo{
+			{IssueID: "gt-a", DependsOnID: "gt-epic", Type: "parent-child"},
+			{IssueID: "gt-b", DependsOnID: "gt-epic", Type: "parent-child"},
+			{IssueID: "gt-b", DependsOnID: "gt-a", Type: "blocks"},
+			{IssueID: "gt-c", DependsOnID: "gt-b", Type: "blocks"},
+			{IssueID: "gt-e", DependsOnID: "gt-c", Type: "blocks"},
+			{IssueID: "gt-d", DependsOnID: "gt-b", Type: "blocks"},
+		},
+	)
+	return &depGraph{Root: "gt-epic", dag: dag}
+}
+
+func TestCriticalPath(t *testing.T) {
+	g := testGraph()
+	got := criticalPath(g.dag)
+	want := []string{"gt-b", "gt-c", "gt-e"} // gt-a is closed
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("criticalPath = %v, want %v", got, want)
+	}
+
+	single := buildConvoyDAG([]BeadInfo{{ID: "gt-1", Status: "open"}}, nil)
+	if got := criticalPath(single); got != nil {
+		t.Errorf("criticalPath with no edges = %v, want nil", got)
+	}
+}
+
+func TestGraphAddMRs(t *testing.T) {
+	g := testGraph()
+	g.addMRs([]*refinery.MergeRequest{
+		{ID: "gt-mr1", IssueID: "gt-b", Branch: "polecat/Toast/gt-b", TargetBranch: "integration/epic"},
+		{ID: "gt-mr2", IssueID: "gt-zzz", Branch: "polecat/Nux/gt-zzz", TargetBranch: "integration/epic"},
+		{ID: "gt-mr3", IssueID: "gt-other", Branch: "polecat/Max/gt-other", TargetBranch: "main"},
+	}, "gt-epic", "integration/epic")
+
+	if len(g.MRs) != 2 {
+		t.Fatalf("expected 2 MRs attached, got %d", len(g.MRs))
+	}
+	ex := g.export()
+	var kinds []string
+	for _, e := range ex.Edges {
+		if e.Kind != graphEdgeBlocks && e.Kind != graphEdgeParent {
+			kinds = append(kinds, e.From+">"+e.To+":"+e.Kind)
+		}
+	}
+	want := []string{
+		"gt-epic>branch:integration/epic:integration",
+		"gt-b>gt-mr1:mr",
+		"gt-mr1>branch:integration/epic:targets",
+		"gt-mr2>branch:integration/epic:targets",
+	}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("MR edges = %v, want %v", kinds, want)
+	}
+}
+
+func TestGraphDOT(t *testing.T) {
+	g := testGraph()
+	g.CriticalPath = criticalPath(g.dag)
+	out := g.DOT("LR")
+
+	for _, want := range []string{
+		`digraph "gt-epic" {`,
+		"rankdir=LR;",
+		`"gt-b" [label="gt-b\nSecond \"quoted\"\n(in_progress)", fillcolor="#fff2a8", color="#c62828", penwidth=2.5];`,
+		`"gt-epic" [label="gt-epic\nEpic\n(open)", fillcolor="#ffffff", shape=box3d];`,
+		`"gt-a" -> "gt-b";`,
+		`"gt-b" -> "gt-c" [color="#c62828", penwidth=2.5];`,
+		`"gt-b" -> "gt-d";`,
+		`"gt-epic" -> "gt-a" [style=dotted, arrowhead=none];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGraphMermaid(t *testing.T) {
+	g := testGraph()
+	g.CriticalPath = criticalPath(g.dag)
+	out := g.Mermaid("TB")
+
+	// Nodes are numbered in sorted ID order: gt-a, gt-b, gt-c, gt-d, gt-e, gt-epic.
+	for _, want := range []string{
+		"flowchart TB\n",
+		`n1["gt-b<br/>Second #quot;quoted#quot;<br/>(in_progress)"]`,
+		`n5[["gt-epic<br/>Epic<br/>(open)"]]`,
+		"n0 --> n1\n",
+		"n1 ==> n2\n",
+		"n5 -.- n0\n",
+		"style n1 fill:#fff2a8,stroke:#c62828,stroke-width:3px\n",
+		"style n0 fill:#c8e6c9\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestTruncateGraphTitle(t *testing.T) {
+	short := "Fix the thing"
+	if got := truncateGraphTitle(short); got != short {
+		t.Errorf("truncateGraphTitle(%q) = %q", short, got)
+	}
+	long := strings.Repeat("x", 60)
+	if got := truncateGraphTitle(long); len([]rune(got)) != graphTitleMax {
+		t.Errorf("truncated title has %d runes, want %d", len([]rune(got)), graphTitleMax)
+	}
+}
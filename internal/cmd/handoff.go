@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -17,12 +18,13 @@ import (
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/summarize"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var handoffCmd = &cobra.Command{
-	Use:     "handoff [bead-or-role]",
+	Use:     "handoff [bead-or-role] [new-target]",
 	GroupID: GroupWork,
 	Short:   "Hand off to a fresh session, work continues from hook",
 	Long: `End watch. Hand off to a fresh agent session.
@@ -35,6 +37,8 @@ This is the canonical way to end any agent session. It handles all roles:
 When run without arguments, hands off the current session.
 When given a bead ID (gt-xxx, hq-xxx), hooks that work first, then restarts.
 When given a role name, hands off that role's session (and switches to it).
+When given an issue and a new target, reassigns the issue without losing
+its context (see below).
 
 Examples:
   gt handoff                          # Hand off current session
@@ -44,6 +48,8 @@ Examples:
   gt handoff -c                       # Collect state into handoff message
   gt handoff crew                     # Hand off crew session
   gt handoff mayor                    # Hand off mayor session
+  gt handoff gt-abc gastown           # Reassign gt-abc to a fresh polecat
+  gt handoff gt-abc gastown/Nux -m "Blocked on flaky CI, see notes"
 
 The --collect (-c) flag gathers current state (hooked work, inbox, ready beads,
 in-progress items) and includes it in the handoff mail. This provides context
@@ -55,7 +61,16 @@ always does a full respawn regardless of role. This enables crew workers and
 polecats to get a fresh context window when the current one fills up.
 
 Any molecule on the hook will be auto-continued by the new session.
-The SessionStart hook runs 'gt prime' to restore context.`,
+The SessionStart hook runs 'gt prime' to restore context.
+
+Reassigning an issue (gt handoff <issue> <new-target>) moves it from the
+polecat working on it to another target, carrying its context along:
+  - the old polecat's uncommitted work is committed and its branch pushed
+  - a HANDOFF comment is posted to the issue with the branch, commits so
+    far, the issue's notes, the tail of the old session, and any -m text
+  - the old session is stopped
+  - the issue is re-slung to the new target, told to pick up the branch`,
+	Args: cobra.MaximumNArgs(2),
 	RunE: runHandoff,
 }
 
@@ -99,6 +114,10 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		handoffMessage = strings.TrimRight(string(data), "\n")
 	}
 
+	if len(args) == 2 {
+		return runHandoffReassign(args[0], args[1])
+	}
+
 	// --auto mode: save state only, no session cycling.
 	// Used by PreCompact hook to preserve state before compaction.
 	// Note: auto-mode exits here, before the git-status warning check below.
@@ -1341,6 +1360,11 @@ func collectHandoffState() string {
 		parts = append(parts, gitState)
 	}
 
+	// What the outgoing session was doing, condensed for the successor.
+	if recent := collectSessionSummary(); recent != "" {
+		parts = append(parts, recent)
+	}
+
 	// Get hooked work
 	hookOutput, err := exec.Command("gt", "hook").Output()
 	if err == nil {
@@ -1398,6 +1422,59 @@ func collectHandoffState() string {
 	return strings.Join(parts, "\n\n")
 }
 
+const (
+	// handoffPaneLines is how much of the pane is summarized for the
+	// successor's resume prompt, together with the session's transcript.
+	handoffPaneLines = 300
+
+	// handoffTranscriptEntries is how many recent transcript output
+	// captures are summarized.
+	handoffTranscriptEntries = 10
+
+	// handoffSummaryChars caps the "Recent Session" section.
+	handoffSummaryChars = 1500
+)
+
+// collectSessionSummary condenses the current session's recent output (its
+// transcript captures and the pane) into a "## Recent Session" section,
+// using the town's summarizer model if one is configured and the tail of
+// the output otherwise. Returns "" outside a town session.
+func collectSessionSummary() string {
+	sess, err := getCurrentTmuxSession()
+	if err != nil || sess == "" {
+		return ""
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return ""
+	}
+
+	var chunks []string
+	entries, _ := tmux.ReadTranscript(townRoot, sess)
+	var outputs []string
+	for _, e := range entries {
+		if e.Dir == tmux.TranscriptOut && strings.TrimSpace(e.Data) != "" {
+			outputs = append(outputs, e.Data)
+		}
+	}
+	if len(outputs) > handoffTranscriptEntries {
+		outputs = outputs[len(outputs)-handoffTranscriptEntries:]
+	}
+	chunks = append(chunks, outputs...)
+	if lines, err := tmux.NewTmux().CapturePaneLines(sess, handoffPaneLines); err == nil {
+		chunks = append(chunks, strings.Join(lines, "\n"))
+	}
+	text := strings.TrimSpace(strings.Join(chunks, "\n"))
+	if text == "" {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), summarize.DefaultTimeout)
+	defer cancel()
+	summary := summarize.Text(ctx, summarize.New(townRoot), summarize.KindTranscript, text, handoffSummaryChars)
+	return "## Recent Session\n" + summary
+}
+
 // collectGitState captures deterministic workspace state using the Go git library.
 // This uses only the git.Git wrapper (no shelling out to gt/bd), so it works
 // reliably even when PATH is broken or external commands are unavailable.
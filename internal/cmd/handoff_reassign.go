@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Limits on what goes into a reassignment's context summary.
+const (
+	handoffContextCommits = 20
+	handoffContextLines   = 40
+)
+
+// issueHandoff is the context carried from an issue's previous owner to
+// its new one by 'gt handoff <issue> <target>'.
+type issueHandoff struct {
+	BeadID  string
+	Title   string
+	From    string // previous assignee, e.g. gastown/polecats/Toast
+	To      string // new sling target
+	Branch  string // pushed branch holding the previous owner's work
+	Commits []string
+	Notes   string
+	Tail    []string // last lines of the previous owner's session
+	Message string   // extra context from the person handing off (-m)
+}
+
+// Summary renders the context posted to the issue for the new owner.
+func (h *issueHandoff) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "HANDOFF from %s to %s\n", h.From, h.To)
+	if h.Message != "" {
+		fmt.Fprintf(&b, "\n%s\n", h.Message)
+	}
+	if h.Branch != "" {
+		fmt.Fprintf(&b, "\nBranch: %s (pushed to origin)\n", h.Branch)
+		fmt.Fprintf(&b, "Pick it up with: git fetch origin && git merge origin/%s\n", h.Branch)
+	} else {
+		b.WriteString("\nNo branch was transferred; start from the issue description.\n")
+	}
+	if len(h.Commits) > 0 {
+		b.WriteString("\nCommits so far:\n")
+		for _, c := range h.Commits {
+			fmt.Fprintf(&b, "  %s\n", c)
+		}
+	}
+	if h.Notes != "" {
+		fmt.Fprintf(&b, "\nNotes:\n%s\n", h.Notes)
+	}
+	if len(h.Tail) > 0 {
+		b.WriteString("\nLast output from the previous session:\n")
+		for _, line := range h.Tail {
+			fmt.Fprintf(&b, "  | %s\n", line)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// SlingArgs is the short instruction given to the new owner on its hook.
+func (h *issueHandoff) SlingArgs() string {
+	args := fmt.Sprintf("Handoff from %s: continue their work, don't start over. Read the HANDOFF comment on %s (bd show %s).",
+		h.From, h.BeadID, h.BeadID)
+	if h.Branch != "" {
+		args += fmt.Sprintf(" Their work is on branch %s: git fetch origin && git merge origin/%s.", h.Branch, h.Branch)
+	}
+	return args
+}
+
+// parsePolecatAssignee splits a polecat assignee ("rig/polecats/name").
+func parsePolecatAssignee(assignee string) (rigName, name string, ok bool) {
+	parts := strings.Split(assignee, "/")
+	if len(parts) != 3 || parts[1] != "polecats" || parts[0] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[2], true
+}
+
+// tailLines returns the last n non-blank lines of s.
+func tailLines(s string, n int) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimRight(line, " \t"))
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// runHandoffReassign moves an issue from its current polecat to a new
+// sling target without losing the work: the old polecat's branch is
+// committed and pushed, a context summary is posted to the issue, the old
+// session is stopped, and the issue is re-slung with --force (which also
+// asks the old rig's witness to clean up the polecat).
+func runHandoffReassign(beadID, target string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	info, err := getBeadInfo(beadID)
+	if err != nil {
+		return err
+	}
+	if info.Assignee == "" {
+		return fmt.Errorf("%s is not assigned to anyone; use gt sling %s %s", beadID, beadID, target)
+	}
+
+	h := &issueHandoff{
+		BeadID:  beadID,
+		Title:   info.Title,
+		From:    info.Assignee,
+		To:      target,
+		Notes:   strings.TrimSpace(info.Notes),
+		Message: handoffMessage,
+	}
+
+	rigName, polecatName, isPolecat := parsePolecatAssignee(info.Assignee)
+	var sessMgr *polecat.SessionManager
+	if isPolecat {
+		mgr, _, err := getPolecatManager(rigName)
+		if err != nil {
+			return err
+		}
+		p, err := mgr.Get(polecatName)
+		if err != nil {
+			return fmt.Errorf("finding %s: %w", info.Assignee, err)
+		}
+		if sessMgr, _, err = getSessionManager(rigName); err != nil {
+			return err
+		}
+		if out, err := sessMgr.Capture(polecatName, 200); err == nil {
+			h.Tail = tailLines(out, handoffContextLines)
+		}
+
+		if handoffDryRun {
+			h.Branch = p.Branch
+		} else if err := saveHandoffBranch(h, p); err != nil {
+			return err
+		}
+	} else {
+		style.PrintWarning("%s is not a polecat; no branch to transfer", info.Assignee)
+	}
+
+	if handoffDryRun {
+		fmt.Printf("Would hand off %s from %s to %s\n", beadID, h.From, h.To)
+		if h.Branch != "" {
+			fmt.Printf("  push branch %s and stop %s\n", h.Branch, h.From)
+		}
+		fmt.Printf("  post context:\n\n%s\n", h.Summary())
+		return nil
+	}
+
+	if _, err := beads.New(resolveBeadDir(beadID)).Run("comment", beadID, h.Summary()); err != nil {
+		return fmt.Errorf("posting handoff context to %s: %w", beadID, err)
+	}
+	fmt.Printf("%s Posted handoff context to %s\n", style.SuccessPrefix, beadID)
+
+	if sessMgr != nil {
+		if err := sessMgr.Stop(polecatName, false); err != nil && !errors.Is(err, polecat.ErrSessionNotFound) {
+			style.PrintWarning("could not stop %s: %v", info.Assignee, err)
+		} else if err == nil {
+			fmt.Printf("%s Stopped %s\n", style.SuccessPrefix, info.Assignee)
+		}
+	}
+
+	slingCmd := exec.Command("gt", "sling", beadID, target, "--force", "--args", h.SlingArgs())
+	slingCmd.Dir = townRoot
+	slingCmd.Stdout = os.Stdout
+	slingCmd.Stderr = os.Stderr
+	if err := slingCmd.Run(); err != nil {
+		return fmt.Errorf("reassigning %s to %s (context is on the issue; retry with gt sling %s %s --force): %w",
+			beadID, target, beadID, target, err)
+	}
+
+	_ = events.LogFeed(events.TypeIssueHandoff, detectSender(), events.IssueHandoffPayload(beadID, h.From, target, h.Branch))
+	fmt.Printf("%s Handed %s off from %s to %s\n", style.Bold.Render("✓"), beadID, h.From, target)
+	return nil
+}
+
+// saveHandoffBranch commits any uncommitted work in the polecat's clone,
+// pushes its branch, and records the branch and its commits in h. The
+// handoff is aborted if the push fails: stopping the polecat could then
+// lose the work.
+func saveHandoffBranch(h *issueHandoff, p *polecat.Polecat) error {
+	if p.ClonePath == "" || p.Branch == "" {
+		style.PrintWarning("%s has no branch; nothing to transfer", h.From)
+		return nil
+	}
+	g := git.NewGit(p.ClonePath)
+	if dirty, err := g.HasUncommittedChanges(); err == nil && dirty {
+		if err := g.Add("-A"); err != nil {
+			return fmt.Errorf("staging %s's work: %w", h.From, err)
+		}
+		if err := g.Commit(fmt.Sprintf("WIP: hand off %s to %s", h.BeadID, h.To)); err != nil {
+			return fmt.Errorf("committing %s's work: %w", h.From, err)
+		}
+		fmt.Printf("%s Committed uncommitted work in %s\n", style.SuccessPrefix, h.From)
+	}
+	if err := g.Push("origin", p.Branch, false); err != nil {
+		return fmt.Errorf("pushing %s (handoff aborted so the work isn't lost): %w", p.Branch, err)
+	}
+	fmt.Printf("%s Pushed %s\n", style.SuccessPrefix, p.Branch)
+	h.Branch = p.Branch
+
+	base := "origin/" + g.RemoteDefaultBranch()
+	out, err := exec.Command("git", "-C", p.ClonePath, "log", "--oneline", //nolint:gosec // G204: args are from trusted polecat state
+		fmt.Sprintf("-%d", handoffContextCommits), base+"..HEAD").Output()
+	if err == nil {
+		h.Commits = tailLines(string(out), handoffContextCommits)
+	}
+	return nil
+}
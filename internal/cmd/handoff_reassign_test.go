@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePolecatAssignee(t *testing.T) {
+	tests := []struct {
+		assignee string
+		rig      string
+		name     string
+		ok       bool
+	}{
+		{"gastown/polecats/Toast", "gastown", "Toast", true},
+		{"gastown/crew/max", "", "", false},
+		{"gastown/Toast", "", "", false},
+		{"mayor", "", "", false},
+	}
+	for _, tt := range tests {
+		rig, name, ok := parsePolecatAssignee(tt.assignee)
+		if rig != tt.rig || name != tt.name || ok != tt.ok {
+			t.Errorf("parsePolecatAssignee(%q) = %q, %q, %v; want %q, %q, %v",
+				tt.assignee, rig, name, ok, tt.rig, tt.name, tt.ok)
+		}
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	got := tailLines("a\n\nb  \n   \nc\nd\n", 3)
+	want := []string{"b", "c", "d"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("tailLines = %q, want %q", got, want)
+	}
+}
+
+func TestIssueHandoffSummary(t *testing.T) {
+	h := &issueHandoff{
+		BeadID:  "gt-abc",
+		From:    "gastown/polecats/Toast",
+		To:      "gastown",
+		Branch:  "polecat/Toast/gt-abc",
+		Commits: []string{"1234567 Add parser"},
+		Notes:   "Parser done, lexer half-finished.",
+		Tail:    []string{"Running tests..."},
+		Message: "Blocked on flaky CI",
+	}
+	summary := h.Summary()
+	for _, want := range []string{
+		"HANDOFF from gastown/polecats/Toast to gastown",
+		"Blocked on flaky CI",
+		"git merge origin/polecat/Toast/gt-abc",
+		"1234567 Add parser",
+		"lexer half-finished",
+		"| Running tests...",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+	if args := h.SlingArgs(); !strings.Contains(args, "bd show gt-abc") || !strings.Contains(args, "origin/polecat/Toast/gt-abc") {
+		t.Errorf("SlingArgs = %q", args)
+	}
+
+	h.Branch = ""
+	if !strings.Contains(h.Summary(), "No branch was transferred") {
+		t.Error("summary without a branch should say so")
+	}
+}
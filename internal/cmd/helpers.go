@@ -87,6 +87,20 @@ func isInSameTmuxSocket() bool {
 // control, and passes -u for UTF-8 support regardless of locale settings.
 // See: https://github.com/steveyegge/gastown/issues/1219
 func attachToTmuxSession(sessionID string) error {
+	// Another multiplexer runs the town's sessions: attach with it.
+	if t := tmux.NewTmux(); t.Name() != tmux.BackendTmux {
+		argv, err := t.AttachCommand(sessionID)
+		if err != nil {
+			return err
+		}
+		path, err := exec.LookPath(argv[0])
+		if err != nil {
+			return fmt.Errorf("%s not found: %w", argv[0], err)
+		}
+		tmux.RecordAttach(sessionID)
+		return syscall.Exec(path, argv, os.Environ())
+	}
+
 	tmuxPath, err := exec.LookPath("tmux")
 	if err != nil {
 		return fmt.Errorf("tmux not found: %w", err)
@@ -107,6 +121,8 @@ func attachToTmuxSession(sessionID string) error {
 		args = append(baseArgs, "attach-session", "-t", sessionID)
 	}
 
+	tmux.RecordAttach(sessionID)
+
 	// Replace the Go process with tmux for direct terminal control
 	return syscall.Exec(tmuxPath, args, os.Environ())
 }
@@ -0,0 +1,497 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Incident command flags
+var (
+	incidentSince   string
+	incidentUntil   string
+	incidentPadding time.Duration
+	incidentOutput  string
+)
+
+var incidentCmd = &cobra.Command{
+	Use:     "incident <issue|session>",
+	GroupID: GroupDiag,
+	Short:   "Build a blame-free incident timeline as markdown",
+	Long: `Assemble a chronological timeline of everything that happened around an
+issue or agent session, for a blame-free post-incident review.
+
+Sources:
+  - Activity events (.events.jsonl): slings, nudges, witness patrols, deaths
+  - Town log: spawns, handoffs, crashes, kills
+  - Git reflogs of the agents' worktrees
+  - Mail sent to or from the agents involved
+
+For an issue, the window runs from its creation until it closed (or now), and
+the agents are its assignee plus anyone whose events mention it. For a
+session, the window spans the events mentioning the session (default: the
+last 24h) and the agent is the session's owner. The window is widened by
+--padding on both sides; --since/--until override it.
+
+Examples:
+  gt incident gt-abc12                      # Timeline for an issue
+  gt incident gt-gastown-Toast              # Timeline for a polecat session
+  gt incident gt-abc12 --since=2h           # Only the last two hours
+  gt incident gt-abc12 -o incident.md       # Write markdown to a file`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIncident,
+}
+
+func init() {
+	incidentCmd.Flags().StringVar(&incidentSince, "since", "", "Start of the window as a duration ago (e.g., 2h, 7d) or RFC3339 time")
+	incidentCmd.Flags().StringVar(&incidentUntil, "until", "", "End of the window as a duration ago or RFC3339 time (default: now)")
+	incidentCmd.Flags().DurationVar(&incidentPadding, "padding", 15*time.Minute, "Widen the derived window by this much on both sides")
+	incidentCmd.Flags().StringVarP(&incidentOutput, "output", "o", "", "Write the markdown to a file instead of stdout")
+
+	rootCmd.AddCommand(incidentCmd)
+}
+
+// incidentScope is what an incident timeline covers.
+type incidentScope struct {
+	Subject  string    // issue ID or session name
+	Title    string    // issue title, if any
+	Keywords []string  // strings whose mention pulls an entry in (issue ID, session name)
+	Actors   []string  // agent addresses involved
+	Start    time.Time // window start
+	End      time.Time // window end
+}
+
+// inWindow reports whether t falls inside the scope's window.
+func (s *incidentScope) inWindow(t time.Time) bool {
+	return !t.Before(s.Start) && !t.After(s.End)
+}
+
+// mentions reports whether text contains one of the scope's keywords.
+func (s *incidentScope) mentions(text string) bool {
+	for _, k := range s.Keywords {
+		if k != "" && strings.Contains(text, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// involves reports whether name matches one of the scope's actors.
+func (s *incidentScope) involves(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, a := range s.Actors {
+		if matchesActor(name, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// names reports whether text contains one of the scope's actor addresses,
+// e.g. the target of a nudge or a witness patrol check.
+func (s *incidentScope) names(text string) bool {
+	for _, a := range s.Actors {
+		if strings.Contains(text, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// addActor records an agent address once.
+func (s *incidentScope) addActor(actor string) {
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return
+	}
+	for _, a := range s.Actors {
+		if a == actor {
+			return
+		}
+	}
+	s.Actors = append(s.Actors, actor)
+}
+
+func runIncident(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	scope, err := resolveIncidentScope(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if incidentSince != "" {
+		if scope.Start, err = parseIncidentTime(incidentSince); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if incidentUntil != "" {
+		if scope.End, err = parseIncidentTime(incidentUntil); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	if scope.End.Before(scope.Start) {
+		return fmt.Errorf("incident window ends (%s) before it starts (%s)",
+			scope.End.Format(time.RFC3339), scope.Start.Format(time.RFC3339))
+	}
+
+	var entries []AuditEntry
+	collectors := []struct {
+		name    string
+		collect func(string, *incidentScope) ([]AuditEntry, error)
+	}{
+		{"events feed", collectIncidentEvents},
+		{"town log", collectIncidentTownlog},
+		{"git reflog", collectIncidentReflog},
+		{"mail", collectIncidentMail},
+	}
+	for _, c := range collectors {
+		found, err := c.collect(townRoot, scope)
+		if err != nil {
+			// Non-fatal: a partial timeline is still useful
+			fmt.Fprintf(os.Stderr, "Warning: could not query %s: %v\n", c.name, err)
+		}
+		entries = append(entries, found...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	md := renderIncidentMarkdown(scope, entries)
+	if incidentOutput == "" {
+		fmt.Print(md)
+		return nil
+	}
+	if err := os.WriteFile(incidentOutput, []byte(md), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", incidentOutput, err)
+	}
+	fmt.Printf("%s Wrote incident timeline (%d entries) to %s\n", style.Success.Render("✓"), len(entries), incidentOutput)
+	return nil
+}
+
+// parseIncidentTime accepts an RFC3339 time or a duration ago.
+func parseIncidentTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	d, err := parseDuration(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().Add(-d), nil
+}
+
+// resolveIncidentScope treats target as an issue ID if beads knows it, and
+// as a session name otherwise.
+func resolveIncidentScope(townRoot, target string) (*incidentScope, error) {
+	if issue, err := beads.New(resolveBeadDir(target)).Show(target); err == nil {
+		return issueIncidentScope(townRoot, issue), nil
+	}
+	id, err := session.ParseSessionName(target)
+	if err != nil {
+		return nil, fmt.Errorf("%q is neither a known issue nor an agent session name", target)
+	}
+	return sessionIncidentScope(townRoot, target, id.Address()), nil
+}
+
+// issueIncidentScope covers an issue from creation to close.
+func issueIncidentScope(townRoot string, issue *beads.Issue) *incidentScope {
+	s := &incidentScope{
+		Subject:  issue.ID,
+		Title:    issue.Title,
+		Keywords: []string{issue.ID},
+		Start:    parseBeadsTimestamp(issue.CreatedAt),
+		End:      parseBeadsTimestamp(issue.ClosedAt),
+	}
+	s.addActor(issue.Assignee)
+	if s.End.IsZero() {
+		s.End = time.Now()
+	}
+	if s.Start.IsZero() {
+		s.Start = s.End.Add(-24 * time.Hour)
+	}
+
+	// Everyone whose events mention the issue took part in it.
+	_ = scanIncidentEvents(townRoot, func(e events.Event, raw []byte, _ time.Time) {
+		if e.Actor != "" && s.mentions(string(raw)) {
+			s.addActor(e.Actor)
+		}
+	})
+
+	s.Start = s.Start.Add(-incidentPadding)
+	s.End = s.End.Add(incidentPadding)
+	return s
+}
+
+// sessionIncidentScope covers the span of events mentioning a session.
+func sessionIncidentScope(townRoot, name, address string) *incidentScope {
+	s := &incidentScope{
+		Subject:  name,
+		Keywords: []string{name},
+	}
+	s.addActor(address)
+
+	_ = scanIncidentEvents(townRoot, func(e events.Event, raw []byte, ts time.Time) {
+		if ts.IsZero() || !s.mentions(string(raw)) {
+			return
+		}
+		if s.Start.IsZero() || ts.Before(s.Start) {
+			s.Start = ts
+		}
+		if ts.After(s.End) {
+			s.End = ts
+		}
+	})
+	if s.Start.IsZero() {
+		s.End = time.Now()
+		s.Start = s.End.Add(-24 * time.Hour)
+	}
+
+	s.Start = s.Start.Add(-incidentPadding)
+	s.End = s.End.Add(incidentPadding)
+	return s
+}
+
+// scanIncidentEvents calls fn for every well-formed line of the events feed,
+// keeping the raw line so payloads can be searched.
+func scanIncidentEvents(townRoot string, fn func(e events.Event, raw []byte, ts time.Time)) error {
+	file, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // No events file yet
+		}
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip malformed lines
+		}
+		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
+		fn(e, scanner.Bytes(), ts)
+	}
+	return scanner.Err()
+}
+
+// collectIncidentEvents returns feed events by or about the agents involved,
+// or mentioning the subject. Nudges and witness patrol events live here too.
+func collectIncidentEvents(townRoot string, s *incidentScope) ([]AuditEntry, error) {
+	var entries []AuditEntry
+	err := scanIncidentEvents(townRoot, func(e events.Event, raw []byte, ts time.Time) {
+		if !s.inWindow(ts) || (!s.involves(e.Actor) && !s.mentions(string(raw)) && !s.names(string(raw))) {
+			return
+		}
+		entries = append(entries, AuditEntry{
+			Timestamp: ts,
+			Source:    "events",
+			Type:      e.Type,
+			Actor:     e.Actor,
+			Summary:   formatFeedSummary(e),
+		})
+	})
+	return entries, err
+}
+
+// collectIncidentTownlog returns town log events by the agents involved or
+// mentioning the subject.
+func collectIncidentTownlog(townRoot string, s *incidentScope) ([]AuditEntry, error) {
+	all, err := townlog.ReadEvents(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var entries []AuditEntry
+	for _, e := range all {
+		if !s.inWindow(e.Timestamp) || (!s.involves(e.Agent) && !s.mentions(e.Context)) {
+			continue
+		}
+		entries = append(entries, AuditEntry{
+			Timestamp: e.Timestamp,
+			Source:    "townlog",
+			Type:      string(e.Type),
+			Actor:     e.Agent,
+			Summary:   formatTownlogSummary(e),
+		})
+	}
+	return entries, nil
+}
+
+// incidentWorktree returns the git worktree of a rig agent address, or "".
+func incidentWorktree(townRoot, actor string) string {
+	parts := strings.Split(actor, "/")
+	if len(parts) != 3 || parts[2] == "" {
+		return ""
+	}
+	rig, kind, name := parts[0], parts[1], parts[2]
+	switch kind {
+	case "polecats":
+		// New structure: polecats/<name>/<rig>/, old: polecats/<name>/
+		nested := filepath.Join(townRoot, rig, "polecats", name, rig)
+		if _, err := os.Stat(nested); err == nil {
+			return nested
+		}
+		return filepath.Join(townRoot, rig, "polecats", name)
+	case "crew":
+		return filepath.Join(townRoot, rig, "crew", name)
+	}
+	return ""
+}
+
+// collectIncidentReflog returns reflog entries from the worktrees of the
+// agents involved: branch switches, resets, rebases and commits, including
+// ones that were later rewritten away.
+func collectIncidentReflog(townRoot string, s *incidentScope) ([]AuditEntry, error) {
+	var entries []AuditEntry
+	seen := make(map[string]bool)
+	for _, actor := range s.Actors {
+		dir := incidentWorktree(townRoot, actor)
+		if dir == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+			continue // worktree already removed
+		}
+		cmd := exec.Command("git", "reflog", "show", "--all", "--date=iso-strict", "--format=%h%x1f%gD%x1f%gs")
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			return entries, fmt.Errorf("reading reflog in %s: %w", dir, err)
+		}
+		for _, e := range parseIncidentReflog(string(out), actor) {
+			key := e.ID + e.Details + e.Timestamp.String()
+			if !s.inWindow(e.Timestamp) || seen[key] {
+				continue
+			}
+			seen[key] = true
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// parseIncidentReflog parses `git reflog --format=%h%x1f%gD%x1f%gs` output
+// with --date=iso-strict, where %gD looks like "HEAD@{2026-01-02T15:04:05Z}".
+func parseIncidentReflog(out, actor string) []AuditEntry {
+	var entries []AuditEntry
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		open, end := strings.Index(fields[1], "@{"), strings.LastIndex(fields[1], "}")
+		if open < 0 || end < open {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, fields[1][open+2:end])
+		if err != nil {
+			continue
+		}
+		ref := strings.TrimPrefix(fields[1][:open], "refs/heads/")
+		entries = append(entries, AuditEntry{
+			Timestamp: ts,
+			Source:    "git",
+			Type:      "reflog",
+			Actor:     actor,
+			Summary:   fmt.Sprintf("%s %s: %s", ref, fields[0], fields[2]),
+			Details:   ref,
+			ID:        fields[0],
+		})
+	}
+	return entries
+}
+
+// collectIncidentMail returns mail sent to or from the agents involved, or
+// mentioning the subject.
+func collectIncidentMail(townRoot string, s *incidentScope) ([]AuditEntry, error) {
+	msgs, err := beads.New(townRoot).List(beads.ListOptions{Label: "gt:message", Status: "all", Limit: 0})
+	if err != nil {
+		return nil, err
+	}
+	var entries []AuditEntry
+	for _, m := range msgs {
+		ts := parseBeadsTimestamp(m.CreatedAt)
+		if !s.inWindow(ts) {
+			continue
+		}
+		var from string
+		for _, l := range m.Labels {
+			if strings.HasPrefix(l, "from:") {
+				from = strings.TrimPrefix(l, "from:")
+			}
+		}
+		if !s.involves(from) && !s.involves(m.Assignee) && !s.mentions(m.Title) && !s.mentions(m.Description) {
+			continue
+		}
+		entries = append(entries, AuditEntry{
+			Timestamp: ts,
+			Source:    "mail",
+			Type:      "mail",
+			Actor:     from,
+			Summary:   fmt.Sprintf("→ %s: %s", m.Assignee, m.Title),
+			ID:        m.ID,
+		})
+	}
+	return entries, nil
+}
+
+// renderIncidentMarkdown formats the timeline as a markdown document.
+func renderIncidentMarkdown(s *incidentScope, entries []AuditEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Incident timeline: %s\n\n", s.Subject)
+	if s.Title != "" {
+		fmt.Fprintf(&b, "**%s**\n\n", s.Title)
+	}
+	b.WriteString("> Blame-free review: this timeline records what the system and its agents did, " +
+		"to find what let the failure happen, not who caused it.\n\n")
+
+	fmt.Fprintf(&b, "- **Window:** %s → %s (UTC)\n",
+		s.Start.UTC().Format("2006-01-02 15:04:05"), s.End.UTC().Format("2006-01-02 15:04:05"))
+	if len(s.Actors) > 0 {
+		fmt.Fprintf(&b, "- **Agents:** %s\n", strings.Join(s.Actors, ", "))
+	}
+	counts := make(map[string]int)
+	for _, e := range entries {
+		counts[e.Source]++
+	}
+	var sources []string
+	for _, src := range []string{"events", "townlog", "git", "mail"} {
+		sources = append(sources, fmt.Sprintf("%s %d", src, counts[src]))
+	}
+	fmt.Fprintf(&b, "- **Entries:** %d (%s)\n\n", len(entries), strings.Join(sources, ", "))
+
+	b.WriteString("## Timeline\n\n")
+	if len(entries) == 0 {
+		b.WriteString("_No activity found in this window._\n")
+		return b.String()
+	}
+	b.WriteString("| Time (UTC) | Source | Actor | What happened |\n")
+	b.WriteString("|---|---|---|---|\n")
+	cell := strings.NewReplacer("|", `\|`, "\n", " ")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+			e.Timestamp.UTC().Format("2006-01-02 15:04:05"),
+			e.Source, cell.Replace(e.Actor), cell.Replace(e.Summary))
+	}
+	return b.String()
+}
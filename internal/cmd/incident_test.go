@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/townlog"
+)
+
+func TestIncidentCollectors(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	at := func(m int) string { return base.Add(time.Duration(m) * time.Minute).Format(time.RFC3339) }
+
+	feed := strings.Join([]string{
+		`{"ts":"` + at(0) + `","source":"gt","type":"sling","actor":"mayor","payload":{"bead":"gt-abc12","target":"gastown/polecats/Toast"}}`,
+		`{"ts":"` + at(5) + `","source":"gt","type":"nudge","actor":"gastown/witness","payload":{"target":"gastown/polecats/Toast"}}`,
+		`{"ts":"` + at(6) + `","source":"gt","type":"hook","actor":"gastown/polecats/Nux","payload":{"bead":"gt-zzz99"}}`,
+		`{"ts":"` + at(90) + `","source":"gt","type":"done","actor":"gastown/polecats/Toast","payload":{"bead":"gt-abc12"}}`,
+		`not json`,
+	}, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(feed), 0644); err != nil {
+		t.Fatal(err)
+	}
+	logger := townlog.NewLogger(townRoot)
+	for _, e := range []townlog.Event{
+		{Timestamp: base.Add(1 * time.Minute), Type: townlog.EventSpawn, Agent: "gastown/Toast", Context: "gt-abc12"},
+		{Timestamp: base.Add(2 * time.Minute), Type: townlog.EventSpawn, Agent: "gastown/Nux", Context: "gt-zzz99"},
+	} {
+		if err := logger.LogEvent(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &incidentScope{
+		Subject:  "gt-abc12",
+		Keywords: []string{"gt-abc12"},
+		Actors:   []string{"gastown/polecats/Toast"},
+		Start:    base,
+		End:      base.Add(60 * time.Minute),
+	}
+	got, err := collectIncidentEvents(townRoot, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []string
+	for _, e := range got {
+		types = append(types, e.Type)
+	}
+	// The nudge names Toast in its payload; Nux's hook and the done event
+	// outside the window are excluded.
+	if strings.Join(types, ",") != "sling,nudge" {
+		t.Errorf("events = %v, want [sling nudge]", types)
+	}
+
+	got, err = collectIncidentTownlog(townRoot, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Actor != "gastown/Toast" {
+		t.Errorf("townlog entries = %+v, want Toast's spawn only", got)
+	}
+}
+
+func TestParseIncidentReflog(t *testing.T) {
+	out := "abc1234\x1fHEAD@{2026-01-02T15:04:05+00:00}\x1fcommit: fix parser\n" +
+		"def5678\x1frefs/heads/polecat/Toast@{2026-01-02T15:10:00Z}\x1freset: moving to origin/main\n" +
+		"garbage line\n"
+	got := parseIncidentReflog(out, "gastown/polecats/Toast")
+	if len(got) != 2 {
+		t.Fatalf("parsed %d entries, want 2: %+v", len(got), got)
+	}
+	if got[0].Summary != "HEAD abc1234: commit: fix parser" {
+		t.Errorf("summary = %q", got[0].Summary)
+	}
+	if got[1].Details != "polecat/Toast" || got[1].Timestamp.Minute() != 10 {
+		t.Errorf("second entry = %+v", got[1])
+	}
+}
+
+func TestRenderIncidentMarkdown(t *testing.T) {
+	base := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	s := &incidentScope{
+		Subject: "gt-abc12",
+		Title:   "Refinery stuck",
+		Actors:  []string{"gastown/polecats/Toast"},
+		Start:   base,
+		End:     base.Add(time.Hour),
+	}
+	md := renderIncidentMarkdown(s, []AuditEntry{
+		{Timestamp: base.Add(time.Minute), Source: "events", Actor: "mayor", Summary: "a | b"},
+		{Timestamp: base.Add(2 * time.Minute), Source: "git", Actor: "gastown/polecats/Toast", Summary: "HEAD abc: commit"},
+	})
+	for _, want := range []string{
+		"# Incident timeline: gt-abc12",
+		"**Refinery stuck**",
+		"Blame-free",
+		"2026-01-02 15:00:00 → 2026-01-02 16:00:00",
+		"(events 1, townlog 0, git 1, mail 0)",
+		`| 2026-01-02 15:01:00 | events | mayor | a \| b |`,
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if md := renderIncidentMarkdown(s, nil); !strings.Contains(md, "No activity found") {
+		t.Errorf("empty timeline:\n%s", md)
+	}
+}
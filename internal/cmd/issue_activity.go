@@ -0,0 +1,423 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var issueActivityJSON bool
+
+var issueActivityCmd = &cobra.Command{
+	Use:   "activity <issue-id>",
+	Short: "Show everything that happened to an issue, oldest first",
+	Long: `Show a unified, chronological feed for one issue across subsystems:
+
+  - The issue's creation and closing, and status changes
+  - Assignment: slings, hooks, handoffs between agents, unhooks, done
+  - Dispatch attempts, including scheduler dispatches and failures
+  - Session starts, deaths and restarts, and nudges, of agents while
+    they held the issue
+  - Commits mentioning the issue in the rig's repo
+  - Merge requests for the issue and their merge events
+  - Mail whose subject or body mentions the issue
+
+Use it to see where a stuck piece of work got stuck.
+
+Examples:
+  gt issue activity gt-abc12
+  gt issue activity gt-abc12 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueActivity,
+}
+
+func init() {
+	issueActivityCmd.Flags().BoolVar(&issueActivityJSON, "json", false, "Output as JSON")
+	issueCmd.AddCommand(issueActivityCmd)
+}
+
+func runIssueActivity(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	id := args[0]
+	rigDir := beads.ResolveHookDir(townRoot, id, "")
+
+	var entries []AuditEntry
+	warn := func(what string, err error) {
+		fmt.Fprintf(os.Stderr, "Warning: could not query %s: %v\n", what, err)
+	}
+
+	issue, err := beads.New(rigDir).Show(id)
+	if err != nil {
+		warn("issue", err)
+	} else {
+		entries = append(entries, issueLifecycleEntries(issue)...)
+	}
+
+	feed, err := readFeedEvents(townRoot)
+	if err != nil {
+		warn("events feed", err)
+	}
+	entries = append(entries, issueFeedEntries(feed, id)...)
+
+	if tl, err := townlog.ReadEvents(townRoot); err != nil {
+		warn("town log", err)
+	} else {
+		entries = append(entries, issueTownlogEntries(tl, id)...)
+	}
+
+	entries = append(entries, issueCommitEntries(rigDir, id)...)
+
+	if mrs, err := issueMREntries(rigDir, id); err != nil {
+		warn("merge requests", err)
+	} else {
+		entries = append(entries, mrs...)
+	}
+
+	if mail, err := issueMailEntries(townRoot, id); err != nil {
+		warn("mail", err)
+	} else {
+		entries = append(entries, mail...)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	if issueActivityJSON {
+		if entries == nil {
+			entries = []AuditEntry{}
+		}
+		return outputAuditJSON(entries)
+	}
+	if issue != nil {
+		assignee := issue.Assignee
+		if assignee == "" {
+			assignee = "unassigned"
+		}
+		fmt.Printf("%s %s %s\n\n", style.Bold.Render(issue.ID+":"), issue.Title,
+			style.Dim.Render(fmt.Sprintf("[%s, %s]", issue.Status, assignee)))
+	}
+	if len(entries) == 0 {
+		fmt.Printf("%s No activity found for %s\n", style.Dim.Render("○"), id)
+		return nil
+	}
+	return outputAuditText(entries)
+}
+
+// issueLifecycleEntries covers what the issue records about itself.
+func issueLifecycleEntries(issue *beads.Issue) []AuditEntry {
+	var entries []AuditEntry
+	if ts := parseBeadsTimestamp(issue.CreatedAt); !ts.IsZero() {
+		entries = append(entries, AuditEntry{
+			Timestamp: ts,
+			Source:    "beads",
+			Type:      "bead_created",
+			Actor:     issue.CreatedBy,
+			Summary:   fmt.Sprintf("Created: %s", issue.Title),
+			ID:        issue.ID,
+		})
+	}
+	if ts := parseBeadsTimestamp(issue.ClosedAt); !ts.IsZero() {
+		entries = append(entries, AuditEntry{
+			Timestamp: ts,
+			Source:    "beads",
+			Type:      "bead_closed",
+			Actor:     issue.Assignee,
+			Summary:   "Closed",
+			ID:        issue.ID,
+		})
+	}
+	return entries
+}
+
+// readFeedEvents reads the town's event log, oldest first.
+func readFeedEvents(townRoot string) ([]events.Event, error) {
+	file, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var evs []events.Event
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip malformed lines
+		}
+		evs = append(evs, e)
+	}
+	return evs, scanner.Err()
+}
+
+// agentSessionEvents are events about an agent's session rather than an
+// issue. They belong to an issue's activity while the agent holds it.
+var agentSessionEvents = map[string]bool{
+	events.TypeSessionStart:   true,
+	events.TypeSessionEnd:     true,
+	events.TypeSessionDeath:   true,
+	events.TypeSessionFailure: true,
+	events.TypeSpawn:          true,
+	events.TypeKill:           true,
+	events.TypeNudge:          true,
+	events.TypePolecatNudged:  true,
+	events.TypeHandoff:        true,
+}
+
+// issueFeedEntries picks the events that concern issue id: those whose
+// payload mentions it, and session events and nudges of the agents holding
+// it at the time. An agent holds the issue from a sling or handoff to it
+// until it is done with, unhooks, or hands off the issue. evs must be in
+// log order.
+func issueFeedEntries(evs []events.Event, id string) []AuditEntry {
+	var entries []AuditEntry
+	holders := make(map[string]bool)
+	for _, e := range evs {
+		mentioned := payloadMentions(e.Payload, id)
+		if !mentioned && !(agentSessionEvents[e.Type] && eventConcernsAny(e, holders)) {
+			continue
+		}
+		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
+		entries = append(entries, AuditEntry{
+			Timestamp: ts,
+			Source:    "events",
+			Type:      e.Type,
+			Actor:     e.Actor,
+			Summary:   formatFeedSummary(e),
+		})
+
+		if !mentioned {
+			continue
+		}
+		switch e.Type {
+		case events.TypeSling:
+			if target, _ := e.Payload["target"].(string); target != "" {
+				holders[target] = true
+			}
+		case events.TypeHook:
+			holders[e.Actor] = true
+		case events.TypeIssueHandoff:
+			from, _ := e.Payload["from"].(string)
+			to, _ := e.Payload["to"].(string)
+			delete(holders, from)
+			if to != "" {
+				holders[to] = true
+			}
+		case events.TypeDone, events.TypeUnhook:
+			delete(holders, e.Actor)
+		}
+	}
+	return entries
+}
+
+// eventConcernsAny reports whether e is about one of agents: as its actor,
+// or as the agent, target or session it names.
+func eventConcernsAny(e events.Event, agents map[string]bool) bool {
+	if len(agents) == 0 {
+		return false
+	}
+	if agents[e.Actor] {
+		return true
+	}
+	for _, key := range []string{"agent", "target", "session"} {
+		if v, _ := e.Payload[key].(string); v != "" && agents[v] {
+			return true
+		}
+	}
+	return false
+}
+
+// payloadMentions reports whether any string in payload refers to id.
+func payloadMentions(payload map[string]interface{}, id string) bool {
+	var mentions func(v interface{}) bool
+	mentions = func(v interface{}) bool {
+		switch v := v.(type) {
+		case string:
+			return mentionsIssue(v, id)
+		case []interface{}:
+			for _, item := range v {
+				if mentions(item) {
+					return true
+				}
+			}
+		case map[string]interface{}:
+			for _, item := range v {
+				if mentions(item) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return mentions(map[string]interface{}(payload))
+}
+
+// mentionsIssue reports whether s refers to issue id as a whole word, so
+// gt-abc matches "polecat/Toast/gt-abc@x" and "fix gt-abc." but not
+// "gt-abcd" or the child issue "gt-abc.1".
+func mentionsIssue(s, id string) bool {
+	idChar := func(c byte) bool {
+		return c == '-' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	}
+	for start := 0; ; {
+		i := strings.Index(s[start:], id)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(id)
+		before := i == 0 || !idChar(s[i-1])
+		after := end == len(s) || !idChar(s[end]) && (s[end] != '.' || end+1 == len(s) || !idChar(s[end+1]))
+		if before && after {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+// issueTownlogEntries picks the town log events whose context mentions id.
+func issueTownlogEntries(tl []townlog.Event, id string) []AuditEntry {
+	var entries []AuditEntry
+	for _, e := range tl {
+		if !mentionsIssue(e.Context, id) {
+			continue
+		}
+		entries = append(entries, AuditEntry{
+			Timestamp: e.Timestamp,
+			Source:    "townlog",
+			Type:      string(e.Type),
+			Actor:     e.Agent,
+			Summary:   formatTownlogSummary(e),
+		})
+	}
+	return entries
+}
+
+// issueCommitEntries lists commits on any branch of the rig's repo whose
+// message mentions id. A rig without a repo has none.
+func issueCommitEntries(rigDir, id string) []AuditEntry {
+	var gitArgs []string
+	if bare := filepath.Join(rigDir, ".repo.git"); isDir(bare) {
+		gitArgs = []string{"--git-dir", bare}
+	} else if clone := filepath.Join(rigDir, "mayor", "rig"); isDir(clone) {
+		gitArgs = []string{"-C", clone}
+	} else {
+		return nil
+	}
+	args := append(gitArgs, "log", "--all", "--fixed-strings", "--grep="+id, "--format=%H%x1f%aI%x1f%an%x1f%s%x1f%b%x1e")
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil
+	}
+
+	var entries []AuditEntry
+	for _, rec := range strings.Split(string(out), "\x1e") {
+		parts := strings.Split(strings.TrimSpace(rec), "\x1f")
+		if len(parts) < 5 || !mentionsIssue(parts[3]+"\n"+parts[4], id) {
+			continue
+		}
+		ts, _ := time.Parse(time.RFC3339, parts[1])
+		entries = append(entries, AuditEntry{
+			Timestamp: ts,
+			Source:    "git",
+			Type:      "commit",
+			Actor:     parts[2],
+			Summary:   parts[3],
+			ID:        parts[0][:8],
+		})
+	}
+	return entries
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// issueMREntries lists the merge requests submitted for id: when each was
+// created and, if closed, how it ended.
+func issueMREntries(rigDir, id string) ([]AuditEntry, error) {
+	mrs, err := beads.New(rigDir).List(beads.ListOptions{Status: "all", Label: "gt:merge-request", Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	var entries []AuditEntry
+	for _, mr := range mrs {
+		fields := beads.ParseMRFields(mr)
+		if fields == nil || fields.SourceIssue != id {
+			continue
+		}
+		entries = append(entries, AuditEntry{
+			Timestamp: parseBeadsTimestamp(mr.CreatedAt),
+			Source:    "mr",
+			Type:      "mr_created",
+			Actor:     fields.Worker,
+			Summary:   fmt.Sprintf("Merge request for %s into %s", fields.Branch, fields.Target),
+			ID:        mr.ID,
+		})
+		if ts := parseBeadsTimestamp(mr.ClosedAt); !ts.IsZero() {
+			reason := fields.CloseReason
+			if reason == "" {
+				reason = "closed"
+			}
+			entries = append(entries, AuditEntry{
+				Timestamp: ts,
+				Source:    "mr",
+				Type:      "mr_" + reason,
+				Summary:   fmt.Sprintf("Merge request %s", reason),
+				Details:   fields.MergeCommit,
+				ID:        mr.ID,
+			})
+		}
+	}
+	return entries, nil
+}
+
+// issueMailEntries lists mail whose subject or body mentions id.
+func issueMailEntries(townRoot, id string) ([]AuditEntry, error) {
+	msgs, err := beads.New(townRoot).List(beads.ListOptions{Status: "all", Label: "gt:message", Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	var entries []AuditEntry
+	for _, msg := range msgs {
+		if !mentionsIssue(msg.Title, id) && !mentionsIssue(msg.Description, id) {
+			continue
+		}
+		from := msg.CreatedBy
+		for _, l := range msg.Labels {
+			if strings.HasPrefix(l, "from:") {
+				from = strings.TrimPrefix(l, "from:")
+			}
+		}
+		entries = append(entries, AuditEntry{
+			Timestamp: parseBeadsTimestamp(msg.CreatedAt),
+			Source:    "mail",
+			Type:      "mail",
+			Actor:     from,
+			Summary:   fmt.Sprintf("Mail to %s: %s", msg.Assignee, msg.Title),
+			ID:        msg.ID,
+		})
+	}
+	return entries, nil
+}
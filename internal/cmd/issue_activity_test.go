@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestMentionsIssue(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"gt-abc", true},
+		{"polecat/Toast/gt-abc@mk1", true},
+		{"fix: handle empty config (gt-abc).", true},
+		{"gt-abcd", false},
+		{"xgt-abc", false},
+		{"gt-abc.1", false},
+		{"gt-abcd and gt-abc", true},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := mentionsIssue(tt.s, "gt-abc"); got != tt.want {
+			t.Errorf("mentionsIssue(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestIssueFeedEntries(t *testing.T) {
+	ev := func(ts, typ, actor string, payload map[string]interface{}) events.Event {
+		return events.Event{Timestamp: "2026-03-01T10:" + ts + ":00Z", Type: typ, Actor: actor, Payload: payload}
+	}
+	toast := "gastown/polecats/Toast"
+	evs := []events.Event{
+		ev("00", events.TypeSessionStart, toast, nil), // before the sling
+		ev("01", events.TypeSling, "mayor", map[string]interface{}{"bead": "gt-abc", "target": toast}),
+		ev("02", events.TypeSessionDeath, "daemon", map[string]interface{}{"agent": toast}),
+		ev("03", events.TypeNudge, "witness", map[string]interface{}{"target": toast}),
+		ev("04", events.TypeSling, "mayor", map[string]interface{}{"bead": "gt-other", "target": "gastown/polecats/Nux"}),
+		ev("05", events.TypeDone, toast, map[string]interface{}{"bead": "gt-abc"}),
+		ev("06", events.TypeSessionEnd, toast, nil), // after done
+	}
+
+	entries := issueFeedEntries(evs, "gt-abc")
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Type)
+	}
+	want := []string{events.TypeSling, events.TypeSessionDeath, events.TypeNudge, events.TypeDone}
+	if len(got) != len(want) {
+		t.Fatalf("entries = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entries = %v, want %v", got, want)
+			break
+		}
+	}
+	if entries[0].Timestamp.IsZero() {
+		t.Error("timestamp not parsed")
+	}
+}
@@ -8,7 +8,10 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/failure"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -64,6 +67,10 @@ The exit code determines if this was a crash or expected exit:
   - Exit code 0: Expected exit (logged as 'done' if no other done was recorded)
   - Exit code non-zero: Crash (logged as 'crash')
 
+With --session, the dead pane's output is classified (binary not found,
+auth required, rate limited, crashed, ...), recorded for 'gt failures', and
+the dead session is removed.
+
 Examples:
   gt log crash --agent greenplace/Toast --session gt-greenplace-Toast --exit-code 1`,
 	RunE: runLogCrash,
@@ -342,6 +349,14 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Classify the failure from the dead pane's output. The pane is only
+	// still around because remain-on-exit kept it for us to read.
+	if crashSession != "" {
+		if rec, ok := recordDeadSession(townRoot, crashSession, crashAgent); ok {
+			context += fmt.Sprintf(" [%s]", rec.Reason())
+		}
+	}
+
 	// Log the event
 	logger := townlog.NewLogger(townRoot)
 	if err := logger.Log(eventType, crashAgent, context); err != nil {
@@ -351,6 +366,25 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// recordDeadSession classifies a dead session from its pane, records the
+// failure, and removes the session so it reads as dead to everyone else.
+func recordDeadSession(townRoot, sessionName, agent string) (failure.Record, bool) {
+	t := tmux.NewTmux()
+	rec, ok := failure.CaptureDead(t, sessionName)
+	if !ok {
+		return rec, false
+	}
+	rec.Agent = agent
+	rec.Issue, _ = t.GetEnvironment(sessionName, "GT_ISSUE")
+	if err := failure.Append(townRoot, rec); err != nil {
+		fmt.Fprintf(os.Stderr, "%s recording failure: %v\n", style.WarningPrefix, err)
+	}
+	_ = events.LogFeed(events.TypeSessionFailure, agent,
+		events.SessionFailurePayload(sessionName, agent, rec.Issue, string(rec.Class), string(rec.Recovery), rec.Evidence))
+	_ = t.KillSession(sessionName)
+	return rec, true
+}
+
 // LogEvent is a helper that logs an event from anywhere in the codebase.
 // It finds the town root and logs the event.
 func LogEvent(eventType townlog.EventType, agent, context string) error {
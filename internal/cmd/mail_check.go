@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/maintenance"
 	"github.com/steveyegge/gastown/internal/nudge"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -85,8 +86,9 @@ func runMailCheck(cmd *cobra.Command, args []string) error {
 
 		// Also drain queued nudges (from --mode=queue or --mode=wait-idle fallback).
 		// The nudge queue is per-session; detect our session name.
+		// A frozen town (gt panic) holds queued nudges until it thaws.
 		sessionName := tmux.CurrentSessionName()
-		if sessionName != "" {
+		if sessionName != "" && maintenance.FrozenState(workDir) == nil {
 			queuedNudges, drainErr := nudge.Drain(workDir, sessionName)
 			if drainErr != nil {
 				fmt.Fprintf(os.Stderr, "gt mail check: nudge queue drain error: %v\n", drainErr)
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/maintenance"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	maintenanceReason string
+	maintenanceFor    string
+)
+
+var maintenanceCmd = &cobra.Command{
+	Use:     "maintenance",
+	GroupID: GroupServices,
+	Short:   "Open or close a maintenance window",
+	Long: `Open or close an ad-hoc maintenance window.
+
+During a maintenance window the daemon does not auto-restart agents, send
+nudges, dispatch work, or run heavy patrols (including backups), so the
+fleet doesn't thrash while a human does surgery on the town.
+
+Recurring quiet windows are configured in mayor/daemon.json:
+
+  "quiet_hours": {
+    "timezone": "America/Los_Angeles",
+    "windows": [{"start": "22:00", "end": "06:00", "days": ["mon", "tue"]}],
+    "pause_backups": false
+  }
+
+Quiet hours behave like a maintenance window, except backup patrols keep
+running unless pause_backups is set.
+
+Examples:
+  gt maintenance start --reason "migrating beads db"
+  gt maintenance start --for 2h
+  gt maintenance status
+  gt maintenance end`,
+	RunE: requireSubcommand,
+}
+
+var maintenanceStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Open a maintenance window",
+	Args:  cobra.NoArgs,
+	RunE:  runMaintenanceStart,
+}
+
+var maintenanceEndCmd = &cobra.Command{
+	Use:   "end",
+	Short: "Close the maintenance window",
+	Args:  cobra.NoArgs,
+	RunE:  runMaintenanceEnd,
+}
+
+var maintenanceStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the town is in a quiet window",
+	Args:  cobra.NoArgs,
+	RunE:  runMaintenanceStatus,
+}
+
+func init() {
+	maintenanceStartCmd.Flags().StringVarP(&maintenanceReason, "reason", "r", "", "Why the window is open")
+	maintenanceStartCmd.Flags().StringVar(&maintenanceFor, "for", "", "Close the window automatically after this long (e.g., 90m, 2h)")
+	maintenanceCmd.AddCommand(maintenanceStartCmd)
+	maintenanceCmd.AddCommand(maintenanceEndCmd)
+	maintenanceCmd.AddCommand(maintenanceStatusCmd)
+	rootCmd.AddCommand(maintenanceCmd)
+}
+
+func runMaintenanceStart(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	var duration time.Duration
+	if maintenanceFor != "" {
+		duration, err = parseDuration(maintenanceFor)
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("invalid --for %q", maintenanceFor)
+		}
+	}
+
+	if frozen := maintenance.FrozenState(townRoot); frozen != nil {
+		return fmt.Errorf("the town is frozen by gt panic; end that window first with gt maintenance end")
+	}
+
+	state, err := maintenance.Start(townRoot, maintenanceReason, "human", duration)
+	if err != nil {
+		return fmt.Errorf("opening maintenance window: %w", err)
+	}
+	until := ""
+	if !state.Until.IsZero() {
+		until = state.Until.Format(time.RFC3339)
+	}
+	_ = events.LogAudit(events.TypeMaintenanceStart, state.StartedBy, events.MaintenancePayload(maintenanceReason, until))
+
+	fmt.Printf("%s Maintenance window open\n", style.SuccessPrefix)
+	fmt.Println("  The daemon won't restart agents, send nudges, dispatch work, or run heavy patrols.")
+	if until != "" {
+		fmt.Printf("  Closes automatically at %s\n", state.Until.Local().Format("15:04"))
+	}
+	fmt.Printf("  Close it with: %s\n", style.Dim.Render("gt maintenance end"))
+	return nil
+}
+
+func runMaintenanceEnd(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := maintenance.End(townRoot)
+	if err != nil {
+		return fmt.Errorf("closing maintenance window: %w", err)
+	}
+	if state == nil {
+		fmt.Printf("%s No maintenance window is open\n", style.Dim.Render("○"))
+		return nil
+	}
+	_ = events.LogAudit(events.TypeMaintenanceEnd, state.StartedBy, events.MaintenancePayload(state.Reason, ""))
+
+	fmt.Printf("%s Maintenance window closed after %s\n", style.SuccessPrefix, time.Since(state.StartedAt).Round(time.Second))
+	if state.Frozen {
+		fmt.Println("  Freeze lifted: agents may nudge and act again.")
+		if paused, _, _ := deacon.IsPaused(townRoot); paused {
+			fmt.Printf("  The Deacon is still paused; resume it with: %s\n", style.Dim.Render("gt deacon resume"))
+		}
+	}
+	if qs := maintenanceStatus(townRoot); qs.Active {
+		fmt.Printf("  Still quiet: %s\n", qs)
+	}
+	return nil
+}
+
+func runMaintenanceStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	qs := maintenanceStatus(townRoot)
+	if !qs.Active {
+		fmt.Println("Not in a quiet window; automation is running normally.")
+		return nil
+	}
+	fmt.Printf("%s In %s\n", style.WarningPrefix, qs)
+	if !qs.PauseBackups {
+		fmt.Println("  Backup patrols keep running.")
+	}
+	if frozen := maintenance.FrozenState(townRoot); frozen != nil {
+		fmt.Println("  Frozen by gt panic: agents may not nudge or act on each other.")
+		if frozen.Incident != "" {
+			fmt.Printf("  Incident: %s\n", frozen.Incident)
+		}
+	}
+	return nil
+}
+
+// maintenanceStatus checks the quiet window the daemon sees.
+func maintenanceStatus(townRoot string) maintenance.Status {
+	var cfg *maintenance.QuietHoursConfig
+	if pc := daemon.LoadPatrolConfig(townRoot); pc != nil {
+		cfg = pc.QuietHours
+	}
+	return maintenance.Check(townRoot, cfg, time.Now())
+}
@@ -0,0 +1,417 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/notes"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/summarize"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	mayorAutopilotInterval time.Duration
+	mayorAutopilotLogLines int
+)
+
+// autopilotStallAge is how long hooked or in-progress work may go without
+// an update before the digest lists it as stalled.
+const autopilotStallAge = 2 * time.Hour
+
+var mayorAutopilotCmd = &cobra.Command{
+	Use:   "autopilot",
+	Short: "Let the Mayor triage and dispatch on a schedule",
+	Long: `Run the Mayor on autopilot.
+
+While autopilot is on, the daemon periodically writes a digest of ready
+work, stalled work, and open merge requests and nudges the Mayor to read
+it. The Mayor then triages, prioritizes, and dispatches using normal gt
+commands, with two constraints:
+
+  - Destructive commands (rig remove, polecat nuke, down, ...) are refused
+  - Every gt command the Mayor runs is recorded in the audit log
+
+'gt mayor autopilot stop' is the kill-switch: it takes effect immediately,
+including for commands the Mayor is about to run.
+
+Examples:
+  gt mayor autopilot start                 # Digest every 15m
+  gt mayor autopilot start --interval 30m
+  gt mayor autopilot status
+  gt mayor autopilot log                   # Audit trail
+  gt mayor autopilot stop                  # Kill-switch`,
+	RunE: requireSubcommand,
+}
+
+var mayorAutopilotStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Turn autopilot on",
+	Args:  cobra.NoArgs,
+	RunE:  runMayorAutopilotStart,
+}
+
+var mayorAutopilotStopCmd = &cobra.Command{
+	Use:     "stop",
+	Aliases: []string{"off"},
+	Short:   "Turn autopilot off (kill-switch)",
+	Args:    cobra.NoArgs,
+	RunE:    runMayorAutopilotStop,
+}
+
+var mayorAutopilotStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show autopilot state",
+	Args:  cobra.NoArgs,
+	RunE:  runMayorAutopilotStatus,
+}
+
+var mayorAutopilotLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show the autopilot audit log",
+	Args:  cobra.NoArgs,
+	RunE:  runMayorAutopilotLog,
+}
+
+var mayorAutopilotDigestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Print the latest autopilot digest",
+	Args:  cobra.NoArgs,
+	RunE:  runMayorAutopilotDigest,
+}
+
+// mayorAutopilotTickCmd is run by the daemon on each heartbeat.
+var mayorAutopilotTickCmd = &cobra.Command{
+	Use:    "tick",
+	Short:  "Send a digest to the Mayor if one is due (called by the daemon)",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runMayorAutopilotTick,
+}
+
+func init() {
+	mayorAutopilotStartCmd.Flags().DurationVar(&mayorAutopilotInterval, "interval", mayor.DefaultAutopilotInterval, "How often to send the Mayor a digest")
+	mayorAutopilotLogCmd.Flags().IntVarP(&mayorAutopilotLogLines, "lines", "n", 50, "Number of entries to show (0 for all)")
+
+	mayorAutopilotCmd.AddCommand(mayorAutopilotStartCmd)
+	mayorAutopilotCmd.AddCommand(mayorAutopilotStopCmd)
+	mayorAutopilotCmd.AddCommand(mayorAutopilotStatusCmd)
+	mayorAutopilotCmd.AddCommand(mayorAutopilotLogCmd)
+	mayorAutopilotCmd.AddCommand(mayorAutopilotDigestCmd)
+	mayorAutopilotCmd.AddCommand(mayorAutopilotTickCmd)
+	mayorCmd.AddCommand(mayorAutopilotCmd)
+}
+
+func runMayorAutopilotStart(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if mayorAutopilotInterval < time.Minute {
+		return fmt.Errorf("--interval must be at least 1m")
+	}
+	actor := detectSender()
+	state := &mayor.AutopilotState{
+		Enabled:   true,
+		Interval:  mayorAutopilotInterval.String(),
+		EnabledAt: time.Now().UTC(),
+		EnabledBy: actor,
+	}
+	if err := mayor.SaveAutopilot(townRoot, state); err != nil {
+		return err
+	}
+	_ = mayor.AppendAudit(townRoot, mayor.AuditEntry{Actor: actor, Action: "start", Detail: "interval " + state.Interval})
+
+	fmt.Printf("%s Mayor autopilot on (digest every %s)\n", style.SuccessPrefix, mayorAutopilotInterval)
+	fmt.Printf("   Stop with %s\n", style.Dim.Render("gt mayor autopilot stop"))
+	return nil
+}
+
+func runMayorAutopilotStop(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := mayor.LoadAutopilot(townRoot)
+	if err != nil {
+		// A corrupt state file must not block the kill-switch.
+		state = &mayor.AutopilotState{}
+	}
+	wasOn := state.Enabled
+	state.Enabled = false
+	if err := mayor.SaveAutopilot(townRoot, state); err != nil {
+		return err
+	}
+	_ = mayor.AppendAudit(townRoot, mayor.AuditEntry{Actor: detectSender(), Action: "stop"})
+
+	if wasOn {
+		fmt.Printf("%s Mayor autopilot off\n", style.SuccessPrefix)
+	} else {
+		fmt.Println("Mayor autopilot was already off")
+	}
+	return nil
+}
+
+func runMayorAutopilotStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := mayor.LoadAutopilot(townRoot)
+	if err != nil {
+		return err
+	}
+	if !state.Enabled {
+		fmt.Printf("Mayor autopilot: %s\n", style.Dim.Render("off"))
+		return nil
+	}
+	fmt.Printf("Mayor autopilot: %s\n", style.Success.Render("on"))
+	fmt.Printf("  Interval:    %s\n", state.IntervalDuration())
+	fmt.Printf("  Enabled:     %s by %s\n", state.EnabledAt.Local().Format("2006-01-02 15:04"), state.EnabledBy)
+	if state.LastDigest.IsZero() {
+		fmt.Printf("  Last digest: %s\n", style.Dim.Render("never"))
+	} else {
+		fmt.Printf("  Last digest: %s\n", formatRelativeTime(state.LastDigest.Format(time.RFC3339)))
+	}
+	return nil
+}
+
+func runMayorAutopilotLog(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	entries, err := mayor.ReadAudit(townRoot, mayorAutopilotLogLines)
+	if err != nil {
+		return fmt.Errorf("reading audit log: %w", err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No autopilot activity recorded")
+		return nil
+	}
+	for _, e := range entries {
+		action := e.Action
+		if e.Blocked {
+			action = style.Error.Render("BLOCKED " + action)
+		}
+		fmt.Printf("%s  %-14s %s %s\n", style.Dim.Render(e.Time.Local().Format("2006-01-02 15:04:05")), e.Actor, action, e.Detail)
+	}
+	return nil
+}
+
+func runMayorAutopilotDigest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	data, err := os.ReadFile(mayor.DigestFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no digest yet; autopilot writes one every interval while on")
+		}
+		return err
+	}
+	fmt.Print(string(data))
+	return nil
+}
+
+func runMayorAutopilotTick(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := mayor.LoadAutopilot(townRoot)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if !state.Due(now) {
+		return nil
+	}
+
+	t := tmux.NewTmux()
+	sessionName := mayor.SessionName()
+	if running, _ := t.HasSession(sessionName); !running {
+		// The daemon restarts the mayor; the digest goes out next tick.
+		return nil
+	}
+
+	digest := collectAutopilotDigest(townRoot, now)
+	if err := os.MkdirAll(filepath.Dir(mayor.DigestFile(townRoot)), 0755); err != nil {
+		return fmt.Errorf("creating autopilot dir: %w", err)
+	}
+	if err := os.WriteFile(mayor.DigestFile(townRoot), []byte(digest.Format()), 0644); err != nil {
+		return fmt.Errorf("writing digest: %w", err)
+	}
+
+	msg := fmt.Sprintf("AUTOPILOT: new digest (%d ready, %d stalled, %d open MRs). Read it with: gt mayor autopilot digest",
+		len(digest.Ready), len(digest.Stalled), len(digest.OpenMRs))
+	if err := t.NudgeSession(sessionName, msg); err != nil {
+		return fmt.Errorf("nudging mayor: %w", err)
+	}
+
+	state.LastDigest = now
+	if err := mayor.SaveAutopilot(townRoot, state); err != nil {
+		return err
+	}
+	_ = mayor.AppendAudit(townRoot, mayor.AuditEntry{
+		Actor:  "daemon",
+		Action: "digest",
+		Detail: fmt.Sprintf("%d ready, %d stalled, %d open MRs", len(digest.Ready), len(digest.Stalled), len(digest.OpenMRs)),
+	})
+	return nil
+}
+
+// collectAutopilotDigest gathers ready work, stalled work, and open merge
+// requests across all rigs. Rigs whose beads cannot be read are skipped.
+func collectAutopilotDigest(townRoot string, now time.Time) *mayor.Digest {
+	d := &mayor.Digest{Generated: now}
+	threads := map[string]string{}
+	for _, rigName := range discoverRigs(townRoot) {
+		b := beads.New(filepath.Join(townRoot, rigName))
+
+		if ready, err := b.Ready(); err == nil {
+			for _, issue := range ready {
+				if beads.HasLabel(issue, "gt:merge-request") {
+					continue
+				}
+				d.Ready = append(d.Ready, autopilotItem(rigName, issue, now))
+			}
+		}
+
+		open, err := b.List(beads.ListOptions{Status: "all", Priority: -1})
+		if err != nil {
+			continue
+		}
+		for _, issue := range open {
+			item := autopilotItem(rigName, issue, now)
+			switch {
+			case issue.Status == "closed":
+			case beads.HasLabel(issue, "gt:merge-request"):
+				item.Age = autopilotAge(issue.CreatedAt, now)
+				d.OpenMRs = append(d.OpenMRs, item)
+			case (issue.Status == "hooked" || issue.Status == "in_progress") && item.Age > autopilotStallAge:
+				d.Stalled = append(d.Stalled, item)
+				threads[issue.ID] = issueThread(townRoot, issue)
+			}
+		}
+	}
+
+	byPriority := func(items []mayor.DigestItem) {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Priority < items[j].Priority })
+	}
+	byPriority(d.Ready)
+	byPriority(d.Stalled)
+	byPriority(d.OpenMRs)
+	summarizeStalled(townRoot, d.Stalled, threads)
+	return d
+}
+
+const (
+	// autopilotSummaryChars caps each stalled item's summary.
+	autopilotSummaryChars = 300
+
+	// autopilotSummarized is how many stalled items are summarized, the
+	// number the digest lists per section.
+	autopilotSummarized = 15
+)
+
+// summarizeStalled condenses the thread (description and notes) of the
+// first stalled items so the mayor can triage without opening each one.
+func summarizeStalled(townRoot string, stalled []mayor.DigestItem, threads map[string]string) {
+	if len(stalled) > autopilotSummarized {
+		stalled = stalled[:autopilotSummarized]
+	}
+	s := summarize.New(townRoot)
+	for i := range stalled {
+		thread := threads[stalled[i].ID]
+		if thread == "" {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), summarize.DefaultTimeout)
+		stalled[i].Summary = summarize.Text(ctx, s, summarize.KindThread, thread, autopilotSummaryChars)
+		cancel()
+	}
+}
+
+// issueThread joins an issue's description and its notes (see package
+// notes) into one text.
+func issueThread(townRoot string, issue *beads.Issue) string {
+	var b strings.Builder
+	if desc := strings.TrimSpace(issue.Description); desc != "" {
+		b.WriteString(desc + "\n")
+	}
+	entries, _ := notes.Read(townRoot, issue.ID)
+	for _, e := range entries {
+		fmt.Fprintf(&b, "\n[%s %s] %s\n", e.Time.Format(time.RFC3339), e.Author, strings.TrimSpace(e.Text))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// autopilotItem converts an issue to a digest item aged by its last update.
+func autopilotItem(rigName string, issue *beads.Issue, now time.Time) mayor.DigestItem {
+	return mayor.DigestItem{
+		Rig:      rigName,
+		ID:       issue.ID,
+		Title:    issue.Title,
+		Priority: issue.Priority,
+		Assignee: issue.Assignee,
+		Age:      autopilotAge(issue.UpdatedAt, now),
+	}
+}
+
+// autopilotAge returns how long ago ts (RFC3339) was, or zero if unparseable.
+func autopilotAge(ts string, now time.Time) time.Duration {
+	parsed, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return 0
+	}
+	return now.Sub(parsed)
+}
+
+// checkMayorAutopilot enforces autopilot constraints on commands run from
+// the Mayor's session: destructive commands are refused and everything
+// else is audited. Commands from any other role are unaffected.
+func checkMayorAutopilot(cmd *cobra.Command, args []string) error {
+	if strings.TrimSuffix(os.Getenv("GT_ROLE"), "/") != "mayor" {
+		return nil
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" || !mayor.AutopilotEnabled(townRoot) {
+		return nil
+	}
+
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	detail := strings.TrimSpace(path + " " + strings.Join(args, " "))
+	if !mayor.AutopilotAllows(path) {
+		_ = mayor.AppendAudit(townRoot, mayor.AuditEntry{Actor: "mayor", Action: "command", Detail: detail, Blocked: true})
+		return fmt.Errorf("'gt %s' is not permitted while the Mayor is on autopilot; a human must run it (or stop autopilot: gt mayor autopilot stop)", path)
+	}
+	if !autopilotUnaudited[strings.Fields(path)[0]] {
+		_ = mayor.AppendAudit(townRoot, mayor.AuditEntry{Actor: "mayor", Action: "command", Detail: detail})
+	}
+	return nil
+}
+
+// autopilotUnaudited are read-only or hook-driven commands that run
+// constantly in every agent session; auditing them would bury real actions.
+var autopilotUnaudited = map[string]bool{
+	"prime":   true,
+	"signal":  true,
+	"tap":     true,
+	"hook":    true,
+	"status":  true,
+	"version": true,
+	"help":    true,
+	"notes":   true,
+	"costs":   true,
+}
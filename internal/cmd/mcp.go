@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/mcp"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Role kinds that do assigned work and are limited to their own issues.
+var mcpWorkerRoles = []string{string(session.RolePolecat), string(session.RoleCrew)}
+
+var mcpCmd = &cobra.Command{
+	Use:     "mcp",
+	GroupID: GroupServices,
+	Short:   "Model Context Protocol server for agents",
+	RunE:    requireSubcommand,
+}
+
+var mcpServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve town operations as MCP tools over stdio",
+	Long: `Serve town operations to an agent as Model Context Protocol tools, so it
+can query state and act through structured tool calls instead of running gt
+and parsing its text output.
+
+Tools:
+  whoami          The caller's address and role
+  list_issues     List issues (filter by status, label, assignee)
+  show_issue      Show one issue
+  read_inbox      List the caller's mail
+  read_message    Read one message (marks it read)
+  send_mail       Send mail as the caller
+  post_progress   Comment on an issue
+  request_review  Submit the current branch to the merge queue
+
+The caller is identified like 'gt mail' does (GT_ROLE, then the working
+directory) and its role limits what it can do: tools the role may not use
+are neither listed nor callable. Polecats and crew may only post progress
+on issues assigned to them, and only they can request review.
+
+Register it with the agent runtime, e.g. for Claude Code:
+  claude mcp add gastown -- gt mcp serve`,
+	Args: cobra.NoArgs,
+	RunE: runMCPServe,
+}
+
+func init() {
+	mcpCmd.AddCommand(mcpServeCmd)
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func runMCPServe(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	caller := detectSender()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	srv := newTownMCPServer(townRoot, workDir, caller)
+	return srv.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// mcpRoleKind returns the role kind ("mayor", "polecat", ...) of a sender
+// address, or "unknown".
+func mcpRoleKind(address string) string {
+	switch {
+	case address == "overseer":
+		return string(session.RoleOverseer)
+	case address == "dog" || strings.HasPrefix(address, "dog/"):
+		return "dog"
+	case strings.HasPrefix(address, "deacon/") && address != "deacon/":
+		return string(session.RoleDeacon) // deacon/boot
+	}
+	id, err := session.ParseAddress(address)
+	if err != nil {
+		return "unknown"
+	}
+	return string(id.Role)
+}
+
+// sameAgent reports whether two addresses name the same agent, accepting
+// both "rig/name" and "rig/polecats/name" for polecats.
+func sameAgent(a, b string) bool {
+	ida, errA := session.ParseAddress(a)
+	idb, errB := session.ParseAddress(b)
+	if errA != nil || errB != nil {
+		return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+	}
+	return ida.Address() == idb.Address()
+}
+
+// newTownMCPServer builds the MCP server for caller, running from workDir.
+func newTownMCPServer(townRoot, workDir, caller string) *mcp.Server {
+	role := mcpRoleKind(caller)
+	srv := mcp.NewServer("gastown", Version, role)
+	isWorker := false
+	for _, r := range mcpWorkerRoles {
+		isWorker = isWorker || r == role
+	}
+
+	srv.AddTool(&mcp.Tool{
+		Name:        "whoami",
+		Description: "Return the caller's agent address, role and town.",
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			return map[string]string{"address": caller, "role": role, "town_root": townRoot}, nil
+		},
+	})
+
+	srv.AddTool(&mcp.Tool{
+		Name:        "list_issues",
+		Description: "List issues in the caller's rig (or town). Use assignee \"me\" for the caller's own work.",
+		InputSchema: mcpSchema(map[string]interface{}{
+			"status":   mcpProp("string", `"open" (default), "in_progress", "closed" or "all"`),
+			"label":    mcpProp("string", "Only issues with this label"),
+			"assignee": mcpProp("string", `Only issues assigned to this address, or "me"`),
+			"limit":    mcpProp("integer", "Maximum issues to return (default 50, 0 for all)"),
+		}),
+		Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+			args := struct {
+				Status   string `json:"status"`
+				Label    string `json:"label"`
+				Assignee string `json:"assignee"`
+				Limit    *int   `json:"limit"`
+			}{Status: "open"}
+			if err := mcp.DecodeArgs(raw, &args); err != nil {
+				return nil, err
+			}
+			if args.Assignee == "me" {
+				args.Assignee = caller
+			}
+			limit := 50
+			if args.Limit != nil {
+				limit = *args.Limit
+			}
+			issues, err := beads.New(workDir).List(beads.ListOptions{
+				Status: args.Status, Label: args.Label, Assignee: args.Assignee, Priority: -1, Limit: limit,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if issues == nil {
+				issues = []*beads.Issue{}
+			}
+			return issues, nil
+		},
+	})
+
+	srv.AddTool(&mcp.Tool{
+		Name:        "show_issue",
+		Description: "Show an issue's full details.",
+		InputSchema: mcpSchema(map[string]interface{}{
+			"id": mcpProp("string", "Issue ID, e.g. gt-abc12"),
+		}, "id"),
+		Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+			var args struct {
+				ID string `json:"id"`
+			}
+			if err := mcp.DecodeArgs(raw, &args); err != nil {
+				return nil, err
+			}
+			return beads.New(resolveBeadDir(args.ID)).Show(args.ID)
+		},
+	})
+
+	srv.AddTool(&mcp.Tool{
+		Name:        "read_inbox",
+		Description: "List the caller's mail, newest first.",
+		InputSchema: mcpSchema(map[string]interface{}{
+			"unread_only": mcpProp("boolean", "Only unread messages"),
+		}),
+		Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+			var args struct {
+				UnreadOnly bool `json:"unread_only"`
+			}
+			if err := mcp.DecodeArgs(raw, &args); err != nil {
+				return nil, err
+			}
+			mailbox, err := mail.NewRouter(townRoot).GetMailbox(caller)
+			if err != nil {
+				return nil, fmt.Errorf("getting mailbox: %w", err)
+			}
+			var msgs []*mail.Message
+			if args.UnreadOnly {
+				msgs, err = mailbox.ListUnread()
+			} else {
+				msgs, err = mailbox.List()
+			}
+			if msgs == nil {
+				msgs = []*mail.Message{}
+			}
+			return msgs, err
+		},
+	})
+
+	srv.AddTool(&mcp.Tool{
+		Name:        "read_message",
+		Description: "Read one of the caller's messages and mark it read.",
+		InputSchema: mcpSchema(map[string]interface{}{
+			"id":          mcpProp("string", "Message ID"),
+			"keep_unread": mcpProp("boolean", "Leave the message unread"),
+		}, "id"),
+		Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+			var args struct {
+				ID         string `json:"id"`
+				KeepUnread bool   `json:"keep_unread"`
+			}
+			if err := mcp.DecodeArgs(raw, &args); err != nil {
+				return nil, err
+			}
+			mailbox, err := mail.NewRouter(townRoot).GetMailbox(caller)
+			if err != nil {
+				return nil, fmt.Errorf("getting mailbox: %w", err)
+			}
+			msg, err := mailbox.Get(args.ID)
+			if err != nil {
+				return nil, err
+			}
+			if !args.KeepUnread && !msg.Read {
+				if err := mailbox.MarkRead(args.ID); err != nil {
+					return nil, fmt.Errorf("marking read: %w", err)
+				}
+				msg.Read = true
+			}
+			return msg, nil
+		},
+	})
+
+	srv.AddTool(&mcp.Tool{
+		Name:        "send_mail",
+		Description: "Send mail from the caller, e.g. to \"mayor/\" or \"gastown/witness\".",
+		InputSchema: mcpSchema(map[string]interface{}{
+			"to":      mcpProp("string", "Recipient address"),
+			"subject": mcpProp("string", "Subject line"),
+			"body":    mcpProp("string", "Message body"),
+		}, "to", "subject"),
+		Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+			var args struct {
+				To      string `json:"to"`
+				Subject string `json:"subject"`
+				Body    string `json:"body"`
+			}
+			if err := mcp.DecodeArgs(raw, &args); err != nil {
+				return nil, err
+			}
+			msg := mail.NewMessage(caller, args.To, args.Subject, args.Body)
+			if err := mail.NewRouter(townRoot).Send(msg); err != nil {
+				return nil, err
+			}
+			return map[string]string{"id": msg.ID, "to": args.To}, nil
+		},
+	})
+
+	srv.AddTool(&mcp.Tool{
+		Name:        "post_progress",
+		Description: "Add a progress note (comment) to an issue. Polecats and crew can only post on issues assigned to them.",
+		InputSchema: mcpSchema(map[string]interface{}{
+			"issue": mcpProp("string", "Issue ID"),
+			"note":  mcpProp("string", "Progress note"),
+		}, "issue", "note"),
+		Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+			var args struct {
+				Issue string `json:"issue"`
+				Note  string `json:"note"`
+			}
+			if err := mcp.DecodeArgs(raw, &args); err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(args.Note) == "" {
+				return nil, fmt.Errorf("note is empty")
+			}
+			bd := beads.New(resolveBeadDir(args.Issue))
+			if isWorker {
+				issue, err := bd.Show(args.Issue)
+				if err != nil {
+					return nil, err
+				}
+				if !sameAgent(issue.Assignee, caller) {
+					return nil, fmt.Errorf("%w: %s is assigned to %q, not %s", mcp.ErrPermission, args.Issue, issue.Assignee, caller)
+				}
+			}
+			if _, err := bd.Run("comment", args.Issue, args.Note); err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"issue": args.Issue, "posted": true}, nil
+		},
+	})
+
+	srv.AddTool(&mcp.Tool{
+		Name:        "request_review",
+		Description: "Submit the current branch to the merge queue for the Refinery to review and merge. The session keeps running.",
+		InputSchema: mcpSchema(map[string]interface{}{
+			"issue": mcpProp("string", "Issue the branch implements (default: parsed from the branch name)"),
+		}),
+		Roles: mcpWorkerRoles,
+		Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+			var args struct {
+				Issue string `json:"issue"`
+			}
+			if err := mcp.DecodeArgs(raw, &args); err != nil {
+				return nil, err
+			}
+			gtArgs := []string{"mq", "submit", "--no-cleanup"}
+			if args.Issue != "" {
+				gtArgs = append(gtArgs, "--issue", args.Issue)
+			}
+			c := exec.CommandContext(ctx, "gt", gtArgs...)
+			c.Dir = workDir
+			var out bytes.Buffer
+			c.Stdout, c.Stderr = &out, &out
+			if err := c.Run(); err != nil {
+				return nil, fmt.Errorf("gt mq submit: %v\n%s", err, strings.TrimSpace(out.String()))
+			}
+			return map[string]string{"output": strings.TrimSpace(out.String())}, nil
+		},
+	})
+
+	return srv
+}
+
+// mcpSchema returns an object JSON Schema with the given properties.
+func mcpSchema(props map[string]interface{}, required ...string) map[string]interface{} {
+	s := map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// mcpProp is a typed, described schema property.
+func mcpProp(typ, description string) map[string]interface{} {
+	return map[string]interface{}{"type": typ, "description": description}
+}
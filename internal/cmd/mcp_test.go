@@ -0,0 +1,42 @@
+package cmd
+
+import "testing"
+
+func TestMCPRoleKind(t *testing.T) {
+	tests := map[string]string{
+		"mayor/":           "mayor",
+		"deacon/":          "deacon",
+		"deacon/boot":      "deacon",
+		"dog/alpha":        "dog",
+		"overseer":         "overseer",
+		"gastown/witness":  "witness",
+		"gastown/refinery": "refinery",
+		"gastown/Toast":    "polecat",
+		"gastown/crew/max": "crew",
+		"":                 "unknown",
+	}
+	for addr, want := range tests {
+		if got := mcpRoleKind(addr); got != want {
+			t.Errorf("mcpRoleKind(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
+
+func TestSameAgent(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"gastown/Toast", "gastown/Toast", true},
+		{"gastown/polecats/Toast", "gastown/Toast", true},
+		{"gastown/crew/max", "gastown/crew/max", true},
+		{"gastown/Toast", "gastown/Nux", false},
+		{"gastown/crew/max", "other/crew/max", false},
+		{"", "gastown/Toast", false},
+	}
+	for _, tt := range tests {
+		if got := sameAgent(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameAgent(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -9,13 +9,16 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -26,6 +29,7 @@ var (
 	awaitSignalBackoffMax  string
 	awaitSignalQuiet       bool
 	awaitSignalAgentBead   string
+	awaitSignalBusyRig     string
 )
 
 var moleculeAwaitSignalCmd = &cobra.Command{
@@ -51,6 +55,11 @@ The idle_cycles value is read from the agent bead's "idle" label, enabling
 exponential backoff that persists across invocations. When a signal is
 received, the caller should reset idle:0 on the agent bead.
 
+With --busy-rig, backoff adapts to the rig's sessions as well: while any of
+the rig's agent sessions (other than the witness) produced output within the
+base interval, the timeout stays at the base instead of growing, so busy
+rigs are patrolled often and idle ones back off.
+
 EXIT CODES:
   0 - Signal received or timeout (check output for which)
   1 - Error opening events file
@@ -63,6 +72,10 @@ EXAMPLES:
   gt mol await-signal --agent-bead gt-gastown-witness \
     --backoff-base 30s --backoff-mult 2 --backoff-max 5m
 
+  # Keep the wait short while gastown's polecats are working:
+  gt mol await-signal --agent-bead gt-gastown-witness --busy-rig gastown \
+    --backoff-base 30s --backoff-mult 2 --backoff-max 5m
+
   # On timeout, the agent bead's idle:N label is auto-incremented
   # On signal, caller should reset: gt agent state gt-gastown-witness --set idle=0
 
@@ -90,6 +103,8 @@ func init() {
 		"Maximum interval cap for backoff (e.g., 10m)")
 	moleculeAwaitSignalCmd.Flags().StringVar(&awaitSignalAgentBead, "agent-bead", "",
 		"Agent bead ID for tracking idle cycles (reads/writes idle:N label)")
+	moleculeAwaitSignalCmd.Flags().StringVar(&awaitSignalBusyRig, "busy-rig", "",
+		"Rig whose session activity holds backoff at its base (with --backoff-base)")
 	moleculeAwaitSignalCmd.Flags().BoolVar(&awaitSignalQuiet, "quiet", false,
 		"Suppress output (for scripting)")
 	moleculeAwaitSignalCmd.Flags().BoolVar(&moleculeJSON, "json", false,
@@ -138,8 +153,19 @@ func runMoleculeAwaitSignal(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Calculate full timeout from backoff formula (uses idle cycles)
-	fullTimeout, err := calculateEffectiveTimeout(idleCycles)
+	// Calculate full timeout from backoff formula (uses idle cycles).
+	// A busy rig polls at the base interval regardless of idle cycles.
+	backoffCycles := idleCycles
+	if awaitSignalBusyRig != "" && awaitSignalBackoffBase != "" && backoffCycles > 0 {
+		if busy := busyRigSessions(awaitSignalBusyRig); len(busy) > 0 {
+			backoffCycles = 0
+			if !awaitSignalQuiet && !moleculeJSON {
+				fmt.Printf("%s Rig busy (%s), holding backoff at base\n",
+					style.Dim.Render("⚡"), strings.Join(busy, ", "))
+			}
+		}
+	}
+	fullTimeout, err := calculateEffectiveTimeout(backoffCycles)
 	if err != nil {
 		return fmt.Errorf("invalid timeout configuration: %w", err)
 	}
@@ -302,6 +328,34 @@ func calculateEffectiveTimeout(idleCycles int) (time.Duration, error) {
 	return time.ParseDuration(awaitSignalTimeout)
 }
 
+// busyRigSessions returns the rig's agent sessions, other than its witness,
+// that showed tmux activity within the backoff base interval.
+func busyRigSessions(rigName string) []string {
+	base, err := time.ParseDuration(awaitSignalBackoffBase)
+	if err != nil {
+		return nil
+	}
+	activities, err := tmux.NewTmux().SessionActivities()
+	if err != nil {
+		return nil
+	}
+	prefix := session.PrefixFor(rigName)
+	return activeSessions(activities, prefix+"-", session.WitnessSessionName(prefix), time.Now().Add(-base))
+}
+
+// activeSessions returns, sorted, the sessions named with prefix (except
+// exclude) whose last activity is after since.
+func activeSessions(activities map[string]time.Time, prefix, exclude string, since time.Time) []string {
+	var active []string
+	for name, at := range activities {
+		if strings.HasPrefix(name, prefix) && name != exclude && at.After(since) {
+			active = append(active, name)
+		}
+	}
+	sort.Strings(active)
+	return active
+}
+
 // waitForActivitySignal tails the events file for new activity.
 // townRoot is the Gas Town workspace root; the events file is at
 // <townRoot>/.events.jsonl. Returns immediately when a new event line is
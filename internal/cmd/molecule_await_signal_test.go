@@ -298,3 +298,17 @@ func TestBackoffWindowResumption(t *testing.T) {
 		})
 	}
 }
+
+func TestActiveSessions(t *testing.T) {
+	now := time.Now()
+	activities := map[string]time.Time{
+		"gt-witness":  now,                        // the caller itself
+		"gt-toast":    now.Add(-10 * time.Second), // busy polecat
+		"gt-refinery": now.Add(-time.Hour),        // idle
+		"bd-nux":      now,                        // another rig
+	}
+	got := activeSessions(activities, "gt-", "gt-witness", now.Add(-30*time.Second))
+	if len(got) != 1 || got[0] != "gt-toast" {
+		t.Errorf("activeSessions() = %v, want [gt-toast]", got)
+	}
+}
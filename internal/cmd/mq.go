@@ -32,6 +32,10 @@ var (
 	mqRejectNotify bool
 	mqRejectStdin  bool // Read reason from stdin
 
+	// Approve flags
+	mqApproveComment string
+	mqApproveStdin   bool // Read comment from stdin
+
 	// List command flags
 	mqListReady   bool
 	mqListStatus  string
@@ -47,6 +51,7 @@ var (
 	mqIntegrationLandForce     bool
 	mqIntegrationLandSkipTests bool
 	mqIntegrationLandDryRun    bool
+	mqIntegrationLandNoBisect  bool
 
 	// Integration status flags
 	mqIntegrationStatusJSON bool
@@ -163,6 +168,25 @@ Examples:
 	RunE: runMQReject,
 }
 
+var mqApproveCmd = &cobra.Command{
+	Use:   "approve <rig> <mr-id-or-branch>",
+	Short: "Approve a merge request after review",
+	Long: `Record a review approval on a merge request.
+
+The MR is labelled review:approved and the comment, if given, is added to
+it. Approval does not merge anything: the refinery processes the MR as
+usual. To reject instead, use 'gt mq reject'.
+
+Reviewers dispatched with 'gt sling --review' record their verdict with
+this command or 'gt mq reject'.
+
+Examples:
+  gt mq approve greenplace gp-mr-abc123 --comment "LGTM, tests cover the retry path"
+  gt mq approve greenplace polecat/Nux/gp-xyz --stdin < review.md`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQApprove,
+}
+
 var mqStatusCmd = &cobra.Command{
 	Use:   "status <id>",
 	Short: "Show detailed merge request status",
@@ -243,7 +267,7 @@ Actions:
   1. Verify all MRs targeting integration/<epic> are merged
   2. Verify integration branch exists
   3. Merge integration/<epic> to main (--no-ff)
-  4. Run tests on main
+  4. Run tests on main (on failure, dispatch a bisection polecat; see gt bisect)
   5. Push to origin
   6. Delete integration branch
   7. Update epic status
@@ -252,6 +276,7 @@ Options:
   --force       Land even if some MRs still open
   --skip-tests  Skip test run
   --dry-run     Preview only, make no changes
+  --no-bisect   Don't dispatch a bisection polecat when tests fail
 
 Examples:
   gt mq integration land gt-auth-epic
@@ -302,6 +327,10 @@ func init() {
 	mqRejectCmd.Flags().BoolVar(&mqRejectNotify, "notify", false, "Send mail notification to worker")
 	mqRejectCmd.Flags().BoolVar(&mqRejectStdin, "stdin", false, "Read reason from stdin (avoids shell quoting issues)")
 
+	// Approve flags
+	mqApproveCmd.Flags().StringVarP(&mqApproveComment, "comment", "c", "", "Review comment to add to the MR")
+	mqApproveCmd.Flags().BoolVar(&mqApproveStdin, "stdin", false, "Read comment from stdin (avoids shell quoting issues)")
+
 	// Status flags
 	mqStatusCmd.Flags().BoolVar(&mqStatusJSON, "json", false, "Output as JSON")
 
@@ -310,6 +339,7 @@ func init() {
 	mqCmd.AddCommand(mqRetryCmd)
 	mqCmd.AddCommand(mqListCmd)
 	mqCmd.AddCommand(mqRejectCmd)
+	mqCmd.AddCommand(mqApproveCmd)
 	mqCmd.AddCommand(mqStatusCmd)
 
 	// Integration branch subcommands
@@ -322,6 +352,7 @@ func init() {
 	mqIntegrationLandCmd.Flags().BoolVar(&mqIntegrationLandForce, "force", false, "Land even if some MRs still open")
 	mqIntegrationLandCmd.Flags().BoolVar(&mqIntegrationLandSkipTests, "skip-tests", false, "Skip test run")
 	mqIntegrationLandCmd.Flags().BoolVar(&mqIntegrationLandDryRun, "dry-run", false, "Preview only, make no changes")
+	mqIntegrationLandCmd.Flags().BoolVar(&mqIntegrationLandNoBisect, "no-bisect", false, "Don't dispatch a bisection polecat when tests fail")
 	mqIntegrationCmd.AddCommand(mqIntegrationLandCmd)
 
 	// Integration status flags
@@ -469,3 +500,36 @@ func runMQReject(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runMQApprove(cmd *cobra.Command, args []string) error {
+	if mqApproveStdin {
+		if mqApproveComment != "" {
+			return fmt.Errorf("cannot use --stdin with --comment/-c")
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("reading stdin: %w", err)
+		}
+		mqApproveComment = strings.TrimRight(string(data), "\n")
+	}
+
+	rigName := args[0]
+	mrIDOrBranch := args[1]
+
+	mgr, _, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	result, err := mgr.ApproveMR(mrIDOrBranch, mqApproveComment)
+	if err != nil {
+		return fmt.Errorf("approving MR: %w", err)
+	}
+
+	fmt.Printf("%s Approved: %s\n", style.Bold.Render("✓"), result.Branch)
+	fmt.Printf("  Worker: %s\n", result.Worker)
+	if mqApproveComment != "" {
+		fmt.Printf("  Comment: %s\n", mqApproveComment)
+	}
+	return nil
+}
@@ -644,6 +644,12 @@ func runMqIntegrationLand(cmd *cobra.Command, args []string) error {
 			if err := runTestCommand(landGit.WorkDir(), testCmd); err != nil {
 				// Tests failed - no need to reset, worktree is temporary
 				fmt.Printf("  %s Tests failed\n", style.Bold.Render("✗"))
+				if !mqIntegrationLandNoBisect {
+					fmt.Printf("Dispatching bisection to find the failing MR...\n")
+					if _, bisectErr := startBisection(townRoot, r, epicID, branchName, targetBranch, testCmd, true); bisectErr != nil {
+						fmt.Printf("  %s %v\n", style.WarningPrefix, bisectErr)
+					}
+				}
 				return fmt.Errorf("tests failed: %w", err)
 			}
 			fmt.Printf("  %s Tests passed\n", style.Bold.Render("✓"))
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/takeover"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	msgReplyTo   string
+	msgMailOnly  bool
+	msgInterrupt bool
+)
+
+var msgCmd = &cobra.Command{
+	Use:     "msg <agent> <text>",
+	GroupID: GroupComm,
+	Short:   "Send a direct message to another agent",
+	Long: `Send a short direct message to another agent.
+
+The message is stored as mail, so the conversation is kept as a thread
+(gt mail thread). If the recipient's session is running, the text is also
+injected into it: right away if the agent is idle at its prompt, otherwise
+at its next turn boundary. If the session is not running, is muted (DND),
+or is under human takeover, the message waits in the mailbox.
+
+The injected text tells the recipient how to answer. Replies use --reply
+with the message ID, stay in the same thread, and are injected back into
+the asker's session the same way.
+
+Examples:
+  gt msg gastown/witness "Is the refinery queue blocked?"
+  gt msg gastown/crew/max "Which branch has the auth fix?"
+  gt msg --reply hq-abc123 "Yes - main is red, hold your merge"
+  gt msg mayor "Done with the migration" --mail-only`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runMsg,
+}
+
+func init() {
+	msgCmd.Flags().StringVar(&msgReplyTo, "reply", "", "Reply to a message by ID (the recipient is its sender)")
+	msgCmd.Flags().BoolVar(&msgMailOnly, "mail-only", false, "Don't inject into the recipient's session; leave it in the mailbox")
+	msgCmd.Flags().BoolVar(&msgInterrupt, "interrupt", false, "Inject immediately even if the recipient is busy")
+	rootCmd.AddCommand(msgCmd)
+}
+
+// msgIdleWait is how long gt msg waits for the recipient to reach its prompt
+// before queuing the message for its next turn boundary instead.
+const msgIdleWait = 3 * time.Second
+
+// msgSubjectMax is the subject length taken from the start of the text.
+const msgSubjectMax = 60
+
+func runMsg(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	from := detectSender()
+	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
+	defer router.WaitPendingNotifications()
+
+	var msg *mail.Message
+	if msgReplyTo != "" {
+		if len(args) != 1 {
+			return fmt.Errorf("with --reply, give only the text: gt msg --reply <msg-id> <text>")
+		}
+		mailbox, err := router.GetMailbox(from)
+		if err != nil {
+			return fmt.Errorf("getting mailbox: %w", err)
+		}
+		original, err := mailbox.Get(msgReplyTo)
+		if err != nil {
+			return fmt.Errorf("getting message %s: %w", msgReplyTo, err)
+		}
+		subject := original.Subject
+		if !strings.HasPrefix(subject, "Re: ") {
+			subject = "Re: " + subject
+		}
+		msg = mail.NewReplyMessage(from, original.From, subject, args[0], original)
+		if msg.ThreadID == "" {
+			msg.ThreadID = generateThreadID()
+		}
+		// The question has been answered; don't leave it unread.
+		_ = mailbox.MarkReadOnly(original.ID)
+	} else {
+		if len(args) != 2 {
+			return fmt.Errorf("message text required: gt msg <agent> <text>")
+		}
+		msg = mail.NewMessage(from, normalizeMsgAddress(args[0]), msgSubject(args[1]), args[1])
+	}
+	if strings.TrimSpace(msg.Body) == "" {
+		return fmt.Errorf("message text is empty")
+	}
+
+	sessionID, skip := msgTargetSession(townRoot, router, msg.To)
+	if sessionID != "" {
+		// We inject the text ourselves; a "you have mail" notice on top
+		// would be noise.
+		msg.SuppressNotify = true
+	}
+	if err := router.Send(msg); err != nil {
+		return fmt.Errorf("sending message: %w", err)
+	}
+	_ = events.LogFeed(events.TypeMail, from, events.MailPayload(msg.To, msg.Subject))
+
+	delivery := "mailbox"
+	if sessionID != "" {
+		how, err := injectMsg(townRoot, sessionID, msg)
+		if err != nil {
+			style.PrintWarning("could not inject into %s (message is in the mailbox): %v", sessionID, err)
+		} else {
+			delivery = how
+		}
+	}
+
+	fmt.Printf("%s Message %s sent to %s (%s)\n", style.Bold.Render("✓"), style.Dim.Render(msg.ID), msg.To, delivery)
+	if skip != "" {
+		fmt.Printf("  %s\n", style.Dim.Render(skip))
+	}
+	fmt.Printf("  Thread: %s\n", style.Dim.Render(msg.ThreadID))
+	return nil
+}
+
+// normalizeMsgAddress expands town-level shortcuts to mail addresses.
+func normalizeMsgAddress(target string) string {
+	switch target {
+	case "mayor", "deacon":
+		return target + "/"
+	}
+	return target
+}
+
+// msgSubject derives a mail subject from the first line of the text.
+func msgSubject(text string) string {
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(text), "\n", 2)[0])
+	if len(line) > msgSubjectMax {
+		line = strings.TrimSpace(line[:msgSubjectMax-3]) + "..."
+	}
+	return line
+}
+
+// msgTargetSession returns the live tmux session to inject a message into,
+// or "" if it should only go to the mailbox. skip explains why a running
+// session was passed over.
+func msgTargetSession(townRoot string, router *mail.Router, address string) (sessionID, skip string) {
+	if msgMailOnly || address == "overseer" {
+		return "", ""
+	}
+	t := tmux.NewTmux()
+	for _, id := range mail.AddressToSessionIDs(address) {
+		if exists, _ := t.HasSession(id); !exists {
+			continue
+		}
+		if router.IsRecipientMuted(address) {
+			return "", "Recipient has DND enabled - left in mailbox"
+		}
+		if takeover.IsTakenOver(townRoot, id) {
+			return "", "Recipient is under human takeover - left in mailbox"
+		}
+		return id, ""
+	}
+	return "", ""
+}
+
+// formatMsgInjection is the text injected into the recipient's session.
+func formatMsgInjection(msg *mail.Message) string {
+	kind := "Message"
+	if msg.Type == mail.TypeReply {
+		kind = "Reply"
+	}
+	return fmt.Sprintf("%s %s from %s: %s (answer with: gt msg --reply %s \"<text>\")",
+		kind, msg.ID, msg.From, strings.Join(strings.Fields(msg.Body), " "), msg.ID)
+}
+
+// injectMsg delivers a message into a live session and returns how it was
+// delivered. An agent at its prompt gets it right away; a busy one gets it
+// at its next turn boundary via the nudge queue, unless --interrupt is set.
+func injectMsg(townRoot, sessionID string, msg *mail.Message) (string, error) {
+	t := tmux.NewTmux()
+	text := formatMsgInjection(msg)
+	if msgInterrupt {
+		return "injected", t.NudgeSession(sessionID, fmt.Sprintf("[from %s] %s", msg.From, text))
+	}
+
+	err := t.WaitForIdle(sessionID, msgIdleWait)
+	if err == nil {
+		return "injected", t.NudgeSession(sessionID, fmt.Sprintf("[from %s] %s", msg.From, text))
+	}
+	if errors.Is(err, tmux.ErrSessionNotFound) || errors.Is(err, tmux.ErrNoServer) {
+		return "", err
+	}
+	return "queued for next turn", nudge.Enqueue(townRoot, sessionID, nudge.QueuedNudge{
+		Sender:  msg.From,
+		Message: text,
+	})
+}
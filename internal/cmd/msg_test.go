@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestMsgSubject(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Is main green?", "Is main green?"},
+		{"  first line\nsecond line", "first line"},
+		{strings.Repeat("a", 80), strings.Repeat("a", 57) + "..."},
+	}
+	for _, tt := range tests {
+		if got := msgSubject(tt.text); got != tt.want {
+			t.Errorf("msgSubject(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMsgInjection(t *testing.T) {
+	original := mail.NewMessage("gastown/Toast", "gastown/witness", "q", "Is the\nqueue blocked?")
+	got := formatMsgInjection(original)
+	for _, want := range []string{"Message " + original.ID, "gastown/Toast", "Is the queue blocked?", "gt msg --reply " + original.ID} {
+		if !strings.Contains(got, want) {
+			t.Errorf("injection %q missing %q", got, want)
+		}
+	}
+	if strings.Contains(got, "\n") {
+		t.Errorf("injection must be a single line: %q", got)
+	}
+
+	reply := mail.NewReplyMessage("gastown/witness", "gastown/Toast", "Re: q", "no", original)
+	if got := formatMsgInjection(reply); !strings.HasPrefix(got, "Reply ") {
+		t.Errorf("reply injection = %q, want Reply prefix", got)
+	}
+	if reply.ThreadID != original.ThreadID {
+		t.Errorf("reply thread = %q, want %q", reply.ThreadID, original.ThreadID)
+	}
+}
+
+func TestNormalizeMsgAddress(t *testing.T) {
+	for in, want := range map[string]string{"mayor": "mayor/", "deacon": "deacon/", "gastown/witness": "gastown/witness"} {
+		if got := normalizeMsgAddress(in); got != want {
+			t.Errorf("normalizeMsgAddress(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/notes"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	notesShowJSON bool
+	notesShowTail int
+	notesStdin    bool
+)
+
+var notesCmd = &cobra.Command{
+	Use:     "notes",
+	GroupID: GroupWork,
+	Short:   "Per-issue progress notes shared with supervisors",
+	Long: `Read and append an issue's progress notes.
+
+Notes are an append-only scratchpad per issue, stored under the town's
+.runtime/notes directory. Agents record progress as they work; the witness
+uses the last update time to spot agents that have stopped making progress,
+and gt prime replays recent notes when a session resumes so context that
+lived only in the agent's conversation is not lost.
+
+Examples:
+  gt notes append gt-abc "Reproduced; root cause is in parser.go"
+  gt notes append gt-abc --stdin <<'EOF'
+  Tests pass locally. Remaining: docs.
+  EOF
+  gt notes show gt-abc
+  gt notes show gt-abc --tail 3`,
+	RunE: requireSubcommand,
+}
+
+var notesShowCmd = &cobra.Command{
+	Use:   "show <issue>",
+	Short: "Show an issue's notes",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNotesShow,
+}
+
+var notesAppendCmd = &cobra.Command{
+	Use:   "append <issue> [text...]",
+	Short: "Append a progress note to an issue",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runNotesAppend,
+}
+
+func init() {
+	notesShowCmd.Flags().BoolVar(&notesShowJSON, "json", false, "Output as JSON")
+	notesShowCmd.Flags().IntVar(&notesShowTail, "tail", 0, "Show only the last N entries")
+	notesAppendCmd.Flags().BoolVar(&notesStdin, "stdin", false, "Read note text from stdin (avoids shell quoting issues)")
+
+	notesCmd.AddCommand(notesShowCmd)
+	notesCmd.AddCommand(notesAppendCmd)
+	rootCmd.AddCommand(notesCmd)
+}
+
+func runNotesShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	entries, err := notes.Read(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	entries = notes.Tail(entries, notesShowTail)
+
+	if notesShowJSON {
+		if entries == nil {
+			entries = []notes.Entry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("No notes for %s\n", args[0])
+		return nil
+	}
+	for _, e := range entries {
+		header := e.Time.Local().Format("2006-01-02 15:04")
+		if e.Author != "" {
+			header += "  " + e.Author
+		}
+		fmt.Println(style.Dim.Render(header))
+		fmt.Println(e.Text)
+		fmt.Println()
+	}
+	return nil
+}
+
+func runNotesAppend(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	text := strings.Join(args[1:], " ")
+	if notesStdin {
+		if text != "" {
+			return fmt.Errorf("cannot use --stdin with text arguments")
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("reading stdin: %w", err)
+		}
+		text = string(data)
+	}
+
+	if err := notes.Append(townRoot, args[0], detectSender(), text); err != nil {
+		return err
+	}
+	fmt.Printf("%s Noted on %s\n", style.SuccessPrefix, args[0])
+	return nil
+}
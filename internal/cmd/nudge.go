@@ -22,12 +22,16 @@ import (
 )
 
 var (
-	nudgeMessageFlag  string
-	nudgeForceFlag    bool
-	nudgeStdinFlag    bool
-	nudgeIfFreshFlag  bool
-	nudgeModeFlag     string
-	nudgePriorityFlag string
+	nudgeMessageFlag   string
+	nudgeForceFlag     bool
+	nudgeStdinFlag     bool
+	nudgeIfFreshFlag   bool
+	nudgeModeFlag      string
+	nudgePriorityFlag  string
+	nudgeAtFlag        string
+	nudgeInFlag        string
+	nudgeScheduledFlag bool
+	nudgeCancelFlag    string
 )
 
 // Nudge delivery modes.
@@ -46,11 +50,15 @@ const (
 func init() {
 	rootCmd.AddCommand(nudgeCmd)
 	nudgeCmd.Flags().StringVarP(&nudgeMessageFlag, "message", "m", "", "Message to send")
-	nudgeCmd.Flags().BoolVarP(&nudgeForceFlag, "force", "f", false, "Send even if target has DND enabled")
+	nudgeCmd.Flags().BoolVarP(&nudgeForceFlag, "force", "f", false, "Send even if target has DND enabled or is taken over")
 	nudgeCmd.Flags().BoolVar(&nudgeStdinFlag, "stdin", false, "Read message from stdin (avoids shell quoting issues)")
 	nudgeCmd.Flags().BoolVar(&nudgeIfFreshFlag, "if-fresh", false, "Only send if caller's tmux session is <60s old (suppresses compaction nudges)")
 	nudgeCmd.Flags().StringVar(&nudgeModeFlag, "mode", NudgeModeImmediate, "Delivery mode: immediate (default), queue, or wait-idle")
 	nudgeCmd.Flags().StringVar(&nudgePriorityFlag, "priority", nudge.PriorityNormal, "Queue priority: normal (default) or urgent")
+	nudgeCmd.Flags().StringVar(&nudgeAtFlag, "at", "", "Deliver later, at a time (e.g., 14:00, \"2026-03-10 09:30\")")
+	nudgeCmd.Flags().StringVar(&nudgeInFlag, "in", "", "Deliver later, after a delay (e.g., 30m, 2h)")
+	nudgeCmd.Flags().BoolVar(&nudgeScheduledFlag, "scheduled", false, "List scheduled nudges")
+	nudgeCmd.Flags().StringVar(&nudgeCancelFlag, "cancel", "", "Cancel a scheduled nudge by ID")
 }
 
 var nudgeCmd = &cobra.Command{
@@ -62,6 +70,11 @@ var nudgeCmd = &cobra.Command{
 Delivers a message to any worker's Claude Code session: polecats, crew,
 witness, refinery, mayor, or deacon.
 
+Scheduling (--at, --in):
+  The nudge is stored and delivered later by the daemon, with the same
+  mode, priority, and DND handling as a direct nudge. Use it for timed
+  reminders. List pending nudges with --scheduled; remove one with --cancel.
+
 Delivery modes (--mode):
   immediate  Send directly via tmux send-keys (default). Interrupts in-flight
              work but guarantees immediate delivery.
@@ -94,6 +107,7 @@ Channel syntax:
 DND (Do Not Disturb):
   If the target has DND enabled (gt dnd on), the nudge is skipped.
   Use --force to override DND and send anyway.
+  Sessions taken over by a human (gt takeover) are skipped the same way.
 
 Examples:
   gt nudge greenplace/furiosa "Check your mail and start working"
@@ -102,6 +116,10 @@ Examples:
   gt nudge witness "Check polecat health"
   gt nudge deacon session-started
   gt nudge channel:workers "New priority work available"
+  gt nudge gastown/Toast -m "Wrap up before the merge window" --at 14:00
+  gt nudge mayor -m "Review the convoy" --in 30m
+  gt nudge --scheduled
+  gt nudge --cancel sn-1a2b3c4d
 
   # Use --stdin for messages with special characters or formatting:
   gt nudge gastown/alpha --stdin <<'EOF'
@@ -109,7 +127,12 @@ Examples:
   - Task 1: complete
   - Task 2: in progress
   EOF`,
-	Args: cobra.RangeArgs(1, 2),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if nudgeScheduledFlag || nudgeCancelFlag != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.RangeArgs(1, 2)(cmd, args)
+	},
 	RunE: runNudge,
 }
 
@@ -122,7 +145,7 @@ const ifFreshMaxAge = 60 * time.Second
 var waitIdleTimeout = 15 * time.Second
 
 // deliverNudge routes a nudge based on the --mode flag.
-// For "immediate" mode: sends directly via tmux (current behavior).
+// For "immediate" mode: sends directly via tmux, queueing if the pane is busy.
 // For "queue" mode: writes to the nudge queue for cooperative delivery.
 // For "wait-idle" mode: waits for idle, then delivers or falls back to queue.
 func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) error {
@@ -175,7 +198,20 @@ func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) error {
 		return nil
 
 	default: // NudgeModeImmediate
-		return t.NudgeSession(sessionName, prefixedMessage)
+		err := t.NudgeSession(sessionName, prefixedMessage)
+		// A pane stuck on a dialog or an open editor would swallow the
+		// nudge; queue it for the agent's next turn instead.
+		if errors.Is(err, tmux.ErrPaneBusy) && townRoot != "" {
+			if qErr := nudge.Enqueue(townRoot, sessionName, nudge.QueuedNudge{
+				Sender:   sender,
+				Message:  message,
+				Priority: nudgePriorityFlag,
+			}); qErr == nil {
+				fmt.Fprintf(os.Stderr, "%s not taking input (%v); nudge queued\n", sessionName, err)
+				return nil
+			}
+		}
+		return err
 	}
 }
 
@@ -207,6 +243,12 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 	if !validNudgePriorities[nudgePriorityFlag] {
 		return fmt.Errorf("invalid --priority %q: must be one of normal, urgent", nudgePriorityFlag)
 	}
+	if nudgeScheduledFlag {
+		return runNudgeListScheduled()
+	}
+	if nudgeCancelFlag != "" {
+		return runNudgeCancelScheduled(nudgeCancelFlag)
+	}
 
 	// --if-fresh: skip nudge if the caller's tmux session is older than 60s.
 	// This prevents compaction/clear SessionStart hooks from spamming the deacon.
@@ -270,6 +312,21 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	// Scheduled delivery: the daemon runs gt nudge on behalf of whoever
+	// scheduled the nudge, recorded in the entry it is delivering.
+	if id := os.Getenv(nudge.ScheduleIDEnv); id != "" {
+		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+			if s, err := nudge.ReadClaimed(townRoot, id); err == nil && s.Sender != "" {
+				sender = s.Sender
+			}
+		}
+	}
+
+	// --at/--in: store for the daemon to deliver later.
+	if nudgeAtFlag != "" || nudgeInFlag != "" {
+		return runNudgeSchedule(target, message, sender)
+	}
+
 	// Handle channel syntax: channel:<name>
 	if strings.HasPrefix(target, "channel:") {
 		channelName := strings.TrimPrefix(target, "channel:")
@@ -285,6 +342,11 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 			fmt.Printf("  Use %s to override\n", style.Bold.Render("--force"))
 			return nil
 		}
+		if isTakenOverTarget(townRoot, target) {
+			fmt.Printf("%s Target is under human takeover - nudge skipped\n", style.Dim.Render("○"))
+			fmt.Printf("  Use %s to override\n", style.Bold.Render("--force"))
+			return nil
+		}
 	}
 
 	t := tmux.NewTmux()
@@ -546,6 +608,7 @@ func runNudgeChannel(channelName, message, sender string) error {
 //   - Wildcard: "gastown/polecats/*" → all polecat sessions in gastown
 //   - Role: "*/witness" → all witness sessions
 //   - Special: "mayor", "deacon" → gt-{town}-mayor, gt-{town}-deacon
+//
 // townName is used to generate the correct session names for mayor/deacon.
 func resolveNudgePattern(pattern string, agents []*AgentSession) []string {
 	var results []string
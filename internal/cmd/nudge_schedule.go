@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// runNudgeSchedule stores a nudge for the daemon to deliver at --at or
+// after --in.
+func runNudgeSchedule(target, message, sender string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	now := time.Now()
+	deliverAt, err := nudgeDeliverAt(nudgeAtFlag, nudgeInFlag, now)
+	if err != nil {
+		return err
+	}
+	target, err = scheduledNudgeTarget(target)
+	if err != nil {
+		return err
+	}
+
+	id, err := nudge.Schedule(townRoot, nudge.ScheduledNudge{
+		Target:    target,
+		Sender:    sender,
+		Message:   message,
+		Mode:      nudgeModeFlag,
+		Priority:  nudgePriorityFlag,
+		Force:     nudgeForceFlag,
+		DeliverAt: deliverAt,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Scheduled nudge %s for %s at %s (in %s)\n", style.Bold.Render("✓"),
+		id, target, deliverAt.Format("Mon 15:04"), deliverAt.Sub(now).Round(time.Minute))
+	fmt.Printf("  Cancel with: gt nudge --cancel %s\n", id)
+	return nil
+}
+
+// nudgeDeliverAt resolves --at or --in to a delivery time. --at accepts a
+// time of day (the next occurrence), or a date and time.
+func nudgeDeliverAt(at, in string, now time.Time) (time.Time, error) {
+	if at != "" && in != "" {
+		return time.Time{}, fmt.Errorf("--at and --in are mutually exclusive")
+	}
+	if in != "" {
+		d, err := time.ParseDuration(in)
+		if err != nil || d <= 0 {
+			return time.Time{}, fmt.Errorf("invalid --in %q: want a duration like 30m or 2h", in)
+		}
+		return now.Add(d), nil
+	}
+
+	for _, layout := range []string{"15:04", "3:04pm", "3pm"} {
+		t, err := time.ParseInLocation(layout, strings.ToLower(at), now.Location())
+		if err != nil {
+			continue
+		}
+		y, m, d := now.Date()
+		when := time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !when.After(now) {
+			when = when.AddDate(0, 0, 1)
+		}
+		return when, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", time.RFC3339} {
+		when, err := time.ParseInLocation(layout, at, now.Location())
+		if err != nil {
+			continue
+		}
+		if !when.After(now) {
+			return time.Time{}, fmt.Errorf("--at %s is in the past", at)
+		}
+		return when, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --at %q: want a time like 14:00 or 2pm, or \"2006-01-02 15:04\"", at)
+}
+
+// scheduledNudgeTarget resolves targets that depend on the caller's context,
+// since the daemon delivers from the town root. "witness" and "refinery"
+// mean the caller's rig, so they become session names now.
+func scheduledNudgeTarget(target string) (string, error) {
+	if target != "witness" && target != "refinery" {
+		return target, nil
+	}
+	roleInfo, err := GetRole()
+	if err != nil || roleInfo.Rig == "" {
+		return "", fmt.Errorf("cannot determine rig for %s shortcut; use <rig>/%s's session name", target, target)
+	}
+	rigPrefix := session.PrefixFor(roleInfo.Rig)
+	if target == "witness" {
+		return session.WitnessSessionName(rigPrefix), nil
+	}
+	return session.RefinerySessionName(rigPrefix), nil
+}
+
+func runNudgeListScheduled() error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	pending, err := nudge.ListScheduled(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Printf("%s No scheduled nudges\n", style.Dim.Render("○"))
+		return nil
+	}
+	now := time.Now()
+	for _, s := range pending {
+		when := s.DeliverAt.Format("Mon 15:04")
+		if s.DeliverAt.After(now) {
+			when += style.Dim.Render(fmt.Sprintf(" (in %s)", s.DeliverAt.Sub(now).Round(time.Minute)))
+		} else {
+			when += style.Dim.Render(" (due)")
+		}
+		fmt.Printf("%s  %s  → %s  %s\n", style.Bold.Render(s.ID), when, s.Target, truncateNudgeMessage(s.Message))
+	}
+	return nil
+}
+
+func runNudgeCancelScheduled(id string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := nudge.CancelScheduled(townRoot, id); err != nil {
+		if errors.Is(err, nudge.ErrScheduledNotFound) {
+			return fmt.Errorf("no scheduled nudge %q (see gt nudge --scheduled)", id)
+		}
+		return err
+	}
+	fmt.Printf("%s Cancelled scheduled nudge %s\n", style.Bold.Render("✓"), id)
+	return nil
+}
+
+func truncateNudgeMessage(msg string) string {
+	msg = strings.Join(strings.Fields(msg), " ")
+	if r := []rune(msg); len(r) > 60 {
+		return string(r[:59]) + "…"
+	}
+	return msg
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNudgeDeliverAt(t *testing.T) {
+	now := time.Date(2026, 3, 10, 13, 30, 0, 0, time.Local)
+	tests := []struct {
+		at, in  string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "30m", want: now.Add(30 * time.Minute)},
+		{in: "2h", want: now.Add(2 * time.Hour)},
+		{at: "14:00", want: time.Date(2026, 3, 10, 14, 0, 0, 0, time.Local)},
+		{at: "2pm", want: time.Date(2026, 3, 10, 14, 0, 0, 0, time.Local)},
+		{at: "2:15PM", want: time.Date(2026, 3, 10, 14, 15, 0, 0, time.Local)},
+		{at: "09:00", want: time.Date(2026, 3, 11, 9, 0, 0, 0, time.Local)}, // passed today: tomorrow
+		{at: "2026-03-12 08:45", want: time.Date(2026, 3, 12, 8, 45, 0, 0, time.Local)},
+		{at: "2026-03-01 08:45", wantErr: true}, // in the past
+		{at: "soon", wantErr: true},
+		{in: "-5m", wantErr: true},
+		{in: "tomorrow", wantErr: true},
+		{at: "14:00", in: "30m", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := nudgeDeliverAt(tt.at, tt.in, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("nudgeDeliverAt(%q, %q) error = %v, wantErr %v", tt.at, tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("nudgeDeliverAt(%q, %q) = %v, want %v", tt.at, tt.in, got, tt.want)
+		}
+	}
+}
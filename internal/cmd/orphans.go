@@ -37,6 +37,7 @@ This command scans for:
 2. Unmerged polecat worktree branches (always shown)
 
 Note: --days and --all only apply to orphaned commits, not polecat branches.
+Use 'gt orphans branches' to audit polecat branches nothing refers to.
 
 Examples:
   gt orphans              # Last 7 days (default), infers rig from cwd
@@ -0,0 +1,387 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	gitpkg "github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Zombie branch classes.
+const (
+	zombieMerged    = "merged"    // every commit is in the default branch (incl. squash/rebase)
+	zombieEmpty     = "empty"     // no commits beyond the default branch
+	zombieAbandoned = "abandoned" // has commits found nowhere else
+)
+
+var (
+	orphansBranchesClean     bool
+	orphansBranchesAbandoned bool
+	orphansBranchesDryRun    bool
+	orphansBranchesForce     bool
+	orphansBranchesRescueDir string
+	orphansBranchesJSON      bool
+)
+
+var orphansBranchesCmd = &cobra.Command{
+	Use:   "branches",
+	Short: "Find and clean up zombie polecat branches",
+	Long: `Audit polecat/* branches that nothing refers to any more.
+
+A polecat branch, local or on origin, is a zombie when it has no open
+issue, no open merge request, and no polecat worktree checked out on it.
+Zombies are classified as:
+
+  merged     All commits are already in the default branch (including
+             squash and rebase merges)
+  empty      No commits beyond the default branch
+  abandoned  Has commits found nowhere else — work that may be lost
+
+--clean deletes merged and empty zombies, locally and on origin. Abandoned
+branches are only deleted with --include-abandoned, and each is first
+saved as a rescue bundle (default: <rig>/.runtime/rescue-bundles/).
+Restore one with:
+
+  git fetch <bundle> '+refs/heads/*:refs/heads/*'
+
+The witness runs 'gt orphans branches --clean --force' during patrol, so
+only abandoned branches accumulate for review.
+
+Examples:
+  gt orphans branches                       # Report zombies in the current rig
+  gt orphans branches --rig=gastown --json
+  gt orphans branches --clean --dry-run     # Show what would be deleted
+  gt orphans branches --clean --include-abandoned`,
+	Args: cobra.NoArgs,
+	RunE: runOrphansBranches,
+}
+
+func init() {
+	orphansBranchesCmd.Flags().BoolVar(&orphansBranchesClean, "clean", false, "Delete merged and empty zombie branches")
+	orphansBranchesCmd.Flags().BoolVar(&orphansBranchesAbandoned, "include-abandoned", false, "With --clean, also delete abandoned branches (after writing rescue bundles)")
+	orphansBranchesCmd.Flags().BoolVar(&orphansBranchesDryRun, "dry-run", false, "Preview cleanup without deleting")
+	orphansBranchesCmd.Flags().BoolVarP(&orphansBranchesForce, "force", "f", false, "Skip confirmation prompt")
+	orphansBranchesCmd.Flags().StringVar(&orphansBranchesRescueDir, "rescue-dir", "", "Directory for rescue bundles (default: <rig>/.runtime/rescue-bundles)")
+	orphansBranchesCmd.Flags().BoolVar(&orphansBranchesJSON, "json", false, "Output as JSON")
+
+	orphansCmd.AddCommand(orphansBranchesCmd)
+}
+
+// ZombieBranch is a polecat branch with no open issue, MR, or live polecat.
+type ZombieBranch struct {
+	Branch  string `json:"branch"`
+	Local   bool   `json:"local"`  // exists in the rig's repo
+	Remote  bool   `json:"remote"` // exists on origin
+	Class   string `json:"class"`
+	Unique  int    `json:"unique_commits"` // commits not in the default branch
+	Issue   string `json:"issue,omitempty"`
+	Polecat string `json:"polecat,omitempty"`
+	Latest  string `json:"latest,omitempty"` // subject of the tip commit
+}
+
+// ref returns the ref to inspect the branch through.
+func (z ZombieBranch) ref() string {
+	if z.Local {
+		return z.Branch
+	}
+	return "origin/" + z.Branch
+}
+
+// classifyZombieBranch classifies a zombie from its commits ahead of the
+// default branch and how many of those are not in it by content.
+func classifyZombieBranch(ahead, unique int) string {
+	switch {
+	case ahead == 0:
+		return zombieEmpty
+	case unique == 0:
+		return zombieMerged
+	default:
+		return zombieAbandoned
+	}
+}
+
+func runOrphansBranches(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	var r *rig.Rig
+	if orphansRig != "" {
+		if _, r, err = getRig(orphansRig); err != nil {
+			return err
+		}
+	} else if _, r, err = findCurrentRig(townRoot); err != nil {
+		return fmt.Errorf("not in a rig directory. Use --rig <name> to specify the target rig, or run from within a rig directory")
+	}
+
+	mgr, _, err := getPolecatManager(r.Name)
+	if err != nil {
+		return err
+	}
+	repo, err := mgr.RepoBase()
+	if err != nil {
+		return err
+	}
+	// Non-fatal: the audit works from the refs we have.
+	_ = repo.FetchPrune("origin")
+
+	zombies, err := findZombieBranches(repo, r)
+	if err != nil {
+		return err
+	}
+
+	if orphansBranchesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if zombies == nil {
+			zombies = []ZombieBranch{}
+		}
+		return enc.Encode(zombies)
+	}
+
+	if len(zombies) == 0 {
+		fmt.Printf("%s No zombie polecat branches in %s\n", style.Bold.Render("✓"), r.Name)
+		return nil
+	}
+	printZombieBranches(r.Name, zombies)
+	if !orphansBranchesClean {
+		fmt.Printf("%s\n", style.Dim.Render("Run with --clean to delete merged and empty branches (--include-abandoned for the rest)"))
+		return nil
+	}
+
+	var targets []ZombieBranch
+	for _, z := range zombies {
+		if z.Class != zombieAbandoned || orphansBranchesAbandoned {
+			targets = append(targets, z)
+		}
+	}
+	if len(targets) == 0 {
+		fmt.Printf("%s Only abandoned branches found; use --include-abandoned to delete them\n", style.Dim.Render("ℹ"))
+		return nil
+	}
+	if orphansBranchesDryRun {
+		fmt.Printf("%s Dry run - would delete %d branch(es)\n", style.Dim.Render("ℹ"), len(targets))
+		return nil
+	}
+	if !orphansBranchesForce {
+		fmt.Printf("Delete %d branch(es)? [y/N] ", len(targets))
+		var response string
+		_, _ = fmt.Scanln(&response)
+		if strings.ToLower(strings.TrimSpace(response)) != "y" {
+			fmt.Printf("%s Canceled\n", style.Dim.Render("ℹ"))
+			return nil
+		}
+	}
+
+	rescueDir := orphansBranchesRescueDir
+	if rescueDir == "" {
+		rescueDir = filepath.Join(constants.RigRuntimePath(r.Path), "rescue-bundles")
+	}
+	deleted, failed := cleanZombieBranches(repo, targets, baseRefFor(repo, r.DefaultBranch()), rescueDir)
+	fmt.Printf("\n%s Deleted %d zombie branch(es)", style.Bold.Render("✓"), deleted)
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d branch(es) could not be deleted", failed)
+	}
+	return nil
+}
+
+// baseRefFor returns the ref of the default branch to compare against,
+// preferring origin's copy.
+func baseRefFor(repo *gitpkg.Git, defaultBranch string) string {
+	if _, err := repo.Rev("origin/" + defaultBranch); err == nil {
+		return "origin/" + defaultBranch
+	}
+	return defaultBranch
+}
+
+// findZombieBranches lists the rig's polecat branches (local and on origin)
+// and returns those with no open issue, open MR, or live polecat.
+func findZombieBranches(repo *gitpkg.Git, r *rig.Rig) ([]ZombieBranch, error) {
+	byName := make(map[string]*ZombieBranch)
+	local, err := repo.ListBranches(constants.BranchPolecatPrefix + "*")
+	if err != nil {
+		return nil, fmt.Errorf("listing branches: %w", err)
+	}
+	for _, b := range local {
+		if b = strings.TrimSpace(b); b != "" {
+			byName[b] = &ZombieBranch{Branch: b, Local: true}
+		}
+	}
+	remote, err := repo.ListRemoteBranches("origin", constants.BranchPolecatPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("listing remote branches: %w", err)
+	}
+	for _, b := range remote {
+		if z, ok := byName[b]; ok {
+			z.Remote = true
+		} else {
+			byName[b] = &ZombieBranch{Branch: b, Remote: true}
+		}
+	}
+	if len(byName) == 0 {
+		return nil, nil
+	}
+
+	// Branches checked out in a worktree belong to a live polecat.
+	live := make(map[string]bool)
+	if worktrees, err := repo.WorktreeList(); err == nil {
+		for _, wt := range worktrees {
+			live[wt.Branch] = true
+		}
+	}
+	queue, err := refinery.NewManager(r).Queue()
+	if err != nil {
+		return nil, fmt.Errorf("listing merge requests: %w", err)
+	}
+	for _, item := range queue {
+		live[item.MR.Branch] = true
+	}
+
+	baseRef := baseRefFor(repo, r.DefaultBranch())
+	var zombies []ZombieBranch
+	for _, z := range byName {
+		if live[z.Branch] {
+			continue
+		}
+		info := parseBranchName(z.Branch)
+		z.Issue, z.Polecat = info.Issue, info.Worker
+		if z.Issue != "" {
+			open, err := issueStillOpen(z.Issue)
+			if err != nil || open {
+				// Unknown counts as open: never call a branch a zombie
+				// because beads was unreachable.
+				continue
+			}
+		}
+
+		ahead, err := repo.CommitsAhead(baseRef, z.ref())
+		if err != nil {
+			continue
+		}
+		if ahead > 0 {
+			if z.Unique, err = repo.UniqueCommits(baseRef, z.ref()); err != nil {
+				continue
+			}
+		}
+		z.Class = classifyZombieBranch(ahead, z.Unique)
+		z.Latest, _ = repo.GetBranchCommitMessage(z.ref())
+		if i := strings.IndexByte(z.Latest, '\n'); i >= 0 {
+			z.Latest = z.Latest[:i]
+		}
+		zombies = append(zombies, *z)
+	}
+	sort.Slice(zombies, func(i, j int) bool { return zombies[i].Branch < zombies[j].Branch })
+	return zombies, nil
+}
+
+// issueStillOpen reports whether a branch's issue is still open.
+func issueStillOpen(issueID string) (bool, error) {
+	issue, err := beads.New(resolveBeadDir(issueID)).Show(issueID)
+	if errors.Is(err, beads.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return issue.Status != "closed", nil
+}
+
+func printZombieBranches(rigName string, zombies []ZombieBranch) {
+	fmt.Printf("%s Found %d zombie polecat branch(es) in %s:\n\n", style.Warning.Render("⚠"), len(zombies), rigName)
+	for _, z := range zombies {
+		var where []string
+		if z.Local {
+			where = append(where, "local")
+		}
+		if z.Remote {
+			where = append(where, "origin")
+		}
+		class := z.Class
+		if z.Class == zombieAbandoned {
+			class = style.Warning.Render(fmt.Sprintf("%s, %d unique commit(s)", z.Class, z.Unique))
+		}
+		fmt.Printf("  %s (%s) %s\n", style.Bold.Render(z.Branch), class, style.Dim.Render(strings.Join(where, "+")))
+		if z.Latest != "" {
+			fmt.Printf("    %s %s\n", style.Dim.Render("latest:"), z.Latest)
+		}
+	}
+	fmt.Println()
+}
+
+// cleanZombieBranches deletes zombies locally and on origin. Abandoned
+// branches are bundled to rescueDir first and kept if that fails.
+func cleanZombieBranches(repo *gitpkg.Git, zombies []ZombieBranch, baseRef, rescueDir string) (deleted, failed int) {
+	for _, z := range zombies {
+		if z.Class == zombieAbandoned {
+			bundle, err := writeRescueBundle(repo, z, baseRef, rescueDir)
+			if err != nil {
+				fmt.Printf("  %s %s: rescue bundle failed, keeping branch: %v\n", style.Error.Render("✗"), z.Branch, err)
+				failed++
+				continue
+			}
+			fmt.Printf("  %s %s %s\n", style.Dim.Render("↳"), z.Branch, style.Dim.Render("rescued to "+bundle))
+		}
+		var errs []string
+		if z.Remote {
+			if err := repo.DeleteRemoteBranch("origin", z.Branch); err != nil {
+				errs = append(errs, fmt.Sprintf("origin: %v", err))
+			}
+		}
+		if z.Local {
+			if err := repo.DeleteBranch(z.Branch, true); err != nil {
+				errs = append(errs, fmt.Sprintf("local: %v", err))
+			}
+		}
+		if len(errs) > 0 {
+			fmt.Printf("  %s %s: %s\n", style.Error.Render("✗"), z.Branch, strings.Join(errs, "; "))
+			failed++
+			continue
+		}
+		fmt.Printf("  %s %s (%s)\n", style.Bold.Render("✓"), z.Branch, z.Class)
+		deleted++
+	}
+	return deleted, failed
+}
+
+// writeRescueBundle saves a branch's commits beyond baseRef as a bundle
+// whose ref is the branch name, and returns the bundle path.
+func writeRescueBundle(repo *gitpkg.Git, z ZombieBranch, baseRef, rescueDir string) (string, error) {
+	if err := os.MkdirAll(rescueDir, 0755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%s.bundle",
+		strings.ReplaceAll(strings.ReplaceAll(z.Branch, "/", "_"), "@", "_"),
+		time.Now().Format("20060102-150405"))
+	path, err := filepath.Abs(filepath.Join(rescueDir, name))
+	if err != nil {
+		return "", err
+	}
+	// Bundle from a local branch so the bundle carries refs/heads/<branch>.
+	if !z.Local {
+		if err := repo.CreateBranchFrom(z.Branch, z.ref()); err != nil {
+			return "", err
+		}
+		defer func() { _ = repo.DeleteBranch(z.Branch, true) }()
+	}
+	if err := repo.CreateBundle(path, z.Branch, "^"+baseRef); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import "testing"
+
+func TestClassifyZombieBranch(t *testing.T) {
+	tests := []struct {
+		ahead, unique int
+		want          string
+	}{
+		{0, 0, zombieEmpty},
+		{3, 0, zombieMerged},
+		{3, 1, zombieAbandoned},
+	}
+	for _, tt := range tests {
+		if got := classifyZombieBranch(tt.ahead, tt.unique); got != tt.want {
+			t.Errorf("classifyZombieBranch(%d, %d) = %q, want %q", tt.ahead, tt.unique, got, tt.want)
+		}
+	}
+}
+
+func TestZombieBranchRef(t *testing.T) {
+	if got := (ZombieBranch{Branch: "polecat/Toast", Local: true, Remote: true}).ref(); got != "polecat/Toast" {
+		t.Errorf("local ref = %q", got)
+	}
+	if got := (ZombieBranch{Branch: "polecat/Toast", Remote: true}).ref(); got != "origin/polecat/Toast" {
+		t.Errorf("remote-only ref = %q", got)
+	}
+}
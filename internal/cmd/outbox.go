@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/outbox"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var outboxListJSON bool
+
+var outboxCmd = &cobra.Command{
+	Use:     "outbox",
+	GroupID: GroupDiag,
+	Short:   "Commands queued while the beads database was unreachable",
+	Long: `Show and manage the outbox of queued agent commands.
+
+When the beads database (Dolt) is temporarily unreachable, agent-facing
+mutations such as gt done and gt escalate ack/close are queued under the
+town's .runtime/outbox directory instead of failing halfway. The daemon's
+outbox patrol replays them, oldest first, with the agent's identity once
+the database is back. An entry that still fails after ` + fmt.Sprint(outbox.MaxAttempts) + ` replays
+is set aside as failed for a human to retry or drop.
+
+Examples:
+  gt outbox                 # Pending and failed entries
+  gt outbox retry ob-1a2b   # Queue a failed entry again
+  gt outbox drop ob-1a2b    # Discard an entry without running it`,
+	Args: cobra.NoArgs,
+	RunE: runOutboxList,
+}
+
+var outboxRetryCmd = &cobra.Command{
+	Use:   "retry <id>",
+	Short: "Move a failed entry back to the outbox",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runOutboxRetry,
+}
+
+var outboxDropCmd = &cobra.Command{
+	Use:   "drop <id>",
+	Short: "Discard an entry without running it",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runOutboxDrop,
+}
+
+func init() {
+	outboxCmd.Flags().BoolVar(&outboxListJSON, "json", false, "Output as JSON")
+
+	outboxCmd.AddCommand(outboxRetryCmd)
+	outboxCmd.AddCommand(outboxDropCmd)
+	rootCmd.AddCommand(outboxCmd)
+}
+
+func runOutboxList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	pending, err := outbox.List(townRoot)
+	if err != nil {
+		return err
+	}
+	failed, err := outbox.ListFailed(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if outboxListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string][]outbox.Entry{
+			"pending": nonNilEntries(pending),
+			"failed":  nonNilEntries(failed),
+		})
+	}
+
+	if len(pending) == 0 && len(failed) == 0 {
+		fmt.Println("Outbox is empty")
+		return nil
+	}
+	printOutboxEntries("Pending", pending)
+	printOutboxEntries("Failed", failed)
+	return nil
+}
+
+func nonNilEntries(entries []outbox.Entry) []outbox.Entry {
+	if entries == nil {
+		return []outbox.Entry{}
+	}
+	return entries
+}
+
+func printOutboxEntries(title string, entries []outbox.Entry) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Printf("%s (%d):\n", style.Bold.Render(title), len(entries))
+	for _, e := range entries {
+		fmt.Printf("  %s  %s\n", e.ID, e.Command())
+		detail := fmt.Sprintf("queued %s ago", time.Since(e.QueuedAt).Round(time.Second))
+		if e.Actor != "" {
+			detail += " by " + e.Actor
+		}
+		if e.Attempts > 0 {
+			detail += fmt.Sprintf(", %d attempts", e.Attempts)
+		}
+		fmt.Printf("    %s\n", style.Dim.Render(detail))
+		if e.LastError != "" {
+			fmt.Printf("    %s\n", style.Dim.Render("last error: "+e.LastError))
+		}
+	}
+	fmt.Println()
+}
+
+func runOutboxRetry(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := outbox.Retry(townRoot, args[0]); err != nil {
+		if errors.Is(err, outbox.ErrNotFound) {
+			return fmt.Errorf("no failed outbox entry %s", args[0])
+		}
+		return err
+	}
+	fmt.Printf("%s Requeued %s\n", style.SuccessPrefix, args[0])
+	return nil
+}
+
+func runOutboxDrop(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := outbox.Drop(townRoot, args[0]); err != nil {
+		if errors.Is(err, outbox.ErrNotFound) {
+			return fmt.Errorf("no outbox entry %s", args[0])
+		}
+		return err
+	}
+	fmt.Printf("%s Dropped %s\n", style.SuccessPrefix, args[0])
+	return nil
+}
+
+// beadsUnreachable returns why the beads database cannot be reached, or
+// nil if it can. Outbox replays always get nil: a replay that fails is
+// counted by the daemon, never queued again.
+func beadsUnreachable(townRoot string) error {
+	if outbox.Replaying() {
+		return nil
+	}
+	return outbox.Reachable(townRoot)
+}
+
+// queueForReplay queues `gt <args>`, to run in dir, in the outbox because
+// of reachErr, so the agent's command is applied once the database is back
+// instead of failing partway.
+func queueForReplay(townRoot, dir string, args []string, reachErr error) error {
+	reason, _, _ := strings.Cut(reachErr.Error(), "\n")
+	entry := outbox.Entry{
+		Args:   args,
+		Dir:    dir,
+		Env:    outbox.CaptureEnv(),
+		Actor:  detectSender(),
+		Reason: reason,
+	}
+	id, err := outbox.Add(townRoot, entry)
+	if err != nil {
+		return fmt.Errorf("%s, and queueing the command failed: %w", reason, err)
+	}
+	_ = events.LogFeed(events.TypeOutboxQueued, entry.Actor, map[string]interface{}{
+		"id":      id,
+		"command": entry.Command(),
+		"reason":  reason,
+	})
+
+	style.PrintWarning("beads database unreachable: %s", reason)
+	fmt.Printf("%s Queued %q as %s\n", style.Bold.Render("→"), entry.Command(), id)
+	fmt.Printf("  The daemon runs it once the database is back (see: gt outbox).\n")
+	return nil
+}
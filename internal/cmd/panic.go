@@ -0,0 +1,332 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/maintenance"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	panicReason string
+	panicOutput string
+)
+
+var panicCmd = &cobra.Command{
+	Use:     "panic",
+	GroupID: GroupServices,
+	Short:   "Freeze the town and snapshot everything",
+	Long: `Emergency stop for when an autonomous loop starts doing something
+destructive. In order, gt panic:
+
+  1. Freezes the town: opens a maintenance window that stays open until
+     gt maintenance end, so the daemon stops restarting agents, nudging,
+     dispatching and patrolling; pauses the Deacon; and stops agents from
+     nudging each other or running commands that sling, kill, nuke, merge
+     or clean up. Queued nudges are held. Humans are not restricted.
+  2. Snapshots every tmux session's scrollback and the town's state files
+     into an incident directory.
+  3. Prints a recovery checklist (also written to the incident directory).
+
+Running agents are not killed: their scrollback is evidence, and killing
+one is a decision to make with the checklist in hand.
+
+Examples:
+  gt panic
+  gt panic -r "polecats force-pushing main"
+  gt panic -o /tmp/incident`,
+	Args: cobra.NoArgs,
+	RunE: runPanic,
+}
+
+func init() {
+	panicCmd.Flags().StringVarP(&panicReason, "reason", "r", "", "What is going wrong")
+	panicCmd.Flags().StringVarP(&panicOutput, "output", "o", "", "Incident directory (default: .runtime/incidents/panic-<time>)")
+	rootCmd.AddCommand(panicCmd)
+}
+
+// panicMaxFileBytes caps how much of one state file or log a snapshot
+// copies; larger files keep their tail.
+const panicMaxFileBytes = 16 << 20
+
+// panicEventsTail is how many lines of the events log a snapshot keeps.
+const panicEventsTail = 5000
+
+// panicSnapshot is what a panic captured.
+type panicSnapshot struct {
+	Sessions []panicSession `json:"sessions"`
+	Files    int            `json:"files"`
+	Errors   []string       `json:"errors,omitempty"`
+}
+
+// panicSession is one tmux session as the panic found it.
+type panicSession struct {
+	Name    string                 `json:"name"`
+	Agent   *session.SessionRecord `json:"agent,omitempty"`
+	Command string                 `json:"command,omitempty"`
+	Lines   int                    `json:"lines"`
+}
+
+func runPanic(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	now := time.Now()
+	dir := panicOutput
+	if dir == "" {
+		dir = filepath.Join(townRoot, ".runtime", "incidents", "panic-"+now.Format("20060102-150405"))
+	}
+	if dir, err = filepath.Abs(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating incident directory: %w", err)
+	}
+
+	// Freeze first: the snapshot can wait, the damage can't.
+	by := detectSender()
+	if _, err := maintenance.Freeze(townRoot, panicReason, by, dir); err != nil {
+		return fmt.Errorf("freezing town: %w", err)
+	}
+	pausedDeacon := false
+	if paused, _, _ := deacon.IsPaused(townRoot); !paused {
+		if err := deacon.Pause(townRoot, strings.TrimSpace("gt panic "+panicReason), by); err != nil {
+			style.PrintWarning("could not pause the Deacon: %v", err)
+		} else {
+			pausedDeacon = true
+		}
+	}
+	_ = events.LogFeed(events.TypePanic, by, events.PanicPayload(panicReason, dir))
+	fmt.Printf("%s Town frozen\n", style.Bold.Render("🛑"))
+
+	snap := snapshotTown(townRoot, dir, tmux.NewTmux())
+	if data, err := json.MarshalIndent(snap, "", "  "); err == nil {
+		_ = os.WriteFile(filepath.Join(dir, "snapshot.json"), data, 0600)
+	}
+	fmt.Printf("%s Snapshot: %d session(s), %d state file(s) in %s\n",
+		style.SuccessPrefix, len(snap.Sessions), snap.Files, dir)
+	for _, e := range snap.Errors {
+		style.PrintWarning("%s", e)
+	}
+
+	checklist := panicChecklist(now, panicReason, dir, pausedDeacon)
+	_ = os.WriteFile(filepath.Join(dir, "CHECKLIST.md"), []byte(checklist), 0600)
+	fmt.Println()
+	fmt.Print(checklist)
+	return nil
+}
+
+// snapshotTown captures every session's scrollback and the town's state
+// files into dir. It keeps going past failures and reports them.
+func snapshotTown(townRoot, dir string, t *tmux.Tmux) *panicSnapshot {
+	snap := &panicSnapshot{}
+	fail := func(format string, a ...interface{}) {
+		snap.Errors = append(snap.Errors, fmt.Sprintf(format, a...))
+	}
+
+	names, err := t.ListSessions()
+	if err != nil {
+		fail("listing sessions: %v", err)
+	}
+	sessionsDir := filepath.Join(dir, "sessions")
+	if len(names) > 0 {
+		if err := os.MkdirAll(sessionsDir, 0755); err != nil {
+			fail("creating %s: %v", sessionsDir, err)
+		}
+	}
+	for _, name := range names {
+		ps := panicSession{Name: name}
+		if rec, err := session.LookupSession(townRoot, name); err == nil {
+			ps.Agent = rec
+		}
+		ps.Command, _ = t.GetPaneCommand(name)
+		out, err := t.CapturePaneAll(name)
+		if err != nil {
+			fail("capturing %s: %v", name, err)
+		} else if err := os.WriteFile(filepath.Join(sessionsDir, name+".log"), []byte(out), 0600); err != nil {
+			fail("saving %s: %v", name, err)
+		} else {
+			ps.Lines = strings.Count(out, "\n")
+		}
+		snap.Sessions = append(snap.Sessions, ps)
+	}
+
+	stateDir := filepath.Join(dir, "state")
+	incidents := filepath.Join(townRoot, ".runtime", "incidents")
+	skip := func(path string) bool { return path == incidents || path == dir }
+	for _, src := range []string{".runtime", "daemon", "mayor", "deacon"} {
+		n, errs := copyStateTree(filepath.Join(townRoot, src), filepath.Join(stateDir, src), skip)
+		snap.Files += n
+		snap.Errors = append(snap.Errors, errs...)
+	}
+
+	if err := copyLastLines(filepath.Join(townRoot, events.EventsFile), filepath.Join(dir, "events-tail.jsonl"), panicEventsTail); err != nil && !os.IsNotExist(err) {
+		fail("copying events log: %v", err)
+	}
+	return snap
+}
+
+// copyStateTree copies the regular files under src to dst, skipping the
+// directories skip reports, git checkouts (they're not state) and anything
+// that isn't a file or directory (sockets, pipes).
+func copyStateTree(src, dst string, skip func(path string) bool) (int, []string) {
+	copied := 0
+	var errs []string
+	_ = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if !os.IsNotExist(err) {
+				errs = append(errs, fmt.Sprintf("reading %s: %v", path, err))
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if skip(path) {
+				return filepath.SkipDir
+			}
+			if _, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, _ := filepath.Rel(src, path)
+		if err := copyFileTail(path, filepath.Join(dst, rel), panicMaxFileBytes); err != nil {
+			errs = append(errs, fmt.Sprintf("copying %s: %v", path, err))
+			return nil
+		}
+		copied++
+		return nil
+	})
+	return copied, errs
+}
+
+// copyFileTail copies src to dst, keeping only the last max bytes of a
+// larger file.
+func copyFileTail(src, dst string, max int64) error {
+	in, err := os.Open(src) //nolint:gosec // G304: path is under the town root
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if info, err := in.Stat(); err == nil && info.Size() > max {
+		if _, err := in.Seek(-max, io.SeekEnd); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// copyLastLines writes the last n lines of src to dst.
+func copyLastLines(src, dst string, n int) error {
+	f, err := os.Open(src) //nolint:gosec // G304: path is under the town root
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	ring := make([]string, 0, n)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if len(ring) == n {
+			ring = ring[1:]
+		}
+		ring = append(ring, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(ring) == 0 {
+		return nil
+	}
+	return os.WriteFile(dst, []byte(strings.Join(ring, "\n")+"\n"), 0600)
+}
+
+// panicChecklist is the recovery checklist gt panic prints and saves.
+func panicChecklist(at time.Time, reason, dir string, pausedDeacon bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Panic at %s\n\n", at.Format("2006-01-02 15:04:05"))
+	if reason != "" {
+		fmt.Fprintf(&b, "Reason: %s\n", reason)
+	}
+	fmt.Fprintf(&b, "Incident: %s\n\n", dir)
+
+	b.WriteString("## Frozen\n\n")
+	b.WriteString("- Daemon: no agent restarts, nudges, dispatch or patrols (maintenance window)\n")
+	if pausedDeacon {
+		b.WriteString("- Deacon: paused\n")
+	} else {
+		b.WriteString("- Deacon: was already paused\n")
+	}
+	b.WriteString("- Agents: may not nudge, sling, nuke, kill, merge or clean up; queued nudges are held\n")
+	b.WriteString("- Agents are still running. Nothing was killed.\n\n")
+
+	b.WriteString("## Recovery checklist\n\n")
+	b.WriteString("1. Find the culprit: `gt feed`, `gt audit --since 1h`, and the scrollbacks in sessions/.\n")
+	b.WriteString("2. Stop it: `gt session kill <session> --yes`, or `gt whois <session>` first to see who it is.\n")
+	b.WriteString("3. Assess the damage: `gt incident <issue|session>` for a timeline; check the affected\n")
+	b.WriteString("   branches and worktrees with git; `bd list --status=in_progress` for claimed work.\n")
+	b.WriteString("4. Repair: revert bad pushes with git, restore the beads database with\n")
+	b.WriteString("   `gt dolt rollback` if needed, and reopen or reassign affected issues.\n")
+	b.WriteString("5. Thaw when safe: `gt maintenance end`")
+	if pausedDeacon {
+		b.WriteString(", then `gt deacon resume`")
+	}
+	b.WriteString(".\n")
+	return b.String()
+}
+
+// checkPanicFreeze refuses the commands maintenance.FreezeAllows rules out
+// when an agent runs them in a frozen town, and holds the agent's nudges.
+// Humans are unaffected: they are the ones recovering.
+func checkPanicFreeze(cmd *cobra.Command) error {
+	if os.Getenv("GT_ROLE") == "" {
+		return nil
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	state := maintenance.FrozenState(townRoot)
+	if state == nil {
+		return nil
+	}
+	tmux.NudgeGate = func(string) error { return maintenance.ErrFrozen }
+
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	if !maintenance.FreezeAllows(path) {
+		why := ""
+		if state.Reason != "" {
+			why = " (" + state.Reason + ")"
+		}
+		return fmt.Errorf("'gt %s' refused: %w%s; a human must run gt maintenance end to thaw it", path, maintenance.ErrFrozen, why)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/maintenance"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSnapshotTown_CopiesStateFiles(t *testing.T) {
+	town := t.TempDir()
+	writeTestFile(t, filepath.Join(town, ".runtime", "session-names", "gt-Toast.json"), `{}`)
+	writeTestFile(t, filepath.Join(town, ".runtime", "incidents", "panic-old", "CHECKLIST.md"), "old")
+	writeTestFile(t, filepath.Join(town, "mayor", "town.json"), `{"type":"town"}`)
+	writeTestFile(t, filepath.Join(town, "mayor", "rig", ".git", "HEAD"), "ref: refs/heads/main")
+	writeTestFile(t, filepath.Join(town, "mayor", "rig", "main.go"), "package main")
+	writeTestFile(t, filepath.Join(town, "daemon", "daemon.log"), "started\n")
+	writeTestFile(t, filepath.Join(town, ".events.jsonl"), "{\"n\":1}\n{\"n\":2}\n")
+
+	dir := filepath.Join(town, ".runtime", "incidents", "panic-now")
+	// No tmux server on this socket, so no sessions.
+	snap := snapshotTown(town, dir, tmux.NewTmuxWithSocket("gt-panic-test-none"))
+
+	if len(snap.Errors) > 0 {
+		t.Errorf("unexpected errors: %v", snap.Errors)
+	}
+	for _, want := range []string{
+		"state/.runtime/session-names/gt-Toast.json",
+		"state/mayor/town.json",
+		"state/daemon/daemon.log",
+		"events-tail.jsonl",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, want)); err != nil {
+			t.Errorf("missing %s: %v", want, err)
+		}
+	}
+	for _, unwanted := range []string{
+		"state/.runtime/incidents",
+		"state/mayor/rig",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, unwanted)); err == nil {
+			t.Errorf("%s should not be copied", unwanted)
+		}
+	}
+	if snap.Files != 3 {
+		t.Errorf("Files = %d, want 3", snap.Files)
+	}
+}
+
+func TestCopyLastLines(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "in")
+	writeTestFile(t, src, "a\nb\nc\nd\n")
+	dst := filepath.Join(dir, "out")
+	if err := copyLastLines(src, dst, 2); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(dst)
+	if string(got) != "c\nd\n" {
+		t.Errorf("got %q, want last two lines", got)
+	}
+}
+
+func TestCopyFileTail_KeepsTail(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "big.log")
+	writeTestFile(t, src, "0123456789")
+	dst := filepath.Join(dir, "copy", "big.log")
+	if err := copyFileTail(src, dst, 4); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(dst)
+	if string(got) != "6789" {
+		t.Errorf("got %q, want the last 4 bytes", got)
+	}
+}
+
+func TestCheckPanicFreeze(t *testing.T) {
+	town := t.TempDir()
+	writeTestFile(t, filepath.Join(town, "mayor", "town.json"), `{"type":"town"}`)
+	t.Chdir(town)
+	oldGate := tmux.NudgeGate
+	t.Cleanup(func() { tmux.NudgeGate = oldGate })
+
+	root := &cobra.Command{Use: "gt"}
+	sling := &cobra.Command{Use: "sling"}
+	inbox := &cobra.Command{Use: "inbox"}
+	mail := &cobra.Command{Use: "mail"}
+	mail.AddCommand(inbox)
+	root.AddCommand(sling, mail)
+
+	t.Setenv("GT_ROLE", "gastown/witness")
+	if err := checkPanicFreeze(sling); err != nil {
+		t.Fatalf("unfrozen town refused sling: %v", err)
+	}
+
+	if _, err := maintenance.Freeze(town, "runaway", "human", ""); err != nil {
+		t.Fatal(err)
+	}
+	err := checkPanicFreeze(sling)
+	if !errors.Is(err, maintenance.ErrFrozen) || !strings.Contains(err.Error(), "runaway") {
+		t.Errorf("frozen town: sling err = %v, want ErrFrozen with the reason", err)
+	}
+	if err := checkPanicFreeze(inbox); err != nil {
+		t.Errorf("frozen town refused mail inbox: %v", err)
+	}
+	if tmux.NudgeGate == nil || !errors.Is(tmux.NudgeGate("gt-Toast"), maintenance.ErrFrozen) {
+		t.Error("frozen town should hold the agent's nudges")
+	}
+
+	tmux.NudgeGate = nil
+	t.Setenv("GT_ROLE", "")
+	if err := checkPanicFreeze(sling); err != nil {
+		t.Errorf("humans must not be restricted: %v", err)
+	}
+	if tmux.NudgeGate != nil {
+		t.Error("humans' nudges must not be held")
+	}
+}
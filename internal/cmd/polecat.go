@@ -740,6 +740,9 @@ type GitState struct {
 	UncommittedFiles []string `json:"uncommitted_files"`
 	UnpushedCommits  int      `json:"unpushed_commits"`
 	StashCount       int      `json:"stash_count"`
+	// UnpushedSubmoduleCommits counts submodule commits on no remote; they
+	// live only in this worktree's submodule repos.
+	UnpushedSubmoduleCommits int `json:"unpushed_submodule_commits,omitempty"`
 }
 
 func runPolecatGitState(cmd *cobra.Command, args []string) error {
@@ -793,6 +796,10 @@ func runPolecatGitState(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Unpushed:      %s\n", style.Warning.Render(fmt.Sprintf("%d commits ahead", state.UnpushedCommits)))
 	}
 
+	if state.UnpushedSubmoduleCommits > 0 {
+		fmt.Printf("  Submodules:    %s\n", style.Warning.Render(fmt.Sprintf("%d unpushed commits", state.UnpushedSubmoduleCommits)))
+	}
+
 	// Stashes
 	if state.StashCount == 0 {
 		fmt.Printf("  Stashes:       %s\n", style.Dim.Render("0"))
@@ -892,6 +899,12 @@ func getGitState(worktreePath string) (*GitState, error) {
 		}
 	}
 
+	// Check for submodule commits that would be lost with the worktree
+	if subUnpushed, subErr := worktreeGit.UnpushedSubmoduleCommits(); subErr == nil && subUnpushed > 0 {
+		state.UnpushedSubmoduleCommits = subUnpushed
+		state.Clean = false
+	}
+
 	return state, nil
 }
 
@@ -950,7 +963,7 @@ func runPolecatCheckRecovery(cmd *cobra.Command, args []string) error {
 			status.CleanupStatus = polecat.CleanupClean
 			status.NeedsRecovery = false
 			status.Verdict = "SAFE_TO_NUKE"
-		} else if gitState.UnpushedCommits > 0 {
+		} else if gitState.UnpushedCommits > 0 || gitState.UnpushedSubmoduleCommits > 0 {
 			status.CleanupStatus = polecat.CleanupUnpushed
 			status.NeedsRecovery = true
 			status.Verdict = "NEEDS_RECOVERY"
@@ -1150,10 +1163,7 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 	for _, p := range targets {
 		if polecatNukeDryRun {
 			fmt.Printf("Would nuke %s/%s:\n", p.rigName, p.polecatName)
-			fmt.Printf("  - Kill session: gt-%s-%s\n", p.rigName, p.polecatName)
-			fmt.Printf("  - Delete worktree: %s/polecats/%s\n", p.r.Path, p.polecatName)
-			fmt.Printf("  - Delete branch (if exists)\n")
-			fmt.Printf("  - Close agent bead: %s\n", polecatBeadIDForRig(p.r, p.rigName, p.polecatName))
+			printCleanupPlan(planPolecatCleanup(p.polecatName, p.rigName, p.mgr, p.r))
 
 			displayDryRunSafetyCheck(p)
 			fmt.Println()
@@ -1204,144 +1214,13 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// nukePolecatFull performs the complete cleanup sequence for a single polecat:
-// 1. Kill tmux session
-// 2. Delete worktree (via RemoveWithOptions with nuclear=true)
-// 3. Delete git branch
-// 4. Close agent bead
-// This is the canonical cleanup path used by both `polecat nuke` and `polecat stale --cleanup`.
+// nukePolecatFull performs the complete cleanup sequence for a single polecat,
+// planned by planPolecatCleanup: kill the session, burn any attached
+// molecule, push and delete the branch, delete the worktree, and reset the
+// agent bead. This is the canonical cleanup path used by `polecat nuke`,
+// `polecat stale --cleanup` and `witness cleanup`.
 func nukePolecatFull(polecatName, rigName string, mgr *polecat.Manager, r *rig.Rig) error {
-	t := tmux.NewTmux()
-
-	// Step 1: Kill tmux session unconditionally to prevent ghost sessions
-	// when IsRunning fails to detect the session.
-	sessMgr := polecat.NewSessionManager(t, r)
-	if err := sessMgr.Stop(polecatName, true); err != nil {
-		if !errors.Is(err, polecat.ErrSessionNotFound) {
-			fmt.Printf("  %s session kill failed: %v\n", style.Warning.Render("⚠"), err)
-		}
-	} else {
-		fmt.Printf("  %s killed session\n", style.Success.Render("✓"))
-	}
-
-	// Step 2: Get polecat info before deletion (for branch name + hooked work bead)
-	polecatInfo, getErr := mgr.Get(polecatName)
-	var branchToDelete string
-	if getErr == nil && polecatInfo != nil {
-		branchToDelete = polecatInfo.Branch
-	}
-
-	// Step 2.5: Burn any molecule attached to the polecat's hooked work bead.
-	// Without this, nuked polecats leave orphan molecule refs that block re-sling.
-	// The stale attached_molecule in the work bead's description causes sling to
-	// fail with "bead already has N attached molecule(s)" on re-dispatch (gt-npzy).
-	if getErr == nil && polecatInfo != nil && polecatInfo.Issue != "" {
-		nukeCleanupMolecules(polecatInfo.Issue, r)
-	}
-
-	// Step 2.75: Best-effort push before nuke (gt-4vr guardrail).
-	// Try to preserve any unpushed commits on the branch. If push fails,
-	// proceed — --force already means "I accept data loss".
-	if branchToDelete != "" {
-		var pushGit *git.Git
-		// Try worktree first (may still exist), then bare repo fallback
-		if polecatInfo != nil {
-			wtPath := filepath.Join(r.Path, "polecats", polecatName)
-			if _, statErr := os.Stat(wtPath); statErr == nil {
-				pushGit = git.NewGit(wtPath)
-			}
-		}
-		if pushGit == nil {
-			bareRepoPath := filepath.Join(r.Path, ".repo.git")
-			if info, statErr := os.Stat(bareRepoPath); statErr == nil && info.IsDir() {
-				pushGit = git.NewGitWithDir(bareRepoPath, "")
-			}
-		}
-		if pushGit != nil {
-			refspec := branchToDelete + ":" + branchToDelete
-			if err := pushGit.Push("origin", refspec, false); err != nil {
-				fmt.Printf("  %s best-effort push failed (proceeding): %v\n", style.Dim.Render("○"), err)
-			} else {
-				fmt.Printf("  %s pushed branch %s before nuke\n", style.Success.Render("✓"), branchToDelete)
-			}
-		}
-	}
-
-	// Step 3: Delete worktree (nuclear=true to bypass safety checks for stale polecats)
-	if err := mgr.RemoveWithOptions(polecatName, true, true, false); err != nil {
-		if errors.Is(err, polecat.ErrPolecatNotFound) {
-			fmt.Printf("  %s worktree already gone\n", style.Dim.Render("○"))
-		} else {
-			return fmt.Errorf("worktree removal failed: %w", err)
-		}
-	} else {
-		fmt.Printf("  %s deleted worktree\n", style.Success.Render("✓"))
-	}
-
-	// Step 3.5: Check for pending MR before touching the branch.
-	// If an open MR exists for this branch, the refinery still needs the remote
-	// branch to complete the merge. Preserve both the MR and remote branch;
-	// the refinery will delete the remote branch after a successful merge.
-	// Fixes: https://github.com/steveyegge/gastown/issues/2028
-	hasPendingMR := false
-	if branchToDelete != "" {
-		bd := beads.New(r.Path)
-		mr, findErr := bd.FindMRForBranch(branchToDelete)
-		if findErr != nil {
-			fmt.Printf("  %s MR lookup failed: %v\n", style.Dim.Render("○"), findErr)
-		} else if mr != nil {
-			hasPendingMR = true
-			fmt.Printf("  %s preserving MR %s and remote branch for refinery merge\n", style.Success.Render("✓"), mr.ID)
-		}
-	}
-
-	// Step 4: Delete branch (if we know it)
-	// Local branch can always be deleted (worktree is already gone).
-	// Remote branch is only deleted when there is NO pending MR — otherwise
-	// the refinery would find the source branch missing and fail to merge.
-	if branchToDelete != "" {
-		var repoGit *git.Git
-		bareRepoPath := filepath.Join(r.Path, ".repo.git")
-		if info, statErr := os.Stat(bareRepoPath); statErr == nil && info.IsDir() {
-			repoGit = git.NewGitWithDir(bareRepoPath, "")
-		} else {
-			repoGit = git.NewGit(filepath.Join(r.Path, "mayor", "rig"))
-		}
-		if err := repoGit.DeleteBranch(branchToDelete, true); err != nil {
-			fmt.Printf("  %s branch delete: %v\n", style.Dim.Render("○"), err)
-		} else {
-			fmt.Printf("  %s deleted local branch %s\n", style.Success.Render("✓"), branchToDelete)
-		}
-		if hasPendingMR {
-			fmt.Printf("  %s skipped remote branch delete (MR pending in merge queue)\n", style.Dim.Render("○"))
-		} else {
-			// No pending MR — safe to delete remote branch
-			if err := repoGit.DeleteRemoteBranch("origin", branchToDelete); err != nil {
-				fmt.Printf("  %s remote branch delete: %v\n", style.Dim.Render("○"), err)
-			} else {
-				fmt.Printf("  %s deleted remote branch %s\n", style.Success.Render("✓"), branchToDelete)
-			}
-		}
-	}
-
-	// Step 5: Reset agent bead for reuse (if exists)
-	// Uses ResetAgentBeadForReuse instead of bd close because agent beads are
-	// ephemeral (wisps table). Shelling out to `bd close` operates on the issues
-	// table and silently fails to affect wisps, leaving stale rows that block
-	// re-sling with "duplicate primary key" errors. (gt--irj)
-	// ResetAgentBeadForReuse keeps the bead open with agent_state="nuked" so
-	// CreateOrReopenAgentBead can simply update it on re-spawn without needing
-	// a close/reopen cycle.
-	agentBeadID := polecatBeadIDForRig(r, rigName, polecatName)
-	bd := beads.New(r.Path)
-	if err := bd.ResetAgentBeadForReuse(agentBeadID, "nuked"); err != nil {
-		// Bead may not exist (first spawn failed, or test environment)
-		fmt.Printf("  %s agent bead not found or already cleaned\n", style.Dim.Render("○"))
-	} else {
-		fmt.Printf("  %s closed agent bead %s\n", style.Success.Render("✓"), agentBeadID)
-	}
-
-	return nil
+	return executeCleanupPlan(planPolecatCleanup(polecatName, rigName, mgr, r), mgr, r)
 }
 
 // nukeCleanupMolecules burns any molecule attached to a work bead during polecat nuke.
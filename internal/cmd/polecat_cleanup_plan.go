@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// cleanupStep is one kind of polecat cleanup action.
+type cleanupStep string
+
+const (
+	cleanupKillSession        cleanupStep = "kill-session"
+	cleanupBurnMolecule       cleanupStep = "burn-molecule"
+	cleanupPushBranch         cleanupStep = "push-branch"
+	cleanupRemoveWorktree     cleanupStep = "remove-worktree"
+	cleanupDeleteBranch       cleanupStep = "delete-branch"
+	cleanupDeleteRemoteBranch cleanupStep = "delete-remote-branch"
+	cleanupResetAgentBead     cleanupStep = "reset-agent-bead"
+)
+
+// cleanupAction is one planned step of polecat cleanup.
+type cleanupAction struct {
+	Step   cleanupStep `json:"step"`
+	Target string      `json:"target"`         // session, issue, branch, path or bead
+	Note   string      `json:"note,omitempty"` // context, or why the step is skipped
+	Skip   bool        `json:"skip,omitempty"` // planned for the record but not run
+}
+
+// String describes the action, e.g. "delete branch polecat/toast".
+func (a cleanupAction) String() string {
+	var s string
+	switch a.Step {
+	case cleanupKillSession:
+		s = "kill session " + a.Target
+	case cleanupBurnMolecule:
+		s = "burn molecule attached to " + a.Target
+	case cleanupPushBranch:
+		s = "push branch " + a.Target + " to origin (best effort)"
+	case cleanupRemoveWorktree:
+		s = "remove worktree " + a.Target
+	case cleanupDeleteBranch:
+		s = "delete branch " + a.Target
+	case cleanupDeleteRemoteBranch:
+		s = "delete remote branch origin/" + a.Target
+	case cleanupResetAgentBead:
+		s = "reset agent bead " + a.Target
+	default:
+		s = string(a.Step) + " " + a.Target
+	}
+	if a.Skip {
+		s = "skip: " + s
+	}
+	if a.Note != "" {
+		s += " (" + a.Note + ")"
+	}
+	return s
+}
+
+// cleanupPlan is the ordered list of actions that cleans up a polecat.
+type cleanupPlan struct {
+	Rig     string
+	Polecat string
+	Actions []cleanupAction
+}
+
+// planPolecatCleanup computes what cleaning up a polecat will do, without
+// changing anything. The order is the execution order: the session dies
+// first so nothing writes to the worktree while it is removed, and the
+// branch is pushed before it is deleted so unpushed commits survive.
+func planPolecatCleanup(polecatName, rigName string, mgr *polecat.Manager, r *rig.Rig) *cleanupPlan {
+	plan := &cleanupPlan{Rig: rigName, Polecat: polecatName}
+	add := func(a cleanupAction) { plan.Actions = append(plan.Actions, a) }
+
+	t := tmux.NewTmux()
+	sessionName := polecat.NewSessionManager(t, r).SessionName(polecatName)
+	kill := cleanupAction{Step: cleanupKillSession, Target: sessionName}
+	if running, _ := t.HasSession(sessionName); !running {
+		kill.Note = "not running"
+	}
+	add(kill)
+
+	info, getErr := mgr.Get(polecatName)
+	var branch string
+	if getErr == nil && info != nil {
+		branch = info.Branch
+		if info.Issue != "" {
+			// A failed lookup keeps the step: the molecule may be there, and
+			// executing the step fetches the work bead again.
+			molecule, err := attachedMolecule(info.Issue, r)
+			switch {
+			case err != nil:
+				add(cleanupAction{Step: cleanupBurnMolecule, Target: info.Issue, Note: fmt.Sprintf("could not fetch work bead: %v", err)})
+			case molecule != "":
+				add(cleanupAction{Step: cleanupBurnMolecule, Target: info.Issue, Note: "molecule " + molecule})
+			}
+		}
+	}
+
+	if branch != "" {
+		add(cleanupAction{Step: cleanupPushBranch, Target: branch})
+	}
+
+	wtPath := filepath.Join(r.Path, "polecats", polecatName)
+	remove := cleanupAction{Step: cleanupRemoveWorktree, Target: wtPath}
+	if _, err := os.Stat(wtPath); err != nil {
+		remove.Note = "already gone"
+	}
+	add(remove)
+
+	if branch != "" {
+		add(cleanupAction{Step: cleanupDeleteBranch, Target: branch})
+		remote := cleanupAction{Step: cleanupDeleteRemoteBranch, Target: branch}
+		// The refinery still needs the remote branch of a pending MR; it
+		// deletes the branch itself after merging (#2028).
+		mr, err := beads.New(r.Path).FindMRForBranch(branch)
+		if err != nil {
+			remote.Note = fmt.Sprintf("MR lookup failed: %v", err)
+		} else if mr != nil {
+			remote.Skip = true
+			remote.Note = fmt.Sprintf("MR %s pending in merge queue", mr.ID)
+		}
+		add(remote)
+	}
+
+	add(cleanupAction{Step: cleanupResetAgentBead, Target: polecatBeadIDForRig(r, rigName, polecatName)})
+	return plan
+}
+
+// attachedMolecule returns the molecule attached to a work bead, if any.
+func attachedMolecule(workBeadID string, r *rig.Rig) (string, error) {
+	issue, err := beads.New(filepath.Join(r.Path, "mayor", "rig")).Show(workBeadID)
+	if err != nil {
+		return "", err
+	}
+	if attachment := beads.ParseAttachmentFields(issue); attachment != nil {
+		return attachment.AttachedMolecule, nil
+	}
+	return "", nil
+}
+
+// printCleanupPlan prints the plan as a numbered list.
+func printCleanupPlan(plan *cleanupPlan) {
+	for i, a := range plan.Actions {
+		line := fmt.Sprintf("  %d. %s", i+1, a)
+		if a.Skip {
+			line = style.Dim.Render(line)
+		}
+		fmt.Println(line)
+	}
+}
+
+// executeCleanupPlan runs a cleanup plan in order and logs the plan and
+// its outcome to the audit log. Failing to remove the worktree aborts the
+// cleanup; other steps are best effort, as a half-cleaned polecat is worse
+// than one with a leftover branch.
+func executeCleanupPlan(plan *cleanupPlan, mgr *polecat.Manager, r *rig.Rig) error {
+	var failed []string
+	var err error
+	for _, a := range plan.Actions {
+		if a.Skip {
+			fmt.Printf("  %s skipped %s\n", style.Dim.Render("○"), strings.TrimPrefix(a.String(), "skip: "))
+			continue
+		}
+		if stepErr := runCleanupAction(plan, a, mgr, r); stepErr != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", a.Step, stepErr))
+			if a.Step == cleanupRemoveWorktree {
+				err = fmt.Errorf("worktree removal failed: %w", stepErr)
+				break
+			}
+		}
+	}
+
+	actions := make([]string, len(plan.Actions))
+	for i, a := range plan.Actions {
+		actions[i] = a.String()
+	}
+	_ = events.LogAudit(events.TypePolecatCleanup, detectSender(),
+		events.PolecatCleanupPayload(plan.Rig, plan.Polecat, actions, failed))
+	return err
+}
+
+// runCleanupAction performs one action, printing its result. Only a
+// failed worktree removal is returned as an error that stops the plan;
+// the other steps report failure and let cleanup continue.
+func runCleanupAction(plan *cleanupPlan, a cleanupAction, mgr *polecat.Manager, r *rig.Rig) error {
+	switch a.Step {
+	case cleanupKillSession:
+		// Stop unconditionally to prevent ghost sessions when the plan
+		// didn't see the session running.
+		sessMgr := polecat.NewSessionManager(tmux.NewTmux(), r)
+		if err := sessMgr.Stop(plan.Polecat, true); err != nil {
+			if errors.Is(err, polecat.ErrSessionNotFound) {
+				return nil
+			}
+			fmt.Printf("  %s session kill failed: %v\n", style.Warning.Render("⚠"), err)
+			return err
+		}
+		fmt.Printf("  %s killed session\n", style.Success.Render("✓"))
+
+	case cleanupBurnMolecule:
+		nukeCleanupMolecules(a.Target, r)
+
+	case cleanupPushBranch:
+		var pushGit *git.Git
+		// Try the worktree first (may still exist), then the bare repo.
+		wtPath := filepath.Join(r.Path, "polecats", plan.Polecat)
+		if _, statErr := os.Stat(wtPath); statErr == nil {
+			pushGit = git.NewGit(wtPath)
+		} else if info, statErr := os.Stat(filepath.Join(r.Path, ".repo.git")); statErr == nil && info.IsDir() {
+			pushGit = git.NewGitWithDir(filepath.Join(r.Path, ".repo.git"), "")
+		}
+		if pushGit == nil {
+			return nil
+		}
+		if err := pushGit.Push("origin", a.Target+":"+a.Target, false); err != nil {
+			fmt.Printf("  %s best-effort push failed (proceeding): %v\n", style.Dim.Render("○"), err)
+			return err
+		}
+		fmt.Printf("  %s pushed branch %s before nuke\n", style.Success.Render("✓"), a.Target)
+
+	case cleanupRemoveWorktree:
+		// nuclear=true bypasses safety checks: they ran before planning.
+		if err := mgr.RemoveWithOptions(plan.Polecat, true, true, false); err != nil {
+			if errors.Is(err, polecat.ErrPolecatNotFound) {
+				fmt.Printf("  %s worktree already gone\n", style.Dim.Render("○"))
+				return nil
+			}
+			return err
+		}
+		fmt.Printf("  %s deleted worktree\n", style.Success.Render("✓"))
+
+	case cleanupDeleteBranch:
+		if err := cleanupRepoGit(r).DeleteBranch(a.Target, true); err != nil {
+			fmt.Printf("  %s branch delete: %v\n", style.Dim.Render("○"), err)
+			return err
+		}
+		fmt.Printf("  %s deleted local branch %s\n", style.Success.Render("✓"), a.Target)
+
+	case cleanupDeleteRemoteBranch:
+		if err := cleanupRepoGit(r).DeleteRemoteBranch("origin", a.Target); err != nil {
+			fmt.Printf("  %s remote branch delete: %v\n", style.Dim.Render("○"), err)
+			return err
+		}
+		fmt.Printf("  %s deleted remote branch %s\n", style.Success.Render("✓"), a.Target)
+
+	case cleanupResetAgentBead:
+		// ResetAgentBeadForReuse rather than bd close: agent beads live in
+		// the wisps table, which bd close doesn't touch (gt--irj).
+		if err := beads.New(r.Path).ResetAgentBeadForReuse(a.Target, "nuked"); err != nil {
+			// Bead may not exist (first spawn failed, or test environment)
+			fmt.Printf("  %s agent bead not found or already cleaned\n", style.Dim.Render("○"))
+			return nil
+		}
+		fmt.Printf("  %s closed agent bead %s\n", style.Success.Render("✓"), a.Target)
+	}
+	return nil
+}
+
+// cleanupRepoGit returns the rig's shared repo: the bare repo when there
+// is one, otherwise the mayor's clone.
+func cleanupRepoGit(r *rig.Rig) *git.Git {
+	bareRepoPath := filepath.Join(r.Path, ".repo.git")
+	if info, err := os.Stat(bareRepoPath); err == nil && info.IsDir() {
+		return git.NewGitWithDir(bareRepoPath, "")
+	}
+	return git.NewGit(filepath.Join(r.Path, "mayor", "rig"))
+}
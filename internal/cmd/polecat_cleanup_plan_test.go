@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestCleanupActionString(t *testing.T) {
+	tests := []struct {
+		action cleanupAction
+		want   string
+	}{
+		{cleanupAction{Step: cleanupKillSession, Target: "gt-toast"}, "kill session gt-toast"},
+		{cleanupAction{Step: cleanupKillSession, Target: "gt-toast", Note: "not running"}, "kill session gt-toast (not running)"},
+		{cleanupAction{Step: cleanupBurnMolecule, Target: "gt-abc", Note: "could not fetch work bead: bd unavailable"},
+			"burn molecule attached to gt-abc (could not fetch work bead: bd unavailable)"},
+		{cleanupAction{Step: cleanupDeleteBranch, Target: "polecat/toast"}, "delete branch polecat/toast"},
+		{cleanupAction{Step: cleanupDeleteRemoteBranch, Target: "polecat/toast", Skip: true, Note: "MR gt-1 pending in merge queue"},
+			"skip: delete remote branch origin/polecat/toast (MR gt-1 pending in merge queue)"},
+	}
+	for _, tt := range tests {
+		if got := tt.action.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestPlanPolecatCleanupMissingPolecat(t *testing.T) {
+	rigPath := filepath.Join(t.TempDir(), "greenplace")
+	if err := os.MkdirAll(filepath.Join(rigPath, "polecats"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	r := &rig.Rig{Name: "greenplace", Path: rigPath}
+	mgr := polecat.NewManager(r, git.NewGit(rigPath), tmux.NewTmux())
+
+	plan := planPolecatCleanup("Toast", "greenplace", mgr, r)
+	var steps []string
+	for _, a := range plan.Actions {
+		steps = append(steps, string(a.Step))
+	}
+	// Without polecat info there is no branch or molecule to clean up.
+	want := "kill-session,remove-worktree,reset-agent-bead"
+	if got := strings.Join(steps, ","); got != want {
+		t.Fatalf("steps = %s, want %s", got, want)
+	}
+	if plan.Actions[0].Note != "not running" || plan.Actions[1].Note != "already gone" {
+		t.Errorf("notes = %q, %q", plan.Actions[0].Note, plan.Actions[1].Note)
+	}
+}
+
+func TestFormatFeedSummaryPolecatCleanup(t *testing.T) {
+	e := events.Event{Type: events.TypePolecatCleanup, Payload: map[string]interface{}{
+		"rig": "greenplace", "polecat": "Toast",
+		"actions": []interface{}{"a", "b", "c"},
+		"failed":  []interface{}{"delete-branch: gone"},
+	}}
+	if got, want := formatFeedSummary(e), "Cleaned up greenplace/Toast (3 actions, 1 failed)"; got != want {
+		t.Errorf("formatFeedSummary() = %q, want %q", got, want)
+	}
+}
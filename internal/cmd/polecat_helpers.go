@@ -118,6 +118,8 @@ func checkPolecatSafety(target polecatTarget) *SafetyCheckResult {
 			} else if !gitState.Clean {
 				if gitState.UnpushedCommits > 0 {
 					result.Reasons = append(result.Reasons, fmt.Sprintf("has %d unpushed commit(s)", gitState.UnpushedCommits))
+				} else if gitState.UnpushedSubmoduleCommits > 0 {
+					result.Reasons = append(result.Reasons, fmt.Sprintf("has %d unpushed submodule commit(s)", gitState.UnpushedSubmoduleCommits))
 				} else if len(gitState.UncommittedFiles) > 0 {
 					result.Reasons = append(result.Reasons, fmt.Sprintf("has %d uncommitted file(s)", len(gitState.UncommittedFiles)))
 				} else if gitState.StashCount > 0 {
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/featureflag"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var polecatPoolMaintainAll bool
+
+var polecatPoolCmd = &cobra.Command{
+	Use:   "pool <rig>",
+	Short: "Show a rig's warm worktree pool",
+	Long: `Show the warm worktree pool of a rig.
+
+When a rig sets worktree_pool.size in settings/config.json, a pool of
+pre-created worktrees is kept in <rig>/.worktree-pool/. Spawning a polecat
+claims one and resets it onto the polecat's branch, which is much faster
+than checking out a large repo from scratch. When the pool is empty, spawns
+fall back to a fresh checkout.
+
+The pool is refilled by 'gt polecat pool maintain', which the daemon runs
+when the worktree_pool patrol is enabled.
+
+Examples:
+  gt polecat pool greenplace
+  gt polecat pool maintain greenplace
+  gt polecat pool maintain --all`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatPool,
+}
+
+var polecatPoolMaintainCmd = &cobra.Command{
+	Use:   "maintain [rig]",
+	Short: "Fetch, clean, and refill a rig's warm worktree pool",
+	Long: `Fetch origin, reset every pooled worktree to the default branch, and
+create worktrees until the pool reaches worktree_pool.size.
+
+Rigs without worktree_pool.size have their pool emptied.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPolecatPoolMaintain,
+}
+
+func init() {
+	polecatPoolMaintainCmd.Flags().BoolVar(&polecatPoolMaintainAll, "all", false, "Maintain the pools of all rigs")
+	polecatPoolCmd.AddCommand(polecatPoolMaintainCmd)
+	polecatCmd.AddCommand(polecatPoolCmd)
+}
+
+// worktreePoolSize returns the configured pool size of a rig (0 if unset or
+// switched off by the warm_pools feature flag).
+func worktreePoolSize(r *rig.Rig) int {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil || settings.WorktreePool == nil {
+		return 0
+	}
+	if !featureflag.Enabled(filepath.Dir(r.Path), featureflag.WarmPools, r.Name, "") {
+		return 0
+	}
+	return settings.WorktreePool.Size
+}
+
+func runPolecatPool(cmd *cobra.Command, args []string) error {
+	mgr, r, err := getPolecatManager(args[0])
+	if err != nil {
+		return err
+	}
+	paths, err := mgr.PooledWorktrees()
+	if err != nil {
+		return fmt.Errorf("listing pool: %w", err)
+	}
+	size := worktreePoolSize(r)
+	if size == 0 && len(paths) == 0 {
+		fmt.Printf("No worktree pool for %s %s\n", r.Name,
+			style.Dim.Render("(set worktree_pool.size in settings/config.json)"))
+		return nil
+	}
+	fmt.Printf("%s worktree pool: %d/%d ready\n", style.Bold.Render(r.Name), len(paths), size)
+	for _, p := range paths {
+		fmt.Printf("  %s\n", style.Dim.Render(filepath.Base(p)))
+	}
+	return nil
+}
+
+func runPolecatPoolMaintain(cmd *cobra.Command, args []string) error {
+	var rigs []*rig.Rig
+	if polecatPoolMaintainAll {
+		allRigs, _, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		rigs = allRigs
+	} else {
+		if len(args) < 1 {
+			return fmt.Errorf("rig name required (or use --all)")
+		}
+		_, r, err := getPolecatManager(args[0])
+		if err != nil {
+			return err
+		}
+		rigs = []*rig.Rig{r}
+	}
+
+	var failed int
+	for _, r := range rigs {
+		mgr, _, err := getPolecatManager(r.Name)
+		if err != nil {
+			failed++
+			fmt.Printf("%s %s: %v\n", style.ErrorPrefix, r.Name, err)
+			continue
+		}
+		size := worktreePoolSize(r)
+		res, err := mgr.MaintainWorktreePool(size)
+		if err != nil {
+			failed++
+			fmt.Printf("%s %s: %v\n", style.ErrorPrefix, r.Name, err)
+			continue
+		}
+		if size == 0 && res.Removed == 0 {
+			continue
+		}
+		fmt.Printf("%s %s: %d/%d ready %s\n", style.SuccessPrefix, r.Name, res.Ready, size,
+			style.Dim.Render(fmt.Sprintf("(created %d, refreshed %d, removed %d)", res.Created, res.Refreshed, res.Removed)))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d rig pool(s) failed maintenance", failed)
+	}
+	return nil
+}
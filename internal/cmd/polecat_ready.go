@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Readiness exit codes. Scripts gate on these instead of parsing output;
+// 3 keeps lookup failures apart from "starting" (cobra errors exit 1).
+const (
+	ExitReady    = 0
+	ExitStarting = 1
+	ExitDead     = 2
+	ExitUnknown  = 3
+)
+
+// agentReadiness is the scripting-level state of an agent session.
+type agentReadiness int
+
+const (
+	readinessReady agentReadiness = iota
+	readinessStarting
+	readinessDead
+)
+
+func (r agentReadiness) String() string {
+	switch r {
+	case readinessReady:
+		return "ready"
+	case readinessStarting:
+		return "starting"
+	default:
+		return "dead"
+	}
+}
+
+// exitCode maps the state to its documented exit code.
+func (r agentReadiness) exitCode() int {
+	switch r {
+	case readinessReady:
+		return ExitReady
+	case readinessStarting:
+		return ExitStarting
+	default:
+		return ExitDead
+	}
+}
+
+var (
+	polecatReadyWait  time.Duration
+	polecatReadyGrace time.Duration
+	polecatReadyQuiet bool
+)
+
+var polecatReadyCmd = &cobra.Command{
+	Use:   "ready <rig>/<polecat>",
+	Short: "Report whether a polecat is ready, via exit code",
+	Long: `Report whether an agent session is ready to take work, for scripts and CI.
+
+Exit codes:
+  0  ready     the agent is running and showing its input prompt
+  1  starting  the session exists but the agent has not reached its prompt
+  2  dead      no session, or the agent process exited after startup
+  3  unknown   the rig or agent could not be resolved
+
+A session whose agent process is not running yet counts as starting for
+--grace after the session was created, and as dead after that.
+
+The state is also printed ("ready", "starting", "dead") unless --quiet.
+With --wait, polls until the agent is ready or dead, or the wait expires
+(then exits with the last state, usually 1).
+
+Crew workers use the rig/crew/name form.
+
+Examples:
+  gt polecat ready greenplace/Toast
+  gt polecat ready greenplace/Toast --wait 2m && gt nudge greenplace/Toast "go"
+  gt polecat ready beads/crew/dave -q || echo "dave is not up"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatReady,
+}
+
+func init() {
+	polecatReadyCmd.Flags().DurationVar(&polecatReadyWait, "wait", 0, "Wait up to this long for the agent to become ready")
+	polecatReadyCmd.Flags().DurationVar(&polecatReadyGrace, "grace", 2*time.Minute, "How long after session creation a missing agent process counts as starting")
+	polecatReadyCmd.Flags().BoolVarP(&polecatReadyQuiet, "quiet", "q", false, "Print nothing; only set the exit code")
+
+	polecatCmd.AddCommand(polecatReadyCmd)
+}
+
+func runPolecatReady(cmd *cobra.Command, args []string) error {
+	sessionName, rc, err := resolveReadySession(args[0])
+	if err != nil {
+		if !polecatReadyQuiet {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		return NewSilentExit(ExitUnknown)
+	}
+
+	t := tmux.NewTmux()
+	deadline := time.Now().Add(polecatReadyWait)
+	state := checkReadiness(t, sessionName, rc, polecatReadyGrace)
+	for state == readinessStarting && time.Now().Before(deadline) {
+		time.Sleep(500 * time.Millisecond)
+		state = checkReadiness(t, sessionName, rc, polecatReadyGrace)
+	}
+
+	if !polecatReadyQuiet {
+		fmt.Println(state)
+	}
+	if code := state.exitCode(); code != ExitReady {
+		return NewSilentExit(code)
+	}
+	return nil
+}
+
+// resolveReadySession returns the tmux session and runtime config for a
+// polecat (rig/name) or crew (rig/crew/name) address.
+func resolveReadySession(address string) (string, *config.RuntimeConfig, error) {
+	rigName, name, err := parseAddress(address)
+	if err != nil {
+		return "", nil, err
+	}
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return "", nil, err
+	}
+	if crewName, ok := strings.CutPrefix(name, "crew/"); ok {
+		return session.CrewSessionName(session.PrefixFor(rigName), crewName),
+			config.ResolveRoleAgentConfig("crew", townRoot, r.Path), nil
+	}
+	return session.PolecatSessionName(session.PrefixFor(rigName), name),
+		config.ResolveRoleAgentConfig("polecat", townRoot, r.Path), nil
+}
+
+// checkReadiness classifies a session without blocking. An agent is ready
+// once its process runs and, for runtimes with prompt detection, its input
+// prompt is on screen.
+func checkReadiness(t *tmux.Tmux, sessionName string, rc *config.RuntimeConfig, grace time.Duration) agentReadiness {
+	if alive, err := t.HasSession(sessionName); err != nil || !alive {
+		return readinessDead
+	}
+	if !t.IsAgentAlive(sessionName) {
+		created, err := t.GetSessionCreatedUnix(sessionName)
+		if err == nil && time.Since(time.Unix(created, 0)) < grace {
+			return readinessStarting
+		}
+		return readinessDead
+	}
+	if rc != nil && rc.Tmux != nil && rc.Tmux.ReadyPromptPrefix != "" && !t.IsAtPrompt(sessionName, rc) {
+		return readinessStarting
+	}
+	return readinessReady
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestAgentReadinessExitCodes(t *testing.T) {
+	for state, want := range map[agentReadiness]int{
+		readinessReady:    0,
+		readinessStarting: 1,
+		readinessDead:     2,
+	} {
+		if got := state.exitCode(); got != want {
+			t.Errorf("%s.exitCode() = %d, want %d", state, got, want)
+		}
+	}
+}
+
+func TestCheckReadiness(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("tmux not supported on Windows")
+	}
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	tm := tmux.NewTmux()
+	name := fmt.Sprintf("gt-readytest-%d", os.Getpid())
+	rc := &config.RuntimeConfig{Tmux: &config.RuntimeTmuxConfig{ReadyPromptPrefix: "❯ "}}
+
+	if got := checkReadiness(tm, name, rc, time.Hour); got != readinessDead {
+		t.Errorf("missing session = %s, want dead", got)
+	}
+
+	// The "agent" is sleep: running but silent, then showing its prompt.
+	if err := tm.NewSessionWithCommand(name, t.TempDir(), `sh -c 'sleep 2; printf "\342\235\257 \n"; exec sleep 60'`); err != nil {
+		t.Skipf("cannot start tmux session: %v", err)
+	}
+	defer func() { _ = tm.KillSession(name) }()
+
+	if err := tm.SetEnvironment(name, "GT_PROCESS_NAMES", "no-such-agent"); err != nil {
+		t.Fatal(err)
+	}
+	if got := checkReadiness(tm, name, rc, time.Hour); got != readinessStarting {
+		t.Errorf("agent not running within grace = %s, want starting", got)
+	}
+	if got := checkReadiness(tm, name, rc, 0); got != readinessDead {
+		t.Errorf("agent not running after grace = %s, want dead", got)
+	}
+
+	if err := tm.SetEnvironment(name, "GT_PROCESS_NAMES", "sleep"); err != nil {
+		t.Fatal(err)
+	}
+	if got := checkReadiness(tm, name, rc, time.Hour); got != readinessStarting {
+		t.Errorf("agent before prompt = %s, want starting", got)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for checkReadiness(tm, name, rc, time.Hour) != readinessReady {
+		if time.Now().After(deadline) {
+			t.Fatal("agent never became ready after showing its prompt")
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+var (
+	polecatSnapshotOutput string
+	polecatSnapshotLines  int
+)
+
+var polecatSnapshotCmd = &cobra.Command{
+	Use:   "snapshot <rig>/<polecat>",
+	Short: "Capture a polecat's state into a single archive",
+	Long: `Capture everything needed to reproduce a polecat's state into one
+tar.gz that can be attached to an issue.
+
+The archive contains:
+  manifest.json    Polecat state, branch, assigned issue, session health
+  diff.patch       Committed changes vs the default branch plus uncommitted work
+  git-status.txt   Porcelain status of the worktree
+  env.txt          tmux session environment (secret-looking values redacted)
+  transcript.txt   Recent pane scrollback
+
+Collection problems (no session, unreachable origin) are recorded in the
+manifest rather than failing the snapshot.
+
+Examples:
+  gt polecat snapshot greenplace/Toast
+  gt polecat snapshot greenplace/Toast -o /tmp/toast.tar.gz
+  gt polecat snapshot greenplace/Toast --lines 5000`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatSnapshot,
+}
+
+func init() {
+	polecatSnapshotCmd.Flags().StringVarP(&polecatSnapshotOutput, "output", "o", "", "Archive path (default: ./<rig>-<polecat>-<timestamp>.tar.gz)")
+	polecatSnapshotCmd.Flags().IntVar(&polecatSnapshotLines, "lines", 2000, "Lines of pane scrollback to include")
+	polecatCmd.AddCommand(polecatSnapshotCmd)
+}
+
+// polecatSnapshotManifest is the manifest.json of a snapshot archive.
+type polecatSnapshotManifest struct {
+	Rig          string    `json:"rig"`
+	Name         string    `json:"name"`
+	State        string    `json:"state"`
+	Issue        string    `json:"issue,omitempty"`
+	Branch       string    `json:"branch,omitempty"`
+	Base         string    `json:"base,omitempty"`
+	ClonePath    string    `json:"clone_path"`
+	Session      string    `json:"session"`
+	Running      bool      `json:"session_running"`
+	Attached     bool      `json:"attached"`
+	PaneDead     bool      `json:"pane_dead"`
+	PaneCommand  string    `json:"pane_command,omitempty"`
+	Created      string    `json:"session_created,omitempty"`
+	LastActivity string    `json:"last_activity,omitempty"`
+	GTVersion    string    `json:"gt_version"`
+	CapturedAt   time.Time `json:"captured_at"`
+	Errors       []string  `json:"errors,omitempty"`
+}
+
+func runPolecatSnapshot(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+
+	mgr, r, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	p, err := mgr.Get(polecatName)
+	if err != nil {
+		return fmt.Errorf("polecat '%s' not found in rig '%s'", polecatName, rigName)
+	}
+
+	t := tmux.NewTmux()
+	sessMgr := polecat.NewSessionManager(t, r)
+	sessionName := sessMgr.SessionName(polecatName)
+
+	m := &polecatSnapshotManifest{
+		Rig:        rigName,
+		Name:       polecatName,
+		State:      string(p.State),
+		Issue:      p.Issue,
+		Branch:     p.Branch,
+		ClonePath:  p.ClonePath,
+		Session:    sessionName,
+		GTVersion:  Version,
+		CapturedAt: time.Now().UTC(),
+	}
+	note := func(format string, a ...interface{}) {
+		m.Errors = append(m.Errors, fmt.Sprintf(format, a...))
+	}
+
+	files := map[string][]byte{}
+
+	// Worktree diff: committed work relative to the default branch, then
+	// anything not yet committed.
+	m.Base = "origin/" + git.NewGit(p.ClonePath).RemoteDefaultBranch()
+	var diff strings.Builder
+	if out, err := snapshotGit(p.ClonePath, "diff", m.Base+"...HEAD"); err != nil {
+		note("diff vs %s: %v", m.Base, err)
+	} else {
+		fmt.Fprintf(&diff, "# Committed changes (%s...HEAD)\n%s", m.Base, out)
+	}
+	if out, err := snapshotGit(p.ClonePath, "diff", "HEAD"); err != nil {
+		note("uncommitted diff: %v", err)
+	} else {
+		fmt.Fprintf(&diff, "\n# Uncommitted changes\n%s", out)
+	}
+	files["diff.patch"] = []byte(diff.String())
+	if out, err := snapshotGit(p.ClonePath, "status", "--porcelain", "--branch"); err != nil {
+		note("git status: %v", err)
+	} else {
+		files["git-status.txt"] = []byte(out)
+	}
+
+	// Session health, environment, and transcript.
+	if info, err := sessMgr.Status(polecatName); err != nil {
+		note("session status: %v", err)
+	} else {
+		m.Running = info.Running
+		m.Attached = info.Attached
+		if !info.Created.IsZero() {
+			m.Created = info.Created.Format(time.RFC3339)
+		}
+		if !info.LastActivity.IsZero() {
+			m.LastActivity = info.LastActivity.Format(time.RFC3339)
+		}
+	}
+	if m.Running {
+		m.PaneDead = t.IsPaneDead(sessionName)
+		m.PaneCommand, _ = t.GetPaneCommand(sessionName)
+		if env, err := t.GetAllEnvironment(sessionName); err != nil {
+			note("session environment: %v", err)
+		} else {
+			files["env.txt"] = []byte(formatSnapshotEnv(env))
+		}
+		if out, err := t.CapturePane(sessionName, polecatSnapshotLines); err != nil {
+			note("capture pane: %v", err)
+		} else {
+			files["transcript.txt"] = []byte(out)
+		}
+	} else {
+		note("session %s not running; no environment or transcript captured", sessionName)
+	}
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	files["manifest.json"] = append(manifest, '\n')
+
+	out := polecatSnapshotOutput
+	if out == "" {
+		out = fmt.Sprintf("%s-%s-%s.tar.gz", rigName, polecatName, m.CapturedAt.Format("20060102-150405"))
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("creating archive: %w", err)
+	}
+	if err := writeSnapshotArchive(f, m.Rig+"-"+m.Name, m.CapturedAt, files); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+
+	fmt.Printf("%s Snapshot of %s/%s written to %s\n", style.SuccessPrefix, rigName, polecatName, style.Bold.Render(out))
+	for _, e := range m.Errors {
+		fmt.Printf("  %s %s\n", style.Warning.Render("!"), e)
+	}
+	return nil
+}
+
+// snapshotGit runs a read-only git command in the worktree.
+func snapshotGit(dir string, args ...string) (string, error) {
+	c := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := c.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("%s", strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
+// snapshotSecretMarkers are substrings of environment variable names whose
+// values must not leave the machine in a snapshot.
+var snapshotSecretMarkers = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "API_KEY", "APIKEY", "PRIVATE", "CREDENTIAL"}
+
+// formatSnapshotEnv renders env as sorted KEY=value lines with secret-looking
+// values redacted.
+func formatSnapshotEnv(env map[string]string) string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		v := env[k]
+		upper := strings.ToUpper(k)
+		for _, marker := range snapshotSecretMarkers {
+			if strings.Contains(upper, marker) {
+				v = "[REDACTED]"
+				break
+			}
+		}
+		fmt.Fprintf(&b, "%s=%s\n", k, v)
+	}
+	return b.String()
+}
+
+// writeSnapshotArchive writes files as a gzipped tar under a top-level
+// directory named prefix. Entries are written in sorted order so archives
+// of identical state are identical.
+func writeSnapshotArchive(w io.Writer, prefix string, modTime time.Time, files map[string][]byte) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data := files[name]
+		hdr := &tar.Header{
+			Name:    prefix + "/" + name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
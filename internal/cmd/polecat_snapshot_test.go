@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatSnapshotEnvRedactsSecrets(t *testing.T) {
+	got := formatSnapshotEnv(map[string]string{
+		"GT_RIG":            "greenplace",
+		"ANTHROPIC_API_KEY": "sk-123",
+		"GITHUB_TOKEN":      "ghp_abc",
+		"GIT_AUTHOR_NAME":   "Toast",
+	})
+	want := "ANTHROPIC_API_KEY=[REDACTED]\nGITHUB_TOKEN=[REDACTED]\nGIT_AUTHOR_NAME=Toast\nGT_RIG=greenplace\n"
+	if got != want {
+		t.Errorf("formatSnapshotEnv() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteSnapshotArchive(t *testing.T) {
+	var buf bytes.Buffer
+	files := map[string][]byte{
+		"manifest.json": []byte("{}\n"),
+		"diff.patch":    []byte("diff --git a/x b/x\n"),
+	}
+	if err := writeSnapshotArchive(&buf, "greenplace-Toast", time.Unix(0, 0), files); err != nil {
+		t.Fatalf("writeSnapshotArchive: %v", err)
+	}
+
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("tar: %v", err)
+		}
+		names = append(names, hdr.Name)
+		data, _ := io.ReadAll(tr)
+		base := strings.TrimPrefix(hdr.Name, "greenplace-Toast/")
+		if string(data) != string(files[base]) {
+			t.Errorf("%s = %q, want %q", hdr.Name, data, files[base])
+		}
+	}
+	want := "greenplace-Toast/diff.patch,greenplace-Toast/manifest.json"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("entries = %s, want %s", got, want)
+	}
+}
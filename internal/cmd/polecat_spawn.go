@@ -6,15 +6,20 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/doctor"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/featureflag"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/runcontext"
+	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -27,11 +32,21 @@ type SpawnedPolecatInfo struct {
 	ClonePath   string // Path to polecat's git worktree
 	SessionName string // Tmux session name (e.g., "gt-gastown-p-Toast")
 	Pane        string // Tmux pane ID (empty until StartSession is called)
-	BaseBranch string // Effective base branch (e.g., "main", "integration/epic-id")
+	BaseBranch  string // Effective base branch (e.g., "main", "integration/epic-id")
+
+	// AgentVersion is the installed agent CLI version ("" if unknown),
+	// recorded on the dispatch for reproducibility.
+	AgentVersion string
+
+	// Features are the configured feature flags consulted while spawning,
+	// recorded on the dispatch for later analysis.
+	Features []featureflag.Decision
 
 	// Internal fields for deferred session start
-	account string
-	agent   string
+	account  string
+	agent    string
+	hookBead string
+	standby  bool // claimed from warm standby: session already running
 }
 
 // AgentID returns the agent identifier (e.g., "gastown/polecats/Toast")
@@ -95,6 +110,45 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		return nil, fmt.Errorf("admission control: %w", err)
 	}
 
+	// Environment preflight (gt doctor --rig): fail fast with a precise
+	// reason instead of spawning a session that can't succeed.
+	if err := rigPreflight(townRoot, r.Name, r.Path, opts.Agent); err != nil {
+		return nil, err
+	}
+
+	// Spend budget (gt budget): no new polecats once the rig is over.
+	if err := checkRigBudget(townRoot, r.Name); err != nil {
+		return nil, err
+	}
+
+	// Enforce the rig's pinned agent version (agent_version) and note the
+	// installed version to record on the dispatch.
+	agentVersion, err := polecat.NewSessionManager(t, r).VerifyAgentVersion(opts.Agent)
+	if err != nil {
+		return nil, err
+	}
+
+	// The warm_pools feature flag can keep this dispatch off standby
+	// sessions and pooled worktrees.
+	var features []featureflag.Decision
+	warmPools := featureflag.Evaluate(townRoot, featureflag.WarmPools, rigName, opts.HookBead)
+	if warmPools.Source != featureflag.SourceDefault {
+		features = append(features, warmPools)
+	}
+	if !warmPools.Enabled {
+		fmt.Printf("  Warm pools off for this dispatch (%s)\n", warmPools.Reason)
+	}
+
+	// Warm standby: claim a polecat whose session is already running, so
+	// the agent's startup is off the dispatch path. Standby sessions run the
+	// rig's default agent and account, so overrides need a fresh session.
+	if opts.Agent == "" && opts.Account == "" && warmPools.Enabled {
+		if info := claimStandbyPolecat(polecatMgr, t, r, opts, agentVersion); info != nil {
+			info.Features = features
+			return info, nil
+		}
+	}
+
 	// Persistent polecat model (gt-4ac): try to reuse an idle polecat first.
 	// Idle polecats have completed their work but kept their sandbox (worktree).
 	// Reusing avoids the overhead of creating a new worktree.
@@ -104,28 +158,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		fmt.Printf("Reusing idle polecat: %s\n", polecatName)
 
 		// Determine base branch
-		baseBranch := opts.BaseBranch
-		if baseBranch == "" && opts.HookBead != "" {
-			settingsPath := filepath.Join(r.Path, "settings", "config.json")
-			polecatIntegrationEnabled := true
-			if settings, err := config.LoadRigSettings(settingsPath); err == nil && settings.MergeQueue != nil {
-				polecatIntegrationEnabled = settings.MergeQueue.IsPolecatIntegrationEnabled()
-			}
-			if polecatIntegrationEnabled {
-				repoGit, repoErr := getRigGit(r.Path)
-				if repoErr == nil {
-					bd := beads.New(r.Path)
-					detected, detectErr := beads.DetectIntegrationBranch(bd, repoGit, opts.HookBead)
-					if detectErr == nil && detected != "" {
-						baseBranch = "origin/" + detected
-						fmt.Printf("  Auto-detected integration branch: %s\n", detected)
-					}
-				}
-			}
-		}
-		if baseBranch != "" && !strings.HasPrefix(baseBranch, "origin/") {
-			baseBranch = "origin/" + baseBranch
-		}
+		baseBranch := spawnBaseBranch(r, opts)
 
 		// Repair the idle polecat's worktree for fresh work
 		addOpts := polecat.AddOptions{
@@ -157,14 +190,17 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 			}
 
 			return &SpawnedPolecatInfo{
-				RigName:     rigName,
-				PolecatName: polecatName,
-				ClonePath:   polecatObj.ClonePath,
-				SessionName: sessionName,
-				Pane:        "",
-				BaseBranch:  effectiveBranch,
-				account:     opts.Account,
-				agent:       opts.Agent,
+				RigName:      rigName,
+				PolecatName:  polecatName,
+				ClonePath:    polecatObj.ClonePath,
+				SessionName:  sessionName,
+				Pane:         "",
+				BaseBranch:   effectiveBranch,
+				AgentVersion: agentVersion,
+				Features:     features,
+				account:      opts.Account,
+				agent:        opts.Agent,
+				hookBead:     opts.HookBead,
 			}, nil
 		}
 	}
@@ -180,34 +216,13 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	existingPolecat, err := polecatMgr.Get(polecatName)
 
 	// Determine base branch for polecat worktree
-	baseBranch := opts.BaseBranch
-	if baseBranch == "" && opts.HookBead != "" {
-		// Auto-detect: check if the hooked bead's parent epic has an integration branch
-		settingsPath := filepath.Join(r.Path, "settings", "config.json")
-		polecatIntegrationEnabled := true
-		if settings, err := config.LoadRigSettings(settingsPath); err == nil && settings.MergeQueue != nil {
-			polecatIntegrationEnabled = settings.MergeQueue.IsPolecatIntegrationEnabled()
-		}
-		if polecatIntegrationEnabled {
-			repoGit, repoErr := getRigGit(r.Path)
-			if repoErr == nil {
-				bd := beads.New(r.Path)
-				detected, detectErr := beads.DetectIntegrationBranch(bd, repoGit, opts.HookBead)
-				if detectErr == nil && detected != "" {
-					baseBranch = "origin/" + detected
-					fmt.Printf("  Auto-detected integration branch: %s\n", detected)
-				}
-			}
-		}
-	}
-	if baseBranch != "" && !strings.HasPrefix(baseBranch, "origin/") {
-		baseBranch = "origin/" + baseBranch
-	}
+	baseBranch := spawnBaseBranch(r, opts)
 
 	// Build add options with hook_bead set atomically at spawn time
 	addOpts := polecat.AddOptions{
 		HookBead:   opts.HookBead,
 		BaseBranch: baseBranch,
+		NoPool:     !warmPools.Enabled,
 	}
 
 	if err == nil {
@@ -279,14 +294,17 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	}
 
 	return &SpawnedPolecatInfo{
-		RigName:     rigName,
-		PolecatName: polecatName,
-		ClonePath:   polecatObj.ClonePath,
-		SessionName: sessionName,
-		Pane:        "", // Empty until StartSession is called
-		BaseBranch: effectiveBranch,
-		account:     opts.Account,
-		agent:       opts.Agent,
+		RigName:      rigName,
+		PolecatName:  polecatName,
+		ClonePath:    polecatObj.ClonePath,
+		SessionName:  sessionName,
+		Pane:         "", // Empty until StartSession is called
+		BaseBranch:   effectiveBranch,
+		AgentVersion: agentVersion,
+		Features:     features,
+		account:      opts.Account,
+		agent:        opts.Agent,
+		hookBead:     opts.HookBead,
 	}, nil
 }
 
@@ -318,6 +336,10 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 		return "", fmt.Errorf("rig '%s' not found", s.RigName)
 	}
 
+	if s.standby {
+		return s.wakeStandby(tmux.NewTmux(), r)
+	}
+
 	// Resolve account
 	accountsPath := constants.MayorAccountsPath(townRoot)
 	claudeConfigDir, _, err := config.ResolveAccountConfigDir(accountsPath, s.account)
@@ -332,6 +354,7 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	fmt.Printf("Starting session for %s/%s...\n", s.RigName, s.PolecatName)
 	startOpts := polecat.SessionStartOptions{
 		RuntimeConfigDir: claudeConfigDir,
+		HookBead:         s.hookBead,
 		Agent:            s.agent,
 	}
 	if s.agent != "" {
@@ -399,6 +422,117 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	return pane, nil
 }
 
+// spawnBaseBranch returns the origin/ ref a spawned polecat's worktree
+// starts from: the --base-branch override, else the hooked bead's epic
+// integration branch if the rig uses them, else "" for the default branch.
+func spawnBaseBranch(r *rig.Rig, opts SlingSpawnOptions) string {
+	baseBranch := opts.BaseBranch
+	if baseBranch == "" && opts.HookBead != "" {
+		// Auto-detect: check if the hooked bead's parent epic has an integration branch
+		settingsPath := filepath.Join(r.Path, "settings", "config.json")
+		polecatIntegrationEnabled := true
+		if settings, err := config.LoadRigSettings(settingsPath); err == nil && settings.MergeQueue != nil {
+			polecatIntegrationEnabled = settings.MergeQueue.IsPolecatIntegrationEnabled()
+		}
+		if polecatIntegrationEnabled {
+			repoGit, repoErr := getRigGit(r.Path)
+			if repoErr == nil {
+				bd := beads.New(r.Path)
+				detected, detectErr := beads.DetectIntegrationBranch(bd, repoGit, opts.HookBead)
+				if detectErr == nil && detected != "" {
+					baseBranch = "origin/" + detected
+					fmt.Printf("  Auto-detected integration branch: %s\n", detected)
+				}
+			}
+		}
+	}
+	if baseBranch != "" && !strings.HasPrefix(baseBranch, "origin/") {
+		baseBranch = "origin/" + baseBranch
+	}
+	return baseBranch
+}
+
+// claimStandbyPolecat claims a warm standby polecat for the work in opts,
+// or returns nil if none could be claimed and a normal spawn should follow.
+func claimStandbyPolecat(polecatMgr *polecat.Manager, t *tmux.Tmux, r *rig.Rig, opts SlingSpawnOptions, agentVersion string) *SpawnedPolecatInfo {
+	standby, err := polecatMgr.FindStandbyPolecat()
+	if err != nil || standby == nil {
+		return nil
+	}
+	fmt.Printf("Claiming standby polecat: %s\n", standby.Name)
+
+	baseBranch := spawnBaseBranch(r, opts)
+	p, err := polecatMgr.ClaimStandby(standby.Name, polecat.AddOptions{
+		HookBead:   opts.HookBead,
+		BaseBranch: baseBranch,
+	})
+	if err != nil {
+		fmt.Printf("  Could not claim standby polecat %s: %v, falling back...\n", standby.Name, err)
+		return nil
+	}
+
+	fmt.Printf("%s Polecat %s claimed from warm standby (session already running)\n", style.Bold.Render("✓"), p.Name)
+	_ = events.LogFeed(events.TypeSpawn, "gt", events.SpawnPayload(r.Name, p.Name))
+
+	effectiveBranch := strings.TrimPrefix(baseBranch, "origin/")
+	if effectiveBranch == "" {
+		effectiveBranch = r.DefaultBranch()
+	}
+	return &SpawnedPolecatInfo{
+		RigName:      r.Name,
+		PolecatName:  p.Name,
+		ClonePath:    p.ClonePath,
+		SessionName:  polecat.NewSessionManager(t, r).SessionName(p.Name),
+		BaseBranch:   effectiveBranch,
+		AgentVersion: agentVersion,
+		hookBead:     opts.HookBead,
+		standby:      true,
+	}
+}
+
+// wakeStandby hands a claimed standby session its work: the session stops
+// counting as standby, the polecat is recorded as working, and the waiting
+// agent is nudged to check its hook.
+func (s *SpawnedPolecatInfo) wakeStandby(t *tmux.Tmux, r *rig.Rig) (string, error) {
+	pane, err := getSessionPane(s.SessionName)
+	if err != nil {
+		return "", fmt.Errorf("getting pane for standby session %s: %w", s.SessionName, err)
+	}
+	_ = t.SetEnvironment(s.SessionName, polecat.StandbyEnvVar, "0")
+	// The worktree moved to a work branch after the session started.
+	if branch, err := git.NewGit(s.ClonePath).CurrentBranch(); err == nil {
+		_ = t.SetEnvironment(s.SessionName, "GT_BRANCH", branch)
+	}
+
+	// Warn-only, as in StartSession: the session is running either way.
+	polecatMgr := polecat.NewManager(r, git.NewGit(r.Path), t)
+	if err := polecatMgr.SetAgentStateWithRetry(s.PolecatName, "working"); err != nil {
+		style.PrintWarning("could not update agent state after retries: %v", err)
+	}
+	if err := polecatMgr.SetState(s.PolecatName, polecat.StateWorking); err != nil {
+		style.PrintWarning("could not update issue status to in_progress: %v", err)
+	}
+
+	nudge := runtime.StartupNudgeContent()
+	if s.hookBead != "" {
+		if err := polecat.NewSessionManager(t, r).WriteRunContext(s.PolecatName, s.ClonePath, s.hookBead, s.agent); err != nil {
+			style.PrintWarning("could not write run context: %v", err)
+		} else {
+			nudge += "\n\nDispatch context (issue, constraints, budgets, commands): `" + runcontext.RelMarkdownPath() + "`"
+		}
+	}
+
+	if os.Getenv("GT_TEST_NO_NUDGE") == "" {
+		if err := t.NudgeSession(s.SessionName, nudge); err != nil {
+			return "", fmt.Errorf("nudging standby session %s: %w", s.SessionName, err)
+		}
+	}
+	fmt.Printf("Woke standby session for %s/%s\n", s.RigName, s.PolecatName)
+
+	s.Pane = pane
+	return pane, nil
+}
+
 // IsRigName checks if a target string is a rig name (not a role or path).
 // Returns the rig name and true if it's a valid rig.
 func IsRigName(target string) (string, bool) {
@@ -466,3 +600,32 @@ func verifyWorktreeExists(clonePath string) error {
 
 	return nil
 }
+
+// preflightCache remembers preflight results per rig and agent for the life
+// of the process, so slinging a batch to one rig checks it once.
+var preflightCache = struct {
+	sync.Mutex
+	results map[string]error
+}{results: make(map[string]error)}
+
+// rigPreflight runs the rig's environment preflight unless the rig disables
+// it in settings (preflight.disabled).
+func rigPreflight(townRoot, rigName, rigPath, agent string) error {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err == nil && settings.Preflight != nil && settings.Preflight.Disabled {
+		return nil
+	}
+
+	key := rigName + "|" + agent
+	preflightCache.Lock()
+	defer preflightCache.Unlock()
+	if err, ok := preflightCache.results[key]; ok {
+		return err
+	}
+	err = nil
+	if perr := doctor.RunPreflight(&doctor.CheckContext{TownRoot: townRoot, RigName: rigName, Agent: agent}); perr != nil {
+		err = fmt.Errorf("%w (see: gt doctor --rig %s)", perr, rigName)
+	}
+	preflightCache.results[key] = err
+	return err
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/featureflag"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+var polecatStandbyMaintainAll bool
+
+var polecatStandbyCmd = &cobra.Command{
+	Use:   "standby <rig>",
+	Short: "Show a rig's warm standby polecats",
+	Long: `Show the warm standby polecats of a rig.
+
+When a rig sets warm_standby.size in settings/config.json, that many
+polecats are kept with their agent session already started and waiting at
+an idle prompt. gt sling claims a standby polecat before reusing an idle
+one or creating a new one: its worktree is reset onto the work branch in
+place and the agent is nudged, so dispatch skips the agent's startup.
+Slings with --agent or --account always start a fresh session.
+
+Standby polecats are replenished by 'gt polecat standby maintain', which
+the witness runs on every patrol. They do not count toward the scheduler's
+max_polecats.
+
+Examples:
+  gt polecat standby greenplace
+  gt polecat standby maintain greenplace
+  gt polecat standby maintain --all`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatStandby,
+}
+
+var polecatStandbyMaintainCmd = &cobra.Command{
+	Use:   "maintain [rig]",
+	Short: "Start or stop standby polecats to match warm_standby.size",
+	Long: `Bring a rig's warm standby polecats to warm_standby.size.
+
+Missing standby polecats are started from idle polecats first, then newly
+created ones. Surplus standby sessions are stopped and their polecats go
+idle. Standby polecats whose session died are returned to idle. Rigs
+without warm_standby.size have all standby sessions stopped.
+
+No new standby sessions are started while the rig is over its spend budget.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPolecatStandbyMaintain,
+}
+
+func init() {
+	polecatStandbyMaintainCmd.Flags().BoolVar(&polecatStandbyMaintainAll, "all", false, "Maintain the standby polecats of all rigs")
+	polecatStandbyCmd.AddCommand(polecatStandbyMaintainCmd)
+	polecatCmd.AddCommand(polecatStandbyCmd)
+}
+
+// warmStandbySize returns the configured standby size of a rig (0 if unset or
+// switched off by the warm_pools feature flag).
+func warmStandbySize(r *rig.Rig) int {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil || settings.WarmStandby == nil {
+		return 0
+	}
+	if !featureflag.Enabled(filepath.Dir(r.Path), featureflag.WarmPools, r.Name, "") {
+		return 0
+	}
+	return settings.WarmStandby.Size
+}
+
+func runPolecatStandby(cmd *cobra.Command, args []string) error {
+	mgr, r, err := getPolecatManager(args[0])
+	if err != nil {
+		return err
+	}
+	standby, err := mgr.StandbyPolecats()
+	if err != nil {
+		return fmt.Errorf("listing standby polecats: %w", err)
+	}
+	size := warmStandbySize(r)
+	if size == 0 && len(standby) == 0 {
+		fmt.Printf("No warm standby for %s %s\n", r.Name,
+			style.Dim.Render("(set warm_standby.size in settings/config.json)"))
+		return nil
+	}
+	fmt.Printf("%s warm standby: %d/%d ready\n", style.Bold.Render(r.Name), len(standby), size)
+	for _, p := range standby {
+		fmt.Printf("  %s\n", p.Name)
+	}
+	return nil
+}
+
+func runPolecatStandbyMaintain(cmd *cobra.Command, args []string) error {
+	var rigs []*rig.Rig
+	if polecatStandbyMaintainAll {
+		allRigs, _, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		rigs = allRigs
+	} else {
+		if len(args) < 1 {
+			return fmt.Errorf("rig name required (or use --all)")
+		}
+		_, r, err := getPolecatManager(args[0])
+		if err != nil {
+			return err
+		}
+		rigs = []*rig.Rig{r}
+	}
+
+	var failed int
+	for _, r := range rigs {
+		mgr, _, err := getPolecatManager(r.Name)
+		if err != nil {
+			failed++
+			fmt.Printf("%s %s: %v\n", style.ErrorPrefix, r.Name, err)
+			continue
+		}
+		size := warmStandbySize(r)
+		res, err := maintainWarmStandby(mgr, r, size)
+		if size == 0 && res.Stopped == 0 && res.Released == 0 && err == nil {
+			continue
+		}
+		summary := fmt.Sprintf("%s: %d/%d ready %s", r.Name, res.Ready, size,
+			style.Dim.Render(fmt.Sprintf("(started %d, stopped %d, released %d)", res.Started, res.Stopped, res.Released)))
+		if err != nil {
+			failed++
+			fmt.Printf("%s %s: %v\n", style.ErrorPrefix, summary, err)
+			continue
+		}
+		fmt.Printf("%s %s\n", style.SuccessPrefix, summary)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d rig(s) failed standby maintenance", failed)
+	}
+	return nil
+}
+
+// warmStandbyResult reports what maintainWarmStandby did.
+type warmStandbyResult struct {
+	Ready    int // standby polecats with a running session afterwards
+	Started  int // standby sessions started
+	Stopped  int // surplus standby sessions stopped
+	Released int // standby polecats whose session had died, returned to idle
+}
+
+// maintainWarmStandby brings a rig's standby polecats to size.
+func maintainWarmStandby(mgr *polecat.Manager, r *rig.Rig, size int) (warmStandbyResult, error) {
+	var res warmStandbyResult
+	polecats, err := mgr.List()
+	if err != nil {
+		return res, fmt.Errorf("listing polecats: %w", err)
+	}
+
+	t := tmux.NewTmux()
+	sessMgr := polecat.NewSessionManager(t, r)
+	for _, p := range polecats {
+		if p.State != polecat.StateStandby {
+			continue
+		}
+		running, _ := sessMgr.IsRunning(p.Name)
+		switch {
+		case !running:
+			if err := mgr.SetAgentState(p.Name, "idle"); err == nil {
+				res.Released++
+			}
+		case res.Ready >= size:
+			if err := sessMgr.Stop(p.Name, false); err != nil {
+				return res, fmt.Errorf("stopping surplus standby %s: %w", p.Name, err)
+			}
+			if err := mgr.SetAgentState(p.Name, "idle"); err != nil {
+				return res, fmt.Errorf("marking %s idle: %w", p.Name, err)
+			}
+			res.Stopped++
+		default:
+			res.Ready++
+		}
+	}
+	if res.Ready >= size {
+		return res, nil
+	}
+
+	townRoot := filepath.Dir(r.Path)
+	if err := checkRigBudget(townRoot, r.Name); err != nil {
+		return res, err
+	}
+	claudeConfigDir, _, err := config.ResolveAccountConfigDir(constants.MayorAccountsPath(townRoot), "")
+	if err != nil {
+		return res, fmt.Errorf("resolving account: %w", err)
+	}
+
+	for res.Ready < size {
+		name, err := nextStandbyPolecat(mgr)
+		if err != nil {
+			return res, err
+		}
+		if err := sessMgr.Start(name, polecat.SessionStartOptions{RuntimeConfigDir: claudeConfigDir}); err != nil {
+			_ = mgr.SetAgentState(name, "idle")
+			return res, fmt.Errorf("starting standby session for %s: %w", name, err)
+		}
+		_ = t.SetEnvironment(sessMgr.SessionName(name), polecat.StandbyEnvVar, "1")
+		res.Started++
+		res.Ready++
+	}
+	return res, nil
+}
+
+// nextStandbyPolecat readies a polecat for a new standby session: an idle
+// polecat with a clean worktree if there is one, otherwise a new polecat.
+func nextStandbyPolecat(mgr *polecat.Manager) (string, error) {
+	polecats, err := mgr.List()
+	if err != nil {
+		return "", fmt.Errorf("listing polecats: %w", err)
+	}
+	for _, p := range polecats {
+		if p.State == polecat.StateIdle && mgr.PrepareStandby(p.Name) == nil {
+			return p.Name, nil
+		}
+	}
+
+	name, err := mgr.AllocateName()
+	if err != nil {
+		return "", fmt.Errorf("allocating polecat name: %w", err)
+	}
+	if _, err := mgr.AddWithOptions(name, polecat.AddOptions{}); err != nil {
+		return "", fmt.Errorf("creating polecat %s: %w", name, err)
+	}
+	if err := mgr.PrepareStandby(name); err != nil {
+		return "", fmt.Errorf("preparing %s for standby: %w", name, err)
+	}
+	return name, nil
+}
@@ -211,6 +211,8 @@ func runPrimeCompactResume(ctx RoleContext, cwd string) {
 		attachment := beads.ParseAttachmentFields(hookedBead)
 		hasMolecule := attachment != nil && attachment.AttachedMolecule != ""
 		outputContinuationDirective(hookedBead, hasMolecule)
+		outputRunContext(ctx, hookedBead.ID)
+		outputIssueNotes(ctx, hookedBead.ID)
 	}
 
 	// Molecule progress if available
@@ -429,6 +431,8 @@ func checkSlungWork(ctx RoleContext) bool {
 
 	outputAutonomousDirective(ctx, hookedBead, hasMolecule)
 	outputHookedBeadDetails(hookedBead)
+	outputRunContext(ctx, hookedBead.ID)
+	outputIssueNotes(ctx, hookedBead.ID)
 
 	if hasMolecule {
 		outputMoleculeWorkflow(ctx, attachment)
@@ -566,6 +570,9 @@ func outputAutonomousDirective(ctx RoleContext, hookedBead *beads.Issue, hasMole
 		fmt.Println("3. Begin execution - no waiting for user input")
 	}
 	fmt.Println()
+	fmt.Printf("Record progress as you go with `%s notes append %s \"...\"`. Your witness\n", cli.Name(), hookedBead.ID)
+	fmt.Println("watches these notes, and they are replayed to you if your session resumes.")
+	fmt.Println()
 	fmt.Println("**DO NOT:**")
 	fmt.Println("- Wait for user response after announcing")
 	fmt.Println("- Ask clarifying questions")
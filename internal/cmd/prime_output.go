@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/notes"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/runcontext"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/templates"
@@ -549,6 +552,51 @@ func outputContinuationDirective(hookedBead *beads.Issue, hasMolecule bool) {
 	fmt.Println()
 }
 
+// primeNotesTail is how many recent notes entries gt prime replays.
+const primeNotesTail = 5
+
+// outputIssueNotes replays the most recent progress notes for the hooked
+// issue so a resumed session picks up where its predecessor left off.
+func outputIssueNotes(ctx RoleContext, issueID string) {
+	if ctx.TownRoot == "" {
+		return
+	}
+	entries, err := notes.Read(ctx.TownRoot, issueID)
+	if err != nil || len(entries) == 0 {
+		return
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render("## 📝 Progress Notes"))
+	for _, e := range notes.Tail(entries, primeNotesTail) {
+		header := e.Time.Format("2006-01-02 15:04")
+		if e.Author != "" {
+			header += " " + e.Author
+		}
+		fmt.Printf("  [%s]\n", header)
+		for _, line := range strings.Split(e.Text, "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	if len(entries) > primeNotesTail {
+		fmt.Printf("  (%d earlier entries: `%s notes show %s`)\n", len(entries)-primeNotesTail, cli.Name(), issueID)
+	}
+	fmt.Println()
+}
+
+// outputRunContext points a polecat at the run-context file written when its
+// hooked issue was dispatched. A file left over from other work is ignored.
+func outputRunContext(ctx RoleContext, issueID string) {
+	if ctx.Role != RolePolecat {
+		return
+	}
+	rc, err := runcontext.Read(ctx.WorkDir)
+	if err != nil || rc == nil || rc.Issue == nil || rc.Issue.ID != issueID {
+		return
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render("## 🧭 Dispatch Context"))
+	fmt.Printf("Issue details, constraints, budgets and commands for %s are in\n", issueID)
+	fmt.Printf("`%s`. Re-read it whenever you lose track of the task.\n\n", runcontext.RelMarkdownPath())
+}
+
 // outputHandoffWarning outputs the post-handoff warning message.
 func outputHandoffWarning(prevSession string) {
 	fmt.Println()
@@ -307,6 +307,12 @@ func printScanText(results []quota.ScanResult) error {
 				account,
 				resets,
 			)
+		} else if r.Retrying {
+			fmt.Printf(" %s %-25s %s\n",
+				style.Warning.Render("~"),
+				r.Session,
+				style.Dim.Render("retrying: "+r.RetryLine),
+			)
 		}
 	}
 
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var quotaPressureCmd = &cobra.Command{
+	Use:   "pressure",
+	Short: "Show rate-limit pressure per provider and dispatch throttling",
+	Long: `Show the latest rate-limit pressure per agent provider, as measured by
+the daemon's provider_pressure patrol, and how dispatch is throttled.
+
+A provider is slowed (one dispatch per scheduler cycle, staggered spawns and
+broadcast nudges) when any of its sessions is rate-limited or retrying 429s,
+and paused (no new dispatches) when at least scheduler.throttle.pause_ratio
+of its sessions are rate-limited.
+
+Configure in settings/config.json:
+  "scheduler": {"throttle": {"pause_ratio": 0.5, "stagger": "30s", "max_age": "10m"}}
+
+Examples:
+  gt quota pressure
+  gt quota pressure --json`,
+	Args: cobra.NoArgs,
+	RunE: runQuotaPressure,
+}
+
+func init() {
+	quotaPressureCmd.Flags().BoolVar(&quotaJSON, "json", false, "Output as JSON")
+	quotaCmd.AddCommand(quotaPressureCmd)
+}
+
+// quotaPressureEntry is a provider's pressure with its throttle level.
+type quotaPressureEntry struct {
+	capacity.ProviderPressure
+	Level string `json:"level"`
+}
+
+func runQuotaPressure(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := capacity.LoadPressure(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading provider pressure: %w", err)
+	}
+	var cfg *capacity.ThrottleConfig
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && settings.Scheduler != nil {
+		cfg = settings.Scheduler.Throttle
+	}
+
+	now := time.Now()
+	entries := make([]quotaPressureEntry, 0, len(state.Providers))
+	for _, p := range state.Providers {
+		entries = append(entries, quotaPressureEntry{ProviderPressure: p, Level: cfg.Level(p, now).String()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Provider < entries[j].Provider })
+
+	if quotaJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("%s No provider pressure recorded (is the daemon running?)\n", style.Dim.Render("○"))
+		return nil
+	}
+	if cfg != nil && cfg.Disabled {
+		fmt.Printf("%s Throttling disabled (scheduler.throttle.disabled)\n", style.Dim.Render("○"))
+	}
+	if age := now.Sub(state.UpdatedAt); age > cfg.GetMaxAge() {
+		fmt.Printf("%s Reading is %s old; ignored until the daemon measures again\n",
+			style.WarningPrefix, age.Round(time.Second))
+	}
+	for _, e := range entries {
+		icon := style.SuccessPrefix
+		switch e.Level {
+		case capacity.ThrottleSlow.String():
+			icon = style.Warning.Render("◐")
+		case capacity.ThrottlePause.String():
+			icon = style.Error.Render("⏸")
+		}
+		fmt.Printf(" %s %-12s %-6s %d session(s), %d limited, %d retrying\n",
+			icon, e.Provider, e.Level, e.Sessions, e.Limited, e.Retrying)
+		if len(e.LimitedSessions) > 0 {
+			fmt.Printf("     %s %s\n", style.Dim.Render("limited:"), strings.Join(e.LimitedSessions, ", "))
+		}
+		if len(e.RetryingSessions) > 0 {
+			fmt.Printf("     %s %s\n", style.Dim.Render("retrying:"), strings.Join(e.RetryingSessions, ", "))
+		}
+		if e.ResetsAt != "" {
+			fmt.Printf("     %s %s\n", style.Dim.Render("resets:"), e.ResetsAt)
+		}
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("Measured "+state.UpdatedAt.Format("15:04:05")))
+	return nil
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/recording"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+var (
+	replaySpeed   float64
+	replayMaxIdle time.Duration
+	replayList    bool
+	replayPath    bool
+)
+
+var replayCmd = &cobra.Command{
+	Use:     "replay [<issue>|<session>|<file.cast>]",
+	GroupID: GroupDiag,
+	Short:   "Play back a recorded agent session",
+	Long: `Play back a session recorded with 'gt session record' (or by a rig with
+recording enabled) in this terminal, with its original timing.
+
+The argument may be:
+  - an issue ID: plays the recording of the session of the issue's assignee,
+    made before the issue was closed
+  - a session name: plays that session's latest recording
+  - a path to a .cast file (or a .cast.gz, as finished recordings are stored)
+
+Recordings are asciicast v2 files in <town>/.runtime/recordings/, so they
+also play in asciinema ('asciinema play <file>', after gunzip for finished
+ones) or upload for sharing.
+
+Examples:
+  gt replay gt-abc12                # Watch how gt-abc12 was worked
+  gt replay gt-abc12 --speed 4      # 4x speed
+  gt replay gt-Toast --max-idle 1s  # Cap pauses at 1s
+  gt replay --list                  # List recordings
+  gt replay gt-abc12 --path         # Print the file (for asciinema)`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if replayList {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().Float64Var(&replaySpeed, "speed", 1, "Playback speed multiplier")
+	replayCmd.Flags().DurationVar(&replayMaxIdle, "max-idle", 2*time.Second, "Cap pauses between output at this long (0 = no cap)")
+	replayCmd.Flags().BoolVar(&replayList, "list", false, "List recordings instead of playing")
+	replayCmd.Flags().BoolVar(&replayPath, "path", false, "Print the recording's path instead of playing")
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	recs, err := recording.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing recordings: %w", err)
+	}
+	if replayList {
+		return printRecordings(recs)
+	}
+
+	path, err := findReplayRecording(args[0], recs)
+	if err != nil {
+		return err
+	}
+	if replayPath {
+		fmt.Println(path)
+		return nil
+	}
+
+	h, events, err := recording.Load(path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%s Replaying %s (%dx%d, recorded %s). Ctrl-C to stop.\n",
+		style.Dim.Render("▶"), path, h.Width, h.Height, h.Start().Format("2006-01-02 15:04"))
+	if cols, rows, err := term.GetSize(int(os.Stdout.Fd())); err == nil && (cols < h.Width || rows < h.Height) {
+		fmt.Fprintf(os.Stderr, "%s Terminal is %dx%d; the recording may render poorly.\n", style.WarningPrefix, cols, rows)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err = recording.Play(ctx, os.Stdout, events, recording.PlayOptions{Speed: replaySpeed, MaxIdle: replayMaxIdle})
+	fmt.Print("\x1b[0m\n")
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// findReplayRecording resolves a replay argument to a recording path.
+func findReplayRecording(arg string, recs []recording.Info) (string, error) {
+	if recording.IsRecording(arg) {
+		if _, err := os.Stat(arg); err != nil {
+			return "", err
+		}
+		return arg, nil
+	}
+	if rec := pickRecording(recs, arg, time.Time{}); rec != nil {
+		return rec.Path, nil
+	}
+
+	issue, err := beads.New(resolveBeadDir(arg)).Show(arg)
+	if err != nil {
+		return "", fmt.Errorf("no recording for %q: not a recorded session, and not an issue: %w", arg, err)
+	}
+	if issue.Assignee == "" {
+		return "", fmt.Errorf("issue %s has no assignee, so no session to replay", arg)
+	}
+	id, err := session.ParseAddress(issue.Assignee)
+	if err != nil {
+		return "", fmt.Errorf("issue %s: assignee %q has no session: %w", arg, issue.Assignee, err)
+	}
+	sessionName := id.SessionName()
+	var before time.Time
+	if issue.ClosedAt != "" {
+		before, _ = time.Parse(time.RFC3339, issue.ClosedAt)
+	}
+	rec := pickRecording(recs, sessionName, before)
+	if rec == nil {
+		return "", fmt.Errorf("no recording of %s (%s) for issue %s; enable recording with 'gt session record' or the rig's recording setting",
+			issue.Assignee, sessionName, arg)
+	}
+	return rec.Path, nil
+}
+
+// pickRecording returns the newest recording of sessionName that started
+// before before (any time, if zero). recs must be newest first.
+func pickRecording(recs []recording.Info, sessionName string, before time.Time) *recording.Info {
+	for i := range recs {
+		if recs[i].Header.Session() != sessionName {
+			continue
+		}
+		if !before.IsZero() && recs[i].Header.Start().After(before) {
+			continue
+		}
+		return &recs[i]
+	}
+	return nil
+}
+
+func printRecordings(recs []recording.Info) error {
+	if len(recs) == 0 {
+		fmt.Printf("%s No recordings\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, r := range recs {
+		fmt.Printf("%s  %-24s %8s  %s\n", r.Header.Start().Format("2006-01-02 15:04"),
+			r.Header.Session(), formatRecordingSize(r.Size), style.Dim.Render(r.Path))
+	}
+	return nil
+}
+
+func formatRecordingSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fM", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fK", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%dB", n)
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/recording"
+)
+
+func TestPickRecording(t *testing.T) {
+	rec := func(path, session string, start int64) recording.Info {
+		return recording.Info{Path: path, Header: recording.Header{
+			Timestamp: start,
+			Env:       map[string]string{"GT_SESSION": session},
+		}}
+	}
+	// Newest first, as recording.List returns them.
+	recs := []recording.Info{
+		rec("c", "gt-Toast", 3000),
+		rec("b", "gt-Nux", 2000),
+		rec("a", "gt-Toast", 1000),
+	}
+
+	if got := pickRecording(recs, "gt-Toast", time.Time{}); got == nil || got.Path != "c" {
+		t.Errorf("latest gt-Toast = %v, want c", got)
+	}
+	if got := pickRecording(recs, "gt-Toast", time.Unix(2500, 0)); got == nil || got.Path != "a" {
+		t.Errorf("gt-Toast before 2500 = %v, want a", got)
+	}
+	if got := pickRecording(recs, "gt-Toast", time.Unix(500, 0)); got != nil {
+		t.Errorf("gt-Toast before 500 = %v, want none", got)
+	}
+	if got := pickRecording(recs, "gt-Slit", time.Time{}); got != nil {
+		t.Errorf("gt-Slit = %v, want none", got)
+	}
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/metrics"
+	"github.com/steveyegge/gastown/internal/report"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	reportWeek   bool
+	reportDays   int
+	reportSince  string
+	reportFormat string
+	reportOutput string
+)
+
+var reportCmd = &cobra.Command{
+	Use:     "report",
+	GroupID: GroupDiag,
+	Short:   "Generate an activity report for a period",
+	Long: `Summarize town activity over a period for a team update.
+
+The report covers, per rig and in total:
+  - Merge throughput: MRs merged and failed (from the events log)
+  - Issues closed and their cycle time, created to closed (from beads)
+  - Cost (from the cost ledger and daily cost digests)
+  - Slings, polecat spawns, completions, escalations, session deaths
+  - Trends in sessions alive, merge queue depth and patrol durations
+    (from the metrics history the daemon records)
+
+Markdown is printed by default, ready to paste. --format html writes a
+self-contained page with inline SVG charts of merges and cost per day.
+
+The period ends now. --week (the default) covers the last 7 days, counted
+from midnight.
+
+Examples:
+  gt report --week
+  gt report --days 14
+  gt report --since 2026-01-05
+  gt report --week --format html -o week.html
+  gt report --week --format json`,
+	Args: cobra.NoArgs,
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().BoolVar(&reportWeek, "week", false, "Report on the last 7 days (default)")
+	reportCmd.Flags().IntVar(&reportDays, "days", 0, "Report on the last N days")
+	reportCmd.Flags().StringVar(&reportSince, "since", "", "Report from a date (YYYY-MM-DD) until now")
+	reportCmd.Flags().StringVar(&reportFormat, "format", "markdown", "Output format: markdown, html, or json")
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	period, err := reportPeriod(time.Now())
+	if err != nil {
+		return err
+	}
+	switch reportFormat {
+	case "markdown", "md", "html", "json":
+	default:
+		return fmt.Errorf("invalid --format %q: must be markdown, html, or json", reportFormat)
+	}
+
+	rigs := discoverRigs(townRoot)
+	evts, err := readReportEvents(townRoot, period)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s reading events: %v\n", style.WarningPrefix, err)
+	}
+	rpt := report.Build(report.Input{
+		Period:  period,
+		Rigs:    rigs,
+		Events:  evts,
+		Costs:   reportCosts(period),
+		Closed:  reportClosedIssues(townRoot, rigs, period),
+		Metrics: reportMetrics(townRoot, period),
+	})
+
+	var out string
+	switch reportFormat {
+	case "html":
+		out = rpt.HTML()
+	case "json":
+		data, err := json.MarshalIndent(rpt, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding report: %w", err)
+		}
+		out = string(data) + "\n"
+	default:
+		out = rpt.Markdown()
+	}
+
+	if reportOutput == "" {
+		fmt.Print(out)
+		return nil
+	}
+	if err := os.WriteFile(reportOutput, []byte(out), 0644); err != nil { //nolint:gosec // G306: report is not secret
+		return fmt.Errorf("writing report: %w", err)
+	}
+	fmt.Printf("%s Wrote report to %s\n", style.SuccessPrefix, reportOutput)
+	return nil
+}
+
+// reportPeriod returns the reporting window ending at now, from the
+// --week/--days/--since flags. Day counts start at local midnight so
+// every day in the report is whole except today.
+func reportPeriod(now time.Time) (report.Period, error) {
+	set := 0
+	for _, on := range []bool{reportWeek, reportDays > 0, reportSince != ""} {
+		if on {
+			set++
+		}
+	}
+	if set > 1 {
+		return report.Period{}, fmt.Errorf("--week, --days, and --since are mutually exclusive")
+	}
+
+	if reportSince != "" {
+		start, err := time.ParseInLocation("2006-01-02", reportSince, now.Location())
+		if err != nil {
+			return report.Period{}, fmt.Errorf("invalid --since %q: want YYYY-MM-DD", reportSince)
+		}
+		if !start.Before(now) {
+			return report.Period{}, fmt.Errorf("--since %s is in the future", reportSince)
+		}
+		return report.Period{Start: start, End: now}, nil
+	}
+
+	days := 7
+	if reportDays > 0 {
+		days = reportDays
+	}
+	y, m, d := now.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, now.Location()).AddDate(0, 0, -(days - 1))
+	return report.Period{Start: start, End: now}, nil
+}
+
+// readReportEvents reads the town events log, keeping events in period.
+func readReportEvents(townRoot string, period report.Period) ([]events.Event, error) {
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []events.Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if ts, err := time.Parse(time.RFC3339, e.Timestamp); err == nil && period.Contains(ts) {
+			out = append(out, e)
+		}
+	}
+	return out, scanner.Err()
+}
+
+// reportCosts collects cost samples for the period: daily digests for
+// days already digested, and the raw ledger for days that are not.
+func reportCosts(period report.Period) []report.CostSample {
+	days := period.Days()
+	entries, err := queryDigestBeads(days)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s reading cost digests: %v\n", style.WarningPrefix, err)
+	}
+	for d := period.Start; d.Before(period.End); d = d.AddDate(0, 0, 1) {
+		dayEntries, err := querySessionCostEntries(d)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s reading cost ledger: %v\n", style.WarningPrefix, err)
+			break
+		}
+		entries = append(entries, dayEntries...)
+	}
+
+	samples := make([]report.CostSample, 0, len(entries))
+	for _, e := range entries {
+		samples = append(samples, report.CostSample{Rig: e.Rig, USD: e.CostUSD, At: e.EndedAt})
+	}
+	return samples
+}
+
+// reportMetrics reads the period's samples from the metrics history.
+func reportMetrics(townRoot string, period report.Period) []metrics.Sample {
+	samples, err := metrics.Open(townRoot).Range(period.Start, period.End)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s reading metrics history: %v\n", style.WarningPrefix, err)
+	}
+	return samples
+}
+
+// reportClosedIssues collects work items closed during the period from
+// every rig and the town HQ.
+func reportClosedIssues(townRoot string, rigs []string, period report.Period) []report.ClosedIssue {
+	var out []report.ClosedIssue
+	for _, rigName := range append([]string{""}, rigs...) { // "" is the town-level (HQ) database
+		issues, err := beads.New(filepath.Join(townRoot, rigName)).List(beads.ListOptions{Status: "closed", Priority: -1})
+		if err != nil {
+			continue
+		}
+		for _, issue := range issues {
+			if !beads.IsWorkItem(issue) {
+				continue
+			}
+			closed, err := time.Parse(time.RFC3339, issue.ClosedAt)
+			if err != nil || !period.Contains(closed) {
+				continue
+			}
+			created, _ := time.Parse(time.RFC3339, issue.CreatedAt)
+			out = append(out, report.ClosedIssue{Rig: rigName, ID: issue.ID, Created: created, Closed: closed})
+		}
+	}
+	return out
+}
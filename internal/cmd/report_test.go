@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportPeriod(t *testing.T) {
+	now := time.Date(2026, 3, 8, 15, 30, 0, 0, time.UTC)
+	defer func() { reportWeek, reportDays, reportSince = false, 0, "" }()
+
+	reportWeek, reportDays, reportSince = false, 0, ""
+	p, err := reportPeriod(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC); !p.Start.Equal(want) || !p.End.Equal(now) {
+		t.Errorf("default period = %v..%v, want %v..%v", p.Start, p.End, want, now)
+	}
+
+	reportDays = 1
+	if p, _ = reportPeriod(now); !p.Start.Equal(time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("--days 1 start = %v, want today's midnight", p.Start)
+	}
+
+	reportDays, reportSince = 0, "2026-03-01"
+	if p, _ = reportPeriod(now); !p.Start.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("--since start = %v", p.Start)
+	}
+
+	reportWeek = true
+	if _, err := reportPeriod(now); err == nil {
+		t.Error("expected error for --week with --since")
+	}
+}
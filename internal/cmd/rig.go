@@ -72,9 +72,27 @@ Use --adopt to register an existing directory instead of creating new:
   - Auto-detects git URL from origin remote (git-url argument not required)
   - Adds entry to mayor/rigs.json
 
+For giant repositories, --partial-clone blobless (or treeless) clones the
+shared repo without file contents, fetching them as worktrees need them.
+Set partial_clone.sparse in <rig>/settings/config.json to a list of
+directories to limit polecat worktrees to those directories.
+
+Use --from-template to standardize rig setup from a template repository
+(a git URL or local directory). Any of these entries are applied:
+  - settings/config.json  Merged into <rig>/settings/config.json
+  - merge_queue.json      Merge queue section of <rig>/config.json (gates)
+  - roles/*.toml          Role overrides (prompt packs)
+  - scripts/              CI gate scripts, copied to <rig>/scripts/
+  - plugins/              Rig-level plugins
+  - hooks/<role>.json     Hooks overrides for <rig>/<role>
+{{rig}}, {{prefix}}, {{rig_path}} and {{town}} in template files are
+replaced with the new rig's values.
+
 Example:
   gt rig add gastown https://github.com/steveyegge/gastown
   gt rig add my-project git@github.com:user/repo.git --prefix mp
+  gt rig add monorepo git@github.com:org/mono.git --partial-clone blobless
+  gt rig add api git@github.com:org/api.git --from-template git@github.com:org/rig-template.git
   gt rig add existing-rig --adopt`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runRigAdd,
@@ -99,22 +117,28 @@ Examples:
 
 var rigRemoveCmd = &cobra.Command{
 	Use:   "remove <name>",
-	Short: "Remove a rig from the registry (does not delete files)",
-	Long: `Remove a rig from the Gas Town registry.
+	Short: "Remove a rig and tear down its sessions, work, and registrations",
+	Long: `Remove a rig from Gas Town.
 
-This only removes the rig entry from mayor/rigs.json and cleans up
-the beads route. The rig's files on disk are NOT deleted.
+Teardown steps:
+  - Kill the rig's tmux sessions (witness, refinery, polecats, crew; requires --force)
+  - Release in-progress issues held by the rig's agents (back to open, unassigned)
+  - Close the rig's open merge requests
+  - Remove the rig from mayor/rigs.json, daemon patrols, and backup/remote
+    patrol database lists, and drop its beads route
+  - Archive the final state to .archive/rigs/<name>-<timestamp>.json
 
-If the rig has running tmux sessions (witness, refinery, polecats, crew),
-you must shut them down first with 'gt rig shutdown' or use --force to
-kill them automatically.
+The rig's files on disk are kept unless --purge is given, which also removes
+polecat worktrees and deletes the rig directory. --delete-branches deletes
+the polecats' pushed branches from origin.
 
-To fully remove a rig, delete the directory manually after unregistering.
+Use --dry-run to list everything that would be touched.
 
 Examples:
+  gt rig remove myproject --dry-run          # Show the teardown plan
   gt rig remove myproject                    # Unregister (fails if sessions running)
   gt rig remove myproject --force            # Kill sessions then unregister
-  gt rig remove myproject && rm -rf myproject # Unregister and delete files`,
+  gt rig remove myproject --force --purge    # Also delete worktrees and files`,
 	Args: cobra.ExactArgs(1),
 	RunE: runRigRemove,
 }
@@ -295,10 +319,12 @@ var (
 	rigAddPrefix       string
 	rigAddLocalRepo    string
 	rigAddBranch       string
+	rigAddPartialClone string
 	rigAddPushURL      string
 	rigAddAdopt        bool
 	rigAddAdoptURL     string
 	rigAddAdoptForce   bool
+	rigAddFromTemplate string
 	rigResetHandoff    bool
 	rigResetMail       bool
 	rigResetStale      bool
@@ -314,6 +340,9 @@ var (
 	rigRestartNuclear  bool
 	rigListJSON        bool
 	rigRemoveForce     bool
+	rigRemoveDryRun    bool
+	rigRemovePurge     bool
+	rigRemoveBranches  bool
 )
 
 var (
@@ -350,14 +379,19 @@ func init() {
 	rigListCmd.Flags().BoolVar(&rigListJSON, "json", false, "Output as JSON")
 
 	rigRemoveCmd.Flags().BoolVarP(&rigRemoveForce, "force", "f", false, "Kill running tmux sessions before removing (may lose uncommitted work)")
+	rigRemoveCmd.Flags().BoolVar(&rigRemoveDryRun, "dry-run", false, "List everything that would be touched without changing anything")
+	rigRemoveCmd.Flags().BoolVar(&rigRemovePurge, "purge", false, "Remove polecat worktrees and delete the rig directory")
+	rigRemoveCmd.Flags().BoolVar(&rigRemoveBranches, "delete-branches", false, "Delete polecat branches from origin")
 
 	rigAddCmd.Flags().StringVar(&rigAddPrefix, "prefix", "", "Beads issue prefix (default: derived from name)")
 	rigAddCmd.Flags().StringVar(&rigAddLocalRepo, "local-repo", "", "Local repo path to share git objects (optional)")
 	rigAddCmd.Flags().StringVar(&rigAddBranch, "branch", "", "Default branch name (default: auto-detected from remote)")
+	rigAddCmd.Flags().StringVar(&rigAddPartialClone, "partial-clone", "", "Clone the shared repo partially for giant repos: blobless or treeless")
 	rigAddCmd.Flags().StringVar(&rigAddPushURL, "push-url", "", "Push URL for read-only upstreams (push to fork)")
 	rigAddCmd.Flags().BoolVar(&rigAddAdopt, "adopt", false, "Adopt an existing directory instead of creating new")
 	rigAddCmd.Flags().StringVar(&rigAddAdoptURL, "url", "", "Git remote URL for --adopt (default: auto-detected from origin)")
 	rigAddCmd.Flags().BoolVar(&rigAddAdoptForce, "force", false, "With --adopt, register even if git remote cannot be detected")
+	rigAddCmd.Flags().StringVar(&rigAddFromTemplate, "from-template", "", "Apply a rig template (git URL or directory) to the new rig")
 
 	rigResetCmd.Flags().BoolVar(&rigResetHandoff, "handoff", false, "Clear handoff content")
 	rigResetCmd.Flags().BoolVar(&rigResetMail, "mail", false, "Clear stale mail messages")
@@ -470,6 +504,9 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 
 	// Handle --adopt mode: register existing directory
 	if rigAddAdopt {
+		if rigAddFromTemplate != "" {
+			return fmt.Errorf("--from-template cannot be used with --adopt")
+		}
 		return runRigAdopt(cmd, args)
 	}
 
@@ -521,6 +558,19 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid push URL %q: expected a remote URL (https://, git@, ssh://, git://)", rigAddPushURL)
 	}
 
+	// Fetch the template before creating anything so a bad template
+	// doesn't leave a half-configured rig behind.
+	var tmpl *rig.Template
+	if rigAddFromTemplate != "" {
+		fmt.Printf("  Template:   %s\n", rigAddFromTemplate)
+		var cleanup func()
+		tmpl, cleanup, err = fetchRigTemplate(rigAddFromTemplate)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+	}
+
 	startTime := time.Now()
 
 	// Add the rig
@@ -531,6 +581,7 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		BeadsPrefix:   rigAddPrefix,
 		LocalRepo:     rigAddLocalRepo,
 		DefaultBranch: rigAddBranch,
+		PartialClone:  rigAddPartialClone,
 	})
 	if err != nil {
 		return fmt.Errorf("adding rig: %w", err)
@@ -576,6 +627,19 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Apply the template before syncing hooks so its overrides take effect
+	if tmpl != nil {
+		vars := rig.TemplateVars{
+			Rig:      name,
+			Prefix:   newRig.Config.Prefix,
+			RigPath:  filepath.Join(townRoot, name),
+			TownRoot: townRoot,
+		}
+		if err := applyRigTemplate(tmpl, vars); err != nil {
+			return fmt.Errorf("rig %s was added, but applying the template failed: %w", name, err)
+		}
+	}
+
 	// Sync hooks for the new rig's targets
 	if err := syncRigHooks(townRoot, name); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to sync hooks for new rig: %v\n", err)
@@ -799,9 +863,18 @@ func runRigRemove(cmd *cobra.Command, args []string) error {
 	// Create rig manager
 	g := git.NewGit(townRoot)
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
+	if !mgr.RigExists(name) {
+		return fmt.Errorf("removing rig: %w", rig.ErrRigNotFound)
+	}
 
-	// Check for running tmux sessions before removing
 	t := tmux.NewTmux()
+	plan := buildRigTeardownPlan(townRoot, name, rigsConfig, t)
+	if rigRemoveDryRun {
+		printRigTeardownPlan(plan, rigRemovePurge, rigRemoveBranches)
+		return nil
+	}
+
+	// Check for running tmux sessions before removing
 	sessions, sessErr := findRigSessions(t, name)
 	if sessErr != nil {
 		if !rigRemoveForce {
@@ -840,6 +913,15 @@ func runRigRemove(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	releaseRigWork(plan)
+	if rigRemovePurge {
+		removeRigWorktrees(townRoot, rigsConfig, plan, rigRemoveBranches, t)
+	}
+	archivePath, err := archiveRigState(townRoot, plan)
+	if err != nil {
+		fmt.Printf("  %s %v\n", style.Warning.Render("!"), err)
+	}
+
 	if err := mgr.RemoveRig(name); err != nil {
 		return fmt.Errorf("removing rig: %w", err)
 	}
@@ -854,6 +936,9 @@ func runRigRemove(cmd *cobra.Command, args []string) error {
 		// Non-fatal: daemon will stop spawning for this rig anyway since it's unregistered
 		fmt.Printf("  %s Could not update daemon.json patrols: %v\n", style.Warning.Render("!"), err)
 	}
+	if err := config.RemoveDatabaseFromDaemonPatrols(townRoot, name); err != nil {
+		fmt.Printf("  %s Could not update daemon.json backup patrols: %v\n", style.Warning.Render("!"), err)
+	}
 
 	// Remove route from routes.jsonl (issue #899)
 	if beadsPrefix != "" {
@@ -864,6 +949,17 @@ func runRigRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s Rig %s removed from registry\n", style.Success.Render("✓"), name)
+	if archivePath != "" {
+		fmt.Printf("  Final state archived to %s\n", archivePath)
+	}
+
+	if rigRemovePurge {
+		if err := os.RemoveAll(plan.RigPath); err != nil {
+			return fmt.Errorf("deleting rig directory: %w", err)
+		}
+		fmt.Printf("  Deleted %s\n", plan.RigPath)
+		return nil
+	}
 	fmt.Printf("\nNote: Files at %s were NOT deleted.\n", filepath.Join(townRoot, name))
 	fmt.Printf("To delete: %s\n", style.Dim.Render(fmt.Sprintf("rm -rf %s", filepath.Join(townRoot, name))))
 
@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/platform"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -185,7 +186,7 @@ func sanitizeRigName(name string) string {
 
 func findOrCreateTown() (string, error) {
 	// Priority 1: GT_TOWN_ROOT env var (explicit user preference)
-	if townRoot := os.Getenv("GT_TOWN_ROOT"); townRoot != "" {
+	if townRoot := platform.LocalPath(os.Getenv("GT_TOWN_ROOT")); townRoot != "" {
 		if isValidTown(townRoot) {
 			return townRoot, nil
 		}
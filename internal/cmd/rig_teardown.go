@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// rigTeardownPlan lists everything a rig removal touches.
+type rigTeardownPlan struct {
+	Rig           string             `json:"rig"`
+	RigPath       string             `json:"rig_path"`
+	Entry         *config.RigEntry   `json:"entry,omitempty"`
+	Sessions      []string           `json:"sessions"`
+	Polecats      []rigTeardownWork  `json:"polecats"`
+	ReleaseIssues []rigTeardownIssue `json:"release_issues"`
+	CloseMRs      []rigTeardownIssue `json:"close_merge_requests"`
+	RemovedAt     time.Time          `json:"removed_at"`
+}
+
+// rigTeardownWork is a polecat worktree affected by removal.
+type rigTeardownWork struct {
+	Name      string `json:"name"`
+	Branch    string `json:"branch,omitempty"`
+	ClonePath string `json:"clone_path"`
+}
+
+// rigTeardownIssue is an issue that removal reassigns or closes.
+type rigTeardownIssue struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Assignee string `json:"assignee,omitempty"`
+}
+
+// buildRigTeardownPlan inspects a rig's sessions, polecats, and beads.
+// Discovery failures are reported as warnings; the plan covers what could
+// be found.
+func buildRigTeardownPlan(townRoot, name string, rigsConfig *config.RigsConfig, t *tmux.Tmux) *rigTeardownPlan {
+	plan := &rigTeardownPlan{Rig: name, RigPath: filepath.Join(townRoot, name)}
+	if entry, ok := rigsConfig.Rigs[name]; ok {
+		plan.Entry = &entry
+	}
+
+	plan.Sessions, _ = findRigSessions(t, name)
+
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	if r, err := mgr.GetRig(name); err == nil {
+		pm := polecat.NewManager(r, git.NewGit(r.Path), t)
+		if polecats, err := pm.List(); err == nil {
+			for _, p := range polecats {
+				plan.Polecats = append(plan.Polecats, rigTeardownWork{Name: p.Name, Branch: p.Branch, ClonePath: p.ClonePath})
+			}
+		}
+	}
+
+	issues, err := beads.New(plan.RigPath).List(beads.ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		fmt.Printf("  %s Could not list rig issues: %v\n", style.Warning.Render("!"), err)
+		return plan
+	}
+	for _, issue := range issues {
+		if issue.Status == "closed" {
+			continue
+		}
+		item := rigTeardownIssue{ID: issue.ID, Title: issue.Title, Status: issue.Status, Assignee: issue.Assignee}
+		switch {
+		case beads.HasLabel(issue, "gt:merge-request"):
+			plan.CloseMRs = append(plan.CloseMRs, item)
+		case strings.HasPrefix(issue.Assignee, name+"/"):
+			plan.ReleaseIssues = append(plan.ReleaseIssues, item)
+		}
+	}
+	return plan
+}
+
+// printRigTeardownPlan prints the plan for --dry-run.
+func printRigTeardownPlan(plan *rigTeardownPlan, purge, deleteBranches bool) {
+	fmt.Printf("%s Would remove rig %s\n\n", style.Bold.Render("🔍"), style.Bold.Render(plan.Rig))
+
+	section := func(title string, items []string) {
+		fmt.Printf("%s (%d)\n", title, len(items))
+		for _, item := range items {
+			fmt.Printf("  - %s\n", item)
+		}
+		if len(items) == 0 {
+			fmt.Printf("  %s\n", style.Dim.Render("none"))
+		}
+	}
+
+	section("Sessions to kill", plan.Sessions)
+
+	var issues []string
+	for _, i := range plan.ReleaseIssues {
+		issues = append(issues, fmt.Sprintf("%s %s (%s, assigned %s) → open, unassigned", i.ID, i.Title, i.Status, i.Assignee))
+	}
+	section("Issues to release", issues)
+
+	var mrs []string
+	for _, i := range plan.CloseMRs {
+		mrs = append(mrs, fmt.Sprintf("%s %s → closed", i.ID, i.Title))
+	}
+	section("Merge requests to close", mrs)
+
+	var worktrees, branches []string
+	for _, p := range plan.Polecats {
+		worktrees = append(worktrees, fmt.Sprintf("%s (%s)", p.ClonePath, p.Name))
+		if p.Branch != "" {
+			branches = append(branches, "origin/"+p.Branch)
+		}
+	}
+	if purge {
+		section("Worktrees to remove", worktrees)
+	}
+	if deleteBranches {
+		section("Remote branches to delete", branches)
+	}
+
+	fmt.Println("Registry and config")
+	fmt.Printf("  - mayor/rigs.json entry %q\n", plan.Rig)
+	fmt.Printf("  - witness/refinery patrol entries in mayor/daemon.json\n")
+	fmt.Printf("  - backup/remote patrol entries for database %q\n", plan.Rig)
+	fmt.Printf("  - beads route\n")
+	fmt.Printf("  - archive final state to %s\n", rigArchiveDir(filepath.Dir(plan.RigPath)))
+	if purge {
+		fmt.Printf("  - delete %s\n", plan.RigPath)
+	}
+}
+
+// releaseRigWork reopens issues held by the rig's agents and closes the
+// rig's open merge requests so nothing is left pointing at a dead rig.
+func releaseRigWork(plan *rigTeardownPlan) {
+	b := beads.New(plan.RigPath)
+	open, unassigned := "open", ""
+	for _, i := range plan.ReleaseIssues {
+		if err := b.Update(i.ID, beads.UpdateOptions{Status: &open, Assignee: &unassigned}); err != nil {
+			fmt.Printf("  %s Could not release %s: %v\n", style.Warning.Render("!"), i.ID, err)
+			continue
+		}
+		fmt.Printf("  Released %s\n", i.ID)
+	}
+	for _, i := range plan.CloseMRs {
+		if err := b.CloseWithReason("rig "+plan.Rig+" removed", i.ID); err != nil {
+			fmt.Printf("  %s Could not close MR %s: %v\n", style.Warning.Render("!"), i.ID, err)
+			continue
+		}
+		fmt.Printf("  Closed MR %s\n", i.ID)
+	}
+}
+
+// removeRigWorktrees removes polecat worktrees and, optionally, their
+// pushed branches on origin.
+func removeRigWorktrees(townRoot string, rigsConfig *config.RigsConfig, plan *rigTeardownPlan, deleteBranches bool, t *tmux.Tmux) {
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	r, err := mgr.GetRig(plan.Rig)
+	if err != nil {
+		fmt.Printf("  %s Could not load rig for worktree removal: %v\n", style.Warning.Render("!"), err)
+		return
+	}
+	pm := polecat.NewManager(r, git.NewGit(r.Path), t)
+
+	repo := git.NewGit(filepath.Join(r.Path, "mayor", "rig"))
+	if info, err := os.Stat(filepath.Join(r.Path, ".repo.git")); err == nil && info.IsDir() {
+		repo = git.NewGitWithDir(filepath.Join(r.Path, ".repo.git"), "")
+	}
+
+	for _, p := range plan.Polecats {
+		if deleteBranches && p.Branch != "" {
+			if err := repo.DeleteRemoteBranch("origin", p.Branch); err != nil {
+				fmt.Printf("  %s Could not delete origin/%s: %v\n", style.Warning.Render("!"), p.Branch, err)
+			} else {
+				fmt.Printf("  Deleted origin/%s\n", p.Branch)
+			}
+		}
+		if err := pm.Remove(p.Name, true); err != nil {
+			fmt.Printf("  %s Could not remove worktree %s: %v\n", style.Warning.Render("!"), p.Name, err)
+			continue
+		}
+		fmt.Printf("  Removed worktree %s\n", p.Name)
+	}
+}
+
+// rigArchiveDir is where final state of removed rigs is kept.
+func rigArchiveDir(townRoot string) string {
+	return filepath.Join(townRoot, ".archive", "rigs")
+}
+
+// archiveRigState writes the plan (registry entry, sessions, work) to the
+// archive so a removed rig can be audited or re-added later.
+func archiveRigState(townRoot string, plan *rigTeardownPlan) (string, error) {
+	plan.RemovedAt = time.Now().UTC()
+	path := filepath.Join(rigArchiveDir(townRoot), fmt.Sprintf("%s-%s.json", plan.Rig, plan.RemovedAt.Format("20060102-150405")))
+	if err := util.EnsureDirAndWriteJSON(path, plan); err != nil {
+		return "", fmt.Errorf("archiving rig state: %w", err)
+	}
+	return path, nil
+}
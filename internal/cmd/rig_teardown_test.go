@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestArchiveRigState(t *testing.T) {
+	townRoot := t.TempDir()
+	plan := &rigTeardownPlan{
+		Rig:           "myrig",
+		RigPath:       townRoot + "/myrig",
+		Sessions:      []string{"mr-witness"},
+		ReleaseIssues: []rigTeardownIssue{{ID: "mr-1", Status: "in_progress", Assignee: "myrig/Toast"}},
+	}
+
+	path, err := archiveRigState(townRoot, plan)
+	if err != nil {
+		t.Fatalf("archiveRigState: %v", err)
+	}
+	if !strings.HasPrefix(path, rigArchiveDir(townRoot)) || !strings.Contains(path, "myrig-") {
+		t.Errorf("archive path = %q", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got rigTeardownPlan
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Rig != "myrig" || len(got.ReleaseIssues) != 1 || got.RemovedAt.IsZero() {
+		t.Errorf("archived plan = %+v", got)
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/hooks"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// fetchRigTemplate returns the rig template at source: a local directory
+// is used in place, a git URL is cloned to a temporary directory that
+// cleanup removes.
+func fetchRigTemplate(source string) (tmpl *rig.Template, cleanup func(), err error) {
+	cleanup = func() {}
+	if info, statErr := os.Stat(source); statErr == nil && info.IsDir() {
+		tmpl, err = rig.LoadTemplate(source)
+		return tmpl, cleanup, err
+	}
+	if !isGitRemoteURL(source) {
+		return nil, cleanup, fmt.Errorf("invalid --from-template %q: expected a directory or a git URL", source)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gt-rig-template-*")
+	if err != nil {
+		return nil, cleanup, fmt.Errorf("creating temp dir: %w", err)
+	}
+	cleanup = func() { _ = os.RemoveAll(tmpDir) }
+
+	dest := filepath.Join(tmpDir, "template")
+	if err := git.NewGit(tmpDir).Clone(source, dest); err != nil {
+		cleanup()
+		return nil, func() {}, fmt.Errorf("cloning template %s: %w", source, err)
+	}
+	tmpl, err = rig.LoadTemplate(dest)
+	if err != nil {
+		cleanup()
+		return nil, func() {}, err
+	}
+	return tmpl, cleanup, nil
+}
+
+// applyRigTemplate installs a template into a newly added rig, including
+// its hooks overrides, and prints what it applied.
+func applyRigTemplate(tmpl *rig.Template, vars rig.TemplateVars) error {
+	applied, err := tmpl.Apply(vars)
+	if err != nil {
+		return err
+	}
+
+	overrides, err := tmpl.HookOverrides(vars)
+	if err != nil {
+		return err
+	}
+	for role, data := range overrides {
+		target, ok := hooks.NormalizeTarget(vars.Rig + "/" + role)
+		if !ok {
+			return fmt.Errorf("template hooks/%s.json: %q is not a hooks target role", role, role)
+		}
+		var cfg hooks.HooksConfig
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return fmt.Errorf("template hooks/%s.json: %w", role, err)
+		}
+		if err := hooks.SaveOverride(target, &cfg); err != nil {
+			return fmt.Errorf("saving hooks override for %s: %w", target, err)
+		}
+		applied = append(applied, "hooks override "+target)
+	}
+
+	if len(applied) > 0 {
+		fmt.Printf("  Applied template:\n")
+		for _, item := range applied {
+			fmt.Printf("    %s %s\n", style.Dim.Render("+"), item)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/instructions"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/templates"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	roleInstructionsRig   string
+	roleInstructionsName  string
+	roleInstructionsWrite bool
+)
+
+var roleInstructionsCmd = &cobra.Command{
+	Use:   "instructions [ROLE]",
+	Short: "Show or write the generated instructions section for a role",
+	Long: `Render the Gas Town section of an agent's instructions file (CLAUDE.md,
+AGENTS.md): the agent's identity, the gt commands its role uses, the rig's
+conventions, and current constraints such as a frozen town.
+
+With town settings "role_instructions" set, the section is written into
+polecat and crew worktrees (or the configured roles) whenever their
+sessions start. It is rendered from settings/instructions/<role>.md.tmpl
+or settings/instructions/agent.md.tmpl in the town if present, else the
+built-in template, and includes the rig's settings/conventions.md.
+
+Only the marked section is rewritten; the rest of the file is kept. Files
+the project tracks in git are never modified, and files gt creates are
+excluded from git in the clone.
+
+Without a role, shows the section for the current agent.
+
+Examples:
+  gt role instructions                        # Current agent
+  gt role instructions polecat --rig greenplace --name Toast
+  gt role instructions --write                # Write into the current worktree`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRoleInstructions,
+}
+
+func init() {
+	roleCmd.AddCommand(roleInstructionsCmd)
+	roleInstructionsCmd.Flags().StringVar(&roleInstructionsRig, "rig", "", "Rig name (with ROLE)")
+	roleInstructionsCmd.Flags().StringVar(&roleInstructionsName, "name", "", "Polecat/crew member name (with ROLE)")
+	roleInstructionsCmd.Flags().BoolVar(&roleInstructionsWrite, "write", false, "Write the section into the agent's instructions file")
+}
+
+func runRoleInstructions(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var agent instructions.Agent
+	if len(args) == 1 {
+		agent = instructions.Agent{TownRoot: townRoot, Rig: roleInstructionsRig, Role: args[0], Name: roleInstructionsName}
+		agent.WorkDir = getRoleHome(Role(agent.Role), agent.Rig, agent.Name, townRoot)
+	} else {
+		info, err := GetRole()
+		if err != nil {
+			return err
+		}
+		agent = instructions.Agent{TownRoot: townRoot, Rig: info.Rig, Role: string(info.Role), Name: info.Polecat, WorkDir: info.WorkDir}
+	}
+
+	section, err := instructions.Render(agent)
+	if err != nil {
+		return err
+	}
+	if !roleInstructionsWrite {
+		fmt.Print(section)
+		return nil
+	}
+
+	if agent.WorkDir == "" {
+		return fmt.Errorf("cannot determine the work directory for %s (rig=%q, name=%q)", agent.Role, agent.Rig, agent.Name)
+	}
+	file := instructions.FileFor(config.ResolveRoleAgentConfig(agent.Role, townRoot, rigPathFor(townRoot, agent.Rig)))
+	outcome, err := instructions.Write(agent.WorkDir, file, section)
+	if err != nil {
+		return err
+	}
+	switch outcome {
+	case instructions.OutcomeTracked:
+		style.PrintWarning("%s is tracked by the project; not modified (agents get this context from %s prime)", file, templates.CmdName())
+	case instructions.OutcomeUnchanged:
+		fmt.Printf("%s is up to date\n", file)
+	default:
+		fmt.Printf("%s %s %s\n", style.Success.Render("✓"), outcome, file)
+	}
+	return nil
+}
+
+// rigPathFor returns a rig's directory, or "" for town-level agents.
+func rigPathFor(townRoot, rigName string) string {
+	if rigName == "" {
+		return ""
+	}
+	return filepath.Join(townRoot, rigName)
+}
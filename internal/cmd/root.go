@@ -56,6 +56,7 @@ var beadsExemptCommands = map[string]bool{
 	"seance":     true,
 	"doctor":     true,
 	"dolt":       true,
+	"db":         true,
 	"handoff":    true,
 	"costs":      true,
 	"feed":       true,
@@ -67,7 +68,14 @@ var beadsExemptCommands = map[string]bool{
 	"signal":        true, // Hook signal handlers must be fast, handle beads internally
 	"metrics":       true, // Metrics reads local JSONL, no beads needed
 	"krc":           true, // KRC doesn't require beads
+	"federation":    true, // Federation reads remote dashboards over HTTP
+	"bootstrap":     true, // Bootstrap installs bd itself (delegates to install)
+	"upgrade":       true, // Upgrade migrates state files, no beads needed
+	"notes":         true, // Notes are plain files under .runtime
+	"record-pipe":   true, // Session recorder fed by tmux pipe-pane
+	"restore":       true, // Town state restore runs on fresh machines
 	"run-migration":       true, // Migration orchestrator handles its own beads checks
+	"panic":         true, // Emergency freeze must not wait on bd
 }
 
 // Commands exempt from the town root branch warning.
@@ -78,7 +86,9 @@ var branchCheckExemptCommands = map[string]bool{
 	"completion": true,
 	"doctor":     true, // Used to fix the problem
 	"install":    true, // Initial setup
+	"bootstrap":  true, // Initial setup
 	"git-init":   true, // Git setup
+	"panic":      true, // Emergency freeze; no time for warnings
 }
 
 // persistentPreRun runs before every command.
@@ -110,6 +120,16 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Enforce autopilot constraints on commands the Mayor runs.
+	if err := checkMayorAutopilot(cmd, args); err != nil {
+		return err
+	}
+
+	// Hold agents still while the town is frozen by gt panic.
+	if err := checkPanicFreeze(cmd); err != nil {
+		return err
+	}
+
 	// Get the root command name being run
 	cmdName := cmd.Name()
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/scheduler/capacity"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -482,6 +483,8 @@ func beadsSearchDirs(townRoot string) []string {
 }
 
 // countActivePolecats counts all running polecats across all rigs in the town.
+// Warm standby sessions are waiting, not working, and are left out: dispatch
+// claims them without adding a session.
 func countActivePolecats() int {
 	listCmd := tmux.BuildCommand("list-sessions", "-F", "#{session_name}")
 	out, err := listCmd.Output()
@@ -489,6 +492,7 @@ func countActivePolecats() int {
 		return 0
 	}
 
+	t := tmux.NewTmux()
 	count := 0
 	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
 		if line == "" {
@@ -498,7 +502,7 @@ func countActivePolecats() int {
 		if err != nil {
 			continue
 		}
-		if identity.Role == session.RolePolecat {
+		if identity.Role == session.RolePolecat && !polecat.IsStandbySession(t, line) {
 			count++
 		}
 	}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/search"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// searchIndexMaxAge is how old the search index may get before gt search
+// rebuilds it.
+const searchIndexMaxAge = 10 * time.Minute
+
+var (
+	searchRig     string
+	searchType    string
+	searchIn      string
+	searchSince   string
+	searchUntil   string
+	searchLimit   int
+	searchJSON    bool
+	searchReindex bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:     "search <query>",
+	GroupID: GroupWork,
+	Short:   "Full-text search across issues, mail, and transcripts",
+	Long: `Search issue titles and descriptions, mail, and agent session
+transcripts for the town.
+
+Every word in the query must match. Wrap words in double quotes to require
+an exact phrase. Results are ranked by relevance.
+
+Search uses a local index under .runtime/search, rebuilt automatically
+when it is more than 10 minutes old (or on --reindex). Transcripts are the
+Claude Code session logs for agents working inside this town.
+
+Examples:
+  gt search merge slot deadlock
+  gt search '"cache eviction"' --in transcript
+  gt search parser --rig greenplace --type bug
+  gt search rollout --in mail --since 7d
+  gt search flaky --since 2026-01-01 --until 2026-02-01 --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().StringVar(&searchRig, "rig", "", "Only results from this rig")
+	searchCmd.Flags().StringVar(&searchType, "type", "", "Only issues of this type (bug, feature, task, ...)")
+	searchCmd.Flags().StringVar(&searchIn, "in", "", "Only search one source: issue, mail, or transcript")
+	searchCmd.Flags().StringVar(&searchSince, "since", "", "Only results since a duration ago (e.g., 24h, 7d) or date (YYYY-MM-DD)")
+	searchCmd.Flags().StringVar(&searchUntil, "until", "", "Only results until a duration ago or date (YYYY-MM-DD, inclusive)")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 20, "Maximum results (0 = no limit)")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "Output as JSON")
+	searchCmd.Flags().BoolVar(&searchReindex, "reindex", false, "Rebuild the index before searching")
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	q := search.Query{
+		Text:  strings.Join(args, " "),
+		Kind:  searchIn,
+		Rig:   searchRig,
+		Type:  searchType,
+		Limit: searchLimit,
+	}
+	switch q.Kind {
+	case "", search.KindIssue, search.KindMail, search.KindTranscript:
+	default:
+		return fmt.Errorf("invalid --in %q: must be issue, mail, or transcript", q.Kind)
+	}
+	if q.Since, err = parseSearchTime(searchSince, false); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	if q.Until, err = parseSearchTime(searchUntil, true); err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+
+	ix, err := loadSearchIndex(townRoot, searchReindex)
+	if err != nil {
+		return err
+	}
+	results := ix.Search(q)
+
+	if searchJSON {
+		if results == nil {
+			results = []search.Result{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No results for %q\n", q.Text)
+		return nil
+	}
+	for _, r := range results {
+		d := r.Doc
+		where := d.Kind
+		if d.Rig != "" {
+			where += ", " + d.Rig
+		}
+		fmt.Printf("%s %s %s\n", style.Bold.Render(d.ID), d.Title,
+			style.Dim.Render(fmt.Sprintf("(%s, %s)", where, d.Date.Local().Format("2006-01-02"))))
+		if r.Snippet != "" {
+			fmt.Printf("  %s\n", r.Snippet)
+		}
+	}
+	return nil
+}
+
+// parseSearchTime parses a --since/--until value: a duration ago (24h, 7d)
+// or a date. An --until date includes the whole day.
+func parseSearchTime(s string, endOfDay bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := parseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a duration (24h, 7d) nor a date (YYYY-MM-DD)", s)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+// loadSearchIndex returns the town's search index, rebuilding it when it
+// is missing, unreadable, stale, or a rebuild was requested.
+func loadSearchIndex(townRoot string, rebuild bool) (*search.Index, error) {
+	path := search.IndexFile(townRoot)
+	if !rebuild {
+		if ix, err := search.Load(path); err == nil && time.Since(ix.BuiltAt) < searchIndexMaxAge {
+			return ix, nil
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, style.Dim.Render("Indexing issues, mail, and transcripts..."))
+	ix := search.Build(collectSearchDocs(townRoot))
+	if err := ix.Save(path); err != nil {
+		// A search can still be answered from memory.
+		fmt.Fprintf(os.Stderr, "%s saving search index: %v\n", style.WarningPrefix, err)
+	}
+	return ix, nil
+}
+
+// collectSearchDocs gathers every searchable document in the town. Sources
+// that fail to load are skipped with a warning so one broken rig does not
+// break search.
+func collectSearchDocs(townRoot string) []search.Doc {
+	rigs := discoverRigs(townRoot)
+
+	var docs []search.Doc
+	sources := append([]string{""}, rigs...) // "" is the town-level (HQ) database
+	for _, rigName := range sources {
+		issues, err := beads.New(filepath.Join(townRoot, rigName)).List(beads.ListOptions{Status: "all", Priority: -1})
+		if err != nil {
+			label := rigName
+			if label == "" {
+				label = "town"
+			}
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", style.WarningPrefix, label, err)
+			continue
+		}
+		for _, issue := range issues {
+			if doc, ok := issueSearchDoc(rigName, issue); ok {
+				docs = append(docs, doc)
+			}
+		}
+	}
+
+	return append(docs, transcriptSearchDocs(townRoot, rigs)...)
+}
+
+// issueSearchDoc converts an issue to a search document. Mail is stored as
+// gt:message beads; other bookkeeping beads (agents, MRs, molecules) are
+// not searchable.
+func issueSearchDoc(rigName string, issue *beads.Issue) (search.Doc, bool) {
+	doc := search.Doc{
+		ID:    issue.ID,
+		Kind:  search.KindIssue,
+		Rig:   rigName,
+		Type:  issue.Type,
+		Title: issue.Title,
+		Body:  issue.Description,
+		Date:  searchIssueDate(issue),
+	}
+	switch {
+	case beads.HasLabel(issue, "gt:message"):
+		doc.Kind = search.KindMail
+		doc.Type = ""
+	case !beads.IsWorkItem(issue):
+		return search.Doc{}, false
+	}
+	return doc, true
+}
+
+// searchIssueDate returns when an issue last changed.
+func searchIssueDate(issue *beads.Issue) time.Time {
+	for _, ts := range []string{issue.UpdatedAt, issue.CreatedAt} {
+		if t, err := time.Parse(time.RFC3339, ts); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// transcriptSearchDocs indexes the Claude Code transcripts of sessions that
+// ran inside the town. Claude Code keeps them under ~/.claude/projects in
+// directories named after the session's working directory, so every
+// directory named after a path inside the town belongs to one of its agents.
+func transcriptSearchDocs(townRoot string, rigs []string) []search.Doc {
+	townDir, err := getClaudeProjectDir(townRoot)
+	if err != nil {
+		return nil
+	}
+	projectsDir := filepath.Dir(townDir)
+	prefix := filepath.Base(townDir)
+
+	entries, err := os.ReadDir(projectsDir)
+	if err != nil {
+		return nil
+	}
+
+	var docs []search.Doc
+	for _, e := range entries {
+		if !e.IsDir() || (e.Name() != prefix && !strings.HasPrefix(e.Name(), prefix+"-")) {
+			continue
+		}
+		agent := strings.TrimPrefix(strings.TrimPrefix(e.Name(), prefix), "-")
+		rigName := transcriptRig(agent, rigs)
+
+		files, _ := filepath.Glob(filepath.Join(projectsDir, e.Name(), "*.jsonl"))
+		for _, path := range files {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			text, err := search.TranscriptText(path)
+			if err != nil || text == "" {
+				continue
+			}
+			title := agent
+			if title == "" {
+				title = "town"
+			}
+			docs = append(docs, search.Doc{
+				ID:    path,
+				Kind:  search.KindTranscript,
+				Rig:   rigName,
+				Title: title + " transcript",
+				Body:  text,
+				Date:  info.ModTime(),
+			})
+		}
+	}
+	return docs
+}
+
+// transcriptRig recovers the rig from a transcript directory suffix such as
+// "greenplace-polecats-Toast". Claude Code encodes "/" as "-", so the rig is
+// the longest rig name the suffix starts with.
+func transcriptRig(agent string, rigs []string) string {
+	best := ""
+	for _, r := range rigs {
+		if (agent == r || strings.HasPrefix(agent, r+"-")) && len(r) > len(best) {
+			best = r
+		}
+	}
+	return best
+}
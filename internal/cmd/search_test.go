@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/search"
+)
+
+func TestTranscriptRig(t *testing.T) {
+	rigs := []string{"green", "green-place", "blue"}
+	tests := map[string]string{
+		"green-place-polecats-Toast": "green-place",
+		"green-crew-joe":             "green",
+		"blue":                       "blue",
+		"mayor":                      "",
+		"bluefield-witness":          "",
+	}
+	for agent, want := range tests {
+		if got := transcriptRig(agent, rigs); got != want {
+			t.Errorf("transcriptRig(%q) = %q, want %q", agent, got, want)
+		}
+	}
+}
+
+func TestIssueSearchDoc(t *testing.T) {
+	doc, ok := issueSearchDoc("greenplace", &beads.Issue{ID: "gp-1", Type: "bug", Title: "Crash", UpdatedAt: "2026-01-02T03:04:05Z"})
+	if !ok || doc.Kind != search.KindIssue || doc.Rig != "greenplace" || doc.Date.IsZero() {
+		t.Errorf("bug issue doc = %+v, %v", doc, ok)
+	}
+
+	doc, ok = issueSearchDoc("", &beads.Issue{ID: "hq-m1", Type: "message", Labels: []string{"gt:message"}})
+	if !ok || doc.Kind != search.KindMail {
+		t.Errorf("mail doc = %+v, %v", doc, ok)
+	}
+
+	if _, ok := issueSearchDoc("greenplace", &beads.Issue{ID: "gp-a", Type: "agent", Labels: []string{"gt:agent"}}); ok {
+		t.Error("agent bead should not be searchable")
+	}
+}
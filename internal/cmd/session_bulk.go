@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	sessionBulkRig      string
+	sessionBulkRole     string
+	sessionBulkParallel int
+	sessionBulkDryRun   bool
+	sessionBulkJSON     bool
+	sessionKillYes      bool
+	sessionNudgeMessage string
+	sessionNudgeForce   bool
+)
+
+var sessionKillCmd = &cobra.Command{
+	Use:   "kill [pattern...]",
+	Short: "Kill every session matching a pattern or selector",
+	Long: `Kill all tmux sessions matching shell-style patterns and/or a selector,
+in parallel, with their processes.
+
+Patterns match tmux session names ("gt-test-*"). --rig and --role select
+by the agent a session runs; combined with patterns, a session must match
+both. Your own session is never killed.
+
+Examples:
+  gt sessions kill 'gt-test-*' --yes
+  gt sessions kill --rig gastown --role polecat --dry-run`,
+	RunE: runSessionKill,
+}
+
+var sessionNudgeCmd = &cobra.Command{
+	Use:   "nudge [pattern...] -m <message>",
+	Short: "Nudge every session matching a pattern or selector",
+	Long: `Send a nudge to all tmux sessions matching shell-style patterns and/or a
+selector, in parallel. Agents with DND on are skipped unless --force.
+
+Examples:
+  gt sessions nudge --rig gastown -m "Pull main, the build is fixed"
+  gt sessions nudge 'bd-*' --role polecat -m "Status?"`,
+	RunE: runSessionNudge,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{sessionKillCmd, sessionNudgeCmd} {
+		c.Flags().StringVar(&sessionBulkRig, "rig", "", "Only sessions of this rig")
+		c.Flags().StringVar(&sessionBulkRole, "role", "", "Only sessions of this role (polecat, crew, witness, refinery, mayor, deacon)")
+		c.Flags().IntVar(&sessionBulkParallel, "parallel", session.DefaultBulkParallelism, "How many sessions to work on at once")
+		c.Flags().BoolVarP(&sessionBulkDryRun, "dry-run", "n", false, "List the matching sessions without acting on them")
+		c.Flags().BoolVar(&sessionBulkJSON, "json", false, "Output as JSON")
+		sessionCmd.AddCommand(c)
+	}
+	sessionKillCmd.Flags().BoolVarP(&sessionKillYes, "yes", "y", false, "Don't ask for confirmation")
+	sessionNudgeCmd.Flags().StringVarP(&sessionNudgeMessage, "message", "m", "", "Message to send (required)")
+	sessionNudgeCmd.Flags().BoolVarP(&sessionNudgeForce, "force", "f", false, "Nudge agents with DND on too")
+}
+
+// sessionBulkResult is the JSON output of a bulk session operation.
+type sessionBulkResult struct {
+	Session string `json:"session"`
+	Status  string `json:"status"` // ok, failed, skipped, or matched (dry run)
+	Error   string `json:"error,omitempty"`
+}
+
+// errBulkSkipped marks a session a bulk operation chose not to act on.
+type errBulkSkipped string
+
+func (e errBulkSkipped) Error() string { return string(e) }
+
+// selectSessions lists the running tmux sessions the command's arguments
+// and flags select.
+func selectSessions(t *tmux.Tmux, patterns []string) ([]string, error) {
+	sel := session.Selector{Patterns: patterns, Rig: sessionBulkRig, Role: session.Role(sessionBulkRole)}
+	if err := sel.Validate(); err != nil {
+		return nil, err
+	}
+	names, err := t.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	return sel.Select(names), nil
+}
+
+func runSessionKill(cmd *cobra.Command, args []string) error {
+	t := tmux.NewTmux()
+	targets, err := selectSessions(t, args)
+	if err != nil {
+		return err
+	}
+	if !sessionBulkDryRun && !sessionKillYes && len(targets) > 0 {
+		fmt.Printf("Sessions to kill (%d):\n  %s\n", len(targets), strings.Join(targets, "\n  "))
+		if !promptYesNo(fmt.Sprintf("Kill %d session(s)?", len(targets))) {
+			return fmt.Errorf("aborted")
+		}
+	}
+
+	self, sender := tmux.CurrentSessionName(), detectSender()
+	return runSessionBulk("kill", "killed", targets, func(name string) error {
+		if name == self {
+			return errBulkSkipped("own session")
+		}
+		if err := t.KillSessionWithProcesses(name); err != nil {
+			return err
+		}
+		_ = events.LogFeed(events.TypeKill, sender, map[string]interface{}{
+			"target": name,
+			"reason": "gt session kill",
+		})
+		return nil
+	})
+}
+
+func runSessionNudge(cmd *cobra.Command, args []string) error {
+	if sessionNudgeMessage == "" {
+		return fmt.Errorf("message required: use -m")
+	}
+	t := tmux.NewTmux()
+	targets, err := selectSessions(t, args)
+	if err != nil {
+		return err
+	}
+
+	townRoot, _ := workspace.FindFromCwd()
+	sender := detectSender()
+	err = runSessionBulk("nudge", "nudged", targets, func(name string) error {
+		if addr := sessionNameToAddress(name); addr != "" && townRoot != "" {
+			if ok, level, _ := shouldNudgeTarget(townRoot, addr, sessionNudgeForce); !ok {
+				return errBulkSkipped("DND: " + level)
+			}
+		}
+		return t.NudgeSession(name, fmt.Sprintf("[from %s] %s", sender, sessionNudgeMessage))
+	})
+	if !sessionBulkDryRun && len(targets) > 0 {
+		_ = events.LogFeed(events.TypeNudge, sender, events.NudgePayload("", "sessions:"+strings.Join(args, ","), sessionNudgeMessage))
+	}
+	return err
+}
+
+// runSessionBulk applies op to targets in parallel (or lists them for a dry
+// run) and prints a summary. It fails if op failed for any session.
+func runSessionBulk(verb, done string, targets []string, op func(string) error) error {
+	if len(targets) == 0 {
+		if sessionBulkJSON {
+			fmt.Println("[]")
+			return nil
+		}
+		fmt.Printf("%s No sessions match\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	var results []session.BulkResult
+	if sessionBulkDryRun {
+		for _, name := range targets {
+			results = append(results, session.BulkResult{Session: name})
+		}
+	} else {
+		results = session.RunBulk(targets, sessionBulkParallel, op)
+	}
+
+	out := make([]sessionBulkResult, len(results))
+	var succeeded, failed, skipped int
+	for i, r := range results {
+		out[i] = sessionBulkResult{Session: r.Session, Status: "ok"}
+		var skip errBulkSkipped
+		switch {
+		case sessionBulkDryRun:
+			out[i].Status = "matched"
+		case r.Err == nil:
+			succeeded++
+		case errors.As(r.Err, &skip):
+			skipped++
+			out[i].Status, out[i].Error = "skipped", r.Err.Error()
+		default:
+			failed++
+			out[i].Status, out[i].Error = "failed", r.Err.Error()
+		}
+	}
+
+	if sessionBulkJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	} else {
+		printSessionBulk(verb, done, out, succeeded, failed, skipped)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d session(s) failed to %s", failed, verb)
+	}
+	return nil
+}
+
+func printSessionBulk(verb, done string, results []sessionBulkResult, succeeded, failed, skipped int) {
+	if sessionBulkDryRun {
+		fmt.Printf("Would %s %d session(s):\n", verb, len(results))
+		for _, r := range results {
+			fmt.Printf("  %s\n", r.Session)
+		}
+		return
+	}
+	for _, r := range results {
+		switch r.Status {
+		case "ok":
+			fmt.Printf("  %s %s\n", style.SuccessPrefix, r.Session)
+		case "skipped":
+			fmt.Printf("  %s %s %s\n", style.Dim.Render("○"), r.Session, style.Dim.Render("("+r.Error+")"))
+		default:
+			fmt.Printf("  %s %s: %s\n", style.ErrorPrefix, r.Session, r.Error)
+		}
+	}
+	summary := fmt.Sprintf("%d %s, %d failed", succeeded, done, failed)
+	if skipped > 0 {
+		summary += fmt.Sprintf(", %d skipped", skipped)
+	}
+	prefix := style.SuccessPrefix
+	if failed > 0 {
+		prefix = style.WarningPrefix
+	}
+	fmt.Printf("\n%s %s\n", prefix, summary)
+}
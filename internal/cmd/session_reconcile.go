@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+var (
+	sessionReconcileDryRun bool
+	sessionReconcileJSON   bool
+)
+
+var sessionReconcileCmd = &cobra.Command{
+	Use:   "reconcile <rig>|<rig>/<polecat>",
+	Short: "Update running sessions whose dispatch context changed",
+	Long: `Bring running polecat sessions in line with state that changed mid-task.
+
+A session's environment is set when it starts. Some of it is derived from
+state that can change while the polecat works:
+  GT_BRANCH          the worktree's branch (renamed?)
+  GT_ISSUE           the hooked issue (re-slung?)
+  GT_ISSUE_PRIORITY  the hooked issue's priority (re-prioritized?)
+  GT_TARGET_BRANCH   the rig's default branch (renamed?)
+
+Reconcile updates stale values in the tmux session environment, so
+respawned processes pick them up. The running agent can't see those
+updates, so it is also nudged with what changed, and its run-context file
+is rewritten. Filling in a value the session never had is done quietly.
+Every update is recorded as a session_env_reconciled event.
+
+Reconcile also renews each session's dispatch claim on its issue, which
+keeps other dispatches from assigning the issue while the polecat works it.
+A claim held by someone else is reported: the issue may be assigned twice.
+
+The witness runs this each patrol cycle.
+
+Examples:
+  gt session reconcile greenplace            # All polecat sessions in the rig
+  gt session reconcile greenplace/Toast      # One session
+  gt session reconcile greenplace --dry-run  # Show what is stale`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionReconcile,
+}
+
+func init() {
+	sessionReconcileCmd.Flags().BoolVar(&sessionReconcileDryRun, "dry-run", false, "Show stale values without changing anything")
+	sessionReconcileCmd.Flags().BoolVar(&sessionReconcileJSON, "json", false, "Output as JSON")
+	sessionCmd.AddCommand(sessionReconcileCmd)
+}
+
+func runSessionReconcile(cmd *cobra.Command, args []string) error {
+	rigName, only, _ := strings.Cut(args[0], "/")
+	polecatMgr, r, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	sessMgr := polecat.NewSessionManager(tmux.NewTmux(), r)
+
+	var targets []*polecat.Polecat
+	if only != "" {
+		p, err := polecatMgr.Get(only)
+		if err != nil {
+			return fmt.Errorf("polecat %s: %w", args[0], err)
+		}
+		targets = append(targets, p)
+	} else {
+		if targets, err = polecatMgr.List(); err != nil {
+			return fmt.Errorf("listing polecats: %w", err)
+		}
+	}
+
+	var results []*polecat.EnvReconciliation
+	var failed []string
+	for _, p := range targets {
+		res, err := sessMgr.ReconcileEnv(p.Name, p.Issue, sessionReconcileDryRun)
+		if errors.Is(err, polecat.ErrSessionNotFound) && only == "" {
+			continue
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", p.Name, err))
+			if res == nil {
+				continue
+			}
+		}
+		if len(res.Changes) > 0 && !sessionReconcileDryRun {
+			agent := fmt.Sprintf("%s/polecats/%s", r.Name, p.Name)
+			_ = events.LogFeed(events.TypeSessionEnvReconciled, agent,
+				events.SessionEnvPayload(res.Session, agent, describeEnvChanges(res.Changes), res.Nudged))
+		}
+		results = append(results, res)
+	}
+
+	if sessionReconcileJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printEnvReconciliations(results, sessionReconcileDryRun)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("reconcile failed for %d session(s):\n  %s", len(failed), strings.Join(failed, "\n  "))
+	}
+	return nil
+}
+
+// describeEnvChanges formats changes for event payloads.
+func describeEnvChanges(changes []polecat.EnvChange) []string {
+	out := make([]string, 0, len(changes))
+	for _, c := range changes {
+		old := c.Old
+		if old == "" {
+			old = "(unset)"
+		}
+		out = append(out, fmt.Sprintf("%s: %s -> %s", c.Key, old, c.New))
+	}
+	return out
+}
+
+func printEnvReconciliations(results []*polecat.EnvReconciliation, dryRun bool) {
+	stale := 0
+	for _, res := range results {
+		if res.ClaimHeldBy != "" {
+			fmt.Printf("%s %s: its issue is claimed by %s (assigned twice?)\n",
+				style.Warning.Render("⚠"), res.Session, res.ClaimHeldBy)
+		}
+		if len(res.Changes) == 0 {
+			continue
+		}
+		stale++
+		verb := "updated"
+		if dryRun {
+			verb = "stale"
+		} else if res.Nudged {
+			verb = "updated, agent nudged"
+		}
+		fmt.Printf("%s %s (%s)\n", style.Bold.Render("●"), res.Session, verb)
+		for _, line := range describeEnvChanges(res.Changes) {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	if stale == 0 {
+		fmt.Printf("%s %d session(s) up to date\n", style.Success.Render("✓"), len(results))
+	}
+}
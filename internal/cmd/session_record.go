@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/recording"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	sessionRecordStop bool
+
+	recordPipeOut     string
+	recordPipeSession string
+	recordPipeCols    int
+	recordPipeRows    int
+)
+
+var sessionRecordCmd = &cobra.Command{
+	Use:   "record <rig>/<polecat>|<session>",
+	Short: "Record a session as an asciicast (play back with gt replay)",
+	Long: `Start or stop recording a running session.
+
+Recording copies the agent pane's terminal output, with timing, into an
+asciicast v2 file under <town>/.runtime/recordings/. Recordings play in
+asciinema or with 'gt replay'. A recording ends when the session does, and
+is then gzipped; 'gt session rotate' expires old ones.
+
+To record every polecat session in a rig, set in <rig>/settings/config.json:
+  "recording": {"enabled": true}
+
+Examples:
+  gt session record wyvern/Toast
+  gt session record wyvern/Toast --stop`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionRecord,
+}
+
+// sessionRecordPipeCmd is the recorder that tmux pipe-pane runs.
+var sessionRecordPipeCmd = &cobra.Command{
+	Use:    "record-pipe",
+	Short:  "Write pane output from stdin as an asciicast (used by pipe-pane)",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runSessionRecordPipe,
+}
+
+func init() {
+	sessionRecordCmd.Flags().BoolVar(&sessionRecordStop, "stop", false, "Stop recording")
+
+	sessionRecordPipeCmd.Flags().StringVar(&recordPipeOut, "out", "", "Recording file to write")
+	sessionRecordPipeCmd.Flags().StringVar(&recordPipeSession, "session", "", "Session being recorded")
+	sessionRecordPipeCmd.Flags().IntVar(&recordPipeCols, "cols", 80, "Terminal width")
+	sessionRecordPipeCmd.Flags().IntVar(&recordPipeRows, "rows", 24, "Terminal height")
+	_ = sessionRecordPipeCmd.MarkFlagRequired("out")
+
+	sessionCmd.AddCommand(sessionRecordCmd)
+	sessionCmd.AddCommand(sessionRecordPipeCmd)
+}
+
+func runSessionRecord(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	sessionName, err := sessionNameArg(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	t := tmux.NewTmux()
+	if running, _ := t.HasSession(sessionName); !running {
+		return fmt.Errorf("session %s is not running", sessionName)
+	}
+
+	if sessionRecordStop {
+		if !t.IsPanePiped(sessionName) {
+			fmt.Printf("%s %s is not being recorded\n", style.Dim.Render("○"), sessionName)
+			return nil
+		}
+		if err := recording.StopSession(t, sessionName); err != nil {
+			return fmt.Errorf("stopping recording: %w", err)
+		}
+		fmt.Printf("%s Stopped recording %s\n", style.Bold.Render("✓"), sessionName)
+		return nil
+	}
+
+	if t.IsPanePiped(sessionName) {
+		fmt.Printf("%s %s is already being recorded (or piped elsewhere)\n", style.Dim.Render("○"), sessionName)
+		return nil
+	}
+	path, err := recording.StartSession(t, townRoot, sessionName)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Recording %s to %s\n", style.Bold.Render("✓"), sessionName, path)
+	fmt.Printf("  Stop with: gt session record %s --stop\n", args[0])
+	return nil
+}
+
+func runSessionRecordPipe(cmd *cobra.Command, args []string) error {
+	f, err := os.OpenFile(recordPipeOut, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := recording.Header{
+		Width:  recordPipeCols,
+		Height: recordPipeRows,
+		Title:  recordPipeSession,
+		Env:    map[string]string{"TERM": "xterm-256color", "GT_SESSION": recordPipeSession},
+	}
+	if err := recording.Record(os.Stdin, f, h, time.Now); err != nil {
+		return err
+	}
+	// The pane has exited: compress the finished recording. The witness's
+	// gt session rotate compresses any a killed recorder left behind.
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if _, err := recording.Compress(recordPipeOut); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/recording"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+var (
+	sessionRotateAll    bool
+	sessionRotateDryRun bool
+	sessionRotateJSON   bool
+)
+
+var sessionRotateCmd = &cobra.Command{
+	Use:   "rotate [rig]",
+	Short: "Compress and expire a rig's session transcripts",
+	Long: `Apply transcript retention to a rig's sessions.
+
+Covers recordings (<town>/.runtime/recordings/) and input transcripts
+(<town>/.runtime/transcripts/) of the rig's agents:
+  1. Recordings of sessions that have ended are gzipped (gt replay reads them)
+  2. Files last written more than recording.retention_days ago are deleted
+  3. The oldest files are deleted until the rig fits recording.max_size_mb
+
+Limits come from <rig>/settings/config.json:
+  "recording": {"retention_days": 14, "max_size_mb": 1024}
+Defaults are 14 days and 1024 MB; -1 disables a limit. A running session's
+input transcript and current recording are never touched.
+
+The witness runs this each patrol; run it by hand to free disk now.
+
+Examples:
+  gt session rotate gastown --dry-run
+  gt session rotate --all`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSessionRotate,
+}
+
+func init() {
+	sessionRotateCmd.Flags().BoolVar(&sessionRotateAll, "all", false, "Rotate the transcripts of all rigs")
+	sessionRotateCmd.Flags().BoolVarP(&sessionRotateDryRun, "dry-run", "n", false, "Show what would be compressed and deleted")
+	sessionRotateCmd.Flags().BoolVar(&sessionRotateJSON, "json", false, "Output as JSON")
+	sessionCmd.AddCommand(sessionRotateCmd)
+}
+
+// rigTranscriptRotation is the JSON output of gt session rotate for one rig.
+type rigTranscriptRotation struct {
+	Rig string `json:"rig"`
+	recording.RotateResult
+	Error string `json:"error,omitempty"`
+}
+
+func runSessionRotate(cmd *cobra.Command, args []string) error {
+	var townRoot string
+	var rigs []*rig.Rig
+	if sessionRotateAll {
+		allRigs, root, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		townRoot, rigs = root, allRigs
+	} else {
+		if len(args) < 1 {
+			return fmt.Errorf("rig name required (or use --all)")
+		}
+		root, r, err := getRig(args[0])
+		if err != nil {
+			return err
+		}
+		townRoot, rigs = root, []*rig.Rig{r}
+	}
+
+	live := liveSessionSet()
+	var results []rigTranscriptRotation
+	var failed int
+	for _, r := range rigs {
+		res, err := rotateRigTranscripts(townRoot, r, live, sessionRotateDryRun)
+		out := rigTranscriptRotation{Rig: r.Name, RotateResult: res}
+		if err != nil {
+			failed++
+			out.Error = err.Error()
+		}
+		results = append(results, out)
+	}
+
+	if sessionRotateJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		for _, res := range results {
+			printTranscriptRotation(res)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d rig(s) failed transcript rotation", failed)
+	}
+	return nil
+}
+
+// rotateRigTranscripts applies the rig's retention limits to its sessions'
+// transcript files.
+func rotateRigTranscripts(townRoot string, r *rig.Rig, live func(string) bool, dryRun bool) (recording.RotateResult, error) {
+	var rc *config.RecordingConfig
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path)); err == nil {
+		rc = settings.Recording
+	}
+	files, err := recording.StoredFiles(townRoot, func(s string) bool { return sessionRig(s) == r.Name })
+	if err != nil {
+		return recording.RotateResult{}, fmt.Errorf("listing transcripts: %w", err)
+	}
+	policy := recording.RetentionPolicy{MaxAge: rc.Retention(), MaxBytes: rc.MaxBytes()}
+	return recording.Rotate(files, policy, live, time.Now(), dryRun)
+}
+
+// sessionRig returns the rig a session name belongs to ("" for town-level
+// agents and unknown names).
+func sessionRig(name string) string {
+	id, err := session.ParseSessionName(name)
+	if err != nil {
+		return ""
+	}
+	return id.Rig
+}
+
+// liveSessionSet reports which sessions are running. If tmux can't be
+// asked, every session counts as running so nothing in use is touched.
+func liveSessionSet() func(string) bool {
+	sessions, err := tmux.NewTmux().ListSessions()
+	if err != nil {
+		return func(string) bool { return true }
+	}
+	running := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		running[s] = true
+	}
+	return func(s string) bool { return running[s] }
+}
+
+func printTranscriptRotation(res rigTranscriptRotation) {
+	verb := ""
+	if sessionRotateDryRun {
+		verb = "would be "
+	}
+	summary := fmt.Sprintf("%s: %s of transcripts %s", res.Rig, formatBytes(res.Bytes),
+		style.Dim.Render(fmt.Sprintf("(%d %scompressed, %d %sdeleted, %s freed)",
+			len(res.Compressed), verb, len(res.Deleted), verb, formatBytes(res.Freed))))
+	if res.Error != "" {
+		fmt.Printf("%s %s: %s\n", style.ErrorPrefix, summary, res.Error)
+		return
+	}
+	fmt.Printf("%s %s\n", style.SuccessPrefix, summary)
+	if sessionRotateDryRun {
+		for _, p := range res.Compressed {
+			fmt.Printf("  compress %s\n", p)
+		}
+		for _, p := range res.Deleted {
+			fmt.Printf("  delete   %s\n", p)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	sessionTranscriptJSON     bool
+	sessionTranscriptTail     int
+	sessionTranscriptNoOutput bool
+)
+
+// transcriptMergeWindow is how close literal chunks of one message (nudges
+// over 512 bytes are sent in chunks) must be to display as one line.
+const transcriptMergeWindow = 2 * time.Second
+
+var sessionTranscriptCmd = &cobra.Command{
+	Use:   "transcript <rig>/<polecat>|<session>",
+	Short: "Show input sent into a session, with output context",
+	Long: `Show the transcript of everything gastown sent into a session.
+
+Every send-keys is recorded with a direction marker and its source:
+  →  input: nudges, dialog macro keys, injected text, control keys
+  ←  output: the pane content a nudge or dialog macro responded to
+  ⇢  a human attaching through gt (their keystrokes are not visible)
+
+Transcripts live in <town>/.runtime/transcripts/<session>.jsonl.
+Recording is off by default: set "transcripts": true in the town settings
+(settings/config.json) to turn it on, or GT_TRANSCRIPT=on|off to override
+the setting for one process.
+
+Examples:
+  gt session transcript wyvern/Toast
+  gt session transcript hq-mayor -n 20
+  gt session transcript wyvern/Toast --no-output
+  gt session transcript wyvern/Toast --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionTranscript,
+}
+
+func init() {
+	sessionTranscriptCmd.Flags().BoolVar(&sessionTranscriptJSON, "json", false, "Output raw entries as JSON lines")
+	sessionTranscriptCmd.Flags().IntVarP(&sessionTranscriptTail, "tail", "n", 0, "Show only the last N entries")
+	sessionTranscriptCmd.Flags().BoolVar(&sessionTranscriptNoOutput, "no-output", false, "Hide output context entries")
+	sessionCmd.AddCommand(sessionTranscriptCmd)
+}
+
+func runSessionTranscript(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	sessionName, err := sessionNameArg(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+
+	entries, err := tmux.ReadTranscript(townRoot, sessionName)
+	if err != nil {
+		return fmt.Errorf("reading transcript: %w", err)
+	}
+	if sessionTranscriptNoOutput {
+		kept := entries[:0]
+		for _, e := range entries {
+			if e.Dir != tmux.TranscriptOut {
+				kept = append(kept, e)
+			}
+		}
+		entries = kept
+	}
+	if !sessionTranscriptJSON {
+		entries = mergeTranscriptChunks(entries)
+	}
+	if n := sessionTranscriptTail; n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+
+	if sessionTranscriptJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(entries) == 0 {
+		fmt.Printf("%s No transcript for %s\n", style.Dim.Render("○"), sessionName)
+		return nil
+	}
+	writeTranscript(os.Stdout, entries)
+	return nil
+}
+
+// sessionNameArg resolves a rig/polecat address to its session name. A
+// bare session name (e.g., "hq-mayor") that is running or has a transcript
+// is used as is.
+func sessionNameArg(townRoot, addr string) (string, error) {
+	if !strings.Contains(addr, "/") {
+		if _, err := os.Stat(tmux.TranscriptPath(townRoot, addr)); err == nil {
+			return addr, nil
+		}
+		if running, _ := tmux.NewTmux().HasSession(addr); running {
+			return addr, nil
+		}
+	}
+	rigName, polecatName, err := parseAddress(addr)
+	if err != nil {
+		return "", err
+	}
+	polecatMgr, _, err := getSessionManager(rigName)
+	if err != nil {
+		return "", err
+	}
+	return polecatMgr.SessionName(polecatName), nil
+}
+
+// mergeTranscriptChunks joins consecutive literal input entries from the
+// same source that arrived within transcriptMergeWindow, so a chunked nudge
+// reads as one message.
+func mergeTranscriptChunks(entries []tmux.TranscriptEntry) []tmux.TranscriptEntry {
+	var out []tmux.TranscriptEntry
+	for _, e := range entries {
+		if n := len(out); n > 0 && e.Literal && e.Dir == tmux.TranscriptIn {
+			prev := &out[n-1]
+			if prev.Literal && prev.Dir == tmux.TranscriptIn && prev.Source == e.Source &&
+				e.Time.Sub(prev.Time) < transcriptMergeWindow {
+				prev.Data += e.Data
+				continue
+			}
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// writeTranscript renders entries with direction markers.
+func writeTranscript(w io.Writer, entries []tmux.TranscriptEntry) {
+	for _, e := range entries {
+		ts := style.Dim.Render(e.Time.Local().Format("01-02 15:04:05"))
+		source := fmt.Sprintf("%-6s", e.Source)
+		switch {
+		case e.Dir == tmux.TranscriptOut:
+			fmt.Fprintf(w, "%s ← %s\n", ts, source)
+			for _, line := range strings.Split(strings.TrimRight(e.Data, "\n"), "\n") {
+				fmt.Fprintf(w, "    %s\n", style.Dim.Render("│ "+line))
+			}
+		case e.Kind == "attach":
+			fmt.Fprintf(w, "%s ⇢ %s attached\n", ts, source)
+		case e.Literal:
+			fmt.Fprintf(w, "%s → %s %q\n", ts, source, e.Data)
+		default:
+			fmt.Fprintf(w, "%s → %s [%s]\n", ts, source, strings.Join(e.Keys, " "))
+		}
+	}
+}
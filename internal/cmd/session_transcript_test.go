@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestMergeTranscriptChunks(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	in := []tmux.TranscriptEntry{
+		{Time: t0, Dir: tmux.TranscriptIn, Source: tmux.SourceNudge, Literal: true, Data: "hello "},
+		{Time: t0.Add(10 * time.Millisecond), Dir: tmux.TranscriptIn, Source: tmux.SourceNudge, Literal: true, Data: "world"},
+		{Time: t0.Add(time.Second), Dir: tmux.TranscriptIn, Source: tmux.SourceNudge, Keys: []string{"Enter"}},
+		{Time: t0.Add(2 * time.Second), Dir: tmux.TranscriptIn, Source: tmux.SourceKeys, Literal: true, Data: "a"},
+		{Time: t0.Add(2 * time.Second), Dir: tmux.TranscriptIn, Source: tmux.SourceNudge, Literal: true, Data: "b"},
+		{Time: t0.Add(time.Minute), Dir: tmux.TranscriptIn, Source: tmux.SourceNudge, Literal: true, Data: "c"},
+	}
+	out := mergeTranscriptChunks(in)
+	if len(out) != 5 {
+		t.Fatalf("got %d entries, want 5: %+v", len(out), out)
+	}
+	if out[0].Data != "hello world" {
+		t.Errorf("merged data = %q, want %q", out[0].Data, "hello world")
+	}
+}
+
+func TestWriteTranscript(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var buf bytes.Buffer
+	writeTranscript(&buf, []tmux.TranscriptEntry{
+		{Time: t0, Dir: tmux.TranscriptOut, Source: tmux.SourceDialog, Data: "Quick safety check\n"},
+		{Time: t0, Dir: tmux.TranscriptIn, Source: tmux.SourceDialog, Keys: []string{"Enter"}},
+		{Time: t0, Dir: tmux.TranscriptIn, Source: tmux.SourceNudge, Literal: true, Data: "check mail"},
+		{Time: t0, Dir: tmux.TranscriptIn, Source: tmux.SourceHuman, Kind: "attach"},
+	})
+	got := buf.String()
+	for _, want := range []string{"← dialog", "Quick safety check", "→ dialog [Enter]", `→ nudge  "check mail"`, "⇢ human  attached"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("transcript output missing %q:\n%s", want, got)
+		}
+	}
+}
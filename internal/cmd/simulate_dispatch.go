@@ -0,0 +1,564 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	simDispatchKeep    bool
+	simDispatchJSON    bool
+	simDispatchTimeout time.Duration
+)
+
+var simulateDispatchCmd = &cobra.Command{
+	Use:     "simulate-dispatch",
+	GroupID: GroupDiag,
+	Short:   "Run a full dispatch lifecycle against a mock agent",
+	Long: `Run the whole dispatch lifecycle end to end in an isolated temporary town,
+with a mock agent standing in for the LLM, and check every stage:
+
+  preflight  git, tmux, bd and dolt are installed
+  town       gt install into a temp dir (own HOME, Dolt port and tmux socket)
+  rig        a local source repo is added as a rig
+  sling      a bead is created and slung to a new polecat running the mock agent
+  readiness  the polecat's session starts and the mock agent comes up
+  work       the mock agent commits fake work on the polecat branch
+  done       the mock agent runs gt done, submitting a merge request
+  merge      the merge request is merged to the source repo's main
+  cleanup    sessions, the Dolt server and the temp town are torn down
+
+Use it after installing to verify your environment, or in CI. It exits
+non-zero at the first stage that fails. No LLM is used and your own towns
+are not touched.
+
+Examples:
+  gt simulate-dispatch
+  gt simulate-dispatch --keep          # keep the temp town for inspection
+  gt simulate-dispatch --json --timeout 5m`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true, // the stage report says what failed
+	RunE:          runSimulateDispatch,
+}
+
+var simulateDispatchAgentCmd = &cobra.Command{
+	Use:    "agent",
+	Short:  "The mock agent run in the simulated polecat's session",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runSimulateDispatchAgent,
+}
+
+var simulateDispatchMergeCmd = &cobra.Command{
+	Use:    "merge <rig>",
+	Short:  "Merge the rig's ready merge requests, standing in for the Refinery agent",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE:   runSimulateDispatchMerge,
+}
+
+func init() {
+	simulateDispatchCmd.Flags().BoolVar(&simDispatchKeep, "keep", false, "Keep the temp town instead of removing it")
+	simulateDispatchCmd.Flags().BoolVar(&simDispatchJSON, "json", false, "Output the stage report as JSON")
+	simulateDispatchCmd.Flags().DurationVar(&simDispatchTimeout, "timeout", 2*time.Minute, "How long to wait for each asynchronous stage")
+	simulateDispatchCmd.AddCommand(simulateDispatchAgentCmd)
+	simulateDispatchCmd.AddCommand(simulateDispatchMergeCmd)
+	rootCmd.AddCommand(simulateDispatchCmd)
+}
+
+const (
+	simRigName   = "simrig"
+	simAgentName = "mock"
+
+	// simWorkFile is the file the mock agent commits.
+	simWorkFile = "SIMULATED_DISPATCH.md"
+
+	// The mock agent's progress markers, in <town>/.runtime/simulate/<polecat>.
+	simMarkerReady     = "ready"
+	simMarkerCommitted = "committed"
+	simMarkerDone      = "done"
+)
+
+// simStage is the outcome of one stage of the simulation.
+type simStage struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Detail  string `json:"detail,omitempty"`
+	Elapsed string `json:"elapsed"`
+}
+
+// simReport is the outcome of the whole simulation.
+type simReport struct {
+	OK     bool       `json:"ok"`
+	Town   string     `json:"town,omitempty"`
+	Stages []simStage `json:"stages"`
+}
+
+// dispatchSim is one simulated dispatch: the temp town and what each stage
+// learned for the next.
+type dispatchSim struct {
+	gt      string
+	root    string
+	town    string
+	source  string
+	env     []string
+	timeout time.Duration
+	out     io.Writer
+
+	bead    string
+	polecat string
+
+	report simReport
+}
+
+// simStep is a stage: it returns a detail line for the report, or an error.
+type simStep struct {
+	name string
+	run  func(*dispatchSim) (string, error)
+}
+
+// simSteps are the lifecycle stages in order. Cleanup is not one of them:
+// it always runs.
+var simSteps = []simStep{
+	{"preflight", (*dispatchSim).preflight},
+	{"town", (*dispatchSim).createTown},
+	{"rig", (*dispatchSim).addRig},
+	{"sling", (*dispatchSim).sling},
+	{"readiness", (*dispatchSim).awaitReady},
+	{"work", (*dispatchSim).awaitWork},
+	{"done", (*dispatchSim).awaitDone},
+	{"merge", (*dispatchSim).merge},
+}
+
+func runSimulateDispatch(cmd *cobra.Command, args []string) error {
+	gt, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding gt binary: %w", err)
+	}
+	s := &dispatchSim{gt: gt, timeout: simDispatchTimeout, out: os.Stdout}
+	if simDispatchJSON {
+		s.out = io.Discard
+	}
+
+	s.run(simSteps)
+
+	if simDispatchJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(s.report); err != nil {
+			return err
+		}
+	} else if s.report.OK {
+		fmt.Printf("\n%s Dispatch lifecycle works\n", style.Bold.Render("✓"))
+	}
+	if !s.report.OK {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// run runs the steps in order, stopping at the first failure, then cleans
+// up.
+func (s *dispatchSim) run(steps []simStep) {
+	s.report.OK = true
+	for _, step := range steps {
+		if !s.stage(step.name, step.run) {
+			break
+		}
+	}
+	s.stage("cleanup", (*dispatchSim).cleanup)
+}
+
+// stage runs one stage and records it; it reports whether it passed.
+func (s *dispatchSim) stage(name string, fn func(*dispatchSim) (string, error)) bool {
+	start := time.Now()
+	detail, err := fn(s)
+	st := simStage{Name: name, OK: err == nil, Detail: detail, Elapsed: time.Since(start).Round(time.Millisecond).String()}
+	if err != nil {
+		st.Detail = err.Error()
+		s.report.OK = false
+	}
+	s.report.Stages = append(s.report.Stages, st)
+
+	mark := style.Success.Render("✓")
+	if !st.OK {
+		mark = style.Error.Render("✗")
+	}
+	fmt.Fprintf(s.out, "%s %-10s %s %s\n", mark, name, st.Detail, style.Dim.Render("("+st.Elapsed+")"))
+	return st.OK
+}
+
+func (s *dispatchSim) preflight() (string, error) {
+	var missing []string
+	for _, bin := range []string{"git", "tmux", "bd", "dolt"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			missing = append(missing, bin)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("not found in PATH: %s", strings.Join(missing, ", "))
+	}
+	return "git, tmux, bd, dolt found; gt is " + s.gt, nil
+}
+
+func (s *dispatchSim) createTown() (string, error) {
+	root, err := os.MkdirTemp("", "gt-simulate-")
+	if err != nil {
+		return "", err
+	}
+	s.root = root
+	// The town's directory name picks its tmux socket, so make it unique.
+	s.town = filepath.Join(root, fmt.Sprintf("simtown%d", os.Getpid()))
+	s.report.Town = s.town
+	home := filepath.Join(root, "home")
+	if err := os.MkdirAll(home, 0755); err != nil {
+		return "", err
+	}
+
+	port, err := freePort()
+	if err != nil {
+		return "", fmt.Errorf("picking a Dolt port: %w", err)
+	}
+	s.env = append(simEnv(), "HOME="+home, "GT_DOLT_PORT="+strconv.Itoa(port))
+
+	// git and dolt read their identity from HOME, which is now empty.
+	name, email := gitIdentity()
+	for _, kv := range [][2]string{{"user.name", name}, {"user.email", email}} {
+		if _, err := s.exec(root, "git", "config", "--global", kv[0], kv[1]); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := s.exec(root, s.gt, "install", s.town, "--git"); err != nil {
+		return "", err
+	}
+
+	// Register the mock agent.
+	path := config.TownSettingsPath(s.town)
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		return "", err
+	}
+	if settings.Agents == nil {
+		settings.Agents = map[string]*config.RuntimeConfig{}
+	}
+	settings.Agents[simAgentName] = &config.RuntimeConfig{
+		Command: s.gt,
+		Args:    []string{"simulate-dispatch", "agent"},
+	}
+	if err := config.SaveTownSettings(path, settings); err != nil {
+		return "", fmt.Errorf("registering mock agent: %w", err)
+	}
+	return fmt.Sprintf("%s (Dolt port %d)", s.town, port), nil
+}
+
+func (s *dispatchSim) addRig() (string, error) {
+	s.source = filepath.Join(s.root, "source")
+	if err := os.MkdirAll(s.source, 0755); err != nil {
+		return "", err
+	}
+	readme := filepath.Join(s.source, "README.md")
+	if err := os.WriteFile(readme, []byte("# Simulated dispatch\n"), 0644); err != nil {
+		return "", err
+	}
+	for _, args := range [][]string{
+		{"init", "--initial-branch=main"},
+		{"add", "README.md"},
+		{"commit", "-m", "Initial commit"},
+		// Let the rig push to main while main is checked out here.
+		{"config", "receive.denyCurrentBranch", "updateInstead"},
+	} {
+		if _, err := s.exec(s.source, "git", args...); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := s.exec(s.town, s.gt, "rig", "add", simRigName, s.source, "--branch", "main"); err != nil {
+		return "", err
+	}
+	return "rig " + simRigName + " from " + s.source, nil
+}
+
+func (s *dispatchSim) sling() (string, error) {
+	out, err := s.exec(filepath.Join(s.town, simRigName), "bd", "create", "--json",
+		"--title=Simulated dispatch", "--description=Commit a file and run gt done.")
+	if err != nil {
+		return "", err
+	}
+	var issue struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(jsonObject(out)), &issue); err != nil || issue.ID == "" {
+		return "", fmt.Errorf("reading created bead from %q", strings.TrimSpace(out))
+	}
+	s.bead = issue.ID
+
+	if _, err := s.exec(s.town, s.gt, "sling", s.bead, simRigName, "--agent", simAgentName, "--no-convoy"); err != nil {
+		return "", err
+	}
+
+	polecats, _ := os.ReadDir(filepath.Join(s.town, simRigName, "polecats"))
+	for _, p := range polecats {
+		if p.IsDir() && !strings.HasPrefix(p.Name(), ".") {
+			s.polecat = p.Name()
+			break
+		}
+	}
+	if s.polecat == "" {
+		return "", fmt.Errorf("sling of %s created no polecat", s.bead)
+	}
+	return fmt.Sprintf("%s → %s/polecats/%s", s.bead, simRigName, s.polecat), nil
+}
+
+func (s *dispatchSim) awaitReady() (string, error) {
+	if _, err := s.awaitMarker(simMarkerReady); err != nil {
+		return "", err
+	}
+	return "mock agent running in " + s.polecat + "'s session", nil
+}
+
+func (s *dispatchSim) awaitWork() (string, error) {
+	if _, err := s.awaitMarker(simMarkerCommitted); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(s.town, simRigName, "polecats", s.polecat)
+	out, err := s.exec(dir, "git", "log", "--oneline", "-1", "--", simWorkFile)
+	if err != nil || strings.TrimSpace(out) == "" {
+		return "", fmt.Errorf("no commit of %s on the polecat branch", simWorkFile)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (s *dispatchSim) awaitDone() (string, error) {
+	result, err := s.awaitMarker(simMarkerDone)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(result, "ok") {
+		return "", fmt.Errorf("gt done failed: %s", result)
+	}
+	out, err := s.exec(s.town, s.gt, "mq", "list", simRigName, "--json")
+	if err != nil {
+		return "", err
+	}
+	if !strings.Contains(out, s.bead) && !strings.Contains(out, s.polecat) {
+		return "", fmt.Errorf("no merge request for %s in the queue", s.bead)
+	}
+	return "merge request submitted", nil
+}
+
+func (s *dispatchSim) merge() (string, error) {
+	if _, err := s.exec(s.town, s.gt, "simulate-dispatch", "merge", simRigName); err != nil {
+		return "", err
+	}
+	if _, err := s.exec(s.source, "git", "cat-file", "-e", "main:"+simWorkFile); err != nil {
+		return "", fmt.Errorf("%s is not on the source repo's main after the merge", simWorkFile)
+	}
+	return simWorkFile + " merged to main", nil
+}
+
+func (s *dispatchSim) cleanup() (string, error) {
+	if s.root == "" {
+		return "nothing to clean up", nil
+	}
+	var problems []string
+	if _, err := os.Stat(filepath.Join(s.town, "mayor")); err == nil {
+		if _, err := s.exec(s.town, s.gt, "down", "--all", "--nuke", "--force"); err != nil {
+			problems = append(problems, "gt down: "+err.Error())
+		}
+		_, _ = s.exec(s.town, s.gt, "dolt", "stop")
+	}
+	if simDispatchKeep {
+		if len(problems) > 0 {
+			return "", fmt.Errorf("%s", strings.Join(problems, "; "))
+		}
+		return "kept " + s.root, nil
+	}
+	if err := os.RemoveAll(s.root); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		return "", fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return "removed " + s.root, nil
+}
+
+// awaitMarker waits for the mock agent to write a progress marker and
+// returns its contents.
+func (s *dispatchSim) awaitMarker(marker string) (string, error) {
+	path := simMarkerPath(s.town, s.polecat, marker)
+	deadline := time.Now().Add(s.timeout)
+	for {
+		if data, err := os.ReadFile(path); err == nil {
+			return strings.TrimSpace(string(data)), nil
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("mock agent did not report %q within %s", marker, s.timeout)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// exec runs a command in the simulated town's environment and returns its
+// combined output; a failure includes the output.
+func (s *dispatchSim) exec(dir, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	c := exec.CommandContext(ctx, name, args...)
+	c.Dir = dir
+	c.Env = s.env
+	out, err := c.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("%s %s: %w\n%s", filepath.Base(name), strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// simEnv is the environment without Gas Town's variables, so nothing from
+// the caller's town leaks into the simulated one.
+func simEnv() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "GT_") || strings.HasPrefix(kv, "BD_") || strings.HasPrefix(kv, "BEADS_") ||
+			strings.HasPrefix(kv, "HOME=") || strings.HasPrefix(kv, "TMUX=") || strings.HasPrefix(kv, "TMUX_PANE=") {
+			continue
+		}
+		env = append(env, kv)
+	}
+	return env
+}
+
+// gitIdentity returns the caller's git identity, or a placeholder.
+func gitIdentity() (name, email string) {
+	name, email = "Gas Town Simulation", "simulate@gastown.invalid"
+	if out, err := exec.Command("git", "config", "user.name").Output(); err == nil && strings.TrimSpace(string(out)) != "" {
+		name = strings.TrimSpace(string(out))
+	}
+	if out, err := exec.Command("git", "config", "user.email").Output(); err == nil && strings.TrimSpace(string(out)) != "" {
+		email = strings.TrimSpace(string(out))
+	}
+	return name, email
+}
+
+// freePort returns a TCP port nothing is listening on.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// jsonObject returns the JSON object in command output that may have
+// warnings before it.
+func jsonObject(out string) string {
+	if i := strings.Index(out, "{"); i >= 0 {
+		return out[i:]
+	}
+	return out
+}
+
+// simMarkerPath is where the mock agent in a polecat writes a marker.
+func simMarkerPath(townRoot, polecat, marker string) string {
+	return filepath.Join(townRoot, ".runtime", "simulate", polecat, marker)
+}
+
+// runSimulateDispatchAgent is the mock agent. It announces itself, commits
+// a file in its worktree, runs gt done, and records each step in a marker
+// for the harness. Then it idles like an agent at its prompt until its
+// session is killed.
+func runSimulateDispatchAgent(cmd *cobra.Command, args []string) error {
+	polecat := os.Getenv("GT_POLECAT")
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil || polecat == "" {
+		return fmt.Errorf("the mock agent runs in a simulated polecat (GT_POLECAT=%q): %v", polecat, err)
+	}
+	mark := func(marker, content string) {
+		path := simMarkerPath(townRoot, polecat, marker)
+		_ = os.MkdirAll(filepath.Dir(path), 0755)
+		_ = os.WriteFile(path, []byte(content+"\n"), 0644)
+	}
+
+	fmt.Println("mock agent ready")
+	mark(simMarkerReady, time.Now().UTC().Format(time.RFC3339))
+
+	work := fmt.Sprintf("Simulated work by %s at %s.\n", polecat, time.Now().UTC().Format(time.RFC3339))
+	if err := os.WriteFile(simWorkFile, []byte(work), 0644); err != nil {
+		return err
+	}
+	for _, gitArgs := range [][]string{{"add", simWorkFile}, {"commit", "-m", "Simulated dispatch work"}} {
+		if out, err := exec.Command("git", gitArgs...).CombinedOutput(); err != nil {
+			mark(simMarkerDone, fmt.Sprintf("failed: git %s: %v: %s", gitArgs[0], err, strings.TrimSpace(string(out))))
+			return err
+		}
+	}
+	mark(simMarkerCommitted, "ok")
+	fmt.Println("mock agent committed work; running gt done")
+
+	gt, err := os.Executable()
+	if err != nil {
+		gt = "gt"
+	}
+	// gt done may kill this session once the merge request is in, so the
+	// marker is written by a shell that outlives it.
+	marker := simMarkerPath(townRoot, polecat, simMarkerDone)
+	script := `out=$("$0" done 2>&1); if [ $? -eq 0 ]; then echo ok > "$1"; else printf 'failed: %s\n' "$out" > "$1"; fi`
+	done := exec.Command("sh", "-c", script, gt, marker)
+	done.Stdout, done.Stderr = os.Stdout, os.Stderr
+	_ = done.Run()
+
+	fmt.Print("> ")
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		fmt.Print("> ")
+	}
+	return nil
+}
+
+// runSimulateDispatchMerge merges the rig's ready merge requests the way
+// the Refinery agent would, without an agent.
+func runSimulateDispatchMerge(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	mrs, err := eng.ListReadyMRs()
+	if err != nil {
+		return fmt.Errorf("listing ready merge requests: %w", err)
+	}
+	if len(mrs) == 0 {
+		return fmt.Errorf("no ready merge requests in %s", args[0])
+	}
+	for _, mr := range mrs {
+		result := eng.ProcessMRInfo(cmd.Context(), mr)
+		if !result.Success {
+			eng.HandleMRInfoFailure(mr, result)
+			return fmt.Errorf("merging %s: %s", mr.ID, result.Error)
+		}
+		eng.HandleMRInfoSuccess(mr, result)
+		fmt.Printf("merged %s (%s)\n", mr.ID, mr.Branch)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+//go:build integration
+
+package cmd
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+)
+
+// TestSimulateDispatchE2E runs the whole dispatch lifecycle against the mock
+// agent through the gt binary.
+func TestSimulateDispatchE2E(t *testing.T) {
+	for _, bin := range []string{"bd", "dolt", "tmux"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			t.Skipf("%s not installed", bin)
+		}
+	}
+	gtBinary := buildGT(t)
+
+	cmd := exec.Command(gtBinary, "simulate-dispatch", "--json", "--timeout", "3m")
+	cmd.Env = cleanGTEnv()
+	out, err := cmd.Output()
+	var report simReport
+	if jsonErr := json.Unmarshal(out, &report); jsonErr != nil {
+		t.Fatalf("reading report: %v (err %v)\n%s", jsonErr, err, out)
+	}
+	for _, st := range report.Stages {
+		if !st.OK {
+			t.Errorf("stage %s failed: %s", st.Name, st.Detail)
+		}
+	}
+	if err != nil || !report.OK {
+		t.Fatalf("simulate-dispatch failed: %v", err)
+	}
+}
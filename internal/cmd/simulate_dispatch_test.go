@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDispatchSim_StopsAtFirstFailureAndCleansUp(t *testing.T) {
+	var ran []string
+	step := func(name string, err error) simStep {
+		return simStep{name, func(*dispatchSim) (string, error) {
+			ran = append(ran, name)
+			return name + " ok", err
+		}}
+	}
+	s := &dispatchSim{out: io.Discard}
+	s.run([]simStep{step("a", nil), step("b", errors.New("boom")), step("c", nil)})
+
+	if strings.Join(ran, ",") != "a,b" {
+		t.Errorf("ran %v, want a,b", ran)
+	}
+	if s.report.OK {
+		t.Error("report should fail")
+	}
+	var names []string
+	for _, st := range s.report.Stages {
+		names = append(names, st.Name)
+	}
+	if strings.Join(names, ",") != "a,b,cleanup" {
+		t.Errorf("stages = %v, want a,b,cleanup", names)
+	}
+	if st := s.report.Stages[1]; st.OK || st.Detail != "boom" {
+		t.Errorf("failed stage = %+v", st)
+	}
+}
+
+func TestSimEnv_DropsTownVariables(t *testing.T) {
+	t.Setenv("GT_ROLE", "mayor")
+	t.Setenv("BEADS_DIR", "/tmp/x")
+	t.Setenv("TMUX", "/tmp/tmux-0/default,1,0")
+	for _, kv := range simEnv() {
+		if strings.HasPrefix(kv, "GT_") || strings.HasPrefix(kv, "BEADS_") || strings.HasPrefix(kv, "TMUX=") || strings.HasPrefix(kv, "HOME=") {
+			t.Errorf("simEnv kept %s", kv)
+		}
+	}
+}
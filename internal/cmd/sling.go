@@ -12,8 +12,10 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/featureflag"
 	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -93,6 +95,34 @@ Compare:
 
 The propulsion principle: if it's on your hook, YOU RUN IT.
 
+Handing Off Local Changes (--from-diff):
+  Create an issue from half-finished local work and dispatch a polecat to
+  continue it. The issue carries the patch; the polecat applies it first.
+  gt sling --from-diff greenplace -s "Finish retry logic"   # staged + unstaged changes
+  gt sling --from-diff=fix.patch greenplace                 # a patch file
+  git diff main | gt sling --from-diff=- greenplace         # patch on stdin
+
+  --message becomes the issue's statement of intent; untracked files are
+  not included and are listed as a warning.
+
+Review Dispatch (--review):
+  Dispatch an agent to review an existing merge request. The reviewer's
+  worktree starts at the MR branch, its issue carries the diff and the
+  source issue's acceptance criteria, and it records its verdict with
+  'gt mq approve' or 'gt mq reject'.
+  gt sling --review gp-mr-abc123              # Rig resolved from the MR prefix
+  gt sling --review gp-mr-abc123 greenplace
+
+Capability Requirements (--needs):
+  Rig settings can tag agents with capabilities (languages, repos they
+  know, hardware, model traits) under "capabilities": tags for all the
+  rig's polecats, tags added by agent presets, and tags per crew member.
+  --needs dispatches only to an agent with every listed tag: the rig's
+  polecats, polecats running a tagged agent, or a free crew member.
+  gt sling gt-abc greenplace --needs go,db-migrations
+  gt sling gt-abc greenplace --needs long-context   # May pick the agent
+  gt sling gt-abc greenplace/crew/max --needs gpu   # Only checks max
+
 Batch Slinging:
   gt sling gt-abc gt-def gt-ghi gastown   # Sling multiple beads to a rig
   gt sling gt-abc gt-def gastown --max-concurrent 3  # Limit concurrent spawns
@@ -128,6 +158,9 @@ var (
 	slingBaseBranch    string // --base-branch: override base branch for polecat worktree
 	slingRalph         bool   // --ralph: enable Ralph Wiggum loop mode for multi-step workflows
 	slingFormula       string // --formula: override formula for dispatch (default: mol-polecat-work)
+	slingFromDiff      string // --from-diff: create the bead from local changes or a patch file
+	slingReview        string // --review: dispatch a reviewer for this merge request
+	slingNeeds         string // --needs: capabilities the target agent must have
 )
 
 func init() {
@@ -154,6 +187,10 @@ func init() {
 	slingCmd.Flags().StringVar(&slingBaseBranch, "base-branch", "", "Override base branch for polecat worktree (e.g., 'develop', 'release/v2')")
 	slingCmd.Flags().BoolVar(&slingRalph, "ralph", false, "Enable Ralph Wiggum loop mode (fresh context per step, for multi-step workflows)")
 	slingCmd.Flags().StringVar(&slingFormula, "formula", "", "Formula to apply (default: mol-polecat-work for polecat targets)")
+	slingCmd.Flags().StringVar(&slingFromDiff, "from-diff", "", "Create an issue from local changes (or a patch file, - for stdin) and sling it to a rig")
+	slingCmd.Flags().Lookup("from-diff").NoOptDefVal = slingFromDiffLocal
+	slingCmd.Flags().StringVar(&slingReview, "review", "", "Dispatch an agent to review this merge request (gt sling --review <mr-id> [rig])")
+	slingCmd.Flags().StringVar(&slingNeeds, "needs", "", "Capabilities the target agent must have, comma-separated (e.g., go,db-migrations)")
 
 	rootCmd.AddCommand(slingCmd)
 }
@@ -242,6 +279,65 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		args[i] = strings.TrimRight(args[i], "/")
 	}
 
+	// --from-diff: turn half-finished local work into a new bead, then sling
+	// it like any other: gt sling --from-diff <rig> becomes gt sling <new-bead> <rig>.
+	if slingFromDiff != "" {
+		if len(args) != 1 {
+			return fmt.Errorf("--from-diff takes exactly one argument, the target rig: gt sling --from-diff <rig>")
+		}
+		if slingFromDiff == "-" && slingStdin {
+			return fmt.Errorf("--from-diff=- and --stdin both read stdin; use one")
+		}
+		rigName, isRig := IsRigName(args[0])
+		if !isRig {
+			return fmt.Errorf("--from-diff requires a rig target: '%s' is not a known rig", args[0])
+		}
+		handoff, err := readDiffHandoff(slingFromDiff)
+		if err != nil {
+			return err
+		}
+		beadID, err := createDiffHandoffIssue(townRoot, rigName, handoff)
+		if err != nil {
+			return err
+		}
+		if beadID == "" { // dry run
+			return nil
+		}
+		args = []string{beadID, rigName}
+	}
+
+	// --review: dispatch a reviewer for an existing MR. The reviewer's
+	// worktree starts at the MR branch and it files no MR of its own.
+	if slingReview != "" {
+		if slingFromDiff != "" {
+			return fmt.Errorf("--review and --from-diff cannot be combined")
+		}
+		if len(args) > 1 {
+			return fmt.Errorf("--review takes at most one argument, the rig: gt sling --review <mr-id> [rig]")
+		}
+		rigName := ""
+		if len(args) == 1 {
+			var isRig bool
+			if rigName, isRig = IsRigName(args[0]); !isRig {
+				return fmt.Errorf("--review requires a rig target: '%s' is not a known rig", args[0])
+			}
+		} else if rigName = resolveRigForBead(townRoot, slingReview); rigName == "" {
+			return fmt.Errorf("cannot resolve rig for %s\nSpecify explicitly: gt sling --review %s <rig>", slingReview, slingReview)
+		}
+		beadID, mr, err := createReviewIssue(rigName, slingReview)
+		if err != nil {
+			return err
+		}
+		if beadID == "" { // dry run
+			return nil
+		}
+		if slingBaseBranch == "" {
+			slingBaseBranch = mr.Branch
+		}
+		slingNoMerge = true
+		args = []string{beadID, rigName}
+	}
+
 	// Validate target format early, before any dispatch path (bead, formula, batch)
 	// can trigger resolveTarget side-effects like polecat spawning.
 	if len(args) > 1 {
@@ -250,6 +346,14 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	// --needs: route to an agent with the required capabilities, before any
+	// dispatch path resolves the target.
+	if slingNeeds != "" {
+		if err := applySlingNeeds(townRoot, args); err != nil {
+			return err
+		}
+	}
+
 	// Config-driven dispatch mode: check scheduler.max_polecats
 	deferred, deferErr := shouldDeferDispatch()
 	if deferErr != nil {
@@ -540,9 +644,20 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		return fmt.Errorf("refusing to sling deferred bead %s: %q\nDeferred work should not consume polecat slots. Use --force to override", beadID, info.Title)
 	}
 
+	// Guard against dispatching a probable duplicate of work already in
+	// flight, so two polecats don't end up fixing the same bug.
+	if info.Status == "open" && !slingForce {
+		if dup := findInFlightDuplicate(beadID, info); dup != nil {
+			return fmt.Errorf("refusing to sling bead %s: it looks like a duplicate of %s (%q), already %s to %s\nClose one as a duplicate, or use --force if they are different work",
+				beadID, dup.ID, dup.Title, dup.Status, dup.Assignee)
+		}
+	}
+
 	originalStatus := info.Status
 	originalAssignee := info.Assignee
 	force := slingForce // local copy to avoid mutating package-level flag
+	// Configured feature flags consulted, recorded on the sling event.
+	var slingFeatures []featureflag.Decision
 	if (info.Status == "pinned" || info.Status == "hooked" || info.Status == "in_progress") && !force {
 		// Auto-force when hooked/in_progress agent's session is confirmed dead (gt-pqf9x, GH#1380).
 		// This eliminates the #1 friction in convoy feeding: stale hooks from
@@ -550,6 +665,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		// IMPORTANT: Stale-hook check must run BEFORE idempotency check so that
 		// a dead polecat with a matching target triggers re-sling, not a no-op.
 		if (info.Status == "hooked" || info.Status == "in_progress") && info.Assignee != "" && isHookedAgentDeadFn(info.Assignee) {
+			if err := checkAutoRecovery(townRoot, beadID, info.Status, info.Assignee, &slingFeatures); err != nil {
+				return err
+			}
 			fmt.Printf("%s Hooked agent %s has no active session, auto-forcing re-sling...\n",
 				style.Warning.Render("⚠"), info.Assignee)
 			force = true
@@ -597,6 +715,19 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	// Claim the bead before resolving the target, which can spawn a polecat.
+	// The status checks above read a snapshot; the claim keeps a concurrent
+	// dispatch (e.g. mayor autopilot) from assigning the bead between that
+	// read and the hook.
+	var claim *slingClaim
+	if !slingDryRun {
+		claim, err = acquireSlingClaim(townRoot, beadID, info.Assignee, force)
+		if err != nil {
+			return err
+		}
+		defer claim.abandon()
+	}
+
 	// TODO(scheduler-unify): Migrate single-sling rig dispatch to use executeSling().
 	// The inline logic below duplicates executeSling's 12-step flow. Batch sling
 	// and scheduler dispatch already use the unified path. Single-sling is deferred
@@ -852,7 +983,10 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 
 	// Log sling event to activity feed
 	actor := detectActor()
-	_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
+	if newPolecatInfo != nil {
+		slingFeatures = append(slingFeatures, newPolecatInfo.Features...)
+	}
+	_ = events.LogFeed(events.TypeSling, actor, slingEventPayload(beadID, targetAgent, slingFeatures))
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Skip if hook was already set atomically during polecat spawn - avoids "agent bead not found"
@@ -872,6 +1006,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		AttachedFormula:  formulaName,
 		NoMerge:          slingNoMerge,
 	}
+	if newPolecatInfo != nil {
+		fieldUpdates.AgentVersion = newPolecatInfo.AgentVersion
+	}
 	if err := storeFieldsInBead(beadID, fieldUpdates); err != nil {
 		// Warn but don't fail - polecat will still complete work
 		fmt.Printf("%s Could not store fields in bead: %v\n", style.Dim.Render("Warning:"), err)
@@ -884,6 +1021,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	// The bead is assigned: its claim now belongs to the agent.
+	claim.handOff(targetAgent)
+
 	// Start delayed dog session now that hook is set
 	// This ensures dog sees the hook when gt prime runs on session start
 	if delayedDogInfo != nil {
@@ -904,6 +1044,7 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 			// Without rollback, next sling attempt fails with "bead already hooked" (gt-jn40ft).
 			fmt.Printf("%s Session failed, rolling back spawned polecat %s...\n", style.Warning.Render("⚠"), newPolecatInfo.PolecatName)
 			rollbackSlingArtifactsFn(newPolecatInfo, beadID, hookWorkDir)
+			claim.release()
 			return fmt.Errorf("starting polecat session: %w", err)
 		}
 		targetPane = pane
@@ -925,6 +1066,7 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		// message arrives before Claude has fully started - see issue #115)
 		sessionName := getSessionFromPane(targetPane)
 		if sessionName != "" {
+			_ = session.SetSessionIssue(townRoot, sessionName, beadID)
 			if err := ensureAgentReady(sessionName); err != nil {
 				// Non-fatal: warn and continue, agent will discover work via gt prime
 				fmt.Printf("%s Could not verify agent ready: %v\n", style.Dim.Render("○"), err)
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// slingClaim is the dispatch claim a sling holds on a bead from its status
+// checks until the bead is hooked. The sling flock only serializes slings on
+// one machine for as long as the process runs; the claim is recorded in
+// beads, so batch and scheduler dispatch (which skip the flock) and a
+// concurrent human sling all see it.
+type slingClaim struct {
+	bd        *beads.Beads
+	beadID    string
+	holder    string
+	handedOff bool
+}
+
+// slingClaimHolder identifies this sling process as a claim holder. The pid
+// tells apart two slings by the same actor.
+func slingClaimHolder() string {
+	return fmt.Sprintf("%s (sling pid %d)", detectActor(), os.Getpid())
+}
+
+// acquireSlingClaim claims beadID for this sling. force takes over a claim
+// held by anyone; a claim held by the bead's current assignee is taken over
+// too, since the status checks have already decided whether to replace it.
+// Only a claim held by someone else fails the sling: if the claim can't be
+// recorded, the sling warns and goes ahead unclaimed (nil, nil), as it did
+// before claims existed.
+func acquireSlingClaim(townRoot, beadID, assignee string, force bool) (*slingClaim, error) {
+	c := &slingClaim{
+		bd:     beads.New(beads.ResolveHookDir(townRoot, beadID, "")),
+		beadID: beadID,
+		holder: slingClaimHolder(),
+	}
+	err := c.bd.ClaimIssue(beadID, c.holder, beads.ClaimOptions{
+		Force:     force,
+		Supersede: []string{assignee},
+	})
+	var held *beads.ClaimHeldError
+	if errors.As(err, &held) {
+		return nil, fmt.Errorf("%w\nAnother dispatch is assigning it; retry after it finishes, or use --force", err)
+	}
+	if err != nil {
+		fmt.Printf("%s Could not claim %s: %v\n", style.Dim.Render("Warning:"), beadID, err)
+		return nil, nil
+	}
+	return c, nil
+}
+
+// handOff passes the claim to the agent the bead was hooked to, whose
+// session keeps renewing it. Failure is only a warning: the bead is hooked,
+// so the status checks already guard it.
+func (c *slingClaim) handOff(agent string) {
+	if c == nil {
+		return
+	}
+	err := c.bd.ClaimIssue(c.beadID, agent, beads.ClaimOptions{Supersede: []string{c.holder}})
+	if err != nil {
+		fmt.Printf("%s Could not hand claim on %s to %s: %v\n", style.Dim.Render("Warning:"), c.beadID, agent, err)
+		return
+	}
+	c.holder = agent
+	c.handedOff = true
+}
+
+// release drops the claim, whoever it was handed to. Used when a dispatch
+// is rolled back.
+func (c *slingClaim) release() {
+	if c == nil {
+		return
+	}
+	if err := c.bd.ReleaseIssueClaim(c.beadID, c.holder); err != nil {
+		fmt.Printf("%s Could not release claim on %s: %v\n", style.Dim.Render("Warning:"), c.beadID, err)
+	}
+}
+
+// abandon releases the claim unless it was handed off. Defer it right
+// after acquiring the claim.
+func (c *slingClaim) abandon() {
+	if c != nil && !c.handedOff {
+		c.release()
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/featureflag"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 )
@@ -123,12 +124,17 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 	// Save explicit force state before dead-agent auto-force, so the deferred
 	// gate below still requires an explicit --force for deferred beads.
 	explicitForce := params.Force
+	var features []featureflag.Decision // configured feature flags consulted, recorded on the sling event
 
 	if (info.Status == "pinned" || info.Status == "hooked" || info.Status == "in_progress") && !params.Force {
 		// Auto-force when hooked/in_progress agent's session is confirmed dead (gt-npzy, GH#1380).
 		// Mirrors the dead-agent detection in runSling (sling.go) so that
 		// programmatic dispatch also handles stale hooks from nuked polecats.
 		if (info.Status == "hooked" || info.Status == "in_progress") && info.Assignee != "" && isHookedAgentDeadFn(info.Assignee) {
+			if err := checkAutoRecovery(townRoot, params.BeadID, info.Status, info.Assignee, &features); err != nil {
+				result.ErrMsg = "auto-recovery off"
+				return result, err
+			}
 			fmt.Printf("  %s Hooked agent %s has no active session, auto-forcing dispatch...\n",
 				style.Warning.Render("⚠"), info.Assignee)
 			params.Force = true
@@ -146,6 +152,15 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 		return result, fmt.Errorf("bead %s is deferred (use --force to override)", params.BeadID)
 	}
 
+	// Claim the bead so a concurrent sling can't assign it while this
+	// dispatch spawns and hooks (see acquireSlingClaim).
+	claim, err := acquireSlingClaim(townRoot, params.BeadID, info.Assignee, params.Force)
+	if err != nil {
+		result.ErrMsg = "claimed by another dispatch"
+		return result, err
+	}
+	defer claim.abandon()
+
 	// Send LIFECYCLE:Shutdown to the witness when force-stealing a bead from a
 	// live polecat. Without this, the old polecat becomes a zombie — still running
 	// but unaware it lost its hook. Mirrors the same logic in runSling (sling.go).
@@ -295,7 +310,8 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 
 	// 8. Log sling event
 	actor := detectActor()
-	_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadToHook, targetAgent))
+	features = append(features, spawnInfo.Features...)
+	_ = events.LogFeed(events.TypeSling, actor, slingEventPayload(beadToHook, targetAgent, features))
 
 	// 9. Update agent hook_bead state
 	updateAgentHookBead(targetAgent, beadToHook, hookWorkDir, beadsDir)
@@ -308,6 +324,7 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 		AttachedFormula:  params.FormulaName,
 		NoMerge:          params.NoMerge,
 		Mode:             params.Mode,
+		AgentVersion:     spawnInfo.AgentVersion,
 	}
 	// Use beadToHook for the update target (may differ from beadID when formula-on-bead)
 	if err := storeFieldsInBead(beadToHook, fieldUpdates); err != nil {
@@ -319,11 +336,15 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 		updateAgentMode(targetAgent, params.Mode, hookWorkDir, beadsDir)
 	}
 
+	// The bead is assigned: its claim now belongs to the polecat.
+	claim.handOff(targetAgent)
+
 	// 11. Start polecat session
 	pane, err := spawnInfo.StartSession()
 	if err != nil {
 		fmt.Printf("  %s Could not start session: %v, cleaning up partial state...\n", style.Dim.Render("✗"), err)
 		rollbackSlingArtifactsFn(spawnInfo, beadToHook, hookWorkDir)
+		claim.release()
 		result.ErrMsg = fmt.Sprintf("session failed: %v", err)
 		return result, fmt.Errorf("starting polecat session: %w", err)
 	}
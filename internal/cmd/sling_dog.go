@@ -6,15 +6,17 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/dog"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
-// maxDogPoolSize is the maximum number of dogs allowed in the pool.
-// Pool dispatch auto-creates dogs up to this limit.
-const maxDogPoolSize = 4
+// maxDogPoolSize is the default maximum number of dogs allowed in the pool.
+// Pool dispatch auto-creates dogs up to this limit unless patrols.dog_pool
+// in mayor/daemon.json sets a different max_size.
+const maxDogPoolSize = daemon.DefaultMaxDogPoolSize
 
 // IsDogTarget checks if target is a dog target pattern.
 // Returns the dog name (or empty for pool dispatch) and true if it's a dog target.
@@ -128,15 +130,16 @@ func DispatchToDog(dogName string, opts DogDispatchOptions) (*DogDispatchInfo, e
 			if listErr != nil {
 				return nil, fmt.Errorf("listing dogs: %w", listErr)
 			}
-			if len(dogs) >= maxDogPoolSize {
-				return nil, fmt.Errorf("no idle dogs available (pool at max %d, all busy)", maxDogPoolSize)
+			maxSize := daemon.DogPoolMaxSize(daemon.LoadPatrolConfig(townRoot))
+			if len(dogs) >= maxSize {
+				return nil, fmt.Errorf("no idle dogs available (pool at max %d, all busy)", maxSize)
 			}
-			newName := generateDogName(mgr)
+			newName := mgr.NextName()
 			targetDog, err = mgr.Add(newName)
 			if err != nil {
 				return nil, fmt.Errorf("creating dog %s: %w", newName, err)
 			}
-			fmt.Printf("✓ Auto-created dog %s (no idle dogs, pool %d/%d)\n", newName, len(dogs)+1, maxDogPoolSize)
+			fmt.Printf("✓ Auto-created dog %s (no idle dogs, pool %d/%d)\n", newName, len(dogs)+1, maxSize)
 			spawned = true
 		}
 	}
@@ -212,31 +215,3 @@ func (d *DogDispatchInfo) StartDelayedSession() (string, error) {
 	d.sessionDelayed = false
 	return pane, nil
 }
-
-// generateDogName creates a unique dog name for pool expansion.
-func generateDogName(mgr *dog.Manager) string {
-	// Use Greek alphabet for dog names
-	names := []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel"}
-
-	dogs, _ := mgr.List()
-	existing := make(map[string]bool)
-	for _, d := range dogs {
-		existing[d.Name] = true
-	}
-
-	for _, name := range names {
-		if !existing[name] {
-			return name
-		}
-	}
-
-	// Fallback: numbered dogs
-	for i := 1; i <= 100; i++ {
-		name := fmt.Sprintf("dog%d", i)
-		if !existing[name] {
-			return name
-		}
-	}
-
-	return fmt.Sprintf("dog%d", len(dogs)+1)
-}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/featureflag"
+)
+
+// checkAutoRecovery consults the auto_recovery feature flag before work
+// hooked to a dead agent is re-slung without --force. Configured decisions
+// are appended to features so the dispatch records them.
+func checkAutoRecovery(townRoot, beadID, status, assignee string, features *[]featureflag.Decision) error {
+	rigName, _, _ := strings.Cut(assignee, "/")
+	d := featureflag.Evaluate(townRoot, featureflag.AutoRecovery, rigName, beadID)
+	if d.Source != featureflag.SourceDefault {
+		*features = append(*features, d)
+	}
+	if !d.Enabled {
+		return fmt.Errorf("bead %s is %s to %s, which has no active session, but auto-recovery is off (%s)\nUse --force to re-sling", beadID, status, assignee, d.Reason)
+	}
+	return nil
+}
+
+// slingEventPayload is the sling event payload, with the feature flags that
+// shaped the dispatch.
+func slingEventPayload(beadID, target string, features []featureflag.Decision) map[string]interface{} {
+	payload := events.SlingPayload(beadID, target)
+	if len(features) > 0 {
+		payload["features"] = featureflag.Summary(features)
+	}
+	return payload
+}
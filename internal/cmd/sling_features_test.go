@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/featureflag"
+)
+
+func TestCheckAutoRecovery(t *testing.T) {
+	town := t.TempDir()
+
+	var features []featureflag.Decision
+	if err := checkAutoRecovery(town, "gt-abc", "hooked", "gastown/polecats/Toast", &features); err != nil {
+		t.Fatalf("unconfigured flag blocked recovery: %v", err)
+	}
+	if len(features) != 0 {
+		t.Errorf("default decision recorded: %v", features)
+	}
+
+	t.Setenv(featureflag.EnvVar(featureflag.AutoRecovery), "off")
+	err := checkAutoRecovery(town, "gt-abc", "hooked", "gastown/polecats/Toast", &features)
+	if err == nil || !strings.Contains(err.Error(), "auto-recovery is off") {
+		t.Fatalf("err = %v, want auto-recovery off", err)
+	}
+	payload := slingEventPayload("gt-abc", "gastown/polecats/Nux", features)
+	if got := payload["features"]; got != "auto_recovery=off" {
+		t.Errorf("payload features = %v, want auto_recovery=off", got)
+	}
+}
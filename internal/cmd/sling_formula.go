@@ -198,6 +198,9 @@ func runSlingFormula(args []string) error {
 		Args:            slingArgs,
 		AttachedFormula: formulaName,
 	}
+	if resolved.NewPolecatInfo != nil {
+		fieldUpdates.AgentVersion = resolved.NewPolecatInfo.AgentVersion
+	}
 	if err := storeFieldsInBead(wispRootID, fieldUpdates); err != nil {
 		fmt.Printf("%s Could not store fields in bead: %v\n", style.Dim.Render("Warning:"), err)
 	} else if slingArgs != "" {
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// slingFromDiffLocal is the --from-diff value used when the flag is given
+// without a patch file: take the local staged and unstaged changes.
+const slingFromDiffLocal = "local"
+
+// maxInlinePatchBytes is the largest patch embedded directly in the issue
+// description. Larger patches are written under .runtime/patches and the
+// issue points at the file.
+const maxInlinePatchBytes = 64 * 1024
+
+// diffHandoff is half-finished local work to be handed to a polecat.
+type diffHandoff struct {
+	Patch     string   // unified diff
+	Source    string   // where the patch came from, for the issue text
+	BaseRef   string   // commit the patch applies to, if known
+	Files     []string // files the patch touches
+	Untracked []string // untracked files left out of a local diff
+}
+
+// readDiffHandoff reads the patch named by --from-diff: a patch file, "-"
+// for stdin, or slingFromDiffLocal for the current repo's changes against
+// HEAD (staged and unstaged).
+func readDiffHandoff(from string) (*diffHandoff, error) {
+	h := &diffHandoff{Source: from}
+	switch from {
+	case slingFromDiffLocal:
+		out, err := exec.Command("git", "diff", "HEAD", "--binary").Output()
+		if err != nil {
+			return nil, fmt.Errorf("reading local changes (git diff HEAD): %w", err)
+		}
+		h.Patch = string(out)
+		h.Source = "local changes"
+		if head, err := exec.Command("git", "rev-parse", "HEAD").Output(); err == nil {
+			h.BaseRef = strings.TrimSpace(string(head))
+		}
+		if out, err := exec.Command("git", "ls-files", "--others", "--exclude-standard").Output(); err == nil {
+			h.Untracked = strings.Fields(string(out))
+		}
+	case "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading patch from stdin: %w", err)
+		}
+		h.Patch = string(data)
+		h.Source = "stdin"
+	default:
+		data, err := os.ReadFile(from) //nolint:gosec // G304: user-supplied patch path
+		if err != nil {
+			return nil, fmt.Errorf("reading patch: %w", err)
+		}
+		h.Patch = string(data)
+	}
+
+	if strings.TrimSpace(h.Patch) == "" {
+		return nil, fmt.Errorf("no changes to hand off from %s", h.Source)
+	}
+	h.Files = patchFiles(h.Patch)
+	return h, nil
+}
+
+// patchFiles returns the files a unified diff touches, in order.
+func patchFiles(patch string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(patch, "\n") {
+		if !strings.HasPrefix(line, "+++ ") && !strings.HasPrefix(line, "--- ") {
+			continue
+		}
+		name := strings.TrimSpace(line[4:])
+		if i := strings.IndexByte(name, '\t'); i >= 0 {
+			name = name[:i]
+		}
+		if name == "/dev/null" {
+			continue
+		}
+		if strings.HasPrefix(name, "a/") || strings.HasPrefix(name, "b/") {
+			name = name[2:]
+		}
+		if !seen[name] {
+			seen[name] = true
+			files = append(files, name)
+		}
+	}
+	return files
+}
+
+// diffHandoffTitle derives an issue title from the files a patch touches.
+func diffHandoffTitle(files []string) string {
+	switch len(files) {
+	case 0:
+		return "Continue work from patch"
+	case 1:
+		return "Continue work on " + files[0]
+	default:
+		return fmt.Sprintf("Continue work on %s (+%d more)", files[0], len(files)-1)
+	}
+}
+
+// diffHandoffDescription writes the issue body: the intent, how to apply
+// the starting patch, and the patch itself (inline or as a file path).
+func diffHandoffDescription(h *diffHandoff, intent, patchPath string) string {
+	var sb strings.Builder
+	if intent != "" {
+		sb.WriteString(intent)
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString("## Starting point\n\n")
+	fmt.Fprintf(&sb, "This work was started by hand and handed off from %s. ", h.Source)
+	sb.WriteString("Apply the patch below before doing anything else, then continue from there.\n\n")
+	if h.BaseRef != "" {
+		fmt.Fprintf(&sb, "The patch was taken against commit %s. ", h.BaseRef)
+		sb.WriteString("If it does not apply cleanly, use `git apply --3way`.\n\n")
+	}
+	if len(h.Files) > 0 {
+		sb.WriteString("Files touched:\n")
+		for _, f := range h.Files {
+			fmt.Fprintf(&sb, "- %s\n", f)
+		}
+		sb.WriteString("\n")
+	}
+	if len(h.Untracked) > 0 {
+		sb.WriteString("Untracked files were not included in the patch:\n")
+		for _, f := range h.Untracked {
+			fmt.Fprintf(&sb, "- %s\n", f)
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Patch\n\n")
+	if patchPath != "" {
+		fmt.Fprintf(&sb, "The patch is too large to inline. Apply it with:\n\n    git apply %s\n", patchPath)
+		return sb.String()
+	}
+	sb.WriteString("Save this block to a file and `git apply` it:\n\n```diff\n")
+	sb.WriteString(strings.TrimRight(h.Patch, "\n"))
+	sb.WriteString("\n```\n")
+	return sb.String()
+}
+
+// savePatch writes a patch too large to inline under the town's
+// .runtime/patches directory, named by content hash.
+func savePatch(townRoot, patch string) (string, error) {
+	sum := sha256.Sum256([]byte(patch))
+	path := filepath.Join(townRoot, ".runtime", "patches", hex.EncodeToString(sum[:])[:12]+".patch")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("creating patch dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(patch), 0644); err != nil { //nolint:gosec // G306: patch is not secret
+		return "", fmt.Errorf("writing patch: %w", err)
+	}
+	return path, nil
+}
+
+// createDiffHandoffIssue creates an issue in rigName describing the
+// half-finished work in h and returns its ID. In dry-run mode nothing is
+// written and the returned ID is empty.
+func createDiffHandoffIssue(townRoot, rigName string, h *diffHandoff) (string, error) {
+	title := slingSubject
+	if title == "" {
+		title = diffHandoffTitle(h.Files)
+	}
+
+	if slingDryRun {
+		fmt.Printf("Would create issue in %s: %s\n", rigName, title)
+		fmt.Printf("  patch: %d bytes from %s, %d file(s)\n", len(h.Patch), h.Source, len(h.Files))
+		return "", nil
+	}
+
+	patchPath := ""
+	if len(h.Patch) > maxInlinePatchBytes {
+		var err error
+		if patchPath, err = savePatch(townRoot, h.Patch); err != nil {
+			return "", err
+		}
+	}
+
+	issue, err := beads.New(filepath.Join(townRoot, rigName)).Create(beads.CreateOptions{
+		Title:       title,
+		Type:        "task",
+		Priority:    2,
+		Description: diffHandoffDescription(h, slingMessage, patchPath),
+		Actor:       detectSender(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating issue: %w", err)
+	}
+	fmt.Printf("%s Created %s from %s: %s\n", style.SuccessPrefix, issue.ID, h.Source, title)
+	for _, f := range h.Untracked {
+		fmt.Printf("%s Untracked file not included: %s\n", style.WarningPrefix, f)
+	}
+	return issue.ID, nil
+}
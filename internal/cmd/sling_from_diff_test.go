@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testHandoffPatch = `diff --git a/internal/retry.go b/internal/retry.go
+index 1111111..2222222 100644
+--- a/internal/retry.go
++++ b/internal/retry.go
+@@ -1,3 +1,4 @@
+ package internal
++// TODO: backoff
+diff --git a/docs/retry.md b/docs/retry.md
+new file mode 100644
+--- /dev/null
++++ b/docs/retry.md
+@@ -0,0 +1 @@
++# Retry
+`
+
+func TestPatchFiles(t *testing.T) {
+	got := patchFiles(testHandoffPatch)
+	want := []string{"internal/retry.go", "docs/retry.md"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("patchFiles = %v, want %v", got, want)
+	}
+}
+
+func TestDiffHandoffTitle(t *testing.T) {
+	tests := []struct {
+		files []string
+		want  string
+	}{
+		{nil, "Continue work from patch"},
+		{[]string{"a.go"}, "Continue work on a.go"},
+		{[]string{"a.go", "b.go", "c.go"}, "Continue work on a.go (+2 more)"},
+	}
+	for _, tt := range tests {
+		if got := diffHandoffTitle(tt.files); got != tt.want {
+			t.Errorf("diffHandoffTitle(%v) = %q, want %q", tt.files, got, tt.want)
+		}
+	}
+}
+
+func TestDiffHandoffDescription(t *testing.T) {
+	h := &diffHandoff{
+		Patch:     testHandoffPatch,
+		Source:    "local changes",
+		BaseRef:   "abc123",
+		Files:     patchFiles(testHandoffPatch),
+		Untracked: []string{"scratch.txt"},
+	}
+
+	inline := diffHandoffDescription(h, "Finish the retry loop", "")
+	for _, want := range []string{"Finish the retry loop", "abc123", "- internal/retry.go", "- scratch.txt", "```diff\n", "+// TODO: backoff"} {
+		if !strings.Contains(inline, want) {
+			t.Errorf("inline description missing %q:\n%s", want, inline)
+		}
+	}
+
+	attached := diffHandoffDescription(h, "", "/town/.runtime/patches/x.patch")
+	if !strings.Contains(attached, "git apply /town/.runtime/patches/x.patch") {
+		t.Errorf("attached description missing apply command:\n%s", attached)
+	}
+	if strings.Contains(attached, "+// TODO: backoff") {
+		t.Errorf("attached description should not inline the patch:\n%s", attached)
+	}
+}
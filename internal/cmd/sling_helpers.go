@@ -79,6 +79,7 @@ type beadInfo struct {
 	Status       string           `json:"status"`
 	Assignee     string           `json:"assignee"`
 	Description  string           `json:"description"`
+	Notes        string           `json:"notes,omitempty"`
 	Labels       []string         `json:"labels,omitempty"`
 	Dependencies []beads.IssueDep `json:"dependencies,omitempty"`
 	IssueType    string           `json:"issue_type,omitempty"`
@@ -236,6 +237,22 @@ func getBeadInfo(beadID string) (*beadInfo, error) {
 	return &infos[0], nil
 }
 
+// findInFlightDuplicate returns a hooked or in-progress issue that is a
+// probable duplicate of the bead being slung, or nil. Lookup failures are
+// ignored so a failing bd list never blocks dispatch.
+func findInFlightDuplicate(beadID string, info *beadInfo) *beads.Issue {
+	dups, err := beads.New(resolveBeadDir(beadID)).FindDuplicates(beadID, info.Title, info.Description)
+	if err != nil {
+		return nil
+	}
+	for _, d := range dups {
+		if (d.Issue.Status == "hooked" || d.Issue.Status == "in_progress") && d.Issue.Assignee != "" {
+			return d.Issue
+		}
+	}
+	return nil
+}
+
 // beadFieldUpdates holds all the fields that need to be stored in a bead's description.
 // This enables a single read-modify-write cycle instead of sequential independent updates,
 // eliminating the race condition where concurrent writers could overwrite each other's fields.
@@ -249,6 +266,7 @@ type beadFieldUpdates struct {
 	ConvoyID         string // Convoy bead ID (e.g., "hq-cv-abc")
 	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local"
 	ConvoyOwned      bool   // Convoy has gt:owned label (caller-managed lifecycle)
+	AgentVersion     string // Agent CLI version of the spawned polecat
 }
 
 // storeFieldsInBead performs a single read-modify-write to update all attachment fields
@@ -319,6 +337,9 @@ func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
 	if updates.ConvoyOwned {
 		fields.ConvoyOwned = true
 	}
+	if updates.AgentVersion != "" {
+		fields.AgentVersion = updates.AgentVersion
+	}
 
 	// Write back once
 	newDesc := beads.SetAttachmentFields(issue, fields)
@@ -405,7 +426,10 @@ func ensureAgentReady(sessionName string) error {
 	} else {
 		// Agent not running yet - wait for it to start (shell → program transition)
 		if err := t.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
-			return fmt.Errorf("waiting for agent to start: %w", err)
+			// A runtime under a wrapper shell is up; anything else can't take input.
+			if d, ok := tmux.DiagnoseWait(err); !ok || d.Cause != tmux.WaitWrapperNotExeced {
+				return fmt.Errorf("waiting for agent to start: %w", err)
+			}
 		}
 	}
 
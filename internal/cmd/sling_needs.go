@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// capableTarget is where work needing some capabilities can go.
+type capableTarget struct {
+	Target string // Sling target: the rig, or rig/crew/<name>
+	Agent  string // Agent polecats should run ("" = unchanged)
+}
+
+// resolveCapableTarget routes a sling with --needs. A rig target is kept if
+// its polecats have the capabilities, switched to an agent that adds them
+// (unless agent was given explicitly), or, with allowCrew, redirected to a
+// free crew member who has them. Explicit polecat and crew targets are only
+// checked.
+func resolveCapableTarget(townRoot, target string, needs []string, agent string, allowCrew bool) (capableTarget, error) {
+	rigName, _, _ := strings.Cut(target, "/")
+	if _, isRig := IsRigName(rigName); !isRig {
+		return capableTarget{}, fmt.Errorf("--needs applies to rig, polecat and crew targets, not %q", target)
+	}
+	rigPath := filepath.Join(townRoot, rigName)
+	var caps *config.CapabilitiesConfig
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil {
+		caps = settings.Capabilities
+	}
+
+	explicitAgent := agent != ""
+	if !explicitAgent {
+		agent, _ = config.ResolveRoleAgentName(constants.RolePolecat, townRoot, rigPath)
+	}
+
+	parts := strings.Split(target, "/")
+	switch {
+	case len(parts) == 1:
+		return pickCapableTarget(rigName, caps, needs, agent, explicitAgent, allowCrew, func(name string) bool {
+			return crewMemberFree(townRoot, rigName, name)
+		})
+	case len(parts) == 3 && parts[1] == "polecats":
+		if missing := config.MissingCapabilities(caps.PolecatCapabilities(agent), needs); len(missing) > 0 {
+			return capableTarget{}, fmt.Errorf("polecat %s (agent %s) lacks capabilities: %s", target, agent, strings.Join(missing, ", "))
+		}
+		return capableTarget{Target: target}, nil
+	case len(parts) == 3 && parts[1] == "crew":
+		if missing := config.MissingCapabilities(caps.CrewCapabilities(parts[2]), needs); len(missing) > 0 {
+			return capableTarget{}, fmt.Errorf("crew member %s lacks capabilities: %s", target, strings.Join(missing, ", "))
+		}
+		return capableTarget{Target: target}, nil
+	}
+	return capableTarget{}, fmt.Errorf("--needs applies to rig, polecat and crew targets, not %q", target)
+}
+
+// pickCapableTarget chooses where work for rigName needing needs goes:
+// polecats running agent, then polecats running another tagged agent (unless
+// the agent is fixed), then a free crew member (if allowCrew). The error
+// says what each candidate lacks.
+func pickCapableTarget(rigName string, caps *config.CapabilitiesConfig, needs []string, agent string, agentFixed, allowCrew bool, crewFree func(string) bool) (capableTarget, error) {
+	var reasons []string
+
+	missing := config.MissingCapabilities(caps.PolecatCapabilities(agent), needs)
+	if len(missing) == 0 {
+		return capableTarget{Target: rigName}, nil
+	}
+	reasons = append(reasons, fmt.Sprintf("polecats (agent %s): missing %s", agent, strings.Join(missing, ", ")))
+
+	if !agentFixed {
+		for _, name := range caps.AgentNames() {
+			if name == agent {
+				continue
+			}
+			missing := config.MissingCapabilities(caps.PolecatCapabilities(name), needs)
+			if len(missing) == 0 {
+				return capableTarget{Target: rigName, Agent: name}, nil
+			}
+			reasons = append(reasons, fmt.Sprintf("polecats (agent %s): missing %s", name, strings.Join(missing, ", ")))
+		}
+	}
+
+	if allowCrew {
+		for _, name := range caps.CrewNames() {
+			missing := config.MissingCapabilities(caps.CrewCapabilities(name), needs)
+			switch {
+			case len(missing) > 0:
+				reasons = append(reasons, fmt.Sprintf("crew %s: missing %s", name, strings.Join(missing, ", ")))
+			case !crewFree(name):
+				reasons = append(reasons, fmt.Sprintf("crew %s: capable but busy or not running", name))
+			default:
+				return capableTarget{Target: rigName + "/crew/" + name}, nil
+			}
+		}
+	}
+
+	return capableTarget{}, fmt.Errorf("no free agent in rig %s has capabilities %s:\n  %s\nTag agents under \"capabilities\" in %s/settings/config.json, retry when crew is free, or drop --needs",
+		rigName, strings.Join(needs, ", "), strings.Join(reasons, "\n  "), rigName)
+}
+
+// crewMemberFree reports whether a crew member can take work now: their
+// session is running and nothing is hooked to them.
+func crewMemberFree(townRoot, rigName, name string) bool {
+	running, err := tmux.NewTmux().HasSession(crewSessionName(rigName, name))
+	if err != nil || !running {
+		return false
+	}
+	bd := beads.New(filepath.Join(townRoot, rigName))
+	assignee := rigName + "/crew/" + name
+	for _, status := range []string{beads.StatusHooked, "in_progress"} {
+		issues, err := bd.List(beads.ListOptions{Status: status, Assignee: assignee, Priority: -1, Limit: 1})
+		if err != nil || len(issues) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// applySlingNeeds rewrites the sling target (the last argument) and agent
+// for --needs. Crew fallback is only possible for a single bead.
+func applySlingNeeds(townRoot string, args []string) error {
+	needs := config.ParseCapabilities(slingNeeds)
+	if len(needs) == 0 {
+		return nil
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("--needs requires a rig, polecat or crew target: gt sling <bead> <rig> --needs %s", slingNeeds)
+	}
+	target := args[len(args)-1]
+	picked, err := resolveCapableTarget(townRoot, target, needs, slingAgent, len(args) == 2)
+	if err != nil {
+		return err
+	}
+	if picked.Agent != "" {
+		fmt.Printf("%s Using agent %s for capabilities %s\n", style.Bold.Render("→"), picked.Agent, strings.Join(needs, ", "))
+		slingAgent = picked.Agent
+	}
+	if picked.Target != target {
+		fmt.Printf("%s No polecat in %s has %s; slinging to %s\n", style.Bold.Render("→"), target, strings.Join(needs, ", "), picked.Target)
+		args[len(args)-1] = picked.Target
+	}
+	return nil
+}
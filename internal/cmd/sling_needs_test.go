@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestPickCapableTarget(t *testing.T) {
+	caps := &config.CapabilitiesConfig{
+		Polecats: []string{"go"},
+		Agents:   map[string][]string{"claude-opus": {"long-context"}},
+		Crew: map[string][]string{
+			"max":  {"go", "gpu"},
+			"emma": {"go", "gpu"},
+		},
+	}
+	free := func(name string) bool { return name == "max" }
+
+	tests := []struct {
+		name       string
+		needs      []string
+		agentFixed bool
+		allowCrew  bool
+		want       capableTarget
+		wantErr    string
+	}{
+		{name: "polecats have it", needs: []string{"go"}, want: capableTarget{Target: "gastown"}},
+		{name: "switch agent", needs: []string{"go", "long-context"}, want: capableTarget{Target: "gastown", Agent: "claude-opus"}},
+		{name: "fixed agent falls to error", needs: []string{"long-context"}, agentFixed: true, wantErr: "polecats (agent claude): missing long-context"},
+		{name: "free crew", needs: []string{"gpu"}, allowCrew: true, want: capableTarget{Target: "gastown/crew/max"}},
+		{name: "no crew fallback", needs: []string{"gpu"}, wantErr: "no free agent in rig gastown has capabilities gpu"},
+		{name: "busy crew reported", needs: []string{"gpu", "rust"}, allowCrew: true, wantErr: "crew emma: missing rust"},
+	}
+	for _, tt := range tests {
+		got, err := pickCapableTarget("gastown", caps, tt.needs, "claude", tt.agentFixed, tt.allowCrew, free)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: err = %v, want containing %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %+v, %v; want %+v", tt.name, got, err, tt.want)
+		}
+	}
+}
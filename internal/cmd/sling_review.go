@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// maxReviewDiffBytes caps the diff embedded in a review issue. The
+// reviewer's worktree is checked out at the MR branch, so a truncated diff
+// loses nothing.
+const maxReviewDiffBytes = 64 * 1024
+
+// createReviewIssue creates an issue asking an agent to review merge
+// request mrID in rigName, and returns the issue ID and the MR. In dry-run
+// mode nothing is written and the returned ID is empty.
+func createReviewIssue(rigName, mrID string) (string, *refinery.MergeRequest, error) {
+	mgr, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return "", nil, err
+	}
+	mr, err := mgr.FindMR(mrID)
+	if err != nil {
+		if err == refinery.ErrMRNotFound {
+			return "", nil, fmt.Errorf("merge request '%s' not found in rig '%s'", mrID, rigName)
+		}
+		return "", nil, fmt.Errorf("finding merge request: %w", err)
+	}
+	if mr.IsClosed() {
+		return "", nil, fmt.Errorf("merge request %s is already closed (%s)", mr.ID, mr.CloseReason)
+	}
+
+	bd := beads.New(r.BeadsPath())
+	var source *beads.Issue
+	if mr.IssueID != "" {
+		if source, err = bd.Show(mr.IssueID); err != nil {
+			fmt.Printf("%s Could not load source issue %s: %v\n", style.WarningPrefix, mr.IssueID, err)
+		}
+	}
+
+	target := mr.TargetBranch
+	if target == "" {
+		target = r.DefaultBranch()
+	}
+	diff := ""
+	if repoGit, err := getRigGit(r.Path); err == nil {
+		diff, err = repoGit.DiffFromMergeBase("origin/"+target, "origin/"+mr.Branch)
+		if err != nil {
+			fmt.Printf("%s Could not diff %s against %s: %v\n", style.WarningPrefix, mr.Branch, target, err)
+		}
+	}
+
+	title := reviewIssueTitle(mr, source)
+	if slingDryRun {
+		fmt.Printf("Would create review issue in %s: %s\n", rigName, title)
+		fmt.Printf("  worktree: %s, diff: %d bytes\n", mr.Branch, len(diff))
+		return "", mr, nil
+	}
+
+	issue, err := bd.Create(beads.CreateOptions{
+		Title:       title,
+		Type:        "task",
+		Priority:    2,
+		Description: reviewIssueDescription(rigName, target, mr, source, diff),
+		Actor:       detectSender(),
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("creating review issue: %w", err)
+	}
+	fmt.Printf("%s Created review issue %s for %s\n", style.SuccessPrefix, issue.ID, mr.ID)
+	return issue.ID, mr, nil
+}
+
+// reviewIssueTitle names a review issue after the work under review.
+func reviewIssueTitle(mr *refinery.MergeRequest, source *beads.Issue) string {
+	if source != nil && source.Title != "" {
+		return fmt.Sprintf("Review %s: %s", mr.ID, source.Title)
+	}
+	return fmt.Sprintf("Review %s (%s)", mr.ID, mr.Branch)
+}
+
+// reviewIssueDescription writes the reviewer's brief: what to review, the
+// acceptance criteria, the diff, and how to record the verdict.
+func reviewIssueDescription(rigName, target string, mr *refinery.MergeRequest, source *beads.Issue, diff string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Review merge request %s before it is merged. Do not change the code under review.\n\n", mr.ID)
+	fmt.Fprintf(&sb, "- Branch: %s (your worktree starts at this branch)\n", mr.Branch)
+	fmt.Fprintf(&sb, "- Target: %s\n", target)
+	if mr.Worker != "" {
+		fmt.Fprintf(&sb, "- Author: %s\n", mr.Worker)
+	}
+	if mr.IssueID != "" {
+		fmt.Fprintf(&sb, "- Issue: %s\n", mr.IssueID)
+	}
+	sb.WriteString("\n")
+
+	if source != nil {
+		if source.Description != "" {
+			sb.WriteString("## Intent\n\n")
+			sb.WriteString(strings.TrimSpace(source.Description))
+			sb.WriteString("\n\n")
+		}
+		if source.AcceptanceCriteria != "" {
+			sb.WriteString("## Acceptance criteria\n\n")
+			sb.WriteString(strings.TrimSpace(source.AcceptanceCriteria))
+			sb.WriteString("\n\n")
+		}
+	}
+
+	sb.WriteString("## Verdict\n\n")
+	sb.WriteString("Record exactly one verdict, with your review comments:\n\n")
+	fmt.Fprintf(&sb, "    gt mq approve %s %s --stdin <<'EOF'\n    <comments>\n    EOF\n\n", rigName, mr.ID)
+	fmt.Fprintf(&sb, "    gt mq reject %s %s --notify --stdin <<'EOF'\n    <what must change>\n    EOF\n\n", rigName, mr.ID)
+	sb.WriteString("Then run `gt done`.\n\n")
+
+	sb.WriteString("## Diff\n\n")
+	if diff == "" {
+		fmt.Fprintf(&sb, "Diff unavailable; run `git diff origin/%s...HEAD` in your worktree.\n", target)
+		return sb.String()
+	}
+	if len(diff) > maxReviewDiffBytes {
+		fmt.Fprintf(&sb, "Truncated to %d KB; run `git diff origin/%s...HEAD` for the rest.\n\n", maxReviewDiffBytes/1024, target)
+		diff = diff[:maxReviewDiffBytes]
+	}
+	sb.WriteString("```diff\n")
+	sb.WriteString(strings.TrimRight(diff, "\n"))
+	sb.WriteString("\n```\n")
+	return sb.String()
+}
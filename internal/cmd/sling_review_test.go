@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+func TestReviewIssueDescription(t *testing.T) {
+	mr := &refinery.MergeRequest{
+		ID:      "gp-mr-abc",
+		Branch:  "polecat/Nux/gp-xyz",
+		Worker:  "Nux",
+		IssueID: "gp-xyz",
+	}
+	source := &beads.Issue{
+		ID:                 "gp-xyz",
+		Title:              "Add retry backoff",
+		Description:        "Retries hammer the API.",
+		AcceptanceCriteria: "- [ ] backoff is exponential",
+	}
+
+	got := reviewIssueDescription("greenplace", "main", mr, source, "+func backoff() {}\n")
+	for _, want := range []string{
+		"polecat/Nux/gp-xyz",
+		"Retries hammer the API.",
+		"- [ ] backoff is exponential",
+		"gt mq approve greenplace gp-mr-abc",
+		"gt mq reject greenplace gp-mr-abc",
+		"+func backoff() {}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("description missing %q:\n%s", want, got)
+		}
+	}
+
+	if title := reviewIssueTitle(mr, source); title != "Review gp-mr-abc: Add retry backoff" {
+		t.Errorf("reviewIssueTitle = %q", title)
+	}
+	if title := reviewIssueTitle(mr, nil); title != "Review gp-mr-abc (polecat/Nux/gp-xyz)" {
+		t.Errorf("reviewIssueTitle without source = %q", title)
+	}
+}
+
+func TestReviewIssueDescription_TruncatesLargeDiff(t *testing.T) {
+	mr := &refinery.MergeRequest{ID: "gp-mr-abc", Branch: "polecat/Nux/gp-xyz"}
+	diff := strings.Repeat("+x\n", maxReviewDiffBytes)
+
+	got := reviewIssueDescription("greenplace", "main", mr, nil, diff)
+	if !strings.Contains(got, "Truncated") {
+		t.Error("expected truncation notice")
+	}
+	if len(got) > maxReviewDiffBytes+4096 {
+		t.Errorf("description is %d bytes, want diff capped near %d", len(got), maxReviewDiffBytes)
+	}
+}
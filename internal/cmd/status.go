@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -20,8 +21,12 @@ import (
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/endpoints"
+	"github.com/steveyegge/gastown/internal/featureflag"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/platform"
+	"github.com/steveyegge/gastown/internal/recording"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -61,15 +66,17 @@ func init() {
 
 // TownStatus represents the overall status of the workspace.
 type TownStatus struct {
-	Name     string         `json:"name"`
-	Location string         `json:"location"`
-	Overseer *OverseerInfo  `json:"overseer,omitempty"` // Human operator
-	Daemon   *ServiceInfo   `json:"daemon,omitempty"`   // Daemon status
-	Dolt     *DoltInfo      `json:"dolt,omitempty"`     // Dolt server status
-	Tmux     *TmuxInfo      `json:"tmux,omitempty"`     // Tmux server status
-	Agents   []AgentRuntime `json:"agents"`             // Global agents (Mayor, Deacon)
-	Rigs     []RigStatus    `json:"rigs"`
-	Summary  StatusSum      `json:"summary"`
+	Name      string         `json:"name"`
+	Location  string         `json:"location"`
+	Overseer  *OverseerInfo  `json:"overseer,omitempty"`  // Human operator
+	Daemon    *ServiceInfo   `json:"daemon,omitempty"`    // Daemon status
+	Dolt      *DoltInfo      `json:"dolt,omitempty"`      // Dolt server status
+	Tmux      *TmuxInfo      `json:"tmux,omitempty"`      // Tmux server status
+	Endpoints []EndpointInfo `json:"endpoints,omitempty"` // Registered ports and sockets
+	Features  []FeatureInfo  `json:"features,omitempty"`  // Configured or overridden feature flags
+	Agents    []AgentRuntime `json:"agents"`              // Global agents (Mayor, Deacon)
+	Rigs      []RigStatus    `json:"rigs"`
+	Summary   StatusSum      `json:"summary"`
 }
 
 // ServiceInfo represents a background service status.
@@ -97,6 +104,23 @@ type TmuxInfo struct {
 	SessionCount int    `json:"session_count"`            // Number of sessions
 }
 
+// FeatureInfo is a feature flag the town configures or the environment
+// overrides.
+type FeatureInfo struct {
+	Name   string `json:"name"`
+	State  string `json:"state"`  // "off", "on", "on (rigs: gastown, 25%)"
+	Source string `json:"source"` // "config" or the overriding GT_FEATURE_<NAME>
+}
+
+// EndpointInfo is a port or socket from the town's endpoint registry.
+type EndpointInfo struct {
+	Name        string   `json:"name"`
+	Address     string   `json:"address"`
+	PID         int      `json:"pid,omitempty"`
+	Stale       bool     `json:"stale,omitempty"`        // Registering process has exited
+	ClaimedWith []string `json:"claimed_with,omitempty"` // Other towns registering the same address
+}
+
 // OverseerInfo represents the human operator's identity and status.
 type OverseerInfo struct {
 	Name       string `json:"name"`
@@ -135,6 +159,9 @@ type RigStatus struct {
 	Hooks        []AgentHookInfo `json:"hooks,omitempty"`
 	Agents       []AgentRuntime  `json:"agents,omitempty"` // Runtime state of all agents in rig
 	MQ           *MQSummary      `json:"mq,omitempty"`     // Merge queue summary
+
+	TranscriptBytes    int64 `json:"transcript_bytes,omitempty"`     // Recordings and input transcripts of the rig's sessions
+	TranscriptCapBytes int64 `json:"transcript_cap_bytes,omitempty"` // recording.max_size_mb (0 = no cap)
 }
 
 // MQSummary represents the merge queue status for a rig.
@@ -235,27 +262,27 @@ func findAgentCmdline(panePid string) string {
 		return cmdline
 	}
 
-	// Walk children (shell → agent)
-	childrenPath := "/proc/" + panePid + "/task/" + panePid + "/children"
-	childrenBytes, err := os.ReadFile(childrenPath)
+	pid, err := strconv.Atoi(panePid)
 	if err != nil {
-		return cmdline // return whatever the pane process is
+		return cmdline
 	}
 
-	children := strings.Fields(string(childrenBytes))
-	for _, childPid := range children {
-		childCmd := readCmdline(childPid)
+	// Walk children (shell → agent), then grandchildren (cgroup-wrap → agent)
+	children, err := platform.Children(pid)
+	if err != nil {
+		return cmdline // return whatever the pane process is
+	}
+	for _, child := range children {
+		childCmd := readCmdline(strconv.Itoa(child))
 		if isAgentCmdline(childCmd) {
 			return childCmd
 		}
-		// Check grandchildren (cgroup-wrap → agent)
-		gcPath := "/proc/" + childPid + "/task/" + childPid + "/children"
-		gcBytes, err := os.ReadFile(gcPath)
+		grandchildren, err := platform.Children(child)
 		if err != nil {
 			continue
 		}
-		for _, gcPid := range strings.Fields(string(gcBytes)) {
-			gcCmd := readCmdline(gcPid)
+		for _, gc := range grandchildren {
+			gcCmd := readCmdline(strconv.Itoa(gc))
 			if isAgentCmdline(gcCmd) {
 				return gcCmd
 			}
@@ -287,14 +314,18 @@ func isAgentCmdline(cmdline string) bool {
 	return false
 }
 
-// readCmdline reads /proc/<pid>/cmdline and returns it as a space-joined string.
+// readCmdline returns the argv of pid joined with null bytes, matching the
+// /proc/<pid>/cmdline layout on every platform.
 func readCmdline(pid string) string {
-	data, err := os.ReadFile("/proc/" + pid + "/cmdline")
-	if err != nil || len(data) == 0 {
+	n, err := strconv.Atoi(pid)
+	if err != nil {
 		return ""
 	}
-	// cmdline uses null bytes as separators
-	return string(data)
+	argv, err := platform.Cmdline(n)
+	if err != nil || len(argv) == 0 {
+		return ""
+	}
+	return strings.Join(argv, "\x00")
 }
 
 // extractBaseName gets the base command name from a null-separated cmdline.
@@ -817,6 +848,9 @@ func gatherStatus() (TownStatus, error) {
 	}
 	status.Tmux = tmuxInfo
 
+	status.Endpoints = gatherEndpoints(townRoot)
+	status.Features = gatherFeatures(townRoot)
+
 	var wg sync.WaitGroup
 
 	// Fetch global agents in parallel with rig discovery
@@ -826,6 +860,8 @@ func gatherStatus() (TownStatus, error) {
 		status.Agents = discoverGlobalAgents(allSessions, allAgentBeads, allHookBeads, mailRouter, statusFast)
 	}()
 
+	transcriptBytes := gatherTranscriptBytes(townRoot)
+
 	// Process all rigs in parallel
 	rigActiveHooks := make([]int, len(rigs)) // Track hooks per rig for thread safety
 	for i, r := range rigs {
@@ -874,6 +910,15 @@ func gatherStatus() (TownStatus, error) {
 				rs.MQ = getMQSummary(r)
 			}
 
+			rs.TranscriptBytes = transcriptBytes[r.Name]
+			if rs.TranscriptBytes > 0 {
+				var rc *config.RecordingConfig
+				if settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path)); err == nil {
+					rc = settings.Recording
+				}
+				rs.TranscriptCapBytes = rc.MaxBytes()
+			}
+
 			status.Rigs[idx] = rs
 		}(i, r)
 	}
@@ -913,6 +958,63 @@ func gatherStatus() (TownStatus, error) {
 	return status, nil
 }
 
+// gatherEndpoints lists the town's registered endpoints, flagging those
+// another town on this machine also claims.
+func gatherEndpoints(townRoot string) []EndpointInfo {
+	reg, err := endpoints.Load(townRoot)
+	if err != nil {
+		return nil
+	}
+	var infos []EndpointInfo
+	for _, e := range reg.Endpoints {
+		info := EndpointInfo{Name: e.Name, Address: e.Address(), PID: e.PID, Stale: !e.Live()}
+		if !info.Stale {
+			conflicts, _ := endpoints.Conflicts(townRoot, e)
+			for _, c := range conflicts {
+				info.ClaimedWith = append(info.ClaimedWith, c.Town)
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// gatherFeatures lists the known feature flags that differ from the default:
+// configured in town settings or overridden by GT_FEATURE_<NAME>.
+func gatherFeatures(townRoot string) []FeatureInfo {
+	flags := featureflag.Flags(townRoot)
+	var infos []FeatureInfo
+	for _, k := range featureflag.Known {
+		d := featureflag.Decide(k.Name, flags[k.Name], "", "", os.Getenv)
+		switch d.Source {
+		case featureflag.SourceEnv:
+			state := "off"
+			if d.Enabled {
+				state = "on"
+			}
+			infos = append(infos, FeatureInfo{Name: k.Name, State: state, Source: featureflag.EnvVar(k.Name)})
+		case featureflag.SourceConfig:
+			infos = append(infos, FeatureInfo{Name: k.Name, State: featureflag.Describe(flags[k.Name]), Source: d.Source})
+		}
+	}
+	return infos
+}
+
+// gatherTranscriptBytes sums the transcript storage of each rig's sessions.
+func gatherTranscriptBytes(townRoot string) map[string]int64 {
+	files, err := recording.StoredFiles(townRoot, nil)
+	if err != nil {
+		return nil
+	}
+	bytes := make(map[string]int64)
+	for _, f := range files {
+		if rigName := sessionRig(f.Session); rigName != "" {
+			bytes[rigName] += f.Size
+		}
+	}
+	return bytes
+}
+
 func outputStatusJSON(status TownStatus) error {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
@@ -972,6 +1074,39 @@ func outputStatusText(w io.Writer, status TownStatus) error {
 		fmt.Fprintln(w)
 	}
 
+	// Where each registered port and socket lives
+	if len(status.Endpoints) > 0 {
+		fmt.Fprintf(w, "%s ", style.Bold.Render("Endpoints:"))
+		var parts []string
+		for _, e := range status.Endpoints {
+			switch {
+			case e.Stale:
+				parts = append(parts, fmt.Sprintf("%s %s", e.Name, style.Dim.Render(fmt.Sprintf("(%s, stale)", e.Address))))
+			case len(e.ClaimedWith) > 0:
+				parts = append(parts, fmt.Sprintf("%s %s", e.Name, style.Bold.Render(fmt.Sprintf("(%s ⚠ also claimed by %s)", e.Address, strings.Join(e.ClaimedWith, ", ")))))
+			default:
+				parts = append(parts, fmt.Sprintf("%s %s", e.Name, style.Dim.Render(fmt.Sprintf("(%s)", e.Address))))
+			}
+		}
+		fmt.Fprintf(w, "%s\n", strings.Join(parts, "  "))
+		fmt.Fprintln(w)
+	}
+
+	// Feature flags gating risky subsystems, when any differ from the default
+	if len(status.Features) > 0 {
+		fmt.Fprintf(w, "%s ", style.Bold.Render("Features:"))
+		var parts []string
+		for _, f := range status.Features {
+			state := f.State
+			if f.Source != featureflag.SourceConfig {
+				state += ", " + f.Source
+			}
+			parts = append(parts, fmt.Sprintf("%s %s", f.Name, style.Dim.Render("("+state+")")))
+		}
+		fmt.Fprintf(w, "%s\n", strings.Join(parts, "  "))
+		fmt.Fprintln(w)
+	}
+
 	// Role icons - uses centralized emojis from constants package
 	roleIcons := map[string]string{
 		constants.RoleMayor:    constants.EmojiMayor,
@@ -1110,6 +1245,15 @@ func outputStatusText(w io.Writer, status TownStatus) error {
 		if len(witnesses) == 0 && len(refineries) == 0 && len(crews) == 0 && len(polecats) == 0 {
 			fmt.Fprintf(w, "   %s\n", style.Dim.Render("(no agents)"))
 		}
+
+		// Transcript storage, against the rig's cap
+		if r.TranscriptBytes > 0 {
+			usage := formatBytes(r.TranscriptBytes)
+			if r.TranscriptCapBytes > 0 {
+				usage += " of " + formatBytes(r.TranscriptCapBytes)
+			}
+			fmt.Fprintf(w, "📼 %s\n", style.Dim.Render("Transcripts: "+usage))
+		}
 		fmt.Fprintln(w)
 	}
 
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Tail command flags
+var (
+	tailCount    int
+	tailFollow   bool
+	tailGrep     string
+	tailInterval time.Duration
+)
+
+// tailWindow is how many lines each follow poll captures. Output scrolling
+// past more than this between polls is printed whole.
+const tailWindow = 500
+
+func init() {
+	rootCmd.AddCommand(tailCmd)
+	tailCmd.Flags().IntVarP(&tailCount, "lines", "n", 20, "Number of existing lines to show first")
+	tailCmd.Flags().BoolVarP(&tailFollow, "follow", "f", false, "Keep streaming new output until the session ends")
+	tailCmd.Flags().StringVar(&tailGrep, "grep", "", "Only show lines matching this regular expression")
+	tailCmd.Flags().DurationVar(&tailInterval, "interval", 500*time.Millisecond, "Poll interval with --follow")
+}
+
+var tailCmd = &cobra.Command{
+	Use:     "tail <rig/polecat>",
+	GroupID: GroupComm,
+	Short:   "Stream an agent session's output without attaching",
+	Long: `Show the last lines of an agent session's output and, with --follow,
+stream new lines as they appear.
+
+Unlike 'gt session at', tail never attaches to tmux, so there is no risk of
+typing into the agent's prompt. Output is read with incremental pane
+captures (the pane's pipe is left free for 'gt session record'); lines the
+agent redraws in place, such as spinners, show up as new lines.
+
+Supports both polecats and crew workers:
+  - Polecats: rig/name format (e.g., greenplace/furiosa)
+  - Crew: rig/crew/name format (e.g., beads/crew/dave)
+
+Examples:
+  gt tail greenplace/furiosa                  # Last 20 lines
+  gt tail greenplace/furiosa -f               # Follow until Ctrl-C or session end
+  gt tail greenplace/furiosa -f --grep 'FAIL|panic'
+  gt tail beads/crew/dave -n 0 -f             # Only new output`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTail,
+}
+
+func runTail(cmd *cobra.Command, args []string) error {
+	var filter *regexp.Regexp
+	if tailGrep != "" {
+		re, err := regexp.Compile(tailGrep)
+		if err != nil {
+			return fmt.Errorf("invalid --grep pattern: %w", err)
+		}
+		filter = re
+	}
+
+	rigName, polecatName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	mgr, _, err := getSessionManager(rigName)
+	if err != nil {
+		return err
+	}
+	sessionID := mgr.SessionName(polecatName)
+	if strings.HasPrefix(polecatName, "crew/") {
+		sessionID = session.CrewSessionName(session.PrefixFor(rigName), strings.TrimPrefix(polecatName, "crew/"))
+	}
+	capture := func(lines int) ([]string, error) {
+		out, err := mgr.CaptureSession(sessionID, lines)
+		if err != nil {
+			return nil, err
+		}
+		return trimTrailingBlank(strings.Split(out, "\n")), nil
+	}
+
+	show := func(lines []string) {
+		for _, line := range lines {
+			if filter == nil || filter.MatchString(line) {
+				fmt.Println(line)
+			}
+		}
+	}
+
+	prev, err := capture(tailWindow)
+	if err != nil {
+		if errors.Is(err, polecat.ErrSessionNotFound) {
+			return fmt.Errorf("session %s is not running", sessionID)
+		}
+		return fmt.Errorf("capturing output: %w", err)
+	}
+	show(prev[max(0, len(prev)-tailCount):])
+	if !tailFollow {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ticker := time.NewTicker(tailInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		cur, err := capture(tailWindow)
+		if err != nil {
+			if errors.Is(err, polecat.ErrSessionNotFound) {
+				fmt.Fprintf(os.Stderr, "%s Session %s ended\n", style.Dim.Render("○"), sessionID)
+				return nil
+			}
+			return fmt.Errorf("capturing output: %w", err)
+		}
+		show(tailNewLines(prev, cur))
+		prev = cur
+	}
+}
+
+// trimTrailingBlank drops the empty rows below the cursor that capture-pane
+// includes for an unfilled pane.
+func trimTrailingBlank(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// tailNewLines returns the lines of cur that are not in prev, in order. The
+// two captures are aligned with a longest common subsequence, so output that
+// scrolled up and a fixed footer (an agent's input box) both match, and
+// only lines that appeared or changed are returned.
+func tailNewLines(prev, cur []string) []string {
+	// lcs[i][j] is the LCS length of prev[i:] and cur[j:].
+	lcs := make([][]int, len(prev)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(cur)+1)
+	}
+	for i := len(prev) - 1; i >= 0; i-- {
+		for j := len(cur) - 1; j >= 0; j-- {
+			if prev[i] == cur[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var added []string
+	i, j := 0, 0
+	for j < len(cur) {
+		switch {
+		case i < len(prev) && prev[i] == cur[j]:
+			i++
+			j++
+		case i < len(prev) && lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			added = append(added, cur[j])
+			j++
+		}
+	}
+	return added
+}
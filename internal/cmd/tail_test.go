@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTailNewLines(t *testing.T) {
+	footer := []string{"──────", "> ", "──────"}
+	with := func(lines ...string) []string {
+		return append(append([]string{}, lines...), footer...)
+	}
+
+	tests := []struct {
+		name      string
+		prev, cur []string
+		want      []string
+	}{
+		{"unchanged", with("a", "b"), with("a", "b"), nil},
+		{"appended above footer", with("a", "b"), with("a", "b", "c", "d"), []string{"c", "d"}},
+		{"scrolled", with("a", "b", "c"), with("b", "c", "d"), []string{"d"}},
+		{"repeated line", with("ok", "ok"), with("ok", "ok", "ok"), []string{"ok"}},
+		{"redrawn line", with("a", "⠋ working"), with("a", "⠙ working"), []string{"⠙ working"}},
+		{"scrolled past window", []string{"a", "b"}, []string{"x", "y"}, []string{"x", "y"}},
+		{"first capture", nil, []string{"a"}, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tailNewLines(tt.prev, tt.cur); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tailNewLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrimTrailingBlank(t *testing.T) {
+	got := trimTrailingBlank([]string{"a", "", "b", "  ", ""})
+	if !reflect.DeepEqual(got, []string{"a", "", "b"}) {
+		t.Errorf("trimTrailingBlank() = %q", got)
+	}
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/takeover"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	takeoverReason string
+	takeoverList   bool
+)
+
+var takeoverCmd = &cobra.Command{
+	Use:     "takeover [rig/polecat]",
+	GroupID: GroupAgents,
+	Short:   "Take over a polecat session from automation",
+	Long: `Take over a polecat's session so a human can work in it.
+
+While a session is taken over:
+  - the witness skips it in zombie and stall detection
+  - the daemon does not restart it
+  - gt nudge does not deliver to it (use --force to override)
+
+The agent is told a human is taking over, and the takeover is recorded in
+the audit log (gt audit). Attach with 'gt session at <rig/polecat>'.
+
+Run 'gt handback' to return the session to automation.
+
+Examples:
+  gt takeover gastown/Toast
+  gt takeover gastown/Toast --reason "debugging flaky test"
+  gt takeover --list`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTakeover,
+}
+
+var handbackCmd = &cobra.Command{
+	Use:     "handback <rig/polecat>",
+	GroupID: GroupAgents,
+	Short:   "Return a taken-over polecat session to automation",
+	Long: `End a takeover started with 'gt takeover'.
+
+The agent is told automation has resumed, supervisors watch the session
+again, and the takeover window is recorded in the audit log.
+
+Examples:
+  gt handback gastown/Toast`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHandback,
+}
+
+func init() {
+	takeoverCmd.Flags().StringVarP(&takeoverReason, "reason", "r", "", "Why the session is being taken over")
+	takeoverCmd.Flags().BoolVar(&takeoverList, "list", false, "List sessions currently taken over")
+	rootCmd.AddCommand(takeoverCmd)
+	rootCmd.AddCommand(handbackCmd)
+}
+
+// takeoverNotice and handbackNotice are nudged to the agent.
+const (
+	takeoverNotice = "A human is taking over this session. Stop what you are doing, " +
+		"do not start new work, and wait for instructions typed here."
+	handbackNotice = "The human has handed this session back. Automation has resumed: " +
+		"re-check your hook (gt hook) and continue your work."
+)
+
+// resolveTakeoverTarget returns the tmux session for a polecat address.
+func resolveTakeoverTarget(target string) (string, error) {
+	id, err := session.ParseAddress(target)
+	if err != nil {
+		return "", err
+	}
+	if id.Role != session.RolePolecat {
+		return "", fmt.Errorf("%s is not a polecat (expected rig/polecat)", target)
+	}
+	return id.SessionName(), nil
+}
+
+// isTakenOverTarget reports whether a nudge target names a polecat session
+// under human takeover.
+func isTakenOverTarget(townRoot, target string) bool {
+	sessionName, err := resolveTakeoverTarget(target)
+	if err != nil {
+		return false
+	}
+	return takeover.IsTakenOver(townRoot, sessionName)
+}
+
+func runTakeover(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if takeoverList || len(args) == 0 {
+		return listTakeovers(townRoot)
+	}
+
+	target := args[0]
+	sessionName, err := resolveTakeoverTarget(target)
+	if err != nil {
+		return err
+	}
+	t := tmux.NewTmux()
+	if exists, _ := t.HasSession(sessionName); !exists {
+		return fmt.Errorf("session %s is not running", sessionName)
+	}
+
+	state, err := takeover.Start(townRoot, sessionName, target, "human", takeoverReason)
+	if errors.Is(err, takeover.ErrAlreadyTakenOver) {
+		fmt.Printf("%s %s is already taken over (by %s, %s)\n", style.Dim.Render("○"), target, state.By, formatAge(state.StartedAt))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("recording takeover: %w", err)
+	}
+
+	if err := t.NudgeSession(sessionName, "[from overseer] "+takeoverNotice); err != nil {
+		style.PrintWarning("could not notify the agent: %v", err)
+	}
+	_ = events.LogAudit(events.TypeTakeover, state.By, events.TakeoverPayload(target, sessionName, takeoverReason, ""))
+
+	fmt.Printf("%s Took over %s\n", style.SuccessPrefix, style.Bold.Render(target))
+	fmt.Println("  Supervisor nudges and recovery are paused for this session.")
+	fmt.Printf("  Attach:    %s\n", style.Dim.Render("gt session at "+target))
+	fmt.Printf("  Hand back: %s\n", style.Dim.Render("gt handback "+target))
+	return nil
+}
+
+func runHandback(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	target := args[0]
+	sessionName, err := resolveTakeoverTarget(target)
+	if err != nil {
+		return err
+	}
+
+	state, err := takeover.End(townRoot, sessionName)
+	if errors.Is(err, takeover.ErrNotTakenOver) {
+		fmt.Printf("%s %s is not taken over\n", style.Dim.Render("○"), target)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("ending takeover: %w", err)
+	}
+
+	window := time.Since(state.StartedAt).Round(time.Second)
+	t := tmux.NewTmux()
+	if exists, _ := t.HasSession(sessionName); exists {
+		if err := t.NudgeSession(sessionName, "[from overseer] "+handbackNotice); err != nil {
+			style.PrintWarning("could not notify the agent: %v", err)
+		}
+	}
+	_ = events.LogAudit(events.TypeHandback, state.By, events.TakeoverPayload(target, sessionName, state.Reason, window.String()))
+
+	fmt.Printf("%s Handed %s back to automation after %s\n", style.SuccessPrefix, style.Bold.Render(target), window)
+	return nil
+}
+
+func listTakeovers(townRoot string) error {
+	states, err := takeover.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing takeovers: %w", err)
+	}
+	if len(states) == 0 {
+		fmt.Println("No sessions are taken over.")
+		return nil
+	}
+	for _, s := range states {
+		line := fmt.Sprintf("  %s  %s", style.Bold.Render(s.Target), style.Dim.Render(fmt.Sprintf("(by %s, %s)", s.By, formatAge(s.StartedAt))))
+		if s.Reason != "" {
+			line += "  " + s.Reason
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/townstate"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	townRestoreInto   string
+	townRestoreForce  bool
+	townRestoreDryRun bool
+)
+
+var townBackupCmd = &cobra.Command{
+	Use:   "backup [dir]",
+	Short: "Snapshot the town's non-issue state",
+	Long: `Snapshot the town's non-issue state: the orchestration config that the
+JSONL issue backup doesn't cover.
+
+Included (missing files are skipped):
+  mayor/     town.json, rigs.json, accounts.json, config.json, daemon.json,
+             overseer.json, .claude/settings.json
+  town       settings/, config/, roles/, hooks/, plugins/, CLAUDE.md,
+             .beads/routes.jsonl, .beads/formulas/, deacon/.claude/settings.json
+  each rig   config.json, settings/, roles/, plugins/
+  ~/.gt      hooks-base.json, hooks-overrides/
+
+The snapshot goes to dir, or by default to town-state/ in the JSONL backup
+repo (patrols.jsonl_git_backup.git_repo, default ~/.dolt-archive/git). The
+daemon's jsonl_git_backup patrol takes the same snapshot on every run and
+commits it with the issue export; set "town_state": false to turn that off.
+
+Examples:
+  gt town backup                    # Into the backup repo's town-state/
+  gt town backup /mnt/usb/gt-state  # Into a directory`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTownBackup,
+}
+
+var townRestoreCmd = &cobra.Command{
+	Use:   "restore <source>",
+	Short: "Restore the town's non-issue state from a snapshot",
+	Long: `Restore town state from a snapshot taken by gt town backup or the
+daemon's jsonl_git_backup patrol, to rebuild a machine.
+
+source is the snapshot directory or the backup repo containing town-state/.
+Files that already exist are skipped unless --force is given, so restoring
+over a freshly installed town needs --force to replace its defaults.
+
+Rig repositories and issues are not part of the snapshot: re-clone rigs from
+the URLs in the restored mayor/rigs.json and import issues from the JSONL
+backup.
+
+Examples:
+  git clone <backup-remote> ~/gt-backup
+  gt install ~/gt && cd ~/gt
+  gt town restore ~/gt-backup --dry-run
+  gt town restore ~/gt-backup --force
+  gt town restore ~/gt-backup --into ~/gt   # From outside the town`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTownRestore,
+}
+
+func init() {
+	townRestoreCmd.Flags().StringVar(&townRestoreInto, "into", "", "Town root to restore into (default: current workspace)")
+	townRestoreCmd.Flags().BoolVarP(&townRestoreForce, "force", "f", false, "Overwrite files that already exist")
+	townRestoreCmd.Flags().BoolVarP(&townRestoreDryRun, "dry-run", "n", false, "Show what would be restored without writing")
+
+	townCmd.AddCommand(townBackupCmd)
+	townCmd.AddCommand(townRestoreCmd)
+}
+
+func runTownBackup(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var dest string
+	if len(args) > 0 {
+		dest = args[0]
+	} else {
+		var backupConfig *daemon.JsonlGitBackupConfig
+		if patrolConfig := daemon.LoadPatrolConfig(townRoot); patrolConfig != nil && patrolConfig.Patrols != nil {
+			backupConfig = patrolConfig.Patrols.JsonlGitBackup
+		}
+		repo, err := daemon.JsonlGitBackupRepo(backupConfig)
+		if err != nil {
+			return err
+		}
+		dest = filepath.Join(repo, townstate.Dir)
+	}
+
+	m, err := townstate.Snapshot(townRoot, dest)
+	if err != nil {
+		return fmt.Errorf("snapshotting town state: %w", err)
+	}
+	fmt.Printf("%s Town state saved to %s (%d files, %d rigs)\n",
+		style.SuccessPrefix, dest, len(m.Files), len(m.Rigs))
+	if len(args) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("Committed and pushed with the next jsonl_git_backup run"))
+	}
+	return nil
+}
+
+func runTownRestore(cmd *cobra.Command, args []string) error {
+	townRoot := townRestoreInto
+	if townRoot == "" {
+		var err error
+		townRoot, err = workspace.FindFromCwd()
+		if err != nil || townRoot == "" {
+			return fmt.Errorf("not in a Gas Town workspace (use --into <town-root> to restore elsewhere)")
+		}
+	}
+
+	dir, err := townstate.FindSnapshot(args[0])
+	if err != nil {
+		return err
+	}
+	m, err := townstate.ReadManifest(dir)
+	if err != nil {
+		return fmt.Errorf("reading snapshot: %w", err)
+	}
+	if m.Host != "" {
+		fmt.Printf("Snapshot of %s on %s\n", m.TownRoot, m.Host)
+	}
+
+	result, err := townstate.Restore(dir, townRoot, townstate.RestoreOptions{
+		Force:  townRestoreForce,
+		DryRun: townRestoreDryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("restoring town state: %w", err)
+	}
+
+	verb := "Restored"
+	if townRestoreDryRun {
+		verb = "Would restore"
+	}
+	for _, f := range result.Restored {
+		fmt.Printf("  %s %s\n", style.Bold.Render("+"), f)
+	}
+	for _, f := range result.Skipped {
+		fmt.Printf("  %s %s %s\n", style.Dim.Render("="), f, style.Dim.Render("(exists)"))
+	}
+	fmt.Printf("%s %s %d file(s) into %s", style.SuccessPrefix, verb, len(result.Restored), townRoot)
+	if len(result.Skipped) > 0 {
+		fmt.Printf(", skipped %d existing (use --force to overwrite)", len(result.Skipped))
+	}
+	fmt.Println()
+
+	if !townRestoreDryRun && len(result.Restored) > 0 {
+		fmt.Println()
+		fmt.Println("Next steps:")
+		fmt.Printf("  1. Re-clone rig repositories listed in %s\n", filepath.Join(townRoot, "mayor", "rigs.json"))
+		fmt.Println("  2. Import issues from the JSONL backup")
+		fmt.Printf("  3. Run %s to check the rebuilt town\n", style.Bold.Render("gt doctor"))
+	}
+	return nil
+}
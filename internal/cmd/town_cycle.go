@@ -41,7 +41,8 @@ func init() {
 var townCmd = &cobra.Command{
 	Use:   "town",
 	Short: "Town-level operations",
-	Long:  `Commands for town-level operations including session cycling.`,
+	Long:  `Commands for town-level operations including session cycling and
+backup/restore of town state.`,
 }
 
 var townNextCmd = &cobra.Command{
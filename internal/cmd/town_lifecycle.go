@@ -0,0 +1,655 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+const (
+	// townStopRecordFile records what gt town stop shut down, for gt town start.
+	townStopRecordFile = "daemon/town-stop.json"
+
+	// townWrapUpQuiet is how long a worker session must show no output to
+	// count as having reached a safe point during wrap-up.
+	townWrapUpQuiet = 15 * time.Second
+
+	// townWrapUpPoll is how often wrap-up checks worker sessions.
+	townWrapUpPoll = 2 * time.Second
+
+	// townStopActor is recorded as who paused the scheduler.
+	townStopActor = "gt town stop"
+)
+
+var (
+	townStopWrapUp        time.Duration
+	townStopStageTimeout  time.Duration
+	townStopForce         bool
+	townStartStageTimeout time.Duration
+	townStartNoRestore    bool
+)
+
+var townStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Shut down the whole town in dependency order",
+	Long: `Shut down the town cleanly, for machine reboots and upgrades.
+
+Stages run in dependency order, each with its own timeout:
+
+  1. dispatch    Pause the scheduler so no new work is handed out
+  2. workers     Ask polecats and crew to reach a safe point (commit work,
+                 write a checkpoint), wait for them to go quiet or for
+                 --wrap-up, checkpoint working polecats, then stop them
+  3. rig agents  Stop refineries and witnesses
+  4. town agents Stop the mayor, boot and deacon
+  5. daemon      Stop the daemon
+  6. dolt        Stop the Dolt server
+
+What was running is recorded in daemon/town-stop.json, before any worker
+is stopped, so 'gt town start' can bring the same sessions back. A stage
+that fails or overruns its timeout stops the shutdown there: the later
+stages are skipped, since each depends on the one before it.
+
+Unlike 'gt down', workers get time to wrap up and are restored afterwards.
+
+Examples:
+  gt town stop
+  gt town stop --wrap-up 5m
+  gt town stop --force          # no wrap-up, kill sessions immediately`,
+	Args: cobra.NoArgs,
+	RunE: runTownStop,
+}
+
+var townStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the town and restore the sessions stopped by gt town stop",
+	Long: `Start the town in dependency order and resume where 'gt town stop' left off.
+
+  1. dolt        Start the Dolt server and wait until it accepts connections
+  2. daemon      Start the daemon
+  3. town agents Start the deacon and mayor
+  4. rig agents  Start witnesses and refineries
+  5. workers     Restart the polecats and crew recorded by gt town stop;
+                 polecats resume from their hooked work and checkpoint
+  6. dispatch    Resume the scheduler (unless it was paused before the stop)
+
+Without a stop record this starts the services only, like 'gt up'.
+
+Examples:
+  gt town start
+  gt town start --no-restore    # services only, leave workers stopped`,
+	Args: cobra.NoArgs,
+	RunE: runTownStart,
+}
+
+func init() {
+	townStopCmd.Flags().DurationVar(&townStopWrapUp, "wrap-up", 2*time.Minute, "How long workers get to reach a safe point")
+	townStopCmd.Flags().DurationVar(&townStopStageTimeout, "stage-timeout", 30*time.Second, "Timeout for each other stage")
+	townStopCmd.Flags().BoolVarP(&townStopForce, "force", "f", false, "Skip wrap-up and kill sessions without graceful shutdown")
+	townStartCmd.Flags().DurationVar(&townStartStageTimeout, "stage-timeout", 2*time.Minute, "Timeout for each stage")
+	townStartCmd.Flags().BoolVar(&townStartNoRestore, "no-restore", false, "Do not restart the workers recorded by gt town stop")
+	townCmd.AddCommand(townStopCmd)
+	townCmd.AddCommand(townStartCmd)
+}
+
+// townStopRecord is what gt town stop shut down.
+type townStopRecord struct {
+	StoppedAt time.Time `json:"stopped_at"`
+
+	// SchedulerWasPaused is true if dispatch was already paused, in which
+	// case gt town start leaves it paused.
+	SchedulerWasPaused bool `json:"scheduler_was_paused,omitempty"`
+
+	Workers []townWorker `json:"workers,omitempty"`
+}
+
+// townWorker is a polecat or crew session stopped by gt town stop.
+type townWorker struct {
+	Role     string `json:"role"` // "polecat" or "crew"
+	Rig      string `json:"rig"`
+	Name     string `json:"name"`
+	HookBead string `json:"hook_bead,omitempty"`
+}
+
+func (w townWorker) String() string {
+	return fmt.Sprintf("%s/%s/%s", w.Rig, w.Role, w.Name)
+}
+
+func townStopRecordPath(townRoot string) string {
+	return filepath.Join(townRoot, townStopRecordFile)
+}
+
+func saveTownStopRecord(townRoot string, rec *townStopRecord) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := townStopRecordPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644) //nolint:gosec // G306: not secret
+}
+
+// loadTownStopRecord reads the stop record, or returns nil if there is none.
+func loadTownStopRecord(townRoot string) (*townStopRecord, error) {
+	data, err := os.ReadFile(townStopRecordPath(townRoot)) //nolint:gosec // G304: fixed path in the town
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var rec townStopRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", townStopRecordFile, err)
+	}
+	return &rec, nil
+}
+
+// townStage is one step of gt town stop or start. Run returns a short
+// detail for the status line.
+type townStage struct {
+	Name    string
+	Timeout time.Duration
+	Run     func(ctx context.Context) (string, error)
+}
+
+// runTownStages runs stages in order and reports whether all succeeded.
+// Each stage depends on the ones before it, so once a stage fails or
+// overruns its timeout the rest are skipped. A timed-out stage's context is
+// cancelled, so its work stops at its next check.
+func runTownStages(stages []townStage) bool {
+	for i, st := range stages {
+		start := time.Now()
+		detail, err := runTownStage(st)
+		elapsed := style.Dim.Render(fmt.Sprintf("(%s)", time.Since(start).Round(100*time.Millisecond)))
+		label := fmt.Sprintf("[%d/%d] %s", i+1, len(stages), st.Name)
+		if err != nil {
+			fmt.Printf("%s %s: %v %s\n", style.ErrorPrefix, label, err, elapsed)
+			for j, rest := range stages[i+1:] {
+				fmt.Printf("%s [%d/%d] %s: %s\n", style.WarningPrefix, i+j+2, len(stages), rest.Name, style.Dim.Render("skipped"))
+			}
+			return false
+		}
+		fmt.Printf("%s %s: %s %s\n", style.SuccessPrefix, label, style.Dim.Render(detail), elapsed)
+	}
+	return true
+}
+
+func runTownStage(st townStage) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), st.Timeout)
+	defer cancel()
+
+	type result struct {
+		detail string
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		detail, err := st.Run(ctx)
+		done <- result{detail, err}
+	}()
+	select {
+	case r := <-done:
+		return r.detail, r.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("timed out after %s", st.Timeout)
+	}
+}
+
+func runTownStop(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	t := tmux.NewTmux()
+	if !t.IsAvailable() {
+		return fmt.Errorf("%s not available (is %s installed and on PATH?)", t.Name(), t.Name())
+	}
+
+	// Holding the shutdown lock keeps the daemon from restarting the
+	// sessions we stop before we get to it.
+	lock, err := acquireShutdownLock(townRoot)
+	if err != nil {
+		return fmt.Errorf("cannot proceed: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+	_ = t.SetExitEmpty(false)
+
+	// stopSession (gt down) reads this for graceful vs. forced stops.
+	downForce = townStopForce
+	rigs := discoverRigs(townRoot)
+	rec := &townStopRecord{StoppedAt: time.Now()}
+	wrapUp := townStopWrapUp
+	if townStopForce {
+		wrapUp = 0
+	}
+
+	stages := []townStage{
+		{Name: "Dispatch", Timeout: townStopStageTimeout, Run: func(ctx context.Context) (string, error) {
+			return pauseDispatchForStop(townRoot, rec)
+		}},
+		{Name: "Workers", Timeout: wrapUp + townStopStageTimeout, Run: func(ctx context.Context) (string, error) {
+			return wrapUpWorkers(ctx, t, townRoot, rigs, rec, wrapUp)
+		}},
+		{Name: "Rig agents", Timeout: townStopStageTimeout, Run: func(ctx context.Context) (string, error) {
+			return stopRigAgents(ctx, t, rigs)
+		}},
+		{Name: "Town agents", Timeout: townStopStageTimeout, Run: func(ctx context.Context) (string, error) {
+			stopped := 0
+			for _, ts := range session.TownSessions() {
+				if ctx.Err() != nil {
+					return "", ctx.Err()
+				}
+				ok, err := session.StopTownSession(t, ts, townStopForce)
+				if err != nil {
+					return "", fmt.Errorf("%s: %w", ts.Name, err)
+				}
+				if ok {
+					stopped++
+				}
+			}
+			return fmt.Sprintf("%d stopped", stopped), nil
+		}},
+		{Name: "Daemon", Timeout: townStopStageTimeout, Run: func(ctx context.Context) (string, error) {
+			running, pid, err := daemon.IsRunning(townRoot)
+			if err != nil || !running {
+				return "not running", err
+			}
+			if err := daemon.StopDaemon(townRoot); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("stopped (was PID %d)", pid), nil
+		}},
+		{Name: "Dolt", Timeout: townStopStageTimeout, Run: func(ctx context.Context) (string, error) {
+			if _, err := os.Stat(doltserver.DefaultConfig(townRoot).DataDir); err != nil {
+				return "not configured", nil
+			}
+			running, pid, err := doltserver.IsRunning(townRoot)
+			if err != nil || !running {
+				return "not running", err
+			}
+			if err := doltserver.Stop(townRoot); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("stopped (was PID %d)", pid), nil
+		}},
+	}
+
+	fmt.Printf("%s Stopping town\n\n", style.Bold.Render("⏻"))
+	allOK := runTownStages(stages)
+	fmt.Println()
+	if !allOK {
+		fmt.Printf("%s Some stages failed; run 'gt down --all' to force the rest\n", style.Bold.Render("✗"))
+		return fmt.Errorf("town did not stop cleanly")
+	}
+	_ = events.LogFeed(events.TypeHalt, "gt", events.HaltPayload([]string{"town"}))
+	fmt.Printf("%s Town stopped; %d worker(s) recorded for 'gt town start'\n", style.Bold.Render("✓"), len(rec.Workers))
+	return nil
+}
+
+// pauseDispatchForStop pauses the scheduler, remembering whether it was
+// already paused.
+func pauseDispatchForStop(townRoot string, rec *townStopRecord) (string, error) {
+	state, err := capacity.LoadState(townRoot)
+	if err != nil {
+		return "", fmt.Errorf("loading scheduler state: %w", err)
+	}
+	if state.Paused {
+		rec.SchedulerWasPaused = true
+		return fmt.Sprintf("already paused (by %s)", state.PausedBy), nil
+	}
+	state.SetPaused(townStopActor)
+	if err := capacity.SaveState(townRoot, state); err != nil {
+		return "", fmt.Errorf("saving scheduler state: %w", err)
+	}
+	return "scheduler paused", nil
+}
+
+// townWrapUpNudge asks a worker to reach a safe point before it is stopped.
+const townWrapUpNudge = "Gas Town is shutting down (gt town stop). Finish or pause your current step, " +
+	"commit work in progress, run `gt checkpoint write`, then stop and wait. " +
+	"Your session will be restored by gt town start."
+
+// wrapUpWorkers records the running polecat and crew sessions, gives them
+// until wrapUp to go quiet, checkpoints working polecats, and stops them.
+// The stop record is saved before anything is stopped, so gt town start
+// can restore the workers even if this stage is cut short.
+func wrapUpWorkers(ctx context.Context, t *tmux.Tmux, townRoot string, rigs []string, rec *townStopRecord, wrapUp time.Duration) (string, error) {
+	names, err := t.ListSessions()
+	if err != nil {
+		return "", fmt.Errorf("listing sessions: %w", err)
+	}
+	rigSet := make(map[string]bool, len(rigs))
+	for _, r := range rigs {
+		rigSet[r] = true
+	}
+
+	type running struct {
+		worker  townWorker
+		session string
+		clone   string // polecat worktree, for the checkpoint
+	}
+	var workers []running
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil || !rigSet[id.Rig] || (id.Role != session.RolePolecat && id.Role != session.RoleCrew) {
+			continue
+		}
+		w := running{worker: townWorker{Role: string(id.Role), Rig: id.Rig, Name: id.Name}, session: name}
+		if id.Role == session.RolePolecat {
+			// Standby polecats have no work; the witness starts new ones.
+			if polecat.IsStandbySession(t, name) {
+				_ = t.KillSessionWithProcesses(name)
+				continue
+			}
+			if mgr, _, err := getPolecatManager(id.Rig); err == nil {
+				if p, err := mgr.Get(id.Name); err == nil {
+					w.worker.HookBead = p.Issue
+					w.clone = p.ClonePath
+				}
+			}
+		}
+		workers = append(workers, w)
+		rec.Workers = append(rec.Workers, w.worker)
+	}
+	if err := saveTownStopRecord(townRoot, rec); err != nil {
+		return "", fmt.Errorf("saving stop record: %w", err)
+	}
+	if len(workers) == 0 {
+		return "none running", nil
+	}
+
+	if wrapUp > 0 {
+		if os.Getenv("GT_TEST_NO_NUDGE") == "" {
+			for _, w := range workers {
+				_ = t.NudgeSession(w.session, townWrapUpNudge)
+			}
+		}
+		deadline := time.Now().Add(wrapUp)
+		for time.Now().Before(deadline) {
+			// Give the nudge time to land before judging quiet.
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(townWrapUpPoll):
+			}
+			activity := make(map[string]time.Time, len(workers))
+			for _, w := range workers {
+				if last, err := t.GetSessionActivity(w.session); err == nil {
+					activity[w.session] = last
+				}
+			}
+			if len(busySessions(activity, time.Now(), townWrapUpQuiet)) == 0 {
+				break
+			}
+		}
+	}
+
+	checkpointed := 0
+	for _, w := range workers {
+		if w.clone != "" && w.worker.HookBead != "" && writeStopCheckpoint(w.clone, w.worker.HookBead, rec.StoppedAt) == nil {
+			checkpointed++
+		}
+	}
+
+	var failed []string
+	for _, w := range workers {
+		if _, err := stopSession(ctx, t, w.session); err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			failed = append(failed, w.worker.String())
+		}
+	}
+	if len(failed) > 0 {
+		return "", fmt.Errorf("could not stop %v", failed)
+	}
+	return fmt.Sprintf("%d stopped, %d checkpointed", len(workers), checkpointed), nil
+}
+
+// busySessions returns the sessions whose last activity is within quiet of
+// now, sorted for stable output.
+func busySessions(activity map[string]time.Time, now time.Time, quiet time.Duration) []string {
+	var busy []string
+	for name, last := range activity {
+		if now.Sub(last) < quiet {
+			busy = append(busy, name)
+		}
+	}
+	sort.Strings(busy)
+	return busy
+}
+
+// writeStopCheckpoint makes sure a stopped polecat has a checkpoint to
+// resume from. A checkpoint the agent wrote during wrap-up is kept;
+// otherwise the git state is captured.
+func writeStopCheckpoint(clonePath, hookBead string, stoppedAt time.Time) error {
+	if cp, err := checkpoint.Read(clonePath); err == nil && cp != nil && !cp.Timestamp.Before(stoppedAt) {
+		return nil
+	}
+	cp, err := checkpoint.Capture(clonePath)
+	if err != nil {
+		return err
+	}
+	cp.WithHookedBead(hookBead).WithNotes(fmt.Sprintf("Session stopped by gt town stop at %s.", stoppedAt.Format(time.RFC3339)))
+	return checkpoint.Write(clonePath, cp)
+}
+
+// stopRigAgents stops every rig's refinery and then its witness.
+func stopRigAgents(ctx context.Context, t *tmux.Tmux, rigs []string) (string, error) {
+	stopped := 0
+	for _, rigName := range rigs {
+		prefix := session.PrefixFor(rigName)
+		for _, name := range []string{session.RefinerySessionName(prefix), session.WitnessSessionName(prefix)} {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			wasRunning, err := stopSession(ctx, t, name)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", name, err)
+			}
+			if wasRunning {
+				stopped++
+			}
+		}
+	}
+	return fmt.Sprintf("%d stopped", stopped), nil
+}
+
+func runTownStart(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rec, err := loadTownStopRecord(townRoot)
+	if err != nil {
+		return err
+	}
+	if patrolCfg := daemon.LoadPatrolConfig(townRoot); patrolCfg != nil {
+		for k, v := range patrolCfg.Env {
+			os.Setenv(k, v)
+		}
+	}
+	rigs := discoverRigs(townRoot)
+	timeout := townStartStageTimeout
+
+	stages := []townStage{
+		{Name: "Dolt", Timeout: timeout, Run: func(ctx context.Context) (string, error) {
+			cfg := doltserver.DefaultConfig(townRoot)
+			if _, err := os.Stat(cfg.DataDir); err != nil {
+				return "not configured", nil
+			}
+			detail := "already running"
+			if running, _, _ := doltserver.IsRunning(townRoot); !running {
+				if err := doltserver.Start(townRoot); err != nil {
+					return "", err
+				}
+				detail = fmt.Sprintf("started (port %d)", cfg.Port)
+			}
+			_, _ = doltserver.EnsureAllMetadata(townRoot)
+			waitForDoltReady(townRoot)
+			return detail, nil
+		}},
+		{Name: "Daemon", Timeout: timeout, Run: func(ctx context.Context) (string, error) {
+			if err := ensureDaemon(townRoot); err != nil {
+				return "", err
+			}
+			_, pid, _ := daemon.IsRunning(townRoot)
+			return fmt.Sprintf("PID %d", pid), nil
+		}},
+		{Name: "Town agents", Timeout: timeout, Run: func(ctx context.Context) (string, error) {
+			if err := deacon.NewManager(townRoot).Start(""); err != nil && err != deacon.ErrAlreadyRunning {
+				return "", fmt.Errorf("deacon: %w", err)
+			}
+			if err := mayor.NewManager(townRoot).Start(""); err != nil && err != mayor.ErrAlreadyRunning {
+				return "", fmt.Errorf("mayor: %w", err)
+			}
+			return "deacon, mayor", nil
+		}},
+		{Name: "Rig agents", Timeout: timeout, Run: func(ctx context.Context) (string, error) {
+			prefetched, rigErrors := prefetchRigs(rigs)
+			witnesses, refineries := startRigAgentsWithPrefetch(rigs, prefetched, rigErrors)
+			var failed []string
+			for _, results := range []map[string]agentStartResult{witnesses, refineries} {
+				for _, r := range results {
+					if !r.ok {
+						failed = append(failed, fmt.Sprintf("%s: %s", r.name, r.detail))
+					}
+				}
+			}
+			if len(failed) > 0 {
+				sort.Strings(failed)
+				return "", fmt.Errorf("%v", failed)
+			}
+			return fmt.Sprintf("%d rig(s)", len(rigs)), nil
+		}},
+		{Name: "Workers", Timeout: timeout, Run: func(ctx context.Context) (string, error) {
+			if townStartNoRestore {
+				return "skipped (--no-restore)", nil
+			}
+			if rec == nil {
+				return "no stop record", nil
+			}
+			return restoreTownWorkers(ctx, townRoot, rec.Workers)
+		}},
+		{Name: "Dispatch", Timeout: timeout, Run: func(ctx context.Context) (string, error) {
+			return resumeDispatchAfterStop(townRoot, rec)
+		}},
+	}
+
+	fmt.Printf("%s Starting town\n\n", style.Bold.Render("⏻"))
+	allOK := runTownStages(stages)
+	ensureCrossSocketBindings()
+	fmt.Println()
+	if !allOK {
+		fmt.Printf("%s Some stages failed; the stop record is kept so 'gt town start' can retry\n", style.Bold.Render("✗"))
+		return fmt.Errorf("town did not start cleanly")
+	}
+	if rec != nil {
+		_ = os.Remove(townStopRecordPath(townRoot))
+	}
+	_ = events.LogFeed(events.TypeBoot, "gt", events.BootPayload("town", []string{"town"}))
+	fmt.Printf("%s Town started\n", style.Bold.Render("✓"))
+	return nil
+}
+
+// restoreTownWorkers restarts the recorded workers. Polecats whose work
+// was finished or unhooked while the town was down are left idle; those
+// still hooked resume from their checkpoint when gt prime runs.
+func restoreTownWorkers(ctx context.Context, townRoot string, workers []townWorker) (string, error) {
+	accountsPath := constants.MayorAccountsPath(townRoot)
+	claudeConfigDir, _, err := config.ResolveAccountConfigDir(accountsPath, "")
+	if err != nil {
+		return "", fmt.Errorf("resolving account: %w", err)
+	}
+
+	started, skipped := 0, 0
+	var failed []string
+	for _, w := range workers {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		var err error
+		switch w.Role {
+		case string(session.RolePolecat):
+			mgr, rigObj, getErr := getPolecatManager(w.Rig)
+			var p *polecat.Polecat
+			if getErr == nil {
+				p, getErr = mgr.Get(w.Name)
+			}
+			if getErr != nil || p.Issue == "" {
+				skipped++
+				continue
+			}
+			sessMgr := polecat.NewSessionManager(tmux.NewTmux(), rigObj)
+			err = sessMgr.Start(w.Name, polecat.SessionStartOptions{Issue: p.Issue, RuntimeConfigDir: claudeConfigDir})
+			if err == polecat.ErrSessionRunning {
+				err = nil
+			}
+		case string(session.RoleCrew):
+			crewMgr, _, getErr := getCrewManager(w.Rig)
+			if getErr != nil {
+				err = getErr
+				break
+			}
+			err = crewMgr.Start(w.Name, crew.StartOptions{})
+			if err == crew.ErrSessionRunning {
+				err = nil
+			}
+		default:
+			skipped++
+			continue
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", w, err))
+			continue
+		}
+		started++
+	}
+	if len(failed) > 0 {
+		return "", fmt.Errorf("%d restored, failed: %v", started, failed)
+	}
+	return fmt.Sprintf("%d restored, %d no longer hooked", started, skipped), nil
+}
+
+// resumeDispatchAfterStop resumes the scheduler if gt town stop paused it.
+func resumeDispatchAfterStop(townRoot string, rec *townStopRecord) (string, error) {
+	state, err := capacity.LoadState(townRoot)
+	if err != nil {
+		return "", fmt.Errorf("loading scheduler state: %w", err)
+	}
+	if !state.Paused {
+		return "running", nil
+	}
+	if rec == nil || rec.SchedulerWasPaused || state.PausedBy != townStopActor {
+		return fmt.Sprintf("left paused (by %s)", state.PausedBy), nil
+	}
+	state.SetResumed()
+	if err := capacity.SaveState(townRoot, state); err != nil {
+		return "", fmt.Errorf("saving scheduler state: %w", err)
+	}
+	return "scheduler resumed", nil
+}
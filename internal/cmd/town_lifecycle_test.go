@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTownStopRecord_RoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	if rec, err := loadTownStopRecord(townRoot); err != nil || rec != nil {
+		t.Fatalf("loadTownStopRecord(empty) = %v, %v; want nil, nil", rec, err)
+	}
+
+	want := &townStopRecord{
+		StoppedAt:          time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		SchedulerWasPaused: true,
+		Workers: []townWorker{
+			{Role: "polecat", Rig: "greenplace", Name: "Toast", HookBead: "gp-abc"},
+			{Role: "crew", Rig: "greenplace", Name: "max"},
+		},
+	}
+	if err := saveTownStopRecord(townRoot, want); err != nil {
+		t.Fatalf("saveTownStopRecord: %v", err)
+	}
+	got, err := loadTownStopRecord(townRoot)
+	if err != nil {
+		t.Fatalf("loadTownStopRecord: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestBusySessions(t *testing.T) {
+	now := time.Now()
+	activity := map[string]time.Time{
+		"gt-gp-Toast": now.Add(-2 * time.Second),
+		"gt-gp-Nux":   now.Add(-time.Minute),
+		"gt-gp-max":   now.Add(-5 * time.Second),
+	}
+	got := busySessions(activity, now, 15*time.Second)
+	want := []string{"gt-gp-Toast", "gt-gp-max"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("busySessions = %v, want %v", got, want)
+	}
+	if busy := busySessions(activity, now.Add(time.Hour), 15*time.Second); len(busy) != 0 {
+		t.Errorf("busySessions after an hour = %v, want none", busy)
+	}
+}
+
+func TestRunTownStages_TimeoutSkipsLaterStages(t *testing.T) {
+	var ran []string
+	stages := []townStage{
+		{Name: "first", Timeout: time.Second, Run: func(ctx context.Context) (string, error) {
+			ran = append(ran, "first")
+			return "ok", nil
+		}},
+		{Name: "slow", Timeout: 20 * time.Millisecond, Run: func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}},
+		{Name: "dependent", Timeout: time.Second, Run: func(ctx context.Context) (string, error) {
+			ran = append(ran, "dependent")
+			return "ok", nil
+		}},
+	}
+	if runTownStages(stages) {
+		t.Error("runTownStages = true, want false when a stage times out")
+	}
+	if !reflect.DeepEqual(ran, []string{"first"}) {
+		t.Errorf("ran %v, want only the stage before the timeout", ran)
+	}
+}
+
+func TestStopSession_HonorsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// A cancelled stop gives up before it touches the session.
+	if _, err := stopSession(ctx, nil, "gt-gp-Toast"); !errors.Is(err, context.Canceled) {
+		t.Errorf("stopSession(cancelled) = %v, want context.Canceled", err)
+	}
+}
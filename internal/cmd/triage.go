@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/triage"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// triagedLabel marks issues that have been through gt triage so they are
+// not offered again.
+const triagedLabel = "triaged"
+
+// triageTypes are the issue types triage can assign.
+var triageTypes = map[string]bool{
+	"task":    true,
+	"bug":     true,
+	"feature": true,
+	"chore":   true,
+}
+
+var (
+	triageJSON  bool
+	triageYes   bool
+	triageLimit int
+)
+
+var triageCmd = &cobra.Command{
+	Use:     "triage [rig]",
+	GroupID: GroupWork,
+	Short:   "Suggest priority, type, rig, and duplicates for new issues",
+	Long: `Walk open, unassigned issues that have not been triaged yet and suggest
+a priority, issue type, and rig for each, plus likely duplicates among the
+town's existing issues (open and closed).
+
+Suggestions come from keyword rules and word-overlap similarity with
+existing issues. Interactively, each issue is shown with its suggestion
+and you choose what to apply:
+
+  enter/a        accept the suggestion
+  p <0-4>        change the suggested priority
+  t <type>       change the suggested type
+  d <issue>      close as a duplicate of <issue>
+  s              skip (ask again next time)
+  q              quit
+
+Accepted issues get the "triaged" label so they are not offered again.
+Rig suggestions are advisory: dispatch with 'gt sling <issue> <rig>'.
+
+For batch use (e.g. by an agent), --json prints the suggestions without
+applying anything, and --yes applies every suggestion without prompting.
+Duplicates are never closed automatically.
+
+Examples:
+  gt triage                  # Triage all rigs interactively
+  gt triage greenplace       # Only one rig
+  gt triage --json           # Suggestions for an agent to review
+  gt triage --yes --limit 5  # Apply suggestions for the first 5 issues`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTriage,
+}
+
+func init() {
+	triageCmd.Flags().BoolVar(&triageJSON, "json", false, "Print suggestions as JSON without applying them")
+	triageCmd.Flags().BoolVarP(&triageYes, "yes", "y", false, "Apply all suggestions without prompting")
+	triageCmd.Flags().IntVar(&triageLimit, "limit", 20, "Maximum issues to triage (0 = no limit)")
+	rootCmd.AddCommand(triageCmd)
+}
+
+func runTriage(cmd *cobra.Command, args []string) error {
+	if triageJSON && triageYes {
+		return fmt.Errorf("--json and --yes are mutually exclusive")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rigs := discoverRigs(townRoot)
+	if len(args) == 1 {
+		found := false
+		for _, r := range rigs {
+			if r == args[0] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("rig %q not found", args[0])
+		}
+	}
+
+	// The corpus spans every rig so duplicates and rig suggestions can
+	// cross rig boundaries; only the selected rig's issues are triaged.
+	var corpus []triage.Issue
+	var pending []*triageTarget
+	for _, rigName := range rigs {
+		b := beads.New(filepath.Join(townRoot, rigName))
+		issues, err := b.List(beads.ListOptions{Status: "all", Priority: -1})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", style.WarningPrefix, rigName, err)
+			continue
+		}
+		for _, issue := range issues {
+			if !beads.IsWorkItem(issue) {
+				continue
+			}
+			corpus = append(corpus, toTriageIssue(rigName, issue))
+			if (len(args) == 0 || args[0] == rigName) && needsTriage(issue) {
+				pending = append(pending, &triageTarget{beads: b, issue: toTriageIssue(rigName, issue)})
+			}
+		}
+	}
+
+	if triageLimit > 0 && len(pending) > triageLimit {
+		pending = pending[:triageLimit]
+	}
+
+	suggestions := make([]triage.Suggestion, len(pending))
+	for i, target := range pending {
+		suggestions[i] = triage.Suggest(target.issue, corpus)
+	}
+
+	if triageJSON {
+		if suggestions == nil {
+			suggestions = []triage.Suggestion{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(suggestions)
+	}
+
+	if len(pending) == 0 {
+		fmt.Println("No issues need triage")
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	applied := 0
+	for i, target := range pending {
+		s := suggestions[i]
+		printTriageSuggestion(i+1, len(pending), &s)
+
+		if triageYes {
+			if err := applyTriage(target, &s); err != nil {
+				fmt.Printf("  %s %v\n", style.Warning.Render("!"), err)
+				continue
+			}
+			applied++
+			continue
+		}
+
+		done, quit, err := promptTriage(reader, target, &s)
+		if err != nil {
+			fmt.Printf("  %s %v\n", style.Warning.Render("!"), err)
+		}
+		if done {
+			applied++
+		}
+		if quit {
+			break
+		}
+	}
+
+	fmt.Printf("\n%s Triaged %d of %d issue(s)\n", style.SuccessPrefix, applied, len(pending))
+	return nil
+}
+
+// triageTarget is an issue awaiting triage and the beads DB it lives in.
+type triageTarget struct {
+	beads *beads.Beads
+	issue triage.Issue
+}
+
+// needsTriage reports whether an issue should be offered for triage: open,
+// unassigned, and not already triaged.
+func needsTriage(issue *beads.Issue) bool {
+	return issue.Status == "open" && issue.Assignee == "" && !beads.HasLabel(issue, triagedLabel)
+}
+
+func toTriageIssue(rigName string, issue *beads.Issue) triage.Issue {
+	return triage.Issue{
+		Rig:         rigName,
+		ID:          issue.ID,
+		Title:       issue.Title,
+		Description: issue.Description,
+		Type:        issue.Type,
+		Priority:    issue.Priority,
+		Status:      issue.Status,
+	}
+}
+
+// currentIssueType returns an issue's type, treating unset as "task" (the
+// bd default).
+func currentIssueType(issue triage.Issue) string {
+	if issue.Type == "" {
+		return "task"
+	}
+	return issue.Type
+}
+
+func printTriageSuggestion(n, total int, s *triage.Suggestion) {
+	fmt.Printf("\n%s %s %s\n", style.Dim.Render(fmt.Sprintf("[%d/%d]", n, total)),
+		style.Bold.Render(s.Issue.ID), s.Issue.Title)
+	fmt.Printf("  Current:   P%d %s (%s)\n", s.Issue.Priority, currentIssueType(s.Issue), s.Issue.Rig)
+	rig := s.Rig
+	if rig == "" {
+		rig = s.Issue.Rig
+	}
+	fmt.Printf("  Suggested: P%d %s (%s)\n", s.Priority, s.Type, rig)
+	for _, r := range s.Reasons {
+		fmt.Printf("             %s\n", style.Dim.Render(r))
+	}
+	if len(s.Duplicates) > 0 {
+		fmt.Printf("  %s Possible duplicates:\n", style.Warning.Render("!"))
+		for _, m := range s.Duplicates {
+			fmt.Printf("    %s %.2f %s [%s]\n", m.ID, m.Score, m.Title, m.Status)
+		}
+	}
+}
+
+// promptTriage reads triage choices for one issue until it is applied,
+// skipped, or the user quits. done reports whether a change was applied.
+func promptTriage(reader *bufio.Reader, target *triageTarget, s *triage.Suggestion) (done, quit bool, err error) {
+	for {
+		fmt.Print("  [a]ccept  [p]riority N  [t]ype T  [d]uplicate ID  [s]kip  [q]uit > ")
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return false, true, readErr
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			if readErr == io.EOF {
+				return false, true, nil
+			}
+			return true, false, applyTriage(target, s)
+		}
+
+		switch fields[0] {
+		case "a", "accept":
+			return true, false, applyTriage(target, s)
+		case "s", "skip":
+			return false, false, nil
+		case "q", "quit":
+			return false, true, nil
+		case "p", "priority":
+			p, convErr := strconv.Atoi(strings.Join(fields[1:], ""))
+			if convErr != nil || p < 0 || p > 4 {
+				fmt.Println("  priority must be 0-4")
+				continue
+			}
+			s.Priority = p
+			fmt.Printf("  Suggested: P%d %s\n", s.Priority, s.Type)
+		case "t", "type":
+			if len(fields) != 2 || !triageTypes[fields[1]] {
+				fmt.Println("  type must be one of: task, bug, feature, chore")
+				continue
+			}
+			s.Type = fields[1]
+			fmt.Printf("  Suggested: P%d %s\n", s.Priority, s.Type)
+		case "d", "duplicate":
+			if len(fields) != 2 {
+				fmt.Println("  usage: d <issue>")
+				continue
+			}
+			reason := "duplicate of " + fields[1]
+			if err := target.beads.CloseWithReason(reason, s.Issue.ID); err != nil {
+				return false, false, fmt.Errorf("closing %s: %w", s.Issue.ID, err)
+			}
+			fmt.Printf("  %s Closed %s as %s\n", style.SuccessPrefix, s.Issue.ID, reason)
+			return true, false, nil
+		default:
+			fmt.Printf("  unknown choice %q\n", fields[0])
+		}
+
+		if readErr == io.EOF {
+			return false, true, nil
+		}
+	}
+}
+
+// applyTriage writes the suggested priority and type and marks the issue
+// triaged.
+func applyTriage(target *triageTarget, s *triage.Suggestion) error {
+	opts := beads.UpdateOptions{AddLabels: []string{triagedLabel}}
+	if s.Priority != s.Issue.Priority {
+		opts.Priority = &s.Priority
+	}
+	if s.Type != currentIssueType(s.Issue) {
+		opts.Type = &s.Type
+	}
+	if err := target.beads.Update(s.Issue.ID, opts); err != nil {
+		return fmt.Errorf("updating %s: %w", s.Issue.ID, err)
+	}
+	fmt.Printf("  %s %s: P%d %s\n", style.SuccessPrefix, s.Issue.ID, s.Priority, s.Type)
+	if s.Rig != "" && s.Rig != s.Issue.Rig {
+		fmt.Printf("  %s Similar work lives in %s: gt sling %s %s\n",
+			style.Dim.Render("→"), s.Rig, s.Issue.ID, s.Rig)
+	}
+	return nil
+}
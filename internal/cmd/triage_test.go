@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestNeedsTriage(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue *beads.Issue
+		want  bool
+	}{
+		{"open unassigned", &beads.Issue{Status: "open"}, true},
+		{"assigned", &beads.Issue{Status: "open", Assignee: "greenplace/polecats/Toast"}, false},
+		{"closed", &beads.Issue{Status: "closed"}, false},
+		{"already triaged", &beads.Issue{Status: "open", Labels: []string{triagedLabel}}, false},
+	}
+	for _, tt := range tests {
+		if got := needsTriage(tt.issue); got != tt.want {
+			t.Errorf("%s: needsTriage = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/templates"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/upgrade"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	upgradeCheckOnly bool
+	upgradeDryRun    bool
+	upgradeOffline   bool
+	upgradeNoRestart bool
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:     "upgrade",
+	GroupID: GroupConfig,
+	Short:   "Check for a newer gt and migrate on-disk state",
+	Long: `Bring the town in line with the running gt binary.
+
+Steps:
+  1. Check GitHub for a newer gt release (skip with --offline)
+  2. Migrate versioned state files (town.json, rigs.json, settings,
+     daemon.json, per-rig config) to the schemas this binary writes.
+     Each rewritten file is backed up as <file>.v<N>.bak
+  3. Restart the daemon on the new binary and verify it, and each rig's
+     witness, comes back up (skip with --no-restart)
+
+Run this after installing a new gt so state written by older binaries
+does not drift from what the new binary expects.
+
+Examples:
+  gt upgrade                 # Full upgrade flow
+  gt upgrade --check         # Only check for a newer release
+  gt upgrade --dry-run       # Show pending state migrations
+  gt upgrade --offline       # Skip the release check`,
+	Args: cobra.NoArgs,
+	RunE: runUpgrade,
+}
+
+func init() {
+	upgradeCmd.Flags().BoolVar(&upgradeCheckOnly, "check", false, "Only check for a newer release")
+	upgradeCmd.Flags().BoolVar(&upgradeDryRun, "dry-run", false, "Show pending migrations without writing")
+	upgradeCmd.Flags().BoolVar(&upgradeOffline, "offline", false, "Skip the release check")
+	upgradeCmd.Flags().BoolVar(&upgradeNoRestart, "no-restart", false, "Do not restart the daemon after migrating")
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	if !upgradeOffline {
+		checkLatestRelease()
+	}
+	if upgradeCheckOnly {
+		return nil
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	fmt.Printf("\n%s Migrating state\n", style.Bold.Render("📦"))
+	changes, migrateErr := upgrade.Migrate(townRoot, upgrade.Schemas(), upgradeDryRun)
+	for _, c := range changes {
+		verb := "migrated"
+		if upgradeDryRun {
+			verb = "would migrate"
+		}
+		fmt.Printf("   %s %s %s v%d → v%d  %s\n", style.Success.Render("✓"), c.Schema, verb, c.From, c.To, style.Dim.Render(c.Path))
+	}
+	if len(changes) == 0 && migrateErr == nil {
+		fmt.Printf("   %s\n", style.Dim.Render("all state files are current"))
+	}
+	if migrateErr != nil {
+		fmt.Printf("   %s %v\n", style.Error.Render("✗"), migrateErr)
+		if errors.Is(migrateErr, upgrade.ErrNewerSchema) {
+			return fmt.Errorf("state was written by a newer gt; install that version instead of downgrading")
+		}
+		return fmt.Errorf("state migration failed")
+	}
+
+	if upgradeDryRun || upgradeNoRestart {
+		return nil
+	}
+	return restartAndVerify(townRoot)
+}
+
+// checkLatestRelease reports whether a newer gt release is available.
+// Network failures are reported but never fatal.
+func checkLatestRelease() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rel, err := upgrade.LatestRelease(ctx, &http.Client{Timeout: 10 * time.Second}, upgrade.DefaultReleaseURL)
+	if err != nil {
+		fmt.Printf("%s Could not check for a newer release: %v\n", style.WarningPrefix, err)
+		return
+	}
+	if upgrade.IsNewer(Version, rel.Version) {
+		fmt.Printf("%s gt %s is available (running %s)\n", style.ArrowPrefix, style.Bold.Render(rel.Version), Version)
+		fmt.Printf("   Install it, then re-run %s\n", style.Dim.Render("gt upgrade"))
+		if rel.URL != "" {
+			fmt.Printf("   %s\n", style.Dim.Render(rel.URL))
+		}
+		return
+	}
+	fmt.Printf("%s gt %s is the latest release\n", style.SuccessPrefix, Version)
+}
+
+// restartAndVerify restarts the daemon on the current binary and checks that
+// it and the rig witnesses come back.
+func restartAndVerify(townRoot string) error {
+	running, _, err := daemon.IsRunning(townRoot)
+	if err != nil {
+		return fmt.Errorf("checking daemon status: %w", err)
+	}
+	if !running {
+		fmt.Printf("\n%s Daemon not running; skipping restart\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("\n%s Restarting daemon\n", style.Bold.Render("🔄"))
+	if err := daemon.StopDaemon(townRoot); err != nil {
+		return fmt.Errorf("stopping daemon: %w", err)
+	}
+
+	// A supervised daemon is restarted through its service manager so it
+	// stays supervised; otherwise start it directly.
+	unitPath, _ := templates.SupervisorUnitPath(runtime.GOOS)
+	if _, statErr := os.Stat(unitPath); unitPath != "" && statErr == nil {
+		if _, err := templates.ProvisionSupervisor(townRoot); err != nil {
+			return fmt.Errorf("restarting supervised daemon: %w", err)
+		}
+	} else if err := runDaemonStart(nil, nil); err != nil {
+		return err
+	}
+
+	// Give the daemon time to acquire its lock and write its PID.
+	var pid int
+	for i := 0; i < 10; i++ {
+		if running, pid, _ = daemon.IsRunning(townRoot); running {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	if !running {
+		return fmt.Errorf("daemon did not come back after restart (check 'gt daemon logs')")
+	}
+	fmt.Printf("   %s daemon running (PID %d)\n", style.Success.Render("✓"), pid)
+
+	// Witnesses are respawned by the daemon heartbeat; report any that are
+	// down so the user knows to check rather than failing the upgrade.
+	t := tmux.NewTmux()
+	for _, rigName := range discoverRigs(townRoot) {
+		name := session.WitnessSessionName(session.PrefixFor(rigName))
+		if ok, _ := t.HasSession(name); ok {
+			fmt.Printf("   %s witness (%s) running\n", style.Success.Render("✓"), rigName)
+		} else {
+			fmt.Printf("   %s witness (%s) not running; the daemon will restart it on its next heartbeat\n", style.Warning.Render("⚠"), rigName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	whoisJSON  bool
+	whoisIssue bool
+)
+
+var whoisCmd = &cobra.Command{
+	Use:     "whois <session|address>",
+	GroupID: GroupDiag,
+	Short:   "Show which agent runs in a tmux session, or where an agent runs",
+	Long: `Look up the session directory, which maps tmux sessions to the agent
+running in them: rig, role, name, and the issue it is working on.
+
+Given a tmux session name, shows the agent in it. Given an agent address
+(anything with a "/", or mayor/deacon), shows the session it runs in.
+With --issue, lists the sessions working on an issue.
+
+Sessions that were never registered are identified from their name.
+
+Examples:
+  gt whois gt-Toast
+  gt whois gastown/polecats/Toast
+  gt whois --issue gt-abc12`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWhois,
+}
+
+func init() {
+	whoisCmd.Flags().BoolVar(&whoisJSON, "json", false, "Output as JSON")
+	whoisCmd.Flags().BoolVar(&whoisIssue, "issue", false, "Treat the argument as an issue ID and list its sessions")
+	rootCmd.AddCommand(whoisCmd)
+}
+
+func runWhois(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	arg := args[0]
+
+	var recs []session.SessionRecord
+	switch {
+	case whoisIssue:
+		if recs, err = session.SessionsForIssue(townRoot, arg); err != nil {
+			return err
+		}
+		if len(recs) == 0 && !whoisJSON {
+			fmt.Printf("%s No session is working on %s\n", style.Dim.Render("○"), arg)
+			return nil
+		}
+	default:
+		name := arg
+		if isAgentAddress(arg) {
+			if name, err = session.SessionForAddress(townRoot, strings.TrimSuffix(arg, "/")); err != nil {
+				return err
+			}
+		}
+		rec, err := session.LookupSession(townRoot, name)
+		if err != nil {
+			return fmt.Errorf("unknown session %s: %w", name, err)
+		}
+		recs = []session.SessionRecord{*rec}
+	}
+
+	if whoisJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if recs == nil {
+			recs = []session.SessionRecord{}
+		}
+		return enc.Encode(recs)
+	}
+
+	t := tmux.NewTmux()
+	for i, rec := range recs {
+		if i > 0 {
+			fmt.Println()
+		}
+		printSessionRecord(t, rec)
+	}
+	return nil
+}
+
+// isAgentAddress reports whether a whois argument is an agent address
+// rather than a session name.
+func isAgentAddress(s string) bool {
+	s = strings.TrimSuffix(s, "/")
+	return strings.Contains(s, "/") || s == "mayor" || s == "deacon"
+}
+
+func printSessionRecord(t *tmux.Tmux, rec session.SessionRecord) {
+	state := style.Dim.Render("not running")
+	if ok, _ := t.HasSession(rec.Session); ok {
+		state = style.Success.Render("running")
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render(rec.Session), state)
+	fmt.Printf("  Role:    %s\n", rec.Role)
+	if rec.Rig != "" {
+		fmt.Printf("  Rig:     %s\n", rec.Rig)
+	}
+	if rec.Name != "" {
+		fmt.Printf("  Name:    %s\n", rec.Name)
+	}
+	fmt.Printf("  Address: %s\n", rec.Address)
+	if rec.Issue != "" {
+		fmt.Printf("  Issue:   %s\n", rec.Issue)
+	}
+	if !rec.StartedAt.IsZero() {
+		fmt.Printf("  Started: %s\n", rec.StartedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	if rec.Parsed {
+		fmt.Printf("  %s\n", style.Dim.Render("(not registered; identified from the session name)"))
+	}
+}
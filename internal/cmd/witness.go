@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -18,6 +20,7 @@ import (
 var (
 	witnessForeground    bool
 	witnessStatusJSON    bool
+	witnessCheckJSON     bool
 	witnessAgentOverride string
 	witnessEnvOverrides  []string
 )
@@ -35,6 +38,10 @@ The Witness patrols a single rig, watching over its polecats:
   - Cleans up zombie polecats (finished but failed to exit)
   - Nukes sandboxes when polecats complete via 'gt done'
 
+It also watches the rig's other agents, each under its own policy: a
+crashed refinery is restarted, while crew are only ever reported, never
+reaped or restarted. The daemon watches the Witness itself.
+
 The Witness does NOT force session cycles or interrupt working polecats.
 Polecats manage their own sessions (via gt handoff). The Witness handles
 failures and edge cases only.
@@ -81,11 +88,31 @@ var witnessStatusCmd = &cobra.Command{
 	Short: "Show witness status",
 	Long: `Show the status of a rig's Witness.
 
-Displays running state, monitored polecats, and statistics.`,
+Displays running state and the agents it monitors, with their session
+health and monitoring policy.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runWitnessStatus,
 }
 
+var witnessCheckAgentsCmd = &cobra.Command{
+	Use:   "check-agents <rig>",
+	Short: "Check the rig's crew and refinery sessions",
+	Long: `Check the health of a rig's crew and refinery sessions and apply their
+monitoring policies. Run by the Witness during patrol.
+
+  refinery  restarted if its agent died; reported if it has no session
+  crew      reported if its agent died; never reaped or restarted
+
+Polecats are checked by the patrol's zombie detection, and the Witness
+itself by the daemon.
+
+Examples:
+  gt witness check-agents greenplace
+  gt witness check-agents greenplace --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessCheckAgents,
+}
+
 var witnessAttachCmd = &cobra.Command{
 	Use:     "attach [rig]",
 	Aliases: []string{"at"},
@@ -129,6 +156,9 @@ func init() {
 	// Status flags
 	witnessStatusCmd.Flags().BoolVar(&witnessStatusJSON, "json", false, "Output as JSON")
 
+	// Check-agents flags
+	witnessCheckAgentsCmd.Flags().BoolVar(&witnessCheckJSON, "json", false, "Output as JSON")
+
 	// Restart flags
 	witnessRestartCmd.Flags().StringVar(&witnessAgentOverride, "agent", "", "Agent alias to run the Witness with (overrides town default)")
 	witnessRestartCmd.Flags().StringArrayVar(&witnessEnvOverrides, "env", nil, "Environment variable override (KEY=VALUE, can be repeated)")
@@ -139,6 +169,7 @@ func init() {
 	witnessCmd.AddCommand(witnessRestartCmd)
 	witnessCmd.AddCommand(witnessStatusCmd)
 	witnessCmd.AddCommand(witnessAttachCmd)
+	witnessCmd.AddCommand(witnessCheckAgentsCmd)
 
 	rootCmd.AddCommand(witnessCmd)
 }
@@ -226,10 +257,10 @@ func runWitnessStop(cmd *cobra.Command, args []string) error {
 
 // WitnessStatusOutput is the JSON output format for witness status.
 type WitnessStatusOutput struct {
-	Running           bool     `json:"running"`
-	RigName           string   `json:"rig_name"`
-	Session           string   `json:"session,omitempty"`
-	MonitoredPolecats []string `json:"monitored_polecats,omitempty"`
+	Running         bool                     `json:"running"`
+	RigName         string                   `json:"rig_name"`
+	Session         string                   `json:"session,omitempty"`
+	MonitoredAgents *witness.MonitoredAgents `json:"monitored_agents,omitempty"`
 }
 
 func runWitnessStatus(cmd *cobra.Command, args []string) error {
@@ -247,15 +278,15 @@ func runWitnessStatus(cmd *cobra.Command, args []string) error {
 	running, _ := mgr.IsRunning()
 	sessionInfo, _ := mgr.Status() // may be nil if not running
 
-	// Polecats come from rig config, not state file
-	polecats := r.Polecats
+	// Agents come from rig config, not state file
+	monitored := witness.ListMonitoredAgents(r)
 
 	// JSON output
 	if witnessStatusJSON {
 		output := WitnessStatusOutput{
-			Running:           running,
-			RigName:           rigName,
-			MonitoredPolecats: polecats,
+			Running:         running,
+			RigName:         rigName,
+			MonitoredAgents: monitored,
 		}
 		if sessionInfo != nil {
 			output.Session = sessionInfo.Name
@@ -277,19 +308,87 @@ func runWitnessStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  State: %s\n", style.Dim.Render("○ stopped"))
 	}
 
-	// Show monitored polecats
-	fmt.Printf("\n  %s\n", style.Bold.Render("Monitored Polecats:"))
-	if len(polecats) == 0 {
+	// Show monitored agents
+	fmt.Printf("\n  %s\n", style.Bold.Render("Monitored Agents:"))
+	if len(monitored.Agents) == 0 {
 		fmt.Printf("    %s\n", style.Dim.Render("(none)"))
-	} else {
-		for _, p := range polecats {
-			fmt.Printf("    • %s\n", p)
+	}
+	for _, a := range monitored.Agents {
+		label := string(a.Role)
+		if a.Name != "" {
+			label += "/" + a.Name
+		}
+		health := a.Health
+		if a.Health != tmux.SessionHealthy.String() {
+			health = style.Dim.Render(health)
 		}
+		fmt.Printf("    • %-24s %-14s %s\n", label, health, style.Dim.Render(describeMonitorPolicy(a.Policy)))
 	}
 
 	return nil
 }
 
+// describeMonitorPolicy summarizes a monitoring policy for status output.
+func describeMonitorPolicy(p witness.MonitorPolicy) string {
+	var parts []string
+	if p.AutoReap {
+		parts = append(parts, "auto-reaped")
+	}
+	if p.RestartOnCrash {
+		parts = append(parts, "restarted on crash")
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "reported only")
+	}
+	if p.CheckedBy != "witness" {
+		parts = append(parts, "checked by "+p.CheckedBy)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func runWitnessCheckAgents(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	result := witness.CheckRigAgents(townRoot, r, func() error {
+		if state, _ := getRigOperationalState(townRoot, rigName); state != "OPERATIONAL" {
+			return fmt.Errorf("rig is %s", strings.ToLower(state))
+		}
+		mgr := refinery.NewManager(r)
+		_ = tmux.NewTmux().KillSessionWithProcesses(mgr.SessionName())
+		if err := mgr.Start(false, ""); err != nil && err != refinery.ErrAlreadyRunning {
+			return err
+		}
+		return nil
+	})
+
+	if witnessCheckJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	if len(result.Unhealthy) == 0 {
+		fmt.Printf("%s %d crew/refinery session(s) checked, all healthy\n", style.SuccessPrefix, result.Checked)
+		return nil
+	}
+	for _, u := range result.Unhealthy {
+		label := string(u.Role)
+		if u.Name != "" {
+			label += "/" + u.Name
+		}
+		if u.Error != "" {
+			fmt.Printf("  %s %s (%s): %s\n", style.ErrorPrefix, label, u.Health, u.Error)
+			continue
+		}
+		fmt.Printf("  %s %s (%s): %s\n", style.WarningPrefix, label, u.Health, u.Action)
+	}
+	return nil
+}
+
 // witnessSessionName returns the tmux session name for a rig's witness.
 func witnessSessionName(rigName string) string {
 	return session.WitnessSessionName(session.PrefixFor(rigName))
@@ -334,6 +433,7 @@ func runWitnessAttach(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("tmux not found: %w", err)
 	}
 
+	tmux.RecordAttach(sessionName)
 	attachCmd := exec.Command(tmuxPath, "attach-session", "-t", sessionName)
 	attachCmd.Stdin = os.Stdin
 	attachCmd.Stdout = os.Stdout
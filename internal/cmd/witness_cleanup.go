@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	witnessCleanupDryRun bool
+	witnessCleanupForce  bool
+	witnessCleanupJSON   bool
+)
+
+var witnessCleanupCmd = &cobra.Command{
+	Use:   "cleanup <rig>/<polecat>",
+	Short: "Clean up a polecat, showing the plan first",
+	Long: `Clean up a finished or abandoned polecat the way the Witness does: kill its
+session, burn any attached molecule, push and delete its branch, remove its
+worktree, and reset its agent bead.
+
+The ordered list of actions is computed first and printed before anything
+runs, so --dry-run shows exactly what cleanup would do. The remote branch is
+kept while an MR for it is pending in the merge queue. The executed plan is
+recorded in the audit log (gt audit).
+
+Cleanup is refused when the polecat has unpushed or uncommitted work unless
+--force is given.
+
+Examples:
+  gt witness cleanup greenplace/Toast --dry-run
+  gt witness cleanup greenplace/Toast
+  gt witness cleanup greenplace/Toast --dry-run --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessCleanup,
+}
+
+func init() {
+	witnessCleanupCmd.Flags().BoolVarP(&witnessCleanupDryRun, "dry-run", "n", false, "Show the cleanup plan without executing it")
+	witnessCleanupCmd.Flags().BoolVarP(&witnessCleanupForce, "force", "f", false, "Clean up even if the polecat has unpushed work")
+	witnessCleanupCmd.Flags().BoolVar(&witnessCleanupJSON, "json", false, "Print the plan as JSON (with --dry-run)")
+	witnessCmd.AddCommand(witnessCleanupCmd)
+}
+
+func runWitnessCleanup(cmd *cobra.Command, args []string) error {
+	targets, err := resolvePolecatTargets(args, false)
+	if err != nil {
+		return err
+	}
+	if len(targets) != 1 {
+		return fmt.Errorf("expected one polecat, got %d", len(targets))
+	}
+	p := targets[0]
+	plan := planPolecatCleanup(p.polecatName, p.rigName, p.mgr, p.r)
+
+	if witnessCleanupDryRun && witnessCleanupJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan.Actions)
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render(fmt.Sprintf("Cleanup plan for %s/%s:", p.rigName, p.polecatName)))
+	printCleanupPlan(plan)
+	if witnessCleanupDryRun {
+		displayDryRunSafetyCheck(p)
+		return nil
+	}
+
+	if !witnessCleanupForce {
+		if result := checkPolecatSafety(p); result.Blocked {
+			fmt.Println()
+			displaySafetyCheckBlocked([]*SafetyCheckResult{result})
+			return fmt.Errorf("blocked: %s has active work", result.Polecat)
+		}
+	}
+
+	fmt.Printf("\nCleaning up %s/%s...\n", p.rigName, p.polecatName)
+	if err := executeCleanupPlan(plan, p.mgr, p.r); err != nil {
+		return err
+	}
+	fmt.Printf("%s Cleaned up %s/%s\n", style.SuccessPrefix, p.rigName, p.polecatName)
+	return nil
+}
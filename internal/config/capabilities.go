@@ -0,0 +1,86 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// ParseCapabilities splits a comma-separated capability list (as given to
+// gt sling --needs) into normalized tags, dropping blanks and duplicates.
+func ParseCapabilities(s string) []string {
+	return normalizeCapabilities(strings.Split(s, ","))
+}
+
+// normalizeCapabilities lowercases and trims tags, dropping blanks and
+// duplicates while keeping their order.
+func normalizeCapabilities(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
+
+// MissingCapabilities returns the tags in need that are not in have.
+func MissingCapabilities(have, need []string) []string {
+	got := make(map[string]bool)
+	for _, t := range normalizeCapabilities(have) {
+		got[t] = true
+	}
+	var missing []string
+	for _, t := range normalizeCapabilities(need) {
+		if !got[t] {
+			missing = append(missing, t)
+		}
+	}
+	return missing
+}
+
+// PolecatCapabilities returns the capabilities of a polecat running agent
+// ("" = none beyond the rig's polecat tags).
+func (c *CapabilitiesConfig) PolecatCapabilities(agent string) []string {
+	if c == nil {
+		return nil
+	}
+	return normalizeCapabilities(append(append([]string{}, c.Polecats...), c.Agents[agent]...))
+}
+
+// CrewCapabilities returns the capabilities of a crew member.
+func (c *CapabilitiesConfig) CrewCapabilities(name string) []string {
+	if c == nil {
+		return nil
+	}
+	return normalizeCapabilities(c.Crew[name])
+}
+
+// AgentNames returns the agents with capability tags, sorted.
+func (c *CapabilitiesConfig) AgentNames() []string {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.Agents))
+	for name := range c.Agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CrewNames returns the crew members with capability tags, sorted.
+func (c *CapabilitiesConfig) CrewNames() []string {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.Crew))
+	for name := range c.Crew {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
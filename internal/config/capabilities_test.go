@@ -0,0 +1,35 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	got := ParseCapabilities(" Go, db-migrations,,go ")
+	if want := []string{"go", "db-migrations"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseCapabilities = %v, want %v", got, want)
+	}
+}
+
+func TestPolecatCapabilities(t *testing.T) {
+	caps := &CapabilitiesConfig{
+		Polecats: []string{"go", "gastown-repo"},
+		Agents:   map[string][]string{"claude-opus": {"Long-Context"}},
+		Crew:     map[string][]string{"max": {"gpu"}},
+	}
+	if missing := MissingCapabilities(caps.PolecatCapabilities("claude"), []string{"go", "long-context"}); !reflect.DeepEqual(missing, []string{"long-context"}) {
+		t.Errorf("claude polecat missing = %v, want [long-context]", missing)
+	}
+	if missing := MissingCapabilities(caps.PolecatCapabilities("claude-opus"), []string{"go", "long-context"}); len(missing) != 0 {
+		t.Errorf("claude-opus polecat missing = %v, want none", missing)
+	}
+	if missing := MissingCapabilities(caps.CrewCapabilities("max"), []string{"GPU"}); len(missing) != 0 {
+		t.Errorf("crew max missing = %v, want none", missing)
+	}
+
+	var none *CapabilitiesConfig
+	if got := none.PolecatCapabilities("claude"); got != nil {
+		t.Errorf("nil config capabilities = %v, want nil", got)
+	}
+}
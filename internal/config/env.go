@@ -128,6 +128,10 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 		}
 	}
 
+	// Per-agent git author/committer identity and commit signing, if the
+	// town configures git_identity. Overrides GIT_AUTHOR_NAME above.
+	gitIdentityEnv(cfg, env)
+
 	// Only set GT_ROOT if provided
 	// Empty values would override tmux session environment
 	if cfg.TownRoot != "" {
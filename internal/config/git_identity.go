@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Commit signing formats (CommitSigningConfig.Format).
+const (
+	SigningFormatSSH = "ssh"
+	SigningFormatGPG = "gpg"
+)
+
+// Validate checks the signing format and that an ssh key file exists.
+func (c *GitIdentityConfig) Validate(townRoot string) error {
+	if c.Signing == nil {
+		return nil
+	}
+	switch c.Signing.Format {
+	case SigningFormatSSH, SigningFormatGPG:
+	default:
+		return fmt.Errorf("invalid git_identity.signing.format: must be %q or %q, got %q",
+			SigningFormatSSH, SigningFormatGPG, c.Signing.Format)
+	}
+	if c.Signing.Key == "" {
+		return fmt.Errorf("git_identity.signing.key is required")
+	}
+	if c.Signing.Format == SigningFormatSSH {
+		key := c.SigningKey(townRoot)
+		if strings.HasPrefix(key, "key::") {
+			return nil
+		}
+		if _, err := os.Stat(key); err != nil {
+			return fmt.Errorf("git_identity.signing.key: %w", err)
+		}
+	}
+	return nil
+}
+
+// SigningKey returns the value for git's user.signingkey. Relative ssh key
+// paths are resolved against the town root; gpg key IDs and literal ssh
+// keys ("key::...") are returned as-is.
+func (c *GitIdentityConfig) SigningKey(townRoot string) string {
+	if c.Signing == nil {
+		return ""
+	}
+	key := c.Signing.Key
+	if c.Signing.Format != SigningFormatSSH || strings.HasPrefix(key, "key::") {
+		return key
+	}
+	if strings.HasPrefix(key, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, key[2:])
+		}
+	}
+	if !filepath.IsAbs(key) && townRoot != "" {
+		return filepath.Join(townRoot, key)
+	}
+	return key
+}
+
+// Identity expands the name and email templates for an agent.
+// "Toast (gastown polecat) <toast@gastown.gastown.local>" with the defaults.
+func (c *GitIdentityConfig) Identity(role, rig, agentName, domain string) (name, email string) {
+	if agentName == "" {
+		agentName = role
+	}
+	if rig == "" {
+		rig = "town"
+	}
+	if domain == "" {
+		domain = DefaultAgentEmailDomain
+	}
+	r := strings.NewReplacer("{name}", agentName, "{rig}", rig, "{role}", role, "{domain}", domain)
+
+	nameTmpl, emailTmpl := c.Name, c.Email
+	if nameTmpl == "" {
+		nameTmpl = DefaultGitIdentityName
+	}
+	if emailTmpl == "" {
+		emailTmpl = DefaultGitIdentityEmail
+	}
+	return r.Replace(nameTmpl), strings.ToLower(r.Replace(emailTmpl))
+}
+
+// gitIdentityEnv sets the agent's git identity and signing variables from
+// the town's git_identity settings. Without them, env is left unchanged.
+func gitIdentityEnv(cfg AgentEnvConfig, env map[string]string) {
+	if cfg.TownRoot == "" || cfg.Role == "" {
+		return
+	}
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(cfg.TownRoot))
+	if err != nil || settings.GitIdentity == nil {
+		return
+	}
+	id := settings.GitIdentity
+
+	name, email := id.Identity(cfg.Role, cfg.Rig, cfg.AgentName, settings.AgentEmailDomain)
+	env["GIT_AUTHOR_NAME"] = name
+	env["GIT_AUTHOR_EMAIL"] = email
+	env["GIT_COMMITTER_NAME"] = name
+	env["GIT_COMMITTER_EMAIL"] = email
+
+	// Signing goes through GIT_CONFIG_* so it applies to every git command
+	// in the session without touching the repo's shared config. A broken
+	// signing setup is reported by gt doctor rather than silently dropped
+	// here: git then fails the commit, which is what orgs requiring signed
+	// commits want.
+	if id.Signing == nil {
+		return
+	}
+	format := "openpgp"
+	if id.Signing.Format == SigningFormatSSH {
+		format = "ssh"
+	}
+	for i, kv := range [][2]string{
+		{"commit.gpgsign", "true"},
+		{"gpg.format", format},
+		{"user.signingkey", id.SigningKey(cfg.TownRoot)},
+	} {
+		env[fmt.Sprintf("GIT_CONFIG_KEY_%d", i)] = kv[0]
+		env[fmt.Sprintf("GIT_CONFIG_VALUE_%d", i)] = kv[1]
+		env["GIT_CONFIG_COUNT"] = strconv.Itoa(i + 1)
+	}
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitIdentity_Defaults(t *testing.T) {
+	id := &GitIdentityConfig{}
+
+	name, email := id.Identity("polecat", "gastown", "Toast", "")
+	if name != "Toast (gastown polecat)" {
+		t.Errorf("name = %q", name)
+	}
+	if email != "toast@gastown.gastown.local" {
+		t.Errorf("email = %q", email)
+	}
+
+	name, email = id.Identity("mayor", "", "", "example.com")
+	if name != "mayor (town mayor)" || email != "mayor@town.example.com" {
+		t.Errorf("mayor identity = %q <%s>", name, email)
+	}
+}
+
+func TestGitIdentity_Templates(t *testing.T) {
+	id := &GitIdentityConfig{Name: "{name} ({rig} {role})", Email: "{name}@{rig}"}
+	name, email := id.Identity("polecat", "gastown", "Toast", "")
+	if got := name + " <" + email + ">"; got != "Toast (gastown polecat) <toast@gastown>" {
+		t.Errorf("identity = %q", got)
+	}
+}
+
+func TestGitIdentity_Validate(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.WriteFile(filepath.Join(townRoot, "agent_key"), []byte("k"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		signing *CommitSigningConfig
+		wantErr bool
+	}{
+		{"no signing", nil, false},
+		{"ssh relative key", &CommitSigningConfig{Format: "ssh", Key: "agent_key"}, false},
+		{"ssh missing key", &CommitSigningConfig{Format: "ssh", Key: "missing"}, true},
+		{"ssh literal key", &CommitSigningConfig{Format: "ssh", Key: "key::ssh-ed25519 AAAA"}, false},
+		{"gpg key id", &CommitSigningConfig{Format: "gpg", Key: "ABCD1234"}, false},
+		{"gpg without key", &CommitSigningConfig{Format: "gpg"}, true},
+		{"bad format", &CommitSigningConfig{Format: "x509", Key: "k"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&GitIdentityConfig{Signing: tt.signing}).Validate(townRoot)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAgentEnv_GitIdentity(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := AgentEnvConfig{Role: "polecat", Rig: "gastown", AgentName: "Toast", TownRoot: townRoot}
+
+	// Without git_identity, only the legacy author name is set.
+	env := AgentEnv(cfg)
+	if env["GIT_AUTHOR_NAME"] != "Toast" {
+		t.Errorf("GIT_AUTHOR_NAME = %q, want Toast", env["GIT_AUTHOR_NAME"])
+	}
+	if _, ok := env["GIT_AUTHOR_EMAIL"]; ok {
+		t.Error("GIT_AUTHOR_EMAIL set without git_identity")
+	}
+
+	settings := NewTownSettings()
+	settings.GitIdentity = &GitIdentityConfig{
+		Email:   "{name}@{rig}",
+		Signing: &CommitSigningConfig{Format: "ssh", Key: "keys/agent"},
+	}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	env = AgentEnv(cfg)
+	want := map[string]string{
+		"GIT_AUTHOR_NAME":     "Toast (gastown polecat)",
+		"GIT_AUTHOR_EMAIL":    "toast@gastown",
+		"GIT_COMMITTER_NAME":  "Toast (gastown polecat)",
+		"GIT_COMMITTER_EMAIL": "toast@gastown",
+		"GIT_CONFIG_COUNT":    "3",
+		"GIT_CONFIG_KEY_0":    "commit.gpgsign",
+		"GIT_CONFIG_VALUE_0":  "true",
+		"GIT_CONFIG_VALUE_1":  "ssh",
+		"GIT_CONFIG_VALUE_2":  filepath.Join(townRoot, "keys", "agent"),
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("%s = %q, want %q", k, env[k], v)
+		}
+	}
+}
+
+// TestAgentEnv_GitIdentitySignsCommits commits in a repo with the agent
+// environment and checks git records the identity and an SSH signature.
+func TestAgentEnv_GitIdentitySignsCommits(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+	townRoot := t.TempDir()
+	keyPath := filepath.Join(townRoot, "agent_key")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", keyPath).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen: %v\n%s", err, out)
+	}
+	settings := NewTownSettings()
+	settings.GitIdentity = &GitIdentityConfig{Signing: &CommitSigningConfig{Format: "ssh", Key: "agent_key"}}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := filepath.Join(townRoot, "repo")
+	environ := os.Environ()
+	for k, v := range AgentEnv(AgentEnvConfig{Role: "polecat", Rig: "gastown", AgentName: "Toast", TownRoot: townRoot}) {
+		environ = append(environ, k+"="+v)
+	}
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = townRoot
+		if _, err := os.Stat(repo); err == nil {
+			cmd.Dir = repo
+		}
+		cmd.Env = environ
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q", repo)
+	git("commit", "-q", "--allow-empty", "-m", "signed")
+
+	if got := git("log", "-1", "--format=%an <%ae>|%cn"); got != "Toast (gastown polecat) <toast@gastown.gastown.local>|Toast (gastown polecat)" {
+		t.Errorf("identity = %q", got)
+	}
+	raw := git("cat-file", "commit", "HEAD")
+	if !strings.Contains(raw, "BEGIN SSH SIGNATURE") {
+		t.Errorf("commit not SSH-signed:\n%s", raw)
+	}
+}
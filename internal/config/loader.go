@@ -219,9 +219,60 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	switch c.AgentVersionPolicy {
+	case "", AgentVersionRefuse, AgentVersionWarn:
+	default:
+		return fmt.Errorf("%w: must be %q or %q, got %q",
+			ErrInvalidAgentVersionPolicy, AgentVersionRefuse, AgentVersionWarn, c.AgentVersionPolicy)
+	}
+	if c.PartialClone != nil {
+		switch c.PartialClone.Filter {
+		case "", "blobless", "treeless":
+		default:
+			return fmt.Errorf("invalid partial_clone.filter: must be \"blobless\" or \"treeless\", got %q", c.PartialClone.Filter)
+		}
+	}
+	if c.PushPolicy != nil && c.PushPolicy.MaxFileSizeKB < 0 {
+		return fmt.Errorf("invalid push_policy.max_file_size_kb: must be >= 0, got %d", c.PushPolicy.MaxFileSizeKB)
+	}
+	if c.SessionTemplate != nil {
+		if err := validateSessionTemplate("session_template", c.SessionTemplate); err != nil {
+			return err
+		}
+		for role, override := range c.SessionTemplate.Roles {
+			if override == nil {
+				continue
+			}
+			if len(override.Roles) > 0 {
+				return fmt.Errorf("invalid session_template.roles.%s: roles cannot be nested", role)
+			}
+			if err := validateSessionTemplate("session_template.roles."+role, override); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateSessionTemplate validates one level of a SessionTemplateConfig.
+func validateSessionTemplate(field string, c *SessionTemplateConfig) error {
+	if _, ok := c.Hooks["pane-died"]; ok {
+		return fmt.Errorf("invalid %s.hooks: pane-died is reserved for crash detection", field)
+	}
+	for i, split := range c.Splits {
+		switch split.Direction {
+		case "", SplitRight, SplitBelow:
+		default:
+			return fmt.Errorf("invalid %s.splits[%d].direction: must be %q or %q, got %q",
+				field, i, SplitRight, SplitBelow, split.Direction)
+		}
+	}
 	return nil
 }
 
+// ErrInvalidAgentVersionPolicy indicates an invalid agent_version_policy.
+var ErrInvalidAgentVersionPolicy = errors.New("invalid agent_version_policy")
+
 // ErrInvalidOnConflict indicates an invalid on_conflict strategy.
 var ErrInvalidOnConflict = errors.New("invalid on_conflict strategy")
 
@@ -308,6 +359,17 @@ func LoadRigSettings(path string) (*RigSettings, error) {
 	return &settings, nil
 }
 
+// LoadRigSessionTemplate returns the session template for a role in the rig
+// at rigPath. It returns an empty template when the rig has none or its
+// settings can't be loaded, so callers can apply it unconditionally.
+func LoadRigSessionTemplate(rigPath, role string) *SessionTemplateConfig {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		return &SessionTemplateConfig{}
+	}
+	return settings.SessionTemplate.ForRole(role)
+}
+
 // DeprecatedMergeQueueKeys lists merge_queue config keys that have been removed.
 // target_branch and integration_branches were replaced by rig default_branch
 // and per-epic integration branch metadata.
@@ -587,6 +649,20 @@ func AddRigToDaemonPatrols(townRoot string, rigName string) error {
 // in daemon.json. Uses raw JSON manipulation to preserve fields not in PatrolConfig
 // (e.g., dolt_server config). If daemon.json doesn't exist, this is a no-op.
 func RemoveRigFromDaemonPatrols(townRoot string, rigName string) error {
+	return removeFromDaemonPatrolLists(townRoot, []string{"witness", "refinery"}, "rigs", rigName)
+}
+
+// RemoveDatabaseFromDaemonPatrols removes a database from the explicit
+// databases lists of the dolt_remotes, dolt_backup, and jsonl_git_backup
+// patrols in daemon.json, so a removed rig's database is no longer pushed or
+// backed up. If daemon.json doesn't exist, this is a no-op.
+func RemoveDatabaseFromDaemonPatrols(townRoot string, dbName string) error {
+	return removeFromDaemonPatrolLists(townRoot, []string{"dolt_remotes", "dolt_backup", "jsonl_git_backup"}, "databases", dbName)
+}
+
+// removeFromDaemonPatrolLists removes value from the string array field of
+// each named patrol in daemon.json, preserving all other fields.
+func removeFromDaemonPatrolLists(townRoot string, patrolNames []string, field, value string) error {
 	path := DaemonPatrolConfigPath(townRoot)
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
 	if err != nil {
@@ -612,7 +688,7 @@ func RemoveRigFromDaemonPatrols(townRoot string, rigName string) error {
 	}
 
 	modified := false
-	for _, patrolName := range []string{"witness", "refinery"} {
+	for _, patrolName := range patrolNames {
 		pRaw, ok := patrols[patrolName]
 		if !ok {
 			continue
@@ -623,32 +699,32 @@ func RemoveRigFromDaemonPatrols(townRoot string, rigName string) error {
 			continue
 		}
 
-		// Parse existing rigs array
-		var rigs []string
-		if rigsRaw, ok := patrol["rigs"]; ok {
-			if err := json.Unmarshal(rigsRaw, &rigs); err != nil {
-				rigs = nil
+		// Parse existing array
+		var values []string
+		if listRaw, ok := patrol[field]; ok {
+			if err := json.Unmarshal(listRaw, &values); err != nil {
+				values = nil
 			}
 		}
 
-		// Filter out the rig
+		// Filter out the value
 		var filtered []string
-		for _, r := range rigs {
-			if r != rigName {
-				filtered = append(filtered, r)
+		for _, v := range values {
+			if v != value {
+				filtered = append(filtered, v)
 			}
 		}
 
-		if len(filtered) == len(rigs) {
-			continue // Rig wasn't present
+		if len(filtered) == len(values) {
+			continue // Value wasn't present
 		}
 
 		// Update with filtered list
-		rigsJSON, err := json.Marshal(filtered)
+		listJSON, err := json.Marshal(filtered)
 		if err != nil {
-			return fmt.Errorf("encoding rigs: %w", err)
+			return fmt.Errorf("encoding %s: %w", field, err)
 		}
-		patrol["rigs"] = rigsJSON
+		patrol[field] = listJSON
 
 		patrolJSON, err := json.Marshal(patrol)
 		if err != nil {
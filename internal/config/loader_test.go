@@ -317,6 +317,25 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "agent_version_policy warn",
+			settings: &RigSettings{
+				Type:               "rig-settings",
+				Version:            1,
+				AgentVersion:       ">= 2.0",
+				AgentVersionPolicy: AgentVersionWarn,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid agent_version_policy",
+			settings: &RigSettings{
+				Type:               "rig-settings",
+				Version:            1,
+				AgentVersionPolicy: "ignore",
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid on_conflict",
 			settings: &RigSettings{
@@ -372,6 +391,30 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid session_template split direction",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				SessionTemplate: &SessionTemplateConfig{
+					Splits: []PaneSplitConfig{{Direction: "left"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "session_template role hooks pane-died",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				SessionTemplate: &SessionTemplateConfig{
+					Roles: map[string]*SessionTemplateConfig{
+						"polecat": {Hooks: map[string]string{"pane-died": "kill-session"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -431,6 +474,40 @@ func TestLoadRigConfigNotFound(t *testing.T) {
 	}
 }
 
+func TestSessionTemplateForRole(t *testing.T) {
+	t.Parallel()
+	tmpl := &SessionTemplateConfig{
+		Options: map[string]string{"history-limit": "50000", "status-right": "rig"},
+		Splits:  []PaneSplitConfig{{Command: "htop"}},
+		Env:     map[string]string{"EDITOR": "vi"},
+		Roles: map[string]*SessionTemplateConfig{
+			"polecat": {
+				Options: map[string]string{"status-right": "polecat"},
+				Splits:  []PaneSplitConfig{{Direction: SplitBelow}, {Direction: SplitRight}},
+			},
+		},
+	}
+
+	polecat := tmpl.ForRole("polecat")
+	if polecat.Options["history-limit"] != "50000" || polecat.Options["status-right"] != "polecat" {
+		t.Errorf("polecat options = %v, want rig history-limit with the role's status-right", polecat.Options)
+	}
+	if len(polecat.Splits) != 2 || polecat.Env["EDITOR"] != "vi" {
+		t.Errorf("polecat template = %+v, want the role's 2 splits and the rig env", polecat)
+	}
+	if tmpl.Options["status-right"] != "rig" {
+		t.Error("ForRole modified the rig template")
+	}
+
+	if crew := tmpl.ForRole("crew"); len(crew.Splits) != 1 || crew.Options["status-right"] != "rig" {
+		t.Errorf("crew template = %+v, want the rig template unchanged", crew)
+	}
+	var unset *SessionTemplateConfig
+	if !unset.ForRole("crew").Empty() {
+		t.Error("nil template ForRole() is not empty")
+	}
+}
+
 func TestLoadRigSettingsNotFound(t *testing.T) {
 	t.Parallel()
 	_, err := LoadRigSettings("/nonexistent/path.json")
@@ -2308,6 +2385,62 @@ func TestRemoveRigFromDaemonPatrols(t *testing.T) {
 	})
 }
 
+func TestRemoveDatabaseFromDaemonPatrols(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	mayorDir := filepath.Join(townRoot, "mayor")
+	if err := os.MkdirAll(mayorDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	daemonJSON := `{
+  "type": "daemon-patrol-config",
+  "version": 1,
+  "patrols": {
+    "witness": {"enabled": true, "rigs": ["beads"]},
+    "dolt_backup": {"enabled": true, "interval": "15m", "databases": ["hq", "beads"]},
+    "jsonl_git_backup": {"enabled": true, "databases": ["beads"], "git_repo": "/tmp/x"}
+  }
+}`
+	path := filepath.Join(mayorDir, "daemon.json")
+	if err := os.WriteFile(path, []byte(daemonJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemoveDatabaseFromDaemonPatrols(townRoot, "beads"); err != nil {
+		t.Fatalf("RemoveDatabaseFromDaemonPatrols: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Patrols map[string]struct {
+			Rigs      []string `json:"rigs"`
+			Databases []string `json:"databases"`
+			Interval  string   `json:"interval"`
+			GitRepo   string   `json:"git_repo"`
+		} `json:"patrols"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if dbs := got.Patrols["dolt_backup"].Databases; len(dbs) != 1 || dbs[0] != "hq" {
+		t.Errorf("dolt_backup databases = %v, want [hq]", dbs)
+	}
+	if dbs := got.Patrols["jsonl_git_backup"].Databases; len(dbs) != 0 {
+		t.Errorf("jsonl_git_backup databases = %v, want empty", dbs)
+	}
+	if got.Patrols["dolt_backup"].Interval != "15m" || got.Patrols["jsonl_git_backup"].GitRepo != "/tmp/x" {
+		t.Error("unrelated patrol fields were not preserved")
+	}
+	if rigs := got.Patrols["witness"].Rigs; len(rigs) != 1 {
+		t.Errorf("witness rigs = %v, should be untouched", rigs)
+	}
+}
+
+
 func TestSaveTownSettings(t *testing.T) {
 	t.Parallel()
 	t.Run("saves valid town settings", func(t *testing.T) {
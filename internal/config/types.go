@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -72,6 +73,11 @@ type TownSettings struct {
 	// Default: "gastown.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
 
+	// GitIdentity gives each agent its own git author/committer identity
+	// and optionally signs agent commits. Nil keeps the legacy behavior
+	// (GIT_AUTHOR_NAME only).
+	GitIdentity *GitIdentityConfig `json:"git_identity,omitempty"`
+
 	// WebTimeouts configures command execution timeouts for the web dashboard.
 	WebTimeouts *WebTimeoutsConfig `json:"web_timeouts,omitempty"`
 
@@ -84,6 +90,22 @@ type TownSettings struct {
 	// Convoy configures convoy behavior settings.
 	Convoy *ConvoyConfig `json:"convoy,omitempty"`
 
+	// Summarizer configures the model used to condense session transcripts,
+	// issue threads and diffs (see package summarize). Nil truncates instead.
+	Summarizer *SummarizerConfig `json:"summarizer,omitempty"`
+
+	// RoleInstructions generates a Gas Town section in agents' instructions
+	// files (CLAUDE.md, AGENTS.md) when their sessions start. Nil leaves
+	// instructions files alone; agents get their context from gt prime.
+	RoleInstructions *RoleInstructionsConfig `json:"role_instructions,omitempty"`
+
+	// Multiplexer is the terminal multiplexer agent sessions run under:
+	// "tmux" (default), "zellij", or "screen". Under zellij and screen,
+	// sessions are created, nudged, captured, attached and killed, but
+	// tmux-only extras (themes, status lines, key bindings, respawn hooks,
+	// pane process checks) are unavailable.
+	Multiplexer string `json:"multiplexer,omitempty"`
+
 	// CostTier tracks which cost tier preset was applied (informational).
 	// Actual model assignments live in RoleAgents and Agents.
 	// Values: "standard", "economy", "budget", or empty for custom configs.
@@ -91,6 +113,37 @@ type TownSettings struct {
 
 	// Scheduler configures the capacity scheduler for polecat dispatch.
 	Scheduler *capacity.SchedulerConfig `json:"scheduler,omitempty"`
+
+	// SpecDir is the directory of the declarative town spec last applied
+	// with gt apply. gt config diff and the daemon's config_drift patrol
+	// compare the town against it.
+	SpecDir string `json:"spec_dir,omitempty"`
+
+	// Features gates risky subsystems by name (see package featureflag),
+	// e.g. {"warm_pools": {"enabled": true, "rigs": ["gastown"]}}.
+	Features map[string]*FeatureFlag `json:"features,omitempty"`
+
+	// Transcripts records the input gastown sends into agent sessions
+	// under .runtime/transcripts (see gt session transcript). Off unless
+	// set; GT_TRANSCRIPT=on|off overrides it for one process.
+	Transcripts bool `json:"transcripts,omitempty"`
+}
+
+// FeatureFlag scopes a risky subsystem. A subsystem without a flag is
+// governed by its own settings alone; a flag can switch it off, limit it to
+// some rigs, or roll it out to a share of dispatches. GT_FEATURE_<NAME>
+// (on/off) overrides the flag.
+type FeatureFlag struct {
+	// Enabled turns the feature on. False switches it off everywhere.
+	Enabled bool `json:"enabled"`
+
+	// Rigs limits the feature to these rigs (empty = all rigs).
+	Rigs []string `json:"rigs,omitempty"`
+
+	// Percent enables the feature for this share of dispatches (1-99;
+	// 0 or 100 = all). Each issue always lands on the same side, so a
+	// re-dispatch gets the same answer.
+	Percent int `json:"percent,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -187,6 +240,94 @@ type ConvoyConfig struct {
 	NotifyOnComplete bool `json:"notify_on_complete,omitempty"`
 }
 
+// SummarizerConfig configures the summarizer model. Set either Command (a
+// local model reading the prompt on stdin, e.g. ["ollama", "run", "llama3.2"]
+// or ["llm", "-m", "gpt-4o-mini"]) or Endpoint (an OpenAI-compatible chat
+// completions API); Command wins when both are set.
+type SummarizerConfig struct {
+	// Command runs a local model: the prompt is written to its stdin and the
+	// summary read from its stdout.
+	Command []string `json:"command,omitempty"`
+	// Endpoint is a chat completions URL, e.g.
+	// "http://localhost:11434/v1/chat/completions".
+	Endpoint string `json:"endpoint,omitempty"`
+	// Model is the model name sent to Endpoint.
+	Model string `json:"model,omitempty"`
+	// APIKeyEnv names the environment variable holding Endpoint's API key.
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+	// Timeout bounds each summary (e.g. "60s"). Default: 60s.
+	Timeout string `json:"timeout,omitempty"`
+	// NoCache disables the summary cache in .runtime/summaries.
+	NoCache bool `json:"no_cache,omitempty"`
+}
+
+// RoleInstructionsConfig configures generated instructions files. The
+// section is rendered from settings/instructions/<role>.md.tmpl (or
+// agent.md.tmpl) in the town if present, else the built-in template. Files
+// tracked by the project are never modified, and files gastown creates are
+// excluded from git, so nothing leaks into the project's commits.
+type RoleInstructionsConfig struct {
+	// Roles to generate for. Default: polecat and crew.
+	Roles []string `json:"roles,omitempty"`
+}
+
+// DefaultRoleInstructionsRoles are the roles RoleInstructionsConfig.Roles
+// defaults to: the workers whose worktrees are project clones.
+var DefaultRoleInstructionsRoles = []string{"polecat", "crew"}
+
+// Enabled reports whether instructions are generated for role.
+func (c *RoleInstructionsConfig) Enabled(role string) bool {
+	if c == nil {
+		return false
+	}
+	roles := c.Roles
+	if len(roles) == 0 {
+		roles = DefaultRoleInstructionsRoles
+	}
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// GitIdentityConfig configures the git identity agents commit with. Name
+// and Email are templates expanded per agent:
+//
+//	{name}   agent name (polecat/crew/dog name, or the role for singletons)
+//	{rig}    rig name ("town" for town-level agents)
+//	{role}   agent role (polecat, crew, witness, ...)
+//	{domain} agent_email_domain (default "gastown.local")
+//
+// Emails are lowercased. The identity is exported to agent sessions as
+// GIT_AUTHOR_* and GIT_COMMITTER_*, so it applies to every commit made
+// in the agent's worktree regardless of the repo's user.name.
+type GitIdentityConfig struct {
+	// Name is the author name template. Default: "{name} ({rig} {role})".
+	Name string `json:"name,omitempty"`
+	// Email is the author email template. Default: "{name}@{rig}.{domain}".
+	Email string `json:"email,omitempty"`
+	// Signing signs agent commits with a town key. Nil disables signing.
+	Signing *CommitSigningConfig `json:"signing,omitempty"`
+}
+
+// CommitSigningConfig configures commit signing for agent commits.
+type CommitSigningConfig struct {
+	// Format is "ssh" or "gpg".
+	Format string `json:"format"`
+	// Key is the signing key: a key file path for ssh (relative paths are
+	// resolved against the town root), or a key ID for gpg.
+	Key string `json:"key"`
+}
+
+// Git identity defaults.
+const (
+	DefaultGitIdentityName  = "{name} ({rig} {role})"
+	DefaultGitIdentityEmail = "{name}@{rig}.{domain}"
+	DefaultAgentEmailDomain = "gastown.local"
+)
+
 // ParseDurationOrDefault parses a Go duration string, returning fallback on error or empty input.
 func ParseDurationOrDefault(s string, fallback time.Duration) time.Duration {
 	if s == "" {
@@ -330,14 +471,22 @@ type WorkflowConfig struct {
 
 // RigSettings represents per-rig behavioral configuration (settings/config.json).
 type RigSettings struct {
-	Type       string            `json:"type"`                  // "rig-settings"
-	Version    int               `json:"version"`               // schema version
-	MergeQueue *MergeQueueConfig `json:"merge_queue,omitempty"` // merge queue settings
-	Theme      *ThemeConfig      `json:"theme,omitempty"`       // tmux theme settings
-	Namepool   *NamepoolConfig   `json:"namepool,omitempty"`    // polecat name pool settings
-	Crew       *CrewConfig       `json:"crew,omitempty"`        // crew startup settings
-	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
-	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
+	Type         string              `json:"type"`                    // "rig-settings"
+	Version      int                 `json:"version"`                 // schema version
+	MergeQueue   *MergeQueueConfig   `json:"merge_queue,omitempty"`   // merge queue settings
+	Theme        *ThemeConfig        `json:"theme,omitempty"`         // tmux theme settings
+	Namepool     *NamepoolConfig     `json:"namepool,omitempty"`      // polecat name pool settings
+	Crew         *CrewConfig         `json:"crew,omitempty"`          // crew startup settings
+	Workflow     *WorkflowConfig     `json:"workflow,omitempty"`      // workflow settings
+	Staleness    *StalenessConfig    `json:"staleness,omitempty"`     // stale-work policies
+	Recording    *RecordingConfig    `json:"recording,omitempty"`     // session recording
+	Budget       *BudgetConfig       `json:"budget,omitempty"`        // spend limits
+	WorktreePool *WorktreePoolConfig `json:"worktree_pool,omitempty"` // warm polecat worktrees
+	WarmStandby  *WarmStandbyConfig  `json:"warm_standby,omitempty"`  // pre-started polecat sessions
+	PartialClone *PartialCloneConfig `json:"partial_clone,omitempty"` // monorepo clone/checkout limits
+	LFS          *LFSConfig          `json:"lfs,omitempty"`           // Git LFS content in polecat worktrees
+	PushPolicy   *PushPolicyConfig   `json:"push_policy,omitempty"`   // checks on agent pushes
+	Runtime      *RuntimeConfig      `json:"runtime,omitempty"`       // LLM runtime settings (deprecated: use Agent)
 
 	// Agent selects which agent preset to use for this rig.
 	// Can be a built-in preset ("claude", "gemini", "codex", "cursor", "auggie", "amp", "opencode", "copilot")
@@ -346,6 +495,27 @@ type RigSettings struct {
 	// Takes precedence over Runtime if both are set.
 	Agent string `json:"agent,omitempty"`
 
+	// AgentVersion pins the version of the rig's agent CLI, as a constraint:
+	// comma-separated clauses like ">= 2.0.14" or ">= 2.0, < 3", or a bare
+	// version matching its patch releases ("2.1"). Empty allows any version.
+	AgentVersion string `json:"agent_version,omitempty"`
+
+	// AgentVersionPolicy is what spawning does when the installed agent CLI
+	// doesn't satisfy AgentVersion: "refuse" (default) or "warn".
+	AgentVersionPolicy string `json:"agent_version_policy,omitempty"`
+
+	// Preflight configures the environment checks run before dispatching
+	// work to this rig (gt sling, gt doctor --rig).
+	Preflight *PreflightConfig `json:"preflight,omitempty"`
+
+	// SessionTemplate customizes the tmux sessions of the rig's agents:
+	// extra options, hooks, pane splits and environment defaults.
+	SessionTemplate *SessionTemplateConfig `json:"session_template,omitempty"`
+
+	// Capabilities tags what the rig's agents can do, for targeted
+	// dispatch (gt sling --needs).
+	Capabilities *CapabilitiesConfig `json:"capabilities,omitempty"`
+
 	// Agents defines custom agent configurations or overrides for this rig.
 	// Similar to TownSettings.Agents but applies to this rig only.
 	// Allows per-rig custom agents for polecats and crew members.
@@ -359,6 +529,50 @@ type RigSettings struct {
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
 }
 
+// Agent version policies for RigSettings.AgentVersionPolicy.
+const (
+	AgentVersionRefuse = "refuse"
+	AgentVersionWarn   = "warn"
+)
+
+// AgentVersionWarnOnly reports whether an agent version mismatch should only
+// warn rather than refuse to spawn.
+func (s *RigSettings) AgentVersionWarnOnly() bool {
+	return s != nil && s.AgentVersionPolicy == AgentVersionWarn
+}
+
+// PreflightConfig configures a rig's environment preflight: checks that a
+// polecat session can succeed (agent binary and version, remote access, test
+// gate command, secrets) run before work is dispatched, so dispatch fails
+// fast with a precise reason instead of producing a doomed session.
+type PreflightConfig struct {
+	// Disabled skips preflight when dispatching. gt doctor --rig still runs it.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Secrets lists environment variables polecats need (e.g., "NPM_TOKEN").
+	// Each must be set in the environment or in the agent's env config.
+	Secrets []string `json:"secrets,omitempty"`
+
+	// SkipRemote skips the remote reachability check (e.g., offline rigs).
+	SkipRemote bool `json:"skip_remote,omitempty"`
+}
+
+// CapabilitiesConfig tags what a rig's agents can do: languages, repos they
+// know, hardware, model traits. Tags are free-form and case-insensitive, e.g.
+// "go", "db-migrations", "gpu", "long-context". gt sling --needs dispatches
+// only to an agent that has every required tag.
+type CapabilitiesConfig struct {
+	// Polecats lists the capabilities every polecat in the rig has.
+	Polecats []string `json:"polecats,omitempty"`
+
+	// Agents maps agent names to capabilities they add to polecats running
+	// them. Example: {"claude-opus": ["long-context"]}
+	Agents map[string][]string `json:"agents,omitempty"`
+
+	// Crew maps crew member names to their capabilities.
+	Crew map[string][]string `json:"crew,omitempty"`
+}
+
 // CrewConfig represents crew workspace settings for a rig.
 type CrewConfig struct {
 	// Startup is a natural language instruction for which crew to start on boot.
@@ -463,6 +677,11 @@ type RuntimeTmuxConfig struct {
 
 	// ReadyDelayMs is a fixed delay used when prompt detection is unavailable.
 	ReadyDelayMs int `json:"ready_delay_ms,omitempty"`
+
+	// SignalPatterns adds regular expressions, by progress signal kind
+	// ("tool_call", "awaiting_input", "error", "compaction", "usage"), to
+	// the provider's pane output parser. See the progress package.
+	SignalPatterns map[string][]string `json:"signal_patterns,omitempty"`
 }
 
 // RuntimeInstructionsConfig controls the name of the role instruction file.
@@ -988,6 +1207,321 @@ func DefaultNamepoolConfig() *NamepoolConfig {
 	}
 }
 
+// Staleness policy actions.
+const (
+	StaleActionNudge    = "nudge"    // nudge the assignee
+	StaleActionReassign = "reassign" // re-sling the issue to a fresh polecat
+	StaleActionEscalate = "escalate" // escalate to the mayor
+	StaleActionLabel    = "label"    // add a label
+)
+
+// DefaultStaleExemptLabel exempts an issue from all staleness policies.
+const DefaultStaleExemptLabel = "sla:exempt"
+
+// DefaultStaleActionLabel is the label added by the "label" action when a
+// policy does not name one.
+const DefaultStaleActionLabel = "stale"
+
+// RecordingConfig controls asciicast recording of a rig's polecat sessions
+// and how long the rig's transcripts (recordings and input transcripts) are
+// kept. The witness patrol applies the retention limits with
+// 'gt session rotate'.
+type RecordingConfig struct {
+	// Enabled records every polecat session started in the rig to
+	// <town>/.runtime/recordings/, for playback with gt replay.
+	Enabled bool `json:"enabled"`
+
+	// RetentionDays deletes transcripts of ended sessions last written more
+	// than this many days ago. 0 means DefaultTranscriptRetentionDays; -1
+	// keeps them forever.
+	RetentionDays int `json:"retention_days,omitempty"`
+
+	// MaxSizeMB caps the rig's total transcript storage; the oldest ended
+	// sessions' transcripts are deleted first. 0 means
+	// DefaultTranscriptMaxSizeMB; -1 means no cap.
+	MaxSizeMB int `json:"max_size_mb,omitempty"`
+}
+
+// Transcript retention defaults.
+const (
+	DefaultTranscriptRetentionDays = 14
+	DefaultTranscriptMaxSizeMB     = 1024
+)
+
+// Retention returns how long transcripts are kept (0 = forever). Nil
+// config uses the defaults.
+func (c *RecordingConfig) Retention() time.Duration {
+	days := DefaultTranscriptRetentionDays
+	if c != nil && c.RetentionDays != 0 {
+		days = c.RetentionDays
+	}
+	if days < 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// MaxBytes returns the cap on the rig's transcript storage (0 = none). Nil
+// config uses the defaults.
+func (c *RecordingConfig) MaxBytes() int64 {
+	mb := DefaultTranscriptMaxSizeMB
+	if c != nil && c.MaxSizeMB != 0 {
+		mb = c.MaxSizeMB
+	}
+	if mb < 0 {
+		return 0
+	}
+	return int64(mb) << 20
+}
+
+// SessionTemplateConfig customizes the tmux sessions of a rig's agents
+// beyond the Gas Town theme: extra tmux options, hooks, panes split off the
+// agent pane, and environment defaults. It is applied after theming, so its
+// options win over Gas Town's (e.g., status-right).
+type SessionTemplateConfig struct {
+	// Options are tmux session options, e.g. {"history-limit": "50000"}.
+	Options map[string]string `json:"options,omitempty"`
+
+	// Hooks are tmux session hooks mapped to the tmux command they run,
+	// e.g. {"client-attached": "display-message hello"}. pane-died is
+	// reserved for Gas Town's crash detection.
+	Hooks map[string]string `json:"hooks,omitempty"`
+
+	// Splits are panes opened beside the agent pane, in order. The agent
+	// pane stays active, so nudges and captures still reach the agent.
+	Splits []PaneSplitConfig `json:"splits,omitempty"`
+
+	// Env holds environment defaults for the agent and its panes. Variables
+	// Gas Town sets (GT_ROLE, GT_RIG, ...) take precedence.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Roles overrides the template for specific roles in this rig.
+	// Keys: "witness", "refinery", "crew", "polecat". Maps are merged key by
+	// key; a role's Splits replace the rig's.
+	Roles map[string]*SessionTemplateConfig `json:"roles,omitempty"`
+}
+
+// PaneSplitConfig describes one pane split off the agent pane.
+type PaneSplitConfig struct {
+	// Direction is "right" (side by side, the default) or "below".
+	Direction string `json:"direction,omitempty"`
+
+	// Size is the new pane's size in cells or as a percentage (e.g., "30%").
+	// Empty splits the pane in half.
+	Size string `json:"size,omitempty"`
+
+	// Command runs in the new pane. Empty starts the default shell.
+	Command string `json:"command,omitempty"`
+}
+
+// Pane split directions for PaneSplitConfig.Direction.
+const (
+	SplitRight = "right"
+	SplitBelow = "below"
+)
+
+// ForRole returns the template for a role: the rig-wide template with the
+// role's overrides applied. Returns an empty template when c is nil.
+func (c *SessionTemplateConfig) ForRole(role string) *SessionTemplateConfig {
+	if c == nil {
+		return &SessionTemplateConfig{}
+	}
+	merged := &SessionTemplateConfig{
+		Options: mergeStringMaps(c.Options, nil),
+		Hooks:   mergeStringMaps(c.Hooks, nil),
+		Splits:  c.Splits,
+		Env:     mergeStringMaps(c.Env, nil),
+	}
+	if override := c.Roles[role]; override != nil {
+		merged.Options = mergeStringMaps(merged.Options, override.Options)
+		merged.Hooks = mergeStringMaps(merged.Hooks, override.Hooks)
+		merged.Env = mergeStringMaps(merged.Env, override.Env)
+		if len(override.Splits) > 0 {
+			merged.Splits = override.Splits
+		}
+	}
+	return merged
+}
+
+// Empty reports whether the template changes nothing.
+func (c *SessionTemplateConfig) Empty() bool {
+	return c == nil || (len(c.Options) == 0 && len(c.Hooks) == 0 && len(c.Splits) == 0 && len(c.Env) == 0)
+}
+
+// mergeStringMaps returns a copy of base with override's entries applied.
+func mergeStringMaps(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		out[k] = v
+	}
+	return out
+}
+
+// BudgetConfig sets a rig's spend limits, in USD, from the cost ledger
+// (gt costs). When a limit is reached, new polecat spawns for the rig are
+// blocked, its polecats are nudged to wrap up, and the mayor is notified.
+// gt budget extend raises a limit for the rest of the period.
+type BudgetConfig struct {
+	// DailyUSD limits spend per calendar day (local time). 0 means no limit.
+	DailyUSD float64 `json:"daily_usd,omitempty"`
+
+	// WeeklyUSD limits spend per week, starting Monday. 0 means no limit.
+	WeeklyUSD float64 `json:"weekly_usd,omitempty"`
+}
+
+// WorktreePoolConfig keeps a warm pool of pre-created worktrees for a rig's
+// polecats. Spawning a polecat claims one and resets it onto a fresh branch
+// instead of checking out the repo from scratch, which is the slowest part
+// of gt sling on big repos. The daemon's worktree_pool patrol keeps the pool
+// fetched, clean, and at size.
+type WorktreePoolConfig struct {
+	// Size is how many ready worktrees to keep. 0 disables the pool.
+	Size int `json:"size"`
+}
+
+// WarmStandbyConfig keeps polecats pre-spawned with the agent runtime
+// started and waiting at an idle prompt. Dispatch claims one, attaches its
+// worktree to the work branch and nudges it, so the agent's startup time
+// is not on the critical path of gt sling. The witness patrol replenishes
+// standby polecats with 'gt polecat standby maintain'. Standby sessions do
+// not count toward the scheduler's max_polecats.
+type WarmStandbyConfig struct {
+	// Size is how many standby polecats to keep. 0 disables warm standby.
+	Size int `json:"size"`
+}
+
+// PartialCloneConfig cuts disk use and setup time for very large repos.
+// Filter is recorded by 'gt rig add --partial-clone' when the rig's shared
+// repo is cloned; Sparse can be changed at any time and applies to polecat
+// worktrees created afterwards. The refinery always keeps a full checkout
+// so merges and gates see the whole tree.
+type PartialCloneConfig struct {
+	// Filter is the partial clone mode of the rig's shared repo: "blobless"
+	// (file contents fetched on demand) or "treeless" (directory trees too).
+	Filter string `json:"filter,omitempty"`
+
+	// Sparse lists the directories polecat worktrees check out (cone-mode
+	// sparse checkout). Files in the repo root are always included. Empty
+	// means the whole tree.
+	Sparse []string `json:"sparse,omitempty"`
+}
+
+// LFSConfig controls Git LFS content in a rig's polecat worktrees. Worktrees
+// are checked out with LFS pointer files, which is fast but leaves binary
+// assets unusable; with Pull set, the content is downloaded before the
+// polecat starts. Submodules are always initialized.
+type LFSConfig struct {
+	// Pull downloads LFS content into new polecat worktrees.
+	Pull bool `json:"pull"`
+
+	// Include limits the download to paths matching these patterns
+	// (git lfs pull --include). Empty means every LFS file.
+	Include []string `json:"include,omitempty"`
+}
+
+// PushPolicyConfig rejects agent pushes (gt done) whose commits add
+// oversized or forbidden files or have non-conventional messages. The agent
+// is told which commit broke which rule so it can rewrite its branch.
+type PushPolicyConfig struct {
+	// MaxFileSizeKB rejects files larger than this. Zero means no limit.
+	MaxFileSizeKB int64 `json:"max_file_size_kb,omitempty"`
+
+	// ForbiddenPaths are glob patterns of files that must not be pushed
+	// (base name, or full path if the pattern has a slash; "!" re-allows).
+	// Omitted means the built-in list (.env, keys, credentials); an empty
+	// list disables the check.
+	ForbiddenPaths []string `json:"forbidden_paths"`
+
+	// ConventionalCommits requires "type(scope): summary" commit subjects.
+	ConventionalCommits bool `json:"conventional_commits,omitempty"`
+}
+
+// StalenessConfig represents a rig's stale-work policies, evaluated by the
+// daemon's staleness patrol.
+type StalenessConfig struct {
+	// ExemptLabel exempts an issue from every policy.
+	// Default: "sla:exempt".
+	ExemptLabel string `json:"exempt_label,omitempty"`
+
+	// Policies are evaluated independently; an issue can match several.
+	Policies []StalenessPolicy `json:"policies,omitempty"`
+}
+
+// StalenessPolicy matches issues that have sat unchanged too long in some
+// state and names what to do about them. For example:
+//
+//	{"name": "stuck-work", "status": "in_progress", "older_than": "24h", "no_commits": true, "action": "nudge"}
+//	{"name": "old-blocker", "status": "open", "blocker": true, "older_than": "3d", "action": "escalate"}
+type StalenessPolicy struct {
+	// Name identifies the policy in logs and events.
+	Name string `json:"name"`
+
+	// Status is the issue status the policy applies to (e.g., "in_progress", "open", "hooked").
+	Status string `json:"status"`
+
+	// Type restricts the policy to one issue type (e.g., "bug"). Empty matches all.
+	Type string `json:"type,omitempty"`
+
+	// Label restricts the policy to issues carrying this label. Empty matches all.
+	Label string `json:"label,omitempty"`
+
+	// Blocker restricts the policy to issues that other issues depend on.
+	Blocker bool `json:"blocker,omitempty"`
+
+	// OlderThan is how long the issue must have gone without an update
+	// (e.g., "24h", "3d"). Actions repeat at this interval while the issue
+	// stays stale.
+	OlderThan string `json:"older_than"`
+
+	// NoCommits additionally requires that the assignee's worktree has had
+	// no commits within OlderThan. Issues without a worktree never match.
+	NoCommits bool `json:"no_commits,omitempty"`
+
+	// Action is one of "nudge", "reassign", "escalate", or "label".
+	Action string `json:"action"`
+
+	// ActionLabel is the label added by the "label" action. Default: "stale".
+	ActionLabel string `json:"action_label,omitempty"`
+}
+
+// Age parses OlderThan. Besides Go durations it accepts whole days ("3d").
+func (p *StalenessPolicy) Age() (time.Duration, error) {
+	if days, ok := strings.CutSuffix(p.OlderThan, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("policy %q: invalid older_than %q", p.Name, p.OlderThan)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(p.OlderThan)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("policy %q: invalid older_than %q", p.Name, p.OlderThan)
+	}
+	return d, nil
+}
+
+// Validate checks that the policy can be evaluated.
+func (p *StalenessPolicy) Validate() error {
+	if p.Status == "" {
+		return fmt.Errorf("policy %q: status is required", p.Name)
+	}
+	if _, err := p.Age(); err != nil {
+		return err
+	}
+	switch p.Action {
+	case StaleActionNudge, StaleActionReassign, StaleActionEscalate, StaleActionLabel:
+		return nil
+	default:
+		return fmt.Errorf("policy %q: unknown action %q (want nudge, reassign, escalate, or label)", p.Name, p.Action)
+	}
+}
+
 // AccountsConfig represents Claude Code account configuration (mayor/accounts.json).
 // This enables Gas Town to manage multiple Claude Code accounts with easy switching.
 type AccountsConfig struct {
@@ -1123,6 +1657,7 @@ type EscalationConfig struct {
 	//   - "email:human" → Send email to contacts.human_email
 	//   - "sms:human"   → Send SMS to contacts.human_sms
 	//   - "slack"       → Post to contacts.slack_webhook
+	//   - "popup"       → Show a summary in the operator's attached tmux client
 	//   - "log"         → Write to escalation log file
 	Routes map[string][]string `json:"routes"`
 
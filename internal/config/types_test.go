@@ -582,3 +582,64 @@ func TestParseDurationOrDefault_AllWebTimeoutDefaults(t *testing.T) {
 }
 
 
+
+func TestStalenessPolicyAge(t *testing.T) {
+	tests := []struct {
+		olderThan string
+		want      time.Duration
+		wantErr   bool
+	}{
+		{"24h", 24 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"3d", 72 * time.Hour, false},
+		{"0d", 0, true},
+		{"xd", 0, true},
+		{"-1h", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		p := StalenessPolicy{Name: "p", OlderThan: tt.olderThan}
+		got, err := p.Age()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Age(%q) error = %v, wantErr %v", tt.olderThan, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Age(%q) = %v, want %v", tt.olderThan, got, tt.want)
+		}
+	}
+}
+
+func TestStalenessPolicyValidate(t *testing.T) {
+	valid := StalenessPolicy{Name: "p", Status: "open", OlderThan: "3d", Action: StaleActionEscalate}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("valid policy: %v", err)
+	}
+
+	noStatus := valid
+	noStatus.Status = ""
+	if err := noStatus.Validate(); err == nil {
+		t.Error("policy without status should be invalid")
+	}
+
+	badAction := valid
+	badAction.Action = "delete"
+	if err := badAction.Validate(); err == nil {
+		t.Error("policy with unknown action should be invalid")
+	}
+}
+
+func TestRigSettingsStalenessRoundTrip(t *testing.T) {
+	data := `{"type":"rig-settings","version":1,"staleness":{"exempt_label":"keep","policies":[
+		{"name":"stuck","status":"in_progress","older_than":"24h","no_commits":true,"action":"nudge"}]}}`
+	var settings RigSettings
+	if err := json.Unmarshal([]byte(data), &settings); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if settings.Staleness == nil || settings.Staleness.ExemptLabel != "keep" || len(settings.Staleness.Policies) != 1 {
+		t.Fatalf("staleness = %+v", settings.Staleness)
+	}
+	if p := settings.Staleness.Policies[0]; !p.NoCommits || p.Action != StaleActionNudge {
+		t.Errorf("policy = %+v", p)
+	}
+}
@@ -44,6 +44,41 @@ const (
 	// when a transient tmux error is encountered during nudge delivery.
 	NudgeRetryInterval = 500 * time.Millisecond
 
+	// NudgeRecoverAttempts is how many times NudgeSession tries to deliver a
+	// nudge when the pane is dead or the tmux server is restarting.
+	NudgeRecoverAttempts = 4
+
+	// NudgeRecoverBackoff is the first wait between those attempts; it
+	// doubles each time (250ms, 500ms, 1s).
+	NudgeRecoverBackoff = 250 * time.Millisecond
+
+	// NudgeDeferTimeout is how long NudgeSession waits for a dialog or a
+	// full-screen program in the target pane to go away before giving up
+	// with tmux.ErrPaneBusy. Kept under nudgeLockTimeout (30s).
+	NudgeDeferTimeout = 10 * time.Second
+
+	// NudgeDeferPollInterval is how often the pane is checked meanwhile.
+	NudgeDeferPollInterval = 500 * time.Millisecond
+
+	// NudgeBufferPollInterval is how often a tmux MessageBuffer checks whether
+	// its agent is ready for input.
+	NudgeBufferPollInterval = 500 * time.Millisecond
+
+	// NudgeBufferExpiry is how long a buffered nudge waits for its agent to
+	// become ready before it is dropped. Covers a cold Claude start.
+	NudgeBufferExpiry = 2 * time.Minute
+
+	// NudgeBufferAttempts is how many times a buffered nudge is delivered
+	// (waiting for the agent to be ready again each time) before it fails.
+	NudgeBufferAttempts = 3
+
+	// SessionVerifyGrace is how long NewSessionWithCommandVerified watches a
+	// new session for its command dying before declaring it started.
+	SessionVerifyGrace = 2 * time.Second
+
+	// SessionVerifyPollInterval is how often it checks the session meanwhile.
+	SessionVerifyPollInterval = 100 * time.Millisecond
+
 	// BdCommandTimeout is the default timeout for bd (beads CLI) command
 	// execution. Used across polecat session management and plugin recording.
 	BdCommandTimeout = 30 * time.Second
@@ -311,3 +346,16 @@ var DefaultRateLimitPatterns = []string{
 	`OAuth token revoked`,                            // Token invalidated after keychain swap
 	`OAuth token has expired`,                        // Token expired — needs fresh auth
 }
+
+// DefaultRateLimitRetryPatterns indicate a session is hitting 429s but still
+// retrying on its own — the provider's limit is approaching. Matched like
+// DefaultRateLimitPatterns, and used to throttle dispatch before sessions
+// stall outright.
+var DefaultRateLimitRetryPatterns = []string{
+	`API Error.*\b429\b`,         // Claude Code: "API Error (429 ...) · Retrying in 5 seconds…"
+	`rate_limit_error`,           // Anthropic API error type
+	`\b429 Too Many Requests\b`,  // generic HTTP
+	`Rate limit reached for`,     // OpenAI / Codex
+	`RESOURCE_EXHAUSTED`,         // Gemini
+	`rate limit exceeded.*retry`, // generic client retry message
+}
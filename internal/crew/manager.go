@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/instructions"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
@@ -491,6 +492,24 @@ func (m *Manager) getLocked(name string) (*CrewWorker, error) {
 	return m.loadState(name)
 }
 
+// SetStartupPrompt sets (or, when prompt is empty, clears) the text sent
+// with the startup beacon whenever the crew worker's session starts fresh.
+func (m *Manager) SetStartupPrompt(name, prompt string) error {
+	fl, err := m.lockCrew(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	worker, err := m.getLocked(name)
+	if err != nil {
+		return err
+	}
+	worker.StartupPrompt = strings.TrimSpace(prompt)
+	worker.UpdatedAt = time.Now()
+	return m.saveState(worker)
+}
+
 // saveState persists crew worker state to disk using atomic write.
 func (m *Manager) saveState(crew *CrewWorker) error {
 	stateFile := m.stateFile(crew.Name)
@@ -685,6 +704,13 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		return fmt.Errorf("ensuring runtime settings: %w", err)
 	}
 
+	// Refresh the Gas Town section of the instructions file, if the town
+	// generates them (opt-in, see package instructions).
+	agent := instructions.Agent{TownRoot: townRoot, Rig: m.rig.Name, Role: "crew", Name: name, WorkDir: worker.ClonePath}
+	if _, err := instructions.Generate(agent, instructions.FileFor(runtimeConfig)); err != nil {
+		style.PrintWarning("could not generate instructions for %s: %v", name, err)
+	}
+
 	// Compute environment variables BEFORE creating the session.
 	// These are passed via tmux -e flags so the initial shell inherits the correct
 	// env from the start, preventing parent env (e.g., GT_ROLE=mayor) from leaking
@@ -750,6 +776,9 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 			Sender:    "human",
 			Topic:     topic,
 		})
+		if worker.StartupPrompt != "" {
+			beacon += "\n\n" + worker.StartupPrompt
+		}
 		claudeCmd, err = config.BuildStartupCommandFromConfig(config.AgentEnvConfig{
 			Role:        "crew",
 			Rig:         m.rig.Name,
@@ -803,19 +832,29 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	// initial shell inherits the correct GT_ROLE (not the parent's).
 	// See: https://github.com/anthropics/gastown/issues/280 (race condition fix)
 	// See: https://github.com/steveyegge/gastown/issues/1289 (env inheritance fix)
+	claudeCmd = session.WithTemplateEnv(claudeCmd, m.rig.Path, "crew")
 	if err := t.NewSessionWithCommandAndEnv(sessionID, worker.ClonePath, claudeCmd, envVars); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
 	// Apply rig-based theming (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, name, "crew")
+	_ = session.ConfigureSession(t, session.SessionConfig{
+		SessionID: sessionID,
+		WorkDir:   worker.ClonePath,
+		Role:      "crew",
+		RigPath:   m.rig.Path,
+		RigName:   m.rig.Name,
+		AgentName: name,
+		Theme:     &theme,
+	})
 
 	// Set up C-b n/p keybindings for crew session cycling (non-fatal)
 	_ = t.SetCrewCycleBindings(sessionID)
 
 	// Track PID for defense-in-depth orphan cleanup (non-fatal)
 	_ = session.TrackSessionPID(townRoot, sessionID, t)
+	_ = session.RegisterSession(townRoot, session.SessionRecord{Session: sessionID, Role: session.RoleCrew, Rig: m.rig.Name, Name: name})
 
 	// Wait for the agent to start, then accept the bypass permissions warning
 	// dialog if it appears. Without this, crew sessions get stuck on the
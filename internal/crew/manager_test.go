@@ -552,6 +552,45 @@ func TestManagerRenameValidatesNewName(t *testing.T) {
 	}
 }
 
+func TestManagerSetStartupPrompt(t *testing.T) {
+	tmpDir := t.TempDir()
+	rigPath := filepath.Join(tmpDir, "test-rig")
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		t.Fatalf("failed to create rig dir: %v", err)
+	}
+	bareRepoPath := filepath.Join(tmpDir, "bare-repo.git")
+	if err := runCmd("git", "init", "--bare", bareRepoPath); err != nil {
+		t.Fatalf("failed to create bare repo: %v", err)
+	}
+	r := &rig.Rig{Name: "test-rig", Path: rigPath, GitURL: bareRepoPath}
+	mgr := NewManager(r, git.NewGit(rigPath))
+
+	if err := mgr.SetStartupPrompt("ghost", "hi"); err != ErrCrewNotFound {
+		t.Fatalf("SetStartupPrompt(missing) = %v, want ErrCrewNotFound", err)
+	}
+	if _, err := mgr.Add("dave", false); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	if err := mgr.SetStartupPrompt("dave", "  Own the docs.  "); err != nil {
+		t.Fatalf("SetStartupPrompt: %v", err)
+	}
+	worker, err := mgr.Get("dave")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if worker.StartupPrompt != "Own the docs." {
+		t.Errorf("StartupPrompt = %q, want %q", worker.StartupPrompt, "Own the docs.")
+	}
+
+	if err := mgr.SetStartupPrompt("dave", ""); err != nil {
+		t.Fatalf("clearing prompt: %v", err)
+	}
+	if worker, _ := mgr.Get("dave"); worker.StartupPrompt != "" {
+		t.Errorf("StartupPrompt after clear = %q, want empty", worker.StartupPrompt)
+	}
+}
+
 func TestValidateSessionID(t *testing.T) {
 	t.Parallel()
 
@@ -22,6 +22,10 @@ type CrewWorker struct {
 
 	// UpdatedAt is when the crew worker was last updated.
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// StartupPrompt is extra instruction text sent with the startup beacon
+	// on every fresh start (not on resume).
+	StartupPrompt string `json:"startup_prompt,omitempty"`
 }
 
 // Summary provides a concise view of crew worker status.
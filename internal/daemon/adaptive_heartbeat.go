@@ -0,0 +1,125 @@
+package daemon
+
+import (
+	"time"
+)
+
+const (
+	defaultHeartbeatMin = time.Minute
+	defaultHeartbeatMax = 10 * time.Minute
+)
+
+// AdaptiveHeartbeatConfig bounds the recovery heartbeat's adaptive interval.
+//
+// After each heartbeat the daemon compares tmux session activity with the
+// previous heartbeat. When any session produced output, started, or died,
+// the next heartbeat comes after Min, so stuck or crashed agents are caught
+// quickly while work is happening. While the town stays idle the interval
+// doubles up to Max, cutting background churn for idle rigs.
+type AdaptiveHeartbeatConfig struct {
+	// Enabled controls adaptive scheduling. Default: true. When false the
+	// heartbeat runs at the fixed recovery interval (3m).
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MinStr is the interval while sessions are busy (e.g., "1m").
+	MinStr string `json:"min,omitempty"`
+
+	// MaxStr is the longest interval while the town is idle (e.g., "10m").
+	MaxStr string `json:"max,omitempty"`
+}
+
+// heartbeatBounds returns the adaptive heartbeat's min and max intervals,
+// and false when adaptive scheduling is disabled.
+func heartbeatBounds(config *DaemonPatrolConfig) (minInterval, maxInterval time.Duration, enabled bool) {
+	minInterval, maxInterval = defaultHeartbeatMin, defaultHeartbeatMax
+	if config == nil || config.AdaptiveHeartbeat == nil {
+		return minInterval, maxInterval, true
+	}
+	cfg := config.AdaptiveHeartbeat
+	if cfg.Enabled != nil && !*cfg.Enabled {
+		return 0, 0, false
+	}
+	if d, err := time.ParseDuration(cfg.MinStr); err == nil && d > 0 {
+		minInterval = d
+	}
+	if d, err := time.ParseDuration(cfg.MaxStr); err == nil && d > 0 {
+		maxInterval = d
+	}
+	if maxInterval < minInterval {
+		maxInterval = minInterval
+	}
+	return minInterval, maxInterval, true
+}
+
+// heartbeatScheduler picks the interval to the next heartbeat from how
+// tmux session activity changed since the last one.
+// Only accessed from the main loop goroutine - no sync needed.
+type heartbeatScheduler struct {
+	min, max time.Duration
+	interval time.Duration
+	seen     map[string]time.Time // session -> last activity at the previous heartbeat
+}
+
+func newHeartbeatScheduler(minInterval, maxInterval time.Duration) *heartbeatScheduler {
+	interval := recoveryHeartbeatInterval
+	if interval < minInterval {
+		interval = minInterval
+	}
+	if interval > maxInterval {
+		interval = maxInterval
+	}
+	return &heartbeatScheduler{min: minInterval, max: maxInterval, interval: interval}
+}
+
+// next records the current session activity and returns the interval to
+// the next heartbeat: min when anything changed, otherwise the previous
+// interval doubled, capped at max. The first call only records a baseline.
+func (s *heartbeatScheduler) next(activities map[string]time.Time) time.Duration {
+	if s.seen != nil {
+		if sessionsChanged(s.seen, activities) {
+			s.interval = s.min
+		} else {
+			s.interval *= 2
+			if s.interval > s.max {
+				s.interval = s.max
+			}
+		}
+	}
+	s.seen = activities
+	return s.interval
+}
+
+// sessionsChanged reports whether a session started, died, or showed
+// activity between two snapshots.
+func sessionsChanged(before, after map[string]time.Time) bool {
+	if len(before) != len(after) {
+		return true
+	}
+	for name, activity := range after {
+		prev, ok := before[name]
+		if !ok || activity.After(prev) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextHeartbeatInterval returns the interval to the next heartbeat. Falls
+// back to the fixed recovery interval when adaptive scheduling is off or
+// tmux can't be queried.
+func (d *Daemon) nextHeartbeatInterval() time.Duration {
+	if d.heartbeatScheduler == nil {
+		return recoveryHeartbeatInterval
+	}
+	activities, err := d.tmux.SessionActivities()
+	if err != nil {
+		d.logger.Printf("adaptive heartbeat: reading session activity: %v", err)
+		return recoveryHeartbeatInterval
+	}
+	prev := d.heartbeatScheduler.interval
+	interval := d.heartbeatScheduler.next(activities)
+	if interval != prev {
+		d.logger.Printf("Heartbeat interval now %v", interval)
+	}
+	return interval
+}
@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHeartbeatSchedulerAdapts(t *testing.T) {
+	s := newHeartbeatScheduler(time.Minute, 10*time.Minute)
+	t0 := time.Unix(1000, 0)
+
+	steps := []struct {
+		name       string
+		activities map[string]time.Time
+		want       time.Duration
+	}{
+		{"baseline", map[string]time.Time{"gt-witness": t0, "gt-toast": t0}, recoveryHeartbeatInterval},
+		{"idle doubles", map[string]time.Time{"gt-witness": t0, "gt-toast": t0}, 6 * time.Minute},
+		{"idle caps at max", map[string]time.Time{"gt-witness": t0, "gt-toast": t0}, 10 * time.Minute},
+		{"output drops to min", map[string]time.Time{"gt-witness": t0, "gt-toast": t0.Add(time.Minute)}, time.Minute},
+		{"idle again", map[string]time.Time{"gt-witness": t0, "gt-toast": t0.Add(time.Minute)}, 2 * time.Minute},
+		{"session died", map[string]time.Time{"gt-witness": t0}, time.Minute},
+		{"session started", map[string]time.Time{"gt-witness": t0, "gt-nux": t0}, time.Minute},
+	}
+	for _, step := range steps {
+		if got := s.next(step.activities); got != step.want {
+			t.Errorf("%s: next() = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestHeartbeatBounds(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name     string
+		config   *DaemonPatrolConfig
+		min, max time.Duration
+		enabled  bool
+	}{
+		{"default", nil, defaultHeartbeatMin, defaultHeartbeatMax, true},
+		{"configured", &DaemonPatrolConfig{AdaptiveHeartbeat: &AdaptiveHeartbeatConfig{MinStr: "30s", MaxStr: "20m"}},
+			30 * time.Second, 20 * time.Minute, true},
+		{"max below min", &DaemonPatrolConfig{AdaptiveHeartbeat: &AdaptiveHeartbeatConfig{MinStr: "5m", MaxStr: "2m"}},
+			5 * time.Minute, 5 * time.Minute, true},
+		{"disabled", &DaemonPatrolConfig{AdaptiveHeartbeat: &AdaptiveHeartbeatConfig{Enabled: &disabled}}, 0, 0, false},
+	}
+	for _, tt := range tests {
+		minInterval, maxInterval, enabled := heartbeatBounds(tt.config)
+		if minInterval != tt.min || maxInterval != tt.max || enabled != tt.enabled {
+			t.Errorf("%s: heartbeatBounds() = %v, %v, %v; want %v, %v, %v",
+				tt.name, minInterval, maxInterval, enabled, tt.min, tt.max, tt.enabled)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/progress"
+	"github.com/steveyegge/gastown/internal/quota"
+	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/takeover"
+)
+
+const (
+	defaultAgentSignalsInterval = time.Minute
+
+	// agentSignalLines is how much of each pane is parsed.
+	agentSignalLines = 40
+)
+
+// AgentSignalsConfig holds configuration for the agent_signals patrol.
+//
+// The patrol parses each agent session's pane output with its runtime's
+// output parser (see package progress) and publishes new signals as
+// agent_signal events: tool calls and usage readouts to the raw events
+// log, questions for the user, error banners and compactions to the feed.
+// Agents without hooks to re-run gt prime are told to re-prime after their
+// context is compacted. On by default.
+type AgentSignalsConfig struct {
+	// Enabled controls whether the patrol runs.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to scan (e.g., "1m").
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// agentSignalsInterval returns the configured interval, or the default (1m).
+func agentSignalsInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.AgentSignals != nil {
+		if config.Patrols.AgentSignals.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.AgentSignals.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultAgentSignalsInterval
+}
+
+// feedSignals are the signal kinds worth a line in the feed.
+var feedSignals = map[progress.Kind]bool{
+	progress.KindAwaitingInput: true,
+	progress.KindError:         true,
+	progress.KindCompaction:    true,
+}
+
+// scanAgentSignals parses every agent pane and publishes the signals that
+// appeared since the last scan.
+func (d *Daemon) scanAgentSignals() {
+	townRoot := d.config.TownRoot
+	sessions, err := d.tmux.ListSessions()
+	if err != nil {
+		d.logger.Printf("agent_signals: listing sessions: %v", err)
+		return
+	}
+	state, err := progress.LoadState(townRoot)
+	if err != nil {
+		d.logger.Printf("agent_signals: loading state: %v", err)
+		state = &progress.State{Sessions: map[string]*progress.SessionState{}}
+	}
+
+	now := time.Now()
+	resolver := &quota.ProviderResolver{TownRoot: townRoot}
+	var agents []string
+	for _, name := range sessions {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		agents = append(agents, name)
+		lines, err := d.tmux.CapturePaneLines(name, agentSignalLines)
+		if err != nil {
+			continue
+		}
+		rc := resolver.SessionRuntime(d.tmux, name)
+		fresh := state.Observe(name, progress.ParserFor(rc).Parse(lines), now)
+		for _, sig := range fresh {
+			payload := events.AgentSignalPayload(name, string(sig.Kind), sig.Detail, sig.Tokens, sig.ContextLeft)
+			if feedSignals[sig.Kind] {
+				_ = events.LogFeed(events.TypeAgentSignal, "daemon", payload)
+			} else {
+				_ = events.LogAudit(events.TypeAgentSignal, "daemon", payload)
+			}
+		}
+		if _, compacted := progress.Last(fresh, progress.KindCompaction); compacted {
+			d.reprimeAfterCompaction(name, string(id.Role), runtime.StartupFallbackCommands(string(id.Role), rc))
+		}
+	}
+
+	state.Prune(agents)
+	if err := progress.SaveState(townRoot, state); err != nil {
+		d.logger.Printf("agent_signals: saving state: %v", err)
+	}
+}
+
+// reprimeAfterCompaction tells an agent whose context was just compacted to
+// run its startup commands again. Agents with hooks re-prime themselves
+// (fallback is empty); a human-driven session or a quiet window is left
+// alone.
+func (d *Daemon) reprimeAfterCompaction(sessionName, role string, fallback []string) {
+	if len(fallback) == 0 || takeover.IsTakenOver(d.config.TownRoot, sessionName) {
+		return
+	}
+	if qs := d.quietStatus(); qs.Active {
+		return
+	}
+	msg := "Your context was just compacted. Run `" + fallback[0] + "` to restore your " + role + " context."
+	if err := d.tmux.NudgeSession(sessionName, msg); err != nil {
+		d.logger.Printf("agent_signals: re-priming %s: %v", sessionName, err)
+		return
+	}
+	d.logger.Printf("agent_signals: re-primed %s after compaction", sessionName)
+}
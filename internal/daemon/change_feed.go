@@ -0,0 +1,288 @@
+package daemon
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+const (
+	defaultChangeFeedInterval = 15 * time.Second
+	changeFeedQueryTimeout    = 10 * time.Second
+	// changeFeedMaxEvents caps events emitted per database per tick, so a
+	// bulk import does not flood the event log.
+	changeFeedMaxEvents = 200
+)
+
+// ChangeFeedConfig holds configuration for the change_feed patrol.
+//
+// The change feed tails Dolt commit history for the beads databases and
+// turns row changes into events in the town event log (.events.jsonl):
+// new issues, status changes, new mail, and new merge requests. Supervisors
+// can react to those events instead of polling bd on a timer.
+type ChangeFeedConfig struct {
+	// Enabled controls whether the change feed runs.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to check for new commits (e.g., "15s").
+	IntervalStr string `json:"interval,omitempty"`
+
+	// Databases lists specific database names to tail.
+	// If empty, auto-discovers from dolt server.
+	Databases []string `json:"databases,omitempty"`
+}
+
+// changeFeedInterval returns the configured interval, or the default (15s).
+func changeFeedInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.ChangeFeed != nil {
+		if config.Patrols.ChangeFeed.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.ChangeFeed.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultChangeFeedInterval
+}
+
+// changeFeedCursor is how far the feed has read one database.
+type changeFeedCursor struct {
+	// Commit is the last Dolt commit whose changes were emitted.
+	Commit string `json:"commit"`
+	// WispsSince is the newest wisp created_at seen. Wisps are dolt_ignored,
+	// so they never show up in commit diffs and are tailed by timestamp.
+	WispsSince time.Time `json:"wisps_since"`
+}
+
+// changeFeedState persists cursors so a daemon restart neither replays old
+// changes nor misses ones made while it was down.
+type changeFeedState struct {
+	Databases map[string]*changeFeedCursor `json:"databases"`
+}
+
+func changeFeedStateFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "change_feed.json")
+}
+
+func loadChangeFeedState(townRoot string) *changeFeedState {
+	state := &changeFeedState{}
+	if data, err := os.ReadFile(changeFeedStateFile(townRoot)); err == nil {
+		_ = json.Unmarshal(data, state)
+	}
+	if state.Databases == nil {
+		state.Databases = make(map[string]*changeFeedCursor)
+	}
+	return state
+}
+
+// validCommitHash matches Dolt commit hashes (base32, lowercase). Hashes are
+// interpolated into dolt_diff() calls, so they are validated first.
+var validCommitHash = regexp.MustCompile(`^[0-9a-v]{32}$`)
+
+// changeEvent is one event to emit.
+type changeEvent struct {
+	Type    string
+	Payload map[string]interface{}
+}
+
+// issueDiffRow is one row of dolt_diff(from, to, 'issues').
+type issueDiffRow struct {
+	DiffType   string // "added", "modified", or "removed"
+	ID         string
+	Title      string
+	FromStatus string
+	ToStatus   string
+}
+
+// issueChangeEvents converts an issues diff into events. Issues in mailIDs
+// were created as mail and are reported as mail, not as new issues.
+func issueChangeEvents(dbName string, rows []issueDiffRow, mailIDs map[string]bool) []changeEvent {
+	var out []changeEvent
+	for _, r := range rows {
+		switch {
+		case r.DiffType == "added" && mailIDs[r.ID]:
+			out = append(out, changeEvent{events.TypeMailCreated, events.ChangePayload(dbName, r.ID, r.Title, "", "")})
+		case r.DiffType == "added":
+			out = append(out, changeEvent{events.TypeIssueCreated, events.ChangePayload(dbName, r.ID, r.Title, "", r.ToStatus)})
+		case r.DiffType == "modified" && r.FromStatus != r.ToStatus:
+			out = append(out, changeEvent{events.TypeIssueStatusChanged, events.ChangePayload(dbName, r.ID, r.Title, r.FromStatus, r.ToStatus)})
+		}
+	}
+	return out
+}
+
+// tailChangeFeed emits events for changes committed since the last tick.
+// Non-fatal: errors are logged and the database is retried next tick.
+func (d *Daemon) tailChangeFeed() {
+	if !IsPatrolEnabled(d.patrolConfig, "change_feed") {
+		return
+	}
+
+	databases := d.patrolConfig.Patrols.ChangeFeed.Databases
+	if len(databases) == 0 {
+		databases = d.discoverDoltDatabases()
+	}
+
+	state := loadChangeFeedState(d.config.TownRoot)
+	for _, dbName := range databases {
+		if !validDBName.MatchString(dbName) {
+			d.logger.Printf("change_feed: skipping invalid database name: %q", dbName)
+			continue
+		}
+		cur := state.Databases[dbName]
+		if cur == nil {
+			cur = &changeFeedCursor{}
+			state.Databases[dbName] = cur
+		}
+		evts, err := d.readChanges(dbName, cur)
+		if err != nil {
+			d.logger.Printf("change_feed: %s: %v", dbName, err)
+			continue
+		}
+		if len(evts) > changeFeedMaxEvents {
+			d.logger.Printf("change_feed: %s: %d changes, emitting first %d", dbName, len(evts), changeFeedMaxEvents)
+			evts = evts[:changeFeedMaxEvents]
+		}
+		for _, e := range evts {
+			_ = events.LogAudit(e.Type, "change-feed", e.Payload)
+		}
+	}
+
+	if err := util.EnsureDirAndWriteJSON(changeFeedStateFile(d.config.TownRoot), state); err != nil {
+		d.logger.Printf("change_feed: saving state: %v", err)
+	}
+}
+
+// readChanges reads one database's changes since cur and advances cur. On
+// first sight of a database the cursor is set to the present without
+// emitting anything: the feed reports new changes, not history.
+func (d *Daemon) readChanges(dbName string, cur *changeFeedCursor) ([]changeEvent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), changeFeedQueryTimeout)
+	defer cancel()
+
+	dsn := fmt.Sprintf("root@tcp(%s:%d)/%s?parseTime=true&timeout=5s&readTimeout=10s",
+		"127.0.0.1", d.doltServerPort(), dbName)
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open connection: %w", err)
+	}
+	defer db.Close()
+
+	var head string
+	if err := db.QueryRowContext(ctx, "SELECT commit_hash FROM dolt_log LIMIT 1").Scan(&head); err != nil {
+		return nil, fmt.Errorf("reading head commit: %w", err)
+	}
+
+	if cur.Commit == "" {
+		cur.Commit = head
+		cur.WispsSince = time.Now().UTC()
+		return nil, nil
+	}
+
+	var out []changeEvent
+	if head != cur.Commit {
+		if !validCommitHash.MatchString(cur.Commit) || !validCommitHash.MatchString(head) {
+			return nil, fmt.Errorf("unexpected commit hash %q..%q", cur.Commit, head)
+		}
+		mailIDs, err := addedMailIDs(ctx, db, cur.Commit, head)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := issueDiff(ctx, db, cur.Commit, head)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, issueChangeEvents(dbName, rows, mailIDs)...)
+	}
+
+	mrs, newest, err := newMergeRequests(ctx, db, dbName, cur.WispsSince)
+	if err != nil {
+		// Databases without a wisps table still get issue events.
+		d.logger.Printf("change_feed: %s: reading wisps: %v", dbName, err)
+	} else {
+		out = append(out, mrs...)
+		cur.WispsSince = newest
+	}
+
+	cur.Commit = head
+	return out, nil
+}
+
+// issueDiff returns the issue rows that changed between two commits.
+func issueDiff(ctx context.Context, db *sql.DB, from, to string) ([]issueDiffRow, error) {
+	query := fmt.Sprintf(`SELECT diff_type,
+		COALESCE(to_id, from_id), COALESCE(to_title, from_title, ''),
+		COALESCE(from_status, ''), COALESCE(to_status, '')
+		FROM dolt_diff('%s', '%s', 'issues')`, from, to)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("diffing issues: %w", err)
+	}
+	defer rows.Close()
+
+	var out []issueDiffRow
+	for rows.Next() {
+		var r issueDiffRow
+		if err := rows.Scan(&r.DiffType, &r.ID, &r.Title, &r.FromStatus, &r.ToStatus); err != nil {
+			return nil, fmt.Errorf("scanning issue diff: %w", err)
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// addedMailIDs returns issues that gained the gt:message label between two
+// commits, i.e. newly sent mail.
+func addedMailIDs(ctx context.Context, db *sql.DB, from, to string) (map[string]bool, error) {
+	query := fmt.Sprintf(`SELECT to_issue_id FROM dolt_diff('%s', '%s', 'labels')
+		WHERE diff_type = 'added' AND to_label = 'gt:message'`, from, to)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("diffing labels: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning label diff: %w", err)
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
+}
+
+// newMergeRequests returns events for merge-request wisps created after
+// since, and the newest created_at seen (since itself if there are none).
+func newMergeRequests(ctx context.Context, db *sql.DB, dbName string, since time.Time) ([]changeEvent, time.Time, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT id, title, created_at FROM wisps WHERE issue_type = 'merge-request' AND created_at > ? ORDER BY created_at",
+		since)
+	if err != nil {
+		return nil, since, err
+	}
+	defer rows.Close()
+
+	newest := since
+	var out []changeEvent
+	for rows.Next() {
+		var id, title string
+		var created time.Time
+		if err := rows.Scan(&id, &title, &created); err != nil {
+			return nil, since, err
+		}
+		out = append(out, changeEvent{events.TypeMRCreated, events.ChangePayload(dbName, id, title, "", "")})
+		if created.After(newest) {
+			newest = created
+		}
+	}
+	return out, newest, rows.Err()
+}
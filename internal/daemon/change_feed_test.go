@@ -0,0 +1,59 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestChangeFeedInterval(t *testing.T) {
+	if got := changeFeedInterval(nil); got != defaultChangeFeedInterval {
+		t.Errorf("expected default interval %v, got %v", defaultChangeFeedInterval, got)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			ChangeFeed: &ChangeFeedConfig{Enabled: true, IntervalStr: "5s"},
+		},
+	}
+	if got := changeFeedInterval(config); got != 5*time.Second {
+		t.Errorf("expected 5s interval, got %v", got)
+	}
+	if !IsPatrolEnabled(config, "change_feed") {
+		t.Error("change_feed should be enabled")
+	}
+	if IsPatrolEnabled(nil, "change_feed") {
+		t.Error("change_feed should be opt-in")
+	}
+}
+
+func TestIssueChangeEvents(t *testing.T) {
+	rows := []issueDiffRow{
+		{DiffType: "added", ID: "gt-1", Title: "New bug", ToStatus: "open"},
+		{DiffType: "added", ID: "hq-m1", Title: "Hello", ToStatus: "open"},
+		{DiffType: "modified", ID: "gt-2", Title: "Work", FromStatus: "open", ToStatus: "in_progress"},
+		{DiffType: "modified", ID: "gt-3", Title: "Retitled", FromStatus: "open", ToStatus: "open"},
+		{DiffType: "removed", ID: "gt-4", Title: "Gone", FromStatus: "closed"},
+	}
+	got := issueChangeEvents("gastown", rows, map[string]bool{"hq-m1": true})
+
+	want := []struct {
+		typ, issue string
+	}{
+		{events.TypeIssueCreated, "gt-1"},
+		{events.TypeMailCreated, "hq-m1"},
+		{events.TypeIssueStatusChanged, "gt-2"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Type != w.typ || got[i].Payload["issue"] != w.issue || got[i].Payload["db"] != "gastown" {
+			t.Errorf("event %d = %+v, want %s for %s", i, got[i], w.typ, w.issue)
+		}
+	}
+	if got[2].Payload["from"] != "open" || got[2].Payload["to"] != "in_progress" {
+		t.Errorf("status change payload = %+v", got[2].Payload)
+	}
+}
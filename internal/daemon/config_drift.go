@@ -0,0 +1,134 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/townspec"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+const (
+	defaultConfigDriftInterval = time.Hour
+	configDriftMailTimeout     = 30 * time.Second
+)
+
+// ConfigDriftConfig holds configuration for the config_drift patrol.
+//
+// The patrol compares the town with the declarative spec last applied by
+// gt apply (spec_dir in settings/config.json) and mails the mayor when the
+// town has drifted from it: rigs or crew added by hand, declared ones
+// missing, or settings changed outside the spec.
+type ConfigDriftConfig struct {
+	// Enabled controls whether the config drift patrol runs.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to compare (e.g., "1h").
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// configDriftInterval returns the configured interval, or the default (1h).
+func configDriftInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.ConfigDrift != nil {
+		if config.Patrols.ConfigDrift.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.ConfigDrift.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultConfigDriftInterval
+}
+
+// configDriftState is the drift last reported, so the mayor hears about
+// each drift once rather than every interval.
+type configDriftState struct {
+	Report     string    `json:"report"`
+	ReportedAt time.Time `json:"reported_at"`
+}
+
+func configDriftStateFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "config_drift.json")
+}
+
+// checkConfigDrift compares the town with its spec and mails the mayor
+// when the drift differs from what was last reported.
+func (d *Daemon) checkConfigDrift() {
+	townRoot := d.config.TownRoot
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		d.logger.Printf("config_drift: loading town settings: %v", err)
+		return
+	}
+	if settings.SpecDir == "" {
+		return // no spec applied yet
+	}
+
+	report, err := configDriftReport(townRoot, settings.SpecDir)
+	if err != nil {
+		d.logger.Printf("config_drift: %v", err)
+		return
+	}
+
+	stateFile := configDriftStateFile(townRoot)
+	var state configDriftState
+	if data, err := os.ReadFile(stateFile); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	if report == state.Report {
+		return
+	}
+	if report == "" {
+		d.logger.Printf("config_drift: town matches %s again", settings.SpecDir)
+	} else {
+		d.logger.Printf("config_drift: town has drifted from %s", settings.SpecDir)
+		if err := d.mailConfigDrift(settings.SpecDir, report); err != nil {
+			d.logger.Printf("config_drift: mailing mayor: %v", err)
+			return // retry next interval
+		}
+	}
+	state = configDriftState{Report: report, ReportedAt: time.Now()}
+	if err := util.AtomicWriteJSON(stateFile, state); err != nil {
+		d.logger.Printf("config_drift: saving state: %v", err)
+	}
+}
+
+// configDriftReport describes how the town differs from the spec in
+// specDir, or returns "" when it matches.
+func configDriftReport(townRoot, specDir string) (string, error) {
+	spec, err := townspec.Load(specDir)
+	if err != nil {
+		return "", fmt.Errorf("loading spec: %w", err)
+	}
+	plan, err := townspec.Diff(townRoot, spec)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(plan.Drift(), "\n"), nil
+}
+
+func (d *Daemon) mailConfigDrift(specDir, report string) error {
+	subject := "CONFIG DRIFT: town differs from its spec"
+	body := fmt.Sprintf(`The town no longer matches the spec in %s:
+
+%s
+
+Review with: gt config diff
+Reconcile with: gt apply %s (or update the spec to match)`, specDir, report, specDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), configDriftMailTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, "mail", "send", "mayor/", "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v (output: %s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigDriftInterval(t *testing.T) {
+	if got := configDriftInterval(nil); got != defaultConfigDriftInterval {
+		t.Errorf("configDriftInterval(nil) = %v, want %v", got, defaultConfigDriftInterval)
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{ConfigDrift: &ConfigDriftConfig{Enabled: true, IntervalStr: "15m"}}}
+	if got := configDriftInterval(cfg); got != 15*time.Minute {
+		t.Errorf("configDriftInterval() = %v, want 15m", got)
+	}
+	if !IsPatrolEnabled(cfg, "config_drift") {
+		t.Error("config_drift not enabled")
+	}
+	if IsPatrolEnabled(&DaemonPatrolConfig{Patrols: &PatrolsConfig{}}, "config_drift") {
+		t.Error("config_drift should be opt-in")
+	}
+}
+
+func TestConfigDriftReport(t *testing.T) {
+	town := t.TempDir()
+	specDir := filepath.Join(town, "spec")
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(town, "mayor", "rigs.json"), `{"version":1,"rigs":{"gastown":{"git_url":"https://example.com/g.git"}}}`)
+	write(filepath.Join(specDir, "town.yaml"), "rigs:\n  gastown: {}\n")
+
+	report, err := configDriftReport(town, specDir)
+	if err != nil || report != "" {
+		t.Fatalf("configDriftReport() = %q, %v; want no drift", report, err)
+	}
+
+	write(filepath.Join(town, "mayor", "rigs.json"),
+		`{"version":1,"rigs":{"gastown":{"git_url":"https://example.com/g.git"},"extra":{"git_url":"https://example.com/e.git"}}}`)
+	report, err = configDriftReport(town, specDir)
+	if err != nil || !strings.Contains(report, "rig extra: live, not declared") {
+		t.Errorf("configDriftReport() = %q, %v; want the hand-added rig", report, err)
+	}
+}
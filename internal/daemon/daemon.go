@@ -25,12 +25,16 @@ import (
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/failure"
+	"github.com/steveyegge/gastown/internal/featureflag"
 	"github.com/steveyegge/gastown/internal/feed"
 	gitpkg "github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/platform"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/takeover"
 	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
@@ -71,6 +75,11 @@ type Daemon struct {
 	// Only accessed from heartbeat loop goroutine - no sync needed.
 	syncFailures map[string]int
 
+	// heldFailures maps polecat sessions held down after a classified failure
+	// to the failure's time, so the witness is told once per failure.
+	// Only accessed from heartbeat loop goroutine - no sync needed.
+	heldFailures map[string]time.Time
+
 	// PATCH-006: Resolved binary paths to avoid PATH issues in subprocesses.
 	gtPath string
 	bdPath string
@@ -91,6 +100,16 @@ type Daemon struct {
 	// Option B throttling: only pour when anomaly detected AND cooldown elapsed.
 	// Only accessed from heartbeat loop goroutine - no sync needed.
 	lastDoctorMolTime time.Time
+
+	// heartbeatScheduler adapts the heartbeat interval to session activity.
+	// Nil when adaptive scheduling is disabled.
+	heartbeatScheduler *heartbeatScheduler
+
+	// patrolDurations holds how long patrols took since the last metrics
+	// sample, and metricsLastSample when that sample was taken.
+	// Only accessed from the main loop goroutine - no sync needed.
+	patrolDurations   map[string]time.Duration
+	metricsLastSample time.Time
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -149,6 +168,17 @@ func New(config *Config) (*Daemon, error) {
 			os.Setenv(k, v)
 			logger.Printf("Set env %s=%s from daemon.json", k, v)
 		}
+		if qh := patrolConfig.QuietHours; qh != nil {
+			if err := qh.Validate(); err != nil {
+				logger.Printf("Warning: %v", err)
+			}
+		}
+		if err := ValidateDryRun(patrolConfig.DryRun); err != nil {
+			logger.Printf("Warning: daemon.json dry_run: %v", err)
+		}
+	}
+	if dryRun := append(append([]string{}, config.DryRun...), patrolDryRun(patrolConfig)...); len(dryRun) > 0 {
+		logger.Printf("Dry-run patrols: %s (intended actions are logged, not taken)", strings.Join(dryRun, ", "))
 	}
 
 	// Initialize Dolt server manager if configured
@@ -256,6 +286,17 @@ func (d *Daemon) Run() error {
 		return err
 	}
 
+	// Pre-flight check: refuse to run on a tmux too old to create sessions,
+	// rather than failing later with cryptic "unknown flag" errors mid-dispatch.
+	if err := tmux.CheckVersion(); err != nil {
+		if errors.Is(err, tmux.ErrTmuxTooOld) {
+			return err
+		}
+		d.logger.Printf("Warning: could not detect tmux version: %v", err)
+	} else if v, _ := tmux.DetectVersion(); !v.Supports(tmux.FeatureNewSessionEnv) {
+		d.logger.Printf("tmux %s lacks %s; using set-environment fallback", v, tmux.FeatureNewSessionEnv)
+	}
+
 	// Repair metadata.json for all rigs on startup.
 	// This ensures all rigs have proper Dolt server configuration.
 	if _, errs := doltserver.EnsureAllMetadata(d.config.TownRoot); len(errs) > 0 {
@@ -280,16 +321,26 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
 
+	// Record where the town's Dolt port and tmux socket live, so gt status
+	// can show them and other towns on this machine allocate around them.
+	d.registerEndpoints()
+
 	// Handle signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, daemonSignals()...)
 
-	// Fixed recovery-focused heartbeat (no activity-based backoff)
-	// Normal wake is handled by feed subscription (bd activity --follow)
+	// Recovery-focused heartbeat. Normal wake is handled by feed subscription
+	// (bd activity --follow); the interval adapts to tmux session activity
+	// within bounds unless adaptive_heartbeat is disabled in daemon.json.
 	timer := time.NewTimer(recoveryHeartbeatInterval)
 	defer timer.Stop()
 
-	d.logger.Printf("Daemon running, recovery heartbeat interval %v", recoveryHeartbeatInterval)
+	if minInterval, maxInterval, ok := heartbeatBounds(d.patrolConfig); ok {
+		d.heartbeatScheduler = newHeartbeatScheduler(minInterval, maxInterval)
+		d.logger.Printf("Daemon running, adaptive recovery heartbeat %v-%v", minInterval, maxInterval)
+	} else {
+		d.logger.Printf("Daemon running, recovery heartbeat interval %v", recoveryHeartbeatInterval)
+	}
 
 	// Start feed curator goroutine
 	d.curator = feed.NewCurator(d.config.TownRoot)
@@ -371,7 +422,8 @@ func (d *Daemon) Run() error {
 	}
 
 	// Start dedicated Dolt backup ticker if configured.
-	// Runs filesystem backup sync (dolt backup sync) for production databases.
+	// Runs filesystem backup sync (dolt backup sync) and/or snapshot branches
+	// for production databases.
 	var doltBackupTicker *time.Ticker
 	var doltBackupChan <-chan time.Time
 	if IsPatrolEnabled(d.patrolConfig, "dolt_backup") {
@@ -418,6 +470,18 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Doctor dog ticker started (interval %v)", interval)
 	}
 
+	// Start db maintenance ticker if configured.
+	// Integrity check (orphaned rows), dolt gc and size/row-count logging.
+	var dbMaintenanceTicker *time.Ticker
+	var dbMaintenanceChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "db_maintenance") {
+		interval := dbMaintenanceInterval(d.patrolConfig)
+		dbMaintenanceTicker = time.NewTicker(interval)
+		dbMaintenanceChan = dbMaintenanceTicker.C
+		defer dbMaintenanceTicker.Stop()
+		d.logger.Printf("DB maintenance ticker started (interval %v)", interval)
+	}
+
 	// Start janitor dog ticker if configured.
 	// Cleans up orphan test databases on the test server (port 3308).
 	var janitorDogTicker *time.Ticker
@@ -430,6 +494,117 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Janitor dog ticker started (interval %v)", interval)
 	}
 
+	// Start change feed ticker if configured.
+	// Tails Dolt commit diffs and emits issue/mail/MR events to the event log.
+	var changeFeedTicker *time.Ticker
+	var changeFeedChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "change_feed") {
+		interval := changeFeedInterval(d.patrolConfig)
+		changeFeedTicker = time.NewTicker(interval)
+		changeFeedChan = changeFeedTicker.C
+		defer changeFeedTicker.Stop()
+		d.logger.Printf("Change feed ticker started (interval %v)", interval)
+	}
+
+	// Start staleness ticker if configured.
+	// Applies per-rig staleness policies (nudge/reassign/escalate/label).
+	var stalenessTicker *time.Ticker
+	var stalenessChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "staleness") {
+		interval := stalenessInterval(d.patrolConfig)
+		stalenessTicker = time.NewTicker(interval)
+		stalenessChan = stalenessTicker.C
+		defer stalenessTicker.Stop()
+		d.logger.Printf("Staleness ticker started (interval %v)", interval)
+	}
+
+	// Start worktree pool ticker if configured.
+	// Keeps each rig's warm polecat worktree pool fetched, clean, and full.
+	var worktreePoolTicker *time.Ticker
+	var worktreePoolChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "worktree_pool") {
+		interval := worktreePoolInterval(d.patrolConfig)
+		worktreePoolTicker = time.NewTicker(interval)
+		worktreePoolChan = worktreePoolTicker.C
+		defer worktreePoolTicker.Stop()
+		d.logger.Printf("Worktree pool ticker started (interval %v)", interval)
+	}
+
+	// Start config drift ticker if configured.
+	// Compares the town with the spec last applied by gt apply.
+	var configDriftTicker *time.Ticker
+	var configDriftChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "config_drift") {
+		interval := configDriftInterval(d.patrolConfig)
+		configDriftTicker = time.NewTicker(interval)
+		configDriftChan = configDriftTicker.C
+		defer configDriftTicker.Stop()
+		d.logger.Printf("Config drift ticker started (interval %v)", interval)
+	}
+
+	// Start metrics history ticker. On by default: samples feed the trends
+	// in gt report and the dashboard.
+	var metricsHistoryTicker *time.Ticker
+	var metricsHistoryChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "metrics_history") {
+		interval := metricsHistoryInterval(d.patrolConfig)
+		metricsHistoryTicker = time.NewTicker(interval)
+		metricsHistoryChan = metricsHistoryTicker.C
+		defer metricsHistoryTicker.Stop()
+		d.logger.Printf("Metrics history ticker started (interval %v)", interval)
+	}
+
+	// Start agent signals ticker. On by default: parsed pane signals feed
+	// stall detection and re-prime agents after compaction.
+	var agentSignalsTicker *time.Ticker
+	var agentSignalsChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "agent_signals") {
+		interval := agentSignalsInterval(d.patrolConfig)
+		agentSignalsTicker = time.NewTicker(interval)
+		agentSignalsChan = agentSignalsTicker.C
+		defer agentSignalsTicker.Stop()
+		d.logger.Printf("Agent signals ticker started (interval %v)", interval)
+	}
+
+	// Start outbox ticker. On by default: commands agents queued while
+	// beads was unreachable are replayed once it is back.
+	var outboxTicker *time.Ticker
+	var outboxChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "outbox") {
+		interval := outboxInterval(d.patrolConfig)
+		outboxTicker = time.NewTicker(interval)
+		outboxChan = outboxTicker.C
+		defer outboxTicker.Stop()
+		d.logger.Printf("Outbox ticker started (interval %v)", interval)
+	}
+
+	// Start provider pressure ticker. On by default: the capacity scheduler
+	// and gt broadcast throttle providers whose sessions hit rate limits.
+	var providerPressureTicker *time.Ticker
+	var providerPressureChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "provider_pressure") {
+		interval := providerPressureInterval(d.patrolConfig)
+		providerPressureTicker = time.NewTicker(interval)
+		providerPressureChan = providerPressureTicker.C
+		defer providerPressureTicker.Stop()
+		d.logger.Printf("Provider pressure ticker started (interval %v)", interval)
+	}
+
+	// Start scheduled nudge ticker (gt nudge --at/--in). On by default:
+	// with nothing scheduled each tick is a directory read.
+	var scheduledNudgeTicker *time.Ticker
+	var scheduledNudgeChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "scheduled_nudges") {
+		scheduledNudgeTicker = time.NewTicker(scheduledNudgeInterval)
+		scheduledNudgeChan = scheduledNudgeTicker.C
+		defer scheduledNudgeTicker.Stop()
+	}
+
+	// Poll for gt daemon run-patrol requests. Always on: with nothing
+	// requested each tick is a directory read.
+	patrolRequestTicker := time.NewTicker(patrolRequestInterval)
+	defer patrolRequestTicker.Stop()
+
 	// Note: PATCH-010 uses per-session hooks in deacon/manager.go (SetAutoRespawnHook).
 	// Global pane-died hooks don't fire reliably in tmux 3.2a, so we rely on the
 	// per-session approach which has been tested to work for continuous recovery.
@@ -470,54 +645,129 @@ func (d *Daemon) Run() error {
 		case <-doltRemotesChan:
 			// Periodic Dolt remote push — pushes databases to their configured
 			// git remotes on a 15-minute cadence (independent of heartbeat).
-			if !d.isShutdownInProgress() {
+			if !d.isShutdownInProgress() && !d.quietSkips("dolt_remotes") {
 				d.pushDoltRemotes()
 			}
 
 		case <-doltBackupChan:
 			// Periodic Dolt filesystem backup — syncs production databases to
 			// local backup directory on a 15-minute cadence.
-			if !d.isShutdownInProgress() {
+			if !d.isShutdownInProgress() && !d.quietSkips("dolt_backup") {
 				d.syncDoltBackups()
 			}
 
 		case <-jsonlGitBackupChan:
 			// Periodic JSONL git backup — exports issues, scrubs ephemeral data,
 			// commits and pushes to git repo.
-			if !d.isShutdownInProgress() {
+			if !d.isShutdownInProgress() && !d.quietSkips("jsonl_git_backup") {
 				d.syncJsonlGitBackup()
 			}
 
 		case <-wispReaperChan:
 			// Periodic wisp reaper — closes stale wisps (abandoned molecule steps,
 			// old patrol data) to prevent unbounded table growth (Clown Show audit).
-			if !d.isShutdownInProgress() {
-				d.reapWisps()
+			if !d.isShutdownInProgress() && !d.quietSkips("wisp_reaper") {
+				d.timePatrol("wisp_reaper", d.reapWisps)
 			}
 
 		case <-doctorDogChan:
 			// Doctor dog — comprehensive Dolt health monitor: connectivity, latency,
 			// gc, zombie detection, backup staleness, and disk usage checks.
-			if !d.isShutdownInProgress() {
-				d.runDoctorDog()
+			if !d.isShutdownInProgress() && !d.quietSkips("doctor_dog") {
+				d.timePatrol("doctor_dog", d.runDoctorDog)
+			}
+
+		case <-dbMaintenanceChan:
+			// DB maintenance — keeps long-lived beads databases healthy:
+			// orphaned-row checks, dolt gc, and size tracking.
+			if !d.isShutdownInProgress() && !d.quietSkips("db_maintenance") {
+				d.timePatrol("db_maintenance", d.runDBMaintenance)
 			}
 
 		case <-janitorDogChan:
 			// Janitor dog — pours molecule for test server orphan cleanup.
-			if !d.isShutdownInProgress() {
+			if !d.isShutdownInProgress() && !d.quietSkips("janitor_dog") {
 				d.runJanitorDog()
 			}
 
+		case <-changeFeedChan:
+			// Change feed — turns committed bead changes into events so
+			// supervisors can react without polling.
+			if !d.isShutdownInProgress() {
+				d.tailChangeFeed()
+			}
+
+		case <-scheduledNudgeChan:
+			// Scheduled nudges — deliver reminders that have come due.
+			if !d.isShutdownInProgress() && !d.quietSkips("scheduled_nudges") {
+				d.deliverScheduledNudges()
+			}
+
+		case <-providerPressureChan:
+			// Provider pressure — measures rate-limit signals per provider
+			// so dispatch can back off before the fleet stalls.
+			if !d.isShutdownInProgress() {
+				d.measureProviderPressure()
+			}
+
+		case <-stalenessChan:
+			// Staleness — acts on issues that have sat too long per the
+			// rig's staleness policies.
+			if !d.isShutdownInProgress() && !d.quietSkips("staleness") {
+				d.checkStaleness()
+			}
+
+		case <-worktreePoolChan:
+			// Worktree pool — refills and refreshes the warm worktrees
+			// polecat spawns claim.
+			if !d.isShutdownInProgress() && !d.quietSkips("worktree_pool") {
+				d.maintainWorktreePools()
+			}
+
+		case <-configDriftChan:
+			// Config drift — tells the mayor when the town stops matching
+			// its declarative spec.
+			if !d.isShutdownInProgress() {
+				d.checkConfigDrift()
+			}
+
+		case <-metricsHistoryChan:
+			// Metrics history — samples sessions, queue depth, cost and
+			// patrol durations into the local time-series history.
+			if !d.isShutdownInProgress() {
+				d.recordMetrics()
+			}
+
+		case <-agentSignalsChan:
+			// Agent signals — parses agent panes for tool calls, questions,
+			// error banners, compactions and usage, and publishes them.
+			if !d.isShutdownInProgress() {
+				d.scanAgentSignals()
+			}
+
+		case <-outboxChan:
+			// Outbox — replays agent commands queued while the beads
+			// database was unreachable.
+			if !d.isShutdownInProgress() && !d.quietSkips("outbox") {
+				d.replayOutbox()
+			}
+
+		case <-patrolRequestTicker.C:
+			// On-demand patrols (gt daemon run-patrol), run here so they
+			// never overlap a scheduled run.
+			d.runRequestedPatrols(state)
+
 		case <-timer.C:
-			d.heartbeat(state)
+			d.timePatrol("heartbeat", func() { d.heartbeat(state) })
 
-			// Fixed recovery interval (no activity-based backoff)
-			timer.Reset(recoveryHeartbeatInterval)
+			// Shorter while sessions are busy, longer while the town is idle
+			timer.Reset(d.nextHeartbeatInterval())
 		}
 	}
 }
 
-// recoveryHeartbeatInterval is the fixed interval for recovery-focused daemon.
+// recoveryHeartbeatInterval is the interval for recovery-focused daemon, used
+// for the first heartbeat and whenever adaptive scheduling is off.
 // Normal wake is handled by feed subscription (bd activity --follow).
 // The daemon is a safety net for dead sessions, GUPP violations, and orphaned work.
 // 3 minutes is fast enough to detect stuck agents promptly while avoiding excessive overhead.
@@ -549,6 +799,16 @@ func (d *Daemon) heartbeat(state *State) {
 	// 0b. Ensure Dolt TEST server is running (if configured)
 	d.ensureDoltTestServerRunning()
 
+	// Quiet hours or a gt maintenance window: no restarts, nudges, or
+	// dispatch while a human works on the town. Agents' own lifecycle
+	// requests still go through.
+	if qs := d.quietStatus(); qs.Active {
+		d.logger.Printf("In %s, skipping agent recovery, nudges and dispatch", qs)
+		d.processLifecycleRequests()
+		d.finishHeartbeat(state)
+		return
+	}
+
 	// 1. Ensure Deacon is running (restart if dead)
 	// Check patrol config - can be disabled in mayor/daemon.json
 	if IsPatrolEnabled(d.patrolConfig, "deacon") {
@@ -631,11 +891,28 @@ func (d *Daemon) heartbeat(state *State) {
 	// branches persist indefinitely. This cleans them up periodically.
 	d.pruneStaleBranches()
 
+	// 13b. Enforce rig spend budgets: nudge over-budget rigs' polecats to
+	// wrap up and tell the mayor. Shells out like dispatch below.
+	d.enforceBudgets()
+
+	// 13c. Drop session directory records of sessions that are gone.
+	d.pruneSessionDirectory()
+
 	// 14. Dispatch scheduled work (capacity-controlled polecat dispatch).
 	// Shells out to `gt scheduler run` to avoid circular import between daemon and cmd.
 	d.dispatchQueuedWork()
 
-	// Update state
+	// 15. Send the Mayor an autopilot digest when autopilot is on and due.
+	// The autopilot feature flag can switch it off town-wide.
+	if mayor.AutopilotEnabled(d.config.TownRoot) && featureflag.Enabled(d.config.TownRoot, featureflag.Autopilot, "", "") {
+		d.tickMayorAutopilot()
+	}
+
+	d.finishHeartbeat(state)
+}
+
+// finishHeartbeat records a completed heartbeat in the daemon state.
+func (d *Daemon) finishHeartbeat(state *State) {
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
 	if err := SaveState(d.config.TownRoot, state); err != nil {
@@ -1445,13 +1722,7 @@ func IsRunning(townRoot string) (bool, int, error) {
 	}
 
 	// Check if process is alive
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false, 0, nil
-	}
-
-	// On Unix, FindProcess always succeeds. Send signal 0 to check if alive.
-	if err := process.Signal(syscall.Signal(0)); err != nil {
+	if !platform.ProcessExists(pid) {
 		// Process not running, clean up stale PID file
 		if err := os.Remove(pidFile); err == nil {
 			// Successfully cleaned up stale file
@@ -1515,7 +1786,7 @@ func StopDaemon(townRoot string) error {
 	time.Sleep(constants.ShutdownNotifyDelay)
 
 	// Check if still running
-	if err := process.Signal(syscall.Signal(0)); err == nil {
+	if platform.ProcessExists(pid) {
 		// Still running, force kill
 		_ = process.Signal(syscall.SIGKILL)
 	}
@@ -1584,7 +1855,7 @@ func KillOrphanedDaemons() (int, error) {
 		time.Sleep(200 * time.Millisecond)
 
 		// Check if still alive
-		if err := process.Signal(syscall.Signal(0)); err == nil {
+		if platform.ProcessExists(pid) {
 			// Still alive, force kill
 			_ = process.Signal(syscall.SIGKILL)
 		}
@@ -1651,6 +1922,11 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	// Build the expected tmux session name
 	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
 
+	// A human has taken over the session (gt takeover) - don't restart it.
+	if takeover.IsTakenOver(d.config.TownRoot, sessionName) {
+		return
+	}
+
 	// Check if tmux session exists
 	sessionAlive, err := d.tmux.HasSession(sessionName)
 	if err != nil {
@@ -1658,9 +1934,17 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 		return
 	}
 
+	dryRun := d.isDryRun(DryRunZombieRecovery)
 	if sessionAlive {
-		// Session is alive - nothing to do
-		return
+		// Session is alive - nothing to do, unless its pane died and was
+		// kept by remain-on-exit for the pane-died hook, which didn't run.
+		if dryRun {
+			if dead, _, _ := d.tmux.PaneDeadStatus(sessionName); !dead {
+				return
+			}
+		} else if !d.collectDeadPane(sessionName, rigName+"/"+polecatName) {
+			return
+		}
 	}
 
 	// Session is dead. Check if the polecat has work-on-hook.
@@ -1712,7 +1996,8 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	// Between the initial check and now, the session may have been restarted
 	// by another heartbeat cycle, witness, or the polecat itself.
 	sessionRevived, err := d.tmux.HasSession(sessionName)
-	if err == nil && sessionRevived {
+	// (In dry-run a dead pane's session was never killed, so it's still there.)
+	if err == nil && sessionRevived && !(dryRun && sessionAlive) {
 		return // Session came back - no restart needed
 	}
 
@@ -1720,12 +2005,43 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	d.logger.Printf("CRASH DETECTED: polecat %s/%s has hook_bead=%s but session %s is dead",
 		rigName, polecatName, info.HookBead, sessionName)
 
+	// How the session failed decides how to recover (see internal/failure).
+	cause, recovery := d.sessionFailure(sessionName)
+	reason := "crash detected by daemon health check"
+	if cause != nil {
+		reason += ": " + cause.Reason()
+	}
+
+	if dryRun {
+		d.logDryRun(DryRunZombieRecovery, fmt.Sprintf("apply %s recovery (%s)", recovery, reason), rigName+"/"+polecatName)
+		return
+	}
+
+	switch recovery {
+	case failure.RecoverWait:
+		// Rate-limited: restarting now would hit the same limit.
+		if wait := rateLimitRestartDelay - time.Since(cause.Time); wait > 0 {
+			d.logger.Printf("Deferring restart of %s/%s for %s: %s",
+				rigName, polecatName, wait.Round(time.Second), cause.Reason())
+			return
+		}
+	case failure.RecoverFixEnvironment, failure.RecoverEscalate:
+		// Restarting would fail the same way; hold and tell the witness once.
+		if d.holdFailedSession(sessionName, cause) {
+			d.logger.Printf("Holding %s/%s (%s): %s", rigName, polecatName, recovery, cause.Reason())
+			_ = events.LogFeed(events.TypeSessionDeath, sessionName,
+				events.SessionDeathPayload(sessionName, rigName+"/polecats/"+polecatName, reason, "daemon"))
+			d.notifyWitnessOfHeldPolecat(rigName, polecatName, info.HookBead, cause, recovery)
+		}
+		return
+	}
+
 	// Track this death for mass death detection
 	d.recordSessionDeath(sessionName)
 
 	// Emit session_death event for audit trail / feed visibility
 	_ = events.LogFeed(events.TypeSessionDeath, sessionName,
-		events.SessionDeathPayload(sessionName, rigName+"/polecats/"+polecatName, "crash detected by daemon health check", "daemon"))
+		events.SessionDeathPayload(sessionName, rigName+"/polecats/"+polecatName, reason, "daemon"))
 
 	// Auto-restart the polecat
 	restartErr := d.restartPolecatSession(rigName, polecatName, sessionName)
@@ -1736,7 +2052,7 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	}
 
 	// Always notify witness of crash (with restart outcome)
-	d.notifyWitnessOfCrashedPolecat(rigName, polecatName, info.HookBead, restartErr)
+	d.notifyWitnessOfCrashedPolecat(rigName, polecatName, info.HookBead, cause, restartErr)
 }
 
 // recordSessionDeath records a session death and checks for mass death pattern.
@@ -1867,7 +2183,8 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 
 	// Wait for Claude to start, then accept startup dialogs if they appear.
 	if err := d.tmux.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
-		// Non-fatal - Claude might still start
+		// Non-fatal - Claude might still start; the diagnosis says why it hasn't yet
+		d.logger.Printf("Session %s: %v", sessionName, err)
 	}
 	_ = d.tmux.AcceptStartupDialogs(sessionName)
 
@@ -1877,7 +2194,8 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 // notifyWitnessOfCrashedPolecat notifies the witness when a polecat crash is detected.
 // If restartErr is nil, the notification is informational (restart succeeded).
 // If restartErr is non-nil, the notification indicates manual intervention may be needed.
-func (d *Daemon) notifyWitnessOfCrashedPolecat(rigName, polecatName, hookBead string, restartErr error) {
+// cause, if the crash was captured, adds the pane's last output.
+func (d *Daemon) notifyWitnessOfCrashedPolecat(rigName, polecatName, hookBead string, cause *failure.Record, restartErr error) {
 	witnessAddr := rigName + "/witness"
 	var subject, body string
 	if restartErr == nil {
@@ -1885,18 +2203,18 @@ func (d *Daemon) notifyWitnessOfCrashedPolecat(rigName, polecatName, hookBead st
 		body = fmt.Sprintf(`Polecat %s crashed and was automatically restarted.
 
 hook_bead: %s
-
+%s
 No action required.`,
-			polecatName, hookBead)
+			polecatName, hookBead, crashOutput(cause))
 	} else {
 		subject = fmt.Sprintf("CRASHED_POLECAT: %s/%s restart failed", rigName, polecatName)
 		body = fmt.Sprintf(`Polecat %s crashed and automatic restart failed.
 
 hook_bead: %s
 restart_error: %v
-
+%s
 Manual intervention may be required.`,
-			polecatName, hookBead, restartErr)
+			polecatName, hookBead, restartErr, crashOutput(cause))
 	}
 
 	cmd := exec.Command(d.gtPath, "mail", "send", witnessAddr, "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
@@ -1967,6 +2285,55 @@ func (d *Daemon) pruneStaleBranches() {
 	pruneInDir(d.config.TownRoot, "town-root")
 }
 
+// tickMayorAutopilot shells out to `gt mayor autopilot tick`, which sends
+// the digest if the interval has elapsed. Like dispatchQueuedWork, this
+// avoids importing cmd from the daemon.
+func (d *Daemon) tickMayorAutopilot() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "gt", "mayor", "autopilot", "tick")
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = append(os.Environ(), "GT_DAEMON=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		d.logger.Printf("Mayor autopilot tick failed: %v (output: %s)", err, string(out))
+	}
+}
+
+// enforceBudgets runs gt budget check, which notifies the polecats and
+// mayor of rigs that have gone over their spend budget.
+func (d *Daemon) enforceBudgets() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "gt", "budget", "check")
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = append(os.Environ(), "GT_DAEMON=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("Budget check failed: %v (output: %s)", err, string(out))
+	} else if len(out) > 0 {
+		d.logger.Printf("Budget check: %s", string(out))
+	}
+}
+
+// pruneSessionDirectory removes session directory records for tmux
+// sessions that no longer exist.
+func (d *Daemon) pruneSessionDirectory() {
+	names, err := d.tmux.ListSessions()
+	if err != nil {
+		return // Can't tell what's running; keep the records
+	}
+	running := make(map[string]bool, len(names))
+	for _, n := range names {
+		running[n] = true
+	}
+	pruned, err := session.PruneSessionRecords(d.config.TownRoot, func(s string) bool { return running[s] })
+	if err != nil {
+		d.logger.Printf("Warning: session directory prune failed: %v", err)
+	} else if pruned > 0 {
+		d.logger.Printf("Session directory: pruned %d record(s) of dead sessions", pruned)
+	}
+}
+
 // dispatchQueuedWork shells out to `gt scheduler run` to dispatch scheduled beads.
 // This avoids circular import between the daemon and cmd packages.
 // Uses a 5m timeout to allow multi-bead dispatch with formula cooking and hook retries.
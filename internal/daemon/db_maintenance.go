@@ -0,0 +1,104 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/lock"
+)
+
+const defaultDBMaintenanceInterval = 24 * time.Hour
+
+// DBMaintenanceConfig holds configuration for the db_maintenance patrol.
+// This patrol runs the gt db maintenance commands on a schedule: an
+// integrity check (escalated when orphaned rows turn up), dolt gc, and a
+// size/row-count log line per database.
+type DBMaintenanceConfig struct {
+	// Enabled controls whether maintenance runs.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to run, as a string (e.g., "24h").
+	IntervalStr string `json:"interval,omitempty"`
+
+	// Databases lists specific database names to maintain.
+	// If empty, auto-discovers from dolt server.
+	Databases []string `json:"databases,omitempty"`
+
+	// GC controls whether dolt gc runs. Turn off when doctor_dog already
+	// collects the same databases.
+	// Default: true
+	GC *bool `json:"gc,omitempty"`
+}
+
+// dbMaintenanceInterval returns the configured interval, or the default (24h).
+func dbMaintenanceInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.DBMaintenance != nil {
+		if config.Patrols.DBMaintenance.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.DBMaintenance.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultDBMaintenanceInterval
+}
+
+// runDBMaintenance checks, collects and measures each database.
+// Non-fatal: errors are logged and escalated but don't stop the daemon.
+func (d *Daemon) runDBMaintenance() {
+	if !IsPatrolEnabled(d.patrolConfig, "db_maintenance") {
+		return
+	}
+	release, ok := d.acquireResource(lock.ResourceDolt, "db_maintenance")
+	if !ok {
+		return
+	}
+	defer release()
+	config := d.patrolConfig.Patrols.DBMaintenance
+	townRoot := d.config.TownRoot
+
+	databases := config.Databases
+	if len(databases) == 0 {
+		var err error
+		databases, err = doltserver.ListDatabases(townRoot)
+		if err != nil {
+			d.logger.Printf("db_maintenance: listing databases: %v", err)
+			return
+		}
+	}
+	gc := config.GC == nil || *config.GC
+
+	for _, db := range databases {
+		problems, err := doltserver.CheckIntegrity(townRoot, db)
+		if err != nil {
+			d.logger.Printf("db_maintenance: %s: integrity: %v", db, err)
+		} else if len(problems) > 0 {
+			var parts []string
+			for _, p := range problems {
+				parts = append(parts, fmt.Sprintf("%d %s", p.Count, p.Check))
+			}
+			d.logger.Printf("db_maintenance: %s: integrity: %s", db, strings.Join(parts, ", "))
+			d.escalate("db_maintenance", fmt.Sprintf("%s has orphaned rows: %s (see: gt db integrity %s)",
+				db, strings.Join(parts, ", "), db), "database="+db, "orphans="+strings.Join(parts, "\n"))
+		}
+
+		if gc {
+			result, err := doltserver.GC(townRoot, db)
+			if err != nil {
+				d.logger.Printf("db_maintenance: %s: gc failed after %v: %v", db, result.Duration.Round(time.Second), err)
+				d.escalate("db_maintenance", fmt.Sprintf("dolt gc failed on %s: %v", db, err))
+			} else {
+				d.logger.Printf("db_maintenance: %s: gc reclaimed %s in %v", db,
+					doltserver.FormatBytes(result.Reclaimed()), result.Duration.Round(time.Second))
+			}
+		}
+
+		if stats, err := doltserver.GetDatabaseStats(townRoot, db); err != nil {
+			d.logger.Printf("db_maintenance: %s: stats: %v", db, err)
+		} else {
+			d.logger.Printf("db_maintenance: %s: %s, %d rows in %d tables", db,
+				doltserver.FormatBytes(stats.SizeBytes), stats.TotalRows(), len(stats.Tables))
+		}
+	}
+}
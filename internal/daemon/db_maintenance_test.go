@@ -0,0 +1,43 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDBMaintenanceInterval(t *testing.T) {
+	// Default
+	if got := dbMaintenanceInterval(nil); got != defaultDBMaintenanceInterval {
+		t.Errorf("expected default %v, got %v", defaultDBMaintenanceInterval, got)
+	}
+
+	// Custom
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			DBMaintenance: &DBMaintenanceConfig{
+				Enabled:     true,
+				IntervalStr: "6h",
+			},
+		},
+	}
+	if got := dbMaintenanceInterval(config); got != 6*time.Hour {
+		t.Errorf("expected 6h, got %v", got)
+	}
+
+	// Invalid falls back to default
+	config.Patrols.DBMaintenance.IntervalStr = "nope"
+	if got := dbMaintenanceInterval(config); got != defaultDBMaintenanceInterval {
+		t.Errorf("expected default for invalid, got %v", got)
+	}
+}
+
+func TestIsPatrolEnabled_DBMaintenance(t *testing.T) {
+	// Opt-in: disabled with no config.
+	if IsPatrolEnabled(nil, "db_maintenance") {
+		t.Error("db_maintenance should be disabled with nil config")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{DBMaintenance: &DBMaintenanceConfig{Enabled: true}}}
+	if !IsPatrolEnabled(config, "db_maintenance") {
+		t.Error("db_maintenance should be enabled when configured")
+	}
+}
@@ -159,7 +159,8 @@ func (d *Daemon) doctorDogLatencyCheck(host string, port int) {
 
 	if latency > doctorDogLatencyThreshold {
 		d.logger.Printf("doctor_dog: latency check: %v exceeds threshold %v", latency, doctorDogLatencyThreshold)
-		d.escalate("doctor_dog", fmt.Sprintf("SELECT 1 latency %v exceeds %v threshold", latency, doctorDogLatencyThreshold))
+		d.escalate("doctor_dog", fmt.Sprintf("SELECT 1 latency %v exceeds %v threshold", latency, doctorDogLatencyThreshold),
+			fmt.Sprintf("server=%s:%d", host, port), "latency="+latency.String(), "threshold="+doctorDogLatencyThreshold.String())
 	} else {
 		d.logger.Printf("doctor_dog: latency check: %v (OK)", latency)
 	}
@@ -202,7 +203,8 @@ func (d *Daemon) doctorDogDatabaseCountCheck(host string, port int) {
 	maxCount := doctorDogMaxDBCount(d.patrolConfig)
 	if len(databases) > maxCount {
 		d.logger.Printf("doctor_dog: db count check: %d databases (max %d): %v", len(databases), maxCount, databases)
-		d.escalate("doctor_dog", fmt.Sprintf("Database count %d exceeds expected max %d. DBs: %v", len(databases), maxCount, databases))
+		d.escalate("doctor_dog", fmt.Sprintf("Database count %d exceeds expected max %d", len(databases), maxCount),
+			fmt.Sprintf("server=%s:%d", host, port), "databases="+strings.Join(databases, "\n"))
 	} else {
 		d.logger.Printf("doctor_dog: db count check: %d databases (OK, max %d)", len(databases), maxCount)
 	}
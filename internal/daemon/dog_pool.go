@@ -0,0 +1,52 @@
+package daemon
+
+import "time"
+
+// DogPoolConfig sizes the deacon's dog pool. Dogs are town-level task
+// runners; the handler patrol reaps idle ones and the pool grows on demand
+// up to MaxSize when work arrives and no dog is idle.
+type DogPoolConfig struct {
+	// MaxSize caps the number of dogs in the kennel (default 4).
+	MaxSize int `json:"max_size,omitempty"`
+
+	// IdleSessionStr is how long an idle dog keeps its tmux session before
+	// it is killed (e.g., "1h").
+	IdleSessionStr string `json:"idle_session,omitempty"`
+
+	// IdleRemoveStr is how long a dog can be idle before it is removed from
+	// an oversized pool (e.g., "4h").
+	IdleRemoveStr string `json:"idle_remove,omitempty"`
+}
+
+// DefaultMaxDogPoolSize is the pool cap when dog_pool.max_size is unset.
+const DefaultMaxDogPoolSize = maxDogPoolSize
+
+// DogPoolMaxSize returns the configured pool cap, or the default (4).
+func DogPoolMaxSize(config *DaemonPatrolConfig) int {
+	if config != nil && config.Patrols != nil && config.Patrols.DogPool != nil {
+		if config.Patrols.DogPool.MaxSize > 0 {
+			return config.Patrols.DogPool.MaxSize
+		}
+	}
+	return maxDogPoolSize
+}
+
+// dogPoolIdleSession returns the configured idle session timeout, or the default (1h).
+func dogPoolIdleSession(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.DogPool != nil {
+		if d, err := time.ParseDuration(config.Patrols.DogPool.IdleSessionStr); err == nil && d > 0 {
+			return d
+		}
+	}
+	return dogIdleSessionTimeout
+}
+
+// dogPoolIdleRemove returns the configured idle removal timeout, or the default (4h).
+func dogPoolIdleRemove(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.DogPool != nil {
+		if d, err := time.ParseDuration(config.Patrols.DogPool.IdleRemoveStr); err == nil && d > 0 {
+			return d
+		}
+	}
+	return dogIdleRemoveTimeout
+}
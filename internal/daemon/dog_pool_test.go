@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDogPoolConfigDefaults(t *testing.T) {
+	if got := DogPoolMaxSize(nil); got != maxDogPoolSize {
+		t.Errorf("DogPoolMaxSize(nil) = %d, want %d", got, maxDogPoolSize)
+	}
+	if got := dogPoolIdleSession(nil); got != dogIdleSessionTimeout {
+		t.Errorf("dogPoolIdleSession(nil) = %v, want %v", got, dogIdleSessionTimeout)
+	}
+	if got := dogPoolIdleRemove(nil); got != dogIdleRemoveTimeout {
+		t.Errorf("dogPoolIdleRemove(nil) = %v, want %v", got, dogIdleRemoveTimeout)
+	}
+}
+
+func TestDogPoolConfigOverrides(t *testing.T) {
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			DogPool: &DogPoolConfig{
+				MaxSize:        8,
+				IdleSessionStr: "20m",
+				IdleRemoveStr:  "invalid",
+			},
+		},
+	}
+	if got := DogPoolMaxSize(config); got != 8 {
+		t.Errorf("DogPoolMaxSize = %d, want 8", got)
+	}
+	if got := dogPoolIdleSession(config); got != 20*time.Minute {
+		t.Errorf("dogPoolIdleSession = %v, want 20m", got)
+	}
+	// Invalid duration falls back to default
+	if got := dogPoolIdleRemove(config); got != dogIdleRemoveTimeout {
+		t.Errorf("dogPoolIdleRemove = %v, want default %v", got, dogIdleRemoveTimeout)
+	}
+}
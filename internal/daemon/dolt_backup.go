@@ -8,6 +8,9 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/lock"
 )
 
 const (
@@ -27,12 +30,19 @@ func doltBackupInterval(config *DaemonPatrolConfig) time.Duration {
 	return defaultDoltBackupInterval
 }
 
-// syncDoltBackups syncs each production database to its configured backup location.
+// syncDoltBackups backs up each production database according to the
+// configured mode: syncing it to its backup location, taking a snapshot
+// branch, or both.
 // Non-fatal: errors are logged but don't stop the daemon.
 func (d *Daemon) syncDoltBackups() {
 	if !IsPatrolEnabled(d.patrolConfig, "dolt_backup") {
 		return
 	}
+	release, ok := d.acquireResource(lock.ResourceDolt, "dolt_backup")
+	if !ok {
+		return
+	}
+	defer release()
 
 	// Pour molecule for observability (nil-safe — all methods are no-ops on nil).
 	mol := d.pourDogMolecule("mol-dog-backup", nil)
@@ -52,6 +62,17 @@ func (d *Daemon) syncDoltBackups() {
 	}
 
 	config := d.patrolConfig.Patrols.DoltBackup
+	if config.snapshots() {
+		d.snapshotDoltDatabases(config, dataDir)
+	}
+	mol.closeStep("snapshot")
+	if !config.syncs() {
+		mol.closeStep("sync")
+		mol.closeStep("offsite")
+		mol.closeStep("report")
+		return
+	}
+
 	databases := config.Databases
 	if len(databases) == 0 {
 		databases = d.discoverDatabasesWithBackups(dataDir)
@@ -97,6 +118,64 @@ func (d *Daemon) syncDoltBackups() {
 	mol.closeStep("report")
 }
 
+// snapshotDoltDatabases takes a snapshot branch of each database, pushes it
+// to the configured remote, and prunes snapshots past the retention.
+func (d *Daemon) snapshotDoltDatabases(config *DoltBackupConfig, dataDir string) {
+	databases := config.Databases
+	if len(databases) == 0 {
+		var err error
+		if databases, err = listDoltDatabases(dataDir); err != nil {
+			d.logger.Printf("dolt_backup: snapshot: %v", err)
+			return
+		}
+	}
+	retention := config.SnapshotRetention
+	if retention <= 0 {
+		retention = doltserver.DefaultSnapshotRetention
+	}
+
+	now := time.Now()
+	created := 0
+	for _, db := range databases {
+		if looksLikeTestDatabase(db) {
+			continue
+		}
+		branch, ok, err := doltserver.CreateSnapshot(d.config.TownRoot, db, config.SnapshotRemote, now)
+		if err != nil {
+			d.logger.Printf("dolt_backup: %s: snapshot failed: %v", db, err)
+			continue
+		}
+		if ok {
+			created++
+			d.logger.Printf("dolt_backup: %s: snapshot %s", db, branch)
+		}
+		if pruned, err := doltserver.PruneSnapshots(d.config.TownRoot, db, config.SnapshotRemote, retention); err != nil {
+			d.logger.Printf("dolt_backup: %s: pruning snapshots: %v", db, err)
+		} else if len(pruned) > 0 {
+			d.logger.Printf("dolt_backup: %s: pruned %d snapshot(s)", db, len(pruned))
+		}
+	}
+	d.logger.Printf("dolt_backup: snapshot: %d new across %d database(s)", created, len(databases))
+}
+
+// listDoltDatabases lists the Dolt databases in the data directory.
+func listDoltDatabases(dataDir string) ([]string, error) {
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading data dir: %w", err)
+	}
+	var databases []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dataDir, entry.Name(), ".dolt")); err == nil {
+			databases = append(databases, entry.Name())
+		}
+	}
+	return databases, nil
+}
+
 // syncBackup runs `dolt backup sync <backup-name>` for a single database.
 func (d *Daemon) syncBackup(dataDir, db, backupName string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), doltBackupTimeout)
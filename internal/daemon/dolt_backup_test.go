@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDoltBackupModes(t *testing.T) {
+	tests := []struct {
+		mode               string
+		wantSync, wantSnap bool
+	}{
+		{"", true, false},
+		{DoltBackupModeSync, true, false},
+		{DoltBackupModeSnapshot, false, true},
+		{DoltBackupModeBoth, true, true},
+	}
+	for _, tt := range tests {
+		c := &DoltBackupConfig{Mode: tt.mode}
+		if c.syncs() != tt.wantSync || c.snapshots() != tt.wantSnap {
+			t.Errorf("mode %q: syncs=%v snapshots=%v, want %v %v", tt.mode, c.syncs(), c.snapshots(), tt.wantSync, tt.wantSnap)
+		}
+	}
+}
+
+func TestListDoltDatabases(t *testing.T) {
+	dataDir := t.TempDir()
+	for _, dir := range []string{"gastown/.dolt", "hq/.dolt", ".hidden/.dolt", "notadb"} {
+		if err := os.MkdirAll(filepath.Join(dataDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := listDoltDatabases(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"gastown", "hq"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("listDoltDatabases = %v, want %v", got, want)
+	}
+}
+
+func TestLooksLikeTestDatabase(t *testing.T) {
+	for db, want := range map[string]bool{
+		"gastown":      false,
+		"hq":           false,
+		"testdb_abc":   true,
+		"beads_t1234":  true,
+		"doctest_town": true,
+	} {
+		if got := looksLikeTestDatabase(db); got != want {
+			t.Errorf("looksLikeTestDatabase(%q) = %v, want %v", db, got, want)
+		}
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/lock"
 )
 
 const (
@@ -45,6 +47,17 @@ func (d *Daemon) pushDoltRemotes() {
 		return
 	}
 
+	release, ok := d.acquireResource(lock.ResourceDolt, "dolt_remotes")
+	if !ok {
+		return
+	}
+	defer release()
+	releaseRemote, ok := d.acquireResource(lock.ResourceGitRemote, "dolt_remotes")
+	if !ok {
+		return
+	}
+	defer releaseRemote()
+
 	config := d.patrolConfig.Patrols.DoltRemotes
 	remote := config.Remote
 	branch := config.Branch
@@ -91,6 +104,10 @@ func (d *Daemon) pushDoltRemotes() {
 				continue
 			}
 		}
+		if d.isDryRun(DryRunUpstreamSync) {
+			d.logDryRun(DryRunUpstreamSync, fmt.Sprintf("push to %s/%s", pushRemote, branch), db)
+			continue
+		}
 		if err := d.pushDatabase(dataDir, db, pushRemote, branch); err != nil {
 			d.logger.Printf("dolt_remotes: %s: push failed: %v", db, err)
 		} else {
@@ -105,10 +122,8 @@ func (d *Daemon) pushDoltRemotes() {
 func (d *Daemon) pushDatabase(dataDir, db, remote, branch string) error {
 	// Safety: refuse to push anything that looks like a test database.
 	// This is the last line of defense against pushing pollution to GitHub.
-	for _, prefix := range []string{"test", "beads_t", "beads_pt", "doctest_"} {
-		if strings.HasPrefix(db, prefix) {
-			return fmt.Errorf("REFUSED: %q looks like a test database (prefix %q)", db, prefix)
-		}
+	if looksLikeTestDatabase(db) {
+		return fmt.Errorf("REFUSED: %q looks like a test database", db)
 	}
 
 	// Step 1: Stage any unstaged changes (non-fatal)
@@ -140,6 +155,17 @@ func (d *Daemon) pushDatabase(dataDir, db, remote, branch string) error {
 	return nil
 }
 
+// looksLikeTestDatabase reports whether a database name has a test-database
+// prefix; such databases are never pushed or snapshotted.
+func looksLikeTestDatabase(db string) bool {
+	for _, prefix := range []string{"test", "beads_t", "beads_pt", "doctest_"} {
+		if strings.HasPrefix(db, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // runDoltSQL executes a SQL query against the Dolt data directory.
 func (d *Daemon) runDoltSQL(dataDir, query string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), doltPushTimeout)
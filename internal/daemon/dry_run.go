@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Mutating patrols that support dry-run. In dry-run a patrol logs what it
+// would do - to the daemon log and as patrol_dry_run events - without
+// doing it, so a new policy can be watched on a live town before it acts.
+const (
+	DryRunZombieRecovery = "zombie_recovery" // restart crashed polecats
+	DryRunIdleReaper     = "idle_reaper"     // stop and remove idle dogs
+	DryRunWispReaper     = "wisp_reaper"     // close and delete old wisps, events, mail
+	DryRunUpstreamSync   = "upstream_sync"   // push databases to dolt remotes
+
+	// DryRunAll puts every patrol above in dry-run.
+	DryRunAll = "all"
+)
+
+// DryRunPatrols lists the patrols that support dry-run.
+var DryRunPatrols = []string{DryRunZombieRecovery, DryRunIdleReaper, DryRunWispReaper, DryRunUpstreamSync}
+
+// ValidateDryRun reports the first name that isn't a dry-run patrol.
+func ValidateDryRun(patrols []string) error {
+	for _, p := range patrols {
+		if p == DryRunAll {
+			continue
+		}
+		known := false
+		for _, k := range DryRunPatrols {
+			known = known || p == k
+		}
+		if !known {
+			return fmt.Errorf("unknown dry-run patrol %q (want one of %s, or %s)", p, strings.Join(DryRunPatrols, ", "), DryRunAll)
+		}
+	}
+	return nil
+}
+
+// patrolDryRun returns the "dry_run" list from mayor/daemon.json.
+func patrolDryRun(config *DaemonPatrolConfig) []string {
+	if config == nil {
+		return nil
+	}
+	return config.DryRun
+}
+
+// isDryRun reports whether patrol is in dry-run, from gt daemon run
+// --dry-run or the "dry_run" list in mayor/daemon.json.
+func (d *Daemon) isDryRun(patrol string) bool {
+	for _, list := range [][]string{d.config.DryRun, patrolDryRun(d.patrolConfig)} {
+		for _, p := range list {
+			if p == patrol || p == DryRunAll {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// logDryRun records an action a dry-run patrol skipped.
+func (d *Daemon) logDryRun(patrol, action, target string) {
+	d.logger.Printf("%s: dry run: would %s: %s", patrol, action, target)
+	_ = events.LogFeed(events.TypePatrolDryRun, "daemon", events.PatrolDryRunPayload(patrol, action, target))
+}
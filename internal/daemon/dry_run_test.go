@@ -0,0 +1,31 @@
+package daemon
+
+import "testing"
+
+func TestValidateDryRun(t *testing.T) {
+	if err := ValidateDryRun([]string{DryRunZombieRecovery, DryRunAll}); err != nil {
+		t.Errorf("ValidateDryRun(known) = %v", err)
+	}
+	if err := ValidateDryRun([]string{"zombie-recovery"}); err == nil {
+		t.Error("ValidateDryRun(unknown) = nil, want error")
+	}
+}
+
+func TestIsDryRun(t *testing.T) {
+	d := &Daemon{config: &Config{DryRun: []string{DryRunIdleReaper}}}
+	if !d.isDryRun(DryRunIdleReaper) || d.isDryRun(DryRunWispReaper) {
+		t.Error("CLI dry-run list not honored")
+	}
+
+	d.patrolConfig = &DaemonPatrolConfig{DryRun: []string{DryRunWispReaper}}
+	if !d.isDryRun(DryRunWispReaper) || d.isDryRun(DryRunUpstreamSync) {
+		t.Error("daemon.json dry_run list not honored")
+	}
+
+	d.patrolConfig.DryRun = []string{DryRunAll}
+	for _, p := range DryRunPatrols {
+		if !d.isDryRun(p) {
+			t.Errorf("isDryRun(%q) = false with %q", p, DryRunAll)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/endpoints"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// townEndpoints returns the town's standing endpoints: its Dolt port and
+// its tmux socket. They are reservations (PID 0): another town must not
+// take them even while the server is down.
+func townEndpoints(townRoot string) []endpoints.Endpoint {
+	doltCfg := doltserver.DefaultConfig(townRoot)
+	eps := []endpoints.Endpoint{{
+		Name: endpoints.Dolt,
+		Kind: endpoints.KindTCP,
+		Host: doltCfg.Host,
+		Port: doltCfg.Port,
+	}}
+	if socket := tmux.GetDefaultSocket(); socket != "" {
+		eps = append(eps, endpoints.Endpoint{
+			Name:   endpoints.Tmux,
+			Kind:   endpoints.KindUnix,
+			Socket: filepath.Join(tmux.SocketDir(), socket),
+			Label:  socket,
+		})
+	}
+	return eps
+}
+
+// registerEndpoints records the town's endpoints in the registry and
+// escalates any that another town on this machine already claims.
+func (d *Daemon) registerEndpoints() {
+	townRoot := d.config.TownRoot
+	for _, e := range townEndpoints(townRoot) {
+		if err := endpoints.Register(townRoot, e); err != nil {
+			d.logger.Printf("endpoints: registering %s: %v", e.Name, err)
+			continue
+		}
+		conflicts, err := endpoints.Conflicts(townRoot, e)
+		if err != nil {
+			d.logger.Printf("endpoints: checking %s: %v", e.Name, err)
+			continue
+		}
+		if len(conflicts) == 0 {
+			continue
+		}
+		var towns []string
+		for _, c := range conflicts {
+			towns = append(towns, c.Town)
+		}
+		msg := fmt.Sprintf("%s endpoint %s is also claimed by %s", e.Name, e.Address(), strings.Join(towns, ", "))
+		if e.Name == endpoints.Dolt {
+			if port, err := endpoints.FreePort(townRoot, e.Port); err == nil {
+				msg += fmt.Sprintf(" (set GT_DOLT_PORT=%d in mayor/daemon.json env)", port)
+			}
+		}
+		d.logger.Printf("endpoints: %s", msg)
+		d.escalate("endpoints", msg)
+	}
+}
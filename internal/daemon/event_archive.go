@@ -0,0 +1,135 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/eventarchive"
+)
+
+// eventArchiveBatch caps how many events one backup cycle archives per
+// database, so a first run against a huge table is spread over cycles.
+const eventArchiveBatch = 50000
+
+// eventArchiveTimeFormat formats the archive cutoff for SQL (UTC).
+const eventArchiveTimeFormat = "2006-01-02 15:04:05"
+
+// eventArchiveAge returns the configured event archive age, or 0 when
+// archival is disabled.
+func eventArchiveAge(config *JsonlGitBackupConfig) time.Duration {
+	if config == nil || config.EventArchiveAgeStr == "" {
+		return 0
+	}
+	if d, err := time.ParseDuration(config.EventArchiveAgeStr); err == nil && d > 0 {
+		return d
+	}
+	return 0
+}
+
+// EventArchivalEnabled reports whether the jsonl_git_backup patrol archives
+// old events out of the live tables.
+func EventArchivalEnabled(config *DaemonPatrolConfig) bool {
+	return IsPatrolEnabled(config, "jsonl_git_backup") && eventArchiveAge(config.Patrols.JsonlGitBackup) > 0
+}
+
+// eventArchival records events written to the archive but not yet deleted
+// from the live table: those with id <= maxID created before cutoff.
+type eventArchival struct {
+	db     string
+	cutoff string
+	maxID  int64
+	count  int
+}
+
+// archiveOldEvents appends each database's events older than the archive
+// age to its archive in the backup repo. The returned archivals are
+// deleted from the live tables by deleteArchivedEvents once committed.
+func (d *Daemon) archiveOldEvents(age time.Duration, gitRepo, dataDir string, databases []string) []eventArchival {
+	cutoff := time.Now().UTC().Add(-age).Format(eventArchiveTimeFormat)
+	var archivals []eventArchival
+	for _, db := range databases {
+		if !validDBName.MatchString(db) {
+			continue
+		}
+		query := fmt.Sprintf("SELECT * FROM `%s`.events WHERE created_at < '%s' ORDER BY id LIMIT %d",
+			db, cutoff, eventArchiveBatch)
+		out, err := d.doltQueryJSON(dataDir, query)
+		if err != nil {
+			d.logger.Printf("jsonl_git_backup: %s: event archive query failed: %v", db, err)
+			continue
+		}
+		events, err := eventarchive.ParseRows(out)
+		if err != nil {
+			d.logger.Printf("jsonl_git_backup: %s: event archive: %v", db, err)
+			continue
+		}
+		if len(events) == 0 {
+			continue
+		}
+		if _, err := eventarchive.Append(eventarchive.Dir(gitRepo, db), events); err != nil {
+			d.logger.Printf("jsonl_git_backup: %s: event archive write failed: %v", db, err)
+			continue
+		}
+		archivals = append(archivals, eventArchival{
+			db:     db,
+			cutoff: cutoff,
+			maxID:  events[len(events)-1].ID,
+			count:  len(events),
+		})
+		d.logger.Printf("jsonl_git_backup: %s: archived %d event(s) older than %s", db, len(events), cutoff)
+	}
+	return archivals
+}
+
+// deleteArchivedEvents deletes archived events from the live tables, but
+// only for databases whose archive is committed in the backup repo: an
+// uncommitted archive could still be lost.
+func (d *Daemon) deleteArchivedEvents(gitRepo, dataDir string, archivals []eventArchival) {
+	for _, a := range archivals {
+		rel := filepath.Join(a.db, eventarchive.DirName)
+		status, err := d.gitOutput(gitRepo, "status", "--porcelain", "--", rel)
+		if err != nil || status != "" {
+			d.logger.Printf("jsonl_git_backup: %s: event archive not committed, keeping %d live event(s)", a.db, a.count)
+			continue
+		}
+		query := fmt.Sprintf("DELETE FROM `%s`.events WHERE id <= %d AND created_at < '%s'", a.db, a.maxID, a.cutoff)
+		if _, err := d.doltQueryJSON(dataDir, query); err != nil {
+			d.logger.Printf("jsonl_git_backup: %s: deleting archived events failed: %v", a.db, err)
+			continue
+		}
+		d.logger.Printf("jsonl_git_backup: %s: deleted %d archived event(s) from the live table", a.db, a.count)
+	}
+}
+
+// doltQueryJSON runs a query through the dolt CLI in the data directory
+// and returns its JSON output.
+func (d *Daemon) doltQueryJSON(dataDir, query string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), jsonlExportTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "dolt", "sql", "-r", "json", "-q", query)
+	cmd.Dir = dataDir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// gitOutput runs a git command in dir and returns its trimmed stdout.
+func (d *Daemon) gitOutput(dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitCmdTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...).Output()
+	return strings.TrimSpace(string(out)), err
+}
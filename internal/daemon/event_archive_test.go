@@ -0,0 +1,43 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventArchiveAge(t *testing.T) {
+	if got := eventArchiveAge(nil); got != 0 {
+		t.Errorf("nil config: got %v, want 0 (disabled)", got)
+	}
+	if got := eventArchiveAge(&JsonlGitBackupConfig{EventArchiveAgeStr: "720h"}); got != 720*time.Hour {
+		t.Errorf("got %v, want 720h", got)
+	}
+	if got := eventArchiveAge(&JsonlGitBackupConfig{EventArchiveAgeStr: "soon"}); got != 0 {
+		t.Errorf("invalid age: got %v, want 0 (disabled)", got)
+	}
+}
+
+func TestEventArchivalSupersedesReaperEventPurge(t *testing.T) {
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			WispReaper:     &WispReaperConfig{Enabled: true, EventRetentionStr: "240h"},
+			JsonlGitBackup: &JsonlGitBackupConfig{Enabled: true},
+		},
+	}
+	if got := ReaperRetention(config).Events; got != 240*time.Hour {
+		t.Errorf("without archival: Events = %v, want 240h", got)
+	}
+
+	config.Patrols.JsonlGitBackup.EventArchiveAgeStr = "720h"
+	if !EventArchivalEnabled(config) {
+		t.Fatal("EventArchivalEnabled = false with event_archive_age set")
+	}
+	if got := ReaperRetention(config).Events; got != 0 {
+		t.Errorf("with archival: Events = %v, want 0 (archived, not purged)", got)
+	}
+
+	config.Patrols.JsonlGitBackup.Enabled = false
+	if EventArchivalEnabled(config) {
+		t.Error("EventArchivalEnabled = true with the backup patrol disabled")
+	}
+}
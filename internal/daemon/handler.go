@@ -27,8 +27,9 @@ const (
 	// a tmux session is alive but sitting at an idle prompt.
 	staleWorkingTimeout = 2 * time.Hour
 
-	// maxDogPoolSize is the target pool size. Dogs idle beyond
-	// dogIdleRemoveTimeout are removed when the pool exceeds this count.
+	// maxDogPoolSize is the default target pool size. Dogs idle beyond
+	// the removal timeout are removed when the pool exceeds this count.
+	// Overridden by patrols.dog_pool in mayor/daemon.json.
 	maxDogPoolSize = 4
 )
 
@@ -138,6 +139,10 @@ func (d *Daemon) reapIdleDogs(mgr *dog.Manager, sm *dog.SessionManager) {
 
 	now := time.Now()
 	poolSize := len(dogs)
+	maxSize := DogPoolMaxSize(d.patrolConfig)
+	sessionTimeout := dogPoolIdleSession(d.patrolConfig)
+	removeTimeout := dogPoolIdleRemove(d.patrolConfig)
+	dryRun := d.isDryRun(DryRunIdleReaper)
 
 	for _, dg := range dogs {
 		if dg.State != dog.StateIdle {
@@ -147,13 +152,15 @@ func (d *Daemon) reapIdleDogs(mgr *dog.Manager, sm *dog.SessionManager) {
 		idleDuration := now.Sub(dg.LastActive)
 
 		// Phase 1: kill stale tmux sessions for idle dogs.
-		if idleDuration >= dogIdleSessionTimeout {
+		if idleDuration >= sessionTimeout {
 			running, err := sm.IsRunning(dg.Name)
 			if err != nil {
 				d.logger.Printf("Handler: error checking session for idle dog %s: %v", dg.Name, err)
 				continue
 			}
-			if running {
+			if running && dryRun {
+				d.logDryRun(DryRunIdleReaper, fmt.Sprintf("stop session (idle %v)", idleDuration.Truncate(time.Minute)), dg.Name)
+			} else if running {
 				d.logger.Printf("Handler: reaping idle dog %s session (idle %v)", dg.Name, idleDuration.Truncate(time.Minute))
 				if err := sm.Stop(dg.Name, true); err != nil {
 					d.logger.Printf("Handler: failed to stop session for idle dog %s: %v", dg.Name, err)
@@ -162,9 +169,15 @@ func (d *Daemon) reapIdleDogs(mgr *dog.Manager, sm *dog.SessionManager) {
 		}
 
 		// Phase 2: remove long-idle dogs when pool is oversized.
-		if poolSize > maxDogPoolSize && idleDuration >= dogIdleRemoveTimeout {
+		if poolSize > maxSize && idleDuration >= removeTimeout {
+			if dryRun {
+				d.logDryRun(DryRunIdleReaper, fmt.Sprintf("remove from kennel (idle %v, pool %d/%d)",
+					idleDuration.Truncate(time.Minute), poolSize, maxSize), dg.Name)
+				poolSize--
+				continue
+			}
 			d.logger.Printf("Handler: removing long-idle dog %s from kennel (idle %v, pool %d/%d)",
-				dg.Name, idleDuration.Truncate(time.Minute), poolSize, maxDogPoolSize)
+				dg.Name, idleDuration.Truncate(time.Minute), poolSize, maxSize)
 
 			// Ensure session is dead before removing.
 			running, _ := sm.IsRunning(dg.Name)
@@ -231,8 +244,11 @@ func (d *Daemon) dispatchPlugins(mgr *dog.Manager, sm *dog.SessionManager, rigsC
 			return // No point continuing if we can't list dogs
 		}
 		if idleDog == nil {
-			d.logger.Printf("Handler: no idle dogs available, deferring remaining plugins")
-			return
+			idleDog = d.growDogPool(mgr)
+			if idleDog == nil {
+				d.logger.Printf("Handler: no idle dogs available, deferring remaining plugins")
+				return
+			}
 		}
 
 		// Assign work and start session.
@@ -271,6 +287,28 @@ func (d *Daemon) dispatchPlugins(mgr *dog.Manager, sm *dog.SessionManager, rigsC
 	}
 }
 
+// growDogPool adds a dog when the pool is below its configured cap.
+// Returns nil when the pool is full or the dog could not be created.
+func (d *Daemon) growDogPool(mgr *dog.Manager) *dog.Dog {
+	dogs, err := mgr.List()
+	if err != nil {
+		d.logger.Printf("Handler: failed to list dogs for pool growth: %v", err)
+		return nil
+	}
+	maxSize := DogPoolMaxSize(d.patrolConfig)
+	if len(dogs) >= maxSize {
+		return nil
+	}
+	name := mgr.NextName()
+	newDog, err := mgr.Add(name)
+	if err != nil {
+		d.logger.Printf("Handler: failed to add dog %s: %v", name, err)
+		return nil
+	}
+	d.logger.Printf("Handler: added dog %s (pool %d/%d)", name, len(dogs)+1, maxSize)
+	return newDog
+}
+
 // loadRigsConfig loads the rigs configuration from mayor/rigs.json.
 func (d *Daemon) loadRigsConfig() (*config.RigsConfig, error) {
 	rigsPath := filepath.Join(d.config.TownRoot, "mayor", "rigs.json")
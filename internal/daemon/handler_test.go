@@ -224,6 +224,31 @@ func TestReapIdleDogs_SkipsRecentlyActiveDogs(t *testing.T) {
 	}
 }
 
+func TestReapIdleDogs_DryRunKeepsDogs(t *testing.T) {
+	townRoot := t.TempDir()
+	d := testHandlerDaemon(t, townRoot)
+	d.config.DryRun = []string{DryRunIdleReaper}
+
+	rigsConfig := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{}}
+	mgr := dog.NewManager(townRoot, rigsConfig)
+	sm := dog.NewSessionManager(tmux.NewTmux(), townRoot, mgr)
+
+	// Oversized pool of long-idle dogs: a real reap would remove some.
+	for i := 0; i < 6; i++ {
+		testSetupDogState(t, townRoot, "old-"+string(rune('a'+i)), dog.StateIdle, time.Now().Add(-6*time.Hour))
+	}
+
+	d.reapIdleDogs(mgr, sm)
+
+	dogs, err := mgr.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(dogs) != 6 {
+		t.Errorf("dry run removed dogs: %d left, want 6", len(dogs))
+	}
+}
+
 func TestReapIdleDogs_RemovesLongIdleDogsWhenPoolOversized(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("skipping on Windows: requires tmux")
@@ -394,3 +419,38 @@ func TestReapIdleDogs_Constants(t *testing.T) {
 		t.Errorf("maxDogPoolSize = %d, want 4", maxDogPoolSize)
 	}
 }
+
+func TestReapIdleDogs_HonorsConfiguredPoolSize(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on Windows: requires tmux")
+	}
+	townRoot := t.TempDir()
+	d := testHandlerDaemon(t, townRoot)
+	d.patrolConfig = &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{DogPool: &DogPoolConfig{MaxSize: 2, IdleRemoveStr: "30m"}},
+	}
+
+	rigsConfig := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{}}
+	mgr := dog.NewManager(townRoot, rigsConfig)
+	sm := dog.NewSessionManager(tmux.NewTmux(), townRoot, mgr)
+
+	// Idle for 1h: below the 4h default but past the configured 30m.
+	for _, name := range []string{"a", "b", "c"} {
+		testSetupDogState(t, townRoot, name, dog.StateIdle, time.Now().Add(-1*time.Hour))
+	}
+
+	d.reapIdleDogs(mgr, sm)
+
+	dogs, err := mgr.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(dogs) != 2 {
+		t.Errorf("expected pool trimmed to configured max 2, got %d", len(dogs))
+	}
+
+	// Pool is at its cap, so it must not grow.
+	if got := d.growDogPool(mgr); got != nil {
+		t.Errorf("growDogPool at cap returned %s, want nil", got.Name)
+	}
+}
@@ -0,0 +1,211 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// jsonlBackupManifest is the machine-readable record of a backup commit,
+// written to the root of the backup repo and committed with the exports.
+const jsonlBackupManifest = "backup-manifest.json"
+
+// tableDelta is how one exported table changed since the previous backup
+// commit.
+type tableDelta struct {
+	DB      string `json:"-"`
+	Table   string `json:"-"`
+	Rows    int    `json:"rows"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Changed int    `json:"changed"`
+}
+
+// changed reports whether the table differs from the previous commit.
+func (t tableDelta) changed() bool {
+	return t.Added > 0 || t.Removed > 0 || t.Changed > 0
+}
+
+// backupManifest is the content of jsonlBackupManifest.
+type backupManifest struct {
+	Timestamp time.Time                        `json:"timestamp"`
+	Previous  string                           `json:"previous,omitempty"` // commit the deltas are against
+	Databases map[string]map[string]tableDelta `json:"databases"`
+	Failed    []string                         `json:"failed,omitempty"`
+}
+
+// computeBackupDeltas compares each database's exported tables
+// ({gitRepo}/{db}/*.jsonl) with the previous commit, row by row. Tables
+// that were removed since are included with zero rows. Results are sorted
+// by database (in the given order), then table.
+func computeBackupDeltas(gitRepo string, databases []string) []tableDelta {
+	var deltas []tableDelta
+	for _, db := range databases {
+		tables := map[string]bool{}
+		if matches, err := filepath.Glob(filepath.Join(gitRepo, db, "*.jsonl")); err == nil {
+			for _, m := range matches {
+				tables[strings.TrimSuffix(filepath.Base(m), ".jsonl")] = true
+			}
+		}
+		for _, f := range previousCommitFiles(gitRepo, db) {
+			if strings.HasSuffix(f, ".jsonl") {
+				tables[strings.TrimSuffix(f, ".jsonl")] = true
+			}
+		}
+
+		names := make([]string, 0, len(tables))
+		for name := range tables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, table := range names {
+			rel := path.Join(db, table+".jsonl")
+			prev, _ := previousCommitFile(gitRepo, rel)
+			cur, _ := os.ReadFile(filepath.Join(gitRepo, db, table+".jsonl"))
+			delta := diffJsonlRows(prev, cur)
+			delta.DB, delta.Table = db, table
+			deltas = append(deltas, delta)
+		}
+	}
+	return deltas
+}
+
+// diffJsonlRows counts rows added, removed and changed between two JSONL
+// exports. Rows are matched by rowKey; Rows is the current row count.
+func diffJsonlRows(prev, cur []byte) tableDelta {
+	before := map[string]string{}
+	forEachJsonlLine(prev, func(line string) {
+		before[rowKey(line)] = line
+	})
+
+	var d tableDelta
+	seen := map[string]bool{}
+	forEachJsonlLine(cur, func(line string) {
+		d.Rows++
+		key := rowKey(line)
+		seen[key] = true
+		old, ok := before[key]
+		switch {
+		case !ok:
+			d.Added++
+		case old != line:
+			d.Changed++
+		}
+	})
+	for key := range before {
+		if !seen[key] {
+			d.Removed++
+		}
+	}
+	return d
+}
+
+// rowKey identifies a row across exports: its "id", else its "key" (config
+// and metadata tables), else the whole row, so rows of link tables such as
+// labels and dependencies show up as added and removed, never changed.
+func rowKey(line string) string {
+	var row map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &row); err == nil {
+		for _, field := range []string{"id", "key"} {
+			if v, ok := row[field]; ok && v != nil {
+				return fmt.Sprintf("%s=%v", field, v)
+			}
+		}
+	}
+	return line
+}
+
+// forEachJsonlLine calls fn for each non-empty line.
+func forEachJsonlLine(data []byte, fn func(line string)) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 256*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			fn(line)
+		}
+	}
+}
+
+// previousCommitFile returns a file's content in HEAD, or nil if it is not
+// in HEAD.
+func previousCommitFile(gitRepo, relPath string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitCmdTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", "-C", gitRepo, "show", "HEAD:"+relPath).Output()
+	if err != nil {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// previousCommitFiles lists the files directly under dir in HEAD.
+func previousCommitFiles(gitRepo, dir string) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), gitCmdTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", "-C", gitRepo, "ls-tree", "--name-only", "HEAD", dir+"/").Output()
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			files = append(files, path.Base(line))
+		}
+	}
+	return files
+}
+
+// previousCommitID returns HEAD's commit hash, or "" in a repo without
+// commits.
+func previousCommitID(gitRepo string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), gitCmdTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", "-C", gitRepo, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// writeBackupManifest writes the manifest of a backup commit.
+func writeBackupManifest(gitRepo string, deltas []tableDelta, failed []string, now time.Time) error {
+	m := backupManifest{
+		Timestamp: now.UTC(),
+		Previous:  previousCommitID(gitRepo),
+		Databases: map[string]map[string]tableDelta{},
+		Failed:    failed,
+	}
+	for _, d := range deltas {
+		if m.Databases[d.DB] == nil {
+			m.Databases[d.DB] = map[string]tableDelta{}
+		}
+		m.Databases[d.DB][d.Table] = d
+	}
+	return util.AtomicWriteJSON(filepath.Join(gitRepo, jsonlBackupManifest), m)
+}
+
+// formatDeltaSummary renders the tables that changed, one per line, for
+// the backup commit message body, e.g.
+//
+//	hq/issues: 1203 rows (+5 -300 ~12)
+func formatDeltaSummary(deltas []tableDelta) string {
+	var b strings.Builder
+	for _, d := range deltas {
+		if !d.changed() {
+			continue
+		}
+		fmt.Fprintf(&b, "%s/%s: %d rows (+%d -%d ~%d)\n", d.DB, d.Table, d.Rows, d.Added, d.Removed, d.Changed)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,77 @@
+package daemon
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDiffJsonlRows(t *testing.T) {
+	prev := []byte(`{"id":"gt-1","title":"a"}
+{"id":"gt-2","title":"b"}
+{"id":"gt-3","title":"c"}
+`)
+	cur := []byte(`{"id":"gt-1","title":"a"}
+{"id":"gt-2","title":"B"}
+{"id":"gt-4","title":"d"}
+{"id":"gt-5","title":"e"}
+`)
+	got := diffJsonlRows(prev, cur)
+	if got.Rows != 4 || got.Added != 2 || got.Removed != 1 || got.Changed != 1 {
+		t.Errorf("diffJsonlRows = %+v, want rows=4 +2 -1 ~1", got)
+	}
+
+	// Link tables have no id: an edit is a remove plus an add.
+	got = diffJsonlRows([]byte(`{"issue_id":"gt-1","label":"bug"}`+"\n"), []byte(`{"issue_id":"gt-1","label":"p1"}`+"\n"))
+	if got.Added != 1 || got.Removed != 1 || got.Changed != 0 {
+		t.Errorf("label diff = %+v, want +1 -1", got)
+	}
+}
+
+func TestComputeBackupDeltasAndManifest(t *testing.T) {
+	gitRepo := t.TempDir()
+	initGitRepo(t, gitRepo)
+
+	dbDir := filepath.Join(gitRepo, "hq")
+	os.MkdirAll(dbDir, 0755)
+	writeNLines(t, filepath.Join(dbDir, "issues.jsonl"), 10)
+	writeNLines(t, filepath.Join(dbDir, "comments.jsonl"), 3)
+	commitAll(t, gitRepo, "baseline")
+
+	// issues drop by 4; comments are gone from the export.
+	writeNLines(t, filepath.Join(dbDir, "issues.jsonl"), 6)
+	os.Remove(filepath.Join(dbDir, "comments.jsonl"))
+
+	deltas := computeBackupDeltas(gitRepo, []string{"hq"})
+	if len(deltas) != 2 {
+		t.Fatalf("deltas = %+v, want comments and issues", deltas)
+	}
+	if d := deltas[0]; d.Table != "comments" || d.Rows != 0 || d.Removed != 3 {
+		t.Errorf("comments delta = %+v", d)
+	}
+	if d := deltas[1]; d.Table != "issues" || d.Rows != 6 || d.Removed != 4 || d.Added != 0 {
+		t.Errorf("issues delta = %+v", d)
+	}
+
+	want := "hq/comments: 0 rows (+0 -3 ~0)\nhq/issues: 6 rows (+0 -4 ~0)"
+	if got := formatDeltaSummary(deltas); got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+
+	if err := writeBackupManifest(gitRepo, deltas, nil, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(gitRepo, jsonlBackupManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m backupManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Previous == "" || m.Databases["hq"]["issues"].Removed != 4 {
+		t.Errorf("manifest = %s", data)
+	}
+}
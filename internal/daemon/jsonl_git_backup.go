@@ -14,12 +14,17 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/townstate"
 )
 
 const (
 	defaultJsonlGitBackupInterval = 15 * time.Minute
 	jsonlExportTimeout            = 60 * time.Second
+	defaultJsonlExportWorkers     = 3
 	gitPushTimeout                = 120 * time.Second
 	gitCmdTimeout                 = 30 * time.Second
 	maxConsecutivePushFailures    = 3
@@ -64,6 +69,19 @@ func jsonlGitBackupInterval(config *DaemonPatrolConfig) time.Duration {
 	return defaultJsonlGitBackupInterval
 }
 
+// JsonlGitBackupRepo returns the backup git repository: the configured
+// git_repo, or ~/.dolt-archive/git.
+func JsonlGitBackupRepo(config *JsonlGitBackupConfig) (string, error) {
+	if config != nil && config.GitRepo != "" {
+		return config.GitRepo, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home dir: %w", err)
+	}
+	return filepath.Join(homeDir, ".dolt-archive", "git"), nil
+}
+
 // syncJsonlGitBackup exports issues from each database to JSONL, scrubs ephemeral data,
 // and commits/pushes to a git repository.
 // Non-fatal: errors are logged but don't stop the daemon.
@@ -71,6 +89,11 @@ func (d *Daemon) syncJsonlGitBackup() {
 	if !IsPatrolEnabled(d.patrolConfig, "jsonl_git_backup") {
 		return
 	}
+	release, ok := d.acquireResource(lock.ResourceDolt, "jsonl_git_backup")
+	if !ok {
+		return
+	}
+	defer release()
 
 	// Pour molecule for observability (nil-safe — all methods are no-ops on nil).
 	mol := d.pourDogMolecule("mol-dog-jsonl", nil)
@@ -79,14 +102,10 @@ func (d *Daemon) syncJsonlGitBackup() {
 	config := d.patrolConfig.Patrols.JsonlGitBackup
 
 	// Resolve git repo path.
-	gitRepo := config.GitRepo
-	if gitRepo == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			d.logger.Printf("jsonl_git_backup: cannot determine home dir: %v", err)
-			return
-		}
-		gitRepo = filepath.Join(homeDir, ".dolt-archive", "git")
+	gitRepo, err := JsonlGitBackupRepo(config)
+	if err != nil {
+		d.logger.Printf("jsonl_git_backup: %v", err)
+		return
 	}
 
 	// Verify git repo exists.
@@ -126,7 +145,7 @@ func (d *Daemon) syncJsonlGitBackup() {
 	var failed []string
 	counts := make(map[string]int)
 	for _, db := range databases {
-		n, err := d.exportDatabaseToJsonl(db, gitRepo, dataDir, scrub)
+		n, err := d.exportDatabaseToJsonl(db, gitRepo, dataDir, scrub, config)
 		if err != nil {
 			d.logger.Printf("jsonl_git_backup: %s: export failed: %v", db, err)
 			failed = append(failed, db)
@@ -158,11 +177,26 @@ func (d *Daemon) syncJsonlGitBackup() {
 	if len(spikes) > 0 {
 		report := formatSpikeReport(spikes)
 		d.logger.Printf("jsonl_git_backup: HALTING — spike detected:\n%s", report)
-		d.escalate("jsonl_git_backup", report)
+		d.escalate("jsonl_git_backup", fmt.Sprintf("export count spike in %d table(s), backup halted", len(spikes)),
+			"git_repo="+gitRepo, fmt.Sprintf("threshold=%.0f%%", threshold*100), "spikes="+report)
 		mol.failStep("push", "spike detected")
 		return // Do NOT commit — spike detected.
 	}
 
+	// Old events move into the archive; they leave the live tables only
+	// once the archive is committed.
+	var archivals []eventArchival
+	if age := eventArchiveAge(config); age > 0 {
+		archivals = d.archiveOldEvents(age, gitRepo, dataDir, databases)
+	}
+
+	// Town state rides along in the same commit.
+	if config.TownState == nil || *config.TownState {
+		if _, err := townstate.Snapshot(d.config.TownRoot, filepath.Join(gitRepo, townstate.Dir)); err != nil {
+			d.logger.Printf("jsonl_git_backup: town state snapshot failed: %v", err)
+		}
+	}
+
 	// Commit and push if anything changed.
 	// Include failed databases in commit message so staleness is visible.
 	pushStatus := "ok"
@@ -173,7 +207,8 @@ func (d *Daemon) syncJsonlGitBackup() {
 		d.jsonlPushFailures++
 		if d.jsonlPushFailures >= maxConsecutivePushFailures {
 			d.logger.Printf("jsonl_git_backup: ESCALATION: %d consecutive push failures", d.jsonlPushFailures)
-			d.escalate("jsonl_git_backup", fmt.Sprintf("git push failed %d consecutive times", d.jsonlPushFailures))
+			d.escalate("jsonl_git_backup", fmt.Sprintf("git push failed %d consecutive times", d.jsonlPushFailures),
+				"git_repo="+gitRepo, "last_error="+err.Error())
 			// Reset to avoid flooding escalations every tick.
 			d.jsonlPushFailures = 0
 		}
@@ -181,6 +216,9 @@ func (d *Daemon) syncJsonlGitBackup() {
 		d.jsonlPushFailures = 0
 		mol.closeStep("push")
 	}
+	if len(archivals) > 0 {
+		d.deleteArchivedEvents(gitRepo, dataDir, archivals)
+	}
 
 	d.logger.Printf("jsonl_git_backup: exported %d/%d database(s), push=%s", exported, len(databases), pushStatus)
 	mol.closeStep("report")
@@ -190,6 +228,7 @@ func (d *Daemon) syncJsonlGitBackup() {
 // These contain structural data (dependencies, labels, config) that would be
 // lost if we only backed up the issues table. Wisp tables are excluded — they
 // contain high-volume ephemeral data handled by the Reaper Dog.
+// This is the default; database_tables adjusts it per database.
 var supplementalTables = []string{
 	"comments",
 	"config",
@@ -204,9 +243,11 @@ var supplementalTables = []string{
 //
 // Issues go to {db}/issues.jsonl (scrubbed). Other tables go to {db}/{table}.jsonl.
 // Also writes a legacy {db}.jsonl (symlink to {db}/issues.jsonl) for backward compat.
+// Supplemental tables are exported concurrently (see jsonlExportWorkers), each
+// under its own timeout.
 //
 // Returns the total number of records exported across all tables.
-func (d *Daemon) exportDatabaseToJsonl(db, gitRepo, dataDir string, scrub bool) (int, error) {
+func (d *Daemon) exportDatabaseToJsonl(db, gitRepo, dataDir string, scrub bool, config *JsonlGitBackupConfig) (int, error) {
 	if !validDBName.MatchString(db) {
 		return 0, fmt.Errorf("invalid database name: %q", db)
 	}
@@ -226,7 +267,7 @@ func (d *Daemon) exportDatabaseToJsonl(db, gitRepo, dataDir string, scrub bool)
 	} else {
 		query = "SELECT * FROM `" + db + "`.issues ORDER BY id"
 	}
-	n, err := d.exportTableToJsonl(db, "issues", query, dbDir, dataDir)
+	n, err := d.exportTableToJsonl(db, "issues", query, dbDir, dataDir, jsonlExportTimeout)
 	if err != nil {
 		return 0, fmt.Errorf("issues: %w", err)
 	}
@@ -241,25 +282,163 @@ func (d *Daemon) exportDatabaseToJsonl(db, gitRepo, dataDir string, scrub bool)
 	}
 
 	// 2. Export supplemental tables (no scrub, full export).
-	for _, table := range supplementalTables {
+	existing, err := d.listDatabaseTables(db, dataDir)
+	if err != nil {
+		// Export the configured list unchecked; missing tables fail non-fatally.
+		d.logger.Printf("jsonl_git_backup: %s: listing tables failed, not validating table list: %v", db, err)
+	}
+	tables, skipped := supplementalTablesFor(db, config, existing)
+	if len(skipped) > 0 {
+		d.logger.Printf("jsonl_git_backup: %s: skipping table(s) not in the database: %s", db, strings.Join(skipped, ", "))
+	}
+	start := time.Now()
+	results := exportTablesConcurrently(tables, jsonlExportWorkers(config), func(table string) (int, error) {
 		tQuery := fmt.Sprintf("SELECT * FROM `%s`.`%s` ORDER BY 1", db, table)
-		tn, err := d.exportTableToJsonl(db, table, tQuery, dbDir, dataDir)
-		if err != nil {
+		return d.exportTableToJsonl(db, table, tQuery, dbDir, dataDir, jsonlTableTimeout(config, table))
+	})
+	for _, r := range results {
+		if r.err != nil {
 			// Non-fatal for supplemental tables — log and continue.
-			d.logger.Printf("jsonl_git_backup: %s/%s: export failed (non-fatal): %v", db, table, err)
+			d.logger.Printf("jsonl_git_backup: %s/%s: export failed after %v (non-fatal): %v",
+				db, r.table, r.elapsed.Round(time.Millisecond), r.err)
 			continue
 		}
-		total += tn
+		total += r.count
 	}
 
-	d.logger.Printf("jsonl_git_backup: %s: exported %d records across %d tables", db, total, 1+len(supplementalTables))
+	d.logger.Printf("jsonl_git_backup: %s: exported %d records across %d tables (supplemental in %v)",
+		db, total, 1+len(tables), time.Since(start).Round(time.Millisecond))
 	return total, nil
 }
 
+// supplementalTablesFor returns the supplemental tables to export for db:
+// the default list adjusted by the database's database_tables entry.
+// Invalid names, the issues table and duplicates are dropped. When existing
+// (the database's tables) is non-nil, tables not in it are returned as
+// skipped instead.
+func supplementalTablesFor(db string, config *JsonlGitBackupConfig, existing map[string]bool) (tables, skipped []string) {
+	candidates := supplementalTables
+	var sel *JsonlTableSelection
+	if config != nil {
+		sel = config.DatabaseTables[db]
+	}
+	if sel != nil {
+		if len(sel.Include) > 0 {
+			candidates = sel.Include
+		}
+		candidates = append(append([]string(nil), candidates...), sel.Extra...)
+	}
+
+	excluded := make(map[string]bool)
+	if sel != nil {
+		for _, t := range sel.Exclude {
+			excluded[t] = true
+		}
+	}
+	seen := map[string]bool{"issues": true}
+	for _, t := range candidates {
+		if seen[t] || excluded[t] || !validDBName.MatchString(t) {
+			continue
+		}
+		seen[t] = true
+		if existing != nil && !existing[strings.ToLower(t)] {
+			skipped = append(skipped, t)
+			continue
+		}
+		tables = append(tables, t)
+	}
+	return tables, skipped
+}
+
+// listDatabaseTables returns the base tables in db (lowercased), from
+// information_schema.
+func (d *Daemon) listDatabaseTables(db, dataDir string) (map[string]bool, error) {
+	query := fmt.Sprintf("SELECT table_name AS name FROM information_schema.tables WHERE table_schema = '%s' AND table_type = 'BASE TABLE'", db)
+	out, err := d.doltQueryJSON(dataDir, query)
+	if err != nil {
+		return nil, err
+	}
+	return parseTableNames(out)
+}
+
+// parseTableNames extracts the name column from dolt's JSON query output.
+func parseTableNames(out []byte) (map[string]bool, error) {
+	var result struct {
+		Rows []struct {
+			Name string `json:"name"`
+		} `json:"rows"`
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return map[string]bool{}, nil
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("parsing table list: %w", err)
+	}
+	names := make(map[string]bool, len(result.Rows))
+	for _, r := range result.Rows {
+		names[strings.ToLower(r.Name)] = true
+	}
+	return names, nil
+}
+
+// tableExportResult is the outcome of exporting one table.
+type tableExportResult struct {
+	table   string
+	count   int
+	err     error
+	elapsed time.Duration
+}
+
+// exportTablesConcurrently runs export for each table with at most workers
+// running at once. Results are returned in table order.
+func exportTablesConcurrently(tables []string, workers int, export func(table string) (int, error)) []tableExportResult {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]tableExportResult, len(tables))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, table := range tables {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, table string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			start := time.Now()
+			n, err := export(table)
+			results[i] = tableExportResult{table: table, count: n, err: err, elapsed: time.Since(start)}
+		}(i, table)
+	}
+	wg.Wait()
+	return results
+}
+
+// jsonlExportWorkers returns the configured supplemental export concurrency,
+// or the default (3).
+func jsonlExportWorkers(config *JsonlGitBackupConfig) int {
+	if config != nil && config.ExportWorkers > 0 {
+		return config.ExportWorkers
+	}
+	return defaultJsonlExportWorkers
+}
+
+// jsonlTableTimeout returns the export timeout for a table: its
+// table_timeouts entry, or the default (60s).
+func jsonlTableTimeout(config *JsonlGitBackupConfig, table string) time.Duration {
+	if config != nil {
+		if s, ok := config.TableTimeouts[table]; ok {
+			if d, err := time.ParseDuration(s); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return jsonlExportTimeout
+}
+
 // exportTableToJsonl runs a query and writes the result as JSONL to {dir}/{table}.jsonl.
 // Returns the number of records exported.
-func (d *Daemon) exportTableToJsonl(db, table, query, dir, dataDir string) (int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), jsonlExportTimeout)
+func (d *Daemon) exportTableToJsonl(db, table, query, dir, dataDir string, timeout time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "dolt", "sql", "-r", "json", "-q", query)
@@ -270,6 +449,9 @@ func (d *Daemon) exportTableToJsonl(db, table, query, dir, dataDir string) (int,
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return 0, fmt.Errorf("timed out after %v", timeout)
+		}
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
 			return 0, fmt.Errorf("%s: %s", err, errMsg)
@@ -310,7 +492,9 @@ func (d *Daemon) exportTableToJsonl(db, table, query, dir, dataDir string) (int,
 
 // commitAndPushJsonlBackup stages, commits, and pushes JSONL files if changed.
 // The commit message includes counts for successful exports AND names of failed
-// databases, so partial failures are visible in git history.
+// databases, so partial failures are visible in git history. Its body lists
+// each changed table's rows added, removed and changed since the previous
+// commit; the same deltas for every table go in backup-manifest.json.
 func (d *Daemon) commitAndPushJsonlBackup(gitRepo string, databases []string, counts map[string]int, failed []string) error {
 	// Stage all JSONL files (flat legacy files + subdirectory structure).
 	// Use "." instead of "*/" to correctly handle initially-untracked subdirectories.
@@ -324,8 +508,18 @@ func (d *Daemon) commitAndPushJsonlBackup(gitRepo string, databases []string, co
 		return nil
 	}
 
+	// Record per-table deltas against the previous commit.
+	now := time.Now()
+	sort.Strings(failed)
+	deltas := computeBackupDeltas(gitRepo, databases)
+	if err := writeBackupManifest(gitRepo, deltas, failed, now); err != nil {
+		d.logger.Printf("jsonl_git_backup: writing manifest: %v", err)
+	} else if err := d.runGitCmd(gitRepo, gitCmdTimeout, "add", jsonlBackupManifest); err != nil {
+		return fmt.Errorf("git add %s: %w", jsonlBackupManifest, err)
+	}
+
 	// Build commit message with counts in deterministic order.
-	timestamp := time.Now().Format("2006-01-02 15:04")
+	timestamp := now.Format("2006-01-02 15:04")
 	var parts []string
 	for _, db := range databases {
 		if n, ok := counts[db]; ok {
@@ -334,9 +528,11 @@ func (d *Daemon) commitAndPushJsonlBackup(gitRepo string, databases []string, co
 	}
 	msg := fmt.Sprintf("backup %s: %s", timestamp, strings.Join(parts, " "))
 	if len(failed) > 0 {
-		sort.Strings(failed)
 		msg += fmt.Sprintf(" [FAILED: %s]", strings.Join(failed, ", "))
 	}
+	if summary := formatDeltaSummary(deltas); summary != "" {
+		msg += "\n\n" + summary
+	}
 
 	// Commit.
 	if err := d.runGitCmd(gitRepo, gitCmdTimeout, "commit", "-m", msg,
@@ -345,11 +541,17 @@ func (d *Daemon) commitAndPushJsonlBackup(gitRepo string, databases []string, co
 	}
 
 	// Push — use longer timeout since push involves network I/O.
+	releaseRemote, ok := d.acquireResource(lock.ResourceGitRemote, "jsonl_git_backup")
+	if !ok {
+		return fmt.Errorf("git push: %w", lock.ErrResourceBusy)
+	}
+	defer releaseRemote()
 	if err := d.runGitCmd(gitRepo, gitPushTimeout, "push", "origin", "main"); err != nil {
 		return fmt.Errorf("git push: %w", err)
 	}
 
-	d.logger.Printf("jsonl_git_backup: committed and pushed: %s", msg)
+	subject, _, _ := strings.Cut(msg, "\n")
+	d.logger.Printf("jsonl_git_backup: committed and pushed: %s", subject)
 	return nil
 }
 
@@ -374,16 +576,25 @@ func (d *Daemon) runGitCmd(dir string, timeout time.Duration, args ...string) er
 }
 
 // escalate sends an escalation message to the mayor via gt escalate.
-func (d *Daemon) escalate(source, message string) {
+// attach entries ("name=value") are attached as structured context.
+func (d *Daemon) escalate(source, message string, attach ...string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "gt", "escalate", "-s", "HIGH",
-		fmt.Sprintf("%s: %s", source, message))
+	cmd := exec.CommandContext(ctx, "gt", escalateArgs(source, message, attach)...)
 	cmd.Dir = d.config.TownRoot
 	if output, err := cmd.CombinedOutput(); err != nil {
-		d.logger.Printf("jsonl_git_backup: escalation failed: %v (%s)", err, strings.TrimSpace(string(output)))
+		d.logger.Printf("%s: escalation failed: %v (%s)", source, err, strings.TrimSpace(string(output)))
+	}
+}
+
+// escalateArgs builds the gt escalate arguments for a patrol escalation.
+func escalateArgs(source, message string, attach []string) []string {
+	args := []string{"escalate", "-s", "HIGH", "--source", "patrol:" + source}
+	for _, c := range attach {
+		args = append(args, "--context", c)
 	}
+	return append(args, fmt.Sprintf("%s: %s", source, message))
 }
 
 // spikeThreshold returns the configured spike threshold or the default (20%).
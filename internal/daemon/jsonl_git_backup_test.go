@@ -2,13 +2,17 @@ package daemon
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestIsTestPollution(t *testing.T) {
@@ -203,6 +207,107 @@ func TestSpikeThreshold(t *testing.T) {
 	}
 }
 
+func TestJsonlExportWorkersAndTableTimeout(t *testing.T) {
+	if got := jsonlExportWorkers(nil); got != defaultJsonlExportWorkers {
+		t.Errorf("jsonlExportWorkers(nil) = %d, want %d", got, defaultJsonlExportWorkers)
+	}
+	config := &JsonlGitBackupConfig{
+		ExportWorkers: 6,
+		TableTimeouts: map[string]string{"events": "5s", "labels": "bogus"},
+	}
+	if got := jsonlExportWorkers(config); got != 6 {
+		t.Errorf("jsonlExportWorkers = %d, want 6", got)
+	}
+	if got := jsonlTableTimeout(config, "events"); got != 5*time.Second {
+		t.Errorf("jsonlTableTimeout(events) = %v, want 5s", got)
+	}
+	for _, table := range []string{"labels", "comments"} {
+		if got := jsonlTableTimeout(config, table); got != jsonlExportTimeout {
+			t.Errorf("jsonlTableTimeout(%s) = %v, want default %v", table, got, jsonlExportTimeout)
+		}
+	}
+}
+
+func TestExportTablesConcurrently(t *testing.T) {
+	tables := []string{"comments", "config", "dependencies", "events", "labels", "metadata"}
+	var running, peak int32
+	results := exportTablesConcurrently(tables, 2, func(table string) (int, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		if table == "events" {
+			return 0, errTestTimeout
+		}
+		return len(table), nil
+	})
+
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", peak)
+	}
+	if len(results) != len(tables) {
+		t.Fatalf("got %d results, want %d", len(results), len(tables))
+	}
+	for i, r := range results {
+		if r.table != tables[i] {
+			t.Errorf("results[%d].table = %s, want %s (table order)", i, r.table, tables[i])
+		}
+		if r.table == "events" {
+			if r.err == nil {
+				t.Error("events result should carry its error")
+			}
+		} else if r.err != nil || r.count != len(r.table) {
+			t.Errorf("%s: count=%d err=%v", r.table, r.count, r.err)
+		}
+	}
+}
+
+var errTestTimeout = errors.New("timed out")
+
+func TestSupplementalTablesFor(t *testing.T) {
+	config := &JsonlGitBackupConfig{
+		DatabaseTables: map[string]*JsonlTableSelection{
+			"hq":     {Exclude: []string{"events"}, Extra: []string{"attachments", "issues", "bad-name", "labels"}},
+			"custom": {Include: []string{"dependencies", "widgets"}},
+		},
+	}
+
+	got, skipped := supplementalTablesFor("gastown", config, nil)
+	if !reflect.DeepEqual(got, supplementalTables) || skipped != nil {
+		t.Errorf("unconfigured db = %v, %v; want defaults", got, skipped)
+	}
+
+	got, _ = supplementalTablesFor("hq", config, nil)
+	want := []string{"comments", "config", "dependencies", "labels", "metadata", "attachments"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hq tables = %v, want %v", got, want)
+	}
+
+	existing := map[string]bool{"dependencies": true, "issues": true}
+	got, skipped = supplementalTablesFor("custom", config, existing)
+	if !reflect.DeepEqual(got, []string{"dependencies"}) || !reflect.DeepEqual(skipped, []string{"widgets"}) {
+		t.Errorf("custom tables = %v, skipped %v; want [dependencies], [widgets]", got, skipped)
+	}
+}
+
+func TestParseTableNames(t *testing.T) {
+	got, err := parseTableNames([]byte(`{"rows":[{"name":"Issues"},{"name":"widgets"}]}`))
+	if err != nil {
+		t.Fatalf("parseTableNames: %v", err)
+	}
+	if !reflect.DeepEqual(got, map[string]bool{"issues": true, "widgets": true}) {
+		t.Errorf("parseTableNames = %v", got)
+	}
+	if got, err := parseTableNames(nil); err != nil || len(got) != 0 {
+		t.Errorf("parseTableNames(empty) = %v, %v; want empty", got, err)
+	}
+}
+
 func TestFormatSpikeReport(t *testing.T) {
 	spikes := []spikeInfo{
 		{DB: "prod_beads", File: "prod_beads/issues.jsonl", Previous: 100, Current: 150, Delta: 0.50},
@@ -449,3 +554,12 @@ func writeNLines(t *testing.T, path string, n int) {
 func itoa(i int) string {
 	return strconv.Itoa(i)
 }
+
+func TestEscalateArgs(t *testing.T) {
+	got := escalateArgs("doctor_dog", "latency high", []string{"latency=3s", "threshold=1s"})
+	want := []string{"escalate", "-s", "HIGH", "--source", "patrol:doctor_dog",
+		"--context", "latency=3s", "--context", "threshold=1s", "doctor_dog: latency high"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("escalateArgs = %q, want %q", got, want)
+	}
+}
@@ -390,7 +390,8 @@ func (d *Daemon) restartSession(sessionName, identity string) error {
 
 	// Wait for Claude to start, then accept startup dialogs if they appear.
 	if err := d.tmux.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
-		// Non-fatal - Claude might still start
+		// Non-fatal - Claude might still start; the diagnosis says why it hasn't yet
+		d.logger.Printf("Session %s: %v", sessionName, err)
 	}
 	_ = d.tmux.AcceptStartupDialogs(sessionName)
 	time.Sleep(constants.ShutdownNotifyDelay)
@@ -545,14 +546,22 @@ func (d *Daemon) setSessionEnvironment(sessionName string, roleConfig *beads.Rol
 	}
 }
 
-// applySessionTheme applies tmux theming to the session.
+// applySessionTheme applies tmux theming and, for rig agents, the rig's
+// session template to the session.
 func (d *Daemon) applySessionTheme(sessionName string, parsed *ParsedIdentity) {
 	if parsed.RoleType == "mayor" {
 		theme := tmux.MayorTheme()
 		_ = d.tmux.ConfigureGasTownSession(sessionName, theme, "", "Mayor", "coordinator")
 	} else if parsed.RigName != "" {
 		theme := tmux.AssignTheme(parsed.RigName)
-		_ = d.tmux.ConfigureGasTownSession(sessionName, theme, parsed.RigName, parsed.RoleType, parsed.RoleType)
+		_ = session.ConfigureSession(d.tmux, session.SessionConfig{
+			SessionID: sessionName,
+			Role:      parsed.RoleType,
+			RigPath:   filepath.Join(d.config.TownRoot, parsed.RigName),
+			RigName:   parsed.RigName,
+			AgentName: parsed.RoleType,
+			Theme:     &theme,
+		})
 	}
 }
 
@@ -0,0 +1,210 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/metrics"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+const (
+	defaultMetricsHistoryInterval  = 5 * time.Minute
+	defaultMetricsHistoryRetention = 90 * 24 * time.Hour
+	metricsQueryTimeout            = 10 * time.Second
+)
+
+// MetricsHistoryConfig holds configuration for the metrics_history patrol,
+// which samples key town metrics into the local history read by gt report
+// and the dashboard (see package metrics). On by default.
+type MetricsHistoryConfig struct {
+	// Enabled controls whether the patrol runs.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to sample (e.g., "5m").
+	IntervalStr string `json:"interval,omitempty"`
+
+	// RetentionStr is how long history is kept (e.g., "2160h" for 90 days).
+	// Whole months are pruned once they are older than this.
+	RetentionStr string `json:"retention,omitempty"`
+}
+
+// metricsHistoryInterval returns the configured interval, or the default (5m).
+func metricsHistoryInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.MetricsHistory != nil {
+		if config.Patrols.MetricsHistory.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.MetricsHistory.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultMetricsHistoryInterval
+}
+
+// metricsHistoryRetention returns the configured retention, or the default (90 days).
+func metricsHistoryRetention(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.MetricsHistory != nil {
+		if config.Patrols.MetricsHistory.RetentionStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.MetricsHistory.RetentionStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultMetricsHistoryRetention
+}
+
+// timePatrol runs a patrol and keeps its duration for the next metrics
+// sample. Only called from the main loop goroutine - no sync needed.
+func (d *Daemon) timePatrol(name string, patrol func()) {
+	start := time.Now()
+	patrol()
+	if d.patrolDurations == nil {
+		d.patrolDurations = make(map[string]time.Duration)
+	}
+	d.patrolDurations[name] = time.Since(start)
+}
+
+// recordMetrics appends one sample of the town's metrics to the history
+// and prunes history past retention. Metrics that can't be measured this
+// time are left out of the sample rather than recorded as zero.
+func (d *Daemon) recordMetrics() {
+	now := time.Now()
+	values := make(map[string]float64)
+
+	if sessions, err := d.tmux.ListSessions(); err != nil {
+		d.logger.Printf("metrics_history: listing sessions: %v", err)
+	} else {
+		values[metrics.SessionsAlive] = float64(countAgentSessions(sessions))
+	}
+
+	if depth, err := d.mergeQueueDepth(); err != nil {
+		d.logger.Printf("metrics_history: merge queue depth: %v", err)
+	} else {
+		values[metrics.QueueDepth] = float64(depth)
+	}
+
+	since := d.metricsLastSample
+	if since.IsZero() {
+		since = now.Add(-metricsHistoryInterval(d.patrolConfig))
+	}
+	if cost, err := ledgerSpend(costLedgerPath(), since, now); err != nil {
+		d.logger.Printf("metrics_history: reading cost ledger: %v", err)
+	} else {
+		values[metrics.CostUSD] = cost
+	}
+
+	for name, dur := range d.patrolDurations {
+		values[metrics.PatrolSecondsPrefix+name] = dur.Seconds()
+	}
+	d.patrolDurations = nil
+
+	store := metrics.Open(d.config.TownRoot)
+	if err := store.Append(metrics.Sample{Time: now, Values: values}); err != nil {
+		d.logger.Printf("metrics_history: appending sample: %v", err)
+		return
+	}
+	d.metricsLastSample = now
+
+	if removed, err := store.Prune(now.Add(-metricsHistoryRetention(d.patrolConfig))); err != nil {
+		d.logger.Printf("metrics_history: pruning: %v", err)
+	} else if removed > 0 {
+		d.logger.Printf("metrics_history: pruned %d month(s) of history", removed)
+	}
+}
+
+// countAgentSessions counts the sessions that belong to Gas Town agents.
+func countAgentSessions(sessions []string) int {
+	n := 0
+	for _, name := range sessions {
+		if _, err := session.ParseSessionName(name); err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// mergeQueueDepth counts open merge requests across the town's databases.
+func (d *Daemon) mergeQueueDepth() (int, error) {
+	total := 0
+	var firstErr error
+	for _, dbName := range d.discoverDoltDatabases() {
+		n, err := d.openMergeRequests(dbName)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", dbName, err)
+			}
+			continue
+		}
+		total += n
+	}
+	if firstErr != nil && total == 0 {
+		return 0, firstErr
+	}
+	return total, nil
+}
+
+func (d *Daemon) openMergeRequests(dbName string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsQueryTimeout)
+	defer cancel()
+
+	dsn := fmt.Sprintf("root@tcp(%s:%d)/%s?parseTime=true&timeout=5s&readTimeout=10s",
+		"127.0.0.1", d.doltServerPort(), dbName)
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return 0, fmt.Errorf("open connection: %w", err)
+	}
+	defer db.Close()
+
+	var n int
+	err = db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM wisps WHERE issue_type = 'merge-request' AND status != 'closed'").Scan(&n)
+	return n, err
+}
+
+// costLedgerPath returns the cost ledger written by gt costs record.
+func costLedgerPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".gt", "costs.jsonl")
+}
+
+// ledgerSpend sums the cost ledger entries for sessions that ended in
+// (since, until]. A missing ledger means no spend.
+func ledgerSpend(path string, since, until time.Time) (float64, error) {
+	if path == "" {
+		return 0, nil
+	}
+	f, err := os.Open(path) //nolint:gosec // G304: path is the fixed cost ledger location
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+
+	var total float64
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry struct {
+			CostUSD float64   `json:"cost_usd"`
+			EndedAt time.Time `json:"ended_at"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.EndedAt.After(since) && !entry.EndedAt.After(until) {
+			total += entry.CostUSD
+		}
+	}
+	return total, scanner.Err()
+}
@@ -0,0 +1,38 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLedgerSpend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "costs.jsonl")
+	ledger := `{"session_id":"a","cost_usd":1.5,"ended_at":"2026-10-01T10:00:00Z"}
+{"session_id":"b","cost_usd":2,"ended_at":"2026-10-01T10:05:00Z"}
+not json
+{"session_id":"c","cost_usd":4,"ended_at":"2026-10-01T11:00:00Z"}
+`
+	if err := os.WriteFile(path, []byte(ledger), 0644); err != nil {
+		t.Fatal(err)
+	}
+	since := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	got, err := ledgerSpend(path, since, since.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("ledgerSpend: %v", err)
+	}
+	if got != 2 {
+		t.Errorf("ledgerSpend() = %v, want 2 (only b ended in the window)", got)
+	}
+	if got, err := ledgerSpend(filepath.Join(t.TempDir(), "missing.jsonl"), since, since.Add(time.Hour)); err != nil || got != 0 {
+		t.Errorf("ledgerSpend(missing) = %v, %v; want 0, nil", got, err)
+	}
+}
+
+func TestCountAgentSessions(t *testing.T) {
+	got := countAgentSessions([]string{"hq-mayor", "hq-deacon", "my-scratch-session"})
+	if got != 2 {
+		t.Errorf("countAgentSessions() = %d, want 2", got)
+	}
+}
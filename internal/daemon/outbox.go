@@ -0,0 +1,145 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/maintenance"
+	"github.com/steveyegge/gastown/internal/outbox"
+)
+
+const (
+	defaultOutboxInterval = 30 * time.Second
+
+	// outboxReplayTimeout bounds one replayed command; gt done pushes and
+	// retries bead writes, so it is generous.
+	outboxReplayTimeout = 5 * time.Minute
+)
+
+// OutboxConfig holds configuration for the outbox patrol.
+//
+// The patrol replays gt commands that agents queued while the beads
+// database was unreachable (see package outbox), oldest first, once it is
+// reachable again. Each runs with the queueing agent's environment. An
+// entry that fails outbox.MaxAttempts times is moved aside and reported in
+// the feed. Paused, like other patrols, during quiet hours and maintenance
+// windows, including gt panic freezes. On by default: with nothing queued
+// each tick is a directory read.
+type OutboxConfig struct {
+	// Enabled controls whether the patrol runs.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to check the outbox (e.g., "30s").
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// outboxInterval returns the configured interval, or the default (30s).
+func outboxInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.Outbox != nil {
+		if config.Patrols.Outbox.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.Outbox.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultOutboxInterval
+}
+
+// replayOutbox replays queued commands while the beads database is
+// reachable. It stops as soon as the database drops again or a maintenance
+// window opens, leaving the rest queued in order.
+func (d *Daemon) replayOutbox() {
+	townRoot := d.config.TownRoot
+	pending, err := outbox.List(townRoot)
+	if err != nil {
+		d.logger.Printf("outbox: %v", err)
+		return
+	}
+	for _, queued := range pending {
+		if d.isShutdownInProgress() {
+			return
+		}
+		if d.outboxPaused() {
+			return
+		}
+		if err := outbox.Reachable(townRoot); err != nil {
+			return
+		}
+		e, err := outbox.Claim(townRoot, queued.ID)
+		if err != nil {
+			continue // dropped or claimed by someone else
+		}
+
+		runErr := d.runOutboxEntry(e)
+		payload := map[string]interface{}{"id": e.ID, "command": e.Command(), "actor": e.Actor}
+		if runErr == nil {
+			d.logger.Printf("outbox: replayed %s (%s)", e.ID, e.Command())
+			_ = events.LogAudit(events.TypeOutboxReplayed, "daemon", payload)
+			continue
+		}
+
+		if d.outboxPaused() {
+			// Refused by a freeze that began meanwhile; not the command's fault.
+			d.logger.Printf("outbox: %s (%s) refused during maintenance, keeping it queued", e.ID, e.Command())
+			if _, err := outbox.Add(townRoot, e); err != nil {
+				d.logger.Printf("outbox: requeueing %s: %v", e.ID, err)
+			}
+			return
+		}
+		e.Attempts++
+		e.LastAttempt = time.Now()
+		e.LastError = runErr.Error()
+		if e.Attempts >= outbox.MaxAttempts {
+			d.logger.Printf("outbox: giving up on %s (%s) after %d attempts: %v", e.ID, e.Command(), e.Attempts, runErr)
+			if err := outbox.Fail(townRoot, e); err != nil {
+				d.logger.Printf("outbox: setting %s aside: %v", e.ID, err)
+			}
+			payload["error"] = e.LastError
+			_ = events.LogFeed(events.TypeOutboxFailed, "daemon", payload)
+			continue
+		}
+		d.logger.Printf("outbox: %s (%s) failed (attempt %d), will retry: %v", e.ID, e.Command(), e.Attempts, runErr)
+		if _, err := outbox.Add(townRoot, e); err != nil {
+			d.logger.Printf("outbox: requeueing %s: %v", e.ID, err)
+		}
+	}
+}
+
+// outboxPaused reports whether a maintenance window is open. A gt panic
+// freeze is one, and it refuses gt done; replays wait the window out
+// instead of spending attempts on commands that would be refused.
+func (d *Daemon) outboxPaused() bool {
+	return maintenance.Check(d.config.TownRoot, nil, time.Now()).Active
+}
+
+// runOutboxEntry runs a queued command as the agent that queued it. If its
+// directory is gone (e.g., the worktree was removed), it runs from the town
+// root and relies on the agent's GT_* variables.
+func (d *Daemon) runOutboxEntry(e outbox.Entry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), outboxReplayTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, e.Args...) //nolint:gosec // G204: args are from the town's own outbox
+	cmd.Dir = d.config.TownRoot
+	if e.Dir != "" {
+		if info, err := os.Stat(e.Dir); err == nil && info.IsDir() {
+			cmd.Dir = e.Dir
+		}
+	}
+	cmd.Env = outbox.ReplayEnviron(os.Environ(), e)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w (%s)", err, lastLine(string(output)))
+	}
+	return nil
+}
+
+// lastLine returns the last non-empty line of output, which for gt is
+// usually the error.
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,112 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/maintenance"
+	"github.com/steveyegge/gastown/internal/outbox"
+)
+
+func TestReplayOutbox(t *testing.T) {
+	townRoot := t.TempDir()
+	logPath := filepath.Join(townRoot, "replayed.log")
+	gtPath := filepath.Join(t.TempDir(), "gt")
+	script := `#!/bin/sh
+echo "$* role=$GT_ROLE replay=$GT_OUTBOX_REPLAY dir=$(pwd)" >> "` + logPath + `"
+[ "$1" = "done" ] || { echo "Error: escalation not found" >&2; exit 1; }
+`
+	if err := os.WriteFile(gtPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	workDir := t.TempDir()
+
+	doneID, err := outbox.Add(townRoot, outbox.Entry{Args: []string{"done", "--status", "COMPLETED"}, Dir: workDir, Env: []string{"GT_ROLE=polecat"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ackID, err := outbox.Add(townRoot, outbox.Entry{Args: []string{"escalate", "ack", "hq-x"}, Dir: filepath.Join(townRoot, "gone")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := testHandlerDaemon(t, townRoot)
+	d.gtPath = gtPath
+	d.replayOutbox()
+
+	data, _ := os.ReadFile(logPath)
+	resolvedWork, _ := filepath.EvalSymlinks(workDir)
+	if !strings.Contains(string(data), "done --status COMPLETED role=polecat replay=1 dir="+resolvedWork) {
+		t.Errorf("done not replayed as the agent in its dir:\n%s", data)
+	}
+	pending, _ := outbox.List(townRoot)
+	if len(pending) != 1 || pending[0].ID != ackID || pending[0].Attempts != 1 || !strings.Contains(pending[0].LastError, "escalation not found") {
+		t.Fatalf("after first replay, pending = %+v; want only %s with 1 attempt (done was %s)", pending, ackID, doneID)
+	}
+
+	for i := 1; i < outbox.MaxAttempts; i++ {
+		d.replayOutbox()
+	}
+	if pending, _ := outbox.List(townRoot); len(pending) != 0 {
+		t.Errorf("entry still pending after %d attempts: %+v", outbox.MaxAttempts, pending)
+	}
+	if failed, _ := outbox.ListFailed(townRoot); len(failed) != 1 || failed[0].ID != ackID {
+		t.Errorf("failed = %+v, want %s", failed, ackID)
+	}
+}
+
+func TestReplayOutboxWaitsOutFreeze(t *testing.T) {
+	townRoot := t.TempDir()
+	logPath := filepath.Join(townRoot, "replayed.log")
+	gtPath := filepath.Join(t.TempDir(), "gt")
+	// Like a real gt done under freeze: refused, with the town frozen.
+	script := `#!/bin/sh
+echo "$*" >> "` + logPath + `"
+echo "Error: town is frozen by gt panic" >&2
+exit 1
+`
+	if err := os.WriteFile(gtPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	id, err := outbox.Add(townRoot, outbox.Entry{Args: []string{"done"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := maintenance.Freeze(townRoot, "incident", "overseer", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	d := testHandlerDaemon(t, townRoot)
+	d.gtPath = gtPath
+	for i := 0; i <= outbox.MaxAttempts; i++ {
+		d.replayOutbox()
+	}
+	if _, err := os.Stat(logPath); err == nil {
+		t.Error("replayed a command while the town was frozen")
+	}
+
+	// A freeze that begins while a command runs refuses it; that must not
+	// count as an attempt either.
+	if _, err := maintenance.End(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	script = `#!/bin/sh
+echo '{"frozen": true, "started_at": "2026-01-01T00:00:00Z"}' > "` + maintenance.StatePath(townRoot) + `"
+echo "Error: town is frozen by gt panic" >&2
+exit 1
+`
+	if err := os.WriteFile(gtPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	d.replayOutbox()
+
+	pending, _ := outbox.List(townRoot)
+	if len(pending) != 1 || pending[0].ID != id || pending[0].Attempts != 0 {
+		t.Errorf("pending = %+v, want %s queued with no attempts", pending, id)
+	}
+	if failed, _ := outbox.ListFailed(townRoot); len(failed) != 0 {
+		t.Errorf("entries failed during a freeze: %+v", failed)
+	}
+}
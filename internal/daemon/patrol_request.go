@@ -0,0 +1,280 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// On-demand patrol runs (gt daemon run-patrol). The CLI drops a request
+// file into daemon/patrol-requests/; the daemon's main loop picks it up
+// within patrolRequestInterval and runs the patrol there, so it never
+// overlaps a scheduled run and the same gates apply (enabled in
+// mayor/daemon.json, quiet hours, shutdown). The patrol's log lines are
+// copied to <id>.log and the outcome written to <id>.result.json.
+
+const (
+	// patrolRequestInterval is how often the daemon looks for requests.
+	patrolRequestInterval = 2 * time.Second
+
+	// patrolRequestRetention is how long request logs and results are kept.
+	patrolRequestRetention = 24 * time.Hour
+)
+
+// OnDemandPatrols lists the patrols gt daemon run-patrol can trigger.
+var OnDemandPatrols = []string{
+	"heartbeat",
+	"dolt_health",
+	"dolt_remotes",
+	"dolt_backup",
+	"jsonl_git_backup",
+	"wisp_reaper",
+	"doctor_dog",
+	"db_maintenance",
+	"janitor_dog",
+	"change_feed",
+	"scheduled_nudges",
+	"provider_pressure",
+	"staleness",
+	"worktree_pool",
+	"config_drift",
+	"metrics_history",
+	"agent_signals",
+	"outbox",
+}
+
+// Patrol request outcomes.
+const (
+	PatrolRan     = "ran"
+	PatrolSkipped = "skipped"
+)
+
+// PatrolRequest asks the running daemon to run a patrol now.
+type PatrolRequest struct {
+	ID          string    `json:"id"`
+	Patrol      string    `json:"patrol"`
+	RequestedBy string    `json:"requested_by,omitempty"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// PatrolResult is the outcome of a patrol request.
+type PatrolResult struct {
+	ID         string    `json:"id"`
+	Patrol     string    `json:"patrol"`
+	Status     string    `json:"status"`           // PatrolRan or PatrolSkipped
+	Reason     string    `json:"reason,omitempty"` // why it was skipped
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// PatrolRequestDir returns the directory patrol requests are exchanged in.
+func PatrolRequestDir(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "patrol-requests")
+}
+
+// PatrolRequestLogPath returns where a request's patrol log is copied.
+func PatrolRequestLogPath(townRoot, id string) string {
+	return filepath.Join(PatrolRequestDir(townRoot), id+".log")
+}
+
+func patrolRequestPath(townRoot, id string) string {
+	return filepath.Join(PatrolRequestDir(townRoot), id+".json")
+}
+
+func patrolResultPath(townRoot, id string) string {
+	return filepath.Join(PatrolRequestDir(townRoot), id+".result.json")
+}
+
+// ValidatePatrol reports whether name can be run on demand.
+func ValidatePatrol(name string) error {
+	for _, p := range OnDemandPatrols {
+		if p == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown patrol %q (want one of %s)", name, strings.Join(OnDemandPatrols, ", "))
+}
+
+// RequestPatrol queues a run of patrol for the town's running daemon.
+func RequestPatrol(townRoot, patrol, requestedBy string) (*PatrolRequest, error) {
+	if err := ValidatePatrol(patrol); err != nil {
+		return nil, err
+	}
+	running, _, err := IsRunning(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("checking daemon status: %w", err)
+	}
+	if !running {
+		return nil, fmt.Errorf("daemon is not running (start it with 'gt daemon start')")
+	}
+
+	now := time.Now()
+	req := &PatrolRequest{
+		ID:          fmt.Sprintf("%s-%s-%d", now.UTC().Format("20060102-150405.000"), patrol, os.Getpid()),
+		Patrol:      patrol,
+		RequestedBy: requestedBy,
+		RequestedAt: now,
+	}
+	if err := os.MkdirAll(PatrolRequestDir(townRoot), 0755); err != nil {
+		return nil, err
+	}
+	if err := util.AtomicWriteJSON(patrolRequestPath(townRoot, req.ID), req); err != nil {
+		return nil, fmt.Errorf("writing patrol request: %w", err)
+	}
+	return req, nil
+}
+
+// PatrolRequestPending reports whether the daemon has yet to pick up a
+// request.
+func PatrolRequestPending(townRoot, id string) bool {
+	_, err := os.Stat(patrolRequestPath(townRoot, id))
+	return err == nil
+}
+
+// ReadPatrolResult returns a request's outcome, or nil while it hasn't
+// finished.
+func ReadPatrolResult(townRoot, id string) (*PatrolResult, error) {
+	data, err := os.ReadFile(patrolResultPath(townRoot, id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var res PatrolResult
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("parsing patrol result: %w", err)
+	}
+	return &res, nil
+}
+
+// onDemandPatrol is how the main loop runs one patrol.
+type onDemandPatrol struct {
+	enabled func() bool // nil = always
+	quiet   bool        // skipped during quiet hours, as on its ticker
+	run     func()
+}
+
+// onDemandPatrols maps OnDemandPatrols to the calls their tickers make.
+func (d *Daemon) onDemandPatrols(state *State) map[string]onDemandPatrol {
+	configured := func(name string) func() bool {
+		return func() bool { return IsPatrolEnabled(d.patrolConfig, name) }
+	}
+	timed := func(name string, f func()) func() {
+		return func() { d.timePatrol(name, f) }
+	}
+	return map[string]onDemandPatrol{
+		"heartbeat": {run: timed("heartbeat", func() { d.heartbeat(state) })},
+		"dolt_health": {
+			enabled: func() bool { return d.doltServer != nil && d.doltServer.IsEnabled() },
+			run:     d.ensureDoltServerRunning,
+		},
+		"dolt_remotes":      {enabled: configured("dolt_remotes"), quiet: true, run: d.pushDoltRemotes},
+		"dolt_backup":       {enabled: configured("dolt_backup"), quiet: true, run: d.syncDoltBackups},
+		"jsonl_git_backup":  {enabled: configured("jsonl_git_backup"), quiet: true, run: d.syncJsonlGitBackup},
+		"wisp_reaper":       {enabled: configured("wisp_reaper"), quiet: true, run: timed("wisp_reaper", d.reapWisps)},
+		"doctor_dog":        {enabled: configured("doctor_dog"), quiet: true, run: timed("doctor_dog", d.runDoctorDog)},
+		"db_maintenance":    {enabled: configured("db_maintenance"), quiet: true, run: timed("db_maintenance", d.runDBMaintenance)},
+		"janitor_dog":       {enabled: configured("janitor_dog"), quiet: true, run: d.runJanitorDog},
+		"change_feed":       {enabled: configured("change_feed"), run: d.tailChangeFeed},
+		"scheduled_nudges":  {enabled: configured("scheduled_nudges"), quiet: true, run: d.deliverScheduledNudges},
+		"provider_pressure": {enabled: configured("provider_pressure"), run: d.measureProviderPressure},
+		"staleness":         {enabled: configured("staleness"), quiet: true, run: d.checkStaleness},
+		"worktree_pool":     {enabled: configured("worktree_pool"), quiet: true, run: d.maintainWorktreePools},
+		"config_drift":      {enabled: configured("config_drift"), run: d.checkConfigDrift},
+		"metrics_history":   {enabled: configured("metrics_history"), run: d.recordMetrics},
+		"agent_signals":     {enabled: configured("agent_signals"), run: d.scanAgentSignals},
+		"outbox":            {enabled: configured("outbox"), quiet: true, run: d.replayOutbox},
+	}
+}
+
+// runRequestedPatrols runs the queued patrol requests, oldest first, and
+// prunes old request logs and results.
+func (d *Daemon) runRequestedPatrols(state *State) {
+	dir := PatrolRequestDir(d.config.TownRoot)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var ids []string
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case strings.HasSuffix(name, ".result.json") || strings.HasSuffix(name, ".log"):
+			if info, err := e.Info(); err == nil && time.Since(info.ModTime()) > patrolRequestRetention {
+				_ = os.Remove(filepath.Join(dir, name))
+			}
+		case strings.HasSuffix(name, ".json"):
+			ids = append(ids, strings.TrimSuffix(name, ".json"))
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	sort.Strings(ids)
+	patrols := d.onDemandPatrols(state)
+	for _, id := range ids {
+		d.runRequestedPatrol(id, patrols)
+	}
+}
+
+// runRequestedPatrol runs one request, copying the daemon's log output to
+// the request's log while the patrol runs.
+func (d *Daemon) runRequestedPatrol(id string, patrols map[string]onDemandPatrol) {
+	townRoot := d.config.TownRoot
+	path := patrolRequestPath(townRoot, id)
+	data, err := os.ReadFile(path)
+	_ = os.Remove(path)
+	if err != nil {
+		return
+	}
+	var req PatrolRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		d.logger.Printf("run-patrol: ignoring malformed request %s: %v", id, err)
+		return
+	}
+	res := PatrolResult{ID: id, Patrol: req.Patrol, Status: PatrolSkipped, StartedAt: time.Now()}
+
+	logFile, err := os.OpenFile(PatrolRequestLogPath(townRoot, id), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		d.logger.Printf("run-patrol: opening log for %s: %v", id, err)
+	} else {
+		defer logFile.Close()
+		orig := d.logger.Writer()
+		d.logger.SetOutput(io.MultiWriter(orig, logFile))
+		defer d.logger.SetOutput(orig)
+	}
+
+	p, known := patrols[req.Patrol]
+	switch {
+	case !known:
+		res.Reason = fmt.Sprintf("unknown patrol %q", req.Patrol)
+	case d.isShutdownInProgress():
+		res.Reason = "daemon is shutting down"
+	case p.enabled != nil && !p.enabled():
+		res.Reason = "not enabled in mayor/daemon.json"
+	case p.quiet && d.quietSkips(req.Patrol):
+		res.Reason = "quiet hours"
+	default:
+		d.logger.Printf("run-patrol: running %s (requested by %s)", req.Patrol, req.RequestedBy)
+		p.run()
+		res.Status = PatrolRan
+		d.logger.Printf("run-patrol: %s finished in %v", req.Patrol, time.Since(res.StartedAt).Round(time.Millisecond))
+	}
+	if res.Status == PatrolSkipped {
+		d.logger.Printf("run-patrol: skipped %s: %s", req.Patrol, res.Reason)
+	}
+
+	res.FinishedAt = time.Now()
+	if err := util.AtomicWriteJSON(patrolResultPath(townRoot, id), res); err != nil {
+		d.logger.Printf("run-patrol: writing result for %s: %v", id, err)
+	}
+}
@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+func TestOnDemandPatrolsCoversList(t *testing.T) {
+	d := &Daemon{config: &Config{}}
+	var names []string
+	for name := range d.onDemandPatrols(nil) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := append([]string(nil), OnDemandPatrols...)
+	sort.Strings(want)
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("onDemandPatrols = %v, want %v", names, want)
+	}
+	if err := ValidatePatrol("nope"); err == nil {
+		t.Error("ValidatePatrol accepted an unknown patrol")
+	}
+}
+
+func TestRunRequestedPatrol(t *testing.T) {
+	town := t.TempDir()
+	d := &Daemon{config: &Config{TownRoot: town}, logger: log.New(io.Discard, "", 0)}
+	queue := func(id, patrol string) {
+		t.Helper()
+		if err := os.MkdirAll(PatrolRequestDir(town), 0755); err != nil {
+			t.Fatal(err)
+		}
+		req := PatrolRequest{ID: id, Patrol: patrol, RequestedBy: "test", RequestedAt: time.Now()}
+		if err := util.AtomicWriteJSON(patrolRequestPath(town, id), req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queue("1-probe", "probe")
+	patrols := map[string]onDemandPatrol{
+		"probe": {run: func() { d.logger.Printf("probe: checked 3 rigs") }},
+		"off":   {enabled: func() bool { return false }, run: func() { t.Error("disabled patrol ran") }},
+	}
+	d.runRequestedPatrol("1-probe", patrols)
+
+	if PatrolRequestPending(town, "1-probe") {
+		t.Error("request still pending after the run")
+	}
+	res, err := ReadPatrolResult(town, "1-probe")
+	if err != nil || res == nil || res.Status != PatrolRan {
+		t.Fatalf("result = %+v, %v; want ran", res, err)
+	}
+	logData, err := os.ReadFile(PatrolRequestLogPath(town, "1-probe"))
+	if err != nil || !strings.Contains(string(logData), "probe: checked 3 rigs") {
+		t.Errorf("request log = %q, %v; want the patrol's log lines", logData, err)
+	}
+	d.logger.Printf("after the run")
+	if data, _ := os.ReadFile(PatrolRequestLogPath(town, "1-probe")); strings.Contains(string(data), "after the run") {
+		t.Error("daemon log still copied to the request log after the run")
+	}
+
+	queue("2-off", "off")
+	d.runRequestedPatrol("2-off", patrols)
+	if res, _ := ReadPatrolResult(town, "2-off"); res == nil || res.Status != PatrolSkipped || !strings.Contains(res.Reason, "not enabled") {
+		t.Errorf("disabled patrol result = %+v, want skipped as not enabled", res)
+	}
+}
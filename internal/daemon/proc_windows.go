@@ -5,6 +5,8 @@ package daemon
 import (
 	"os"
 	"os/exec"
+
+	"github.com/steveyegge/gastown/internal/platform"
 )
 
 // setSysProcAttr sets platform-specific process attributes.
@@ -14,15 +16,8 @@ func setSysProcAttr(cmd *exec.Cmd) {
 }
 
 // isProcessAlive checks if a process is still running.
-// On Windows, we try to open the process with minimal access.
 func isProcessAlive(p *os.Process) bool {
-	// On Windows, FindProcess always succeeds, and Signal(0) may not work.
-	// The best we can do is try to signal and see if it fails.
-	// A killed process will return an error.
-	err := p.Signal(os.Signal(nil))
-	// If err is nil or "not supported", process may still be alive
-	// If err mentions "finished" or similar, process is dead
-	return err == nil
+	return platform.ProcessExists(p.Pid)
 }
 
 // sendTermSignal sends a termination signal.
@@ -0,0 +1,98 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/quota"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+const defaultProviderPressureInterval = 2 * time.Minute
+
+// ProviderPressureConfig holds configuration for the provider_pressure patrol.
+//
+// The patrol scans agent sessions for rate-limit signals (limit messages
+// and 429 retries in pane output, and rate-limit API errors in Claude Code
+// transcripts) and records per-provider pressure, which the capacity
+// scheduler and gt broadcast use to throttle. On by default.
+type ProviderPressureConfig struct {
+	// Enabled controls whether the patrol runs.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to scan (e.g., "2m").
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// providerPressureInterval returns the configured interval, or the default (2m).
+func providerPressureInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.ProviderPressure != nil {
+		if config.Patrols.ProviderPressure.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.ProviderPressure.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultProviderPressureInterval
+}
+
+// measureProviderPressure scans sessions and saves per-provider pressure.
+// A provider entering or leaving throttling is logged as a feed event.
+func (d *Daemon) measureProviderPressure() {
+	townRoot := d.config.TownRoot
+	accounts, _ := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+	t := tmux.NewTmux()
+	scanner, err := quota.NewScanner(t, nil, accounts)
+	if err != nil {
+		d.logger.Printf("provider_pressure: %v", err)
+		return
+	}
+	results, err := scanner.ScanAll()
+	if err != nil {
+		d.logger.Printf("provider_pressure: %v", err)
+		return
+	}
+
+	now := time.Now()
+	throttle := d.throttleConfig()
+	quota.MarkTranscriptRetries(results, t.GetPaneWorkDir, now.Add(-throttle.GetMaxAge()))
+	resolver := &quota.ProviderResolver{TownRoot: townRoot}
+	providers := quota.AssessPressure(results, func(sess string) string {
+		return resolver.SessionProvider(t, sess)
+	}, now)
+
+	prev, err := capacity.LoadPressure(townRoot)
+	if err != nil {
+		prev = &capacity.PressureState{}
+	}
+	if err := capacity.SavePressure(townRoot, &capacity.PressureState{UpdatedAt: now, Providers: providers}); err != nil {
+		d.logger.Printf("provider_pressure: saving: %v", err)
+		return
+	}
+
+	for name, p := range providers {
+		level := throttle.Level(p, now)
+		// Compare against the previous reading as of its own time, so a
+		// reading that aged out doesn't count as a change.
+		was := throttle.Level(prev.Providers[name], prev.UpdatedAt)
+		if level == was {
+			continue
+		}
+		d.logger.Printf("provider_pressure: %s %s -> %s (%d/%d limited, %d retrying)",
+			name, was, level, p.Limited, p.Sessions, p.Retrying)
+		_ = events.LogFeed(events.TypeProviderThrottle, "daemon",
+			events.ProviderThrottlePayload(name, level.String(), p.Sessions, p.Limited, p.Retrying))
+	}
+}
+
+// throttleConfig returns the town's scheduler throttle settings.
+func (d *Daemon) throttleConfig() *capacity.ThrottleConfig {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	if err != nil || settings.Scheduler == nil {
+		return nil
+	}
+	return settings.Scheduler.Throttle
+}
@@ -0,0 +1,36 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/maintenance"
+)
+
+// backupPatrols keep running during quiet hours unless quiet_hours
+// pause_backups is set. Maintenance windows pause them too.
+var backupPatrols = map[string]bool{
+	"dolt_backup":      true,
+	"dolt_remotes":     true,
+	"jsonl_git_backup": true,
+}
+
+// quietStatus reports whether the town is in quiet hours or a
+// gt maintenance window.
+func (d *Daemon) quietStatus() maintenance.Status {
+	var cfg *maintenance.QuietHoursConfig
+	if d.patrolConfig != nil {
+		cfg = d.patrolConfig.QuietHours
+	}
+	return maintenance.Check(d.config.TownRoot, cfg, time.Now())
+}
+
+// quietSkips reports whether the current quiet window pauses patrol,
+// logging the skip when it does.
+func (d *Daemon) quietSkips(patrol string) bool {
+	qs := d.quietStatus()
+	if !qs.Active || (backupPatrols[patrol] && !qs.PauseBackups) {
+		return false
+	}
+	d.logger.Printf("In %s, skipping %s patrol", qs, patrol)
+	return true
+}
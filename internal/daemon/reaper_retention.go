@@ -0,0 +1,283 @@
+package daemon
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Events of closed issues older than this are deleted.
+	defaultEventRetention = 90 * 24 * time.Hour
+	// Acked (closed) mail older than this is deleted.
+	defaultMailRetention = 30 * 24 * time.Hour
+
+	// retentionOff disables purging a data type.
+	retentionOff = "off"
+
+	// mailLabel marks mail beads (see internal/mail).
+	mailLabel = "gt:message"
+)
+
+// RetentionPolicy is the wisp_reaper's delete retention per data type.
+// A zero duration keeps that data type forever.
+type RetentionPolicy struct {
+	Wisps  time.Duration // closed wisps
+	Events time.Duration // events of closed issues
+	Mail   time.Duration // acked mail
+}
+
+// ReaperRetention returns the wisp_reaper's retention policy, applying
+// defaults for unset or invalid values. Events are kept when the
+// jsonl_git_backup patrol archives them.
+func ReaperRetention(config *DaemonPatrolConfig) RetentionPolicy {
+	var c *WispReaperConfig
+	if config != nil && config.Patrols != nil {
+		c = config.Patrols.WispReaper
+	}
+	policy := RetentionPolicy{
+		Wisps:  wispDeleteAge(config),
+		Events: defaultEventRetention,
+		Mail:   defaultMailRetention,
+	}
+	if c != nil {
+		policy.Events = parseRetention(c.EventRetentionStr, defaultEventRetention)
+		policy.Mail = parseRetention(c.MailRetentionStr, defaultMailRetention)
+	}
+	// Archived events leave the live table through the archive instead.
+	if EventArchivalEnabled(config) {
+		policy.Events = 0
+	}
+	return policy
+}
+
+// parseRetention parses a retention setting: a duration, or "off" (0).
+func parseRetention(s string, def time.Duration) time.Duration {
+	if s == retentionOff {
+		return 0
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d
+	}
+	return def
+}
+
+// ReapCounts is what the wisp_reaper would delete (or did delete) in one
+// database.
+type ReapCounts struct {
+	Database   string `json:"database"`
+	StaleWisps int    `json:"stale_wisps"` // open wisps to close
+	Wisps      int    `json:"wisps"`       // closed wisps to delete
+	Events     int    `json:"events"`      // events of closed issues to delete
+	Mail       int    `json:"mail"`        // acked mail to delete
+}
+
+// Total returns the number of rows affected.
+func (c ReapCounts) Total() int {
+	return c.StaleWisps + c.Wisps + c.Events + c.Mail
+}
+
+// Where clauses shared by the dry-run counts and the purges, taking the
+// database name for %[1]s and the cutoff as the query argument.
+const (
+	staleWispsWhere = "FROM `%[1]s`.wisps WHERE status IN ('open', 'hooked', 'in_progress') AND created_at < ?"
+	closedWispWhere = "FROM `%[1]s`.wisps WHERE status = 'closed' AND closed_at < ?"
+	oldEventsWhere  = "FROM `%[1]s`.events WHERE created_at < ?" +
+		" AND issue_id IN (SELECT id FROM `%[1]s`.issues WHERE status = 'closed')"
+	ackedMailWhere = "FROM `%[1]s`.issues WHERE status = 'closed' AND closed_at < ?" +
+		" AND id IN (SELECT issue_id FROM `%[1]s`.labels WHERE label = '" + mailLabel + "')"
+)
+
+// PlanReap counts, without changing anything, the rows a wisp_reaper cycle
+// would close or delete in each database. Stale-issue auto-close is not
+// included. A data type with zero retention counts as 0.
+func PlanReap(host string, port int, databases []string, maxAge time.Duration, policy RetentionPolicy, now time.Time) ([]ReapCounts, error) {
+	var plan []ReapCounts
+	for _, dbName := range databases {
+		if !validDBName.MatchString(dbName) {
+			return plan, fmt.Errorf("invalid database name: %q", dbName)
+		}
+		counts, err := planReapInDB(host, port, dbName, maxAge, policy, now)
+		if err != nil {
+			return plan, fmt.Errorf("%s: %w", dbName, err)
+		}
+		plan = append(plan, counts)
+	}
+	return plan, nil
+}
+
+func planReapInDB(host string, port int, dbName string, maxAge time.Duration, policy RetentionPolicy, now time.Time) (ReapCounts, error) {
+	counts := ReapCounts{Database: dbName}
+	ctx, cancel := context.WithTimeout(context.Background(), wispReaperQueryTimeout)
+	defer cancel()
+
+	dsn := fmt.Sprintf("root@tcp(%s:%d)/%s?parseTime=true&timeout=5s&readTimeout=10s", host, port, dbName)
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return counts, fmt.Errorf("open connection: %w", err)
+	}
+	defer db.Close()
+
+	for _, q := range []struct {
+		n     *int
+		where string
+		age   time.Duration
+	}{
+		{&counts.StaleWisps, staleWispsWhere, maxAge},
+		{&counts.Wisps, closedWispWhere, policy.Wisps},
+		{&counts.Events, oldEventsWhere, policy.Events},
+		{&counts.Mail, ackedMailWhere, policy.Mail},
+	} {
+		if q.age <= 0 {
+			continue
+		}
+		query := "SELECT COUNT(*) " + fmt.Sprintf(q.where, dbName)
+		if err := db.QueryRowContext(ctx, query, now.Add(-q.age)).Scan(q.n); err != nil && !isTableMissing(err) {
+			return counts, err
+		}
+	}
+	return counts, nil
+}
+
+// isTableMissing reports whether a query failed because a table doesn't
+// exist; not every database has the wisp or mail tables.
+func isTableMissing(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "table not found") || strings.Contains(err.Error(), "doesn't exist"))
+}
+
+// purgeOldEventsInDB deletes events of closed issues created before the
+// cutoff, in batches. Events of open issues are kept: they're the history
+// agents read. Returns the number of events deleted.
+func (d *Daemon) purgeOldEventsInDB(dbName string, cutoff time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	dsn := fmt.Sprintf("root@tcp(%s:%d)/%s?parseTime=true&timeout=5s&readTimeout=30s&writeTimeout=30s",
+		"127.0.0.1", d.doltServerPort(), dbName)
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return 0, fmt.Errorf("open connection: %w", err)
+	}
+	defer db.Close()
+
+	totalDeleted := 0
+	idQuery := fmt.Sprintf("SELECT id "+oldEventsWhere+" LIMIT %d", dbName, deleteBatchSize)
+	for {
+		ids, err := queryIDs(ctx, db, idQuery, cutoff)
+		if err != nil {
+			if isTableMissing(err) {
+				return 0, nil
+			}
+			return totalDeleted, fmt.Errorf("select batch: %w", err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		inClause, args := inList(ids)
+		result, err := db.ExecContext(ctx, fmt.Sprintf("DELETE FROM `%s`.events WHERE id IN %s", dbName, inClause), args...)
+		if err != nil {
+			return totalDeleted, fmt.Errorf("delete events batch: %w", err)
+		}
+		affected, _ := result.RowsAffected()
+		totalDeleted += int(affected)
+	}
+
+	if totalDeleted > 0 {
+		d.logger.Printf("wisp_reaper: %s: deleted %d events of closed issues (before %v)",
+			dbName, totalDeleted, cutoff.Format(time.RFC3339))
+	}
+	return totalDeleted, nil
+}
+
+// purgeAckedMailInDB deletes acked (closed) mail closed before the cutoff,
+// with its labels, comments, events and thread dependencies, in batches.
+// Returns the number of messages deleted.
+func (d *Daemon) purgeAckedMailInDB(dbName string, cutoff time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	dsn := fmt.Sprintf("root@tcp(%s:%d)/%s?parseTime=true&timeout=5s&readTimeout=30s&writeTimeout=30s",
+		"127.0.0.1", d.doltServerPort(), dbName)
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return 0, fmt.Errorf("open connection: %w", err)
+	}
+	defer db.Close()
+
+	totalDeleted := 0
+	idQuery := fmt.Sprintf("SELECT id "+ackedMailWhere+" LIMIT %d", dbName, deleteBatchSize)
+	for {
+		ids, err := queryIDs(ctx, db, idQuery, cutoff)
+		if err != nil {
+			if isTableMissing(err) {
+				return 0, nil
+			}
+			return totalDeleted, fmt.Errorf("select batch: %w", err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		inClause, args := inList(ids)
+
+		// Delete from auxiliary tables first (foreign key safety).
+		for _, del := range []string{
+			"DELETE FROM `%s`.labels WHERE issue_id IN %s",
+			"DELETE FROM `%s`.comments WHERE issue_id IN %s",
+			"DELETE FROM `%s`.events WHERE issue_id IN %s",
+			"DELETE FROM `%s`.dependencies WHERE issue_id IN %s",
+		} {
+			if _, err := db.ExecContext(ctx, fmt.Sprintf(del, dbName, inClause), args...); err != nil {
+				d.logger.Printf("wisp_reaper: %s: %s: %v", dbName, fmt.Sprintf(del, dbName, "(...)"), err)
+			}
+		}
+		// Replies point at the messages they answer.
+		depQuery := fmt.Sprintf("DELETE FROM `%s`.dependencies WHERE depends_on_id IN %s", dbName, inClause)
+		if _, err := db.ExecContext(ctx, depQuery, args...); err != nil {
+			d.logger.Printf("wisp_reaper: %s: delete reply dependencies: %v", dbName, err)
+		}
+
+		result, err := db.ExecContext(ctx, fmt.Sprintf("DELETE FROM `%s`.issues WHERE id IN %s", dbName, inClause), args...)
+		if err != nil {
+			return totalDeleted, fmt.Errorf("delete mail batch: %w", err)
+		}
+		affected, _ := result.RowsAffected()
+		totalDeleted += int(affected)
+	}
+
+	if totalDeleted > 0 {
+		d.logger.Printf("wisp_reaper: %s: deleted %d acked messages (closed before %v)",
+			dbName, totalDeleted, cutoff.Format(time.RFC3339))
+	}
+	return totalDeleted, nil
+}
+
+// queryIDs runs a single-column id query.
+func queryIDs(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// inList returns an IN clause of placeholders for ids, and its arguments.
+func inList(ids []string) (string, []interface{}) {
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return "(" + joinStrings(placeholders, ",") + ")", args
+}
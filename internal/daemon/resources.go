@@ -0,0 +1,32 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/lock"
+)
+
+// resourceWait is how long a patrol waits for a shared resource before
+// skipping this run; it tries again on its next tick.
+const resourceWait = 5 * time.Minute
+
+// acquireResource takes a slot of the named shared resource (see
+// lock.NewResource) for patrol, so e.g. the backup export and db
+// maintenance never hit Dolt together, even from other processes. It
+// returns false, logging why, if the resource stayed busy; otherwise the
+// caller must call release. Take dolt before git-remote when holding both.
+func (d *Daemon) acquireResource(resource, patrol string) (release func(), ok bool) {
+	ctx := d.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, resourceWait)
+	defer cancel()
+	release, err := lock.NewResource(d.config.TownRoot, resource).Acquire(ctx, "daemon/"+patrol)
+	if err != nil {
+		d.logger.Printf("%s: skipping this run: %v", patrol, err)
+		return nil, false
+	}
+	return release, true
+}
@@ -0,0 +1,84 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/nudge"
+)
+
+const (
+	// scheduledNudgeInterval is how often due scheduled nudges are
+	// delivered. It bounds how late a nudge scheduled with --at can arrive.
+	scheduledNudgeInterval = 30 * time.Second
+	scheduledNudgeTimeout  = 60 * time.Second
+)
+
+// deliverScheduledNudges delivers scheduled nudges (gt nudge --at/--in)
+// that are due. Each goes through `gt nudge` so mode, priority, and DND
+// are handled exactly as for a direct nudge. Failed deliveries are retried
+// up to nudge.MaxScheduleAttempts times.
+func (d *Daemon) deliverScheduledNudges() {
+	now := time.Now()
+	due, err := nudge.ClaimDue(d.config.TownRoot, now)
+	if err != nil {
+		d.logger.Printf("scheduled_nudges: %v", err)
+		return
+	}
+	for _, s := range due {
+		if late := now.Sub(s.DeliverAt); late > nudge.MaxScheduleLateness {
+			nudge.ReleaseClaimed(d.config.TownRoot, s.ID)
+			d.logger.Printf("scheduled_nudges: dropping %s for %s: %s overdue", s.ID, s.Target, late.Round(time.Minute))
+			continue
+		}
+		err := d.runScheduledNudge(s)
+		nudge.ReleaseClaimed(d.config.TownRoot, s.ID)
+		if err == nil {
+			d.logger.Printf("scheduled_nudges: delivered %s to %s", s.ID, s.Target)
+			continue
+		}
+		s.Attempts++
+		if s.Attempts >= nudge.MaxScheduleAttempts {
+			d.logger.Printf("scheduled_nudges: giving up on %s for %s after %d attempts: %v", s.ID, s.Target, s.Attempts, err)
+			continue
+		}
+		d.logger.Printf("scheduled_nudges: %s for %s failed (attempt %d), retrying: %v", s.ID, s.Target, s.Attempts, err)
+		s.DeliverAt = now.Add(nudge.ScheduleRetryDelay)
+		if _, err := nudge.Schedule(d.config.TownRoot, s); err != nil {
+			d.logger.Printf("scheduled_nudges: rescheduling %s: %v", s.ID, err)
+		}
+	}
+}
+
+// scheduledNudgeArgs returns the gt arguments that deliver s. The sender is
+// not among them: gt nudge reads it from the claimed entry named by
+// nudge.ScheduleIDEnv, so nobody can pass a sender on the command line.
+func scheduledNudgeArgs(s nudge.ScheduledNudge) []string {
+	args := []string{"nudge", s.Target, "--message=" + s.Message}
+	if s.Mode != "" {
+		args = append(args, "--mode", s.Mode)
+	}
+	if s.Priority != "" {
+		args = append(args, "--priority", s.Priority)
+	}
+	if s.Force {
+		args = append(args, "--force")
+	}
+	return args
+}
+
+func (d *Daemon) runScheduledNudge(s nudge.ScheduledNudge) error {
+	ctx, cancel := context.WithTimeout(context.Background(), scheduledNudgeTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, scheduledNudgeArgs(s)...) //nolint:gosec // G204: args are from the town's own schedule
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = append(os.Environ(), nudge.ScheduleIDEnv+"="+s.ID)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w (%s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/nudge"
+)
+
+func TestScheduledNudgeArgs(t *testing.T) {
+	got := scheduledNudgeArgs(nudge.ScheduledNudge{
+		Target:   "gastown/Toast",
+		Sender:   "mayor",
+		Message:  "-wrap up",
+		Mode:     "wait-idle",
+		Priority: nudge.PriorityUrgent,
+		Force:    true,
+	})
+	want := []string{"nudge", "gastown/Toast", "--message=-wrap up", "--mode", "wait-idle", "--priority", "urgent", "--force"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scheduledNudgeArgs = %q, want %q", got, want)
+	}
+
+	minimal := scheduledNudgeArgs(nudge.ScheduledNudge{Target: "mayor", Message: "hi"})
+	if want := []string{"nudge", "mayor", "--message=hi"}; !reflect.DeepEqual(minimal, want) {
+		t.Errorf("scheduledNudgeArgs = %q, want %q", minimal, want)
+	}
+}
+
+func TestDeliverScheduledNudgesPassesScheduleID(t *testing.T) {
+	townRoot := t.TempDir()
+	logPath := filepath.Join(townRoot, "delivered.log")
+	gtPath := filepath.Join(t.TempDir(), "gt")
+	// The fake gt reads the sender back from the claimed entry, as gt nudge does.
+	script := `#!/bin/sh
+cat "` + townRoot + `/.runtime/nudge_schedule/$GT_NUDGE_SCHEDULE_ID.json.claimed" >> "` + logPath + `"
+`
+	if err := os.WriteFile(gtPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	id, err := nudge.Schedule(townRoot, nudge.ScheduledNudge{Target: "mayor", Sender: "gastown/Toast", Message: "hi", DeliverAt: time.Now().Add(-time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := testHandlerDaemon(t, townRoot)
+	d.gtPath = gtPath
+	d.deliverScheduledNudges()
+
+	if data, _ := os.ReadFile(logPath); !strings.Contains(string(data), `"sender": "gastown/Toast"`) {
+		t.Errorf("claimed entry not readable during delivery:\n%s", data)
+	}
+	if _, err := nudge.ReadClaimed(townRoot, id); err != nudge.ErrScheduledNotFound {
+		t.Errorf("claimed entry not released after delivery: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/failure"
+)
+
+const (
+	// failureLookback is how recent a failure record must be to explain a
+	// dead session the heartbeat just found.
+	failureLookback = 10 * time.Minute
+
+	// rateLimitRestartDelay is how long a session that died on a rate limit
+	// stays down before it is restarted.
+	rateLimitRestartDelay = 5 * time.Minute
+)
+
+// collectDeadPane handles a session whose pane died but was kept by
+// remain-on-exit because the pane-died hook didn't run (e.g., gt not on the
+// tmux server's PATH): it classifies and records the failure the way the
+// hook would, and kills the session. Returns false if the pane is alive.
+func (d *Daemon) collectDeadPane(sessionName, agent string) bool {
+	rec, ok := failure.CaptureDead(d.tmux, sessionName)
+	if !ok {
+		return false
+	}
+	rec.Agent = agent
+	rec.Issue, _ = d.tmux.GetEnvironment(sessionName, "GT_ISSUE")
+	if err := failure.Append(d.config.TownRoot, rec); err != nil {
+		d.logger.Printf("Warning: recording failure of %s: %v", sessionName, err)
+	}
+	_ = events.LogFeed(events.TypeSessionFailure, "daemon",
+		events.SessionFailurePayload(sessionName, agent, rec.Issue, string(rec.Class), string(rec.Recovery), rec.Evidence))
+	_ = d.tmux.KillSession(sessionName)
+	return true
+}
+
+// sessionFailure returns the recorded cause of a session's recent death and
+// the recovery to apply. Without a record (the session was killed, or died
+// before classification existed) the recovery is a plain retry.
+func (d *Daemon) sessionFailure(sessionName string) (*failure.Record, failure.Recovery) {
+	now := time.Now()
+	records, err := failure.Read(d.config.TownRoot, now.Add(-failure.EscalateWindow))
+	if err != nil {
+		return nil, failure.RecoverRetry
+	}
+	rec := failure.Latest(records, sessionName, now.Add(-failureLookback))
+	if rec == nil {
+		return nil, failure.RecoverRetry
+	}
+	return rec, failure.Decide(records, *rec)
+}
+
+// holdFailedSession records that a session is being held down for rec and
+// reports whether this is the first time (so the witness is told once).
+func (d *Daemon) holdFailedSession(sessionName string, rec *failure.Record) bool {
+	if d.heldFailures == nil {
+		d.heldFailures = make(map[string]time.Time)
+	}
+	if d.heldFailures[sessionName].Equal(rec.Time) {
+		return false
+	}
+	d.heldFailures[sessionName] = rec.Time
+	return true
+}
+
+// notifyWitnessOfHeldPolecat tells the witness a crashed polecat was not
+// restarted because of how it failed.
+func (d *Daemon) notifyWitnessOfHeldPolecat(rigName, polecatName, hookBead string, rec *failure.Record, recovery failure.Recovery) {
+	witnessAddr := rigName + "/witness"
+	subject := fmt.Sprintf("CRASHED_POLECAT: %s/%s held (%s)", rigName, polecatName, rec.Class)
+	action := "Fix the environment (install the agent binary, log in), then restart with: gt session restart " + rigName + "/" + polecatName
+	if recovery == failure.RecoverEscalate {
+		action = fmt.Sprintf("It failed the same way %d+ times within %s. Investigate before restarting.",
+			failure.EscalateAfter, failure.EscalateWindow)
+	}
+	body := fmt.Sprintf(`Polecat %s died and was NOT restarted.
+
+hook_bead: %s
+failure: %s
+recovery: %s
+
+%s
+If nothing changes, the daemon retries in %s.
+%s
+See: gt failures`,
+		polecatName, hookBead, rec.Reason(), recovery, action, failureLookback, crashOutput(rec))
+
+	cmd := exec.Command(d.gtPath, "mail", "send", witnessAddr, "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ()
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: failed to notify witness of held polecat: %v", err)
+	}
+}
+
+// crashOutput renders the last output captured from a dead session's pane
+// for a notification, or "" if none was captured.
+func crashOutput(rec *failure.Record) string {
+	if rec == nil || len(rec.Tail) == 0 {
+		return ""
+	}
+	return "\nLast output before it died:\n\n    " + strings.Join(rec.Tail, "\n    ") + "\n"
+}
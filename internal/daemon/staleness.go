@@ -0,0 +1,265 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+const (
+	defaultStalenessInterval = 15 * time.Minute
+	stalenessActionTimeout   = 60 * time.Second
+)
+
+// StalenessConfig holds configuration for the staleness patrol.
+//
+// The patrol evaluates each rig's staleness policies (the "staleness"
+// section of <rig>/settings/config.json) against the rig's open issues and
+// nudges, reassigns, escalates, or labels the ones that have gone stale.
+type StalenessConfig struct {
+	// Enabled controls whether the staleness patrol runs.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to evaluate policies (e.g., "15m").
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// stalenessInterval returns the configured interval, or the default (15m).
+func stalenessInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.Staleness != nil {
+		if config.Patrols.Staleness.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.Staleness.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultStalenessInterval
+}
+
+// stalenessState records when each policy last acted on each issue, keyed
+// "rig/issue/policy", so an action repeats at most once per policy age.
+type stalenessState struct {
+	Actions map[string]time.Time `json:"actions"`
+}
+
+func stalenessStateFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "staleness.json")
+}
+
+func loadStalenessState(townRoot string) *stalenessState {
+	state := &stalenessState{}
+	if data, err := os.ReadFile(stalenessStateFile(townRoot)); err == nil {
+		_ = json.Unmarshal(data, state)
+	}
+	if state.Actions == nil {
+		state.Actions = make(map[string]time.Time)
+	}
+	return state
+}
+
+// staleMatch is one issue a policy should act on.
+type staleMatch struct {
+	Issue  *beads.Issue
+	Policy config.StalenessPolicy
+	Idle   time.Duration // time since the issue was last updated
+}
+
+// lastCommitFunc returns the time of the newest commit in an assignee's
+// worktree, or false if the assignee has no worktree.
+type lastCommitFunc func(assignee string) (time.Time, bool)
+
+// matchStaleIssues returns the (issue, policy) pairs that are stale at now.
+// Invalid policies are skipped; the caller validates and logs them.
+func matchStaleIssues(cfg *config.StalenessConfig, issues []*beads.Issue, now time.Time, lastCommit lastCommitFunc) []staleMatch {
+	exempt := cfg.ExemptLabel
+	if exempt == "" {
+		exempt = config.DefaultStaleExemptLabel
+	}
+
+	var out []staleMatch
+	for _, issue := range issues {
+		if beads.HasLabel(issue, exempt) {
+			continue
+		}
+		updated, err := time.Parse(time.RFC3339, issue.UpdatedAt)
+		if err != nil {
+			continue
+		}
+		idle := now.Sub(updated)
+		for _, p := range cfg.Policies {
+			if p.Validate() != nil {
+				continue
+			}
+			age, _ := p.Age()
+			if issue.Status != p.Status || idle < age {
+				continue
+			}
+			if p.Type != "" && issue.Type != p.Type {
+				continue
+			}
+			if p.Label != "" && !beads.HasLabel(issue, p.Label) {
+				continue
+			}
+			if p.Blocker && issue.DependentCount == 0 {
+				continue
+			}
+			if p.NoCommits {
+				at, ok := lastCommit(issue.Assignee)
+				if !ok || now.Sub(at) < age {
+					continue
+				}
+			}
+			out = append(out, staleMatch{Issue: issue, Policy: p, Idle: idle})
+		}
+	}
+	return out
+}
+
+// checkStaleness evaluates every rig's staleness policies and acts on the
+// matching issues. Non-fatal: errors are logged and retried next tick.
+func (d *Daemon) checkStaleness() {
+	if !IsPatrolEnabled(d.patrolConfig, "staleness") {
+		return
+	}
+
+	state := loadStalenessState(d.config.TownRoot)
+	now := time.Now()
+	for _, rigName := range d.getPatrolRigs("staleness") {
+		rigPath := filepath.Join(d.config.TownRoot, rigName)
+		settings, err := config.LoadRigSettings(filepath.Join(rigPath, "settings", "config.json"))
+		if err != nil || settings.Staleness == nil || len(settings.Staleness.Policies) == 0 {
+			continue
+		}
+		for _, p := range settings.Staleness.Policies {
+			if err := p.Validate(); err != nil {
+				d.logger.Printf("staleness: %s: skipping %v", rigName, err)
+			}
+		}
+
+		bd := beads.NewWithBeadsDir(rigPath, beads.ResolveBeadsDir(rigPath))
+		issues, err := bd.List(beads.ListOptions{Status: "all", Priority: -1})
+		if err != nil {
+			d.logger.Printf("staleness: %s: listing issues: %v", rigName, err)
+			continue
+		}
+
+		lastCommit := func(assignee string) (time.Time, bool) {
+			return d.worktreeLastCommit(rigName, assignee)
+		}
+		for _, m := range matchStaleIssues(settings.Staleness, issues, now, lastCommit) {
+			key := rigName + "/" + m.Issue.ID + "/" + m.Policy.Name
+			age, _ := m.Policy.Age()
+			if last, ok := state.Actions[key]; ok && now.Sub(last) < age {
+				continue
+			}
+			if err := d.runStaleAction(rigName, m); err != nil {
+				d.logger.Printf("staleness: %s: %s (%s): %v", rigName, m.Issue.ID, m.Policy.Name, err)
+				continue
+			}
+			state.Actions[key] = now
+			d.logger.Printf("staleness: %s: %s %s (policy %s, idle %s)",
+				rigName, m.Policy.Action, m.Issue.ID, m.Policy.Name, m.Idle.Round(time.Minute))
+			_ = events.LogAudit(events.TypeStalenessAction, "daemon",
+				events.StalenessPayload(rigName, m.Issue.ID, m.Policy.Name, m.Policy.Action, m.Issue.Assignee))
+		}
+	}
+
+	// Forget issues no policy has acted on for a week; they were either
+	// resolved or their policies changed.
+	for key, at := range state.Actions {
+		if now.Sub(at) > 7*24*time.Hour {
+			delete(state.Actions, key)
+		}
+	}
+	if err := util.EnsureDirAndWriteJSON(stalenessStateFile(d.config.TownRoot), state); err != nil {
+		d.logger.Printf("staleness: saving state: %v", err)
+	}
+}
+
+// runStaleAction carries out a policy's action on an issue via gt and bd,
+// so it goes through the same paths an operator would use.
+func (d *Daemon) runStaleAction(rigName string, m staleMatch) error {
+	issue, p := m.Issue, m.Policy
+	idle := m.Idle.Round(time.Minute)
+	var args []string
+	name := d.gtPath
+	switch p.Action {
+	case config.StaleActionNudge:
+		if issue.Assignee == "" {
+			return fmt.Errorf("no assignee to nudge")
+		}
+		args = []string{"nudge", issue.Assignee,
+			fmt.Sprintf("%s has had no progress for %s (staleness policy %q). Push your work, or say what is blocking you.", issue.ID, idle, p.Name)}
+	case config.StaleActionReassign:
+		args = []string{"sling", issue.ID, rigName, "--force"}
+	case config.StaleActionEscalate:
+		args = []string{"escalate", "-s", "medium", "--source", "patrol:staleness", "--related", issue.ID,
+			fmt.Sprintf("%s: %s %q has been %s for %s (staleness policy %q)", rigName, issue.ID, issue.Title, issue.Status, idle, p.Name)}
+	case config.StaleActionLabel:
+		label := p.ActionLabel
+		if label == "" {
+			label = config.DefaultStaleActionLabel
+		}
+		if beads.HasLabel(issue, label) {
+			return nil
+		}
+		name = "bd"
+		args = []string{"update", issue.ID, "--add-label", label}
+	default:
+		return fmt.Errorf("unknown action %q", p.Action)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), stalenessActionTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = filepath.Join(d.config.TownRoot, rigName)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s %s: %w (%s)", filepath.Base(name), args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// worktreeLastCommit returns the time of the newest commit in the worktree
+// of an assignee such as "greenplace/polecats/Toast" or "greenplace/crew/max".
+func (d *Daemon) worktreeLastCommit(rigName, assignee string) (time.Time, bool) {
+	dir := assigneeWorktree(d.config.TownRoot, rigName, assignee)
+	if dir == "" {
+		return time.Time{}, false
+	}
+	out, err := exec.Command("git", "-C", dir, "log", "-1", "--format=%ct").Output() //nolint:gosec // G204: path is constructed internally
+	if err != nil {
+		return time.Time{}, false
+	}
+	secs, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(secs, 0), true
+}
+
+// assigneeWorktree maps an assignee address to its worktree directory, or ""
+// if the assignee is not a polecat or crew member of rigName.
+func assigneeWorktree(townRoot, rigName, assignee string) string {
+	parts := strings.Split(assignee, "/")
+	if len(parts) != 3 || parts[0] != rigName || parts[2] == "" {
+		return ""
+	}
+	switch parts[1] {
+	case "polecats":
+		return filepath.Join(townRoot, rigName, "polecats", parts[2], rigName)
+	case "crew":
+		return filepath.Join(townRoot, rigName, "crew", parts[2])
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package daemon
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestStalenessInterval(t *testing.T) {
+	if got := stalenessInterval(nil); got != defaultStalenessInterval {
+		t.Errorf("expected default interval %v, got %v", defaultStalenessInterval, got)
+	}
+
+	cfg := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			Staleness: &StalenessConfig{Enabled: true, IntervalStr: "5m"},
+		},
+	}
+	if got := stalenessInterval(cfg); got != 5*time.Minute {
+		t.Errorf("expected 5m interval, got %v", got)
+	}
+	if !IsPatrolEnabled(cfg, "staleness") {
+		t.Error("staleness should be enabled")
+	}
+	if IsPatrolEnabled(nil, "staleness") {
+		t.Error("staleness should be opt-in")
+	}
+}
+
+func TestMatchStaleIssues(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) string { return now.Add(-d).Format(time.RFC3339) }
+
+	cfg := &config.StalenessConfig{
+		Policies: []config.StalenessPolicy{
+			{Name: "stuck", Status: "in_progress", OlderThan: "24h", NoCommits: true, Action: config.StaleActionNudge},
+			{Name: "blocker", Status: "open", Blocker: true, OlderThan: "3d", Action: config.StaleActionEscalate},
+			{Name: "bugs", Status: "open", Type: "bug", Label: "customer", OlderThan: "1d", Action: config.StaleActionLabel},
+			{Name: "broken", Status: "open", OlderThan: "soon", Action: config.StaleActionLabel},
+		},
+	}
+	issues := []*beads.Issue{
+		// stuck: idle 2 days, no commits for 2 days
+		{ID: "gt-1", Status: "in_progress", UpdatedAt: ago(48 * time.Hour), Assignee: "gastown/polecats/Toast"},
+		// not stuck: committed an hour ago
+		{ID: "gt-2", Status: "in_progress", UpdatedAt: ago(48 * time.Hour), Assignee: "gastown/polecats/Nux"},
+		// not stuck: no worktree to check
+		{ID: "gt-3", Status: "in_progress", UpdatedAt: ago(48 * time.Hour)},
+		// old blocker
+		{ID: "gt-4", Status: "open", UpdatedAt: ago(4 * 24 * time.Hour), DependentCount: 2},
+		// old, but blocks nothing
+		{ID: "gt-5", Status: "open", UpdatedAt: ago(4 * 24 * time.Hour)},
+		// blocker, but exempt
+		{ID: "gt-6", Status: "open", UpdatedAt: ago(4 * 24 * time.Hour), DependentCount: 1, Labels: []string{config.DefaultStaleExemptLabel}},
+		// customer bug
+		{ID: "gt-7", Status: "open", Type: "bug", UpdatedAt: ago(30 * time.Hour), Labels: []string{"customer"}},
+		// customer task: wrong type
+		{ID: "gt-8", Status: "open", Type: "task", UpdatedAt: ago(30 * time.Hour), Labels: []string{"customer"}},
+		// recently updated blocker
+		{ID: "gt-9", Status: "open", UpdatedAt: ago(time.Hour), DependentCount: 3},
+	}
+	lastCommit := func(assignee string) (time.Time, bool) {
+		switch assignee {
+		case "gastown/polecats/Toast":
+			return now.Add(-48 * time.Hour), true
+		case "gastown/polecats/Nux":
+			return now.Add(-time.Hour), true
+		}
+		return time.Time{}, false
+	}
+
+	got := matchStaleIssues(cfg, issues, now, lastCommit)
+	want := map[string]string{"gt-1": "stuck", "gt-4": "blocker", "gt-7": "bugs"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d matches, want %d: %+v", len(got), len(want), got)
+	}
+	for _, m := range got {
+		if want[m.Issue.ID] != m.Policy.Name {
+			t.Errorf("unexpected match %s by policy %s", m.Issue.ID, m.Policy.Name)
+		}
+	}
+}
+
+func TestMatchStaleIssuesCustomExemptLabel(t *testing.T) {
+	now := time.Now()
+	cfg := &config.StalenessConfig{
+		ExemptLabel: "keep",
+		Policies:    []config.StalenessPolicy{{Name: "old", Status: "open", OlderThan: "1h", Action: config.StaleActionLabel}},
+	}
+	issues := []*beads.Issue{
+		{ID: "gt-1", Status: "open", UpdatedAt: now.Add(-2 * time.Hour).Format(time.RFC3339), Labels: []string{"keep"}},
+		{ID: "gt-2", Status: "open", UpdatedAt: now.Add(-2 * time.Hour).Format(time.RFC3339), Labels: []string{config.DefaultStaleExemptLabel}},
+	}
+	got := matchStaleIssues(cfg, issues, now, nil)
+	if len(got) != 1 || got[0].Issue.ID != "gt-2" {
+		t.Errorf("expected only gt-2 to match, got %+v", got)
+	}
+}
+
+func TestAssigneeWorktree(t *testing.T) {
+	town := "/town"
+	tests := []struct {
+		assignee string
+		want     string
+	}{
+		{"gastown/polecats/Toast", filepath.Join(town, "gastown", "polecats", "Toast", "gastown")},
+		{"gastown/crew/max", filepath.Join(town, "gastown", "crew", "max")},
+		{"beads/polecats/Toast", ""},
+		{"gastown/witness", ""},
+		{"mayor", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := assigneeWorktree(town, "gastown", tt.assignee); got != tt.want {
+			t.Errorf("assigneeWorktree(%q) = %q, want %q", tt.assignee, got, tt.want)
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/maintenance"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -31,6 +32,10 @@ type Config struct {
 
 	// PidFile is the path to the PID file.
 	PidFile string `json:"pid_file"`
+
+	// DryRun lists patrols that log intended actions instead of acting
+	// (see DryRunPatrols). Set by gt daemon start --dry-run.
+	DryRun []string `json:"dry_run,omitempty"`
 }
 
 // DefaultConfig returns the default daemon configuration.
@@ -114,18 +119,28 @@ type PatrolConfig struct {
 
 // PatrolsConfig holds configuration for all patrols.
 type PatrolsConfig struct {
-	Refinery       *PatrolConfig          `json:"refinery,omitempty"`
-	Witness        *PatrolConfig          `json:"witness,omitempty"`
-	Deacon         *PatrolConfig          `json:"deacon,omitempty"`
-	Handler        *PatrolConfig          `json:"handler,omitempty"`
-	DoltServer     *DoltServerConfig      `json:"dolt_server,omitempty"`
-	DoltTestServer *DoltServerConfig      `json:"dolt_test_server,omitempty"`
-	DoltRemotes    *DoltRemotesConfig     `json:"dolt_remotes,omitempty"`
-	DoltBackup     *DoltBackupConfig      `json:"dolt_backup,omitempty"`
-	JsonlGitBackup *JsonlGitBackupConfig  `json:"jsonl_git_backup,omitempty"`
-	WispReaper     *WispReaperConfig      `json:"wisp_reaper,omitempty"`
-	DoctorDog      *DoctorDogConfig       `json:"doctor_dog,omitempty"`
-	JanitorDog     *JanitorDogConfig      `json:"janitor_dog,omitempty"`
+	Refinery         *PatrolConfig           `json:"refinery,omitempty"`
+	Witness          *PatrolConfig           `json:"witness,omitempty"`
+	Deacon           *PatrolConfig           `json:"deacon,omitempty"`
+	Handler          *PatrolConfig           `json:"handler,omitempty"`
+	DoltServer       *DoltServerConfig       `json:"dolt_server,omitempty"`
+	DoltTestServer   *DoltServerConfig       `json:"dolt_test_server,omitempty"`
+	DoltRemotes      *DoltRemotesConfig      `json:"dolt_remotes,omitempty"`
+	DoltBackup       *DoltBackupConfig       `json:"dolt_backup,omitempty"`
+	JsonlGitBackup   *JsonlGitBackupConfig   `json:"jsonl_git_backup,omitempty"`
+	WispReaper       *WispReaperConfig       `json:"wisp_reaper,omitempty"`
+	DoctorDog        *DoctorDogConfig        `json:"doctor_dog,omitempty"`
+	DBMaintenance    *DBMaintenanceConfig    `json:"db_maintenance,omitempty"`
+	JanitorDog       *JanitorDogConfig       `json:"janitor_dog,omitempty"`
+	DogPool          *DogPoolConfig          `json:"dog_pool,omitempty"`
+	ChangeFeed       *ChangeFeedConfig       `json:"change_feed,omitempty"`
+	Staleness        *StalenessConfig        `json:"staleness,omitempty"`
+	ProviderPressure *ProviderPressureConfig `json:"provider_pressure,omitempty"`
+	WorktreePool     *WorktreePoolConfig     `json:"worktree_pool,omitempty"`
+	ConfigDrift      *ConfigDriftConfig      `json:"config_drift,omitempty"`
+	MetricsHistory   *MetricsHistoryConfig   `json:"metrics_history,omitempty"`
+	AgentSignals     *AgentSignalsConfig     `json:"agent_signals,omitempty"`
+	Outbox           *OutboxConfig           `json:"outbox,omitempty"`
 }
 
 // DoltRemotesConfig holds configuration for the dolt_remotes patrol.
@@ -158,8 +173,43 @@ type DoltBackupConfig struct {
 	IntervalStr string `json:"interval,omitempty"`
 
 	// Databases lists specific database names to back up.
-	// If empty, auto-discovers databases with configured backup remotes.
+	// If empty, sync mode auto-discovers databases with configured backup
+	// remotes and snapshot mode backs up every database.
 	Databases []string `json:"databases,omitempty"`
+
+	// Mode selects how databases are backed up (default "sync"):
+	//   "sync"     - dolt backup sync to each database's <db>-backup
+	//   "snapshot" - commit and branch backup/<timestamp> per tick, pushed to
+	//                SnapshotRemote, for point-in-time restores
+	//                (gt dolt snapshots)
+	//   "both"     - both of the above
+	Mode string `json:"mode,omitempty"`
+
+	// SnapshotRemote is the Dolt remote snapshot branches are pushed to
+	// (e.g., a DoltHub or file:// remote added with dolt remote add).
+	// Empty keeps snapshots local.
+	SnapshotRemote string `json:"snapshot_remote,omitempty"`
+
+	// SnapshotRetention is how many snapshot branches to keep per database.
+	// Default: 96 (a day of 15-minute ticks).
+	SnapshotRetention int `json:"snapshot_retention,omitempty"`
+}
+
+// Dolt backup modes for DoltBackupConfig.Mode.
+const (
+	DoltBackupModeSync     = "sync"
+	DoltBackupModeSnapshot = "snapshot"
+	DoltBackupModeBoth     = "both"
+)
+
+// syncs reports whether the backup mode runs dolt backup sync.
+func (c *DoltBackupConfig) syncs() bool {
+	return c.Mode == "" || c.Mode == DoltBackupModeSync || c.Mode == DoltBackupModeBoth
+}
+
+// snapshots reports whether the backup mode takes snapshot branches.
+func (c *DoltBackupConfig) snapshots() bool {
+	return c.Mode == DoltBackupModeSnapshot || c.Mode == DoltBackupModeBoth
 }
 
 // JsonlGitBackupConfig holds configuration for the jsonl_git_backup patrol.
@@ -187,18 +237,74 @@ type JsonlGitBackupConfig struct {
 	// between consecutive exports. If the delta exceeds this threshold (in either
 	// direction), the export is halted and escalated. Default: 0.20 (20%).
 	SpikeThreshold *float64 `json:"spike_threshold,omitempty"`
+
+	// TownState controls whether the town's non-issue state (rig configs,
+	// daemon config, roles, hooks, plugins) is snapshotted into the repo's
+	// town-state/ directory with each backup. Restore with gt town restore.
+	// Default: true
+	TownState *bool `json:"town_state,omitempty"`
+
+	// EventArchiveAgeStr enables event archival: events older than this
+	// (e.g., "720h") move from each database's events table into
+	// compressed monthly files under <db>/events-archive/ in the repo, and
+	// are deleted from the live table once committed. gt db events reads
+	// the merged history. Empty disables archival.
+	EventArchiveAgeStr string `json:"event_archive_age,omitempty"`
+
+	// ExportWorkers is how many supplemental tables of a database are
+	// exported at once. Default: 3. Set to 1 for sequential exports.
+	ExportWorkers int `json:"export_workers,omitempty"`
+
+	// TableTimeouts overrides the per-table export timeout (default 60s) for
+	// named supplemental tables, e.g. {"events": "30s"}. A table that times
+	// out keeps its previous export and doesn't hold up the others.
+	TableTimeouts map[string]string `json:"table_timeouts,omitempty"`
+
+	// DatabaseTables adjusts the supplemental tables exported for named
+	// databases, e.g. for rigs whose beads extensions add tables. Databases
+	// not listed export the default set. Configured tables are checked
+	// against the database's information_schema; missing ones are skipped.
+	DatabaseTables map[string]*JsonlTableSelection `json:"database_tables,omitempty"`
+}
+
+// JsonlTableSelection chooses the supplemental tables exported for one
+// database. The issues table is always exported and can't be selected here.
+type JsonlTableSelection struct {
+	// Include replaces the default supplemental table list when non-empty.
+	Include []string `json:"include,omitempty"`
+
+	// Exclude drops tables from the list (e.g., a huge events table).
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Extra adds tables to the list, for non-standard databases.
+	Extra []string `json:"extra,omitempty"`
 }
 
 // DaemonPatrolConfig is the structure of mayor/daemon.json.
 type DaemonPatrolConfig struct {
-	Type      string            `json:"type"`
-	Version   int               `json:"version"`
-	Heartbeat *PatrolConfig     `json:"heartbeat,omitempty"`
-	Patrols   *PatrolsConfig    `json:"patrols,omitempty"`
+	Type      string         `json:"type"`
+	Version   int            `json:"version"`
+	Heartbeat *PatrolConfig  `json:"heartbeat,omitempty"`
+	Patrols   *PatrolsConfig `json:"patrols,omitempty"`
 	// Env holds environment variables to set at startup.
 	// Propagated to all sessions spawned by the daemon and read by gt up/mayor attach.
 	// Example: {"GT_DOLT_PORT": "43211"}
-	Env       map[string]string `json:"env,omitempty"`
+	Env map[string]string `json:"env,omitempty"`
+	// QuietHours are recurring windows during which the daemon doesn't
+	// restart agents, send nudges, or run heavy patrols.
+	// Example: {"windows": [{"start": "22:00", "end": "06:00"}]}
+	QuietHours *maintenance.QuietHoursConfig `json:"quiet_hours,omitempty"`
+	// DryRun lists patrols that log intended actions instead of acting
+	// (see DryRunPatrols), e.g. ["zombie_recovery", "idle_reaper"].
+	DryRun []string `json:"dry_run,omitempty"`
+	// AdaptiveHeartbeat bounds the heartbeat interval, which shortens while
+	// sessions are busy and lengthens while the town is idle.
+	// Example: {"min": "1m", "max": "10m"}
+	AdaptiveHeartbeat *AdaptiveHeartbeatConfig `json:"adaptive_heartbeat,omitempty"`
+	// ResourceLimits caps how many patrols, supervisors and gt commands use
+	// a shared resource at once (see lock.DefaultResourceLimits).
+	// Example: {"dolt": 1, "git-remote": 2, "tmux-server": 4}
+	ResourceLimits map[string]int `json:"resource_limits,omitempty"`
 }
 
 // PatrolConfigFile returns the path to the patrol config file.
@@ -261,12 +367,42 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		}
 		return config.Patrols.DoctorDog.Enabled
 	}
+	if patrol == "db_maintenance" {
+		if config == nil || config.Patrols == nil || config.Patrols.DBMaintenance == nil {
+			return false
+		}
+		return config.Patrols.DBMaintenance.Enabled
+	}
 	if patrol == "janitor_dog" {
 		if config == nil || config.Patrols == nil || config.Patrols.JanitorDog == nil {
 			return false
 		}
 		return config.Patrols.JanitorDog.Enabled
 	}
+	if patrol == "change_feed" {
+		if config == nil || config.Patrols == nil || config.Patrols.ChangeFeed == nil {
+			return false
+		}
+		return config.Patrols.ChangeFeed.Enabled
+	}
+	if patrol == "staleness" {
+		if config == nil || config.Patrols == nil || config.Patrols.Staleness == nil {
+			return false
+		}
+		return config.Patrols.Staleness.Enabled
+	}
+	if patrol == "worktree_pool" {
+		if config == nil || config.Patrols == nil || config.Patrols.WorktreePool == nil {
+			return false
+		}
+		return config.Patrols.WorktreePool.Enabled
+	}
+	if patrol == "config_drift" {
+		if config == nil || config.Patrols == nil || config.Patrols.ConfigDrift == nil {
+			return false
+		}
+		return config.Patrols.ConfigDrift.Enabled
+	}
 
 	if config == nil || config.Patrols == nil {
 		return true // Default: enabled
@@ -289,6 +425,22 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		if config.Patrols.Handler != nil {
 			return config.Patrols.Handler.Enabled
 		}
+	case "provider_pressure":
+		if config.Patrols.ProviderPressure != nil {
+			return config.Patrols.ProviderPressure.Enabled
+		}
+	case "metrics_history":
+		if config.Patrols.MetricsHistory != nil {
+			return config.Patrols.MetricsHistory.Enabled
+		}
+	case "agent_signals":
+		if config.Patrols.AgentSignals != nil {
+			return config.Patrols.AgentSignals.Enabled
+		}
+	case "outbox":
+		if config.Patrols.Outbox != nil {
+			return config.Patrols.Outbox.Enabled
+		}
 	}
 	return true // Default: enabled
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/steveyegge/gastown/internal/lock"
 )
 
 const (
@@ -41,6 +42,18 @@ type WispReaperConfig struct {
 	// StaleIssueAgeStr is how long an issue can be unchanged before auto-close (e.g., "720h" for 30 days).
 	StaleIssueAgeStr string `json:"stale_issue_age,omitempty"`
 
+	// EventRetentionStr is how long events of closed issues are kept before
+	// deletion (e.g., "2160h" for 90 days, the default), or "off".
+	EventRetentionStr string `json:"event_retention,omitempty"`
+
+	// MailRetentionStr is how long acked (closed) mail is kept before
+	// deletion (e.g., "720h" for 30 days, the default), or "off".
+	MailRetentionStr string `json:"mail_retention,omitempty"`
+
+	// DryRun logs what each cycle would close and delete without changing
+	// anything. gt db retention prints the same report on demand.
+	DryRun bool `json:"dry_run,omitempty"`
+
 	// Databases lists specific database names to reap.
 	// If empty, auto-discovers from dolt server.
 	Databases []string `json:"databases,omitempty"`
@@ -58,8 +71,8 @@ func wispReaperInterval(config *DaemonPatrolConfig) time.Duration {
 	return defaultWispReaperInterval
 }
 
-// wispReaperMaxAge returns the configured max age, or the default (24h).
-func wispReaperMaxAge(config *DaemonPatrolConfig) time.Duration {
+// WispReaperMaxAge returns the configured max age, or the default (24h).
+func WispReaperMaxAge(config *DaemonPatrolConfig) time.Duration {
 	if config != nil && config.Patrols != nil && config.Patrols.WispReaper != nil {
 		if config.Patrols.WispReaper.MaxAgeStr != "" {
 			if d, err := time.ParseDuration(config.Patrols.WispReaper.MaxAgeStr); err == nil && d > 0 {
@@ -101,15 +114,30 @@ func (d *Daemon) reapWisps() {
 	if !IsPatrolEnabled(d.patrolConfig, "wisp_reaper") {
 		return
 	}
+	release, ok := d.acquireResource(lock.ResourceDolt, "wisp_reaper")
+	if !ok {
+		return
+	}
+	defer release()
 
 	config := d.patrolConfig.Patrols.WispReaper
-	maxAge := wispReaperMaxAge(d.patrolConfig)
+	maxAge := WispReaperMaxAge(d.patrolConfig)
 	deleteAge := wispDeleteAge(d.patrolConfig)
 
+	policy := ReaperRetention(d.patrolConfig)
+	retention := func(age time.Duration) string {
+		if age <= 0 {
+			return retentionOff
+		}
+		return age.String()
+	}
+
 	// Pour molecule to track this patrol cycle.
 	mol := d.pourDogMolecule("mol-dog-reaper", map[string]string{
-		"max_age":   maxAge.String(),
-		"purge_age": deleteAge.String(),
+		"max_age":         maxAge.String(),
+		"purge_age":       deleteAge.String(),
+		"event_retention": retention(policy.Events),
+		"mail_retention":  retention(policy.Mail),
 	})
 	defer mol.close()
 
@@ -133,6 +161,14 @@ func (d *Daemon) reapWisps() {
 	d.logger.Printf("wisp_reaper: scanning %d databases", len(databases))
 	mol.closeStep("scan")
 
+	if config.DryRun || d.isDryRun(DryRunWispReaper) {
+		d.logDryRunReap(databases, maxAge, policy)
+		mol.closeStep("reap")
+		mol.closeStep("purge")
+		mol.closeStep("report")
+		return
+	}
+
 	// --- REAP STEP: close stale wisps ---
 
 	totalReaped := 0
@@ -178,10 +214,13 @@ func (d *Daemon) reapWisps() {
 		mol.closeStep("reap")
 	}
 
-	// --- PURGE STEP: delete closed wisps older than purge age ---
+	// --- PURGE STEP: delete closed wisps, old events and acked mail past retention ---
 
 	totalPurged := 0
+	totalEvents := 0
+	totalMail := 0
 	purgeErrors := 0
+	now := time.Now().UTC()
 
 	for _, dbName := range databases {
 		if !validDBName.MatchString(dbName) {
@@ -195,6 +234,24 @@ func (d *Daemon) reapWisps() {
 		} else {
 			totalPurged += purged
 		}
+
+		if policy.Events > 0 {
+			events, err := d.purgeOldEventsInDB(dbName, now.Add(-policy.Events))
+			if err != nil {
+				d.logger.Printf("wisp_reaper: %s: event purge error: %v", dbName, err)
+				purgeErrors++
+			}
+			totalEvents += events
+		}
+
+		if policy.Mail > 0 {
+			mail, err := d.purgeAckedMailInDB(dbName, now.Add(-policy.Mail))
+			if err != nil {
+				d.logger.Printf("wisp_reaper: %s: mail purge error: %v", dbName, err)
+				purgeErrors++
+			}
+			totalMail += mail
+		}
 	}
 
 	if totalPurged > 0 {
@@ -215,12 +272,25 @@ func (d *Daemon) reapWisps() {
 			totalOpen, wispAlertThreshold)
 	}
 
-	d.logger.Printf("wisp_reaper: cycle complete — reaped=%d purged=%d open=%d databases=%d",
-		totalReaped, totalPurged, totalOpen, len(databases))
+	d.logger.Printf("wisp_reaper: cycle complete — reaped=%d purged=%d events=%d mail=%d open=%d databases=%d",
+		totalReaped, totalPurged, totalEvents, totalMail, totalOpen, len(databases))
 
 	mol.closeStep("report")
 }
 
+// logDryRunReap logs what a cycle would close and delete, per database.
+func (d *Daemon) logDryRunReap(databases []string, maxAge time.Duration, policy RetentionPolicy) {
+	plan, err := PlanReap("127.0.0.1", d.doltServerPort(), databases, maxAge, policy, time.Now().UTC())
+	for _, c := range plan {
+		d.logDryRun(DryRunWispReaper, fmt.Sprintf("close %d stale wisps, delete %d closed wisps, %d events, %d acked messages",
+			c.StaleWisps, c.Wisps, c.Events, c.Mail), c.Database)
+	}
+	if err != nil {
+		d.logger.Printf("wisp_reaper: dry run: %v", err)
+	}
+	d.logger.Printf("wisp_reaper: dry run: stale issue auto-close skipped; nothing changed")
+}
+
 // reapWispsInDB closes stale wisps in a single database.
 // Returns (reaped count, remaining open count, error).
 func (d *Daemon) reapWispsInDB(dbName string, cutoff time.Time) (int, int, error) {
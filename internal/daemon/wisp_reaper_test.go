@@ -32,7 +32,7 @@ func TestWispReaperInterval(t *testing.T) {
 }
 
 func TestWispReaperMaxAge(t *testing.T) {
-	if got := wispReaperMaxAge(nil); got != defaultWispMaxAge {
+	if got := WispReaperMaxAge(nil); got != defaultWispMaxAge {
 		t.Errorf("expected default %v, got %v", defaultWispMaxAge, got)
 	}
 
@@ -44,7 +44,7 @@ func TestWispReaperMaxAge(t *testing.T) {
 			},
 		},
 	}
-	if got := wispReaperMaxAge(config); got != 48*time.Hour {
+	if got := WispReaperMaxAge(config); got != 48*time.Hour {
 		t.Errorf("expected 48h, got %v", got)
 	}
 }
@@ -117,3 +117,32 @@ func TestJoinStrings(t *testing.T) {
 		}
 	}
 }
+
+func TestReaperRetention(t *testing.T) {
+	// Defaults
+	policy := ReaperRetention(nil)
+	if policy.Wisps != defaultWispDeleteAge || policy.Events != defaultEventRetention || policy.Mail != defaultMailRetention {
+		t.Errorf("default policy = %+v", policy)
+	}
+
+	// Per data type, with "off" disabling and invalid falling back
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			WispReaper: &WispReaperConfig{
+				DeleteAgeStr:      "48h",
+				EventRetentionStr: "off",
+				MailRetentionStr:  "nope",
+			},
+		},
+	}
+	policy = ReaperRetention(config)
+	if policy.Wisps != 48*time.Hour {
+		t.Errorf("Wisps = %v, want 48h", policy.Wisps)
+	}
+	if policy.Events != 0 {
+		t.Errorf("Events = %v, want 0 (off)", policy.Events)
+	}
+	if policy.Mail != defaultMailRetention {
+		t.Errorf("Mail = %v, want default for invalid", policy.Mail)
+	}
+}
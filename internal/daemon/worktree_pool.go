@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	defaultWorktreePoolInterval = 10 * time.Minute
+	worktreePoolTimeout         = 10 * time.Minute
+)
+
+// WorktreePoolConfig holds configuration for the worktree_pool patrol.
+//
+// The patrol refills each rig's warm worktree pool (the "worktree_pool"
+// section of <rig>/settings/config.json) and keeps the pooled worktrees
+// fetched and clean, so polecat spawns can claim one instead of checking
+// out the repo from scratch.
+type WorktreePoolConfig struct {
+	// Enabled controls whether the worktree pool patrol runs.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to maintain the pools (e.g., "10m").
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// worktreePoolInterval returns the configured interval, or the default (10m).
+func worktreePoolInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.WorktreePool != nil {
+		if config.Patrols.WorktreePool.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.WorktreePool.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultWorktreePoolInterval
+}
+
+// maintainWorktreePools shells out to `gt polecat pool maintain --all`.
+// Like enforceBudgets, this avoids importing polecat from the daemon.
+func (d *Daemon) maintainWorktreePools() {
+	ctx, cancel := context.WithTimeout(context.Background(), worktreePoolTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "gt", "polecat", "pool", "maintain", "--all")
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = append(os.Environ(), "GT_DAEMON=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("Worktree pool maintenance failed: %v (output: %s)", err, string(out))
+	} else if s := strings.TrimSpace(string(out)); s != "" {
+		d.logger.Printf("Worktree pool maintenance: %s", s)
+	}
+}
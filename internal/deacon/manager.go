@@ -174,6 +174,7 @@ func (m *Manager) Start(agentOverride string) error {
 	if realTmux, ok := t.(*tmux.Tmux); ok {
 		_ = session.TrackSessionPID(m.townRoot, sessionID, realTmux)
 	}
+	_ = session.RegisterSession(m.townRoot, session.SessionRecord{Session: sessionID, Role: session.RoleDeacon})
 
 	// PATCH-010: Set auto-respawn hook for Deacon resilience.
 	// When Claude exits (for any reason), tmux will automatically respawn it.
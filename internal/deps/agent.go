@@ -0,0 +1,95 @@
+package deps
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// agentVersionTimeout bounds `<agent> --version`, which some CLIs answer
+// slowly on first run.
+const agentVersionTimeout = 10 * time.Second
+
+var (
+	agentVersionRe      = regexp.MustCompile(`(?:^|[^\d.])(\d+\.\d+(?:\.\d+)?)`)
+	constraintVersionRe = regexp.MustCompile(`^\d+(?:\.\d+){0,2}$`)
+)
+
+// AgentVersion runs `<command> --version` and returns the first version
+// number in its output (e.g., "2.0.14" from "2.0.14 (Claude Code)").
+func AgentVersion(command string) (string, error) {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), agentVersionTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, path, "--version").CombinedOutput() //nolint:gosec // G204: command is the configured agent binary
+	if err != nil {
+		detail := strings.TrimSpace(string(output))
+		if detail == "" {
+			detail = err.Error()
+		}
+		return "", fmt.Errorf("%s --version: %s", command, detail)
+	}
+	version := ParseAgentVersion(string(output))
+	if version == "" {
+		return "", fmt.Errorf("%s --version: no version in %q", command, strings.TrimSpace(string(output)))
+	}
+	return version, nil
+}
+
+// ParseAgentVersion returns the first X.Y or X.Y.Z version in output.
+func ParseAgentVersion(output string) string {
+	if m := agentVersionRe.FindStringSubmatch(output); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// SatisfiesConstraint reports whether version satisfies constraint: one or
+// more comma-separated clauses, each an operator (>=, >, <=, <, =) and a
+// version, all of which must hold. A clause without an operator matches
+// that version and its patch releases ("2.1" matches 2.1.x).
+func SatisfiesConstraint(version, constraint string) (bool, error) {
+	for _, clause := range strings.Split(constraint, ",") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		op := ""
+		for _, o := range []string{">=", "<=", "==", ">", "<", "="} {
+			if strings.HasPrefix(clause, o) {
+				op = o
+				break
+			}
+		}
+		want := strings.TrimSpace(strings.TrimPrefix(clause, op))
+		if !constraintVersionRe.MatchString(want) {
+			return false, fmt.Errorf("invalid version constraint %q", clause)
+		}
+		cmp := CompareVersions(version, want)
+		var ok bool
+		switch op {
+		case ">=":
+			ok = cmp >= 0
+		case ">":
+			ok = cmp > 0
+		case "<=":
+			ok = cmp <= 0
+		case "<":
+			ok = cmp < 0
+		case "=", "==":
+			ok = cmp == 0
+		default:
+			ok = version == want || strings.HasPrefix(version, want+".")
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
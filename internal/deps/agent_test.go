@@ -0,0 +1,43 @@
+package deps
+
+import "testing"
+
+func TestParseAgentVersion(t *testing.T) {
+	tests := map[string]string{
+		"2.0.14 (Claude Code)\n": "2.0.14",
+		"codex-cli 0.46.0":       "0.46.0",
+		"gemini v1.2":            "1.2",
+		"no version here":        "",
+	}
+	for in, want := range tests {
+		if got := ParseAgentVersion(in); got != want {
+			t.Errorf("ParseAgentVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSatisfiesConstraint(t *testing.T) {
+	tests := []struct {
+		version, constraint string
+		want                bool
+	}{
+		{"2.0.14", ">= 2.0.10", true},
+		{"2.0.9", ">= 2.0.10", false},
+		{"2.1.0", ">=2.0, <3", true},
+		{"3.0.0", ">=2.0, <3", false},
+		{"2.1.4", "2.1", true},
+		{"2.10.0", "2.1", false},
+		{"2.1.4", "= 2.1.4", true},
+		{"2.1.5", "==2.1.4", false},
+		{"1.0.0", "", true},
+	}
+	for _, tt := range tests {
+		got, err := SatisfiesConstraint(tt.version, tt.constraint)
+		if err != nil || got != tt.want {
+			t.Errorf("SatisfiesConstraint(%q, %q) = %v, %v; want %v", tt.version, tt.constraint, got, err, tt.want)
+		}
+	}
+	if _, err := SatisfiesConstraint("1.0.0", ">= latest"); err == nil {
+		t.Error("expected error for invalid constraint")
+	}
+}
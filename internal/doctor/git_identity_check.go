@@ -0,0 +1,63 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// GitIdentityCheck validates the town's git_identity settings. A broken
+// signing setup makes every agent commit fail, so it is reported as an error.
+type GitIdentityCheck struct {
+	BaseCheck
+}
+
+// NewGitIdentityCheck creates a new git identity check.
+func NewGitIdentityCheck() *GitIdentityCheck {
+	return &GitIdentityCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "git-identity",
+			CheckDescription: "Check agent git identity and commit signing settings",
+			CheckCategory:    CategoryConfig,
+		},
+	}
+}
+
+// Run validates git_identity in town settings.
+func (c *GitIdentityCheck) Run(ctx *CheckContext) *CheckResult {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot))
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Could not load town settings: %v", err),
+		}
+	}
+	id := settings.GitIdentity
+	if id == nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "Agent git identity not configured (agents commit as GIT_AUTHOR_NAME only)",
+		}
+	}
+	if err := id.Validate(ctx.TownRoot); err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: err.Error(),
+			FixHint: "Fix git_identity in settings/config.json, or remove signing to commit unsigned",
+		}
+	}
+
+	name, email := id.Identity("polecat", "<rig>", "<name>", settings.AgentEmailDomain)
+	msg := fmt.Sprintf("Agents commit as %s <%s>", name, email)
+	if id.Signing != nil {
+		msg += fmt.Sprintf(", signed (%s)", id.Signing.Format)
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: msg,
+	}
+}
@@ -0,0 +1,344 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/failure"
+	"github.com/steveyegge/gastown/internal/runtime"
+)
+
+// remoteCheckTimeout bounds the remote reachability check.
+const remoteCheckTimeout = 15 * time.Second
+
+// PreflightChecks returns the rig environment checks run before dispatching
+// work to a rig: things a polecat session needs that, when missing, make it
+// fail after spawning instead of before.
+func PreflightChecks() []Check {
+	return []Check{
+		NewPreflightAgentCheck(),
+		NewPreflightRemoteCheck(),
+		NewPreflightTestCommandCheck(),
+		NewPreflightSecretsCheck(),
+	}
+}
+
+// rigSettings loads the rig's settings, or empty settings if it has none.
+func rigSettings(ctx *CheckContext) *config.RigSettings {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(ctx.RigPath()))
+	if err != nil || settings == nil {
+		return &config.RigSettings{}
+	}
+	return settings
+}
+
+// polecatAgent resolves the agent a polecat in the rig would run.
+func polecatAgent(ctx *CheckContext) *config.RuntimeConfig {
+	if ctx.Agent != "" {
+		if rc, _, err := config.ResolveAgentConfigWithOverride(ctx.TownRoot, ctx.RigPath(), ctx.Agent); err == nil && rc != nil {
+			return rc
+		}
+	}
+	return config.ResolveRoleAgentConfig("polecat", ctx.TownRoot, ctx.RigPath())
+}
+
+// PreflightAgentCheck verifies the polecat agent binary is installed and,
+// when the rig pins agent_version, that its version satisfies the pin. A
+// mismatch only warns under agent_version_policy "warn".
+type PreflightAgentCheck struct {
+	BaseCheck
+}
+
+// NewPreflightAgentCheck creates a new agent binary preflight check.
+func NewPreflightAgentCheck() *PreflightAgentCheck {
+	return &PreflightAgentCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "preflight-agent",
+			CheckDescription: "Verify the polecat agent binary is installed at the pinned version",
+			CheckCategory:    CategoryRig,
+		},
+	}
+}
+
+// Run checks the agent binary and version.
+func (c *PreflightAgentCheck) Run(ctx *CheckContext) *CheckResult {
+	rc := polecatAgent(ctx)
+	command := rc.Command
+	if command == "" {
+		command = "claude"
+	}
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("Agent binary %q not found on PATH", command),
+			FixHint: fmt.Sprintf("Install %s, or set the rig's agent in settings/config.json", command),
+		}
+	}
+
+	settings := rigSettings(ctx)
+	if settings.AgentVersion == "" {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("%s found (no version pinned)", command),
+			Details: []string{path},
+		}
+	}
+	version, err := runtime.VerifyAgentVersion(rc, settings.AgentVersion)
+	if err != nil {
+		status := StatusError
+		if settings.AgentVersionWarnOnly() {
+			status = StatusWarning
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  status,
+			Message: err.Error(),
+			Details: []string{path},
+			FixHint: fmt.Sprintf("Install a matching %s, or update agent_version in settings/config.json", command),
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("%s %s satisfies %q", command, version, settings.AgentVersion),
+	}
+}
+
+// PreflightRemoteCheck verifies the rig's git remote is reachable, so
+// polecats can fetch and push.
+type PreflightRemoteCheck struct {
+	BaseCheck
+}
+
+// NewPreflightRemoteCheck creates a new remote reachability preflight check.
+func NewPreflightRemoteCheck() *PreflightRemoteCheck {
+	return &PreflightRemoteCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "preflight-remote",
+			CheckDescription: "Verify the rig's git remote is reachable",
+			CheckCategory:    CategoryRig,
+		},
+	}
+}
+
+// Run checks the remote with git ls-remote.
+func (c *PreflightRemoteCheck) Run(ctx *CheckContext) *CheckResult {
+	if p := rigSettings(ctx).Preflight; p != nil && p.SkipRemote {
+		return &CheckResult{Name: c.Name(), Status: StatusOK, Message: "Skipped (preflight.skip_remote)"}
+	}
+	repo := ""
+	for _, dir := range []string{".repo.git", filepath.Join("mayor", "rig")} {
+		if _, err := os.Stat(filepath.Join(ctx.RigPath(), dir)); err == nil {
+			repo = filepath.Join(ctx.RigPath(), dir)
+			break
+		}
+	}
+	if repo == "" {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "No rig repository (.repo.git or mayor/rig) to check the remote of",
+		}
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), remoteCheckTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(tctx, "git", "-C", repo, "ls-remote", "--heads", "origin")
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		detail := firstLine(string(output))
+		if tctx.Err() != nil {
+			detail = fmt.Sprintf("timed out after %s", remoteCheckTimeout)
+		} else if detail == "" {
+			detail = err.Error()
+		}
+		remote, _ := exec.Command("git", "-C", repo, "remote", "get-url", "origin").Output()
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("Remote origin unreachable: %s", detail),
+			Details: []string{strings.TrimSpace(string(remote))},
+			FixHint: "Check network access and git credentials for the remote",
+		}
+	}
+	return &CheckResult{Name: c.Name(), Status: StatusOK, Message: "Remote origin reachable"}
+}
+
+// PreflightTestCommandCheck verifies the merge queue's test gate command
+// exists, so polecats' work can pass the gate.
+type PreflightTestCommandCheck struct {
+	BaseCheck
+}
+
+// NewPreflightTestCommandCheck creates a new test gate preflight check.
+func NewPreflightTestCommandCheck() *PreflightTestCommandCheck {
+	return &PreflightTestCommandCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "preflight-test-command",
+			CheckDescription: "Verify the test gate command exists",
+			CheckCategory:    CategoryRig,
+		},
+	}
+}
+
+// Run checks that the test command's program can be found.
+func (c *PreflightTestCommandCheck) Run(ctx *CheckContext) *CheckResult {
+	mq := rigSettings(ctx).MergeQueue
+	if mq == nil || mq.TestCommand == "" || (mq.RunTests != nil && !*mq.RunTests) {
+		return &CheckResult{Name: c.Name(), Status: StatusOK, Message: "No test gate configured"}
+	}
+	program := commandProgram(mq.TestCommand)
+	if program == "" {
+		return &CheckResult{Name: c.Name(), Status: StatusOK, Message: "Test gate is a shell expression; not checked"}
+	}
+	if strings.Contains(program, "/") {
+		// Relative paths are relative to the repository.
+		path := program
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(ctx.RigPath(), "mayor", "rig", path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return &CheckResult{
+				Name:    c.Name(),
+				Status:  StatusError,
+				Message: fmt.Sprintf("Test gate script %s not found", program),
+				Details: []string{"test_command: " + mq.TestCommand},
+				FixHint: "Fix merge_queue.test_command in settings/config.json",
+			}
+		}
+	} else if _, err := exec.LookPath(program); err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("Test gate command %q not found on PATH", program),
+			Details: []string{"test_command: " + mq.TestCommand},
+			FixHint: fmt.Sprintf("Install %s, or fix merge_queue.test_command in settings/config.json", program),
+		}
+	}
+	return &CheckResult{Name: c.Name(), Status: StatusOK, Message: fmt.Sprintf("Test gate %q found", program)}
+}
+
+// shellBuiltins are commands a test gate may start with that aren't
+// programs on PATH.
+var shellBuiltins = map[string]bool{
+	"cd": true, "source": true, ".": true, "export": true, "set": true,
+	"exec": true, "eval": true, "true": true, "test": true, "[": true,
+}
+
+// commandProgram returns the program a shell command runs, skipping leading
+// VAR=value assignments. Returns "" for commands that start with shell
+// syntax or builtins (subshells, "cd web && ...") that can't be resolved.
+func commandProgram(command string) string {
+	for _, field := range strings.Fields(command) {
+		if strings.Contains(field, "=") && !strings.HasPrefix(field, "=") && !strings.Contains(field, "/") {
+			continue
+		}
+		if strings.ContainsAny(field, "()$`{") || shellBuiltins[field] {
+			return ""
+		}
+		return field
+	}
+	return ""
+}
+
+// PreflightSecretsCheck verifies the secrets the rig requires are present.
+type PreflightSecretsCheck struct {
+	BaseCheck
+}
+
+// NewPreflightSecretsCheck creates a new secrets preflight check.
+func NewPreflightSecretsCheck() *PreflightSecretsCheck {
+	return &PreflightSecretsCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "preflight-secrets",
+			CheckDescription: "Verify required secrets are present",
+			CheckCategory:    CategoryRig,
+		},
+	}
+}
+
+// Run checks that each required secret is set in the environment or in the
+// agent's env config.
+func (c *PreflightSecretsCheck) Run(ctx *CheckContext) *CheckResult {
+	p := rigSettings(ctx).Preflight
+	if p == nil || len(p.Secrets) == 0 {
+		return &CheckResult{Name: c.Name(), Status: StatusOK, Message: "No secrets required"}
+	}
+	agentEnv := polecatAgent(ctx).Env
+	var missing []string
+	for _, name := range p.Secrets {
+		if os.Getenv(name) == "" && agentEnv[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("Missing required secret(s): %s", strings.Join(missing, ", ")),
+			FixHint: "Export them in the environment gt runs in, or set them in the agent's env config",
+		}
+	}
+	return &CheckResult{Name: c.Name(), Status: StatusOK, Message: fmt.Sprintf("%d required secret(s) present", len(p.Secrets))}
+}
+
+// PreflightError reports the preflight checks that failed for a rig.
+type PreflightError struct {
+	Rig      string
+	Failures []*CheckResult
+}
+
+func (e *PreflightError) Error() string {
+	reasons := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		reasons[i] = fmt.Sprintf("%s: %s", f.Name, f.Message)
+	}
+	return fmt.Sprintf("rig %s failed preflight: %s", e.Rig, strings.Join(reasons, "; "))
+}
+
+// FailureClass classifies the preflight failure for dispatch (see
+// internal/failure): a missing or mismatched agent binary, else a broken
+// environment. Either way the environment needs fixing before a retry.
+func (e *PreflightError) FailureClass() failure.Class {
+	for _, f := range e.Failures {
+		if f.Name == "preflight-agent" {
+			return failure.ClassBinaryNotFound
+		}
+	}
+	return failure.ClassEnvironment
+}
+
+// RunPreflight runs the preflight checks for ctx's rig and returns a
+// *PreflightError if any fail.
+func RunPreflight(ctx *CheckContext) error {
+	d := NewDoctor()
+	d.RegisterAll(PreflightChecks()...)
+	var failures []*CheckResult
+	for _, r := range d.Run(ctx).Checks {
+		if r.Status == StatusError {
+			failures = append(failures, r)
+		}
+	}
+	if len(failures) > 0 {
+		return &PreflightError{Rig: ctx.RigName, Failures: failures}
+	}
+	return nil
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
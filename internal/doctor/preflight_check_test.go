@@ -0,0 +1,99 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/failure"
+)
+
+func writePreflightSettings(t *testing.T, townRoot, rigName string, settings *config.RigSettings) {
+	t.Helper()
+	rigPath := filepath.Join(townRoot, rigName)
+	if err := os.MkdirAll(filepath.Join(rigPath, "mayor", "rig"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCommandProgram(t *testing.T) {
+	tests := map[string]string{
+		"go test ./...":               "go",
+		"CGO_ENABLED=0 go test ./...": "go",
+		"./scripts/test.sh --fast":    "./scripts/test.sh",
+		"cd web && npm test":          "",
+		"$(which make) test":          "",
+		"":                            "",
+	}
+	for command, want := range tests {
+		if got := commandProgram(command); got != want {
+			t.Errorf("commandProgram(%q) = %q, want %q", command, got, want)
+		}
+	}
+}
+
+func TestPreflightTestCommandCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewRigSettings()
+	settings.MergeQueue = &config.MergeQueueConfig{TestCommand: "./scripts/test.sh"}
+	writePreflightSettings(t, townRoot, "gastown", settings)
+	ctx := &CheckContext{TownRoot: townRoot, RigName: "gastown"}
+
+	check := NewPreflightTestCommandCheck()
+	if r := check.Run(ctx); r.Status != StatusError {
+		t.Fatalf("missing script: status = %v, want error (%s)", r.Status, r.Message)
+	}
+
+	script := filepath.Join(townRoot, "gastown", "mayor", "rig", "scripts", "test.sh")
+	if err := os.MkdirAll(filepath.Dir(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if r := check.Run(ctx); r.Status != StatusOK {
+		t.Errorf("existing script: status = %v, want ok (%s)", r.Status, r.Message)
+	}
+}
+
+func TestPreflightSecretsCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewRigSettings()
+	settings.Preflight = &config.PreflightConfig{Secrets: []string{"GT_TEST_PREFLIGHT_TOKEN"}}
+	writePreflightSettings(t, townRoot, "gastown", settings)
+	ctx := &CheckContext{TownRoot: townRoot, RigName: "gastown"}
+
+	check := NewPreflightSecretsCheck()
+	t.Setenv("GT_TEST_PREFLIGHT_TOKEN", "")
+	r := check.Run(ctx)
+	if r.Status != StatusError || !strings.Contains(r.Message, "GT_TEST_PREFLIGHT_TOKEN") {
+		t.Errorf("unset secret: got %v %q", r.Status, r.Message)
+	}
+
+	t.Setenv("GT_TEST_PREFLIGHT_TOKEN", "secret")
+	if r := check.Run(ctx); r.Status != StatusOK {
+		t.Errorf("set secret: status = %v, want ok (%s)", r.Status, r.Message)
+	}
+}
+
+func TestPreflightError(t *testing.T) {
+	err := &PreflightError{Rig: "gastown", Failures: []*CheckResult{
+		{Name: "preflight-secrets", Message: "Missing required secret(s): NPM_TOKEN"},
+	}}
+	if got := err.Error(); got != "rig gastown failed preflight: preflight-secrets: Missing required secret(s): NPM_TOKEN" {
+		t.Errorf("Error() = %q", got)
+	}
+	if got := err.FailureClass(); got != failure.ClassEnvironment {
+		t.Errorf("FailureClass() = %q, want %q", got, failure.ClassEnvironment)
+	}
+
+	err.Failures = append(err.Failures, &CheckResult{Name: "preflight-agent", Message: "Agent binary \"claude\" not found on PATH"})
+	if got := err.FailureClass(); got != failure.ClassBinaryNotFound {
+		t.Errorf("FailureClass() = %q, want %q", got, failure.ClassBinaryNotFound)
+	}
+}
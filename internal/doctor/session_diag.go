@@ -0,0 +1,199 @@
+package doctor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SessionReport is the evidence gathered about one agent session for
+// gt doctor --sessions. The command fills it in; DiagnoseSession reads it.
+type SessionReport struct {
+	Target  string `json:"target"`  // address as given (e.g. gastown/Toast)
+	Session string `json:"session"` // tmux session name
+	Role    string `json:"role"`    // polecat, crew, witness, ...
+
+	Exists       bool      `json:"exists"`
+	PaneDead     bool      `json:"pane_dead,omitempty"`
+	ExitStatus   int       `json:"exit_status,omitempty"` // -1 if unknown
+	PaneCommand  string    `json:"pane_command,omitempty"`
+	StartCommand string    `json:"start_command,omitempty"` // pane_start_command; empty for a plain shell
+	PanePID      int       `json:"pane_pid,omitempty"`
+	ProcessTree  []Process `json:"process_tree,omitempty"`
+	AgentRunning bool      `json:"agent_running"`
+	LastActivity time.Time `json:"last_activity,omitempty"`
+	Output       string    `json:"output,omitempty"` // last lines of the pane
+
+	Nudges []Nudge `json:"nudges,omitempty"` // recent nudges, oldest first
+
+	HookBead   string `json:"hook_bead,omitempty"`
+	HookTitle  string `json:"hook_title,omitempty"`
+	HookStatus string `json:"hook_status,omitempty"`
+	HookError  string `json:"hook_error,omitempty"`
+
+	WorkDir          string `json:"work_dir,omitempty"`
+	Branch           string `json:"branch,omitempty"`
+	UnpushedCommits  int    `json:"unpushed_commits,omitempty"`
+	UncommittedFiles int    `json:"uncommitted_files,omitempty"`
+	GitError         string `json:"git_error,omitempty"`
+
+	Causes []ProbableCause `json:"causes"`
+}
+
+// Process is one process in a pane's process tree.
+type Process struct {
+	PID     int    `json:"pid"`
+	Depth   int    `json:"depth"` // 0 for the pane process
+	Command string `json:"command"`
+}
+
+// Nudge is a nudge sent to the session, from the event log.
+type Nudge struct {
+	Time    time.Time `json:"time"`
+	Sender  string    `json:"sender"`
+	Message string    `json:"message"`
+}
+
+// ProbableCause is a heuristic explanation of a session's trouble.
+type ProbableCause struct {
+	Summary string `json:"summary"`
+	Detail  string `json:"detail,omitempty"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+// ignoredNudgeCount is how many nudges since the agent's last activity mark
+// it as not responding.
+const ignoredNudgeCount = 3
+
+// shellCommands are pane commands that mean the agent is not in the pane.
+var shellCommands = map[string]bool{
+	"bash": true, "zsh": true, "sh": true, "fish": true, "dash": true, "login": true,
+}
+
+// startupMarkers appear in the startup command gt builds for agents. Seeing
+// one in the pane means the command was echoed as text, not exec'd.
+var startupMarkers = []string{"exec env ", "GT_ROLE="}
+
+// missingBinaryMarkers are shell errors for a command that could not run.
+var missingBinaryMarkers = []string{"not found", "No such file or directory"}
+
+// DiagnoseSession returns the probable causes of a session's trouble, most
+// fundamental first. The two causes behind blank windows are a startup
+// command that failed immediately (dead pane) and one that landed in a
+// shell as typed text instead of replacing it (command visible, no agent).
+func DiagnoseSession(r *SessionReport) []ProbableCause {
+	var causes []ProbableCause
+	if !r.Exists {
+		return append(causes, ProbableCause{
+			Summary: "session is not running",
+			Fix:     fmt.Sprintf("gt session start %s, or check the witness log for why it stopped", r.Target),
+		})
+	}
+
+	blank := strings.TrimSpace(r.Output) == ""
+	switch {
+	case r.PaneDead && r.ExitStatus != 0:
+		c := ProbableCause{
+			Summary: fmt.Sprintf("agent command exited with status %d; the pane is kept dead by remain-on-exit", r.ExitStatus),
+			Fix:     fmt.Sprintf("fix the startup command, then gt session restart %s", r.Target),
+		}
+		if hasAny(r.Output, missingBinaryMarkers) {
+			c.Detail = "the pane output reports a missing binary or path: check the agent is installed and on PATH for tmux"
+		}
+		causes = append(causes, c)
+	case r.PaneDead:
+		causes = append(causes, ProbableCause{
+			Summary: "agent exited cleanly but the pane was kept (remain-on-exit)",
+			Fix:     fmt.Sprintf("gt session restart %s if work remains", r.Target),
+		})
+	case !r.AgentRunning && shellCommands[r.PaneCommand] && commandVisible(r.Output, r.StartCommand):
+		causes = append(causes, ProbableCause{
+			Summary: "startup command is visible in the pane but the agent is not running",
+			Detail: "the command was typed into a shell instead of replacing it, or the agent exited back to the shell; " +
+				"either way the shell is left at a prompt",
+			Fix: fmt.Sprintf("gt session restart %s, which runs the command via respawn-pane", r.Target),
+		})
+	case !r.AgentRunning && shellCommands[r.PaneCommand]:
+		causes = append(causes, ProbableCause{
+			Summary: fmt.Sprintf("pane is at a %s prompt; the agent is not running", r.PaneCommand),
+			Fix:     fmt.Sprintf("gt session restart %s", r.Target),
+		})
+	case !r.AgentRunning:
+		causes = append(causes, ProbableCause{
+			Summary: fmt.Sprintf("agent process not found (pane runs %q)", r.PaneCommand),
+			Detail:  "the process tree shows what is in the pane instead",
+		})
+	case blank:
+		causes = append(causes, ProbableCause{
+			Summary: "blank window: the agent is running but the pane shows no output",
+			Detail:  "the agent may be stuck before its first render (terminal size, auth or trust prompt off-screen)",
+			Fix:     fmt.Sprintf("gt session at %s to look, or gt session restart %s", r.Target, r.Target),
+		})
+	}
+
+	if n := nudgesSince(r.Nudges, r.LastActivity); n >= ignoredNudgeCount {
+		causes = append(causes, ProbableCause{
+			Summary: fmt.Sprintf("%d nudges since the agent's last activity", n),
+			Detail:  "the agent is not acting on input: it may be waiting on a prompt or wedged",
+		})
+	}
+
+	switch {
+	case r.HookError != "":
+		// Unknown hook state: nothing to conclude.
+	case r.HookBead == "" && r.AgentRunning && r.Role == "polecat":
+		causes = append(causes, ProbableCause{
+			Summary: "no work is hooked; the polecat is idle",
+			Fix:     "sling it work or let the witness reclaim it",
+		})
+	case r.HookStatus == "closed":
+		c := ProbableCause{
+			Summary: fmt.Sprintf("hooked issue %s is closed but the session is still up", r.HookBead),
+			Detail:  "the agent finished without running gt done, or cleanup did not run",
+		}
+		if r.UnpushedCommits > 0 {
+			c.Fix = fmt.Sprintf("push its %d unpushed commit(s) before cleaning up", r.UnpushedCommits)
+		}
+		causes = append(causes, c)
+	}
+
+	if r.UncommittedFiles > 0 && !r.AgentRunning {
+		causes = append(causes, ProbableCause{
+			Summary: fmt.Sprintf("%d uncommitted file(s) in %s with no agent to commit them", r.UncommittedFiles, r.WorkDir),
+			Fix:     "commit or stash them before restarting the session",
+		})
+	}
+	return causes
+}
+
+// commandVisible reports whether the pane shows the startup command as text.
+func commandVisible(output, startCommand string) bool {
+	if hasAny(output, startupMarkers) {
+		return true
+	}
+	cmd := strings.TrimSpace(startCommand)
+	if len(cmd) > 40 {
+		cmd = cmd[:40]
+	}
+	return cmd != "" && strings.Contains(output, cmd)
+}
+
+// nudgesSince counts nudges sent after the agent's last activity.
+func nudgesSince(nudges []Nudge, lastActivity time.Time) int {
+	n := 0
+	for _, nudge := range nudges {
+		if nudge.Time.After(lastActivity) {
+			n++
+		}
+	}
+	return n
+}
+
+func hasAny(s string, markers []string) bool {
+	for _, m := range markers {
+		if strings.Contains(s, m) {
+			return true
+		}
+	}
+	return false
+}
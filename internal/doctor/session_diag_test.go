@@ -0,0 +1,81 @@
+package doctor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiagnoseSession(t *testing.T) {
+	now := time.Now()
+	running := SessionReport{
+		Target:       "gastown/Toast",
+		Exists:       true,
+		Role:         "polecat",
+		PaneCommand:  "claude",
+		AgentRunning: true,
+		Output:       "working on it\n",
+		HookBead:     "gt-abc",
+		HookStatus:   "in_progress",
+		LastActivity: now.Add(-time.Minute),
+	}
+
+	tests := []struct {
+		name   string
+		modify func(r *SessionReport)
+		want   []string // substrings of cause summaries, in order
+	}{
+		{"healthy", func(r *SessionReport) {}, nil},
+		{"missing session", func(r *SessionReport) { r.Exists = false }, []string{"not running"}},
+		{"dead pane", func(r *SessionReport) {
+			r.PaneDead, r.ExitStatus, r.AgentRunning = true, 127, false
+			r.Output = "sh: 1: /opt/claude: not found\n"
+		}, []string{"exited with status 127"}},
+		{"command visible at shell prompt", func(r *SessionReport) {
+			r.AgentRunning, r.PaneCommand = false, "bash"
+			r.Output = "$ exec env GT_ROLE=gastown/polecats/Toast claude --settings x\n$ \n"
+		}, []string{"startup command is visible"}},
+		{"shell prompt", func(r *SessionReport) {
+			r.AgentRunning, r.PaneCommand, r.Output = false, "zsh", "% \n"
+		}, []string{"zsh prompt"}},
+		{"blank window", func(r *SessionReport) { r.Output = "\n\n   \n" }, []string{"blank window"}},
+		{"ignored nudges", func(r *SessionReport) {
+			for i := 1; i <= 3; i++ {
+				r.Nudges = append(r.Nudges, Nudge{Time: now.Add(-time.Duration(i) * time.Second)})
+			}
+			r.LastActivity = now.Add(-time.Hour)
+		}, []string{"3 nudges"}},
+		{"idle polecat", func(r *SessionReport) { r.HookBead, r.HookStatus = "", "" }, []string{"no work is hooked"}},
+		{"idle crew is fine", func(r *SessionReport) { r.Role, r.HookBead, r.HookStatus = "crew", "", "" }, nil},
+		{"closed hook with unpushed work", func(r *SessionReport) {
+			r.HookStatus, r.UnpushedCommits = "closed", 2
+		}, []string{"gt-abc is closed"}},
+		{"uncommitted work without agent", func(r *SessionReport) {
+			r.PaneDead, r.ExitStatus, r.AgentRunning = true, 0, false
+			r.UncommittedFiles, r.WorkDir = 4, "/town/gastown/polecats/Toast"
+		}, []string{"exited cleanly", "4 uncommitted"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := running
+			tt.modify(&r)
+			causes := DiagnoseSession(&r)
+			if len(causes) != len(tt.want) {
+				t.Fatalf("causes = %+v, want %d matching %q", causes, len(tt.want), tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(causes[i].Summary, want) {
+					t.Errorf("cause %d = %q, want it to mention %q", i, causes[i].Summary, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagnoseSessionMissingBinaryDetail(t *testing.T) {
+	r := &SessionReport{Exists: true, PaneDead: true, ExitStatus: 127, Output: "exec: claude: not found"}
+	causes := DiagnoseSession(r)
+	if len(causes) == 0 || !strings.Contains(causes[0].Detail, "missing binary") {
+		t.Errorf("causes = %+v, want a missing-binary detail", causes)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 )
 
@@ -13,6 +14,9 @@ import (
 // prevented valid .claude/ files in rigged repos from being used. Now that gastown's
 // repo no longer has .claude/ files, sparse checkout is no longer needed.
 //
+// Polecat worktrees of rigs that configure partial_clone.sparse are sparse on
+// purpose and are not reported.
+//
 // This check runs in both modes:
 //   - With --rig: checks only the specified rig
 //   - Without --rig: iterates over all rig directories in the town root
@@ -135,9 +139,12 @@ func (c *SparseCheckoutCheck) checkRig(rigPath string) {
 		}
 	}
 
-	// Add polecat worktrees (nested structure: polecats/<name>/<rigname>/)
+	// Add polecat worktrees (nested structure: polecats/<name>/<rigname>/),
+	// unless the rig configures sparse polecat worktrees.
 	polecatDir := filepath.Join(rigPath, "polecats")
-	if entries, err := os.ReadDir(polecatDir); err == nil {
+	settings, _ := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	sparsePolecats := settings != nil && settings.PartialClone != nil && len(settings.PartialClone.Sparse) > 0
+	if entries, err := os.ReadDir(polecatDir); err == nil && !sparsePolecats {
 		rigName := filepath.Base(rigPath)
 		for _, entry := range entries {
 			if !entry.IsDir() {
@@ -293,6 +293,31 @@ func TestSparseCheckoutCheck_PolecatNestedWorktree(t *testing.T) {
 	}
 }
 
+func TestSparseCheckoutCheck_ConfiguredSparsePolecatsSkipped(t *testing.T) {
+	tmpDir := t.TempDir()
+	rigName := "testrig"
+	rigDir := filepath.Join(tmpDir, rigName)
+
+	polecatWorktree := filepath.Join(rigDir, "polecats", "pc1", rigName)
+	initGitRepo(t, polecatWorktree)
+	configureLegacySparseCheckout(t, polecatWorktree)
+
+	settings := `{"type":"rig-settings","version":1,"partial_clone":{"sparse":["services/api"]}}`
+	if err := os.MkdirAll(filepath.Join(rigDir, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rigDir, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewSparseCheckoutCheck()
+	result := check.Run(&CheckContext{TownRoot: tmpDir, RigName: rigName})
+
+	if result.Status != StatusOK {
+		t.Errorf("expected StatusOK for rig with configured sparse polecats, got %v: %v", result.Status, result.Details)
+	}
+}
+
 func TestSparseCheckoutCheck_PolecatLegacyFlatLayout(t *testing.T) {
 	tmpDir := t.TempDir()
 	rigName := "testrig"
@@ -0,0 +1,64 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// TmuxVersionCheck verifies that tmux is installed and new enough, and lists
+// optional features that are unavailable on the installed version.
+type TmuxVersionCheck struct {
+	BaseCheck
+}
+
+// NewTmuxVersionCheck creates a new tmux version check.
+func NewTmuxVersionCheck() *TmuxVersionCheck {
+	return &TmuxVersionCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "tmux-version",
+			CheckDescription: "Check that tmux is installed and meets minimum version",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run detects the tmux version and reports unsupported or degraded setups.
+func (c *TmuxVersionCheck) Run(ctx *CheckContext) *CheckResult {
+	if err := tmux.CheckVersion(); err != nil {
+		msg := "tmux not found or version undetectable"
+		if errors.Is(err, tmux.ErrTmuxTooOld) {
+			msg = "tmux is too old"
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: msg,
+			Details: []string{err.Error()},
+			FixHint: fmt.Sprintf("Install tmux %s or newer", tmux.MinVersion),
+		}
+	}
+
+	v, _ := tmux.DetectVersion()
+	var missing []string
+	for _, f := range []tmux.Feature{tmux.FeatureNewSessionEnv, tmux.FeaturePopup, tmux.FeatureControlModeFlags} {
+		if !v.Supports(f) {
+			missing = append(missing, f.String())
+		}
+	}
+	if len(missing) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("tmux %s (fallbacks in use: %s)", v, strings.Join(missing, ", ")),
+			FixHint: "Upgrade to tmux 3.2 or newer for full functionality",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("tmux %s", v),
+	}
+}
@@ -0,0 +1,61 @@
+package doctor
+
+import (
+	"github.com/steveyegge/gastown/internal/platform"
+)
+
+// WSLCheck flags WSL setups that break Gas Town in non-obvious ways: a town
+// on a Windows drive mount, or systemd disabled so the daemon and tmux
+// server die when the last terminal closes. No-op outside WSL.
+type WSLCheck struct {
+	BaseCheck
+}
+
+// NewWSLCheck creates a new WSL environment check.
+func NewWSLCheck() *WSLCheck {
+	return &WSLCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "wsl-environment",
+			CheckDescription: "Check WSL filesystem and systemd setup",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run inspects the WSL environment.
+func (c *WSLCheck) Run(ctx *CheckContext) *CheckResult {
+	if !platform.IsWSL() {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "not running under WSL",
+		}
+	}
+
+	var details []string
+	var hint string
+	if platform.IsWindowsMount(ctx.TownRoot) {
+		details = append(details, "Town is on a Windows drive ("+ctx.TownRoot+"): git worktrees are slow and file locks are unreliable")
+		hint = "Move the town into the Linux filesystem (e.g. ~/gt)"
+	}
+	if !platform.SystemdRunning() {
+		details = append(details, "systemd is not running: sessions and the daemon stop when the last WSL terminal closes")
+		if hint == "" {
+			hint = "Set systemd=true in the [boot] section of /etc/wsl.conf, run 'wsl --shutdown', then 'gt daemon install'"
+		}
+	}
+	if len(details) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "WSL setup needs attention",
+			Details: details,
+			FixHint: hint,
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: "WSL with systemd, town on Linux filesystem",
+	}
+}
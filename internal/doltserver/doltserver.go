@@ -46,6 +46,8 @@ import (
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/endpoints"
+	"github.com/steveyegge/gastown/internal/platform"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/util"
 )
@@ -367,16 +369,9 @@ func IsRunning(townRoot string) (bool, int, error) {
 		pidStr := strings.TrimSpace(string(data))
 		pid, err := strconv.Atoi(pidStr)
 		if err == nil {
-			// Check if process is alive
-			process, err := os.FindProcess(pid)
-			if err == nil {
-				// On Unix, FindProcess always succeeds. Send signal 0 to check if alive.
-				if err := process.Signal(syscall.Signal(0)); err == nil {
-					// Verify it's actually a dolt process
-					if isDoltProcess(pid) {
-						return true, pid, nil
-					}
-				}
+			// Check if process is alive and actually a dolt process
+			if platform.ProcessExists(pid) && isDoltProcess(pid) {
+				return true, pid, nil
 			}
 		}
 		// PID file is stale, clean it up
@@ -708,7 +703,7 @@ func KillImposters(townRoot string) error {
 	// Wait for graceful shutdown
 	for i := 0; i < 10; i++ {
 		time.Sleep(500 * time.Millisecond)
-		if err := process.Signal(syscall.Signal(0)); err != nil {
+		if !platform.ProcessExists(pid) {
 			// Clean up PID file if it pointed to the imposter
 			_ = os.Remove(config.PidFile)
 			return nil
@@ -745,6 +740,25 @@ func checkPortAvailable(port int) error {
 	return nil
 }
 
+// checkPortUnclaimed verifies no other town on this machine has the port
+// registered as its Dolt port, even if that town's server is down: starting
+// on it would hand the port to whichever town starts first.
+func checkPortUnclaimed(townRoot string, port int) error {
+	conflicts, err := endpoints.Conflicts(townRoot, endpoints.Endpoint{Kind: endpoints.KindTCP, Port: port})
+	if err != nil || len(conflicts) == 0 {
+		return nil // the registry is advisory; an unreadable one doesn't block
+	}
+	c := conflicts[0]
+	hint := ""
+	if free, err := endpoints.FreePort(townRoot, port); err == nil {
+		hint = fmt.Sprintf(" (e.g. %d)", free)
+	}
+	return fmt.Errorf("port %d is registered as the %s endpoint of town %s.\n"+
+		"Each Gas Town instance needs a unique Dolt port%s.\n"+
+		"Set GT_DOLT_PORT in mayor/daemon.json env section:\n"+
+		"  {\"env\": {\"GT_DOLT_PORT\": \"<port>\"}}", port, c.Endpoint.Name, c.Town, hint)
+}
+
 // Start starts the Dolt SQL server.
 func Start(townRoot string) error {
 	config := DefaultConfig(townRoot)
@@ -904,6 +918,10 @@ func Start(townRoot string) error {
 		logFile.Close()
 		return err
 	}
+	if err := checkPortUnclaimed(townRoot, config.Port); err != nil {
+		logFile.Close()
+		return err
+	}
 
 	// Start dolt sql-server with --data-dir to serve all databases
 	// Note: --user flag is deprecated in newer Dolt; authentication is handled
@@ -1042,14 +1060,14 @@ func Stop(townRoot string) error {
 	// Wait for graceful shutdown (dolt needs more time)
 	for i := 0; i < 10; i++ {
 		time.Sleep(500 * time.Millisecond)
-		if err := process.Signal(syscall.Signal(0)); err != nil {
+		if !platform.ProcessExists(pid) {
 			// Process has exited
 			break
 		}
 	}
 
 	// Check if still running
-	if err := process.Signal(syscall.Signal(0)); err == nil {
+	if platform.ProcessExists(pid) {
 		// Still running, force kill
 		_ = process.Signal(syscall.SIGKILL)
 		time.Sleep(100 * time.Millisecond)
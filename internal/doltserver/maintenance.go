@@ -0,0 +1,271 @@
+package doltserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gcTimeout bounds a single DOLT_GC call. GC on a large, long-lived
+// database can take minutes.
+const gcTimeout = 10 * time.Minute
+
+// TableStats is the row count of one table.
+type TableStats struct {
+	Name string `json:"name"`
+	Rows int64  `json:"rows"`
+}
+
+// DatabaseStats reports a database's on-disk size and per-table row counts.
+type DatabaseStats struct {
+	Name      string       `json:"name"`
+	SizeBytes int64        `json:"size_bytes"` // 0 for a remote server
+	Tables    []TableStats `json:"tables"`
+}
+
+// TotalRows returns the row count summed over all tables.
+func (s *DatabaseStats) TotalRows() int64 {
+	var total int64
+	for _, t := range s.Tables {
+		total += t.Rows
+	}
+	return total
+}
+
+// FormatBytes returns a human-readable size string (e.g. "12.3 MB").
+func FormatBytes(b int64) string {
+	return formatBytes(b)
+}
+
+// DatabaseSize returns the on-disk size of a database, or 0 when the server
+// is remote or the database directory is missing.
+func DatabaseSize(townRoot, db string) int64 {
+	config := DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return 0
+	}
+	dir := filepath.Join(config.DataDir, db)
+	if _, err := os.Stat(dir); err != nil {
+		return 0
+	}
+	return dirSize(dir)
+}
+
+// listTables returns a database's base tables, sorted.
+func listTables(townRoot, db string) ([]string, error) {
+	out, err := doltSQLQuery(townRoot, fmt.Sprintf(
+		"SELECT table_name AS name FROM information_schema.tables WHERE table_schema = '%s' AND table_type = 'BASE TABLE'",
+		EscapeSQL(db)))
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for _, row := range parseSimpleCSV(out) {
+		if row["name"] != "" {
+			tables = append(tables, row["name"])
+		}
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// GetDatabaseStats returns a database's size and exact per-table row counts.
+func GetDatabaseStats(townRoot, db string) (*DatabaseStats, error) {
+	tables, err := listTables(townRoot, db)
+	if err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+	stats := &DatabaseStats{Name: db, SizeBytes: DatabaseSize(townRoot, db)}
+	if len(tables) == 0 {
+		return stats, nil
+	}
+
+	// One round trip: information_schema row counts are estimates in Dolt.
+	counts := make([]string, len(tables))
+	for i, t := range tables {
+		counts[i] = fmt.Sprintf("SELECT '%s' AS name, COUNT(*) AS n FROM `%s`", EscapeSQL(t), t)
+	}
+	out, err := doltSQLQuery(townRoot, fmt.Sprintf("USE `%s`; %s", db, strings.Join(counts, " UNION ALL ")))
+	if err != nil {
+		return nil, fmt.Errorf("counting rows: %w", err)
+	}
+	for _, row := range parseSimpleCSV(out) {
+		n, _ := strconv.ParseInt(row["n"], 10, 64)
+		stats.Tables = append(stats.Tables, TableStats{Name: row["name"], Rows: n})
+	}
+	return stats, nil
+}
+
+// GCResult reports a dolt gc run on one database.
+type GCResult struct {
+	Database string        `json:"database"`
+	Before   int64         `json:"size_before"`
+	After    int64         `json:"size_after"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Reclaimed returns the bytes freed by the gc (never negative).
+func (r *GCResult) Reclaimed() int64 {
+	if r.After >= r.Before {
+		return 0
+	}
+	return r.Before - r.After
+}
+
+// GC runs dolt gc on a database through the server, reclaiming space held
+// by unreferenced chunks. Sizes are 0 for a remote server.
+func GC(townRoot, db string) (*GCResult, error) {
+	result := &GCResult{Database: db, Before: DatabaseSize(townRoot, db)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gcTimeout)
+	defer cancel()
+	start := time.Now()
+	cmd := buildDoltSQLCmd(ctx, DefaultConfig(townRoot), "-q", fmt.Sprintf("USE `%s`; CALL DOLT_GC()", db))
+	output, err := cmd.CombinedOutput()
+	result.Duration = time.Since(start)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return result, fmt.Errorf("dolt gc timed out after %v", gcTimeout)
+		}
+		return result, fmt.Errorf("dolt gc: %w (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+	result.After = DatabaseSize(townRoot, db)
+	return result, nil
+}
+
+// integrityCheck counts rows that reference a missing issue. A check runs
+// only when all the tables it requires exist.
+type integrityCheck struct {
+	name     string
+	requires []string
+	query    string // SELECT COUNT(*) AS n ...
+}
+
+// localIssue matches an id that resolves to a row in issues or wisps.
+// Databases without a wisps table use issuesOnly instead.
+const (
+	localIssue = "(SELECT id FROM issues UNION SELECT id FROM wisps)"
+	issuesOnly = "(SELECT id FROM issues)"
+)
+
+// localPrefix restricts a reference column to ids with one of this
+// database's own prefixes, so cross-rig references (other databases,
+// external:<rig>:<id>) aren't reported as orphans.
+func localPrefix(column string) string {
+	return fmt.Sprintf("SUBSTRING_INDEX(%s, '-', 1) IN (SELECT DISTINCT SUBSTRING_INDEX(id, '-', 1) FROM issues)", column)
+}
+
+var integrityChecks = []integrityCheck{
+	{"labels on missing issues", []string{"labels", "issues"},
+		"SELECT COUNT(*) AS n FROM labels WHERE issue_id NOT IN " + localIssue},
+	{"comments on missing issues", []string{"comments", "issues"},
+		"SELECT COUNT(*) AS n FROM comments WHERE issue_id NOT IN " + localIssue},
+	{"events on missing issues", []string{"events", "issues"},
+		"SELECT COUNT(*) AS n FROM events WHERE issue_id NOT IN " + localIssue},
+	{"dependencies of missing issues", []string{"dependencies", "issues"},
+		"SELECT COUNT(*) AS n FROM dependencies WHERE issue_id NOT IN " + localIssue},
+	{"dependencies on missing issues", []string{"dependencies", "issues"},
+		"SELECT COUNT(*) AS n FROM dependencies WHERE depends_on_id NOT IN " + localIssue +
+			" AND " + localPrefix("depends_on_id")},
+	{"hooks on missing issues", []string{"issues"},
+		"SELECT COUNT(*) AS n FROM issues WHERE hook_bead IS NOT NULL AND hook_bead != ''" +
+			" AND hook_bead NOT IN " + localIssue + " AND " + localPrefix("hook_bead")},
+	{"wisp labels on missing wisps", []string{"wisp_labels", "wisps"},
+		"SELECT COUNT(*) AS n FROM wisp_labels WHERE issue_id NOT IN (SELECT id FROM wisps)"},
+	{"wisp comments on missing wisps", []string{"wisp_comments", "wisps"},
+		"SELECT COUNT(*) AS n FROM wisp_comments WHERE issue_id NOT IN (SELECT id FROM wisps)"},
+	{"wisp events on missing wisps", []string{"wisp_events", "wisps"},
+		"SELECT COUNT(*) AS n FROM wisp_events WHERE issue_id NOT IN (SELECT id FROM wisps)"},
+	{"wisp dependencies of missing wisps", []string{"wisp_dependencies", "wisps"},
+		"SELECT COUNT(*) AS n FROM wisp_dependencies WHERE issue_id NOT IN (SELECT id FROM wisps)"},
+}
+
+// IntegrityProblem is a failed integrity check: Count rows reference
+// issues that no longer exist.
+type IntegrityProblem struct {
+	Check string `json:"check"`
+	Count int64  `json:"count"`
+}
+
+// CheckIntegrity looks for orphaned rows in a beads database: labels,
+// comments, events and dependencies of deleted issues, and agent hooks
+// pointing at deleted issues. Returns only the checks that found orphans.
+func CheckIntegrity(townRoot, db string) ([]IntegrityProblem, error) {
+	tables, err := listTables(townRoot, db)
+	if err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+	checks := applicableChecks(tables)
+	if len(checks) == 0 {
+		return nil, nil
+	}
+
+	counts := make([]string, len(checks))
+	for i, c := range checks {
+		counts[i] = fmt.Sprintf("SELECT %d AS i, n FROM (%s) AS c%d", i, c.query, i)
+	}
+	out, err := doltSQLQuery(townRoot, fmt.Sprintf("USE `%s`; %s", db, strings.Join(counts, " UNION ALL ")))
+	if err != nil {
+		return nil, fmt.Errorf("checking integrity: %w", err)
+	}
+	var problems []IntegrityProblem
+	for _, row := range parseSimpleCSV(out) {
+		i, err := strconv.Atoi(row["i"])
+		if err != nil || i < 0 || i >= len(checks) {
+			continue
+		}
+		if n, _ := strconv.ParseInt(row["n"], 10, 64); n > 0 {
+			problems = append(problems, IntegrityProblem{Check: checks[i].name, Count: n})
+		}
+	}
+	return problems, nil
+}
+
+// applicableChecks returns the integrity checks whose tables all exist,
+// resolving issue references against issues alone when there's no wisps
+// table.
+func applicableChecks(tables []string) []integrityCheck {
+	have := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		have[t] = true
+	}
+	var checks []integrityCheck
+	for _, c := range integrityChecks {
+		ok := true
+		for _, t := range c.requires {
+			if !have[t] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			if !have["wisps"] {
+				c.query = strings.ReplaceAll(c.query, localIssue, issuesOnly)
+			}
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
+
+// QueryJSON runs a query on the server and returns dolt's JSON output
+// ({"rows": [...]}), which, unlike CSV, survives multi-line values.
+func QueryJSON(townRoot, query string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	cmd := buildDoltSQLCmd(ctx, DefaultConfig(townRoot), "-r", "json", "-q", query)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("dolt sql query failed: %w (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,49 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplicableChecks(t *testing.T) {
+	names := func(checks []integrityCheck) []string {
+		var out []string
+		for _, c := range checks {
+			out = append(out, c.name)
+		}
+		return out
+	}
+
+	if got := applicableChecks([]string{"labels"}); len(got) != 0 {
+		t.Errorf("checks without an issues table: %v", names(got))
+	}
+
+	got := applicableChecks([]string{"issues", "labels"})
+	if len(got) != 2 || got[0].name != "labels on missing issues" || got[1].name != "hooks on missing issues" {
+		t.Fatalf("checks for issues+labels = %v", names(got))
+	}
+	for _, c := range got {
+		if strings.Contains(c.query, "wisps") {
+			t.Errorf("%s references wisps without a wisps table: %s", c.name, c.query)
+		}
+	}
+
+	all := []string{"issues", "wisps", "labels", "comments", "events", "dependencies",
+		"wisp_labels", "wisp_comments", "wisp_events", "wisp_dependencies"}
+	got = applicableChecks(all)
+	if len(got) != len(integrityChecks) {
+		t.Errorf("full schema ran %d of %d checks", len(got), len(integrityChecks))
+	}
+	if !strings.Contains(got[0].query, localIssue) {
+		t.Errorf("issue references don't resolve against wisps: %s", got[0].query)
+	}
+}
+
+func TestGCResultReclaimed(t *testing.T) {
+	if got := (&GCResult{Before: 100, After: 40}).Reclaimed(); got != 60 {
+		t.Errorf("Reclaimed = %d, want 60", got)
+	}
+	if got := (&GCResult{Before: 40, After: 100}).Reclaimed(); got != 0 {
+		t.Errorf("Reclaimed after growth = %d, want 0", got)
+	}
+}
@@ -0,0 +1,144 @@
+package doltserver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnapshotBranchPrefix prefixes the branches that hold backup snapshots.
+const SnapshotBranchPrefix = "backup/"
+
+// snapshotTimeFormat is the timestamp in snapshot branch names (UTC).
+const snapshotTimeFormat = "20060102-150405"
+
+// DefaultSnapshotRetention is how many snapshot branches are kept per
+// database: a day of 15-minute backup ticks.
+const DefaultSnapshotRetention = 96
+
+// Snapshot is a point-in-time backup of a database, held as a branch.
+type Snapshot struct {
+	Branch string    `json:"branch"`
+	Hash   string    `json:"hash"`
+	Time   time.Time `json:"time"`
+}
+
+// SnapshotBranchName returns the snapshot branch name for time t.
+func SnapshotBranchName(t time.Time) string {
+	return SnapshotBranchPrefix + t.UTC().Format(snapshotTimeFormat)
+}
+
+// parseSnapshotBranch returns the time encoded in a snapshot branch name.
+func parseSnapshotBranch(branch string) (time.Time, bool) {
+	if !strings.HasPrefix(branch, SnapshotBranchPrefix) {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(snapshotTimeFormat, strings.TrimPrefix(branch, SnapshotBranchPrefix))
+	return t, err == nil
+}
+
+// ListSnapshots returns a database's snapshot branches, newest first.
+func ListSnapshots(townRoot, db string) ([]Snapshot, error) {
+	out, err := doltSQLQuery(townRoot, fmt.Sprintf(
+		"USE `%s`; SELECT name, hash FROM dolt_branches WHERE name LIKE '%s%%'", db, SnapshotBranchPrefix))
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []Snapshot
+	for _, row := range parseSimpleCSV(out) {
+		t, ok := parseSnapshotBranch(row["name"])
+		if !ok {
+			continue
+		}
+		snapshots = append(snapshots, Snapshot{Branch: row["name"], Hash: row["hash"], Time: t})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Time.After(snapshots[j].Time) })
+	return snapshots, nil
+}
+
+// CreateSnapshot commits the database's pending changes and, if HEAD moved
+// since the newest snapshot, branches a new snapshot from it and pushes the
+// branch to remote (if set). Returns the snapshot branch and whether it was
+// created; an unchanged database returns the newest snapshot.
+func CreateSnapshot(townRoot, db, remote string, now time.Time) (string, bool, error) {
+	if err := doltSQLWithRetry(townRoot, db, "CALL DOLT_ADD('-A')"); err != nil {
+		return "", false, fmt.Errorf("staging changes: %w", err)
+	}
+	commit := "CALL DOLT_COMMIT('-m', 'backup: snapshot', '--author', 'Gas Town Daemon <daemon@gastown.local>')"
+	if err := doltSQLWithRetry(townRoot, db, commit); err != nil && !isNothingToCommit(err) {
+		return "", false, fmt.Errorf("committing changes: %w", err)
+	}
+
+	out, err := doltSQLQuery(townRoot, fmt.Sprintf("USE `%s`; SELECT DOLT_HASHOF('HEAD') AS hash", db))
+	if err != nil {
+		return "", false, fmt.Errorf("resolving HEAD: %w", err)
+	}
+	rows := parseSimpleCSV(out)
+	if len(rows) == 0 || rows[0]["hash"] == "" {
+		return "", false, fmt.Errorf("resolving HEAD: no hash in %q", strings.TrimSpace(out))
+	}
+	head := rows[0]["hash"]
+
+	snapshots, err := ListSnapshots(townRoot, db)
+	if err != nil {
+		return "", false, fmt.Errorf("listing snapshots: %w", err)
+	}
+	if len(snapshots) > 0 && snapshots[0].Hash == head {
+		return snapshots[0].Branch, false, nil
+	}
+
+	branch := SnapshotBranchName(now)
+	if err := doltSQLWithRetry(townRoot, db, fmt.Sprintf("CALL DOLT_BRANCH('%s')", branch)); err != nil {
+		return "", false, fmt.Errorf("creating %s: %w", branch, err)
+	}
+	if remote != "" {
+		if err := doltSQLWithRetry(townRoot, db, fmt.Sprintf("CALL DOLT_PUSH('%s', '%s')", EscapeSQL(remote), branch)); err != nil {
+			return branch, true, fmt.Errorf("pushing %s to %s: %w", branch, remote, err)
+		}
+	}
+	return branch, true, nil
+}
+
+// PruneSnapshots deletes all but the newest keep snapshot branches of a
+// database, locally and (best-effort) on remote. Returns the deleted
+// branches.
+func PruneSnapshots(townRoot, db, remote string, keep int) ([]string, error) {
+	snapshots, err := ListSnapshots(townRoot, db)
+	if err != nil {
+		return nil, err
+	}
+	if keep < 1 || len(snapshots) <= keep {
+		return nil, nil
+	}
+	var deleted []string
+	for _, s := range snapshots[keep:] {
+		if err := doltSQL(townRoot, db, fmt.Sprintf("CALL DOLT_BRANCH('-D', '%s')", s.Branch)); err != nil {
+			return deleted, fmt.Errorf("deleting %s: %w", s.Branch, err)
+		}
+		if remote != "" {
+			// Deleting the remote branch is best-effort: a remote that
+			// rejects it keeps more history, which is safe.
+			_ = doltSQL(townRoot, db, fmt.Sprintf("CALL DOLT_PUSH('%s', ':%s')", EscapeSQL(remote), s.Branch))
+		}
+		deleted = append(deleted, s.Branch)
+	}
+	return deleted, nil
+}
+
+// RestoreSnapshot resets a database's main branch to a snapshot, with full
+// schema fidelity. The current state is snapshotted first so the restore
+// can itself be undone; its branch is returned.
+func RestoreSnapshot(townRoot, db, branch string) (string, error) {
+	if _, ok := parseSnapshotBranch(branch); !ok {
+		return "", fmt.Errorf("%q is not a snapshot branch (%s<timestamp>)", branch, SnapshotBranchPrefix)
+	}
+	safety, _, err := CreateSnapshot(townRoot, db, "", time.Now())
+	if err != nil {
+		return "", fmt.Errorf("snapshotting current state before restore: %w", err)
+	}
+	if err := doltSQLWithRetry(townRoot, db, fmt.Sprintf("CALL DOLT_RESET('--hard', '%s')", branch)); err != nil {
+		return safety, fmt.Errorf("resetting to %s: %w", branch, err)
+	}
+	return safety, nil
+}
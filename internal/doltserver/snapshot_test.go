@@ -0,0 +1,29 @@
+package doltserver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotBranchName(t *testing.T) {
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("PST", -8*3600))
+	branch := SnapshotBranchName(at)
+	if branch != "backup/20260304-130607" {
+		t.Fatalf("SnapshotBranchName = %q, want backup/20260304-130607 (UTC)", branch)
+	}
+	got, ok := parseSnapshotBranch(branch)
+	if !ok || !got.Equal(at) {
+		t.Errorf("parseSnapshotBranch(%q) = %v, %v; want %v", branch, got, ok, at)
+	}
+	for _, name := range []string{"main", "backup/latest", "polecat/Toast"} {
+		if _, ok := parseSnapshotBranch(name); ok {
+			t.Errorf("parseSnapshotBranch(%q) accepted a non-snapshot branch", name)
+		}
+	}
+}
+
+func TestRestoreSnapshotRejectsNonSnapshotBranch(t *testing.T) {
+	if _, err := RestoreSnapshot(t.TempDir(), "gastown", "main"); err == nil {
+		t.Error("RestoreSnapshot accepted main as a snapshot")
+	}
+}
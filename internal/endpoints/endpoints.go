@@ -0,0 +1,366 @@
+// Package endpoints is the registry of the ports and sockets a town's
+// services listen on: the Dolt server, the dashboard's HTTP API, the
+// WebSocket bridge and the tmux socket.
+//
+// Each town keeps its registry in <town>/daemon/endpoints.json. A machine
+// index (~/.gt/towns.json) lists every town that has registered anything,
+// so a town can see which ports and sockets the other towns on the same
+// machine have claimed and allocate around them.
+package endpoints
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/platform"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Endpoint names.
+const (
+	Dolt      = "dolt"
+	HTTP      = "http"
+	WebSocket = "websocket"
+	Tmux      = "tmux"
+)
+
+// Endpoint kinds.
+const (
+	KindTCP  = "tcp"
+	KindUnix = "unix"
+)
+
+// allocateRange is how many ports past the preferred one FreePort tries.
+const allocateRange = 100
+
+// Endpoint is one registered port or socket.
+type Endpoint struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+
+	// Host and Port locate a TCP endpoint.
+	Host string `json:"host,omitempty"`
+	Port int    `json:"port,omitempty"`
+
+	// Socket is a unix socket path. For tmux, Label is the -L name.
+	Socket string `json:"socket,omitempty"`
+	Label  string `json:"label,omitempty"`
+
+	// PID is the process serving the endpoint. Zero means the endpoint is a
+	// standing reservation (e.g. the configured Dolt port), held whether or
+	// not its server is running.
+	PID int `json:"pid,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Address returns where the endpoint lives: host:port or the socket path.
+func (e Endpoint) Address() string {
+	if e.Kind == KindUnix {
+		return e.Socket
+	}
+	host := e.Host
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, fmt.Sprint(e.Port))
+}
+
+// Live reports whether the endpoint still holds its claim: a reservation,
+// or a process that is still running.
+func (e Endpoint) Live() bool {
+	return e.PID == 0 || platform.ProcessExists(e.PID)
+}
+
+// collides reports whether two endpoints would clash on one machine.
+func (e Endpoint) collides(o Endpoint) bool {
+	if e.Kind != o.Kind {
+		return false
+	}
+	if e.Kind == KindUnix {
+		return e.Socket != "" && e.Socket == o.Socket
+	}
+	return e.Port != 0 && e.Port == o.Port && isLocal(e.Host) && isLocal(o.Host)
+}
+
+// isLocal reports whether host is this machine. Remote endpoints can't
+// collide with local ones.
+func isLocal(host string) bool {
+	switch host {
+	case "", "127.0.0.1", "localhost", "::1", "0.0.0.0":
+		return true
+	}
+	return false
+}
+
+// Registry is a town's registered endpoints.
+type Registry struct {
+	Town      string     `json:"town"`
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+// Get returns the endpoint registered under name.
+func (r *Registry) Get(name string) (Endpoint, bool) {
+	for _, e := range r.Endpoints {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return Endpoint{}, false
+}
+
+func (r *Registry) set(e Endpoint) {
+	for i := range r.Endpoints {
+		if r.Endpoints[i].Name == e.Name {
+			r.Endpoints[i] = e
+			return
+		}
+	}
+	r.Endpoints = append(r.Endpoints, e)
+	sort.Slice(r.Endpoints, func(i, j int) bool { return r.Endpoints[i].Name < r.Endpoints[j].Name })
+}
+
+// Path returns the town's registry file.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "endpoints.json")
+}
+
+// indexPath returns the machine index file. A variable so tests can
+// redirect it.
+var indexPath = func() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".gt", "towns.json")
+}
+
+// Load reads the town's registry. A missing registry reads as empty.
+func Load(townRoot string) (*Registry, error) {
+	r := &Registry{Town: townRoot}
+	data, err := os.ReadFile(Path(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return r, nil
+		}
+		return nil, fmt.Errorf("reading endpoint registry: %w", err)
+	}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("parsing endpoint registry: %w", err)
+	}
+	r.Town = townRoot
+	return r, nil
+}
+
+// Register records an endpoint for the town, replacing any earlier entry
+// with the same name, and lists the town in the machine index.
+func Register(townRoot string, e Endpoint) error {
+	if e.UpdatedAt.IsZero() {
+		e.UpdatedAt = time.Now().UTC()
+	}
+	unlock, err := lock(Path(townRoot) + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r, err := Load(townRoot)
+	if err != nil {
+		return err
+	}
+	r.set(e)
+	if err := util.EnsureDirAndWriteJSON(Path(townRoot), r); err != nil {
+		return fmt.Errorf("writing endpoint registry: %w", err)
+	}
+	return addToIndex(townRoot)
+}
+
+// Unregister removes the named endpoint from the town's registry.
+func Unregister(townRoot, name string) error {
+	unlock, err := lock(Path(townRoot) + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r, err := Load(townRoot)
+	if err != nil {
+		return err
+	}
+	kept := r.Endpoints[:0]
+	for _, e := range r.Endpoints {
+		if e.Name != name {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(r.Endpoints) {
+		return nil
+	}
+	r.Endpoints = kept
+	return util.EnsureDirAndWriteJSON(Path(townRoot), r)
+}
+
+// Claim is an endpoint registered by another town.
+type Claim struct {
+	Town     string   `json:"town"`
+	Endpoint Endpoint `json:"endpoint"`
+}
+
+// OtherClaims returns the live endpoints registered by every other town in
+// the machine index.
+func OtherClaims(townRoot string) ([]Claim, error) {
+	towns, err := readIndex()
+	if err != nil {
+		return nil, err
+	}
+	self := canonical(townRoot)
+	var claims []Claim
+	for _, town := range towns {
+		if canonical(town) == self {
+			continue
+		}
+		r, err := Load(town)
+		if err != nil {
+			continue // unreadable registry: nothing to honor
+		}
+		for _, e := range r.Endpoints {
+			if e.Live() {
+				claims = append(claims, Claim{Town: town, Endpoint: e})
+			}
+		}
+	}
+	return claims, nil
+}
+
+// Conflicts returns the other towns' claims that clash with e.
+func Conflicts(townRoot string, e Endpoint) ([]Claim, error) {
+	claims, err := OtherClaims(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []Claim
+	for _, c := range claims {
+		if e.collides(c.Endpoint) {
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts, nil
+}
+
+// FreePort returns the first port from preferred upward that no other town
+// has claimed and that is free to listen on.
+func FreePort(townRoot string, preferred int) (int, error) {
+	claims, err := OtherClaims(townRoot)
+	if err != nil {
+		return 0, err
+	}
+	claimed := make(map[int]bool)
+	for _, c := range claims {
+		if c.Endpoint.Kind == KindTCP && isLocal(c.Endpoint.Host) {
+			claimed[c.Endpoint.Port] = true
+		}
+	}
+	for port := preferred; port < preferred+allocateRange; port++ {
+		if claimed[port] || !portFree(port) {
+			continue
+		}
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free port in %d-%d", preferred, preferred+allocateRange-1)
+}
+
+func portFree(port int) bool {
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return false
+	}
+	_ = ln.Close()
+	return true
+}
+
+// index is the machine index file format.
+type index struct {
+	Towns []string `json:"towns"`
+}
+
+// readIndex returns the indexed towns that still have a registry.
+func readIndex() ([]string, error) {
+	path := indexPath()
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading town index: %w", err)
+	}
+	var idx index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("parsing town index: %w", err)
+	}
+	var towns []string
+	for _, town := range idx.Towns {
+		if _, err := os.Stat(Path(town)); err == nil {
+			towns = append(towns, town)
+		}
+	}
+	return towns, nil
+}
+
+// addToIndex lists the town in the machine index, dropping towns whose
+// registry is gone.
+func addToIndex(townRoot string) error {
+	path := indexPath()
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating town index dir: %w", err)
+	}
+	unlock, err := lock(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	towns, err := readIndex()
+	if err != nil {
+		return err
+	}
+	self := canonical(townRoot)
+	for _, town := range towns {
+		if canonical(town) == self {
+			return nil
+		}
+	}
+	towns = append(towns, self)
+	sort.Strings(towns)
+	return util.AtomicWriteJSON(path, index{Towns: towns})
+}
+
+// canonical resolves symlinks so one town isn't indexed twice.
+func canonical(townRoot string) string {
+	if resolved, err := filepath.EvalSymlinks(townRoot); err == nil {
+		return resolved
+	}
+	return filepath.Clean(townRoot)
+}
+
+func lock(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating lock dir: %w", err)
+	}
+	fl := flock.New(path)
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("acquiring %s: %w", filepath.Base(path), err)
+	}
+	return func() { _ = fl.Unlock() }, nil
+}
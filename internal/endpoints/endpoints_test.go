@@ -0,0 +1,156 @@
+package endpoints
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// useIndex points the machine index at a temp file for the test.
+func useIndex(t *testing.T) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "towns.json")
+	orig := indexPath
+	indexPath = func() string { return path }
+	t.Cleanup(func() { indexPath = orig })
+}
+
+func TestRegisterAndLoad(t *testing.T) {
+	useIndex(t)
+	town := t.TempDir()
+
+	if err := Register(town, Endpoint{Name: Tmux, Kind: KindUnix, Socket: "/tmp/tmux-1/gt", Label: "gt"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register(town, Endpoint{Name: Dolt, Kind: KindTCP, Port: 3307}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register(town, Endpoint{Name: Dolt, Kind: KindTCP, Port: 3308}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Load(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Endpoints) != 2 || r.Endpoints[0].Name != Dolt || r.Endpoints[1].Name != Tmux {
+		t.Fatalf("endpoints = %+v, want dolt then tmux", r.Endpoints)
+	}
+	dolt, _ := r.Get(Dolt)
+	if dolt.Address() != "127.0.0.1:3308" {
+		t.Errorf("dolt address = %q, want re-registered 127.0.0.1:3308", dolt.Address())
+	}
+	tmux, _ := r.Get(Tmux)
+	if tmux.Address() != "/tmp/tmux-1/gt" {
+		t.Errorf("tmux address = %q", tmux.Address())
+	}
+
+	if err := Unregister(town, Tmux); err != nil {
+		t.Fatal(err)
+	}
+	r, _ = Load(town)
+	if _, ok := r.Get(Tmux); ok {
+		t.Error("tmux still registered after Unregister")
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	useIndex(t)
+	townA, townB := t.TempDir(), t.TempDir()
+
+	for _, e := range []Endpoint{
+		{Name: Dolt, Kind: KindTCP, Port: 3307},
+		{Name: Tmux, Kind: KindUnix, Socket: "/tmp/tmux-1/gt"},
+		{Name: HTTP, Kind: KindTCP, Port: 8080, PID: 1 << 22}, // exited process
+	} {
+		if err := Register(townA, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Register(townB, Endpoint{Name: Tmux, Kind: KindUnix, Socket: "/tmp/tmux-1/other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		e    Endpoint
+		want int
+	}{
+		{"same dolt port", Endpoint{Kind: KindTCP, Port: 3307}, 1},
+		{"remote host", Endpoint{Kind: KindTCP, Host: "db.example.com", Port: 3307}, 0},
+		{"other port", Endpoint{Kind: KindTCP, Port: 3308}, 0},
+		{"same socket", Endpoint{Kind: KindUnix, Socket: "/tmp/tmux-1/gt"}, 1},
+		{"stale claim", Endpoint{Kind: KindTCP, Port: 8080}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts, err := Conflicts(townB, tt.e)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(conflicts) != tt.want {
+				t.Fatalf("conflicts = %+v, want %d", conflicts, tt.want)
+			}
+			if tt.want > 0 && conflicts[0].Town != townA {
+				t.Errorf("conflict town = %q, want %q", conflicts[0].Town, townA)
+			}
+		})
+	}
+
+	// A town never conflicts with itself.
+	if conflicts, _ := Conflicts(townA, Endpoint{Kind: KindTCP, Port: 3307}); len(conflicts) != 0 {
+		t.Errorf("town conflicts with itself: %+v", conflicts)
+	}
+}
+
+func TestFreePort(t *testing.T) {
+	useIndex(t)
+	townA, townB := t.TempDir(), t.TempDir()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	// base is claimed by town A; base+1 is in use by a listener.
+	if err := Register(townA, Endpoint{Name: HTTP, Kind: KindTCP, Port: base, PID: os.Getpid()}); err != nil {
+		t.Fatal(err)
+	}
+	busy, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(base+1)))
+	if err != nil {
+		t.Skipf("port %d unavailable: %v", base+1, err)
+	}
+	defer busy.Close()
+
+	port, err := FreePort(townB, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port < base+2 {
+		t.Errorf("FreePort = %d, want >= %d (skipping claimed and busy ports)", port, base+2)
+	}
+}
+
+func TestReadIndexDropsRemovedTowns(t *testing.T) {
+	useIndex(t)
+	kept, removed := t.TempDir(), t.TempDir()
+	for _, town := range []string{kept, removed} {
+		if err := Register(town, Endpoint{Name: Dolt, Kind: KindTCP, Port: 3307}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.RemoveAll(filepath.Join(removed, "daemon")); err != nil {
+		t.Fatal(err)
+	}
+	towns, err := readIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(towns) != 1 || canonical(towns[0]) != canonical(kept) {
+		t.Errorf("towns = %v, want only %s", towns, kept)
+	}
+}
@@ -0,0 +1,272 @@
+// Package eventarchive moves old rows of a beads database's events table
+// into compressed monthly JSONL files in the backup repo, and reads them
+// back merged with the live table so historical reports see one history.
+//
+// Layout, per database in the JSONL backup repo:
+//
+//	<db>/events-archive/2026-01.jsonl.gz
+//	<db>/events-archive/2026-02.jsonl.gz
+//
+// Each file holds the events created in that month (UTC), sorted by id.
+// Files are written deterministically, so re-archiving the same events
+// produces no git diff.
+package eventarchive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DirName is the archive directory within a database's backup directory.
+	DirName = "events-archive"
+
+	fileSuffix  = ".jsonl.gz"
+	monthFormat = "2006-01"
+)
+
+// Event is a row of the events table.
+type Event struct {
+	ID        int64   `json:"id"`
+	IssueID   string  `json:"issue_id"`
+	EventType string  `json:"event_type"`
+	Actor     string  `json:"actor"`
+	OldValue  *string `json:"old_value"`
+	NewValue  *string `json:"new_value"`
+	Comment   *string `json:"comment"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// timeLayouts are the created_at formats Dolt emits.
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	time.RFC3339Nano,
+}
+
+// Time returns when the event was created, or the zero time if created_at
+// doesn't parse.
+func (e Event) Time() time.Time {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, e.CreatedAt); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// Dir returns the archive directory for a database in the backup repo.
+func Dir(repo, db string) string {
+	return filepath.Join(repo, db, DirName)
+}
+
+// ParseRows parses dolt sql -r json output ({"rows": [...]}) into events.
+func ParseRows(data []byte) ([]Event, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	var result struct {
+		Rows []Event `json:"rows"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parsing dolt output: %w", err)
+	}
+	return result.Rows, nil
+}
+
+// Append merges events into the archive in dir, one file per month, and
+// returns the files written. Events already archived (by id) are kept
+// once, so an interrupted archival can simply be rerun.
+func Append(dir string, events []Event) ([]string, error) {
+	byMonth := make(map[string][]Event)
+	for _, e := range events {
+		t := e.Time()
+		if t.IsZero() {
+			return nil, fmt.Errorf("event %d: unparseable created_at %q", e.ID, e.CreatedAt)
+		}
+		month := t.Format(monthFormat)
+		byMonth[month] = append(byMonth[month], e)
+	}
+	if len(byMonth) == 0 {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	months := make([]string, 0, len(byMonth))
+	for month := range byMonth {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+
+	var written []string
+	for _, month := range months {
+		path := filepath.Join(dir, month+fileSuffix)
+		existing, err := readFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return written, fmt.Errorf("reading %s: %w", path, err)
+		}
+		merged := dedupe(append(existing, byMonth[month]...))
+		sort.Slice(merged, func(i, j int) bool { return merged[i].ID < merged[j].ID })
+		if err := writeFile(path, merged); err != nil {
+			return written, fmt.Errorf("writing %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// Filter selects events. Zero fields match everything.
+type Filter struct {
+	IssueID string
+	Since   time.Time
+	Until   time.Time
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e Event) bool {
+	if f.IssueID != "" && e.IssueID != f.IssueID {
+		return false
+	}
+	if f.Since.IsZero() && f.Until.IsZero() {
+		return true
+	}
+	t := e.Time()
+	if !f.Since.IsZero() && t.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !t.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Read returns the archived events in dir that match f, skipping month
+// files outside its time range. A missing archive reads as empty.
+func Read(dir string, f Filter) ([]Event, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var events []Event
+	for _, entry := range entries {
+		month, ok := strings.CutSuffix(entry.Name(), fileSuffix)
+		if !ok {
+			continue
+		}
+		start, err := time.Parse(monthFormat, month)
+		if err != nil {
+			continue
+		}
+		if !f.Until.IsZero() && !start.Before(f.Until) {
+			continue
+		}
+		if !f.Since.IsZero() && !start.AddDate(0, 1, 0).After(f.Since) {
+			continue
+		}
+		monthEvents, err := readFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", entry.Name(), err)
+		}
+		for _, e := range monthEvents {
+			if f.Match(e) {
+				events = append(events, e)
+			}
+		}
+	}
+	return events, nil
+}
+
+// Merge combines archived and live events into one history, oldest first.
+// An event present in both (archived but not yet deleted) appears once.
+func Merge(archived, live []Event) []Event {
+	merged := dedupe(append(append([]Event(nil), archived...), live...))
+	sort.SliceStable(merged, func(i, j int) bool {
+		ti, tj := merged[i].Time(), merged[j].Time()
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return merged[i].ID < merged[j].ID
+	})
+	return merged
+}
+
+// dedupe drops events whose id was already seen, keeping the first.
+func dedupe(events []Event) []Event {
+	seen := make(map[int64]bool, len(events))
+	out := events[:0:0]
+	for _, e := range events {
+		if seen[e.ID] {
+			continue
+		}
+		seen[e.ID] = true
+		out = append(out, e)
+	}
+	return out
+}
+
+func readFile(path string) ([]Event, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path within the backup repo
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("parsing event: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
+
+// writeFile writes events atomically. The gzip header carries no name or
+// timestamp, so identical events produce identical bytes.
+func writeFile(path string, events []Event) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil { //nolint:gosec // G306: backup data, not secret
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
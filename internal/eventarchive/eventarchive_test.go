@@ -0,0 +1,103 @@
+package eventarchive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestAppendAndRead(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hq", DirName)
+	events := []Event{
+		{ID: 1, IssueID: "hq-1", EventType: "created", Actor: "mayor", CreatedAt: "2026-01-05 10:00:00"},
+		{ID: 2, IssueID: "hq-2", EventType: "status_changed", NewValue: strPtr("closed"), CreatedAt: "2026-01-31 23:59:59"},
+		{ID: 3, IssueID: "hq-1", EventType: "commented", Comment: strPtr("line one\nline two"), CreatedAt: "2026-02-01 00:00:00"},
+	}
+	written, err := Append(dir, events)
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if len(written) != 2 {
+		t.Fatalf("wrote %v, want two month files", written)
+	}
+	before, err := os.ReadFile(written[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-archiving the same events is idempotent, byte for byte.
+	if _, err := Append(dir, events[:2]); err != nil {
+		t.Fatalf("Append again: %v", err)
+	}
+	after, _ := os.ReadFile(written[0])
+	if string(before) != string(after) {
+		t.Error("re-archiving identical events changed the file")
+	}
+
+	all, err := Read(dir, Filter{})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("Read returned %d events, want 3", len(all))
+	}
+	if all[2].Comment == nil || *all[2].Comment != "line one\nline two" {
+		t.Errorf("comment did not round-trip: %v", all[2].Comment)
+	}
+
+	feb, err := Read(dir, Filter{Since: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(feb) != 1 || feb[0].ID != 3 {
+		t.Errorf("Since filter = %+v, want event 3", feb)
+	}
+	issue, _ := Read(dir, Filter{IssueID: "hq-1"})
+	if len(issue) != 2 {
+		t.Errorf("IssueID filter returned %d events, want 2", len(issue))
+	}
+}
+
+func TestReadMissingArchive(t *testing.T) {
+	events, err := Read(filepath.Join(t.TempDir(), "none"), Filter{})
+	if err != nil || len(events) != 0 {
+		t.Errorf("Read(missing) = %v, %v", events, err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	archived := []Event{
+		{ID: 1, CreatedAt: "2026-01-01 00:00:00"},
+		{ID: 2, CreatedAt: "2026-01-02 00:00:00"},
+	}
+	live := []Event{
+		{ID: 2, CreatedAt: "2026-01-02 00:00:00"}, // archived, not yet deleted
+		{ID: 3, CreatedAt: "2026-03-01 00:00:00"},
+	}
+	merged := Merge(archived, live)
+	if len(merged) != 3 || merged[0].ID != 1 || merged[2].ID != 3 {
+		t.Errorf("Merge = %+v", merged)
+	}
+}
+
+func TestAppendRejectsBadTimestamp(t *testing.T) {
+	if _, err := Append(t.TempDir(), []Event{{ID: 1, CreatedAt: "yesterday"}}); err == nil {
+		t.Error("Append accepted an unparseable created_at")
+	}
+}
+
+func TestParseRows(t *testing.T) {
+	events, err := ParseRows([]byte(`{"rows":[{"id":7,"issue_id":"gt-1","event_type":"created","actor":"a","old_value":null,"new_value":null,"comment":null,"created_at":"2026-01-01 00:00:00"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].ID != 7 || events[0].Time().Year() != 2026 {
+		t.Errorf("ParseRows = %+v", events)
+	}
+	if events, err := ParseRows(nil); err != nil || events != nil {
+		t.Errorf("ParseRows(empty) = %v, %v", events, err)
+	}
+}
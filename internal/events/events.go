@@ -51,8 +51,28 @@ const (
 	TypeSessionEnd   = "session_end"
 
 	// Session death events (for crash investigation)
-	TypeSessionDeath = "session_death" // Feed-visible session termination
-	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
+	TypeSessionDeath   = "session_death"   // Feed-visible session termination
+	TypeMassDeath      = "mass_death"      // Multiple sessions died in short window
+	TypeSessionFailure = "session_failure" // A dead session was classified (see internal/failure)
+
+	// Human takeover window (see internal/takeover)
+	TypeTakeover = "takeover"
+	TypeHandback = "handback"
+
+	// Ad-hoc maintenance window (see internal/maintenance)
+	TypeMaintenanceStart = "maintenance_start"
+	TypeMaintenanceEnd   = "maintenance_end"
+	TypePanic            = "panic" // gt panic froze the town
+
+	// Action a patrol in dry-run mode would have taken
+	TypePatrolDryRun = "patrol_dry_run"
+
+	// Announcements with acknowledgement tracking (see internal/announce)
+	TypeAnnouncement    = "announcement"
+	TypeAnnouncementAck = "announcement_ack"
+
+	// Issue reassigned between agents with context (gt handoff <issue> <target>)
+	TypeIssueHandoff = "issue_handoff"
 
 	// Witness patrol events
 	TypePatrolStarted   = "patrol_started"
@@ -74,6 +94,36 @@ const (
 	TypeSchedulerDispatch       = "scheduler_dispatch"        // Bead dispatched from scheduler
 	TypeSchedulerDispatchFailed = "scheduler_dispatch_failed" // Bead dispatch failed (requeued)
 	TypeSchedulerCloseRetry     = "scheduler_close_retry"     // Context close needed last-resort attempt
+
+	// Rig spend budgets (see internal/budget)
+	TypeBudgetExceeded = "budget_exceeded"
+	TypeBudgetExtended = "budget_extended"
+
+	// Change-feed events (emitted by the daemon from Dolt commit diffs)
+	TypeIssueCreated       = "issue_created"        // New issue row committed
+	TypeIssueStatusChanged = "issue_status_changed" // Issue status changed between commits
+	TypeMailCreated        = "mail_created"         // New gt:message bead committed
+	TypeMRCreated          = "mr_created"           // New merge-request wisp row
+
+	// Staleness events (emitted by the daemon's staleness patrol)
+	TypeStalenessAction = "staleness_action" // A staleness policy acted on an issue
+
+	// Provider throttle events (emitted by the daemon's provider_pressure patrol)
+	TypeProviderThrottle = "provider_throttle" // A provider's throttle level changed
+
+	// Polecat cleanup events (gt polecat nuke, gt witness cleanup)
+	TypePolecatCleanup = "polecat_cleanup" // A polecat was cleaned up; payload has the plan
+
+	// Session environment events (gt session reconcile)
+	TypeSessionEnvReconciled = "session_env_reconciled" // A running session's derived environment was updated
+
+	// Agent progress signals (emitted by the daemon's agent_signals patrol)
+	TypeAgentSignal = "agent_signal" // A progress signal appeared in an agent's pane output
+
+	// Outbox events (gt commands queued while beads was unreachable)
+	TypeOutboxQueued   = "outbox_queued"   // A command was queued for replay
+	TypeOutboxReplayed = "outbox_replayed" // The daemon replayed a queued command
+	TypeOutboxFailed   = "outbox_failed"   // A queued command kept failing and was set aside
 )
 
 // EventsFile is the name of the raw events log.
@@ -176,6 +226,19 @@ func HandoffPayload(subject string, toSession bool) map[string]interface{} {
 	return p
 }
 
+// IssueHandoffPayload creates a payload for issue_handoff events.
+func IssueHandoffPayload(beadID, from, to, branch string) map[string]interface{} {
+	p := map[string]interface{}{
+		"bead": beadID,
+		"from": from,
+		"to":   to,
+	}
+	if branch != "" {
+		p["branch"] = branch
+	}
+	return p
+}
+
 // DonePayload creates a payload for done events.
 func DonePayload(beadID, branch string) map[string]interface{} {
 	return map[string]interface{}{
@@ -259,6 +322,73 @@ func NudgePayload(rig, target, reason string) map[string]interface{} {
 	}
 }
 
+// PatrolDryRunPayload creates a payload for patrol_dry_run events.
+func PatrolDryRunPayload(patrol, action, target string) map[string]interface{} {
+	return map[string]interface{}{
+		"patrol": patrol,
+		"action": action,
+		"target": target,
+	}
+}
+
+// MaintenancePayload creates a payload for maintenance window events.
+// until is empty for a window with no end time.
+func MaintenancePayload(reason, until string) map[string]interface{} {
+	p := map[string]interface{}{}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	if until != "" {
+		p["until"] = until
+	}
+	return p
+}
+
+// PanicPayload creates a payload for panic events. incident is the
+// directory the town was snapshotted into.
+func PanicPayload(reason, incident string) map[string]interface{} {
+	p := map[string]interface{}{"incident": incident}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	return p
+}
+
+// TakeoverPayload creates a payload for takeover and handback events.
+// duration is empty for a takeover and the window's length for a handback.
+func TakeoverPayload(target, session, reason, duration string) map[string]interface{} {
+	p := map[string]interface{}{
+		"target":  target,
+		"session": session,
+	}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	if duration != "" {
+		p["duration"] = duration
+	}
+	return p
+}
+
+// AnnouncementPayload creates a payload for announcement events.
+func AnnouncementPayload(id, scope, message string, recipients int) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         id,
+		"scope":      scope,
+		"message":    message,
+		"recipients": recipients,
+	}
+}
+
+// AnnouncementAckPayload creates a payload for announcement_ack events.
+// via is "command" (gt ack) or "output" (seen in the agent's pane).
+func AnnouncementAckPayload(id, via string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":  id,
+		"via": via,
+	}
+}
+
 // EscalationPayload creates a payload for escalation events.
 func EscalationPayload(rig, target, to, reason string) map[string]interface{} {
 	return map[string]interface{}{
@@ -306,6 +436,25 @@ func SessionDeathPayload(session, agent, reason, caller string) map[string]inter
 	}
 }
 
+// SessionFailurePayload creates a payload for classified session failures.
+// class and recovery come from the failure taxonomy (e.g., "auth_required",
+// "fix_environment"); evidence is the output line that decided the class.
+func SessionFailurePayload(session, agent, issue, class, recovery, evidence string) map[string]interface{} {
+	p := map[string]interface{}{
+		"session":  session,
+		"agent":    agent,
+		"class":    class,
+		"recovery": recovery,
+	}
+	if issue != "" {
+		p["issue"] = issue
+	}
+	if evidence != "" {
+		p["evidence"] = evidence
+	}
+	return p
+}
+
 // MassDeathPayload creates a payload for mass death events.
 // count: number of sessions that died
 // window: time window in which deaths occurred (e.g., "5s")
@@ -360,6 +509,22 @@ func SchedulerDispatchPayload(beadID, rig, polecat string) map[string]interface{
 	}
 }
 
+// BudgetPayload creates a payload for budget_exceeded and budget_extended
+// events. amount is the spend for an exceeded budget and the extension for
+// an extended one.
+func BudgetPayload(rig, period string, amount, limit float64, reason string) map[string]interface{} {
+	p := map[string]interface{}{
+		"rig":    rig,
+		"period": period,
+		"amount": amount,
+		"limit":  limit,
+	}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	return p
+}
+
 // SchedulerDispatchFailedPayload creates a payload for scheduler dispatch failure events.
 func SchedulerDispatchFailedPayload(beadID, rig, errMsg string) map[string]interface{} {
 	return map[string]interface{}{
@@ -368,3 +533,87 @@ func SchedulerDispatchFailedPayload(beadID, rig, errMsg string) map[string]inter
 		"error": errMsg,
 	}
 }
+
+// ChangePayload creates a payload for change-feed events. from and to are
+// the old and new status for status changes and empty otherwise.
+func ChangePayload(db, issueID, title, from, to string) map[string]interface{} {
+	p := map[string]interface{}{
+		"db":    db,
+		"issue": issueID,
+		"title": title,
+	}
+	if from != "" {
+		p["from"] = from
+	}
+	if to != "" {
+		p["to"] = to
+	}
+	return p
+}
+
+// StalenessPayload creates a payload for staleness_action events.
+func StalenessPayload(rig, issueID, policy, action, assignee string) map[string]interface{} {
+	p := map[string]interface{}{
+		"rig":    rig,
+		"issue":  issueID,
+		"policy": policy,
+		"action": action,
+	}
+	if assignee != "" {
+		p["assignee"] = assignee
+	}
+	return p
+}
+
+// ProviderThrottlePayload creates a payload for provider_throttle events.
+func ProviderThrottlePayload(provider, level string, sessions, limited, retrying int) map[string]interface{} {
+	return map[string]interface{}{
+		"provider": provider,
+		"level":    level,
+		"sessions": sessions,
+		"limited":  limited,
+		"retrying": retrying,
+	}
+}
+
+// AgentSignalPayload creates a payload for agent_signal events. tokens and
+// contextLeft are only included when known.
+func AgentSignalPayload(session, kind, detail string, tokens, contextLeft int) map[string]interface{} {
+	p := map[string]interface{}{
+		"session": session,
+		"kind":    kind,
+		"detail":  detail,
+	}
+	if tokens > 0 {
+		p["tokens"] = tokens
+	}
+	if contextLeft >= 0 {
+		p["context_left"] = contextLeft
+	}
+	return p
+}
+
+// PolecatCleanupPayload creates a payload for polecat_cleanup events:
+// the planned actions in order, and the ones that failed.
+func PolecatCleanupPayload(rig, polecat string, actions, failed []string) map[string]interface{} {
+	p := map[string]interface{}{
+		"rig":     rig,
+		"polecat": polecat,
+		"actions": actions,
+	}
+	if len(failed) > 0 {
+		p["failed"] = failed
+	}
+	return p
+}
+
+// SessionEnvPayload creates a payload for session_env_reconciled events.
+// changes are "KEY: old -> new" descriptions.
+func SessionEnvPayload(session, agent string, changes []string, nudged bool) map[string]interface{} {
+	return map[string]interface{}{
+		"session": session,
+		"agent":   agent,
+		"changes": changes,
+		"nudged":  nudged,
+	}
+}
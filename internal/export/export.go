@@ -0,0 +1,124 @@
+// Package export writes tables of records as CSV or Parquet so town data
+// (issues, merges, costs) can be analyzed in spreadsheets and notebooks.
+//
+// Tables are small and written in one go: the Parquet writer produces a
+// single uncompressed row group with one PLAIN-encoded page per column,
+// which every Parquet reader understands.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Type is the type of a column's values.
+type Type int
+
+// Column types. Values are string, int64, float64 and time.Time
+// respectively; nil is a missing value in any column.
+const (
+	String Type = iota
+	Int64
+	Float64
+	Time
+)
+
+// Column is a named, typed column.
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Table is a named set of rows with a fixed schema.
+type Table struct {
+	Name    string
+	Columns []Column
+	Rows    [][]any
+}
+
+// NewTable returns an empty table with the given columns.
+func NewTable(name string, columns ...Column) *Table {
+	return &Table{Name: name, Columns: columns}
+}
+
+// Append adds a row. Values must match the columns in number and type
+// (or be nil); Validate reports mismatches.
+func (t *Table) Append(values ...any) {
+	t.Rows = append(t.Rows, values)
+}
+
+// Validate checks every row against the schema.
+func (t *Table) Validate() error {
+	for i, row := range t.Rows {
+		if len(row) != len(t.Columns) {
+			return fmt.Errorf("%s row %d: %d values for %d columns", t.Name, i, len(row), len(t.Columns))
+		}
+		for j, v := range row {
+			if v == nil {
+				continue
+			}
+			ok := false
+			switch t.Columns[j].Type {
+			case String:
+				_, ok = v.(string)
+			case Int64:
+				_, ok = v.(int64)
+			case Float64:
+				_, ok = v.(float64)
+			case Time:
+				_, ok = v.(time.Time)
+			}
+			if !ok {
+				return fmt.Errorf("%s row %d: column %s: unexpected %T", t.Name, i, t.Columns[j].Name, v)
+			}
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes t as CSV with a header row. Times are RFC 3339 in UTC;
+// missing values are empty.
+func WriteCSV(w io.Writer, t *Table) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	header := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		header[i] = c.Name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(t.Columns))
+	for _, row := range t.Rows {
+		for i, v := range row {
+			record[i] = formatCSV(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatCSV(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,211 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testTable() *Table {
+	t := NewTable("issues",
+		Column{"id", String},
+		Column{"priority", Int64},
+		Column{"cycle_hours", Float64},
+		Column{"closed_at", Time},
+	)
+	closed := time.Date(2026, 10, 2, 15, 4, 5, 0, time.UTC)
+	t.Append("gt-a", int64(1), 2.5, closed)
+	t.Append("gt-b, \"quoted\"", int64(2), nil, nil)
+	t.Append("gt-c", int64(0), 0.25, closed.Add(time.Hour))
+	return t
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testTable()); err != nil {
+		t.Fatal(err)
+	}
+	want := `id,priority,cycle_hours,closed_at
+gt-a,1,2.5,2026-10-02T15:04:05Z
+"gt-b, ""quoted""",2,,
+gt-c,0,0.25,2026-10-02T16:04:05Z
+`
+	if buf.String() != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tbl := NewTable("x", Column{"n", Int64})
+	tbl.Append(3) // int, not int64
+	if err := WriteCSV(&bytes.Buffer{}, tbl); err == nil || !strings.Contains(err.Error(), "column n") {
+		t.Errorf("WriteCSV() error = %v, want column type error", err)
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	tbl := testTable()
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, tbl); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if string(data[:4]) != "PAR1" || string(data[len(data)-4:]) != "PAR1" {
+		t.Fatalf("missing magic bytes")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	r := &thriftReader{data: data[len(data)-8-footerLen : len(data)-8]}
+	meta := r.readStruct()
+
+	if got := meta[3].(int64); got != 3 {
+		t.Errorf("num_rows = %d, want 3", got)
+	}
+	schema := meta[2].([]any)
+	if len(schema) != 5 || string(schema[2].(map[int16]any)[4].([]byte)) != "priority" {
+		t.Fatalf("schema = %v", schema)
+	}
+
+	rowGroup := meta[4].([]any)[0].(map[int16]any)
+	columns := rowGroup[1].([]any)
+	if len(columns) != 4 {
+		t.Fatalf("row group has %d columns, want 4", len(columns))
+	}
+
+	// Decode each column's page and compare with the table.
+	for i, c := range columns {
+		colMeta := c.(map[int16]any)[3].(map[int16]any)
+		offset := int(colMeta[9].(int64))
+		pr := &thriftReader{data: data[offset:]}
+		header := pr.readStruct()
+		pageSize := int(header[2].(int64))
+		page := data[offset+pr.pos : offset+pr.pos+pageSize]
+
+		levelsLen := int(binary.LittleEndian.Uint32(page))
+		levels := decodeLevels(page[4:4+levelsLen], len(tbl.Rows))
+		values := page[4+levelsLen:]
+		for row, present := range levels {
+			want := tbl.Rows[row][i]
+			if !present {
+				if want != nil {
+					t.Errorf("column %d row %d: null, want %v", i, row, want)
+				}
+				continue
+			}
+			var got any
+			switch tbl.Columns[i].Type {
+			case String:
+				n := binary.LittleEndian.Uint32(values)
+				got, values = string(values[4:4+n]), values[4+n:]
+			case Int64:
+				got, values = int64(binary.LittleEndian.Uint64(values)), values[8:]
+			case Float64:
+				got, values = math.Float64frombits(binary.LittleEndian.Uint64(values)), values[8:]
+			case Time:
+				got, values = time.UnixMilli(int64(binary.LittleEndian.Uint64(values))).UTC(), values[8:]
+			}
+			if got != want {
+				t.Errorf("column %d row %d = %v, want %v", i, row, got, want)
+			}
+		}
+	}
+}
+
+func TestWriteParquetEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, NewTable("empty", Column{"id", String})); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	meta := (&thriftReader{data: data[len(data)-8-footerLen : len(data)-8]}).readStruct()
+	if meta[3].(int64) != 0 || len(meta[4].([]any)) != 0 {
+		t.Errorf("empty table metadata = %v", meta)
+	}
+}
+
+// decodeLevels decodes RLE-only definition levels of bit width 1.
+func decodeLevels(b []byte, n int) []bool {
+	var out []bool
+	for len(out) < n {
+		header, k := binary.Uvarint(b)
+		count, level := int(header>>1), b[k]
+		b = b[k+1:]
+		for j := 0; j < count; j++ {
+			out = append(out, level == 1)
+		}
+	}
+	return out
+}
+
+// thriftReader decodes the compact protocol generically: structs become
+// map[field id]value, lists []any, integers int64 and binaries []byte.
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) byte() byte {
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	u := r.uvarint()
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (r *thriftReader) readStruct() map[int16]any {
+	out := make(map[int16]any)
+	var last int16
+	for {
+		b := r.byte()
+		if b == 0 {
+			return out
+		}
+		typ := b & 0x0f
+		id := last + int16(b>>4)
+		if b>>4 == 0 {
+			id = int16(r.varint())
+		}
+		last = id
+		out[id] = r.readValue(typ)
+	}
+}
+
+func (r *thriftReader) readValue(typ byte) any {
+	switch typ {
+	case 1, 2:
+		return typ == 1
+	case thriftI32, thriftI64:
+		return r.varint()
+	case thriftBinary:
+		n := int(r.uvarint())
+		v := r.data[r.pos : r.pos+n]
+		r.pos += n
+		return v
+	case thriftList:
+		h := r.byte()
+		n, elem := int(h>>4), h&0x0f
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]any, 0, n)
+		for i := 0; i < n; i++ {
+			list = append(list, r.readValue(elem))
+		}
+		return list
+	case thriftStruct:
+		return r.readStruct()
+	}
+	panic("unsupported thrift type")
+}
@@ -0,0 +1,295 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+// Parquet format constants (see parquet.thrift).
+const (
+	parquetMagic = "PAR1"
+
+	// Physical types.
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	// Converted types.
+	parquetUTF8            = 0
+	parquetTimestampMillis = 9
+
+	parquetOptional     = 1 // FieldRepetitionType
+	parquetPlain        = 0 // Encoding
+	parquetRLE          = 3 // Encoding
+	parquetDataPage     = 0 // PageType
+	parquetUncompressed = 0 // CompressionCodec
+)
+
+// WriteParquet writes t as a Parquet file. Every column is OPTIONAL so
+// nil values round-trip as nulls; times are TIMESTAMP_MILLIS in UTC.
+func WriteParquet(w io.Writer, t *Table) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+
+	var chunks []columnChunk
+	if len(t.Rows) > 0 {
+		for i, col := range t.Columns {
+			page := encodeColumnPage(t.Rows, i, col.Type)
+			header := encodePageHeader(len(t.Rows), len(page))
+			chunk := columnChunk{
+				column: col,
+				offset: int64(file.Len()),
+				size:   int64(len(header) + len(page)),
+				values: int64(len(t.Rows)),
+			}
+			file.Write(header)
+			file.Write(page)
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	footer := encodeFileMetaData(t, chunks)
+	file.Write(footer)
+	_ = binary.Write(&file, binary.LittleEndian, uint32(len(footer)))
+	file.WriteString(parquetMagic)
+
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// columnChunk records where a column's page landed in the file.
+type columnChunk struct {
+	column Column
+	offset int64
+	size   int64
+	values int64
+}
+
+func physicalType(t Type) int32 {
+	switch t {
+	case Int64, Time:
+		return parquetInt64
+	case Float64:
+		return parquetDouble
+	default:
+		return parquetByteArray
+	}
+}
+
+// encodeColumnPage returns the body of a v1 data page for column i:
+// RLE definition levels (1 = present, 0 = null) followed by the PLAIN
+// values of the present rows.
+func encodeColumnPage(rows [][]any, i int, typ Type) []byte {
+	var levels, values bytes.Buffer
+	// RLE runs of equal definition levels: varint(count<<1), then the
+	// level in one byte (bit width 1).
+	run, runLevel := 0, byte(0)
+	flush := func() {
+		if run > 0 {
+			writeUvarint(&levels, uint64(run)<<1)
+			levels.WriteByte(runLevel)
+		}
+	}
+	for _, row := range rows {
+		v := row[i]
+		level := byte(0)
+		if v != nil {
+			level = 1
+			writePlain(&values, v, typ)
+		}
+		if run > 0 && level != runLevel {
+			flush()
+			run = 0
+		}
+		runLevel = level
+		run++
+	}
+	flush()
+
+	var page bytes.Buffer
+	_ = binary.Write(&page, binary.LittleEndian, uint32(levels.Len()))
+	page.Write(levels.Bytes())
+	page.Write(values.Bytes())
+	return page.Bytes()
+}
+
+func writePlain(buf *bytes.Buffer, v any, typ Type) {
+	switch typ {
+	case Int64:
+		_ = binary.Write(buf, binary.LittleEndian, v.(int64))
+	case Time:
+		_ = binary.Write(buf, binary.LittleEndian, v.(time.Time).UnixMilli())
+	case Float64:
+		_ = binary.Write(buf, binary.LittleEndian, math.Float64bits(v.(float64)))
+	default:
+		s := v.(string)
+		_ = binary.Write(buf, binary.LittleEndian, uint32(len(s)))
+		buf.WriteString(s)
+	}
+}
+
+func encodePageHeader(numValues, pageSize int) []byte {
+	var w thriftWriter
+	w.begin()
+	w.i32(1, parquetDataPage)
+	w.i32(2, int32(pageSize)) // uncompressed_page_size
+	w.i32(3, int32(pageSize)) // compressed_page_size
+	w.structField(5)          // data_page_header
+	w.i32(1, int32(numValues))
+	w.i32(2, parquetPlain)
+	w.i32(3, parquetRLE) // definition levels
+	w.i32(4, parquetRLE) // repetition levels
+	w.end()
+	w.end()
+	return w.buf.Bytes()
+}
+
+func encodeFileMetaData(t *Table, chunks []columnChunk) []byte {
+	var w thriftWriter
+	w.begin()
+	w.i32(1, 1) // version
+
+	w.list(2, thriftStruct, len(t.Columns)+1) // schema
+	w.begin()
+	w.binary(4, t.Name)
+	w.i32(5, int32(len(t.Columns))) // num_children
+	w.end()
+	for _, col := range t.Columns {
+		w.begin()
+		w.i32(1, physicalType(col.Type))
+		w.i32(3, parquetOptional)
+		w.binary(4, col.Name)
+		switch col.Type {
+		case String:
+			w.i32(6, parquetUTF8)
+		case Time:
+			w.i32(6, parquetTimestampMillis)
+		}
+		w.end()
+	}
+
+	w.i64(3, int64(len(t.Rows))) // num_rows
+
+	rowGroups := 0
+	if len(chunks) > 0 {
+		rowGroups = 1
+	}
+	w.list(4, thriftStruct, rowGroups)
+	if rowGroups == 1 {
+		var total int64
+		w.begin()
+		w.list(1, thriftStruct, len(chunks)) // columns
+		for _, c := range chunks {
+			total += c.size
+			w.begin()
+			w.i64(2, c.offset) // file_offset
+			w.structField(3)   // meta_data
+			w.i32(1, physicalType(c.column.Type))
+			w.list(2, thriftI32, 2) // encodings
+			w.listI32(parquetPlain)
+			w.listI32(parquetRLE)
+			w.list(3, thriftBinary, 1) // path_in_schema
+			w.listBinary(c.column.Name)
+			w.i32(4, parquetUncompressed)
+			w.i64(5, c.values)
+			w.i64(6, c.size) // total_uncompressed_size
+			w.i64(7, c.size) // total_compressed_size
+			w.i64(9, c.offset)
+			w.end()
+			w.end()
+		}
+		w.i64(2, total) // total_byte_size
+		w.i64(3, int64(len(t.Rows)))
+		w.end()
+	}
+
+	w.binary(6, "gastown gt export")
+	w.end()
+	return w.buf.Bytes()
+}
+
+// Thrift compact protocol element types.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes the subset of the Thrift compact protocol Parquet
+// metadata needs. Structs are opened with begin (or structField) and
+// closed with end; field IDs are delta-encoded per struct.
+type thriftWriter struct {
+	buf  bytes.Buffer
+	last []int16
+}
+
+func (w *thriftWriter) begin() { w.last = append(w.last, 0) }
+
+func (w *thriftWriter) end() {
+	w.buf.WriteByte(0) // STOP
+	w.last = w.last[:len(w.last)-1]
+}
+
+func (w *thriftWriter) field(id int16, typ byte) {
+	top := len(w.last) - 1
+	if delta := id - w.last[top]; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		writeUvarint(&w.buf, zigzag(int64(id)))
+	}
+	w.last[top] = id
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	writeUvarint(&w.buf, zigzag(int64(v)))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	writeUvarint(&w.buf, zigzag(v))
+}
+
+func (w *thriftWriter) binary(id int16, s string) {
+	w.field(id, thriftBinary)
+	w.listBinary(s)
+}
+
+func (w *thriftWriter) structField(id int16) {
+	w.field(id, thriftStruct)
+	w.begin()
+}
+
+func (w *thriftWriter) list(id int16, elemType byte, n int) {
+	w.field(id, thriftList)
+	if n < 15 {
+		w.buf.WriteByte(byte(n)<<4 | elemType)
+	} else {
+		w.buf.WriteByte(0xf0 | elemType)
+		writeUvarint(&w.buf, uint64(n))
+	}
+}
+
+func (w *thriftWriter) listI32(v int32) { writeUvarint(&w.buf, zigzag(int64(v))) }
+
+func (w *thriftWriter) listBinary(s string) {
+	writeUvarint(&w.buf, uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+func zigzag(v int64) uint64 { return uint64(v<<1) ^ uint64(v>>63) }
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	buf.Write(tmp[:binary.PutUvarint(tmp[:], v)])
+}
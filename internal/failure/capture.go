@@ -0,0 +1,24 @@
+package failure
+
+import "github.com/steveyegge/gastown/internal/tmux"
+
+// captureLines is how much scrollback is captured from a dead pane.
+const captureLines = 200
+
+// DeadPane is the tmux access needed to capture a dead pane.
+type DeadPane interface {
+	CaptureDeadPane(session string, lines int) (*tmux.DeadPaneCapture, error)
+}
+
+// CaptureDead captures and classifies session if its pane has died
+// (remain-on-exit keeps a dead pane around). ok is false when the pane is
+// alive or cannot be inspected.
+func CaptureDead(t DeadPane, session string) (r Record, ok bool) {
+	c, err := t.CaptureDeadPane(session, captureLines)
+	if err != nil {
+		return Record{}, false
+	}
+	r = Record{Session: session, ExitCode: c.ExitCode}
+	r.Classify(c.Output())
+	return r, true
+}
@@ -0,0 +1,210 @@
+// Package failure classifies why agent sessions died.
+//
+// When a session's process exits, the tail of its pane output and its exit
+// status are classified into a small taxonomy (binary not found, auth
+// required, rate limited, crashed, clean exit, ...). Each class maps to a
+// recovery: retry the session, wait before retrying, fix the environment
+// first, or escalate to a human. Classifications are appended to a per-town
+// log so failures can be reported in aggregate.
+package failure
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Class is the kind of failure that ended a session.
+type Class string
+
+const (
+	// ClassBinaryNotFound: the agent command could not be executed.
+	ClassBinaryNotFound Class = "binary_not_found"
+	// ClassAuthRequired: the agent needs a login or its credentials expired.
+	ClassAuthRequired Class = "auth_required"
+	// ClassEnvironment: the rig's environment can't support a session
+	// (remote unreachable, secrets missing), found by preflight.
+	ClassEnvironment Class = "environment"
+	// ClassRateLimited: the agent stopped on a provider rate limit.
+	ClassRateLimited Class = "rate_limited"
+	// ClassCrashed: the agent crashed with a stack trace or fatal error.
+	ClassCrashed Class = "crashed"
+	// ClassKilled: the agent was stopped by a signal (Ctrl-C, OOM killer, kill).
+	ClassKilled Class = "killed"
+	// ClassCleanExit: the agent exited normally.
+	ClassCleanExit Class = "clean_exit"
+	// ClassUnknown: the agent exited with an error that matched nothing else.
+	ClassUnknown Class = "unknown"
+)
+
+// Classes lists every class, in reporting order.
+var Classes = []Class{
+	ClassBinaryNotFound, ClassAuthRequired, ClassEnvironment, ClassRateLimited,
+	ClassCrashed, ClassKilled, ClassCleanExit, ClassUnknown,
+}
+
+// Recovery is how a failure should be handled.
+type Recovery string
+
+const (
+	// RecoverRetry restarts the session.
+	RecoverRetry Recovery = "retry"
+	// RecoverWait restarts the session after a backoff (the cause is transient
+	// but immediate retries would fail the same way).
+	RecoverWait Recovery = "wait"
+	// RecoverFixEnvironment holds the session: restarting fails the same way
+	// until someone fixes the machine (install the binary, log in).
+	RecoverFixEnvironment Recovery = "fix_environment"
+	// RecoverEscalate holds the session and asks a human to look.
+	RecoverEscalate Recovery = "escalate"
+)
+
+// Recovery returns the default recovery for c.
+func (c Class) Recovery() Recovery {
+	switch c {
+	case ClassBinaryNotFound, ClassAuthRequired, ClassEnvironment:
+		return RecoverFixEnvironment
+	case ClassRateLimited:
+		return RecoverWait
+	}
+	return RecoverRetry
+}
+
+// Classification is the result of classifying a dead session.
+type Classification struct {
+	Class    Class    `json:"class"`
+	Recovery Recovery `json:"recovery"`
+	// Evidence is the output line that decided the class, if any.
+	Evidence string `json:"evidence,omitempty"`
+}
+
+// Exit codes with fixed meanings in shells.
+const (
+	exitCannotExecute = 126
+	exitNotFound      = 127
+	exitSignalBase    = 128
+)
+
+var (
+	binaryNotFoundPatterns = compile(
+		`command not found`,
+		`executable file not found`,
+		`exec: .*: not found`,
+		`exec .*: no such file or directory`,
+		`env: .*: No such file or directory`,
+		`cannot execute binary file`,
+	)
+	authPatterns = compile(
+		`Invalid API key`,
+		`Please run /login`,
+		`\bnot logged in\b`,
+		`authentication_error`,
+		`OAuth token (revoked|has expired)`,
+		`\b401 Unauthorized\b`,
+		`API Error.*\b401\b`,
+		`Missing API key`,
+		`(OPENAI|ANTHROPIC|GEMINI)_API_KEY.*(not set|missing|required)`,
+	)
+	rateLimitPatterns = compile(append(append([]string{}, constants.DefaultRateLimitPatterns...),
+		constants.DefaultRateLimitRetryPatterns...)...)
+	crashPatterns = compile(
+		`^panic: `,
+		`^goroutine \d+ \[`,
+		`^Traceback \(most recent call last\)`,
+		`^\s+at .+ \(.+:\d+:\d+\)$`,
+		`^Uncaught \w*Error`,
+		`FATAL ERROR:`,
+		`Segmentation fault`,
+		`core dumped`,
+		`JavaScript heap out of memory`,
+		`thread '.+' panicked at`,
+	)
+)
+
+func compile(patterns ...string) []*regexp.Regexp {
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		res[i] = regexp.MustCompile(`(?i)` + p)
+	}
+	return res
+}
+
+// TailLines is how many non-blank lines at the end of the output Classify
+// looks at. A process's dying words are at the bottom; looking further up
+// would match errors the agent merely displayed while working.
+const TailLines = 15
+
+// Classify classifies a dead session from the tail of its pane output and
+// its exit code (-1 if unknown). Environment problems are checked before
+// rate limits and crashes, since they explain the others (an expired token
+// can read like a limit, a missing binary makes the shell print errors).
+func Classify(tail string, exitCode int) Classification {
+	lines := lastLines(tail, TailLines)
+
+	if exitCode == exitNotFound || exitCode == exitCannotExecute {
+		return classification(ClassBinaryNotFound, lastMatch(lines, binaryNotFoundPatterns))
+	}
+	for _, c := range []struct {
+		class    Class
+		patterns []*regexp.Regexp
+	}{
+		{ClassBinaryNotFound, binaryNotFoundPatterns},
+		{ClassAuthRequired, authPatterns},
+		{ClassRateLimited, rateLimitPatterns},
+		{ClassCrashed, crashPatterns},
+	} {
+		if line := lastMatch(lines, c.patterns); line != "" {
+			return classification(c.class, line)
+		}
+	}
+
+	switch {
+	case exitCode == 0:
+		return classification(ClassCleanExit, "")
+	case exitCode > exitSignalBase && exitCode <= exitSignalBase+64:
+		return classification(ClassKilled, "")
+	}
+	return classification(ClassUnknown, lastLine(lines))
+}
+
+func classification(c Class, evidence string) Classification {
+	return Classification{Class: c, Recovery: c.Recovery(), Evidence: evidence}
+}
+
+// lastLines returns the last n non-blank lines of s.
+func lastLines(s string, n int) []string {
+	all := strings.Split(s, "\n")
+	var out []string
+	for i := len(all) - 1; i >= 0 && len(out) < n; i-- {
+		if strings.TrimSpace(all[i]) != "" {
+			out = append(out, all[i])
+		}
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// lastMatch returns the last line matching any pattern, trimmed, or "".
+func lastMatch(lines []string, patterns []*regexp.Regexp) string {
+	for i := len(lines) - 1; i >= 0; i-- {
+		for _, re := range patterns {
+			if re.MatchString(lines[i]) {
+				return strings.TrimSpace(lines[i])
+			}
+		}
+	}
+	return ""
+}
+
+// lastLine returns the last non-blank line, trimmed.
+func lastLine(lines []string) string {
+	for i := len(lines) - 1; i >= 0; i-- {
+		if s := strings.TrimSpace(lines[i]); s != "" {
+			return s
+		}
+	}
+	return ""
+}
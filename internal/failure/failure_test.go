@@ -0,0 +1,168 @@
+package failure
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		tail     string
+		exitCode int
+		want     Class
+		recovery Recovery
+	}{
+		{"exit 127", "bash: claude: command not found", 127, ClassBinaryNotFound, RecoverFixEnvironment},
+		{"exit 127 no output", "", 127, ClassBinaryNotFound, RecoverFixEnvironment},
+		{"env not found", "env: 'codex': No such file or directory", 1, ClassBinaryNotFound, RecoverFixEnvironment},
+		{"login", "Invalid API key · Please run /login", 1, ClassAuthRequired, RecoverFixEnvironment},
+		{"oauth expired", "OAuth token has expired. Please obtain a new token", 1, ClassAuthRequired, RecoverFixEnvironment},
+		{"rate limited", "  ⎿  You've hit your limit · resets 7pm (America/Los_Angeles)", 1, ClassRateLimited, RecoverWait},
+		{"go panic", "panic: runtime error: index out of range\n\ngoroutine 1 [running]:\nmain.main()", 2, ClassCrashed, RecoverRetry},
+		{"node crash", "TypeError: x is undefined\n    at run (/usr/lib/node_modules/cli.js:10:5)", 1, ClassCrashed, RecoverRetry},
+		{"oom", "<--- Last few GCs --->\nFATAL ERROR: Reached heap limit Allocation failed - JavaScript heap out of memory", 134, ClassCrashed, RecoverRetry},
+		{"clean", "Goodbye!", 0, ClassCleanExit, RecoverRetry},
+		{"sigterm", "", 143, ClassKilled, RecoverRetry},
+		{"unknown", "something odd\nError: boom\n", 1, ClassUnknown, RecoverRetry},
+	}
+	for _, tt := range tests {
+		got := Classify(tt.tail, tt.exitCode)
+		if got.Class != tt.want || got.Recovery != tt.recovery {
+			t.Errorf("%s: Classify = %s/%s, want %s/%s", tt.name, got.Class, got.Recovery, tt.want, tt.recovery)
+		}
+	}
+	if got := Classify("something odd\nError: boom\n", 1); got.Evidence != "Error: boom" {
+		t.Errorf("unknown evidence = %q, want last line", got.Evidence)
+	}
+}
+
+func TestClassifyOnlyLooksAtTail(t *testing.T) {
+	// An error the agent displayed while working, well above the exit,
+	// doesn't decide the class.
+	var b strings.Builder
+	b.WriteString("⏺ Bash(npm test)\n  ⎿  sh: jest: command not found\n")
+	for i := 0; i < TailLines+5; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	if got := Classify(b.String(), 0); got.Class != ClassCleanExit {
+		t.Errorf("Classify = %s, want %s", got.Class, ClassCleanExit)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	err := fmt.Errorf("starting polecat: %w", &tmux.CommandExitError{
+		Session: "gt-gastown-Toast", Command: "claude", Status: "127",
+		Output: "exec: claude: not found\n",
+	})
+	r := ClassifyError(err)
+	if r.Class != ClassBinaryNotFound || r.Session != "gt-gastown-Toast" || r.ExitCode != 127 {
+		t.Errorf("ClassifyError(exit) = %+v", r)
+	}
+	r = ClassifyError(fmt.Errorf("spawning: exec: \"codex\": executable file not found in $PATH"))
+	if r.Class != ClassBinaryNotFound || r.ExitCode != -1 {
+		t.Errorf("ClassifyError(plain) = %+v", r)
+	}
+	r = ClassifyError(fmt.Errorf("sling: %w", classifiedErr{}))
+	if r.Class != ClassEnvironment || r.Recovery != RecoverFixEnvironment {
+		t.Errorf("ClassifyError(classified) = %+v", r)
+	}
+}
+
+type classifiedErr struct{}
+
+func (classifiedErr) Error() string       { return "rig gastown failed preflight" }
+func (classifiedErr) FailureClass() Class { return ClassEnvironment }
+
+func TestAppendReadLatest(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now().UTC()
+	for i, class := range []Class{ClassCrashed, ClassAuthRequired, ClassCrashed} {
+		r := Record{Time: now.Add(time.Duration(i-3) * time.Minute), Session: "gt-a", ExitCode: 1}
+		r.Classification = classification(class, "")
+		if err := Append(townRoot, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Append(townRoot, Record{Time: now.Add(-2 * time.Hour), Session: "gt-b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := Read(townRoot, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Read = %d records, want 3", len(records))
+	}
+	if r := Latest(records, "gt-a", now.Add(-time.Hour)); r == nil || r.Time != records[2].Time {
+		t.Errorf("Latest(gt-a) = %+v", r)
+	}
+	if r := Latest(records, "gt-a", now); r != nil {
+		t.Errorf("Latest after the last failure = %+v, want nil", r)
+	}
+
+	empty, err := Read(t.TempDir(), time.Time{})
+	if err != nil || empty != nil {
+		t.Errorf("Read(no log) = %v, %v", empty, err)
+	}
+}
+
+func TestDecideEscalates(t *testing.T) {
+	now := time.Now()
+	crash := func(ago time.Duration) Record {
+		return Record{Time: now.Add(-ago), Session: "gt-a", Classification: classification(ClassCrashed, "")}
+	}
+	records := []Record{crash(2 * time.Hour), crash(20 * time.Minute), crash(10 * time.Minute)}
+	if got := Decide(records, records[2]); got != RecoverRetry {
+		t.Errorf("2 crashes in window: Decide = %s, want retry", got)
+	}
+	records = append(records, crash(0))
+	if got := Decide(records, records[3]); got != RecoverEscalate {
+		t.Errorf("3 crashes in window: Decide = %s, want escalate", got)
+	}
+
+	auth := Record{Time: now, Session: "gt-a", Classification: classification(ClassAuthRequired, "")}
+	if got := Decide([]Record{auth, auth, auth}, auth); got != RecoverFixEnvironment {
+		t.Errorf("auth: Decide = %s, want fix_environment", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	records := []Record{
+		{Agent: "gastown/Toast", Classification: classification(ClassCrashed, "")},
+		{Agent: "gastown/Nux", Classification: classification(ClassCrashed, "")},
+		{Agent: "beads/Ace", Classification: classification(ClassRateLimited, "")},
+	}
+	s := Summarize(records, func(r Record) string { return strings.SplitN(r.Agent, "/", 2)[0] })
+	if s.Total != 3 || s.ByClass[ClassCrashed] != 2 || s.ByRig["gastown"][ClassCrashed] != 2 || s.ByRig["beads"][ClassRateLimited] != 1 {
+		t.Errorf("Summarize = %+v", s)
+	}
+}
+
+type fakeDeadPane struct {
+	dead  bool
+	code  int
+	lines []string
+}
+
+func (f fakeDeadPane) CaptureDeadPane(session string, _ int) (*tmux.DeadPaneCapture, error) {
+	if !f.dead {
+		return nil, tmux.ErrPaneAlive
+	}
+	return &tmux.DeadPaneCapture{Session: session, ExitCode: f.code, Lines: f.lines}, nil
+}
+
+func TestCaptureDead(t *testing.T) {
+	if _, ok := CaptureDead(fakeDeadPane{}, "gt-a"); ok {
+		t.Error("CaptureDead(alive pane) ok, want not ok")
+	}
+	r, ok := CaptureDead(fakeDeadPane{dead: true, code: 3, lines: []string{"working...", "Error: boom"}}, "gt-a")
+	if !ok || r.Session != "gt-a" || r.ExitCode != 3 || r.Class != ClassUnknown || r.Evidence != "Error: boom" {
+		t.Errorf("CaptureDead = %+v, %v", r, ok)
+	}
+}
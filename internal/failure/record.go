@@ -0,0 +1,190 @@
+package failure
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Record is one classified session failure.
+// Stored as a line of <townRoot>/.runtime/failures.jsonl.
+type Record struct {
+	Time     time.Time `json:"time"`
+	Session  string    `json:"session"`
+	Agent    string    `json:"agent,omitempty"`
+	Issue    string    `json:"issue,omitempty"` // work hooked when the session died
+	ExitCode int       `json:"exit_code"`       // -1 if unknown
+	Classification
+	Tail []string `json:"tail,omitempty"` // the lines that were classified
+}
+
+// Escalation thresholds: a session failing the same way this many times
+// within the window is escalated instead of retried again.
+const (
+	EscalateAfter  = 3
+	EscalateWindow = time.Hour
+)
+
+// LogFile returns the failure log path for a town.
+func LogFile(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "failures.jsonl")
+}
+
+// Classify classifies a dead session and fills in the record.
+func (r *Record) Classify(tail string) {
+	r.Classification = Classify(tail, r.ExitCode)
+	r.Tail = lastLines(tail, TailLines)
+}
+
+// ClassifiedError is implemented by errors that know their failure class
+// (e.g., a failed rig preflight).
+type ClassifiedError interface {
+	error
+	FailureClass() Class
+}
+
+// ClassifyError classifies a failure to start a session. A command that
+// exited right after the session was created is classified from its output
+// and exit status; any other error from its message.
+func ClassifyError(err error) Record {
+	var classified ClassifiedError
+	if errors.As(err, &classified) {
+		return Record{ExitCode: -1, Classification: classification(classified.FailureClass(), classified.Error())}
+	}
+	var exitErr *tmux.CommandExitError
+	if errors.As(err, &exitErr) {
+		code, convErr := strconv.Atoi(exitErr.Status)
+		if convErr != nil {
+			code = -1
+		}
+		r := Record{Session: exitErr.Session, ExitCode: code}
+		r.Classify(exitErr.Output)
+		return r
+	}
+	r := Record{ExitCode: -1}
+	r.Classify(err.Error())
+	return r
+}
+
+// Append records a failure.
+func Append(townRoot string, r Record) error {
+	if r.Time.IsZero() {
+		r.Time = time.Now().UTC()
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	path := LogFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		return fmt.Errorf("opening failure log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing failure log: %w", err)
+	}
+	return f.Close()
+}
+
+// Read returns the failures recorded since since (all when zero), oldest
+// first.
+func Read(townRoot string, since time.Time) ([]Record, error) {
+	f, err := os.Open(LogFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if json.Unmarshal(scanner.Bytes(), &r) != nil || r.Time.Before(since) {
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// Latest returns the most recent failure of session since since, or nil.
+func Latest(records []Record, session string, since time.Time) *Record {
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].Session == session && !records[i].Time.Before(since) {
+			return &records[i]
+		}
+	}
+	return nil
+}
+
+// Decide returns the recovery for r given earlier failures: r's class
+// default, escalated when the session has failed the same way
+// EscalateAfter times within EscalateWindow (counting r).
+func Decide(records []Record, r Record) Recovery {
+	rec := r.Class.Recovery()
+	if rec == RecoverFixEnvironment {
+		return rec
+	}
+	n := 0
+	for _, p := range records {
+		if p.Session == r.Session && p.Class == r.Class && !p.Time.After(r.Time) && r.Time.Sub(p.Time) <= EscalateWindow {
+			n++
+		}
+	}
+	if n >= EscalateAfter {
+		return RecoverEscalate
+	}
+	return rec
+}
+
+// Summary counts failures by class and by rig.
+type Summary struct {
+	Total   int                      `json:"total"`
+	ByClass map[Class]int            `json:"by_class"`
+	ByRig   map[string]map[Class]int `json:"by_rig"`
+}
+
+// Summarize aggregates records. rigOf maps a record to its rig ("" if none).
+func Summarize(records []Record, rigOf func(Record) string) Summary {
+	s := Summary{ByClass: make(map[Class]int), ByRig: make(map[string]map[Class]int)}
+	for _, r := range records {
+		s.Total++
+		s.ByClass[r.Class]++
+		rig := rigOf(r)
+		if s.ByRig[rig] == nil {
+			s.ByRig[rig] = make(map[Class]int)
+		}
+		s.ByRig[rig][r.Class]++
+	}
+	return s
+}
+
+// Reason is a one-line description of r, for logs and notifications.
+func (r Record) Reason() string {
+	var b strings.Builder
+	b.WriteString(string(r.Class))
+	if r.ExitCode >= 0 {
+		fmt.Fprintf(&b, " (exit %d)", r.ExitCode)
+	}
+	if r.Evidence != "" {
+		fmt.Fprintf(&b, ": %s", r.Evidence)
+	}
+	return b.String()
+}
@@ -0,0 +1,171 @@
+// Package featureflag gates risky subsystems behind town-level flags, so
+// operators can switch them off, limit them to some rigs, or roll them out
+// to a share of dispatches. Flags live in the town settings ("features")
+// and GT_FEATURE_<NAME>=on|off overrides them for one process.
+//
+// A subsystem without a flag is governed by its own settings alone: flags
+// only narrow what is already switched on (gt mayor autopilot on, a rig's
+// warm_standby size, ...), so an existing town behaves the same until a
+// flag is added.
+package featureflag
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Flag names.
+const (
+	Autopilot    = "autopilot"     // Mayor autopilot digests sent by the daemon
+	AutoRecovery = "auto_recovery" // Re-slinging work hooked to a dead agent without --force
+	WarmPools    = "warm_pools"    // Warm standby sessions and pooled worktrees at spawn
+)
+
+// Known lists the flags and what they gate, in display order.
+var Known = []struct {
+	Name  string
+	Gates string
+}{
+	{Autopilot, "mayor autopilot digests"},
+	{AutoRecovery, "auto re-sling of work hooked to dead agents"},
+	{WarmPools, "warm standby sessions and pooled worktrees"},
+}
+
+// Decision sources.
+const (
+	SourceDefault = "default" // No flag: the subsystem's own settings decide
+	SourceConfig  = "config"  // Town settings "features"
+	SourceEnv     = "env"     // GT_FEATURE_<NAME>
+)
+
+// Decision is the outcome of evaluating a flag.
+type Decision struct {
+	Flag    string `json:"flag"`
+	Enabled bool   `json:"enabled"`
+	Source  string `json:"source"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+func (d Decision) String() string {
+	state := "off"
+	if d.Enabled {
+		state = "on"
+	}
+	return d.Flag + "=" + state
+}
+
+// EnvVar returns the variable overriding a flag, e.g. GT_FEATURE_WARM_POOLS.
+func EnvVar(name string) string {
+	return "GT_FEATURE_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// Flags loads the town's configured flags. Unreadable settings count as
+// none configured.
+func Flags(townRoot string) map[string]*config.FeatureFlag {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	return settings.Features
+}
+
+// Evaluate decides a flag for rig ("" = town-wide) and a dispatch key,
+// usually the issue being dispatched ("" = not a dispatch; a percentage
+// rollout then doesn't apply).
+func Evaluate(townRoot, name, rig, key string) Decision {
+	return Decide(name, Flags(townRoot)[name], rig, key, os.Getenv)
+}
+
+// Enabled is Evaluate reduced to its answer.
+func Enabled(townRoot, name, rig, key string) bool {
+	return Evaluate(townRoot, name, rig, key).Enabled
+}
+
+// Decide evaluates flag (nil = not configured) under the given environment.
+func Decide(name string, flag *config.FeatureFlag, rig, key string, getenv func(string) string) Decision {
+	d := Decision{Flag: name}
+	if v := strings.ToLower(strings.TrimSpace(getenv(EnvVar(name)))); v != "" {
+		d.Source = SourceEnv
+		switch v {
+		case "1", "on", "true", "yes":
+			d.Enabled = true
+			return d
+		case "0", "off", "false", "no":
+			d.Reason = EnvVar(name) + "=" + v
+			return d
+		}
+		// Unrecognized values fall through to the configured flag.
+	}
+	if flag == nil {
+		d.Enabled, d.Source = true, SourceDefault
+		return d
+	}
+
+	d.Source = SourceConfig
+	switch {
+	case !flag.Enabled:
+		d.Reason = "disabled"
+	case rig != "" && len(flag.Rigs) > 0 && !contains(flag.Rigs, rig):
+		d.Reason = fmt.Sprintf("not enabled for rig %s", rig)
+	case key != "" && flag.Percent > 0 && flag.Percent < 100 && bucket(name, key) >= flag.Percent:
+		d.Reason = fmt.Sprintf("%s is outside the %d%% rollout", key, flag.Percent)
+	default:
+		d.Enabled = true
+	}
+	return d
+}
+
+// bucket places a dispatch key in [0, 100) for a flag. Hashing the flag
+// name too keeps rollouts of different flags independent.
+func bucket(name, key string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name + "/" + key))
+	return int(h.Sum32() % 100)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary formats decisions for logs and event payloads, sorted by flag:
+// "auto_recovery=off warm_pools=on".
+func Summary(decisions []Decision) string {
+	parts := make([]string, len(decisions))
+	for i, d := range decisions {
+		parts[i] = d.String()
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+// Describe formats a configured flag for gt status, e.g. "on (rigs:
+// gastown, 25%)"; nil reads as "default".
+func Describe(flag *config.FeatureFlag) string {
+	if flag == nil {
+		return "default"
+	}
+	if !flag.Enabled {
+		return "off"
+	}
+	var scope []string
+	if len(flag.Rigs) > 0 {
+		scope = append(scope, "rigs: "+strings.Join(flag.Rigs, ", "))
+	}
+	if flag.Percent > 0 && flag.Percent < 100 {
+		scope = append(scope, fmt.Sprintf("%d%%", flag.Percent))
+	}
+	if len(scope) == 0 {
+		return "on"
+	}
+	return "on (" + strings.Join(scope, ", ") + ")"
+}
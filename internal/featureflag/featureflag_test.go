@@ -0,0 +1,74 @@
+package featureflag
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestDecide(t *testing.T) {
+	noEnv := func(string) string { return "" }
+	tests := []struct {
+		name    string
+		flag    *config.FeatureFlag
+		rig     string
+		key     string
+		env     map[string]string
+		want    bool
+		source  string
+		because string
+	}{
+		{name: "unconfigured", want: true, source: SourceDefault},
+		{name: "disabled", flag: &config.FeatureFlag{}, want: false, source: SourceConfig, because: "disabled"},
+		{name: "enabled", flag: &config.FeatureFlag{Enabled: true}, rig: "gastown", want: true, source: SourceConfig},
+		{name: "other rig", flag: &config.FeatureFlag{Enabled: true, Rigs: []string{"beads"}}, rig: "gastown", want: false, source: SourceConfig, because: "rig gastown"},
+		{name: "listed rig", flag: &config.FeatureFlag{Enabled: true, Rigs: []string{"gastown"}}, rig: "gastown", want: true, source: SourceConfig},
+		{name: "percent without dispatch", flag: &config.FeatureFlag{Enabled: true, Percent: 1}, want: true, source: SourceConfig},
+		{name: "env on beats disabled", flag: &config.FeatureFlag{}, env: map[string]string{"GT_FEATURE_WARM_POOLS": "on"}, want: true, source: SourceEnv},
+		{name: "env off beats unconfigured", env: map[string]string{"GT_FEATURE_WARM_POOLS": "0"}, want: false, source: SourceEnv, because: "GT_FEATURE_WARM_POOLS=0"},
+		{name: "env garbage ignored", env: map[string]string{"GT_FEATURE_WARM_POOLS": "maybe"}, want: true, source: SourceDefault},
+	}
+	for _, tt := range tests {
+		getenv := noEnv
+		if tt.env != nil {
+			getenv = func(k string) string { return tt.env[k] }
+		}
+		d := Decide(WarmPools, tt.flag, tt.rig, tt.key, getenv)
+		if d.Enabled != tt.want || d.Source != tt.source || !strings.Contains(d.Reason, tt.because) {
+			t.Errorf("%s: got %+v, want enabled=%v source=%s reason~%q", tt.name, d, tt.want, tt.source, tt.because)
+		}
+	}
+}
+
+func TestDecidePercentRollout(t *testing.T) {
+	flag := &config.FeatureFlag{Enabled: true, Percent: 30}
+	noEnv := func(string) string { return "" }
+	on := 0
+	for i := 0; i < 1000; i++ {
+		key := "gt-" + strings.Repeat("x", i%7) + string(rune('a'+i%26)) + string(rune('a'+i/26%26))
+		d := Decide(AutoRecovery, flag, "gastown", key, noEnv)
+		if d != Decide(AutoRecovery, flag, "gastown", key, noEnv) {
+			t.Fatalf("decision for %s is not stable", key)
+		}
+		if d.Enabled {
+			on++
+		}
+	}
+	if on < 200 || on > 400 {
+		t.Errorf("%d of 1000 dispatches enabled at 30%%, want roughly 300", on)
+	}
+}
+
+func TestSummaryAndDescribe(t *testing.T) {
+	got := Summary([]Decision{{Flag: WarmPools, Enabled: true}, {Flag: AutoRecovery}})
+	if want := "auto_recovery=off warm_pools=on"; got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+	if got := Describe(&config.FeatureFlag{Enabled: true, Rigs: []string{"gastown"}, Percent: 25}); got != "on (rigs: gastown, 25%)" {
+		t.Errorf("Describe = %q", got)
+	}
+	if got := Describe(nil); got != "default" {
+		t.Errorf("Describe(nil) = %q", got)
+	}
+}
@@ -0,0 +1,315 @@
+// Package federation aggregates read-only status from several Gas Towns.
+//
+// A "capital" town lists its member towns in mayor/federation.json. Each
+// member exposes a compact summary over its dashboard HTTP API
+// (GET /api/federation/summary); the capital fetches those summaries
+// concurrently and rolls them up into a fleet-level view.
+package federation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// ConfigFileName is the federation config file name within the mayor directory.
+const ConfigFileName = "federation.json"
+
+// CurrentConfigVersion is the current schema version for Config.
+const CurrentConfigVersion = 1
+
+// SummaryPath is the dashboard API path that serves a town's summary.
+const SummaryPath = "/api/federation/summary"
+
+// DefaultFetchTimeout bounds a single member fetch.
+const DefaultFetchTimeout = 10 * time.Second
+
+// Member is a town participating in the federation.
+type Member struct {
+	// Name is a short label for the town (e.g., "laptop", "buildbox").
+	Name string `json:"name"`
+
+	// URL is the base URL of the town's dashboard (e.g., "http://buildbox:8080").
+	URL string `json:"url"`
+}
+
+// Config is the structure of mayor/federation.json.
+type Config struct {
+	Type    string   `json:"type"` // "federation"
+	Version int      `json:"version"`
+	Members []Member `json:"members"`
+}
+
+// TownSummary is the read-only status a town reports to the capital.
+type TownSummary struct {
+	Name            string    `json:"name"`
+	Rigs            int       `json:"rigs"`
+	Polecats        int       `json:"polecats"`
+	Crew            int       `json:"crew"`
+	AgentsRunning   int       `json:"agents_running"`
+	AgentsWorking   int       `json:"agents_working"`
+	OpenEscalations int       `json:"open_escalations"`
+	LastBackup      time.Time `json:"last_backup,omitempty"`
+	GeneratedAt     time.Time `json:"generated_at"`
+}
+
+// Utilization returns the fraction of running agents that have work hooked.
+func (s TownSummary) Utilization() float64 {
+	if s.AgentsRunning == 0 {
+		return 0
+	}
+	return float64(s.AgentsWorking) / float64(s.AgentsRunning)
+}
+
+// MemberStatus is the result of fetching one member's summary.
+type MemberStatus struct {
+	Member  Member       `json:"member"`
+	Summary *TownSummary `json:"summary,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// FleetStatus is the aggregated view across all members.
+type FleetStatus struct {
+	Members []MemberStatus `json:"members"`
+	Totals  TownSummary    `json:"totals"`
+	// Unreachable counts members whose summary could not be fetched.
+	Unreachable int `json:"unreachable"`
+}
+
+// ConfigPath returns the path to the federation config for a town.
+func ConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "mayor", ConfigFileName)
+}
+
+// LoadConfig loads the federation config. Returns an empty config if the
+// file does not exist.
+func LoadConfig(townRoot string) (*Config, error) {
+	data, err := os.ReadFile(ConfigPath(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{Type: "federation", Version: CurrentConfigVersion}, nil
+		}
+		return nil, fmt.Errorf("reading federation config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing federation config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// SaveConfig writes the federation config atomically.
+func SaveConfig(townRoot string, cfg *Config) error {
+	if cfg.Type == "" {
+		cfg.Type = "federation"
+	}
+	if cfg.Version == 0 {
+		cfg.Version = CurrentConfigVersion
+	}
+	return util.EnsureDirAndWriteJSON(ConfigPath(townRoot), cfg)
+}
+
+// AddMember adds or replaces a member by name.
+func (c *Config) AddMember(m Member) error {
+	if m.Name == "" {
+		return fmt.Errorf("member name is required")
+	}
+	if !strings.HasPrefix(m.URL, "http://") && !strings.HasPrefix(m.URL, "https://") {
+		return fmt.Errorf("member URL must start with http:// or https://: %q", m.URL)
+	}
+	m.URL = strings.TrimRight(m.URL, "/")
+	for i := range c.Members {
+		if c.Members[i].Name == m.Name {
+			c.Members[i] = m
+			return nil
+		}
+	}
+	c.Members = append(c.Members, m)
+	return nil
+}
+
+// RemoveMember removes a member by name. Returns false if it was not present.
+func (c *Config) RemoveMember(name string) bool {
+	for i := range c.Members {
+		if c.Members[i].Name == name {
+			c.Members = append(c.Members[:i], c.Members[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Fetch retrieves a single member's summary.
+func Fetch(ctx context.Context, client *http.Client, m Member) (*TownSummary, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.URL+SummaryPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var summary TownSummary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		return nil, fmt.Errorf("decoding summary: %w", err)
+	}
+	if summary.Name == "" {
+		summary.Name = m.Name
+	}
+	return &summary, nil
+}
+
+// Collect fetches all members concurrently and aggregates the results.
+// Unreachable members are reported individually and never fail the whole call.
+func Collect(ctx context.Context, client *http.Client, members []Member) *FleetStatus {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultFetchTimeout}
+	}
+
+	results := make([]MemberStatus, len(members))
+	var wg sync.WaitGroup
+	for i, m := range members {
+		wg.Add(1)
+		go func(i int, m Member) {
+			defer wg.Done()
+			results[i] = MemberStatus{Member: m}
+			summary, err := Fetch(ctx, client, m)
+			if err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+			results[i].Summary = summary
+		}(i, m)
+	}
+	wg.Wait()
+
+	return Aggregate(results)
+}
+
+// Aggregate rolls member results up into fleet totals.
+func Aggregate(results []MemberStatus) *FleetStatus {
+	fleet := &FleetStatus{Members: results}
+	fleet.Totals.Name = "fleet"
+	for _, r := range results {
+		if r.Summary == nil {
+			fleet.Unreachable++
+			continue
+		}
+		s := r.Summary
+		fleet.Totals.Rigs += s.Rigs
+		fleet.Totals.Polecats += s.Polecats
+		fleet.Totals.Crew += s.Crew
+		fleet.Totals.AgentsRunning += s.AgentsRunning
+		fleet.Totals.AgentsWorking += s.AgentsWorking
+		fleet.Totals.OpenEscalations += s.OpenEscalations
+		// Fleet-level LastBackup is the OLDEST member backup: the fleet is
+		// only as well backed up as its least recently backed-up town.
+		if !s.LastBackup.IsZero() && (fleet.Totals.LastBackup.IsZero() || s.LastBackup.Before(fleet.Totals.LastBackup)) {
+			fleet.Totals.LastBackup = s.LastBackup
+		}
+	}
+	sort.SliceStable(fleet.Members, func(i, j int) bool {
+		return fleet.Members[i].Member.Name < fleet.Members[j].Member.Name
+	})
+	fleet.Totals.GeneratedAt = time.Now()
+	return fleet
+}
+
+// LastBackupTime returns the modification time of the newest file under the
+// town's .dolt-backup directory, or the zero time if there are no backups.
+func LastBackupTime(townRoot string) time.Time {
+	var newest time.Time
+	_ = filepath.Walk(filepath.Join(townRoot, ".dolt-backup"), func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !info.IsDir() && info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+		return nil
+	})
+	return newest
+}
+
+// statusJSON is the subset of `gt status --json` used to build a summary.
+type statusJSON struct {
+	Name   string `json:"name"`
+	Agents []struct {
+		Running bool `json:"running"`
+		HasWork bool `json:"has_work"`
+	} `json:"agents"`
+	Rigs []struct {
+		Agents []struct {
+			Running bool `json:"running"`
+			HasWork bool `json:"has_work"`
+		} `json:"agents"`
+	} `json:"rigs"`
+	Summary struct {
+		RigCount     int `json:"rig_count"`
+		PolecatCount int `json:"polecat_count"`
+		CrewCount    int `json:"crew_count"`
+	} `json:"summary"`
+}
+
+// SummarizeStatus builds a TownSummary from `gt status --json` output and
+// `gt escalate list --json` output. escalationsOut may be empty. Only the
+// first JSON value of each input is decoded, so trailing stderr noise
+// appended by the caller is tolerated.
+func SummarizeStatus(statusOut, escalationsOut []byte) (*TownSummary, error) {
+	var st statusJSON
+	if err := json.NewDecoder(bytes.NewReader(statusOut)).Decode(&st); err != nil {
+		return nil, fmt.Errorf("parsing status: %w", err)
+	}
+
+	s := &TownSummary{
+		Name:        st.Name,
+		Rigs:        st.Summary.RigCount,
+		Polecats:    st.Summary.PolecatCount,
+		Crew:        st.Summary.CrewCount,
+		GeneratedAt: time.Now(),
+	}
+	count := func(running, hasWork bool) {
+		if running {
+			s.AgentsRunning++
+			if hasWork {
+				s.AgentsWorking++
+			}
+		}
+	}
+	for _, a := range st.Agents {
+		count(a.Running, a.HasWork)
+	}
+	for _, r := range st.Rigs {
+		for _, a := range r.Agents {
+			count(a.Running, a.HasWork)
+		}
+	}
+
+	if len(strings.TrimSpace(string(escalationsOut))) > 0 {
+		var escalations []json.RawMessage
+		if err := json.NewDecoder(bytes.NewReader(escalationsOut)).Decode(&escalations); err != nil {
+			return nil, fmt.Errorf("parsing escalations: %w", err)
+		}
+		s.OpenEscalations = len(escalations)
+	}
+	return s, nil
+}
@@ -0,0 +1,141 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+
+	cfg, err := LoadConfig(townRoot)
+	if err != nil {
+		t.Fatalf("LoadConfig on missing file: %v", err)
+	}
+	if len(cfg.Members) != 0 {
+		t.Fatalf("expected empty config, got %d members", len(cfg.Members))
+	}
+
+	if err := cfg.AddMember(Member{Name: "laptop", URL: "http://laptop:8080/"}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+	if err := cfg.AddMember(Member{Name: "buildbox", URL: "http://buildbox:8080"}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+	// Re-adding replaces rather than duplicates.
+	if err := cfg.AddMember(Member{Name: "laptop", URL: "http://laptop:9090"}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+	if err := SaveConfig(townRoot, cfg); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+
+	loaded, err := LoadConfig(townRoot)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(loaded.Members) != 2 {
+		t.Fatalf("expected 2 members, got %d", len(loaded.Members))
+	}
+	if loaded.Members[0].URL != "http://laptop:9090" {
+		t.Errorf("laptop URL = %q, want replaced URL without trailing slash", loaded.Members[0].URL)
+	}
+
+	if !loaded.RemoveMember("buildbox") {
+		t.Error("RemoveMember(buildbox) = false, want true")
+	}
+	if loaded.RemoveMember("missing") {
+		t.Error("RemoveMember(missing) = true, want false")
+	}
+}
+
+func TestAddMemberValidation(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.AddMember(Member{Name: "", URL: "http://x"}); err == nil {
+		t.Error("expected error for empty name")
+	}
+	if err := cfg.AddMember(Member{Name: "x", URL: "buildbox:8080"}); err == nil {
+		t.Error("expected error for URL without scheme")
+	}
+}
+
+func TestCollectAggregatesAndToleratesFailures(t *testing.T) {
+	older := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+	newer := time.Now().Add(-10 * time.Minute).UTC().Truncate(time.Second)
+
+	serve := func(s TownSummary) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != SummaryPath {
+				http.NotFound(w, r)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(s)
+		}))
+	}
+	a := serve(TownSummary{Name: "a", Rigs: 2, AgentsRunning: 4, AgentsWorking: 3, OpenEscalations: 1, LastBackup: newer})
+	defer a.Close()
+	b := serve(TownSummary{Name: "b", Rigs: 1, AgentsRunning: 2, AgentsWorking: 1, LastBackup: older})
+	defer b.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	fleet := Collect(context.Background(), nil, []Member{
+		{Name: "b", URL: b.URL},
+		{Name: "broken", URL: broken.URL},
+		{Name: "a", URL: a.URL},
+	})
+
+	if fleet.Unreachable != 1 {
+		t.Errorf("Unreachable = %d, want 1", fleet.Unreachable)
+	}
+	if fleet.Totals.Rigs != 3 || fleet.Totals.AgentsRunning != 6 || fleet.Totals.AgentsWorking != 4 {
+		t.Errorf("unexpected totals: %+v", fleet.Totals)
+	}
+	if fleet.Totals.OpenEscalations != 1 {
+		t.Errorf("OpenEscalations = %d, want 1", fleet.Totals.OpenEscalations)
+	}
+	if !fleet.Totals.LastBackup.Equal(older) {
+		t.Errorf("fleet LastBackup = %v, want oldest member backup %v", fleet.Totals.LastBackup, older)
+	}
+	if fleet.Members[0].Member.Name != "a" || fleet.Members[2].Member.Name != "broken" {
+		t.Errorf("members not sorted by name: %v", fleet.Members)
+	}
+	if fleet.Members[2].Error == "" {
+		t.Error("expected error recorded for broken member")
+	}
+}
+
+func TestSummarizeStatus(t *testing.T) {
+	status := `{
+  "name": "hq",
+  "agents": [{"running": true, "has_work": false}],
+  "rigs": [{"agents": [{"running": true, "has_work": true}, {"running": false, "has_work": true}]}],
+  "summary": {"rig_count": 1, "polecat_count": 2, "crew_count": 1}
+}
+WARNING: trailing stderr noise`
+	escalations := `[{"id": "hq-1"}, {"id": "hq-2"}]`
+
+	s, err := SummarizeStatus([]byte(status), []byte(escalations))
+	if err != nil {
+		t.Fatalf("SummarizeStatus: %v", err)
+	}
+	if s.Name != "hq" || s.Rigs != 1 || s.Polecats != 2 || s.Crew != 1 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+	if s.AgentsRunning != 2 || s.AgentsWorking != 1 {
+		t.Errorf("AgentsRunning/Working = %d/%d, want 2/1", s.AgentsRunning, s.AgentsWorking)
+	}
+	if s.OpenEscalations != 2 {
+		t.Errorf("OpenEscalations = %d, want 2", s.OpenEscalations)
+	}
+
+	if _, err := SummarizeStatus([]byte(status), []byte("null")); err != nil {
+		t.Errorf("null escalations should be accepted: %v", err)
+	}
+}
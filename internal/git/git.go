@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 )
 
@@ -56,8 +57,9 @@ func moveDir(src, dest string) error {
 
 // Git wraps git operations for a working directory.
 type Git struct {
-	workDir string
-	gitDir  string // Optional: explicit git directory (for bare repos)
+	workDir    string
+	gitDir     string      // Optional: explicit git directory (for bare repos)
+	pushPolicy *PushPolicy // Optional: checked before Push (see SetPushPolicy)
 }
 
 // NewGit creates a new Git wrapper for the given directory.
@@ -164,6 +166,7 @@ type cloneOptions struct {
 	singleBranch bool   // Pass --single-branch to git clone (only fetch default branch)
 	depth        int    // Pass --depth N to git clone (shallow clone); 0 means full history
 	branch       string // Pass --branch <name> to git clone (checkout specific branch)
+	filter       string // Pass --filter=<spec> to git clone (partial clone, e.g. "blob:none")
 }
 
 // cloneInternal runs `git clone` in an isolated temp directory, moves the result
@@ -203,6 +206,9 @@ func (g *Git) cloneInternal(url, dest string, opts cloneOptions) error {
 	if opts.branch != "" {
 		args = append(args, "--branch", opts.branch)
 	}
+	if opts.filter != "" {
+		args = append(args, "--filter="+opts.filter)
+	}
 	if opts.reference != "" {
 		args = append(args, "--reference-if-able", opts.reference)
 	}
@@ -228,7 +234,7 @@ func (g *Git) cloneInternal(url, dest string, opts cloneOptions) error {
 		// Configure refspec so worktrees can fetch and see origin/* refs.
 		// For single-branch shallow clones, only set the config without
 		// fetching all branches (which would defeat the purpose of --single-branch).
-		return configureRefspec(dest, opts.singleBranch)
+		return configureRefspec(dest, opts.singleBranch, opts.depth > 0)
 	}
 	// Configure hooks path for Gas Town clones
 	if err := configureHooksPath(dest); err != nil {
@@ -268,6 +274,51 @@ func (g *Git) CloneBare(url, dest string) error {
 	return g.cloneInternal(url, dest, cloneOptions{bare: true, singleBranch: true, depth: 1})
 }
 
+// Partial clone modes accepted by PartialCloneFilter.
+const (
+	PartialCloneBlobless = "blobless" // all commits and trees, blobs on demand
+	PartialCloneTreeless = "treeless" // all commits, trees and blobs on demand
+)
+
+// PartialCloneFilter returns the git --filter spec for a partial clone mode.
+func PartialCloneFilter(mode string) (string, error) {
+	switch mode {
+	case PartialCloneBlobless:
+		return "blob:none", nil
+	case PartialCloneTreeless:
+		return "tree:0", nil
+	default:
+		return "", fmt.Errorf("unknown partial clone mode %q (want %s or %s)", mode, PartialCloneBlobless, PartialCloneTreeless)
+	}
+}
+
+// CloneBarePartial clones a repository as a bare partial clone: full commit
+// history, with the objects excluded by filter (a --filter spec such as
+// "blob:none") fetched from origin on demand. Unlike CloneBare it is not
+// shallow, so merge bases are always available to the refinery.
+// reference may be empty.
+func (g *Git) CloneBarePartial(url, dest, reference, filter string) error {
+	return g.cloneInternal(url, dest, cloneOptions{bare: true, reference: reference, singleBranch: true, filter: filter})
+}
+
+// IsPartialClone reports whether origin is a promisor remote, i.e. the
+// repo is a partial clone that fetches missing objects on demand.
+func (g *Git) IsPartialClone() bool {
+	out, err := g.run("config", "--get", "remote.origin.promisor")
+	return err == nil && out == "true"
+}
+
+// IsPromisorFetchError reports whether err is git failing to fetch an
+// object a partial clone left out (origin unreachable or the object gone),
+// as opposed to a failure of the operation itself.
+func IsPromisorFetchError(err error) bool {
+	var gitErr *GitError
+	if !errors.As(err, &gitErr) {
+		return false
+	}
+	return strings.Contains(gitErr.Stderr, "promisor remote")
+}
+
 // configureHooksPath sets core.hooksPath to use the repo's .githooks directory
 // if it exists. This ensures Gas Town agents use the pre-push hook that blocks
 // pushes to non-main branches (internal PRs are not allowed).
@@ -301,7 +352,10 @@ func (g *Git) ConfigureHooksPath() error {
 // When singleBranch is true, fetches only the default branch's ref instead of all
 // branches. This prevents failures on repos with many branches where a full fetch
 // would error with "some local refs could not be updated".
-func configureRefspec(repoPath string, singleBranch bool) error {
+//
+// When shallow is false (partial clones), the branch is fetched with full
+// history.
+func configureRefspec(repoPath string, singleBranch, shallow bool) error {
 	gitDir := repoPath
 	if _, err := os.Stat(filepath.Join(repoPath, ".git")); err == nil {
 		gitDir = filepath.Join(repoPath, ".git")
@@ -328,7 +382,11 @@ func configureRefspec(repoPath string, singleBranch bool) error {
 		headCmd.Stderr = &stderr
 		if err := headCmd.Run(); err != nil {
 			// Fallback: if HEAD is detached, try fetching all (shouldn't happen for clones)
-			fetchCmd := exec.Command("git", "--git-dir", gitDir, "fetch", "--depth", "1", "origin")
+			fetchArgs := []string{"--git-dir", gitDir, "fetch", "origin"}
+			if shallow {
+				fetchArgs = append(fetchArgs, "--depth", "1")
+			}
+			fetchCmd := exec.Command("git", fetchArgs...)
 			fetchCmd.Stderr = &stderr
 			if fetchErr := fetchCmd.Run(); fetchErr != nil {
 				return fmt.Errorf("fetching origin: %s", strings.TrimSpace(stderr.String()))
@@ -339,7 +397,11 @@ func configureRefspec(repoPath string, singleBranch bool) error {
 		branch := strings.TrimPrefix(headRef, "refs/heads/")  // e.g. "main"
 		refspec := branch + ":refs/remotes/origin/" + branch   // e.g. "main:refs/remotes/origin/main"
 
-		fetchCmd := exec.Command("git", "--git-dir", gitDir, "fetch", "--depth", "1", "origin", refspec)
+		fetchArgs := []string{"--git-dir", gitDir, "fetch", "origin", refspec}
+		if shallow {
+			fetchArgs = append(fetchArgs, "--depth", "1")
+		}
+		fetchCmd := exec.Command("git", fetchArgs...)
 		fetchCmd.Stderr = &stderr
 		if err := fetchCmd.Run(); err != nil {
 			return fmt.Errorf("fetching origin %s: %s", branch, strings.TrimSpace(stderr.String()))
@@ -441,8 +503,12 @@ func (g *Git) GetPushURL(remote string) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
-// Push pushes to the remote branch.
+// Push pushes to the remote branch, after checking the push policy if one
+// is set.
 func (g *Git) Push(remote, branch string, force bool) error {
+	if err := g.CheckPushPolicy(g.pushPolicy, remote, branch); err != nil {
+		return err
+	}
 	args := []string{"push", remote, branch}
 	if force {
 		args = append(args, "--force")
@@ -455,6 +521,9 @@ func (g *Git) Push(remote, branch string, force bool) error {
 // Used by gt mq integration land to set GT_INTEGRATION_LAND=1, which the
 // pre-push hook checks to allow integration branch content landing on main.
 func (g *Git) PushWithEnv(remote, branch string, force bool, env []string) error {
+	if err := g.CheckPushPolicy(g.pushPolicy, remote, branch); err != nil {
+		return err
+	}
 	args := []string{"push", remote, branch}
 	if force {
 		args = append(args, "--force")
@@ -715,6 +784,10 @@ func (g *Git) CheckConflicts(source, target string) ([]string, error) {
 	if err := g.Checkout(target); err != nil {
 		return nil, fmt.Errorf("checkout target %s: %w", target, err)
 	}
+	// git checkout and merge leave submodule working trees where they were;
+	// keep them at the commits the target records so the test merge (and
+	// whatever runs after it) sees a consistent tree.
+	defer func() { _ = InitSubmodules(g.workDir) }()
 
 	// Attempt test merge with --no-commit --no-ff
 	// We need to capture both stdout and stderr to detect conflicts
@@ -785,6 +858,89 @@ func (g *Git) GetConflictingFiles() ([]string, error) {
 	return result, nil
 }
 
+// ConflictHunks returns the conflict-marked regions that merging source into
+// target would produce, per file, with a few lines of context — enough for
+// an agent to start resolving without re-running the merge. It uses
+// "git merge-tree --write-tree" (git 2.38+), so no worktree is touched.
+// Output is capped at maxLines lines. It returns "" if the merge is clean.
+func (g *Git) ConflictHunks(source, target string, maxLines int) (string, error) {
+	tree, err := g.run("merge-tree", "--write-tree", "--name-only", target, source)
+	if err == nil {
+		return "", nil // clean merge
+	}
+	var gitErr *GitError
+	if !errors.As(err, &gitErr) || gitErr.Stdout == "" {
+		return "", err
+	}
+	// Exit status 1 output: the merged tree's OID, the conflicted paths,
+	// then a blank line and informational messages.
+	lines := strings.Split(gitErr.Stdout, "\n")
+	tree = lines[0]
+	var files []string
+	for _, l := range lines[1:] {
+		if l == "" {
+			break
+		}
+		if len(files) == 0 || files[len(files)-1] != l {
+			files = append(files, l)
+		}
+	}
+
+	const context = 3
+	var b strings.Builder
+	written := 0
+	for _, file := range files {
+		content, err := g.run("cat-file", "-p", tree+":"+file)
+		if err != nil {
+			continue // e.g. modify/delete conflicts leave no merged file
+		}
+		fileLines := strings.Split(content, "\n")
+		var keep []bool
+		inConflict := false
+		for i, l := range fileLines {
+			if strings.HasPrefix(l, "<<<<<<< ") {
+				inConflict = true
+				if keep == nil {
+					keep = make([]bool, len(fileLines))
+				}
+				for k := max(0, i-context); k < i; k++ {
+					keep[k] = true
+				}
+			}
+			if inConflict {
+				keep[i] = true
+			}
+			if inConflict && strings.HasPrefix(l, ">>>>>>> ") {
+				inConflict = false
+				for k := i + 1; k < min(len(fileLines), i+1+context); k++ {
+					keep[k] = true
+				}
+			}
+		}
+		if keep == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "--- %s\n", file)
+		prev := -1
+		for i, k := range keep {
+			if !k {
+				continue
+			}
+			if written >= maxLines {
+				b.WriteString("... (truncated)\n")
+				return b.String(), nil
+			}
+			if prev >= 0 && i > prev+1 {
+				b.WriteString("...\n")
+			}
+			fmt.Fprintf(&b, "%d: %s\n", i+1, fileLines[i])
+			prev = i
+			written++
+		}
+	}
+	return b.String(), nil
+}
+
 // AbortRebase aborts a rebase in progress.
 func (g *Git) AbortRebase() error {
 	_, err := g.run("rebase", "--abort")
@@ -1007,6 +1163,28 @@ func (g *Git) WorktreeAddExistingForce(path, branch string) error {
 	return InitSubmodules(path)
 }
 
+// WorktreeAddSparse creates a worktree that checks out only dirs (cone-mode
+// sparse checkout), so files outside them are never written, or, in a
+// partial clone, fetched. With a branch name the branch is created at ref;
+// without one, HEAD is detached at ref.
+func (g *Git) WorktreeAddSparse(path, branch, ref string, dirs []string) error {
+	if _, err := g.run("worktree", "add", "--no-checkout", "--detach", path, ref); err != nil {
+		return err
+	}
+	wt := NewGit(path)
+	if err := wt.SparseCheckoutSet(dirs); err != nil {
+		return err
+	}
+	return wt.ResetWorktreeTo(branch, ref)
+}
+
+// SparseCheckoutSet limits the worktree to dirs (cone-mode sparse checkout).
+// In a linked worktree the setting is per-worktree.
+func (g *Git) SparseCheckoutSet(dirs []string) error {
+	_, err := g.run(append([]string{"sparse-checkout", "set", "--cone"}, dirs...)...)
+	return err
+}
+
 // IsSparseCheckoutConfigured checks if sparse checkout is enabled for a given repo/worktree.
 // This is used by doctor to detect legacy sparse checkout configurations that should be removed.
 func IsSparseCheckoutConfigured(repoPath string) bool {
@@ -1044,6 +1222,57 @@ func (g *Git) WorktreePrune() error {
 	return err
 }
 
+// WorktreeMove moves a worktree to a new path, keeping it registered.
+// The new path's parent must exist and the path itself must not.
+// git refuses to move worktrees with submodules, so they are deinitialized
+// and their repos parked outside the worktree's git dir during the move; a
+// later InitSubmodules (e.g. via ResetWorktreeTo) reuses them instead of
+// cloning again.
+func (g *Git) WorktreeMove(from, to string) error {
+	if _, err := os.Stat(filepath.Join(from, ".gitmodules")); err != nil {
+		_, err := g.run("worktree", "move", from, to)
+		return err
+	}
+	wt := NewGit(from)
+	adminDir, err := wt.run("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return err
+	}
+	if _, err := wt.run("submodule", "deinit", "--all", "--force"); err != nil {
+		return err
+	}
+	modules := filepath.Join(adminDir, "modules")
+	// Park in the common git dir: under worktrees/, git worktree prune
+	// would take it for a stale worktree.
+	parked := filepath.Join(filepath.Dir(filepath.Dir(adminDir)), "parked-modules-"+filepath.Base(adminDir))
+	if err := os.Rename(modules, parked); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("parking submodule repos: %w", err)
+	} else if err == nil {
+		// The worktree's git dir keeps its name across the move.
+		defer func() { _ = os.Rename(parked, modules) }()
+	}
+	_, err = g.run("worktree", "move", from, to)
+	return err
+}
+
+// ResetWorktreeTo force-checks out ref and removes untracked and ignored
+// files, leaving a pristine checkout. With a branch name, the branch is
+// created (or reset) at ref; without one, HEAD is detached at ref.
+// Skips LFS smudge filter during checkout (see WorktreeAddFromRef).
+func (g *Git) ResetWorktreeTo(branch, ref string) error {
+	args := []string{"checkout", "--force", "--detach", ref}
+	if branch != "" {
+		args = []string{"checkout", "--force", "-B", branch, ref}
+	}
+	if _, err := g.runWithEnv(args, []string{"GIT_LFS_SKIP_SMUDGE=1"}); err != nil {
+		return err
+	}
+	if _, err := g.run("clean", "-ffdx"); err != nil {
+		return err
+	}
+	return InitSubmodules(g.workDir)
+}
+
 // Worktree represents a git worktree.
 type Worktree struct {
 	Path   string
@@ -1144,6 +1373,165 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// UniqueCommits returns the number of commits on branch whose changes are
+// not in upstream (git cherry). Unlike CommitsAhead, commits that were
+// squashed or rebased into upstream do not count.
+func (g *Git) UniqueCommits(upstream, branch string) (int, error) {
+	out, err := g.run("cherry", upstream, branch)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "+ ") {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ListRemoteBranches returns the remote-tracking branches of remote whose
+// names start with prefix (e.g., "polecat/"), without the remote prefix.
+func (g *Git) ListRemoteBranches(remote, prefix string) ([]string, error) {
+	out, err := g.run("for-each-ref", "--format=%(refname:short)", "refs/remotes/"+remote+"/"+prefix)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	var branches []string
+	for _, ref := range strings.Split(out, "\n") {
+		branches = append(branches, strings.TrimPrefix(ref, remote+"/"))
+	}
+	return branches, nil
+}
+
+// CreateBundle writes a git bundle of revs (e.g., "polecat/x", "^main") to
+// path, so the commits can be restored with git fetch <path> after the
+// refs are deleted.
+func (g *Git) CreateBundle(path string, revs ...string) error {
+	_, err := g.run(append([]string{"bundle", "create", path}, revs...)...)
+	return err
+}
+
+// DiffFromMergeBase returns the changes branch makes relative to where it
+// forked from base (git diff base...branch).
+func (g *Git) DiffFromMergeBase(base, branch string) (string, error) {
+	return g.run("diff", base+"..."+branch)
+}
+
+// Diff returns the patch of git diff for the given revisions (e.g.,
+// "main...branch").
+func (g *Git) Diff(revs ...string) (string, error) {
+	return g.run(append([]string{"diff", "--no-color"}, revs...)...)
+}
+
+// IsTracked reports whether path (relative to the work tree) is tracked.
+func (g *Git) IsTracked(path string) bool {
+	_, err := g.run("ls-files", "--error-unmatch", "--", path)
+	return err == nil
+}
+
+// ExcludeLocally adds pattern to the repository's info/exclude, so the file
+// is ignored in this clone (and its worktrees) without touching .gitignore.
+// A pattern already listed is not added again.
+func (g *Git) ExcludeLocally(pattern string) error {
+	excludePath, err := g.run("rev-parse", "--git-path", "info/exclude")
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(excludePath) {
+		excludePath = filepath.Join(g.workDir, excludePath)
+	}
+	data, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		pattern = "\n" + pattern
+	}
+	_, err = f.WriteString(pattern + "\n")
+	return err
+}
+
+// FileChange is one file's line counts in a diff.
+type FileChange struct {
+	Path    string
+	Added   int
+	Deleted int
+	Binary  bool // binary files have no line counts
+}
+
+// DiffNumstat returns per-file line counts of git diff for the given
+// revisions (e.g., "main...branch"). Renames are reported as a delete and
+// an add so every entry has a single path.
+func (g *Git) DiffNumstat(revs ...string) ([]FileChange, error) {
+	out, err := g.run(append([]string{"diff", "--numstat", "--no-renames"}, revs...)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseNumstat(out), nil
+}
+
+// parseNumstat parses git diff --numstat output.
+func parseNumstat(out string) []FileChange {
+	var changes []FileChange
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		fc := FileChange{Path: parts[2]}
+		if parts[0] == "-" && parts[1] == "-" {
+			fc.Binary = true
+		} else {
+			fc.Added, _ = strconv.Atoi(parts[0])
+			fc.Deleted, _ = strconv.Atoi(parts[1])
+		}
+		changes = append(changes, fc)
+	}
+	return changes
+}
+
+// CommitInfo is a commit's hash and message.
+type CommitInfo struct {
+	SHA     string
+	Subject string
+	Body    string
+}
+
+// Commits returns the commits in revRange (e.g., "main..branch"), oldest
+// first.
+func (g *Git) Commits(revRange string) ([]CommitInfo, error) {
+	out, err := g.run("log", "--reverse", "--format=%H%x1f%s%x1f%b%x1e", revRange)
+	if err != nil {
+		return nil, err
+	}
+	var commits []CommitInfo
+	for _, rec := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(rec), "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, CommitInfo{SHA: fields[0], Subject: fields[1], Body: strings.TrimSpace(fields[2])})
+	}
+	return commits, nil
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
@@ -1241,6 +1629,8 @@ type UncommittedWorkStatus struct {
 	HasUncommittedChanges bool
 	StashCount            int
 	UnpushedCommits       int
+	// UnpushedSubmoduleCommits counts submodule commits on no remote.
+	UnpushedSubmoduleCommits int
 	// Details for error messages
 	ModifiedFiles   []string
 	UntrackedFiles  []string
@@ -1248,7 +1638,7 @@ type UncommittedWorkStatus struct {
 
 // Clean returns true if there is no uncommitted work.
 func (s *UncommittedWorkStatus) Clean() bool {
-	return !s.HasUncommittedChanges && s.StashCount == 0 && s.UnpushedCommits == 0
+	return !s.HasUncommittedChanges && s.StashCount == 0 && s.UnpushedCommits == 0 && s.UnpushedSubmoduleCommits == 0
 }
 
 // CleanExcludingBeads returns true if the only uncommitted changes are .beads/ files.
@@ -1256,7 +1646,7 @@ func (s *UncommittedWorkStatus) Clean() bool {
 // across worktrees and shouldn't block cleanup.
 func (s *UncommittedWorkStatus) CleanExcludingBeads() bool {
 	// Stashes and unpushed commits always count as uncommitted work
-	if s.StashCount > 0 || s.UnpushedCommits > 0 {
+	if s.StashCount > 0 || s.UnpushedCommits > 0 || s.UnpushedSubmoduleCommits > 0 {
 		return false
 	}
 
@@ -1294,6 +1684,9 @@ func (s *UncommittedWorkStatus) String() string {
 	if s.UnpushedCommits > 0 {
 		issues = append(issues, fmt.Sprintf("%d unpushed commit(s)", s.UnpushedCommits))
 	}
+	if s.UnpushedSubmoduleCommits > 0 {
+		issues = append(issues, fmt.Sprintf("%d unpushed submodule commit(s)", s.UnpushedSubmoduleCommits))
+	}
 	if len(issues) == 0 {
 		return "clean"
 	}
@@ -1328,6 +1721,13 @@ func (g *Git) CheckUncommittedWork() (*UncommittedWorkStatus, error) {
 	}
 	status.UnpushedCommits = unpushed
 
+	// Check submodule commits that exist only in this worktree
+	subUnpushed, err := g.UnpushedSubmoduleCommits()
+	if err != nil {
+		return nil, fmt.Errorf("checking submodule commits: %w", err)
+	}
+	status.UnpushedSubmoduleCommits = subUnpushed
+
 	return status, nil
 }
 
@@ -1517,6 +1917,45 @@ func InitSubmodules(repoPath string) error {
 	return nil
 }
 
+// UnpushedSubmoduleCommits counts commits checked out in the repo's
+// submodules (recursively) that no remote-tracking branch of the submodule
+// contains. Removing the worktree would lose them: each worktree keeps its
+// own submodule repos. Returns 0 for repos without submodules.
+func (g *Git) UnpushedSubmoduleCommits() (int, error) {
+	if _, err := os.Stat(filepath.Join(g.workDir, ".gitmodules")); err != nil {
+		return 0, nil
+	}
+	out, err := g.run("submodule", "foreach", "--quiet", "--recursive", "git rev-list --count HEAD --not --remotes")
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, line := range strings.Fields(out) {
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			return 0, fmt.Errorf("parsing submodule unpushed count %q: %w", line, err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// LFSPull downloads Git LFS content for the checked-out files, limited to
+// paths matching include patterns if any are given. Worktrees are created
+// with LFS pointer files (see WorktreeAddFromRef); this fills them in.
+// Objects are cached in the shared git dir, so later pulls in other
+// worktrees of the same repo are local.
+func (g *Git) LFSPull(include []string) error {
+	args := []string{"lfs", "pull"}
+	if len(include) > 0 {
+		args = append(args, "--include="+strings.Join(include, ","))
+	}
+	if _, err := g.run(args...); err != nil {
+		return fmt.Errorf("git lfs pull (is git-lfs installed?): %w", err)
+	}
+	return nil
+}
+
 // SubmoduleChanges detects submodule pointer changes between two refs.
 // Returns nil if no submodules changed or if the repo has no submodules.
 func (g *Git) SubmoduleChanges(base, head string) ([]SubmoduleChange, error) {
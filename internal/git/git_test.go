@@ -1,6 +1,7 @@
 package git
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -452,6 +453,45 @@ func TestCheckConflicts_WithConflict(t *testing.T) {
 	}
 }
 
+func TestConflictHunks(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	mainBranch, _ := g.CurrentBranch()
+
+	base := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	commit := func(content, msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, dir, "add", "file.txt")
+		runGit(t, dir, "commit", "-m", msg)
+	}
+	commit(base, "base")
+	runGit(t, dir, "checkout", "-b", "feature")
+	commit(strings.Replace(base, "e\n", "feature\n", 1), "feature")
+	runGit(t, dir, "checkout", mainBranch)
+	commit(strings.Replace(base, "e\n", "main\n", 1), "main")
+
+	hunks, err := g.ConflictHunks("feature", mainBranch, 50)
+	if err != nil {
+		t.Fatalf("ConflictHunks: %v", err)
+	}
+	for _, want := range []string{"--- file.txt", "2: b", "5: <<<<<<< ", "6: main", "8: feature", "12: h"} {
+		if !strings.Contains(hunks, want) {
+			t.Errorf("hunks missing %q:\n%s", want, hunks)
+		}
+	}
+	if strings.Contains(hunks, "1: a") {
+		t.Errorf("hunks include lines outside the context window:\n%s", hunks)
+	}
+
+	clean, err := g.ConflictHunks(mainBranch, mainBranch, 50)
+	if err != nil || clean != "" {
+		t.Errorf("ConflictHunks(clean) = %q, %v", clean, err)
+	}
+}
+
 // TestCloneBareHasOriginRefs verifies that after CloneBare, origin/* refs
 // are available for worktree creation. This was broken before the fix:
 // bare clones had refspec configured but no fetch was run, so origin/main
@@ -1554,3 +1594,262 @@ func TestClearPushURL(t *testing.T) {
 		t.Errorf("ClearPushURL (idempotent) should not error, got: %v", err)
 	}
 }
+
+func TestCloneBarePartialSparseWorktree(t *testing.T) {
+	tmp := t.TempDir()
+	remoteDir := filepath.Join(tmp, "remote")
+	for _, dir := range []string{"services/api", "services/web"} {
+		if err := os.MkdirAll(filepath.Join(remoteDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(remoteDir, dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, remoteDir, "init", "-b", "main")
+	runGit(t, remoteDir, "config", "user.email", "test@test.com")
+	runGit(t, remoteDir, "config", "user.name", "Test User")
+	runGit(t, remoteDir, "config", "uploadpack.allowFilter", "true")
+	runGit(t, remoteDir, "add", ".")
+	runGit(t, remoteDir, "commit", "-m", "initial")
+
+	filter, err := PartialCloneFilter(PartialCloneBlobless)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bareDir := filepath.Join(tmp, "bare.git")
+	if err := NewGit(tmp).CloneBarePartial("file://"+remoteDir, bareDir, "", filter); err != nil {
+		t.Fatalf("CloneBarePartial: %v", err)
+	}
+	bareGit := NewGitWithDir(bareDir, "")
+	if !bareGit.IsPartialClone() {
+		t.Error("IsPartialClone = false for a blobless clone")
+	}
+	if out, _ := exec.Command("git", "--git-dir", bareDir, "rev-parse", "--is-shallow-repository").Output(); strings.TrimSpace(string(out)) != "false" {
+		t.Errorf("partial clone should not be shallow, is-shallow-repository = %s", out)
+	}
+
+	worktreePath := filepath.Join(tmp, "worktree")
+	if err := bareGit.WorktreeAddSparse(worktreePath, "polecat/test", "origin/main", []string{"services/api"}); err != nil {
+		t.Fatalf("WorktreeAddSparse: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "services", "api", "main.go")); err != nil {
+		t.Errorf("sparse directory not checked out: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "services", "web")); !os.IsNotExist(err) {
+		t.Errorf("directory outside the sparse set was checked out (stat err %v)", err)
+	}
+	if branch, _ := NewGit(worktreePath).CurrentBranch(); branch != "polecat/test" {
+		t.Errorf("worktree branch = %q, want polecat/test", branch)
+	}
+	if !IsSparseCheckoutConfigured(worktreePath) {
+		t.Error("IsSparseCheckoutConfigured = false for sparse worktree")
+	}
+
+	// Sparse checkout is per-worktree: other worktrees get the full tree.
+	fullPath := filepath.Join(tmp, "full")
+	if err := bareGit.WorktreeAddDetached(fullPath, "origin/main"); err != nil {
+		t.Fatalf("WorktreeAddDetached: %v", err)
+	}
+	if IsSparseCheckoutConfigured(fullPath) {
+		t.Error("sparse checkout leaked into another worktree")
+	}
+	if _, err := os.Stat(filepath.Join(fullPath, "services", "web", "main.go")); err != nil {
+		t.Errorf("full worktree is missing files: %v", err)
+	}
+}
+
+func TestPartialCloneFilter(t *testing.T) {
+	for mode, want := range map[string]string{PartialCloneBlobless: "blob:none", PartialCloneTreeless: "tree:0"} {
+		if got, err := PartialCloneFilter(mode); err != nil || got != want {
+			t.Errorf("PartialCloneFilter(%q) = %q, %v; want %q", mode, got, err, want)
+		}
+	}
+	if _, err := PartialCloneFilter("shallow"); err == nil {
+		t.Error("PartialCloneFilter(shallow) should fail")
+	}
+}
+
+func TestIsPromisorFetchError(t *testing.T) {
+	promisor := &GitError{Command: "git", Stderr: "fatal: could not fetch 8b13789 from promisor remote"}
+	if !IsPromisorFetchError(fmt.Errorf("checkout target main: %w", promisor)) {
+		t.Error("wrapped promisor fetch error not detected")
+	}
+	if IsPromisorFetchError(&GitError{Command: "git", Stderr: "CONFLICT (content): Merge conflict in a.go"}) {
+		t.Error("merge conflict misdetected as promisor fetch error")
+	}
+}
+
+func TestUnpushedSubmoduleCommits(t *testing.T) {
+	parent, _ := initTestRepoWithSubmodule(t)
+	g := NewGit(parent)
+
+	if n, err := g.UnpushedSubmoduleCommits(); err != nil || n != 0 {
+		t.Fatalf("UnpushedSubmoduleCommits = %d, %v; want 0", n, err)
+	}
+
+	// A commit made in the submodule and never pushed exists only here.
+	subDir := filepath.Join(parent, "libs", "sub")
+	runGit(t, subDir, "config", "user.email", "test@test.com")
+	runGit(t, subDir, "config", "user.name", "Test User")
+	runGit(t, subDir, "commit", "--allow-empty", "-m", "local only")
+
+	if n, err := g.UnpushedSubmoduleCommits(); err != nil || n != 1 {
+		t.Fatalf("UnpushedSubmoduleCommits = %d, %v; want 1", n, err)
+	}
+	status, err := g.CheckUncommittedWork()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.UnpushedSubmoduleCommits != 1 || status.Clean() || status.CleanExcludingBeads() {
+		t.Errorf("CheckUncommittedWork = %+v, want 1 unpushed submodule commit and not clean", status)
+	}
+	if !strings.Contains(status.String(), "1 unpushed submodule commit(s)") {
+		t.Errorf("String() = %q, want unpushed submodule commit mentioned", status.String())
+	}
+}
+
+func TestWorktreeMoveWithSubmodules(t *testing.T) {
+	parent, _ := initTestRepoWithSubmodule(t)
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	g := NewGit(parent)
+	tmp := t.TempDir()
+	from := filepath.Join(tmp, "pooled")
+	if err := g.WorktreeAddDetached(from, "HEAD"); err != nil {
+		t.Fatalf("WorktreeAddDetached: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(from, "libs", "sub", "lib.go")); err != nil {
+		t.Fatalf("submodule not initialized in new worktree: %v", err)
+	}
+
+	to := filepath.Join(tmp, "claimed")
+	if err := g.WorktreeMove(from, to); err != nil {
+		t.Fatalf("WorktreeMove with submodules: %v", err)
+	}
+	if err := NewGit(to).ResetWorktreeTo("polecat/test", "HEAD"); err != nil {
+		t.Fatalf("ResetWorktreeTo: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(to, "libs", "sub", "lib.go")); err != nil {
+		t.Errorf("submodule not restored after move and reset: %v", err)
+	}
+}
+
+func TestUniqueCommits_IgnoresSquashedWork(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	mainBranch, _ := g.CurrentBranch()
+
+	if err := g.CreateBranch("polecat/Toast"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("polecat/Toast"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("a.txt"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("add a"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	tip, _ := g.Rev("HEAD")
+
+	// Land the same change on main as a different commit (like a squash merge).
+	if err := g.Checkout(mainBranch); err != nil {
+		t.Fatalf("Checkout main: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "other.txt"), []byte("other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("other.txt"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("other work"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if _, err := g.run("cherry-pick", tip); err != nil {
+		t.Fatalf("cherry-pick: %v", err)
+	}
+
+	if ahead, _ := g.CommitsAhead(mainBranch, "polecat/Toast"); ahead != 1 {
+		t.Errorf("CommitsAhead = %d, want 1", ahead)
+	}
+	unique, err := g.UniqueCommits(mainBranch, "polecat/Toast")
+	if err != nil {
+		t.Fatalf("UniqueCommits: %v", err)
+	}
+	if unique != 0 {
+		t.Errorf("UniqueCommits = %d, want 0 for squashed work", unique)
+	}
+
+	// A commit that never landed is unique.
+	if err := g.Checkout("polecat/Toast"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("b.txt"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("add b"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if unique, _ := g.UniqueCommits(mainBranch, "polecat/Toast"); unique != 1 {
+		t.Errorf("UniqueCommits = %d, want 1", unique)
+	}
+
+	bundle := filepath.Join(t.TempDir(), "toast.bundle")
+	if err := g.CreateBundle(bundle, "polecat/Toast", "^"+mainBranch); err != nil {
+		t.Fatalf("CreateBundle: %v", err)
+	}
+	if out, err := g.run("bundle", "list-heads", bundle); err != nil || !strings.Contains(out, "refs/heads/polecat/Toast") {
+		t.Errorf("bundle heads = %q, %v; want refs/heads/polecat/Toast", out, err)
+	}
+}
+
+func TestDiffNumstatAndCommits(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	mainBranch, _ := g.CurrentBranch()
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test\nmore\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bin.dat"), []byte{0, 1, 2, 0}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("."); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("feat: change\n\nWhy it changed."); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	files, err := g.DiffNumstat(mainBranch + "...feature")
+	if err != nil {
+		t.Fatalf("DiffNumstat: %v", err)
+	}
+	want := []FileChange{{Path: "README.md", Added: 1}, {Path: "bin.dat", Binary: true}}
+	if fmt.Sprint(files) != fmt.Sprint(want) {
+		t.Errorf("DiffNumstat = %+v, want %+v", files, want)
+	}
+
+	commits, err := g.Commits(mainBranch + "..feature")
+	if err != nil {
+		t.Fatalf("Commits: %v", err)
+	}
+	if len(commits) != 1 || commits[0].Subject != "feat: change" || commits[0].Body != "Why it changed." {
+		t.Errorf("Commits = %+v", commits)
+	}
+}
@@ -0,0 +1,190 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PushPolicy is a set of checks run on the commits an agent is about to
+// push. A zero PushPolicy allows everything.
+type PushPolicy struct {
+	// MaxFileSize rejects commits adding or modifying a file larger than
+	// this many bytes. Zero disables the check.
+	MaxFileSize int64
+
+	// ForbiddenPaths rejects commits adding or modifying matching files.
+	// Patterns without a slash match the file's base name, patterns with one
+	// match the whole path (path.Match syntax). A leading "!" re-allows
+	// paths matched by an earlier pattern.
+	ForbiddenPaths []string
+
+	// ConventionalCommits requires non-merge commit subjects of the form
+	// "type(scope)!: summary".
+	ConventionalCommits bool
+}
+
+// DefaultForbiddenPaths keeps secrets and credentials out of shared remotes.
+var DefaultForbiddenPaths = []string{
+	".env", ".env.*", "!.env.example", "!.env.sample", "!.env.template",
+	"*.pem", "*.key", "*.p12", "*.pfx",
+	"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519",
+	".netrc", ".npmrc", ".pypirc",
+	"credentials", "credentials.json", ".aws/credentials",
+}
+
+// conventionalSubject matches a Conventional Commits subject line.
+var conventionalSubject = regexp.MustCompile(`^(feat|fix|docs|style|refactor|perf|test|build|ci|chore|revert)(\([^)]+\))?!?: \S`)
+
+// PushPolicyViolation is one commit breaking one rule.
+type PushPolicyViolation struct {
+	Commit string // short SHA
+	Path   string // offending file, empty for message rules
+	Reason string
+}
+
+// PushPolicyError reports every violation on the commits being pushed,
+// worded so the pushing agent can fix its branch and retry.
+type PushPolicyError struct {
+	Ref        string
+	Violations []PushPolicyViolation
+}
+
+func (e *PushPolicyError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "push of %s blocked by the rig's push policy (%d violation(s)):\n", e.Ref, len(e.Violations))
+	for _, v := range e.Violations {
+		if v.Path != "" {
+			fmt.Fprintf(&b, "  %s %s: %s\n", v.Commit, v.Path, v.Reason)
+		} else {
+			fmt.Fprintf(&b, "  %s: %s\n", v.Commit, v.Reason)
+		}
+	}
+	b.WriteString("Rewrite the branch so no commit contains a violation, then push again. For example:\n")
+	b.WriteString("  git reset --soft <base>          # squash your commits back into the index\n")
+	b.WriteString("  git rm --cached <path>           # unstage forbidden or oversized files (add them to .gitignore)\n")
+	b.WriteString("  git commit -m \"fix(scope): summary\"")
+	return b.String()
+}
+
+// IsZero reports whether the policy checks nothing.
+func (p *PushPolicy) IsZero() bool {
+	return p == nil || (p.MaxFileSize <= 0 && len(p.ForbiddenPaths) == 0 && !p.ConventionalCommits)
+}
+
+// forbidden returns the pattern forbidding file, or "" if it is allowed.
+func (p *PushPolicy) forbidden(file string) string {
+	var match string
+	for _, pattern := range p.ForbiddenPaths {
+		allow := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+		name := path.Base(file)
+		if strings.Contains(pattern, "/") {
+			name = file
+		}
+		if ok, _ := path.Match(pattern, name); !ok {
+			continue
+		}
+		if allow {
+			match = ""
+		} else {
+			match = pattern
+		}
+	}
+	return match
+}
+
+// SetPushPolicy makes Push and PushWithEnv check the pushed commits against
+// policy first. Nil removes the policy.
+func (g *Git) SetPushPolicy(policy *PushPolicy) {
+	g.pushPolicy = policy
+}
+
+// PushPolicy returns the policy set by SetPushPolicy, or nil.
+func (g *Git) PushPolicy() *PushPolicy {
+	return g.pushPolicy
+}
+
+// CheckPushPolicy checks the commits that pushing ref (a branch or
+// "local:remote" refspec) to remote would publish: commits reachable from
+// the local ref that no remote-tracking ref of remote has yet. It returns a
+// *PushPolicyError listing every violation, or nil.
+func (g *Git) CheckPushPolicy(policy *PushPolicy, remote, ref string) error {
+	if policy.IsZero() {
+		return nil
+	}
+	local := strings.TrimPrefix(strings.SplitN(ref, ":", 2)[0], "+")
+	if local == "" {
+		return nil // deletion
+	}
+	out, err := g.run("log", "--format=%H%x1f%P%x1f%s", local, "--not", "--remotes="+remote)
+	if err != nil {
+		return fmt.Errorf("listing commits to push: %w", err)
+	}
+
+	perr := &PushPolicyError{Ref: ref}
+	blobSizes := make(map[string]int64)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		sha, parents, subject := fields[0], strings.Fields(fields[1]), fields[2]
+		short := sha[:min(len(sha), 8)]
+
+		if policy.ConventionalCommits && len(parents) <= 1 && !conventionalSubject.MatchString(subject) {
+			perr.Violations = append(perr.Violations, PushPolicyViolation{
+				Commit: short,
+				Reason: fmt.Sprintf("subject %q is not a conventional commit (e.g. \"fix(parser): handle empty input\")", subject),
+			})
+		}
+		if len(parents) > 1 || (policy.MaxFileSize <= 0 && len(policy.ForbiddenPaths) == 0) {
+			continue
+		}
+
+		changes, err := g.run("diff-tree", "-r", "--root", "--no-commit-id", "--no-renames", "--diff-filter=AM", "-z", sha)
+		if err != nil {
+			return fmt.Errorf("listing files of %s: %w", short, err)
+		}
+		// -z raw output: ":<mode> <mode> <sha> <sha> <status>\0<path>\0" per file.
+		parts := strings.Split(changes, "\x00")
+		for i := 0; i+1 < len(parts); i += 2 {
+			meta := strings.Fields(parts[i])
+			file := parts[i+1]
+			if len(meta) < 5 {
+				continue
+			}
+			if pattern := policy.forbidden(file); pattern != "" {
+				perr.Violations = append(perr.Violations, PushPolicyViolation{
+					Commit: short, Path: file,
+					Reason: fmt.Sprintf("forbidden file (matches %q); secrets and credentials must not be pushed", pattern),
+				})
+			}
+			if policy.MaxFileSize <= 0 || meta[1] == "160000" {
+				continue // no size limit, or a submodule
+			}
+			blob := meta[3]
+			size, ok := blobSizes[blob]
+			if !ok {
+				s, err := g.run("cat-file", "-s", blob)
+				if err != nil {
+					return fmt.Errorf("sizing %s: %w", file, err)
+				}
+				size, _ = strconv.ParseInt(s, 10, 64)
+				blobSizes[blob] = size
+			}
+			if size > policy.MaxFileSize {
+				perr.Violations = append(perr.Violations, PushPolicyViolation{
+					Commit: short, Path: file,
+					Reason: fmt.Sprintf("%d KB exceeds the %d KB file size limit", size/1024, policy.MaxFileSize/1024),
+				})
+			}
+		}
+	}
+	if len(perr.Violations) > 0 {
+		return perr
+	}
+	return nil
+}
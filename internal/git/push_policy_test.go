@@ -0,0 +1,87 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckPushPolicy(t *testing.T) {
+	localDir, _, mainBranch := initTestRepoWithRemote(t)
+	g := NewGit(localDir)
+	runGit(t, localDir, "checkout", "-b", "polecat/toast")
+
+	commit := func(file, content, msg string) {
+		t.Helper()
+		p := filepath.Join(localDir, file)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, localDir, "add", file)
+		runGit(t, localDir, "commit", "-m", msg)
+	}
+	commit("main.go", "package main\n", "feat(cli): add entrypoint")
+	commit("config/.env.example", "TOKEN=\n", "docs: document env")
+
+	policy := &PushPolicy{
+		MaxFileSize:         1024,
+		ForbiddenPaths:      DefaultForbiddenPaths,
+		ConventionalCommits: true,
+	}
+	if err := g.CheckPushPolicy(policy, "origin", "polecat/toast:polecat/toast"); err != nil {
+		t.Fatalf("clean branch rejected: %v", err)
+	}
+
+	commit("config/.env", "TOKEN=secret\n", "add config")
+	commit("assets/big.bin", strings.Repeat("x", 4096), "chore: add asset")
+
+	err := g.CheckPushPolicy(policy, "origin", "polecat/toast")
+	var perr *PushPolicyError
+	if !errors.As(err, &perr) {
+		t.Fatalf("CheckPushPolicy() = %v, want *PushPolicyError", err)
+	}
+	var got []string
+	for _, v := range perr.Violations {
+		got = append(got, v.Path+"|"+strings.SplitN(v.Reason, " ", 2)[0])
+	}
+	want := []string{"assets/big.bin|4", "|subject", "config/.env|forbidden"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("violations = %v, want %v", got, want)
+	}
+	if !strings.Contains(err.Error(), "git rm --cached") {
+		t.Errorf("error lacks fix instructions:\n%v", err)
+	}
+
+	// Push enforces the policy set on the Git; the default branch itself
+	// has nothing new to push.
+	g.SetPushPolicy(policy)
+	if err := g.Push("origin", "polecat/toast", false); !errors.As(err, &perr) {
+		t.Errorf("Push() = %v, want *PushPolicyError", err)
+	}
+	if err := g.Push("origin", mainBranch, false); err != nil {
+		t.Errorf("Push(%s) = %v", mainBranch, err)
+	}
+}
+
+func TestPushPolicyForbidden(t *testing.T) {
+	p := &PushPolicy{ForbiddenPaths: DefaultForbiddenPaths}
+	tests := map[string]bool{
+		".env":             true,
+		"svc/.env.local":   true,
+		".env.example":     false,
+		"certs/server.pem": true,
+		"home/.aws/config": false,
+		".aws/credentials": true,
+		"docs/keys.md":     false,
+	}
+	for file, want := range tests {
+		if got := p.forbidden(file) != ""; got != want {
+			t.Errorf("forbidden(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// Package instructions generates the Gas Town section of an agent's
+// instructions file (CLAUDE.md, AGENTS.md, ...) in its worktree: who the
+// agent is, the gt commands its role uses, the rig's conventions, and what
+// currently restricts it. The section is rendered from a template the town
+// can override and rewritten whenever a session starts, so operating
+// instructions are managed centrally instead of hand-edited per rig.
+//
+// Generation is opt-in (town settings "role_instructions"). gt prime remains
+// the source of the full role context; the generated section is what the
+// agent's CLI loads on its own before any hook runs.
+package instructions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/maintenance"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/templates"
+)
+
+// Markers delimit the generated section. Text outside them is the
+// project's or the user's and is preserved.
+const (
+	BeginMarker = "<!-- BEGIN GAS TOWN: generated by gt, edits inside are overwritten -->"
+	EndMarker   = "<!-- END GAS TOWN -->"
+)
+
+// ConventionsFile is a rig's free-form conventions document, included in its
+// agents' instructions: <rig>/settings/conventions.md.
+const ConventionsFile = "conventions.md"
+
+// Agent identifies the agent whose instructions are generated.
+type Agent struct {
+	TownRoot string
+	Rig      string // empty for town-level agents
+	Role     string
+	Name     string // polecat or crew name; empty for singletons
+	WorkDir  string
+}
+
+// Outcome is what Generate or Write did.
+type Outcome string
+
+const (
+	OutcomeCreated   Outcome = "created"   // the file did not exist
+	OutcomeUpdated   Outcome = "updated"   // the section was added or rewritten
+	OutcomeUnchanged Outcome = "unchanged" // the section was up to date
+	OutcomeTracked   Outcome = "tracked"   // the project tracks the file; left alone
+	OutcomeDisabled  Outcome = "disabled"  // generation is off for the role
+)
+
+// roleCommands are the gt commands each role's instructions list.
+var roleCommands = map[string][]templates.InstructionsCommand{
+	"polecat": {
+		{Usage: "hook", Purpose: "show the work assigned to you"},
+		{Usage: "notes append <issue> \"...\"", Purpose: "record progress so it survives your context"},
+		{Usage: "done", Purpose: "submit finished work to the merge queue and exit; run it the moment you are done"},
+		{Usage: "escalate \"...\"", Purpose: "ask for help when you are blocked"},
+		{Usage: "mail inbox", Purpose: "read messages from the witness and mayor"},
+	},
+	"crew": {
+		{Usage: "hook", Purpose: "show the work on your hook"},
+		{Usage: "mail inbox", Purpose: "read your messages"},
+		{Usage: "sling <issue> <rig>", Purpose: "dispatch work to a polecat"},
+		{Usage: "notes append <issue> \"...\"", Purpose: "record progress on an issue"},
+		{Usage: "handoff", Purpose: "hand off to a fresh session when your context is full"},
+	},
+	"witness": {
+		{Usage: "polecat list", Purpose: "see the rig's polecats"},
+		{Usage: "notes show <issue>", Purpose: "read a polecat's progress"},
+		{Usage: "nudge <agent> \"...\"", Purpose: "prod a stalled agent"},
+		{Usage: "escalate \"...\"", Purpose: "raise what you cannot resolve"},
+	},
+	"refinery": {
+		{Usage: "mq list", Purpose: "see the merge queue"},
+		{Usage: "diff-summary <issue>", Purpose: "summarize a change for review"},
+		{Usage: "escalate \"...\"", Purpose: "raise merges you cannot land"},
+	},
+	"mayor": {
+		{Usage: "status", Purpose: "see the town at a glance"},
+		{Usage: "sling <issue> <rig>", Purpose: "dispatch work"},
+		{Usage: "convoy list", Purpose: "track batches of work"},
+		{Usage: "mail inbox", Purpose: "read escalations and reports"},
+	},
+	"deacon": {
+		{Usage: "status", Purpose: "see the town at a glance"},
+		{Usage: "nudge <agent> \"...\"", Purpose: "prod an unresponsive agent"},
+		{Usage: "escalate \"...\"", Purpose: "raise what you cannot resolve"},
+	},
+}
+
+// Enabled reports whether the town generates instructions for role.
+func Enabled(townRoot, role string) bool {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return false
+	}
+	return settings.RoleInstructions.Enabled(role)
+}
+
+// Render renders an agent's instructions section (without markers).
+func Render(a Agent) (string, error) {
+	return templates.RenderInstructions(a.TownRoot, BuildData(a, time.Now()))
+}
+
+// BuildData gathers what an agent's instructions say.
+func BuildData(a Agent, now time.Time) templates.InstructionsData {
+	data := templates.InstructionsData{
+		Role:      a.Role,
+		RigName:   a.Rig,
+		AgentName: a.Name,
+		Identity:  identity(a),
+		Commands:  roleCommands[a.Role],
+	}
+	if a.Rig != "" {
+		rigPath := filepath.Join(a.TownRoot, a.Rig)
+		data.Conventions = rigConventions(rigPath)
+		if doc, err := os.ReadFile(filepath.Join(rigPath, "settings", ConventionsFile)); err == nil {
+			data.ConventionsDoc = strings.TrimSpace(string(doc))
+		}
+	}
+	data.Constraints = constraints(a, now)
+	return data
+}
+
+// identity describes the agent in one phrase.
+func identity(a Agent) string {
+	who := "the " + a.Role
+	if a.Name != "" {
+		who = a.Role + " " + a.Name
+	}
+	if a.Rig != "" {
+		return who + " of rig " + a.Rig
+	}
+	return who + " of this Gas Town"
+}
+
+// rigConventions lists the rig's branch and check commands.
+func rigConventions(rigPath string) []string {
+	var out []string
+	if cfg, err := rig.LoadRigConfig(rigPath); err == nil && cfg.DefaultBranch != "" {
+		out = append(out, fmt.Sprintf("Work lands on `%s` through the merge queue; never push to it directly.", cfg.DefaultBranch))
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.MergeQueue == nil {
+		return out
+	}
+	mq := settings.MergeQueue
+	for _, c := range []struct{ what, cmd string }{
+		{"Set up", mq.SetupCommand},
+		{"Build", mq.BuildCommand},
+		{"Type-check", mq.TypecheckCommand},
+		{"Lint", mq.LintCommand},
+		{"Test", mq.TestCommand},
+	} {
+		if c.cmd != "" {
+			out = append(out, fmt.Sprintf("%s: `%s`", c.what, c.cmd))
+		}
+	}
+	return out
+}
+
+// constraints lists what currently restricts the agent.
+func constraints(a Agent, now time.Time) []string {
+	var out []string
+	if state, err := maintenance.Get(a.TownRoot, now); err == nil && state != nil {
+		reason := state.Reason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		if state.Frozen {
+			out = append(out, fmt.Sprintf("The town is frozen (%s): do not dispatch, nudge, merge, or run `%s done`; wait for a human.", reason, templates.CmdName()))
+		} else {
+			out = append(out, fmt.Sprintf("A maintenance window is open (%s): patrols are paused.", reason))
+		}
+	}
+	if a.Role == "mayor" && mayor.AutopilotEnabled(a.TownRoot) {
+		out = append(out, fmt.Sprintf("Autopilot is on: destructive commands are blocked and every `%s` command is audited.", templates.CmdName()))
+	}
+	return out
+}
+
+// FileFor returns the instructions file an agent runtime reads, e.g.
+// "CLAUDE.md" for Claude and "AGENTS.md" for most others.
+func FileFor(rc *config.RuntimeConfig) string {
+	if rc == nil {
+		rc = config.DefaultRuntimeConfig()
+	}
+	if rc.Instructions != nil && rc.Instructions.File != "" {
+		return rc.Instructions.File
+	}
+	return "AGENTS.md"
+}
+
+// Generate writes an agent's instructions section into file (e.g.,
+// "CLAUDE.md") in its work directory, if the town generates instructions
+// for its role.
+func Generate(a Agent, file string) (Outcome, error) {
+	if !Enabled(a.TownRoot, a.Role) {
+		return OutcomeDisabled, nil
+	}
+	section, err := Render(a)
+	if err != nil {
+		return "", err
+	}
+	return Write(a.WorkDir, file, section)
+}
+
+// Write puts section between the markers in workDir/file, replacing an
+// earlier section and keeping everything else. A file the project tracks
+// is left alone: editing it would show up in the agent's commits. A file
+// Write creates is excluded from git in the clone.
+func Write(workDir, file, section string) (Outcome, error) {
+	g := git.NewGit(workDir)
+	if g.IsTracked(file) {
+		return OutcomeTracked, nil
+	}
+
+	path := filepath.Join(workDir, file)
+	block := BeginMarker + "\n" + strings.TrimSpace(section) + "\n" + EndMarker + "\n"
+	existing, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	outcome := OutcomeUpdated
+	var content string
+	switch {
+	case os.IsNotExist(err):
+		outcome, content = OutcomeCreated, block
+	case err != nil:
+		return "", err
+	default:
+		content = replaceSection(string(existing), block)
+		if content == string(existing) {
+			return OutcomeUnchanged, nil
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil { //nolint:gosec // G306: instructions are not secret
+		return "", err
+	}
+	if outcome == OutcomeCreated && g.IsRepo() {
+		if err := g.ExcludeLocally("/" + filepath.ToSlash(file)); err != nil {
+			return outcome, fmt.Errorf("excluding %s from git: %w", file, err)
+		}
+	}
+	return outcome, nil
+}
+
+// replaceSection replaces the marked section of content with block, or
+// appends block if there is none.
+func replaceSection(content, block string) string {
+	start := strings.Index(content, BeginMarker)
+	if start >= 0 {
+		if end := strings.Index(content[start:], EndMarker); end >= 0 {
+			end += start + len(EndMarker)
+			if end < len(content) && content[end] == '\n' {
+				end++
+			}
+			return content[:start] + block + content[end:]
+		}
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if content != "" {
+		content += "\n"
+	}
+	return content + block
+}
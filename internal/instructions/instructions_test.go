@@ -0,0 +1,155 @@
+package instructions
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/maintenance"
+	"github.com/steveyegge/gastown/internal/templates"
+)
+
+func gitRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "t@example.com"},
+		{"config", "user.name", "t"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func gitStatus(t *testing.T, dir string) string {
+	t.Helper()
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestWrite_CreatesExcludedFileAndKeepsOtherText(t *testing.T) {
+	dir := gitRepo(t)
+
+	outcome, err := Write(dir, "CLAUDE.md", "# Gas Town: polecat Toast\n")
+	if err != nil || outcome != OutcomeCreated {
+		t.Fatalf("Write = %v, %v; want created", outcome, err)
+	}
+	if status := gitStatus(t, dir); status != "" {
+		t.Errorf("generated file shows in git status: %q", status)
+	}
+	if outcome, _ := Write(dir, "CLAUDE.md", "# Gas Town: polecat Toast\n"); outcome != OutcomeUnchanged {
+		t.Errorf("rewrite outcome = %v, want unchanged", outcome)
+	}
+
+	path := filepath.Join(dir, "CLAUDE.md")
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, append([]byte("My notes.\n\n"), append(data, []byte("\nMore notes.\n")...)...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if outcome, err := Write(dir, "CLAUDE.md", "# Gas Town: polecat Nux\n"); err != nil || outcome != OutcomeUpdated {
+		t.Fatalf("update = %v, %v", outcome, err)
+	}
+	data, _ = os.ReadFile(path)
+	want := "My notes.\n\n" + BeginMarker + "\n# Gas Town: polecat Nux\n" + EndMarker + "\n\nMore notes.\n"
+	if string(data) != want {
+		t.Errorf("file =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestWrite_LeavesTrackedFileAlone(t *testing.T) {
+	dir := gitRepo(t)
+	path := filepath.Join(dir, "AGENTS.md")
+	if err := os.WriteFile(path, []byte("Project instructions.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", "AGENTS.md"}, {"commit", "-qm", "init"}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	if outcome, err := Write(dir, "AGENTS.md", "# Gas Town\n"); err != nil || outcome != OutcomeTracked {
+		t.Fatalf("Write = %v, %v; want tracked", outcome, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "Project instructions.\n" {
+		t.Errorf("tracked file modified: %q", data)
+	}
+}
+
+func TestRender_ConventionsConstraintsAndOverride(t *testing.T) {
+	town := t.TempDir()
+	rigPath := filepath.Join(town, "greenplace")
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rigPath, "config.json"), []byte(`{"default_branch":"develop"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), &config.RigSettings{
+		Type: "rig-settings", Version: 1,
+		MergeQueue: &config.MergeQueueConfig{TestCommand: "make test"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", ConventionsFile), []byte("Use table-driven tests.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := maintenance.Freeze(town, "bad merge loop", "human", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	agent := Agent{TownRoot: town, Rig: "greenplace", Role: "polecat", Name: "Toast"}
+	out, err := templates.RenderInstructions(town, BuildData(agent, time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# Gas Town: polecat Toast\n",
+		"You are polecat Toast of rig greenplace.",
+		"done`: submit finished work",
+		"- Work lands on `develop` through the merge queue",
+		"- Test: `make test`",
+		"Use table-driven tests.",
+		"- The town is frozen (bad merge loop)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("instructions missing %q:\n%s", want, out)
+		}
+	}
+
+	overrides := templates.InstructionsOverrideDir(town)
+	if err := os.MkdirAll(overrides, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(overrides, "polecat.md.tmpl"), []byte("Custom {{ .Identity }}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = Render(agent)
+	if err != nil || out != "Custom polecat Toast of rig greenplace\n" {
+		t.Errorf("override rendered %q, %v", out, err)
+	}
+}
+
+func TestGenerate_DisabledByDefault(t *testing.T) {
+	dir := gitRepo(t)
+	outcome, err := Generate(Agent{TownRoot: t.TempDir(), Role: "polecat", WorkDir: dir}, "CLAUDE.md")
+	if err != nil || outcome != OutcomeDisabled {
+		t.Fatalf("Generate = %v, %v; want disabled", outcome, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "CLAUDE.md")); !os.IsNotExist(err) {
+		t.Error("instructions written while disabled")
+	}
+	if !(&config.RoleInstructionsConfig{}).Enabled("crew") || (&config.RoleInstructionsConfig{}).Enabled("mayor") {
+		t.Error("default roles should be polecat and crew")
+	}
+}
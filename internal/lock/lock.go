@@ -17,6 +17,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/platform"
 )
 
 // Common errors
@@ -36,7 +38,7 @@ type LockInfo struct {
 
 // IsStale checks if the lock is stale (owning process is dead).
 func (l *LockInfo) IsStale() bool {
-	return !processExists(l.PID)
+	return !platform.ProcessExists(l.PID)
 }
 
 // Lock represents an agent identity lock for a worker directory.
@@ -328,27 +328,6 @@ func TestLock_ForceRelease(t *testing.T) {
 	}
 }
 
-func TestProcessExists(t *testing.T) {
-	// Current process exists
-	if !processExists(os.Getpid()) {
-		t.Error("processExists(current PID) = false, want true")
-	}
-
-	// Note: PID 1 (init/launchd) cannot be signaled without permission on macOS,
-	// so we only test our own process and invalid PIDs.
-
-	// Invalid PIDs
-	if processExists(0) {
-		t.Error("processExists(0) = true, want false")
-	}
-	if processExists(-1) {
-		t.Error("processExists(-1) = true, want false")
-	}
-	if processExists(999999999) {
-		t.Error("processExists(999999999) = true, want false")
-	}
-}
-
 func TestFindAllLocks(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -0,0 +1,185 @@
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Shared resources that patrols and supervisors limit their concurrent use
+// of, across processes: the daemon, gt commands and agents' tools all count
+// against the same limit.
+const (
+	// ResourceDolt is the Dolt server: exports, backups, gc and other
+	// heavy queries.
+	ResourceDolt = "dolt"
+
+	// ResourceGitRemote is pushing to and fetching from git remotes.
+	ResourceGitRemote = "git-remote"
+
+	// ResourceTmuxServer is creating tmux sessions, each of which forks.
+	ResourceTmuxServer = "tmux-server"
+)
+
+// DefaultResourceLimits is how many holders each resource allows at once
+// unless mayor/daemon.json sets "resource_limits".
+var DefaultResourceLimits = map[string]int{
+	ResourceDolt:       1,
+	ResourceGitRemote:  2,
+	ResourceTmuxServer: 4,
+}
+
+// ErrResourceBusy is returned when a resource stays at its limit for longer
+// than the caller is willing to wait.
+var ErrResourceBusy = errors.New("resource busy")
+
+// resourcePollInterval is how often Acquire retries a full resource.
+const resourcePollInterval = 200 * time.Millisecond
+
+// Resource is a named counting semaphore shared by every process in a town.
+// Each of its Limit slots is a flock'd file under .runtime/locks, so slots
+// held by a process that dies are freed with it.
+type Resource struct {
+	Name  string
+	Limit int
+	dir   string
+}
+
+// ResourceHolder describes who holds a slot of a resource.
+type ResourceHolder struct {
+	Slot   int       `json:"slot"`
+	PID    int       `json:"pid"`
+	Holder string    `json:"holder"`
+	Since  time.Time `json:"since"`
+}
+
+// NewResource returns the town's named resource, with its limit from
+// mayor/daemon.json or DefaultResourceLimits (1 for unknown names).
+func NewResource(townRoot, name string) *Resource {
+	return &Resource{
+		Name:  name,
+		Limit: ResourceLimit(townRoot, name),
+		dir:   filepath.Join(townRoot, ".runtime", "locks"),
+	}
+}
+
+// ResourceLimit returns the configured limit for a resource. The daemon
+// config is read directly so gt commands agree with the daemon on it.
+func ResourceLimit(townRoot, name string) int {
+	var cfg struct {
+		ResourceLimits map[string]int `json:"resource_limits"`
+	}
+	if data, err := os.ReadFile(filepath.Join(townRoot, "mayor", "daemon.json")); err == nil {
+		_ = json.Unmarshal(data, &cfg)
+	}
+	if n := cfg.ResourceLimits[name]; n > 0 {
+		return n
+	}
+	if n := DefaultResourceLimits[name]; n > 0 {
+		return n
+	}
+	return 1
+}
+
+func (r *Resource) slotPath(slot int) string {
+	return filepath.Join(r.dir, fmt.Sprintf("resource-%s.%d.lock", r.Name, slot))
+}
+
+func (r *Resource) holderPath(slot int) string {
+	return r.slotPath(slot) + ".holder"
+}
+
+// TryAcquire takes a free slot without waiting. holder says who is using
+// the resource (e.g. "daemon/dolt_backup"), for Holders. ok is false if
+// every slot is taken.
+func (r *Resource) TryAcquire(holder string) (release func(), ok bool, err error) {
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return nil, false, fmt.Errorf("creating lock dir: %w", err)
+	}
+	for slot := 0; slot < r.Limit; slot++ {
+		unlock, got, err := FlockTryAcquire(r.slotPath(slot))
+		if err != nil {
+			return nil, false, err
+		}
+		if !got {
+			continue
+		}
+		info := fmt.Sprintf("%d\t%s\t%s\n", os.Getpid(), time.Now().UTC().Format(time.RFC3339), holder)
+		_ = os.WriteFile(r.holderPath(slot), []byte(info), 0644) //nolint:gosec // G306: lock files are internal operational data
+		return unlock, true, nil
+	}
+	return nil, false, nil
+}
+
+// Acquire waits for a free slot until ctx is done, then returns
+// ErrResourceBusy.
+func (r *Resource) Acquire(ctx context.Context, holder string) (func(), error) {
+	for {
+		release, ok, err := r.TryAcquire(holder)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return release, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%s: %w (held by %s)", r.Name, ErrResourceBusy, r.describeHolders())
+		case <-time.After(resourcePollInterval):
+		}
+	}
+}
+
+// AcquireTimeout is Acquire with a timeout.
+func (r *Resource) AcquireTimeout(holder string, timeout time.Duration) (func(), error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return r.Acquire(ctx, holder)
+}
+
+// Holders returns who holds the resource's slots right now.
+func (r *Resource) Holders() []ResourceHolder {
+	var holders []ResourceHolder
+	for slot := 0; slot < r.Limit; slot++ {
+		if _, err := os.Stat(r.slotPath(slot)); err != nil {
+			continue
+		}
+		unlock, free, err := FlockTryAcquire(r.slotPath(slot))
+		if err != nil {
+			continue
+		}
+		if free {
+			unlock()
+			continue
+		}
+		h := ResourceHolder{Slot: slot}
+		if data, err := os.ReadFile(r.holderPath(slot)); err == nil {
+			fields := strings.SplitN(strings.TrimSpace(string(data)), "\t", 3)
+			if len(fields) == 3 {
+				h.PID, _ = strconv.Atoi(fields[0])
+				h.Since, _ = time.Parse(time.RFC3339, fields[1])
+				h.Holder = fields[2]
+			}
+		}
+		holders = append(holders, h)
+	}
+	return holders
+}
+
+func (r *Resource) describeHolders() string {
+	holders := r.Holders()
+	if len(holders) == 0 {
+		return "unknown"
+	}
+	names := make([]string, len(holders))
+	for i, h := range holders {
+		names[i] = fmt.Sprintf("%s (pid %d)", h.Holder, h.PID)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,74 @@
+//go:build !windows
+
+package lock
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResourceLimit(t *testing.T) {
+	town := t.TempDir()
+	if got := ResourceLimit(town, ResourceTmuxServer); got != DefaultResourceLimits[ResourceTmuxServer] {
+		t.Errorf("default tmux-server limit = %d", got)
+	}
+	if got := ResourceLimit(town, "unknown"); got != 1 {
+		t.Errorf("unknown resource limit = %d, want 1", got)
+	}
+
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := `{"type": "daemon-patrol-config", "resource_limits": {"dolt": 3, "git-remote": 0}}`
+	if err := os.WriteFile(filepath.Join(town, "mayor", "daemon.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := ResourceLimit(town, ResourceDolt); got != 3 {
+		t.Errorf("configured dolt limit = %d, want 3", got)
+	}
+	if got := ResourceLimit(town, ResourceGitRemote); got != DefaultResourceLimits[ResourceGitRemote] {
+		t.Errorf("a zero limit should fall back to the default, got %d", got)
+	}
+}
+
+func TestResource_LimitsHolders(t *testing.T) {
+	r := &Resource{Name: "test", Limit: 2, dir: t.TempDir()}
+
+	first, ok, err := r.TryAcquire("daemon/dolt_backup")
+	if err != nil || !ok {
+		t.Fatalf("first TryAcquire = %v, %v", ok, err)
+	}
+	second, ok, err := r.TryAcquire("daemon/db_maintenance")
+	if err != nil || !ok {
+		t.Fatalf("second TryAcquire = %v, %v", ok, err)
+	}
+	if _, ok, _ := r.TryAcquire("gt dolt gc"); ok {
+		t.Fatal("third holder got a slot past the limit")
+	}
+
+	holders := r.Holders()
+	if len(holders) != 2 || holders[0].Holder != "daemon/dolt_backup" || holders[0].PID != os.Getpid() {
+		t.Errorf("Holders = %+v", holders)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := r.Acquire(ctx, "waiter"); !errors.Is(err, ErrResourceBusy) {
+		t.Errorf("Acquire on a full resource: err = %v, want ErrResourceBusy", err)
+	}
+
+	first()
+	third, err := r.AcquireTimeout("gt dolt gc", time.Second)
+	if err != nil {
+		t.Fatalf("Acquire after a release: %v", err)
+	}
+	third()
+	second()
+	if holders := r.Holders(); len(holders) != 0 {
+		t.Errorf("Holders after releasing all = %+v", holders)
+	}
+}
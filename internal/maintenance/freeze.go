@@ -0,0 +1,77 @@
+package maintenance
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrFrozen is returned for actions refused while the town is frozen.
+var ErrFrozen = errors.New("town is frozen by gt panic")
+
+// Freeze opens a frozen maintenance window that stays open until End. On
+// top of the daemon pausing as in any maintenance window, agents may not
+// nudge each other or run the gt commands FreezeAllows refuses, so an
+// autonomous loop doing damage stops where it is.
+func Freeze(townRoot, reason, by, incident string) (*State, error) {
+	state := &State{
+		Reason:    reason,
+		StartedBy: by,
+		StartedAt: time.Now().UTC(),
+		Frozen:    true,
+		Incident:  incident,
+	}
+	return state, write(townRoot, state)
+}
+
+// FrozenState returns the open maintenance window if it is frozen, or nil.
+func FrozenState(townRoot string) *State {
+	state, err := Get(townRoot, time.Now())
+	if err != nil || state == nil || !state.Frozen {
+		return nil
+	}
+	return state
+}
+
+// freezeBlocked are the gt commands agents may not run while the town is
+// frozen: everything that dispatches work, nudges, kills or cleans up
+// agents, or merges. Matching is by prefix, so "polecat nuke" covers all of
+// its flags. Humans are never restricted; they are the ones recovering.
+var freezeBlocked = []string{
+	"sling",
+	"nudge",
+	"broadcast",
+	"done",
+	"handoff",
+	"polecat nuke",
+	"polecat remove",
+	"polecat gc",
+	"polecat spawn",
+	"polecat standby",
+	"crew remove",
+	"session start",
+	"session restart",
+	"session kill",
+	"session nudge",
+	"witness check-agents",
+	"refinery",
+	"mq",
+	"scheduler run",
+	"orphans",
+	"convoy",
+	"rig remove",
+	"rig shutdown",
+	"down",
+	"shutdown",
+}
+
+// FreezeAllows reports whether an agent may run the gt command at path
+// (e.g. "sling" or "polecat nuke") while the town is frozen.
+func FreezeAllows(path string) bool {
+	for _, blocked := range freezeBlocked {
+		if path == blocked || strings.HasPrefix(path, blocked+" ") {
+			return false
+		}
+	}
+	return true
+}
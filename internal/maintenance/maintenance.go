@@ -0,0 +1,251 @@
+// Package maintenance decides when the town is in a quiet window.
+//
+// During a quiet window the daemon doesn't auto-restart agents, send
+// nudges, or run heavy patrols, so a human can do surgery on the town
+// without the fleet thrashing around them. Windows come from two places:
+//
+//   - quiet hours: recurring windows in mayor/daemon.json ("quiet_hours")
+//   - maintenance: ad-hoc windows opened with gt maintenance start and
+//     closed with gt maintenance end (or when their --for duration runs out)
+//
+// gt panic opens a frozen maintenance window, which also stops agents from
+// nudging or acting on each other (see Freeze).
+package maintenance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// QuietHoursConfig is the "quiet_hours" section of mayor/daemon.json.
+type QuietHoursConfig struct {
+	// Windows are the recurring quiet windows.
+	Windows []Window `json:"windows,omitempty"`
+
+	// Timezone is the IANA zone windows are written in (e.g.,
+	// "America/Los_Angeles"). Empty means the daemon's local time.
+	Timezone string `json:"timezone,omitempty"`
+
+	// PauseBackups also pauses backup patrols (dolt_backup, dolt_remotes,
+	// jsonl_git_backup) during quiet windows. Default: backups keep running.
+	PauseBackups bool `json:"pause_backups,omitempty"`
+}
+
+// Window is one recurring quiet window. Start and End are "HH:MM"; a window
+// whose End is before its Start runs past midnight. Days limits the window
+// to the days it starts on ("mon".."sun"); empty means every day.
+type Window struct {
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+	Days  []string `json:"days,omitempty"`
+}
+
+// State is an ad-hoc maintenance window opened with gt maintenance start.
+type State struct {
+	Reason    string    `json:"reason,omitempty"`
+	StartedBy string    `json:"started_by,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	// Until ends the window automatically. Zero means until gt maintenance end.
+	Until time.Time `json:"until,omitempty"`
+	// Frozen marks a window opened by gt panic (see Freeze).
+	Frozen bool `json:"frozen,omitempty"`
+	// Incident is the directory gt panic snapshotted the town into.
+	Incident string `json:"incident,omitempty"`
+}
+
+// Status describes whether the town is quiet right now, and why.
+type Status struct {
+	Active bool
+	// Source is "maintenance" or "quiet_hours".
+	Source string
+	Reason string
+	// Until is when the window ends, if known.
+	Until time.Time
+	// PauseBackups reports whether backups pause too. Always true for
+	// maintenance windows.
+	PauseBackups bool
+}
+
+// String describes the window for logs, e.g. "maintenance window (db surgery)".
+func (s Status) String() string {
+	if !s.Active {
+		return "no quiet window"
+	}
+	desc := "quiet hours"
+	if s.Source == "maintenance" {
+		desc = "maintenance window"
+	}
+	if s.Reason != "" {
+		desc += " (" + s.Reason + ")"
+	}
+	if !s.Until.IsZero() {
+		desc += " until " + s.Until.Local().Format("15:04")
+	}
+	return desc
+}
+
+// StatePath returns the path of the ad-hoc maintenance state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "maintenance.json")
+}
+
+// Get returns the ad-hoc maintenance window, or nil if none is open.
+// An expired window is reported as nil but left for End to clean up.
+func Get(townRoot string, now time.Time) (*State, error) {
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing maintenance state: %w", err)
+	}
+	if !state.Until.IsZero() && !now.Before(state.Until) {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// Start opens an ad-hoc maintenance window, replacing any open one. A zero
+// duration keeps it open until End.
+func Start(townRoot, reason, by string, duration time.Duration) (*State, error) {
+	state := &State{Reason: reason, StartedBy: by, StartedAt: time.Now().UTC()}
+	if duration > 0 {
+		state.Until = state.StartedAt.Add(duration)
+	}
+	return state, write(townRoot, state)
+}
+
+// write stores state as the open maintenance window.
+func write(townRoot string, state *State) error {
+	path := StatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// End closes the ad-hoc maintenance window and returns it, or nil if none
+// was open.
+func End(townRoot string) (*State, error) {
+	state, err := Get(townRoot, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Remove(StatePath(townRoot)); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Check reports whether the town is in a quiet window at now. An open
+// maintenance window wins over quiet hours. cfg may be nil.
+func Check(townRoot string, cfg *QuietHoursConfig, now time.Time) Status {
+	if state, err := Get(townRoot, now); err == nil && state != nil {
+		return Status{Active: true, Source: "maintenance", Reason: state.Reason, Until: state.Until, PauseBackups: true}
+	}
+	if cfg == nil {
+		return Status{}
+	}
+	if loc, err := time.LoadLocation(cfg.Timezone); err == nil && cfg.Timezone != "" {
+		now = now.In(loc)
+	}
+	for _, w := range cfg.Windows {
+		if until, ok := w.contains(now); ok {
+			return Status{Active: true, Source: "quiet_hours", Until: until, PauseBackups: cfg.PauseBackups}
+		}
+	}
+	return Status{}
+}
+
+// Validate reports the first malformed window in cfg.
+func (cfg *QuietHoursConfig) Validate() error {
+	if cfg.Timezone != "" {
+		if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+			return fmt.Errorf("quiet_hours timezone: %w", err)
+		}
+	}
+	for i, w := range cfg.Windows {
+		if _, err := parseClock(w.Start); err != nil {
+			return fmt.Errorf("quiet_hours window %d start: %w", i, err)
+		}
+		if _, err := parseClock(w.End); err != nil {
+			return fmt.Errorf("quiet_hours window %d end: %w", i, err)
+		}
+		for _, d := range w.Days {
+			if _, ok := weekdays[strings.ToLower(d)]; !ok {
+				return fmt.Errorf("quiet_hours window %d: unknown day %q", i, d)
+			}
+		}
+	}
+	return nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// contains reports whether now falls in the window and, if so, when the
+// window ends.
+func (w Window) contains(now time.Time) (time.Time, bool) {
+	start, err1 := parseClock(w.Start)
+	end, err2 := parseClock(w.End)
+	if err1 != nil || err2 != nil || start == end {
+		return time.Time{}, false
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	minute := now.Hour()*60 + now.Minute()
+
+	// The window can have started today, or (when it runs past midnight)
+	// yesterday.
+	startDay := midnight
+	switch {
+	case start < end && minute >= start && minute < end:
+	case start > end && minute >= start:
+	case start > end && minute < end:
+		startDay = midnight.AddDate(0, 0, -1)
+	default:
+		return time.Time{}, false
+	}
+	if !w.onDay(startDay.Weekday()) {
+		return time.Time{}, false
+	}
+	until := startDay.Add(time.Duration(end) * time.Minute)
+	if start > end {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until, true
+}
+
+func (w Window) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if wd, ok := weekdays[strings.ToLower(d)]; ok && wd == day {
+			return true
+		}
+	}
+	return false
+}
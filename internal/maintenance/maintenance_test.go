@@ -0,0 +1,148 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowContains(t *testing.T) {
+	// 2026-10-16 is a Friday.
+	at := func(day, hour, min int) time.Time { return time.Date(2026, 10, day, hour, min, 0, 0, time.UTC) }
+	tests := []struct {
+		name      string
+		w         Window
+		now       time.Time
+		want      bool
+		wantUntil time.Time
+	}{
+		{"inside daytime window", Window{Start: "09:00", End: "17:00"}, at(16, 12, 0), true, at(16, 17, 0)},
+		{"end is exclusive", Window{Start: "09:00", End: "17:00"}, at(16, 17, 0), false, time.Time{}},
+		{"overnight before midnight", Window{Start: "22:00", End: "06:00"}, at(16, 23, 30), true, at(17, 6, 0)},
+		{"overnight after midnight", Window{Start: "22:00", End: "06:00"}, at(17, 5, 59), true, at(17, 6, 0)},
+		{"overnight outside", Window{Start: "22:00", End: "06:00"}, at(16, 12, 0), false, time.Time{}},
+		{"matching day", Window{Start: "09:00", End: "17:00", Days: []string{"fri"}}, at(16, 12, 0), true, at(16, 17, 0)},
+		{"other day", Window{Start: "09:00", End: "17:00", Days: []string{"Sat"}}, at(16, 12, 0), false, time.Time{}},
+		{"overnight uses start day", Window{Start: "22:00", End: "06:00", Days: []string{"fri"}}, at(17, 2, 0), true, at(17, 6, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			until, got := tt.w.contains(tt.now)
+			if got != tt.want || !until.Equal(tt.wantUntil) {
+				t.Errorf("contains(%v) = %v, %v; want %v, %v", tt.now, until, got, tt.wantUntil, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	town := t.TempDir()
+	cfg := &QuietHoursConfig{Windows: []Window{{Start: "00:00", End: "23:59"}}}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+
+	if qs := Check(town, nil, now); qs.Active {
+		t.Fatalf("Check with no config = %+v, want inactive", qs)
+	}
+	qs := Check(town, cfg, now)
+	if !qs.Active || qs.Source != "quiet_hours" || qs.PauseBackups {
+		t.Errorf("Check in quiet hours = %+v", qs)
+	}
+
+	if _, err := Start(town, "db surgery", "human", 0); err != nil {
+		t.Fatal(err)
+	}
+	qs = Check(town, nil, time.Now())
+	if !qs.Active || qs.Source != "maintenance" || qs.Reason != "db surgery" || !qs.PauseBackups {
+		t.Errorf("Check in maintenance = %+v", qs)
+	}
+
+	state, err := End(town)
+	if err != nil || state == nil || state.Reason != "db surgery" {
+		t.Fatalf("End = %+v, %v", state, err)
+	}
+	if qs := Check(town, nil, time.Now()); qs.Active {
+		t.Errorf("Check after End = %+v, want inactive", qs)
+	}
+}
+
+func TestMaintenanceExpires(t *testing.T) {
+	town := t.TempDir()
+	if _, err := Start(town, "", "human", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if qs := Check(town, nil, time.Now()); !qs.Active || qs.Until.IsZero() {
+		t.Errorf("Check during window = %+v", qs)
+	}
+	if qs := Check(town, nil, time.Now().Add(2*time.Hour)); qs.Active {
+		t.Errorf("Check after window = %+v, want inactive", qs)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	good := &QuietHoursConfig{Timezone: "UTC", Windows: []Window{{Start: "22:00", End: "06:00", Days: []string{"mon"}}}}
+	if err := good.Validate(); err != nil {
+		t.Errorf("Validate(good) = %v", err)
+	}
+	for _, bad := range []*QuietHoursConfig{
+		{Windows: []Window{{Start: "25:00", End: "06:00"}}},
+		{Windows: []Window{{Start: "22:00", End: "06:00", Days: []string{"someday"}}}},
+		{Timezone: "Nowhere/City"},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", bad)
+		}
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	town := t.TempDir()
+	if FrozenState(town) != nil {
+		t.Fatal("fresh town should not be frozen")
+	}
+	if _, err := Start(town, "db surgery", "human", 0); err != nil {
+		t.Fatal(err)
+	}
+	if FrozenState(town) != nil {
+		t.Error("a plain maintenance window is not a freeze")
+	}
+
+	if _, err := Freeze(town, "runaway loop", "human", "/tmp/incident"); err != nil {
+		t.Fatal(err)
+	}
+	state := FrozenState(town)
+	if state == nil || state.Reason != "runaway loop" || state.Incident != "/tmp/incident" {
+		t.Fatalf("FrozenState = %+v", state)
+	}
+	if qs := Check(town, nil, time.Now()); !qs.Active || !qs.PauseBackups {
+		t.Errorf("a freeze should pause the daemon like maintenance: %+v", qs)
+	}
+
+	if _, err := End(town); err != nil {
+		t.Fatal(err)
+	}
+	if FrozenState(town) != nil {
+		t.Error("End should lift the freeze")
+	}
+}
+
+func TestFreezeAllows(t *testing.T) {
+	for path, want := range map[string]bool{
+		"sling":                false,
+		"polecat nuke":         false,
+		"polecat nuke --force": false,
+		"session kill":         false,
+		"nudge":                false,
+		"witness check-agents": false,
+		"mail inbox":           true,
+		"mail check":           true,
+		"status":               true,
+		"polecat list":         true,
+		"prime":                true,
+		"slingshot":            true, // prefix match is by whole words
+		"session status":       true,
+		"maintenance status":   true,
+	} {
+		if got := FreezeAllows(path); got != want {
+			t.Errorf("FreezeAllows(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package mayor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultAutopilotInterval is how often the autopilot sends the mayor a
+// digest when no interval is configured.
+const DefaultAutopilotInterval = 15 * time.Minute
+
+// AutopilotState is the persisted autopilot switch. Autopilot is off unless
+// this file exists with Enabled set; deleting or disabling it is the
+// kill-switch.
+type AutopilotState struct {
+	Enabled    bool      `json:"enabled"`
+	Interval   string    `json:"interval,omitempty"`
+	EnabledAt  time.Time `json:"enabled_at,omitempty"`
+	EnabledBy  string    `json:"enabled_by,omitempty"`
+	LastDigest time.Time `json:"last_digest,omitempty"`
+}
+
+// IntervalDuration returns the configured digest interval, falling back to
+// DefaultAutopilotInterval when unset or invalid.
+func (s *AutopilotState) IntervalDuration() time.Duration {
+	if d, err := time.ParseDuration(s.Interval); err == nil && d > 0 {
+		return d
+	}
+	return DefaultAutopilotInterval
+}
+
+// Due reports whether a digest should be sent at now.
+func (s *AutopilotState) Due(now time.Time) bool {
+	return s.Enabled && now.Sub(s.LastDigest) >= s.IntervalDuration()
+}
+
+// autopilotDir holds autopilot state, the latest digest, and the audit log.
+func autopilotDir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "mayor")
+}
+
+// AutopilotFile returns the path to the autopilot state file.
+func AutopilotFile(townRoot string) string {
+	return filepath.Join(autopilotDir(townRoot), "autopilot.json")
+}
+
+// DigestFile returns the path to the most recent digest.
+func DigestFile(townRoot string) string {
+	return filepath.Join(autopilotDir(townRoot), "autopilot-digest.md")
+}
+
+// AuditFile returns the path to the autopilot audit log.
+func AuditFile(townRoot string) string {
+	return filepath.Join(autopilotDir(townRoot), "autopilot-audit.jsonl")
+}
+
+// LoadAutopilot reads the autopilot state. A missing file means autopilot
+// is off.
+func LoadAutopilot(townRoot string) (*AutopilotState, error) {
+	data, err := os.ReadFile(AutopilotFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &AutopilotState{}, nil
+		}
+		return nil, fmt.Errorf("reading autopilot state: %w", err)
+	}
+	var s AutopilotState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing autopilot state: %w", err)
+	}
+	return &s, nil
+}
+
+// SaveAutopilot writes the autopilot state.
+func SaveAutopilot(townRoot string, s *AutopilotState) error {
+	if err := util.EnsureDirAndWriteJSON(AutopilotFile(townRoot), s); err != nil {
+		return fmt.Errorf("writing autopilot state: %w", err)
+	}
+	return nil
+}
+
+// AutopilotEnabled reports whether autopilot is on. Read errors count as
+// off so a corrupt state file can never keep autopilot running.
+func AutopilotEnabled(townRoot string) bool {
+	s, err := LoadAutopilot(townRoot)
+	return err == nil && s.Enabled
+}
+
+// autopilotBlocked lists gt command paths the mayor may not run while on
+// autopilot. These are destructive or change the town's own machinery;
+// a human must run them. Matching is by prefix, so "rig remove" covers
+// all of its flags.
+var autopilotBlocked = []string{
+	"down",
+	"shutdown",
+	"uninstall",
+	"install",
+	"bootstrap",
+	"upgrade",
+	"rig remove",
+	"polecat nuke",
+	"polecat remove",
+	"crew remove",
+	"daemon stop",
+	"daemon uninstall",
+	"dolt stop",
+	"dolt sql",
+	"dolt rollback",
+	"dolt cleanup",
+	"dolt migrate",
+	"mayor autopilot start",
+}
+
+// AutopilotAllows reports whether the mayor may run the gt command at
+// path (e.g. "sling" or "rig remove") while on autopilot.
+func AutopilotAllows(path string) bool {
+	for _, blocked := range autopilotBlocked {
+		if path == blocked || strings.HasPrefix(path, blocked+" ") {
+			return false
+		}
+	}
+	return true
+}
+
+// AuditEntry is one line of the autopilot audit log.
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Actor   string    `json:"actor"`
+	Action  string    `json:"action"`
+	Detail  string    `json:"detail,omitempty"`
+	Blocked bool      `json:"blocked,omitempty"`
+}
+
+// AppendAudit records an autopilot action.
+func AppendAudit(townRoot string, e AuditEntry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(autopilotDir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating autopilot dir: %w", err)
+	}
+	f, err := os.OpenFile(AuditFile(townRoot), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	return f.Close()
+}
+
+// ReadAudit returns the last n audit entries (all when n <= 0).
+func ReadAudit(townRoot string, n int) ([]AuditEntry, error) {
+	f, err := os.Open(AuditFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e AuditEntry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	if n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, scanner.Err()
+}
+
+// DigestItem is one issue listed in a digest.
+type DigestItem struct {
+	Rig      string
+	ID       string
+	Title    string
+	Priority int
+	Assignee string
+	Age      time.Duration
+	// Summary condenses the issue's description and notes (stalled work
+	// only).
+	Summary string
+}
+
+// Digest is the periodic summary the autopilot sends to the mayor.
+type Digest struct {
+	Generated time.Time
+	Ready     []DigestItem
+	Stalled   []DigestItem
+	OpenMRs   []DigestItem
+}
+
+// digestListLimit caps each digest section so the mayor's context is not
+// flooded on a busy town.
+const digestListLimit = 15
+
+// Format renders the digest as markdown with the mayor's instructions.
+func (d *Digest) Format() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Autopilot digest (%s)\n\n", d.Generated.Format(time.RFC3339))
+
+	section := func(title string, items []DigestItem, line func(DigestItem) string) {
+		fmt.Fprintf(&b, "## %s (%d)\n\n", title, len(items))
+		if len(items) == 0 {
+			b.WriteString("none\n\n")
+			return
+		}
+		for i, it := range items {
+			if i == digestListLimit {
+				fmt.Fprintf(&b, "- ... and %d more\n", len(items)-digestListLimit)
+				break
+			}
+			b.WriteString("- " + line(it) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	section("Ready work", d.Ready, func(it DigestItem) string {
+		return fmt.Sprintf("%s [P%d] %s (%s)", it.ID, it.Priority, it.Title, it.Rig)
+	})
+	section("Stalled work", d.Stalled, func(it DigestItem) string {
+		line := fmt.Sprintf("%s %s (%s, %s, no update for %s)", it.ID, it.Title, it.Rig, it.Assignee, it.Age.Round(time.Minute))
+		if it.Summary != "" {
+			line += "\n  > " + strings.ReplaceAll(it.Summary, "\n", "\n  > ")
+		}
+		return line
+	})
+	section("Open merge requests", d.OpenMRs, func(it DigestItem) string {
+		return fmt.Sprintf("%s %s (%s, open %s)", it.ID, it.Title, it.Rig, it.Age.Round(time.Minute))
+	})
+
+	b.WriteString(`## Your job
+
+Triage, prioritize, and dispatch:
+- Dispatch ready work: gt sling <issue> <rig>
+- Reprioritize: bd update <issue> --priority <0-4>
+- Check on stalled work: gt notes show <issue>, gt nudge <agent> "..."
+- Escalate what you cannot resolve: gt escalate
+
+Destructive commands are blocked while on autopilot, and every gt command
+you run is audited. A human can stop autopilot at any time with
+'gt mayor autopilot stop'.
+`)
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package mayor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAutopilotStateRoundTrip(t *testing.T) {
+	town := t.TempDir()
+
+	if AutopilotEnabled(town) {
+		t.Fatal("autopilot enabled with no state file")
+	}
+
+	if err := SaveAutopilot(town, &AutopilotState{Enabled: true, Interval: "30m"}); err != nil {
+		t.Fatalf("SaveAutopilot: %v", err)
+	}
+	if !AutopilotEnabled(town) {
+		t.Fatal("autopilot not enabled after save")
+	}
+	s, err := LoadAutopilot(town)
+	if err != nil {
+		t.Fatalf("LoadAutopilot: %v", err)
+	}
+	if s.IntervalDuration() != 30*time.Minute {
+		t.Errorf("IntervalDuration = %v, want 30m", s.IntervalDuration())
+	}
+}
+
+func TestAutopilotDue(t *testing.T) {
+	now := time.Now()
+	s := &AutopilotState{Enabled: true, Interval: "10m", LastDigest: now.Add(-5 * time.Minute)}
+	if s.Due(now) {
+		t.Error("Due before interval elapsed")
+	}
+	s.LastDigest = now.Add(-11 * time.Minute)
+	if !s.Due(now) {
+		t.Error("not Due after interval elapsed")
+	}
+	s.Enabled = false
+	if s.Due(now) {
+		t.Error("Due while disabled")
+	}
+	if (&AutopilotState{Interval: "bogus"}).IntervalDuration() != DefaultAutopilotInterval {
+		t.Error("invalid interval should fall back to default")
+	}
+}
+
+func TestAutopilotAllows(t *testing.T) {
+	tests := map[string]bool{
+		"sling":                  true,
+		"nudge":                  true,
+		"mayor autopilot stop":   true,
+		"mayor autopilot start":  false,
+		"rig remove":             false,
+		"rig list":               true,
+		"polecat nuke":           false,
+		"down":                   false,
+		"dolt status":            true,
+		"dolt stop":              false,
+		"downstream-nonexistent": true,
+	}
+	for path, want := range tests {
+		if got := AutopilotAllows(path); got != want {
+			t.Errorf("AutopilotAllows(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	town := t.TempDir()
+	for _, action := range []string{"start", "digest", "stop"} {
+		if err := AppendAudit(town, AuditEntry{Actor: "overseer", Action: action}); err != nil {
+			t.Fatalf("AppendAudit: %v", err)
+		}
+	}
+	entries, err := ReadAudit(town, 2)
+	if err != nil {
+		t.Fatalf("ReadAudit: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != "digest" || entries[1].Action != "stop" {
+		t.Errorf("ReadAudit(2) = %+v", entries)
+	}
+}
+
+func TestDigestFormat(t *testing.T) {
+	d := &Digest{
+		Generated: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Ready:     []DigestItem{{Rig: "greenplace", ID: "gp-1", Title: "Fix parser", Priority: 1}},
+		Stalled:   []DigestItem{{Rig: "greenplace", ID: "gp-2", Title: "Flaky CI", Summary: "Retries tried.\nBlocked on runner access."}},
+	}
+	for i := 0; i < digestListLimit+3; i++ {
+		d.OpenMRs = append(d.OpenMRs, DigestItem{Rig: "greenplace", ID: "gp-mr", Title: "MR"})
+	}
+	out := d.Format()
+	for _, want := range []string{"gp-1 [P1] Fix parser (greenplace)", "## Stalled work (1)", "  > Retries tried.\n  > Blocked on runner access.", "... and 3 more", "gt mayor autopilot stop"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("digest missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -79,6 +79,7 @@ func (m *Manager) Start(agentOverride string) error {
 		},
 		AgentOverride: agentOverride,
 		Theme:         &theme,
+		VerifyStartup: true,
 		WaitForAgent:  true,
 		WaitFatal:     true,
 		AutoRespawn:   true,
@@ -0,0 +1,250 @@
+// Package mcp is a minimal Model Context Protocol server: JSON-RPC 2.0 over
+// newline-delimited stdio, serving tools only. It lets agents call town
+// operations as structured tools instead of running gt and parsing text.
+//
+// Every tool declares the roles allowed to call it. The server only lists
+// and runs tools the caller's role may use, so role limits hold no matter
+// what the agent asks for.
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Protocol versions the server speaks, newest first.
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool is an operation agents can call.
+type Tool struct {
+	Name        string
+	Description string
+
+	// InputSchema is the JSON Schema of the arguments object.
+	InputSchema map[string]interface{}
+
+	// Roles lists the role kinds ("mayor", "polecat", ...) allowed to call
+	// the tool. Empty allows every role.
+	Roles []string
+
+	// Handler runs the tool. Its result is returned to the agent as JSON;
+	// an error is returned as a tool error the agent can read and act on.
+	Handler func(ctx context.Context, args json.RawMessage) (interface{}, error)
+}
+
+// allows reports whether role may call the tool.
+func (t *Tool) allows(role string) bool {
+	if len(t.Roles) == 0 {
+		return true
+	}
+	for _, r := range t.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Server serves tools to one client.
+type Server struct {
+	name    string
+	version string
+	role    string
+	tools   []*Tool
+
+	mu  sync.Mutex // serializes writes
+	out io.Writer
+}
+
+// NewServer creates a server identifying as name/version to a caller with
+// the given role kind.
+func NewServer(name, version, role string) *Server {
+	return &Server{name: name, version: version, role: role}
+}
+
+// AddTool registers a tool.
+func (s *Server) AddTool(t *Tool) {
+	s.tools = append(s.tools, t)
+}
+
+// tool returns the named tool if the caller may use it.
+func (s *Server) tool(name string) (*Tool, bool) {
+	for _, t := range s.tools {
+		if t.Name == name {
+			return t, t.allows(s.role)
+		}
+	}
+	return nil, false
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from in and writes responses to out until in is
+// exhausted or ctx is canceled. Requests are handled one at a time.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.write(response{ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "parse error: " + err.Error()}})
+			continue
+		}
+		if len(req.ID) == 0 {
+			continue // notification (e.g. notifications/initialized): no reply
+		}
+		result, rerr := s.handle(ctx, &req)
+		s.write(response{ID: req.ID, Result: result, Error: rerr})
+	}
+	return scanner.Err()
+}
+
+func (s *Server) write(resp response) {
+	resp.JSONRPC = "2.0"
+	data, err := json.Marshal(resp)
+	if err != nil {
+		data, _ = json.Marshal(response{JSONRPC: "2.0", ID: resp.ID, Error: &rpcError{codeInvalidRequest, err.Error()}})
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = s.out.Write(append(data, '\n'))
+}
+
+func (s *Server) handle(ctx context.Context, req *request) (interface{}, *rpcError) {
+	if req.JSONRPC != "2.0" {
+		return nil, &rpcError{codeInvalidRequest, `jsonrpc must be "2.0"`}
+	}
+	switch req.Method {
+	case "initialize":
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &p)
+		version := protocolVersions[0]
+		for _, v := range protocolVersions {
+			if v == p.ProtocolVersion {
+				version = v
+			}
+		}
+		return map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		}, nil
+
+	case "ping":
+		return map[string]interface{}{}, nil
+
+	case "tools/list":
+		tools := []map[string]interface{}{}
+		for _, t := range s.tools {
+			if !t.allows(s.role) {
+				continue
+			}
+			schema := t.InputSchema
+			if schema == nil {
+				schema = map[string]interface{}{"type": "object"}
+			}
+			tools = append(tools, map[string]interface{}{
+				"name":        t.Name,
+				"description": t.Description,
+				"inputSchema": schema,
+			})
+		}
+		return map[string]interface{}{"tools": tools}, nil
+
+	case "tools/call":
+		var p struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &p); err != nil || p.Name == "" {
+			return nil, &rpcError{codeInvalidParams, "tools/call needs a tool name"}
+		}
+		t, allowed := s.tool(p.Name)
+		if t == nil {
+			return nil, &rpcError{codeInvalidParams, fmt.Sprintf("unknown tool %q", p.Name)}
+		}
+		if !allowed {
+			return toolError(fmt.Errorf("permission denied: role %q may not call %s", s.role, p.Name)), nil
+		}
+		if len(p.Arguments) == 0 || string(p.Arguments) == "null" {
+			p.Arguments = json.RawMessage("{}")
+		}
+		out, err := t.Handler(ctx, p.Arguments)
+		if err != nil {
+			return toolError(err), nil
+		}
+		text, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return toolError(err), nil
+		}
+		return map[string]interface{}{
+			"content": []map[string]string{{"type": "text", "text": string(text)}},
+			"isError": false,
+		}, nil
+	}
+	return nil, &rpcError{codeMethodNotFound, fmt.Sprintf("method %q not found", req.Method)}
+}
+
+// toolError is a tools/call result reporting a failure to the agent.
+func toolError(err error) map[string]interface{} {
+	return map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": err.Error()}},
+		"isError": true,
+	}
+}
+
+// ErrPermission is returned by tool handlers refusing an operation the
+// caller's role may not perform on that target.
+var ErrPermission = errors.New("permission denied")
+
+// DecodeArgs unmarshals tool arguments into v, rejecting unknown fields so
+// typos in argument names surface as errors.
+func DecodeArgs(args json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// serve runs a server over the given request lines and returns the decoded
+// responses.
+func serve(t *testing.T, s *Server, lines ...string) []map[string]interface{} {
+	t.Helper()
+	var out strings.Builder
+	if err := s.Serve(context.Background(), strings.NewReader(strings.Join(lines, "\n")), &out); err != nil {
+		t.Fatalf("Serve() error: %v", err)
+	}
+	var resps []map[string]interface{}
+	sc := bufio.NewScanner(strings.NewReader(out.String()))
+	for sc.Scan() {
+		var r map[string]interface{}
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatalf("bad response %q: %v", sc.Text(), err)
+		}
+		resps = append(resps, r)
+	}
+	return resps
+}
+
+func testServer(role string) *Server {
+	s := NewServer("test", "1.0", role)
+	s.AddTool(&Tool{
+		Name: "echo",
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var a struct {
+				Text string `json:"text"`
+			}
+			if err := DecodeArgs(args, &a); err != nil {
+				return nil, err
+			}
+			return map[string]string{"text": a.Text}, nil
+		},
+	})
+	s.AddTool(&Tool{
+		Name:  "review",
+		Roles: []string{"polecat"},
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			return nil, errors.New("should not run")
+		},
+	})
+	return s
+}
+
+func TestInitialize(t *testing.T) {
+	resps := serve(t, testServer("mayor"),
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"ping"}`,
+	)
+	if len(resps) != 2 {
+		t.Fatalf("got %d responses, want 2 (notifications get none): %v", len(resps), resps)
+	}
+	result := resps[0]["result"].(map[string]interface{})
+	if result["protocolVersion"] != "2024-11-05" {
+		t.Errorf("protocolVersion = %v, want the client's 2024-11-05", result["protocolVersion"])
+	}
+	if info := result["serverInfo"].(map[string]interface{}); info["name"] != "test" {
+		t.Errorf("serverInfo = %v", info)
+	}
+}
+
+func TestToolsListFilteredByRole(t *testing.T) {
+	for role, want := range map[string]int{"mayor": 1, "polecat": 2} {
+		resps := serve(t, testServer(role), `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+		tools := resps[0]["result"].(map[string]interface{})["tools"].([]interface{})
+		if len(tools) != want {
+			t.Errorf("role %s: %d tools, want %d", role, len(tools), want)
+		}
+	}
+}
+
+func TestToolsCall(t *testing.T) {
+	resps := serve(t, testServer("mayor"),
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"txt":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"review"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"nope"}}`,
+	)
+	text := func(r map[string]interface{}) (string, bool) {
+		res := r["result"].(map[string]interface{})
+		c := res["content"].([]interface{})[0].(map[string]interface{})
+		return c["text"].(string), res["isError"].(bool)
+	}
+
+	if got, isErr := text(resps[0]); isErr || !strings.Contains(got, `"text": "hi"`) {
+		t.Errorf("echo = %q (isError %v)", got, isErr)
+	}
+	if got, isErr := text(resps[1]); !isErr || !strings.Contains(got, "unknown field") {
+		t.Errorf("echo with bad argument = %q (isError %v), want unknown field error", got, isErr)
+	}
+	if got, isErr := text(resps[2]); !isErr || !strings.Contains(got, "permission denied") {
+		t.Errorf("review as mayor = %q (isError %v), want permission denied", got, isErr)
+	}
+	if resps[3]["error"] == nil {
+		t.Errorf("unknown tool = %v, want JSON-RPC error", resps[3])
+	}
+}
+
+func TestProtocolErrors(t *testing.T) {
+	resps := serve(t, testServer("mayor"),
+		`{not json`,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+	)
+	codes := []float64{codeParseError, codeMethodNotFound}
+	for i, want := range codes {
+		e, ok := resps[i]["error"].(map[string]interface{})
+		if !ok || e["code"] != want {
+			t.Errorf("response %d = %v, want error code %v", i, resps[i], want)
+		}
+	}
+}
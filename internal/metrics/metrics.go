@@ -0,0 +1,250 @@
+// Package metrics keeps a local history of key town metrics (sessions
+// alive, merge queue depth, patrol durations, cost) so reports and the
+// dashboard can show trends over weeks without an external time-series
+// database.
+//
+// Samples are stored as compact JSON lines, one file per month, under
+// <town>/.runtime/metrics/ (e.g. 2026-10.jsonl):
+//
+//	{"t":1760000000,"v":{"queue_depth":3,"sessions_alive":7}}
+//
+// Whole months are the unit of retention: Prune deletes month files that
+// end before a cutoff.
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Metric names recorded by the daemon.
+const (
+	// SessionsAlive is the number of Gas Town agent sessions running.
+	SessionsAlive = "sessions_alive"
+
+	// QueueDepth is the number of open merge requests across rigs.
+	QueueDepth = "queue_depth"
+
+	// CostUSD is the spend recorded in the cost ledger since the previous
+	// sample. Unlike the other metrics it is summed, not averaged.
+	CostUSD = "cost_usd"
+
+	// PatrolSecondsPrefix prefixes patrol durations in seconds, e.g.
+	// "patrol_seconds.heartbeat".
+	PatrolSecondsPrefix = "patrol_seconds."
+)
+
+// Sample is the value of each metric at one point in time.
+type Sample struct {
+	Time   time.Time
+	Values map[string]float64
+}
+
+// sampleLine is a Sample as stored on disk.
+type sampleLine struct {
+	T int64              `json:"t"`
+	V map[string]float64 `json:"v"`
+}
+
+// Dir returns the directory holding a town's metrics history.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "metrics")
+}
+
+// Store reads and appends a town's metrics history.
+type Store struct {
+	dir string
+}
+
+// Open returns the metrics store for a town. Nothing is created until the
+// first Append.
+func Open(townRoot string) *Store {
+	return &Store{dir: Dir(townRoot)}
+}
+
+func (s *Store) monthFile(t time.Time) string {
+	return filepath.Join(s.dir, t.UTC().Format("2006-01")+".jsonl")
+}
+
+// Append adds a sample to the history.
+func (s *Store) Append(sample Sample) error {
+	if len(sample.Values) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(sampleLine{T: sample.Time.Unix(), V: sample.Values})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("creating metrics dir: %w", err)
+	}
+	f, err := os.OpenFile(s.monthFile(sample.Time), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: metrics are not secret
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Range returns the samples in [start, end), oldest first. Unparseable
+// lines (e.g. a torn final write) are skipped.
+func (s *Store) Range(start, end time.Time) ([]Sample, error) {
+	var out []Sample
+	for month := monthStart(start); month.Before(end); month = month.AddDate(0, 1, 0) {
+		f, err := os.Open(s.monthFile(month))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return out, err
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var line sampleLine
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				continue
+			}
+			t := time.Unix(line.T, 0)
+			if !t.Before(start) && t.Before(end) {
+				out = append(out, Sample{Time: t, Values: line.V})
+			}
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return out, err
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out, nil
+}
+
+// Prune deletes month files that end before cutoff and returns how many
+// were removed.
+func (s *Store) Prune(cutoff time.Time) (int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	removed := 0
+	for _, e := range entries {
+		name := e.Name()
+		month, err := time.Parse("2006-01", strings.TrimSuffix(name, ".jsonl"))
+		if err != nil || !strings.HasSuffix(name, ".jsonl") {
+			continue
+		}
+		if month.AddDate(0, 1, 0).After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, name)); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// IsCounter reports whether a metric counts something per sample (and so
+// is summed when samples are aggregated) rather than measuring a level.
+func IsCounter(name string) bool {
+	return name == CostUSD
+}
+
+// Point is one value of a series.
+type Point struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Series aggregates one metric into buckets of width step starting at
+// start: counters are summed, levels averaged. Buckets without samples
+// are omitted.
+func Series(samples []Sample, name string, start time.Time, step time.Duration) []Point {
+	if step <= 0 {
+		return nil
+	}
+	type bucket struct {
+		sum float64
+		n   int
+	}
+	buckets := make(map[int64]*bucket)
+	var keys []int64
+	for _, s := range samples {
+		v, ok := s.Values[name]
+		if !ok || s.Time.Before(start) {
+			continue
+		}
+		k := int64(s.Time.Sub(start) / step)
+		b := buckets[k]
+		if b == nil {
+			b = &bucket{}
+			buckets[k] = b
+			keys = append(keys, k)
+		}
+		b.sum += v
+		b.n++
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	points := make([]Point, 0, len(keys))
+	for _, k := range keys {
+		b := buckets[k]
+		v := b.sum
+		if !IsCounter(name) {
+			v /= float64(b.n)
+		}
+		points = append(points, Point{Time: start.Add(time.Duration(k) * step), Value: v})
+	}
+	return points
+}
+
+// Summary describes one metric over a set of samples.
+type Summary struct {
+	Name  string  `json:"name"`
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Mean  float64 `json:"mean"`
+	Last  float64 `json:"last"`
+	Total float64 `json:"total"`
+}
+
+// Summarize describes every metric present in samples, sorted by name.
+func Summarize(samples []Sample) []Summary {
+	byName := make(map[string]*Summary)
+	for _, s := range samples {
+		for name, v := range s.Values {
+			sum := byName[name]
+			if sum == nil {
+				sum = &Summary{Name: name, Min: v, Max: v}
+				byName[name] = sum
+			}
+			sum.Count++
+			sum.Total += v
+			sum.Min = min(sum.Min, v)
+			sum.Max = max(sum.Max, v)
+			sum.Last = v
+		}
+	}
+	out := make([]Summary, 0, len(byName))
+	for _, sum := range byName {
+		sum.Mean = sum.Total / float64(sum.Count)
+		out = append(out, *sum)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendRangePrune(t *testing.T) {
+	town := t.TempDir()
+	s := Open(town)
+	sep := time.Date(2026, 9, 30, 23, 0, 0, 0, time.UTC)
+	oct := time.Date(2026, 10, 1, 1, 0, 0, 0, time.UTC)
+	for _, sample := range []Sample{
+		{Time: sep, Values: map[string]float64{SessionsAlive: 4}},
+		{Time: oct, Values: map[string]float64{SessionsAlive: 6, QueueDepth: 2}},
+		{Time: oct.Add(time.Hour), Values: nil}, // empty samples are dropped
+	} {
+		if err := s.Append(sample); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	for _, month := range []string{"2026-09.jsonl", "2026-10.jsonl"} {
+		if _, err := os.Stat(filepath.Join(Dir(town), month)); err != nil {
+			t.Errorf("month file %s: %v", month, err)
+		}
+	}
+
+	got, err := s.Range(sep, oct.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Range: %v", err)
+	}
+	if len(got) != 2 || got[0].Values[SessionsAlive] != 4 || got[1].Values[QueueDepth] != 2 {
+		t.Errorf("Range() = %+v", got)
+	}
+	if got, _ := s.Range(oct, oct.Add(time.Hour)); len(got) != 1 {
+		t.Errorf("Range(oct) = %d samples, want 1", len(got))
+	}
+
+	removed, err := s.Prune(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil || removed != 1 {
+		t.Fatalf("Prune() = %d, %v; want 1 month removed", removed, err)
+	}
+	if got, _ := s.Range(sep, oct.Add(time.Minute)); len(got) != 1 {
+		t.Errorf("after Prune, Range() = %d samples, want 1", len(got))
+	}
+}
+
+func TestSeriesAndSummarize(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	samples := []Sample{
+		{Time: start, Values: map[string]float64{SessionsAlive: 2, CostUSD: 1}},
+		{Time: start.Add(time.Hour), Values: map[string]float64{SessionsAlive: 4, CostUSD: 2}},
+		{Time: start.Add(25 * time.Hour), Values: map[string]float64{SessionsAlive: 9}},
+	}
+
+	sessions := Series(samples, SessionsAlive, start, 24*time.Hour)
+	if len(sessions) != 2 || sessions[0].Value != 3 || sessions[1].Value != 9 {
+		t.Errorf("Series(sessions_alive) = %+v, want daily means 3 and 9", sessions)
+	}
+	cost := Series(samples, CostUSD, start, 24*time.Hour)
+	if len(cost) != 1 || cost[0].Value != 3 {
+		t.Errorf("Series(cost_usd) = %+v, want one day summing to 3", cost)
+	}
+
+	sums := Summarize(samples)
+	if len(sums) != 2 || sums[0].Name != CostUSD || sums[0].Total != 3 {
+		t.Fatalf("Summarize() = %+v", sums)
+	}
+	if s := sums[1]; s.Min != 2 || s.Max != 9 || s.Mean != 5 || s.Last != 9 || s.Count != 3 {
+		t.Errorf("sessions_alive summary = %+v", s)
+	}
+}
@@ -0,0 +1,158 @@
+// Package notes provides per-issue scratchpads shared between the agent
+// working an issue and its supervisors. Agents append progress as they go;
+// the witness reads the last update time for stall detection and gt prime
+// replays recent entries when a session resumes, so progress survives the
+// agent's conversation context.
+package notes
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// entryPrefix starts every entry header line.
+const entryPrefix = "## "
+
+// Entry is one appended note.
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Author string    `json:"author,omitempty"`
+	Text   string    `json:"text"`
+}
+
+// Dir returns the directory holding notes files for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "notes")
+}
+
+// Path returns the notes file for an issue.
+func Path(townRoot, issueID string) string {
+	return filepath.Join(Dir(townRoot), issueID+".md")
+}
+
+// validateID rejects issue IDs that would escape the notes directory.
+func validateID(issueID string) error {
+	if issueID == "" || strings.ContainsAny(issueID, `/\`) || strings.HasPrefix(issueID, ".") {
+		return fmt.Errorf("invalid issue ID %q", issueID)
+	}
+	return nil
+}
+
+// Append adds an entry to an issue's notes. Each entry is written with a
+// single append so concurrent writers do not interleave.
+func Append(townRoot, issueID, author, text string) error {
+	if err := validateID(issueID); err != nil {
+		return err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("note text is empty")
+	}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating notes dir: %w", err)
+	}
+
+	header := entryPrefix + time.Now().UTC().Format(time.RFC3339)
+	if author != "" {
+		header += " " + author
+	}
+	f, err := os.OpenFile(Path(townRoot, issueID), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G304: path built from validated issue ID
+	if err != nil {
+		return fmt.Errorf("opening notes: %w", err)
+	}
+	if _, err := fmt.Fprintf(f, "%s\n%s\n\n", header, text); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("appending note: %w", err)
+	}
+	return f.Close()
+}
+
+// Read returns all entries for an issue, oldest first. A missing notes file
+// yields no entries and no error.
+func Read(townRoot, issueID string) ([]Entry, error) {
+	if err := validateID(issueID); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(Path(townRoot, issueID)) //nolint:gosec // G304: path built from validated issue ID
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading notes: %w", err)
+	}
+	return Parse(string(data)), nil
+}
+
+// Parse splits a notes file into entries. Lines before the first valid
+// header are ignored.
+func Parse(content string) []Entry {
+	var entries []Entry
+	var cur *Entry
+	var body []string
+
+	flush := func() {
+		if cur != nil {
+			cur.Text = strings.TrimSpace(strings.Join(body, "\n"))
+			entries = append(entries, *cur)
+		}
+		body = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if e, ok := parseHeader(line); ok {
+			flush()
+			cur = &e
+			continue
+		}
+		if cur != nil {
+			body = append(body, line)
+		}
+	}
+	flush()
+	return entries
+}
+
+// parseHeader parses "## <RFC3339> [author]".
+func parseHeader(line string) (Entry, bool) {
+	if !strings.HasPrefix(line, entryPrefix) {
+		return Entry{}, false
+	}
+	fields := strings.SplitN(strings.TrimPrefix(line, entryPrefix), " ", 2)
+	ts, err := time.Parse(time.RFC3339, fields[0])
+	if err != nil {
+		return Entry{}, false
+	}
+	e := Entry{Time: ts}
+	if len(fields) == 2 {
+		e.Author = strings.TrimSpace(fields[1])
+	}
+	return e, true
+}
+
+// Tail returns the last n entries (all of them when n <= 0).
+func Tail(entries []Entry, n int) []Entry {
+	if n <= 0 || len(entries) <= n {
+		return entries
+	}
+	return entries[len(entries)-n:]
+}
+
+// LastUpdated returns when an issue's notes were last appended to. ok is
+// false when the issue has no notes.
+func LastUpdated(townRoot, issueID string) (t time.Time, ok bool) {
+	if validateID(issueID) != nil {
+		return time.Time{}, false
+	}
+	info, err := os.Stat(Path(townRoot, issueID))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
@@ -0,0 +1,71 @@
+package notes
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAppendRead(t *testing.T) {
+	town := t.TempDir()
+
+	if entries, err := Read(town, "gt-abc"); err != nil || entries != nil {
+		t.Fatalf("Read(missing) = %v, %v; want nil, nil", entries, err)
+	}
+	if _, ok := LastUpdated(town, "gt-abc"); ok {
+		t.Fatal("LastUpdated(missing) ok = true")
+	}
+
+	if err := Append(town, "gt-abc", "greenplace/polecats/Toast", "Reproduced the bug"); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err := Append(town, "gt-abc", "", "Fix in parser.go\n\nTests pending"); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	entries, err := Read(town, "gt-abc")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Author != "greenplace/polecats/Toast" || entries[0].Text != "Reproduced the bug" {
+		t.Errorf("entry 0 = %+v", entries[0])
+	}
+	if entries[1].Author != "" || entries[1].Text != "Fix in parser.go\n\nTests pending" {
+		t.Errorf("entry 1 = %+v", entries[1])
+	}
+	if time.Since(entries[1].Time) > time.Minute {
+		t.Errorf("entry time = %v, want recent", entries[1].Time)
+	}
+	if _, ok := LastUpdated(town, "gt-abc"); !ok {
+		t.Error("LastUpdated ok = false after append")
+	}
+
+	if got := Tail(entries, 1); len(got) != 1 || got[0].Text != entries[1].Text {
+		t.Errorf("Tail(1) = %+v", got)
+	}
+}
+
+func TestAppendRejectsBadInput(t *testing.T) {
+	town := t.TempDir()
+	for _, id := range []string{"", "../x", "a/b", ".hidden"} {
+		if err := Append(town, id, "", "text"); err == nil {
+			t.Errorf("Append(%q) succeeded, want error", id)
+		}
+	}
+	if err := Append(town, "gt-abc", "", "   "); err == nil {
+		t.Error("Append(empty text) succeeded, want error")
+	}
+}
+
+func TestParseIgnoresPreamble(t *testing.T) {
+	content := "free text\n## not-a-time heading\n## 2026-01-02T03:04:05Z mayor\nline\n"
+	entries := Parse(content)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if entries[0].Author != "mayor" || entries[0].Text != "line" {
+		t.Errorf("entry = %+v", entries[0])
+	}
+}
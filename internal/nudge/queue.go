@@ -7,6 +7,9 @@
 //
 // Queue location: <townRoot>/.runtime/nudge_queue/<session>/
 // Each nudge is a JSON file named by timestamp for FIFO ordering.
+//
+// Scheduled nudges (gt nudge --at/--in) wait in
+// <townRoot>/.runtime/nudge_schedule/ until the daemon delivers them.
 package nudge
 
 import (
@@ -0,0 +1,200 @@
+package nudge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Scheduled nudge limits.
+const (
+	// MaxScheduleAttempts is how many times the daemon tries to deliver a
+	// scheduled nudge before giving up.
+	MaxScheduleAttempts = 3
+
+	// ScheduleRetryDelay is how long the daemon waits between attempts.
+	ScheduleRetryDelay = time.Minute
+
+	// MaxScheduleLateness is how overdue a scheduled nudge may be and still
+	// be delivered (e.g., after the daemon was down). Later than that, a
+	// timed reminder is more confusing than useful, so it is dropped.
+	MaxScheduleLateness = time.Hour
+)
+
+// ScheduleIDEnv is set by the daemon when it runs `gt nudge` to deliver a
+// scheduled nudge. gt nudge attributes the nudge to the sender recorded in
+// that schedule entry (see ReadClaimed) rather than to the daemon.
+const ScheduleIDEnv = "GT_NUDGE_SCHEDULE_ID"
+
+// ErrScheduledNotFound is returned by CancelScheduled for unknown IDs.
+var ErrScheduledNotFound = errors.New("scheduled nudge not found")
+
+// ScheduledNudge is a nudge to be delivered by the daemon at DeliverAt,
+// through `gt nudge` with the same target, mode, and priority.
+type ScheduledNudge struct {
+	ID        string    `json:"id"`
+	Target    string    `json:"target"` // as given to gt nudge (e.g., "gastown/Toast", "mayor")
+	Sender    string    `json:"sender"`
+	Message   string    `json:"message"`
+	Mode      string    `json:"mode,omitempty"`
+	Priority  string    `json:"priority,omitempty"`
+	Force     bool      `json:"force,omitempty"`
+	DeliverAt time.Time `json:"deliver_at"`
+	CreatedAt time.Time `json:"created_at"`
+	Attempts  int       `json:"attempts,omitempty"`
+}
+
+// scheduleDir returns the scheduled nudge directory.
+// Path: <townRoot>/.runtime/nudge_schedule/
+func scheduleDir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "nudge_schedule")
+}
+
+// Schedule stores a nudge for later delivery and returns its ID. An
+// existing ID is kept, so a retry overwrites the original entry.
+func Schedule(townRoot string, s ScheduledNudge) (string, error) {
+	if s.DeliverAt.IsZero() {
+		return "", fmt.Errorf("scheduled nudge has no delivery time")
+	}
+	if s.ID == "" {
+		s.ID = "sn-" + randomSuffix()
+	}
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now()
+	}
+	if s.Priority == "" {
+		s.Priority = PriorityNormal
+	}
+
+	dir := scheduleDir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating nudge schedule dir: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling scheduled nudge: %w", err)
+	}
+	// Write then rename so the daemon never reads a partial file.
+	path := filepath.Join(dir, s.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return "", fmt.Errorf("writing scheduled nudge: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return "", fmt.Errorf("writing scheduled nudge: %w", err)
+	}
+	return s.ID, nil
+}
+
+// ListScheduled returns all pending scheduled nudges, soonest first.
+func ListScheduled(townRoot string) ([]ScheduledNudge, error) {
+	entries, err := os.ReadDir(scheduleDir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading nudge schedule: %w", err)
+	}
+
+	var out []ScheduledNudge
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		s, err := readScheduled(filepath.Join(scheduleDir(townRoot), e.Name()))
+		if err != nil {
+			continue
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].DeliverAt.Equal(out[j].DeliverAt) {
+			return out[i].DeliverAt.Before(out[j].DeliverAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// CancelScheduled removes a pending scheduled nudge.
+func CancelScheduled(townRoot, id string) error {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return ErrScheduledNotFound
+	}
+	err := os.Remove(filepath.Join(scheduleDir(townRoot), id+".json"))
+	if os.IsNotExist(err) {
+		return ErrScheduledNotFound
+	}
+	return err
+}
+
+// ClaimDue removes and returns the scheduled nudges due at now, soonest
+// first. Each file is renamed before it is read, so concurrent callers
+// never claim the same nudge twice. The claimed entry stays readable with
+// ReadClaimed until the caller is done delivering it and calls
+// ReleaseClaimed.
+func ClaimDue(townRoot string, now time.Time) ([]ScheduledNudge, error) {
+	pending, err := ListScheduled(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var due []ScheduledNudge
+	for _, s := range pending {
+		if s.DeliverAt.After(now) {
+			continue
+		}
+		path := filepath.Join(scheduleDir(townRoot), s.ID+".json")
+		claimed := path + ".claimed"
+		if err := os.Rename(path, claimed); err != nil {
+			continue // claimed or cancelled by someone else
+		}
+		s, err := readScheduled(claimed)
+		if err != nil {
+			_ = os.Remove(claimed)
+			continue
+		}
+		due = append(due, s)
+	}
+	return due, nil
+}
+
+// ReadClaimed returns a scheduled nudge that is being delivered.
+func ReadClaimed(townRoot, id string) (ScheduledNudge, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return ScheduledNudge{}, ErrScheduledNotFound
+	}
+	s, err := readScheduled(filepath.Join(scheduleDir(townRoot), id+".json.claimed"))
+	if os.IsNotExist(err) {
+		return s, ErrScheduledNotFound
+	}
+	return s, err
+}
+
+// ReleaseClaimed removes a claimed scheduled nudge once delivery is over.
+func ReleaseClaimed(townRoot, id string) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return
+	}
+	_ = os.Remove(filepath.Join(scheduleDir(townRoot), id+".json.claimed"))
+}
+
+func readScheduled(path string) (ScheduledNudge, error) {
+	var s ScheduledNudge
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, err
+	}
+	return s, nil
+}
@@ -0,0 +1,104 @@
+package nudge
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduleListCancel(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+
+	later, err := Schedule(townRoot, ScheduledNudge{Target: "gastown/Toast", Message: "later", DeliverAt: now.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	sooner, err := Schedule(townRoot, ScheduledNudge{Target: "mayor", Message: "sooner", DeliverAt: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+
+	pending, err := ListScheduled(townRoot)
+	if err != nil {
+		t.Fatalf("ListScheduled: %v", err)
+	}
+	if len(pending) != 2 || pending[0].ID != sooner || pending[1].ID != later {
+		t.Fatalf("expected [%s %s] soonest first, got %+v", sooner, later, pending)
+	}
+	if pending[0].Priority != PriorityNormal {
+		t.Errorf("expected default priority %q, got %q", PriorityNormal, pending[0].Priority)
+	}
+
+	if err := CancelScheduled(townRoot, sooner); err != nil {
+		t.Fatalf("CancelScheduled: %v", err)
+	}
+	if err := CancelScheduled(townRoot, sooner); !errors.Is(err, ErrScheduledNotFound) {
+		t.Errorf("second cancel: got %v, want ErrScheduledNotFound", err)
+	}
+	if err := CancelScheduled(townRoot, "../escape"); !errors.Is(err, ErrScheduledNotFound) {
+		t.Errorf("path cancel: got %v, want ErrScheduledNotFound", err)
+	}
+	pending, _ = ListScheduled(townRoot)
+	if len(pending) != 1 || pending[0].ID != later {
+		t.Errorf("expected only %s left, got %+v", later, pending)
+	}
+}
+
+func TestScheduleRequiresTime(t *testing.T) {
+	if _, err := Schedule(t.TempDir(), ScheduledNudge{Target: "mayor", Message: "x"}); err == nil {
+		t.Error("expected error for nudge without delivery time")
+	}
+}
+
+func TestClaimDue(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+
+	due, _ := Schedule(townRoot, ScheduledNudge{Target: "mayor", Message: "due", DeliverAt: now.Add(-time.Minute)})
+	notDue, _ := Schedule(townRoot, ScheduledNudge{Target: "mayor", Message: "not yet", DeliverAt: now.Add(time.Hour)})
+
+	claimed, err := ClaimDue(townRoot, now)
+	if err != nil {
+		t.Fatalf("ClaimDue: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != due || claimed[0].Message != "due" {
+		t.Fatalf("expected %s claimed, got %+v", due, claimed)
+	}
+
+	// The entry being delivered is readable until released.
+	if s, err := ReadClaimed(townRoot, due); err != nil || s.Message != "due" {
+		t.Errorf("ReadClaimed = %+v, %v", s, err)
+	}
+	ReleaseClaimed(townRoot, due)
+	if _, err := ReadClaimed(townRoot, due); err != ErrScheduledNotFound {
+		t.Errorf("ReadClaimed after release = %v, want ErrScheduledNotFound", err)
+	}
+
+	// Claimed nudges are gone; a second claim finds nothing due.
+	if again, _ := ClaimDue(townRoot, now); len(again) != 0 {
+		t.Errorf("expected nothing on second claim, got %+v", again)
+	}
+	pending, _ := ListScheduled(townRoot)
+	if len(pending) != 1 || pending[0].ID != notDue {
+		t.Errorf("expected %s still pending, got %+v", notDue, pending)
+	}
+
+	// Rescheduling with the same ID (as the daemon does on retry) keeps it.
+	claimed[0].Attempts = 1
+	claimed[0].DeliverAt = now.Add(-time.Second)
+	if id, err := Schedule(townRoot, claimed[0]); err != nil || id != due {
+		t.Fatalf("reschedule: id=%s err=%v", id, err)
+	}
+	retried, _ := ClaimDue(townRoot, now)
+	if len(retried) != 1 || retried[0].Attempts != 1 {
+		t.Errorf("expected retried nudge with 1 attempt, got %+v", retried)
+	}
+}
+
+func TestListScheduledMissingDir(t *testing.T) {
+	pending, err := ListScheduled(t.TempDir())
+	if err != nil || len(pending) != 0 {
+		t.Errorf("expected empty list, got %v, %v", pending, err)
+	}
+}
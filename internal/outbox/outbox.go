@@ -0,0 +1,269 @@
+// Package outbox queues agent-facing gt commands that could not reach the
+// beads database. When Dolt is briefly unreachable, commands such as
+// gt done and gt escalate ack store themselves here instead of failing
+// halfway and leaving work state inconsistent; the daemon's outbox patrol
+// replays them, oldest first, once the database is back.
+package outbox
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// ReplayEnv is set in the environment of replayed commands, so a command
+// that finds the database unreachable again fails instead of re-queueing.
+const ReplayEnv = "GT_OUTBOX_REPLAY"
+
+// MaxAttempts is how many times the daemon replays an entry while the
+// database is reachable before moving it to the failed directory.
+const MaxAttempts = 5
+
+// ErrNotFound is returned for unknown entry IDs.
+var ErrNotFound = errors.New("outbox entry not found")
+
+// Entry is a queued gt command, replayed as `gt <Args>` in Dir with the
+// queueing agent's GT_*/BD_* environment.
+type Entry struct {
+	ID          string    `json:"id"`
+	Args        []string  `json:"args"`
+	Dir         string    `json:"dir,omitempty"`
+	Env         []string  `json:"env,omitempty"`
+	Actor       string    `json:"actor,omitempty"`
+	Reason      string    `json:"reason,omitempty"` // why it was queued
+	QueuedAt    time.Time `json:"queued_at"`
+	Attempts    int       `json:"attempts,omitempty"`
+	LastAttempt time.Time `json:"last_attempt,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// Command returns the entry's command line for display.
+func (e Entry) Command() string {
+	return "gt " + strings.Join(e.Args, " ")
+}
+
+// Dir returns the pending outbox directory.
+// Path: <townRoot>/.runtime/outbox/
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "outbox")
+}
+
+// FailedDir returns where entries go after MaxAttempts failed replays.
+func FailedDir(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "failed")
+}
+
+// Reachable returns nil if the beads database can be reached. Towns
+// without a Dolt server have nothing to wait for.
+func Reachable(townRoot string) error {
+	if len(doltserver.HasServerModeMetadata(townRoot)) == 0 {
+		return nil
+	}
+	return doltserver.CheckServerReachable(townRoot)
+}
+
+// Replaying reports whether this process is an outbox replay.
+func Replaying() bool {
+	return os.Getenv(ReplayEnv) != ""
+}
+
+// CaptureEnv returns the GT_*, BD_* and BEADS_* variables of the current
+// environment: what identifies the agent to a replayed command.
+func CaptureEnv() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		if agentVar(kv) {
+			env = append(env, kv)
+		}
+	}
+	sort.Strings(env)
+	return env
+}
+
+// ReplayEnviron returns the environment to replay e with: base without its
+// agent variables, then e's, then ReplayEnv.
+func ReplayEnviron(base []string, e Entry) []string {
+	var env []string
+	for _, kv := range base {
+		if !agentVar(kv) {
+			env = append(env, kv)
+		}
+	}
+	env = append(env, e.Env...)
+	return append(env, ReplayEnv+"=1")
+}
+
+func agentVar(kv string) bool {
+	for _, prefix := range []string{"GT_", "BD_", "BEADS_"} {
+		if strings.HasPrefix(kv, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add stores an entry and returns its ID. An existing ID is kept, so
+// re-adding a replayed entry overwrites the original.
+func Add(townRoot string, e Entry) (string, error) {
+	if len(e.Args) == 0 {
+		return "", fmt.Errorf("outbox entry has no command")
+	}
+	if e.ID == "" {
+		e.ID = newID()
+	}
+	if e.QueuedAt.IsZero() {
+		e.QueuedAt = time.Now()
+	}
+	if err := write(Dir(townRoot), e); err != nil {
+		return "", err
+	}
+	return e.ID, nil
+}
+
+// List returns the pending entries, oldest first.
+func List(townRoot string) ([]Entry, error) {
+	return list(Dir(townRoot))
+}
+
+// ListFailed returns the entries that gave up, oldest first.
+func ListFailed(townRoot string) ([]Entry, error) {
+	return list(FailedDir(townRoot))
+}
+
+// Claim removes a pending entry and returns it. The file is renamed before
+// it is read, so concurrent callers never replay the same entry twice.
+func Claim(townRoot, id string) (Entry, error) {
+	if !validID(id) {
+		return Entry{}, ErrNotFound
+	}
+	path := filepath.Join(Dir(townRoot), id+".json")
+	claimed := path + ".claimed"
+	if err := os.Rename(path, claimed); err != nil {
+		return Entry{}, ErrNotFound
+	}
+	e, err := read(claimed)
+	_ = os.Remove(claimed)
+	return e, err
+}
+
+// Fail moves an entry to the failed directory.
+func Fail(townRoot string, e Entry) error {
+	return write(FailedDir(townRoot), e)
+}
+
+// Retry moves a failed entry back to the pending outbox with its attempts
+// reset.
+func Retry(townRoot, id string) error {
+	if !validID(id) {
+		return ErrNotFound
+	}
+	path := filepath.Join(FailedDir(townRoot), id+".json")
+	e, err := read(path)
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	e.Attempts, e.LastError = 0, ""
+	if _, err := Add(townRoot, e); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Drop deletes a pending or failed entry without replaying it.
+func Drop(townRoot, id string) error {
+	if !validID(id) {
+		return ErrNotFound
+	}
+	for _, dir := range []string{Dir(townRoot), FailedDir(townRoot)} {
+		err := os.Remove(filepath.Join(dir, id+".json"))
+		if err == nil {
+			return nil
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return ErrNotFound
+}
+
+func write(dir string, e Entry) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating outbox dir: %w", err)
+	}
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling outbox entry: %w", err)
+	}
+	// Write then rename so the daemon never reads a partial file.
+	path := filepath.Join(dir, e.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("writing outbox entry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing outbox entry: %w", err)
+	}
+	return nil
+}
+
+func list(dir string) ([]Entry, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading outbox: %w", err)
+	}
+	var out []Entry
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		e, err := read(filepath.Join(dir, f.Name()))
+		if err != nil {
+			continue
+		}
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].QueuedAt.Equal(out[j].QueuedAt) {
+			return out[i].QueuedAt.Before(out[j].QueuedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+func read(path string) (Entry, error) {
+	var e Entry
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return e, err
+	}
+	err = json.Unmarshal(data, &e)
+	return e, err
+}
+
+func newID() string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return "ob-" + hex.EncodeToString(b[:])
+}
+
+func validID(id string) bool {
+	return id != "" && !strings.ContainsAny(id, `/\`) && !strings.HasPrefix(id, ".")
+}
@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAddClaimFailRetry(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now()
+
+	second, err := Add(town, Entry{Args: []string{"escalate", "ack", "hq-2"}, QueuedAt: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := Add(town, Entry{Args: []string{"done"}, QueuedAt: now.Add(-time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Add(town, Entry{}); err == nil {
+		t.Error("Add with no command succeeded")
+	}
+
+	pending, err := List(town)
+	if err != nil || len(pending) != 2 || pending[0].ID != first || pending[1].ID != second {
+		t.Fatalf("List = %+v, %v; want %s then %s", pending, err, first, second)
+	}
+
+	e, err := Claim(town, first)
+	if err != nil || e.Command() != "gt done" {
+		t.Fatalf("Claim = %+v, %v", e, err)
+	}
+	if _, err := Claim(town, first); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Claim err = %v, want ErrNotFound", err)
+	}
+
+	e.Attempts, e.LastError = MaxAttempts, "exit status 1"
+	if err := Fail(town, e); err != nil {
+		t.Fatal(err)
+	}
+	if failed, _ := ListFailed(town); len(failed) != 1 || failed[0].LastError != "exit status 1" {
+		t.Fatalf("ListFailed = %+v", failed)
+	}
+
+	if err := Retry(town, first); err != nil {
+		t.Fatal(err)
+	}
+	pending, _ = List(town)
+	if len(pending) != 2 || pending[0].ID != first || pending[0].Attempts != 0 {
+		t.Fatalf("after Retry, List = %+v", pending)
+	}
+	if failed, _ := ListFailed(town); len(failed) != 0 {
+		t.Errorf("failed entry kept after Retry: %+v", failed)
+	}
+
+	if err := Drop(town, second); err != nil {
+		t.Fatal(err)
+	}
+	if err := Drop(town, "../x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Drop(../x) err = %v", err)
+	}
+}
+
+func TestReplayEnviron(t *testing.T) {
+	base := []string{"PATH=/bin", "GT_ROLE=deacon", "BD_ACTOR=deacon", "HOME=/root"}
+	e := Entry{Env: []string{"GT_ROLE=polecat", "GT_POLECAT=Toast", "BD_ACTOR=greenplace/polecats/Toast"}}
+
+	got := ReplayEnviron(base, e)
+	want := []string{"PATH=/bin", "HOME=/root", "GT_ROLE=polecat", "GT_POLECAT=Toast", "BD_ACTOR=greenplace/polecats/Toast", ReplayEnv + "=1"}
+	if len(got) != len(want) {
+		t.Fatalf("ReplayEnviron = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ReplayEnviron[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReachable_NoServer(t *testing.T) {
+	if err := Reachable(t.TempDir()); err != nil {
+		t.Errorf("Reachable without a Dolt server = %v, want nil", err)
+	}
+}
@@ -0,0 +1,51 @@
+// Package platform abstracts the OS-specific parts of process inspection and
+// default paths so callers do not hard-code Linux assumptions such as /proc.
+//
+// Linux reads /proc directly. macOS (and other Unix systems without /proc)
+// fall back to pgrep and ps. Windows provides process existence and
+// termination only; tree walking returns ErrUnsupported.
+package platform
+
+import (
+	"errors"
+	"os"
+	"sort"
+)
+
+// ErrUnsupported is returned by operations not available on this OS.
+var ErrUnsupported = errors.New("not supported on this platform")
+
+// Descendants returns every descendant of pid, depth first, deepest last.
+// Processes that exit during the walk are skipped.
+func Descendants(pid int) []int {
+	var out []int
+	seen := map[int]bool{pid: true}
+	var walk func(int)
+	walk = func(p int) {
+		children, err := Children(p)
+		if err != nil {
+			return
+		}
+		sort.Ints(children)
+		for _, c := range children {
+			if seen[c] {
+				continue
+			}
+			seen[c] = true
+			out = append(out, c)
+			walk(c)
+		}
+	}
+	walk(pid)
+	return out
+}
+
+// RuntimeDir returns a per-user directory for short-lived runtime files
+// (locks, signal bookkeeping). Uses $XDG_RUNTIME_DIR when set, otherwise the
+// OS temp dir (per-user on macOS, /tmp on Linux).
+func RuntimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir
+	}
+	return os.TempDir()
+}
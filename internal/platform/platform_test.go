@@ -0,0 +1,64 @@
+//go:build !windows
+
+package platform
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestProcessExists(t *testing.T) {
+	if !ProcessExists(os.Getpid()) {
+		t.Error("ProcessExists(self) = false")
+	}
+	if ProcessExists(0) || ProcessExists(-1) {
+		t.Error("ProcessExists should reject non-positive PIDs")
+	}
+	if ProcessExists(999999999) {
+		t.Error("ProcessExists(999999999) = true, want false")
+	}
+}
+
+func TestChildrenAndDescendants(t *testing.T) {
+	// sh -> sleep gives a two-level tree rooted at the test process.
+	cmd := exec.Command("sh", "-c", "sleep 30 & wait")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sh: %v", err)
+	}
+	defer func() {
+		for _, pid := range Descendants(cmd.Process.Pid) {
+			_ = Kill(pid)
+		}
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	var desc []int
+	for i := 0; i < 50; i++ {
+		if desc = Descendants(os.Getpid()); len(desc) >= 2 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if len(desc) < 2 {
+		t.Fatalf("Descendants(self) = %v, want sh and sleep", desc)
+	}
+
+	children, err := Children(cmd.Process.Pid)
+	if err != nil || len(children) != 1 {
+		t.Fatalf("Children(sh) = %v, %v; want one child", children, err)
+	}
+	argv, err := Cmdline(children[0])
+	if err != nil || len(argv) == 0 || argv[0] != "sleep" {
+		t.Errorf("Cmdline(sleep) = %v, %v", argv, err)
+	}
+}
+
+func TestParsePIDs(t *testing.T) {
+	got := parsePIDs("12 34\n56 x -1\n")
+	if len(got) != 3 || got[0] != 12 || got[2] != 56 {
+		t.Errorf("parsePIDs = %v", got)
+	}
+}
@@ -0,0 +1,43 @@
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Children returns the direct children of pid. Reads the children list of
+// every thread under /proc/<pid>/task, falling back to pgrep when /proc is
+// unavailable (e.g., CONFIG_PROC_CHILDREN disabled).
+func Children(pid int) ([]int, error) {
+	tasks, err := os.ReadDir(filepath.Join("/proc", strconv.Itoa(pid), "task"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return childrenPgrep(pid)
+	}
+	var children []int
+	for _, task := range tasks {
+		data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "task", task.Name(), "children"))
+		if err != nil {
+			return childrenPgrep(pid)
+		}
+		children = append(children, parsePIDs(string(data))...)
+	}
+	return children, nil
+}
+
+// Cmdline returns the argv of pid.
+func Cmdline(pid int) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "cmdline"))
+	if err != nil {
+		return nil, err
+	}
+	data = []byte(strings.TrimRight(string(data), "\x00"))
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return strings.Split(string(data), "\x00"), nil
+}
@@ -0,0 +1,25 @@
+//go:build !linux && !windows
+
+package platform
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Children returns the direct children of pid using pgrep, since macOS and
+// the BSDs have no /proc.
+func Children(pid int) ([]int, error) {
+	return childrenPgrep(pid)
+}
+
+// Cmdline returns the argv of pid via ps. ps reports the command as a single
+// string, so arguments containing spaces cannot be split back exactly.
+func Cmdline(pid int) ([]string, error) {
+	out, err := exec.Command("ps", "-o", "command=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
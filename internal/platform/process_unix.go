@@ -0,0 +1,54 @@
+//go:build !windows
+
+package platform
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ProcessExists reports whether a process with the given PID is alive.
+// A process owned by another user (EPERM) counts as existing.
+func ProcessExists(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// Terminate asks the process to exit (SIGTERM).
+func Terminate(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
+
+// Kill forcibly stops the process (SIGKILL).
+func Kill(pid int) error {
+	return syscall.Kill(pid, syscall.SIGKILL)
+}
+
+// childrenPgrep lists direct children using pgrep -P, which is available on
+// both macOS and Linux. pgrep exits 1 when nothing matches.
+func childrenPgrep(pid int) ([]int, error) {
+	out, err := exec.Command("pgrep", "-P", strconv.Itoa(pid)).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parsePIDs(string(out)), nil
+}
+
+// parsePIDs parses whitespace-separated PIDs, skipping anything non-numeric.
+func parsePIDs(s string) []int {
+	var pids []int
+	for _, f := range strings.Fields(s) {
+		if n, err := strconv.Atoi(f); err == nil && n > 0 {
+			pids = append(pids, n)
+		}
+	}
+	return pids
+}
@@ -0,0 +1,48 @@
+//go:build windows
+
+package platform
+
+import (
+	"math"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// ProcessExists reports whether a process with the given PID is alive.
+func ProcessExists(pid int) bool {
+	if pid <= 0 || pid > math.MaxUint32 {
+		return false
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return err == windows.ERROR_ACCESS_DENIED
+	}
+	_ = windows.CloseHandle(handle)
+	return true
+}
+
+// Terminate stops the process. Windows has no SIGTERM equivalent for
+// console-less processes, so this is the same as Kill.
+func Terminate(pid int) error {
+	return Kill(pid)
+}
+
+// Kill forcibly stops the process.
+func Kill(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
+
+// Children is not supported on Windows.
+func Children(pid int) ([]int, error) {
+	return nil, ErrUnsupported
+}
+
+// Cmdline is not supported on Windows.
+func Cmdline(pid int) ([]string, error) {
+	return nil, ErrUnsupported
+}
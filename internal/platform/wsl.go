@@ -0,0 +1,86 @@
+package platform
+
+import (
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// procVersionPath is read to detect WSL; overridable in tests.
+var procVersionPath = "/proc/version"
+
+// IsWSL reports whether the process is running under Windows Subsystem for
+// Linux. WSL sets WSL_DISTRO_NAME for login shells; services started by
+// systemd may not inherit it, so the kernel version string is checked too.
+// The answer is worked out once per process.
+func IsWSL() bool {
+	return isWSL()
+}
+
+// isWSL caches detectWSL; tests replace it.
+var isWSL = sync.OnceValue(detectWSL)
+
+func detectWSL() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	data, err := os.ReadFile(procVersionPath)
+	if err != nil {
+		return false
+	}
+	v := strings.ToLower(string(data))
+	return strings.Contains(v, "microsoft") || strings.Contains(v, "wsl")
+}
+
+var windowsDriveRe = regexp.MustCompile(`^([A-Za-z]):[\\/]?`)
+
+// WindowsToWSLPath converts a Windows path such as C:\Users\me\gt to its
+// WSL mount (/mnt/c/Users/me/gt). Returns false if p is not a drive path.
+func WindowsToWSLPath(p string) (string, bool) {
+	m := windowsDriveRe.FindStringSubmatch(p)
+	if m == nil {
+		return "", false
+	}
+	rest := strings.ReplaceAll(p[len(m[0]):], `\`, "/")
+	out := "/mnt/" + strings.ToLower(m[1])
+	if rest != "" {
+		out += "/" + rest
+	}
+	return out, true
+}
+
+// LocalPath returns p in a form this process can open. Under WSL, Windows
+// drive paths (typed by the user or handed over by Windows-side tools and
+// environment variables such as GT_TOWN_ROOT) become their /mnt mount;
+// everything else is returned unchanged.
+func LocalPath(p string) string {
+	if !IsWSL() {
+		return p
+	}
+	if wsl, ok := WindowsToWSLPath(p); ok {
+		return wsl
+	}
+	return p
+}
+
+var wslMountRe = regexp.MustCompile(`^/mnt/[a-z](/|$)`)
+
+// IsWindowsMount reports whether p lives on a Windows drive mounted into WSL
+// (/mnt/c/...). Those mounts go through a 9P bridge: slow for git worktrees
+// and lacking reliable file locking and inotify.
+func IsWindowsMount(p string) bool {
+	return wslMountRe.MatchString(p)
+}
+
+// SystemdRunning reports whether systemd is PID 1, which user services
+// (gt daemon install) require. WSL only runs systemd when enabled in
+// /etc/wsl.conf.
+func SystemdRunning() bool {
+	info, err := os.Stat("/run/systemd/system")
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,66 @@
+package platform
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestWindowsToWSLPath(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{`C:\Users\me\gt`, "/mnt/c/Users/me/gt", true},
+		{`d:/work`, "/mnt/d/work", true},
+		{`E:`, "/mnt/e", true},
+		{"/home/me/gt", "", false},
+		{`\\server\share`, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := WindowsToWSLPath(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("WindowsToWSLPath(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDetectWSL(t *testing.T) {
+	procVersionPath = "/nonexistent"
+	t.Cleanup(func() { procVersionPath = "/proc/version" })
+
+	t.Setenv("WSL_DISTRO_NAME", "")
+	if detectWSL() {
+		t.Error("detected WSL without WSL_DISTRO_NAME or a WSL kernel")
+	}
+	t.Setenv("WSL_DISTRO_NAME", "Ubuntu")
+	if got := detectWSL(); got != (runtime.GOOS == "linux") {
+		t.Errorf("detectWSL with WSL_DISTRO_NAME = %v", got)
+	}
+}
+
+func TestLocalPath(t *testing.T) {
+	orig := isWSL
+	t.Cleanup(func() { isWSL = orig })
+
+	isWSL = func() bool { return false }
+	if got := LocalPath(`C:\gt`); got != `C:\gt` {
+		t.Errorf("outside WSL, LocalPath = %q; want it unchanged", got)
+	}
+	isWSL = func() bool { return true }
+	if got := LocalPath(`C:\Users\me\gt`); got != "/mnt/c/Users/me/gt" {
+		t.Errorf("under WSL, LocalPath = %q", got)
+	}
+	if got := LocalPath("/home/me/gt"); got != "/home/me/gt" {
+		t.Errorf("LocalPath(unix path) = %q", got)
+	}
+}
+
+func TestIsWindowsMount(t *testing.T) {
+	if !IsWindowsMount("/mnt/c/Users/me") || !IsWindowsMount("/mnt/d") {
+		t.Error("drive mounts not detected")
+	}
+	if IsWindowsMount("/mnt/data/gt") || IsWindowsMount("/home/me") {
+		t.Error("non-drive paths misdetected")
+	}
+}
@@ -0,0 +1,16 @@
+package polecat
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// RenewClaim takes or renews the polecat's dispatch claim on issueID, so no
+// other dispatch can assign the issue while the polecat works it. Returns a
+// *beads.ClaimHeldError if someone else holds the claim.
+func (m *SessionManager) RenewClaim(polecat, issueID string) error {
+	agentID := fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat)
+	bd := beads.New(m.resolveBeadsDir(issueID, m.clonePath(polecat)))
+	return bd.ClaimIssue(issueID, agentID, beads.ClaimOptions{})
+}
@@ -0,0 +1,165 @@
+package polecat
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/runcontext"
+)
+
+// contextEnvLabels names the session environment variables derived from
+// state that can change while a polecat works, for change descriptions.
+var contextEnvLabels = map[string]string{
+	"GT_BRANCH":         "working branch",
+	"GT_ISSUE":          "hooked issue",
+	"GT_ISSUE_PRIORITY": "issue priority",
+	"GT_TARGET_BRANCH":  "target branch",
+}
+
+// EnvChange is a session environment variable that no longer matched the
+// state it is derived from.
+type EnvChange struct {
+	Key string `json:"key"`
+	Old string `json:"old,omitempty"`
+	New string `json:"new"`
+}
+
+// Notable reports whether the agent should be told about the change. Filling
+// in a variable the session never had is bookkeeping, not news.
+func (c EnvChange) Notable() bool {
+	return c.Old != ""
+}
+
+// EnvReconciliation is the result of reconciling one session.
+type EnvReconciliation struct {
+	Session string      `json:"session"`
+	Changes []EnvChange `json:"changes,omitempty"`
+	Nudged  bool        `json:"nudged,omitempty"`
+
+	// ClaimHeldBy is set when another dispatch holds the claim on the
+	// session's issue: the issue may have been assigned twice.
+	ClaimHeldBy string `json:"claim_held_by,omitempty"`
+}
+
+// contextEnv returns the session environment derived from live state: the
+// worktree's branch, the hooked issue and its priority, and the rig's
+// target branch. Values that can't be read are left out, so a transient
+// beads or git error never clears what the session already has.
+func (m *SessionManager) contextEnv(workDir, issueID string) map[string]string {
+	env := map[string]string{
+		"GT_TARGET_BRANCH": m.rig.DefaultBranch(),
+	}
+	if branch, err := git.NewGit(workDir).CurrentBranch(); err == nil && branch != "" {
+		env["GT_BRANCH"] = branch
+	}
+	if issueID != "" {
+		env["GT_ISSUE"] = issueID
+		if issue, err := beads.New(m.resolveBeadsDir(issueID, workDir)).Show(issueID); err == nil {
+			env["GT_ISSUE_PRIORITY"] = fmt.Sprintf("P%d", issue.Priority)
+		}
+	}
+	return env
+}
+
+// diffContextEnv returns the desired values that differ from current,
+// sorted by key.
+func diffContextEnv(current, desired map[string]string) []EnvChange {
+	var changes []EnvChange
+	for k, v := range desired {
+		if current[k] != v {
+			changes = append(changes, EnvChange{Key: k, Old: current[k], New: v})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// FormatEnvChangeNudge describes changes to an agent. Only notable changes
+// are listed; refreshed reports whether the run-context file was rewritten.
+func FormatEnvChangeNudge(changes []EnvChange, refreshed bool) string {
+	var b strings.Builder
+	b.WriteString("[GAS TOWN] Your dispatch context changed:\n")
+	for _, c := range changes {
+		if !c.Notable() {
+			continue
+		}
+		label := contextEnvLabels[c.Key]
+		if label == "" {
+			label = c.Key
+		}
+		fmt.Fprintf(&b, "- %s: %s → %s\n", label, c.Old, c.New)
+	}
+	b.WriteString("Your session environment is updated; use the new values from here on.")
+	if refreshed {
+		fmt.Fprintf(&b, " Refreshed: %s", runcontext.RelMarkdownPath())
+	}
+	return b.String()
+}
+
+// ReconcileEnv brings a running polecat session's environment in line with
+// the state it was derived from, for work hooked as issueID ("" = none).
+// A running agent does not see tmux environment updates, so notable changes
+// are also nudged to it and its run-context file is rewritten. The session's
+// dispatch claim on issueID is renewed. With dryRun nothing is changed.
+func (m *SessionManager) ReconcileEnv(polecat, issueID string, dryRun bool) (*EnvReconciliation, error) {
+	sessionID := m.SessionName(polecat)
+	running, err := m.tmux.HasSession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("checking session: %w", err)
+	}
+	if !running {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, sessionID)
+	}
+
+	current, err := m.tmux.GetAllEnvironment(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("reading environment of %s: %w", sessionID, err)
+	}
+	workDir := m.clonePath(polecat)
+	result := &EnvReconciliation{
+		Session: sessionID,
+		Changes: diffContextEnv(current, m.contextEnv(workDir, issueID)),
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	// Other renewal errors are left for the next patrol: the claim outlives
+	// a missed renewal.
+	if issueID != "" {
+		var held *beads.ClaimHeldError
+		if errors.As(m.RenewClaim(polecat, issueID), &held) {
+			result.ClaimHeldBy = held.Claim.Holder
+		}
+	}
+	if len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	notable := false
+	for _, c := range result.Changes {
+		if err := m.tmux.SetEnvironment(sessionID, c.Key, c.New); err != nil {
+			return result, fmt.Errorf("setting %s in %s: %w", c.Key, sessionID, err)
+		}
+		notable = notable || c.Notable()
+	}
+	if !notable {
+		return result, nil
+	}
+
+	refreshed := false
+	if issueID != "" {
+		if rc, err := runcontext.Read(workDir); err == nil && rc != nil {
+			refreshed = m.WriteRunContext(polecat, workDir, issueID, rc.Runtime) == nil
+		}
+	}
+	if err := m.tmux.NudgeSession(sessionID, FormatEnvChangeNudge(result.Changes, refreshed)); err != nil {
+		return result, fmt.Errorf("nudging %s: %w", sessionID, err)
+	}
+	result.Nudged = true
+	return result, nil
+}
@@ -0,0 +1,49 @@
+package polecat
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffContextEnv(t *testing.T) {
+	current := map[string]string{
+		"GT_BRANCH":         "polecat/Toast/gt-abc",
+		"GT_ISSUE_PRIORITY": "P2",
+		"GT_TARGET_BRANCH":  "master",
+		"GT_RIG":            "gastown", // not derived: ignored
+	}
+	desired := map[string]string{
+		"GT_BRANCH":         "polecat/Toast/gt-abc",
+		"GT_ISSUE":          "gt-abc",
+		"GT_ISSUE_PRIORITY": "P0",
+		"GT_TARGET_BRANCH":  "main",
+	}
+	got := diffContextEnv(current, desired)
+	want := []EnvChange{
+		{Key: "GT_ISSUE", New: "gt-abc"},
+		{Key: "GT_ISSUE_PRIORITY", Old: "P2", New: "P0"},
+		{Key: "GT_TARGET_BRANCH", Old: "master", New: "main"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffContextEnv = %+v, want %+v", got, want)
+	}
+	if diffContextEnv(desired, desired) != nil {
+		t.Error("diffContextEnv of equal maps should be empty")
+	}
+}
+
+func TestFormatEnvChangeNudge(t *testing.T) {
+	got := FormatEnvChangeNudge([]EnvChange{
+		{Key: "GT_ISSUE", New: "gt-abc"},
+		{Key: "GT_ISSUE_PRIORITY", Old: "P2", New: "P0"},
+	}, true)
+	for _, want := range []string{"issue priority: P2 → P0", ".runtime/run-context.md"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("nudge missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "hooked issue") {
+		t.Errorf("nudge lists a change that only filled in an unset value:\n%s", got)
+	}
+}
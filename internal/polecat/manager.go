@@ -12,7 +12,6 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/gofrs/flock"
@@ -21,6 +20,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/platform"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
@@ -409,6 +409,12 @@ func (m *Manager) checkCleanupStatus(name string, status CleanupStatus, force bo
 	}
 }
 
+// RepoBase returns the repository polecat worktrees are created from: the
+// rig's shared bare repo, or mayor/rig for legacy rigs.
+func (m *Manager) RepoBase() (*git.Git, error) {
+	return m.repoBase()
+}
+
 // repoBase returns the git directory and Git object to use for worktree operations.
 // Prefers the shared bare repo (.repo.git) if it exists, otherwise falls back to mayor/rig.
 // The bare repo architecture allows all worktrees (refinery, polecats) to share branch visibility.
@@ -481,6 +487,7 @@ func (m *Manager) exists(name string) bool {
 type AddOptions struct {
 	HookBead   string // Bead ID to set as hook_bead at spawn time (atomic assignment)
 	BaseBranch string // Override base branch for worktree (e.g., "origin/integration/gt-epic")
+	NoPool     bool   // Create a fresh worktree even if the rig's pool has one ready
 }
 
 // Add creates a new polecat as a git worktree from the repo base.
@@ -677,15 +684,9 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (_ *Polecat, retE
 	}
 
 	// Determine the start point for the new worktree
-	var startPoint string
-	if opts.BaseBranch != "" {
-		startPoint = opts.BaseBranch
-	} else {
-		defaultBranch := "main"
-		if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
-			defaultBranch = rigCfg.DefaultBranch
-		}
-		startPoint = fmt.Sprintf("origin/%s", defaultBranch)
+	startPoint := opts.BaseBranch
+	if startPoint == "" {
+		startPoint = m.defaultStartPoint()
 	}
 
 	// Validate that startPoint ref exists before attempting worktree creation
@@ -705,13 +706,22 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (_ *Polecat, retE
 
 	// Always create fresh branch - unique name guarantees no collision
 	// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
-	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
-	if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
-		cleanupOnError()
-		return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
+	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics.
+	// A warm worktree from the rig's pool is claimed first if one is ready.
+	if opts.NoPool || !m.claimPooledWorktree(repoGit, clonePath, branchName, startPoint) {
+		if err := m.addWorktree(repoGit, clonePath, branchName, startPoint); err != nil {
+			cleanupOnError()
+			return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
+		}
 	}
 	worktreeCreated = true
 
+	// Pointer files would leave the polecat with broken binary assets.
+	if err := m.pullLFS(clonePath); err != nil {
+		cleanupOnError()
+		return nil, fmt.Errorf("pulling LFS content: %w", err)
+	}
+
 	// NOTE: No per-directory CLAUDE.md or AGENTS.md is created here.
 	// Only ~/gt/CLAUDE.md (town-root identity anchor) exists on disk.
 	// Full context is injected ephemerally via SessionStart hook (gt prime).
@@ -848,7 +858,7 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear, selfNuke bool)
 				// For backward compatibility: force only bypasses uncommitted changes, not stashes/unpushed
 				if force {
 					// Force mode: allow uncommitted changes but still block on stashes/unpushed
-					if status.StashCount > 0 || status.UnpushedCommits > 0 {
+					if status.StashCount > 0 || status.UnpushedCommits > 0 || status.UnpushedSubmoduleCommits > 0 {
 						return &UncommittedWorkError{PolecatName: name, Status: status}
 					}
 				} else {
@@ -1150,15 +1160,9 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	}
 
 	// Determine the start point for the new worktree
-	var startPoint string
-	if opts.BaseBranch != "" {
-		startPoint = opts.BaseBranch
-	} else {
-		defaultBranch := "main"
-		if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
-			defaultBranch = rigCfg.DefaultBranch
-		}
-		startPoint = fmt.Sprintf("origin/%s", defaultBranch)
+	startPoint := opts.BaseBranch
+	if startPoint == "" {
+		startPoint = m.defaultStartPoint()
 	}
 
 	// Validate that startPoint ref exists before attempting worktree creation
@@ -1179,9 +1183,14 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	branchName := m.buildBranchName(name, opts.HookBead)
 	tmpClonePath := newClonePath + ".repair-tmp"
 	_ = os.RemoveAll(tmpClonePath) // clean up any leftover temp dir
-	if err := repoGit.WorktreeAddFromRef(tmpClonePath, branchName, startPoint); err != nil {
+	if err := m.addWorktree(repoGit, tmpClonePath, branchName, startPoint); err != nil {
 		return nil, fmt.Errorf("creating fresh worktree from %s: %w", startPoint, err)
 	}
+	if err := m.pullLFS(tmpClonePath); err != nil {
+		_ = repoGit.WorktreeRemove(tmpClonePath, true)
+		_ = os.RemoveAll(tmpClonePath)
+		return nil, fmt.Errorf("pulling LFS content: %w", err)
+	}
 
 	// New worktree created successfully — now safe to remove old worktree and reset bead.
 	// Remove old worktree BEFORE resetting bead to prevent name collision if a new
@@ -1388,15 +1397,7 @@ func isSessionProcessDead(t *tmux.Tmux, sessionName string) bool {
 		// Got a non-numeric PID — shouldn't happen, but don't kill.
 		return false
 	}
-	p, err := os.FindProcess(pid)
-	if err != nil {
-		return true
-	}
-	// On Unix, Signal(0) checks if process exists without sending a signal
-	if err := p.Signal(syscall.Signal(0)); err != nil {
-		return true
-	}
-	return false
+	return !platform.ProcessExists(pid)
 }
 
 // pendingMaxAge is how long a .pending reservation marker may exist before
@@ -1689,6 +1690,17 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 		}, nil
 	}
 
+	// Warm standby: no hook_bead, session pre-started at an idle prompt.
+	if agentErr == nil && fields != nil && fields.AgentState == AgentStateStandby {
+		return &Polecat{
+			Name:      name,
+			Rig:       m.rig.Name,
+			State:     StateStandby,
+			ClonePath: clonePath,
+			Branch:    branchName,
+		}, nil
+	}
+
 	// Fallback: Query beads for assigned issue (for polecats without agent beads
 	// or with empty hook_bead)
 	assignee := m.assigneeID(name)
@@ -0,0 +1,79 @@
+package polecat
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cli"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/runcontext"
+)
+
+// WriteRunContext writes the run-context file for a polecat dispatched on
+// issueID into its worktree. agent is the runtime override ("" = rig default).
+func (m *SessionManager) WriteRunContext(polecat, workDir, issueID, agent string) error {
+	issue, err := beads.New(m.resolveBeadsDir(issueID, workDir)).Show(issueID)
+	if err != nil {
+		return fmt.Errorf("loading issue %s: %w", issueID, err)
+	}
+	rc := &runcontext.RunContext{
+		Rig:        m.rig.Name,
+		Agent:      "polecat " + polecat,
+		Session:    m.SessionName(polecat),
+		Runtime:    agent,
+		BaseBranch: m.rig.DefaultBranch(),
+		Issue: &runcontext.Issue{
+			ID:                 issue.ID,
+			Title:              issue.Title,
+			Type:               issue.Type,
+			Priority:           issue.Priority,
+			Description:        issue.Description,
+			AcceptanceCriteria: issue.AcceptanceCriteria,
+		},
+		Commands: runcontext.PolecatCommands(issueID),
+	}
+	if branch, err := git.NewGit(workDir).CurrentBranch(); err == nil {
+		rc.Branch = branch
+	}
+
+	attachment := beads.ParseAttachmentFields(issue)
+	if attachment != nil {
+		rc.Formula = attachment.AttachedFormula
+		rc.Molecule = attachment.AttachedMolecule
+		rc.Args = attachment.AttachedArgs
+		rc.DispatchedBy = attachment.DispatchedBy
+	}
+	rc.Constraints = polecatConstraints(rc.Branch, rc.BaseBranch, attachment)
+
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(m.rig.Path)); err == nil && settings.Budget != nil {
+		if settings.Budget.DailyUSD > 0 {
+			rc.Budgets = append(rc.Budgets, runcontext.Budget{Name: "daily", LimitUSD: settings.Budget.DailyUSD})
+		}
+		if settings.Budget.WeeklyUSD > 0 {
+			rc.Budgets = append(rc.Budgets, runcontext.Budget{Name: "weekly", LimitUSD: settings.Budget.WeeklyUSD})
+		}
+	}
+
+	return runcontext.Write(workDir, rc)
+}
+
+// polecatConstraints lists the rules a polecat's work runs under.
+func polecatConstraints(branch, baseBranch string, attachment *beads.AttachmentFields) []string {
+	var out []string
+	if branch != "" {
+		out = append(out, fmt.Sprintf("Work only in this worktree, on branch %s.", branch))
+	} else {
+		out = append(out, "Work only in this worktree.")
+	}
+	if attachment != nil && attachment.NoMerge {
+		out = append(out, fmt.Sprintf("Do not push to %s. This work is for human review: `%s done` leaves the branch unmerged.", baseBranch, cli.Name()))
+	} else {
+		out = append(out, fmt.Sprintf("Do not push to %s. `%s done` submits your branch to the merge queue.", baseBranch, cli.Name()))
+	}
+	if attachment != nil && attachment.MergeStrategy != "" {
+		out = append(out, fmt.Sprintf("Convoy merge strategy: %s.", attachment.MergeStrategy))
+	}
+	out = append(out, "Commit early and often; uncommitted work is lost if the session dies.")
+	return out
+}
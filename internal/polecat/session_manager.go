@@ -16,7 +16,10 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/instructions"
+	"github.com/steveyegge/gastown/internal/recording"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/runcontext"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/session"
@@ -70,6 +73,10 @@ type SessionStartOptions struct {
 	// If set, this is injected as an environment variable.
 	RuntimeConfigDir string
 
+	// HookBead is work already hooked to the polecat (gt sling hooks before
+	// starting the session). Used with Issue for the run-context file only.
+	HookBead string
+
 	// Agent is the agent override for this polecat session (e.g., "codex", "gemini").
 	// If set, GT_AGENT is written to the tmux session environment table so that
 	// IsAgentAlive and waitForPolecatReady read the correct process names.
@@ -230,15 +237,15 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// sequence used Claude's ReadyPromptPrefix ("❯ ") to detect readiness in a Codex
 	// session, timing out instead of using Codex's delay-based readiness.
 	townRoot := filepath.Dir(m.rig.Path)
-	var runtimeConfig *config.RuntimeConfig
-	if opts.Agent != "" {
-		rc, _, err := config.ResolveAgentConfigWithOverride(townRoot, m.rig.Path, opts.Agent)
-		if err != nil {
-			return fmt.Errorf("resolving agent config for %s: %w", opts.Agent, err)
-		}
-		runtimeConfig = rc
-	} else {
-		runtimeConfig = config.ResolveRoleAgentConfig("polecat", townRoot, m.rig.Path)
+	runtimeConfig, err := m.resolveRuntimeConfig(opts.Agent)
+	if err != nil {
+		return err
+	}
+
+	// Enforce the rig's pinned agent version before creating anything.
+	agentVersion, err := m.verifyAgentVersion(runtimeConfig)
+	if err != nil {
+		return err
 	}
 
 	// Ensure runtime settings exist in the shared polecats parent directory.
@@ -248,6 +255,13 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		return fmt.Errorf("ensuring runtime settings: %w", err)
 	}
 
+	// Refresh the Gas Town section of the instructions file, if the town
+	// generates them (opt-in, see package instructions).
+	agent := instructions.Agent{TownRoot: townRoot, Rig: m.rig.Name, Role: "polecat", Name: polecat, WorkDir: workDir}
+	if _, err := instructions.Generate(agent, instructions.FileFor(runtimeConfig)); err != nil {
+		style.PrintWarning("could not generate instructions for %s: %v", polecat, err)
+	}
+
 	// Get fallback info to determine beacon content based on agent capabilities.
 	// Non-hook agents need "Run gt prime" in beacon; work instructions come as delayed nudge.
 	fallbackInfo := runtime.GetStartupFallbackInfo(runtimeConfig)
@@ -263,6 +277,20 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		IncludePrimeInstruction: fallbackInfo.IncludePrimeInBeacon,
 		ExcludeWorkInstructions: fallbackInfo.SendStartupNudge,
 	}
+
+	// Write the run-context file so the dispatch is inspectable on disk and
+	// survives compaction. Non-fatal: the beacon and gt prime still work.
+	contextIssue := opts.Issue
+	if contextIssue == "" {
+		contextIssue = opts.HookBead
+	}
+	if contextIssue != "" {
+		if err := m.WriteRunContext(polecat, workDir, contextIssue, opts.Agent); err != nil {
+			style.PrintWarning("could not write run context: %v", err)
+		} else {
+			beaconConfig.ContextFile = runcontext.RelMarkdownPath()
+		}
+	}
 	beacon := session.FormatStartupBeacon(beaconConfig)
 
 	command := opts.Command
@@ -314,11 +342,21 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	if polecatGitBranch != "" {
 		envVarsToInject["GT_BRANCH"] = polecatGitBranch
 	}
+	if agentVersion != "" {
+		envVarsToInject["GT_AGENT_VERSION"] = agentVersion
+	}
 	command = config.PrependEnv(command, envVarsToInject)
+	command = session.WithTemplateEnv(command, m.rig.Path, "polecat")
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
-	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
+	releaseTmux := session.AcquireTmuxServer(townRoot, sessionID)
+	// Verified: a polecat whose agent dies on startup must fail the spawn,
+	// not leave sling holding a session that is already gone. Remain-on-exit
+	// stays on so a later crash's output can be captured.
+	err = m.tmux.NewSessionWithCommandVerified(sessionID, workDir, command, tmux.VerifyOptions{RemainOnExit: true})
+	releaseTmux()
+	if err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
@@ -356,6 +394,11 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	debugSession("SetEnvironment GT_POLECAT_PATH", m.tmux.SetEnvironment(sessionID, "GT_POLECAT_PATH", workDir))
 	debugSession("SetEnvironment GT_TOWN_ROOT", m.tmux.SetEnvironment(sessionID, "GT_TOWN_ROOT", townRoot))
 
+	// Seed the state-derived variables ReconcileEnv keeps current.
+	for k, v := range m.contextEnv(workDir, contextIssue) {
+		debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
+	}
+
 	// Disable Dolt auto-commit in tmux session environment (gt-5cc2p).
 	// This ensures respawned processes also inherit the setting.
 	debugSession("SetEnvironment BD_DOLT_AUTO_COMMIT", m.tmux.SetEnvironment(sessionID, "BD_DOLT_AUTO_COMMIT", "off"))
@@ -372,15 +415,33 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 			style.PrintWarning("could not hook issue %s: %v", opts.Issue, err)
 		}
 	}
+	// Renew the dispatch claim sling handed over; the witness keeps renewing
+	// it each patrol (gt session reconcile) while the session runs.
+	if contextIssue != "" {
+		if err := m.RenewClaim(polecat, contextIssue); err != nil {
+			style.PrintWarning("could not renew claim on %s: %v", contextIssue, err)
+		}
+	}
 
 	// Apply theme (non-fatal)
 	theme := tmux.AssignTheme(m.rig.Name)
-	debugSession("ConfigureGasTownSession", m.tmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, polecat, "polecat"))
+	debugSession("ConfigureSession", session.ConfigureSession(m.tmux, session.SessionConfig{
+		SessionID: sessionID,
+		WorkDir:   workDir,
+		Role:      "polecat",
+		RigPath:   m.rig.Path,
+		RigName:   m.rig.Name,
+		AgentName: polecat,
+		Theme:     &theme,
+	}))
 
 	// Set pane-died hook for crash detection (non-fatal)
 	agentID := fmt.Sprintf("%s/%s", m.rig.Name, polecat)
 	debugSession("SetPaneDiedHook", m.tmux.SetPaneDiedHook(sessionID, agentID))
 
+	// Record the session if the rig asks for it (non-fatal)
+	m.startRecording(townRoot, sessionID)
+
 	// Wait for Claude to start (non-fatal)
 	debugSession("WaitForCommand", m.tmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
 
@@ -394,14 +455,17 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 	// Handle fallback nudges for non-hook agents.
 	// See StartupFallbackInfo in runtime package for the fallback matrix.
+	// Nudges are buffered until the agent is at its prompt, so none is lost
+	// to a welcome screen or dialog still on the pane.
+	nudgeBuffer := tmux.BufferConfigFor(runtimeConfig)
 	if fallbackInfo.SendBeaconNudge && fallbackInfo.SendStartupNudge && fallbackInfo.StartupNudgeDelayMs == 0 {
 		// Hooks + no prompt: Single combined nudge (hook already ran gt prime synchronously)
 		combined := beacon + "\n\n" + runtime.StartupNudgeContent()
-		debugSession("SendCombinedNudge", m.tmux.NudgeSession(sessionID, combined))
+		debugSession("SendCombinedNudge", m.tmux.NudgeSessionWhenReady(sessionID, combined, nudgeBuffer))
 	} else {
 		if fallbackInfo.SendBeaconNudge {
 			// Agent doesn't support CLI prompt - send beacon via nudge
-			debugSession("SendBeaconNudge", m.tmux.NudgeSession(sessionID, beacon))
+			debugSession("SendBeaconNudge", m.tmux.NudgeSessionWhenReady(sessionID, beacon, nudgeBuffer))
 		}
 
 		if fallbackInfo.StartupNudgeDelayMs > 0 {
@@ -413,7 +477,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 		if fallbackInfo.SendStartupNudge {
 			// Send work instructions via nudge
-			debugSession("SendStartupNudge", m.tmux.NudgeSession(sessionID, runtime.StartupNudgeContent()))
+			debugSession("SendStartupNudge", m.tmux.NudgeSessionWhenReady(sessionID, runtime.StartupNudgeContent(), nudgeBuffer))
 		}
 	}
 
@@ -451,10 +515,73 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 	// Track PID for defense-in-depth orphan cleanup (non-fatal)
 	_ = session.TrackSessionPID(townRoot, sessionID, m.tmux)
+	debugSession("RegisterSession", session.RegisterSession(townRoot, session.SessionRecord{
+		Session: sessionID,
+		Role:    session.RolePolecat,
+		Rig:     m.rig.Name,
+		Name:    polecat,
+		Issue:   contextIssue,
+	}))
 
 	return nil
 }
 
+// resolveRuntimeConfig resolves the runtime config for the agent a polecat
+// session will run: the agent override if given, else the rig's polecat agent.
+func (m *SessionManager) resolveRuntimeConfig(agent string) (*config.RuntimeConfig, error) {
+	townRoot := filepath.Dir(m.rig.Path)
+	if agent != "" {
+		rc, _, err := config.ResolveAgentConfigWithOverride(townRoot, m.rig.Path, agent)
+		if err != nil {
+			return nil, fmt.Errorf("resolving agent config for %s: %w", agent, err)
+		}
+		return rc, nil
+	}
+	return config.ResolveRoleAgentConfig("polecat", townRoot, m.rig.Path), nil
+}
+
+// VerifyAgentVersion checks the installed CLI of the agent a polecat session
+// would run (agent override, or "" for the rig's polecat agent) against the
+// rig's pinned agent_version. It returns the installed version for recording
+// on the dispatch ("" if unknown), and an error on a mismatch unless the
+// rig's agent_version_policy is "warn".
+func (m *SessionManager) VerifyAgentVersion(agent string) (string, error) {
+	rc, err := m.resolveRuntimeConfig(agent)
+	if err != nil {
+		return "", err
+	}
+	return m.verifyAgentVersion(rc)
+}
+
+func (m *SessionManager) verifyAgentVersion(rc *config.RuntimeConfig) (string, error) {
+	settings, _ := config.LoadRigSettings(config.RigSettingsPath(m.rig.Path)) // no settings: no pin
+	pin := ""
+	if settings != nil {
+		pin = settings.AgentVersion
+	}
+	version, err := runtime.VerifyAgentVersion(rc, pin)
+	if err != nil {
+		if settings.AgentVersionWarnOnly() {
+			style.PrintWarning("%v (agent_version_policy: warn)", err)
+			return version, nil
+		}
+		return "", fmt.Errorf("%w (set agent_version_policy to \"warn\" in %s/settings/config.json to spawn anyway)", err, m.rig.Name)
+	}
+	return version, nil
+}
+
+// startRecording records the session as an asciicast when the rig's
+// settings enable recording. Started before the agent is up, so the
+// recording includes startup dialogs and the initial prompt.
+func (m *SessionManager) startRecording(townRoot, sessionID string) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(m.rig.Path))
+	if err != nil || settings.Recording == nil || !settings.Recording.Enabled {
+		return
+	}
+	_, err = recording.StartSession(m.tmux, townRoot, sessionID)
+	debugSession("StartRecording", err)
+}
+
 // isSessionStale checks if a tmux session's pane process has died.
 // A stale session exists in tmux but its main process (the agent) is no longer running.
 // This happens when the agent crashes during startup but tmux keeps the dead pane.
@@ -486,6 +613,7 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 	if err := m.tmux.KillSessionWithProcesses(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	session.UnregisterSession(filepath.Dir(m.rig.Path), sessionID)
 
 	return nil
 }
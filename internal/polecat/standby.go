@@ -0,0 +1,202 @@
+package polecat
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Warm standby (config.WarmStandbyConfig).
+//
+// A standby polecat's session is already running with the agent sitting at
+// an idle prompt. Its worktree is detached at the rig's default branch and
+// its agent bead has agent_state "standby" and no hook. ClaimStandby
+// attaches work lazily: it resets the worktree in place onto the work
+// branch, so the running agent's working directory stays valid, and hooks
+// the bead. Dispatch then only costs the nudge round-trip instead of agent
+// startup. The witness patrol keeps the rig at size with
+// 'gt polecat standby maintain'.
+
+// AgentStateStandby is the agent_state of a polecat on warm standby.
+const AgentStateStandby = "standby"
+
+// StandbyEnvVar marks a standby session in its tmux environment ("1"), so
+// capacity checks can tell waiting sessions from working ones without a
+// beads lookup per session.
+const StandbyEnvVar = "GT_STANDBY"
+
+// ErrNotStandby is returned when claiming a polecat that is not (or no
+// longer) on warm standby.
+var ErrNotStandby = errors.New("polecat is not on warm standby")
+
+// IsStandbySession reports whether a tmux session is marked as a warm
+// standby session.
+func IsStandbySession(t *tmux.Tmux, sessionName string) bool {
+	v, err := t.GetEnvironment(sessionName, StandbyEnvVar)
+	return err == nil && v == "1"
+}
+
+// StandbyPolecats returns the rig's standby polecats whose sessions are
+// running.
+func (m *Manager) StandbyPolecats() ([]*Polecat, error) {
+	polecats, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	var out []*Polecat
+	for _, p := range polecats {
+		if p.State == StateStandby && m.sessionRunning(p.Name) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// FindStandbyPolecat returns a standby polecat ready to be claimed, or nil
+// if there is none.
+func (m *Manager) FindStandbyPolecat() (*Polecat, error) {
+	standby, err := m.StandbyPolecats()
+	if err != nil || len(standby) == 0 {
+		return nil, err
+	}
+	return standby[0], nil
+}
+
+// sessionRunning reports whether the polecat's tmux session exists.
+func (m *Manager) sessionRunning(name string) bool {
+	if m.tmux == nil {
+		return false
+	}
+	running, _ := m.tmux.HasSession(session.PolecatSessionName(session.PrefixFor(m.rig.Name), name))
+	return running
+}
+
+// PrepareStandby readies a polecat with no hooked work for warm standby:
+// its worktree is reset to the rig's default branch and its agent_state
+// set to standby. The caller starts the session. Uncommitted work is never
+// discarded.
+func (m *Manager) PrepareStandby(name string) error {
+	fl, err := m.lockPolecat(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	if !m.exists(name) {
+		return ErrPolecatNotFound
+	}
+	agentID := m.agentBeadID(name)
+	if _, fields, err := m.beads.GetAgentBead(agentID); err == nil && fields != nil && fields.HookBead != "" {
+		return fmt.Errorf("polecat %s has hooked work (%s)", name, fields.HookBead)
+	}
+
+	clonePath := m.clonePath(name)
+	wt := git.NewGit(clonePath)
+	if status, err := wt.CheckUncommittedWork(); err == nil && !status.Clean() {
+		return &UncommittedWorkError{PolecatName: name, Status: status}
+	}
+	if err := m.resetStandbyWorktree(clonePath, "", m.defaultStartPoint()); err != nil {
+		return err
+	}
+	return m.beads.UpdateAgentState(agentID, AgentStateStandby, nil)
+}
+
+// ClaimStandby takes a standby polecat for new work: the worktree is reset
+// in place onto a fresh branch at opts.BaseBranch (default branch if empty)
+// and opts.HookBead is hooked. The session keeps running; the caller nudges
+// the agent. Returns ErrNotStandby if another dispatch claimed it first or
+// its session is gone.
+func (m *Manager) ClaimStandby(name string, opts AddOptions) (*Polecat, error) {
+	fl, err := m.lockPolecat(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	if !m.exists(name) {
+		return nil, ErrPolecatNotFound
+	}
+	agentID := m.agentBeadID(name)
+	_, fields, err := m.beads.GetAgentBead(agentID)
+	if err != nil || fields == nil || fields.AgentState != AgentStateStandby || fields.HookBead != "" {
+		return nil, ErrNotStandby
+	}
+	if !m.sessionRunning(name) {
+		return nil, ErrNotStandby
+	}
+
+	// Claim before the slow git work so a concurrent dispatch that reads
+	// the bead after we release the lock sees it taken.
+	if err := m.beads.UpdateAgentState(agentID, "spawning", nil); err != nil {
+		return nil, fmt.Errorf("claiming standby polecat: %w", err)
+	}
+
+	startPoint := opts.BaseBranch
+	if startPoint == "" {
+		startPoint = m.defaultStartPoint()
+	}
+	branchName := m.buildBranchName(name, opts.HookBead)
+	clonePath := m.clonePath(name)
+	if err := m.resetStandbyWorktree(clonePath, branchName, startPoint); err != nil {
+		// The worktree is reset again on the next claim, so the polecat can
+		// go back on standby.
+		_ = m.beads.UpdateAgentState(agentID, AgentStateStandby, nil)
+		return nil, err
+	}
+
+	if opts.HookBead != "" {
+		if err := m.beads.SetHookBead(agentID, opts.HookBead); err != nil {
+			_ = m.beads.UpdateAgentState(agentID, AgentStateStandby, nil)
+			return nil, fmt.Errorf("hooking %s: %w", opts.HookBead, err)
+		}
+	}
+
+	return &Polecat{
+		Name:      name,
+		Rig:       m.rig.Name,
+		State:     StateWorking,
+		ClonePath: clonePath,
+		Branch:    branchName,
+		Issue:     opts.HookBead,
+	}, nil
+}
+
+// resetStandbyWorktree fetches origin and resets a polecat's worktree in
+// place onto branch at startPoint (detached if branch is empty), then
+// restores the Gas Town files the reset's clean removes.
+func (m *Manager) resetStandbyWorktree(clonePath, branch, startPoint string) error {
+	repoGit, err := m.repoBase()
+	if err != nil {
+		return fmt.Errorf("finding repo base: %w", err)
+	}
+	_ = repoGit.Fetch("origin") // non-fatal: may be offline
+
+	wt := git.NewGit(clonePath)
+	if dirs := m.sparseDirs(); len(dirs) > 0 {
+		if err := wt.SparseCheckoutSet(dirs); err != nil {
+			return fmt.Errorf("updating sparse checkout: %w", err)
+		}
+	}
+	if err := wt.ResetWorktreeTo(branch, startPoint); err != nil {
+		return fmt.Errorf("resetting worktree to %s: %w", startPoint, err)
+	}
+	if err := m.pullLFS(clonePath); err != nil {
+		return fmt.Errorf("pulling LFS content: %w", err)
+	}
+
+	if err := m.setupSharedBeads(clonePath); err != nil {
+		style.PrintWarning("could not set up shared beads: %v", err)
+	}
+	if err := rig.CopyOverlay(m.rig.Path, clonePath); err != nil {
+		style.PrintWarning("could not copy overlay files: %v", err)
+	}
+	if err := rig.EnsureGitignorePatterns(clonePath); err != nil {
+		style.PrintWarning("could not update .gitignore: %v", err)
+	}
+	return nil
+}
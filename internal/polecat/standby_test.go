@@ -0,0 +1,70 @@
+package polecat
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestResetStandbyWorktree_InPlace(t *testing.T) {
+	root := t.TempDir()
+	mayorRig := filepath.Join(root, "mayor", "rig")
+	if err := os.MkdirAll(mayorRig, 0755); err != nil {
+		t.Fatalf("mkdir mayor/rig: %v", err)
+	}
+	for _, args := range [][]string{
+		{"init", "-b", "main"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test"},
+		{"commit", "--allow-empty", "-m", "Initial commit"},
+		{"remote", "add", "origin", mayorRig},
+		{"fetch", "origin"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = mayorRig
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	m := NewManager(&rig.Rig{Name: "rig", Path: root}, git.NewGit(root), nil)
+	clonePath := filepath.Join(root, "polecats", "Toast", "rig")
+	repoGit := git.NewGit(mayorRig)
+	if err := repoGit.WorktreeAddDetached(clonePath, "origin/main"); err != nil {
+		t.Fatalf("adding worktree: %v", err)
+	}
+	before, err := os.Stat(clonePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(clonePath, "junk.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.resetStandbyWorktree(clonePath, "polecat/Toast-1", "origin/main"); err != nil {
+		t.Fatalf("resetStandbyWorktree: %v", err)
+	}
+	// The running agent's working directory must survive the claim.
+	after, err := os.Stat(clonePath)
+	if err != nil || !os.SameFile(before, after) {
+		t.Errorf("worktree directory was replaced, want it reset in place (%v)", err)
+	}
+	if branch, err := git.NewGit(clonePath).CurrentBranch(); err != nil || branch != "polecat/Toast-1" {
+		t.Errorf("branch = %q, %v; want polecat/Toast-1", branch, err)
+	}
+	if _, err := os.Stat(filepath.Join(clonePath, "junk.txt")); !os.IsNotExist(err) {
+		t.Error("worktree was not cleaned")
+	}
+}
+
+func TestClaimStandby_NotFound(t *testing.T) {
+	m := NewManager(&rig.Rig{Name: "rig", Path: t.TempDir()}, git.NewGit(t.TempDir()), nil)
+	if _, err := m.ClaimStandby("Nux", AddOptions{}); !errors.Is(err, ErrPolecatNotFound) {
+		t.Errorf("ClaimStandby(missing) error = %v, want ErrPolecatNotFound", err)
+	}
+}
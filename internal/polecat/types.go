@@ -10,6 +10,7 @@ import "time"
 //
 //   - Working: Session active, doing assigned work (normal operation)
 //   - Idle: Work completed, session killed, sandbox preserved for reuse
+//   - Standby: No work, session pre-started and waiting (warm standby)
 //   - Stalled: Session stopped unexpectedly, was never nudged back to life
 //   - Zombie: Session called 'gt done' but cleanup failed - tried to die but couldn't
 //
@@ -36,6 +37,12 @@ const (
 	// creating a new worktree.
 	StateIdle State = "idle"
 
+	// StateStandby means the polecat is on warm standby: its session is
+	// running with the agent at an idle prompt, no work is hooked, and its
+	// worktree is detached at the default branch. Dispatch claims it with
+	// ClaimStandby instead of starting a session (config.WarmStandbyConfig).
+	StateStandby State = "standby"
+
 	// StateDone means the polecat has completed its assigned work and called
 	// 'gt done'. This is normally a transient state - the session should exit
 	// immediately after. If a polecat remains in StateDone, it's a "zombie":
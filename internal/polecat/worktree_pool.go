@@ -0,0 +1,222 @@
+package polecat
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// Warm worktree pool (config.WorktreePoolConfig).
+//
+// Pooled worktrees live in <rig>/.worktree-pool/, detached at the rig's
+// default branch. AddWithOptions claims one by moving it into the new
+// polecat's directory and resetting it onto the polecat's branch, which
+// takes seconds where a fresh checkout of a big repo takes minutes.
+// MaintainWorktreePool (run by the daemon's worktree_pool patrol) keeps the
+// pool fetched, clean, and at size.
+
+// WorktreePoolResult reports what MaintainWorktreePool did.
+type WorktreePoolResult struct {
+	Ready     int `json:"ready"`
+	Created   int `json:"created"`
+	Refreshed int `json:"refreshed"`
+	Removed   int `json:"removed"`
+}
+
+// worktreePoolDir returns the directory holding the rig's pooled worktrees.
+func (m *Manager) worktreePoolDir() string {
+	return filepath.Join(m.rig.Path, ".worktree-pool")
+}
+
+// worktreePoolLock returns the lock guarding the worktree pool. Claims
+// only try it, so a spawn never waits on pool maintenance.
+func (m *Manager) worktreePoolLock() (*flock.Flock, error) {
+	lockDir := filepath.Join(m.rig.Path, ".runtime", "locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating lock dir: %w", err)
+	}
+	return flock.New(filepath.Join(lockDir, "worktree-pool.lock")), nil
+}
+
+// defaultStartPoint returns the ref new polecat worktrees start from.
+func (m *Manager) defaultStartPoint() string {
+	defaultBranch := "main"
+	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
+		defaultBranch = rigCfg.DefaultBranch
+	}
+	return fmt.Sprintf("origin/%s", defaultBranch)
+}
+
+// sparseDirs returns the directories polecat worktrees check out
+// (config.PartialCloneConfig.Sparse), or nil for the whole tree.
+func (m *Manager) sparseDirs() []string {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(m.rig.Path))
+	if err != nil || settings.PartialClone == nil {
+		return nil
+	}
+	return settings.PartialClone.Sparse
+}
+
+// pullLFS downloads Git LFS content into a worktree if the rig asks for it
+// (config.LFSConfig).
+func (m *Manager) pullLFS(path string) error {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(m.rig.Path))
+	if err != nil || settings.LFS == nil || !settings.LFS.Pull {
+		return nil
+	}
+	return git.NewGit(path).LFSPull(settings.LFS.Include)
+}
+
+// addWorktree creates a worktree at path, sparse if the rig configures
+// sparse directories. With an empty branch, HEAD is detached at ref.
+func (m *Manager) addWorktree(repoGit *git.Git, path, branch, ref string) error {
+	if dirs := m.sparseDirs(); len(dirs) > 0 {
+		return repoGit.WorktreeAddSparse(path, branch, ref, dirs)
+	}
+	if branch == "" {
+		return repoGit.WorktreeAddDetached(path, ref)
+	}
+	return repoGit.WorktreeAddFromRef(path, branch, ref)
+}
+
+// PooledWorktrees returns the paths of the rig's ready pooled worktrees,
+// oldest first.
+func (m *Manager) PooledWorktrees() ([]string, error) {
+	entries, err := os.ReadDir(m.worktreePoolDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		if e.IsDir() {
+			paths = append(paths, filepath.Join(m.worktreePoolDir(), e.Name()))
+		}
+	}
+	sort.Strings(paths) // names are creation timestamps
+	return paths, nil
+}
+
+// claimPooledWorktree moves a pooled worktree to clonePath and resets it
+// onto a new branch at startPoint. It returns false if no worktree could be
+// claimed (pool empty, under maintenance, or the reset failed); the caller
+// then creates the worktree from scratch.
+func (m *Manager) claimPooledWorktree(repoGit *git.Git, clonePath, branch, startPoint string) bool {
+	fl, err := m.worktreePoolLock()
+	if err != nil {
+		return false
+	}
+	if locked, err := fl.TryLock(); err != nil || !locked {
+		return false
+	}
+	// A worktree whose sparseness doesn't match the rig's setting is left
+	// for MaintainWorktreePool to replace.
+	dirs := m.sparseDirs()
+	paths, _ := m.PooledWorktrees()
+	if len(paths) == 0 || git.IsSparseCheckoutConfigured(paths[0]) != (len(dirs) > 0) {
+		_ = fl.Unlock()
+		return false
+	}
+	moveErr := repoGit.WorktreeMove(paths[0], clonePath)
+	if moveErr != nil {
+		m.removePooledWorktree(repoGit, paths[0])
+	}
+	_ = fl.Unlock()
+	if moveErr != nil {
+		return false
+	}
+
+	wt := git.NewGit(clonePath)
+	if len(dirs) > 0 {
+		err = wt.SparseCheckoutSet(dirs) // the rig's directory list may have changed
+	}
+	if err == nil {
+		err = wt.ResetWorktreeTo(branch, startPoint)
+	}
+	if err != nil {
+		_ = repoGit.WorktreeRemove(clonePath, true)
+		_ = os.RemoveAll(clonePath)
+		return false
+	}
+	return true
+}
+
+// removePooledWorktree unregisters and deletes a pooled worktree.
+func (m *Manager) removePooledWorktree(repoGit *git.Git, path string) {
+	_ = repoGit.WorktreeRemove(path, true)
+	_ = os.RemoveAll(path)
+}
+
+// MaintainWorktreePool fetches origin, resets every pooled worktree to the
+// rig's default branch, drops any that can't be reset or exceed size, and
+// creates new ones until size are ready.
+func (m *Manager) MaintainWorktreePool(size int) (WorktreePoolResult, error) {
+	var res WorktreePoolResult
+	fl, err := m.worktreePoolLock()
+	if err != nil {
+		return res, err
+	}
+	if err := fl.Lock(); err != nil {
+		return res, fmt.Errorf("acquiring worktree pool lock: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	repoGit, err := m.repoBase()
+	if err != nil {
+		return res, fmt.Errorf("finding repo base: %w", err)
+	}
+	paths, err := m.PooledWorktrees()
+	if err != nil {
+		return res, err
+	}
+	if size <= 0 && len(paths) == 0 {
+		return res, nil
+	}
+	if err := repoGit.Fetch("origin"); err != nil {
+		return res, fmt.Errorf("fetching origin: %w", err)
+	}
+	startPoint := m.defaultStartPoint()
+	sparse := len(m.sparseDirs()) > 0
+
+	for _, path := range paths {
+		// Worktrees created before the rig's sparse setting changed are
+		// replaced rather than converted.
+		if res.Ready >= size || git.IsSparseCheckoutConfigured(path) != sparse {
+			m.removePooledWorktree(repoGit, path)
+			res.Removed++
+			continue
+		}
+		if err := git.NewGit(path).ResetWorktreeTo("", startPoint); err != nil {
+			m.removePooledWorktree(repoGit, path)
+			res.Removed++
+			continue
+		}
+		// Keeps the shared LFS cache warm so a claim's pull is local.
+		_ = m.pullLFS(path)
+		res.Refreshed++
+		res.Ready++
+	}
+	_ = repoGit.WorktreePrune()
+
+	for res.Ready < size {
+		path := filepath.Join(m.worktreePoolDir(), "wt-"+strconv.FormatInt(time.Now().UnixNano(), 36))
+		if err := m.addWorktree(repoGit, path, "", startPoint); err != nil {
+			_ = os.RemoveAll(path)
+			return res, fmt.Errorf("creating pooled worktree: %w", err)
+		}
+		_ = m.pullLFS(path)
+		res.Created++
+		res.Ready++
+	}
+	return res, nil
+}
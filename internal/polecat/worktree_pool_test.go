@@ -0,0 +1,77 @@
+package polecat
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestWorktreePool_MaintainAndClaim(t *testing.T) {
+	root := t.TempDir()
+	mayorRig := filepath.Join(root, "mayor", "rig")
+	if err := os.MkdirAll(mayorRig, 0755); err != nil {
+		t.Fatalf("mkdir mayor/rig: %v", err)
+	}
+	for _, args := range [][]string{
+		{"init", "-b", "main"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test"},
+		{"commit", "--allow-empty", "-m", "Initial commit"},
+		{"remote", "add", "origin", mayorRig},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = mayorRig
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	m := NewManager(&rig.Rig{Name: "rig", Path: root}, git.NewGit(root), nil)
+	res, err := m.MaintainWorktreePool(2)
+	if err != nil {
+		t.Fatalf("MaintainWorktreePool: %v", err)
+	}
+	if res.Created != 2 || res.Ready != 2 {
+		t.Fatalf("first maintain = %+v, want 2 created and ready", res)
+	}
+
+	// Leave junk in the oldest pooled worktree; claiming must clean it.
+	paths, _ := m.PooledWorktrees()
+	if err := os.WriteFile(filepath.Join(paths[0], "junk.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	clonePath := filepath.Join(root, "polecats", "Toast", "rig")
+	if err := os.MkdirAll(filepath.Dir(clonePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	repoGit := git.NewGit(mayorRig)
+	if !m.claimPooledWorktree(repoGit, clonePath, "polecat/Toast-1", "origin/main") {
+		t.Fatal("claimPooledWorktree = false, want a pooled worktree")
+	}
+	if branch, err := git.NewGit(clonePath).CurrentBranch(); err != nil || branch != "polecat/Toast-1" {
+		t.Errorf("claimed worktree branch = %q, %v; want polecat/Toast-1", branch, err)
+	}
+	if _, err := os.Stat(filepath.Join(clonePath, "junk.txt")); !os.IsNotExist(err) {
+		t.Error("claimed worktree was not cleaned")
+	}
+	if paths, _ := m.PooledWorktrees(); len(paths) != 1 {
+		t.Errorf("pool after claim = %d, want 1", len(paths))
+	}
+
+	// Shrinking the pool removes the surplus.
+	res, err = m.MaintainWorktreePool(0)
+	if err != nil {
+		t.Fatalf("MaintainWorktreePool(0): %v", err)
+	}
+	if res.Removed != 1 || res.Ready != 0 {
+		t.Errorf("shrink maintain = %+v, want 1 removed, 0 ready", res)
+	}
+	if m.claimPooledWorktree(repoGit, filepath.Join(root, "polecats", "Nux", "rig"), "polecat/Nux-1", "origin/main") {
+		t.Error("claimPooledWorktree on an empty pool = true, want false")
+	}
+}
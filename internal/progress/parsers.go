@@ -0,0 +1,117 @@
+package progress
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register("claude", claudeParser())
+	Register("codex", codexParser())
+	Register("generic", genericParser())
+}
+
+// lineRule emits a signal of its kind for a matching line.
+type lineRule struct {
+	kind Kind
+	re   *regexp.Regexp
+}
+
+// usageRule reads a usage readout: a token count and/or the context left.
+type usageRule struct {
+	tokens  *regexp.Regexp // first group is the count
+	context *regexp.Regexp // first group is the percent left
+}
+
+// ruleParser checks each line against rules, first match wins, then
+// against the usage readout.
+func ruleParser(rules []lineRule, usage usageRule) Parser {
+	return ParserFunc(func(lines []string) []Signal {
+		var out []Signal
+		for _, line := range lines {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" {
+				continue
+			}
+			matched := false
+			for _, r := range rules {
+				if r.re.MatchString(trimmed) {
+					out = append(out, Signal{Kind: r.kind, Detail: trimmed})
+					matched = true
+					break
+				}
+			}
+			if matched {
+				continue
+			}
+			if s, ok := usage.parse(trimmed); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	})
+}
+
+func (u usageRule) parse(line string) (Signal, bool) {
+	s := Signal{Kind: KindUsage, Detail: line, ContextLeft: -1}
+	found := false
+	if u.tokens != nil {
+		if m := u.tokens.FindStringSubmatch(line); m != nil {
+			s.Tokens = parseTokens(m[1])
+			found = true
+		}
+	}
+	if u.context != nil {
+		if m := u.context.FindStringSubmatch(line); m != nil {
+			s.ContextLeft, _ = strconv.Atoi(m[1])
+			found = true
+		}
+	}
+	return s, found
+}
+
+// claudeParser reads Claude Code's TUI: "⏺ Bash(go test ./...)" tool
+// calls, "Do you want to proceed?" permission questions, API error banners,
+// the compaction notice, and the spinner's token count and the
+// auto-compact warning.
+func claudeParser() Parser {
+	return ruleParser([]lineRule{
+		{KindToolCall, regexp.MustCompile(`^⏺\s+[A-Z][A-Za-z]*\(`)},
+		{KindAwaitingInput, regexp.MustCompile(`Do you want to (proceed|make this edit|create|allow)`)},
+		{KindError, regexp.MustCompile(`API Error|Request timed out|Prompt is too long`)},
+		{KindCompaction, regexp.MustCompile(`(?i)compacting conversation|conversation compacted`)},
+	}, usageRule{
+		tokens:  regexp.MustCompile(`[↓↑]\s*([\d.,]+[kKmM]?) tokens`),
+		context: regexp.MustCompile(`Context left until auto-compact: (\d+)%`),
+	})
+}
+
+// codexParser reads the Codex CLI: "• Running"/"• Ran" command cells,
+// approval requests, "■" error lines, context compaction, and the context
+// and token readouts.
+func codexParser() Parser {
+	return ruleParser([]lineRule{
+		{KindToolCall, regexp.MustCompile(`^•\s+(Running|Ran|Edited|Explored)\b`)},
+		{KindAwaitingInput, regexp.MustCompile(`(?i)allow command\?|would you like to (run|make)`)},
+		{KindError, regexp.MustCompile(`^■\s+|(?i)stream error`)},
+		{KindCompaction, regexp.MustCompile(`(?i)context compacted|compact task completed`)},
+	}, usageRule{
+		tokens:  regexp.MustCompile(`(?i)token usage: total=([\d.,]+[kKmM]?)`),
+		context: regexp.MustCompile(`(\d+)% context left`),
+	})
+}
+
+// genericParser is for runtimes without a parser of their own: yes/no
+// questions, "Error:" lines, compaction notices and "N tokens" readouts.
+// It finds no tool calls; add signal_patterns for those.
+func genericParser() Parser {
+	return ruleParser([]lineRule{
+		{KindAwaitingInput, regexp.MustCompile(`\[y/N\]|\[Y/n\]|\(y/n\)`)},
+		{KindError, regexp.MustCompile(`^(Error|ERROR|Fatal|FATAL):`)},
+		{KindCompaction, regexp.MustCompile(`(?i)context (was )?compacted`)},
+	}, usageRule{
+		tokens:  regexp.MustCompile(`([\d.,]+[kKmM]?) tokens`),
+		context: regexp.MustCompile(`(\d+)% context left`),
+	})
+}
@@ -0,0 +1,197 @@
+// Package progress extracts structured progress signals from an agent's pane
+// output: a tool call starting, the agent waiting on user input, an error
+// banner, a context compaction, and usage stats. Each runtime provider has
+// its own output parser; runtimes can add patterns in config.
+//
+// The signals are far more precise than watching whether the pane changed:
+// an agent sitting on a permission question is stalled even though its
+// spinner redraws, and one that just compacted its context may need to be
+// re-primed.
+package progress
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Kind is the kind of a progress signal.
+type Kind string
+
+const (
+	// KindToolCall is the agent starting a tool call.
+	KindToolCall Kind = "tool_call"
+
+	// KindAwaitingInput is the agent blocked on a question for the user
+	// (a permission or confirmation prompt, not its idle input prompt).
+	KindAwaitingInput Kind = "awaiting_input"
+
+	// KindError is an error banner, e.g. an API error the agent gave up on.
+	KindError Kind = "error"
+
+	// KindCompaction is the agent compacting its context.
+	KindCompaction Kind = "compaction"
+
+	// KindUsage is a usage readout: tokens and context remaining.
+	KindUsage Kind = "usage"
+)
+
+// Kinds lists every signal kind.
+var Kinds = []Kind{KindToolCall, KindAwaitingInput, KindError, KindCompaction, KindUsage}
+
+// Signal is one progress signal found in pane output.
+type Signal struct {
+	Kind Kind `json:"kind"`
+
+	// Detail is the matched text: the tool call, the question, the error.
+	Detail string `json:"detail,omitempty"`
+
+	// Tokens and ContextLeft (percent, -1 if not shown) are set for
+	// KindUsage.
+	Tokens      int `json:"tokens,omitempty"`
+	ContextLeft int `json:"context_left,omitempty"`
+}
+
+// Parser extracts signals from the last lines of an agent's pane, oldest
+// line first. Signals are returned in the order their lines appear.
+type Parser interface {
+	Parse(lines []string) []Signal
+}
+
+// ParserFunc adapts a function to Parser.
+type ParserFunc func(lines []string) []Signal
+
+// Parse calls f.
+func (f ParserFunc) Parse(lines []string) []Signal { return f(lines) }
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[string]Parser{}
+)
+
+// Register sets the output parser for a runtime provider (e.g. "claude"),
+// replacing any registered before.
+func Register(provider string, p Parser) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	parsers[provider] = p
+}
+
+// ParserFor returns the output parser for a runtime: its provider's parser
+// (the generic one for providers without their own), plus the runtime's
+// configured SignalPatterns.
+func ParserFor(rc *config.RuntimeConfig) Parser {
+	provider := "claude"
+	if rc != nil && rc.Provider != "" {
+		provider = rc.Provider
+	}
+	parsersMu.RLock()
+	p, ok := parsers[provider]
+	if !ok {
+		p = parsers["generic"]
+	}
+	parsersMu.RUnlock()
+
+	if rc == nil || rc.Tmux == nil || len(rc.Tmux.SignalPatterns) == 0 {
+		return p
+	}
+	extra := PatternParser(rc.Tmux.SignalPatterns)
+	return ParserFunc(func(lines []string) []Signal {
+		return merge(lines, p.Parse(lines), extra.Parse(lines))
+	})
+}
+
+// PatternParser returns a parser that emits a signal for each line matching
+// one of a kind's regular expressions. Invalid expressions are skipped.
+// Usage patterns may capture the token count as their first group.
+func PatternParser(patterns map[string][]string) Parser {
+	type rule struct {
+		kind Kind
+		re   *regexp.Regexp
+	}
+	var rules []rule
+	for _, kind := range Kinds {
+		for _, expr := range patterns[string(kind)] {
+			if re, err := regexp.Compile(expr); err == nil {
+				rules = append(rules, rule{kind, re})
+			}
+		}
+	}
+	return ParserFunc(func(lines []string) []Signal {
+		var out []Signal
+		for _, line := range lines {
+			for _, r := range rules {
+				m := r.re.FindStringSubmatch(line)
+				if m == nil {
+					continue
+				}
+				s := Signal{Kind: r.kind, Detail: strings.TrimSpace(line)}
+				if r.kind == KindUsage {
+					s.ContextLeft = -1
+					if len(m) > 1 {
+						s.Tokens = parseTokens(m[1])
+					}
+				}
+				out = append(out, s)
+				break
+			}
+		}
+		return out
+	})
+}
+
+// merge combines two parsers' signals back into line order, dropping
+// duplicates.
+func merge(lines []string, a, b []Signal) []Signal {
+	seen := make(map[Signal]bool, len(a))
+	for _, s := range a {
+		seen[s] = true
+	}
+	out := append([]Signal(nil), a...)
+	for _, s := range b {
+		if !seen[s] {
+			out = append(out, s)
+		}
+	}
+	pos := func(s Signal) int {
+		for i, line := range lines {
+			if strings.Contains(line, s.Detail) {
+				return i
+			}
+		}
+		return len(lines)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return pos(out[i]) < pos(out[j]) })
+	return out
+}
+
+// Last returns the last signal of the given kind, or false.
+func Last(signals []Signal, kind Kind) (Signal, bool) {
+	for i := len(signals) - 1; i >= 0; i-- {
+		if signals[i].Kind == kind {
+			return signals[i], true
+		}
+	}
+	return Signal{}, false
+}
+
+// parseTokens reads a token count such as "3.4k", "12,345" or "1.2M".
+func parseTokens(s string) int {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(s, "k"), strings.HasSuffix(s, "K"):
+		mult, s = 1e3, s[:len(s)-1]
+	case strings.HasSuffix(s, "m"), strings.HasSuffix(s, "M"):
+		mult, s = 1e6, s[:len(s)-1]
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return int(f * mult)
+}
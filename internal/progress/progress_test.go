@@ -0,0 +1,136 @@
+package progress
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func kinds(signals []Signal) []Kind {
+	out := make([]Kind, len(signals))
+	for i, s := range signals {
+		out[i] = s.Kind
+	}
+	return out
+}
+
+func equalKinds(a, b []Kind) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestClaudeParser(t *testing.T) {
+	lines := []string{
+		"⏺ I'll run the tests first.",
+		"⏺ Bash(go test ./...)",
+		"  ⎿  ok  github.com/x/y  0.2s",
+		"✻ Conversation compacted · ctrl+o for history",
+		"⏺ Update(internal/foo.go)",
+		"Do you want to make this edit to foo.go?",
+		"❯ 1. Yes",
+		"✶ Thinking… (12s · ↓ 3.4k tokens · esc to interrupt)",
+		"Context left until auto-compact: 8%",
+	}
+	signals := ParserFor(&config.RuntimeConfig{Provider: "claude"}).Parse(lines)
+	want := []Kind{KindToolCall, KindCompaction, KindToolCall, KindAwaitingInput, KindUsage, KindUsage}
+	if !equalKinds(kinds(signals), want) {
+		t.Fatalf("kinds = %v, want %v", kinds(signals), want)
+	}
+	if signals[4].Tokens != 3400 {
+		t.Errorf("Tokens = %d, want 3400", signals[4].Tokens)
+	}
+	if signals[5].ContextLeft != 8 {
+		t.Errorf("ContextLeft = %d, want 8", signals[5].ContextLeft)
+	}
+	if sig, blocked := Blocking(signals); !blocked || sig.Kind != KindAwaitingInput {
+		t.Errorf("Blocking = %+v, %v; want the edit question", sig, blocked)
+	}
+}
+
+func TestBlocking_ToolCallAfterErrorClearsIt(t *testing.T) {
+	signals := ParserFor(nil).Parse([]string{
+		"  ⎿  API Error: 529 overloaded",
+		"⏺ Read(README.md)",
+	})
+	if _, blocked := Blocking(signals); blocked {
+		t.Error("an agent that made a tool call after the error is not blocked")
+	}
+	signals = ParserFor(nil).Parse([]string{"⏺ Read(README.md)", "  ⎿  API Error: 529 overloaded"})
+	if sig, blocked := Blocking(signals); !blocked || sig.Kind != KindError {
+		t.Errorf("Blocking = %+v, %v; want the error banner", sig, blocked)
+	}
+}
+
+func TestParserFor_UnknownProviderAndPatterns(t *testing.T) {
+	rc := &config.RuntimeConfig{
+		Provider: "my-agent",
+		Tmux: &config.RuntimeTmuxConfig{SignalPatterns: map[string][]string{
+			"tool_call": {`^>> tool: `},
+			"usage":     {`used ([\d.]+k) tok`},
+			"error":     {`(`}, // invalid, skipped
+		}},
+	}
+	signals := ParserFor(rc).Parse([]string{
+		">> tool: grep foo",
+		"Overwrite file? [y/N]",
+		"used 12k tok",
+	})
+	want := []Kind{KindToolCall, KindAwaitingInput, KindUsage}
+	if !equalKinds(kinds(signals), want) {
+		t.Fatalf("kinds = %v, want %v", kinds(signals), want)
+	}
+	if signals[2].Tokens != 12000 {
+		t.Errorf("Tokens = %d, want 12000", signals[2].Tokens)
+	}
+}
+
+func TestState_ObserveReportsOnlyNewSignals(t *testing.T) {
+	state := &State{Sessions: map[string]*SessionState{}}
+	now := time.Now()
+	tool := Signal{Kind: KindToolCall, Detail: "⏺ Bash(ls)"}
+	ask := Signal{Kind: KindAwaitingInput, Detail: "Do you want to proceed?"}
+
+	if fresh := state.Observe("gt-Toast", []Signal{tool}, now); len(fresh) != 1 {
+		t.Fatalf("first capture: fresh = %v", fresh)
+	}
+	fresh := state.Observe("gt-Toast", []Signal{tool, ask}, now.Add(time.Minute))
+	if len(fresh) != 1 || fresh[0] != ask {
+		t.Errorf("second capture: fresh = %v, want only the question", fresh)
+	}
+	if got := state.Sessions["gt-Toast"].Latest[KindAwaitingInput]; !got.At.Equal(now.Add(time.Minute)) {
+		t.Errorf("Latest question seen at %v", got.At)
+	}
+
+	town := t.TempDir()
+	state.Prune([]string{"gt-Other"})
+	if err := SaveState(town, state); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadState(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Sessions) != 0 {
+		t.Errorf("pruned session was saved: %v", loaded.Sessions)
+	}
+	if _, err := LoadState(filepath.Join(town, "missing")); err != nil {
+		t.Errorf("missing state file: %v", err)
+	}
+}
+
+func TestParseTokens(t *testing.T) {
+	for in, want := range map[string]int{"3.4k": 3400, "12,345": 12345, "1.2M": 1200000, "x": 0} {
+		if got := parseTokens(in); got != want {
+			t.Errorf("parseTokens(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package progress
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Observed is a signal and when it was first seen.
+type Observed struct {
+	Signal
+	At time.Time `json:"at"`
+}
+
+// SessionState is what was last seen of one session's signals.
+type SessionState struct {
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Latest is the most recent signal of each kind.
+	Latest map[Kind]Observed `json:"latest,omitempty"`
+
+	// Visible are the signals in the last capture, to tell new ones from
+	// ones still on screen.
+	Visible []Signal `json:"visible,omitempty"`
+}
+
+// State is the last-seen signals of every agent session, kept by the
+// daemon's agent_signals patrol.
+type State struct {
+	Sessions map[string]*SessionState `json:"sessions"`
+}
+
+// StatePath returns the path of the town's signal state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "progress.json")
+}
+
+// LoadState reads the signal state; a missing file is an empty state.
+func LoadState(townRoot string) (*State, error) {
+	state := &State{Sessions: map[string]*SessionState{}}
+	data, err := os.ReadFile(StatePath(townRoot))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Sessions == nil {
+		state.Sessions = map[string]*SessionState{}
+	}
+	return state, nil
+}
+
+// SaveState writes the signal state.
+func SaveState(townRoot string, state *State) error {
+	if err := os.MkdirAll(filepath.Dir(StatePath(townRoot)), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(StatePath(townRoot), state)
+}
+
+// Observe records the signals in a session's latest capture and returns
+// the ones that were not on screen in the previous capture.
+func (s *State) Observe(session string, signals []Signal, now time.Time) []Signal {
+	ss := s.Sessions[session]
+	if ss == nil {
+		ss = &SessionState{Latest: map[Kind]Observed{}}
+		s.Sessions[session] = ss
+	}
+	if ss.Latest == nil {
+		ss.Latest = map[Kind]Observed{}
+	}
+	visible := make(map[Signal]bool, len(ss.Visible))
+	for _, v := range ss.Visible {
+		visible[v] = true
+	}
+	var fresh []Signal
+	for _, sig := range signals {
+		if visible[sig] {
+			continue
+		}
+		visible[sig] = true
+		fresh = append(fresh, sig)
+		ss.Latest[sig.Kind] = Observed{Signal: sig, At: now}
+	}
+	ss.Visible = signals
+	ss.UpdatedAt = now
+	return fresh
+}
+
+// Prune drops sessions that are no longer running.
+func (s *State) Prune(running []string) {
+	live := make(map[string]bool, len(running))
+	for _, name := range running {
+		live[name] = true
+	}
+	for name := range s.Sessions {
+		if !live[name] {
+			delete(s.Sessions, name)
+		}
+	}
+}
+
+// Blocking reports whether pane signals show the agent blocked: waiting on
+// user input, or stopped at an error banner, with no tool call after it.
+// It returns the blocking signal.
+func Blocking(signals []Signal) (Signal, bool) {
+	for i := len(signals) - 1; i >= 0; i-- {
+		switch signals[i].Kind {
+		case KindToolCall:
+			return Signal{}, false
+		case KindAwaitingInput, KindError:
+			return signals[i], true
+		}
+	}
+	return Signal{}, false
+}
@@ -0,0 +1,226 @@
+package quota
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// ProviderOf returns the provider an agent runtime talks to, used to group
+// sessions for rate-limit pressure: the configured provider, else the
+// command's name (e.g., "codex"), else "claude".
+func ProviderOf(rc *config.RuntimeConfig) string {
+	if rc == nil {
+		return "claude"
+	}
+	if rc.Provider != "" {
+		return rc.Provider
+	}
+	if rc.Command != "" {
+		return filepath.Base(rc.Command)
+	}
+	return "claude"
+}
+
+// ProviderResolver resolves agents to their runtime configs and providers,
+// caching by agent, role, and rig. Not safe for concurrent use.
+type ProviderResolver struct {
+	TownRoot string
+	cache    map[string]*config.RuntimeConfig
+}
+
+// Runtime returns the runtime config for role in rigName running agent. An
+// empty agent means the role's configured agent; an empty rigName means a
+// town-level role.
+func (r *ProviderResolver) Runtime(agent, role, rigName string) *config.RuntimeConfig {
+	key := agent + "|" + role + "|" + rigName
+	if rc, ok := r.cache[key]; ok {
+		return rc
+	}
+	rigPath := ""
+	if rigName != "" {
+		rigPath = filepath.Join(r.TownRoot, rigName)
+	}
+	var rc *config.RuntimeConfig
+	if agent != "" {
+		rc, _, _ = config.ResolveAgentConfigWithOverride(r.TownRoot, rigPath, agent)
+	}
+	if rc == nil {
+		rc = config.ResolveRoleAgentConfig(role, r.TownRoot, rigPath)
+	}
+	if r.cache == nil {
+		r.cache = make(map[string]*config.RuntimeConfig)
+	}
+	r.cache[key] = rc
+	return rc
+}
+
+// Provider returns the provider for role in rigName running agent.
+func (r *ProviderResolver) Provider(agent, role, rigName string) string {
+	return ProviderOf(r.Runtime(agent, role, rigName))
+}
+
+// SessionRuntime returns the runtime config of a running session, from its
+// GT_AGENT and the role and rig in its name.
+func (r *ProviderResolver) SessionRuntime(t *tmux.Tmux, sess string) *config.RuntimeConfig {
+	agent, _ := t.GetEnvironment(sess, "GT_AGENT")
+	role, rigName := "polecat", ""
+	if id, err := session.ParseSessionName(sess); err == nil {
+		role, rigName = string(id.Role), id.Rig
+	}
+	return r.Runtime(agent, role, rigName)
+}
+
+// SessionProvider returns the provider of a running session.
+func (r *ProviderResolver) SessionProvider(t *tmux.Tmux, sess string) string {
+	return ProviderOf(r.SessionRuntime(t, sess))
+}
+
+// AssessPressure aggregates scan results into rate-limit pressure per
+// provider. providerOf maps a session to its provider.
+func AssessPressure(results []ScanResult, providerOf func(session string) string, now time.Time) map[string]capacity.ProviderPressure {
+	out := make(map[string]capacity.ProviderPressure)
+	for _, r := range results {
+		provider := providerOf(r.Session)
+		p := out[provider]
+		p.Provider = provider
+		p.ObservedAt = now
+		p.Sessions++
+		switch {
+		case r.RateLimited:
+			p.Limited++
+			p.LimitedSessions = append(p.LimitedSessions, r.Session)
+			if p.ResetsAt == "" {
+				p.ResetsAt = r.ResetsAt
+			}
+		case r.Retrying:
+			p.Retrying++
+			p.RetryingSessions = append(p.RetryingSessions, r.Session)
+		}
+		out[provider] = p
+	}
+	for provider, p := range out {
+		sort.Strings(p.LimitedSessions)
+		sort.Strings(p.RetryingSessions)
+		out[provider] = p
+	}
+	return out
+}
+
+// transcriptTailBytes is how much of the end of a transcript is checked
+// for recent rate-limit errors.
+const transcriptTailBytes = 256 * 1024
+
+// MarkTranscriptRetries sets Retrying on results whose Claude Code
+// transcript logged a rate-limit API error since since. Claude Code retries
+// 429s on its own, so the pane may show nothing while the transcript
+// records each failed call. workDir returns a session's pane directory,
+// which locates its transcript. Sessions already limited or retrying are
+// skipped.
+func MarkTranscriptRetries(results []ScanResult, workDir func(session string) (string, error), since time.Time) {
+	for i := range results {
+		r := &results[i]
+		if r.RateLimited || r.Retrying || r.ConfigDir == "" {
+			continue
+		}
+		dir, err := workDir(r.Session)
+		if err != nil || dir == "" {
+			continue
+		}
+		path := latestTranscript(filepath.Join(r.ConfigDir, "projects", claudeProjectName(dir)))
+		if path == "" {
+			continue
+		}
+		if line := transcriptRateLimitError(path, since); line != "" {
+			r.Retrying = true
+			r.RetryLine = line
+		}
+	}
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// claudeProjectName returns Claude Code's project directory name for a
+// working directory: every non-alphanumeric character becomes "-".
+func claudeProjectName(workDir string) string {
+	return nonAlphanumeric.ReplaceAllString(workDir, "-")
+}
+
+// latestTranscript returns the most recently modified .jsonl file in dir.
+func latestTranscript(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	var latest string
+	var latestMod time.Time
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(latestMod) {
+			latest, latestMod = filepath.Join(dir, e.Name()), info.ModTime()
+		}
+	}
+	return latest
+}
+
+// transcriptAPIError is the subset of a Claude Code transcript line needed
+// to spot API errors.
+type transcriptAPIError struct {
+	Timestamp         time.Time `json:"timestamp"`
+	IsAPIErrorMessage bool      `json:"isApiErrorMessage"`
+}
+
+// transcriptRateLimitError returns the latest rate-limit API error line
+// logged in the transcript at path since since, or "".
+func transcriptRateLimitError(path string, since time.Time) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() > transcriptTailBytes {
+		if _, err := f.Seek(-transcriptTailBytes, io.SeekEnd); err != nil {
+			return ""
+		}
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := lines[i]
+		if !strings.Contains(line, `"isApiErrorMessage"`) {
+			continue
+		}
+		var e transcriptAPIError
+		if err := json.Unmarshal([]byte(line), &e); err != nil || !e.IsAPIErrorMessage {
+			continue // also skips a partial first line after Seek
+		}
+		if e.Timestamp.Before(since) {
+			return ""
+		}
+		lower := strings.ToLower(line)
+		if strings.Contains(lower, "429") || strings.Contains(lower, "rate_limit") || strings.Contains(lower, "rate limit") {
+			return "transcript: API error 429 at " + e.Timestamp.Format(time.RFC3339)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package quota
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestScanAll_DetectsRetrying(t *testing.T) {
+	setupTestRegistry(t)
+
+	tmux := &mockTmux{
+		sessions: []string{"gt-crew-bear", "gt-crew-wolf"},
+		paneContent: map[string]string{
+			"gt-crew-bear": "  ⎿  API Error (429 {\"type\":\"rate_limit_error\"}) · Retrying in 8 seconds…",
+			"gt-crew-wolf": "  All tests passed.",
+		},
+	}
+	scanner, err := NewScanner(tmux, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := scanner.ScanAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]ScanResult)
+	for _, r := range results {
+		byName[r.Session] = r
+	}
+	if r := byName["gt-crew-bear"]; !r.Retrying || r.RateLimited || r.RetryLine == "" {
+		t.Errorf("gt-crew-bear = %+v, want retrying", r)
+	}
+	if r := byName["gt-crew-wolf"]; r.Retrying {
+		t.Errorf("gt-crew-wolf = %+v, want not retrying", r)
+	}
+}
+
+func TestProviderOf(t *testing.T) {
+	tests := []struct {
+		rc   *config.RuntimeConfig
+		want string
+	}{
+		{nil, "claude"},
+		{&config.RuntimeConfig{}, "claude"},
+		{&config.RuntimeConfig{Command: "/usr/local/bin/codex"}, "codex"},
+		{&config.RuntimeConfig{Provider: "gemini", Command: "gemini-cli"}, "gemini"},
+	}
+	for _, tt := range tests {
+		if got := ProviderOf(tt.rc); got != tt.want {
+			t.Errorf("ProviderOf(%+v) = %q, want %q", tt.rc, got, tt.want)
+		}
+	}
+}
+
+func TestAssessPressure(t *testing.T) {
+	now := time.Now()
+	results := []ScanResult{
+		{Session: "gt-a", RateLimited: true, ResetsAt: "7pm"},
+		{Session: "gt-b", Retrying: true},
+		{Session: "gt-c"},
+		{Session: "gt-d"},
+	}
+	providerOf := func(s string) string {
+		if s == "gt-d" {
+			return "codex"
+		}
+		return "claude"
+	}
+	got := AssessPressure(results, providerOf, now)
+	c := got["claude"]
+	if c.Sessions != 3 || c.Limited != 1 || c.Retrying != 1 || c.ResetsAt != "7pm" {
+		t.Errorf("claude = %+v", c)
+	}
+	if len(c.LimitedSessions) != 1 || c.LimitedSessions[0] != "gt-a" {
+		t.Errorf("claude limited sessions = %v", c.LimitedSessions)
+	}
+	if x := got["codex"]; x.Sessions != 1 || x.Limited != 0 || !x.ObservedAt.Equal(now) {
+		t.Errorf("codex = %+v", x)
+	}
+}
+
+func TestClaudeProjectName(t *testing.T) {
+	if got := claudeProjectName("/home/u/gt/rig/polecats/toast_1"); got != "-home-u-gt-rig-polecats-toast-1" {
+		t.Errorf("claudeProjectName = %q", got)
+	}
+}
+
+func TestMarkTranscriptRetries(t *testing.T) {
+	configDir := t.TempDir()
+	workDir := "/town/rig/polecats/toast"
+	projDir := filepath.Join(configDir, "projects", claudeProjectName(workDir))
+	if err := os.MkdirAll(projDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	recent := now.Add(-time.Minute).Format(time.RFC3339)
+	old := now.Add(-time.Hour).Format(time.RFC3339)
+	transcript := `{"type":"user","timestamp":"` + old + `"}
+{"type":"assistant","timestamp":"` + recent + `","isApiErrorMessage":true,"message":{"content":[{"type":"text","text":"API Error: 429 {\"type\":\"rate_limit_error\"}"}]}}
+`
+	if err := os.WriteFile(filepath.Join(projDir, "s1.jsonl"), []byte(transcript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	workDirOf := func(string) (string, error) { return workDir, nil }
+
+	results := []ScanResult{{Session: "gt-toast", ConfigDir: configDir}, {Session: "gt-other"}}
+	MarkTranscriptRetries(results, workDirOf, now.Add(-10*time.Minute))
+	if !results[0].Retrying || results[0].RetryLine == "" {
+		t.Errorf("recent 429 not detected: %+v", results[0])
+	}
+	if results[1].Retrying {
+		t.Errorf("session without config dir marked retrying")
+	}
+
+	// An error older than the window doesn't count.
+	results = []ScanResult{{Session: "gt-toast", ConfigDir: configDir}}
+	MarkTranscriptRetries(results, workDirOf, now)
+	if results[0].Retrying {
+		t.Errorf("stale 429 counted: %+v", results[0])
+	}
+}
@@ -20,6 +20,8 @@ type ScanResult struct {
 	RateLimited   bool   `json:"rate_limited"`             // whether rate-limit was detected
 	MatchedLine   string `json:"matched_line,omitempty"`   // the line that matched
 	ResetsAt      string `json:"resets_at,omitempty"`      // parsed reset time if available
+	Retrying      bool   `json:"retrying,omitempty"`       // hitting 429s but still retrying
+	RetryLine     string `json:"retry_line,omitempty"`     // the line that showed retrying
 }
 
 // TmuxClient is the interface for tmux operations needed by the scanner.
@@ -34,6 +36,7 @@ type TmuxClient interface {
 type Scanner struct {
 	tmux     TmuxClient
 	patterns []*regexp.Regexp
+	retry    []*regexp.Regexp
 	accounts *config.AccountsConfig
 }
 
@@ -44,6 +47,25 @@ func NewScanner(tmux TmuxClient, patterns []string, accounts *config.AccountsCon
 		patterns = constants.DefaultRateLimitPatterns
 	}
 
+	compiled, err := compilePatterns(patterns)
+	if err != nil {
+		return nil, err
+	}
+	retry, err := compilePatterns(constants.DefaultRateLimitRetryPatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Scanner{
+		tmux:     tmux,
+		patterns: compiled,
+		retry:    retry,
+		accounts: accounts,
+	}, nil
+}
+
+// compilePatterns compiles patterns for case-insensitive matching.
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
 	compiled := make([]*regexp.Regexp, 0, len(patterns))
 	for _, p := range patterns {
 		re, err := regexp.Compile("(?i)" + p)
@@ -52,12 +74,7 @@ func NewScanner(tmux TmuxClient, patterns []string, accounts *config.AccountsCon
 		}
 		compiled = append(compiled, re)
 	}
-
-	return &Scanner{
-		tmux:     tmux,
-		patterns: compiled,
-		accounts: accounts,
-	}, nil
+	return compiled, nil
 }
 
 // scanLines is the number of pane lines to capture for rate-limit detection.
@@ -143,6 +160,15 @@ func (s *Scanner) scanSession(session string) ScanResult {
 				return result
 			}
 		}
+		if !result.Retrying {
+			for _, re := range s.retry {
+				if re.MatchString(line) {
+					result.Retrying = true
+					result.RetryLine = line
+					break
+				}
+			}
+		}
 	}
 
 	return result
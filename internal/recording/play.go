@@ -0,0 +1,63 @@
+package recording
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// PlayOptions control playback.
+type PlayOptions struct {
+	// Speed multiplies playback speed; values <= 0 mean 1.
+	Speed float64
+
+	// MaxIdle caps pauses between events (after Speed is applied), so an
+	// agent thinking for minutes doesn't stall a demo. Zero means no cap.
+	MaxIdle time.Duration
+}
+
+// Delays returns how long to wait before each event under opts.
+func Delays(events []Event, opts PlayOptions) []time.Duration {
+	speed := opts.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	delays := make([]time.Duration, len(events))
+	prev := 0.0
+	for i, e := range events {
+		gap := e.Time - prev
+		if gap < 0 {
+			gap = 0
+		}
+		prev = e.Time
+		d := time.Duration(gap / speed * float64(time.Second))
+		if opts.MaxIdle > 0 && d > opts.MaxIdle {
+			d = opts.MaxIdle
+		}
+		delays[i] = d
+	}
+	return delays
+}
+
+// Play writes the output events to w with their original timing, adjusted
+// by opts. It stops early, returning ctx.Err(), when ctx is cancelled.
+func Play(ctx context.Context, w io.Writer, events []Event, opts PlayOptions) error {
+	for i, d := range Delays(events, opts) {
+		if d > 0 {
+			timer := time.NewTimer(d)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if events[i].Code != "o" {
+			continue
+		}
+		if _, err := io.WriteString(w, events[i].Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+// Package recording records agent tmux sessions as asciicast v2 files and
+// plays them back.
+//
+// A recording is fed by tmux pipe-pane, which copies everything the pane's
+// program writes to the stdin of `gt session record-pipe`. Each chunk is
+// stored as an output event with its time offset, so the file plays in
+// asciinema or with `gt replay`. See https://docs.asciinema.org/manual/asciicast/v2/.
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// FileExt is the extension of recording files.
+const FileExt = ".cast"
+
+// CompressedExt is the extension of recordings compressed after their
+// session ended.
+const CompressedExt = FileExt + ".gz"
+
+// IsRecording reports whether name is a recording file, compressed or not.
+func IsRecording(name string) bool {
+	return strings.HasSuffix(name, FileExt) || strings.HasSuffix(name, CompressedExt)
+}
+
+// Header is the first line of an asciicast v2 file.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Start returns when the recording started.
+func (h Header) Start() time.Time {
+	return time.Unix(h.Timestamp, 0)
+}
+
+// Session returns the tmux session the recording was made from.
+func (h Header) Session() string {
+	return h.Env["GT_SESSION"]
+}
+
+// Event is one asciicast v2 event: [time, code, data]. Recordings made by
+// gastown only contain output ("o") events.
+type Event struct {
+	Time float64 // seconds since the start of the recording
+	Code string
+	Data string
+}
+
+// MarshalJSON encodes e as an asciicast event array.
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{e.Time, e.Code, e.Data})
+}
+
+// UnmarshalJSON decodes an asciicast event array.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 3 {
+		return fmt.Errorf("asciicast event has %d fields, want 3", len(raw))
+	}
+	if err := json.Unmarshal(raw[0], &e.Time); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw[1], &e.Code); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw[2], &e.Data)
+}
+
+// Dir returns the recordings directory.
+// Path: <townRoot>/.runtime/recordings/
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "recordings")
+}
+
+// NewPath returns the path for a new recording of session started at start.
+func NewPath(townRoot, session string, start time.Time) string {
+	return filepath.Join(Dir(townRoot), fmt.Sprintf("%s-%s%s", session, start.UTC().Format("20060102-150405"), FileExt))
+}
+
+// Record writes an asciicast v2 recording of the raw terminal output read
+// from r to w until r is exhausted. now supplies event times (time.Now in
+// production); the header's timestamp is taken from the first call.
+// Each event is flushed as it is written, so a recording cut short by a
+// killed session is still playable.
+func Record(r io.Reader, w io.Writer, h Header, now func() time.Time) error {
+	start := now()
+	h.Version = 2
+	h.Timestamp = start.Unix()
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	if err := enc.Encode(h); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 32*1024)
+	var pending []byte // incomplete UTF-8 sequence carried to the next chunk
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			chunk := append(pending, buf[:n]...)
+			cut := utf8Boundary(chunk)
+			pending = append([]byte(nil), chunk[cut:]...)
+			if cut > 0 {
+				ev := Event{Time: now().Sub(start).Seconds(), Code: "o", Data: string(chunk[:cut])}
+				if err := enc.Encode(ev); err != nil {
+					return err
+				}
+				if err := bw.Flush(); err != nil {
+					return err
+				}
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	if len(pending) > 0 {
+		if err := enc.Encode(Event{Time: now().Sub(start).Seconds(), Code: "o", Data: string(pending)}); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// utf8Boundary returns the length of the longest prefix of b that does
+// not end in the middle of a UTF-8 sequence.
+func utf8Boundary(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if utf8.FullRune(b[i:]) {
+				return len(b)
+			}
+			return i
+		}
+	}
+	return len(b)
+}
+
+// Load reads a recording, compressed or not.
+func Load(path string) (Header, []Event, error) {
+	f, err := open(path)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var h Header
+	if !scanner.Scan() {
+		return h, nil, fmt.Errorf("%s: empty recording", path)
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &h); err != nil {
+		return h, nil, fmt.Errorf("%s: parsing header: %w", path, err)
+	}
+	if h.Version != 2 {
+		return h, nil, fmt.Errorf("%s: unsupported asciicast version %d", path, h.Version)
+	}
+	var events []Event
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // tolerate a torn last line
+		}
+		events = append(events, e)
+	}
+	return h, events, scanner.Err()
+}
+
+// Info describes a recording on disk.
+type Info struct {
+	Path   string
+	Header Header
+	Size   int64
+}
+
+// List returns the town's recordings, newest first. Only headers are read.
+func List(townRoot string) ([]Info, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []Info
+	for _, e := range entries {
+		if e.IsDir() || !IsRecording(e.Name()) {
+			continue
+		}
+		path := filepath.Join(Dir(townRoot), e.Name())
+		h, err := readHeader(path)
+		if err != nil {
+			continue
+		}
+		info := Info{Path: path, Header: h}
+		if fi, err := e.Info(); err == nil {
+			info.Size = fi.Size()
+		}
+		out = append(out, info)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Header.Timestamp != out[j].Header.Timestamp {
+			return out[i].Header.Timestamp > out[j].Header.Timestamp
+		}
+		return out[i].Path > out[j].Path
+	})
+	return out, nil
+}
+
+func readHeader(path string) (Header, error) {
+	var h Header
+	f, err := open(path)
+	if err != nil {
+		return h, err
+	}
+	defer f.Close()
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return h, err
+	}
+	if err := json.Unmarshal(line, &h); err != nil {
+		return h, err
+	}
+	return h, nil
+}
@@ -0,0 +1,149 @@
+package recording
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// chunkReader returns one chunk per Read call.
+type chunkReader struct{ chunks [][]byte }
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	return n, nil
+}
+
+func fakeClock(start time.Time, step time.Duration) func() time.Time {
+	now := start
+	return func() time.Time {
+		t := now
+		now = now.Add(step)
+		return t
+	}
+}
+
+func TestRecordAndLoad(t *testing.T) {
+	// "❯" is 3 bytes; split it across two reads.
+	prompt := []byte("❯ ")
+	r := &chunkReader{chunks: [][]byte{
+		[]byte("hello\r\n"),
+		prompt[:1],
+		prompt[1:],
+	}}
+	path := filepath.Join(t.TempDir(), "s.cast")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	h := Header{Width: 120, Height: 40, Env: map[string]string{"GT_SESSION": "gt-Toast"}}
+	if err := Record(r, f, h, fakeClock(start, 500*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	got, events, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != 2 || got.Width != 120 || got.Session() != "gt-Toast" || !got.Start().Equal(start) {
+		t.Errorf("header = %+v", got)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %+v", len(events), events)
+	}
+	if events[0].Data != "hello\r\n" || events[0].Time != 0.5 {
+		t.Errorf("event 0 = %+v", events[0])
+	}
+	if events[1].Data != "❯ " || events[1].Code != "o" {
+		t.Errorf("event 1 = %+v, want the prompt rejoined", events[1])
+	}
+}
+
+func TestEventJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Record(strings.NewReader("x"), &buf, Header{Width: 1, Height: 1}, fakeClock(time.Unix(0, 0), time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || lines[1] != `[1,"o","x"]` {
+		t.Errorf("recording = %q", buf.String())
+	}
+}
+
+func TestUTF8Boundary(t *testing.T) {
+	b := []byte("a❯")
+	for n, want := range map[int]int{1: 1, 2: 1, 3: 1, 4: 4} {
+		if got := utf8Boundary(b[:n]); got != want {
+			t.Errorf("utf8Boundary(%q) = %d, want %d", b[:n], got, want)
+		}
+	}
+}
+
+func TestListNewestFirst(t *testing.T) {
+	townRoot := t.TempDir()
+	for i, session := range []string{"gt-a", "gt-b"} {
+		start := time.Unix(int64(1000+i), 0)
+		path := NewPath(townRoot, session, start)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := Header{Width: 80, Height: 24, Env: map[string]string{"GT_SESSION": session}}
+		if err := Record(strings.NewReader("x"), f, h, fakeClock(start, time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+
+	recs, err := List(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 || recs[0].Header.Session() != "gt-b" || recs[1].Header.Session() != "gt-a" {
+		t.Errorf("List = %+v", recs)
+	}
+}
+
+func TestDelays(t *testing.T) {
+	events := []Event{{Time: 1}, {Time: 1.5}, {Time: 11.5}}
+	got := Delays(events, PlayOptions{Speed: 2, MaxIdle: 2 * time.Second})
+	want := []time.Duration{500 * time.Millisecond, 250 * time.Millisecond, 2 * time.Second}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("delay %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPlayWritesOutput(t *testing.T) {
+	var buf bytes.Buffer
+	events := []Event{{Time: 0, Code: "o", Data: "a"}, {Time: 0, Code: "i", Data: "x"}, {Time: 0, Code: "o", Data: "b"}}
+	if err := Play(context.Background(), &buf, events, PlayOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "ab" {
+		t.Errorf("Play wrote %q, want %q", buf.String(), "ab")
+	}
+}
+
+func TestPipeCommandQuotes(t *testing.T) {
+	got := PipeCommand("/town's/rec.cast", "gt-Toast", 80, 24)
+	want := `gt session record-pipe --out '/town'\''s/rec.cast' --session 'gt-Toast' --cols 80 --rows 24`
+	if got != want {
+		t.Errorf("PipeCommand = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,271 @@
+package recording
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// recordingStampLen is the length of the "-20060102-150405" start time
+// NewPath appends to the session name.
+const recordingStampLen = len("-20060102-150405")
+
+// open opens a recording, decompressing it if needed.
+func open(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, CompressedExt) {
+		return f, nil
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return gzipFile{zr, f}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g gzipFile) Close() error {
+	_ = g.Reader.Close()
+	return g.f.Close()
+}
+
+// Compress gzips a finished recording next to it and removes the original.
+// The compressed file keeps the original's modification time, so retention
+// still counts from when the session last wrote to it. Returns the new path.
+func Compress(path string) (string, error) {
+	if !strings.HasSuffix(path, FileExt) {
+		return "", fmt.Errorf("%s: not an uncompressed recording", path)
+	}
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	fi, err := src.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	out := path + ".gz"
+	tmp := out + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		_ = os.Chtimes(tmp, fi.ModTime(), fi.ModTime())
+		err = os.Rename(tmp, out)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return "", fmt.Errorf("compressing %s: %w", path, err)
+	}
+	return out, os.Remove(path)
+}
+
+// StoredFile is one of a session's transcript files: a recording, or the
+// input transcript kept by the tmux package.
+type StoredFile struct {
+	Path    string    `json:"path"`
+	Session string    `json:"session"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// IsRecording reports whether the file is a recording rather than an input
+// transcript.
+func (f StoredFile) IsRecording() bool {
+	return IsRecording(f.Path)
+}
+
+// StoredFiles lists the town's transcript files of sessions match accepts
+// (nil = all), oldest first.
+func StoredFiles(townRoot string, match func(session string) bool) ([]StoredFile, error) {
+	var files []StoredFile
+	add := func(dir string, sessionOf func(name string) string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			session := sessionOf(e.Name())
+			if session == "" || (match != nil && !match(session)) {
+				continue
+			}
+			fi, err := e.Info()
+			if err != nil {
+				continue
+			}
+			files = append(files, StoredFile{
+				Path:    filepath.Join(dir, e.Name()),
+				Session: session,
+				Size:    fi.Size(),
+				ModTime: fi.ModTime(),
+			})
+		}
+		return nil
+	}
+
+	if err := add(Dir(townRoot), recordingSession); err != nil {
+		return nil, err
+	}
+	if err := add(tmux.TranscriptDir(townRoot), func(name string) string {
+		return strings.TrimSuffix(name, ".jsonl")
+	}); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].ModTime.Before(files[j].ModTime) })
+	return files, nil
+}
+
+// recordingSession returns the session a recording file name belongs to,
+// or "" if name is not a recording made by NewPath.
+func recordingSession(name string) string {
+	base := strings.TrimSuffix(name, ".gz")
+	if !strings.HasSuffix(base, FileExt) {
+		return ""
+	}
+	base = strings.TrimSuffix(base, FileExt)
+	if len(base) <= recordingStampLen {
+		return ""
+	}
+	return base[:len(base)-recordingStampLen]
+}
+
+// RetentionPolicy limits how much transcript storage a rig keeps.
+type RetentionPolicy struct {
+	MaxAge   time.Duration // delete files last written longer ago (0 = keep)
+	MaxBytes int64         // delete the oldest files until the total fits (0 = no cap)
+}
+
+// RotateResult reports what Rotate did, or would do on a dry run.
+type RotateResult struct {
+	Compressed []string `json:"compressed,omitempty"`
+	Deleted    []string `json:"deleted,omitempty"`
+	Freed      int64    `json:"freed"` // bytes deleted
+	Bytes      int64    `json:"bytes"` // total left (before compression, on a dry run)
+}
+
+// Rotate applies policy to files, as listed by StoredFiles. Recordings of
+// ended sessions are compressed, then files past MaxAge are deleted, then
+// the oldest files until the total fits MaxBytes. A running session's input
+// transcript and newest recording are still being written and are never
+// touched; live reports whether a session is running. Failures on one file
+// don't stop the others and are returned together.
+func Rotate(files []StoredFile, policy RetentionPolicy, live func(session string) bool, now time.Time, dryRun bool) (RotateResult, error) {
+	var res RotateResult
+	var errs []error
+
+	writing := make(map[int]bool)
+	newest := make(map[string]int)
+	for i, f := range files {
+		if !live(f.Session) {
+			continue
+		}
+		if !f.IsRecording() {
+			writing[i] = true
+		} else if j, ok := newest[f.Session]; !ok || !files[j].ModTime.After(f.ModTime) {
+			newest[f.Session] = i
+		}
+	}
+	for _, i := range newest {
+		writing[i] = true
+	}
+
+	kept := make([]bool, len(files))
+	for i := range files {
+		kept[i] = true
+		f := &files[i]
+		if writing[i] || !strings.HasSuffix(f.Path, FileExt) {
+			continue
+		}
+		res.Compressed = append(res.Compressed, f.Path)
+		if dryRun {
+			continue
+		}
+		out, err := Compress(f.Path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		f.Path = out
+		if fi, err := os.Stat(out); err == nil {
+			f.Size = fi.Size()
+		}
+	}
+
+	for _, f := range files {
+		res.Bytes += f.Size
+	}
+	remove := func(i int) {
+		f := files[i]
+		kept[i] = false
+		res.Deleted = append(res.Deleted, f.Path)
+		res.Freed += f.Size
+		res.Bytes -= f.Size
+		if dryRun {
+			return
+		}
+		if err := os.Remove(f.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	if policy.MaxAge > 0 {
+		for i, f := range files {
+			if !writing[i] && now.Sub(f.ModTime) > policy.MaxAge {
+				remove(i)
+			}
+		}
+	}
+	if policy.MaxBytes > 0 {
+		for i := range files {
+			if res.Bytes <= policy.MaxBytes {
+				break
+			}
+			if kept[i] && !writing[i] {
+				remove(i)
+			}
+		}
+	}
+	return res, errors.Join(errs...)
+}
+
+// TotalSize sums the sizes of files.
+func TotalSize(files []StoredFile) int64 {
+	var n int64
+	for _, f := range files {
+		n += f.Size
+	}
+	return n
+}
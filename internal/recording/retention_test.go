@@ -0,0 +1,135 @@
+package recording
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestCompressKeepsRecordingPlayable(t *testing.T) {
+	town := t.TempDir()
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	path := NewPath(town, "gt-Toast", start)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Record(bytes.NewReader([]byte("hello\r\n")), &buf, Header{Width: 80, Height: 24, Env: map[string]string{"GT_SESSION": "gt-Toast"}}, fakeClock(start, time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ended := start.Add(time.Hour)
+	if err := os.Chtimes(path, ended, ended); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := Compress(path)
+	if err != nil {
+		t.Fatalf("Compress: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("original still exists: %v", err)
+	}
+	if fi, err := os.Stat(out); err != nil || !fi.ModTime().Equal(ended) {
+		t.Errorf("compressed file mtime = %v (err %v), want %v", fi.ModTime(), err, ended)
+	}
+
+	h, events, err := Load(out)
+	if err != nil {
+		t.Fatalf("Load compressed: %v", err)
+	}
+	if h.Session() != "gt-Toast" || len(events) != 1 || events[0].Data != "hello\r\n" {
+		t.Errorf("Load = %+v %+v", h, events)
+	}
+	recs, err := List(town)
+	if err != nil || len(recs) != 1 || recs[0].Path != out {
+		t.Errorf("List = %+v, %v; want the compressed recording", recs, err)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	write := func(path string, size int, age time.Duration) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, bytes.Repeat([]byte("x"), size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	day := 24 * time.Hour
+	expired := NewPath(town, "gt-Old", now.Add(-30*day))
+	write(expired, 100, 30*day)
+	ended := NewPath(town, "gt-Nux", now.Add(-2*day))
+	write(ended, 5000, 2*day)
+	earlier := NewPath(town, "gt-Toast", now.Add(-3*day))
+	write(earlier, 300, 3*day)
+	current := NewPath(town, "gt-Toast", now.Add(-4*day))
+	write(current, 400, time.Minute)
+	input := tmux.TranscriptPath(town, "gt-Toast")
+	write(input, 200, 40*day)
+	other := NewPath(town, "bd-Ace", now.Add(-40*day))
+	write(other, 100, 40*day)
+
+	files, err := StoredFiles(town, func(s string) bool { return s != "bd-Ace" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 5 {
+		t.Fatalf("StoredFiles = %d files, want 5 (bd-Ace filtered out)", len(files))
+	}
+	live := func(s string) bool { return s == "gt-Toast" }
+
+	policy := RetentionPolicy{MaxAge: 14 * day, MaxBytes: 900}
+
+	// A dry run compresses nothing, so the cap deletes the oldest ended
+	// recordings after the expired one.
+	dry, err := Rotate(append([]StoredFile(nil), files...), policy, live, now, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if want := []string{expired, earlier, ended}; len(dry.Deleted) != 3 || dry.Deleted[0] != want[0] || dry.Deleted[1] != want[1] || dry.Deleted[2] != want[2] {
+		t.Errorf("dry run deletes %v, want %v", dry.Deleted, want)
+	}
+	if dry.Bytes != 600 {
+		t.Errorf("dry run leaves %d bytes, want 600", dry.Bytes)
+	}
+	if _, err := os.Stat(ended); err != nil {
+		t.Fatalf("dry run touched files: %v", err)
+	}
+
+	res, err := Rotate(files, policy, live, now, false)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	// Ended sessions' recordings are compressed; the running session's
+	// current recording and input transcript are left alone.
+	if len(res.Compressed) != 3 {
+		t.Errorf("compressed %v, want the 3 recordings not being written", res.Compressed)
+	}
+	for _, p := range []string{current, input, other} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("%s touched: %v", p, err)
+		}
+	}
+	if _, err := os.Stat(expired + ".gz"); !os.IsNotExist(err) {
+		t.Errorf("expired recording kept: %v", err)
+	}
+	if _, err := os.Stat(earlier + ".gz"); err != nil {
+		t.Errorf("earlier recording of running session: %v", err)
+	}
+	if res.Bytes > 900 {
+		t.Errorf("%d bytes left, want at most the 900 cap", res.Bytes)
+	}
+}
@@ -0,0 +1,46 @@
+package recording
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// StartSession starts recording session's agent pane to a new file and
+// returns its path. The recorder is `gt session record-pipe`, run by tmux
+// from the pane's pipe, so it keeps recording after the caller exits and
+// stops when the pane does.
+func StartSession(t *tmux.Tmux, townRoot, session string) (string, error) {
+	width, height, err := t.GetPaneSize(session)
+	if err != nil {
+		return "", fmt.Errorf("reading pane size: %w", err)
+	}
+	path := NewPath(townRoot, session, time.Now())
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("creating recordings dir: %w", err)
+	}
+	if err := t.PipePane(session, PipeCommand(path, session, width, height)); err != nil {
+		return "", fmt.Errorf("starting pipe-pane: %w", err)
+	}
+	return path, nil
+}
+
+// StopSession stops recording session. The recorder finishes the file when
+// its stdin closes.
+func StopSession(t *tmux.Tmux, session string) error {
+	return t.StopPipePane(session)
+}
+
+// PipeCommand returns the shell command tmux runs to record into path.
+func PipeCommand(path, session string, width, height int) string {
+	return fmt.Sprintf("gt session record-pipe --out %s --session %s --cols %d --rows %d",
+		shellQuote(path), shellQuote(session), width, height)
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
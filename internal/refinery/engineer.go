@@ -62,6 +62,7 @@ type GateResult struct {
 	Name    string
 	Success bool
 	Error   string
+	Output  string // Tail of combined stdout/stderr on failure
 	Elapsed time.Duration
 }
 
@@ -112,8 +113,22 @@ type MergeQueueConfig struct {
 	// GatesParallel controls whether gates run concurrently.
 	// When true, all gates start simultaneously; any failure = overall failure.
 	GatesParallel bool `json:"gates_parallel"`
+
+	// ConflictDispatch slings each conflict-resolution task as soon as it is
+	// created: "original" sends it back to the polecat that did the work,
+	// any other value is a gt sling target (a rig for a fresh polecat, or an
+	// agent such as "gastown/crew/resolver"). Empty leaves the task for
+	// manual dispatch.
+	ConflictDispatch string `json:"conflict_dispatch"`
 }
 
+// ConflictDispatchOriginal dispatches conflict-resolution tasks to the MR's
+// original polecat.
+const ConflictDispatchOriginal = "original"
+
+// conflictHunkLines caps the conflicting hunks quoted in a resolution task.
+const conflictHunkLines = 200
+
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
 func DefaultMergeQueueConfig() *MergeQueueConfig {
 	return &MergeQueueConfig{
@@ -196,6 +211,7 @@ type Engineer struct {
 	mergeSlotRelease      func(holder string) error
 	mergeSlotMaxRetries   int           // Max retries for slot acquisition (0 = no retry)
 	mergeSlotRetryBackoff time.Duration // Initial backoff between retries
+	dispatch              func(beadID, target string) error
 }
 
 // NewEngineer creates a new Engineer for the given rig.
@@ -230,6 +246,18 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		},
 		mergeSlotMaxRetries:   10,
 		mergeSlotRetryBackoff: 500 * time.Millisecond,
+		dispatch: func(beadID, target string) error {
+			args := []string{"sling", beadID, target, "--no-convoy"}
+			if strings.Contains(target, "/polecats/") {
+				args = append(args, "--create") // the original polecat may be gone
+			}
+			cmd := exec.Command("gt", args...) //nolint:gosec // G204: args are constructed internally
+			cmd.Dir = r.Path
+			if out, err := cmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("gt sling: %w (%s)", err, strings.TrimSpace(string(out)))
+			}
+			return nil
+		},
 	}
 }
 
@@ -278,6 +306,7 @@ func (e *Engineer) LoadConfig() error {
 		StaleClaimTimeout    *string                    `json:"stale_claim_timeout"`
 		Gates                map[string]*gateConfigRaw  `json:"gates"`
 		GatesParallel        *bool                      `json:"gates_parallel"`
+		ConflictDispatch     *string                    `json:"conflict_dispatch"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -345,6 +374,9 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.GatesParallel != nil {
 		e.config.GatesParallel = *mqRaw.GatesParallel
 	}
+	if mqRaw.ConflictDispatch != nil {
+		e.config.ConflictDispatch = *mqRaw.ConflictDispatch
+	}
 
 	return nil
 }
@@ -368,7 +400,20 @@ type ProcessResult struct {
 	Error       string
 	Conflict    bool
 	TestsFailed bool
-	SlotTimeout bool // Merge slot contention timeout (distinct from build/test failure)
+	SlotTimeout bool   // Merge slot contention timeout (distinct from build/test failure)
+	Output      string // Tail of test/gate output when TestsFailed
+}
+
+// failureOutputLines caps how much test output is kept for failure reports.
+const failureOutputLines = 60
+
+// tailLines returns the last n lines of s, trimmed.
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
 }
 
 // doMerge performs the actual git merge operation.
@@ -407,6 +452,14 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	// Step 3: Check for merge conflicts (using local branch)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking for conflicts...\n")
 	conflicts, err := e.git.CheckConflicts(branch, target)
+	if err != nil && git.IsPromisorFetchError(err) {
+		// Partial clone: the test merge needed objects origin couldn't
+		// supply. That says nothing about the branch, so it isn't a conflict.
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("conflict check could not fetch objects missing from the partial clone (is origin reachable?): %v", err),
+		}
+	}
 	if err != nil {
 		return ProcessResult{
 			Success:  false,
@@ -453,6 +506,14 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	}
 
 	// Step 4: Run quality gates (or legacy tests) if configured
+	if len(e.config.Gates) > 0 || (e.config.RunTests && e.config.TestCommand != "") {
+		if err := e.ensureFullCheckout(); err != nil {
+			return ProcessResult{
+				Success: false,
+				Error:   err.Error(),
+			}
+		}
+	}
 	if len(e.config.Gates) > 0 {
 		// New gates system: run configured quality gates
 		gateResult := e.runGates(ctx)
@@ -468,6 +529,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 				Success:     false,
 				TestsFailed: true,
 				Error:       result.Error,
+				Output:      result.Output,
 			}
 		}
 		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
@@ -498,6 +560,12 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 				Error:    "merge conflict during actual merge",
 			}
 		}
+		if git.IsPromisorFetchError(err) {
+			return ProcessResult{
+				Success: false,
+				Error:   fmt.Sprintf("merge could not fetch objects missing from the partial clone (is origin reachable?): %v", err),
+			}
+		}
 		return ProcessResult{
 			Success: false,
 			Error:   fmt.Sprintf("merge failed: %v", err),
@@ -645,6 +713,7 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 	}
 
 	var lastErr error
+	var lastOutput string
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Retrying tests (attempt %d/%d)...\n", attempt, maxRetries)
@@ -665,6 +734,7 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 			return ProcessResult{Success: true}
 		}
 		lastErr = err
+		lastOutput = stdout.String() + stderr.String()
 
 		// Check if context was canceled
 		if ctx.Err() != nil {
@@ -679,7 +749,22 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		Success:     false,
 		TestsFailed: true,
 		Error:       fmt.Sprintf("tests failed after %d attempts: %v", maxRetries, lastErr),
+		Output:      tailLines(lastOutput, failureOutputLines),
+	}
+}
+
+// ensureFullCheckout makes sure gates and tests see the whole tree. Polecat
+// worktrees of rigs with partial_clone.sparse are sparse; the refinery's
+// must not be, or gates would pass against a subset of the repo.
+func (e *Engineer) ensureFullCheckout() error {
+	if !git.IsSparseCheckoutConfigured(e.workDir) {
+		return nil
 	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Refinery worktree is a sparse checkout; restoring the full tree before gates\n")
+	if err := git.RemoveSparseCheckout(e.workDir); err != nil {
+		return fmt.Errorf("refinery worktree is sparse and could not be restored: %w", err)
+	}
+	return nil
 }
 
 // runGate executes a single quality gate command and returns the result.
@@ -736,6 +821,7 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 		Name:    name,
 		Success: false,
 		Error:   errMsg,
+		Output:  tailLines(stdout.String()+stderr.String(), failureOutputLines),
 		Elapsed: elapsed,
 	}
 }
@@ -786,12 +872,16 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 
 	// Report results
 	var failures []string
+	var output strings.Builder
 	for _, r := range results {
 		if r.Success {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: passed (%v)\n", r.Name, r.Elapsed.Truncate(time.Millisecond))
 		} else {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: FAILED (%v) - %s\n", r.Name, r.Elapsed.Truncate(time.Millisecond), r.Error)
 			failures = append(failures, fmt.Sprintf("%s: %s", r.Name, r.Error))
+			if r.Output != "" {
+				fmt.Fprintf(&output, "--- gate %s ---\n%s\n", r.Name, r.Output)
+			}
 		}
 	}
 
@@ -800,6 +890,7 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 			Success:     false,
 			TestsFailed: true,
 			Error:       fmt.Sprintf("quality gates failed: %s", strings.Join(failures, "; ")),
+			Output:      strings.TrimSpace(output.String()),
 		}
 	}
 
@@ -848,6 +939,8 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
+	e.noteConflictResolved(mr)
+
 	// Use the shared merge logic
 	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
 }
@@ -979,10 +1072,25 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
+	// Test/gate failures get a bug filed against the work so the failure is
+	// tracked and dispatchable. The MR is blocked on it rather than retried
+	// against the same broken branch every poll cycle.
+	if result.TestsFailed {
+		bugID, err := e.createTestFailureIssueForMR(mr, result)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to file test failure issue: %v\n", err)
+		} else if err := e.beads.AddDependency(mr.ID, bugID); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to block MR on %s: %v\n", bugID, err)
+		} else {
+			mr.BlockedBy = bugID
+			_, _ = fmt.Fprintf(e.output, "[Engineer] MR %s blocked on test failure %s\n", mr.ID, bugID)
+		}
+	}
+
 	// Log the failure - MR stays in queue but may be blocked
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed: %s - %s\n", mr.ID, result.Error)
 	if mr.BlockedBy != "" {
-		_, _ = fmt.Fprintln(e.output, "[Engineer] MR blocked pending conflict resolution - queue continues to next MR")
+		_, _ = fmt.Fprintln(e.output, "[Engineer] MR blocked pending resolution - queue continues to next MR")
 	} else {
 		_, _ = fmt.Fprintln(e.output, "[Engineer] MR remains in queue for retry")
 	}
@@ -1093,6 +1201,9 @@ The Refinery will automatically retry the merge after you force-push.`,
 		mr.Branch,
 		mr.Target,
 	)
+	if hunks, err := e.git.ConflictHunks(mr.Branch, "origin/"+mr.Target, conflictHunkLines); err == nil && hunks != "" {
+		description += fmt.Sprintf("\n\n## Conflicting hunks (%s into %s)\n```\n%s```", mr.Branch, mr.Target, hunks)
+	}
 
 	// Create the conflict resolution task
 	taskTitle := fmt.Sprintf("Resolve merge conflicts: %s", originalTitle)
@@ -1113,9 +1224,192 @@ The Refinery will automatically retry the merge after you force-push.`,
 
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Created conflict resolution task: %s (P%d)\n", task.ID, task.Priority)
 
+	e.recordConflictOnMR(mr.ID, task.ID, mainSHA, retryCount)
+
+	if target := e.conflictDispatchTarget(mr); target != "" && e.dispatch != nil {
+		if err := e.dispatch(task.ID, target); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to dispatch %s to %s: %v\n", task.ID, target, err)
+		} else {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Dispatched conflict resolution %s to %s\n", task.ID, target)
+		}
+	}
+
 	return task.ID, nil
 }
 
+// conflictDispatchTarget returns where a conflict-resolution task for mr is
+// slung (MergeQueueConfig.ConflictDispatch), or "" to leave it undispatched.
+func (e *Engineer) conflictDispatchTarget(mr *MRInfo) string {
+	switch e.config.ConflictDispatch {
+	case "":
+		return ""
+	case ConflictDispatchOriginal:
+		if mr.Worker == "" {
+			return ""
+		}
+		if strings.Contains(mr.Worker, "/") {
+			return mr.Worker // already an agent address
+		}
+		return e.rig.Name + "/polecats/" + mr.Worker
+	default:
+		return e.config.ConflictDispatch
+	}
+}
+
+// recordConflictOnMR attaches a conflict-resolution task to the MR bead's
+// fields, so the MR shows what it is waiting on.
+func (e *Engineer) recordConflictOnMR(mrID, taskID, mainSHA string, retryCount int) {
+	if mrID == "" {
+		return
+	}
+	e.updateMRFields(mrID, func(f *beads.MRFields) {
+		f.ConflictTaskID = taskID
+		f.LastConflictSHA = mainSHA
+		f.RetryCount = retryCount
+	})
+}
+
+// noteConflictResolved clears an MR's conflict task once it has closed —
+// the resolver's rebase has landed on the branch and the MR is being
+// retried.
+func (e *Engineer) noteConflictResolved(mr *MRInfo) {
+	if mr.ID == "" {
+		return
+	}
+	issue, err := e.beads.Show(mr.ID)
+	if err != nil {
+		return
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil || fields.ConflictTaskID == "" {
+		return
+	}
+	if open, err := e.IsBeadOpen(fields.ConflictTaskID); err != nil || open {
+		return
+	}
+	head, _ := e.git.Rev(mr.Branch)
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Conflict task %s resolved; retrying %s at %.8s\n", fields.ConflictTaskID, mr.Branch, head)
+	e.updateMRFields(mr.ID, func(f *beads.MRFields) {
+		f.ConflictTaskID = ""
+	})
+}
+
+// updateMRFields applies update to an MR bead's structured fields.
+func (e *Engineer) updateMRFields(mrID string, update func(*beads.MRFields)) {
+	issue, err := e.beads.Show(mrID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mrID, err)
+		return
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+	update(fields)
+	desc := beads.SetMRFields(issue, fields)
+	if err := e.beads.Update(mrID, beads.UpdateOptions{Description: &desc}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s: %v\n", mrID, err)
+	}
+}
+
+// createTestFailureIssueForMR files a bug for an MR that failed its tests or
+// quality gates. The bug records the MR, branch, and the tail of the failing
+// output. If an open bug was already filed for the same MR or branch, that
+// bug is reused. Returns the bug's ID so the MR can be blocked on it, which links
+// the two: the MR shows the bug as its blocker.
+func (e *Engineer) createTestFailureIssueForMR(mr *MRInfo, result ProcessResult) (string, error) {
+	originalTitle := mr.SourceIssue
+	if mr.SourceIssue != "" {
+		if sourceIssue, err := e.beads.Show(mr.SourceIssue); err == nil && sourceIssue != nil {
+			originalTitle = sourceIssue.Title
+		}
+	}
+	if originalTitle == "" {
+		originalTitle = mr.Branch
+	}
+
+	output := result.Output
+	if output == "" {
+		output = "(no output captured)"
+	}
+	description := fmt.Sprintf(`Tests failed merging branch %s into %s
+
+## Metadata
+- Original MR: %s
+- Branch: %s
+- Original issue: %s
+- Worker: %s
+
+## Failure
+%s
+
+## Output (tail)
+`+"```"+`
+%s
+`+"```"+`
+
+## Instructions
+1. Check out the branch: git checkout %s
+2. Reproduce and fix the failure, then push the branch
+3. Close this issue: bd close <this-issue-id>
+
+The Refinery will retry the merge once this issue is closed.`,
+		mr.Branch, mr.Target,
+		mr.ID,
+		mr.Branch,
+		mr.SourceIssue,
+		mr.Worker,
+		result.Error,
+		output,
+		mr.Branch,
+	)
+
+	title := fmt.Sprintf("Fix failing tests: %s", originalTitle)
+
+	// Reuse an open bug filed for this MR or branch instead of filing a
+	// duplicate, so repeated merge attempts don't spawn one fix task each.
+	if issues, err := e.beads.ListActive(); err == nil {
+		for _, issue := range issues {
+			if isTestFailureIssueFor(issue, mr) {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Test failure already filed as %s, not filing a duplicate\n", issue.ID)
+				return issue.ID, nil
+			}
+		}
+	}
+
+	bug, err := e.beads.Create(beads.CreateOptions{
+		Title:       title,
+		Type:        "bug",
+		Priority:    mr.Priority,
+		Description: description,
+		Actor:       e.rig.Name + "/refinery",
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating test failure issue: %w", err)
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Filed test failure issue: %s (P%d)\n", bug.ID, bug.Priority)
+	return bug.ID, nil
+}
+
+// isTestFailureIssueFor reports whether issue is a test-failure bug filed by
+// createTestFailureIssueForMR for mr, going by the MR ID or branch recorded
+// in its metadata rather than by text similarity: bugs for different MRs
+// share most of their wording.
+func isTestFailureIssueFor(issue *beads.Issue, mr *MRInfo) bool {
+	if issue.Type != "bug" || !strings.HasPrefix(issue.Title, "Fix failing tests: ") {
+		return false
+	}
+	for _, line := range strings.Split(issue.Description, "\n") {
+		switch {
+		case mr.ID != "" && line == "- Original MR: "+mr.ID:
+			return true
+		case mr.Branch != "" && line == "- Branch: "+mr.Branch:
+			return true
+		}
+	}
+	return false
+}
+
 // IsBeadOpen checks if a bead is still open (not closed).
 // This is used as a status checker to filter blocked MRs.
 func (e *Engineer) IsBeadOpen(beadID string) (bool, error) {
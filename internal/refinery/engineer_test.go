@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -82,6 +83,7 @@ func TestEngineer_LoadConfig_WithMergeQueue(t *testing.T) {
 			"run_tests":           false,
 			"test_command":        "make test",
 			"stale_claim_timeout": "1h",
+			"conflict_dispatch":   "original",
 		},
 	}
 
@@ -117,6 +119,9 @@ func TestEngineer_LoadConfig_WithMergeQueue(t *testing.T) {
 	if e.config.StaleClaimTimeout != 1*time.Hour {
 		t.Errorf("expected StaleClaimTimeout 1h, got %v", e.config.StaleClaimTimeout)
 	}
+	if e.config.ConflictDispatch != ConflictDispatchOriginal {
+		t.Errorf("expected ConflictDispatch 'original', got %q", e.config.ConflictDispatch)
+	}
 
 	// Check that defaults are preserved for unspecified fields
 	if e.config.OnConflict != "assign_back" {
@@ -394,6 +399,32 @@ func TestRunGate_Failure(t *testing.T) {
 	}
 }
 
+func TestRunGate_FailureCapturesOutput(t *testing.T) {
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.workDir = t.TempDir()
+
+	result := e.runGate(context.Background(), "fail-test", &GateConfig{
+		Cmd: "echo FAIL: TestParser; exit 1",
+	})
+
+	if result.Success {
+		t.Fatal("expected failure")
+	}
+	if !strings.Contains(result.Output, "FAIL: TestParser") {
+		t.Errorf("expected output to contain test failure, got %q", result.Output)
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	if got := tailLines("a\nb\nc\n", 2); got != "b\nc" {
+		t.Errorf("tailLines(3 lines, 2) = %q, want %q", got, "b\nc")
+	}
+	if got := tailLines("only\n", 5); got != "only" {
+		t.Errorf("tailLines(1 line, 5) = %q, want %q", got, "only")
+	}
+}
+
 func TestRunGate_EmptyCmd(t *testing.T) {
 	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
 	e := NewEngineer(r)
@@ -819,3 +850,56 @@ func TestIsClaimStale(t *testing.T) {
 		})
 	}
 }
+
+func TestConflictDispatchTarget(t *testing.T) {
+	tests := []struct {
+		dispatch string
+		worker   string
+		want     string
+	}{
+		{"", "Toast", ""},
+		{"original", "Toast", "gastown/polecats/Toast"},
+		{"original", "gastown/crew/max", "gastown/crew/max"},
+		{"original", "", ""},
+		{"gastown/crew/resolver", "Toast", "gastown/crew/resolver"},
+		{"gastown", "Toast", "gastown"},
+	}
+	for _, tt := range tests {
+		e := &Engineer{
+			rig:    &rig.Rig{Name: "gastown"},
+			config: &MergeQueueConfig{ConflictDispatch: tt.dispatch},
+		}
+		if got := e.conflictDispatchTarget(&MRInfo{Worker: tt.worker}); got != tt.want {
+			t.Errorf("conflictDispatchTarget(%q, worker %q) = %q, want %q", tt.dispatch, tt.worker, got, tt.want)
+		}
+	}
+}
+
+func TestIsTestFailureIssueFor(t *testing.T) {
+	bug := &beads.Issue{
+		Type:        "bug",
+		Title:       "Fix failing tests: Refactor config loader",
+		Description: "Tests failed merging branch polecat/toast/gt-abc into main\n\n## Metadata\n- Original MR: gt-mr1\n- Branch: polecat/toast/gt-abc\n",
+	}
+	tests := []struct {
+		name string
+		mr   MRInfo
+		want bool
+	}{
+		{"same MR", MRInfo{ID: "gt-mr1", Branch: "polecat/toast/gt-abc"}, true},
+		{"resubmitted branch", MRInfo{ID: "gt-mr2", Branch: "polecat/toast/gt-abc"}, true},
+		{"other MR from the same polecat", MRInfo{ID: "gt-mr3", Branch: "polecat/toast/gt-def"}, false},
+		{"no MR or branch", MRInfo{}, false},
+	}
+	for _, tt := range tests {
+		if got := isTestFailureIssueFor(bug, &tt.mr); got != tt.want {
+			t.Errorf("%s: isTestFailureIssueFor = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	task := *bug
+	task.Type = "task"
+	if isTestFailureIssueFor(&task, &MRInfo{ID: "gt-mr1"}) {
+		t.Error("a task with the same metadata matched")
+	}
+}
@@ -175,6 +175,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
+	command = session.WithTemplateEnv(command, m.rig.Path, "refinery")
 	if err := t.NewSessionWithCommand(sessionID, refineryRigDir, command); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
@@ -199,7 +200,15 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 
 	// Apply theme (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "refinery", "refinery")
+	_ = session.ConfigureSession(t, session.SessionConfig{
+		SessionID: sessionID,
+		WorkDir:   refineryRigDir,
+		Role:      "refinery",
+		RigPath:   m.rig.Path,
+		RigName:   m.rig.Name,
+		AgentName: "refinery",
+		Theme:     &theme,
+	})
 
 	// Accept startup dialogs (workspace trust + bypass permissions) if they appear.
 	// Must be before WaitForRuntimeReady to avoid race where dialog blocks prompt detection.
@@ -214,6 +223,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	}
 
 	_ = runtime.RunStartupFallback(t, sessionID, "refinery", runtimeConfig)
+	_ = session.RegisterSession(townRoot, session.SessionRecord{Session: sessionID, Role: session.RoleRefinery, Rig: m.rig.Name})
 
 	return nil
 }
@@ -483,6 +493,33 @@ func (m *Manager) RejectMR(idOrBranch string, reason string, notify bool) (*Merg
 	return mr, nil
 }
 
+// LabelReviewApproved marks a merge request approved by a reviewer.
+const LabelReviewApproved = "review:approved"
+
+// ApproveMR records a reviewer's approval of an open merge request: the MR
+// bead gets the review:approved label and the comment, if any. Approval
+// does not merge anything; the refinery processes the MR as usual.
+func (m *Manager) ApproveMR(idOrBranch, comment string) (*MergeRequest, error) {
+	mr, err := m.FindMR(idOrBranch)
+	if err != nil {
+		return nil, err
+	}
+	if mr.IsClosed() {
+		return nil, fmt.Errorf("%w: MR is already closed with reason: %s", ErrClosedImmutable, mr.CloseReason)
+	}
+
+	b := beads.New(m.rig.BeadsPath())
+	if err := b.Update(mr.ID, beads.UpdateOptions{AddLabels: []string{LabelReviewApproved}}); err != nil {
+		return nil, fmt.Errorf("labelling MR bead: %w", err)
+	}
+	if comment != "" {
+		if _, err := b.Run("comment", mr.ID, "approved: "+comment); err != nil {
+			return nil, fmt.Errorf("commenting on MR bead: %w", err)
+		}
+	}
+	return mr, nil
+}
+
 // notifyWorkerRejected sends a rejection notification to a polecat.
 func (m *Manager) notifyWorkerRejected(mr *MergeRequest, reason string) {
 	router := mail.NewRouter(m.workDir)
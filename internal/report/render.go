@@ -0,0 +1,287 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/metrics"
+)
+
+// Markdown renders the report as markdown for pasting into a team update.
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Gas Town report: %s\n\n", r.periodLabel())
+
+	sb.WriteString("## Summary\n\n")
+	for _, line := range r.summaryLines() {
+		fmt.Fprintf(&sb, "- %s\n", line)
+	}
+
+	if len(r.Rigs) > 0 {
+		sb.WriteString("\n## By rig\n\n")
+		sb.WriteString("| Rig | Merged | Failed | Issues closed | Cycle time (median) | Cost | Slings |\n")
+		sb.WriteString("|---|---:|---:|---:|---:|---:|---:|\n")
+		for _, s := range append(r.Rigs, r.Total) {
+			name := s.Rig
+			if s.Rig == r.Total.Rig {
+				name = "**total**"
+			}
+			fmt.Fprintf(&sb, "| %s | %d | %d | %d | %s | %s | %d |\n",
+				name, s.Merged, s.MergeFailed, s.IssuesClosed, FormatDuration(s.CycleTimeMedian), formatUSD(s.CostUSD), s.Slings)
+		}
+	}
+
+	if len(r.Days) > 0 {
+		sb.WriteString("\n## By day\n\n")
+		if r.hasDailyMetrics() {
+			sb.WriteString("| Day | Merged | Cost | Sessions (avg) | Queue (avg) |\n")
+			sb.WriteString("|---|---:|---:|---:|---:|\n")
+		} else {
+			sb.WriteString("| Day | Merged | Cost |\n")
+			sb.WriteString("|---|---:|---:|\n")
+		}
+		for _, d := range r.Days {
+			fmt.Fprintf(&sb, "| %s | %d %s | %s |", d.Date.Format("Mon Jan 2"), d.Merged, textBar(d.Merged, r.maxDailyMerged()), formatUSD(d.CostUSD))
+			if r.hasDailyMetrics() {
+				fmt.Fprintf(&sb, " %s | %s |", formatMean(d.SessionsAlive), formatMean(d.QueueDepth))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(r.Trends) > 0 {
+		sb.WriteString("\n## Trends\n\n")
+		sb.WriteString("From the metrics history recorded by the daemon.\n\n")
+		sb.WriteString("| Metric | Min | Mean | Max | Latest |\n")
+		sb.WriteString("|---|---:|---:|---:|---:|\n")
+		for _, t := range r.Trends {
+			if metrics.IsCounter(t.Name) {
+				fmt.Fprintf(&sb, "| %s (total %s) | %s | %s | %s | %s |\n",
+					t.Name, formatMetric(t.Total), formatMetric(t.Min), formatMetric(t.Mean), formatMetric(t.Max), formatMetric(t.Last))
+				continue
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n",
+				t.Name, formatMetric(t.Min), formatMetric(t.Mean), formatMetric(t.Max), formatMetric(t.Last))
+		}
+	}
+	return sb.String()
+}
+
+// HTML renders the report as a self-contained HTML page with inline SVG
+// charts.
+func (r *Report) HTML() string {
+	var sb strings.Builder
+	title := "Gas Town report: " + r.periodLabel()
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&sb, "<title>%s</title>\n", html.EscapeString(title))
+	sb.WriteString(`<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 860px; margin: 2em auto; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { padding: 4px 10px; border-bottom: 1px solid #ddd; }
+td.n, th.n { text-align: right; }
+tr.total td { font-weight: bold; }
+svg text { font-size: 11px; fill: #444; }
+</style>
+</head>
+<body>
+`)
+	fmt.Fprintf(&sb, "<h1>%s</h1>\n", html.EscapeString(title))
+
+	sb.WriteString("<h2>Summary</h2>\n<ul>\n")
+	for _, line := range r.summaryLines() {
+		fmt.Fprintf(&sb, "<li>%s</li>\n", markdownBold(html.EscapeString(line)))
+	}
+	sb.WriteString("</ul>\n")
+
+	if len(r.Days) > 0 {
+		labels := make([]string, len(r.Days))
+		merged := make([]float64, len(r.Days))
+		cost := make([]float64, len(r.Days))
+		for i, d := range r.Days {
+			labels[i] = d.Date.Format("Mon 2")
+			merged[i] = float64(d.Merged)
+			cost[i] = d.CostUSD
+		}
+		sb.WriteString("<h2>Merges per day</h2>\n")
+		sb.WriteString(barChartSVG(labels, merged, "#4a7ebb", func(v float64) string { return fmt.Sprintf("%.0f", v) }))
+		sb.WriteString("<h2>Cost per day</h2>\n")
+		sb.WriteString(barChartSVG(labels, cost, "#c0793a", formatUSD))
+
+		if r.hasDailyMetrics() {
+			sessions := make([]float64, len(r.Days))
+			queue := make([]float64, len(r.Days))
+			for i, d := range r.Days {
+				if d.SessionsAlive != nil {
+					sessions[i] = *d.SessionsAlive
+				}
+				if d.QueueDepth != nil {
+					queue[i] = *d.QueueDepth
+				}
+			}
+			sb.WriteString("<h2>Sessions alive (daily mean)</h2>\n")
+			sb.WriteString(barChartSVG(labels, sessions, "#5a9a5a", formatMetric))
+			sb.WriteString("<h2>Merge queue depth (daily mean)</h2>\n")
+			sb.WriteString(barChartSVG(labels, queue, "#8a6ab8", formatMetric))
+		}
+	}
+
+	if len(r.Rigs) > 0 {
+		sb.WriteString("<h2>By rig</h2>\n<table>\n")
+		sb.WriteString(`<tr><th>Rig</th><th class="n">Merged</th><th class="n">Failed</th><th class="n">Issues closed</th><th class="n">Cycle time (median)</th><th class="n">Cost</th><th class="n">Slings</th></tr>` + "\n")
+		for _, s := range append(r.Rigs, r.Total) {
+			class := ""
+			if s.Rig == r.Total.Rig {
+				class = ` class="total"`
+			}
+			fmt.Fprintf(&sb, `<tr%s><td>%s</td><td class="n">%d</td><td class="n">%d</td><td class="n">%d</td><td class="n">%s</td><td class="n">%s</td><td class="n">%d</td></tr>`+"\n",
+				class, html.EscapeString(s.Rig), s.Merged, s.MergeFailed, s.IssuesClosed, FormatDuration(s.CycleTimeMedian), formatUSD(s.CostUSD), s.Slings)
+		}
+		sb.WriteString("</table>\n")
+	}
+
+	if len(r.Trends) > 0 {
+		sb.WriteString("<h2>Trends</h2>\n<table>\n")
+		sb.WriteString(`<tr><th>Metric</th><th class="n">Min</th><th class="n">Mean</th><th class="n">Max</th><th class="n">Latest</th></tr>` + "\n")
+		for _, t := range r.Trends {
+			fmt.Fprintf(&sb, `<tr><td>%s</td><td class="n">%s</td><td class="n">%s</td><td class="n">%s</td><td class="n">%s</td></tr>`+"\n",
+				html.EscapeString(t.Name), formatMetric(t.Min), formatMetric(t.Mean), formatMetric(t.Max), formatMetric(t.Last))
+		}
+		sb.WriteString("</table>\n")
+	}
+
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+func (r *Report) periodLabel() string {
+	end := r.Period.End.Add(-time.Nanosecond) // End is exclusive
+	return fmt.Sprintf("%s – %s", r.Period.Start.Format("Jan 2"), end.Format("Jan 2, 2006"))
+}
+
+// summaryLines are the headline numbers, with **bold** markup.
+func (r *Report) summaryLines() []string {
+	t := r.Total
+	merges := fmt.Sprintf("**%d** MRs merged", t.Merged)
+	if rate := t.MergeSuccessRate(); rate >= 0 {
+		merges += fmt.Sprintf(" (%.0f%% of %d attempts succeeded)", rate*100, t.Merged+t.MergeFailed)
+	}
+	closed := fmt.Sprintf("**%d** issues closed", t.IssuesClosed)
+	if t.CycleTimeMedian > 0 {
+		closed += fmt.Sprintf(", median cycle time **%s** (mean %s)", FormatDuration(t.CycleTimeMedian), FormatDuration(t.CycleTimeMean))
+	}
+	return []string{
+		merges,
+		closed,
+		fmt.Sprintf("**%s** spent", formatUSD(t.CostUSD)),
+		fmt.Sprintf("%d slings, %d polecats spawned, %d completed, %d escalations, %d session deaths",
+			t.Slings, t.Spawns, t.Done, t.Escalations, t.SessionDeaths),
+	}
+}
+
+// hasDailyMetrics reports whether any day has metrics history samples.
+func (r *Report) hasDailyMetrics() bool {
+	for _, d := range r.Days {
+		if d.SessionsAlive != nil || d.QueueDepth != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Report) maxDailyMerged() int {
+	most := 0
+	for _, d := range r.Days {
+		most = max(most, d.Merged)
+	}
+	return most
+}
+
+// FormatDuration renders a cycle time compactly: 45m, 6h, 2.5d.
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "-"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%.0fh", d.Hours())
+	default:
+		return fmt.Sprintf("%.1fd", d.Hours()/24)
+	}
+}
+
+func formatUSD(v float64) string {
+	return fmt.Sprintf("$%.2f", v)
+}
+
+// formatMetric renders a metric value with at most one decimal.
+func formatMetric(v float64) string {
+	if v == float64(int64(v)) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// formatMean renders a daily mean, or "-" for a day without samples.
+func formatMean(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return formatMetric(*v)
+}
+
+// textBar is a small unicode bar for markdown tables.
+func textBar(n, most int) string {
+	const width = 20
+	if n <= 0 || most <= 0 {
+		return ""
+	}
+	return strings.Repeat("█", max(1, n*width/most))
+}
+
+// markdownBold converts **bold** markup in already-escaped text to <b>.
+func markdownBold(s string) string {
+	parts := strings.Split(s, "**")
+	for i := 1; i < len(parts)-1; i += 2 {
+		parts[i] = "<b>" + parts[i] + "</b>"
+	}
+	return strings.Join(parts, "")
+}
+
+// barChartSVG renders a vertical bar chart with a value above each bar and
+// a label below it.
+func barChartSVG(labels []string, values []float64, color string, format func(float64) string) string {
+	const (
+		barWidth = 48
+		gap      = 16
+		height   = 160
+		top      = 18 // room for value labels
+		bottom   = 20 // room for axis labels
+	)
+	maxVal := 0.0
+	for _, v := range values {
+		if v > maxVal {
+			maxVal = v
+		}
+	}
+	width := len(values)*(barWidth+gap) + gap
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" role="img">`+"\n", width, height+top+bottom)
+	for i, v := range values {
+		x := gap + i*(barWidth+gap)
+		h := 0
+		if maxVal > 0 {
+			h = int(v / maxVal * height)
+		}
+		y := top + height - h
+		cx := x + barWidth/2
+		fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", x, y, barWidth, h, color)
+		fmt.Fprintf(&sb, `<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n", cx, y-4, html.EscapeString(format(v)))
+		fmt.Fprintf(&sb, `<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n", cx, top+height+14, html.EscapeString(labels[i]))
+	}
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
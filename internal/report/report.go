@@ -0,0 +1,308 @@
+// Package report aggregates a town's activity over a period into a
+// summary suitable for a team update: events, costs, merge throughput,
+// and issue cycle time per rig.
+//
+// Collection is the caller's job (events log, cost ledger, beads); this
+// package only aggregates and renders, so it is easy to test.
+package report
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/metrics"
+)
+
+// TownRig is the pseudo-rig for activity not attributable to a rig
+// (mayor, deacon, town-level issues).
+const TownRig = "town"
+
+// Period is the reporting window [Start, End).
+type Period struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Contains reports whether t falls inside the period.
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
+
+// Days returns the number of whole or partial days in the period.
+func (p Period) Days() int {
+	n := int(p.End.Sub(p.Start).Hours()+23) / 24
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// CostSample is one cost ledger entry.
+type CostSample struct {
+	Rig string
+	USD float64
+	At  time.Time
+}
+
+// ClosedIssue is an issue closed during the period.
+type ClosedIssue struct {
+	Rig     string
+	ID      string
+	Created time.Time
+	Closed  time.Time
+}
+
+// Input is everything a report is built from.
+type Input struct {
+	Period Period
+	Rigs   []string // known rig names, used to attribute events by actor
+	Events []events.Event
+	Costs  []CostSample
+	Closed []ClosedIssue
+
+	// Metrics are samples from the town's metrics history (sessions
+	// alive, queue depth, patrol durations), if any were recorded.
+	Metrics []metrics.Sample
+}
+
+// RigStats is the activity of one rig (or the whole town) in the period.
+type RigStats struct {
+	Rig             string        `json:"rig"`
+	Slings          int           `json:"slings"`
+	Spawns          int           `json:"spawns"`
+	Done            int           `json:"done"`
+	Merged          int           `json:"merged"`
+	MergeFailed     int           `json:"merge_failed"`
+	SessionDeaths   int           `json:"session_deaths"`
+	Escalations     int           `json:"escalations"`
+	CostUSD         float64       `json:"cost_usd"`
+	IssuesClosed    int           `json:"issues_closed"`
+	CycleTimeMedian time.Duration `json:"cycle_time_median_ns"`
+	CycleTimeMean   time.Duration `json:"cycle_time_mean_ns"`
+}
+
+// MergeSuccessRate returns the fraction of finished merges that succeeded,
+// or -1 if nothing was merged or failed.
+func (s RigStats) MergeSuccessRate() float64 {
+	if s.Merged+s.MergeFailed == 0 {
+		return -1
+	}
+	return float64(s.Merged) / float64(s.Merged+s.MergeFailed)
+}
+
+// Day is one calendar day's merge and cost totals, and the day's mean
+// sessions alive and merge queue depth from the metrics history (nil when
+// no samples were recorded that day).
+type Day struct {
+	Date          time.Time `json:"date"`
+	Merged        int       `json:"merged"`
+	CostUSD       float64   `json:"cost_usd"`
+	SessionsAlive *float64  `json:"sessions_alive,omitempty"`
+	QueueDepth    *float64  `json:"queue_depth,omitempty"`
+}
+
+// Report is the aggregated activity for a period.
+type Report struct {
+	Period Period         `json:"period"`
+	Total  RigStats       `json:"total"`
+	Rigs   []RigStats     `json:"rigs"`
+	Days   []Day          `json:"days"`
+	Events map[string]int `json:"events"` // count by event type
+
+	// Trends summarizes each metric in the metrics history over the period.
+	Trends []metrics.Summary `json:"trends,omitempty"`
+}
+
+// Build aggregates in into a report. Rigs with no activity are omitted;
+// days are in the period's location.
+func Build(in Input) *Report {
+	r := &Report{
+		Period: in.Period,
+		Total:  RigStats{Rig: "total"},
+		Events: make(map[string]int),
+	}
+	loc := in.Period.Start.Location()
+	for d := startOfDay(in.Period.Start); d.Before(in.Period.End); d = d.AddDate(0, 0, 1) {
+		r.Days = append(r.Days, Day{Date: d})
+	}
+	dayIndex := func(t time.Time) int {
+		t = t.In(loc)
+		for i := range r.Days {
+			if !t.Before(r.Days[i].Date) && t.Before(r.Days[i].Date.AddDate(0, 0, 1)) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	byRig := make(map[string]*RigStats)
+	stats := func(rig string) *RigStats {
+		if rig == "" {
+			rig = TownRig
+		}
+		s, ok := byRig[rig]
+		if !ok {
+			s = &RigStats{Rig: rig}
+			byRig[rig] = s
+		}
+		return s
+	}
+
+	for _, e := range in.Events {
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || !in.Period.Contains(ts) {
+			continue
+		}
+		r.Events[e.Type]++
+		s := stats(EventRig(e, in.Rigs))
+		switch e.Type {
+		case events.TypeSling:
+			s.Slings++
+		case events.TypeSpawn:
+			s.Spawns++
+		case events.TypeDone:
+			s.Done++
+		case events.TypeMerged:
+			s.Merged++
+			if i := dayIndex(ts); i >= 0 {
+				r.Days[i].Merged++
+			}
+		case events.TypeMergeFailed:
+			s.MergeFailed++
+		case events.TypeSessionDeath:
+			s.SessionDeaths++
+		case events.TypeEscalationSent:
+			s.Escalations++
+		}
+	}
+
+	for _, c := range in.Costs {
+		if !in.Period.Contains(c.At) {
+			continue
+		}
+		stats(c.Rig).CostUSD += c.USD
+		if i := dayIndex(c.At); i >= 0 {
+			r.Days[i].CostUSD += c.USD
+		}
+	}
+
+	var inPeriod []metrics.Sample
+	daySums := make([]map[string][2]float64, len(r.Days)) // metric -> {sum, count}
+	for _, s := range in.Metrics {
+		if !in.Period.Contains(s.Time) {
+			continue
+		}
+		inPeriod = append(inPeriod, s)
+		i := dayIndex(s.Time)
+		if i < 0 {
+			continue
+		}
+		if daySums[i] == nil {
+			daySums[i] = make(map[string][2]float64)
+		}
+		for _, name := range []string{metrics.SessionsAlive, metrics.QueueDepth} {
+			if v, ok := s.Values[name]; ok {
+				acc := daySums[i][name]
+				daySums[i][name] = [2]float64{acc[0] + v, acc[1] + 1}
+			}
+		}
+	}
+	for i, sums := range daySums {
+		r.Days[i].SessionsAlive = dayMean(sums[metrics.SessionsAlive])
+		r.Days[i].QueueDepth = dayMean(sums[metrics.QueueDepth])
+	}
+	r.Trends = metrics.Summarize(inPeriod)
+
+	cycles := make(map[string][]time.Duration)
+	var allCycles []time.Duration
+	for _, ci := range in.Closed {
+		if !in.Period.Contains(ci.Closed) {
+			continue
+		}
+		s := stats(ci.Rig)
+		s.IssuesClosed++
+		if !ci.Created.IsZero() && ci.Closed.After(ci.Created) {
+			d := ci.Closed.Sub(ci.Created)
+			cycles[s.Rig] = append(cycles[s.Rig], d)
+			allCycles = append(allCycles, d)
+		}
+	}
+
+	for name, s := range byRig {
+		s.CycleTimeMedian, s.CycleTimeMean = durationStats(cycles[name])
+		r.Rigs = append(r.Rigs, *s)
+		addStats(&r.Total, s)
+	}
+	r.Total.CycleTimeMedian, r.Total.CycleTimeMean = durationStats(allCycles)
+	sort.Slice(r.Rigs, func(i, j int) bool {
+		// Town-level activity last; rigs alphabetically.
+		if (r.Rigs[i].Rig == TownRig) != (r.Rigs[j].Rig == TownRig) {
+			return r.Rigs[j].Rig == TownRig
+		}
+		return r.Rigs[i].Rig < r.Rigs[j].Rig
+	})
+	return r
+}
+
+// EventRig attributes an event to a rig: the payload's "rig" field, else
+// the rig an actor address like "greenplace/polecats/Toast" starts with.
+// Returns "" for town-level events.
+func EventRig(e events.Event, rigs []string) string {
+	if rig, ok := e.Payload["rig"].(string); ok && rig != "" {
+		return rig
+	}
+	first, _, _ := strings.Cut(e.Actor, "/")
+	for _, r := range rigs {
+		if r == first {
+			return r
+		}
+	}
+	return ""
+}
+
+func addStats(total, s *RigStats) {
+	total.Slings += s.Slings
+	total.Spawns += s.Spawns
+	total.Done += s.Done
+	total.Merged += s.Merged
+	total.MergeFailed += s.MergeFailed
+	total.SessionDeaths += s.SessionDeaths
+	total.Escalations += s.Escalations
+	total.CostUSD += s.CostUSD
+	total.IssuesClosed += s.IssuesClosed
+}
+
+// dayMean returns the mean of a {sum, count} pair, or nil with no samples.
+func dayMean(acc [2]float64) *float64 {
+	if acc[1] == 0 {
+		return nil
+	}
+	mean := acc[0] / acc[1]
+	return &mean
+}
+
+// durationStats returns the median and mean of ds (zero if empty).
+func durationStats(ds []time.Duration) (median, mean time.Duration) {
+	if len(ds) == 0 {
+		return 0, 0
+	}
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+	mid := len(sorted) / 2
+	median = sorted[mid]
+	if len(sorted)%2 == 0 {
+		median = (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return median, sum / time.Duration(len(sorted))
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,170 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/metrics"
+)
+
+func testPeriod() Period {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	return Period{Start: start, End: start.AddDate(0, 0, 7)}
+}
+
+func ev(ts time.Time, typ, actor string, payload map[string]interface{}) events.Event {
+	return events.Event{Timestamp: ts.Format(time.RFC3339), Type: typ, Actor: actor, Payload: payload}
+}
+
+func TestBuild(t *testing.T) {
+	p := testPeriod()
+	day := func(n int) time.Time { return p.Start.AddDate(0, 0, n).Add(12 * time.Hour) }
+
+	in := Input{
+		Period: p,
+		Rigs:   []string{"greenplace", "blueline"},
+		Events: []events.Event{
+			ev(day(0), events.TypeMerged, "greenplace/refinery", nil),
+			ev(day(0), events.TypeMerged, "greenplace/refinery", nil),
+			ev(day(1), events.TypeMergeFailed, "greenplace/refinery", nil),
+			ev(day(2), events.TypeMerged, "mayor", map[string]interface{}{"rig": "blueline"}),
+			ev(day(3), events.TypeSling, "mayor", nil),
+			ev(p.Start.Add(-time.Hour), events.TypeMerged, "greenplace/refinery", nil), // before period
+		},
+		Costs: []CostSample{
+			{Rig: "greenplace", USD: 2.50, At: day(0)},
+			{Rig: "", USD: 1.00, At: day(1)},
+		},
+		Closed: []ClosedIssue{
+			{Rig: "greenplace", ID: "gp-1", Created: day(0).Add(-2 * time.Hour), Closed: day(0)},
+			{Rig: "greenplace", ID: "gp-2", Created: day(0), Closed: day(0).Add(6 * time.Hour)},
+			{Rig: "greenplace", ID: "gp-3", Created: day(0), Closed: day(0).Add(10 * time.Hour)},
+		},
+	}
+	r := Build(in)
+
+	if r.Total.Merged != 3 || r.Total.MergeFailed != 1 {
+		t.Errorf("total merged/failed = %d/%d, want 3/1", r.Total.Merged, r.Total.MergeFailed)
+	}
+	if r.Total.CostUSD != 3.50 {
+		t.Errorf("total cost = %v, want 3.50", r.Total.CostUSD)
+	}
+	if len(r.Days) != 7 || r.Days[0].Merged != 2 || r.Days[2].Merged != 1 {
+		t.Errorf("daily merges = %+v", r.Days)
+	}
+
+	var names []string
+	for _, s := range r.Rigs {
+		names = append(names, s.Rig)
+	}
+	if got := strings.Join(names, ","); got != "blueline,greenplace,town" {
+		t.Errorf("rigs = %s, want blueline,greenplace,town (town last)", got)
+	}
+
+	gp := r.Rigs[1]
+	if gp.IssuesClosed != 3 || gp.CycleTimeMedian != 6*time.Hour {
+		t.Errorf("greenplace closed=%d median=%v, want 3 and 6h", gp.IssuesClosed, gp.CycleTimeMedian)
+	}
+	if gp.CycleTimeMean != 6*time.Hour {
+		t.Errorf("greenplace mean = %v, want 6h", gp.CycleTimeMean)
+	}
+	if r.Rigs[2].Slings != 1 {
+		t.Errorf("town slings = %d, want 1", r.Rigs[2].Slings)
+	}
+}
+
+func TestEventRig(t *testing.T) {
+	rigs := []string{"greenplace"}
+	tests := []struct {
+		e    events.Event
+		want string
+	}{
+		{events.Event{Actor: "greenplace/polecats/Toast"}, "greenplace"},
+		{events.Event{Actor: "mayor", Payload: map[string]interface{}{"rig": "blueline"}}, "blueline"},
+		{events.Event{Actor: "deacon"}, ""},
+		{events.Event{Actor: "unknown/witness"}, ""},
+	}
+	for _, tt := range tests {
+		if got := EventRig(tt.e, rigs); got != tt.want {
+			t.Errorf("EventRig(%+v) = %q, want %q", tt.e, got, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	p := testPeriod()
+	r := Build(Input{
+		Period: p,
+		Rigs:   []string{"greenplace"},
+		Events: []events.Event{ev(p.Start.Add(time.Hour), events.TypeMerged, "greenplace/refinery", nil)},
+		Costs:  []CostSample{{Rig: "greenplace", USD: 4.25, At: p.Start.Add(time.Hour)}},
+	})
+
+	md := r.Markdown()
+	for _, want := range []string{"# Gas Town report: Mar 2 – Mar 8, 2026", "**1** MRs merged", "| greenplace | 1 |", "$4.25"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	page := r.HTML()
+	for _, want := range []string{"<svg", "<rect", "<b>1</b> MRs merged", "greenplace"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("html missing %q", want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                "-",
+		45 * time.Minute: "45m",
+		6 * time.Hour:    "6h",
+		60 * time.Hour:   "2.5d",
+	}
+	for d, want := range tests {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestBuildTrends(t *testing.T) {
+	p := testPeriod()
+	day := func(n int, h int) time.Time { return p.Start.AddDate(0, 0, n).Add(time.Duration(h) * time.Hour) }
+
+	r := Build(Input{
+		Period: p,
+		Metrics: []metrics.Sample{
+			{Time: day(0, 1), Values: map[string]float64{metrics.SessionsAlive: 4, metrics.QueueDepth: 1}},
+			{Time: day(0, 2), Values: map[string]float64{metrics.SessionsAlive: 6, metrics.QueueDepth: 3}},
+			{Time: day(2, 1), Values: map[string]float64{metrics.SessionsAlive: 1}},
+			{Time: p.End.Add(time.Hour), Values: map[string]float64{metrics.SessionsAlive: 99}}, // after period
+		},
+	})
+
+	if d := r.Days[0]; d.SessionsAlive == nil || *d.SessionsAlive != 5 || d.QueueDepth == nil || *d.QueueDepth != 2 {
+		t.Errorf("day 0 = %+v, want sessions 5 and queue 2", d)
+	}
+	if r.Days[1].SessionsAlive != nil {
+		t.Errorf("day 1 sessions = %v, want nil (no samples)", *r.Days[1].SessionsAlive)
+	}
+	if len(r.Trends) != 2 || r.Trends[1].Name != metrics.SessionsAlive || r.Trends[1].Max != 6 {
+		t.Errorf("trends = %+v", r.Trends)
+	}
+
+	md := r.Markdown()
+	for _, want := range []string{"## Trends", "| sessions_alive | 1 | 3.7 | 6 | 1 |", "Sessions (avg)"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if !strings.Contains(r.HTML(), "Sessions alive (daily mean)") {
+		t.Error("HTML missing sessions chart")
+	}
+	if strings.Contains(Build(Input{Period: p}).Markdown(), "Trends") {
+		t.Error("report without metrics history shows a Trends section")
+	}
+}
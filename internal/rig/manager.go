@@ -225,6 +225,7 @@ type AddRigOptions struct {
 	BeadsPrefix   string // Beads issue prefix (defaults to derived from name)
 	LocalRepo     string // Optional local repo for reference clones
 	DefaultBranch string // Default branch (defaults to auto-detected from remote)
+	PartialClone  string // Optional partial clone mode for the shared repo: "blobless" or "treeless"
 }
 
 func resolveLocalRepo(path, gitURL string) (string, string) {
@@ -314,6 +315,15 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		opts.BeadsPrefix = deriveBeadsPrefix(opts.Name)
 	}
 
+	var cloneFilter string
+	if opts.PartialClone != "" {
+		filter, err := git.PartialCloneFilter(opts.PartialClone)
+		if err != nil {
+			return nil, err
+		}
+		cloneFilter = filter
+	}
+
 	localRepo, warn := resolveLocalRepo(opts.LocalRepo, opts.GitURL)
 	if warn != "" {
 		fmt.Printf("  Warning: %s\n", warn)
@@ -355,7 +365,13 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	// Mayor remains a separate clone (doesn't need branch visibility).
 	fmt.Printf("  Cloning repository (this may take a moment)...\n")
 	bareRepoPath := filepath.Join(rigPath, ".repo.git")
-	if localRepo != "" {
+	if cloneFilter != "" {
+		// Partial clone: full history, file contents (and, treeless,
+		// directory trees) fetched from origin as worktrees need them.
+		if err := m.git.CloneBarePartial(opts.GitURL, bareRepoPath, localRepo, cloneFilter); err != nil {
+			return nil, wrapCloneError(err, opts.GitURL)
+		}
+	} else if localRepo != "" {
 		if err := m.git.CloneBareWithReference(opts.GitURL, bareRepoPath, localRepo); err != nil {
 			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
 			_ = os.RemoveAll(bareRepoPath)
@@ -368,7 +384,11 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 			return nil, wrapCloneError(err, opts.GitURL)
 		}
 	}
-	fmt.Printf("   ✓ Created shared bare repo\n")
+	if cloneFilter != "" {
+		fmt.Printf("   ✓ Created shared bare repo (%s partial clone)\n", opts.PartialClone)
+	} else {
+		fmt.Printf("   ✓ Created shared bare repo\n")
+	}
 	bareGit := git.NewGitWithDir(bareRepoPath, "")
 
 	// Detect empty repos (no commits) early with a clear diagnostic.
@@ -721,6 +741,13 @@ Use crew for your own workspace. Polecats are for batch work dispatch.
 	if err := os.MkdirAll(rigSettingsPath, 0755); err != nil {
 		return nil, fmt.Errorf("creating settings dir: %w", err)
 	}
+	if opts.PartialClone != "" {
+		settings := config.NewRigSettings()
+		settings.PartialClone = &config.PartialCloneConfig{Filter: opts.PartialClone}
+		if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+			return nil, fmt.Errorf("recording partial clone settings: %w", err)
+		}
+	}
 
 	// Create rig-level agent beads (witness, refinery) in rig beads.
 	// Town-level agents (mayor, deacon) are created by gt install in town beads.
@@ -0,0 +1,246 @@
+package rig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// A rig template is a repository that standardizes how rigs are set up.
+// Every entry is optional, but a template must contain at least one:
+//
+//	settings/config.json   merged into <rig>/settings/config.json
+//	merge_queue.json       becomes the merge_queue section of <rig>/config.json
+//	                       (quality gates, test commands)
+//	roles/*.toml           role overrides (prompt packs), copied to <rig>/roles/
+//	scripts/               CI gate scripts, copied to <rig>/scripts/
+//	plugins/               rig-level plugins, copied to <rig>/plugins/
+//	hooks/<role>.json      hooks overrides for <rig>/<role> (see gt hooks)
+//
+// Text files have {{rig}}, {{prefix}}, {{rig_path}} and {{town}} replaced
+// with the new rig's values.
+const (
+	templateSettingsFile   = "settings/config.json"
+	templateMergeQueueFile = "merge_queue.json"
+	templateHooksDir       = "hooks"
+)
+
+// templateCopyDirs are the template directories copied into the rig as-is.
+var templateCopyDirs = []string{"roles", "scripts", "plugins"}
+
+// Template is a rig template checked out at Dir.
+type Template struct {
+	Dir string
+}
+
+// TemplateVars are the values substituted into template files.
+type TemplateVars struct {
+	Rig      string
+	Prefix   string
+	RigPath  string
+	TownRoot string
+}
+
+// Expand replaces the template variables in s.
+func (v TemplateVars) Expand(s string) string {
+	return strings.NewReplacer(
+		"{{rig}}", v.Rig,
+		"{{prefix}}", v.Prefix,
+		"{{rig_path}}", v.RigPath,
+		"{{town}}", v.TownRoot,
+	).Replace(s)
+}
+
+// LoadTemplate checks that dir is a rig template: it has at least one
+// template entry and its JSON files parse.
+func LoadTemplate(dir string) (*Template, error) {
+	found := false
+	for _, name := range append([]string{templateSettingsFile, templateMergeQueueFile, templateHooksDir}, templateCopyDirs...) {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%s is not a rig template: expected settings/config.json, merge_queue.json, roles/, scripts/, plugins/ or hooks/", dir)
+	}
+
+	t := &Template{Dir: dir}
+	jsonFiles := []string{templateSettingsFile, templateMergeQueueFile}
+	hooks, err := t.hookFiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hooks {
+		jsonFiles = append(jsonFiles, filepath.Join(templateHooksDir, h))
+	}
+	for _, name := range jsonFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // G304: path is within the template checkout
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !json.Valid(data) {
+			return nil, fmt.Errorf("template %s: invalid JSON", name)
+		}
+	}
+	return t, nil
+}
+
+// Apply installs the template into the rig at vars.RigPath and returns
+// the rig-relative paths it wrote. Hooks overrides are not installed here;
+// see HookOverrides.
+func (t *Template) Apply(vars TemplateVars) ([]string, error) {
+	var applied []string
+
+	if data, err := t.render(templateSettingsFile, vars); err != nil {
+		return applied, err
+	} else if data != nil {
+		path := config.RigSettingsPath(vars.RigPath)
+		if err := mergeJSONObject(path, data); err != nil {
+			return applied, fmt.Errorf("applying %s: %w", templateSettingsFile, err)
+		}
+		if _, err := config.LoadRigSettings(path); err != nil {
+			return applied, fmt.Errorf("template settings are invalid: %w", err)
+		}
+		applied = append(applied, templateSettingsFile)
+	}
+
+	if data, err := t.render(templateMergeQueueFile, vars); err != nil {
+		return applied, err
+	} else if data != nil {
+		section, _ := json.Marshal(map[string]json.RawMessage{"merge_queue": data})
+		if err := mergeJSONObject(filepath.Join(vars.RigPath, "config.json"), section); err != nil {
+			return applied, fmt.Errorf("applying %s: %w", templateMergeQueueFile, err)
+		}
+		applied = append(applied, "config.json (merge_queue)")
+	}
+
+	for _, dir := range templateCopyDirs {
+		src := filepath.Join(t.Dir, dir)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(t.Dir, path)
+			if err != nil {
+				return err
+			}
+			data, err := t.render(rel, vars)
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			dst := filepath.Join(vars.RigPath, rel)
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(dst, data, info.Mode().Perm()); err != nil {
+				return err
+			}
+			applied = append(applied, rel)
+			return nil
+		})
+		if err != nil {
+			return applied, fmt.Errorf("copying %s/: %w", dir, err)
+		}
+	}
+	return applied, nil
+}
+
+// HookOverrides returns the rendered hooks overrides in the template,
+// keyed by role (the file name without .json).
+func (t *Template) HookOverrides(vars TemplateVars) (map[string][]byte, error) {
+	files, err := t.hookFiles()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]byte, len(files))
+	for _, name := range files {
+		data, err := t.render(filepath.Join(templateHooksDir, name), vars)
+		if err != nil {
+			return nil, err
+		}
+		out[strings.TrimSuffix(name, ".json")] = data
+	}
+	return out, nil
+}
+
+func (t *Template) hookFiles() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(t.Dir, templateHooksDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			files = append(files, e.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// render reads a template file with variables expanded, or returns nil
+// if it doesn't exist. Binary files (containing NUL) are not expanded.
+func (t *Template) render(name string, vars TemplateVars) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(t.Dir, name)) //nolint:gosec // G304: path is within the template checkout
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return data, nil
+	}
+	return []byte(vars.Expand(string(data))), nil
+}
+
+// mergeJSONObject sets the top-level keys of overlay in the JSON object
+// file at path, keeping its other keys. A missing file is created.
+func mergeJSONObject(path string, overlay []byte) error {
+	merged := make(map[string]json.RawMessage)
+	if data, err := os.ReadFile(path); err == nil { //nolint:gosec // G304: path is within the rig
+		if err := json.Unmarshal(data, &merged); err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(overlay, &keys); err != nil {
+		return fmt.Errorf("template must be a JSON object: %w", err)
+	}
+	for k, v := range keys {
+		merged[k] = v
+	}
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644) //nolint:gosec // G306: rig config is not secret
+}
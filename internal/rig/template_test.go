@@ -0,0 +1,114 @@
+package rig
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplateFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	if _, err := LoadTemplate(t.TempDir()); err == nil || !strings.Contains(err.Error(), "not a rig template") {
+		t.Errorf("empty dir: err = %v, want not a rig template", err)
+	}
+
+	bad := t.TempDir()
+	writeTemplateFiles(t, bad, map[string]string{"merge_queue.json": "{not json"})
+	if _, err := LoadTemplate(bad); err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+		t.Errorf("bad JSON: err = %v", err)
+	}
+
+	badHook := t.TempDir()
+	writeTemplateFiles(t, badHook, map[string]string{"hooks/crew.json": "["})
+	if _, err := LoadTemplate(badHook); err == nil {
+		t.Error("bad hooks JSON: expected error")
+	}
+}
+
+func TestTemplateApply(t *testing.T) {
+	tmplDir := t.TempDir()
+	writeTemplateFiles(t, tmplDir, map[string]string{
+		"settings/config.json": `{"type":"rig-settings","version":1,"theme":{"name":"forest"}}`,
+		"merge_queue.json":     `{"enabled":true,"gates":{"lint":{"cmd":"{{rig_path}}/scripts/lint.sh"}}}`,
+		"roles/polecat.toml":   "# prompt pack for {{rig}} ({{prefix}})\n",
+		"scripts/lint.sh":      "#!/bin/sh\necho linting {{rig}}\n",
+		"hooks/crew.json":      `{"SessionStart":[]}`,
+		"README.md":            "not copied",
+	})
+	tmpl, err := LoadTemplate(tmplDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rigPath := filepath.Join(t.TempDir(), "api")
+	writeTemplateFiles(t, rigPath, map[string]string{
+		"config.json":          `{"type":"rig","name":"api","beads":{"prefix":"ap"}}`,
+		"settings/config.json": `{"type":"rig-settings","version":1,"partial_clone":{"filter":"blobless"}}`,
+	})
+	vars := TemplateVars{Rig: "api", Prefix: "ap", RigPath: rigPath, TownRoot: "/town"}
+	applied, err := tmpl.Apply(vars)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(applied) != 4 {
+		t.Errorf("applied = %v, want settings, merge_queue, roles and scripts", applied)
+	}
+
+	var settings map[string]json.RawMessage
+	data, _ := os.ReadFile(filepath.Join(rigPath, "settings", "config.json"))
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings["theme"] == nil || settings["partial_clone"] == nil {
+		t.Errorf("settings = %s, want template theme merged with existing partial_clone", data)
+	}
+
+	data, _ = os.ReadFile(filepath.Join(rigPath, "config.json"))
+	if !strings.Contains(string(data), rigPath+"/scripts/lint.sh") || !strings.Contains(string(data), `"prefix": "ap"`) {
+		t.Errorf("config.json = %s, want expanded merge_queue alongside existing keys", data)
+	}
+
+	data, _ = os.ReadFile(filepath.Join(rigPath, "roles", "polecat.toml"))
+	if string(data) != "# prompt pack for api (ap)\n" {
+		t.Errorf("roles/polecat.toml = %q", data)
+	}
+	if info, err := os.Stat(filepath.Join(rigPath, "scripts", "lint.sh")); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("scripts/lint.sh should be copied executable: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(rigPath, "README.md")); !os.IsNotExist(err) {
+		t.Error("README.md should not be copied")
+	}
+
+	overrides, err := tmpl.HookOverrides(vars)
+	if err != nil || len(overrides) != 1 || overrides["crew"] == nil {
+		t.Errorf("HookOverrides() = %v, %v", overrides, err)
+	}
+}
+
+func TestTemplateApplyInvalidSettings(t *testing.T) {
+	tmplDir := t.TempDir()
+	writeTemplateFiles(t, tmplDir, map[string]string{
+		"settings/config.json": `{"type":"rig-settings","version":1,"session_template":{"hooks":{"pane-died":"x"}}}`,
+	})
+	tmpl, err := LoadTemplate(tmplDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmpl.Apply(TemplateVars{Rig: "api", RigPath: t.TempDir()}); err == nil || !strings.Contains(err.Error(), "invalid") {
+		t.Errorf("Apply() error = %v, want invalid settings", err)
+	}
+}
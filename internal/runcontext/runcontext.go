@@ -0,0 +1,206 @@
+// Package runcontext writes a per-dispatch context file into an agent's
+// worktree: the issue, the constraints the work runs under, budgets, and a
+// gt command cheatsheet. The startup prompt points at it and gt prime
+// mentions it again after a restart or compaction, so what an agent was
+// dispatched with is inspectable on disk instead of living only in
+// environment variables and the first nudge.
+//
+// The context is written twice: run-context.json for tools and
+// run-context.md for the agent. Both live under .runtime/, which every
+// Gas Town worktree gitignores, and are wiped when the worktree is reset
+// for its next dispatch.
+package runcontext
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/cli"
+)
+
+const (
+	// Dir is the worktree-relative directory holding the context files.
+	Dir = ".runtime"
+
+	// JSONFile is the machine-readable context file name.
+	JSONFile = "run-context.json"
+
+	// MarkdownFile is the agent-readable context file name.
+	MarkdownFile = "run-context.md"
+)
+
+// RunContext is what an agent was dispatched with.
+type RunContext struct {
+	WrittenAt time.Time `json:"written_at"`
+
+	Rig     string `json:"rig"`
+	Agent   string `json:"agent"` // e.g. "polecat Toast"
+	Session string `json:"session,omitempty"`
+	Runtime string `json:"runtime,omitempty"` // agent runtime, e.g. "claude"
+
+	Branch     string `json:"branch,omitempty"`
+	BaseBranch string `json:"base_branch,omitempty"`
+
+	Issue *Issue `json:"issue,omitempty"`
+
+	// Formula and Args come from gt sling (--formula / --args).
+	Formula      string `json:"formula,omitempty"`
+	Molecule     string `json:"molecule,omitempty"`
+	Args         string `json:"args,omitempty"`
+	DispatchedBy string `json:"dispatched_by,omitempty"`
+
+	Constraints []string  `json:"constraints,omitempty"`
+	Budgets     []Budget  `json:"budgets,omitempty"`
+	Commands    []Command `json:"commands,omitempty"`
+}
+
+// Issue is the dispatched issue.
+type Issue struct {
+	ID                 string `json:"id"`
+	Title              string `json:"title"`
+	Type               string `json:"type,omitempty"`
+	Priority           int    `json:"priority"`
+	Description        string `json:"description,omitempty"`
+	AcceptanceCriteria string `json:"acceptance_criteria,omitempty"`
+}
+
+// Budget is a spend limit the work runs under.
+type Budget struct {
+	Name     string  `json:"name"` // e.g. "daily", "weekly"
+	LimitUSD float64 `json:"limit_usd"`
+}
+
+// Command is one cheatsheet entry.
+type Command struct {
+	Command string `json:"command"`
+	Use     string `json:"use"`
+}
+
+// Path returns the JSON context path for a worktree.
+func Path(workDir string) string {
+	return filepath.Join(workDir, Dir, JSONFile)
+}
+
+// MarkdownPath returns the Markdown context path for a worktree.
+func MarkdownPath(workDir string) string {
+	return filepath.Join(workDir, Dir, MarkdownFile)
+}
+
+// RelMarkdownPath is the Markdown context path relative to the worktree,
+// for prompts.
+func RelMarkdownPath() string {
+	return Dir + "/" + MarkdownFile
+}
+
+// PolecatCommands returns the cheatsheet for a polecat working an issue.
+func PolecatCommands(issueID string) []Command {
+	gt := cli.Name()
+	return []Command{
+		{gt + " hook", "Show the work on your hook"},
+		{"bd show " + issueID, "Re-read the issue"},
+		{gt + " notes append " + issueID + " \"<progress>\"", "Record progress your supervisors can see"},
+		{gt + " checkpoint write", "Save a recovery checkpoint before risky steps"},
+		{gt + " escalate \"<problem>\"", "Ask for help when blocked"},
+		{gt + " done", "Submit your branch to the merge queue and finish"},
+	}
+}
+
+// Write saves the context to workDir as JSON and Markdown.
+func Write(workDir string, rc *RunContext) error {
+	if rc.WrittenAt.IsZero() {
+		rc.WrittenAt = time.Now()
+	}
+	if err := os.MkdirAll(filepath.Join(workDir, Dir), 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", Dir, err)
+	}
+	data, err := json.MarshalIndent(rc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling run context: %w", err)
+	}
+	if err := os.WriteFile(Path(workDir), append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: not secret
+		return fmt.Errorf("writing run context: %w", err)
+	}
+	if err := os.WriteFile(MarkdownPath(workDir), []byte(Render(rc)), 0644); err != nil { //nolint:gosec // G306: not secret
+		return fmt.Errorf("writing run context: %w", err)
+	}
+	return nil
+}
+
+// Read loads the context from workDir. Returns nil, nil if there is none.
+func Read(workDir string) (*RunContext, error) {
+	data, err := os.ReadFile(Path(workDir)) //nolint:gosec // G304: path is constructed from trusted workDir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading run context: %w", err)
+	}
+	var rc RunContext
+	if err := json.Unmarshal(data, &rc); err != nil {
+		return nil, fmt.Errorf("parsing run context: %w", err)
+	}
+	return &rc, nil
+}
+
+// Render formats the context as Markdown for the agent.
+func Render(rc *RunContext) string {
+	var b strings.Builder
+	b.WriteString("# Run context\n\n")
+	fmt.Fprintf(&b, "You are %s in rig %s. Written %s; re-read this file after a restart or compaction.\n\n",
+		rc.Agent, rc.Rig, rc.WrittenAt.Format(time.RFC3339))
+
+	b.WriteString("## Dispatch\n\n")
+	writeField(&b, "Session", rc.Session)
+	writeField(&b, "Runtime", rc.Runtime)
+	writeField(&b, "Branch", rc.Branch)
+	writeField(&b, "Base branch", rc.BaseBranch)
+	writeField(&b, "Dispatched by", rc.DispatchedBy)
+	writeField(&b, "Formula", rc.Formula)
+	writeField(&b, "Molecule", rc.Molecule)
+	writeField(&b, "Args", rc.Args)
+
+	if is := rc.Issue; is != nil {
+		fmt.Fprintf(&b, "\n## Issue %s: %s\n\n", is.ID, is.Title)
+		if is.Type != "" {
+			fmt.Fprintf(&b, "Type: %s, priority P%d\n\n", is.Type, is.Priority)
+		}
+		if d := strings.TrimSpace(is.Description); d != "" {
+			b.WriteString(d + "\n")
+		}
+		if ac := strings.TrimSpace(is.AcceptanceCriteria); ac != "" {
+			b.WriteString("\n### Acceptance criteria\n\n" + ac + "\n")
+		}
+	}
+
+	if len(rc.Constraints) > 0 {
+		b.WriteString("\n## Constraints\n\n")
+		for _, c := range rc.Constraints {
+			fmt.Fprintf(&b, "- %s\n", c)
+		}
+	}
+
+	if len(rc.Budgets) > 0 {
+		b.WriteString("\n## Budgets\n\n")
+		for _, bg := range rc.Budgets {
+			fmt.Fprintf(&b, "- %s: $%.2f for the rig\n", bg.Name, bg.LimitUSD)
+		}
+	}
+
+	if len(rc.Commands) > 0 {
+		b.WriteString("\n## Commands\n\n")
+		for _, c := range rc.Commands {
+			fmt.Fprintf(&b, "- `%s` — %s\n", c.Command, c.Use)
+		}
+	}
+	return b.String()
+}
+
+func writeField(b *strings.Builder, name, value string) {
+	if value != "" {
+		fmt.Fprintf(b, "- %s: %s\n", name, value)
+	}
+}
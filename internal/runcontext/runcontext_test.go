@@ -0,0 +1,71 @@
+package runcontext
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if rc, err := Read(dir); err != nil || rc != nil {
+		t.Fatalf("Read(empty) = %v, %v; want nil, nil", rc, err)
+	}
+
+	want := &RunContext{
+		WrittenAt:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Rig:        "gastown",
+		Agent:      "polecat Toast",
+		Branch:     "polecat/Toast/gt-abc",
+		BaseBranch: "main",
+		Issue:      &Issue{ID: "gt-abc", Title: "Fix the thing", Type: "bug", Priority: 1},
+		Budgets:    []Budget{{Name: "daily", LimitUSD: 25}},
+		Commands:   PolecatCommands("gt-abc"),
+	}
+	if err := Write(dir, want); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got, err := Read(dir)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	rc := &RunContext{
+		Rig:    "gastown",
+		Agent:  "polecat Toast",
+		Branch: "polecat/Toast/gt-abc",
+		Issue: &Issue{
+			ID:                 "gt-abc",
+			Title:              "Fix the thing",
+			Description:        "It is broken.",
+			AcceptanceCriteria: "- It works",
+		},
+		Constraints: []string{"Work only in this worktree."},
+		Budgets:     []Budget{{Name: "weekly", LimitUSD: 100}},
+		Commands:    []Command{{Command: "gt done", Use: "Finish"}},
+	}
+	got := Render(rc)
+	for _, want := range []string{
+		"You are polecat Toast in rig gastown",
+		"- Branch: polecat/Toast/gt-abc",
+		"## Issue gt-abc: Fix the thing",
+		"It is broken.",
+		"### Acceptance criteria",
+		"## Constraints\n\n- Work only in this worktree.",
+		"- weekly: $100.00",
+		"- `gt done` — Finish",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Formula") {
+		t.Errorf("Render() shows empty fields:\n%s", got)
+	}
+}
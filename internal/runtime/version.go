@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/failure"
+)
+
+// agentVersions caches installed agent versions by command for the life of
+// the process; `<agent> --version` can take seconds.
+var agentVersions sync.Map
+
+// AgentVersionError reports an installed agent CLI that doesn't satisfy the
+// rig's pinned agent_version.
+type AgentVersionError struct {
+	Command string
+	Version string // Installed version ("" if it couldn't be determined)
+	Pin     string
+	Err     error // Why the version couldn't be checked, if it couldn't
+}
+
+func (e *AgentVersionError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("cannot verify %s against pinned agent_version %q: %v", e.Command, e.Pin, e.Err)
+	}
+	return fmt.Sprintf("%s %s does not satisfy pinned agent_version %q", e.Command, e.Version, e.Pin)
+}
+
+func (e *AgentVersionError) Unwrap() error { return e.Err }
+
+// FailureClass classifies a version mismatch for dispatch: the environment
+// needs fixing (install the pinned version) before a retry can succeed.
+func (e *AgentVersionError) FailureClass() failure.Class {
+	return failure.ClassEnvironment
+}
+
+// InstalledAgentVersion returns the version of rc's agent CLI.
+func InstalledAgentVersion(rc *config.RuntimeConfig) (string, error) {
+	command := agentCommand(rc)
+	if v, ok := agentVersions.Load(command); ok {
+		return v.(string), nil
+	}
+	version, err := deps.AgentVersion(command)
+	if err != nil {
+		return "", err
+	}
+	agentVersions.Store(command, version)
+	return version, nil
+}
+
+// VerifyAgentVersion checks rc's agent CLI against a rig's pinned version
+// constraint (RigSettings.AgentVersion). It returns the installed version,
+// which is recorded on the dispatch, and an *AgentVersionError if the pin
+// isn't satisfied. With no pin, the version is returned when it can be
+// determined and a failure to determine it is not an error.
+func VerifyAgentVersion(rc *config.RuntimeConfig, pin string) (string, error) {
+	command := agentCommand(rc)
+	version, err := InstalledAgentVersion(rc)
+	if pin == "" {
+		return version, nil
+	}
+	if err != nil {
+		return "", &AgentVersionError{Command: command, Pin: pin, Err: err}
+	}
+	ok, err := deps.SatisfiesConstraint(version, pin)
+	if err != nil {
+		return version, &AgentVersionError{Command: command, Version: version, Pin: pin, Err: err}
+	}
+	if !ok {
+		return version, &AgentVersionError{Command: command, Version: version, Pin: pin}
+	}
+	return version, nil
+}
+
+func agentCommand(rc *config.RuntimeConfig) string {
+	if rc == nil || rc.Command == "" {
+		return "claude"
+	}
+	return rc.Command
+}
@@ -0,0 +1,71 @@
+package runtime
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/failure"
+)
+
+func fakeAgent(t *testing.T, output string) *config.RuntimeConfig {
+	t.Helper()
+	if goruntime.GOOS == "windows" {
+		t.Skip("fake agent is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "fake-agent")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho '"+output+"'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return &config.RuntimeConfig{Command: path}
+}
+
+func TestVerifyAgentVersion(t *testing.T) {
+	rc := fakeAgent(t, "2.0.14 (Claude Code)")
+
+	tests := []struct {
+		pin     string
+		wantErr bool
+	}{
+		{"", false},
+		{">= 2.0.14", false},
+		{">= 2.0, < 3", false},
+		{"2.0", false},
+		{">= 2.1", true},
+		{"< 2", true},
+	}
+	for _, tt := range tests {
+		version, err := VerifyAgentVersion(rc, tt.pin)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("VerifyAgentVersion(pin %q) error = %v, wantErr %v", tt.pin, err, tt.wantErr)
+		}
+		if version != "2.0.14" {
+			t.Errorf("VerifyAgentVersion(pin %q) version = %q, want 2.0.14", tt.pin, version)
+		}
+	}
+}
+
+func TestVerifyAgentVersion_MismatchIsEnvironmentFailure(t *testing.T) {
+	rc := fakeAgent(t, "1.9.0")
+	_, err := VerifyAgentVersion(rc, ">= 2.0")
+	var verr *AgentVersionError
+	if !errors.As(err, &verr) || verr.Version != "1.9.0" {
+		t.Fatalf("VerifyAgentVersion = %v, want *AgentVersionError for 1.9.0", err)
+	}
+	if r := failure.ClassifyError(err); r.Class != failure.ClassEnvironment {
+		t.Errorf("ClassifyError class = %q, want %q", r.Class, failure.ClassEnvironment)
+	}
+}
+
+func TestVerifyAgentVersion_MissingBinary(t *testing.T) {
+	rc := &config.RuntimeConfig{Command: filepath.Join(t.TempDir(), "no-such-agent")}
+	if version, err := VerifyAgentVersion(rc, ""); err != nil || version != "" {
+		t.Errorf("unpinned: got (%q, %v), want no version and no error", version, err)
+	}
+	if _, err := VerifyAgentVersion(rc, ">= 2.0"); err == nil {
+		t.Error("pinned: want error for a missing binary")
+	}
+}
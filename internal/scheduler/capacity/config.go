@@ -28,6 +28,10 @@ type SchedulerConfig struct {
 	// SpawnDelay is the delay between spawns to prevent Dolt lock contention.
 	// Default: "0s".
 	SpawnDelay string `json:"spawn_delay,omitempty"`
+
+	// Throttle configures quota-aware throttling of dispatch to agent
+	// providers that are hitting rate limits. nil = defaults (enabled).
+	Throttle *ThrottleConfig `json:"throttle,omitempty"`
 }
 
 // DefaultSchedulerConfig returns a SchedulerConfig with sensible defaults.
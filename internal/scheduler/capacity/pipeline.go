@@ -34,6 +34,7 @@ type SlingContextFields struct {
 	Mode             string `json:"mode,omitempty"`
 	DispatchFailures int    `json:"dispatch_failures,omitempty"`
 	LastFailure      string `json:"last_failure,omitempty"`
+	FailureClass     string `json:"failure_class,omitempty"` // failure.Class of LastFailure
 }
 
 // LabelSlingContext is the label used to identify sling context beads.
@@ -0,0 +1,218 @@
+package capacity
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ThrottleConfig configures quota-aware dispatch throttling. Provider
+// pressure (rate-limited and retrying sessions per agent provider) is
+// measured by the daemon's provider_pressure patrol; the scheduler holds or
+// slows dispatch to providers under pressure instead of spawning polecats
+// that will stall on rate limits.
+type ThrottleConfig struct {
+	// Disabled turns throttling off. Default: enabled.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// PauseRatio is the fraction of a provider's sessions that must be
+	// rate-limited to hold all new dispatches to it. Default: 0.5.
+	PauseRatio float64 `json:"pause_ratio,omitempty"`
+
+	// Stagger is the delay between dispatches (and broadcast nudges) while
+	// a provider is under pressure. Default: "30s".
+	Stagger string `json:"stagger,omitempty"`
+
+	// MaxAge is how long a pressure reading is trusted. Older readings
+	// (e.g., the daemon is down) are ignored. Default: "10m".
+	MaxAge string `json:"max_age,omitempty"`
+}
+
+// Throttle defaults.
+const (
+	DefaultThrottlePauseRatio = 0.5
+	DefaultThrottleStagger    = 30 * time.Second
+	DefaultThrottleMaxAge     = 10 * time.Minute
+)
+
+// GetPauseRatio returns PauseRatio or its default.
+func (c *ThrottleConfig) GetPauseRatio() float64 {
+	if c == nil || c.PauseRatio <= 0 {
+		return DefaultThrottlePauseRatio
+	}
+	return c.PauseRatio
+}
+
+// GetStagger returns Stagger as a duration, or its default.
+func (c *ThrottleConfig) GetStagger() time.Duration {
+	if c == nil {
+		return DefaultThrottleStagger
+	}
+	return ParseDurationOrDefault(c.Stagger, DefaultThrottleStagger)
+}
+
+// GetMaxAge returns MaxAge as a duration, or its default.
+func (c *ThrottleConfig) GetMaxAge() time.Duration {
+	if c == nil {
+		return DefaultThrottleMaxAge
+	}
+	return ParseDurationOrDefault(c.MaxAge, DefaultThrottleMaxAge)
+}
+
+// ProviderPressure is the rate-limit pressure observed on one agent
+// provider (e.g., "claude", "codex") across the town's sessions.
+type ProviderPressure struct {
+	Provider string `json:"provider"`
+	Sessions int    `json:"sessions"` // sessions running this provider
+
+	// Limited sessions are stuck on a rate limit.
+	Limited         int      `json:"limited"`
+	LimitedSessions []string `json:"limited_sessions,omitempty"`
+
+	// Retrying sessions are hitting 429s but still retrying on their own:
+	// the limit is approaching.
+	Retrying         int      `json:"retrying"`
+	RetryingSessions []string `json:"retrying_sessions,omitempty"`
+
+	ResetsAt   string    `json:"resets_at,omitempty"` // earliest reported reset, if any
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+// LimitedRatio returns the fraction of sessions that are rate-limited.
+func (p ProviderPressure) LimitedRatio() float64 {
+	if p.Sessions <= 0 {
+		return 0
+	}
+	return float64(p.Limited) / float64(p.Sessions)
+}
+
+// ThrottleLevel is how hard dispatch to a provider is throttled.
+type ThrottleLevel int
+
+const (
+	// ThrottleNone dispatches normally.
+	ThrottleNone ThrottleLevel = iota
+	// ThrottleSlow dispatches one bead per cycle, staggered.
+	ThrottleSlow
+	// ThrottlePause holds all new dispatches.
+	ThrottlePause
+)
+
+func (l ThrottleLevel) String() string {
+	switch l {
+	case ThrottleSlow:
+		return "slow"
+	case ThrottlePause:
+		return "pause"
+	}
+	return "none"
+}
+
+// Level returns the throttle level for p at now.
+func (c *ThrottleConfig) Level(p ProviderPressure, now time.Time) ThrottleLevel {
+	if c != nil && c.Disabled {
+		return ThrottleNone
+	}
+	if p.ObservedAt.IsZero() || now.Sub(p.ObservedAt) > c.GetMaxAge() {
+		return ThrottleNone
+	}
+	if p.Limited > 0 && p.LimitedRatio() >= c.GetPauseRatio() {
+		return ThrottlePause
+	}
+	if p.Limited > 0 || p.Retrying > 0 {
+		return ThrottleSlow
+	}
+	return ThrottleNone
+}
+
+// ThrottleReport summarizes what ThrottlePending held back.
+type ThrottleReport struct {
+	Held    int                      // beads held this cycle
+	Stagger bool                     // a slowed provider is being dispatched to
+	Levels  map[string]ThrottleLevel // provider -> level, for throttled providers
+}
+
+// ThrottlePending filters pending beads by their provider's pressure:
+// beads for paused providers are held, and at most one bead per slowed
+// provider is kept. providerOf maps a bead to the provider it would run on.
+// Order is preserved.
+func ThrottlePending(cfg *ThrottleConfig, pending []PendingBead, providerOf func(PendingBead) string,
+	pressure map[string]ProviderPressure, now time.Time) ([]PendingBead, ThrottleReport) {
+	report := ThrottleReport{Levels: make(map[string]ThrottleLevel)}
+	if len(pressure) == 0 || (cfg != nil && cfg.Disabled) {
+		return pending, report
+	}
+
+	slowKept := make(map[string]bool)
+	kept := make([]PendingBead, 0, len(pending))
+	for _, b := range pending {
+		provider := providerOf(b)
+		level := cfg.Level(pressure[provider], now)
+		if level != ThrottleNone {
+			report.Levels[provider] = level
+		}
+		switch level {
+		case ThrottlePause:
+			report.Held++
+			continue
+		case ThrottleSlow:
+			if slowKept[provider] {
+				report.Held++
+				continue
+			}
+			slowKept[provider] = true
+			report.Stagger = true
+		}
+		kept = append(kept, b)
+	}
+	return kept, report
+}
+
+// PressureState is the latest provider pressure reading.
+// Stored at <townRoot>/.runtime/provider-pressure.json.
+type PressureState struct {
+	UpdatedAt time.Time                   `json:"updated_at"`
+	Providers map[string]ProviderPressure `json:"providers"`
+}
+
+func pressureFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "provider-pressure.json")
+}
+
+// LoadPressure loads the latest provider pressure reading. A missing file
+// is an empty reading.
+func LoadPressure(townRoot string) (*PressureState, error) {
+	data, err := os.ReadFile(pressureFile(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &PressureState{Providers: map[string]ProviderPressure{}}, nil
+		}
+		return nil, err
+	}
+	var state PressureState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	if state.Providers == nil {
+		state.Providers = map[string]ProviderPressure{}
+	}
+	return &state, nil
+}
+
+// SavePressure writes the provider pressure reading atomically.
+func SavePressure(townRoot string, state *PressureState) error {
+	path := pressureFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
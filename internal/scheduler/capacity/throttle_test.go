@@ -0,0 +1,97 @@
+package capacity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottleLevel(t *testing.T) {
+	now := time.Now()
+	var cfg *ThrottleConfig
+	tests := []struct {
+		name string
+		cfg  *ThrottleConfig
+		p    ProviderPressure
+		want ThrottleLevel
+	}{
+		{"no pressure", cfg, ProviderPressure{Sessions: 4, ObservedAt: now}, ThrottleNone},
+		{"retrying", cfg, ProviderPressure{Sessions: 4, Retrying: 1, ObservedAt: now}, ThrottleSlow},
+		{"some limited", cfg, ProviderPressure{Sessions: 4, Limited: 1, ObservedAt: now}, ThrottleSlow},
+		{"half limited", cfg, ProviderPressure{Sessions: 4, Limited: 2, ObservedAt: now}, ThrottlePause},
+		{"stale", cfg, ProviderPressure{Sessions: 4, Limited: 4, ObservedAt: now.Add(-time.Hour)}, ThrottleNone},
+		{"never observed", cfg, ProviderPressure{Sessions: 4, Limited: 4}, ThrottleNone},
+		{"disabled", &ThrottleConfig{Disabled: true}, ProviderPressure{Sessions: 4, Limited: 4, ObservedAt: now}, ThrottleNone},
+		{"custom ratio", &ThrottleConfig{PauseRatio: 0.75}, ProviderPressure{Sessions: 4, Limited: 2, ObservedAt: now}, ThrottleSlow},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.Level(tt.p, now); got != tt.want {
+			t.Errorf("%s: Level = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestThrottlePending(t *testing.T) {
+	now := time.Now()
+	pending := []PendingBead{
+		{ID: "c1", TargetRig: "claude-rig"},
+		{ID: "c2", TargetRig: "codex-rig"},
+		{ID: "c3", TargetRig: "codex-rig"},
+		{ID: "c4", TargetRig: "gemini-rig"},
+		{ID: "c5", TargetRig: "claude-rig"},
+	}
+	providerOf := func(b PendingBead) string {
+		switch b.TargetRig {
+		case "codex-rig":
+			return "codex"
+		case "gemini-rig":
+			return "gemini"
+		}
+		return "claude"
+	}
+	pressure := map[string]ProviderPressure{
+		"claude": {Sessions: 2, Limited: 2, ObservedAt: now},
+		"codex":  {Sessions: 3, Retrying: 1, ObservedAt: now},
+	}
+
+	kept, report := ThrottlePending(nil, pending, providerOf, pressure, now)
+	var ids []string
+	for _, b := range kept {
+		ids = append(ids, b.ID)
+	}
+	if got := len(ids); got != 2 || ids[0] != "c2" || ids[1] != "c4" {
+		t.Errorf("kept = %v, want [c2 c4]", ids)
+	}
+	if report.Held != 3 || !report.Stagger {
+		t.Errorf("report = %+v, want 3 held and stagger", report)
+	}
+	if report.Levels["claude"] != ThrottlePause || report.Levels["codex"] != ThrottleSlow {
+		t.Errorf("levels = %v", report.Levels)
+	}
+
+	kept, report = ThrottlePending(&ThrottleConfig{Disabled: true}, pending, providerOf, pressure, now)
+	if len(kept) != len(pending) || report.Held != 0 {
+		t.Errorf("disabled: kept %d, held %d", len(kept), report.Held)
+	}
+}
+
+func TestPressureRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	state, err := LoadPressure(townRoot)
+	if err != nil || len(state.Providers) != 0 {
+		t.Fatalf("LoadPressure(empty) = %+v, %v", state, err)
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	in := &PressureState{UpdatedAt: now, Providers: map[string]ProviderPressure{
+		"claude": {Provider: "claude", Sessions: 3, Limited: 1, LimitedSessions: []string{"gt-a"}, ObservedAt: now},
+	}}
+	if err := SavePressure(townRoot, in); err != nil {
+		t.Fatal(err)
+	}
+	out, err := LoadPressure(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.UpdatedAt.Equal(now) || out.Providers["claude"].Limited != 1 || out.Providers["claude"].LimitedSessions[0] != "gt-a" {
+		t.Errorf("round trip = %+v", out)
+	}
+}
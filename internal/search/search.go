@@ -0,0 +1,310 @@
+// Package search is a small local full-text index over a town's issues,
+// mail, and agent transcripts.
+//
+// The index is an in-memory inverted index persisted to the town's
+// .runtime directory. It is rebuilt from source (beads and transcript
+// files) rather than updated incrementally, so it can always be thrown
+// away: a stale or corrupt index costs one rebuild, never data.
+package search
+
+import (
+	"encoding/gob"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Document kinds.
+const (
+	KindIssue      = "issue"
+	KindMail       = "mail"
+	KindTranscript = "transcript"
+)
+
+// MaxBodyBytes caps how much of a document's body is indexed. Transcripts
+// can run to many megabytes; the head of a long document is enough to find
+// it.
+const MaxBodyBytes = 256 * 1024
+
+// Doc is one searchable document.
+type Doc struct {
+	ID    string    `json:"id"`   // issue/message ID, or transcript path
+	Kind  string    `json:"kind"` // KindIssue, KindMail, or KindTranscript
+	Rig   string    `json:"rig,omitempty"`
+	Type  string    `json:"type,omitempty"` // issue type, for issues
+	Title string    `json:"title"`
+	Body  string    `json:"-"`
+	Date  time.Time `json:"date"`
+}
+
+// posting records how often a term occurs in a document.
+type posting struct {
+	Doc int
+	TF  int
+}
+
+// Index is an inverted index over a set of documents.
+type Index struct {
+	BuiltAt  time.Time
+	Docs     []Doc
+	Lengths  []int // token count per document
+	Postings map[string][]posting
+}
+
+// IndexFile returns the path of the town's persisted search index.
+func IndexFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "search", "index.gob")
+}
+
+// tokenize splits text into lowercase words of two or more characters.
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := words[:0]
+	for _, w := range words {
+		if len(w) >= 2 {
+			tokens = append(tokens, w)
+		}
+	}
+	return tokens
+}
+
+// Build indexes docs. Titles are indexed twice so a match in the title
+// outranks the same match buried in a body.
+func Build(docs []Doc) *Index {
+	ix := &Index{
+		BuiltAt:  time.Now(),
+		Docs:     docs,
+		Lengths:  make([]int, len(docs)),
+		Postings: make(map[string][]posting),
+	}
+	for i := range ix.Docs {
+		d := &ix.Docs[i]
+		if len(d.Body) > MaxBodyBytes {
+			d.Body = d.Body[:MaxBodyBytes]
+		}
+		tokens := tokenize(d.Title + " " + d.Title + " " + d.Body)
+		ix.Lengths[i] = len(tokens)
+		counts := make(map[string]int)
+		for _, t := range tokens {
+			counts[t]++
+		}
+		for t, n := range counts {
+			ix.Postings[t] = append(ix.Postings[t], posting{Doc: i, TF: n})
+		}
+	}
+	return ix
+}
+
+// Save writes the index to path.
+func (ix *Index) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating index dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		return fmt.Errorf("creating index: %w", err)
+	}
+	if err := gob.NewEncoder(f).Encode(ix); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing index: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load reads an index written by Save.
+func Load(path string) (*Index, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var ix Index
+	if err := gob.NewDecoder(f).Decode(&ix); err != nil {
+		return nil, fmt.Errorf("reading index: %w", err)
+	}
+	return &ix, nil
+}
+
+// Query is a search request. Every term in Text must match; "quoted
+// phrases" must also appear verbatim (case-insensitively). The remaining
+// fields filter results and are ignored when empty.
+type Query struct {
+	Text  string
+	Kind  string
+	Rig   string
+	Type  string
+	Since time.Time
+	Until time.Time
+	Limit int
+}
+
+// Result is a matching document.
+type Result struct {
+	Doc     *Doc    `json:"doc"`
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet,omitempty"`
+}
+
+// parseQuery splits query text into search terms and quoted phrases.
+func parseQuery(text string) (terms []string, phrases []string) {
+	parts := strings.Split(text, `"`)
+	for i, part := range parts {
+		if i%2 == 1 {
+			if p := strings.TrimSpace(strings.ToLower(part)); p != "" {
+				phrases = append(phrases, p)
+			}
+		}
+		terms = append(terms, tokenize(part)...)
+	}
+	return terms, phrases
+}
+
+// BM25 parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Search returns documents matching q, best first.
+func (ix *Index) Search(q Query) []Result {
+	terms, phrases := parseQuery(q.Text)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	avgLen := 0.0
+	for _, n := range ix.Lengths {
+		avgLen += float64(n)
+	}
+	if len(ix.Lengths) > 0 {
+		avgLen /= float64(len(ix.Lengths))
+	}
+
+	scores := make(map[int]float64)
+	matched := make(map[int]int)
+	seen := make(map[string]bool)
+	unique := 0
+	for _, term := range terms {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+		unique++
+
+		postings := ix.Postings[term]
+		if len(postings) == 0 {
+			return nil // every term must match
+		}
+		n := float64(len(postings))
+		idf := math.Log(1 + (float64(len(ix.Docs))-n+0.5)/(n+0.5))
+		for _, p := range postings {
+			tf := float64(p.TF)
+			norm := 1 - bm25B + bm25B*float64(ix.Lengths[p.Doc])/avgLen
+			scores[p.Doc] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+			matched[p.Doc]++
+		}
+	}
+
+	var results []Result
+	for i, score := range scores {
+		if matched[i] < unique {
+			continue
+		}
+		d := &ix.Docs[i]
+		if !q.matches(d) || !containsPhrases(d, phrases) {
+			continue
+		}
+		results = append(results, Result{Doc: d, Score: score, Snippet: snippet(d.Body, terms, phrases)})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Doc.Date.After(results[j].Doc.Date)
+	})
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results
+}
+
+// matches applies q's filters to d.
+func (q Query) matches(d *Doc) bool {
+	switch {
+	case q.Kind != "" && d.Kind != q.Kind:
+		return false
+	case q.Rig != "" && d.Rig != q.Rig:
+		return false
+	case q.Type != "" && d.Type != q.Type:
+		return false
+	case !q.Since.IsZero() && d.Date.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && d.Date.After(q.Until):
+		return false
+	}
+	return true
+}
+
+func containsPhrases(d *Doc, phrases []string) bool {
+	if len(phrases) == 0 {
+		return true
+	}
+	text := strings.ToLower(d.Title + "\n" + d.Body)
+	for _, p := range phrases {
+		if !strings.Contains(text, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// snippetRadius is how many bytes of context to show on each side of a hit.
+const snippetRadius = 80
+
+// snippet returns a single-line excerpt of body around the first hit.
+func snippet(body string, terms, phrases []string) string {
+	lower := strings.ToLower(body)
+	at := -1
+	for _, needle := range append(phrases, terms...) {
+		if i := strings.Index(lower, needle); i >= 0 && (at < 0 || i < at) {
+			at = i
+		}
+	}
+	if at < 0 {
+		return ""
+	}
+	start, end := min(len(body), max(0, at-snippetRadius)), min(len(body), at+snippetRadius)
+	// Don't cut a multi-byte rune in half.
+	for start > 0 && !isRuneStart(body[start]) {
+		start--
+	}
+	for end < len(body) && !isRuneStart(body[end]) {
+		end++
+	}
+	s := strings.Join(strings.Fields(body[start:end]), " ")
+	if start > 0 {
+		s = "..." + s
+	}
+	if end < len(body) {
+		s += "..."
+	}
+	return s
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
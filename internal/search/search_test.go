@@ -0,0 +1,112 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testIndex() *Index {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	return Build([]Doc{
+		{ID: "gp-1", Kind: KindIssue, Rig: "greenplace", Type: "bug", Title: "Refinery deadlock on merge slot", Body: "The merge slot is never released after a failed rebase.", Date: day},
+		{ID: "gp-2", Kind: KindIssue, Rig: "greenplace", Type: "task", Title: "Document the merge queue", Body: "Explain how the refinery picks MRs.", Date: day.Add(24 * time.Hour)},
+		{ID: "hq-m1", Kind: KindMail, Title: "Re: deadlock", Body: "Seeing the refinery deadlock again on greenplace.", Date: day.Add(48 * time.Hour)},
+		{ID: "/tmp/t.jsonl", Kind: KindTranscript, Rig: "bluefield", Title: "bluefield transcript", Body: "We discussed the cache eviction policy at length.", Date: day},
+	})
+}
+
+func TestSearchRanksAndRequiresAllTerms(t *testing.T) {
+	ix := testIndex()
+
+	results := ix.Search(Query{Text: "refinery deadlock"})
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	for _, r := range results {
+		if r.Doc.ID != "gp-1" && r.Doc.ID != "hq-m1" {
+			t.Errorf("unexpected result %s", r.Doc.ID)
+		}
+	}
+	if results[0].Score < results[1].Score {
+		t.Errorf("results not sorted by score: %v < %v", results[0].Score, results[1].Score)
+	}
+
+	if got := ix.Search(Query{Text: "refinery nonexistentword"}); len(got) != 0 {
+		t.Errorf("query with unknown term returned %d results", len(got))
+	}
+}
+
+func TestSearchFilters(t *testing.T) {
+	ix := testIndex()
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		q    Query
+		want string
+	}{
+		{"kind", Query{Text: "deadlock", Kind: KindMail}, "hq-m1"},
+		{"type", Query{Text: "refinery", Type: "task"}, "gp-2"},
+		{"rig", Query{Text: "cache", Rig: "bluefield"}, "/tmp/t.jsonl"},
+		{"since", Query{Text: "refinery", Since: day.Add(36 * time.Hour)}, "hq-m1"},
+		{"until", Query{Text: "merge", Until: day.Add(time.Hour)}, "gp-1"},
+		{"phrase", Query{Text: `"merge queue"`}, "gp-2"},
+	}
+	for _, tt := range tests {
+		results := ix.Search(tt.q)
+		if len(results) != 1 || results[0].Doc.ID != tt.want {
+			var ids []string
+			for _, r := range results {
+				ids = append(ids, r.Doc.ID)
+			}
+			t.Errorf("%s: got %v, want [%s]", tt.name, ids, tt.want)
+		}
+	}
+}
+
+func TestSearchSnippet(t *testing.T) {
+	results := testIndex().Search(Query{Text: "eviction"})
+	if len(results) != 1 || !strings.Contains(results[0].Snippet, "cache eviction policy") {
+		t.Errorf("snippet = %+v", results)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search", "index.gob")
+	if err := testIndex().Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	ix, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := ix.Search(Query{Text: "eviction"}); len(got) != 1 {
+		t.Errorf("loaded index returned %d results, want 1", len(got))
+	}
+}
+
+func TestTranscriptText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "t.jsonl")
+	lines := `{"type":"user","message":{"content":"Why is the build red?"}}
+{"type":"assistant","message":{"content":[{"type":"text","text":"The linter fails."},{"type":"tool_use","name":"Bash"}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","content":"huge output"}]}}
+{"type":"summary","summary":"ignored"}
+not json
+`
+	if err := os.WriteFile(path, []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+	text, err := TranscriptText(path)
+	if err != nil {
+		t.Fatalf("TranscriptText: %v", err)
+	}
+	if !strings.Contains(text, "Why is the build red?") || !strings.Contains(text, "The linter fails.") {
+		t.Errorf("missing conversation text:\n%s", text)
+	}
+	if strings.Contains(text, "huge output") || strings.Contains(text, "ignored") {
+		t.Errorf("tool output or non-message lines leaked:\n%s", text)
+	}
+}
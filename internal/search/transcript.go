@@ -0,0 +1,70 @@
+package search
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// transcriptLine is the part of a Claude Code transcript entry that search
+// reads. Content is either a string or a list of content blocks.
+type transcriptLine struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// TranscriptText extracts the user and assistant text from a Claude Code
+// transcript (.jsonl), one message per paragraph. Tool calls and results
+// are skipped; they are mostly file contents and command output that would
+// drown out the conversation. Reading stops at MaxBodyBytes.
+func TranscriptText(path string) (string, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path comes from the transcript directory scan
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() && b.Len() < MaxBodyBytes {
+		var line transcriptLine
+		if json.Unmarshal(scanner.Bytes(), &line) != nil || line.Message == nil {
+			continue
+		}
+		if line.Type != "user" && line.Type != "assistant" {
+			continue
+		}
+		if text := contentText(line.Message.Content); text != "" {
+			b.WriteString(text)
+			b.WriteString("\n\n")
+		}
+	}
+	return b.String(), scanner.Err()
+}
+
+// contentText returns the text of a message's content, which is either a
+// plain string or a list of typed blocks.
+func contentText(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return strings.TrimSpace(s)
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(raw, &blocks) != nil {
+		return ""
+	}
+	var parts []string
+	for _, bl := range blocks {
+		if bl.Type == "text" && strings.TrimSpace(bl.Text) != "" {
+			parts = append(parts, strings.TrimSpace(bl.Text))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+)
+
+// DefaultBulkParallelism is how many sessions a bulk operation works on at
+// once unless told otherwise.
+const DefaultBulkParallelism = 8
+
+// Selector picks sessions by name pattern and by the agent they run.
+// Patterns are shell globs over tmux session names ("gt-test-*"); a
+// session matches if it matches any pattern, or if there are none. Rig and
+// Role narrow the match to sessions whose name parses to that rig or role.
+type Selector struct {
+	Patterns []string
+	Rig      string
+	Role     Role
+}
+
+// Validate reports malformed patterns and selectors that would match every
+// session.
+func (s Selector) Validate() error {
+	for _, p := range s.Patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	switch s.Role {
+	case "", RoleMayor, RoleDeacon, RoleWitness, RoleRefinery, RoleCrew, RolePolecat:
+	default:
+		return fmt.Errorf("unknown role %q", s.Role)
+	}
+	if len(s.Patterns) == 0 && s.Rig == "" && s.Role == "" {
+		return fmt.Errorf("no sessions selected: give a pattern, a rig or a role")
+	}
+	return nil
+}
+
+// Matches reports whether the session name is selected.
+func (s Selector) Matches(name string) bool {
+	if len(s.Patterns) > 0 {
+		matched := false
+		for _, p := range s.Patterns {
+			if ok, _ := path.Match(p, name); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if s.Rig == "" && s.Role == "" {
+		return true
+	}
+	id, err := ParseSessionName(name)
+	if err != nil {
+		return false
+	}
+	return (s.Rig == "" || id.Rig == s.Rig) && (s.Role == "" || id.Role == s.Role)
+}
+
+// Select returns the selected names, sorted.
+func (s Selector) Select(names []string) []string {
+	var out []string
+	for _, name := range names {
+		if s.Matches(name) {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// BulkResult is the outcome of a bulk operation on one session.
+type BulkResult struct {
+	Session string
+	Err     error
+}
+
+// RunBulk runs op on each session, at most parallel at a time, and returns
+// the results in the order of sessions.
+func RunBulk(sessions []string, parallel int, op func(session string) error) []BulkResult {
+	if parallel < 1 {
+		parallel = DefaultBulkParallelism
+	}
+	results := make([]BulkResult, len(sessions))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, name := range sessions {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = BulkResult{Session: name, Err: op(name)}
+		}(i, name)
+	}
+	wg.Wait()
+	return results
+}
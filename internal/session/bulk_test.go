@@ -0,0 +1,81 @@
+package session
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSelector(t *testing.T) {
+	old := defaultRegistry
+	defaultRegistry = testRegistry()
+	defer func() { defaultRegistry = old }()
+
+	names := []string{"gt-witness", "gt-Toast", "gt-crew-max", "bd-Nux", "hq-mayor", "gt-test-1", "gt-test-2", "scratch"}
+	tests := []struct {
+		name string
+		sel  Selector
+		want []string
+	}{
+		{"pattern", Selector{Patterns: []string{"gt-test-*"}}, []string{"gt-test-1", "gt-test-2"}},
+		{"patterns", Selector{Patterns: []string{"hq-*", "scratch"}}, []string{"hq-mayor", "scratch"}},
+		{"rig", Selector{Rig: "gastown"}, []string{"gt-Toast", "gt-crew-max", "gt-test-1", "gt-test-2", "gt-witness"}},
+		{"rig and role", Selector{Rig: "gastown", Role: RoleCrew}, []string{"gt-crew-max"}},
+		{"pattern and role", Selector{Patterns: []string{"*-witness", "bd-*"}, Role: RolePolecat}, []string{"bd-Nux"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.sel.Validate(); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			got := tt.sel.Select(names)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Select = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Select = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	for _, bad := range []Selector{{}, {Patterns: []string{"gt-["}}, {Role: "janitor"}} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", bad)
+		}
+	}
+}
+
+func TestRunBulk(t *testing.T) {
+	sessions := []string{"a", "b", "c", "d", "e"}
+	var running, peak int32
+	results := RunBulk(sessions, 2, func(name string) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		if name == "c" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	if peak > 2 {
+		t.Errorf("%d ran at once, want at most 2", peak)
+	}
+	for i, r := range results {
+		if r.Session != sessions[i] {
+			t.Errorf("results[%d] = %s, want %s", i, r.Session, sessions[i])
+		}
+		if (r.Err != nil) != (r.Session == "c") {
+			t.Errorf("%s: err = %v", r.Session, r.Err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// The session directory records who runs in each tmux session: rig, role,
+// agent name, and the issue it is working on. Session starters register
+// sessions when they come up, so identity no longer hangs on the session
+// naming convention. Lookups fall back to parsing the name for sessions
+// started before the directory existed, or by code that doesn't register.
+
+// SessionRecord is the directory entry for one tmux session.
+type SessionRecord struct {
+	Session   string    `json:"session"`
+	Role      Role      `json:"role"`
+	Rig       string    `json:"rig,omitempty"`
+	Name      string    `json:"name,omitempty"` // crew, polecat or dog name
+	Issue     string    `json:"issue,omitempty"`
+	Address   string    `json:"address,omitempty"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+
+	// Parsed is set on records derived from the session name because the
+	// session was never registered.
+	Parsed bool `json:"parsed,omitempty"`
+}
+
+// Identity returns the record as an AgentIdentity.
+func (r *SessionRecord) Identity() *AgentIdentity {
+	return &AgentIdentity{Role: r.Role, Rig: r.Rig, Name: r.Name}
+}
+
+// sessionDirectoryDir returns where session records live. One file per
+// session keeps concurrent starters from clobbering each other.
+func sessionDirectoryDir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "session-names")
+}
+
+func sessionRecordPath(townRoot, session string) string {
+	return filepath.Join(sessionDirectoryDir(townRoot), session+".json")
+}
+
+// RegisterSession records who runs in a session, replacing any earlier
+// record for the same name. Address and StartedAt are filled in when empty.
+func RegisterSession(townRoot string, rec SessionRecord) error {
+	if rec.Session == "" {
+		return fmt.Errorf("registering session: empty session name")
+	}
+	if rec.Address == "" {
+		rec.Address = rec.Identity().Address()
+	}
+	if rec.StartedAt.IsZero() {
+		rec.StartedAt = time.Now()
+	}
+	rec.Parsed = false
+	if err := os.MkdirAll(sessionDirectoryDir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating session directory: %w", err)
+	}
+	return util.AtomicWriteJSON(sessionRecordPath(townRoot, rec.Session), rec)
+}
+
+// SetSessionIssue records the issue a session is working on ("" clears it).
+// A session that was never registered is registered from its name.
+func SetSessionIssue(townRoot, session, issue string) error {
+	rec, err := LookupSession(townRoot, session)
+	if err != nil {
+		return err
+	}
+	rec.Issue = issue
+	return RegisterSession(townRoot, *rec)
+}
+
+// UnregisterSession removes a session's record.
+func UnregisterSession(townRoot, session string) {
+	_ = os.Remove(sessionRecordPath(townRoot, session))
+}
+
+// LookupSession returns who runs in a session: its registered record, or
+// one parsed from the session name.
+func LookupSession(townRoot, session string) (*SessionRecord, error) {
+	data, err := os.ReadFile(sessionRecordPath(townRoot, session))
+	if err == nil {
+		var rec SessionRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("reading session record %s: %w", session, err)
+		}
+		return &rec, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	id, err := ParseSessionName(session)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionRecord{
+		Session: session,
+		Role:    id.Role,
+		Rig:     id.Rig,
+		Name:    id.Name,
+		Address: id.Address(),
+		Parsed:  true,
+	}, nil
+}
+
+// SessionRecords returns every registered session, sorted by name.
+func SessionRecords(townRoot string) ([]SessionRecord, error) {
+	entries, err := os.ReadDir(sessionDirectoryDir(townRoot))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var recs []SessionRecord
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(sessionDirectoryDir(townRoot), e.Name()))
+		if err != nil {
+			continue
+		}
+		var rec SessionRecord
+		if json.Unmarshal(data, &rec) != nil || rec.Session == "" {
+			continue // Skip malformed records
+		}
+		recs = append(recs, rec)
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Session < recs[j].Session })
+	return recs, nil
+}
+
+// SessionForAddress returns the session an agent address ("gastown/witness",
+// "gastown/polecats/Toast") runs in: the registered one, or the name the
+// naming convention gives.
+func SessionForAddress(townRoot, address string) (string, error) {
+	recs, err := SessionRecords(townRoot)
+	if err != nil {
+		return "", err
+	}
+	for _, rec := range recs {
+		if rec.Address == address {
+			return rec.Session, nil
+		}
+	}
+	id, err := ParseAddress(address)
+	if err != nil {
+		return "", err
+	}
+	if name := id.SessionName(); name != "" {
+		return name, nil
+	}
+	return "", fmt.Errorf("no session for %s", address)
+}
+
+// SessionsForIssue returns the registered sessions working on an issue.
+func SessionsForIssue(townRoot, issue string) ([]SessionRecord, error) {
+	recs, err := SessionRecords(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var out []SessionRecord
+	for _, rec := range recs {
+		if rec.Issue == issue {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+// PruneSessionRecords removes the records of sessions that are no longer
+// running and returns how many it removed.
+func PruneSessionRecords(townRoot string, running func(session string) bool) (int, error) {
+	recs, err := SessionRecords(townRoot)
+	if err != nil {
+		return 0, err
+	}
+	pruned := 0
+	for _, rec := range recs {
+		if !running(rec.Session) {
+			UnregisterSession(townRoot, rec.Session)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
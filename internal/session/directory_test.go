@@ -0,0 +1,73 @@
+package session
+
+import (
+	"testing"
+)
+
+func TestSessionDirectory(t *testing.T) {
+	old := defaultRegistry
+	defaultRegistry = testRegistry()
+	defer func() { defaultRegistry = old }()
+
+	town := t.TempDir()
+
+	// Unregistered sessions are identified from their name.
+	rec, err := LookupSession(town, "gt-Toast")
+	if err != nil {
+		t.Fatalf("LookupSession: %v", err)
+	}
+	if !rec.Parsed || rec.Role != RolePolecat || rec.Rig != "gastown" || rec.Name != "Toast" {
+		t.Errorf("parsed record = %+v", rec)
+	}
+	if rec.Address != "gastown/polecats/Toast" {
+		t.Errorf("parsed address = %q", rec.Address)
+	}
+
+	// A registered session doesn't need to follow the naming convention.
+	if err := RegisterSession(town, SessionRecord{Session: "custom-worker", Role: RolePolecat, Rig: "beads", Name: "Nux"}); err != nil {
+		t.Fatalf("RegisterSession: %v", err)
+	}
+	rec, err = LookupSession(town, "custom-worker")
+	if err != nil {
+		t.Fatalf("LookupSession: %v", err)
+	}
+	if rec.Parsed || rec.Rig != "beads" || rec.Address != "beads/polecats/Nux" || rec.StartedAt.IsZero() {
+		t.Errorf("registered record = %+v", rec)
+	}
+
+	if got, err := SessionForAddress(town, "beads/polecats/Nux"); err != nil || got != "custom-worker" {
+		t.Errorf("SessionForAddress(registered) = %q, %v", got, err)
+	}
+	if got, err := SessionForAddress(town, "gastown/witness"); err != nil || got != "gt-witness" {
+		t.Errorf("SessionForAddress(by convention) = %q, %v", got, err)
+	}
+
+	// Setting an issue on an unregistered session registers it.
+	if err := SetSessionIssue(town, "custom-worker", "bd-abc"); err != nil {
+		t.Fatalf("SetSessionIssue: %v", err)
+	}
+	if err := SetSessionIssue(town, "gt-Toast", "bd-abc"); err != nil {
+		t.Fatalf("SetSessionIssue: %v", err)
+	}
+	recs, err := SessionsForIssue(town, "bd-abc")
+	if err != nil {
+		t.Fatalf("SessionsForIssue: %v", err)
+	}
+	if len(recs) != 2 || recs[0].Session != "custom-worker" || recs[1].Session != "gt-Toast" {
+		t.Errorf("SessionsForIssue = %+v", recs)
+	}
+	if recs[1].Parsed {
+		t.Error("gt-Toast should be registered after SetSessionIssue")
+	}
+
+	pruned, err := PruneSessionRecords(town, func(s string) bool { return s == "gt-Toast" })
+	if err != nil || pruned != 1 {
+		t.Fatalf("PruneSessionRecords = %d, %v; want 1", pruned, err)
+	}
+	if recs, _ := SessionRecords(town); len(recs) != 1 || recs[0].Session != "gt-Toast" {
+		t.Errorf("after prune: %+v", recs)
+	}
+	if _, err := LookupSession(town, "custom-worker"); err == nil {
+		t.Error("pruned unconventional session should no longer resolve")
+	}
+}
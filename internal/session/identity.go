@@ -17,6 +17,7 @@ const (
 	RoleRefinery Role = "refinery"
 	RoleCrew     Role = "crew"
 	RolePolecat  Role = "polecat"
+	RoleDog      Role = "dog"
 )
 
 // AgentIdentity represents a parsed Gas Town agent identity.
@@ -234,6 +235,7 @@ func (a *AgentIdentity) BeaconAddress() string {
 //   - refinery → "gastown/refinery"
 //   - crew → "gastown/crew/max"
 //   - polecat → "gastown/polecats/Toast"
+//   - dog → "deacon/dogs/alpha"
 func (a *AgentIdentity) Address() string {
 	switch a.Role {
 	case RoleMayor:
@@ -250,6 +252,8 @@ func (a *AgentIdentity) Address() string {
 		return fmt.Sprintf("%s/crew/%s", a.Rig, a.Name)
 	case RolePolecat:
 		return fmt.Sprintf("%s/polecats/%s", a.Rig, a.Name)
+	case RoleDog:
+		return fmt.Sprintf("deacon/dogs/%s", a.Name)
 	default:
 		return ""
 	}
@@ -3,6 +3,7 @@ package session
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sort"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -100,6 +102,11 @@ type SessionConfig struct {
 	// AutoRespawn sets the auto-respawn hook so the session survives crashes.
 	AutoRespawn bool
 
+	// VerifyStartup watches the new session for a grace period and fails if
+	// its command dies in that time (tmux.NewSessionWithCommandVerified),
+	// instead of the brief check that can miss a slower failure.
+	VerifyStartup bool
+
 	// RemainOnExit sets remain-on-exit immediately after session creation.
 	RemainOnExit bool
 
@@ -126,7 +133,7 @@ type StartResult struct {
 //  3. Build startup command (if not provided)
 //  4. Create tmux session with command
 //  5. Set environment variables (standard + extra)
-//  6. Apply theme (if configured)
+//  6. Apply theme (if configured) and the rig's session template
 //  7. Optional post-start: wait for agent, accept bypass, ready delay,
 //     auto-respawn, PID tracking, verify survived
 //
@@ -179,9 +186,20 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 	if len(cfg.ExtraEnv) > 0 {
 		command = config.PrependEnv(command, cfg.ExtraEnv)
 	}
+	command = WithTemplateEnv(command, cfg.RigPath, cfg.Role)
 
-	// 4. Create tmux session with command.
-	if err := t.NewSessionWithCommand(cfg.SessionID, cfg.WorkDir, command); err != nil {
+	// 4. Create tmux session with command, within the town's limit on
+	// concurrent session creation so mass recovery doesn't fork them all
+	// at once.
+	releaseTmux := AcquireTmuxServer(cfg.TownRoot, cfg.SessionID)
+	var err error
+	if cfg.VerifyStartup {
+		err = t.NewSessionWithCommandVerified(cfg.SessionID, cfg.WorkDir, command, tmux.VerifyOptions{RemainOnExit: cfg.RemainOnExit})
+	} else {
+		err = t.NewSessionWithCommand(cfg.SessionID, cfg.WorkDir, command)
+	}
+	releaseTmux()
+	if err != nil {
 		return nil, fmt.Errorf("creating session: %w", err)
 	}
 
@@ -207,15 +225,18 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 		_ = t.SetEnvironment(cfg.SessionID, k, cfg.ExtraEnv[k])
 	}
 
-	// 7. Apply theme.
-	if cfg.Theme != nil {
-		_ = t.ConfigureGasTownSession(cfg.SessionID, *cfg.Theme, cfg.RigName, cfg.AgentName, cfg.Role)
-	}
+	// 7. Apply theme and the rig's session template.
+	_ = ConfigureSession(t, cfg)
 
 	// 8. Wait for agent to start.
 	if cfg.WaitForAgent {
 		if err := t.WaitForCommand(cfg.SessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
-			if cfg.WaitFatal {
+			// A runtime running under a wrapper shell has started even though
+			// the pane still shows the shell.
+			if d, ok := tmux.DiagnoseWait(err); ok && d.Cause == tmux.WaitWrapperNotExeced {
+				err = nil
+			}
+			if err != nil && cfg.WaitFatal {
 				_ = t.KillSessionWithProcesses(cfg.SessionID)
 				return nil, fmt.Errorf("waiting for %s to start: %w", cfg.Role, err)
 			}
@@ -261,9 +282,55 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 		_ = TrackSessionPID(cfg.TownRoot, cfg.SessionID, t)
 	}
 
+	// 14. Record who runs in the session.
+	if cfg.TownRoot != "" {
+		_ = RegisterSession(cfg.TownRoot, sessionRecordFor(cfg))
+	}
+
 	return &StartResult{RuntimeConfig: runtimeConfig}, nil
 }
 
+// WithTemplateEnv returns command with the env of the rig's session
+// template for role exported ahead of it. Call it on the finished command:
+// Gas Town's own exports come later and so win over template defaults.
+// Town-level agents (empty rigPath) have no template.
+func WithTemplateEnv(command, rigPath, role string) string {
+	if rigPath == "" {
+		return command
+	}
+	return config.PrependEnv(command, config.LoadRigSessionTemplate(rigPath, role).Env)
+}
+
+// ConfigureSession applies cfg.Theme (if set) and then, for rig agents, the
+// rig's session template, so the template's tmux options override the
+// theme's. Uses SessionID, WorkDir, Role, RigPath, RigName, AgentName and
+// Theme from cfg. StartSession calls it; role managers that create their
+// sessions themselves call it in place of ConfigureGasTownSession.
+func ConfigureSession(t *tmux.Tmux, cfg SessionConfig) error {
+	var errs []error
+	if cfg.Theme != nil {
+		errs = append(errs, t.ConfigureGasTownSession(cfg.SessionID, *cfg.Theme, cfg.RigName, cfg.AgentName, cfg.Role))
+	}
+	if cfg.RigPath != "" {
+		tmpl := config.LoadRigSessionTemplate(cfg.RigPath, cfg.Role)
+		errs = append(errs, t.ApplySessionTemplate(cfg.SessionID, cfg.WorkDir, tmpl))
+	}
+	return errors.Join(errs...)
+}
+
+// sessionRecordFor builds the directory record for a session being started.
+// Names that follow the naming convention say who runs there; for others
+// (dogs) the config does.
+func sessionRecordFor(cfg SessionConfig) SessionRecord {
+	rec := SessionRecord{Session: cfg.SessionID}
+	if id, err := ParseSessionName(cfg.SessionID); err == nil {
+		rec.Role, rec.Rig, rec.Name = id.Role, id.Rig, id.Name
+		return rec
+	}
+	rec.Role, rec.Rig, rec.Name = Role(cfg.Role), cfg.RigName, cfg.AgentName
+	return rec
+}
+
 // StopSession stops a tmux session with optional graceful shutdown.
 //
 // If graceful is true, sends Ctrl-C first and waits for the session to exit
@@ -289,6 +356,26 @@ func StopSession(t *tmux.Tmux, sessionID string, graceful bool) error {
 	return nil
 }
 
+// tmuxServerWait is how long session creation waits for a tmux-server slot
+// before going ahead anyway: the limit smooths bursts, it must not keep an
+// agent from starting.
+const tmuxServerWait = 30 * time.Second
+
+// AcquireTmuxServer takes a slot of the town's tmux-server resource for
+// creating sessionID and returns its release. With no town root, or when
+// the slots stay busy, it returns a no-op release.
+func AcquireTmuxServer(townRoot, sessionID string) func() {
+	if townRoot == "" {
+		return func() {}
+	}
+	release, err := lock.NewResource(townRoot, lock.ResourceTmuxServer).AcquireTimeout("start "+sessionID, tmuxServerWait)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: creating %s without a tmux-server slot: %v\n", sessionID, err)
+		return func() {}
+	}
+	return release
+}
+
 func mapKeysSorted(m map[string]string) []string {
 	if len(m) == 0 {
 		return nil
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/config"
@@ -207,3 +208,23 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestWithTemplateEnv(t *testing.T) {
+	rigPath := t.TempDir()
+	settings := config.NewRigSettings()
+	settings.SessionTemplate = &config.SessionTemplateConfig{
+		Env:   map[string]string{"EDITOR": "vim"},
+		Roles: map[string]*config.SessionTemplateConfig{"crew": {Env: map[string]string{"PAGER": "less"}}},
+	}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	got := WithTemplateEnv("exec claude", rigPath, "crew")
+	if !strings.Contains(got, "EDITOR=vim") || !strings.Contains(got, "PAGER=less") || !strings.HasSuffix(got, "exec claude") {
+		t.Errorf("WithTemplateEnv = %q", got)
+	}
+	if got := WithTemplateEnv("exec claude", "", "mayor"); got != "exec claude" {
+		t.Errorf("town-level agent got template env: %q", got)
+	}
+}
@@ -7,8 +7,8 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-	"syscall"
 
+	"github.com/steveyegge/gastown/internal/platform"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -115,14 +115,7 @@ func KillTrackedPIDs(townRoot string) (killed int, errSessions []string) {
 		pid := record.PID
 
 		// Check if process is still alive
-		proc, err := os.FindProcess(pid)
-		if err != nil {
-			_ = os.Remove(path)
-			continue
-		}
-
-		// Signal 0 checks existence without killing
-		if err := proc.Signal(syscall.Signal(0)); err != nil {
+		if !platform.ProcessExists(pid) {
 			// Process is already dead — clean up PID file
 			_ = os.Remove(path)
 			continue
@@ -146,7 +139,7 @@ func KillTrackedPIDs(townRoot string) (killed int, errSessions []string) {
 		}
 
 		// Process is alive — kill it
-		if err := proc.Signal(syscall.SIGTERM); err != nil {
+		if err := platform.Terminate(pid); err != nil {
 			errSessions = append(errSessions, fmt.Sprintf("%s (PID %d): SIGTERM failed: %v", sessionID, pid, err))
 		} else {
 			killed++
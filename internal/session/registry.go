@@ -124,6 +124,14 @@ func InitRegistry(townRoot string) error {
 	// different tmux server.
 	tmux.SetDefaultSocket(sanitizeTownName(filepath.Base(townRoot)))
 
+	// Run sessions under the town's configured multiplexer (tmux unless
+	// town settings say otherwise).
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		if err := tmux.SetDefaultBackend(settings.Multiplexer); err != nil {
+			errs = append(errs, fmt.Errorf("town settings: %w", err))
+		}
+	}
+
 	r, err := BuildPrefixRegistryFromTown(townRoot)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("prefix registry: %w", err))
@@ -54,6 +54,10 @@ type BeaconConfig struct {
 	// Used for non-hook agents where gt prime must complete first.
 	// Default (false) preserves backward compatible behavior.
 	ExcludeWorkInstructions bool
+
+	// ContextFile is an optional worktree-relative path to the run-context
+	// file describing the dispatch (see internal/runcontext).
+	ContextFile string
 }
 
 // FormatStartupBeacon builds the formatted startup beacon message.
@@ -111,6 +115,10 @@ func FormatStartupBeacon(cfg BeaconConfig) string {
 		beacon += "\n\nRun `" + cli.Name() + " prime --hook` and begin work on your hook."
 	}
 
+	if cfg.ContextFile != "" {
+		beacon += "\n\nDispatch context (issue, constraints, budgets, commands): `" + cfg.ContextFile + "`"
+	}
+
 	return beacon
 }
 
@@ -375,6 +375,21 @@ func TestFormatStartupBeacon(t *testing.T) {
 				"gt mail inbox",
 			},
 		},
+		{
+			name: "assigned with context file",
+			cfg: BeaconConfig{
+				Recipient:   BeaconRecipient("polecat", "Toast", "gastown"),
+				Sender:      "witness",
+				Topic:       "assigned",
+				MolID:       "gt-abc12",
+				ContextFile: ".runtime/run-context.md",
+			},
+			wantSub: []string{
+				"gt prime --hook",
+				"Dispatch context",
+				"`.runtime/run-context.md`",
+			},
+		},
 	}
 
 	for _, tt := range tests {
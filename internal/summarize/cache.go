@@ -0,0 +1,68 @@
+package summarize
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// cacheTTL is how long a cached summary is reused. Summaries are keyed by
+// their input, so this only bounds how long stale entries linger.
+const cacheTTL = 7 * 24 * time.Hour
+
+// CacheDir returns the directory holding cached summaries for a town.
+func CacheDir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "summaries")
+}
+
+// cached reuses earlier summaries of the same text by the same model, so
+// digests and prompts rebuilt from unchanged input cost no model calls.
+type cached struct {
+	next Summarizer
+	dir  string
+	id   string
+}
+
+func (c *cached) key(req Request) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d\x00", c.id, req.Kind, req.MaxChars)
+	h.Write([]byte(req.Text))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *cached) Summarize(ctx context.Context, req Request) (string, error) {
+	path := filepath.Join(c.dir, c.key(req)+".txt")
+	if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) < cacheTTL {
+		if data, err := os.ReadFile(path); err == nil && len(data) > 0 {
+			return string(data), nil
+		}
+	}
+	out, err := c.next.Summarize(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(c.dir, 0755); err == nil {
+		_ = util.AtomicWriteFile(path, []byte(out), 0644)
+		c.prune()
+	}
+	return out, nil
+}
+
+// prune removes summaries older than cacheTTL.
+func (c *cached) prune() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && time.Since(info.ModTime()) > cacheTTL {
+			_ = os.Remove(filepath.Join(c.dir, e.Name()))
+		}
+	}
+}
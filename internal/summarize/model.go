@@ -0,0 +1,149 @@
+package summarize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// model runs a prompt through a language model.
+type model interface {
+	complete(ctx context.Context, prompt string) (string, error)
+	// id identifies the model in cache keys.
+	id() string
+}
+
+// modelSummarizer summarizes with a model, bounding each call.
+type modelSummarizer struct {
+	model   model
+	timeout time.Duration
+}
+
+func (s *modelSummarizer) Summarize(ctx context.Context, req Request) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	out, err := s.model.complete(ctx, prompt(req))
+	if err != nil {
+		return "", err
+	}
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return "", fmt.Errorf("%s returned an empty summary", s.model.id())
+	}
+	// Models overshoot length limits; never hand back more than was asked.
+	return Truncate(KindDiff, out, req.MaxChars), nil
+}
+
+// commandModel is a local model run as a command, e.g. "ollama run
+// llama3.2": the prompt goes to stdin, the completion comes from stdout.
+type commandModel struct {
+	argv []string
+}
+
+func (m *commandModel) id() string {
+	return "command:" + strings.Join(m.argv, " ")
+}
+
+func (m *commandModel) complete(ctx context.Context, prompt string) (string, error) {
+	cmd := exec.CommandContext(ctx, m.argv[0], m.argv[1:]...) //nolint:gosec // G204: command is from town settings
+	cmd.Stdin = strings.NewReader(prompt)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", m.argv[0], err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", m.argv[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// endpointModel is an OpenAI-compatible chat completions API, hosted or
+// local (ollama, llama.cpp and vLLM all serve one).
+type endpointModel struct {
+	url       string
+	model     string
+	apiKeyEnv string
+	client    *http.Client // nil uses http.DefaultClient
+}
+
+func (m *endpointModel) id() string {
+	return "endpoint:" + m.url + "#" + m.model
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model    string        `json:"model,omitempty"`
+	Messages []chatMessage `json:"messages"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (m *endpointModel) complete(ctx context.Context, prompt string) (string, error) {
+	body, err := json.Marshal(chatRequest{
+		Model:    m.model,
+		Messages: []chatMessage{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.apiKeyEnv != "" {
+		key := os.Getenv(m.apiKeyEnv)
+		if key == "" {
+			return "", fmt.Errorf("%s is not set", m.apiKeyEnv)
+		}
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+
+	client := m.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return "", err
+	}
+
+	var out chatResponse
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", fmt.Errorf("%s: HTTP %d: %w", m.url, resp.StatusCode, err)
+	}
+	if out.Error != nil {
+		return "", fmt.Errorf("%s: %s", m.url, out.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: HTTP %d", m.url, resp.StatusCode)
+	}
+	if len(out.Choices) == 0 {
+		return "", fmt.Errorf("%s: no choices in response", m.url)
+	}
+	return out.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,180 @@
+// Package summarize condenses long text for prompts and digests: session
+// transcripts into resume prompts, issue threads into digest lines, and
+// diffs into review summaries. A town can configure a model to do it
+// (settings/config.json "summarizer"); without one, or when the model
+// fails, the text is truncated instead, so callers always get something
+// that fits.
+package summarize
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Kind is what is being summarized. It selects the model prompt and which
+// end of the text truncation keeps.
+type Kind string
+
+const (
+	// KindTranscript is an agent session's terminal output.
+	KindTranscript Kind = "transcript"
+	// KindThread is an issue's description and notes.
+	KindThread Kind = "thread"
+	// KindDiff is a change set under review.
+	KindDiff Kind = "diff"
+)
+
+const (
+	// DefaultTimeout bounds each model call when none is configured.
+	DefaultTimeout = 60 * time.Second
+
+	// maxInputChars caps the text sent to a model; longer text is
+	// truncated first.
+	maxInputChars = 100_000
+)
+
+// Request is text to summarize.
+type Request struct {
+	Kind Kind
+	Text string
+	// MaxChars is the longest summary wanted. Zero means no limit.
+	MaxChars int
+}
+
+// Summarizer condenses text.
+type Summarizer interface {
+	Summarize(ctx context.Context, req Request) (string, error)
+}
+
+// Truncator is the no-model summarizer: it truncates.
+type Truncator struct{}
+
+// Summarize returns the request's text truncated to MaxChars.
+func (Truncator) Summarize(_ context.Context, req Request) (string, error) {
+	return Truncate(req.Kind, req.Text, req.MaxChars), nil
+}
+
+// New returns the town's summarizer: its configured model, cached and
+// falling back to truncation, or a Truncator when none is configured.
+func New(townRoot string) Summarizer {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Summarizer == nil {
+		return Truncator{}
+	}
+	return FromConfig(townRoot, settings.Summarizer)
+}
+
+// FromConfig builds a summarizer from a config. townRoot locates the cache.
+func FromConfig(townRoot string, cfg *config.SummarizerConfig) Summarizer {
+	if cfg == nil {
+		return Truncator{}
+	}
+	timeout := DefaultTimeout
+	if d, err := time.ParseDuration(cfg.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+	var m model
+	switch {
+	case len(cfg.Command) > 0:
+		m = &commandModel{argv: cfg.Command}
+	case cfg.Endpoint != "":
+		m = &endpointModel{url: cfg.Endpoint, model: cfg.Model, apiKeyEnv: cfg.APIKeyEnv}
+	default:
+		return Truncator{}
+	}
+	var s Summarizer = &modelSummarizer{model: m, timeout: timeout}
+	if !cfg.NoCache && townRoot != "" {
+		s = &cached{next: s, dir: CacheDir(townRoot), id: m.id()}
+	}
+	return &fallback{next: s}
+}
+
+// Text summarizes with s, falling back to truncation on error. It is the
+// usual entry point for callers that just want a string.
+func Text(ctx context.Context, s Summarizer, kind Kind, text string, maxChars int) string {
+	req := Request{Kind: kind, Text: text, MaxChars: maxChars}
+	if s == nil {
+		s = Truncator{}
+	}
+	out, err := s.Summarize(ctx, req)
+	if err != nil || strings.TrimSpace(out) == "" {
+		return Truncate(kind, text, maxChars)
+	}
+	return out
+}
+
+// fallback truncates when the summarizer it wraps fails or returns nothing.
+type fallback struct {
+	next Summarizer
+}
+
+func (f *fallback) Summarize(ctx context.Context, req Request) (string, error) {
+	return Text(ctx, f.next, req.Kind, req.Text, req.MaxChars), nil
+}
+
+// Truncate shortens text to at most maxChars runes, marking what was cut.
+// Transcripts keep their end (the latest output), diffs their start, and
+// threads both the opening and the latest notes.
+func Truncate(kind Kind, text string, maxChars int) string {
+	text = strings.TrimSpace(text)
+	runes := []rune(text)
+	if maxChars <= 0 || len(runes) <= maxChars {
+		return text
+	}
+	marker := fmt.Sprintf("[… %d chars omitted …]", len(runes)-maxChars)
+	budget := maxChars - len([]rune(marker)) - 2
+	if budget <= 0 {
+		return string(runes[:maxChars])
+	}
+	var head int
+	switch kind {
+	case KindTranscript:
+		head = 0
+	case KindDiff:
+		head = budget
+	default:
+		head = budget / 2
+	}
+	tail := budget - head
+	var b strings.Builder
+	if head > 0 {
+		b.WriteString(strings.TrimSpace(string(runes[:head])))
+		b.WriteString("\n")
+	}
+	b.WriteString(marker)
+	if tail > 0 {
+		b.WriteString("\n")
+		b.WriteString(strings.TrimSpace(string(runes[len(runes)-tail:])))
+	}
+	return b.String()
+}
+
+// prompt is the instruction sent to a model for a request.
+func prompt(req Request) string {
+	var task string
+	switch req.Kind {
+	case KindTranscript:
+		task = "Below is the recent terminal output of a coding agent's session. " +
+			"Summarize what the agent was working on, what it finished, and what it was doing " +
+			"or stuck on when the output ends, so a fresh session can pick up the work."
+	case KindDiff:
+		task = "Below is a code change under review. Summarize what it changes and why, " +
+			"and point out anything a reviewer should look at closely."
+	default:
+		task = "Below is an issue and its discussion. Summarize its current state: " +
+			"what is wanted, what has been tried, and what is blocking it."
+	}
+	var b strings.Builder
+	b.WriteString(task)
+	if req.MaxChars > 0 {
+		fmt.Fprintf(&b, " Keep the summary under %d characters.", req.MaxChars)
+	}
+	b.WriteString(" Reply with the summary only.\n\n---\n")
+	b.WriteString(Truncate(req.Kind, req.Text, maxInputChars))
+	b.WriteString("\n")
+	return b.String()
+}
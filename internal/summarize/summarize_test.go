@@ -0,0 +1,101 @@
+package summarize
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestTruncate(t *testing.T) {
+	text := "first line\n" + strings.Repeat("x", 200) + "\nlast line"
+	if got := Truncate(KindThread, "short", 100); got != "short" {
+		t.Errorf("short text changed: %q", got)
+	}
+	for _, tc := range []struct {
+		kind      Kind
+		head, end bool
+	}{
+		{KindTranscript, false, true},
+		{KindDiff, true, false},
+		{KindThread, true, true},
+	} {
+		got := Truncate(tc.kind, text, 80)
+		if n := len([]rune(got)); n > 80 {
+			t.Errorf("%s: %d chars, want <= 80", tc.kind, n)
+		}
+		if !strings.Contains(got, "chars omitted") {
+			t.Errorf("%s: no omission marker in %q", tc.kind, got)
+		}
+		if strings.HasPrefix(got, "first line") != tc.head {
+			t.Errorf("%s: keeps head = %v, want %v", tc.kind, !tc.head, tc.head)
+		}
+		if strings.HasSuffix(got, "last line") != tc.end {
+			t.Errorf("%s: keeps end = %v, want %v", tc.kind, !tc.end, tc.end)
+		}
+	}
+}
+
+func TestFromConfig_NoModelTruncates(t *testing.T) {
+	if _, ok := FromConfig(t.TempDir(), &config.SummarizerConfig{}).(Truncator); !ok {
+		t.Error("a config without command or endpoint should truncate")
+	}
+	if _, ok := New(t.TempDir()).(Truncator); !ok {
+		t.Error("a town without settings should truncate")
+	}
+}
+
+func TestCommandModel_CachesAndFallsBack(t *testing.T) {
+	town := t.TempDir()
+	calls := filepath.Join(town, "calls")
+	s := FromConfig(town, &config.SummarizerConfig{
+		Command: []string{"sh", "-c", "cat >/dev/null; echo x >> " + calls + "; echo '  the summary  '"},
+	})
+	req := Request{Kind: KindThread, Text: "a long thread", MaxChars: 100}
+	for i := 0; i < 2; i++ {
+		got, err := s.Summarize(context.Background(), req)
+		if err != nil || got != "the summary" {
+			t.Fatalf("Summarize = %q, %v", got, err)
+		}
+	}
+	if data, _ := os.ReadFile(calls); strings.Count(string(data), "x") != 1 {
+		t.Errorf("model ran %d times, want 1 (second call cached)", strings.Count(string(data), "x"))
+	}
+
+	failing := FromConfig(town, &config.SummarizerConfig{Command: []string{"sh", "-c", "exit 3"}})
+	text := strings.Repeat("y", 300)
+	got, err := failing.Summarize(context.Background(), Request{Kind: KindDiff, Text: text, MaxChars: 100})
+	if err != nil || got != Truncate(KindDiff, text, 100) {
+		t.Errorf("failing model: got %q, %v; want the truncation", got, err)
+	}
+}
+
+func TestEndpointModel(t *testing.T) {
+	var gotAuth string
+	var gotReq chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&gotReq)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"diff summary"}}]}`))
+	}))
+	defer srv.Close()
+	t.Setenv("TEST_SUMMARIZER_KEY", "sekrit")
+
+	s := FromConfig("", &config.SummarizerConfig{Endpoint: srv.URL, Model: "small", APIKeyEnv: "TEST_SUMMARIZER_KEY"})
+	got, err := s.Summarize(context.Background(), Request{Kind: KindDiff, Text: "+added line"})
+	if err != nil || got != "diff summary" {
+		t.Fatalf("Summarize = %q, %v", got, err)
+	}
+	if gotAuth != "Bearer sekrit" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if gotReq.Model != "small" || len(gotReq.Messages) != 1 || !strings.Contains(gotReq.Messages[0].Content, "+added line") {
+		t.Errorf("request = %+v", gotReq)
+	}
+}
@@ -0,0 +1,132 @@
+// Package takeover tracks agent sessions a human has taken over.
+//
+// While a session is taken over, supervisors leave it alone: the witness
+// skips it in zombie and stall detection, the daemon does not restart it,
+// and gt nudge does not deliver to it without --force. Mixed human and
+// agent input otherwise looks like a hung or stalled agent.
+//
+// State lives in <town>/.runtime/takeover/<session>.json, one file per
+// session, so checking a session is a single stat.
+package takeover
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrAlreadyTakenOver is returned by Start for a session already taken over.
+var ErrAlreadyTakenOver = errors.New("session is already taken over")
+
+// ErrNotTakenOver is returned by End for a session not taken over.
+var ErrNotTakenOver = errors.New("session is not taken over")
+
+// State is one active takeover.
+type State struct {
+	Session   string    `json:"session"`
+	Target    string    `json:"target"` // address, e.g. gastown/Toast
+	By        string    `json:"by"`
+	Reason    string    `json:"reason,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// Dir returns the directory holding takeover state.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "takeover")
+}
+
+// Path returns the state file for a session.
+func Path(townRoot, session string) string {
+	return filepath.Join(Dir(townRoot), session+".json")
+}
+
+// Get returns the session's takeover, or nil if it isn't taken over.
+func Get(townRoot, session string) (*State, error) {
+	data, err := os.ReadFile(Path(townRoot, session)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing takeover state: %w", err)
+	}
+	return &state, nil
+}
+
+// IsTakenOver reports whether a human has taken over the session.
+func IsTakenOver(townRoot, session string) bool {
+	_, err := os.Stat(Path(townRoot, session))
+	return err == nil
+}
+
+// Start records a takeover of the session.
+func Start(townRoot, session, target, by, reason string) (*State, error) {
+	if existing, err := Get(townRoot, session); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return existing, ErrAlreadyTakenOver
+	}
+	state := &State{
+		Session:   session,
+		Target:    target,
+		By:        by,
+		Reason:    reason,
+		StartedAt: time.Now().UTC(),
+	}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return state, os.WriteFile(Path(townRoot, session), data, 0600)
+}
+
+// End removes the session's takeover and returns it.
+func End(townRoot, session string) (*State, error) {
+	state, err := Get(townRoot, session)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, ErrNotTakenOver
+	}
+	if err := os.Remove(Path(townRoot, session)); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return state, nil
+}
+
+// List returns all active takeovers, oldest first.
+func List(townRoot string) ([]State, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var states []State
+	for _, entry := range entries {
+		session, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		state, err := Get(townRoot, session)
+		if err != nil || state == nil {
+			continue
+		}
+		states = append(states, *state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].StartedAt.Before(states[j].StartedAt) })
+	return states, nil
+}
@@ -0,0 +1,62 @@
+package takeover
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStartEnd(t *testing.T) {
+	town := t.TempDir()
+	if IsTakenOver(town, "gt-Toast") {
+		t.Fatal("fresh town reports a takeover")
+	}
+
+	state, err := Start(town, "gt-Toast", "gastown/Toast", "human", "debugging")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if !IsTakenOver(town, "gt-Toast") {
+		t.Error("IsTakenOver = false after Start")
+	}
+	if _, err := Start(town, "gt-Toast", "gastown/Toast", "human", ""); !errors.Is(err, ErrAlreadyTakenOver) {
+		t.Errorf("second Start err = %v, want ErrAlreadyTakenOver", err)
+	}
+
+	got, err := Get(town, "gt-Toast")
+	if err != nil || got == nil || got.Reason != "debugging" || got.Target != "gastown/Toast" {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+
+	ended, err := End(town, "gt-Toast")
+	if err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	if !ended.StartedAt.Equal(state.StartedAt) {
+		t.Errorf("End returned StartedAt %v, want %v", ended.StartedAt, state.StartedAt)
+	}
+	if IsTakenOver(town, "gt-Toast") {
+		t.Error("IsTakenOver = true after End")
+	}
+	if _, err := End(town, "gt-Toast"); !errors.Is(err, ErrNotTakenOver) {
+		t.Errorf("second End err = %v, want ErrNotTakenOver", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	town := t.TempDir()
+	if states, err := List(town); err != nil || len(states) != 0 {
+		t.Fatalf("List on empty town = %v, %v", states, err)
+	}
+	for _, s := range []string{"gt-Toast", "gt-Nux"} {
+		if _, err := Start(town, s, "gastown/"+s[3:], "human", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	states, err := List(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 2 || states[1].StartedAt.Before(states[0].StartedAt) {
+		t.Errorf("List = %+v, want 2 takeovers oldest first", states)
+	}
+}
@@ -0,0 +1,80 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+//go:embed instructions/*.md.tmpl
+var instructionsFS embed.FS
+
+// InstructionsData contains information for rendering the Gas Town section
+// of an agent's instructions file (see package instructions).
+type InstructionsData struct {
+	Role      string // polecat, crew, ...
+	RigName   string // e.g., "greenplace"
+	AgentName string // polecat or crew name; empty for singletons
+	Identity  string // e.g., "polecat Toast of rig greenplace"
+
+	Commands []InstructionsCommand
+
+	Conventions    []string // one-line rig conventions (default branch, test command, ...)
+	ConventionsDoc string   // the rig's settings/conventions.md, if any
+	Constraints    []string // what currently restricts the agent (freeze, maintenance, ...)
+}
+
+// InstructionsCommand is a gt command listed in instructions.
+type InstructionsCommand struct {
+	Usage   string // without the leading command name, e.g., "done"
+	Purpose string
+}
+
+// InstructionsOverrideDir is where a town keeps its own instructions
+// templates: <role>.md.tmpl for one role, agent.md.tmpl for all.
+func InstructionsOverrideDir(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "instructions")
+}
+
+// RenderInstructions renders the instructions section for data.Role with
+// the town's override template if it has one, else the built-in template.
+func RenderInstructions(townRoot string, data InstructionsData) (string, error) {
+	name, text, err := instructionsTemplate(townRoot, data.Role)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing instructions template %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering instructions template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// instructionsTemplate returns the name and text of the template to render
+// role's instructions with.
+func instructionsTemplate(townRoot, role string) (string, string, error) {
+	if townRoot != "" {
+		for _, name := range []string{role + ".md.tmpl", "agent.md.tmpl"} {
+			path := filepath.Join(InstructionsOverrideDir(townRoot), name)
+			data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+			if err == nil {
+				return path, string(data), nil
+			}
+			if !os.IsNotExist(err) {
+				return "", "", fmt.Errorf("reading %s: %w", path, err)
+			}
+		}
+	}
+	data, err := instructionsFS.ReadFile("instructions/agent.md.tmpl")
+	if err != nil {
+		return "", "", err
+	}
+	return "agent.md.tmpl", string(data), nil
+}
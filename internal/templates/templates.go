@@ -4,14 +4,17 @@ package templates
 import (
 	"bytes"
 	"embed"
+	"encoding/xml"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"text/template"
 
+	"github.com/steveyegge/gastown/internal/platform"
 	"github.com/steveyegge/gastown/internal/templates/commands"
 )
 
@@ -44,6 +47,27 @@ var templateFS embed.FS
 //go:embed launchd/*.plist systemd/*.service
 var supervisorFS embed.FS
 
+// supervisorFuncs escape values for the supervisor file formats, for
+// values such as PATH that are copied from the environment as they are.
+var supervisorFuncs = template.FuncMap{
+	"systemdEnv": systemdEnvEscape, // inside Environment="..."
+	"xml":        xmlEscape,        // plist <string> contents
+}
+
+// systemdEnvEscape escapes s for a double-quoted systemd Environment=
+// assignment: backslashes and quotes are backslash-escaped, and % is
+// doubled so systemd does not expand it as a specifier.
+func systemdEnvEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%").Replace(s)
+}
+
+// xmlEscape escapes s as XML character data.
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
 // Templates manages role and message templates.
 type Templates struct {
 	roleTemplates    *template.Template
@@ -114,6 +138,8 @@ type HandoffData struct {
 type SupervisorData struct {
 	GTPath   string // Path to the gt binary
 	TownRoot string // Path to the Gas Town workspace
+	LogPath  string // Path the daemon's stdout/stderr is appended to
+	Path     string // PATH environment for the daemon process
 }
 
 // New creates a new Templates instance.
@@ -246,65 +272,136 @@ func MissingCommandsFor(workspacePath, agent string) []string {
 	return commands.MissingFor(workspacePath, agent)
 }
 
-// ProvisionSupervisor creates and configures supervisor files for the daemon.
-// On macOS: creates and loads a launchd plist.
-// On Linux: creates and enables a systemd user unit.
-// Returns a message indicating what action was taken (or skipped).
-func ProvisionSupervisor(townRoot string) (string, error) {
+// SupervisorLaunchdLabel is the launchd label for the daemon service.
+const SupervisorLaunchdLabel = "com.gastown.daemon"
+
+// SupervisorSystemdUnit is the systemd user unit name for the daemon service.
+const SupervisorSystemdUnit = "gastown-daemon.service"
+
+// NewSupervisorData builds supervisor template data for a town, capturing
+// the current gt executable and PATH. Service managers start processes with
+// a minimal PATH, so the caller's PATH is baked in to keep tmux, dolt, bd,
+// and agent CLIs resolvable from the daemon.
+func NewSupervisorData(townRoot string) (SupervisorData, error) {
 	gtPath, err := os.Executable()
 	if err != nil {
-		return "", fmt.Errorf("finding gt executable: %w", err)
+		return SupervisorData{}, fmt.Errorf("finding gt executable: %w", err)
 	}
-
-	data := SupervisorData{
+	return SupervisorData{
 		GTPath:   gtPath,
 		TownRoot: townRoot,
-	}
+		LogPath:  filepath.Join(townRoot, "daemon", "daemon.log"),
+		Path:     os.Getenv("PATH"),
+	}, nil
+}
 
-	switch runtime.GOOS {
+// SupervisorUnitPath returns where the supervisor file for goos is installed.
+func SupervisorUnitPath(goos string) (string, error) {
+	switch goos {
 	case "darwin":
-		return provisionLaunchd(data)
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("finding home directory: %w", err)
+		}
+		return filepath.Join(homeDir, "Library", "LaunchAgents", SupervisorLaunchdLabel+".plist"), nil
 	case "linux":
-		return provisionSystemd(data)
+		// Get XDG_DATA_HOME or use ~/.local/share
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("finding home directory: %w", err)
+			}
+			dataHome = filepath.Join(homeDir, ".local", "share")
+		}
+		return filepath.Join(dataHome, "systemd", "user", SupervisorSystemdUnit), nil
 	default:
-		return fmt.Sprintf("Supervisor auto-configuration skipped on %s (not supported yet)", runtime.GOOS), nil
+		return "", fmt.Errorf("supervisor not supported on %s", goos)
 	}
 }
 
-// provisionLaunchd creates and loads a launchd plist on macOS.
-func provisionLaunchd(data SupervisorData) (string, error) {
-	homeDir, err := os.UserHomeDir()
+// RenderSupervisor renders the supervisor file (launchd plist or systemd
+// unit) for goos without writing or loading it.
+func RenderSupervisor(goos string, data SupervisorData) (string, error) {
+	var name string
+	switch goos {
+	case "darwin":
+		name = "launchd/" + SupervisorLaunchdLabel + ".plist"
+	case "linux":
+		name = "systemd/" + SupervisorSystemdUnit
+	default:
+		return "", fmt.Errorf("supervisor not supported on %s", goos)
+	}
+
+	templateContent, err := supervisorFS.ReadFile(name)
 	if err != nil {
-		return "", fmt.Errorf("finding home directory: %w", err)
+		return "", fmt.Errorf("reading supervisor template: %w", err)
 	}
 
-	agentsDir := filepath.Join(homeDir, "Library", "LaunchAgents")
-	if err := os.MkdirAll(agentsDir, 0755); err != nil {
-		return "", fmt.Errorf("creating LaunchAgents directory: %w", err)
+	tmpl, err := template.New(goos).Funcs(supervisorFuncs).Parse(string(templateContent))
+	if err != nil {
+		return "", fmt.Errorf("parsing supervisor template: %w", err)
 	}
 
-	plistPath := filepath.Join(agentsDir, "com.gastown.daemon.plist")
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering supervisor template: %w", err)
+	}
+	return buf.String(), nil
+}
 
-	// Read the template
-	templateContent, err := supervisorFS.ReadFile("launchd/com.gastown.daemon.plist")
+// ProvisionSupervisor creates and configures supervisor files for the daemon.
+// On macOS: creates and loads a launchd plist.
+// On Linux: creates and enables a systemd user unit.
+// Returns a message indicating what action was taken (or skipped).
+func ProvisionSupervisor(townRoot string) (string, error) {
+	data, err := NewSupervisorData(townRoot)
 	if err != nil {
-		return "", fmt.Errorf("reading launchd template: %w", err)
+		return "", err
 	}
 
-	// Parse and execute template
-	tmpl, err := template.New("launchd").Parse(string(templateContent))
-	if err != nil {
-		return "", fmt.Errorf("parsing launchd template: %w", err)
+	switch runtime.GOOS {
+	case "darwin":
+		return provisionLaunchd(data)
+	case "linux":
+		if platform.IsWSL() && !platform.SystemdRunning() {
+			return "", fmt.Errorf("systemd is not running under WSL; set systemd=true in the [boot] section of /etc/wsl.conf and run 'wsl --shutdown'")
+		}
+		return provisionSystemd(data)
+	default:
+		return fmt.Sprintf("Supervisor auto-configuration skipped on %s (not supported yet)", runtime.GOOS), nil
 	}
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("rendering launchd template: %w", err)
+// writeSupervisorFile renders the supervisor file for goos and writes it to
+// its install location, returning the path written.
+func writeSupervisorFile(goos string, data SupervisorData) (string, error) {
+	unitPath, err := SupervisorUnitPath(goos)
+	if err != nil {
+		return "", err
+	}
+	content, err := RenderSupervisor(goos, data)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(unitPath), 0755); err != nil {
+		return "", fmt.Errorf("creating supervisor directory: %w", err)
+	}
+	// Ensure the log directory exists; launchd/systemd will not create it.
+	if err := os.MkdirAll(filepath.Dir(data.LogPath), 0755); err != nil {
+		return "", fmt.Errorf("creating log directory: %w", err)
+	}
+	if err := os.WriteFile(unitPath, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("writing supervisor file: %w", err)
 	}
+	return unitPath, nil
+}
 
-	// Write plist file
-	if err := os.WriteFile(plistPath, buf.Bytes(), 0644); err != nil {
-		return "", fmt.Errorf("writing plist file: %w", err)
+// provisionLaunchd creates and loads a launchd plist on macOS.
+func provisionLaunchd(data SupervisorData) (string, error) {
+	plistPath, err := writeSupervisorFile("darwin", data)
+	if err != nil {
+		return "", err
 	}
 
 	// Unload if already loaded (ignore errors)
@@ -315,64 +412,60 @@ func provisionLaunchd(data SupervisorData) (string, error) {
 		return "", fmt.Errorf("loading launchd service: %s", string(output))
 	}
 
-	return "Created and loaded launchd service: com.gastown.daemon", nil
+	return "Created and loaded launchd service: " + SupervisorLaunchdLabel, nil
 }
 
 // provisionSystemd creates and enables a systemd user unit on Linux.
 func provisionSystemd(data SupervisorData) (string, error) {
-	// Get XDG_DATA_HOME or use ~/.local/share
-	dataHome := os.Getenv("XDG_DATA_HOME")
-	if dataHome == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("finding home directory: %w", err)
-		}
-		dataHome = filepath.Join(homeDir, ".local", "share")
+	if _, err := writeSupervisorFile("linux", data); err != nil {
+		return "", err
 	}
 
-	systemdDir := filepath.Join(dataHome, "systemd", "user")
-	if err := os.MkdirAll(systemdDir, 0755); err != nil {
-		return "", fmt.Errorf("creating systemd user directory: %w", err)
+	// Reload systemd daemon
+	if output, err := exec.Command("systemctl", "--user", "daemon-reload").CombinedOutput(); err != nil {
+		return "", fmt.Errorf("reloading systemd: %s", string(output))
 	}
 
-	servicePath := filepath.Join(systemdDir, "gastown-daemon.service")
-
-	// Read the template
-	templateContent, err := supervisorFS.ReadFile("systemd/gastown-daemon.service")
-	if err != nil {
-		return "", fmt.Errorf("reading systemd template: %w", err)
+	// Enable the service
+	if output, err := exec.Command("systemctl", "--user", "enable", SupervisorSystemdUnit).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("enabling systemd service: %s", string(output))
 	}
 
-	// Parse and execute template
-	tmpl, err := template.New("systemd").Parse(string(templateContent))
-	if err != nil {
-		return "", fmt.Errorf("parsing systemd template: %w", err)
+	// Start the service
+	if output, err := exec.Command("systemctl", "--user", "start", SupervisorSystemdUnit).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("starting systemd service: %s", string(output))
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("rendering systemd template: %w", err)
-	}
+	return "Created and enabled systemd user service: " + SupervisorSystemdUnit, nil
+}
 
-	// Write service file
-	if err := os.WriteFile(servicePath, buf.Bytes(), 0644); err != nil {
-		return "", fmt.Errorf("writing service file: %w", err)
+// UninstallSupervisor stops the supervised daemon and removes its launchd
+// plist or systemd unit. Returns a message describing what was done.
+// Missing files are not an error, so uninstall is idempotent.
+func UninstallSupervisor() (string, error) {
+	unitPath, err := SupervisorUnitPath(runtime.GOOS)
+	if err != nil {
+		return fmt.Sprintf("Supervisor removal skipped on %s (not supported yet)", runtime.GOOS), nil
 	}
-
-	// Reload systemd daemon
-	if output, err := exec.Command("systemctl", "--user", "daemon-reload").CombinedOutput(); err != nil {
-		return "", fmt.Errorf("reloading systemd: %s", string(output))
+	if _, err := os.Stat(unitPath); os.IsNotExist(err) {
+		return "No supervisor service installed", nil
 	}
 
-	// Enable the service
-	if output, err := exec.Command("systemctl", "--user", "enable", "gastown-daemon.service").CombinedOutput(); err != nil {
-		return "", fmt.Errorf("enabling systemd service: %s", string(output))
+	switch runtime.GOOS {
+	case "darwin":
+		_ = exec.Command("launchctl", "unload", unitPath).Run()
+	case "linux":
+		_ = exec.Command("systemctl", "--user", "disable", "--now", SupervisorSystemdUnit).Run()
 	}
 
-	// Start the service
-	if output, err := exec.Command("systemctl", "--user", "start", "gastown-daemon.service").CombinedOutput(); err != nil {
-		return "", fmt.Errorf("starting systemd service: %s", string(output))
+	if err := os.Remove(unitPath); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("removing %s: %w", unitPath, err)
 	}
 
-	return "Created and enabled systemd user service: gastown-daemon.service", nil
+	if runtime.GOOS == "linux" {
+		if output, err := exec.Command("systemctl", "--user", "daemon-reload").CombinedOutput(); err != nil {
+			return "", fmt.Errorf("reloading systemd: %s", string(output))
+		}
+	}
+	return "Removed supervisor service: " + unitPath, nil
 }
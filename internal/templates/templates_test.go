@@ -1,6 +1,7 @@
 package templates
 
 import (
+	"encoding/xml"
 	"fmt"
 	"strings"
 	"testing"
@@ -533,3 +534,74 @@ func TestRoleNames(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderSupervisor(t *testing.T) {
+	data := SupervisorData{
+		GTPath:   "/usr/local/bin/gt",
+		TownRoot: "/home/u/gt",
+		LogPath:  "/home/u/gt/daemon/daemon.log",
+		Path:     "/usr/local/bin:/usr/bin",
+	}
+
+	unit, err := RenderSupervisor("linux", data)
+	if err != nil {
+		t.Fatalf("RenderSupervisor(linux) error = %v", err)
+	}
+	for _, want := range []string{
+		"ExecStart=/usr/local/bin/gt daemon run",
+		"Restart=on-failure",
+		`Environment="PATH=/usr/local/bin:/usr/bin"`,
+		"StandardOutput=append:/home/u/gt/daemon/daemon.log",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("systemd unit missing %q\n%s", want, unit)
+		}
+	}
+
+	plist, err := RenderSupervisor("darwin", data)
+	if err != nil {
+		t.Fatalf("RenderSupervisor(darwin) error = %v", err)
+	}
+	for _, want := range []string{
+		"<string>/usr/local/bin/gt</string>",
+		"<key>PATH</key>",
+		"<string>/home/u/gt/daemon/daemon.log</string>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("launchd plist missing %q\n%s", want, plist)
+		}
+	}
+
+	// PATH is copied from the environment as it is, so it is escaped.
+	data.Path = `/opt/100%/bin:/opt/a&b<c>/bin:/opt/"q"\bin`
+	unit, err = RenderSupervisor("linux", data)
+	if err != nil {
+		t.Fatalf("RenderSupervisor(linux) error = %v", err)
+	}
+	if want := `Environment="PATH=/opt/100%%/bin:/opt/a&b<c>/bin:/opt/\"q\"\\bin"`; !strings.Contains(unit, want) {
+		t.Errorf("systemd unit missing escaped %q\n%s", want, unit)
+	}
+	plist, err = RenderSupervisor("darwin", data)
+	if err != nil {
+		t.Fatalf("RenderSupervisor(darwin) error = %v", err)
+	}
+	if err := xml.Unmarshal([]byte(plist), new(struct{})); err != nil {
+		t.Errorf("launchd plist is not valid XML: %v\n%s", err, plist)
+	}
+	if want := "<string>/opt/100%/bin:/opt/a&amp;b&lt;c&gt;/bin:"; !strings.Contains(plist, want) {
+		t.Errorf("launchd plist missing escaped %q\n%s", want, plist)
+	}
+
+	data.Path = ""
+	unit, err = RenderSupervisor("linux", data)
+	if err != nil {
+		t.Fatalf("RenderSupervisor(linux) error = %v", err)
+	}
+	if strings.Contains(unit, "PATH=") {
+		t.Errorf("systemd unit should omit PATH when empty\n%s", unit)
+	}
+
+	if _, err := RenderSupervisor("plan9", data); err == nil {
+		t.Error("RenderSupervisor(plan9) should fail")
+	}
+}
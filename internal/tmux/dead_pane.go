@@ -0,0 +1,81 @@
+package tmux
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrPaneAlive is returned by CaptureDeadPane for a pane whose process is
+// still running.
+var ErrPaneAlive = errors.New("pane is alive")
+
+// deadStatusWait bounds how long CaptureDeadPane waits for tmux to fill in
+// pane_dead_status, which can lag pane_dead right after the process exits.
+const deadStatusWait = time.Second
+
+// DeadPaneCapture is the last output of a pane whose process exited, taken
+// while remain-on-exit still keeps the pane around.
+type DeadPaneCapture struct {
+	Session  string
+	ExitCode int // -1 if unknown
+
+	// Lines are the pane's last lines, oldest first, without trailing
+	// blank lines or the "Pane is dead" notice tmux draws.
+	Lines []string
+}
+
+// Output returns the captured lines as one string.
+func (c *DeadPaneCapture) Output() string {
+	return strings.Join(c.Lines, "\n")
+}
+
+// CaptureDeadPane captures the last lines of a session's dead pane, for
+// crash forensics. The session must have remain-on-exit on (see
+// SetRemainOnExit, VerifyOptions.RemainOnExit and SetPaneDiedHook), or a
+// crashed pane is gone before it can be read. Returns ErrPaneAlive if the
+// pane's process is still running. The session is left as it is; callers
+// kill it once they have what they need.
+func (t *Tmux) CaptureDeadPane(session string, lines int) (*DeadPaneCapture, error) {
+	dead, code, err := deadPaneStatus(func() (bool, int, error) { return t.PaneDeadStatus(session) }, deadStatusWait)
+	if err != nil {
+		return nil, err
+	}
+	if !dead {
+		return nil, ErrPaneAlive
+	}
+	out, err := t.CapturePane(session, lines)
+	if err != nil {
+		return nil, err
+	}
+	return &DeadPaneCapture{Session: session, ExitCode: code, Lines: deadPaneLines(out)}, nil
+}
+
+// deadPaneStatus reads a pane's dead status, polling again for up to wait
+// while the pane is dead but its exit status is not set yet. A pane killed
+// by a signal never gets one; its code stays -1.
+func deadPaneStatus(status func() (bool, int, error), wait time.Duration) (bool, int, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		dead, code, err := status()
+		if err != nil || !dead || code >= 0 || !time.Now().Before(deadline) {
+			return dead, code, err
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// deadPaneLines splits captured output into lines, dropping the "Pane is
+// dead (status 1, <time>)" notice and trailing blank lines.
+func deadPaneLines(s string) []string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		if !strings.HasPrefix(line, "Pane is dead (") {
+			out = append(out, line)
+		}
+	}
+	for len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+		out = out[:len(out)-1]
+	}
+	return out
+}
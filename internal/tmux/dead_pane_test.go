@@ -0,0 +1,86 @@
+package tmux
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeadPaneLines(t *testing.T) {
+	got := deadPaneLines("working...\nError: boom\n\nPane is dead (status 3, Sat Oct 17 20:20:52 2026)\n\n")
+	want := []string{"working...", "Error: boom"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("deadPaneLines = %q, want %q", got, want)
+	}
+}
+
+func TestDeadPaneStatusWaitsForExitCode(t *testing.T) {
+	calls := 0
+	status := func() (bool, int, error) {
+		calls++
+		if calls < 3 {
+			return true, -1, nil // pane_dead set, pane_dead_status not yet
+		}
+		return true, 4, nil
+	}
+	if dead, code, err := deadPaneStatus(status, time.Minute); !dead || code != 4 || err != nil {
+		t.Errorf("deadPaneStatus = %v, %d, %v; want dead with exit 4", dead, code, err)
+	}
+
+	unknown := func() (bool, int, error) { return true, -1, nil }
+	if _, code, _ := deadPaneStatus(unknown, 0); code != -1 {
+		t.Errorf("deadPaneStatus without a status = %d, want -1", code)
+	}
+}
+
+// TestCaptureDeadPane verifies a session created with RemainOnExit keeps a
+// crashed pane for CaptureDeadPane.
+func TestCaptureDeadPane(t *testing.T) {
+	tm := newTestTmux(t)
+	session := "gt-test-deadpane-" + t.Name()
+	_ = tm.KillSession(session)
+	defer func() { _ = tm.KillSession(session) }()
+
+	// The command crashes once the test creates the release file, so the
+	// pane is known to be alive for the first capture.
+	release := filepath.Join(t.TempDir(), "release")
+	cmd := `sh -c 'while [ ! -e ` + release + ` ]; do sleep 0.05; done; echo "panic: out of cheese"; exit 4'`
+	opts := VerifyOptions{GracePeriod: 200 * time.Millisecond, RemainOnExit: true}
+	if err := tm.NewSessionWithCommandVerified(session, "", cmd, opts); err != nil {
+		t.Fatalf("NewSessionWithCommandVerified: %v", err)
+	}
+	if _, err := tm.CaptureDeadPane(session, 50); !errors.Is(err, ErrPaneAlive) {
+		t.Errorf("CaptureDeadPane(running) err = %v, want ErrPaneAlive", err)
+	}
+	if err := os.WriteFile(release, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var c *DeadPaneCapture
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		var err error
+		if c, err = tm.CaptureDeadPane(session, 50); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if c == nil {
+		t.Fatal("pane did not die, or was not kept")
+	}
+	if !strings.Contains(c.Output(), "out of cheese") {
+		t.Errorf("capture output = %q", c.Output())
+	}
+	// tmux (3.3 at least) can mark a pane dead without ever recording its
+	// exit status; CaptureDeadPane then reports it as unknown, not as code.
+	if c.ExitCode == -1 {
+		if _, code, _ := tm.PaneDeadStatus(session); code != -1 {
+			t.Errorf("capture = exit -1, but tmux reports %d", code)
+		}
+	} else if c.ExitCode != 4 {
+		t.Errorf("capture = exit %d, want 4", c.ExitCode)
+	}
+}
@@ -0,0 +1,298 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Message buffers hold nudges for an agent that is not ready for input yet
+// (still starting, or sitting on a welcome or trust dialog) and deliver them
+// in order once it is. NudgeSession types into the pane straight away, so a
+// nudge sent during startup can land before the agent reads its input and be
+// lost (gt-k8uxb).
+
+var (
+	// ErrMessageExpired is returned for a buffered message whose agent did
+	// not become ready before the message expired.
+	ErrMessageExpired = errors.New("agent not ready before the message expired")
+
+	// ErrPaneDead is returned when the agent's pane has exited, so buffered
+	// messages can never be delivered.
+	ErrPaneDead = errors.New("agent pane is dead")
+)
+
+// startupDialogMarkers is text of dialogs shown before the agent takes
+// input. Their option lists start with the prompt character, so seeing the
+// prompt prefix is not enough to call the agent ready.
+var startupDialogMarkers = []string{
+	"trust this folder",
+	"Quick safety check",
+	"Bypass Permissions mode",
+}
+
+// BufferConfig controls how a MessageBuffer detects readiness and retries.
+// The zero value uses the defaults.
+type BufferConfig struct {
+	// PromptPrefix is the agent's input prompt; "" = DefaultReadyPromptPrefix.
+	PromptPrefix string
+
+	// NoPrompt is set for agents without a detectable prompt: they count as
+	// ready once they are running and past any startup dialog.
+	NoPrompt bool
+
+	// PollInterval is how often readiness is checked.
+	// 0 = constants.NudgeBufferPollInterval.
+	PollInterval time.Duration
+
+	// Expiry is how long a message may wait in the buffer, from when it was
+	// queued. 0 = constants.NudgeBufferExpiry.
+	Expiry time.Duration
+
+	// MaxAttempts is how many deliveries are tried per message.
+	// 0 = constants.NudgeBufferAttempts.
+	MaxAttempts int
+}
+
+// BufferConfigFor returns the buffer config for an agent runtime.
+func BufferConfigFor(rc *config.RuntimeConfig) BufferConfig {
+	if rc == nil || rc.Tmux == nil {
+		return BufferConfig{}
+	}
+	if rc.Tmux.ReadyPromptPrefix == "" {
+		return BufferConfig{NoPrompt: true}
+	}
+	return BufferConfig{PromptPrefix: rc.Tmux.ReadyPromptPrefix}
+}
+
+func (c BufferConfig) withDefaults() BufferConfig {
+	if c.PromptPrefix == "" {
+		c.PromptPrefix = DefaultReadyPromptPrefix
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = constants.NudgeBufferPollInterval
+	}
+	if c.Expiry <= 0 {
+		c.Expiry = constants.NudgeBufferExpiry
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = constants.NudgeBufferAttempts
+	}
+	return c
+}
+
+// InputReady reports whether the agent in session is ready to take a nudge:
+// its pane runs the agent rather than a shell, shows no startup dialog, and
+// (unless cfg.NoPrompt) shows the input prompt. It returns ErrPaneDead or
+// ErrNoServer when delivery has become impossible; a session that does not
+// exist yet is only not ready.
+func (t *Tmux) InputReady(session string, cfg BufferConfig) (bool, error) {
+	cfg = cfg.withDefaults()
+	cmd, err := t.GetPaneCommand(session)
+	if err != nil {
+		if errors.Is(err, ErrNoServer) {
+			return false, err
+		}
+		return false, nil
+	}
+	if dead, _, _ := t.PaneDeadStatus(session); dead {
+		return false, ErrPaneDead
+	}
+	lines, err := t.CapturePaneLines(session, 15)
+	if err != nil {
+		return false, nil
+	}
+	return paneInputReady(cmd, lines, cfg), nil
+}
+
+// paneInputReady is InputReady's decision on a pane's command and its last
+// lines.
+func paneInputReady(cmd string, lines []string, cfg BufferConfig) bool {
+	for _, shell := range constants.SupportedShells {
+		if cmd == shell {
+			return false
+		}
+	}
+	content := strings.Join(lines, "\n")
+	for _, marker := range startupDialogMarkers {
+		if strings.Contains(content, marker) {
+			return false
+		}
+	}
+	if cfg.NoPrompt {
+		return true
+	}
+	for _, line := range lines {
+		if matchesPromptPrefix(line, cfg.PromptPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageBuffer is the delivery queue for one session. Messages are
+// delivered one at a time in the order sent, each waiting until the agent
+// is ready; after a delivery the agent is busy with it, so the next message
+// waits for the prompt to come back.
+type MessageBuffer struct {
+	session string
+	cfg     BufferConfig
+
+	// ready and deliver talk to tmux; tests replace them.
+	ready   func() (bool, error)
+	deliver func(msg string) error
+	now     func() time.Time
+	sleep   func(time.Duration)
+	onIdle  func()
+
+	mu       sync.Mutex
+	queue    []*bufferedMessage
+	draining bool
+}
+
+type bufferedMessage struct {
+	text     string
+	queuedAt time.Time
+	attempts int
+	done     chan error
+}
+
+// messageBuffers holds the live MessageBuffer per socket and session; a
+// buffer removes itself once it has drained.
+var messageBuffers sync.Map // map[string]*MessageBuffer
+
+// MessageBuffer returns the delivery queue for session, creating it with cfg
+// if there is none. A buffer that is already draining keeps its own config.
+func (t *Tmux) MessageBuffer(session string, cfg BufferConfig) *MessageBuffer {
+	key := t.socketName + "\x00" + session
+	b := newMessageBuffer(session, cfg,
+		func() (bool, error) { return t.InputReady(session, cfg) },
+		func(msg string) error { return t.NudgeSession(session, msg) })
+	actual, loaded := messageBuffers.LoadOrStore(key, b)
+	if !loaded {
+		b.onIdle = func() { messageBuffers.CompareAndDelete(key, b) }
+	}
+	return actual.(*MessageBuffer)
+}
+
+func newMessageBuffer(session string, cfg BufferConfig, ready func() (bool, error), deliver func(string) error) *MessageBuffer {
+	return &MessageBuffer{
+		session: session,
+		cfg:     cfg.withDefaults(),
+		ready:   ready,
+		deliver: deliver,
+		now:     time.Now,
+		sleep:   time.Sleep,
+	}
+}
+
+// Send queues msg and returns a channel that receives its outcome: nil once
+// delivered, ErrMessageExpired, or the error that made delivery fail.
+func (b *MessageBuffer) Send(msg string) <-chan error {
+	m := &bufferedMessage{text: msg, queuedAt: b.now(), done: make(chan error, 1)}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queue = append(b.queue, m)
+	if !b.draining {
+		b.draining = true
+		go b.drain()
+	}
+	return m.done
+}
+
+// Pending returns how many messages are waiting to be delivered.
+func (b *MessageBuffer) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.queue)
+}
+
+// head returns the next message to deliver, dropping expired ones, or nil
+// (and stops draining) when the queue is empty.
+func (b *MessageBuffer) head() *bufferedMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for len(b.queue) > 0 {
+		m := b.queue[0]
+		if b.now().Sub(m.queuedAt) < b.cfg.Expiry {
+			return m
+		}
+		b.queue = b.queue[1:]
+		m.done <- fmt.Errorf("nudge to %s: %w", b.session, ErrMessageExpired)
+	}
+	b.draining = false
+	if b.onIdle != nil {
+		b.onIdle()
+	}
+	return nil
+}
+
+// finish removes the head message and reports its outcome.
+func (b *MessageBuffer) finish(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m := b.queue[0]
+	b.queue = b.queue[1:]
+	m.done <- err
+}
+
+// failAll reports err for every queued message.
+func (b *MessageBuffer) failAll(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, m := range b.queue {
+		m.done <- fmt.Errorf("nudge to %s: %w", b.session, err)
+	}
+	b.queue = nil
+}
+
+func (b *MessageBuffer) drain() {
+	for {
+		m := b.head()
+		if m == nil {
+			return
+		}
+		ready, err := b.ready()
+		if err != nil {
+			b.failAll(err)
+			continue
+		}
+		if !ready {
+			b.sleep(b.cfg.PollInterval)
+			continue
+		}
+		m.attempts++
+		err = b.deliver(m.text)
+		if err == nil || m.attempts >= b.cfg.MaxAttempts {
+			b.finish(err)
+			continue
+		}
+		b.sleep(b.cfg.PollInterval)
+	}
+}
+
+// QueueNudge buffers a nudge to session until its agent is ready for input
+// and returns a channel that receives the outcome. Nudges NudgeGate refuses
+// are not queued.
+func (t *Tmux) QueueNudge(session, message string, cfg BufferConfig) <-chan error {
+	if NudgeGate != nil {
+		if err := NudgeGate(session); err != nil {
+			done := make(chan error, 1)
+			done <- err
+			return done
+		}
+	}
+	return t.MessageBuffer(session, cfg).Send(message)
+}
+
+// NudgeSessionWhenReady is QueueNudge that waits for the outcome. Use it
+// instead of NudgeSession for nudges sent while an agent may still be
+// starting.
+func (t *Tmux) NudgeSessionWhenReady(session, message string, cfg BufferConfig) error {
+	return <-t.QueueNudge(session, message, cfg)
+}
@@ -0,0 +1,151 @@
+package tmux
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock advances only when the buffer sleeps.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func testBuffer(cfg BufferConfig, ready func() (bool, error), deliver func(string) error) *MessageBuffer {
+	b := newMessageBuffer("gt-Toast", cfg, ready, deliver)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	b.now = clock.Now
+	b.sleep = clock.Sleep
+	return b
+}
+
+func TestMessageBuffer_WaitsForReadyAndKeepsOrder(t *testing.T) {
+	var mu sync.Mutex
+	checks := 0
+	var delivered []string
+	b := testBuffer(BufferConfig{},
+		func() (bool, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			checks++
+			return checks > 3, nil // still on the welcome screen for a while
+		},
+		func(msg string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			delivered = append(delivered, msg)
+			return nil
+		})
+
+	// Hold the queue so both messages are buffered before delivery starts.
+	b.mu.Lock()
+	b.draining = true
+	b.mu.Unlock()
+	first, second := b.Send("beacon"), b.Send("start work")
+	go b.drain()
+
+	if err := <-first; err != nil {
+		t.Fatalf("first: %v", err)
+	}
+	if err := <-second; err != nil {
+		t.Fatalf("second: %v", err)
+	}
+	if len(delivered) != 2 || delivered[0] != "beacon" || delivered[1] != "start work" {
+		t.Errorf("delivered %v, want beacon then start work", delivered)
+	}
+	if b.Pending() != 0 {
+		t.Errorf("Pending = %d after draining", b.Pending())
+	}
+}
+
+func TestMessageBuffer_Expires(t *testing.T) {
+	b := testBuffer(BufferConfig{Expiry: time.Minute},
+		func() (bool, error) { return false, nil },
+		func(string) error { t.Error("delivered to an agent that never became ready"); return nil })
+	if err := <-b.Send("hello"); !errors.Is(err, ErrMessageExpired) {
+		t.Errorf("err = %v, want ErrMessageExpired", err)
+	}
+}
+
+func TestMessageBuffer_RetriesThenFails(t *testing.T) {
+	attempts := 0
+	b := testBuffer(BufferConfig{MaxAttempts: 2},
+		func() (bool, error) { return true, nil },
+		func(string) error { attempts++; return errors.New("send-keys failed") })
+	if err := <-b.Send("hello"); err == nil {
+		t.Error("want the delivery error")
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+
+	attempts = 0
+	b = testBuffer(BufferConfig{},
+		func() (bool, error) { return true, nil },
+		func(string) error {
+			attempts++
+			if attempts == 1 {
+				return errors.New("not in a mode")
+			}
+			return nil
+		})
+	if err := <-b.Send("hello"); err != nil {
+		t.Errorf("retry should succeed: %v", err)
+	}
+}
+
+func TestMessageBuffer_DeadPaneFailsEverything(t *testing.T) {
+	b := testBuffer(BufferConfig{},
+		func() (bool, error) { return false, ErrPaneDead },
+		func(string) error { return nil })
+	b.mu.Lock()
+	b.draining = true
+	b.mu.Unlock()
+	first, second := b.Send("a"), b.Send("b")
+	go b.drain()
+	for _, done := range []<-chan error{first, second} {
+		if err := <-done; !errors.Is(err, ErrPaneDead) {
+			t.Errorf("err = %v, want ErrPaneDead", err)
+		}
+	}
+}
+
+func TestPaneInputReady(t *testing.T) {
+	claude := BufferConfig{}.withDefaults()
+	tests := []struct {
+		name  string
+		cmd   string
+		lines []string
+		cfg   BufferConfig
+		want  bool
+	}{
+		{"shell before the agent starts", "bash", []string{"$ "}, claude, false},
+		{"welcome screen", "claude", []string{"Welcome to Claude Code", "loading..."}, claude, false},
+		{"trust dialog lists options with the prompt char", "claude", []string{"Do you trust this folder?", "❯ 1. Yes, proceed"}, claude, false},
+		{"at the prompt", "claude", []string{"", "❯ ", "⏵⏵ bypass permissions on"}, claude, true},
+		{"NBSP after the prompt", "claude", []string{"❯ "}, claude, true},
+		{"no prompt detection, agent running", "codex", []string{"thinking"}, BufferConfig{NoPrompt: true}.withDefaults(), true},
+		{"no prompt detection, still a shell", "zsh", nil, BufferConfig{NoPrompt: true}.withDefaults(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := paneInputReady(tt.cmd, tt.lines, tt.cfg); got != tt.want {
+				t.Errorf("paneInputReady = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package tmux
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Terminal multiplexer backends (town settings "multiplexer").
+const (
+	BackendTmux   = "tmux"
+	BackendZellij = "zellij"
+	BackendScreen = "screen"
+)
+
+// ErrUnsupported is returned by tmux-only operations (themes, hooks, key
+// bindings, pane process inspection, ...) when the town runs its agents
+// under another multiplexer.
+var ErrUnsupported = errors.New("not supported by the configured terminal multiplexer")
+
+// Multiplexer is what Gas Town needs from a terminal multiplexer to run
+// agent sessions: create and kill them, find them, type into them, and
+// read what they show. *Tmux implements it, as do Zellij and Screen for
+// systems without tmux or users who prefer another multiplexer.
+//
+// A town selects its backend with the "multiplexer" town setting; NewTmux
+// then routes these operations to it (see SetDefaultBackend), so callers
+// keep using *Tmux.
+type Multiplexer interface {
+	// Name is the backend name, e.g. "tmux".
+	Name() string
+
+	// IsAvailable reports whether the multiplexer is installed.
+	IsAvailable() bool
+
+	// NewSessionWithCommand creates a detached session running command
+	// in workDir. An empty command runs the default shell.
+	NewSessionWithCommand(name, workDir, command string) error
+
+	HasSession(name string) (bool, error)
+	ListSessions() ([]string, error)
+	KillSession(name string) error
+
+	// SetEnvironment and GetEnvironment keep per-session variables such as
+	// GT_AGENT that gt reads back about a running session.
+	SetEnvironment(session, key, value string) error
+	GetEnvironment(session, key string) (string, error)
+
+	// SendKeys types keys into the session followed by Enter.
+	SendKeys(session, keys string) error
+
+	// NudgeSession delivers a message to the agent in the session.
+	NudgeSession(session, message string) error
+
+	// CapturePane returns the last lines of the session's output;
+	// CapturePaneAll returns its whole scrollback.
+	CapturePane(session string, lines int) (string, error)
+	CapturePaneAll(session string) (string, error)
+
+	// AttachCommand returns the command line that attaches a terminal to
+	// the session.
+	AttachCommand(session string) ([]string, error)
+}
+
+var _ Multiplexer = (*Tmux)(nil)
+
+// defaultBackend is the multiplexer NewTmux routes session operations to.
+// Empty means tmux.
+var defaultBackend string
+
+// SetDefaultBackend selects the multiplexer for Tmux wrappers created by
+// NewTmux afterwards. Called during init from the town's settings.
+func SetDefaultBackend(name string) error {
+	if _, err := NewMultiplexer(name); err != nil {
+		return err
+	}
+	if name == BackendTmux {
+		name = ""
+	}
+	defaultBackend = name
+	return nil
+}
+
+// DefaultBackend returns the name of the selected multiplexer.
+func DefaultBackend() string {
+	if defaultBackend == "" {
+		return BackendTmux
+	}
+	return defaultBackend
+}
+
+// NewMultiplexer returns the named backend; "" means tmux.
+func NewMultiplexer(name string) (Multiplexer, error) {
+	switch name {
+	case "", BackendTmux:
+		return NewTmuxWithSocket(defaultSocket), nil
+	case BackendZellij:
+		return NewZellij(), nil
+	case BackendScreen:
+		return NewScreen(), nil
+	}
+	return nil, fmt.Errorf("unknown terminal multiplexer %q (want %s, %s, or %s)", name, BackendTmux, BackendZellij, BackendScreen)
+}
+
+// alternateBackend returns the selected multiplexer if it is not tmux.
+func alternateBackend() Multiplexer {
+	if defaultBackend == "" {
+		return nil
+	}
+	m, err := NewMultiplexer(defaultBackend)
+	if err != nil {
+		return nil
+	}
+	return m
+}
+
+// sessionEnv stores per-session environment variables in files for
+// backends that cannot report a session's environment themselves.
+// Agents get their variables on the command line; this is what gt reads
+// back about a running session.
+type sessionEnv struct {
+	dir string
+}
+
+func newSessionEnv(backend string) sessionEnv {
+	return sessionEnv{dir: filepath.Join(os.TempDir(), fmt.Sprintf("gt-%s-env-%d", backend, os.Getuid()))}
+}
+
+func (e sessionEnv) path(session string) string {
+	return filepath.Join(e.dir, session+".json")
+}
+
+func (e sessionEnv) load(session string) map[string]string {
+	vars := map[string]string{}
+	data, err := os.ReadFile(e.path(session)) //nolint:gosec // G304: session names are validated
+	if err == nil {
+		_ = json.Unmarshal(data, &vars)
+	}
+	return vars
+}
+
+func (e sessionEnv) set(session, key, value string) error {
+	if err := validateSessionName(session); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(e.dir, 0700); err != nil {
+		return err
+	}
+	vars := e.load(session)
+	vars[key] = value
+	data, err := json.Marshal(vars)
+	if err != nil {
+		return err
+	}
+	tmp := e.path(session) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, e.path(session))
+}
+
+func (e sessionEnv) get(session, key string) (string, error) {
+	if err := validateSessionName(session); err != nil {
+		return "", err
+	}
+	value, ok := e.load(session)[key]
+	if !ok {
+		return "", fmt.Errorf("%s not set in session %s", key, session)
+	}
+	return value, nil
+}
+
+func (e sessionEnv) clear(session string) {
+	if validateSessionName(session) == nil {
+		_ = os.Remove(e.path(session))
+	}
+}
+
+// tailLines returns the last n lines of a screen dump, ignoring the blank
+// rows below the cursor. n <= 0 returns everything.
+func tailLines(dump string, n int) string {
+	lines := strings.Split(strings.TrimRight(dump, " \t\r\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \r")
+	}
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// captureVia runs a backend's screen dump command, which writes to a file,
+// and returns the file's contents.
+func captureVia(dump func(path string) error) (string, error) {
+	f, err := os.CreateTemp("", "gt-capture-*.txt")
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	_ = f.Close()
+	defer os.Remove(path)
+	if err := dump(path); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: temp file created above
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// checkWorkDir validates a new session's working directory.
+func checkWorkDir(workDir string) error {
+	if workDir == "" {
+		return nil
+	}
+	info, err := os.Stat(workDir)
+	if err != nil {
+		return fmt.Errorf("invalid work directory %q: %w", workDir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("work directory %q is not a directory", workDir)
+	}
+	return nil
+}
+
+// waitForBackendSession stands in for the pane-command waits under other
+// backends, which cannot see what a session runs: the session being up is
+// all there is to wait for.
+func (t *Tmux) waitForBackendSession(session string) error {
+	running, err := t.mux.HasSession(session)
+	if err != nil {
+		return err
+	}
+	if !running {
+		return ErrSessionNotFound
+	}
+	return nil
+}
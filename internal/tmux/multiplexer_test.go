@@ -0,0 +1,175 @@
+package tmux
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeMux records what Tmux routes to another backend.
+type fakeMux struct {
+	sessions map[string]bool
+	sent     []string
+	killed   []string
+}
+
+func (f *fakeMux) Name() string      { return "fake" }
+func (f *fakeMux) IsAvailable() bool { return true }
+func (f *fakeMux) NewSessionWithCommand(name, workDir, command string) error {
+	if command != "false" { // "false" exits at once, ending its session
+		f.sessions[name] = true
+	}
+	f.sent = append(f.sent, name+": "+command)
+	return nil
+}
+func (f *fakeMux) HasSession(name string) (bool, error) { return f.sessions[name], nil }
+func (f *fakeMux) ListSessions() ([]string, error) {
+	var names []string
+	for name := range f.sessions {
+		names = append(names, name)
+	}
+	return names, nil
+}
+func (f *fakeMux) KillSession(name string) error {
+	if !f.sessions[name] {
+		return ErrSessionNotFound
+	}
+	delete(f.sessions, name)
+	f.killed = append(f.killed, name)
+	return nil
+}
+func (f *fakeMux) SetEnvironment(session, key, value string) error    { return nil }
+func (f *fakeMux) GetEnvironment(session, key string) (string, error) { return "", nil }
+func (f *fakeMux) SendKeys(session, keys string) error {
+	f.sent = append(f.sent, session+": "+keys)
+	return nil
+}
+func (f *fakeMux) NudgeSession(session, message string) error { return f.SendKeys(session, message) }
+func (f *fakeMux) CapturePane(session string, lines int) (string, error) {
+	return "output", nil
+}
+func (f *fakeMux) CapturePaneAll(session string) (string, error) { return "output", nil }
+func (f *fakeMux) AttachCommand(session string) ([]string, error) {
+	return []string{"fake", "attach", session}, nil
+}
+
+func TestTmuxRoutesToBackend(t *testing.T) {
+	fake := &fakeMux{sessions: map[string]bool{}}
+	tm := &Tmux{mux: fake}
+
+	opts := VerifyOptions{GracePeriod: 50 * time.Millisecond, PollInterval: 10 * time.Millisecond, RemainOnExit: true}
+	if err := tm.NewSessionWithCommandVerified("gt-gp-Toast", "", "claude", opts); err != nil {
+		t.Fatal(err)
+	}
+	var exitErr *CommandExitError
+	if err := tm.NewSessionWithCommandVerified("gt-gp-Nux", "", "false", opts); !errors.As(err, &exitErr) {
+		t.Errorf("verified start of a dying command = %v, want CommandExitError", err)
+	}
+	if running, _ := tm.HasSession("gt-gp-Toast"); !running || !tm.IsAgentAlive("gt-gp-Toast") {
+		t.Error("session created through the backend is not running")
+	}
+	if err := tm.WaitForCommand("gt-gp-Toast", nil, 0); err != nil {
+		t.Errorf("WaitForCommand = %v", err)
+	}
+	if err := tm.NudgeSession("gt-gp-Toast", "check mail"); err != nil {
+		t.Fatal(err)
+	}
+	if out, _ := tm.CapturePane("gt-gp-Toast", 10); out != "output" {
+		t.Errorf("CapturePane = %q", out)
+	}
+	if err := tm.KillSessionWithProcesses("gt-gp-Toast"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.KillSession("gt-gp-Toast"); err != nil {
+		t.Errorf("killing a gone session = %v, want nil", err)
+	}
+
+	want := []string{"gt-gp-Toast: claude", "gt-gp-Nux: false", "gt-gp-Toast: check mail"}
+	if !reflect.DeepEqual(fake.sent, want) || !reflect.DeepEqual(fake.killed, []string{"gt-gp-Toast"}) {
+		t.Errorf("sent %q, killed %q", fake.sent, fake.killed)
+	}
+	if err := tm.SetRemainOnExit("gt-gp-Toast", true); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("tmux-only operation = %v, want ErrUnsupported", err)
+	}
+	if argv, _ := tm.AttachCommand("gt-gp-Toast"); argv[0] != "fake" {
+		t.Errorf("AttachCommand = %q", argv)
+	}
+}
+
+func TestSetDefaultBackend(t *testing.T) {
+	defer func() { _ = SetDefaultBackend("") }()
+
+	if err := SetDefaultBackend("byobu"); err == nil {
+		t.Error("unknown backend accepted")
+	}
+	if err := SetDefaultBackend(BackendZellij); err != nil {
+		t.Fatal(err)
+	}
+	if got := NewTmux().Name(); got != BackendZellij {
+		t.Errorf("NewTmux().Name() = %q, want zellij", got)
+	}
+	if got := NewTmuxWithSocket("test").Name(); got != BackendTmux {
+		t.Errorf("NewTmuxWithSocket().Name() = %q, want tmux", got)
+	}
+	if err := SetDefaultBackend(BackendTmux); err != nil || DefaultBackend() != BackendTmux || NewTmux().mux != nil {
+		t.Errorf("switching back to tmux: %v, %q", err, DefaultBackend())
+	}
+}
+
+func TestParseScreenSessions(t *testing.T) {
+	out := "There are screens on:\n" +
+		"\t4242.gt-deacon-boot\t(10/18/26 09:00:00)\t(Detached)\n" +
+		"\t4243.gt-deacon\t(Detached)\n" +
+		"2 Sockets in /run/screen/S-gt.\n"
+	got := parseScreenSessions(out)
+	want := []screenSession{{id: "4242.gt-deacon-boot", name: "gt-deacon-boot"}, {id: "4243.gt-deacon", name: "gt-deacon"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseScreenSessions = %+v, want %+v", got, want)
+	}
+	if got := parseScreenSessions("No Sockets found in /run/screen/S-gt.\n"); got != nil {
+		t.Errorf("no sessions parsed as %+v", got)
+	}
+}
+
+func TestParseZellijSessions(t *testing.T) {
+	out := "hq-mayor [Created 2m ago]\n" +
+		"gt-gp-Toast [Created 10s ago] (current)\n" +
+		"gt-gp-Nux [Created 1h ago] (EXITED - attach to resurrect)\n"
+	if got, want := parseZellijSessions(out), []string{"hq-mayor", "gt-gp-Toast"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseZellijSessions = %q, want %q", got, want)
+	}
+}
+
+func TestScreenEscape(t *testing.T) {
+	if got, want := screenEscape(`cost $5 ^C \n`), `cost \$5 \^C \\n`; got != want {
+		t.Errorf("screenEscape = %q, want %q", got, want)
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	dump := "one\ntwo  \nthree\n\n   \n\n"
+	if got := tailLines(dump, 2); got != "two\nthree" {
+		t.Errorf("tailLines(2) = %q", got)
+	}
+	if got := tailLines(dump, 0); got != "one\ntwo\nthree" {
+		t.Errorf("tailLines(0) = %q", got)
+	}
+}
+
+func TestSessionEnv(t *testing.T) {
+	env := sessionEnv{dir: t.TempDir()}
+	if err := env.set("gt-gp-Toast", "GT_AGENT", "claude"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := env.get("gt-gp-Toast", "GT_AGENT"); err != nil || v != "claude" {
+		t.Errorf("get = %q, %v", v, err)
+	}
+	if err := env.set("../x", "K", "v"); err == nil {
+		t.Error("invalid session name accepted")
+	}
+	env.clear("gt-gp-Toast")
+	if _, err := env.get("gt-gp-Toast", "GT_AGENT"); err == nil {
+		t.Error("variable survived clear")
+	}
+}
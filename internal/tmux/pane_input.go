@@ -0,0 +1,169 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// ErrPaneBusy is returned for a nudge the pane could not take: a dialog is
+// waiting for an answer or a full-screen program owns the screen, and it
+// did not clear in time. The message was not typed; deliver it later.
+var ErrPaneBusy = errors.New("pane is not taking input")
+
+// PaneBusyError reports why a nudge was deferred.
+type PaneBusyError struct {
+	Target string
+	Reason string
+}
+
+func (e *PaneBusyError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Target, ErrPaneBusy, e.Reason)
+}
+
+// Unwrap returns ErrPaneBusy.
+func (e *PaneBusyError) Unwrap() error {
+	return ErrPaneBusy
+}
+
+// PaneInputState is what a pane would do with keys typed into it now.
+type PaneInputState struct {
+	// CopyMode is set while the pane is in copy or view mode, including
+	// when it was scrolled back with the mouse: tmux takes the keys and the
+	// program never sees them.
+	CopyMode bool
+
+	// ScrollPosition is how many lines the pane is scrolled back in copy
+	// mode.
+	ScrollPosition int
+
+	// AlternateScreen is set while a full-screen program has the pane's
+	// alternate screen: an editor or pager, or a full-screen agent TUI.
+	AlternateScreen bool
+
+	// Command is the pane's foreground command.
+	Command string
+
+	// Dialog is the prompt of a dialog waiting for an answer (a startup
+	// dialog or a permission prompt), or "" if there is none. Text typed
+	// into a dialog picks its options rather than reaching the agent.
+	Dialog string
+}
+
+// paneInputLines is how much of the pane is checked for a dialog.
+const paneInputLines = 15
+
+// fullScreenPrograms are programs that take over the alternate screen and
+// would interpret a nudge as commands: editors and pagers opened by the
+// agent or by a human in its pane. Agent TUIs also use the alternate
+// screen, so the screen alone does not make a pane busy.
+var fullScreenPrograms = map[string]bool{
+	"vi": true, "vim": true, "nvim": true, "nano": true, "emacs": true,
+	"less": true, "more": true, "man": true, "view": true,
+	"top": true, "htop": true, "watch": true,
+}
+
+// PaneInputState reads the input state of a pane or session.
+func (t *Tmux) PaneInputState(target string) (PaneInputState, error) {
+	out, err := t.run("display-message", "-p", "-t", target,
+		"#{pane_in_mode}\t#{scroll_position}\t#{alternate_on}\t#{pane_current_command}")
+	if err != nil {
+		return PaneInputState{}, err
+	}
+	state := parsePaneInputState(out)
+	if lines, err := t.CapturePaneLines(target, paneInputLines); err == nil {
+		state.Dialog = pendingDialog(lines)
+	}
+	return state, nil
+}
+
+// parsePaneInputState parses PaneInputState's display-message output.
+func parsePaneInputState(out string) PaneInputState {
+	fields := strings.Split(strings.TrimRight(out, "\n"), "\t")
+	for len(fields) < 4 {
+		fields = append(fields, "")
+	}
+	scroll, _ := strconv.Atoi(strings.TrimSpace(fields[1]))
+	return PaneInputState{
+		CopyMode:        strings.TrimSpace(fields[0]) == "1",
+		ScrollPosition:  scroll,
+		AlternateScreen: strings.TrimSpace(fields[2]) == "1",
+		Command:         strings.TrimSpace(fields[3]),
+	}
+}
+
+// pendingDialog returns the prompt of a dialog shown in a pane's last
+// lines: a startup dialog, or a question followed by a numbered option
+// list (Claude Code's permission prompts).
+func pendingDialog(lines []string) string {
+	question := ""
+	for _, line := range lines {
+		text := strings.Trim(line, dialogBorder)
+		for _, marker := range startupDialogMarkers {
+			if strings.Contains(text, marker) {
+				return text
+			}
+		}
+		if strings.Contains(text, "Do you want to") {
+			question = text
+			continue
+		}
+		if question != "" && isFirstDialogOption(text) {
+			return question
+		}
+	}
+	return ""
+}
+
+// dialogBorder is what surrounds dialog text: spaces and the box's sides.
+const dialogBorder = " \t\u00a0│┃|"
+
+// isFirstDialogOption reports whether a line is the first entry of a
+// numbered option list, selected ("❯ 1. Yes") or not ("1. Yes").
+func isFirstDialogOption(text string) bool {
+	text = strings.TrimSpace(strings.TrimPrefix(strings.Trim(text, dialogBorder), "❯"))
+	return strings.HasPrefix(text, "1. ")
+}
+
+// Blocker returns why a nudge typed now would not reach the agent, or ""
+// if it would. Copy mode is not a blocker: the nudge exits it.
+func (s PaneInputState) Blocker() string {
+	if s.Dialog != "" {
+		return "dialog waiting for an answer: " + s.Dialog
+	}
+	if s.AlternateScreen && fullScreenPrograms[s.Command] {
+		return s.Command + " is open full-screen"
+	}
+	return ""
+}
+
+// preparePaneForInput gets a pane ready to take a nudge: it exits copy
+// mode, and waits up to constants.NudgeDeferTimeout for a dialog or
+// full-screen program to go away. It returns a *PaneBusyError if one is
+// still there. A pane whose state cannot be read is assumed ready, so
+// delivery fails (and is retried) the way it always has.
+func (t *Tmux) preparePaneForInput(target string) error {
+	deadline := time.Now().Add(constants.NudgeDeferTimeout)
+	for {
+		state, err := t.PaneInputState(target)
+		if err != nil {
+			return nil
+		}
+		if state.CopyMode {
+			_, _ = t.run("send-keys", "-t", target, "-X", "cancel")
+			time.Sleep(50 * time.Millisecond)
+		}
+		reason := state.Blocker()
+		if reason == "" {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return &PaneBusyError{Target: target, Reason: reason}
+		}
+		time.Sleep(constants.NudgeDeferPollInterval)
+	}
+}
@@ -0,0 +1,96 @@
+package tmux
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParsePaneInputState(t *testing.T) {
+	got := parsePaneInputState("1\t42\t0\tclaude\n")
+	want := PaneInputState{CopyMode: true, ScrollPosition: 42, Command: "claude"}
+	if got != want {
+		t.Errorf("parsePaneInputState = %+v, want %+v", got, want)
+	}
+	if got := parsePaneInputState(""); got != (PaneInputState{}) {
+		t.Errorf("empty output = %+v", got)
+	}
+}
+
+func TestPendingDialog(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{"idle prompt", []string{"⏺ Done.", "", "❯ "}, ""},
+		{"permission prompt", []string{
+			"╭──────────────────╮",
+			"│ Bash command     │",
+			"│ Do you want to proceed?",
+			"│ ❯ 1. Yes",
+			"│   2. No",
+		}, "Do you want to proceed?"},
+		{"question without options", []string{"Do you want to see the diff first?", "❯ "}, ""},
+		{"trust dialog", []string{"Quick safety check", " ❯ 1. Yes, I trust this folder"}, "Quick safety check"},
+	}
+	for _, tt := range tests {
+		if got := pendingDialog(tt.lines); got != tt.want {
+			t.Errorf("%s: pendingDialog = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPaneInputState_Blocker(t *testing.T) {
+	tests := []struct {
+		state   PaneInputState
+		blocked bool
+	}{
+		{PaneInputState{Command: "claude"}, false},
+		{PaneInputState{CopyMode: true, ScrollPosition: 10, Command: "claude"}, false},
+		{PaneInputState{AlternateScreen: true, Command: "opencode"}, false},
+		{PaneInputState{AlternateScreen: true, Command: "vim"}, true},
+		{PaneInputState{Command: "claude", Dialog: "Do you want to proceed?"}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.state.Blocker() != ""; got != tt.blocked {
+			t.Errorf("%+v: blocked = %v, want %v", tt.state, got, tt.blocked)
+		}
+	}
+	err := error(&PaneBusyError{Target: "%1", Reason: "vim is open full-screen"})
+	if !errors.Is(err, ErrPaneBusy) {
+		t.Errorf("%v does not wrap ErrPaneBusy", err)
+	}
+}
+
+// TestPreparePaneForInput_ExitsCopyMode verifies a pane scrolled back in
+// copy mode is reported as such and returned to normal before a nudge.
+func TestPreparePaneForInput_ExitsCopyMode(t *testing.T) {
+	tm := newTestTmux(t)
+	session := "gt-test-paneinput-" + t.Name()
+	_ = tm.KillSession(session)
+	if err := tm.NewSession(session, os.TempDir()); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer func() { _ = tm.KillSession(session) }()
+	time.Sleep(200 * time.Millisecond)
+
+	if _, err := tm.run("copy-mode", "-t", session); err != nil {
+		t.Fatalf("copy-mode: %v", err)
+	}
+	state, err := tm.PaneInputState(session)
+	if err != nil {
+		t.Fatalf("PaneInputState: %v", err)
+	}
+	if !state.CopyMode || state.Blocker() != "" {
+		t.Fatalf("state = %+v, want copy mode and no blocker", state)
+	}
+
+	if err := tm.preparePaneForInput(session); err != nil {
+		t.Fatalf("preparePaneForInput: %v", err)
+	}
+	if state, _ := tm.PaneInputState(session); state.CopyMode {
+		t.Errorf("pane still in copy mode: %+v", state)
+	}
+}
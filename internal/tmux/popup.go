@@ -0,0 +1,225 @@
+package tmux
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Popups show operator prompts in a display-popup over whatever the user is
+// looking at in their attached tmux client, so gastown processes without a
+// terminal of their own (agents, the daemon) can still ask or tell the
+// operator something.
+
+var (
+	// ErrNoClient is returned when no tmux client is attached to show a
+	// popup on.
+	ErrNoClient = errors.New("no attached tmux client")
+
+	// ErrPopupTimeout is returned when a confirmation popup goes unanswered.
+	ErrPopupTimeout = errors.New("popup not answered")
+)
+
+// Popup describes where and how a popup is shown.
+type Popup struct {
+	Title  string
+	Client string // client tty; "" = the most recently active client
+	Width  string // columns or percentage; "" = 60%
+	Height string // lines or percentage; "" = fit the text
+}
+
+// ActiveClient returns the tty of the attached client with the most recent
+// activity.
+func (t *Tmux) ActiveClient() (string, error) {
+	out, err := t.run("list-clients", "-F", "#{client_activity}\t#{client_tty}")
+	if err != nil {
+		if errors.Is(err, ErrNoServer) {
+			return "", ErrNoClient
+		}
+		return "", err
+	}
+	return mostActiveClient(out)
+}
+
+// mostActiveClient picks the client tty with the latest activity from
+// list-clients output.
+func mostActiveClient(out string) (string, error) {
+	var best string
+	var bestActivity int64 = -1
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		activity, tty, ok := strings.Cut(line, "\t")
+		if !ok || tty == "" {
+			continue
+		}
+		n, _ := strconv.ParseInt(activity, 10, 64)
+		if n > bestActivity {
+			best, bestActivity = tty, n
+		}
+	}
+	if best == "" {
+		return "", ErrNoClient
+	}
+	return best, nil
+}
+
+// popupStartGrace is how long ShowPopup waits for display-popup to fail
+// before taking the popup to be up.
+const popupStartGrace = 500 * time.Millisecond
+
+// ShowPopup shows text in a popup until the operator presses Enter. It
+// returns once the popup is up; the popup outlives the calling process.
+func (t *Tmux) ShowPopup(p Popup, text string) error {
+	dir, err := writePopupText(text)
+	if err != nil {
+		return err
+	}
+	script := fmt.Sprintf(`cat %[1]s/text; printf '\n\033[2m[Enter to close]\033[0m'; read -r _; rm -rf %[1]s`, shellQuote(dir))
+	args, err := t.popupArgs(p, text, 1, script)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return err
+	}
+
+	// display-popup only returns when the popup closes, so start it and
+	// report only errors that show up straight away.
+	allArgs := []string{"-u"}
+	if t.socketName != "" {
+		allArgs = append(allArgs, "-L", t.socketName)
+	}
+	cmd := exec.Command("tmux", append(allArgs, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		_ = os.RemoveAll(dir)
+		return fmt.Errorf("tmux display-popup: %w", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return t.wrapError(err, stderr.String(), args)
+		}
+	case <-time.After(popupStartGrace):
+	}
+	return nil
+}
+
+// ConfirmPopup asks the operator a yes/no question in a popup and waits up
+// to timeout for the answer. An unanswered popup is closed and
+// ErrPopupTimeout returned.
+func (t *Tmux) ConfirmPopup(p Popup, question string, timeout time.Duration) (bool, error) {
+	dir, err := writePopupText(question)
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(dir)
+
+	answer := filepath.Join(dir, "answer")
+	script := confirmScript(dir)
+
+	if p.Client == "" {
+		if p.Client, err = t.ActiveClient(); err != nil {
+			return false, err
+		}
+	}
+	args, err := t.popupArgs(p, question, 2, script)
+	if err != nil {
+		return false, err
+	}
+
+	// display-popup returns when the popup closes; the answer is read from
+	// the file so the wait can time out.
+	opened := make(chan error, 1)
+	go func() {
+		_, err := t.run(args...)
+		opened <- err
+	}()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	tick := time.NewTicker(200 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		select {
+		case err := <-opened:
+			if err != nil {
+				return false, err
+			}
+			opened = nil
+		case <-tick.C:
+			if data, err := os.ReadFile(answer); err == nil {
+				a := strings.ToLower(strings.TrimSpace(string(data)))
+				return a == "y" || a == "yes", nil
+			}
+		case <-deadline.C:
+			_, _ = t.run("display-popup", "-C", "-c", p.Client)
+			return false, ErrPopupTimeout
+		}
+	}
+}
+
+// confirmScript is the shell script ConfirmPopup runs: it prints dir/text,
+// reads the answer and writes it to dir/answer, or "n" if the popup is
+// closed first. dir is quoted once into a variable, so the trap, itself a
+// quoted string, never has to nest its quoting.
+func confirmScript(dir string) string {
+	return `d=` + shellQuote(dir) + `; trap 'echo n > "$d/answer"; exit' HUP INT TERM; cat "$d/text"; ` +
+		`printf '\n\nProceed? [y/N] '; read -r a; echo "$a" > "$d/answer.tmp" && mv "$d/answer.tmp" "$d/answer"`
+}
+
+// popupArgs returns the display-popup command running script in a popup
+// sized for text plus extra lines.
+func (t *Tmux) popupArgs(p Popup, text string, extra int, script string) ([]string, error) {
+	if !t.Supports(FeaturePopup) {
+		v, _ := DetectVersion()
+		return nil, fmt.Errorf("%w: popups need tmux 3.2, found %s", ErrTmuxTooOld, v)
+	}
+	if p.Client == "" {
+		client, err := t.ActiveClient()
+		if err != nil {
+			return nil, err
+		}
+		p.Client = client
+	}
+	width, height := p.Width, p.Height
+	if width == "" {
+		width = "60%"
+	}
+	if height == "" {
+		// Text lines, the prompt, and the border.
+		height = strconv.Itoa(strings.Count(strings.TrimRight(text, "\n"), "\n") + 1 + extra + 3)
+	}
+	args := []string{"display-popup", "-E", "-c", p.Client, "-w", width, "-h", height}
+	// Popup titles (-T) arrived in tmux 3.3.
+	if v, err := DetectVersion(); p.Title != "" && (err != nil || v.AtLeast(3, 3)) {
+		args = append(args, "-T", " "+strings.ReplaceAll(p.Title, "#", "##")+" ")
+	}
+	return append(args, "sh -c "+shellQuote(script)), nil
+}
+
+// writePopupText stores text in a fresh temporary directory for a popup
+// script to print, so it never passes through shell quoting.
+func writePopupText(text string) (string, error) {
+	dir, err := os.MkdirTemp("", "gt-popup-")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "text"), []byte(text), 0600); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// shellQuote single-quotes s for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,66 @@
+package tmux
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestMostActiveClient(t *testing.T) {
+	out := "1700000100\t/dev/pts/3\n1700000200\t/dev/pts/7\n1700000150\t/dev/pts/1\n"
+	got, err := mostActiveClient(out)
+	if err != nil || got != "/dev/pts/7" {
+		t.Errorf("mostActiveClient = %q, %v; want /dev/pts/7", got, err)
+	}
+	if _, err := mostActiveClient(""); !errors.Is(err, ErrNoClient) {
+		t.Errorf("no clients: err = %v, want ErrNoClient", err)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got, want := shellQuote("it's /tmp/x"), `'it'\''s /tmp/x'`; got != want {
+		t.Errorf("shellQuote = %s, want %s", got, want)
+	}
+}
+
+func TestConfirmScriptQuotesDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "it's a dir")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "text"), []byte("Stop the rig?"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("sh", "-c", confirmScript(dir))
+	cmd.Stdin = strings.NewReader("y\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("script failed: %v\n%s", err, out)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "answer")); err != nil || strings.TrimSpace(string(data)) != "y" {
+		t.Errorf("answer = %q, %v; want y", data, err)
+	}
+
+	// Closing the popup before answering runs the trap, which answers no.
+	_ = os.Remove(filepath.Join(dir, "answer"))
+	cmd = exec.Command("sh", "-c", confirmScript(dir))
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	_ = cmd.Process.Signal(syscall.SIGHUP)
+	_ = cmd.Wait()
+	if data, err := os.ReadFile(filepath.Join(dir, "answer")); err != nil || strings.TrimSpace(string(data)) != "n" {
+		t.Errorf("answer after hangup = %q, %v; want n", data, err)
+	}
+}
@@ -0,0 +1,182 @@
+package tmux
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Screen runs sessions under GNU screen. Each session's command is the
+// process of its first window; the session ends when the command exits.
+type Screen struct {
+	env sessionEnv
+}
+
+// NewScreen returns the GNU screen backend.
+func NewScreen() *Screen {
+	return &Screen{env: newSessionEnv(BackendScreen)}
+}
+
+var _ Multiplexer = (*Screen)(nil)
+
+func (s *Screen) Name() string { return BackendScreen }
+
+func (s *Screen) IsAvailable() bool {
+	_, err := exec.LookPath("screen")
+	return err == nil
+}
+
+// run executes a screen command and returns its combined output.
+func (s *Screen) run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("screen", args...)
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	return strings.TrimSpace(out.String()), err
+}
+
+// target resolves a session name to screen's "pid.name" form. Plain names
+// are prefix-matched by screen, so "gt-deacon" would be ambiguous with
+// "gt-deacon-boot".
+func (s *Screen) target(name string) (string, error) {
+	out, _ := s.run("", "-ls")
+	for _, sess := range parseScreenSessions(out) {
+		if sess.name == name {
+			return sess.id, nil
+		}
+	}
+	return "", ErrSessionNotFound
+}
+
+// command runs a screen command in a session's first window.
+func (s *Screen) command(session string, args ...string) error {
+	id, err := s.target(session)
+	if err != nil {
+		return err
+	}
+	out, err := s.run("", append([]string{"-S", id, "-p", "0", "-X"}, args...)...)
+	if err != nil {
+		return fmt.Errorf("screen %s: %v: %s", args[0], err, out)
+	}
+	return nil
+}
+
+func (s *Screen) NewSessionWithCommand(name, workDir, command string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	if err := checkWorkDir(workDir); err != nil {
+		return err
+	}
+	if running, _ := s.HasSession(name); running {
+		return ErrSessionExists
+	}
+	s.env.clear(name)
+
+	args := []string{"-dmS", name}
+	if command != "" {
+		args = append(args, "sh", "-c", command)
+	}
+	if out, err := s.run(workDir, args...); err != nil {
+		return fmt.Errorf("screen: %v: %s", err, out)
+	}
+	return nil
+}
+
+func (s *Screen) HasSession(name string) (bool, error) {
+	_, err := s.target(name)
+	return err == nil, nil
+}
+
+func (s *Screen) ListSessions() ([]string, error) {
+	// screen -ls exits non-zero whether or not there are sessions; the
+	// output says which.
+	out, _ := s.run("", "-ls")
+	var names []string
+	for _, sess := range parseScreenSessions(out) {
+		names = append(names, sess.name)
+	}
+	return names, nil
+}
+
+type screenSession struct {
+	id   string // "pid.name"
+	name string
+}
+
+// parseScreenSessions parses `screen -ls` output, whose session lines look
+// like "\t12345.gt-greenplace-Toast\t(Detached)".
+func parseScreenSessions(out string) []screenSession {
+	var sessions []screenSession
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, "\t") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		pid, name, ok := strings.Cut(fields[0], ".")
+		if !ok || pid == "" || strings.Trim(pid, "0123456789") != "" {
+			continue
+		}
+		sessions = append(sessions, screenSession{id: fields[0], name: name})
+	}
+	return sessions
+}
+
+func (s *Screen) KillSession(name string) error {
+	defer s.env.clear(name)
+	return s.command(name, "quit")
+}
+
+func (s *Screen) SetEnvironment(session, key, value string) error {
+	return s.env.set(session, key, value)
+}
+
+func (s *Screen) GetEnvironment(session, key string) (string, error) {
+	return s.env.get(session, key)
+}
+
+func (s *Screen) SendKeys(session, keys string) error {
+	if err := s.command(session, "stuff", screenEscape(keys)); err != nil {
+		return err
+	}
+	return s.command(session, "stuff", `\015`)
+}
+
+// screenEscape protects text from screen's string processing, which
+// expands $VARIABLES and treats backslash and caret sequences as escapes.
+func screenEscape(text string) string {
+	r := strings.NewReplacer(`\`, `\\`, `^`, `\^`, `$`, `\$`)
+	return r.Replace(text)
+}
+
+func (s *Screen) NudgeSession(session, message string) error {
+	return s.SendKeys(session, sanitizeNudgeMessage(message))
+}
+
+func (s *Screen) CapturePane(session string, lines int) (string, error) {
+	dump, err := captureVia(func(path string) error {
+		return s.command(session, "hardcopy", "-h", path)
+	})
+	if err != nil {
+		return "", err
+	}
+	return tailLines(dump, lines), nil
+}
+
+func (s *Screen) CapturePaneAll(session string) (string, error) {
+	return s.CapturePane(session, 0)
+}
+
+func (s *Screen) AttachCommand(session string) ([]string, error) {
+	id, err := s.target(session)
+	if err != nil {
+		return nil, err
+	}
+	return []string{"screen", "-r", id}, nil
+}
@@ -1,6 +1,7 @@
 package tmux
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -152,13 +153,26 @@ func TestWaitForCommand_Timeout(t *testing.T) {
 	_ = tm.KillSession(session)
 	defer func() { _ = tm.KillSession(session) }()
 
-	if err := tm.NewSessionWithCommand(session, "", "bash"); err != nil {
+	if err := tm.NewSessionWithCommand(session, "", "bash --norc --noprofile"); err != nil {
 		t.Fatalf("session creation: %v", err)
 	}
 
 	err := tm.WaitForCommand(session, []string{"bash", "zsh", "sh"}, 500*time.Millisecond)
 	if err == nil {
-		t.Error("WaitForCommand should timeout when shell is still running")
+		t.Fatal("WaitForCommand should timeout when shell is still running")
+	}
+	d, ok := DiagnoseWait(err)
+	if !ok {
+		t.Fatalf("timeout error %v carries no diagnosis", err)
+	}
+	if !errors.Is(err, ErrWaitTimeout) {
+		t.Errorf("errors.Is(%v, ErrWaitTimeout) = false", err)
+	}
+	if d.Cause != WaitStillAtShell || d.Command != "bash" {
+		t.Errorf("diagnosis = %s (command %q, processes %v), want %s (bash)", d.Cause, d.Command, d.Processes, WaitStillAtShell)
+	}
+	if len(d.Observations) == 0 || d.Observations[0].Command != "bash" {
+		t.Errorf("observations = %+v, want the bash pane", d.Observations)
 	}
 }
 
@@ -215,3 +229,70 @@ func TestSendMessageToTarget_Chunking(t *testing.T) {
 		t.Errorf("expected ~600 A's in output, got %d (message may have been truncated)", count)
 	}
 }
+
+// TestNewSessionWithCommandVerified_BadBinary verifies the verified variant
+// catches the missing binary that the brief check can miss, with output.
+func TestNewSessionWithCommandVerified_BadBinary(t *testing.T) {
+	tm := newTestTmux(t)
+	session := "gt-test-verified-bad-" + t.Name()
+	_ = tm.KillSession(session)
+	defer func() { _ = tm.KillSession(session) }()
+
+	err := tm.NewSessionWithCommandVerified(session, "", "/nonexistent/binary --flag", VerifyOptions{GracePeriod: time.Second})
+	var exitErr *CommandExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("err = %v, want *CommandExitError", err)
+	}
+	if !errors.Is(err, ErrSessionDied) {
+		t.Error("error should match ErrSessionDied")
+	}
+	if exitErr.Status == "0" {
+		t.Errorf("Status = %q, want non-zero", exitErr.Status)
+	}
+	if has, _ := tm.HasSession(session); has {
+		t.Error("dead session should have been cleaned up")
+	}
+}
+
+// TestNewSessionWithCommandVerified_CleanExit verifies a command that exits
+// cleanly within the grace period is still an error, carrying its output.
+func TestNewSessionWithCommandVerified_CleanExit(t *testing.T) {
+	tm := newTestTmux(t)
+	session := "gt-test-verified-exit-" + t.Name()
+	_ = tm.KillSession(session)
+	defer func() { _ = tm.KillSession(session) }()
+
+	err := tm.NewSessionWithCommandVerified(session, "", `sh -c 'sleep 0.3; echo "EARLY_EXIT"'`, VerifyOptions{GracePeriod: 2 * time.Second})
+	var exitErr *CommandExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("err = %v, want *CommandExitError", err)
+	}
+	// tmux (3.3 at least) can mark a pane dead without ever recording its
+	// exit status; that is reported as unknown, never as a made-up code.
+	if exitErr.Status != "0" && exitErr.Status != ExitStatusUnknown {
+		t.Errorf("Status = %q, want 0", exitErr.Status)
+	}
+	if !strings.Contains(exitErr.Output, "EARLY_EXIT") {
+		t.Errorf("Output = %q, want the pane output", exitErr.Output)
+	}
+}
+
+// TestNewSessionWithCommandVerified_Success verifies a command that outlives
+// the grace period starts normally, with remain-on-exit restored.
+func TestNewSessionWithCommandVerified_Success(t *testing.T) {
+	tm := newTestTmux(t)
+	session := "gt-test-verified-ok-" + t.Name()
+	_ = tm.KillSession(session)
+	defer func() { _ = tm.KillSession(session) }()
+
+	if err := tm.NewSessionWithCommandVerified(session, "", "sleep 10", VerifyOptions{GracePeriod: 300 * time.Millisecond}); err != nil {
+		t.Fatalf("NewSessionWithCommandVerified failed: %v", err)
+	}
+	if has, _ := tm.HasSession(session); !has {
+		t.Fatal("session should exist")
+	}
+	out, _ := tm.run("show-options", "-t", session, "-v", "remain-on-exit")
+	if strings.TrimSpace(out) == "on" {
+		t.Error("remain-on-exit should be restored after verification")
+	}
+}
@@ -0,0 +1,87 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ApplySessionTemplate applies a rig's session template to a session:
+// environment defaults, tmux options, hooks, then pane splits. Call it after
+// ConfigureGasTownSession so template options override the theme's.
+//
+// Environment variables already set in the session are left alone, so
+// Gas Town's own variables win over template defaults. Splits open detached
+// beside the agent pane, which stays active. Every entry is attempted; the
+// errors of those that fail are returned together.
+func (t *Tmux) ApplySessionTemplate(session, workDir string, tmpl *config.SessionTemplateConfig) error {
+	if tmpl.Empty() {
+		return nil
+	}
+	if err := validateSessionName(session); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, k := range sortedKeys(tmpl.Env) {
+		if _, err := t.GetEnvironment(session, k); err == nil {
+			continue
+		}
+		if err := t.SetEnvironment(session, k, tmpl.Env[k]); err != nil {
+			errs = append(errs, fmt.Errorf("env %s: %w", k, err))
+		}
+	}
+	for _, k := range sortedKeys(tmpl.Options) {
+		if _, err := t.run("set-option", "-t", session, k, tmpl.Options[k]); err != nil {
+			errs = append(errs, fmt.Errorf("option %s: %w", k, err))
+		}
+	}
+	for _, k := range sortedKeys(tmpl.Hooks) {
+		if k == "pane-died" {
+			errs = append(errs, fmt.Errorf("hook %s: reserved for crash detection", k))
+			continue
+		}
+		if _, err := t.run("set-hook", "-t", session, k, tmpl.Hooks[k]); err != nil {
+			errs = append(errs, fmt.Errorf("hook %s: %w", k, err))
+		}
+	}
+	for i, split := range tmpl.Splits {
+		if _, err := t.run(splitWindowArgs(session, workDir, split)...); err != nil {
+			errs = append(errs, fmt.Errorf("split %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// splitWindowArgs builds the split-window command for a template split.
+// -d keeps the agent pane active, since nudges and captures target the
+// session's active pane.
+func splitWindowArgs(session, workDir string, split config.PaneSplitConfig) []string {
+	args := []string{"split-window", "-d", "-t", session}
+	if split.Direction == config.SplitBelow {
+		args = append(args, "-v")
+	} else {
+		args = append(args, "-h")
+	}
+	if split.Size != "" {
+		args = append(args, "-l", split.Size)
+	}
+	if workDir != "" {
+		args = append(args, "-c", workDir)
+	}
+	if split.Command != "" {
+		args = append(args, split.Command)
+	}
+	return args
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,78 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestApplySessionTemplate(t *testing.T) {
+	tm := newTestTmux(t)
+	session := "gt-test-template"
+	_ = tm.KillSession(session)
+	defer func() { _ = tm.KillSession(session) }()
+
+	if err := tm.NewSessionWithCommand(session, "", "sleep 30"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if err := tm.SetEnvironment(session, "GT_ROLE", "polecat"); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &config.SessionTemplateConfig{
+		Options: map[string]string{"history-limit": "12345", "status-right": "custom"},
+		Hooks:   map[string]string{"client-attached": "display-message hi"},
+		Splits:  []config.PaneSplitConfig{{Direction: config.SplitBelow, Size: "30%", Command: "sleep 30"}},
+		Env:     map[string]string{"GT_ROLE": "overridden", "EDITOR": "vi"},
+	}
+	if err := tm.ApplySessionTemplate(session, "", tmpl); err != nil {
+		t.Fatalf("ApplySessionTemplate: %v", err)
+	}
+
+	if out, _ := tm.run("show-options", "-v", "-t", session, "history-limit"); out != "12345" {
+		t.Errorf("history-limit = %q, want 12345", out)
+	}
+	if out, _ := tm.run("show-options", "-v", "-t", session, "status-right"); out != "custom" {
+		t.Errorf("status-right = %q, want custom", out)
+	}
+	if out, _ := tm.run("show-hooks", "-t", session, "client-attached"); !strings.Contains(out, "display-message") {
+		t.Errorf("client-attached hook = %q", out)
+	}
+	if v, _ := tm.GetEnvironment(session, "GT_ROLE"); v != "polecat" {
+		t.Errorf("GT_ROLE = %q, want polecat (template env is only a default)", v)
+	}
+	if v, _ := tm.GetEnvironment(session, "EDITOR"); v != "vi" {
+		t.Errorf("EDITOR = %q, want vi", v)
+	}
+	panes, _ := tm.run("list-panes", "-t", session, "-F", "#{pane_index}:#{pane_active}")
+	if got := strings.Fields(panes); len(got) != 2 || got[0] != "0:1" {
+		t.Errorf("panes = %q, want 2 with the agent pane (0) active", got)
+	}
+}
+
+func TestApplySessionTemplateRejectsPaneDied(t *testing.T) {
+	tm := newTestTmux(t)
+	session := "gt-test-template-reserved"
+	_ = tm.KillSession(session)
+	defer func() { _ = tm.KillSession(session) }()
+
+	if err := tm.NewSessionWithCommand(session, "", "sleep 30"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	tmpl := &config.SessionTemplateConfig{Hooks: map[string]string{"pane-died": "kill-session"}}
+	if err := tm.ApplySessionTemplate(session, "", tmpl); err == nil {
+		t.Error("ApplySessionTemplate allowed a pane-died hook")
+	}
+}
+
+func TestSplitWindowArgs(t *testing.T) {
+	got := strings.Join(splitWindowArgs("s", "/w", config.PaneSplitConfig{Size: "40%", Command: "htop"}), " ")
+	if want := "split-window -d -t s -h -l 40% -c /w htop"; got != want {
+		t.Errorf("splitWindowArgs = %q, want %q", got, want)
+	}
+	got = strings.Join(splitWindowArgs("s", "", config.PaneSplitConfig{Direction: config.SplitBelow}), " ")
+	if want := "split-window -d -t s -v"; got != want {
+		t.Errorf("splitWindowArgs = %q, want %q", got, want)
+	}
+}
@@ -45,8 +45,41 @@ var (
 	ErrSessionRunning     = errors.New("session already running with healthy agent")
 	ErrInvalidSessionName = errors.New("invalid session name")
 	ErrIdleTimeout        = errors.New("agent not idle before timeout")
+	ErrSessionDied        = errors.New("session died right after creation")
 )
 
+// CommandExitError reports a session whose command exited with a non-zero
+// status right after the session was created. Output is the dead pane's
+// last output, for classifying the failure.
+//
+// NewSessionWithCommandVerified also returns it for a command that exited
+// cleanly (Status "0"), for a dead pane whose exit status tmux never set
+// (Status ExitStatusUnknown), and for a session that vanished outright
+// (Status empty, Output the last output seen before it did).
+type CommandExitError struct {
+	Session string
+	Command string
+	Status  string
+	Output  string
+}
+
+// ExitStatusUnknown is the CommandExitError status of a dead pane whose
+// exit status tmux did not report.
+const ExitStatusUnknown = "unknown"
+
+func (e *CommandExitError) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("session %q vanished right after creation: %s", e.Session, e.Command)
+	}
+	if e.Status == ExitStatusUnknown {
+		return fmt.Sprintf("session %q: command exited with unknown status: %s", e.Session, e.Command)
+	}
+	return fmt.Sprintf("session %q: command exited with status %s: %s", e.Session, e.Status, e.Command)
+}
+
+// Unwrap lets callers match any early session death with ErrSessionDied.
+func (e *CommandExitError) Unwrap() error { return ErrSessionDied }
+
 // validateSessionName checks that a session name contains only safe characters.
 // Returns ErrInvalidSessionName if the name contains dots, colons, or other
 // characters that cause tmux to silently fail or produce cryptic errors.
@@ -107,12 +140,24 @@ func BuildCommand(args ...string) *exec.Cmd {
 
 // Tmux wraps tmux operations.
 type Tmux struct {
-	socketName string // tmux socket name (-L flag), empty = default socket
+	socketName  string      // tmux socket name (-L flag), empty = default socket
+	inputSource string      // transcript source for send-keys (see withSource)
+	mux         Multiplexer // another backend running the sessions, nil = tmux
 }
 
 // NewTmux creates a new Tmux wrapper that inherits the default socket.
+// If the town runs another multiplexer (SetDefaultBackend), session
+// operations go to it and tmux-only ones return ErrUnsupported.
 func NewTmux() *Tmux {
-	return &Tmux{socketName: defaultSocket}
+	return &Tmux{socketName: defaultSocket, mux: alternateBackend()}
+}
+
+// Name returns the multiplexer running the sessions.
+func (t *Tmux) Name() string {
+	if t.mux != nil {
+		return t.mux.Name()
+	}
+	return BackendTmux
 }
 
 // NewTmuxWithSocket creates a Tmux wrapper that targets a named socket.
@@ -127,6 +172,9 @@ func NewTmuxWithSocket(socket string) *Tmux {
 // All commands include -u flag for UTF-8 support regardless of locale settings.
 // See: https://github.com/steveyegge/gastown/issues/1219
 func (t *Tmux) run(args ...string) (string, error) {
+	if t.mux != nil {
+		return "", fmt.Errorf("tmux %s under %s: %w", args[0], t.mux.Name(), ErrUnsupported)
+	}
 	// Prepend global flags: -u (UTF-8 mode, PATCH-004) and optionally -L (socket).
 	// The -L flag must come before the subcommand, so it goes in the prefix.
 	allArgs := []string{"-u"}
@@ -143,6 +191,9 @@ func (t *Tmux) run(args ...string) (string, error) {
 	if err != nil {
 		return "", t.wrapError(err, stderr.String(), args)
 	}
+	if len(args) > 0 && args[0] == "send-keys" {
+		t.recordSendKeys(args)
+	}
 
 	return strings.TrimSpace(stdout.String()), nil
 }
@@ -166,6 +217,14 @@ func (t *Tmux) wrapError(err error, stderr string, args []string) error {
 		return ErrSessionNotFound
 	}
 
+	// Unknown flags/commands usually mean tmux is too old; say so instead of
+	// surfacing a cryptic usage error.
+	if isUnknownFlagError(stderr) {
+		if verr := CheckVersion(); errors.Is(verr, ErrTmuxTooOld) {
+			return fmt.Errorf("tmux %s: %s: %w", args[0], stderr, verr)
+		}
+	}
+
 	if stderr != "" {
 		return fmt.Errorf("tmux %s: %s", args[0], stderr)
 	}
@@ -174,6 +233,9 @@ func (t *Tmux) wrapError(err error, stderr string, args []string) error {
 
 // NewSession creates a new detached tmux session.
 func (t *Tmux) NewSession(name, workDir string) error {
+	if t.mux != nil {
+		return t.mux.NewSessionWithCommand(name, workDir, "")
+	}
 	if err := validateSessionName(name); err != nil {
 		return err
 	}
@@ -195,6 +257,59 @@ func (t *Tmux) NewSession(name, workDir string) error {
 // errors, etc.) so callers get an error instead of a silently dead session.
 // See: https://github.com/anthropics/gastown/issues/280
 func (t *Tmux) NewSessionWithCommand(name, workDir, command string) error {
+	if t.mux != nil {
+		return t.mux.NewSessionWithCommand(name, workDir, command)
+	}
+	if err := t.spawnSessionCommand(name, workDir, command); err != nil {
+		return err
+	}
+	return t.checkSessionAfterCreate(name, command)
+}
+
+// VerifyOptions configures NewSessionWithCommandVerified. Zero values use
+// constants.SessionVerifyGrace and constants.SessionVerifyPollInterval.
+type VerifyOptions struct {
+	// GracePeriod is how long the command must stay up to count as started.
+	GracePeriod time.Duration
+
+	// PollInterval is how often the session is checked meanwhile.
+	PollInterval time.Duration
+
+	// RemainOnExit leaves remain-on-exit on once the session is verified,
+	// so if its command crashes later the dead pane stays around for
+	// CaptureDeadPane. Without it there is no gap between creation and a
+	// later SetRemainOnExit in which a crash loses its output. Only tmux
+	// keeps dead panes; under other multiplexers a crashed session is gone
+	// and CaptureDeadPane returns ErrUnsupported.
+	RemainOnExit bool
+}
+
+// NewSessionWithCommandVerified is NewSessionWithCommand with a longer watch:
+// it keeps the new session under observation for the whole grace period
+// instead of a single 50ms check, so a command that takes a moment to fail
+// (a shell reporting "command not found", an agent exiting on bad flags)
+// is reported instead of leaving the caller holding a session that is
+// already gone.
+//
+// If the command exits within the grace period, with any status, the
+// session is killed and a *CommandExitError carrying the pane's last output
+// is returned; it matches ErrSessionDied.
+func (t *Tmux) NewSessionWithCommandVerified(name, workDir, command string, opts VerifyOptions) error {
+	if t.mux != nil {
+		if err := t.mux.NewSessionWithCommand(name, workDir, command); err != nil {
+			return err
+		}
+		return t.verifySessionAfterCreate(name, command, opts)
+	}
+	if err := t.spawnSessionCommand(name, workDir, command); err != nil {
+		return err
+	}
+	return t.verifySessionAfterCreate(name, command, opts)
+}
+
+// spawnSessionCommand creates a detached session running command, with
+// remain-on-exit on so an early exit can be inspected.
+func (t *Tmux) spawnSessionCommand(name, workDir, command string) error {
 	if err := validateSessionName(name); err != nil {
 		return err
 	}
@@ -232,8 +347,7 @@ func (t *Tmux) NewSessionWithCommand(name, workDir, command string) error {
 		_ = t.KillSession(name)
 		return fmt.Errorf("failed to start command in session %q: %w", name, err)
 	}
-
-	return t.checkSessionAfterCreate(name, command)
+	return nil
 }
 
 // NewSessionWithCommandAndEnv creates a new detached tmux session with environment
@@ -245,8 +359,12 @@ func (t *Tmux) NewSessionWithCommand(name, workDir, command string) error {
 //
 // The command should still use 'exec env' for WaitForCommand detection compatibility,
 // but -e provides defense-in-depth for the initial shell environment.
-// Requires tmux >= 3.2.
+// On tmux < 3.2 (no new-session -e) the variables are set with set-environment
+// before the command is respawned, which the command then inherits.
 func (t *Tmux) NewSessionWithCommandAndEnv(name, workDir, command string, env map[string]string) error {
+	if t.mux != nil {
+		return t.mux.NewSessionWithCommand(name, workDir, config.PrependEnv(command, env))
+	}
 	if err := validateSessionName(name); err != nil {
 		return err
 	}
@@ -273,12 +391,23 @@ func (t *Tmux) NewSessionWithCommandAndEnv(name, workDir, command string, env ma
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
-	for _, k := range keys {
-		args = append(args, "-e", fmt.Sprintf("%s=%s", k, env[k]))
+	envFlags := t.Supports(FeatureNewSessionEnv)
+	if envFlags {
+		for _, k := range keys {
+			args = append(args, "-e", fmt.Sprintf("%s=%s", k, env[k]))
+		}
 	}
 	if _, err := t.run(args...); err != nil {
 		return err
 	}
+	if !envFlags {
+		for _, k := range keys {
+			if _, err := t.run("set-environment", "-t", name, k, env[k]); err != nil {
+				_ = t.KillSession(name)
+				return fmt.Errorf("setting %s in session %q: %w", k, name, err)
+			}
+		}
+	}
 
 	// Enable remain-on-exit BEFORE command runs so we can inspect exit status
 	_, _ = t.run("set-option", "-t", name, "remain-on-exit", "on")
@@ -312,9 +441,11 @@ func (t *Tmux) checkSessionAfterCreate(name, command string) error {
 		exitStatus, _ := t.run("display-message", "-p", "-t", name, "#{pane_dead_status}")
 		status := strings.TrimSpace(exitStatus)
 		if status != "" && status != "0" {
-			// Command failed (non-zero exit) — clean up and return error
+			// Command failed (non-zero exit) — keep its last output, clean up,
+			// and return error
+			output, _ := t.run("capture-pane", "-p", "-t", name, "-S", "-50")
 			_ = t.KillSession(name)
-			return fmt.Errorf("session %q: command exited with status %s: %s", name, status, command)
+			return &CommandExitError{Session: name, Command: command, Status: status, Output: output}
 		}
 		// Command exited cleanly (status 0) — clean up the dead session.
 		// This matches the default tmux behavior (no remain-on-exit) where
@@ -328,6 +459,57 @@ func (t *Tmux) checkSessionAfterCreate(name, command string) error {
 	return nil
 }
 
+// verifySessionAfterCreate watches a newly created session (remain-on-exit
+// on) for the grace period. A pane that dies in that time, with any status,
+// or a session that disappears, is an error carrying the pane's last output.
+func (t *Tmux) verifySessionAfterCreate(name, command string, opts VerifyOptions) error {
+	grace := opts.GracePeriod
+	if grace <= 0 {
+		grace = constants.SessionVerifyGrace
+	}
+	poll := opts.PollInterval
+	if poll <= 0 {
+		poll = constants.SessionVerifyPollInterval
+	}
+
+	var output string
+	deadline := time.Now().Add(grace)
+	for {
+		if alive, err := t.HasSession(name); err == nil && !alive {
+			return &CommandExitError{Session: name, Command: command, Output: output}
+		}
+		if t.mux != nil {
+			// Other backends end the session with its command, so the
+			// session check above is the whole liveness check.
+			if out, err := t.mux.CapturePane(name, 50); err == nil {
+				output = out
+			}
+		} else if out, err := t.run("capture-pane", "-p", "-t", name, "-S", "-50"); err == nil {
+			output = out
+		}
+		dead, code, err := deadPaneStatus(func() (bool, int, error) { return t.PaneDeadStatus(name) }, deadStatusWait)
+		if err == nil && dead {
+			status := ExitStatusUnknown
+			if code >= 0 {
+				status = strconv.Itoa(code)
+			}
+			_ = t.KillSession(name)
+			return &CommandExitError{Session: name, Command: command, Status: status, Output: output}
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
+		time.Sleep(poll)
+	}
+
+	// Survived the grace period — restore default (no need to keep dead
+	// sessions around) unless the caller wants crashes kept for capture.
+	if !opts.RemainOnExit && t.mux == nil {
+		_, _ = t.run("set-option", "-t", name, "remain-on-exit", "off")
+	}
+	return nil
+}
+
 // EnsureSessionFresh ensures a session is available and healthy.
 // If the session exists but is a zombie (Claude not running), it kills the session first.
 // This prevents "session already exists" errors when trying to restart dead agents.
@@ -412,6 +594,12 @@ func (t *Tmux) EnsureSessionFreshWithCommand(name, workDir, command string) erro
 // KillSession terminates a tmux session. Idempotent: returns nil if the
 // session is already gone or there is no tmux server.
 func (t *Tmux) KillSession(name string) (retErr error) {
+	if t.mux != nil {
+		if err := t.mux.KillSession(name); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return err
+		}
+		return nil
+	}
 	defer func() { telemetry.RecordSessionStop(context.Background(), name, retErr) }()
 	_, retErr = t.run("kill-session", "-t", name)
 	if retErr == ErrSessionNotFound || retErr == ErrNoServer {
@@ -443,6 +631,9 @@ const processKillGracePeriod = 2 * time.Second
 //
 // This ensures Claude processes and all their children are properly terminated.
 func (t *Tmux) KillSessionWithProcesses(name string) error {
+	if t.mux != nil {
+		return t.KillSession(name)
+	}
 	// Get the pane PID
 	pid, err := t.GetPanePID(name)
 	if err != nil {
@@ -511,6 +702,9 @@ func (t *Tmux) KillSessionWithProcesses(name string) error {
 // the calling process (e.g., gt done) is running inside the session it's terminating.
 // Without exclusion, the caller would be killed before completing the cleanup.
 func (t *Tmux) KillSessionWithProcessesExcluding(name string, excludePIDs []string) error {
+	if t.mux != nil {
+		return t.KillSession(name)
+	}
 	// Build exclusion set for O(1) lookup
 	exclude := make(map[string]bool)
 	for _, pid := range excludePIDs {
@@ -799,6 +993,9 @@ func (t *Tmux) SetExitEmpty(on bool) error {
 
 // IsAvailable checks if tmux is installed and can be invoked.
 func (t *Tmux) IsAvailable() bool {
+	if t.mux != nil {
+		return t.mux.IsAvailable()
+	}
 	cmd := exec.Command("tmux", "-V")
 	return cmd.Run() == nil
 }
@@ -807,6 +1004,9 @@ func (t *Tmux) IsAvailable() bool {
 // Uses "=" prefix for exact matching, preventing prefix matches
 // (e.g., "gt-deacon-boot" won't match when checking for "gt-deacon").
 func (t *Tmux) HasSession(name string) (bool, error) {
+	if t.mux != nil {
+		return t.mux.HasSession(name)
+	}
 	_, err := t.run("has-session", "-t", "="+name)
 	if err != nil {
 		if errors.Is(err, ErrSessionNotFound) || errors.Is(err, ErrNoServer) {
@@ -819,6 +1019,9 @@ func (t *Tmux) HasSession(name string) (bool, error) {
 
 // ListSessions returns all session names.
 func (t *Tmux) ListSessions() ([]string, error) {
+	if t.mux != nil {
+		return t.mux.ListSessions()
+	}
 	out, err := t.run("list-sessions", "-F", "#{session_name}")
 	if err != nil {
 		if errors.Is(err, ErrNoServer) {
@@ -834,6 +1037,32 @@ func (t *Tmux) ListSessions() ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
+// SessionActivities returns every session's last activity time in one tmux
+// call. A session's activity advances whenever it produces output or gets
+// input, so comparing two snapshots shows which sessions are busy.
+func (t *Tmux) SessionActivities() (map[string]time.Time, error) {
+	out, err := t.run("list-sessions", "-F", "#{session_name}|#{session_activity}")
+	if err != nil {
+		if errors.Is(err, ErrNoServer) {
+			return map[string]time.Time{}, nil
+		}
+		return nil, err
+	}
+	activities := make(map[string]time.Time)
+	for _, line := range strings.Split(out, "\n") {
+		name, ts, ok := strings.Cut(line, "|")
+		if !ok || name == "" {
+			continue
+		}
+		unix, err := strconv.ParseInt(strings.TrimSpace(ts), 10, 64)
+		if err != nil {
+			continue
+		}
+		activities[name] = time.Unix(unix, 0)
+	}
+	return activities, nil
+}
+
 // SessionSet provides O(1) session existence checks by caching session names.
 // Use this when you need to check multiple sessions to avoid N+1 subprocess calls.
 type SessionSet struct {
@@ -858,6 +1087,13 @@ func NewSessionSet(names []string) *SessionSet {
 //
 // Builds the map directly from tmux output to avoid intermediate slice allocation.
 func (t *Tmux) GetSessionSet() (*SessionSet, error) {
+	if t.mux != nil {
+		names, err := t.mux.ListSessions()
+		if err != nil {
+			return nil, err
+		}
+		return NewSessionSet(names), nil
+	}
 	out, err := t.run("list-sessions", "-F", "#{session_name}")
 	if err != nil {
 		if errors.Is(err, ErrNoServer) {
@@ -952,6 +1188,9 @@ func (t *Tmux) ListSessionIDs() (map[string]string, error) {
 // Always sends Enter as a separate command for reliability.
 // Uses a debounce delay between paste and Enter to ensure paste completes.
 func (t *Tmux) SendKeys(session, keys string) error {
+	if t.mux != nil {
+		return t.mux.SendKeys(session, keys)
+	}
 	return t.SendKeysDebounced(session, keys, constants.DefaultDebounceMs) // 100ms default debounce
 }
 
@@ -1232,18 +1471,51 @@ func (t *Tmux) sendKeysLiteralWithRetry(target, text string, timeout time.Durati
 // If the agent TUI hasn't initialized yet (cold startup), retries with backoff
 // up to NudgeReadyTimeout before giving up. See sendKeysLiteralWithRetry.
 //
+// A pane in copy mode is taken out of it first. A pane showing a dialog or
+// a full-screen editor or pager is given NudgeDeferTimeout to clear; if it
+// does not, nothing is typed and the error wraps ErrPaneBusy.
+//
+// If delivery fails because the pane died or the tmux server is restarting,
+// the whole delivery is retried with exponential backoff, re-resolving the
+// agent pane each time: the session may have been recycled with a new pane.
+// If every attempt fails, the returned *NudgeError lists them all.
+//
 // IMPORTANT: Nudges to the same session are serialized to prevent interleaving.
 // If multiple goroutines try to nudge the same session concurrently, they will
 // queue up and execute one at a time. This prevents garbled input when
 // SessionStart hooks and nudges arrive simultaneously.
 func (t *Tmux) NudgeSession(session, message string) error {
+	if NudgeGate != nil {
+		if err := NudgeGate(session); err != nil {
+			return err
+		}
+	}
+
 	// Serialize nudges to this session to prevent interleaving.
 	// Use a timed lock to avoid permanent blocking if a previous nudge hung.
 	if !acquireNudgeLock(session, nudgeLockTimeout) {
 		return fmt.Errorf("nudge lock timeout for session %q: previous nudge may be hung", session)
 	}
 	defer releaseNudgeLock(session)
+	if t.mux != nil {
+		return t.mux.NudgeSession(session, message)
+	}
+	t = t.withSource(SourceNudge)
+
+	// Sanitize control characters that corrupt delivery
+	sanitized := sanitizeNudgeMessage(message)
 
+	return retryNudge(session, constants.NudgeRecoverAttempts, constants.NudgeRecoverBackoff, time.Sleep, func() error {
+		return t.deliverNudge(session, sanitized)
+	})
+}
+
+// NudgeGate, when set, is asked before every nudge; an error refuses it.
+// gt sets it to hold agents' nudges while the town is frozen.
+var NudgeGate func(session string) error
+
+// deliverNudge makes one attempt at delivering a sanitized nudge to session.
+func (t *Tmux) deliverNudge(session, sanitized string) error {
 	// Resolve the correct target: in multi-pane sessions, find the pane
 	// running the agent rather than sending to the focused pane.
 	target := session
@@ -1252,14 +1524,14 @@ func (t *Tmux) NudgeSession(session, message string) error {
 	}
 
 	// 1. Exit copy/scroll mode if active — copy mode intercepts input,
-	//    preventing delivery to the underlying process.
-	if inMode, _ := t.run("display-message", "-p", "-t", target, "#{pane_in_mode}"); strings.TrimSpace(inMode) == "1" {
-		_, _ = t.run("send-keys", "-t", target, "-X", "cancel")
-		time.Sleep(50 * time.Millisecond)
+	//    preventing delivery to the underlying process. Defer to a dialog
+	//    or full-screen program rather than typing into it.
+	if err := t.preparePaneForInput(target); err != nil {
+		return err
 	}
 
-	// 2. Sanitize control characters that corrupt delivery
-	sanitized := sanitizeNudgeMessage(message)
+	// 2. Record what the agent was showing, for the transcript
+	t.recordOutputContext(target)
 
 	// 3. Send text via send-keys -l. Messages > 512 bytes are chunked
 	//    with 10ms inter-chunk delays to avoid argument length limits.
@@ -1297,6 +1569,67 @@ func (t *Tmux) NudgeSession(session, message string) error {
 	return fmt.Errorf("failed to send Enter after 3 attempts: %w", lastErr)
 }
 
+// NudgeError reports a nudge that failed on every attempt.
+type NudgeError struct {
+	Session  string
+	Attempts []error
+}
+
+func (e *NudgeError) Error() string {
+	if len(e.Attempts) == 1 {
+		return e.Attempts[0].Error()
+	}
+	parts := make([]string, len(e.Attempts))
+	for i, err := range e.Attempts {
+		parts[i] = fmt.Sprintf("attempt %d: %v", i+1, err)
+	}
+	return fmt.Sprintf("nudging %s failed after %d attempts: %s", e.Session, len(e.Attempts), strings.Join(parts, "; "))
+}
+
+// Unwrap exposes every attempt's error to errors.Is and errors.As.
+func (e *NudgeError) Unwrap() []error {
+	return e.Attempts
+}
+
+// isRecoverableNudgeError reports whether a failed delivery may succeed if
+// retried against a freshly resolved pane: the pane died or was replaced,
+// the session is being recycled, or the tmux server is restarting.
+func isRecoverableNudgeError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrNoServer) || errors.Is(err, ErrSessionNotFound) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "can't find pane") ||
+		strings.Contains(msg, "pane is dead") ||
+		strings.Contains(msg, "lost server")
+}
+
+// retryNudge runs deliver up to attempts times, sleeping backoff (doubling
+// each time) between attempts, for as long as failures are recoverable.
+// A single failure is returned as is; several as a *NudgeError.
+func retryNudge(session string, attempts int, backoff time.Duration, sleep func(time.Duration), deliver func() error) error {
+	var history []error
+	for attempt := 1; ; attempt++ {
+		err := deliver()
+		if err == nil {
+			return nil
+		}
+		history = append(history, err)
+		if attempt >= attempts || !isRecoverableNudgeError(err) {
+			break
+		}
+		sleep(backoff)
+		backoff *= 2
+	}
+	if len(history) == 1 {
+		return history[0]
+	}
+	return &NudgeError{Session: session, Attempts: history}
+}
+
 // NudgePane sends a message to a specific pane reliably.
 // Same pattern as NudgeSession but targets a pane ID (e.g., "%9") instead of session name.
 // After sending, triggers SIGWINCH to wake Claude in detached sessions.
@@ -1308,16 +1641,18 @@ func (t *Tmux) NudgePane(pane, message string) error {
 		return fmt.Errorf("nudge lock timeout for pane %q: previous nudge may be hung", pane)
 	}
 	defer releaseNudgeLock(pane)
+	t = t.withSource(SourceNudge)
 
 	// 1. Exit copy/scroll mode if active — copy mode intercepts input,
-	//    preventing delivery to the underlying process.
-	if inMode, _ := t.run("display-message", "-p", "-t", pane, "#{pane_in_mode}"); strings.TrimSpace(inMode) == "1" {
-		_, _ = t.run("send-keys", "-t", pane, "-X", "cancel")
-		time.Sleep(50 * time.Millisecond)
+	//    preventing delivery to the underlying process. Defer to a dialog
+	//    or full-screen program rather than typing into it.
+	if err := t.preparePaneForInput(pane); err != nil {
+		return err
 	}
 
 	// 2. Sanitize control characters that corrupt delivery
 	sanitized := sanitizeNudgeMessage(message)
+	t.recordOutputContext(pane)
 
 	// 3. Send text via send-keys -l. Messages > 512 bytes are chunked
 	//    with 10ms inter-chunk delays to avoid argument length limits.
@@ -1375,6 +1710,8 @@ func (t *Tmux) AcceptStartupDialogs(session string) error {
 // this folder") is pre-selected, so we just need to press Enter to accept.
 // This dialog appears BEFORE the bypass permissions warning, so call this first.
 func (t *Tmux) AcceptWorkspaceTrustDialog(session string) error {
+	t = t.withSource(SourceDialog)
+
 	// Wait for the dialog to potentially render
 	time.Sleep(1 * time.Second)
 
@@ -1390,6 +1727,8 @@ func (t *Tmux) AcceptWorkspaceTrustDialog(session string) error {
 		return nil
 	}
 
+	t.recordOutput(session, content)
+
 	// Option 1 ("Yes, I trust this folder") is already pre-selected, just press Enter
 	if _, err := t.run("send-keys", "-t", session, "Enter"); err != nil {
 		return err
@@ -1410,6 +1749,8 @@ func (t *Tmux) AcceptWorkspaceTrustDialog(session string) error {
 // Call this after starting Claude and waiting for it to initialize (WaitForCommand),
 // but before sending any prompts.
 func (t *Tmux) AcceptBypassPermissionsWarning(session string) error {
+	t = t.withSource(SourceDialog)
+
 	// Wait for the dialog to potentially render
 	time.Sleep(1 * time.Second)
 
@@ -1425,6 +1766,8 @@ func (t *Tmux) AcceptBypassPermissionsWarning(session string) error {
 		return nil
 	}
 
+	t.recordOutput(session, content)
+
 	// Press Down to select "Yes, I accept" (option 2)
 	if _, err := t.run("send-keys", "-t", session, "Down"); err != nil {
 		return err
@@ -1460,6 +1803,16 @@ func (t *Tmux) GetPaneCommand(session string) (string, error) {
 	return result, nil
 }
 
+// GetPaneStartCommand returns the command pane 0 was started (or last
+// respawned) with. Empty means the pane runs the default shell.
+func (t *Tmux) GetPaneStartCommand(session string) (string, error) {
+	out, err := t.run("display-message", "-t", session+":0.0", "-p", "#{pane_start_command}")
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(strings.TrimSpace(out), `"`), nil
+}
+
 // FindAgentPane finds the pane running an agent process within a session.
 // In multi-pane sessions, send-keys -t <session> targets the active/focused pane,
 // which may not be the agent pane. This method enumerates all panes and returns
@@ -1768,6 +2121,9 @@ func (t *Tmux) FindSessionByWorkDir(targetDir string, processNames []string) ([]
 
 // CapturePane captures the visible content of a pane.
 func (t *Tmux) CapturePane(session string, lines int) (string, error) {
+	if t.mux != nil {
+		return t.mux.CapturePane(session, lines)
+	}
 	content, err := t.run("capture-pane", "-p", "-t", session, "-S", fmt.Sprintf("-%d", lines))
 	telemetry.RecordPaneRead(context.Background(), session, lines, len(content), err)
 	return content, err
@@ -1775,6 +2131,9 @@ func (t *Tmux) CapturePane(session string, lines int) (string, error) {
 
 // CapturePaneAll captures all scrollback history.
 func (t *Tmux) CapturePaneAll(session string) (string, error) {
+	if t.mux != nil {
+		return t.mux.CapturePaneAll(session)
+	}
 	return t.run("capture-pane", "-p", "-t", session, "-S", "-")
 }
 
@@ -1790,9 +2149,55 @@ func (t *Tmux) CapturePaneLines(session string, lines int) ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
+// PipePane copies everything the agent pane (pane 0) of session writes to
+// the stdin of shellCommand, replacing any previous pipe. tmux runs the
+// command through the shell; the pipe closes when the pane exits.
+func (t *Tmux) PipePane(session, shellCommand string) error {
+	_, err := t.run("pipe-pane", "-t", session+":0.0", shellCommand)
+	return err
+}
+
+// StopPipePane closes the agent pane's pipe, if any.
+func (t *Tmux) StopPipePane(session string) error {
+	_, err := t.run("pipe-pane", "-t", session+":0.0")
+	return err
+}
+
+// IsPanePiped reports whether the agent pane of session has an open pipe.
+func (t *Tmux) IsPanePiped(session string) bool {
+	out, err := t.run("display-message", "-p", "-t", session+":0.0", "#{pane_pipe}")
+	return err == nil && strings.TrimSpace(out) == "1"
+}
+
+// GetPaneSize returns the width and height of the agent pane of session.
+func (t *Tmux) GetPaneSize(session string) (width, height int, err error) {
+	out, err := t.run("display-message", "-p", "-t", session+":0.0", "#{pane_width} #{pane_height}")
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := fmt.Sscanf(out, "%d %d", &width, &height); err != nil {
+		return 0, 0, fmt.Errorf("parsing pane size %q: %w", out, err)
+	}
+	return width, height, nil
+}
+
+// AttachCommand returns the command line that attaches a terminal to
+// session.
+func (t *Tmux) AttachCommand(session string) ([]string, error) {
+	if t.mux != nil {
+		return t.mux.AttachCommand(session)
+	}
+	args := []string{"tmux", "-u"}
+	if t.socketName != "" {
+		args = append(args, "-L", t.socketName)
+	}
+	return append(args, "attach-session", "-t", session), nil
+}
+
 // AttachSession attaches to an existing session.
 // Note: This replaces the current process with tmux attach.
 func (t *Tmux) AttachSession(session string) error {
+	RecordAttach(session)
 	_, err := t.run("attach-session", "-t", session)
 	return err
 }
@@ -1805,12 +2210,18 @@ func (t *Tmux) SelectWindow(session string, index int) error {
 
 // SetEnvironment sets an environment variable in the session.
 func (t *Tmux) SetEnvironment(session, key, value string) error {
+	if t.mux != nil {
+		return t.mux.SetEnvironment(session, key, value)
+	}
 	_, err := t.run("set-environment", "-t", session, key, value)
 	return err
 }
 
 // GetEnvironment gets an environment variable from the session.
 func (t *Tmux) GetEnvironment(session, key string) (string, error) {
+	if t.mux != nil {
+		return t.mux.GetEnvironment(session, key)
+	}
 	out, err := t.run("show-environment", "-t", session, key)
 	if err != nil {
 		return "", err
@@ -1906,6 +2317,10 @@ Run: gt mail inbox
 // If expectedPaneCommands is non-empty, the pane's current command must match one of them.
 // If expectedPaneCommands is empty, any non-shell command counts as "agent running".
 func (t *Tmux) IsAgentRunning(session string, expectedPaneCommands ...string) bool {
+	if t.mux != nil {
+		running, _ := t.mux.HasSession(session)
+		return running
+	}
 	cmd, err := t.GetPaneCommand(session)
 	if err != nil {
 		return false
@@ -1933,6 +2348,10 @@ func (t *Tmux) IsAgentRunning(session string, expectedPaneCommands ...string) bo
 // Checks both pane command and child processes (for agents started via shell).
 // This is the unified agent detection method for all agent types.
 func (t *Tmux) IsRuntimeRunning(session string, processNames []string) bool {
+	if t.mux != nil {
+		running, _ := t.mux.HasSession(session)
+		return running
+	}
 	if len(processNames) == 0 {
 		return false
 	}
@@ -1993,11 +2412,18 @@ func (t *Tmux) resolveSessionProcessNames(session string) []string {
 
 // WaitForCommand polls until the pane is NOT running one of the excluded commands.
 // Useful for waiting until a shell has started a new process (e.g., claude).
-// Returns nil when a non-excluded command is detected, or error on timeout.
+// Returns nil when a non-excluded command is detected. On timeout it returns
+// a *WaitDiagnosis (see DiagnoseWait) recording the pane command transitions
+// it saw and why the command never started.
 func (t *Tmux) WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error {
+	if t.mux != nil {
+		return t.waitForBackendSession(session)
+	}
+	rec := &waitRecorder{t: t, start: time.Now()}
 	deadline := time.Now().Add(timeout)
 	for time.Now().Before(deadline) {
 		cmd, err := t.GetPaneCommand(session)
+		rec.observe(session, cmd)
 		if err != nil {
 			time.Sleep(constants.PollInterval)
 			continue
@@ -2015,12 +2441,15 @@ func (t *Tmux) WaitForCommand(session string, excludeCommands []string, timeout
 		}
 		time.Sleep(constants.PollInterval)
 	}
-	return fmt.Errorf("timeout waiting for command (still running excluded command)")
+	return rec.diagnose(session, excludeCommands, timeout)
 }
 
 // WaitForShellReady polls until the pane is running a shell command.
 // Useful for waiting until a process has exited and returned to shell.
 func (t *Tmux) WaitForShellReady(session string, timeout time.Duration) error {
+	if t.mux != nil {
+		return t.waitForBackendSession(session)
+	}
 	shells := constants.SupportedShells
 	deadline := time.Now().Add(timeout)
 	for time.Now().Before(deadline) {
@@ -2078,6 +2507,9 @@ func matchesPromptPrefix(line, readyPromptPrefix string) bool {
 }
 
 func (t *Tmux) WaitForRuntimeReady(session string, rc *config.RuntimeConfig, timeout time.Duration) error {
+	if t.mux != nil {
+		return t.waitForBackendSession(session)
+	}
 	if rc == nil || rc.Tmux == nil {
 		return nil
 	}
@@ -2392,10 +2824,13 @@ func (t *Tmux) SetMailClickBinding(session string) error {
 		// No prior binding — do nothing in non-GT sessions
 		fallback = ":"
 	}
+	// display-popup needs tmux 3.2; older versions show the output in copy mode.
+	preview := "display-popup -E -w 60 -h 15 'gt mail peek || echo No unread mail'"
+	if !t.Supports(FeaturePopup) {
+		preview = "run-shell 'gt mail peek || echo No unread mail'"
+	}
 	_, err := t.run("bind-key", "-T", "root", "MouseDown1StatusRight",
-		"if-shell", ifShell,
-		"display-popup -E -w 60 -h 15 'gt mail peek || echo No unread mail'",
-		fallback)
+		"if-shell", ifShell, preview, fallback)
 	return err
 }
 
@@ -2410,6 +2845,26 @@ func (t *Tmux) IsPaneDead(session string) bool {
 	return strings.TrimSpace(out) == "1"
 }
 
+// PaneDeadStatus reports whether the session's pane has exited (kept by
+// remain-on-exit), and its exit status (-1 if unknown).
+func (t *Tmux) PaneDeadStatus(session string) (bool, int, error) {
+	out, err := t.run("display-message", "-p", "-t", session, "#{pane_dead} #{pane_dead_status}")
+	if err != nil {
+		return false, -1, err
+	}
+	fields := strings.Fields(out)
+	if len(fields) == 0 || fields[0] != "1" {
+		return false, -1, nil
+	}
+	code := -1
+	if len(fields) > 1 {
+		if n, err := strconv.Atoi(fields[1]); err == nil {
+			code = n
+		}
+	}
+	return true, code, nil
+}
+
 // RespawnPaneDefault restarts a dead pane with its original command.
 // This is used when the pane process has exited (remain-on-exit on) and we
 // want to restart it in place without killing the entire session.
@@ -2859,10 +3314,17 @@ func (t *Tmux) CleanupOrphanedSessions(isGTSession func(string) bool) (cleaned i
 // SetPaneDiedHook sets a pane-died hook on a session to detect crashes.
 // When the pane exits, tmux runs the hook command with exit status info.
 // The agentID is used to identify the agent in crash logs (e.g., "gastown/Toast").
+//
+// remain-on-exit is enabled so the hook fires (tmux only raises pane-died
+// for panes it keeps) and can read the dead pane's output to classify the
+// failure; the hook then kills the session.
 func (t *Tmux) SetPaneDiedHook(session, agentID string) error {
 	if err := validateSessionName(session); err != nil {
 		return err
 	}
+	if err := t.SetRemainOnExit(session, true); err != nil {
+		return fmt.Errorf("setting remain-on-exit: %w", err)
+	}
 	// Sanitize agentID to prevent shell injection (session already validated by regex)
 	agentID = strings.ReplaceAll(agentID, "'", "'\\''")
 	session = strings.ReplaceAll(session, "'", "'\\''") // safe after validation, but keep for consistency
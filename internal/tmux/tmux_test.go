@@ -1623,6 +1623,75 @@ func TestIsTransientSendKeysError(t *testing.T) {
 	}
 }
 
+func TestIsRecoverableNudgeError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil error", nil, false},
+		{"pane gone", fmt.Errorf("tmux send-keys: can't find pane: %%12"), true},
+		{"session recycling", fmt.Errorf("nudge: %w", ErrSessionNotFound), true},
+		{"server restarting", ErrNoServer, true},
+		{"not in a mode", fmt.Errorf("tmux send-keys: not in a mode"), false},
+		{"generic error", fmt.Errorf("something else"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRecoverableNudgeError(tt.err); got != tt.want {
+				t.Errorf("isRecoverableNudgeError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryNudge(t *testing.T) {
+	var slept []time.Duration
+	sleep := func(d time.Duration) { slept = append(slept, d) }
+
+	t.Run("recovers after dead pane", func(t *testing.T) {
+		slept = nil
+		calls := 0
+		err := retryNudge("gt-toast", 4, 100*time.Millisecond, sleep, func() error {
+			calls++
+			if calls < 3 {
+				return fmt.Errorf("tmux send-keys: can't find pane: %%7")
+			}
+			return nil
+		})
+		if err != nil || calls != 3 {
+			t.Fatalf("err = %v after %d calls, want success on call 3", err, calls)
+		}
+		if want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}; fmt.Sprint(slept) != fmt.Sprint(want) {
+			t.Errorf("backoff = %v, want %v", slept, want)
+		}
+	})
+
+	t.Run("reports every attempt", func(t *testing.T) {
+		slept = nil
+		err := retryNudge("gt-toast", 3, time.Millisecond, sleep, func() error { return ErrNoServer })
+		var nerr *NudgeError
+		if !errors.As(err, &nerr) || len(nerr.Attempts) != 3 {
+			t.Fatalf("err = %v, want NudgeError with 3 attempts", err)
+		}
+		if !errors.Is(err, ErrNoServer) {
+			t.Error("errors.Is(err, ErrNoServer) = false, want true")
+		}
+		if !strings.Contains(err.Error(), "attempt 3: no tmux server running") {
+			t.Errorf("error %q does not list the attempts", err)
+		}
+	})
+
+	t.Run("fails fast on other errors", func(t *testing.T) {
+		slept = nil
+		boom := errors.New("boom")
+		err := retryNudge("gt-toast", 4, time.Millisecond, sleep, func() error { return boom })
+		if err != boom || len(slept) != 0 {
+			t.Errorf("err = %v after %d sleeps, want boom with no retry", err, len(slept))
+		}
+	})
+}
+
 func TestSendKeysLiteralWithRetry_ImmediateSuccess(t *testing.T) {
 	tm := newTestTmux(t)
 	sessionName := "gt-test-retry-ok-" + fmt.Sprintf("%d", time.Now().UnixNano()%10000)
@@ -0,0 +1,259 @@
+package tmux
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Transcript directions.
+const (
+	TranscriptIn  = "in"  // sent into the session
+	TranscriptOut = "out" // read from the session's pane
+)
+
+// Transcript sources: who or what produced an entry.
+const (
+	SourceKeys   = "keys"   // generic send-keys (SendKeys, inject, banners)
+	SourceNudge  = "nudge"  // NudgeSession / NudgePane
+	SourceDialog = "dialog" // startup dialog macros
+	SourceHuman  = "human"  // a human attaching through gt
+)
+
+// transcriptContextLines is how much of the pane is recorded as output
+// context before a nudge or dialog macro sends input.
+const transcriptContextLines = 20
+
+// TranscriptEntry is one line of a per-session transcript.
+//
+// Input entries record each send-keys call exactly as sent: Literal entries
+// are text typed verbatim (-l), others are tmux key names ("Enter",
+// "Escape", "C-u"). Output entries hold a capture of the pane taken when
+// gastown acted on what it saw. Human keystrokes after an attach are not
+// visible to gastown; only the attach itself is recorded.
+type TranscriptEntry struct {
+	Time    time.Time `json:"ts"`
+	Session string    `json:"session"`
+	Dir     string    `json:"dir"`
+	Source  string    `json:"source"`
+	Kind    string    `json:"kind,omitempty"` // "attach" for human entries
+	Literal bool      `json:"literal,omitempty"`
+	Keys    []string  `json:"keys,omitempty"`
+	Data    string    `json:"data,omitempty"`
+}
+
+// TranscriptDir returns the transcript directory.
+// Path: <townRoot>/.runtime/transcripts/
+func TranscriptDir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "transcripts")
+}
+
+// TranscriptPath returns the transcript file for a session.
+func TranscriptPath(townRoot, session string) string {
+	return filepath.Join(TranscriptDir(townRoot), session+".jsonl")
+}
+
+// transcriptTownRoot resolves the town that transcripts are written to:
+// GT_ROOT (set in every agent session), else the town containing the cwd.
+// Recording is opt-in, so this is empty (recording off) unless the town
+// settings enable transcripts or GT_TRANSCRIPT=on; GT_TRANSCRIPT=off
+// disables it regardless.
+var transcriptTownRoot = sync.OnceValue(resolveTranscriptTownRoot)
+
+func resolveTranscriptTownRoot() string {
+	env := strings.ToLower(os.Getenv("GT_TRANSCRIPT"))
+	if env == "off" || env == "0" || env == "false" {
+		return ""
+	}
+	root := os.Getenv("GT_ROOT")
+	if root == "" {
+		var err error
+		if root, err = workspace.FindFromCwd(); err != nil || root == "" {
+			return ""
+		}
+	}
+	if env == "on" || env == "1" || env == "true" {
+		return root
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(root))
+	if err != nil || !settings.Transcripts {
+		return ""
+	}
+	return root
+}
+
+var transcriptMu sync.Mutex
+
+// AppendTranscript appends e to its session's transcript. Failures are
+// ignored: a transcript must never get in the way of delivering input.
+func AppendTranscript(townRoot string, e TranscriptEntry) {
+	if townRoot == "" || e.Session == "" || strings.ContainsAny(e.Session, `/\`) {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	transcriptMu.Lock()
+	defer transcriptMu.Unlock()
+	if err := os.MkdirAll(TranscriptDir(townRoot), 0755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(TranscriptPath(townRoot, e.Session), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(append(data, '\n'))
+}
+
+// ReadTranscript returns a session's transcript entries, oldest first.
+// A session without a transcript has no entries.
+func ReadTranscript(townRoot, session string) ([]TranscriptEntry, error) {
+	data, err := os.ReadFile(TranscriptPath(townRoot, session))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entries []TranscriptEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var e TranscriptEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			continue // tolerate a torn last line
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// RecordAttach records that a human attached to session through gt.
+func RecordAttach(session string) {
+	AppendTranscript(transcriptTownRoot(), TranscriptEntry{
+		Session: session,
+		Dir:     TranscriptIn,
+		Source:  SourceHuman,
+		Kind:    "attach",
+	})
+}
+
+// withSource returns a copy of t whose send-keys calls are recorded as
+// coming from source.
+func (t *Tmux) withSource(source string) *Tmux {
+	c := *t
+	c.inputSource = source
+	return &c
+}
+
+// parseSendKeys splits send-keys arguments into the target, whether the
+// keys are literal, and the keys. ok is false for copy-mode commands (-X),
+// which drive tmux itself rather than sending input.
+func parseSendKeys(args []string) (target string, literal bool, keys []string, ok bool) {
+	for i := 1; i < len(args); i++ {
+		switch a := args[i]; a {
+		case "-t":
+			if i+1 < len(args) {
+				target = args[i+1]
+			}
+			i++
+		case "-N":
+			i++
+		case "-l":
+			literal = true
+		case "-X":
+			return "", false, nil, false
+		case "-H", "-R", "-M", "-F":
+		case "--":
+			return target, literal, args[i+1:], true
+		default:
+			return target, literal, args[i:], true
+		}
+	}
+	return target, literal, nil, true
+}
+
+// transcriptSession maps a send-keys target to a session name. Targets may
+// be "session", "session:win.pane", or a pane ID like "%9".
+func (t *Tmux) transcriptSession(target string) string {
+	if strings.HasPrefix(target, "%") {
+		out, err := t.run("display-message", "-p", "-t", target, "#{session_name}")
+		if err != nil {
+			return ""
+		}
+		return out
+	}
+	if i := strings.IndexByte(target, ':'); i >= 0 {
+		target = target[:i]
+	}
+	return strings.TrimPrefix(target, "=")
+}
+
+// recordSendKeys records a successful send-keys call as transcript input.
+func (t *Tmux) recordSendKeys(args []string) {
+	townRoot := transcriptTownRoot()
+	if townRoot == "" {
+		return
+	}
+	target, literal, keys, ok := parseSendKeys(args)
+	if !ok || len(keys) == 0 {
+		return
+	}
+	source := t.inputSource
+	if source == "" {
+		source = SourceKeys
+	}
+	e := TranscriptEntry{
+		Session: t.transcriptSession(target),
+		Dir:     TranscriptIn,
+		Source:  source,
+		Literal: literal,
+	}
+	if literal {
+		e.Data = strings.Join(keys, " ")
+	} else {
+		e.Keys = keys
+	}
+	AppendTranscript(townRoot, e)
+}
+
+// recordOutput records pane content that gastown is about to act on, so the
+// transcript shows what the agent was displaying when input arrived.
+func (t *Tmux) recordOutput(target, content string) {
+	townRoot := transcriptTownRoot()
+	if townRoot == "" || strings.TrimSpace(content) == "" {
+		return
+	}
+	AppendTranscript(townRoot, TranscriptEntry{
+		Session: t.transcriptSession(target),
+		Dir:     TranscriptOut,
+		Source:  t.inputSource,
+		Data:    content,
+	})
+}
+
+// recordOutputContext captures and records the bottom of target's pane
+// when transcripts are enabled.
+func (t *Tmux) recordOutputContext(target string) {
+	if transcriptTownRoot() == "" {
+		return
+	}
+	if content, err := t.run("capture-pane", "-p", "-t", target, "-S", fmt.Sprintf("-%d", transcriptContextLines)); err == nil {
+		t.recordOutput(target, content)
+	}
+}
@@ -0,0 +1,111 @@
+package tmux
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestParseSendKeys(t *testing.T) {
+	tests := []struct {
+		args    []string
+		target  string
+		literal bool
+		keys    []string
+		ok      bool
+	}{
+		{[]string{"send-keys", "-t", "gt-Toast", "-l", "hello world"}, "gt-Toast", true, []string{"hello world"}, true},
+		{[]string{"send-keys", "-t", "%9", "Enter"}, "%9", false, []string{"Enter"}, true},
+		{[]string{"send-keys", "-t", "hq-mayor:0.0", "Down", "Enter"}, "hq-mayor:0.0", false, []string{"Down", "Enter"}, true},
+		{[]string{"send-keys", "-t", "s", "-l", "--", "-rf"}, "s", true, []string{"-rf"}, true},
+		{[]string{"send-keys", "-t", "s", "-X", "cancel"}, "", false, nil, false},
+	}
+	for _, tt := range tests {
+		target, literal, keys, ok := parseSendKeys(tt.args)
+		if target != tt.target || literal != tt.literal || !reflect.DeepEqual(keys, tt.keys) || ok != tt.ok {
+			t.Errorf("parseSendKeys(%q) = %q, %v, %q, %v; want %q, %v, %q, %v",
+				tt.args, target, literal, keys, ok, tt.target, tt.literal, tt.keys, tt.ok)
+		}
+	}
+}
+
+func TestTranscriptSessionStripsWindow(t *testing.T) {
+	tm := NewTmux()
+	for target, want := range map[string]string{
+		"gt-Toast":     "gt-Toast",
+		"hq-mayor:0.0": "hq-mayor",
+		"=gt-Toast":    "gt-Toast",
+	} {
+		if got := tm.transcriptSession(target); got != want {
+			t.Errorf("transcriptSession(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestRecordSendKeysAppendsTranscript(t *testing.T) {
+	townRoot := t.TempDir()
+	orig := transcriptTownRoot
+	transcriptTownRoot = func() string { return townRoot }
+	defer func() { transcriptTownRoot = orig }()
+
+	tm := NewTmux().withSource(SourceNudge)
+	tm.recordSendKeys([]string{"send-keys", "-t", "gt-Toast", "-l", "check mail"})
+	tm.recordSendKeys([]string{"send-keys", "-t", "gt-Toast", "-X", "cancel"})
+	tm.recordSendKeys([]string{"send-keys", "-t", "gt-Toast:0.0", "Enter"})
+	tm.recordOutput("gt-Toast", "❯ ")
+	NewTmux().recordSendKeys([]string{"send-keys", "-t", "gt-Toast", "C-u"})
+	RecordAttach("gt-Toast")
+
+	entries, err := ReadTranscript(townRoot, "gt-Toast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("got %d entries, want 5: %+v", len(entries), entries)
+	}
+	if e := entries[0]; e.Dir != TranscriptIn || e.Source != SourceNudge || !e.Literal || e.Data != "check mail" {
+		t.Errorf("entry 0 = %+v", e)
+	}
+	if e := entries[1]; e.Literal || !reflect.DeepEqual(e.Keys, []string{"Enter"}) {
+		t.Errorf("entry 1 = %+v", e)
+	}
+	if e := entries[2]; e.Dir != TranscriptOut || e.Data != "❯ " {
+		t.Errorf("entry 2 = %+v", e)
+	}
+	if e := entries[3]; e.Source != SourceKeys {
+		t.Errorf("entry 3 source = %q, want %q", e.Source, SourceKeys)
+	}
+	if e := entries[4]; e.Source != SourceHuman || e.Kind != "attach" {
+		t.Errorf("entry 4 = %+v", e)
+	}
+}
+
+func TestReadTranscriptMissing(t *testing.T) {
+	entries, err := ReadTranscript(t.TempDir(), "nope")
+	if err != nil || entries != nil {
+		t.Errorf("ReadTranscript on missing file = %v, %v", entries, err)
+	}
+}
+
+func TestResolveTranscriptTownRootOptIn(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("GT_ROOT", townRoot)
+	t.Setenv("GT_TRANSCRIPT", "")
+	if got := resolveTranscriptTownRoot(); got != "" {
+		t.Errorf("recording on without opting in: %q", got)
+	}
+
+	settings := config.NewTownSettings()
+	settings.Transcripts = true
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	if got := resolveTranscriptTownRoot(); got != townRoot {
+		t.Errorf("town settings opt-in = %q, want %q", got, townRoot)
+	}
+	t.Setenv("GT_TRANSCRIPT", "off")
+	if got := resolveTranscriptTownRoot(); got != "" {
+		t.Errorf("GT_TRANSCRIPT=off still records to %q", got)
+	}
+}
@@ -0,0 +1,169 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrTmuxTooOld is returned when the installed tmux is older than MinVersion.
+var ErrTmuxTooOld = errors.New("tmux version too old")
+
+// Version is a parsed tmux version. Suffix holds letter patch releases
+// ("a" in 3.3a) and pre-release markers ("-rc"). A zero Version with Dev set
+// is a build from source ("tmux master", "tmux next-3.5").
+type Version struct {
+	Major  int
+	Minor  int
+	Suffix string
+	Dev    bool
+}
+
+// MinVersion is the oldest tmux Gas Town supports. Older versions lack
+// respawn-pane -c and the pane_dead_status format used for session health
+// checks.
+var MinVersion = Version{Major: 3, Minor: 0}
+
+// String formats the version the way `tmux -V` does.
+func (v Version) String() string {
+	if v.Dev && v.Major == 0 {
+		return "master"
+	}
+	return fmt.Sprintf("%d.%d%s", v.Major, v.Minor, v.Suffix)
+}
+
+// AtLeast reports whether v is major.minor or newer. Development builds are
+// assumed to support everything.
+func (v Version) AtLeast(major, minor int) bool {
+	if v.Dev {
+		return true
+	}
+	if v.Major != major {
+		return v.Major > major
+	}
+	return v.Minor >= minor
+}
+
+var versionRe = regexp.MustCompile(`(\d+)\.(\d+)([a-z]?(?:-rc\d*)?)`)
+
+// ParseVersion parses `tmux -V` output such as "tmux 3.3a", "tmux 3.4-rc",
+// "tmux next-3.5", or "tmux master".
+func ParseVersion(output string) (Version, error) {
+	s := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(output), "tmux"))
+	if s == "master" {
+		return Version{Dev: true}, nil
+	}
+	m := versionRe.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, fmt.Errorf("unrecognized tmux version %q", output)
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	return Version{
+		Major:  major,
+		Minor:  minor,
+		Suffix: m[3],
+		Dev:    strings.HasPrefix(s, "next-"),
+	}, nil
+}
+
+// Feature is an optional tmux capability that Gas Town gates on version.
+type Feature int
+
+const (
+	// FeatureNewSessionEnv is `new-session -e VAR=value` (tmux 3.2).
+	FeatureNewSessionEnv Feature = iota
+	// FeaturePopup is `display-popup` (tmux 3.2).
+	FeaturePopup
+	// FeatureControlModeFlags is `refresh-client -f` control mode flags
+	// such as pause-after (tmux 3.2).
+	FeatureControlModeFlags
+)
+
+var featureMinVersion = map[Feature]Version{
+	FeatureNewSessionEnv:    {Major: 3, Minor: 2},
+	FeaturePopup:            {Major: 3, Minor: 2},
+	FeatureControlModeFlags: {Major: 3, Minor: 2},
+}
+
+var featureNames = map[Feature]string{
+	FeatureNewSessionEnv:    "new-session -e",
+	FeaturePopup:            "display-popup",
+	FeatureControlModeFlags: "control mode flags",
+}
+
+// String returns a short human-readable name for the feature.
+func (f Feature) String() string {
+	if name, ok := featureNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("Feature(%d)", int(f))
+}
+
+// Supports reports whether v provides feature f.
+func (v Version) Supports(f Feature) bool {
+	req, ok := featureMinVersion[f]
+	if !ok {
+		return false
+	}
+	return v.AtLeast(req.Major, req.Minor)
+}
+
+var (
+	detectOnce    sync.Once
+	detectedVer   Version
+	detectedErr   error
+	detectCommand = func() ([]byte, error) { return exec.Command("tmux", "-V").Output() }
+)
+
+// DetectVersion returns the installed tmux version. The result is cached for
+// the life of the process.
+func DetectVersion() (Version, error) {
+	detectOnce.Do(func() {
+		out, err := detectCommand()
+		if err != nil {
+			detectedErr = fmt.Errorf("running tmux -V: %w", err)
+			return
+		}
+		detectedVer, detectedErr = ParseVersion(string(out))
+	})
+	return detectedVer, detectedErr
+}
+
+// CheckVersion returns an ErrTmuxTooOld error naming the installed and
+// required versions when tmux is older than MinVersion. An undetectable
+// version is reported as an error too, since tmux is then likely missing.
+func CheckVersion() error {
+	v, err := DetectVersion()
+	if err != nil {
+		return err
+	}
+	if !v.AtLeast(MinVersion.Major, MinVersion.Minor) {
+		return fmt.Errorf("%w: found tmux %s, Gas Town requires tmux %s or newer", ErrTmuxTooOld, v, MinVersion)
+	}
+	return nil
+}
+
+// Supports reports whether the installed tmux provides feature f. When the
+// version cannot be detected the feature is assumed present, so detection
+// problems never disable functionality that would otherwise work.
+func (t *Tmux) Supports(f Feature) bool {
+	v, err := DetectVersion()
+	if err != nil {
+		return true
+	}
+	return v.Supports(f)
+}
+
+// isUnknownFlagError reports whether tmux stderr indicates the command or a
+// flag is not understood by the installed version.
+func isUnknownFlagError(stderr string) bool {
+	return strings.Contains(stderr, "unknown flag") ||
+		strings.Contains(stderr, "unknown option") ||
+		strings.Contains(stderr, "unknown command") ||
+		strings.Contains(stderr, "invalid option")
+}
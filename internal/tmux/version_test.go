@@ -0,0 +1,51 @@
+package tmux
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Version
+		wantErr bool
+	}{
+		{in: "tmux 3.3a\n", want: Version{Major: 3, Minor: 3, Suffix: "a"}},
+		{in: "tmux 3.2", want: Version{Major: 3, Minor: 2}},
+		{in: "tmux 3.4-rc", want: Version{Major: 3, Minor: 4, Suffix: "-rc"}},
+		{in: "tmux next-3.5", want: Version{Major: 3, Minor: 5, Dev: true}},
+		{in: "tmux master", want: Version{Dev: true}},
+		{in: "tmux openbsd-7.4", want: Version{Major: 7, Minor: 4}},
+		{in: "garbage", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVersion(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestVersionSupports(t *testing.T) {
+	old := Version{Major: 3, Minor: 1}
+	if old.Supports(FeatureNewSessionEnv) || old.Supports(FeaturePopup) {
+		t.Error("tmux 3.1 should not support new-session -e or display-popup")
+	}
+	if !old.AtLeast(MinVersion.Major, MinVersion.Minor) {
+		t.Error("tmux 3.1 should meet MinVersion")
+	}
+
+	cur := Version{Major: 3, Minor: 3, Suffix: "a"}
+	if !cur.Supports(FeatureNewSessionEnv) || !cur.Supports(FeaturePopup) {
+		t.Error("tmux 3.3a should support new-session -e and display-popup")
+	}
+
+	if (Version{Major: 2, Minor: 9}).AtLeast(MinVersion.Major, MinVersion.Minor) {
+		t.Error("tmux 2.9 should not meet MinVersion")
+	}
+	if !(Version{Dev: true}).Supports(FeaturePopup) {
+		t.Error("development builds should support all features")
+	}
+}
@@ -0,0 +1,198 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrWaitTimeout is wrapped by the *WaitDiagnosis WaitForCommand returns
+// when the pane never leaves the excluded commands.
+var ErrWaitTimeout = errors.New("timeout waiting for command")
+
+// WaitCause classifies why a pane never started its command.
+type WaitCause string
+
+const (
+	// WaitStillAtShell: the pane sits at a shell with nothing but shells
+	// running under it, so the start command never ran or already exited.
+	WaitStillAtShell WaitCause = "still_at_shell"
+
+	// WaitPaneDead: the pane's process exited (kept by remain-on-exit).
+	WaitPaneDead WaitCause = "pane_dead"
+
+	// WaitWrapperNotExeced: the runtime is running, but under a wrapper
+	// shell that never exec'd it, so the pane still reports the shell.
+	WaitWrapperNotExeced WaitCause = "wrapper_not_execed"
+
+	// WaitUnknownRuntime: something other than the session's known runtime
+	// process names is running under the shell, e.g. a custom agent whose
+	// binary doesn't match GT_PROCESS_NAMES, or a wrapper stuck in setup.
+	WaitUnknownRuntime WaitCause = "unknown_runtime"
+
+	// WaitUnknown: the pane could not be inspected.
+	WaitUnknown WaitCause = "unknown"
+)
+
+// waitSnippetLines is how many pane lines an observation keeps.
+const waitSnippetLines = 5
+
+// WaitObservation is the pane as WaitForCommand saw it when its command
+// changed.
+type WaitObservation struct {
+	Elapsed time.Duration `json:"elapsed"`
+	Command string        `json:"command"`          // "" when the pane couldn't be read
+	Output  string        `json:"output,omitempty"` // last lines of the pane
+}
+
+// WaitDiagnosis explains a WaitForCommand timeout so callers can pick a
+// targeted recovery instead of treating every timeout alike.
+type WaitDiagnosis struct {
+	Session      string            `json:"session"`
+	Timeout      time.Duration     `json:"timeout"`
+	Cause        WaitCause         `json:"cause"`
+	Command      string            `json:"command"`                 // final pane command
+	ExitStatus   int               `json:"exit_status"`             // for WaitPaneDead; -1 if unknown
+	Processes    []string          `json:"processes,omitempty"`     // names running under the pane process
+	RuntimeNames []string          `json:"runtime_names,omitempty"` // process names the session's agent runs as
+	Observations []WaitObservation `json:"observations,omitempty"`
+	Output       string            `json:"output,omitempty"` // last lines of the pane at timeout
+}
+
+func (d *WaitDiagnosis) Error() string {
+	msg := fmt.Sprintf("%v after %v: %s", ErrWaitTimeout, d.Timeout, d.Describe())
+	if last := lastLine(d.Output); last != "" {
+		msg += fmt.Sprintf(" (last output: %q)", last)
+	}
+	return msg
+}
+
+func (d *WaitDiagnosis) Unwrap() error { return ErrWaitTimeout }
+
+// Describe says in words what the pane was doing at timeout.
+func (d *WaitDiagnosis) Describe() string {
+	switch d.Cause {
+	case WaitPaneDead:
+		if d.ExitStatus >= 0 {
+			return fmt.Sprintf("pane is dead (exit status %d)", d.ExitStatus)
+		}
+		return "pane is dead"
+	case WaitStillAtShell:
+		return fmt.Sprintf("still at shell (%s)", d.Command)
+	case WaitWrapperNotExeced:
+		return fmt.Sprintf("runtime is running under %s, which never exec'd it", d.Command)
+	case WaitUnknownRuntime:
+		return fmt.Sprintf("%s is running %s, not a known runtime (%s)",
+			d.Command, strings.Join(d.Processes, ", "), strings.Join(d.RuntimeNames, ", "))
+	default:
+		return "pane could not be inspected"
+	}
+}
+
+// DiagnoseWait returns the diagnosis in err if it is a WaitForCommand
+// timeout.
+func DiagnoseWait(err error) (*WaitDiagnosis, bool) {
+	var d *WaitDiagnosis
+	if errors.As(err, &d) {
+		return d, true
+	}
+	return nil, false
+}
+
+// waitRecorder collects what WaitForCommand sees while it polls.
+type waitRecorder struct {
+	t     *Tmux
+	start time.Time
+	obs   []WaitObservation
+}
+
+// observe records the pane command when it differs from the last one seen.
+func (r *waitRecorder) observe(session, cmd string) {
+	if n := len(r.obs); n > 0 && r.obs[n-1].Command == cmd {
+		return
+	}
+	snippet, _ := r.t.CapturePane(session, waitSnippetLines)
+	r.obs = append(r.obs, WaitObservation{
+		Elapsed: time.Since(r.start).Round(time.Millisecond),
+		Command: cmd,
+		Output:  strings.TrimRight(snippet, "\n"),
+	})
+}
+
+// diagnose inspects the pane after a timeout.
+func (r *waitRecorder) diagnose(session string, excludeCommands []string, timeout time.Duration) *WaitDiagnosis {
+	d := &WaitDiagnosis{
+		Session:      session,
+		Timeout:      timeout,
+		ExitStatus:   -1,
+		Observations: r.obs,
+	}
+	if out, err := r.t.CapturePane(session, waitSnippetLines); err == nil {
+		d.Output = strings.TrimRight(out, "\n")
+	}
+	dead, status, deadErr := r.t.PaneDeadStatus(session)
+	d.Command, _ = r.t.GetPaneCommand(session)
+	d.RuntimeNames = r.t.resolveSessionProcessNames(session)
+	if !dead {
+		if pid, err := r.t.GetPanePID(session); err == nil {
+			d.Processes = descendantNames(pid)
+		}
+	}
+	d.ExitStatus = status
+	d.Cause = classifyWait(deadErr == nil && dead, d.Command, d.Processes, excludeCommands, d.RuntimeNames)
+	return d
+}
+
+// classifyWait picks the cause of a timeout from the pane's state: whether
+// it is dead, its command, the processes running under it, the commands
+// being waited out (shells), and the names the session's runtime runs as.
+func classifyWait(dead bool, cmd string, processes, excludeCommands, runtimeNames []string) WaitCause {
+	switch {
+	case dead:
+		return WaitPaneDead
+	case cmd == "":
+		return WaitUnknown
+	case containsAny(processes, runtimeNames):
+		return WaitWrapperNotExeced
+	}
+	for _, p := range processes {
+		if !containsAny([]string{p}, excludeCommands) {
+			return WaitUnknownRuntime
+		}
+	}
+	return WaitStillAtShell
+}
+
+func containsAny(names, set []string) bool {
+	for _, n := range names {
+		for _, s := range set {
+			if n == s {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// descendantNames returns the executable names of pid's descendants.
+func descendantNames(pid string) []string {
+	var names []string
+	for _, child := range getAllDescendants(pid) {
+		out, err := exec.Command("ps", "-p", child, "-o", "comm=").Output()
+		if err != nil {
+			continue
+		}
+		if name := filepath.Base(strings.TrimSpace(string(out))); name != "" && name != "." {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,47 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClassifyWait(t *testing.T) {
+	claude := []string{"claude", "node"}
+	tests := []struct {
+		name      string
+		dead      bool
+		cmd       string
+		processes []string
+		want      WaitCause
+	}{
+		{"dead pane", true, "bash", nil, WaitPaneDead},
+		{"unreadable pane", false, "", nil, WaitUnknown},
+		{"idle shell", false, "bash", nil, WaitStillAtShell},
+		{"nested shell", false, "bash", []string{"bash"}, WaitStillAtShell},
+		{"runtime under wrapper", false, "bash", []string{"sh", "node"}, WaitWrapperNotExeced},
+		{"unrecognized child", false, "zsh", []string{"my-agent"}, WaitUnknownRuntime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyWait(tt.dead, tt.cmd, tt.processes, []string{"bash", "zsh", "sh"}, claude); got != tt.want {
+				t.Errorf("classifyWait = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitDiagnosisError(t *testing.T) {
+	d := &WaitDiagnosis{
+		Timeout:    time.Minute,
+		Cause:      WaitPaneDead,
+		ExitStatus: 127,
+		Output:     "$ claude\nbash: claude: command not found\n",
+	}
+	msg := d.Error()
+	for _, want := range []string{"1m0s", "exit status 127", "command not found"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Error() = %q, want it to mention %q", msg, want)
+		}
+	}
+}
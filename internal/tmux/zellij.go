@@ -0,0 +1,195 @@
+package tmux
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// zellijStartTimeout is how long NewSessionWithCommand waits for a new
+// background session to accept input.
+const zellijStartTimeout = 5 * time.Second
+
+// Zellij runs sessions under zellij (0.40 or later). Sessions are created
+// in the background with the default shell, which then execs the command.
+type Zellij struct {
+	env sessionEnv
+}
+
+// NewZellij returns the zellij backend.
+func NewZellij() *Zellij {
+	return &Zellij{env: newSessionEnv(BackendZellij)}
+}
+
+var _ Multiplexer = (*Zellij)(nil)
+
+func (z *Zellij) Name() string { return BackendZellij }
+
+func (z *Zellij) IsAvailable() bool {
+	_, err := exec.LookPath("zellij")
+	return err == nil
+}
+
+// run executes a zellij command and returns stdout.
+func (z *Zellij) run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("zellij", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("zellij %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("zellij %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// action runs `zellij --session <session> action <args>`.
+func (z *Zellij) action(session string, args ...string) error {
+	_, err := z.run("", append([]string{"--session", session, "action"}, args...)...)
+	return err
+}
+
+func (z *Zellij) NewSessionWithCommand(name, workDir, command string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	if err := checkWorkDir(workDir); err != nil {
+		return err
+	}
+	if running, _ := z.HasSession(name); running {
+		return ErrSessionExists
+	}
+	// A previous session of the same name may linger as resurrectable.
+	_, _ = z.run("", "delete-session", name)
+	z.env.clear(name)
+
+	if _, err := z.run(workDir, "attach", "--create-background", name); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(zellijStartTimeout)
+	for {
+		if running, _ := z.HasSession(name); running {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: %s", ErrSessionDied, name)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if command == "" {
+		return nil
+	}
+	line := "exec " + command
+	if workDir != "" {
+		line = "cd " + shellQuote(workDir) + " && " + line
+	}
+	return z.SendKeys(name, line)
+}
+
+func (z *Zellij) HasSession(name string) (bool, error) {
+	sessions, err := z.ListSessions()
+	if err != nil {
+		return false, err
+	}
+	for _, s := range sessions {
+		if s == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (z *Zellij) ListSessions() ([]string, error) {
+	out, err := z.run("", "list-sessions", "--no-formatting")
+	if err != nil {
+		// zellij exits non-zero when there are no sessions.
+		if strings.Contains(err.Error(), "No active zellij sessions") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseZellijSessions(out), nil
+}
+
+// parseZellijSessions returns the running sessions in `zellij list-sessions
+// --no-formatting` output, skipping exited (resurrectable) ones.
+func parseZellijSessions(out string) []string {
+	var sessions []string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.Contains(line, "EXITED") {
+			continue
+		}
+		sessions = append(sessions, fields[0])
+	}
+	return sessions
+}
+
+func (z *Zellij) KillSession(name string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	running, err := z.HasSession(name)
+	if err != nil {
+		return err
+	}
+	if !running {
+		return ErrSessionNotFound
+	}
+	defer z.env.clear(name)
+	if _, err := z.run("", "kill-session", name); err != nil {
+		return err
+	}
+	_, _ = z.run("", "delete-session", name)
+	return nil
+}
+
+func (z *Zellij) SetEnvironment(session, key, value string) error {
+	return z.env.set(session, key, value)
+}
+
+func (z *Zellij) GetEnvironment(session, key string) (string, error) {
+	return z.env.get(session, key)
+}
+
+func (z *Zellij) SendKeys(session, keys string) error {
+	if err := z.action(session, "write-chars", keys); err != nil {
+		return err
+	}
+	return z.action(session, "write", "13")
+}
+
+func (z *Zellij) NudgeSession(session, message string) error {
+	if running, err := z.HasSession(session); err != nil {
+		return err
+	} else if !running {
+		return ErrSessionNotFound
+	}
+	return z.SendKeys(session, sanitizeNudgeMessage(message))
+}
+
+func (z *Zellij) CapturePane(session string, lines int) (string, error) {
+	dump, err := captureVia(func(path string) error {
+		return z.action(session, "dump-screen", "--full", path)
+	})
+	if err != nil {
+		return "", err
+	}
+	return tailLines(dump, lines), nil
+}
+
+func (z *Zellij) CapturePaneAll(session string) (string, error) {
+	return z.CapturePane(session, 0)
+}
+
+func (z *Zellij) AttachCommand(session string) ([]string, error) {
+	if err := validateSessionName(session); err != nil {
+		return nil, err
+	}
+	return []string{"zellij", "attach", session}, nil
+}
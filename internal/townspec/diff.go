@@ -0,0 +1,349 @@
+package townspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Kind is what a Change does.
+type Kind string
+
+const (
+	// AddRig creates a rig from its spec (gt rig add).
+	AddRig Kind = "add-rig"
+
+	// AddCrew creates a crew workspace (gt crew add).
+	AddCrew Kind = "add-crew"
+
+	// UpdateFile merges spec settings into a config file (Change.Write).
+	UpdateFile Kind = "update-file"
+)
+
+// Change is one step of reconciling the town to its spec.
+type Change struct {
+	Kind Kind
+	Rig  string // AddRig, AddCrew, and UpdateFile of rig settings
+	Crew string // AddCrew
+
+	// Spec is the rig to create (AddRig).
+	Spec *Rig
+
+	// File is the config file to update, relative to the town root, and
+	// Fields the keys that change (UpdateFile).
+	File   string
+	Fields []Field
+
+	path     string
+	overlay  map[string]interface{}
+	defaults func() interface{}
+}
+
+// Field is a settings key whose value changes. Old is nil when the key is
+// unset.
+type Field struct {
+	Key      string
+	Old, New interface{}
+}
+
+// Plan is the set of changes that brings the town to its spec.
+type Plan struct {
+	Changes []*Change
+
+	// Warnings are differences apply can't reconcile, such as an existing
+	// rig declared with another git URL.
+	Warnings []string
+
+	// Unmanaged are rigs and crew the town has but the spec doesn't
+	// declare. Apply leaves them alone.
+	Unmanaged []string
+}
+
+// Empty reports whether the town already matches the spec.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Drifted reports whether the town differs from its spec in any way,
+// including rigs and crew the spec doesn't declare.
+func (p *Plan) Drifted() bool {
+	return len(p.Changes) > 0 || len(p.Warnings) > 0 || len(p.Unmanaged) > 0
+}
+
+// Drift describes how the live town differs from the spec, one line per
+// difference, for people reviewing drift rather than applying a fix.
+func (p *Plan) Drift() []string {
+	var lines []string
+	for _, c := range p.Changes {
+		switch c.Kind {
+		case AddRig:
+			lines = append(lines, fmt.Sprintf("rig %s: declared, missing", c.Rig))
+		case AddCrew:
+			lines = append(lines, fmt.Sprintf("crew %s/crew/%s: declared, missing", c.Rig, c.Crew))
+		default:
+			for _, f := range c.Fields {
+				lines = append(lines, fmt.Sprintf("%s %s: live %s, declared %s",
+					c.File, f.Key, formatValue(f.Old), formatValue(f.New)))
+			}
+		}
+	}
+	lines = append(lines, p.Warnings...)
+	for _, u := range p.Unmanaged {
+		lines = append(lines, u+": live, not declared")
+	}
+	return lines
+}
+
+// String describes the change on one line, followed by one line per
+// changed field.
+func (c *Change) String() string {
+	switch c.Kind {
+	case AddRig:
+		return fmt.Sprintf("+ rig %s (%s)", c.Rig, c.Spec.GitURL)
+	case AddCrew:
+		return fmt.Sprintf("+ crew %s/crew/%s", c.Rig, c.Crew)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "~ %s", c.File)
+	for _, f := range c.Fields {
+		fmt.Fprintf(&b, "\n    %s: %s → %s", f.Key, formatValue(f.Old), formatValue(f.New))
+	}
+	return b.String()
+}
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return "(unset)"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// Diff compares the town at townRoot with spec and returns the changes
+// that reconcile it: town settings first, then each rig (create, settings,
+// crew), then daemon patrols, which rig creation also touches.
+func Diff(townRoot string, spec *Spec) (*Plan, error) {
+	plan := &Plan{}
+
+	rigsPath := filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON)
+	rigs, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			return nil, fmt.Errorf("loading rigs: %w", err)
+		}
+		rigs = &config.RigsConfig{Rigs: map[string]config.RigEntry{}}
+	}
+
+	if c, err := diffFile(townRoot, config.TownSettingsPath(townRoot), spec.Settings,
+		func() interface{} { return config.NewTownSettings() }, &config.TownSettings{}); err != nil {
+		return nil, fmt.Errorf("town settings: %w", err)
+	} else if c != nil {
+		plan.Changes = append(plan.Changes, c)
+	}
+
+	for _, name := range sortedKeys(spec.Rigs) {
+		r := spec.Rigs[name]
+		rigPath := filepath.Join(townRoot, name)
+		entry, exists := rigs.Rigs[name]
+
+		if !exists {
+			if r.GitURL == "" {
+				return nil, fmt.Errorf("rig %s is not in the town and has no git_url to create it from", name)
+			}
+			plan.Changes = append(plan.Changes, &Change{Kind: AddRig, Rig: name, Spec: r})
+		} else {
+			if r.GitURL != "" && r.GitURL != entry.GitURL {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+					"rig %s: git_url is %s, spec says %s (not changed; re-add the rig to move it)", name, entry.GitURL, r.GitURL))
+			}
+			if entry.BeadsConfig != nil && r.Prefix != "" && r.Prefix != entry.BeadsConfig.Prefix {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+					"rig %s: prefix is %s, spec says %s (not changed)", name, entry.BeadsConfig.Prefix, r.Prefix))
+			}
+		}
+
+		c, err := diffFile(townRoot, config.RigSettingsPath(rigPath), r.rigSettings(),
+			func() interface{} { return config.NewRigSettings() }, &config.RigSettings{})
+		if err != nil {
+			return nil, fmt.Errorf("rig %s settings: %w", name, err)
+		}
+		if c != nil {
+			c.Rig = name
+			plan.Changes = append(plan.Changes, c)
+		}
+
+		if r.Crew == nil {
+			continue
+		}
+		have := crewOnDisk(rigPath)
+		want := map[string]bool{}
+		for _, crew := range r.Crew {
+			want[crew] = true
+			if !have[crew] {
+				plan.Changes = append(plan.Changes, &Change{Kind: AddCrew, Rig: name, Crew: crew})
+			}
+		}
+		for _, crew := range sortedKeys(have) {
+			if !want[crew] {
+				plan.Unmanaged = append(plan.Unmanaged, fmt.Sprintf("crew %s/crew/%s", name, crew))
+			}
+		}
+	}
+
+	if c, err := diffFile(townRoot, config.DaemonPatrolConfigPath(townRoot), spec.Daemon,
+		func() interface{} { return config.NewDaemonPatrolConfig() }, nil); err != nil {
+		return nil, fmt.Errorf("daemon config: %w", err)
+	} else if c != nil {
+		plan.Changes = append(plan.Changes, c)
+	}
+
+	for _, name := range sortedKeys(rigs.Rigs) {
+		if _, ok := spec.Rigs[name]; !ok {
+			plan.Unmanaged = append(plan.Unmanaged, "rig "+name)
+		}
+	}
+	return plan, nil
+}
+
+// diffFile returns the change merging overlay into the JSON file at path,
+// or nil if the file already has those values. A missing file starts from
+// defaults(). When typed is non-nil, overlay keys must be fields of it.
+func diffFile(townRoot, path string, overlay map[string]interface{}, defaults func() interface{}, typed interface{}) (*Change, error) {
+	if len(overlay) == 0 {
+		return nil, nil
+	}
+	if typed != nil {
+		data, err := json.Marshal(overlay)
+		if err != nil {
+			return nil, err
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(typed); err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := readJSONMap(path, defaults)
+	if err != nil {
+		return nil, err
+	}
+	fields := diffFields("", current, mergeMaps(current, overlay))
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	rel, err := filepath.Rel(townRoot, path)
+	if err != nil {
+		rel = path
+	}
+	return &Change{
+		Kind:     UpdateFile,
+		File:     filepath.ToSlash(rel),
+		Fields:   fields,
+		path:     path,
+		overlay:  overlay,
+		defaults: defaults,
+	}, nil
+}
+
+// Write applies an UpdateFile change. The file is re-read first, so
+// changes made since Diff (such as by creating the rig) are kept.
+func (c *Change) Write() error {
+	if c.Kind != UpdateFile {
+		return fmt.Errorf("%s is not a file update", c.Kind)
+	}
+	current, err := readJSONMap(c.path, c.defaults)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(mergeMaps(current, c.overlay), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", c.File, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
+		return fmt.Errorf("writing %s: %w", c.File, err)
+	}
+	return nil
+}
+
+// readJSONMap reads a JSON object file, falling back to defaults() when it
+// doesn't exist. Working on the raw object keeps fields the config types
+// don't know about.
+func readJSONMap(path string, defaults func() interface{}) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		data, err = json.Marshal(defaults())
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if m == nil {
+		m = map[string]interface{}{}
+	}
+	return m, nil
+}
+
+// diffFields lists the keys whose values differ between old and new,
+// descending into objects present in both.
+func diffFields(prefix string, old, new map[string]interface{}) []Field {
+	keys := map[string]bool{}
+	for k := range old {
+		keys[k] = true
+	}
+	for k := range new {
+		keys[k] = true
+	}
+	var fields []Field
+	for _, k := range sortedKeys(keys) {
+		ov, nv := old[k], new[k]
+		om, oIsMap := ov.(map[string]interface{})
+		nm, nIsMap := nv.(map[string]interface{})
+		switch {
+		case oIsMap && nIsMap:
+			fields = append(fields, diffFields(prefix+k+".", om, nm)...)
+		case !reflect.DeepEqual(ov, nv):
+			fields = append(fields, Field{Key: prefix + k, Old: ov, New: nv})
+		}
+	}
+	return fields
+}
+
+// crewOnDisk returns the crew workspaces of the rig at rigPath.
+func crewOnDisk(rigPath string) map[string]bool {
+	crew := map[string]bool{}
+	entries, _ := os.ReadDir(filepath.Join(rigPath, constants.DirCrew))
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			crew[e.Name()] = true
+		}
+	}
+	return crew
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,224 @@
+// Package townspec describes a town's desired state — rigs, crew, polecat
+// pool sizes, patrol and scheduler settings — in a directory of YAML files,
+// and compares it with the town on disk. `gt apply` reconciles the town to
+// the spec, so a town can be reproduced from version control.
+//
+// A spec directory holds any number of *.yaml/*.yml files, merged in name
+// order:
+//
+//	settings:            # merged into settings/config.json
+//	  scheduler:
+//	    max_polecats: 6
+//	daemon:              # merged into mayor/daemon.json
+//	  patrols:
+//	    witness: {enabled: true, interval: 5m}
+//	rigs:
+//	  gastown:
+//	    git_url: https://github.com/example/gastown.git
+//	    prefix: gt
+//	    crew: [max, joe]
+//	    pool_size: 4       # worktree_pool.size
+//	    settings:          # merged into <rig>/settings/config.json
+//	      merge_queue: {run_tests: true}
+//
+// Settings use the JSON field names of the files they are merged into.
+// Applying only adds and updates: rigs, crew and settings keys missing from
+// the spec are left alone and reported as unmanaged.
+package townspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is a town's desired state.
+type Spec struct {
+	// Settings is merged into the town settings (settings/config.json).
+	Settings map[string]interface{} `yaml:"settings,omitempty"`
+
+	// Daemon is merged into the daemon patrol config (mayor/daemon.json).
+	Daemon map[string]interface{} `yaml:"daemon,omitempty"`
+
+	// Rigs are the town's rigs by name.
+	Rigs map[string]*Rig `yaml:"rigs,omitempty"`
+}
+
+// Rig is a rig's desired state.
+type Rig struct {
+	// GitURL, PushURL, Prefix and Branch are used to create the rig when it
+	// doesn't exist. An existing rig is never re-cloned.
+	GitURL  string `yaml:"git_url,omitempty"`
+	PushURL string `yaml:"push_url,omitempty"`
+	Prefix  string `yaml:"prefix,omitempty"`
+	Branch  string `yaml:"branch,omitempty"`
+
+	// Crew are the crew workspaces the rig should have. Nil leaves crew
+	// unmanaged.
+	Crew []string `yaml:"crew,omitempty"`
+
+	// PoolSize is shorthand for settings worktree_pool.size: the number of
+	// warm worktrees kept for spawning polecats.
+	PoolSize *int `yaml:"pool_size,omitempty"`
+
+	// Settings is merged into the rig settings (<rig>/settings/config.json).
+	Settings map[string]interface{} `yaml:"settings,omitempty"`
+}
+
+// Load reads and merges the spec files in dir. Settings from later files
+// override earlier ones key by key; a rig may be declared in one file only.
+func Load(dir string) (*Spec, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading spec directory: %w", err)
+	}
+	var files []string
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if !e.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, e.Name())
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no *.yaml files in %s", dir)
+	}
+	sort.Strings(files)
+
+	spec := &Spec{}
+	rigFiles := map[string]string{}
+	for _, name := range files {
+		part, err := loadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		spec.Settings = mergeMaps(spec.Settings, part.Settings)
+		spec.Daemon = mergeMaps(spec.Daemon, part.Daemon)
+		for rigName, r := range part.Rigs {
+			if prev, ok := rigFiles[rigName]; ok {
+				return nil, fmt.Errorf("%s: rig %q is already declared in %s", name, rigName, prev)
+			}
+			if r == nil {
+				r = &Rig{}
+			}
+			if spec.Rigs == nil {
+				spec.Rigs = map[string]*Rig{}
+			}
+			spec.Rigs[rigName] = r
+			rigFiles[rigName] = name
+		}
+	}
+	return spec, spec.validate()
+}
+
+// loadFile parses one spec file. Unknown top-level and rig keys are errors,
+// so typos don't silently leave the town unchanged.
+func loadFile(path string) (*Spec, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the user's spec file
+	if err != nil {
+		return nil, err
+	}
+	var spec Spec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	// YAML values are converted to their JSON equivalents here so the rest
+	// of the package only deals in JSON-shaped maps.
+	if spec.Settings, err = jsonMap(spec.Settings); err != nil {
+		return nil, fmt.Errorf("settings: %w", err)
+	}
+	if spec.Daemon, err = jsonMap(spec.Daemon); err != nil {
+		return nil, fmt.Errorf("daemon: %w", err)
+	}
+	for name, r := range spec.Rigs {
+		if r == nil {
+			continue
+		}
+		if r.Settings, err = jsonMap(r.Settings); err != nil {
+			return nil, fmt.Errorf("rig %s settings: %w", name, err)
+		}
+	}
+	return &spec, nil
+}
+
+func (s *Spec) validate() error {
+	for name, r := range s.Rigs {
+		if name == "" || strings.ContainsAny(name, "/\\ ") {
+			return fmt.Errorf("invalid rig name %q", name)
+		}
+		if r.PoolSize != nil && *r.PoolSize < 0 {
+			return fmt.Errorf("rig %s: pool_size must not be negative", name)
+		}
+		seen := map[string]bool{}
+		for _, c := range r.Crew {
+			if c == "" || strings.ContainsAny(c, "/\\ ") {
+				return fmt.Errorf("rig %s: invalid crew name %q", name, c)
+			}
+			if seen[c] {
+				return fmt.Errorf("rig %s: crew %q listed twice", name, c)
+			}
+			seen[c] = true
+		}
+	}
+	return nil
+}
+
+// rigSettings is the overlay for a rig's settings file, with PoolSize
+// folded in.
+func (r *Rig) rigSettings() map[string]interface{} {
+	overlay := mergeMaps(nil, r.Settings)
+	if r.PoolSize != nil {
+		overlay = mergeMaps(overlay, map[string]interface{}{
+			"worktree_pool": map[string]interface{}{"size": float64(*r.PoolSize)},
+		})
+	}
+	return overlay
+}
+
+// jsonMap round-trips a YAML-decoded map through JSON, normalizing numbers
+// to float64 and rejecting values JSON can't represent.
+func jsonMap(m map[string]interface{}) (map[string]interface{}, error) {
+	if m == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+// mergeMaps returns a copy of dst with src merged in: nested objects merge
+// key by key, anything else (including lists) is replaced.
+func mergeMaps(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil && src == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(dst)+len(src))
+	for k, v := range dst {
+		out[k] = v
+	}
+	for k, v := range src {
+		sm, sok := v.(map[string]interface{})
+		dm, dok := out[k].(map[string]interface{})
+		if sok && dok {
+			out[k] = mergeMaps(dm, sm)
+		} else if sok {
+			out[k] = mergeMaps(nil, sm)
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,194 @@
+package townspec
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadMergesFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "10-town.yaml"), `
+settings:
+  scheduler: {max_polecats: 4, batch_size: 2}
+rigs:
+  gastown:
+    git_url: https://example.com/gastown.git
+    crew: [max]
+    pool_size: 3
+`)
+	writeFile(t, filepath.Join(dir, "20-override.yml"), `
+settings:
+  scheduler: {max_polecats: 6}
+`)
+	writeFile(t, filepath.Join(dir, "README.md"), "not a spec")
+
+	spec, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	sched := spec.Settings["scheduler"].(map[string]interface{})
+	if sched["max_polecats"] != float64(6) || sched["batch_size"] != float64(2) {
+		t.Errorf("scheduler = %v, want max_polecats 6 from the later file and batch_size 2 kept", sched)
+	}
+	r := spec.Rigs["gastown"]
+	if r == nil || r.PoolSize == nil || *r.PoolSize != 3 || len(r.Crew) != 1 {
+		t.Fatalf("rig gastown = %+v", r)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := map[string][]string{
+		"unknown key":   {"rigs:\n  gastown:\n    gitt_url: x\n"},
+		"duplicate rig": {"rigs:\n  gastown: {}\n", "rigs:\n  gastown: {}\n"},
+		"bad crew":      {"rigs:\n  gastown:\n    crew: [a, a]\n"},
+	}
+	for name, files := range tests {
+		dir := t.TempDir()
+		for i, content := range files {
+			writeFile(t, filepath.Join(dir, string(rune('a'+i))+".yaml"), content)
+		}
+		if _, err := Load(dir); err == nil {
+			t.Errorf("%s: Load() succeeded, want error", name)
+		}
+	}
+	if _, err := Load(t.TempDir()); err == nil {
+		t.Error("Load(empty dir) succeeded, want error")
+	}
+}
+
+func TestDiffAndWrite(t *testing.T) {
+	town := t.TempDir()
+	writeFile(t, filepath.Join(town, "mayor", "rigs.json"), `{"version":1,"rigs":{
+		"gastown":{"git_url":"https://example.com/gastown.git","beads":{"repo":"local","prefix":"gt"}},
+		"legacy":{"git_url":"https://example.com/legacy.git"}}}`)
+	writeFile(t, filepath.Join(town, "gastown", "settings", "config.json"),
+		`{"type":"rig-settings","version":1,"custom_key":"kept","worktree_pool":{"size":1}}`)
+	if err := os.MkdirAll(filepath.Join(town, "gastown", "crew", "max"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(town, "gastown", "crew", "bob"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	pool := 4
+	spec := &Spec{
+		Settings: map[string]interface{}{"scheduler": map[string]interface{}{"max_polecats": float64(6)}},
+		Rigs: map[string]*Rig{
+			"gastown": {GitURL: "https://example.com/moved.git", Crew: []string{"max", "joe"}, PoolSize: &pool},
+			"newrig":  {GitURL: "https://example.com/new.git"},
+		},
+	}
+	plan, err := Diff(town, spec)
+	if err != nil {
+		t.Fatalf("Diff() error: %v", err)
+	}
+
+	var got []string
+	for _, c := range plan.Changes {
+		got = append(got, strings.SplitN(c.String(), "\n", 2)[0])
+	}
+	want := []string{
+		"~ settings/config.json",
+		"~ gastown/settings/config.json",
+		"+ crew gastown/crew/joe",
+		"+ rig newrig (https://example.com/new.git)",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("changes = %q, want %q", got, want)
+	}
+	if rigChange := plan.Changes[1]; len(rigChange.Fields) != 1 || rigChange.Fields[0].Key != "worktree_pool.size" {
+		t.Errorf("rig settings fields = %+v, want only worktree_pool.size", rigChange.Fields)
+	}
+	if len(plan.Warnings) != 1 || !strings.Contains(plan.Warnings[0], "moved.git") {
+		t.Errorf("warnings = %q, want the git_url mismatch", plan.Warnings)
+	}
+	if strings.Join(plan.Unmanaged, "|") != "crew gastown/crew/bob|rig legacy" {
+		t.Errorf("unmanaged = %q", plan.Unmanaged)
+	}
+
+	for _, c := range plan.Changes {
+		if c.Kind == UpdateFile {
+			if err := c.Write(); err != nil {
+				t.Fatalf("Write(%s) error: %v", c.File, err)
+			}
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(town, "gastown", "settings", "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written map[string]interface{}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	if written["custom_key"] != "kept" || written["worktree_pool"].(map[string]interface{})["size"] != float64(4) {
+		t.Errorf("rig settings after write = %s", data)
+	}
+
+	delete(spec.Rigs, "newrig")
+	spec.Rigs["gastown"].Crew = nil
+	plan, err = Diff(town, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Errorf("second Diff() changes = %v, want none", plan.Changes)
+	}
+}
+
+func TestDiffRejectsUnknownSettings(t *testing.T) {
+	spec := &Spec{Settings: map[string]interface{}{"schedulr": map[string]interface{}{}}}
+	if _, err := Diff(t.TempDir(), spec); err == nil || !strings.Contains(err.Error(), "schedulr") {
+		t.Errorf("Diff() error = %v, want unknown field schedulr", err)
+	}
+}
+
+func TestDiffNewRigNeedsURL(t *testing.T) {
+	spec := &Spec{Rigs: map[string]*Rig{"gastown": {Crew: []string{"max"}}}}
+	if _, err := Diff(t.TempDir(), spec); err == nil {
+		t.Error("Diff() succeeded for a new rig without git_url")
+	}
+}
+
+func TestDrift(t *testing.T) {
+	town := t.TempDir()
+	writeFile(t, filepath.Join(town, "mayor", "rigs.json"), `{"version":1,"rigs":{
+		"gastown":{"git_url":"https://example.com/gastown.git"},
+		"handmade":{"git_url":"https://example.com/handmade.git"}}}`)
+	writeFile(t, filepath.Join(town, "mayor", "daemon.json"),
+		`{"type":"daemon-patrol-config","version":1,"patrols":{"witness":{"enabled":true,"interval":"10m"}}}`)
+
+	spec := &Spec{
+		Daemon: map[string]interface{}{"patrols": map[string]interface{}{
+			"witness": map[string]interface{}{"interval": "5m"}}},
+		Rigs: map[string]*Rig{"gastown": {Crew: []string{"max"}}},
+	}
+	plan, err := Diff(town, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"crew gastown/crew/max: declared, missing",
+		`mayor/daemon.json patrols.witness.interval: live "10m", declared "5m"`,
+		"rig handmade: live, not declared",
+	}
+	if got := plan.Drift(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Drift() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !plan.Drifted() {
+		t.Error("Drifted() = false")
+	}
+}
@@ -0,0 +1,285 @@
+// Package townstate backs up and restores a town's non-issue state: the
+// orchestration config (rig registry and settings, daemon and escalation
+// config, role definitions, hooks, plugins, formulas) that issue backups
+// don't cover. Losing the workstation otherwise loses all of it even when
+// the issues survive.
+package townstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+const (
+	// Dir is the directory a snapshot is written to within the backup repo.
+	Dir = "town-state"
+
+	// ManifestFile describes a snapshot (see Manifest).
+	ManifestFile = "manifest.json"
+
+	// townPrefix and homePrefix root a snapshot's files: town state is
+	// stored relative to the town root, hooks config relative to $HOME.
+	townPrefix = "town"
+	homePrefix = "home"
+)
+
+// townPaths are the town-level state paths, relative to the town root.
+// Directories are copied recursively; missing paths are skipped.
+var townPaths = []string{
+	"mayor/town.json",
+	"mayor/rigs.json",
+	"mayor/accounts.json",
+	"mayor/config.json",
+	"mayor/daemon.json",
+	"mayor/overseer.json",
+	"mayor/.claude/settings.json",
+	"deacon/.claude/settings.json",
+	"settings",
+	"config",
+	"roles",
+	"hooks",
+	"plugins",
+	".beads/routes.jsonl",
+	".beads/formulas",
+	"CLAUDE.md",
+}
+
+// rigPaths are the per-rig state paths, relative to the rig directory.
+var rigPaths = []string{
+	"config.json",
+	"settings",
+	"roles",
+	"plugins",
+}
+
+// homePaths are the user-level hooks config paths, relative to $HOME.
+var homePaths = []string{
+	".gt/hooks-base.json",
+	".gt/hooks-overrides",
+}
+
+// Manifest describes a town-state snapshot. It carries no timestamp so an
+// unchanged town snapshots identically and the backup repo sees no change.
+type Manifest struct {
+	Host     string   `json:"host,omitempty"`
+	TownRoot string   `json:"town_root"`
+	Rigs     []string `json:"rigs,omitempty"`
+	Files    []string `json:"files"` // Slash paths under "town/" or "home/"
+}
+
+// Paths returns the state paths to back up for the town at townRoot,
+// relative to it and including each registered rig's, and the rig names.
+func Paths(townRoot string) ([]string, []string) {
+	paths := append([]string(nil), townPaths...)
+	rigs := rigNames(townRoot)
+	for _, rig := range rigs {
+		for _, p := range rigPaths {
+			paths = append(paths, rig+"/"+p)
+		}
+	}
+	return paths, rigs
+}
+
+func rigNames(townRoot string) []string {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Snapshot copies the town's state into dest, replacing any previous
+// snapshot there, and writes its manifest.
+func Snapshot(townRoot, dest string) (*Manifest, error) {
+	paths, rigs := Paths(townRoot)
+	for _, prefix := range []string{townPrefix, homePrefix} {
+		if err := os.RemoveAll(filepath.Join(dest, prefix)); err != nil {
+			return nil, fmt.Errorf("clearing previous snapshot: %w", err)
+		}
+	}
+
+	host, _ := os.Hostname()
+	m := &Manifest{Host: host, TownRoot: townRoot, Rigs: rigs}
+	copied, err := copyPaths(townRoot, filepath.Join(dest, townPrefix), paths)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range copied {
+		m.Files = append(m.Files, townPrefix+"/"+f)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		copied, err := copyPaths(home, filepath.Join(dest, homePrefix), homePaths)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range copied {
+			m.Files = append(m.Files, homePrefix+"/"+f)
+		}
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dest, ManifestFile), append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: config backup, not secret
+		return nil, fmt.Errorf("writing manifest: %w", err)
+	}
+	return m, nil
+}
+
+// ReadManifest reads the manifest of the snapshot in dir.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile)) //nolint:gosec // G304: path from the user's restore source
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ManifestFile, err)
+	}
+	return &m, nil
+}
+
+// FindSnapshot returns the snapshot directory in src: src itself, or its
+// town-state/ subdirectory (the backup repo root).
+func FindSnapshot(src string) (string, error) {
+	for _, dir := range []string{src, filepath.Join(src, Dir)} {
+		if _, err := os.Stat(filepath.Join(dir, ManifestFile)); err == nil {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("no town-state snapshot (%s) in %s or %s", ManifestFile, src, filepath.Join(src, Dir))
+}
+
+// RestoreOptions controls Restore.
+type RestoreOptions struct {
+	// Force overwrites files that already exist; otherwise they're skipped.
+	Force bool
+
+	// DryRun reports what would be restored without writing anything.
+	DryRun bool
+
+	// HomeDir is where home/ files are restored (default: $HOME).
+	HomeDir string
+}
+
+// RestoreResult lists the files a restore wrote and skipped, as manifest
+// paths.
+type RestoreResult struct {
+	Restored []string
+	Skipped  []string
+}
+
+// Restore copies the snapshot in dir into the town at townRoot, and hooks
+// config into the home directory.
+func Restore(dir, townRoot string, opts RestoreOptions) (*RestoreResult, error) {
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	home := opts.HomeDir
+	if home == "" {
+		if home, err = os.UserHomeDir(); err != nil {
+			return nil, fmt.Errorf("resolving home directory: %w", err)
+		}
+	}
+
+	result := &RestoreResult{}
+	for _, f := range m.Files {
+		prefix, rel, ok := strings.Cut(f, "/")
+		if !ok || !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return nil, fmt.Errorf("invalid path in manifest: %q", f)
+		}
+		var target string
+		switch prefix {
+		case townPrefix:
+			target = filepath.Join(townRoot, filepath.FromSlash(rel))
+		case homePrefix:
+			target = filepath.Join(home, filepath.FromSlash(rel))
+		default:
+			return nil, fmt.Errorf("invalid path in manifest: %q", f)
+		}
+
+		if _, err := os.Stat(target); err == nil && !opts.Force {
+			result.Skipped = append(result.Skipped, f)
+			continue
+		}
+		if !opts.DryRun {
+			if err := copyFile(filepath.Join(dir, filepath.FromSlash(f)), target); err != nil {
+				return result, fmt.Errorf("restoring %s: %w", f, err)
+			}
+		}
+		result.Restored = append(result.Restored, f)
+	}
+	return result, nil
+}
+
+// copyPaths copies each path under root that exists into dest, keeping its
+// relative location, and returns the copied files as sorted slash paths.
+func copyPaths(root, dest string, paths []string) ([]string, error) {
+	var copied []string
+	for _, p := range paths {
+		src := filepath.Join(root, filepath.FromSlash(p))
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if d.Name() == constants.DirRuntime {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			if err := copyFile(path, filepath.Join(dest, rel)); err != nil {
+				return err
+			}
+			copied = append(copied, filepath.ToSlash(rel))
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("copying %s: %w", p, err)
+		}
+	}
+	sort.Strings(copied)
+	return copied, nil
+}
+
+// copyFile copies a single file preserving its permissions, creating the
+// destination's parent directories.
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(src) //nolint:gosec // G304: paths come from the fixed state path list
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, info.Mode().Perm())
+}
@@ -0,0 +1,154 @@
+package townstate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	townRoot := t.TempDir()
+	writeFile(t, filepath.Join(townRoot, "mayor", "town.json"), `{"name":"test"}`)
+	writeFile(t, filepath.Join(townRoot, "mayor", "rigs.json"), `{"version":1,"rigs":{"gastown":{"git_url":"https://example.com/gastown.git"}}}`)
+	writeFile(t, filepath.Join(townRoot, "settings", "escalation.json"), `{}`)
+	writeFile(t, filepath.Join(townRoot, "roles", "witness.toml"), "role = \"witness\"\n")
+	writeFile(t, filepath.Join(townRoot, "gastown", "settings", "config.json"), `{"type":"rig-settings"}`)
+	writeFile(t, filepath.Join(townRoot, "gastown", "settings", ".runtime", "lock"), "x")
+	writeFile(t, filepath.Join(townRoot, "gastown", "polecats", "Toast", "README.md"), "not state")
+	writeFile(t, filepath.Join(home, ".gt", "hooks-base.json"), `{"hooks":[]}`)
+
+	dest := filepath.Join(t.TempDir(), Dir)
+	writeFile(t, filepath.Join(dest, townPrefix, "stale.json"), "{}")
+	m, err := Snapshot(townRoot, dest)
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	wantFiles := []string{
+		"town/gastown/settings/config.json",
+		"town/mayor/rigs.json",
+		"town/mayor/town.json",
+		"town/roles/witness.toml",
+		"town/settings/escalation.json",
+		"home/.gt/hooks-base.json",
+	}
+	gotFiles := append([]string(nil), m.Files...)
+	if !sameSet(gotFiles, wantFiles) {
+		t.Errorf("Files = %v, want %v", gotFiles, wantFiles)
+	}
+	if !reflect.DeepEqual(m.Rigs, []string{"gastown"}) {
+		t.Errorf("Rigs = %v, want [gastown]", m.Rigs)
+	}
+	if _, err := os.Stat(filepath.Join(dest, townPrefix, "stale.json")); !os.IsNotExist(err) {
+		t.Error("Snapshot kept a file from the previous snapshot")
+	}
+
+	// Restore onto a fresh town that already has a default town.json.
+	newTown := t.TempDir()
+	newHome := t.TempDir()
+	writeFile(t, filepath.Join(newTown, "mayor", "town.json"), `{"name":"default"}`)
+
+	dir, err := FindSnapshot(filepath.Dir(dest))
+	if err != nil {
+		t.Fatalf("FindSnapshot(repo root): %v", err)
+	}
+	result, err := Restore(dir, newTown, RestoreOptions{HomeDir: newHome})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if !reflect.DeepEqual(result.Skipped, []string{"town/mayor/town.json"}) {
+		t.Errorf("Skipped = %v, want [town/mayor/town.json]", result.Skipped)
+	}
+	if got := readFile(t, filepath.Join(newTown, "gastown", "settings", "config.json")); got != `{"type":"rig-settings"}` {
+		t.Errorf("restored rig settings = %q", got)
+	}
+	if got := readFile(t, filepath.Join(newHome, ".gt", "hooks-base.json")); got != `{"hooks":[]}` {
+		t.Errorf("restored hooks base = %q", got)
+	}
+	if got := readFile(t, filepath.Join(newTown, "mayor", "town.json")); got != `{"name":"default"}` {
+		t.Errorf("existing town.json overwritten without Force: %q", got)
+	}
+
+	if _, err := Restore(dir, newTown, RestoreOptions{HomeDir: newHome, Force: true}); err != nil {
+		t.Fatalf("Restore(Force): %v", err)
+	}
+	if got := readFile(t, filepath.Join(newTown, "mayor", "town.json")); got != `{"name":"test"}` {
+		t.Errorf("town.json after Force = %q", got)
+	}
+}
+
+func TestRestoreDryRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	townRoot := t.TempDir()
+	writeFile(t, filepath.Join(townRoot, "mayor", "daemon.json"), `{}`)
+	dest := t.TempDir()
+	if _, err := Snapshot(townRoot, dest); err != nil {
+		t.Fatal(err)
+	}
+
+	newTown := t.TempDir()
+	result, err := Restore(dest, newTown, RestoreOptions{DryRun: true, HomeDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Restored, []string{"town/mayor/daemon.json"}) {
+		t.Errorf("Restored = %v", result.Restored)
+	}
+	if _, err := os.Stat(filepath.Join(newTown, "mayor", "daemon.json")); !os.IsNotExist(err) {
+		t.Error("dry run wrote a file")
+	}
+}
+
+func TestRestoreRejectsEscapingPaths(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ManifestFile), `{"town_root":"/gt","files":["town/../../etc/passwd"]}`)
+	if _, err := Restore(dir, t.TempDir(), RestoreOptions{HomeDir: t.TempDir()}); err == nil {
+		t.Error("Restore accepted a path escaping the town root")
+	}
+}
+
+func TestFindSnapshotMissing(t *testing.T) {
+	if _, err := FindSnapshot(t.TempDir()); err == nil {
+		t.Error("FindSnapshot found a snapshot in an empty directory")
+	}
+}
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int)
+	for _, s := range a {
+		seen[s]++
+	}
+	for _, s := range b {
+		seen[s]--
+	}
+	for _, n := range seen {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
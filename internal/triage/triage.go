@@ -0,0 +1,243 @@
+// Package triage suggests priority, type, rig, and likely duplicates for
+// new issues by comparing them against the issues a town already has.
+//
+// Suggestions are heuristics: keyword rules for priority and type, and
+// word-overlap similarity for duplicates and rig placement. They are meant
+// to speed up a human (or an agent) making the call, not to replace it.
+package triage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DuplicateThreshold is the minimum similarity for an existing issue to be
+// reported as a possible duplicate.
+const DuplicateThreshold = 0.5
+
+// rigThreshold is the minimum similarity for an issue to count towards
+// suggesting its rig.
+const rigThreshold = 0.2
+
+// maxDuplicates caps the duplicate candidates returned per issue.
+const maxDuplicates = 3
+
+// Issue is the subset of an issue that triage looks at.
+type Issue struct {
+	Rig         string `json:"rig,omitempty"`
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Priority    int    `json:"priority"`
+	Status      string `json:"status,omitempty"`
+}
+
+// Match is an existing issue similar to the one being triaged.
+type Match struct {
+	Rig    string  `json:"rig,omitempty"`
+	ID     string  `json:"id"`
+	Title  string  `json:"title"`
+	Status string  `json:"status,omitempty"`
+	Score  float64 `json:"score"`
+}
+
+// Suggestion is the triage recommendation for one issue.
+type Suggestion struct {
+	Issue      Issue    `json:"issue"`
+	Priority   int      `json:"priority"`
+	Type       string   `json:"type"`
+	Rig        string   `json:"rig,omitempty"`
+	Duplicates []Match  `json:"duplicates,omitempty"`
+	Reasons    []string `json:"reasons,omitempty"`
+}
+
+// stopwords are too common in issue text to say anything about similarity.
+var stopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true,
+	"this": true, "from": true, "when": true, "into": true, "should": true,
+	"not": true, "are": true, "was": true, "but": true, "have": true,
+	"has": true, "can": true, "does": true, "will": true, "its": true,
+	"all": true, "any": true, "after": true, "before": true, "use": true,
+	"add": true, "fix": true, "make": true, "issue": true,
+}
+
+// Tokens returns the distinct significant words in text, lowercased.
+func Tokens(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make(map[string]bool, len(words))
+	for _, w := range words {
+		if len(w) < 3 || stopwords[w] {
+			continue
+		}
+		tokens[w] = true
+	}
+	return tokens
+}
+
+// Similarity returns the Jaccard similarity of two token sets, from 0 (no
+// words in common) to 1 (identical).
+func Similarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// issueTokens tokenizes an issue's title and description. The title is the
+// best summary of an issue, so long descriptions do not drown it out: only
+// the first line of the description is used.
+func issueTokens(issue Issue) map[string]bool {
+	desc, _, _ := strings.Cut(issue.Description, "\n")
+	return Tokens(issue.Title + " " + desc)
+}
+
+// FindSimilar returns corpus issues whose similarity to issue is at least
+// threshold, best first, capped at limit (no cap when limit <= 0). The issue
+// itself is skipped if it appears in the corpus.
+func FindSimilar(issue Issue, corpus []Issue, threshold float64, limit int) []Match {
+	tokens := issueTokens(issue)
+	var matches []Match
+	for _, other := range corpus {
+		if other.ID == issue.ID {
+			continue
+		}
+		score := Similarity(tokens, issueTokens(other))
+		if score < threshold {
+			continue
+		}
+		matches = append(matches, Match{
+			Rig:    other.Rig,
+			ID:     other.ID,
+			Title:  other.Title,
+			Status: other.Status,
+			Score:  score,
+		})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// priorityRules are checked in order; the first match wins.
+var priorityRules = []struct {
+	words    []string
+	priority int
+}{
+	{[]string{"security", "vulnerability", "data loss", "corrupt", "outage", "production down"}, 0},
+	{[]string{"crash", "crashes", "panic", "broken", "regression", "blocker", "deadlock", "hang", "hangs"}, 1},
+	{[]string{"typo", "cosmetic", "docs", "documentation", "nit", "cleanup", "someday"}, 3},
+}
+
+// DefaultPriority is suggested when no priority keyword matches.
+const DefaultPriority = 2
+
+// typeRules are checked in order; the first match wins.
+var typeRules = []struct {
+	words []string
+	value string
+}{
+	{[]string{"bug", "crash", "crashes", "panic", "error", "fails", "failing", "failure", "broken", "regression", "wrong", "incorrect"}, "bug"},
+	{[]string{"add", "support", "feature", "implement", "new", "allow", "introduce"}, "feature"},
+	{[]string{"refactor", "cleanup", "clean up", "rename", "bump", "upgrade", "chore"}, "chore"},
+}
+
+// DefaultType is suggested when no type keyword matches.
+const DefaultType = "task"
+
+// keywordText lowercases an issue and pads each word with spaces so
+// keywords match whole words only ("hang" must not match "change").
+func keywordText(issue Issue) string {
+	words := strings.FieldsFunc(strings.ToLower(issue.Title+" "+issue.Description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return " " + strings.Join(words, " ") + " "
+}
+
+// matchKeyword returns the first of words that appears in text as a whole
+// word or phrase.
+func matchKeyword(text string, words []string) string {
+	for _, w := range words {
+		if strings.Contains(text, " "+w+" ") {
+			return w
+		}
+	}
+	return ""
+}
+
+// SuggestPriority suggests a priority (0-4) from keywords in the issue and
+// explains why.
+func SuggestPriority(issue Issue) (int, string) {
+	text := keywordText(issue)
+	for _, rule := range priorityRules {
+		if w := matchKeyword(text, rule.words); w != "" {
+			return rule.priority, fmt.Sprintf("mentions %q", w)
+		}
+	}
+	return DefaultPriority, "no urgency keywords"
+}
+
+// SuggestType suggests an issue type from keywords in the issue and
+// explains why.
+func SuggestType(issue Issue) (string, string) {
+	text := keywordText(issue)
+	for _, rule := range typeRules {
+		if w := matchKeyword(text, rule.words); w != "" {
+			return rule.value, fmt.Sprintf("mentions %q", w)
+		}
+	}
+	return DefaultType, "no type keywords"
+}
+
+// SuggestRig suggests the rig whose issues are most similar to issue, or ""
+// when nothing in the corpus is similar enough to say.
+func SuggestRig(issue Issue, corpus []Issue) string {
+	scores := make(map[string]float64)
+	for _, m := range FindSimilar(issue, corpus, rigThreshold, 0) {
+		if m.Rig != "" {
+			scores[m.Rig] += m.Score
+		}
+	}
+	best, bestScore := "", 0.0
+	for rig, score := range scores {
+		if score > bestScore || (score == bestScore && rig < best) {
+			best, bestScore = rig, score
+		}
+	}
+	return best
+}
+
+// Suggest builds the triage suggestion for issue against corpus, which
+// should hold the town's existing issues (open and closed).
+func Suggest(issue Issue, corpus []Issue) Suggestion {
+	s := Suggestion{Issue: issue}
+
+	var reason string
+	s.Priority, reason = SuggestPriority(issue)
+	s.Reasons = append(s.Reasons, "priority: "+reason)
+
+	s.Type, reason = SuggestType(issue)
+	s.Reasons = append(s.Reasons, "type: "+reason)
+
+	if rig := SuggestRig(issue, corpus); rig != "" {
+		s.Rig = rig
+		if rig != issue.Rig {
+			s.Reasons = append(s.Reasons, "rig: similar issues live in "+rig)
+		}
+	}
+
+	s.Duplicates = FindSimilar(issue, corpus, DuplicateThreshold, maxDuplicates)
+	return s
+}
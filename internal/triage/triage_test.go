@@ -0,0 +1,66 @@
+package triage
+
+import "testing"
+
+func TestSimilarity(t *testing.T) {
+	a := Tokens("Parser crashes on empty config file")
+	b := Tokens("Crash: parser crashes when config file is empty")
+	c := Tokens("Add dark mode to the dashboard")
+
+	if got := Similarity(a, a); got != 1 {
+		t.Errorf("Similarity(a, a) = %v, want 1", got)
+	}
+	if got := Similarity(a, b); got < DuplicateThreshold {
+		t.Errorf("Similarity(a, b) = %v, want >= %v", got, DuplicateThreshold)
+	}
+	if got := Similarity(a, c); got != 0 {
+		t.Errorf("Similarity(a, c) = %v, want 0", got)
+	}
+	if got := Similarity(a, nil); got != 0 {
+		t.Errorf("Similarity(a, nil) = %v, want 0", got)
+	}
+}
+
+func TestSuggestPriorityAndType(t *testing.T) {
+	tests := []struct {
+		title    string
+		priority int
+		typ      string
+	}{
+		{"Security: tokens logged in plain text", 0, "task"},
+		{"Daemon crashes on startup", 1, "bug"},
+		{"Fix typo in README", 3, "task"},
+		{"Support YAML config files", DefaultPriority, "feature"},
+		{"Rename the change handler", DefaultPriority, "chore"},
+	}
+	for _, tt := range tests {
+		issue := Issue{Title: tt.title}
+		if got, _ := SuggestPriority(issue); got != tt.priority {
+			t.Errorf("SuggestPriority(%q) = %d, want %d", tt.title, got, tt.priority)
+		}
+		if got, _ := SuggestType(issue); got != tt.typ {
+			t.Errorf("SuggestType(%q) = %q, want %q", tt.title, got, tt.typ)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	corpus := []Issue{
+		{Rig: "greenplace", ID: "gp-1", Title: "Parser crashes on empty config file", Status: "closed"},
+		{Rig: "greenplace", ID: "gp-2", Title: "Config parser rejects comments"},
+		{Rig: "bluefield", ID: "bf-1", Title: "Dashboard dark mode"},
+		{Rig: "bluefield", ID: "bf-9", Title: "Parser crashes when config file is empty"},
+	}
+	issue := Issue{Rig: "bluefield", ID: "bf-9", Title: "Parser crashes when config file is empty"}
+
+	s := Suggest(issue, corpus)
+	if s.Priority != 1 || s.Type != "bug" {
+		t.Errorf("Suggest priority/type = P%d %s, want P1 bug", s.Priority, s.Type)
+	}
+	if s.Rig != "greenplace" {
+		t.Errorf("Suggest rig = %q, want greenplace", s.Rig)
+	}
+	if len(s.Duplicates) != 1 || s.Duplicates[0].ID != "gp-1" {
+		t.Errorf("Suggest duplicates = %+v, want [gp-1]", s.Duplicates)
+	}
+}
@@ -0,0 +1,206 @@
+// Package upgrade checks for newer gt releases and migrates on-disk state
+// written by older binaries to the current schema versions.
+//
+// Every versioned JSON state file is described by a Schema: where it lives,
+// the version the running binary writes, and one migration step per version
+// bump. Migrate walks the town, applies any pending steps in order, and keeps
+// a backup of each file it rewrites.
+package upgrade
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// ErrNewerSchema is returned when a state file was written by a newer gt.
+var ErrNewerSchema = errors.New("state file written by a newer gt")
+
+// Step migrates a decoded state document from version N to N+1 in place.
+type Step func(doc map[string]interface{}) error
+
+// Schema describes one kind of versioned state file.
+type Schema struct {
+	// Name identifies the schema in output (e.g., "town", "rig-settings").
+	Name string
+
+	// Paths returns the files of this kind under townRoot. Files that do not
+	// exist are skipped.
+	Paths func(townRoot string) []string
+
+	// Current is the version this binary writes.
+	Current int
+
+	// Steps maps a source version to the step that upgrades it by one.
+	// A missing step means the bump is a no-op (only the version changes).
+	Steps map[int]Step
+}
+
+// Change records a migration applied (or planned) for one file.
+type Change struct {
+	Schema string `json:"schema"`
+	Path   string `json:"path"`
+	From   int    `json:"from"`
+	To     int    `json:"to"`
+	Backup string `json:"backup,omitempty"`
+}
+
+// Schemas returns the registered state schemas.
+func Schemas() []Schema {
+	return []Schema{
+		{
+			Name:    "town",
+			Paths:   townFile("mayor", "town.json"),
+			Current: config.CurrentTownVersion,
+			// v1 -> v2 added optional owner/public_name; nothing to rewrite.
+		},
+		{
+			Name:    "rigs",
+			Paths:   townFile("mayor", "rigs.json"),
+			Current: config.CurrentRigsVersion,
+		},
+		{
+			Name:    "town-settings",
+			Paths:   townFile("settings", "config.json"),
+			Current: config.CurrentTownSettingsVersion,
+		},
+		{
+			Name:    "daemon-patrols",
+			Paths:   townFile("mayor", "daemon.json"),
+			Current: config.CurrentDaemonPatrolConfigVersion,
+		},
+		{
+			Name:    "escalation",
+			Paths:   townFile("settings", "escalation.json"),
+			Current: config.CurrentEscalationVersion,
+		},
+		{
+			Name:    "messaging",
+			Paths:   townFile("config", "messaging.json"),
+			Current: config.CurrentMessagingVersion,
+		},
+		{
+			Name:    "rig-config",
+			Paths:   rigFiles("config.json"),
+			Current: config.CurrentRigConfigVersion,
+		},
+		{
+			Name:    "rig-settings",
+			Paths:   rigFiles("settings", "config.json"),
+			Current: config.CurrentRigSettingsVersion,
+		},
+	}
+}
+
+func townFile(elem ...string) func(string) []string {
+	return func(townRoot string) []string {
+		return []string{filepath.Join(append([]string{townRoot}, elem...)...)}
+	}
+}
+
+// rigFiles returns the given file for every rig registered in mayor/rigs.json.
+func rigFiles(elem ...string) func(string) []string {
+	return func(townRoot string) []string {
+		rigs, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+		if err != nil {
+			return nil
+		}
+		names := make([]string, 0, len(rigs.Rigs))
+		for name := range rigs.Rigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var paths []string
+		for _, name := range names {
+			paths = append(paths, filepath.Join(append([]string{townRoot, name}, elem...)...))
+		}
+		return paths
+	}
+}
+
+// Migrate brings every state file under townRoot up to its schema's current
+// version. With dryRun, it reports the pending changes without writing.
+// Files newer than this binary produce an ErrNewerSchema error and are left
+// untouched; migration of the remaining files still proceeds.
+func Migrate(townRoot string, schemas []Schema, dryRun bool) ([]Change, error) {
+	var changes []Change
+	var errs []error
+	for _, s := range schemas {
+		for _, path := range s.Paths(townRoot) {
+			change, err := migrateFile(s, path, dryRun)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s (%s): %w", s.Name, path, err))
+				continue
+			}
+			if change != nil {
+				changes = append(changes, *change)
+			}
+		}
+	}
+	return changes, errors.Join(errs...)
+}
+
+func migrateFile(s Schema, path string, dryRun bool) (*Change, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading: %w", err)
+	}
+
+	// UseNumber keeps integer fields exact when the document is rewritten.
+	var doc map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parsing: %w", err)
+	}
+
+	// Files written before versioning existed carry no version field; treat
+	// them as version 1, which every schema started at.
+	from := 1
+	if v, ok := doc["version"].(json.Number); ok {
+		n, err := v.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q", v)
+		}
+		from = int(n)
+	}
+	if from > s.Current {
+		return nil, fmt.Errorf("%w: version %d, this gt supports %d (upgrade gt)", ErrNewerSchema, from, s.Current)
+	}
+	if _, ok := doc["version"]; ok && from == s.Current {
+		return nil, nil
+	}
+
+	change := &Change{Schema: s.Name, Path: path, From: from, To: s.Current}
+	if dryRun {
+		return change, nil
+	}
+
+	for v := from; v < s.Current; v++ {
+		if step, ok := s.Steps[v]; ok {
+			if err := step(doc); err != nil {
+				return nil, fmt.Errorf("migrating v%d to v%d: %w", v, v+1, err)
+			}
+		}
+	}
+	doc["version"] = s.Current
+
+	change.Backup = fmt.Sprintf("%s.v%d.bak", path, from)
+	if err := os.WriteFile(change.Backup, data, 0600); err != nil {
+		return nil, fmt.Errorf("writing backup: %w", err)
+	}
+	if err := util.AtomicWriteJSON(path, doc); err != nil {
+		return nil, fmt.Errorf("writing: %w", err)
+	}
+	return change, nil
+}
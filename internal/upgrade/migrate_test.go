@@ -0,0 +1,140 @@
+package upgrade
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeJSONFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readDoc(t *testing.T, path string) map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestMigrateAppliesStepsInOrder(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "state.json")
+	writeJSONFile(t, path, `{"version": 1, "name": "old", "count": 9007199254740993}`)
+
+	schema := Schema{
+		Name:    "test",
+		Paths:   townFile("state.json"),
+		Current: 3,
+		Steps: map[int]Step{
+			1: func(doc map[string]interface{}) error {
+				doc["title"] = doc["name"]
+				delete(doc, "name")
+				return nil
+			},
+			2: func(doc map[string]interface{}) error {
+				doc["title"] = doc["title"].(string) + "-v3"
+				return nil
+			},
+		},
+	}
+
+	changes, err := Migrate(root, []Schema{schema}, false)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if len(changes) != 1 || changes[0].From != 1 || changes[0].To != 3 {
+		t.Fatalf("changes = %+v, want one 1→3", changes)
+	}
+
+	doc := readDoc(t, path)
+	if doc["title"] != "old-v3" || doc["version"].(float64) != 3 {
+		t.Errorf("migrated doc = %v", doc)
+	}
+	raw, _ := os.ReadFile(path)
+	if !json.Valid(raw) || !strings.Contains(string(raw), "9007199254740993") {
+		t.Errorf("large integer not preserved: %s", raw)
+	}
+	if _, err := os.Stat(changes[0].Backup); err != nil {
+		t.Errorf("backup missing: %v", err)
+	}
+
+	// Second run is a no-op.
+	changes, err = Migrate(root, []Schema{schema}, false)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("second Migrate = %+v, %v; want no changes", changes, err)
+	}
+}
+
+func TestMigrateDryRunAndMissingVersion(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "state.json")
+	writeJSONFile(t, path, `{"name": "unversioned"}`)
+
+	schema := Schema{Name: "test", Paths: townFile("state.json"), Current: 1}
+
+	changes, err := Migrate(root, []Schema{schema}, true)
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("dry-run Migrate = %+v, %v", changes, err)
+	}
+	if _, ok := readDoc(t, path)["version"]; ok {
+		t.Error("dry run wrote the file")
+	}
+
+	if _, err := Migrate(root, []Schema{schema}, false); err != nil {
+		t.Fatal(err)
+	}
+	if readDoc(t, path)["version"].(float64) != 1 {
+		t.Error("version not stamped")
+	}
+}
+
+func TestMigrateRejectsNewerSchema(t *testing.T) {
+	root := t.TempDir()
+	writeJSONFile(t, filepath.Join(root, "state.json"), `{"version": 5}`)
+	writeJSONFile(t, filepath.Join(root, "other.json"), `{"version": 1}`)
+
+	schemas := []Schema{
+		{Name: "newer", Paths: townFile("state.json"), Current: 2},
+		{Name: "other", Paths: townFile("other.json"), Current: 2},
+		{Name: "absent", Paths: townFile("missing.json"), Current: 2},
+	}
+	changes, err := Migrate(root, schemas, false)
+	if !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("err = %v, want ErrNewerSchema", err)
+	}
+	if len(changes) != 1 || changes[0].Schema != "other" {
+		t.Errorf("changes = %+v, want only other migrated", changes)
+	}
+}
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"0.8.0", "v0.9.0", true},
+		{"0.8.0", "0.8.0", false},
+		{"0.10.0", "0.9.9", false},
+	}
+	for _, tt := range tests {
+		if got := IsNewer(tt.current, tt.latest); got != tt.want {
+			t.Errorf("IsNewer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/deps"
+)
+
+// DefaultReleaseURL is the GitHub API endpoint for the latest gt release.
+const DefaultReleaseURL = "https://api.github.com/repos/steveyegge/gastown/releases/latest"
+
+// Release describes a published gt release.
+type Release struct {
+	Version string `json:"version"`
+	URL     string `json:"url"`
+}
+
+// LatestRelease fetches the latest published release from url (normally
+// DefaultReleaseURL).
+func LatestRelease(ctx context.Context, client *http.Client, url string) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching latest release: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("fetching latest release: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decoding release: %w", err)
+	}
+	if payload.TagName == "" {
+		return nil, fmt.Errorf("release has no tag")
+	}
+	return &Release{Version: strings.TrimPrefix(payload.TagName, "v"), URL: payload.HTMLURL}, nil
+}
+
+// IsNewer reports whether latest is a newer version than current.
+func IsNewer(current, latest string) bool {
+	return deps.CompareVersions(strings.TrimPrefix(latest, "v"), strings.TrimPrefix(current, "v")) > 0
+}
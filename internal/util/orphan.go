@@ -13,6 +13,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/platform"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -127,11 +128,7 @@ type signalState struct {
 
 // stateFileDir returns the directory for state files.
 func stateFileDir() string {
-	dir := os.Getenv("XDG_RUNTIME_DIR")
-	if dir == "" {
-		dir = "/tmp"
-	}
-	return dir
+	return platform.RuntimeDir()
 }
 
 // loadSignalState reads a state file and returns the current signal state
@@ -215,8 +212,7 @@ func saveOrphanState(state map[int]signalState) error {
 
 // processExists checks if a process is still running.
 func processExists(pid int) bool {
-	err := syscall.Kill(pid, 0)
-	return err == nil || err == syscall.EPERM
+	return platform.ProcessExists(pid)
 }
 
 // getProcessCwd returns the current working directory of a process.
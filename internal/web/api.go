@@ -138,6 +138,12 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleSSE(w, r)
 	case path == "/session/preview" && r.Method == http.MethodGet:
 		h.handleSessionPreview(w, r)
+	case path == "/metrics" && r.Method == http.MethodGet:
+		h.handleMetrics(w, r)
+	case path == "/federation/summary" && r.Method == http.MethodGet:
+		h.handleFederationSummary(w, r)
+	case path == "/federation/status" && r.Method == http.MethodGet:
+		h.handleFederationStatus(w, r)
 	default:
 		http.Error(w, "Not found", http.StatusNotFound)
 	}
@@ -6,11 +6,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/metrics"
 	"github.com/steveyegge/gastown/internal/session"
 )
 
@@ -1144,3 +1147,48 @@ func TestHandleSessionPreviewPrefixValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIHandler_Metrics(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	store := metrics.Open(town)
+	for i, v := range []float64{2, 4} {
+		sample := metrics.Sample{Time: now.Add(time.Duration(i-2) * time.Minute), Values: map[string]float64{metrics.SessionsAlive: v}}
+		if err := store.Append(sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, "test-token")
+	handler.workDir = town
+
+	req := httptest.NewRequest(http.MethodGet, "/api/metrics?days=1", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	var resp MetricsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Step != "1h0m0s" || len(resp.Summaries) != 1 || resp.Summaries[0].Mean != 3 {
+		t.Errorf("response = %+v", resp)
+	}
+	if len(resp.Series[metrics.SessionsAlive]) == 0 {
+		t.Errorf("missing %s series", metrics.SessionsAlive)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/metrics?days=0", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("days=0: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,63 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/federation"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// handleFederationSummary returns this town's read-only summary for a
+// federation capital to aggregate.
+func (h *APIHandler) handleFederationSummary(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
+	defer cancel()
+
+	statusOut, err := h.runGtCommand(ctx, 15*time.Second, []string{"status", "--json"})
+	if err != nil {
+		h.sendError(w, "gt status failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// Escalations are best-effort: a town without beads access still reports status.
+	escalationsOut, escErr := h.runGtCommand(ctx, 10*time.Second, []string{"escalate", "list", "--json"})
+	if escErr != nil {
+		escalationsOut = ""
+	}
+
+	summary, err := federation.SummarizeStatus([]byte(statusOut), []byte(escalationsOut))
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if townRoot, err := workspace.Find(h.workDir); err == nil && townRoot != "" {
+		summary.LastBackup = federation.LastBackupTime(townRoot)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(summary)
+}
+
+// handleFederationStatus aggregates summaries from all members listed in
+// this town's mayor/federation.json (the "capital" view).
+func (h *APIHandler) handleFederationStatus(w http.ResponseWriter, r *http.Request) {
+	townRoot, err := workspace.Find(h.workDir)
+	if err != nil || townRoot == "" {
+		h.sendError(w, "not in a Gas Town workspace", http.StatusInternalServerError)
+		return
+	}
+	cfg, err := federation.LoadConfig(townRoot)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 2*federation.DefaultFetchTimeout)
+	defer cancel()
+	fleet := federation.Collect(ctx, nil, cfg.Members)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(fleet)
+}
@@ -0,0 +1,83 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/metrics"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+const (
+	defaultMetricsDays = 30
+	maxMetricsDays     = 366
+)
+
+// MetricsResponse is the response for /api/metrics.
+type MetricsResponse struct {
+	Start     string                     `json:"start"`
+	End       string                     `json:"end"`
+	Step      string                     `json:"step"`
+	Summaries []metrics.Summary          `json:"summaries"`
+	Series    map[string][]metrics.Point `json:"series"`
+}
+
+// handleMetrics returns the town's recorded metrics history: a summary of
+// each metric plus a bucketed series for charting.
+//
+// Query parameters:
+//   - days: how far back to look (default 30, max 366)
+//   - step: bucket width as a Go duration (default 1h up to 7 days, 24h beyond)
+func (h *APIHandler) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	townRoot, err := workspace.Find(h.workDir)
+	if err != nil || townRoot == "" {
+		h.sendError(w, "not in a Gas Town workspace", http.StatusInternalServerError)
+		return
+	}
+
+	days := defaultMetricsDays
+	if s := r.URL.Query().Get("days"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 || n > maxMetricsDays {
+			h.sendError(w, "Invalid days parameter", http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+	step := time.Hour
+	if days > 7 {
+		step = 24 * time.Hour
+	}
+	if s := r.URL.Query().Get("step"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < time.Minute {
+			h.sendError(w, "Invalid step parameter (minimum 1m)", http.StatusBadRequest)
+			return
+		}
+		step = d
+	}
+
+	end := time.Now()
+	start := end.Add(-time.Duration(days) * 24 * time.Hour).Truncate(step)
+	samples, err := metrics.Open(townRoot).Range(start, end)
+	if err != nil {
+		h.sendError(w, "Failed to read metrics history: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := MetricsResponse{
+		Start:     start.Format(time.RFC3339),
+		End:       end.Format(time.RFC3339),
+		Step:      step.String(),
+		Summaries: metrics.Summarize(samples),
+		Series:    make(map[string][]metrics.Point),
+	}
+	for _, sum := range resp.Summaries {
+		resp.Series[sum.Name] = metrics.Series(samples, sum.Name, start, step)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
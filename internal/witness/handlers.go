@@ -12,8 +12,12 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/notes"
+	"github.com/steveyegge/gastown/internal/progress"
+	"github.com/steveyegge/gastown/internal/quota"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/takeover"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -975,6 +979,12 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 		sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
 		result.Checked++
 
+		// A human has the session (gt takeover): mixed input looks like a
+		// zombie, so leave it alone until gt handback.
+		if takeover.IsTakenOver(townRoot, sessionName) {
+			continue
+		}
+
 		detectedAt := time.Now()
 
 		sessionAlive, err := t.HasSession(sessionName)
@@ -1036,10 +1046,11 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 					// A session where Claude is alive but has produced no tmux output
 					// for a long time is likely hung (infinite loop, crashed mid-call,
 					// or waiting for something that will never arrive). See: gt-tr3d
+					// Warm standby sessions sit at an idle prompt by design.
 					lastActivity, actErr := t.GetSessionActivity(sessionName)
 					if actErr == nil && !lastActivity.IsZero() {
 						inactiveMinutes := int(time.Since(lastActivity).Minutes())
-						if inactiveMinutes >= HungSessionThresholdMinutes {
+						if inactiveMinutes >= HungSessionThresholdMinutes && !isStandbyAgent(workDir, agentBeadID) {
 							_, hungHookBead := getAgentBeadState(workDir, agentBeadID)
 							zombie := ZombieResult{
 								PolecatName: polecatName,
@@ -1254,11 +1265,15 @@ func handleZombieCleanup(workDir, rigName, polecatName, hookBead, cleanupStatus
 // StalledResult represents a single stalled polecat detection.
 type StalledResult struct {
 	PolecatName string // e.g., "alpha"
-	StallType   string // "bypass-permissions", "unknown-prompt"
-	Action      string // "auto-dismissed", "escalated"
+	StallType   string // "bypass-permissions", "awaiting-input", "error-banner", "no-progress"
+	Action      string // "auto-dismissed", "escalated", "reported"
 	Error       error
 }
 
+// NotesStallThreshold is how long a polecat's issue notes may go without an
+// update before the witness reports it as making no progress.
+var NotesStallThreshold = 45 * time.Minute
+
 // DetectStalledPolecatsResult holds aggregate results.
 type DetectStalledPolecatsResult struct {
 	Checked int             // Number of live polecats inspected
@@ -1275,6 +1290,9 @@ type DetectStalledPolecatsResult struct {
 //   - Captures pane content (last 30 lines)
 //   - Checks for known stall patterns
 //   - Auto-dismisses known prompts (bypass-permissions) or escalates
+//   - Reports agents the runtime's output parser shows waiting on a question
+//     for the user or stopped at an error banner (see package progress)
+//   - Reports agents whose issue notes have gone quiet (see NotesStallThreshold)
 //
 // This is idempotent: calling AcceptBypassPermissionsWarning on a non-stalled
 // session is harmless, so no dedup or TOCTOU guards are needed.
@@ -1296,6 +1314,7 @@ func DetectStalledPolecats(workDir, rigName string) *DetectStalledPolecatsResult
 	}
 
 	t := tmux.NewTmux()
+	runtimes := &quota.ProviderResolver{TownRoot: townRoot}
 
 	for _, entry := range entries {
 		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -1306,6 +1325,10 @@ func DetectStalledPolecats(workDir, rigName string) *DetectStalledPolecatsResult
 		sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
 		result.Checked++
 
+		if takeover.IsTakenOver(townRoot, sessionName) {
+			continue // Human is driving the session (gt takeover)
+		}
+
 		// Only check live sessions with alive agents (the opposite of zombie detection)
 		sessionAlive, err := t.HasSession(sessionName)
 		if err != nil {
@@ -1358,12 +1381,54 @@ func DetectStalledPolecats(workDir, rigName string) *DetectStalledPolecatsResult
 				stalled.Action = "auto-dismissed"
 			}
 			result.Stalled = append(result.Stalled, stalled)
+			continue
+		}
+
+		// The runtime's output parser tells a question for the user or an
+		// error banner apart from an agent that is merely quiet.
+		parser := progress.ParserFor(runtimes.SessionRuntime(t, sessionName))
+		if sig, blocked := progress.Blocking(parser.Parse(strings.Split(content, "\n"))); blocked {
+			stallType := "awaiting-input"
+			if sig.Kind == progress.KindError {
+				stallType = "error-banner"
+			}
+			result.Stalled = append(result.Stalled, StalledResult{
+				PolecatName: polecatName,
+				StallType:   stallType,
+				Action:      "reported",
+				Error:       fmt.Errorf("%s", sig.Detail),
+			})
+			continue
+		}
+
+		// No prompt is blocking the agent, but it may have stopped making
+		// progress. Only agents that have written notes are judged by them,
+		// so agents that never record progress are not flagged.
+		prefix := beads.GetPrefixForRig(townRoot, rigName)
+		_, hookBead := getAgentBeadState(workDir, beads.PolecatBeadIDWithPrefix(prefix, rigName, polecatName))
+		if hookBead == "" {
+			continue
+		}
+		if updated, ok := notes.LastUpdated(townRoot, hookBead); ok && time.Since(updated) > NotesStallThreshold {
+			result.Stalled = append(result.Stalled, StalledResult{
+				PolecatName: polecatName,
+				StallType:   "no-progress",
+				Action:      "reported",
+				Error:       fmt.Errorf("notes for %s last updated %s ago", hookBead, time.Since(updated).Round(time.Minute)),
+			})
 		}
 	}
 
 	return result
 }
 
+// isStandbyAgent reports whether a polecat is a warm standby: its session
+// is running at an idle prompt with no hooked work (see gt polecat standby).
+func isStandbyAgent(workDir, agentBeadID string) bool {
+	agentState, hookBead := getAgentBeadState(workDir, agentBeadID)
+	return agentState == "standby" && hookBead == ""
+}
+
 // getAgentBeadState reads agent_state and hook_bead from an agent bead.
 // Returns the agent_state string and hook_bead ID.
 func getAgentBeadState(workDir, agentBeadID string) (agentState, hookBead string) {
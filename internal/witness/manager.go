@@ -167,6 +167,7 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
+	command = session.WithTemplateEnv(command, m.rig.Path, "witness")
 	if err := t.NewSessionWithCommand(sessionID, witnessDir, command); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
@@ -196,7 +197,15 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 
 	// Apply Gas Town theming (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "witness", "witness")
+	_ = session.ConfigureSession(t, session.SessionConfig{
+		SessionID: sessionID,
+		WorkDir:   witnessDir,
+		Role:      "witness",
+		RigPath:   m.rig.Path,
+		RigName:   m.rig.Name,
+		AgentName: "witness",
+		Theme:     &theme,
+	})
 
 	// Wait for Claude to start - fatal if Claude fails to launch
 	if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
@@ -214,6 +223,9 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 	if err := session.TrackSessionPID(townRoot, sessionID, t); err != nil {
 		log.Printf("warning: tracking session PID for %s: %v", sessionID, err)
 	}
+	if err := session.RegisterSession(townRoot, session.SessionRecord{Session: sessionID, Role: session.RoleWitness, Rig: m.rig.Name}); err != nil {
+		log.Printf("warning: registering session %s: %v", sessionID, err)
+	}
 
 	time.Sleep(constants.ShutdownNotifyDelay)
 
@@ -0,0 +1,151 @@
+package witness
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// MonitorPolicy says what may be done about a rig agent found dead or hung.
+type MonitorPolicy struct {
+	// AutoReap allows nuking the agent's session and sandbox once its work
+	// is safe (polecats only).
+	AutoReap bool `json:"auto_reap"`
+
+	// RestartOnCrash restarts the agent's session when its agent dies.
+	RestartOnCrash bool `json:"restart_on_crash"`
+
+	// CheckedBy is who watches the agent: "witness", or "daemon" for the
+	// witness itself, which can't notice its own death.
+	CheckedBy string `json:"checked_by"`
+}
+
+// PolicyFor returns the monitoring policy for a rig role. Crew are
+// user-managed and are never reaped or restarted, only reported.
+func PolicyFor(role session.Role) MonitorPolicy {
+	switch role {
+	case session.RolePolecat:
+		return MonitorPolicy{AutoReap: true, CheckedBy: "witness"}
+	case session.RoleRefinery:
+		return MonitorPolicy{RestartOnCrash: true, CheckedBy: "witness"}
+	case session.RoleWitness:
+		return MonitorPolicy{RestartOnCrash: true, CheckedBy: "daemon"}
+	default:
+		return MonitorPolicy{CheckedBy: "witness"}
+	}
+}
+
+// MonitoredAgent is one rig agent under watch and its session's health.
+type MonitoredAgent struct {
+	Role    session.Role  `json:"role"`
+	Name    string        `json:"name,omitempty"` // polecat or crew name
+	Session string        `json:"session"`
+	Health  string        `json:"health"` // tmux.ZombieStatus label
+	Policy  MonitorPolicy `json:"policy"`
+}
+
+// MonitoredAgents is everything a rig's witness keeps watch over.
+type MonitoredAgents struct {
+	Rig    string           `json:"rig"`
+	Agents []MonitoredAgent `json:"agents"`
+}
+
+// ByRole returns the monitored agents with the given role.
+func (m *MonitoredAgents) ByRole(role session.Role) []MonitoredAgent {
+	var out []MonitoredAgent
+	for _, a := range m.Agents {
+		if a.Role == role {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// checkSessionHealth reports a session's health. Tests replace it.
+var checkSessionHealth = func(sessionName string, maxInactivity time.Duration) tmux.ZombieStatus {
+	return tmux.NewTmux().CheckSessionHealth(sessionName, maxInactivity)
+}
+
+// ListMonitoredAgents returns the rig's agents with their session health:
+// polecats, crew, the refinery, and the witness itself.
+func ListMonitoredAgents(r *rig.Rig) *MonitoredAgents {
+	prefix := session.PrefixFor(r.Name)
+	m := &MonitoredAgents{Rig: r.Name}
+	add := func(role session.Role, name, sessionName string) {
+		m.Agents = append(m.Agents, MonitoredAgent{
+			Role:    role,
+			Name:    name,
+			Session: sessionName,
+			Health:  checkSessionHealth(sessionName, HungSessionThresholdMinutes*time.Minute).String(),
+			Policy:  PolicyFor(role),
+		})
+	}
+	for _, name := range r.Polecats {
+		add(session.RolePolecat, name, session.PolecatSessionName(prefix, name))
+	}
+	for _, name := range r.Crew {
+		add(session.RoleCrew, name, session.CrewSessionName(prefix, name))
+	}
+	if r.HasRefinery {
+		add(session.RoleRefinery, "", session.RefinerySessionName(prefix))
+	}
+	if r.HasWitness {
+		add(session.RoleWitness, "", session.WitnessSessionName(prefix))
+	}
+	return m
+}
+
+// AgentCheckResult is what CheckRigAgents did about one unhealthy agent.
+type AgentCheckResult struct {
+	Role    session.Role `json:"role"`
+	Name    string       `json:"name,omitempty"`
+	Session string       `json:"session"`
+	Health  string       `json:"health"`
+	Action  string       `json:"action"` // "restarted", "reported"
+	Error   string       `json:"error,omitempty"`
+}
+
+// CheckRigAgentsResult contains the results of a crew and refinery sweep.
+type CheckRigAgentsResult struct {
+	Checked   int                `json:"checked"`
+	Unhealthy []AgentCheckResult `json:"unhealthy,omitempty"`
+}
+
+// CheckRigAgents applies the monitoring policies to the rig's non-polecat
+// agents; polecats are handled by DetectZombiePolecats, and the witness by
+// the daemon. A refinery whose agent died is restarted; one with no session
+// is only reported, since it may have been stopped on purpose. Crew
+// sessions whose agent died are reported but left alone: a crew member with
+// no session is just off duty. No output for a while (AgentHung) is not a
+// crash: a refinery with an empty queue and a crew prompt waiting on its
+// human are both quiet, so inactivity alone is never acted on.
+//
+// restartRefinery kills the refinery's session and starts a fresh one; the
+// caller supplies it because the refinery package depends on this one.
+func CheckRigAgents(workDir string, r *rig.Rig, restartRefinery func() error) *CheckRigAgentsResult {
+	initRegistryFromWorkDir(workDir)
+	result := &CheckRigAgentsResult{}
+	for _, a := range ListMonitoredAgents(r).Agents {
+		if a.Policy.CheckedBy != "witness" || a.Role == session.RolePolecat {
+			continue
+		}
+		result.Checked++
+		crashed := a.Health == tmux.AgentDead.String()
+		if !crashed && (a.Role == session.RoleCrew || a.Health != tmux.SessionDead.String()) {
+			continue
+		}
+
+		res := AgentCheckResult{Role: a.Role, Name: a.Name, Session: a.Session, Health: a.Health, Action: "reported"}
+		if a.Policy.RestartOnCrash && crashed {
+			res.Action = "restarted"
+			if err := restartRefinery(); err != nil {
+				res.Error = fmt.Sprintf("restarting refinery: %v", err)
+			}
+		}
+		result.Unhealthy = append(result.Unhealthy, res)
+	}
+	return result
+}
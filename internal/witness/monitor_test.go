@@ -0,0 +1,128 @@
+package witness
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// stubSessionHealth makes every session healthy except those whose name
+// contains a key of unhealthy.
+func stubSessionHealth(t *testing.T, unhealthy map[string]tmux.ZombieStatus) {
+	t.Helper()
+	old := checkSessionHealth
+	checkSessionHealth = func(name string, _ time.Duration) tmux.ZombieStatus {
+		for frag, status := range unhealthy {
+			if strings.Contains(name, frag) {
+				return status
+			}
+		}
+		return tmux.SessionHealthy
+	}
+	t.Cleanup(func() { checkSessionHealth = old })
+}
+
+func TestListMonitoredAgents(t *testing.T) {
+	stubSessionHealth(t, map[string]tmux.ZombieStatus{"crew-max": tmux.AgentDead})
+	r := &rig.Rig{Name: "gastown", Polecats: []string{"Toast"}, Crew: []string{"max"}, HasRefinery: true, HasWitness: true}
+
+	m := ListMonitoredAgents(r)
+	if len(m.Agents) != 4 {
+		t.Fatalf("got %d agents, want 4: %+v", len(m.Agents), m.Agents)
+	}
+	crew := m.ByRole(session.RoleCrew)
+	if len(crew) != 1 || crew[0].Name != "max" || crew[0].Health != "agent-dead" {
+		t.Errorf("crew = %+v", crew)
+	}
+	if crew[0].Policy.AutoReap || crew[0].Policy.RestartOnCrash {
+		t.Errorf("crew must never be reaped or restarted: %+v", crew[0].Policy)
+	}
+	if w := m.ByRole(session.RoleWitness); len(w) != 1 || w[0].Policy.CheckedBy != "daemon" {
+		t.Errorf("witness = %+v", w)
+	}
+	if p := m.ByRole(session.RolePolecat); len(p) != 1 || !p[0].Policy.AutoReap {
+		t.Errorf("polecats = %+v", p)
+	}
+}
+
+func TestCheckRigAgents(t *testing.T) {
+	tests := []struct {
+		name        string
+		unhealthy   map[string]tmux.ZombieStatus
+		restartErr  error
+		wantActions []string
+		wantRestart bool
+	}{
+		{
+			name: "all healthy",
+		},
+		{
+			name:        "dead refinery agent is restarted",
+			unhealthy:   map[string]tmux.ZombieStatus{"refinery": tmux.AgentDead},
+			wantActions: []string{"refinery:restarted"},
+			wantRestart: true,
+		},
+		{
+			name:      "idle refinery is left alone",
+			unhealthy: map[string]tmux.ZombieStatus{"refinery": tmux.AgentHung},
+		},
+		{
+			name:        "refinery without a session is only reported",
+			unhealthy:   map[string]tmux.ZombieStatus{"refinery": tmux.SessionDead},
+			wantActions: []string{"refinery:reported"},
+		},
+		{
+			name:        "dead crew agent is reported, not restarted",
+			unhealthy:   map[string]tmux.ZombieStatus{"crew-max": tmux.AgentDead},
+			wantActions: []string{"crew:reported"},
+		},
+		{
+			name:      "idle or absent crew is fine",
+			unhealthy: map[string]tmux.ZombieStatus{"crew-max": tmux.AgentHung, "crew-joe": tmux.SessionDead},
+		},
+		{
+			name:        "restart failure is recorded",
+			unhealthy:   map[string]tmux.ZombieStatus{"refinery": tmux.AgentDead},
+			restartErr:  errors.New("rig is parked"),
+			wantActions: []string{"refinery:restarted"},
+			wantRestart: true,
+		},
+		{
+			name:      "polecats and the witness are left to others",
+			unhealthy: map[string]tmux.ZombieStatus{"Toast": tmux.AgentDead, "witness": tmux.AgentDead},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubSessionHealth(t, tt.unhealthy)
+			r := &rig.Rig{Name: "gastown", Polecats: []string{"Toast"}, Crew: []string{"max", "joe"}, HasRefinery: true, HasWitness: true}
+			restarted := false
+			result := CheckRigAgents(t.TempDir(), r, func() error {
+				restarted = true
+				return tt.restartErr
+			})
+
+			if result.Checked != 3 {
+				t.Errorf("Checked = %d, want 3 (two crew, one refinery)", result.Checked)
+			}
+			var got []string
+			for _, u := range result.Unhealthy {
+				got = append(got, string(u.Role)+":"+u.Action)
+				if tt.restartErr != nil && !strings.Contains(u.Error, tt.restartErr.Error()) {
+					t.Errorf("Error = %q, want it to mention %q", u.Error, tt.restartErr)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantActions, ",") {
+				t.Errorf("actions = %v, want %v", got, tt.wantActions)
+			}
+			if restarted != tt.wantRestart {
+				t.Errorf("restarted = %v, want %v", restarted, tt.wantRestart)
+			}
+		})
+	}
+}
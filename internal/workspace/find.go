@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/platform"
 )
 
 // ErrNotFound indicates no workspace was found.
@@ -30,8 +31,9 @@ const (
 // It prefers mayor/town.json over mayor/ directory as workspace marker.
 // When in a worktree path (polecats/ or crew/), continues to outermost workspace.
 // Does not resolve symlinks to stay consistent with os.Getwd().
+// Under WSL, a Windows drive path is looked up through its /mnt mount.
 func Find(startDir string) (string, error) {
-	absDir, err := filepath.Abs(startDir)
+	absDir, err := filepath.Abs(platform.LocalPath(startDir))
 	if err != nil {
 		return "", fmt.Errorf("resolving path: %w", err)
 	}
@@ -99,7 +101,7 @@ func FindFromCwdOrError() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		// Fallback: try GT_TOWN_ROOT env var (set by polecat sessions)
-		if townRoot := os.Getenv("GT_TOWN_ROOT"); townRoot != "" {
+		if townRoot := platform.LocalPath(os.Getenv("GT_TOWN_ROOT")); townRoot != "" {
 			// Verify it's actually a workspace
 			if _, statErr := os.Stat(filepath.Join(townRoot, PrimaryMarker)); statErr == nil {
 				return townRoot, nil
@@ -118,7 +120,7 @@ func FindFromCwdWithFallback() (townRoot string, cwd string, err error) {
 	cwd, err = os.Getwd()
 	if err != nil {
 		// Fallback: try GT_TOWN_ROOT env var
-		if townRoot = os.Getenv("GT_TOWN_ROOT"); townRoot != "" {
+		if townRoot = platform.LocalPath(os.Getenv("GT_TOWN_ROOT")); townRoot != "" {
 			// Verify it's actually a workspace
 			if _, statErr := os.Stat(filepath.Join(townRoot, PrimaryMarker)); statErr == nil {
 				return townRoot, "", nil // cwd is gone but townRoot is valid